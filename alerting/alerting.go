@@ -0,0 +1,331 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	defaultMinInterval   = 5 * time.Minute
+	defaultCheckInterval = time.Second
+
+	// alertBufferSize is the no.of alerts waiting to be posted, the following ones are dropped
+	alertBufferSize = 64
+)
+
+var (
+	errNoURL = errors.New("the alert webhook url is not set")
+)
+
+// AlertType is the type of an alert
+type AlertType string
+
+const (
+	// Reorg alerts are fired by the reorgs dropping more blocks than the configured depth
+	Reorg AlertType = "reorg"
+
+	// StaleHead alerts are fired when the head doesn't advance for the configured timeout
+	StaleHead AlertType = "stale_head"
+)
+
+// Config is the configuration of the alerts, they are disabled if no webhook is set
+type Config struct {
+	// Webhooks are the endpoints the alerts are posted to
+	Webhooks []*WebhookConfig
+
+	// ReorgDepth is the max no.of blocks dropped by a reorg, the deeper ones are alerted
+	ReorgDepth uint64
+
+	// StaleTimeout is the time the head can stay the same before it is alerted, 0 to disable
+	StaleTimeout time.Duration
+
+	// MinInterval is the minimum time between two identical alerts, the default if zero
+	MinInterval time.Duration
+}
+
+// WebhookConfig is the configuration of an endpoint the alerts are posted to
+type WebhookConfig struct {
+	URL string
+
+	// Headers are the extra HTTP headers of the webhook requests
+	Headers map[string]string
+
+	// Timeout is the time a post can take, the default if zero
+	Timeout time.Duration
+}
+
+// Block is a block referenced by an alert
+type Block struct {
+	Number uint64     `json:"number"`
+	Hash   types.Hash `json:"hash"`
+}
+
+func newBlock(header *types.Header) *Block {
+	return &Block{Number: header.Number, Hash: header.Hash}
+}
+
+// Alert is the JSON body posted to the webhooks
+type Alert struct {
+	Type AlertType `json:"type"`
+
+	// Timestamp is the unix time the alert is fired at
+	Timestamp int64 `json:"timestamp"`
+
+	// Head is the head of the chain
+	Head *Block `json:"head"`
+
+	// the head dropped by the reorg, the last block shared by the chains and the no.of blocks dropped
+	OldHead  *Block `json:"oldHead,omitempty"`
+	Ancestor *Block `json:"ancestor,omitempty"`
+	Depth    uint64 `json:"depth,omitempty"`
+
+	// StaleSeconds is the time the head has stayed the same
+	StaleSeconds uint64 `json:"staleSeconds,omitempty"`
+}
+
+// blockchainStore is the blockchain the monitor watches
+type blockchainStore interface {
+	// Header returns the head of the chain
+	Header() *types.Header
+
+	// SubscribeEventStream subscribes to the events of the blockchain
+	SubscribeEventStream() blockchain.Subscription
+}
+
+// Monitor alerts the webhooks of the reorgs deeper than the configured depth,
+// and of the head not advancing for the configured timeout. The identical alerts,
+// of the same reorg or of the same stale head, are not fired again for the minimum interval
+type Monitor struct {
+	logger   hclog.Logger
+	store    blockchainStore
+	config   *Config
+	webhooks []*webhook
+
+	minInterval   time.Duration
+	now           func() time.Time
+	checkInterval time.Duration
+
+	lock sync.Mutex
+
+	// head is the head of the chain, it advanced last at headTime
+	head     *types.Header
+	headTime time.Time
+
+	// fired are the times the alerts were fired at, by their key
+	fired map[string]time.Time
+
+	alertCh chan *Alert
+	sub     blockchain.Subscription
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewMonitor creates the monitor of the blockchain posting the alerts to the webhooks of the config
+func NewMonitor(logger hclog.Logger, store blockchainStore, config *Config) (*Monitor, error) {
+	m := &Monitor{
+		logger:        logger.Named("alerting"),
+		store:         store,
+		config:        config,
+		minInterval:   config.MinInterval,
+		now:           time.Now,
+		checkInterval: defaultCheckInterval,
+		fired:         map[string]time.Time{},
+		alertCh:       make(chan *Alert, alertBufferSize),
+	}
+
+	if m.minInterval == 0 {
+		m.minInterval = defaultMinInterval
+	}
+
+	for _, webhookConfig := range config.Webhooks {
+		webhook, err := newWebhook(webhookConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		m.webhooks = append(m.webhooks, webhook)
+	}
+
+	return m, nil
+}
+
+// Start starts watching the blockchain, it is a no-op if no webhook is set
+func (m *Monitor) Start() {
+	if len(m.webhooks) == 0 {
+		return
+	}
+
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.head, m.headTime = m.store.Header(), m.now()
+	m.sub = m.store.SubscribeEventStream()
+
+	m.wg.Add(2)
+
+	go func() {
+		defer m.wg.Done()
+
+		m.post()
+	}()
+
+	go func() {
+		defer m.wg.Done()
+
+		m.checkStale()
+	}()
+
+	go m.watch()
+}
+
+// Close stops watching the blockchain, the alerts not posted yet are dropped
+func (m *Monitor) Close() {
+	if m.cancel == nil {
+		return
+	}
+
+	m.cancel()
+	m.sub.Close()
+	m.wg.Wait()
+
+	for _, webhook := range m.webhooks {
+		webhook.close()
+	}
+}
+
+// watch handles the events of the blockchain until the subscription is closed
+func (m *Monitor) watch() {
+	for {
+		evnt := m.sub.GetEvent()
+		if evnt == nil {
+			return
+		}
+
+		m.handleEvent(evnt)
+	}
+}
+
+// handleEvent records the new head, and alerts the reorg of the event if it is too deep
+func (m *Monitor) handleEvent(evnt *blockchain.Event) {
+	if evnt.Type == blockchain.EventFork || len(evnt.NewChain) == 0 {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.head, m.headTime = highest(evnt.NewChain), m.now()
+
+	if evnt.Type != blockchain.EventReorg || uint64(len(evnt.OldChain)) <= m.config.ReorgDepth {
+		return
+	}
+
+	// the dropped headers are listed from the lowest one
+	oldHead, lowest := highest(evnt.OldChain), evnt.OldChain[0]
+
+	alert := &Alert{
+		Type:     Reorg,
+		Head:     newBlock(m.head),
+		OldHead:  newBlock(oldHead),
+		Ancestor: &Block{Number: lowest.Number - 1, Hash: lowest.ParentHash},
+		Depth:    uint64(len(evnt.OldChain)),
+	}
+
+	m.fire(alert, string(Reorg)+oldHead.Hash.String()+m.head.Hash.String())
+}
+
+// checkStale alerts the head that didn't advance for the stale timeout, until the monitor is closed
+func (m *Monitor) checkStale() {
+	if m.config.StaleTimeout == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-m.ctx.Done():
+			return
+		}
+
+		m.lock.Lock()
+
+		if stale := m.now().Sub(m.headTime); stale >= m.config.StaleTimeout {
+			alert := &Alert{
+				Type:         StaleHead,
+				Head:         newBlock(m.head),
+				StaleSeconds: uint64(stale / time.Second),
+			}
+
+			m.fire(alert, string(StaleHead)+m.head.Hash.String())
+		}
+
+		m.lock.Unlock()
+	}
+}
+
+// fire queues the alert to be posted, unless the identical one of the key was fired
+// less than the minimum interval ago. The lock has to be held
+func (m *Monitor) fire(alert *Alert, key string) {
+	now := m.now()
+
+	for firedKey, firedTime := range m.fired {
+		if now.Sub(firedTime) >= m.minInterval {
+			delete(m.fired, firedKey)
+		}
+	}
+
+	if _, ok := m.fired[key]; ok {
+		m.logger.Debug("identical alert fired recently", "type", alert.Type, "head", alert.Head.Number)
+
+		return
+	}
+
+	m.fired[key] = now
+	alert.Timestamp = now.Unix()
+
+	m.logger.Warn("alert", "type", alert.Type, "head", alert.Head.Number)
+
+	select {
+	case m.alertCh <- alert:
+	default:
+		m.logger.Error("alert dropped, too many alerts waiting to be posted", "type", alert.Type)
+	}
+}
+
+// post posts the alerts to the webhooks until the monitor is closed
+func (m *Monitor) post() {
+	for {
+		select {
+		case alert := <-m.alertCh:
+			for _, webhook := range m.webhooks {
+				if err := webhook.post(m.ctx, alert); err != nil {
+					m.logger.Error("failed to post the alert", "type", alert.Type, "url", webhook.url, "err", err)
+				}
+			}
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// highest returns the header of the highest number
+func highest(headers []*types.Header) *types.Header {
+	header := headers[0]
+
+	for _, h := range headers[1:] {
+		if h.Number > header.Number {
+			header = h
+		}
+	}
+
+	return header
+}
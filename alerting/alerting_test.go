@@ -0,0 +1,234 @@
+package alerting
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSubscription returns the pushed events, and nil once closed
+type mockSubscription struct {
+	eventCh chan *blockchain.Event
+	closeCh chan struct{}
+	once    sync.Once
+}
+
+func (m *mockSubscription) GetEventCh() chan *blockchain.Event {
+	return m.eventCh
+}
+
+func (m *mockSubscription) GetEvent() *blockchain.Event {
+	select {
+	case evnt := <-m.eventCh:
+		return evnt
+	case <-m.closeCh:
+		return nil
+	}
+}
+
+func (m *mockSubscription) Close() {
+	m.once.Do(func() {
+		close(m.closeCh)
+	})
+}
+
+type mockStore struct {
+	head *types.Header
+	sub  *mockSubscription
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{
+		head: newHeader(10, 0),
+		sub: &mockSubscription{
+			eventCh: make(chan *blockchain.Event),
+			closeCh: make(chan struct{}),
+		},
+	}
+}
+
+func (m *mockStore) Header() *types.Header {
+	return m.head
+}
+
+func (m *mockStore) SubscribeEventStream() blockchain.Subscription {
+	return m.sub
+}
+
+// mockClock is the time of the monitor, advanced by the tests
+type mockClock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func (c *mockClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+func (c *mockClock) advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// newHeader returns the header of the number, its hash is derived from the fork
+func newHeader(number uint64, fork byte) *types.Header {
+	return &types.Header{
+		Number:     number,
+		Hash:       types.Hash{fork, byte(number)},
+		ParentHash: types.Hash{fork, byte(number - 1)},
+	}
+}
+
+// newTestMonitor returns the started monitor posting the alerts to the returned channel
+func newTestMonitor(t *testing.T, store *mockStore, config *Config) (*Monitor, *mockClock, chan *Alert) {
+	t.Helper()
+
+	alertCh := make(chan *Alert, 10)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+
+		alert := &Alert{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(alert))
+
+		alertCh <- alert
+	}))
+	t.Cleanup(server.Close)
+
+	config.Webhooks = []*WebhookConfig{{URL: server.URL, Headers: map[string]string{"X-Token": "secret"}}}
+
+	monitor, err := NewMonitor(hclog.NewNullLogger(), store, config)
+	assert.NoError(t, err)
+
+	clock := &mockClock{now: time.Unix(1000, 0)}
+	monitor.now = clock.Now
+	monitor.checkInterval = 10 * time.Millisecond
+
+	monitor.Start()
+	t.Cleanup(monitor.Close)
+
+	return monitor, clock, alertCh
+}
+
+func expectAlert(t *testing.T, alertCh chan *Alert) *Alert {
+	t.Helper()
+
+	select {
+	case alert := <-alertCh:
+		return alert
+	case <-time.After(5 * time.Second):
+		t.Fatal("no alert posted")
+
+		return nil
+	}
+}
+
+func expectNoAlert(t *testing.T, alertCh chan *Alert) {
+	t.Helper()
+
+	select {
+	case alert := <-alertCh:
+		t.Fatalf("unexpected alert %v", alert.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// reorgEvent returns the event of the reorg dropping the blocks of the fork 0 above the ancestor
+func reorgEvent(ancestor, depth uint64, fork byte) *blockchain.Event {
+	evnt := &blockchain.Event{Type: blockchain.EventReorg}
+
+	for number := ancestor + 1; number <= ancestor+depth; number++ {
+		evnt.AddOldHeader(newHeader(number, 0))
+		evnt.AddNewHeader(newHeader(number, fork))
+	}
+
+	return evnt
+}
+
+func TestMonitor_Reorg(t *testing.T) {
+	store := newMockStore()
+	_, _, alertCh := newTestMonitor(t, store, &Config{ReorgDepth: 1})
+
+	// the reorgs up to the depth are not alerted
+	store.sub.eventCh <- reorgEvent(9, 1, 1)
+	expectNoAlert(t, alertCh)
+
+	store.sub.eventCh <- reorgEvent(8, 2, 1)
+
+	alert := expectAlert(t, alertCh)
+	assert.Equal(t, &Alert{
+		Type:      Reorg,
+		Timestamp: 1000,
+		Head:      &Block{Number: 10, Hash: types.Hash{1, 10}},
+		OldHead:   &Block{Number: 10, Hash: types.Hash{0, 10}},
+		Ancestor:  &Block{Number: 8, Hash: types.Hash{0, 8}},
+		Depth:     2,
+	}, alert)
+
+	// the identical reorg is not alerted again, the other one is
+	store.sub.eventCh <- reorgEvent(8, 2, 1)
+	expectNoAlert(t, alertCh)
+
+	store.sub.eventCh <- reorgEvent(8, 2, 2)
+	assert.Equal(t, types.Hash{2, 10}, expectAlert(t, alertCh).Head.Hash)
+}
+
+func TestMonitor_StaleHead(t *testing.T) {
+	store := newMockStore()
+	_, clock, alertCh := newTestMonitor(t, store, &Config{
+		StaleTimeout: 10 * time.Second,
+		MinInterval:  time.Minute,
+	})
+
+	clock.advance(5 * time.Second)
+	expectNoAlert(t, alertCh)
+
+	clock.advance(6 * time.Second)
+
+	alert := expectAlert(t, alertCh)
+	assert.Equal(t, StaleHead, alert.Type)
+	assert.Equal(t, &Block{Number: 10, Hash: types.Hash{0, 10}}, alert.Head)
+	assert.Equal(t, uint64(11), alert.StaleSeconds)
+
+	// the head staying the same is alerted again after the minimum interval
+	clock.advance(30 * time.Second)
+	expectNoAlert(t, alertCh)
+
+	clock.advance(30 * time.Second)
+	assert.Equal(t, uint64(71), expectAlert(t, alertCh).StaleSeconds)
+
+	// the head advancing is not stale anymore
+	store.sub.eventCh <- &blockchain.Event{Type: blockchain.EventHead, NewChain: []*types.Header{newHeader(11, 0)}}
+	expectNoAlert(t, alertCh)
+
+	clock.advance(10 * time.Second)
+	assert.Equal(t, uint64(11), expectAlert(t, alertCh).Head.Number)
+}
+
+func TestMonitor_Disabled(t *testing.T) {
+	monitor, err := NewMonitor(hclog.NewNullLogger(), newMockStore(), &Config{StaleTimeout: time.Second})
+	assert.NoError(t, err)
+
+	// no webhook is set
+	monitor.Start()
+	assert.Nil(t, monitor.sub)
+
+	monitor.Close()
+
+	_, err = NewMonitor(hclog.NewNullLogger(), newMockStore(), &Config{Webhooks: []*WebhookConfig{{}}})
+	assert.ErrorIs(t, err, errNoURL)
+}
@@ -0,0 +1,125 @@
+package archive
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+// exportLogInterval is the number of blocks between the progress logs of the export and the import
+const exportLogInterval = 1000
+
+// chainStorage is the blockchain storage the blocks are exported from
+type chainStorage interface {
+	ReadHeadNumber() (uint64, bool)
+	ReadCanonicalHash(uint64) (types.Hash, bool)
+	ReadHeader(types.Hash) (*types.Header, error)
+	ReadBody(types.Hash) (*types.Body, error)
+}
+
+// ExportChain writes the canonical blocks of the storage in the range to the given path,
+// RLP encoded one after the other without metadata. The receipts are not exported,
+// they are computed again when the blocks are imported
+func ExportChain(
+	store chainStorage,
+	logger hclog.Logger,
+	from uint64,
+	to *uint64,
+	outPath string,
+) (uint64, uint64, error) {
+	head, ok := store.ReadHeadNumber()
+	if !ok {
+		return 0, 0, fmt.Errorf("the chain has no head block")
+	}
+
+	if to == nil || *to > head {
+		to = &head
+	}
+
+	if from > *to {
+		return 0, 0, fmt.Errorf("the chain head (%d) is below the beginning height (%d)", head, from)
+	}
+
+	// always create new file, throw error if the file exists
+	fs, err := os.OpenFile(outPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	exportErr := exportBlocks(store, logger, fs, from, *to)
+
+	if err := fs.Close(); err != nil && exportErr == nil {
+		exportErr = err
+	}
+
+	if exportErr != nil {
+		if err := os.Remove(outPath); err != nil {
+			logger.Error("an error occurred while removing file", "err", err)
+		}
+
+		return 0, 0, exportErr
+	}
+
+	return from, *to, nil
+}
+
+// exportBlocks writes the canonical blocks in the range to the writer
+func exportBlocks(store chainStorage, logger hclog.Logger, writer io.Writer, from, to uint64) error {
+	shutdownCh := common.GetTerminationSignalCh()
+
+	for num := from; num <= to; num++ {
+		block, err := readCanonicalBlock(store, num)
+		if err != nil {
+			return err
+		}
+
+		if _, err := writer.Write(block.MarshalRLP()); err != nil {
+			return err
+		}
+
+		if num%exportLogInterval == 0 || num == to {
+			logger.Info("Exported blocks", "number", num, "to", to)
+		}
+
+		select {
+		case <-shutdownCh:
+			return fmt.Errorf("export interrupted at block %d", num)
+		default:
+		}
+	}
+
+	return nil
+}
+
+// readCanonicalBlock reads the block of the canonical chain at the height
+func readCanonicalBlock(store chainStorage, num uint64) (*types.Block, error) {
+	hash, ok := store.ReadCanonicalHash(num)
+	if !ok {
+		return nil, fmt.Errorf("canonical block %d not found", num)
+	}
+
+	header, err := store.ReadHeader(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the header of block %d: %w", num, err)
+	}
+
+	// the genesis block has no body stored
+	if num == 0 {
+		return &types.Block{Header: header}, nil
+	}
+
+	body, err := store.ReadBody(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the body of block %d: %w", num, err)
+	}
+
+	return &types.Block{
+		Header:       header,
+		Transactions: body.Transactions,
+		Uncles:       body.Uncles,
+	}, nil
+}
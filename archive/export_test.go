@@ -0,0 +1,108 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/memory"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestStorage returns a storage with the canonical chain of the genesis and the blocks
+func newTestStorage(t *testing.T) storage.Storage {
+	t.Helper()
+
+	store, err := memory.NewMemoryStorage(hclog.NewNullLogger())
+	assert.NoError(t, err)
+
+	for _, b := range append([]*types.Block{genesis}, blocks...) {
+		assert.NoError(t, store.WriteHeader(b.Header))
+		assert.NoError(t, store.WriteCanonicalHash(b.Number(), b.Hash()))
+
+		if b.Number() != 0 {
+			assert.NoError(t, store.WriteBody(b.Hash(), b.Body()))
+		}
+	}
+
+	assert.NoError(t, store.WriteHeadNumber(blocks[2].Number()))
+
+	return store
+}
+
+// readChainFile returns the blocks of the chain file
+func readChainFile(t *testing.T, path string) []*types.Block {
+	t.Helper()
+
+	fp, err := os.Open(path)
+	assert.NoError(t, err)
+
+	defer fp.Close()
+
+	stream := newBlockStream(fp)
+	res := []*types.Block{}
+
+	for {
+		block, err := stream.nextBlock()
+		assert.NoError(t, err)
+
+		if block == nil {
+			return res
+		}
+
+		res = append(res, block)
+	}
+}
+
+func TestExportChain(t *testing.T) {
+	store := newTestStorage(t)
+	dir := t.TempDir()
+
+	t.Run("should export the whole chain", func(t *testing.T) {
+		path := filepath.Join(dir, "all.rlp")
+
+		from, to, err := ExportChain(store, hclog.NewNullLogger(), 0, nil, path)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), from)
+		assert.Equal(t, blocks[2].Number(), to)
+
+		exported := readChainFile(t, path)
+		if assert.Len(t, exported, 4) {
+			for indx, b := range append([]*types.Block{genesis}, blocks...) {
+				assert.Equal(t, b.Hash(), exported[indx].Hash())
+			}
+		}
+	})
+
+	t.Run("should export the range", func(t *testing.T) {
+		path := filepath.Join(dir, "range.rlp")
+		end := blocks[1].Number()
+
+		from, to, err := ExportChain(store, hclog.NewNullLogger(), 1, &end, path)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), from)
+		assert.Equal(t, end, to)
+
+		exported := readChainFile(t, path)
+		if assert.Len(t, exported, 2) {
+			assert.Equal(t, blocks[0].Hash(), exported[0].Hash())
+			assert.Equal(t, blocks[1].Hash(), exported[1].Hash())
+		}
+	})
+
+	t.Run("should not overwrite a file", func(t *testing.T) {
+		_, _, err := ExportChain(store, hclog.NewNullLogger(), 0, nil, filepath.Join(dir, "all.rlp"))
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail if the range is above the head", func(t *testing.T) {
+		path := filepath.Join(dir, "none.rlp")
+
+		_, _, err := ExportChain(store, hclog.NewNullLogger(), 5, nil, path)
+		assert.Error(t, err)
+		assert.NoFileExists(t, path)
+	})
+}
@@ -0,0 +1,75 @@
+package archive
+
+import (
+	"os"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/helper/progress"
+	"github.com/hashicorp/go-hclog"
+)
+
+// ImportChain writes the blocks of the exported chain file to the chain. The blocks are verified
+// and executed by the chain, the ones it already has are skipped so an interrupted import
+// can be started again. It returns the range of the blocks written, which is empty if
+// the chain had all the blocks of the file
+func ImportChain(
+	chain blockchainInterface,
+	logger hclog.Logger,
+	filePath string,
+	progression *progress.ProgressionWrapper,
+) (uint64, uint64, error) {
+	fp, err := os.Open(filePath)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	defer fp.Close()
+
+	blockStream := newBlockStream(fp)
+	shutdownCh := common.GetTerminationSignalCh()
+
+	// skip existing blocks, the genesis block is checked against the one of the chain
+	firstBlock, err := consumeCommonBlocks(chain, blockStream, shutdownCh)
+	if err != nil || firstBlock == nil {
+		return 0, 0, err
+	}
+
+	progression.StartProgression(firstBlock.Number(), chain.SubscribeEventStream())
+	defer progression.StopProgression()
+
+	var (
+		from      = firstBlock.Number()
+		to        uint64
+		nextBlock = firstBlock
+	)
+
+	for nextBlock != nil {
+		if err := chain.WriteBlock(nextBlock, blockchain.SourceArchive); err != nil {
+			return from, to, err
+		}
+
+		to = nextBlock.Number()
+		progression.UpdateCurrentProgression(to)
+
+		if to%exportLogInterval == 0 {
+			logger.Info("Imported blocks", "from", from, "to", to)
+		}
+
+		select {
+		case <-shutdownCh:
+			logger.Info("Import interrupted, it resumes from the next block when started again", "to", to)
+
+			return from, to, nil
+		default:
+		}
+
+		if nextBlock, err = blockStream.nextBlock(); err != nil {
+			return from, to, err
+		}
+	}
+
+	logger.Info("Imported blocks", "from", from, "to", to)
+
+	return from, to, nil
+}
@@ -0,0 +1,55 @@
+package archive
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/helper/progress"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chain.rlp")
+
+	_, _, err := ExportChain(newTestStorage(t), hclog.NewNullLogger(), 0, nil, path)
+	assert.NoError(t, err)
+
+	importChain := func(chain *mockChain) (uint64, uint64, error) {
+		return ImportChain(chain, hclog.NewNullLogger(), path, progress.NewProgressionWrapper(progress.ChainSyncRestore))
+	}
+
+	t.Run("should resume from the head of the chain", func(t *testing.T) {
+		chain := &mockChain{
+			genesis: genesis,
+			blocks:  []*types.Block{blocks[0]},
+		}
+
+		from, to, err := importChain(chain)
+		assert.NoError(t, err)
+		assert.Equal(t, blocks[1].Number(), from)
+		assert.Equal(t, blocks[2].Number(), to)
+
+		if assert.Len(t, chain.blocks, 3) {
+			assert.Equal(t, blocks[2].Hash(), chain.blocks[2].Hash())
+		}
+
+		// nothing is written once the chain has all the blocks
+		from, to, err = importChain(chain)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), from)
+		assert.Equal(t, uint64(0), to)
+		assert.Len(t, chain.blocks, 3)
+	})
+
+	t.Run("should fail if the genesis does not match", func(t *testing.T) {
+		chain := &mockChain{
+			genesis: blocks[0],
+		}
+
+		_, _, err := importChain(chain)
+		assert.Error(t, err)
+		assert.Empty(t, chain.blocks)
+	})
+}
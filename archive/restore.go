@@ -14,11 +14,11 @@ import (
 )
 
 type blockchainInterface interface {
-	SubscribeEvents() blockchain.Subscription
+	SubscribeEventStream() blockchain.Subscription
 	Genesis() types.Hash
 	GetBlockByNumber(uint64, bool) (*types.Block, bool)
 	GetHashByNumber(uint64) types.Hash
-	WriteBlock(*types.Block) error
+	WriteBlock(*types.Block, string) error
 }
 
 // RestoreChain reads blocks from the archive and write to the chain
@@ -63,7 +63,7 @@ func importBlocks(chain blockchainInterface, blockStream *blockStream, progressi
 	}
 
 	// Create a blockchain subscription for the sync progression and start tracking
-	progression.StartProgression(firstBlock.Number(), chain.SubscribeEvents())
+	progression.StartProgression(firstBlock.Number(), chain.SubscribeEventStream())
 	// Stop monitoring the sync progression upon exit
 	defer progression.StopProgression()
 
@@ -73,7 +73,7 @@ func importBlocks(chain blockchainInterface, blockStream *blockStream, progressi
 	nextBlock := firstBlock
 
 	for {
-		if err := chain.WriteBlock(nextBlock); err != nil {
+		if err := chain.WriteBlock(nextBlock, blockchain.SourceArchive); err != nil {
 			return err
 		}
 
@@ -50,13 +50,13 @@ func (m *mockChain) GetHashByNumber(num uint64) types.Hash {
 	return b.Hash()
 }
 
-func (m *mockChain) WriteBlock(block *types.Block) error {
+func (m *mockChain) WriteBlock(block *types.Block, _ string) error {
 	m.blocks = append(m.blocks, block)
 
 	return nil
 }
 
-func (m *mockChain) SubscribeEvents() blockchain.Subscription {
+func (m *mockChain) SubscribeEventStream() blockchain.Subscription {
 	return protocol.NewMockSubscription()
 }
 
@@ -0,0 +1,358 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
+	"github.com/0xPolygon/polygon-edge/server/proto"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// stateLogInterval is the number of state items between the progress logs of the restore
+const stateLogInterval = 100000
+
+var (
+	errSnapshotInterrupted = errors.New("the snapshot was interrupted")
+)
+
+// snapshotChain is the blockchain a snapshot is restored into
+type snapshotChain interface {
+	Genesis() types.Hash
+	GetHashByNumber(uint64) types.Hash
+	WriteBlockWithReceipts(*types.Block, []*types.Receipt) error
+}
+
+// CreateSnapshot fetches a consistent snapshot of the blocks and the state of the head via gRPC,
+// and saves it to given path. It returns the height of the snapshot
+func CreateSnapshot(conn *grpc.ClientConn, logger hclog.Logger, outPath string) (uint64, error) {
+	// always create new file, throw error if the file exists
+	fs, err := os.OpenFile(outPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return 0, err
+	}
+
+	height, snapshotErr := receiveSnapshot(conn, logger, fs)
+
+	if err := fs.Close(); err != nil && snapshotErr == nil {
+		snapshotErr = err
+	}
+
+	if snapshotErr != nil {
+		if err := os.Remove(outPath); err != nil {
+			logger.Error("an error occurred while removing file", "err", err)
+		}
+
+		return 0, snapshotErr
+	}
+
+	return height, nil
+}
+
+// receiveSnapshot writes the snapshot streamed by the node to the writer
+func receiveSnapshot(conn *grpc.ClientConn, logger hclog.Logger, writer io.Writer) (uint64, error) {
+	signalCh := common.GetTerminationSignalCh()
+	ctx, cancelFn := context.WithCancel(context.Background())
+
+	defer cancelFn()
+
+	go func() {
+		<-signalCh
+		logger.Info("Caught termination signal, shutting down...")
+		cancelFn()
+	}()
+
+	stream, err := proto.NewSystemClient(conn).Snapshot(ctx, &emptypb.Empty{})
+	if err != nil {
+		return 0, err
+	}
+
+	var height *uint64
+
+	for {
+		event, err := stream.Recv()
+		if errors.Is(io.EOF, err) {
+			break
+		}
+
+		if status.Code(err) == codes.Canceled {
+			return 0, errSnapshotInterrupted
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err := writer.Write(event.Data); err != nil {
+			return 0, err
+		}
+
+		height = &event.Height
+
+		logger.Info(
+			"Received snapshot data",
+			"height", event.Height,
+			"blocks", event.Blocks,
+			"state items", event.StateItems,
+		)
+	}
+
+	if height == nil {
+		return 0, errors.New("couldn't get any snapshot data")
+	}
+
+	return *height, nil
+}
+
+// RestoreSnapshot writes the blocks of the snapshot to the chain and the state of the head
+// to the state storage. The blocks are verified by the chain, but not executed. The chain
+// and the state are checked against the manifest, the blocks already in the chain are skipped
+// so an interrupted restore can be started again
+func RestoreSnapshot(
+	chain snapshotChain,
+	stateStorage itrie.Storage,
+	chainID uint64,
+	logger hclog.Logger,
+	filePath string,
+) (*SnapshotManifest, error) {
+	fp, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer fp.Close()
+
+	return restoreSnapshot(newBlockStream(fp), chain, chainID, stateStorage, logger)
+}
+
+// VerifySnapshot checks the integrity of the snapshot without restoring it: the snapshot
+// has all the blocks up to the head of the manifest, and the state of the snapshot is
+// the whole state of the state root of the head
+func VerifySnapshot(logger hclog.Logger, filePath string) (*SnapshotManifest, error) {
+	fp, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer fp.Close()
+
+	// the state is written to a temporary storage to be walked from the root
+	dir, err := ioutil.TempDir("", "snapshot-verify")
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.RemoveAll(dir)
+
+	stateStorage, err := itrie.NewLevelDBStorage(dir, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	defer stateStorage.Close()
+
+	return restoreSnapshot(newBlockStream(fp), nil, 0, stateStorage, logger)
+}
+
+// restoreSnapshot reads the snapshot of the stream, the blocks are written to the chain
+// of the chain id unless it is nil
+func restoreSnapshot(
+	blockStream *blockStream,
+	chain snapshotChain,
+	chainID uint64,
+	stateStorage itrie.Storage,
+	logger hclog.Logger,
+) (*SnapshotManifest, error) {
+	shutdownCh := common.GetTerminationSignalCh()
+
+	manifest, err := blockStream.getSnapshotManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest == nil {
+		return nil, errors.New("expected manifest in snapshot but doesn't exist")
+	}
+
+	if chain != nil {
+		if err := verifySnapshotChain(manifest, chain, chainID); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		head       *types.Header
+		stateItems uint64
+	)
+
+	for {
+		record, err := blockStream.nextSnapshotRecord()
+		if err != nil {
+			return nil, err
+		}
+
+		if record == nil {
+			break
+		}
+
+		switch record.Type {
+		case SnapshotBlock:
+			if stateItems != 0 {
+				return nil, errors.New("unexpected block after the state in snapshot")
+			}
+
+			if err := restoreSnapshotBlock(chain, head, record); err != nil {
+				return nil, err
+			}
+
+			head = record.Block.Header
+
+			if head.Number%exportLogInterval == 0 {
+				logger.Info("Restored blocks", "number", head.Number, "height", manifest.Height)
+			}
+		case SnapshotStateNode:
+			stateStorage.Put(keccak.Keccak256(nil, record.Data), record.Data)
+		case SnapshotCode:
+			stateStorage.SetCode(types.BytesToHash(keccak.Keccak256(nil, record.Data)), record.Data)
+		}
+
+		if record.Type != SnapshotBlock {
+			if stateItems++; stateItems%stateLogInterval == 0 {
+				logger.Info("Restored state items", "items", stateItems)
+			}
+		}
+
+		select {
+		case <-shutdownCh:
+			return nil, errSnapshotInterrupted
+		default:
+		}
+	}
+
+	if err := verifySnapshotHead(manifest, chain, head); err != nil {
+		return nil, err
+	}
+
+	// every item is stored by its hash, so the state is the one of the root if it is complete
+	if err := itrie.VerifyState(manifest.StateRoot, stateStorage); err != nil {
+		return nil, fmt.Errorf("the state of the snapshot is incomplete: %w", err)
+	}
+
+	logger.Info("Restored snapshot", "height", manifest.Height, "state items", stateItems)
+
+	return manifest, nil
+}
+
+// verifySnapshotChain checks that the snapshot is one of the chain
+func verifySnapshotChain(manifest *SnapshotManifest, chain snapshotChain, chainID uint64) error {
+	if manifest.ChainID != chainID {
+		return fmt.Errorf(
+			"the chain id of the snapshot (%d) does not match the chain id (%d)",
+			manifest.ChainID,
+			chainID,
+		)
+	}
+
+	if manifest.GenesisHash != chain.Genesis() {
+		return fmt.Errorf(
+			"the hash of genesis block (%s) does not match blockchain genesis (%s)",
+			manifest.GenesisHash,
+			chain.Genesis(),
+		)
+	}
+
+	return nil
+}
+
+// restoreSnapshotBlock writes the block of the record following the parent, if it is not in the chain yet
+func restoreSnapshotBlock(chain snapshotChain, parent *types.Header, record *SnapshotRecord) error {
+	block := record.Block
+
+	if (parent == nil && block.Number() != 1) || (parent != nil && block.Number() != parent.Number+1) {
+		return fmt.Errorf("unexpected block %d in snapshot", block.Number())
+	}
+
+	if chain == nil {
+		return nil
+	}
+
+	if chain.GetHashByNumber(block.Number()) == block.Hash() {
+		return nil
+	}
+
+	return chain.WriteBlockWithReceipts(block, record.Receipts)
+}
+
+// verifySnapshotHead checks that the head of the snapshot is the one of the manifest
+func verifySnapshotHead(manifest *SnapshotManifest, chain snapshotChain, head *types.Header) error {
+	if manifest.Height == 0 {
+		return nil
+	}
+
+	if head == nil || head.Number != manifest.Height {
+		return fmt.Errorf("the snapshot does not have all the blocks up to the height %d", manifest.Height)
+	}
+
+	if head.StateRoot != manifest.StateRoot {
+		return fmt.Errorf(
+			"the state root of the head (%s) does not match the snapshot (%s)",
+			head.StateRoot,
+			manifest.StateRoot,
+		)
+	}
+
+	if chain != nil && chain.GetHashByNumber(manifest.Height) != manifest.HeadHash {
+		return fmt.Errorf("the hash of the head does not match the snapshot (%s)", manifest.HeadHash)
+	}
+
+	return nil
+}
+
+// getSnapshotManifest consumes some bytes from input and returns parsed SnapshotManifest
+func (b *blockStream) getSnapshotManifest() (*SnapshotManifest, error) {
+	size, err := b.loadRLPArray()
+	if err != nil {
+		return nil, err
+	}
+
+	if size == 0 {
+		return nil, nil
+	}
+
+	manifest := &SnapshotManifest{}
+	if err := manifest.UnmarshalRLP(b.buffer[:size]); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// nextSnapshotRecord consumes some bytes from input and returns parsed SnapshotRecord
+func (b *blockStream) nextSnapshotRecord() (*SnapshotRecord, error) {
+	size, err := b.loadRLPArray()
+	if err != nil {
+		return nil, err
+	}
+
+	if size == 0 {
+		return nil, nil
+	}
+
+	record := &SnapshotRecord{}
+	if err := record.UnmarshalRLP(b.buffer[:size]); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
@@ -0,0 +1,208 @@
+package archive
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSnapshotChain struct {
+	mockChain
+
+	receipts map[types.Hash][]*types.Receipt
+}
+
+func (m *mockSnapshotChain) WriteBlockWithReceipts(block *types.Block, receipts []*types.Receipt) error {
+	m.receipts[block.Hash()] = receipts
+
+	return m.WriteBlock(block, blockchain.SourceArchive)
+}
+
+// newTestSnapshot returns the records of the snapshot of a head with a few accounts,
+// and fills in the head of the manifest
+func newTestSnapshot(t *testing.T, manifest *SnapshotManifest) []*SnapshotRecord {
+	t.Helper()
+
+	source := itrie.NewMemoryStorage()
+	objs := []*state.Object{}
+
+	for i := 0; i < 10; i++ {
+		objs = append(objs, &state.Object{
+			Address: types.BytesToAddress([]byte{byte(i + 1)}),
+			Balance: big.NewInt(int64(i)),
+			Root:    types.EmptyRootHash,
+		})
+	}
+
+	_, root := itrie.NewState(source).NewSnapshot().Commit(objs)
+	manifest.StateRoot = types.BytesToHash(root)
+
+	var (
+		records = []*SnapshotRecord{}
+		parent  = genesis.Header
+	)
+
+	for i := uint64(1); i <= manifest.Height; i++ {
+		header := &types.Header{
+			ParentHash: parent.Hash,
+			Number:     i,
+			StateRoot:  manifest.StateRoot,
+		}
+
+		records = append(records, &SnapshotRecord{
+			Type:     SnapshotBlock,
+			Block:    &types.Block{Header: header.ComputeHash()},
+			Receipts: types.Receipts{},
+		})
+
+		parent = header
+	}
+
+	manifest.HeadHash = parent.Hash
+
+	err := itrie.WalkState(manifest.StateRoot, source, func(item itrie.SyncItem, data []byte) error {
+		records = append(records, &SnapshotRecord{Type: SnapshotStateNode, Data: data})
+
+		return nil
+	})
+	assert.NoError(t, err)
+
+	return records
+}
+
+func newSnapshotStream(manifest *SnapshotManifest, records []*SnapshotRecord) *blockStream {
+	var buf bytes.Buffer
+
+	buf.Write(manifest.MarshalRLP())
+
+	for _, record := range records {
+		buf.Write(record.MarshalRLP())
+	}
+
+	return newBlockStream(&buf)
+}
+
+func Test_restoreSnapshot(t *testing.T) {
+	newManifest := func() *SnapshotManifest {
+		return &SnapshotManifest{
+			ChainID:     100,
+			GenesisHash: genesis.Hash(),
+			Height:      3,
+		}
+	}
+
+	newChain := func() *mockSnapshotChain {
+		return &mockSnapshotChain{
+			mockChain: mockChain{genesis: genesis},
+			receipts:  map[types.Hash][]*types.Receipt{},
+		}
+	}
+
+	restore := func(manifest *SnapshotManifest, records []*SnapshotRecord, chain snapshotChain) error {
+		stream := newSnapshotStream(manifest, records)
+		_, err := restoreSnapshot(stream, chain, 100, itrie.NewMemoryStorage(), hclog.NewNullLogger())
+
+		return err
+	}
+
+	t.Run("should restore the blocks and the state", func(t *testing.T) {
+		manifest := newManifest()
+		records := newTestSnapshot(t, manifest)
+		chain := newChain()
+		stateStorage := itrie.NewMemoryStorage()
+
+		res, err := restoreSnapshot(newSnapshotStream(manifest, records), chain, 100, stateStorage, hclog.NewNullLogger())
+		assert.NoError(t, err)
+		assert.Equal(t, manifest, res)
+
+		assert.Len(t, chain.blocks, 3)
+		assert.Len(t, chain.receipts, 3)
+		assert.Equal(t, manifest.HeadHash, chain.GetHashByNumber(3))
+		assert.NoError(t, itrie.VerifyState(manifest.StateRoot, stateStorage))
+
+		// the restore can be started again
+		_, err = restoreSnapshot(newSnapshotStream(manifest, records), chain, 100, stateStorage, hclog.NewNullLogger())
+		assert.NoError(t, err)
+		assert.Len(t, chain.blocks, 3)
+	})
+
+	t.Run("should verify the snapshot without a chain", func(t *testing.T) {
+		manifest := newManifest()
+
+		assert.NoError(t, restore(manifest, newTestSnapshot(t, manifest), nil))
+	})
+
+	t.Run("should fail if the state is incomplete", func(t *testing.T) {
+		manifest := newManifest()
+		records := newTestSnapshot(t, manifest)
+
+		assert.Error(t, restore(manifest, records[:len(records)-1], nil))
+	})
+
+	t.Run("should fail if blocks are missing", func(t *testing.T) {
+		manifest := newManifest()
+		records := newTestSnapshot(t, manifest)
+
+		manifest.Height = 4
+
+		assert.Error(t, restore(manifest, records, nil))
+	})
+
+	t.Run("should fail if the chain id does not match", func(t *testing.T) {
+		manifest := newManifest()
+		records := newTestSnapshot(t, manifest)
+		chain := newChain()
+
+		manifest.ChainID = 200
+
+		assert.Error(t, restore(manifest, records, chain))
+		assert.Empty(t, chain.blocks)
+	})
+
+	t.Run("should fail if the genesis does not match", func(t *testing.T) {
+		manifest := newManifest()
+		records := newTestSnapshot(t, manifest)
+		chain := newChain()
+
+		manifest.GenesisHash = types.StringToHash("wrong genesis")
+
+		assert.Error(t, restore(manifest, records, chain))
+		assert.Empty(t, chain.blocks)
+	})
+}
+
+func TestSnapshotRecord_RLP(t *testing.T) {
+	records := []*SnapshotRecord{
+		{
+			Type:  SnapshotBlock,
+			Block: blocks[0],
+			Receipts: types.Receipts{
+				{CumulativeGasUsed: 21000, Logs: []*types.Log{}},
+			},
+		},
+		{Type: SnapshotStateNode, Data: []byte{0x1, 0x2}},
+		{Type: SnapshotCode, Data: []byte{0x3}},
+	}
+
+	for _, record := range records {
+		res := &SnapshotRecord{}
+		assert.NoError(t, res.UnmarshalRLP(record.MarshalRLP()))
+		assert.Equal(t, record.Type, res.Type)
+
+		if record.Type == SnapshotBlock {
+			assert.Equal(t, record.Block.Hash(), res.Block.Hash())
+			assert.Equal(t, record.Receipts[0].CumulativeGasUsed, res.Receipts[0].CumulativeGasUsed)
+		} else {
+			assert.Equal(t, record.Data, res.Data)
+		}
+	}
+
+	assert.Error(t, (&SnapshotRecord{}).UnmarshalRLP((&Metadata{}).MarshalRLP()))
+}
@@ -59,3 +59,172 @@ func (m *Metadata) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 
 	return nil
 }
+
+// SnapshotManifest is the data stored in the beginning of a snapshot,
+// it identifies the chain and the head block whose state is in the snapshot
+type SnapshotManifest struct {
+	ChainID     uint64
+	GenesisHash types.Hash
+	Height      uint64
+	HeadHash    types.Hash
+	StateRoot   types.Hash
+}
+
+// MarshalRLP returns RLP encoded bytes
+func (m *SnapshotManifest) MarshalRLP() []byte {
+	return m.MarshalRLPTo(nil)
+}
+
+// MarshalRLPTo sets RLP encoded bytes to given byte slice
+func (m *SnapshotManifest) MarshalRLPTo(dst []byte) []byte {
+	return types.MarshalRLPTo(m.MarshalRLPWith, dst)
+}
+
+// MarshalRLPWith appends own field into arena for encode
+func (m *SnapshotManifest) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	vv := arena.NewArray()
+
+	vv.Set(arena.NewUint(m.ChainID))
+	vv.Set(arena.NewBytes(m.GenesisHash.Bytes()))
+	vv.Set(arena.NewUint(m.Height))
+	vv.Set(arena.NewBytes(m.HeadHash.Bytes()))
+	vv.Set(arena.NewBytes(m.StateRoot.Bytes()))
+
+	return vv
+}
+
+// UnmarshalRLP unmarshals and sets the fields from RLP encoded bytes
+func (m *SnapshotManifest) UnmarshalRLP(input []byte) error {
+	return types.UnmarshalRlp(m.UnmarshalRLPFrom, input)
+}
+
+// UnmarshalRLPFrom sets the fields from parsed RLP encoded value
+func (m *SnapshotManifest) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	if num := len(elems); num != 5 {
+		return fmt.Errorf("not enough elements to decode SnapshotManifest, expected 5 but found %d", num)
+	}
+
+	if m.ChainID, err = elems[0].GetUint64(); err != nil {
+		return err
+	}
+
+	if err = elems[1].GetHash(m.GenesisHash[:]); err != nil {
+		return err
+	}
+
+	if m.Height, err = elems[2].GetUint64(); err != nil {
+		return err
+	}
+
+	if err = elems[3].GetHash(m.HeadHash[:]); err != nil {
+		return err
+	}
+
+	if err = elems[4].GetHash(m.StateRoot[:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SnapshotRecordType is the type of the items following the manifest in a snapshot
+type SnapshotRecordType uint64
+
+const (
+	// SnapshotBlock is a block along with its receipts
+	SnapshotBlock SnapshotRecordType = iota + 1
+	// SnapshotStateNode is a node of the state trie of the head
+	SnapshotStateNode
+	// SnapshotCode is the code of a contract of the state of the head
+	SnapshotCode
+)
+
+// SnapshotRecord is an item of a snapshot, the blocks come first
+// in ascending order, then the state of the head block
+type SnapshotRecord struct {
+	Type SnapshotRecordType
+
+	// Block and Receipts are set for the block records
+	Block    *types.Block
+	Receipts types.Receipts
+
+	// Data is the trie node or the code of the state records
+	Data []byte
+}
+
+// MarshalRLP returns RLP encoded bytes
+func (r *SnapshotRecord) MarshalRLP() []byte {
+	return r.MarshalRLPTo(nil)
+}
+
+// MarshalRLPTo sets RLP encoded bytes to given byte slice
+func (r *SnapshotRecord) MarshalRLPTo(dst []byte) []byte {
+	return types.MarshalRLPTo(r.MarshalRLPWith, dst)
+}
+
+// MarshalRLPWith appends own field into arena for encode
+func (r *SnapshotRecord) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	vv := arena.NewArray()
+
+	vv.Set(arena.NewUint(uint64(r.Type)))
+
+	if r.Type == SnapshotBlock {
+		vv.Set(r.Block.MarshalRLPWith(arena))
+		vv.Set(r.Receipts.MarshalRLPWith(arena))
+	} else {
+		vv.Set(arena.NewCopyBytes(r.Data))
+	}
+
+	return vv
+}
+
+// UnmarshalRLP unmarshals and sets the fields from RLP encoded bytes
+func (r *SnapshotRecord) UnmarshalRLP(input []byte) error {
+	return types.UnmarshalRlp(r.UnmarshalRLPFrom, input)
+}
+
+// UnmarshalRLPFrom sets the fields from parsed RLP encoded value
+func (r *SnapshotRecord) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	if len(elems) < 2 {
+		return fmt.Errorf("not enough elements to decode SnapshotRecord, expected at least 2 but found %d", len(elems))
+	}
+
+	recordType, err := elems[0].GetUint64()
+	if err != nil {
+		return err
+	}
+
+	r.Type = SnapshotRecordType(recordType)
+
+	switch r.Type {
+	case SnapshotBlock:
+		if num := len(elems); num != 3 {
+			return fmt.Errorf("not enough elements to decode the block record, expected 3 but found %d", num)
+		}
+
+		r.Block = &types.Block{}
+		if err := r.Block.UnmarshalRLPFrom(p, elems[1]); err != nil {
+			return err
+		}
+
+		r.Receipts = types.Receipts{}
+
+		return r.Receipts.UnmarshalRLPFrom(p, elems[2])
+	case SnapshotStateNode, SnapshotCode:
+		r.Data, err = elems[1].GetBytes(nil)
+
+		return err
+	default:
+		return fmt.Errorf("unknown snapshot record type %d", recordType)
+	}
+}
@@ -0,0 +1,222 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	badBlocksLimit = 10 // The number of the last rejected blocks kept in memory
+)
+
+// ExtraDecoder is implemented by the consensus mechanisms decoding the extra data of the headers,
+// so the extra data of the rejected blocks is kept in a readable form
+type ExtraDecoder interface {
+	// DecodeExtra returns the decoded extra data of the header
+	DecodeExtra(header *types.Header) (interface{}, error)
+}
+
+// BadBlock is a block rejected by the node, kept along with the reason for the diagnostics
+type BadBlock struct {
+	Block  *types.Block
+	Reason string
+	Time   time.Time
+
+	// Extra is the extra data decoded by the consensus, nil if it can't be decoded
+	Extra interface{}
+
+	// Receipts are the receipts of the block not matching its header, nil if the block is rejected otherwise
+	Receipts []*types.Receipt
+}
+
+// badBlockDump is the file a bad block is dumped to
+type badBlockDump struct {
+	Number uint64      `json:"number"`
+	Hash   types.Hash  `json:"hash"`
+	Reason string      `json:"reason"`
+	Time   time.Time   `json:"time"`
+	RLP    string      `json:"rlp"`
+	Extra  interface{} `json:"extra"`
+
+	Receipts []*badReceiptDump `json:"receipts,omitempty"`
+}
+
+// badReceiptDump is the receipt of a bad block, as it is dumped
+type badReceiptDump struct {
+	TxHash            types.Hash  `json:"transactionHash"`
+	Status            uint64      `json:"status"`
+	CumulativeGasUsed uint64      `json:"cumulativeGasUsed"`
+	Logs              int         `json:"logs"`
+	LogsBloom         types.Bloom `json:"logsBloom"`
+}
+
+// badBlockCache is the ring buffer of the last rejected blocks
+type badBlockCache struct {
+	lock sync.RWMutex
+
+	blocks []*BadBlock // The rejected blocks, from the oldest one
+	limit  int
+
+	dumpDir string // The directory the rejected blocks are dumped to, if set
+}
+
+func newBadBlockCache(limit int) *badBlockCache {
+	return &badBlockCache{
+		blocks: make([]*BadBlock, 0, limit),
+		limit:  limit,
+	}
+}
+
+// add adds the bad block, dropping the oldest one if the cache is full.
+// It returns false if the block is already in the cache
+func (c *badBlockCache) add(bad *BadBlock) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, b := range c.blocks {
+		if b.Block.Hash() == bad.Block.Hash() {
+			return false
+		}
+	}
+
+	if len(c.blocks) == c.limit {
+		c.blocks = append(c.blocks[:0], c.blocks[1:]...)
+	}
+
+	c.blocks = append(c.blocks, bad)
+
+	return true
+}
+
+// list returns the bad blocks, from the latest one
+func (c *badBlockCache) list() []*BadBlock {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	blocks := make([]*BadBlock, len(c.blocks))
+	for indx, bad := range c.blocks {
+		blocks[len(blocks)-1-indx] = bad
+	}
+
+	return blocks
+}
+
+// SetBadBlockDumpDir sets the directory the rejected blocks are dumped to, along with the reason
+func (b *Blockchain) SetBadBlockDumpDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	b.badBlocks.lock.Lock()
+	defer b.badBlocks.lock.Unlock()
+
+	b.badBlocks.dumpDir = dir
+
+	return nil
+}
+
+// BadBlocks returns the last blocks rejected by the node, from the latest one
+func (b *Blockchain) BadBlocks() []*BadBlock {
+	return b.badBlocks.list()
+}
+
+// reportBadBlock keeps the rejected block, and dumps it if the dump directory is set.
+// The blocks of unknown parents are not kept, they are written out of order instead of being bad,
+// nor are the blocks ahead of the local clock, they are written later
+func (b *Blockchain) reportBadBlock(block *types.Block, reason error) {
+	if errors.Is(reason, ErrFutureBlock) {
+		return
+	}
+
+	if _, ok := b.readHeader(block.ParentHash()); !ok {
+		return
+	}
+
+	bad := &BadBlock{
+		Block:  block,
+		Reason: reason.Error(),
+		Time:   time.Now().UTC(),
+	}
+
+	var mismatch *ReceiptsMismatchError
+	if errors.As(reason, &mismatch) {
+		bad.Receipts = mismatch.Receipts
+	}
+
+	if decoder, ok := b.consensus.(ExtraDecoder); ok {
+		if extra, err := decoder.DecodeExtra(block.Header); err == nil {
+			bad.Extra = extra
+		}
+	}
+
+	if !b.badBlocks.add(bad) {
+		return
+	}
+
+	b.logger.Warn(
+		"rejected block",
+		"number", block.Number(),
+		"hash", block.Hash(),
+		"reason", bad.Reason,
+	)
+
+	b.badBlocks.lock.RLock()
+	dumpDir := b.badBlocks.dumpDir
+	b.badBlocks.lock.RUnlock()
+
+	if dumpDir == "" {
+		return
+	}
+
+	if err := dumpBadBlock(dumpDir, bad); err != nil {
+		b.logger.Error("failed to dump the rejected block", "hash", block.Hash(), "err", err)
+	}
+}
+
+// dumpBadBlock writes the bad block to a file of the directory, named by the number and the hash of the block
+func dumpBadBlock(dir string, bad *BadBlock) error {
+	dump := &badBlockDump{
+		Number: bad.Block.Number(),
+		Hash:   bad.Block.Hash(),
+		Reason: bad.Reason,
+		Time:   bad.Time,
+		RLP:    hex.EncodeToHex(bad.Block.MarshalRLP()),
+		Extra:  bad.Extra,
+	}
+
+	for indx, receipt := range bad.Receipts {
+		receiptDump := &badReceiptDump{
+			CumulativeGasUsed: receipt.CumulativeGasUsed,
+			Logs:              len(receipt.Logs),
+			LogsBloom:         types.CreateBloom([]*types.Receipt{receipt}),
+		}
+
+		if receipt.Status != nil {
+			receiptDump.Status = uint64(*receipt.Status)
+		}
+
+		if indx < len(bad.Block.Transactions) {
+			receiptDump.TxHash = bad.Block.Transactions[indx].Hash
+		}
+
+		dump.Receipts = append(dump.Receipts, receiptDump)
+	}
+
+	data, err := json.MarshalIndent(dump, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%s.json", bad.Block.Number(), bad.Block.Hash())
+
+	return ioutil.WriteFile(filepath.Join(dir, name), data, 0600)
+}
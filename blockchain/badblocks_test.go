@@ -0,0 +1,101 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockExtraDecoder is the verifier decoding the extra data as a string
+type mockExtraDecoder struct {
+	MockVerifier
+}
+
+func (m *mockExtraDecoder) DecodeExtra(header *types.Header) (interface{}, error) {
+	return string(header.ExtraData), nil
+}
+
+func TestBadBlockCache(t *testing.T) {
+	cache := newBadBlockCache(3)
+
+	blocks := make([]*BadBlock, 4)
+	for indx := range blocks {
+		header := &types.Header{Number: uint64(indx)}
+		blocks[indx] = &BadBlock{Block: &types.Block{Header: header.ComputeHash()}}
+
+		assert.True(t, cache.add(blocks[indx]))
+	}
+
+	// the same block is kept once
+	assert.False(t, cache.add(&BadBlock{Block: blocks[3].Block}))
+
+	// the oldest block is dropped
+	assert.Equal(t, []*BadBlock{blocks[3], blocks[2], blocks[1]}, cache.list())
+}
+
+func TestWriteBlock_BadBlocks(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+	b.SetConsensus(&mockExtraDecoder{})
+
+	dumpDir := filepath.Join(t.TempDir(), "badblocks")
+	assert.NoError(t, b.SetBadBlockDumpDir(dumpDir))
+
+	header := &types.Header{
+		ParentHash: b.Header().Hash,
+		Number:     1,
+		Sha3Uncles: types.EmptyUncleHash,
+		TxRoot:     types.StringToHash("1"),
+		ExtraData:  []byte("extra"),
+	}
+	block := &types.Block{Header: header.ComputeHash()}
+
+	assert.Error(t, b.WriteBlock(block, SourceSyncer))
+
+	// the same block is rejected again, it is kept once
+	assert.Error(t, b.WriteBlock(block, SourceSyncer))
+
+	badBlocks := b.BadBlocks()
+	assert.Len(t, badBlocks, 1)
+	assert.Equal(t, block, badBlocks[0].Block)
+	assert.Contains(t, badBlocks[0].Reason, "transaction root hash mismatch")
+	assert.Equal(t, "extra", badBlocks[0].Extra)
+
+	// the block is dumped along with its RLP
+	data, err := ioutil.ReadFile(filepath.Join(dumpDir, "1-"+block.Hash().String()+".json"))
+	assert.NoError(t, err)
+
+	var dump badBlockDump
+	assert.NoError(t, json.Unmarshal(data, &dump))
+	assert.Equal(t, block.Hash(), dump.Hash)
+	assert.Equal(t, badBlocks[0].Reason, dump.Reason)
+	assert.Equal(t, "extra", dump.Extra)
+
+	raw, err := hex.DecodeHex(dump.RLP)
+	assert.NoError(t, err)
+
+	decoded := &types.Block{}
+	assert.NoError(t, decoded.UnmarshalRLP(raw))
+	assert.Equal(t, block.Hash(), decoded.Header.ComputeHash().Hash)
+
+	// the blocks of unknown parents are not bad
+	orphan := &types.Header{ParentHash: types.StringToHash("2"), Number: 2}
+	assert.Error(t, b.WriteBlock(&types.Block{Header: orphan.ComputeHash()}, SourceSyncer))
+	assert.Len(t, b.BadBlocks(), 1)
+}
+
+func TestReportBadBlock_FutureBlock(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+
+	header := &types.Header{ParentHash: b.Header().Hash, Number: 1}
+	block := &types.Block{Header: header.ComputeHash()}
+
+	// the blocks ahead of the local clock are written later, they are not bad
+	b.reportBadBlock(block, fmt.Errorf("%w, timestamp too far ahead", ErrFutureBlock))
+	assert.Empty(t, b.BadBlocks())
+}
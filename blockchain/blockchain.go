@@ -4,14 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"path/filepath"
 	"sync"
 	"sync/atomic"
 
 	"github.com/0xPolygon/polygon-edge/blockchain/storage"
-	"github.com/0xPolygon/polygon-edge/blockchain/storage/leveldb"
-	"github.com/0xPolygon/polygon-edge/blockchain/storage/memory"
 	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/helper/common"
 	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/types"
@@ -21,8 +19,20 @@ import (
 	lru "github.com/hashicorp/golang-lru"
 )
 
+var (
+	ErrClosed = errors.New("blockchain is closed")
+
+	// ErrFutureBlock is returned for the blocks too far ahead of the local clock.
+	// The blocks are not invalid, they are written once the local clock catches up
+	ErrFutureBlock = errors.New("block is in the future")
+)
+
 const (
 	BlockGasTargetDivisor uint64 = 1024 // The bound divisor of the gas limit, used in update calculations
+
+	InitialBaseFee       uint64 = 1000000000 // The base fee of the first block past the EIP-1559 fork
+	BaseFeeChangeDenom   uint64 = 8          // The bound divisor of the base fee, used in update calculations
+	ElasticityMultiplier uint64 = 2          // The ratio of the gas limit to the gas target of a block
 )
 
 // Blockchain is a blockchain reference
@@ -41,10 +51,16 @@ type Blockchain struct {
 
 	currentHeader     atomic.Value // The current header
 	currentDifficulty atomic.Value // The current difficulty of the chain (total difficulty)
+	finalizedHeader   atomic.Value // The latest block of the chain with verified seals
 
 	stream *eventStream // Event subscriptions
 
+	writeLock sync.Mutex // Held while a block is written, so the writes can be paused
+	closed    bool       // Set once the storage is closed, the writes fail afterwards
+
 	gpAverage *gasPriceAverage // A reference to the average gas price
+
+	badBlocks *badBlockCache // The last rejected blocks
 }
 
 // gasPriceAverage keeps track of the average gas price (rolling average)
@@ -62,6 +78,20 @@ type Verifier interface {
 	PreStateCommit(header *types.Header, txn *state.Transition) error
 }
 
+// ForkChoice is implemented by the consensus mechanisms choosing between the competing
+// blocks of the same height and difficulty. The first written block is kept otherwise
+type ForkChoice interface {
+	// PreferHeader returns whether the incoming header replaces the current head
+	PreferHeader(current, incoming *types.Header) bool
+}
+
+// SealRecoverer is implemented by the consensus mechanisms recovering the seals of a batch
+// of headers concurrently, ahead of the verification of the headers in order
+type SealRecoverer interface {
+	// RecoverSeals recovers the seals of the headers, the failures are left to the verification
+	RecoverSeals(headers []*types.Header)
+}
+
 type Executor interface {
 	ProcessBlock(parentRoot types.Hash, block *types.Block, blockCreator types.Address) (*state.Transition, error)
 }
@@ -70,6 +100,9 @@ type BlockResult struct {
 	Root     types.Hash
 	Receipts []*types.Receipt
 	TotalGas uint64
+
+	// InternalTxs are the internal transactions of the block, nil if they are not indexed
+	InternalTxs []*types.InternalTransaction
 }
 
 // updateGasPriceAvg updates the rolling average value of the gas price
@@ -150,10 +183,10 @@ func (b *Blockchain) GetAvgGasPrice() *big.Int {
 	return b.gpAverage.price
 }
 
-// NewBlockchain creates a new blockchain object
+// NewBlockchain creates a new blockchain object on the storage
 func NewBlockchain(
 	logger hclog.Logger,
-	dataDir string,
+	db storage.Storage,
 	config *chain.Chain,
 	consensus Verifier,
 	executor Executor,
@@ -163,32 +196,15 @@ func NewBlockchain(
 		config:    config,
 		consensus: consensus,
 		executor:  executor,
-		stream:    &eventStream{},
+		db:        db,
 		gpAverage: &gasPriceAverage{
 			price: big.NewInt(0),
 			count: big.NewInt(0),
 		},
+		badBlocks: newBadBlockCache(badBlocksLimit),
 	}
 
-	var (
-		db  storage.Storage
-		err error
-	)
-
-	if dataDir == "" {
-		if db, err = memory.NewMemoryStorage(nil); err != nil {
-			return nil, err
-		}
-	} else {
-		if db, err = leveldb.NewLevelDBStorage(
-			filepath.Join(dataDir, "blockchain"),
-			logger,
-		); err != nil {
-			return nil, err
-		}
-	}
-
-	b.db = db
+	b.stream = &eventStream{logger: b.logger}
 
 	b.headersCache, _ = lru.New(100)
 	b.difficultyCache, _ = lru.New(100)
@@ -221,7 +237,12 @@ func (b *Blockchain) ComputeGenesis() error {
 			return fmt.Errorf("failed to get header with hash %s", head.String())
 		}
 
-		diff, ok := b.GetTD(head)
+		header, err := b.rollbackPartialHead(header)
+		if err != nil {
+			return fmt.Errorf("failed to roll back the partially written head: %w", err)
+		}
+
+		diff, ok := b.GetTD(header.Hash)
 		if !ok {
 			return fmt.Errorf("failed to read difficulty")
 		}
@@ -235,11 +256,23 @@ func (b *Blockchain) ComputeGenesis() error {
 		)
 
 		b.setCurrentHeader(header, diff)
+
+		if err := b.loadFinalizedHeader(header); err != nil {
+			return fmt.Errorf("failed to load the finalized header: %w", err)
+		}
+
+		if err := b.migrateTxLookups(); err != nil {
+			return fmt.Errorf("failed to migrate the transaction lookups: %w", err)
+		}
 	} else {
 		// empty storage, write the genesis
 		if err := b.writeGenesis(b.config.Genesis); err != nil {
 			return err
 		}
+
+		if err := b.db.WriteTxLookupVersion(txLookupVersion); err != nil {
+			return err
+		}
 	}
 
 	b.logger.Info("genesis", "hash", b.config.Genesis.Hash())
@@ -247,6 +280,79 @@ func (b *Blockchain) ComputeGenesis() error {
 	return nil
 }
 
+// rollbackPartialHead moves the head back to the parent while the head block is not fully
+// written, as left by a node stopped in the middle of a write before the blocks were written
+// in a single batch. The new head is returned
+func (b *Blockchain) rollbackPartialHead(head *types.Header) (*types.Header, error) {
+	for head.Number > 0 {
+		body, complete := b.readWrittenBlock(head)
+		if complete {
+			break
+		}
+
+		parent, ok := b.readHeader(head.ParentHash)
+		if !ok {
+			return nil, fmt.Errorf("parent of %s (%d) not found", head.Hash, head.Number)
+		}
+
+		b.logger.Warn("rolling back the partially written head", "number", head.Number, "hash", head.Hash)
+
+		batch := b.db.NewBatch()
+
+		if body != nil {
+			for _, txn := range body.Transactions {
+				if err := batch.DeleteTxLookup(txn.Hash); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if hash, ok := b.db.ReadCanonicalHash(head.Number); ok && hash == head.Hash {
+			if err := batch.DeleteCanonicalHash(head.Number); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := batch.WriteHeadHash(parent.Hash); err != nil {
+			return nil, err
+		}
+
+		if err := batch.WriteHeadNumber(parent.Number); err != nil {
+			return nil, err
+		}
+
+		if err := batch.Write(); err != nil {
+			return nil, err
+		}
+
+		head = parent
+	}
+
+	return head, nil
+}
+
+// readWrittenBlock reads the body of the block, and checks if the receipts, the total difficulty
+// and the canonical hash of the block are written too. The body is nil if not written
+func (b *Blockchain) readWrittenBlock(header *types.Header) (*types.Body, bool) {
+	body, err := b.db.ReadBody(header.Hash)
+	if err != nil {
+		return nil, false
+	}
+
+	receipts, err := b.db.ReadReceipts(header.Hash)
+	if err != nil || len(receipts) != len(body.Transactions) {
+		return body, false
+	}
+
+	if _, ok := b.db.ReadTotalDifficulty(header.Hash); !ok {
+		return body, false
+	}
+
+	hash, ok := b.db.ReadCanonicalHash(header.Number)
+
+	return body, ok && hash == header.Hash
+}
+
 func (b *Blockchain) GetConsensus() Verifier {
 	return b.consensus
 }
@@ -267,6 +373,46 @@ func (b *Blockchain) setCurrentHeader(h *types.Header, diff *big.Int) {
 	b.currentDifficulty.Store(difficulty)
 }
 
+// setFinalizedHeader sets the finalized header
+func (b *Blockchain) setFinalizedHeader(h *types.Header) {
+	b.finalizedHeader.Store(h.Copy())
+}
+
+// loadFinalizedHeader loads the finalized header of the storage. The head is finalized
+// if none is written, or if the written one is not canonical anymore
+func (b *Blockchain) loadFinalizedHeader(head *types.Header) error {
+	hash, ok := b.db.ReadFinalizedHash()
+	if !ok {
+		b.setFinalizedHeader(head)
+
+		return nil
+	}
+
+	header, ok := b.readHeader(hash)
+	if !ok {
+		return fmt.Errorf("header %s not found", hash)
+	}
+
+	if canonical, ok := b.db.ReadCanonicalHash(header.Number); !ok || canonical != hash || header.Number > head.Number {
+		header = head
+	}
+
+	b.setFinalizedHeader(header)
+
+	return nil
+}
+
+// FinalizedHeader returns the latest block of the chain whose seals were verified by the consensus
+// when it was written, rather than accepted as a header only (atomic). Its ancestors are final too
+func (b *Blockchain) FinalizedHeader() *types.Header {
+	header, ok := b.finalizedHeader.Load().(*types.Header)
+	if !ok {
+		return nil
+	}
+
+	return header
+}
+
 // Header returns the current header (atomic)
 func (b *Blockchain) Header() *types.Header {
 	header, ok := b.currentHeader.Load().(*types.Header)
@@ -322,6 +468,49 @@ func (b *Blockchain) CalculateGasLimit(number uint64) (uint64, error) {
 	return b.calculateGasLimit(parent.GasLimit), nil
 }
 
+// CalculateBaseFee calculates the base fee of the block following the parent, as in EIP-1559.
+// The base fee is 0 before the fork
+func (b *Blockchain) CalculateBaseFee(parent *types.Header) uint64 {
+	if !b.Config().Forks.IsEIP1559(parent.Number + 1) {
+		return 0
+	}
+
+	return calculateBaseFee(parent)
+}
+
+// calculateBaseFee moves the parent base fee up to 1/8 towards the block usage, in reference to the gas target
+func calculateBaseFee(parent *types.Header) uint64 {
+	// the first block past the fork
+	if parent.BaseFee == 0 {
+		return InitialBaseFee
+	}
+
+	gasTarget := parent.GasLimit / ElasticityMultiplier
+	if gasTarget == 0 || parent.GasUsed == gasTarget {
+		return parent.BaseFee
+	}
+
+	var gasDelta uint64
+	if parent.GasUsed > gasTarget {
+		gasDelta = parent.GasUsed - gasTarget
+	} else {
+		gasDelta = gasTarget - parent.GasUsed
+	}
+
+	// baseFee * gasDelta / gasTarget / BaseFeeChangeDenom, without overflows
+	delta := new(big.Int).SetUint64(parent.BaseFee)
+	delta.Mul(delta, new(big.Int).SetUint64(gasDelta))
+	delta.Div(delta, new(big.Int).SetUint64(gasTarget))
+	delta.Div(delta, new(big.Int).SetUint64(BaseFeeChangeDenom))
+
+	if parent.GasUsed > gasTarget {
+		// the base fee increases by at least 1 when the block is over the target
+		return parent.BaseFee + common.Max(delta.Uint64(), 1)
+	}
+
+	return parent.BaseFee - delta.Uint64()
+}
+
 // calculateGasLimit calculates gas limit in reference to the block gas target
 func (b *Blockchain) calculateGasLimit(parentGasLimit uint64) uint64 {
 	// The gas limit cannot move more than 1/1024 * parentGasLimit
@@ -371,15 +560,29 @@ func (b *Blockchain) writeGenesisImpl(header *types.Header) error {
 	b.genesis = header.Hash
 
 	// Update the DB
-	if err := b.db.WriteHeader(header); err != nil {
+	batch := b.db.NewBatch()
+
+	if err := batch.WriteHeader(header); err != nil {
 		return err
 	}
 
 	// Advance the head
-	if _, err := b.advanceHead(header); err != nil {
+	diff, err := b.writeHead(batch, header)
+	if err != nil {
+		return err
+	}
+
+	if err := batch.WriteFinalizedHash(header.Hash); err != nil {
 		return err
 	}
 
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	b.setCurrentHeader(header, diff)
+	b.setFinalizedHeader(header)
+
 	// Create an event and send it to the stream
 	event := &Event{}
 	event.AddNewHeader(header)
@@ -407,15 +610,15 @@ func (b *Blockchain) GetTD(hash types.Hash) (*big.Int, bool) {
 	return b.readTotalDifficulty(hash)
 }
 
-// writeCanonicalHeader writes the new header
-func (b *Blockchain) writeCanonicalHeader(event *Event, h *types.Header) error {
+// writeCanonicalHeader writes the new header as the head of the chain
+func (b *Blockchain) writeCanonicalHeader(batch storage.Writer, event *Event, h *types.Header) error {
 	parentTD, ok := b.readTotalDifficulty(h.ParentHash)
 	if !ok {
 		return fmt.Errorf("parent difficulty not found")
 	}
 
 	newTD := big.NewInt(0).Add(parentTD, new(big.Int).SetUint64(h.Difficulty))
-	if err := b.db.WriteCanonicalHeader(h, newTD); err != nil {
+	if err := batch.WriteCanonicalHeader(h, newTD); err != nil {
 		return err
 	}
 
@@ -423,25 +626,43 @@ func (b *Blockchain) writeCanonicalHeader(event *Event, h *types.Header) error {
 	event.AddNewHeader(h)
 	event.SetDifficulty(newTD)
 
-	b.setCurrentHeader(h, newTD)
-
 	return nil
 }
 
 // advanceHead Sets the passed in header as the new head of the chain
 func (b *Blockchain) advanceHead(newHeader *types.Header) (*big.Int, error) {
+	batch := b.db.NewBatch()
+
+	newTD, err := b.writeHead(batch, newHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+
+	// Update the blockchain reference
+	b.setCurrentHeader(newHeader, newTD)
+
+	return newTD, nil
+}
+
+// writeHead writes the passed in header as the new head of the chain to the batch,
+// the new total difficulty is returned
+func (b *Blockchain) writeHead(batch storage.Writer, newHeader *types.Header) (*big.Int, error) {
 	// Write the current head hash into storage
-	if err := b.db.WriteHeadHash(newHeader.Hash); err != nil {
+	if err := batch.WriteHeadHash(newHeader.Hash); err != nil {
 		return nil, err
 	}
 
 	// Write the current head number into storage
-	if err := b.db.WriteHeadNumber(newHeader.Number); err != nil {
+	if err := batch.WriteHeadNumber(newHeader.Number); err != nil {
 		return nil, err
 	}
 
 	// Matches the current head number with the current hash
-	if err := b.db.WriteCanonicalHash(newHeader.Number, newHeader.Hash); err != nil {
+	if err := batch.WriteCanonicalHash(newHeader.Number, newHeader.Hash); err != nil {
 		return nil, err
 	}
 
@@ -459,13 +680,10 @@ func (b *Blockchain) advanceHead(newHeader *types.Header) (*big.Int, error) {
 
 	// Calculate the new total difficulty
 	newTD := big.NewInt(0).Add(parentTD, big.NewInt(0).SetUint64(newHeader.Difficulty))
-	if err := b.db.WriteTotalDifficulty(newHeader.Hash, newTD); err != nil {
+	if err := batch.WriteTotalDifficulty(newHeader.Hash, newTD); err != nil {
 		return nil, err
 	}
 
-	// Update the blockchain reference
-	b.setCurrentHeader(newHeader, newTD)
-
 	return newTD, nil
 }
 
@@ -474,6 +692,20 @@ func (b *Blockchain) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error
 	return b.db.ReadReceipts(hash)
 }
 
+// GetInternalTxsByHash returns the internal transactions of the block, if it was indexed
+func (b *Blockchain) GetInternalTxsByHash(hash types.Hash) ([]*types.InternalTransaction, bool) {
+	internalTxs, err := b.db.ReadInternalTxs(hash)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			b.logger.Error("failed to read internal transactions", "err", err)
+		}
+
+		return nil, false
+	}
+
+	return internalTxs, true
+}
+
 // GetBodyByHash returns the body by their hash
 func (b *Blockchain) GetBodyByHash(hash types.Hash) (*types.Body, bool) {
 	return b.readBody(hash)
@@ -564,6 +796,14 @@ func (b *Blockchain) GetHeaderByNumber(n uint64) (*types.Header, bool) {
 	return h, true
 }
 
+// PauseWrites stops the writes of the blocks until the returned function is called.
+// The write in progress, if any, is completed first so the chain stops at a block boundary
+func (b *Blockchain) PauseWrites() func() {
+	b.writeLock.Lock()
+
+	return b.writeLock.Unlock
+}
+
 // WriteHeaders writes an array of headers
 func (b *Blockchain) WriteHeaders(headers []*types.Header) error {
 	return b.WriteHeadersWithBodies(headers)
@@ -571,6 +811,13 @@ func (b *Blockchain) WriteHeaders(headers []*types.Header) error {
 
 // WriteHeadersWithBodies writes a batch of headers
 func (b *Blockchain) WriteHeadersWithBodies(headers []*types.Header) error {
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
 	// Check the size
 	if len(headers) == 0 {
 		return fmt.Errorf("passed in headers array is empty")
@@ -596,11 +843,30 @@ func (b *Blockchain) WriteHeadersWithBodies(headers []*types.Header) error {
 
 	// Write the actual headers
 	for _, h := range headers {
-		event := &Event{}
-		if err := b.writeHeaderImpl(event, h); err != nil {
+		batch := b.db.NewBatch()
+
+		event := &Event{Source: SourceSyncer}
+		if err := b.writeHeaderImpl(batch, event, h); err != nil {
 			return err
 		}
 
+		// the seals of the headers are not verified, they only move the finalized block
+		// back to the common ancestor if a reorg drops it
+		finalized := b.finalizedAfterReorg(event)
+		if finalized != nil {
+			if err := batch.WriteFinalizedHash(finalized.Hash); err != nil {
+				return err
+			}
+		}
+
+		if err := b.commitBatch(batch, event, h); err != nil {
+			return err
+		}
+
+		if finalized != nil {
+			b.setFinalizedHeader(finalized)
+		}
+
 		// Notify the event stream
 		b.dispatchEvent(event)
 	}
@@ -608,8 +874,15 @@ func (b *Blockchain) WriteHeadersWithBodies(headers []*types.Header) error {
 	return nil
 }
 
-// WriteBlock writes a single block
-func (b *Blockchain) WriteBlock(block *types.Block) error {
+// WriteBlock writes a single block, the source is passed to the subscribers of the events
+func (b *Blockchain) WriteBlock(block *types.Block, source string) error {
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
 	// Check the param
 	if block == nil {
 		return fmt.Errorf("the passed in block is empty")
@@ -624,6 +897,118 @@ func (b *Blockchain) WriteBlock(block *types.Block) error {
 		block.ParentHash(),
 	)
 
+	if err := b.verifyBlock(block); err != nil {
+		b.reportBadBlock(block, err)
+
+		return err
+	}
+
+	// Checks are passed, process and validate the block
+	res, err := b.processBlock(block)
+	if err != nil {
+		b.reportBadBlock(block, err)
+
+		return err
+	}
+
+	return b.writeBlockWithReceipts(block, res.Receipts, res.InternalTxs, source)
+}
+
+// WriteBlockWithReceipts writes a single block along with its receipts, without executing it.
+// The header, the body and the receipts are verified against each other, but the state
+// of the block is not available afterwards. It is used by the fast sync for the blocks
+// before the pivot block
+func (b *Blockchain) WriteBlockWithReceipts(block *types.Block, receipts []*types.Receipt) error {
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	// Check the param
+	if block == nil {
+		return fmt.Errorf("the passed in block is empty")
+	}
+
+	if err := b.verifyBlock(block); err != nil {
+		b.reportBadBlock(block, err)
+
+		return err
+	}
+
+	if err := b.verifyReceipts(block, receipts); err != nil {
+		b.reportBadBlock(block, err)
+
+		return err
+	}
+
+	return b.writeBlockWithReceipts(block, receipts, nil, SourceSyncer)
+}
+
+// verifyReceipts verifies the receipts of the block, which is not executed, against its header
+func (b *Blockchain) verifyReceipts(block *types.Block, receipts []*types.Receipt) error {
+	if len(receipts) != len(block.Transactions) {
+		return fmt.Errorf("bad size of receipts and transactions")
+	}
+
+	if err := verifyReceiptsRoot(block, receipts); err != nil {
+		return err
+	}
+
+	header := block.Header
+
+	if err := b.fillReceipts(block, receipts); err != nil {
+		return err
+	}
+
+	if len(receipts) != 0 && receipts[len(receipts)-1].CumulativeGasUsed != header.GasUsed {
+		return fmt.Errorf("gas used is different")
+	}
+
+	if gasLimitErr := b.verifyGasLimit(header); gasLimitErr != nil {
+		return fmt.Errorf("invalid gas limit, %w", gasLimitErr)
+	}
+
+	if baseFeeErr := b.verifyBaseFee(header); baseFeeErr != nil {
+		return fmt.Errorf("invalid base fee, %w", baseFeeErr)
+	}
+
+	return nil
+}
+
+// fillReceipts fills in the context fields of the receipts of the block,
+// which are not part of the receipts received from the peers
+func (b *Blockchain) fillReceipts(block *types.Block, receipts []*types.Receipt) error {
+	signer := crypto.NewSigner(b.config.Params.Forks.At(block.Number()), uint64(b.config.Params.ChainID))
+
+	var cumulativeGasUsed uint64
+
+	for indx, receipt := range receipts {
+		txn := block.Transactions[indx]
+
+		receipt.TxHash = txn.Hash
+		receipt.GasUsed = receipt.CumulativeGasUsed - cumulativeGasUsed
+		receipt.EffectiveGasPrice = txn.EffectiveGasPrice(block.Header.GetBaseFee())
+		cumulativeGasUsed = receipt.CumulativeGasUsed
+
+		if txn.To != nil {
+			continue
+		}
+
+		from, err := signer.Sender(txn)
+		if err != nil {
+			return fmt.Errorf("failed to recover the sender of %s: %w", txn.Hash, err)
+		}
+
+		receipt.ContractAddress = crypto.CreateAddress(from, txn.Nonce)
+	}
+
+	return nil
+}
+
+// verifyBlock verifies the header and the body of the block against its parent
+func (b *Blockchain) verifyBlock(block *types.Block) error {
 	parent, ok := b.readHeader(block.ParentHash())
 	if !ok {
 		return fmt.Errorf(
@@ -675,32 +1060,74 @@ func (b *Blockchain) WriteBlock(block *types.Block) error {
 		)
 	}
 
-	// Checks are passed, write the chain
+	// Verify the transactions are within the size limits of the chain, past the fork
+	if err := b.config.Params.SizeLimitsAt(block.Number()).VerifyTransactions(block.Transactions); err != nil {
+		return fmt.Errorf("invalid block size: %w", err)
+	}
+
+	return nil
+}
+
+// writeBlockWithReceipts writes the verified block, its receipts and its internal transactions, if indexed.
+// All the components of the block are committed in a single batch,
+// so a node stopped in the middle of the write does not leave a partial block
+func (b *Blockchain) writeBlockWithReceipts(
+	block *types.Block,
+	receipts []*types.Receipt,
+	internalTxs []*types.InternalTransaction,
+	source string,
+) error {
 	header := block.Header
+	batch := b.db.NewBatch()
 
-	// Process and validate the block
-	res, err := b.processBlock(block)
-	if err != nil {
+	if err := b.writeBody(batch, block); err != nil {
 		return err
 	}
 
-	if err := b.writeBody(block); err != nil {
+	// Write the header to the chain
+	evnt := &Event{Source: source}
+	if err := b.writeHeaderImpl(batch, evnt, header); err != nil {
 		return err
 	}
 
-	// Write the header to the chain
-	evnt := &Event{}
-	if err := b.writeHeaderImpl(evnt, header); err != nil {
+	// Write txn lookups (txHash -> block, index) of the new head. The reorgs update
+	// the lookups of the rest of the new chain, the body of the block is not readable
+	// before the batch is written
+	if evnt.Type == EventHead || evnt.Type == EventReorg {
+		if err := b.writeTxLookups(batch, block.Hash(), block.Transactions); err != nil {
+			return err
+		}
+	}
+
+	if err := batch.WriteReceipts(block.Hash(), receipts); err != nil {
 		return err
 	}
 
-	// write the receipts, do it only after the header has been written.
-	// Otherwise, a client might ask for a header once the receipt is valid
-	// but before it is written into the storage
-	if err := b.db.WriteReceipts(block.Hash(), res.Receipts); err != nil {
+	// The internal transactions are written by block hash, like the receipts, so the ones
+	// of the blocks of the new chain are already there when a reorg makes them canonical
+	if internalTxs != nil {
+		if err := batch.WriteInternalTxs(block.Hash(), internalTxs); err != nil {
+			return err
+		}
+	}
+
+	// The header of the block is verified by the consensus, with its seals,
+	// so the block is finalized once it is the head
+	isHead := evnt.Type == EventHead || evnt.Type == EventReorg
+	if isHead {
+		if err := batch.WriteFinalizedHash(header.Hash); err != nil {
+			return err
+		}
+	}
+
+	if err := b.commitBatch(batch, evnt, header); err != nil {
 		return err
 	}
 
+	if isHead {
+		b.setFinalizedHeader(header)
+	}
+
 	//	update snapshot
 	if err := b.consensus.ProcessHeaders([]*types.Header{header}); err != nil {
 		return err
@@ -738,39 +1165,25 @@ func (b *Blockchain) updateGasPriceAvgWithBlock(block *types.Block) {
 
 	gasPrices := make([]*big.Int, len(block.Transactions))
 	for i, transaction := range block.Transactions {
-		gasPrices[i] = transaction.GasPrice
+		gasPrices[i] = transaction.EffectiveGasPrice(block.Header.GetBaseFee())
 	}
 
 	b.updateGasPriceAvg(gasPrices)
 }
 
-// writeBody writes the block body to the DB.
-// Additionally, it also updates the txn lookup, for txnHash -> block lookups
-func (b *Blockchain) writeBody(block *types.Block) error {
+// writeBody writes the block body to the batch.
+// The txn lookups are only written once the block is canonical
+func (b *Blockchain) writeBody(batch storage.Writer, block *types.Block) error {
 	body := block.Body()
 
 	// Write the full body (txns + receipts)
-	if err := b.db.WriteBody(block.Header.Hash, body); err != nil {
+	if err := batch.WriteBody(block.Header.Hash, body); err != nil {
 		return err
 	}
 
-	// Write txn lookups (txHash -> block)
-	for _, txn := range block.Transactions {
-		if err := b.db.WriteTxLookup(txn.Hash, block.Hash()); err != nil {
-			return err
-		}
-	}
-
 	return nil
 }
 
-// ReadTxLookup returns the block hash using the transaction hash
-func (b *Blockchain) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
-	v, ok := b.db.ReadTxLookup(hash)
-
-	return v, ok
-}
-
 // processBlock Processes the block, and does validation
 func (b *Blockchain) processBlock(block *types.Block) (*BlockResult, error) {
 	header := block.Header
@@ -812,19 +1225,23 @@ func (b *Blockchain) processBlock(block *types.Block) (*BlockResult, error) {
 		return nil, fmt.Errorf("gas used is different")
 	}
 
-	receiptSha := buildroot.CalculateReceiptsRoot(receipts)
-	if receiptSha != header.ReceiptsRoot {
-		return nil, fmt.Errorf("invalid receipts root")
+	if err := verifyReceiptsRoot(block, receipts); err != nil {
+		return nil, err
 	}
 
 	if gasLimitErr := b.verifyGasLimit(header); gasLimitErr != nil {
 		return nil, fmt.Errorf("invalid gas limit, %w", gasLimitErr)
 	}
 
+	if baseFeeErr := b.verifyBaseFee(header); baseFeeErr != nil {
+		return nil, fmt.Errorf("invalid base fee, %w", baseFeeErr)
+	}
+
 	return &BlockResult{
-		Root:     root,
-		Receipts: receipts,
-		TotalGas: totalGas,
+		Root:        root,
+		Receipts:    receipts,
+		TotalGas:    totalGas,
+		InternalTxs: txn.InternalTxs(),
 	}, nil
 }
 
@@ -861,13 +1278,32 @@ func (b *Blockchain) verifyGasLimit(header *types.Header) error {
 			"invalid gas limit, limit = %d, want %d +- %d",
 			header.GasLimit,
 			parent.GasLimit,
-			limit-1,
+			limit,
 		)
 	}
 
 	return nil
 }
 
+// verifyBaseFee is a helper function for validating the base fee in a header
+func (b *Blockchain) verifyBaseFee(header *types.Header) error {
+	// Skip the base fee check for genesis
+	if header.Number == 0 {
+		return nil
+	}
+
+	parent, ok := b.GetHeaderByNumber(header.Number - 1)
+	if !ok {
+		return fmt.Errorf("parent of %d not found", header.Number)
+	}
+
+	if expected := b.CalculateBaseFee(parent); header.BaseFee != expected {
+		return fmt.Errorf("base fee = %d, want %d", header.BaseFee, expected)
+	}
+
+	return nil
+}
+
 // GetHashHelper is used by the EVM, so that the SC can get the hash of the header number
 func (b *Blockchain) GetHashHelper(header *types.Header) func(i uint64) (res types.Hash) {
 	return func(i uint64) (res types.Hash) {
@@ -911,17 +1347,18 @@ func (b *Blockchain) dispatchEvent(evnt *Event) {
 	b.stream.push(evnt)
 }
 
-// writeHeaderImpl writes a block and the data, assumes the genesis is already set
-func (b *Blockchain) writeHeaderImpl(evnt *Event, header *types.Header) error {
+// writeHeaderImpl writes a block and the data to the batch, assumes the genesis is already set.
+// The header becomes the head of the chain if the event is a head or a reorg event
+func (b *Blockchain) writeHeaderImpl(batch storage.Writer, evnt *Event, header *types.Header) error {
 	currentHeader := b.Header()
 
 	// Write the data
 	if header.ParentHash == currentHeader.Hash {
 		// Fast path to save the new canonical header
-		return b.writeCanonicalHeader(evnt, header)
+		return b.writeCanonicalHeader(batch, evnt, header)
 	}
 
-	if err := b.db.WriteHeader(header); err != nil {
+	if err := batch.WriteHeader(header); err != nil {
 		return err
 	}
 
@@ -941,7 +1378,7 @@ func (b *Blockchain) writeHeaderImpl(evnt *Event, header *types.Header) error {
 	}
 
 	// Write the difficulty
-	if err := b.db.WriteTotalDifficulty(
+	if err := batch.WriteTotalDifficulty(
 		header.Hash,
 		big.NewInt(0).Add(
 			parentTD,
@@ -951,13 +1388,10 @@ func (b *Blockchain) writeHeaderImpl(evnt *Event, header *types.Header) error {
 		return err
 	}
 
-	// Update the headers cache
-	b.headersCache.Add(header.Hash, header)
-
 	incomingTD := big.NewInt(0).Add(parentTD, big.NewInt(0).SetUint64(header.Difficulty))
-	if incomingTD.Cmp(currentTD) > 0 {
-		// new block has higher difficulty, reorg the chain
-		if err := b.handleReorg(evnt, currentHeader, header); err != nil {
+	if cmp := incomingTD.Cmp(currentTD); cmp > 0 || (cmp == 0 && b.preferHeader(currentHeader, header)) {
+		// new block has higher difficulty, or is preferred by the consensus, reorg the chain
+		if err := b.handleReorg(batch, evnt, currentHeader, header); err != nil {
 			return err
 		}
 	} else {
@@ -965,7 +1399,7 @@ func (b *Blockchain) writeHeaderImpl(evnt *Event, header *types.Header) error {
 		evnt.AddOldHeader(header)
 		evnt.Type = EventFork
 
-		if err := b.writeFork(header); err != nil {
+		if err := b.writeFork(batch, header); err != nil {
 			return err
 		}
 	}
@@ -973,8 +1407,51 @@ func (b *Blockchain) writeHeaderImpl(evnt *Event, header *types.Header) error {
 	return nil
 }
 
-// writeFork writes the new header forks to the DB
-func (b *Blockchain) writeFork(header *types.Header) error {
+// commitBatch writes the batch of the header, and moves the head of the chain to the header
+// if it became the head. The in-memory references are only updated once the batch is written
+func (b *Blockchain) commitBatch(batch storage.Batch, evnt *Event, header *types.Header) error {
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	// Update the headers cache
+	b.headersCache.Add(header.Hash, header)
+
+	if evnt.Type == EventHead || evnt.Type == EventReorg {
+		b.setCurrentHeader(header, evnt.Difficulty)
+	}
+
+	return nil
+}
+
+// finalizedAfterReorg returns the common ancestor of the reorg of the event if the reorg
+// drops the finalized block, nil otherwise
+func (b *Blockchain) finalizedAfterReorg(evnt *Event) *types.Header {
+	if evnt.Type != EventReorg || len(evnt.OldChain) == 0 {
+		return nil
+	}
+
+	finalized := b.FinalizedHeader()
+
+	for _, header := range evnt.OldChain {
+		if header.Hash != finalized.Hash {
+			continue
+		}
+
+		// the dropped headers are listed from the lowest one
+		ancestor, ok := b.readHeader(evnt.OldChain[0].ParentHash)
+		if !ok {
+			return nil
+		}
+
+		return ancestor
+	}
+
+	return nil
+}
+
+// writeFork writes the new header forks to the batch
+func (b *Blockchain) writeFork(batch storage.Writer, header *types.Header) error {
 	forks, err := b.db.ReadForks()
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
@@ -993,92 +1470,91 @@ func (b *Blockchain) writeFork(header *types.Header) error {
 	}
 
 	newForks = append(newForks, header.Hash)
-	if err := b.db.WriteForks(newForks); err != nil {
+	if err := batch.WriteForks(newForks); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// preferHeader returns whether the consensus prefers the incoming header
+// over the current head of the same height
+func (b *Blockchain) preferHeader(current, incoming *types.Header) bool {
+	forkChoice, ok := b.consensus.(ForkChoice)
+	if !ok {
+		return false
+	}
+
+	if incoming.Number != current.Number || incoming.Hash == current.Hash {
+		return false
+	}
+
+	return forkChoice.PreferHeader(current, incoming)
+}
+
+// RecoverSeals recovers the seals of the headers ahead of their verification,
+// if the consensus supports it
+func (b *Blockchain) RecoverSeals(headers []*types.Header) {
+	if recoverer, ok := b.consensus.(SealRecoverer); ok {
+		recoverer.RecoverSeals(headers)
+	}
+}
+
 // handleReorg handles a reorganization event
 func (b *Blockchain) handleReorg(
+	batch storage.Writer,
 	evnt *Event,
 	oldHeader *types.Header,
 	newHeader *types.Header,
 ) error {
-	newChainHead := newHeader
-	oldChainHead := oldHeader
-
-	oldChain := []*types.Header{}
-	newChain := []*types.Header{}
-
-	var ok bool
-
-	// Fill up the old headers array
-	for oldHeader.Number > newHeader.Number {
-		oldHeader, ok = b.readHeader(oldHeader.ParentHash)
-		if !ok {
-			return fmt.Errorf("header '%s' not found", oldHeader.ParentHash.String())
-		}
-
-		oldChain = append(oldChain, oldHeader)
-	}
-
-	// Fill up the new headers array
-	for newHeader.Number > oldHeader.Number {
-		newHeader, ok = b.readHeader(newHeader.ParentHash)
-		if !ok {
-			return fmt.Errorf("header '%s' not found", newHeader.ParentHash.String())
-		}
-
-		newChain = append(newChain, newHeader)
+	oldChain, newChain, err := b.chainsToAncestor(oldHeader, newHeader)
+	if err != nil {
+		return err
 	}
 
-	for oldHeader.Hash != newHeader.Hash {
-		oldHeader, ok = b.readHeader(oldHeader.ParentHash)
-		if !ok {
-			return fmt.Errorf("header '%s' not found", oldHeader.ParentHash.String())
-		}
+	// the event lists the dropped headers from the lowest one,
+	// and the added headers from the new head
+	dropped := make([]string, 0, len(oldChain))
+	added := make([]string, 0, len(newChain))
 
-		newHeader, ok = b.readHeader(newHeader.ParentHash)
-		if !ok {
-			return fmt.Errorf("header '%s' not found", newHeader.ParentHash.String())
-		}
+	for i := len(oldChain) - 1; i >= 0; i-- {
+		evnt.AddOldHeader(oldChain[i])
 
-		oldChain = append(oldChain, oldHeader)
+		dropped = append(dropped, oldChain[i].Hash.String())
 	}
 
-	for _, b := range oldChain[:len(oldChain)-1] {
-		evnt.AddOldHeader(b)
-	}
-
-	evnt.AddOldHeader(oldChainHead)
-	evnt.AddNewHeader(newChainHead)
+	for _, h := range newChain {
+		evnt.AddNewHeader(h)
 
-	for _, b := range newChain {
-		evnt.AddNewHeader(b)
+		added = append(added, h.Hash.String())
 	}
 
-	if err := b.writeFork(oldChainHead); err != nil {
+	if err := b.writeFork(batch, oldHeader); err != nil {
 		return fmt.Errorf("failed to write the old header as fork: %w", err)
 	}
 
 	// Update canonical chain numbers
 	for _, h := range newChain {
-		if err := b.db.WriteCanonicalHash(h.Number, h.Hash); err != nil {
+		if err := batch.WriteCanonicalHash(h.Number, h.Hash); err != nil {
 			return err
 		}
 	}
 
-	diff, err := b.advanceHead(newChainHead)
+	diff, err := b.writeHead(batch, newHeader)
 	if err != nil {
 		return err
 	}
 
+	if err := b.reorgTxLookups(batch, oldChain, newChain); err != nil {
+		return fmt.Errorf("failed to update the transaction lookups: %w", err)
+	}
+
 	// Set the event type and difficulty
 	evnt.Type = EventReorg
 	evnt.SetDifficulty(diff)
 
+	b.logger.Info("chain reorg", "number", newHeader.Number, "dropped", dropped, "added", added)
+
 	return nil
 }
 
@@ -1130,7 +1606,18 @@ func (b *Blockchain) GetBlockByNumber(blockNumber uint64, full bool) (*types.Blo
 	return b.GetBlockByHash(blockHash, full)
 }
 
-// Close closes the DB connection
+// Close closes the DB connection, once the block write in progress is completed
+// so the storage is not left with a partial block. The writes fail afterwards
 func (b *Blockchain) Close() error {
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	if b.closed {
+		return nil
+	}
+
+	b.closed = true
+	b.stream.close()
+
 	return b.db.Close()
 }
@@ -0,0 +1,167 @@
+package blockchain
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/engine"
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/types/buildroot"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	benchBlocks      = 200
+	benchTxsPerBlock = 200
+	benchGasLimit    = 30000000
+	benchTxGas       = 21000
+)
+
+func newBenchChainConfig() *chain.Chain {
+	return &chain.Chain{
+		Genesis: &chain.Genesis{
+			GasLimit: benchGasLimit,
+		},
+		Params: &chain.Params{
+			Forks: &chain.Forks{
+				Homestead: chain.NewFork(0),
+				EIP155:    chain.NewFork(0),
+			},
+			BlockGasTarget: benchGasLimit,
+		},
+	}
+}
+
+func newBenchBlockchain(b *testing.B, dbEngine engine.Engine, path string) *Blockchain {
+	b.Helper()
+
+	logger := hclog.NewNullLogger()
+
+	db, err := engine.NewStorage(dbEngine, path, logger)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	chain, err := NewBlockchain(logger, db, newBenchChainConfig(), &MockVerifier{}, &mockExecutor{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	if err := chain.ComputeGenesis(); err != nil {
+		b.Fatal(err)
+	}
+
+	return chain
+}
+
+// newBenchBlocks creates a chain of full blocks on top of the genesis
+func newBenchBlocks(genesis *types.Header) ([]*types.Block, [][]*types.Receipt) {
+	blocks := make([]*types.Block, 0, benchBlocks)
+	receipts := make([][]*types.Receipt, 0, benchBlocks)
+
+	parent := genesis
+	to := types.StringToAddress("1")
+
+	for i := 0; i < benchBlocks; i++ {
+		txs := make([]*types.Transaction, benchTxsPerBlock)
+		blockReceipts := make([]*types.Receipt, benchTxsPerBlock)
+
+		for j := range txs {
+			txs[j] = &types.Transaction{
+				Nonce:    uint64(i*benchTxsPerBlock + j),
+				To:       &to,
+				Value:    big.NewInt(1),
+				Gas:      benchTxGas,
+				GasPrice: big.NewInt(0),
+				V:        big.NewInt(27),
+			}
+			txs[j].ComputeHash()
+
+			status := types.ReceiptSuccess
+			blockReceipts[j] = &types.Receipt{
+				Status:            &status,
+				CumulativeGasUsed: uint64(j+1) * benchTxGas,
+			}
+		}
+
+		header := &types.Header{
+			ParentHash:   parent.Hash,
+			Number:       parent.Number + 1,
+			Difficulty:   parent.Number + 1,
+			GasLimit:     benchGasLimit,
+			GasUsed:      benchTxsPerBlock * benchTxGas,
+			Timestamp:    parent.Timestamp + 1,
+			Sha3Uncles:   types.EmptyUncleHash,
+			TxRoot:       buildroot.CalculateTransactionsRoot(txs),
+			ReceiptsRoot: buildroot.CalculateReceiptsRoot(blockReceipts),
+			LogsBloom:    types.CreateBloom(blockReceipts),
+			ExtraData:    []byte{},
+		}
+		header.ComputeHash()
+
+		blocks = append(blocks, &types.Block{Header: header, Transactions: txs})
+		receipts = append(receipts, blockReceipts)
+		parent = header
+	}
+
+	return blocks, receipts
+}
+
+// BenchmarkImportBlocks measures the throughput of the block import for each database engine
+func BenchmarkImportBlocks(b *testing.B) {
+	dir, err := ioutil.TempDir("", "bench-import")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	genesis := newBenchBlockchain(b, engine.Default, filepath.Join(dir, "genesis"))
+	blocks, receipts := newBenchBlocks(genesis.Header())
+
+	if err := genesis.Close(); err != nil {
+		b.Fatal(err)
+	}
+
+	for _, dbEngine := range []engine.Engine{engine.LevelDB, engine.Pebble} {
+		dbEngine := dbEngine
+
+		b.Run(string(dbEngine), func(b *testing.B) {
+			var elapsed time.Duration
+
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+
+				path := filepath.Join(dir, string(dbEngine))
+				if err := os.RemoveAll(path); err != nil {
+					b.Fatal(err)
+				}
+
+				chain := newBenchBlockchain(b, dbEngine, path)
+
+				b.StartTimer()
+				start := time.Now()
+
+				for indx, block := range blocks {
+					if err := chain.WriteBlockWithReceipts(block, receipts[indx]); err != nil {
+						b.Fatal(err)
+					}
+				}
+
+				elapsed += time.Since(start)
+				b.StopTimer()
+
+				if err := chain.Close(); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ReportMetric(float64(b.N*len(blocks))/elapsed.Seconds(), "blocks/s")
+		})
+	}
+}
@@ -6,13 +6,17 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/tests"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/0xPolygon/polygon-edge/blockchain/storage"
 	"github.com/0xPolygon/polygon-edge/blockchain/storage/memory"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/types/buildroot"
 )
 
 func TestGenesis(t *testing.T) {
@@ -457,7 +461,7 @@ func TestInsertHeaders(t *testing.T) {
 			}
 
 			// we need to subscribe just after the genesis and history
-			sub := b.SubscribeEvents()
+			sub := b.SubscribeEventStream()
 
 			// run the history
 			for i := 1; i < len(cc.History); i++ {
@@ -540,6 +544,95 @@ func TestForkUnkwonParents(t *testing.T) {
 	assert.Error(t, b.WriteHeadersWithBodies([]*types.Header{h1[12]}))
 }
 
+// mockForkChoice is a verifier preferring the headers of a miner
+type mockForkChoice struct {
+	MockVerifier
+	preferred types.Address
+}
+
+func (m *mockForkChoice) PreferHeader(current, incoming *types.Header) bool {
+	return incoming.Miner == m.preferred
+}
+
+func TestForkChoice(t *testing.T) {
+	preferred := types.StringToAddress("1")
+
+	cases := []struct {
+		name  string
+		miner types.Address
+		reorg bool
+	}{
+		{
+			name:  "the preferred header replaces the head",
+			miner: preferred,
+			reorg: true,
+		},
+		{
+			name:  "the first written header is kept otherwise",
+			miner: types.StringToAddress("2"),
+			reorg: false,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			headers := NewTestHeaderChain(3)
+
+			b := NewTestBlockchain(t, headers)
+			b.SetConsensus(&mockForkChoice{preferred: preferred})
+
+			// a competing header with the same height and difficulty as the head
+			competing := headers[2].Copy()
+			competing.Miner = c.miner
+			competing.ComputeHash()
+
+			sub := b.SubscribeEventStream()
+			assert.NoError(t, b.WriteHeaders([]*types.Header{competing}))
+
+			evnt := sub.GetEvent()
+
+			if !c.reorg {
+				assert.Equal(t, EventFork, evnt.Type)
+				assert.Equal(t, headers[2].Hash, b.Header().Hash)
+
+				return
+			}
+
+			assert.Equal(t, EventReorg, evnt.Type)
+			assert.Equal(t, competing.Hash, b.Header().Hash)
+
+			// the event lists the dropped and the added blocks
+			assert.Len(t, evnt.OldChain, 1)
+			assert.Equal(t, headers[2].Hash, evnt.OldChain[0].Hash)
+			assert.Len(t, evnt.NewChain, 1)
+			assert.Equal(t, competing.Hash, evnt.NewChain[0].Hash)
+
+			header, ok := b.GetHeaderByNumber(2)
+			assert.True(t, ok)
+			assert.Equal(t, competing.Hash, header.Hash)
+		})
+	}
+}
+
+func TestHandleReorg_CanonicalHashes(t *testing.T) {
+	headers := NewTestHeaderChain(4)
+	forkHeaders := NewTestHeaderFromChainWithSeed(headers[:2], 4, 10)
+
+	b := NewTestBlockchain(t, headers)
+
+	// the fork diverges below the head, and becomes the canonical chain
+	assert.NoError(t, b.WriteHeaders(forkHeaders[2:]))
+	assert.Equal(t, forkHeaders[len(forkHeaders)-1].Hash, b.Header().Hash)
+
+	for _, forkHeader := range forkHeaders[1:] {
+		header, ok := b.GetHeaderByNumber(forkHeader.Number)
+		assert.True(t, ok)
+		assert.Equal(t, forkHeader.Hash, header.Hash)
+	}
+}
+
 func TestBlockchainWriteBody(t *testing.T) {
 	storage, err := memory.NewMemoryStorage(nil)
 	assert.NoError(t, err)
@@ -559,11 +652,93 @@ func TestBlockchainWriteBody(t *testing.T) {
 	}
 	block.Header.ComputeHash()
 
-	if err := b.writeBody(block); err != nil {
+	if err := b.writeBody(b.db, block); err != nil {
 		t.Fatal(err)
 	}
 }
 
+func TestWriteBlockWithReceipts(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+	key, sender := tests.GenerateKeyAndAddr(t)
+
+	to := types.StringToAddress("1")
+	txs := []*types.Transaction{
+		{Nonce: 0, To: &to, Value: big.NewInt(1), GasPrice: big.NewInt(0)},
+		{Nonce: 1, Value: big.NewInt(0), GasPrice: big.NewInt(0), Input: []byte{0x1}},
+	}
+
+	for indx, txn := range txs {
+		signedTx, err := crypto.NewEIP155Signer(0).SignTx(txn, key)
+		assert.NoError(t, err)
+
+		txs[indx] = signedTx.ComputeHash()
+	}
+
+	newReceipts := func() []*types.Receipt {
+		receipts := []*types.Receipt{
+			{CumulativeGasUsed: 21000},
+			{CumulativeGasUsed: 53000},
+		}
+		for _, receipt := range receipts {
+			receipt.SetStatus(types.ReceiptSuccess)
+		}
+
+		return receipts
+	}
+
+	header := &types.Header{
+		ParentHash:   b.Header().Hash,
+		Number:       1,
+		GasLimit:     b.Header().GasLimit,
+		GasUsed:      53000,
+		Sha3Uncles:   types.EmptyUncleHash,
+		TxRoot:       buildroot.CalculateTransactionsRoot(txs),
+		ReceiptsRoot: buildroot.CalculateReceiptsRoot(newReceipts()),
+	}
+	block := &types.Block{
+		Header:       header.ComputeHash(),
+		Transactions: txs,
+	}
+
+	// the receipts have to match the receipts root
+	invalidReceipts := newReceipts()
+	invalidReceipts[1].SetStatus(types.ReceiptFailed)
+
+	assert.Error(t, b.WriteBlockWithReceipts(block, invalidReceipts))
+	assert.Error(t, b.WriteBlockWithReceipts(block, newReceipts()[:1]))
+
+	eventCh, cancel := b.SubscribeEvents()
+	defer cancel()
+
+	assert.NoError(t, b.WriteBlockWithReceipts(block, newReceipts()))
+	assert.Equal(t, header.Hash, b.Header().Hash)
+
+	// the blocks written without being executed are synced
+	evnt := <-eventCh
+	assert.Equal(t, SourceSyncer, evnt.Source)
+	assert.Equal(t, header.Hash, evnt.Header().Hash)
+
+	// the context fields of the receipts are filled in
+	receipts, err := b.GetReceiptsByHash(header.Hash)
+	assert.NoError(t, err)
+
+	if assert.Len(t, receipts, 2) {
+		assert.Equal(t, uint64(21000), receipts[0].GasUsed)
+		assert.Equal(t, types.ZeroAddress, receipts[0].ContractAddress)
+		assert.Equal(t, uint64(32000), receipts[1].GasUsed)
+		assert.Equal(t, crypto.CreateAddress(sender, 1), receipts[1].ContractAddress)
+	}
+
+	blockHash, indx, ok := b.ReadTxLookup(txs[1].Hash)
+	assert.True(t, ok)
+	assert.Equal(t, header.Hash, blockHash)
+	assert.Equal(t, uint64(1), indx)
+
+	// the blocks are not executed, their internal transactions are not indexed
+	_, ok = b.GetInternalTxsByHash(header.Hash)
+	assert.False(t, ok)
+}
+
 func TestCalculateGasLimit(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -621,6 +796,177 @@ func TestCalculateGasLimit(t *testing.T) {
 	}
 }
 
+func TestCalculateGasLimit_TowardsTarget(t *testing.T) {
+	tests := []struct {
+		name            string
+		genesisGasLimit uint64
+		blockGasTarget  uint64
+	}{
+		{
+			name:            "should raise the gas limit to the target",
+			genesisGasLimit: 20000000,
+			blockGasTarget:  25000000,
+		},
+		{
+			name:            "should lower the gas limit to the target",
+			genesisGasLimit: 25000000,
+			blockGasTarget:  20000000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewTestBlockchain(t, nil)
+			assert.NoError(t, b.writeGenesis(&chain.Genesis{
+				GasLimit: tt.genesisGasLimit,
+			}))
+			b.config.Params.BlockGasTarget = tt.blockGasTarget
+
+			// the target is reached in about ln(1.25) * 1024 blocks
+			for i := 0; i < 300; i++ {
+				parent := b.Header()
+
+				gasLimit, err := b.CalculateGasLimit(parent.Number + 1)
+				assert.NoError(t, err)
+
+				header := (&types.Header{
+					ParentHash:   parent.Hash,
+					Number:       parent.Number + 1,
+					GasLimit:     gasLimit,
+					Sha3Uncles:   types.EmptyUncleHash,
+					TxRoot:       types.EmptyRootHash,
+					ReceiptsRoot: types.EmptyRootHash,
+				}).ComputeHash()
+
+				// the limit moves towards the target, within the delta bound
+				assert.NoError(t, b.verifyGasLimit(header))
+
+				if tt.blockGasTarget > tt.genesisGasLimit {
+					assert.GreaterOrEqual(t, gasLimit, parent.GasLimit)
+					assert.LessOrEqual(t, gasLimit, tt.blockGasTarget)
+				} else {
+					assert.LessOrEqual(t, gasLimit, parent.GasLimit)
+					assert.GreaterOrEqual(t, gasLimit, tt.blockGasTarget)
+				}
+
+				assert.NoError(t, b.WriteBlockWithReceipts(&types.Block{Header: header}, nil))
+			}
+
+			assert.Equal(t, tt.blockGasTarget, b.Header().GasLimit)
+		})
+	}
+}
+
+func TestVerifyGasLimit(t *testing.T) {
+	const parentGasLimit uint64 = 20000000
+
+	delta := parentGasLimit / BlockGasTargetDivisor
+
+	tests := []struct {
+		name       string
+		gasLimit   uint64
+		gasUsed    uint64
+		shouldFail bool
+	}{
+		{"same gas limit", parentGasLimit, 0, false},
+		{"raised by the delta", parentGasLimit + delta, 0, false},
+		{"lowered by the delta", parentGasLimit - delta, 0, false},
+		{"raised past the delta", parentGasLimit + delta + 1, 0, true},
+		{"lowered past the delta", parentGasLimit - delta - 1, 0, true},
+		{"gas used over the gas limit", parentGasLimit, parentGasLimit + 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewTestBlockchain(t, nil)
+			assert.NoError(t, b.writeGenesis(&chain.Genesis{
+				GasLimit: parentGasLimit,
+			}))
+
+			err := b.verifyGasLimit(&types.Header{
+				Number:   1,
+				GasLimit: tt.gasLimit,
+				GasUsed:  tt.gasUsed,
+			})
+
+			if tt.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCalculateBaseFee(t *testing.T) {
+	tests := []struct {
+		name            string
+		parentBaseFee   uint64
+		parentGasLimit  uint64
+		parentGasUsed   uint64
+		expectedBaseFee uint64
+	}{
+		{
+			name:            "should start at the initial base fee past the fork",
+			parentBaseFee:   0,
+			parentGasLimit:  20000000,
+			parentGasUsed:   10000000,
+			expectedBaseFee: InitialBaseFee,
+		},
+		{
+			name:            "should not alter base fee when the usage is the target",
+			parentBaseFee:   1000,
+			parentGasLimit:  20000000,
+			parentGasUsed:   10000000,
+			expectedBaseFee: 1000,
+		},
+		{
+			name:            "should increase base fee when the usage is over the target",
+			parentBaseFee:   1000,
+			parentGasLimit:  20000000,
+			parentGasUsed:   20000000,
+			expectedBaseFee: 1000 + 1000/8,
+		},
+		{
+			name:            "should decrease base fee when the usage is under the target",
+			parentBaseFee:   1000,
+			parentGasLimit:  20000000,
+			parentGasUsed:   0,
+			expectedBaseFee: 1000 - 1000/8,
+		},
+		{
+			name:            "should increase base fee by at least 1",
+			parentBaseFee:   1,
+			parentGasLimit:  20000000,
+			parentGasUsed:   10000001,
+			expectedBaseFee: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewTestBlockchain(t, nil)
+			b.config.Params.Forks.EIP1559 = chain.NewFork(1)
+
+			parent := &types.Header{
+				Number:   1,
+				BaseFee:  tt.parentBaseFee,
+				GasLimit: tt.parentGasLimit,
+				GasUsed:  tt.parentGasUsed,
+			}
+
+			assert.Equal(t, tt.expectedBaseFee, b.CalculateBaseFee(parent))
+		})
+	}
+
+	t.Run("should not set base fee before the fork", func(t *testing.T) {
+		b := NewTestBlockchain(t, nil)
+		b.config.Params.Forks.EIP1559 = chain.NewFork(10)
+
+		assert.Zero(t, b.CalculateBaseFee(&types.Header{Number: 1, BaseFee: 1000}))
+	})
+}
+
 // TestGasPriceAverage tests the average gas price of the
 // blockchain
 func TestGasPriceAverage(t *testing.T) {
@@ -681,3 +1027,327 @@ func TestGasPriceAverage(t *testing.T) {
 		})
 	}
 }
+
+func TestPauseWrites(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+
+	header := &types.Header{
+		ParentHash:   b.Header().Hash,
+		Number:       1,
+		GasLimit:     b.Header().GasLimit,
+		Sha3Uncles:   types.EmptyUncleHash,
+		TxRoot:       types.EmptyRootHash,
+		ReceiptsRoot: types.EmptyRootHash,
+	}
+	block := &types.Block{
+		Header: header.ComputeHash(),
+	}
+
+	resume := b.PauseWrites()
+	doneCh := make(chan error, 1)
+
+	go func() {
+		doneCh <- b.WriteBlockWithReceipts(block, nil)
+	}()
+
+	// the block is not written while the writes are paused
+	select {
+	case <-doneCh:
+		t.Fatal("the block was written while the writes are paused")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.Equal(t, uint64(0), b.Header().Number)
+
+	resume()
+
+	assert.NoError(t, <-doneCh)
+	assert.Equal(t, header.Hash, b.Header().Hash)
+}
+
+func TestClose_WaitsForWrite(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+
+	resume := b.PauseWrites()
+	doneCh := make(chan error, 1)
+
+	go func() {
+		doneCh <- b.Close()
+	}()
+
+	// the storage is not closed in the middle of a write
+	select {
+	case <-doneCh:
+		t.Fatal("the blockchain was closed while a block is written")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	resume()
+
+	assert.NoError(t, <-doneCh)
+	assert.ErrorIs(t, b.WriteBlockWithReceipts(&types.Block{Header: b.Header()}, nil), ErrClosed)
+	assert.NoError(t, b.Close())
+}
+
+func TestComputeGenesis_RollbackPartialHead(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+
+	newHeader := func(parent *types.Header) *types.Header {
+		return (&types.Header{
+			ParentHash:   parent.Hash,
+			Number:       parent.Number + 1,
+			GasLimit:     parent.GasLimit,
+			Sha3Uncles:   types.EmptyUncleHash,
+			TxRoot:       types.EmptyRootHash,
+			ReceiptsRoot: types.EmptyRootHash,
+		}).ComputeHash()
+	}
+
+	header1 := newHeader(b.Header())
+	assert.NoError(t, b.WriteBlockWithReceipts(&types.Block{Header: header1}, nil))
+
+	// the fully written head is kept
+	assert.NoError(t, b.ComputeGenesis())
+	assert.Equal(t, header1.Hash, b.Header().Hash)
+
+	// the node stopped after writing the header of the block 2, before its receipts
+	header2 := newHeader(header1)
+	txn := (&types.Transaction{Nonce: 1}).ComputeHash()
+
+	assert.NoError(t, b.db.WriteBody(header2.Hash, &types.Body{Transactions: []*types.Transaction{txn}}))
+	assert.NoError(t, b.db.WriteCanonicalHeader(header2, big.NewInt(2)))
+	assert.NoError(t, b.db.WriteTxLookup(txn.Hash, header2.Hash, 0))
+
+	assert.NoError(t, b.ComputeGenesis())
+	assert.Equal(t, header1.Hash, b.Header().Hash)
+
+	number, _ := b.db.ReadHeadNumber()
+	assert.Equal(t, uint64(1), number)
+
+	_, ok := b.db.ReadCanonicalHash(2)
+	assert.False(t, ok)
+
+	_, _, ok = b.db.ReadTxLookup(txn.Hash)
+	assert.False(t, ok)
+
+	// the block can be written again
+	assert.NoError(t, b.WriteBlockWithReceipts(&types.Block{Header: header2}, nil))
+	assert.Equal(t, header2.Hash, b.Header().Hash)
+}
+
+func TestComputeGenesis_RollbackIncompleteHead(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+
+	header1 := (&types.Header{
+		ParentHash: b.Header().Hash,
+		Number:     1,
+	}).ComputeHash()
+
+	// the node stopped after moving the head, before the canonical hash and the difficulty
+	assert.NoError(t, b.db.WriteHeader(header1))
+	assert.NoError(t, b.db.WriteBody(header1.Hash, &types.Body{}))
+	assert.NoError(t, b.db.WriteReceipts(header1.Hash, []*types.Receipt{}))
+	assert.NoError(t, b.db.WriteHeadHash(header1.Hash))
+	assert.NoError(t, b.db.WriteHeadNumber(header1.Number))
+
+	assert.NoError(t, b.ComputeGenesis())
+	assert.Equal(t, uint64(0), b.Header().Number)
+
+	head, _ := b.db.ReadHeadHash()
+	assert.Equal(t, b.genesis, head)
+}
+
+var errInjectedFault = errors.New("injected fault")
+
+// faultyStorage injects faults in the batches of the storage
+type faultyStorage struct {
+	storage.Storage
+
+	// failReceipts fails to add the receipts to the batch, after the body and the header
+	failReceipts bool
+	// failWrite fails to write the batch
+	failWrite bool
+}
+
+func (f *faultyStorage) NewBatch() storage.Batch {
+	return &faultyBatch{f.Storage.NewBatch(), f}
+}
+
+type faultyBatch struct {
+	storage.Batch
+
+	storage *faultyStorage
+}
+
+func (b *faultyBatch) WriteReceipts(hash types.Hash, receipts []*types.Receipt) error {
+	if b.storage.failReceipts {
+		return errInjectedFault
+	}
+
+	return b.Batch.WriteReceipts(hash, receipts)
+}
+
+func (b *faultyBatch) Write() error {
+	if b.storage.failWrite {
+		return errInjectedFault
+	}
+
+	return b.Batch.Write()
+}
+
+func TestWriteBlock_AtomicBatch(t *testing.T) {
+	cases := []struct {
+		name  string
+		fault faultyStorage
+	}{
+		{"fault in the middle of the batch", faultyStorage{failReceipts: true}},
+		{"fault writing the batch", faultyStorage{failWrite: true}},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			b := NewTestBlockchain(t, nil)
+
+			db := c.fault
+			db.Storage = b.db
+			b.db = &db
+
+			genesis := b.Header()
+			to := types.StringToAddress("1")
+			txn := (&types.Transaction{Nonce: 1, To: &to, GasPrice: big.NewInt(0)}).ComputeHash()
+			receipts := []*types.Receipt{{CumulativeGasUsed: 21000}}
+			receipts[0].SetStatus(types.ReceiptSuccess)
+
+			header := (&types.Header{
+				ParentHash:   genesis.Hash,
+				Number:       1,
+				GasLimit:     genesis.GasLimit,
+				GasUsed:      21000,
+				Sha3Uncles:   types.EmptyUncleHash,
+				TxRoot:       buildroot.CalculateTransactionsRoot([]*types.Transaction{txn}),
+				ReceiptsRoot: buildroot.CalculateReceiptsRoot(receipts),
+			}).ComputeHash()
+			block := &types.Block{Header: header, Transactions: []*types.Transaction{txn}}
+
+			assert.ErrorIs(t, b.WriteBlockWithReceipts(block, receipts), errInjectedFault)
+
+			// none of the components of the block is written
+			assert.Equal(t, genesis.Hash, b.Header().Hash)
+
+			head, _ := b.db.ReadHeadHash()
+			assert.Equal(t, genesis.Hash, head)
+
+			_, ok := b.db.ReadCanonicalHash(1)
+			assert.False(t, ok)
+
+			_, ok = b.db.ReadTotalDifficulty(header.Hash)
+			assert.False(t, ok)
+
+			_, err := b.db.ReadHeader(header.Hash)
+			assert.ErrorIs(t, err, storage.ErrNotFound)
+
+			_, err = b.db.ReadBody(header.Hash)
+			assert.ErrorIs(t, err, storage.ErrNotFound)
+
+			_, _, ok = b.db.ReadTxLookup(txn.Hash)
+			assert.False(t, ok)
+
+			_, ok = b.GetHeaderByHash(header.Hash)
+			assert.False(t, ok)
+
+			// the block is written once the fault is gone
+			db.failReceipts, db.failWrite = false, false
+
+			assert.NoError(t, b.WriteBlockWithReceipts(block, receipts))
+			assert.Equal(t, header.Hash, b.Header().Hash)
+
+			blockHash, _, ok := b.db.ReadTxLookup(txn.Hash)
+			assert.True(t, ok)
+			assert.Equal(t, header.Hash, blockHash)
+		})
+	}
+}
+
+func TestFinalizedHeader(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+	genesis := b.Header()
+
+	newHeader := func(parent *types.Header, difficulty uint64) *types.Header {
+		return (&types.Header{
+			ParentHash:   parent.Hash,
+			Number:       parent.Number + 1,
+			GasLimit:     parent.GasLimit,
+			Difficulty:   difficulty,
+			Sha3Uncles:   types.EmptyUncleHash,
+			TxRoot:       types.EmptyRootHash,
+			ReceiptsRoot: types.EmptyRootHash,
+		}).ComputeHash()
+	}
+
+	assert.Equal(t, genesis.Hash, b.FinalizedHeader().Hash)
+
+	// the verified blocks are finalized
+	header1 := newHeader(genesis, 1)
+	assert.NoError(t, b.WriteBlockWithReceipts(&types.Block{Header: header1}, nil))
+	assert.Equal(t, header1.Hash, b.FinalizedHeader().Hash)
+
+	// the headers only move the head
+	header2 := newHeader(header1, 1)
+	assert.NoError(t, b.WriteHeaders([]*types.Header{header2}))
+	assert.Equal(t, header2.Hash, b.Header().Hash)
+	assert.Equal(t, header1.Hash, b.FinalizedHeader().Hash)
+
+	// the reorg dropping the finalized block moves it back to the common ancestor
+	fork := newHeader(genesis, 5)
+	assert.NoError(t, b.WriteHeaders([]*types.Header{fork}))
+	assert.Equal(t, fork.Hash, b.Header().Hash)
+	assert.Equal(t, genesis.Hash, b.FinalizedHeader().Hash)
+
+	hash, ok := b.db.ReadFinalizedHash()
+	assert.True(t, ok)
+	assert.Equal(t, genesis.Hash, hash)
+
+	// the finalized block is loaded on restart
+	header3 := newHeader(fork, 1)
+	assert.NoError(t, b.WriteBlockWithReceipts(&types.Block{Header: header3}, nil))
+
+	assert.NoError(t, b.ComputeGenesis())
+	assert.Equal(t, header3.Hash, b.FinalizedHeader().Hash)
+}
+
+func TestWriteBlock_SizeLimits(t *testing.T) {
+	b, executor, key := newExecutingBlockchain(t)
+
+	forks := *b.Config().Forks
+	forks.SizeLimits = chain.NewFork(2)
+	b.Config().Forks = &forks
+
+	block, _ := newExecutedBlock(t, b, executor, key, func(header *types.Header) {})
+	txs := block.Transactions
+
+	// the limits are not enforced before the fork
+	b.Config().MaxTxSize = txs[0].Size() - 1
+	assert.NoError(t, b.verifyBlock(block))
+
+	forks.SizeLimits = chain.NewFork(1)
+
+	err := b.WriteBlock(block, SourceSyncer)
+	assert.ErrorIs(t, err, chain.ErrTxTooLarge)
+	assert.NotErrorIs(t, err, chain.ErrBlockTooLarge)
+
+	// each transaction fits, the block doesn't
+	b.Config().MaxTxSize = txs[0].Size() + txs[1].Size()
+	b.Config().MaxBlockSize = txs[0].Size() + txs[1].Size() - 1
+
+	err = b.WriteBlock(block, SourceSyncer)
+	assert.ErrorIs(t, err, chain.ErrBlockTooLarge)
+	assert.NotErrorIs(t, err, chain.ErrTxTooLarge)
+	assert.Equal(t, uint64(0), b.Header().Number)
+
+	b.Config().MaxBlockSize++
+
+	assert.NoError(t, b.WriteBlock(block, SourceSyncer))
+	assert.Equal(t, block.Hash(), b.Header().Hash)
+}
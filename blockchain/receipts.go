@@ -0,0 +1,95 @@
+package blockchain
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/types/buildroot"
+)
+
+// ReceiptsMismatchError is the error of the receipts of a block not matching the receipts root
+// or the logs bloom of its header, the receipts are kept with the bad block for the diagnostics
+type ReceiptsMismatchError struct {
+	// Field is the field of the header, the receipts root or the logs bloom
+	Field string
+
+	// Expected and Computed are the receipts roots of the header and of the receipts, empty for the logs bloom
+	Expected string
+	Computed string
+
+	// TxIndex is the index of the first transaction whose receipt diverges from the header,
+	// -1 if it can't be located from the header
+	TxIndex int
+	TxHash  types.Hash
+
+	Receipts []*types.Receipt
+}
+
+func (e *ReceiptsMismatchError) Error() string {
+	msg := "invalid " + e.Field
+	if e.Expected != "" {
+		msg = fmt.Sprintf("%s, expected %s, computed %s", msg, e.Expected, e.Computed)
+	}
+
+	if e.TxIndex < 0 {
+		return msg
+	}
+
+	return fmt.Sprintf("%s, the receipt of the transaction %s at index %d first diverges", msg, e.TxHash, e.TxIndex)
+}
+
+// verifyReceiptsRoot verifies the receipts root and the logs bloom of the header against the receipts
+func verifyReceiptsRoot(block *types.Block, receipts []*types.Receipt) error {
+	header := block.Header
+
+	newError := func(field, expected, computed string) error {
+		err := &ReceiptsMismatchError{
+			Field:    field,
+			Expected: expected,
+			Computed: computed,
+			TxIndex:  firstDivergingReceipt(header, receipts),
+			Receipts: receipts,
+		}
+
+		if err.TxIndex >= 0 && err.TxIndex < len(block.Transactions) {
+			err.TxHash = block.Transactions[err.TxIndex].Hash
+		}
+
+		return err
+	}
+
+	if root := buildroot.CalculateReceiptsRoot(receipts); root != header.ReceiptsRoot {
+		return newError("receipts root", header.ReceiptsRoot.String(), root.String())
+	}
+
+	// the blocks built before the logs bloom was written have an empty one
+	if header.LogsBloom != (types.Bloom{}) && types.CreateBloom(receipts) != header.LogsBloom {
+		return newError("logs bloom", "", "")
+	}
+
+	return nil
+}
+
+// firstDivergingReceipt returns the index of the first receipt using more gas than the header,
+// or emitting a log missing from the logs bloom of the header. It returns -1 if none does,
+// the receipts only diverge in the fields committed to by the receipts root then
+func firstDivergingReceipt(header *types.Header, receipts []*types.Receipt) int {
+	for indx, receipt := range receipts {
+		if receipt.CumulativeGasUsed > header.GasUsed {
+			return indx
+		}
+
+		if header.LogsBloom == (types.Bloom{}) {
+			continue
+		}
+
+		bloom := types.CreateBloom([]*types.Receipt{receipt})
+		for i := range bloom {
+			if bloom[i]&^header.LogsBloom[i] != 0 {
+				return indx
+			}
+		}
+	}
+
+	return -1
+}
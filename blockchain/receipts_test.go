@@ -0,0 +1,219 @@
+package blockchain
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/tests"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/types/buildroot"
+)
+
+var (
+	logEmitter = types.StringToAddress("e1")
+
+	// logEmitterCode emits an empty log
+	logEmitterCode = []byte{0x60, 0x00, 0x60, 0x00, 0xa0, 0x00}
+)
+
+// newExecutingBlockchain returns the chain executing its blocks, with the funded sender of the transactions
+func newExecutingBlockchain(t *testing.T) (*Blockchain, *state.Executor, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, sender := tests.GenerateKeyAndAddr(t)
+
+	params := &chain.Params{
+		Forks:          chain.AllForksEnabled,
+		ChainID:        100,
+		BlockGasTarget: defaultBlockGasTarget,
+	}
+
+	executor := state.NewExecutor(params, itrie.NewState(itrie.NewMemoryStorage()), hclog.NewNullLogger())
+	executor.SetRuntime(evm.NewEVM())
+	executor.GetHash = func(*types.Header) state.GetHashByNumber {
+		return func(uint64) types.Hash {
+			return types.ZeroHash
+		}
+	}
+
+	genesis := &chain.Genesis{
+		StateRoot: executor.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+			sender:     {Balance: big.NewInt(1000000)},
+			logEmitter: {Code: logEmitterCode},
+		}),
+	}
+
+	b, err := newBlockChain(&chain.Chain{Genesis: genesis, Params: params}, executor)
+	assert.NoError(t, err)
+
+	return b, executor, key
+}
+
+// newExecutedBlock returns the block of a transfer and a call emitting a log, on top of the head.
+// The header is completed from the execution of the block, and tampered before it is hashed
+func newExecutedBlock(
+	t *testing.T,
+	b *Blockchain,
+	executor *state.Executor,
+	key *ecdsa.PrivateKey,
+	tamper func(header *types.Header),
+) (*types.Block, []*types.Receipt) {
+	t.Helper()
+
+	to := types.StringToAddress("1")
+	txs := []*types.Transaction{
+		{Nonce: 0, To: &to, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(0)},
+		{Nonce: 1, To: &logEmitter, Value: big.NewInt(0), Gas: 50000, GasPrice: big.NewInt(0)},
+	}
+
+	for indx, txn := range txs {
+		signedTx, err := crypto.NewEIP155Signer(100).SignTx(txn, key)
+		assert.NoError(t, err)
+
+		txs[indx] = signedTx.ComputeHash()
+	}
+
+	parent := b.Header()
+	block := &types.Block{
+		Header: &types.Header{
+			ParentHash: parent.Hash,
+			Number:     parent.Number + 1,
+			GasLimit:   parent.GasLimit,
+			Sha3Uncles: types.EmptyUncleHash,
+			TxRoot:     buildroot.CalculateTransactionsRoot(txs),
+		},
+		Transactions: txs,
+	}
+
+	transition, err := executor.ProcessBlock(parent.StateRoot, block, types.ZeroAddress)
+	assert.NoError(t, err)
+
+	_, root := transition.Commit()
+	receipts := transition.Receipts()
+
+	block.Header.StateRoot = root
+	block.Header.GasUsed = transition.TotalGas()
+	block.Header.ReceiptsRoot = buildroot.CalculateReceiptsRoot(receipts)
+	block.Header.LogsBloom = types.CreateBloom(receipts)
+
+	tamper(block.Header)
+	block.Header.ComputeHash()
+
+	return block, receipts
+}
+
+func TestWriteBlock_TamperedReceiptsRoot(t *testing.T) {
+	b, executor, key := newExecutingBlockchain(t)
+
+	dumpDir := filepath.Join(t.TempDir(), "badblocks")
+	assert.NoError(t, b.SetBadBlockDumpDir(dumpDir))
+
+	block, receipts := newExecutedBlock(t, b, executor, key, func(header *types.Header) {
+		header.ReceiptsRoot = types.StringToHash("1")
+	})
+
+	err := b.WriteBlock(block, SourceSyncer)
+
+	var mismatch *ReceiptsMismatchError
+	if assert.True(t, errors.As(err, &mismatch)) {
+		assert.Equal(t, "receipts root", mismatch.Field)
+		assert.Equal(t, types.StringToHash("1").String(), mismatch.Expected)
+		assert.Equal(t, buildroot.CalculateReceiptsRoot(receipts).String(), mismatch.Computed)
+
+		// the receipts use the gas and emit the logs of the header, the diverging one can't be located
+		assert.Equal(t, -1, mismatch.TxIndex)
+		assert.NotContains(t, err.Error(), "first diverges")
+	}
+
+	assert.Equal(t, uint64(0), b.Header().Number)
+
+	// the executed receipts are kept with the bad block, and dumped along with it
+	badBlocks := b.BadBlocks()
+	if assert.Len(t, badBlocks, 1) {
+		assert.Equal(t, err.Error(), badBlocks[0].Reason)
+		assert.Len(t, badBlocks[0].Receipts, 2)
+	}
+
+	data, readErr := ioutil.ReadFile(filepath.Join(dumpDir, "1-"+block.Hash().String()+".json"))
+	assert.NoError(t, readErr)
+
+	var dump badBlockDump
+	assert.NoError(t, json.Unmarshal(data, &dump))
+
+	if assert.Len(t, dump.Receipts, 2) {
+		assert.Equal(t, block.Transactions[1].Hash, dump.Receipts[1].TxHash)
+		assert.Equal(t, uint64(types.ReceiptSuccess), dump.Receipts[1].Status)
+		assert.Equal(t, block.Header.GasUsed, dump.Receipts[1].CumulativeGasUsed)
+		assert.Equal(t, 1, dump.Receipts[1].Logs)
+	}
+
+	// the block of the matching receipts root is written
+	valid, _ := newExecutedBlock(t, b, executor, key, func(header *types.Header) {})
+	assert.NoError(t, b.WriteBlock(valid, SourceSyncer))
+	assert.Equal(t, valid.Hash(), b.Header().Hash)
+}
+
+func TestWriteBlock_TamperedLogsBloom(t *testing.T) {
+	b, executor, key := newExecutingBlockchain(t)
+
+	// the bloom of a log the block doesn't emit
+	other := &types.Receipt{Logs: []*types.Log{{Address: types.StringToAddress("2")}}}
+
+	block, _ := newExecutedBlock(t, b, executor, key, func(header *types.Header) {
+		header.LogsBloom = types.CreateBloom([]*types.Receipt{other})
+	})
+
+	err := b.WriteBlock(block, SourceSyncer)
+
+	var mismatch *ReceiptsMismatchError
+	if assert.True(t, errors.As(err, &mismatch)) {
+		assert.Equal(t, "logs bloom", mismatch.Field)
+
+		// the transfer emits no log, the log of the call is missing from the bloom
+		assert.Equal(t, 1, mismatch.TxIndex)
+		assert.Equal(t, block.Transactions[1].Hash, mismatch.TxHash)
+		assert.Contains(t, err.Error(), "the receipt of the transaction "+block.Transactions[1].Hash.String()+
+			" at index 1 first diverges")
+	}
+
+	assert.Equal(t, uint64(0), b.Header().Number)
+}
+
+func TestWriteBlockWithReceipts_DivergingReceipt(t *testing.T) {
+	b, executor, key := newExecutingBlockchain(t)
+
+	block, receipts := newExecutedBlock(t, b, executor, key, func(header *types.Header) {})
+
+	// the receipts of the peer use more gas than the header from the first one
+	tampered := make([]*types.Receipt, len(receipts))
+	for indx, receipt := range receipts {
+		copied := *receipt
+		copied.CumulativeGasUsed += block.Header.GasUsed
+		tampered[indx] = &copied
+	}
+
+	err := b.WriteBlockWithReceipts(block, tampered)
+
+	var mismatch *ReceiptsMismatchError
+	if assert.True(t, errors.As(err, &mismatch)) {
+		assert.Equal(t, "receipts root", mismatch.Field)
+		assert.Equal(t, 0, mismatch.TxIndex)
+		assert.Equal(t, block.Transactions[0].Hash, mismatch.TxHash)
+	}
+
+	assert.NoError(t, b.WriteBlockWithReceipts(block, receipts))
+	assert.Equal(t, block.Hash(), b.Header().Hash)
+}
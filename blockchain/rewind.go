@@ -0,0 +1,275 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	ErrRewindNotCanonical = errors.New("the rewind target is not a canonical block")
+	ErrRollbackAboveHead  = errors.New("the rollback target is not below the head")
+)
+
+// RollbackResult is what a rollback removed from the storage
+type RollbackResult struct {
+	// From is the number of the head before the rollback
+	From uint64
+	// To is the number of the new head, and Head its hash
+	To   uint64
+	Head types.Hash
+
+	// Blocks are the no.of canonical blocks removed, Forks the no.of the non canonical ones
+	Blocks uint64
+	Forks  uint64
+	// TxLookups are the no.of transaction lookups removed
+	TxLookups uint64
+}
+
+// RewindHead moves the head of the chain back to the canonical block, as the dev mode does
+// to revert to a snapshot. The blocks above it are not canonical anymore, their transactions
+// are not looked up, but the blocks are kept in the storage. The subscribers are not notified,
+// the caller resets the components depending on the head
+func (b *Blockchain) RewindHead(hash types.Hash) error {
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	header, ok := b.readHeader(hash)
+	if !ok {
+		return fmt.Errorf("header %s not found", hash)
+	}
+
+	if canonical, ok := b.db.ReadCanonicalHash(header.Number); !ok || canonical != hash {
+		return fmt.Errorf("%w: %s (%d)", ErrRewindNotCanonical, hash, header.Number)
+	}
+
+	diff, ok := b.readTotalDifficulty(hash)
+	if !ok {
+		return fmt.Errorf("failed to read the difficulty of %s", hash)
+	}
+
+	batch := b.db.NewBatch()
+
+	for number := b.Header().Number; number > header.Number; number-- {
+		canonical, ok := b.db.ReadCanonicalHash(number)
+		if !ok {
+			continue
+		}
+
+		if body, ok := b.readBody(canonical); ok {
+			for _, txn := range body.Transactions {
+				if err := batch.DeleteTxLookup(txn.Hash); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := batch.DeleteCanonicalHash(number); err != nil {
+			return err
+		}
+	}
+
+	if err := batch.WriteHeadHash(header.Hash); err != nil {
+		return err
+	}
+
+	if err := batch.WriteHeadNumber(header.Number); err != nil {
+		return err
+	}
+
+	finalized := b.FinalizedHeader()
+	if finalized.Number > header.Number {
+		finalized = header
+
+		if err := batch.WriteFinalizedHash(header.Hash); err != nil {
+			return err
+		}
+	}
+
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	b.setCurrentHeader(header, diff)
+	b.setFinalizedHeader(finalized)
+
+	b.logger.Info("rewound the head", "number", header.Number, "hash", header.Hash)
+
+	return nil
+}
+
+// Rollback moves the head of the chain back to the canonical block of the number, and deletes
+// the blocks above it with their transaction lookups, which is meant to run offline on a node
+// which accepted a bad block. The blocks of the forks above the number are deleted too,
+// the head markers and the forks are reset in the same batch
+func (b *Blockchain) Rollback(number uint64) (*RollbackResult, error) {
+	b.writeLock.Lock()
+	defer b.writeLock.Unlock()
+
+	if b.closed {
+		return nil, ErrClosed
+	}
+
+	head := b.Header()
+	if number >= head.Number {
+		return nil, fmt.Errorf("%w: %d, the head is %d", ErrRollbackAboveHead, number, head.Number)
+	}
+
+	header, ok := b.GetHeaderByNumber(number)
+	if !ok {
+		return nil, fmt.Errorf("header %d not found", number)
+	}
+
+	diff, ok := b.readTotalDifficulty(header.Hash)
+	if !ok {
+		return nil, fmt.Errorf("failed to read the difficulty of %s", header.Hash)
+	}
+
+	result := &RollbackResult{
+		From: head.Number,
+		To:   number,
+		Head: header.Hash,
+	}
+
+	batch := b.db.NewBatch()
+
+	for n := head.Number; n > number; n-- {
+		canonical, ok := b.db.ReadCanonicalHash(n)
+		if !ok {
+			continue
+		}
+
+		if body, ok := b.readBody(canonical); ok {
+			for _, txn := range body.Transactions {
+				if err := batch.DeleteTxLookup(txn.Hash); err != nil {
+					return nil, err
+				}
+
+				result.TxLookups++
+			}
+		}
+
+		if err := batch.DeleteCanonicalHash(n); err != nil {
+			return nil, err
+		}
+
+		if err := batch.DeleteBlock(canonical); err != nil {
+			return nil, err
+		}
+
+		result.Blocks++
+	}
+
+	forks, err := b.rollbackForks(batch, number, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := batch.WriteForks(forks); err != nil {
+		return nil, err
+	}
+
+	if err := batch.WriteHeadHash(header.Hash); err != nil {
+		return nil, err
+	}
+
+	if err := batch.WriteHeadNumber(header.Number); err != nil {
+		return nil, err
+	}
+
+	finalized := b.FinalizedHeader()
+	if finalized.Number > header.Number {
+		finalized = header
+
+		if err := batch.WriteFinalizedHash(header.Hash); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+
+	// the deleted blocks can't be served from the caches
+	b.headersCache.Purge()
+	b.difficultyCache.Purge()
+
+	b.setCurrentHeader(header, diff)
+	b.setFinalizedHeader(finalized)
+
+	b.logger.Info(
+		"rolled back the chain",
+		"from", result.From,
+		"to", result.To,
+		"hash", result.Head,
+		"blocks", result.Blocks,
+		"forks", result.Forks,
+	)
+
+	return result, nil
+}
+
+// rollbackForks deletes the non canonical blocks of the forks above the number,
+// and returns the forks left. A fork above the number which branched off below it
+// is kept with the tip of its remaining blocks
+func (b *Blockchain) rollbackForks(
+	batch storage.Writer,
+	number uint64,
+	result *RollbackResult,
+) ([]types.Hash, error) {
+	forks, err := b.db.ReadForks()
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			return nil, err
+		}
+
+		forks = []types.Hash{}
+	}
+
+	kept := []types.Hash{}
+	deleted := map[types.Hash]bool{}
+
+	for _, fork := range forks {
+		header, ok := b.readHeader(fork)
+		if !ok || header.Number <= number {
+			kept = append(kept, fork)
+
+			continue
+		}
+
+		for ok && header.Number > number {
+			if canonical, ok := b.db.ReadCanonicalHash(header.Number); ok && canonical == header.Hash {
+				break
+			}
+
+			if !deleted[header.Hash] {
+				if err := batch.DeleteBlock(header.Hash); err != nil {
+					return nil, err
+				}
+
+				deleted[header.Hash] = true
+				result.Forks++
+			}
+
+			header, ok = b.readHeader(header.ParentHash)
+		}
+
+		if !ok || header.Number > number {
+			continue
+		}
+
+		if canonical, ok := b.db.ReadCanonicalHash(header.Number); ok && canonical == header.Hash {
+			continue
+		}
+
+		kept = append(kept, header.Hash)
+	}
+
+	return kept, nil
+}
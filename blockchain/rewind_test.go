@@ -0,0 +1,130 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewindHead(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+
+	headers := NewTestHeaderChain(5)
+	forkHeaders := NewTestHeaderFromChainWithSeed(headers[:2], 2, 10)
+
+	txn := newLookupTestTx(1)
+
+	assert.NoError(t, b.db.WriteBody(headers[3].Hash, &types.Body{
+		Transactions: []*types.Transaction{txn},
+	}))
+
+	_, err := b.advanceHead(headers[0])
+	assert.NoError(t, err)
+	assert.NoError(t, b.WriteHeaders(headers[1:]))
+	assert.NoError(t, b.writeTxLookups(b.db, headers[3].Hash, []*types.Transaction{txn}))
+
+	// the blocks which are not canonical can't be rewound to
+	assert.NoError(t, b.db.WriteHeader(forkHeaders[2]))
+	assert.ErrorIs(t, b.RewindHead(forkHeaders[2].Hash), ErrRewindNotCanonical)
+	assert.Error(t, b.RewindHead(types.StringToHash("1")))
+	assert.Equal(t, headers[4].Hash, b.Header().Hash)
+
+	assert.NoError(t, b.RewindHead(headers[2].Hash))
+	assert.Equal(t, headers[2].Hash, b.Header().Hash)
+
+	hash, ok := b.db.ReadHeadHash()
+	assert.True(t, ok)
+	assert.Equal(t, headers[2].Hash, hash)
+
+	diff, ok := b.GetTD(headers[2].Hash)
+	assert.True(t, ok)
+	assert.Equal(t, diff, b.CurrentTD())
+
+	// the blocks above are not canonical, and their transactions are not looked up
+	for _, header := range headers[3:] {
+		_, ok := b.GetHeaderByNumber(header.Number)
+		assert.False(t, ok)
+
+		_, ok = b.GetHeaderByHash(header.Hash)
+		assert.True(t, ok)
+	}
+
+	_, _, ok = b.ReadTxLookup(txn.Hash)
+	assert.False(t, ok)
+
+	// the chain grows again from the new head
+	assert.NoError(t, b.WriteHeaders(headers[3:4]))
+	assert.Equal(t, headers[3].Hash, b.Header().Hash)
+}
+
+func TestRollback(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+
+	headers := NewTestHeaderChain(6)
+
+	// a fork branching off below the target, and another one above it
+	forkBelow := NewTestHeaderFromChainWithSeed(headers[:2], 2, 10)
+	forkAbove := NewTestHeaderFromChainWithSeed(headers[:4], 1, 20)
+
+	txn := newLookupTestTx(1)
+
+	assert.NoError(t, b.db.WriteBody(headers[4].Hash, &types.Body{
+		Transactions: []*types.Transaction{txn},
+	}))
+
+	_, err := b.advanceHead(headers[0])
+	assert.NoError(t, err)
+	assert.NoError(t, b.WriteHeaders(headers[1:]))
+	assert.NoError(t, b.writeTxLookups(b.db, headers[4].Hash, []*types.Transaction{txn}))
+	assert.NoError(t, b.WriteHeaders(forkBelow[2:]))
+	assert.NoError(t, b.WriteHeaders(forkAbove[4:]))
+	assert.Equal(t, headers[5].Hash, b.Header().Hash)
+
+	_, err = b.Rollback(5)
+	assert.ErrorIs(t, err, ErrRollbackAboveHead)
+
+	result, err := b.Rollback(2)
+	assert.NoError(t, err)
+	assert.Equal(t, &RollbackResult{
+		From:      5,
+		To:        2,
+		Head:      headers[2].Hash,
+		Blocks:    3,
+		Forks:     2,
+		TxLookups: 1,
+	}, result)
+	assert.Equal(t, headers[2].Hash, b.Header().Hash)
+	assert.LessOrEqual(t, b.FinalizedHeader().Number, uint64(2))
+
+	number, ok := b.db.ReadHeadNumber()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(2), number)
+
+	diff, ok := b.GetTD(headers[2].Hash)
+	assert.True(t, ok)
+	assert.Equal(t, diff, b.CurrentTD())
+
+	// the blocks above the target are deleted, the fork below it is kept with its remaining tip
+	for _, header := range []*types.Header{headers[3], headers[4], headers[5], forkBelow[3], forkAbove[4]} {
+		_, ok := b.GetHeaderByHash(header.Hash)
+		assert.False(t, ok)
+
+		_, ok = b.GetTD(header.Hash)
+		assert.False(t, ok)
+	}
+
+	_, ok = b.GetHeaderByNumber(3)
+	assert.False(t, ok)
+
+	_, _, ok = b.ReadTxLookup(txn.Hash)
+	assert.False(t, ok)
+
+	forks, err := b.GetForks()
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Hash{forkBelow[2].Hash}, forks)
+
+	// the chain grows again from the new head
+	assert.NoError(t, b.WriteHeaders(headers[3:4]))
+	assert.Equal(t, headers[3].Hash, b.Header().Hash)
+}
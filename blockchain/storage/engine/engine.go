@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/leveldb"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/pebble"
+	"github.com/hashicorp/go-hclog"
+)
+
+// Engine is the key value database of the data dir
+type Engine string
+
+const (
+	LevelDB Engine = "leveldb"
+	Pebble  Engine = "pebble"
+)
+
+// Default is the engine of the data dirs written before the engine could be chosen
+const Default = LevelDB
+
+// pebbleOptionsPrefix is the prefix of the options files only written by pebble
+const pebbleOptionsPrefix = "OPTIONS-"
+
+// engines open the kv storages, the leveldb options are ignored by the other engines
+var engines = map[Engine]func(path string, options *leveldb.Options) (storage.KV, error){
+	LevelDB: leveldb.NewLevelDBKVWithOptions,
+	Pebble: func(path string, _ *leveldb.Options) (storage.KV, error) {
+		return pebble.NewPebbleKV(path)
+	},
+}
+
+// Supported checks if the engine is available
+func Supported(value string) bool {
+	_, ok := engines[Engine(value)]
+
+	return ok
+}
+
+// Names returns the names of the available engines
+func Names() []string {
+	return []string{string(LevelDB), string(Pebble)}
+}
+
+// Detect returns the engine of the database at the path, false if there is no database
+func Detect(path string) (Engine, bool) {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return "", false
+	}
+
+	found := false
+
+	for _, file := range files {
+		if strings.HasPrefix(file.Name(), pebbleOptionsPrefix) {
+			return Pebble, true
+		}
+
+		if file.Name() == "CURRENT" {
+			found = true
+		}
+	}
+
+	if !found {
+		return "", false
+	}
+
+	return LevelDB, true
+}
+
+// Resolve returns the engine to open the database at the path with. The engine of the database
+// is used if none is set, the default one for a new database
+func Resolve(engine Engine, path string) (Engine, error) {
+	detected, ok := Detect(path)
+
+	switch {
+	case engine == "" && ok:
+		return detected, nil
+	case engine == "":
+		return Default, nil
+	case ok && detected != engine:
+		return "", fmt.Errorf(
+			"the database at %s is a %s database, it has to be migrated to be opened with %s",
+			path,
+			detected,
+			engine,
+		)
+	}
+
+	if !Supported(string(engine)) {
+		return "", fmt.Errorf("unknown database engine %s", engine)
+	}
+
+	return engine, nil
+}
+
+// OpenKV opens the kv storage of the engine at the path, see Resolve for the engine selection.
+// The leveldb options are only used by the leveldb databases, the defaults if nil
+func OpenKV(engine Engine, path string, options *leveldb.Options) (storage.KV, error) {
+	engine, err := Resolve(engine, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return engines[engine](path, options)
+}
+
+// NewStorage opens the blockchain storage of the engine at the path
+func NewStorage(engine Engine, path string, logger hclog.Logger) (storage.Storage, error) {
+	resolved, err := Resolve(engine, path)
+	if err != nil {
+		return nil, err
+	}
+
+	kv, err := OpenKV(resolved, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.NewKeyValueStorage(logger.Named(string(resolved)), kv), nil
+}
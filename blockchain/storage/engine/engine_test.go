@@ -0,0 +1,208 @@
+package engine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTempDir(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "engine")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	return dir
+}
+
+func writeKV(t *testing.T, engine Engine, path string, pairs map[string]string) {
+	t.Helper()
+
+	kv, err := OpenKV(engine, path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, value := range pairs {
+		assert.NoError(t, kv.Set([]byte(key), []byte(value)))
+	}
+
+	assert.NoError(t, kv.Close())
+}
+
+func TestResolve(t *testing.T) {
+	dir := newTempDir(t)
+
+	levelDBPath, pebblePath := filepath.Join(dir, "leveldb"), filepath.Join(dir, "pebble")
+
+	writeKV(t, LevelDB, levelDBPath, nil)
+	writeKV(t, Pebble, pebblePath, nil)
+
+	detected, ok := Detect(levelDBPath)
+	assert.True(t, ok)
+	assert.Equal(t, LevelDB, detected)
+
+	detected, ok = Detect(pebblePath)
+	assert.True(t, ok)
+	assert.Equal(t, Pebble, detected)
+
+	_, ok = Detect(filepath.Join(dir, "empty"))
+	assert.False(t, ok)
+
+	// the engine of the existing database is used if none is set
+	resolved, err := Resolve("", pebblePath)
+	assert.NoError(t, err)
+	assert.Equal(t, Pebble, resolved)
+
+	resolved, err = Resolve("", filepath.Join(dir, "empty"))
+	assert.NoError(t, err)
+	assert.Equal(t, Default, resolved)
+
+	// an existing database is not opened with another engine
+	_, err = Resolve(Pebble, levelDBPath)
+	assert.Error(t, err)
+
+	_, err = OpenKV(LevelDB, pebblePath, nil)
+	assert.Error(t, err)
+
+	_, err = Resolve("rocksdb", filepath.Join(dir, "empty"))
+	assert.Error(t, err)
+}
+
+func TestMigrate(t *testing.T) {
+	dir := newTempDir(t)
+	path := filepath.Join(dir, "blockchain")
+
+	pairs := map[string]string{
+		"a":  "1",
+		"b1": "2",
+		"b2": "3",
+	}
+
+	writeKV(t, LevelDB, path, pairs)
+
+	var progress uint64
+
+	res, err := Migrate(path, Pebble, func(pairs uint64) {
+		progress = pairs
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, LevelDB, res.From)
+	assert.Equal(t, uint64(3), res.Pairs)
+	assert.Equal(t, uint64(3), progress)
+	assert.Equal(t, path+".leveldb.bak", res.Backup)
+
+	// the database is replaced, the original one is kept as the backup
+	detected, _ := Detect(path)
+	assert.Equal(t, Pebble, detected)
+
+	detected, _ = Detect(res.Backup)
+	assert.Equal(t, LevelDB, detected)
+
+	kv, err := OpenKV("", path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, value := range pairs {
+		found, ok, err := kv.Get([]byte(key))
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, value, string(found))
+	}
+
+	assert.NoError(t, kv.Close())
+
+	// the database is already migrated
+	_, err = Migrate(path, Pebble, nil)
+	assert.Error(t, err)
+
+	// the backup of a previous migration is not overwritten
+	writeKV(t, Pebble, path+".pebble.bak", nil)
+
+	_, err = Migrate(path, LevelDB, nil)
+	assert.Error(t, err)
+
+	_, err = Migrate(filepath.Join(dir, "missing"), Pebble, nil)
+	assert.Error(t, err)
+}
+
+func TestNewStorage(t *testing.T) {
+	dir := newTempDir(t)
+
+	for _, engine := range []Engine{LevelDB, Pebble} {
+		path := filepath.Join(dir, string(engine))
+
+		s, err := NewStorage(engine, path, hclog.NewNullLogger())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NoError(t, s.WriteHeadNumber(10))
+		assert.NoError(t, s.Close())
+
+		// reopened with the detected engine
+		s, err = NewStorage("", path, hclog.NewNullLogger())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		number, ok := s.ReadHeadNumber()
+		assert.True(t, ok)
+		assert.Equal(t, uint64(10), number)
+		assert.NoError(t, s.Close())
+	}
+}
+
+func TestCopy_Batches(t *testing.T) {
+	dir := newTempDir(t)
+
+	src, err := OpenKV(LevelDB, filepath.Join(dir, "src"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer src.Close()
+
+	dst, err := OpenKV(Pebble, filepath.Join(dir, "dst"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer dst.Close()
+
+	// the pairs are split in 3 batches
+	value := make([]byte, copyBatchSize/2)
+	for _, key := range []string{"1", "2", "3", "4", "5"} {
+		assert.NoError(t, src.Set([]byte(key), value))
+	}
+
+	calls := []uint64{}
+
+	pairs, err := Copy(src, dst, func(pairs uint64) {
+		calls = append(calls, pairs)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), pairs)
+	assert.Equal(t, []uint64{2, 4, 5}, calls)
+
+	iter := dst.NewIterator(nil)
+	defer iter.Close()
+
+	count := 0
+	for iter.Next() {
+		count++
+	}
+
+	assert.Equal(t, 5, count)
+}
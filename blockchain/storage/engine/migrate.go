@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+)
+
+// copyBatchSize is the size of the pairs buffered before they are written in a single batch
+const copyBatchSize = 16 << 20
+
+// Copy copies all the key value pairs of the source to the destination. The progress,
+// if set, is called with the number of pairs copied so far after every batch
+func Copy(src, dst storage.KV, progress func(pairs uint64)) (uint64, error) {
+	iter := src.NewIterator(nil)
+	defer iter.Close()
+
+	var (
+		pairs uint64
+		size  int
+		batch = dst.NewBatch()
+	)
+
+	for iter.Next() {
+		// the batches hold the slices until written
+		key := append([]byte{}, iter.Key()...)
+		value := append([]byte{}, iter.Value()...)
+
+		batch.Set(key, value)
+
+		pairs++
+		size += len(key) + len(value)
+
+		if size < copyBatchSize {
+			continue
+		}
+
+		if err := batch.Write(); err != nil {
+			return pairs, err
+		}
+
+		if progress != nil {
+			progress(pairs)
+		}
+
+		batch, size = dst.NewBatch(), 0
+	}
+
+	if err := iter.Error(); err != nil {
+		return pairs, err
+	}
+
+	if err := batch.Write(); err != nil {
+		return pairs, err
+	}
+
+	if progress != nil {
+		progress(pairs)
+	}
+
+	return pairs, nil
+}
+
+// MigrationResult is the result of the migration of a database
+type MigrationResult struct {
+	From   Engine
+	Pairs  uint64
+	Backup string
+}
+
+// Migrate copies the database at the path into a new database of the engine which replaces it.
+// The original database is moved next to it as a backup, it is left untouched on failure
+func Migrate(path string, to Engine, progress func(pairs uint64)) (*MigrationResult, error) {
+	from, ok := Detect(path)
+	if !ok {
+		return nil, fmt.Errorf("no database found at %s", path)
+	}
+
+	if from == to {
+		return nil, fmt.Errorf("the database at %s is already a %s database", path, to)
+	}
+
+	if !Supported(string(to)) {
+		return nil, fmt.Errorf("unknown database engine %s", to)
+	}
+
+	backup := fmt.Sprintf("%s.%s.bak", path, from)
+	if _, err := os.Stat(backup); err == nil {
+		return nil, fmt.Errorf("the backup path %s already exists", backup)
+	}
+
+	// the leftovers of an interrupted migration are dropped
+	migrating := path + ".migrating"
+	if err := os.RemoveAll(migrating); err != nil {
+		return nil, err
+	}
+
+	pairs, err := copyDatabase(path, from, migrating, to, progress)
+	if err != nil {
+		_ = os.RemoveAll(migrating)
+
+		return nil, err
+	}
+
+	if err := os.Rename(path, backup); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(migrating, path); err != nil {
+		return nil, err
+	}
+
+	return &MigrationResult{
+		From:   from,
+		Pairs:  pairs,
+		Backup: backup,
+	}, nil
+}
+
+// copyDatabase copies the database at the path to a new database of the destination engine
+func copyDatabase(
+	srcPath string,
+	srcEngine Engine,
+	dstPath string,
+	dstEngine Engine,
+	progress func(pairs uint64),
+) (uint64, error) {
+	src, err := engines[srcEngine](srcPath, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer src.Close()
+
+	dst, err := engines[dstEngine](dstPath, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	pairs, err := Copy(src, dst, progress)
+
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+
+	return pairs, err
+}
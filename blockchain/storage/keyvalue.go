@@ -39,13 +39,18 @@ var (
 
 	// TX_LOOKUP_PREFIX is the prefix for transaction lookups
 	TX_LOOKUP_PREFIX = []byte("l")
+
+	// INTERNAL_TXS is the prefix for the internal transactions of the blocks
+	INTERNAL_TXS = []byte("i")
 )
 
 // Sub-prefixes
 var (
-	HASH   = []byte("hash")
-	NUMBER = []byte("number")
-	EMPTY  = []byte("empty")
+	HASH      = []byte("hash")
+	NUMBER    = []byte("number")
+	EMPTY     = []byte("empty")
+	TXLOOKUP  = []byte("txlookup")
+	FINALIZED = []byte("finalized")
 )
 
 // KV is a key value storage interface.
@@ -55,27 +60,101 @@ type KV interface {
 	Close() error
 	Set(p []byte, v []byte) error
 	Get(p []byte) ([]byte, bool, error)
+	Delete(p []byte) error
+	NewBatch() KVBatch
+	NewIterator(prefix []byte) KVIterator
+}
+
+// KVCompacter is implemented by the kv storages which can be compacted on demand
+type KVCompacter interface {
+	// Compact compacts the whole key range of the storage
+	Compact() error
+}
+
+// KVBatch is a set of key value writes, applied atomically and in order by Write
+type KVBatch interface {
+	Set(p []byte, v []byte)
+	Delete(p []byte)
+	Write() error
+}
+
+// KVIterator iterates over the key value pairs of a prefix in the key order. The key and the value
+// are only valid until the next call of Next, the iterator has to be closed before the kv storage
+type KVIterator interface {
+	Next() bool
+	Key() []byte
+	Value() []byte
+	Error() error
+	Close() error
 }
 
 // KeyValueStorage is a generic storage for kv databases
 type KeyValueStorage struct {
+	keyValueWriter
+
 	logger hclog.Logger
 	db     KV
 	Db     KV
 }
 
 func NewKeyValueStorage(logger hclog.Logger, db KV) Storage {
-	return &KeyValueStorage{logger: logger, db: db}
+	return &KeyValueStorage{
+		keyValueWriter: keyValueWriter{
+			setFn:    db.Set,
+			deleteFn: db.Delete,
+		},
+		logger: logger,
+		db:     db,
+	}
+}
+
+// keyValueWriter encodes the writes of the blockchain objects into key value writes
+type keyValueWriter struct {
+	setFn    func(p []byte, v []byte) error
+	deleteFn func(p []byte) error
+}
+
+// keyValueBatch is the batch of the kv storage
+type keyValueBatch struct {
+	keyValueWriter
+
+	batch KVBatch
+}
+
+// NewBatch creates a batch of writes to the storage
+func (s *KeyValueStorage) NewBatch() Batch {
+	batch := s.db.NewBatch()
+
+	return &keyValueBatch{
+		keyValueWriter: keyValueWriter{
+			setFn: func(p []byte, v []byte) error {
+				batch.Set(p, v)
+
+				return nil
+			},
+			deleteFn: func(p []byte) error {
+				batch.Delete(p)
+
+				return nil
+			},
+		},
+		batch: batch,
+	}
 }
 
-func (s *KeyValueStorage) encodeUint(n uint64) []byte {
+// Write commits the writes of the batch
+func (b *keyValueBatch) Write() error {
+	return b.batch.Write()
+}
+
+func encodeUint(n uint64) []byte {
 	b := make([]byte, 8)
 	binary.BigEndian.PutUint64(b[:], n)
 
 	return b[:]
 }
 
-func (s *KeyValueStorage) decodeUint(b []byte) uint64 {
+func decodeUint(b []byte) uint64 {
 	return binary.BigEndian.Uint64(b[:])
 }
 
@@ -83,7 +162,7 @@ func (s *KeyValueStorage) decodeUint(b []byte) uint64 {
 
 // ReadCanonicalHash gets the hash from the number of the canonical chain
 func (s *KeyValueStorage) ReadCanonicalHash(n uint64) (types.Hash, bool) {
-	data, ok := s.get(CANONICAL, s.encodeUint(n))
+	data, ok := s.get(CANONICAL, encodeUint(n))
 	if !ok {
 		return types.Hash{}, false
 	}
@@ -92,8 +171,13 @@ func (s *KeyValueStorage) ReadCanonicalHash(n uint64) (types.Hash, bool) {
 }
 
 // WriteCanonicalHash writes a hash for a number block in the canonical chain
-func (s *KeyValueStorage) WriteCanonicalHash(n uint64, hash types.Hash) error {
-	return s.set(CANONICAL, s.encodeUint(n), hash.Bytes())
+func (w *keyValueWriter) WriteCanonicalHash(n uint64, hash types.Hash) error {
+	return w.set(CANONICAL, encodeUint(n), hash.Bytes())
+}
+
+// DeleteCanonicalHash removes the hash of a number block from the canonical chain
+func (w *keyValueWriter) DeleteCanonicalHash(n uint64) error {
+	return w.delete(CANONICAL, encodeUint(n))
 }
 
 // HEAD //
@@ -119,26 +203,41 @@ func (s *KeyValueStorage) ReadHeadNumber() (uint64, bool) {
 		return 0, false
 	}
 
-	return s.decodeUint(data), true
+	return decodeUint(data), true
 }
 
 // WriteHeadHash writes the hash of the head
-func (s *KeyValueStorage) WriteHeadHash(h types.Hash) error {
-	return s.set(HEAD, HASH, h.Bytes())
+func (w *keyValueWriter) WriteHeadHash(h types.Hash) error {
+	return w.set(HEAD, HASH, h.Bytes())
 }
 
 // WriteHeadNumber writes the number of the head
-func (s *KeyValueStorage) WriteHeadNumber(n uint64) error {
-	return s.set(HEAD, NUMBER, s.encodeUint(n))
+func (w *keyValueWriter) WriteHeadNumber(n uint64) error {
+	return w.set(HEAD, NUMBER, encodeUint(n))
+}
+
+// ReadFinalizedHash returns the hash of the finalized block
+func (s *KeyValueStorage) ReadFinalizedHash() (types.Hash, bool) {
+	data, ok := s.get(HEAD, FINALIZED)
+	if !ok {
+		return types.Hash{}, false
+	}
+
+	return types.BytesToHash(data), true
+}
+
+// WriteFinalizedHash writes the hash of the finalized block
+func (w *keyValueWriter) WriteFinalizedHash(h types.Hash) error {
+	return w.set(HEAD, FINALIZED, h.Bytes())
 }
 
 // FORK //
 
 // WriteForks writes the current forks
-func (s *KeyValueStorage) WriteForks(forks []types.Hash) error {
+func (w *keyValueWriter) WriteForks(forks []types.Hash) error {
 	ff := Forks(forks)
 
-	return s.writeRLP(FORK, EMPTY, &ff)
+	return w.writeRLP(FORK, EMPTY, &ff)
 }
 
 // ReadForks read the current forks
@@ -152,8 +251,8 @@ func (s *KeyValueStorage) ReadForks() ([]types.Hash, error) {
 // DIFFICULTY //
 
 // WriteTotalDifficulty writes the difficulty
-func (s *KeyValueStorage) WriteTotalDifficulty(hash types.Hash, diff *big.Int) error {
-	return s.set(DIFFICULTY, hash.Bytes(), diff.Bytes())
+func (w *keyValueWriter) WriteTotalDifficulty(hash types.Hash, diff *big.Int) error {
+	return w.set(DIFFICULTY, hash.Bytes(), diff.Bytes())
 }
 
 // ReadTotalDifficulty reads the difficulty
@@ -169,8 +268,8 @@ func (s *KeyValueStorage) ReadTotalDifficulty(hash types.Hash) (*big.Int, bool)
 // HEADER //
 
 // WriteHeader writes the header
-func (s *KeyValueStorage) WriteHeader(h *types.Header) error {
-	return s.writeRLP(HEADER, h.Hash.Bytes(), h)
+func (w *keyValueWriter) WriteHeader(h *types.Header) error {
+	return w.writeRLP(HEADER, h.Hash.Bytes(), h)
 }
 
 // ReadHeader reads the header
@@ -181,36 +280,59 @@ func (s *KeyValueStorage) ReadHeader(hash types.Hash) (*types.Header, error) {
 	return header, err
 }
 
-// WriteCanonicalHeader implements the storage interface
-func (s *KeyValueStorage) WriteCanonicalHeader(h *types.Header, diff *big.Int) error {
-	if err := s.WriteHeader(h); err != nil {
+// WriteCanonicalHeader writes the header as the head of the canonical chain
+func (w *keyValueWriter) WriteCanonicalHeader(h *types.Header, diff *big.Int) error {
+	if err := w.WriteHeader(h); err != nil {
+		return err
+	}
+
+	if err := w.WriteHeadHash(h.Hash); err != nil {
 		return err
 	}
 
-	if err := s.WriteHeadHash(h.Hash); err != nil {
+	if err := w.WriteHeadNumber(h.Number); err != nil {
 		return err
 	}
 
-	if err := s.WriteHeadNumber(h.Number); err != nil {
+	if err := w.WriteCanonicalHash(h.Number, h.Hash); err != nil {
 		return err
 	}
 
-	if err := s.WriteCanonicalHash(h.Number, h.Hash); err != nil {
+	if err := w.WriteTotalDifficulty(h.Hash, diff); err != nil {
 		return err
 	}
 
-	if err := s.WriteTotalDifficulty(h.Hash, diff); err != nil {
+	return nil
+}
+
+// WriteCanonicalHeader writes the header as the head of the canonical chain, atomically
+func (s *KeyValueStorage) WriteCanonicalHeader(h *types.Header, diff *big.Int) error {
+	batch := s.NewBatch()
+
+	if err := batch.WriteCanonicalHeader(h, diff); err != nil {
 		return err
 	}
 
+	return batch.Write()
+}
+
+// DeleteBlock deletes the header, the body, the receipts, the internal transactions
+// and the total difficulty of the block
+func (w *keyValueWriter) DeleteBlock(hash types.Hash) error {
+	for _, prefix := range [][]byte{HEADER, BODY, RECEIPTS, INTERNAL_TXS, DIFFICULTY} {
+		if err := w.delete(prefix, hash.Bytes()); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // BODY //
 
 // WriteBody writes the body
-func (s *KeyValueStorage) WriteBody(hash types.Hash, body *types.Body) error {
-	return s.writeRLP(BODY, hash.Bytes(), body)
+func (w *keyValueWriter) WriteBody(hash types.Hash, body *types.Body) error {
+	return w.writeRLP(BODY, hash.Bytes(), body)
 }
 
 // ReadBody reads the body
@@ -224,8 +346,8 @@ func (s *KeyValueStorage) ReadBody(hash types.Hash) (*types.Body, error) {
 // SNAPSHOTS //
 
 // WriteSnapshot writes the snapshot to the DB
-func (s *KeyValueStorage) WriteSnapshot(hash types.Hash, blob []byte) error {
-	return s.set(SNAPSHOTS, hash.Bytes(), blob)
+func (w *keyValueWriter) WriteSnapshot(hash types.Hash, blob []byte) error {
+	return w.set(SNAPSHOTS, hash.Bytes(), blob)
 }
 
 // ReadSnapshot reads the snapshot from the DB
@@ -241,10 +363,10 @@ func (s *KeyValueStorage) ReadSnapshot(hash types.Hash) ([]byte, bool) {
 // RECEIPTS //
 
 // WriteReceipts writes the receipts
-func (s *KeyValueStorage) WriteReceipts(hash types.Hash, receipts []*types.Receipt) error {
+func (w *keyValueWriter) WriteReceipts(hash types.Hash, receipts []*types.Receipt) error {
 	rr := types.Receipts(receipts)
 
-	return s.writeRLP(RECEIPTS, hash.Bytes(), &rr)
+	return w.writeRLP(RECEIPTS, hash.Bytes(), &rr)
 }
 
 // ReadReceipts reads the receipts
@@ -255,38 +377,89 @@ func (s *KeyValueStorage) ReadReceipts(hash types.Hash) ([]*types.Receipt, error
 	return *receipts, err
 }
 
+// INTERNAL TRANSACTIONS //
+
+// WriteInternalTxs writes the internal transactions of the block
+func (w *keyValueWriter) WriteInternalTxs(hash types.Hash, internalTxs []*types.InternalTransaction) error {
+	ii := types.InternalTransactions(internalTxs)
+
+	return w.writeRLP(INTERNAL_TXS, hash.Bytes(), &ii)
+}
+
+// ReadInternalTxs reads the internal transactions of the block,
+// ErrNotFound is returned if the block was not indexed
+func (s *KeyValueStorage) ReadInternalTxs(hash types.Hash) ([]*types.InternalTransaction, error) {
+	internalTxs := &types.InternalTransactions{}
+	err := s.readRLP(INTERNAL_TXS, hash.Bytes(), internalTxs)
+
+	return *internalTxs, err
+}
+
 // TX LOOKUP //
 
-// WriteTxLookup maps the transaction hash to the block hash
-func (s *KeyValueStorage) WriteTxLookup(hash types.Hash, blockHash types.Hash) error {
+// WriteTxLookup maps the transaction hash to the block hash and the index of the transaction in the block
+func (w *keyValueWriter) WriteTxLookup(hash types.Hash, blockHash types.Hash, index uint64) error {
 	ar := &fastrlp.Arena{}
-	vr := ar.NewBytes(blockHash.Bytes())
 
-	return s.write2(TX_LOOKUP_PREFIX, hash.Bytes(), vr)
+	vr := ar.NewArray()
+	vr.Set(ar.NewBytes(blockHash.Bytes()))
+	vr.Set(ar.NewUint(index))
+
+	return w.write2(TX_LOOKUP_PREFIX, hash.Bytes(), vr)
 }
 
-// ReadTxLookup reads the block hash using the transaction hash
-func (s *KeyValueStorage) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
+// ReadTxLookup reads the block hash and the index of the transaction using the transaction hash
+func (s *KeyValueStorage) ReadTxLookup(hash types.Hash) (types.Hash, uint64, bool) {
 	parser := &fastrlp.Parser{}
 
 	v := s.read2(TX_LOOKUP_PREFIX, hash.Bytes(), parser)
 	if v == nil {
-		return types.Hash{}, false
+		return types.Hash{}, 0, false
+	}
+
+	// the lookups written before the version 1 only hold the block hash,
+	// they are rewritten by the migration
+	elems, err := v.GetElems()
+	if err != nil || len(elems) != 2 {
+		return types.Hash{}, 0, false
 	}
 
-	blockHash := []byte{}
-	blockHash, err := v.GetBytes(blockHash[:0], 32)
+	blockHash, err := elems[0].GetBytes(nil, 32)
+	if err != nil {
+		return types.Hash{}, 0, false
+	}
 
+	index, err := elems[1].GetUint64()
 	if err != nil {
-		panic(err)
+		return types.Hash{}, 0, false
 	}
 
-	return types.BytesToHash(blockHash), true
+	return types.BytesToHash(blockHash), index, true
+}
+
+// DeleteTxLookup removes the lookup of the transaction
+func (w *keyValueWriter) DeleteTxLookup(hash types.Hash) error {
+	return w.delete(TX_LOOKUP_PREFIX, hash.Bytes())
+}
+
+// WriteTxLookupVersion writes the format version of the transaction lookups
+func (w *keyValueWriter) WriteTxLookupVersion(version uint64) error {
+	return w.set(HEAD, TXLOOKUP, encodeUint(version))
+}
+
+// ReadTxLookupVersion reads the format version of the transaction lookups
+func (s *KeyValueStorage) ReadTxLookupVersion() (uint64, bool) {
+	data, ok := s.get(HEAD, TXLOOKUP)
+	if !ok || len(data) != 8 {
+		return 0, false
+	}
+
+	return decodeUint(data), true
 }
 
 // WRITE OPERATIONS //
 
-func (s *KeyValueStorage) writeRLP(p, k []byte, raw types.RLPMarshaler) error {
+func (w *keyValueWriter) writeRLP(p, k []byte, raw types.RLPMarshaler) error {
 	var data []byte
 	if obj, ok := raw.(types.RLPStoreMarshaler); ok {
 		data = obj.MarshalStoreRLPTo(nil)
@@ -294,7 +467,7 @@ func (s *KeyValueStorage) writeRLP(p, k []byte, raw types.RLPMarshaler) error {
 		data = raw.MarshalRLPTo(nil)
 	}
 
-	return s.set(p, k, data)
+	return w.set(p, k, data)
 }
 
 var ErrNotFound = fmt.Errorf("not found")
@@ -340,16 +513,27 @@ func (s *KeyValueStorage) read2(p, k []byte, parser *fastrlp.Parser) *fastrlp.Va
 	return v
 }
 
-func (s *KeyValueStorage) write2(p, k []byte, v *fastrlp.Value) error {
+func (w *keyValueWriter) write2(p, k []byte, v *fastrlp.Value) error {
 	dst := v.MarshalTo(nil)
 
-	return s.set(p, k, dst)
+	return w.set(p, k, dst)
 }
 
-func (s *KeyValueStorage) set(p []byte, k []byte, v []byte) error {
-	p = append(p, k...)
+func (w *keyValueWriter) set(p []byte, k []byte, v []byte) error {
+	return w.setFn(batchKey(p, k), v)
+}
+
+func (w *keyValueWriter) delete(p []byte, k []byte) error {
+	return w.deleteFn(batchKey(p, k))
+}
+
+// batchKey joins the prefix and the key in a new slice, the batches
+// hold the keys until written so they can't share the prefix array
+func batchKey(p []byte, k []byte) []byte {
+	key := make([]byte, 0, len(p)+len(k))
+	key = append(key, p...)
 
-	return s.db.Set(p, v)
+	return append(key, k...)
 }
 
 func (s *KeyValueStorage) get(p []byte, k []byte) ([]byte, bool) {
@@ -2,12 +2,39 @@ package leveldb
 
 import (
 	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/go-kit/kit/metrics"
 	"github.com/hashicorp/go-hclog"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
+// statsInterval is the interval the statistics of the database are reported to the metrics at
+const statsInterval = 10 * time.Second
+
+// Options are the tuning of the leveldb storage, the defaults of goleveldb are used for the zero values
+type Options struct {
+	// WriteBufferSize is the size of the memtable in MB, a larger one is flushed
+	// less often to fewer and larger level 0 tables
+	WriteBufferSize uint64
+
+	// BlockCacheSize is the size of the cache of the table blocks in MB
+	BlockCacheSize uint64
+
+	// OpenFilesLimit is the number of the table files kept open
+	OpenFilesLimit uint64
+
+	// Metrics are the metrics the storage reports to, labeled by the name of its directory.
+	// Nothing is reported if not set
+	Metrics *Metrics
+}
+
 // Factory creates a leveldb storage
 func Factory(config map[string]interface{}, logger hclog.Logger) (storage.Storage, error) {
 	path, ok := config["path"]
@@ -25,28 +52,72 @@ func Factory(config map[string]interface{}, logger hclog.Logger) (storage.Storag
 
 // NewLevelDBStorage creates the new storage reference with leveldb
 func NewLevelDBStorage(path string, logger hclog.Logger) (storage.Storage, error) {
-	db, err := leveldb.OpenFile(path, nil)
+	kv, err := NewLevelDBKV(path)
 	if err != nil {
 		return nil, err
 	}
 
-	kv := &levelDBKV{db}
-
 	return storage.NewKeyValueStorage(logger.Named("leveldb"), kv), nil
 }
 
+// NewLevelDBKV opens the leveldb kv storage at the path, it is created if needed
+func NewLevelDBKV(path string) (storage.KV, error) {
+	return NewLevelDBKVWithOptions(path, nil)
+}
+
+// NewLevelDBKVWithOptions opens the leveldb kv storage at the path with the options, it is created if needed
+func NewLevelDBKVWithOptions(path string, options *Options) (storage.KV, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	db, err := leveldb.OpenFile(path, &opt.Options{
+		WriteBuffer:            int(options.WriteBufferSize << 20),
+		BlockCacheCapacity:     int(options.BlockCacheSize << 20),
+		OpenFilesCacheCapacity: int(options.OpenFilesLimit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	kv := &levelDBKV{
+		db:      db,
+		metrics: NilMetrics(),
+	}
+
+	if options.Metrics != nil {
+		kv.metrics = options.Metrics.withDatabase(filepath.Base(path))
+		kv.startStats(statsInterval)
+	}
+
+	return kv, nil
+}
+
 // levelDBKV is the leveldb implementation of the kv storage
 type levelDBKV struct {
-	db *leveldb.DB
+	db      *leveldb.DB
+	metrics *Metrics
+
+	closeCh chan struct{} // Closed to stop reporting the statistics, nil if they are not reported
+	doneCh  chan struct{} // Closed once the statistics are not reported anymore
+}
+
+// observeLatency observes the time elapsed since the start
+func observeLatency(histogram metrics.Histogram, start time.Time) {
+	histogram.Observe(time.Since(start).Seconds())
 }
 
 // Set sets the key-value pair in leveldb storage
 func (l *levelDBKV) Set(p []byte, v []byte) error {
+	defer observeLatency(l.metrics.WriteLatency, time.Now())
+
 	return l.db.Put(p, v, nil)
 }
 
 // Get retrieves the key-value pair in leveldb storage
 func (l *levelDBKV) Get(p []byte) ([]byte, bool, error) {
+	defer observeLatency(l.metrics.ReadLatency, time.Now())
+
 	data, err := l.db.Get(p, nil)
 	if err != nil {
 		if err.Error() == "leveldb: not found" {
@@ -59,7 +130,157 @@ func (l *levelDBKV) Get(p []byte) ([]byte, bool, error) {
 	return data, true, nil
 }
 
+// Delete removes the key-value pair from leveldb storage
+func (l *levelDBKV) Delete(p []byte) error {
+	defer observeLatency(l.metrics.WriteLatency, time.Now())
+
+	return l.db.Delete(p, nil)
+}
+
+// NewBatch creates a leveldb batch, written atomically
+func (l *levelDBKV) NewBatch() storage.KVBatch {
+	return &levelDBBatch{db: l.db, batch: &leveldb.Batch{}, metrics: l.metrics}
+}
+
+// NewIterator creates an iterator over the key-value pairs with the prefix
+func (l *levelDBKV) NewIterator(prefix []byte) storage.KVIterator {
+	return &levelDBIterator{l.db.NewIterator(util.BytesPrefix(prefix), nil)}
+}
+
+// Compact compacts the whole key range, the deleted and the overwritten pairs are discarded
+func (l *levelDBKV) Compact() error {
+	return l.db.CompactRange(util.Range{})
+}
+
 // Close closes the leveldb storage instance
 func (l *levelDBKV) Close() error {
+	l.closeStats()
+
 	return l.db.Close()
 }
+
+// startStats starts reporting the statistics of the database at every interval
+func (l *levelDBKV) startStats(interval time.Duration) {
+	l.closeCh = make(chan struct{})
+	l.doneCh = make(chan struct{})
+
+	go l.reportStats(interval)
+}
+
+// closeStats stops reporting the statistics of the database, if they are reported
+func (l *levelDBKV) closeStats() {
+	if l.closeCh == nil {
+		return
+	}
+
+	close(l.closeCh)
+	<-l.doneCh
+
+	l.closeCh = nil
+}
+
+// reportStats reports the statistics of the database to the metrics at every interval, until closed.
+// The counters of the database are cumulative, their increase since the last report is added
+func (l *levelDBKV) reportStats(interval time.Duration) {
+	defer close(l.doneCh)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var prev leveldb.DBStats
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-l.closeCh:
+			return
+		}
+
+		var stats leveldb.DBStats
+		if err := l.db.Stats(&stats); err != nil {
+			continue
+		}
+
+		compactions := []struct {
+			kind      string
+			now, prev uint32
+		}{
+			{"memtable", stats.MemComp, prev.MemComp},
+			{"level0", stats.Level0Comp, prev.Level0Comp},
+			{"nonlevel0", stats.NonLevel0Comp, prev.NonLevel0Comp},
+			{"seek", stats.SeekComp, prev.SeekComp},
+		}
+
+		for _, compaction := range compactions {
+			l.metrics.Compactions.With("kind", compaction.kind).Add(float64(compaction.now - compaction.prev))
+		}
+
+		l.metrics.CompactionReadBytes.Add(float64(stats.LevelRead.Sum() - prev.LevelRead.Sum()))
+		l.metrics.CompactionWriteBytes.Add(float64(stats.LevelWrite.Sum() - prev.LevelWrite.Sum()))
+		l.metrics.WriteDelay.Add((stats.WriteDelayDuration - prev.WriteDelayDuration).Seconds())
+		l.metrics.OpenFiles.Set(float64(stats.OpenedTablesCount))
+
+		for level, size := range stats.LevelSizes {
+			l.metrics.LevelSize.With("level", strconv.Itoa(level)).Set(float64(size))
+		}
+
+		prev = stats
+	}
+}
+
+// levelDBBatch is the leveldb implementation of the kv batch
+type levelDBBatch struct {
+	db      *leveldb.DB
+	batch   *leveldb.Batch
+	metrics *Metrics
+}
+
+// Set adds the key-value pair to the batch
+func (b *levelDBBatch) Set(p []byte, v []byte) {
+	b.batch.Put(p, v)
+}
+
+// Delete adds the removal of the key to the batch
+func (b *levelDBBatch) Delete(p []byte) {
+	b.batch.Delete(p)
+}
+
+// Write writes the batch to leveldb storage
+func (b *levelDBBatch) Write() error {
+	defer observeLatency(b.metrics.WriteLatency, time.Now())
+
+	return b.db.Write(b.batch, nil)
+}
+
+// levelDBIterator is the leveldb implementation of the kv iterator
+type levelDBIterator struct {
+	iter iterator.Iterator
+}
+
+// Next moves the iterator to the next key-value pair
+func (i *levelDBIterator) Next() bool {
+	return i.iter.Next()
+}
+
+// Key returns the key of the current pair
+func (i *levelDBIterator) Key() []byte {
+	return i.iter.Key()
+}
+
+// Value returns the value of the current pair
+func (i *levelDBIterator) Value() []byte {
+	return i.iter.Value()
+}
+
+// Error returns the error of the iteration, if any
+func (i *levelDBIterator) Error() error {
+	return i.iter.Error()
+}
+
+// Close releases the iterator
+func (i *levelDBIterator) Close() error {
+	err := i.iter.Error()
+	i.iter.Release()
+
+	return err
+}
@@ -1,12 +1,18 @@
 package leveldb
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain/storage"
 	"github.com/hashicorp/go-hclog"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
 )
 
 func newStorage(t *testing.T) (storage.Storage, func()) {
@@ -38,3 +44,139 @@ func newStorage(t *testing.T) (storage.Storage, func()) {
 func TestStorage(t *testing.T) {
 	storage.TestStorage(t, newStorage)
 }
+
+func TestKV(t *testing.T) {
+	storage.TestKV(t, func(t *testing.T) (storage.KV, func()) {
+		t.Helper()
+
+		path, err := ioutil.TempDir("/tmp", "minimal_storage")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		kv, err := NewLevelDBKV(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return kv, func() {
+			if err := kv.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := os.RemoveAll(path); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+}
+
+var (
+	testMetricsOnce sync.Once
+	testMetricsInst *Metrics
+)
+
+// testMetrics returns the metrics registered to the default registry
+func testMetrics() *Metrics {
+	testMetricsOnce.Do(func() {
+		testMetricsInst = GetPrometheusMetrics("test", "chain_id", "100")
+	})
+
+	return testMetricsInst
+}
+
+// gatherMetric returns the values of the metric of the default registry, by the database and the extra label.
+// The values of the histograms are their number of samples
+func gatherMetric(t *testing.T, name, label string) map[string]float64 {
+	t.Helper()
+
+	families, err := stdprometheus.DefaultGatherer.Gather()
+	assert.NoError(t, err)
+
+	values := map[string]float64{}
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+		for _, metric := range family.Metric {
+			key := ""
+
+			for _, pair := range metric.Label {
+				if pair.GetName() == "database" {
+					key = pair.GetValue() + key
+				} else if pair.GetName() == label {
+					key += "/" + pair.GetValue()
+				}
+			}
+
+			values[key] = metric.GetCounter().GetValue() + metric.GetGauge().GetValue() +
+				float64(metric.GetHistogram().GetSampleCount())
+		}
+	}
+
+	return values
+}
+
+func TestLevelDBKV_MetricsAndCompact(t *testing.T) {
+	dir, err := ioutil.TempDir("/tmp", "minimal_storage")
+	assert.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	// the metrics are registered once, the samples of each run are labeled by its database
+	database := filepath.Base(dir)
+
+	kv, err := NewLevelDBKVWithOptions(dir, &Options{
+		WriteBufferSize: 1,
+		BlockCacheSize:  1,
+		OpenFilesLimit:  16,
+		Metrics:         testMetrics(),
+	})
+	assert.NoError(t, err)
+
+	value := make([]byte, 1024)
+
+	// overwrite every key, then delete half of them, so the compaction has entries to discard
+	for round := 0; round < 2; round++ {
+		for i := 0; i < 2000; i++ {
+			assert.NoError(t, kv.Set([]byte(fmt.Sprintf("key-%d", i)), value))
+		}
+	}
+
+	batch := kv.NewBatch()
+	for i := 0; i < 1000; i++ {
+		batch.Delete([]byte(fmt.Sprintf("key-%d", i)))
+	}
+
+	assert.NoError(t, batch.Write())
+
+	compacter, ok := kv.(storage.KVCompacter)
+	assert.True(t, ok)
+	assert.NoError(t, compacter.Compact())
+
+	_, ok, err = kv.Get([]byte("key-0"))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = kv.Get([]byte("key-1000"))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.Equal(t, float64(2), gatherMetric(t, "test_leveldb_read_latency", "")[database])
+	assert.Equal(t, float64(4001), gatherMetric(t, "test_leveldb_write_latency", "")[database])
+
+	// the statistics are reported at the next interval
+	kv.(*levelDBKV).closeStats()
+	kv.(*levelDBKV).startStats(10 * time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		return gatherMetric(t, "test_leveldb_compactions", "kind")[database+"/memtable"] > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Greater(t, gatherMetric(t, "test_leveldb_compaction_write_bytes", "")[database], float64(0))
+	assert.NotEmpty(t, gatherMetric(t, "test_leveldb_level_size", "level"))
+
+	assert.NoError(t, kv.Close())
+}
@@ -0,0 +1,124 @@
+package leveldb
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	prometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics represents the leveldb metrics, labeled by the database of the data dir
+type Metrics struct {
+	// Duration of the reads in seconds
+	ReadLatency metrics.Histogram
+	// Duration of the writes and the batch writes in seconds
+	WriteLatency metrics.Histogram
+	// No.of compactions, labeled by their kind
+	Compactions metrics.Counter
+	// No.of bytes read by the compactions
+	CompactionReadBytes metrics.Counter
+	// No.of bytes written by the compactions
+	CompactionWriteBytes metrics.Counter
+	// Time spent by the writes waiting for the compactions in seconds
+	WriteDelay metrics.Counter
+	// No.of table files kept open
+	OpenFiles metrics.Gauge
+	// Size of the tables of each level in bytes, labeled by the level
+	LevelSize metrics.Gauge
+}
+
+// GetPrometheusMetrics return the leveldb metrics instance
+func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics {
+	labels := []string{}
+
+	for i := 0; i < len(labelsWithValues); i += 2 {
+		labels = append(labels, labelsWithValues[i])
+	}
+
+	labels = append(labels, "database")
+	compactionLabels := append(append([]string{}, labels...), "kind")
+	levelLabels := append(append([]string{}, labels...), "level")
+
+	latencyBuckets := []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1}
+
+	return &Metrics{
+		ReadLatency: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "leveldb",
+			Name:      "read_latency",
+			Help:      "Time spent per read in seconds.",
+			Buckets:   latencyBuckets,
+		}, labels).With(labelsWithValues...),
+		WriteLatency: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "leveldb",
+			Name:      "write_latency",
+			Help:      "Time spent per write or batch write in seconds.",
+			Buckets:   latencyBuckets,
+		}, labels).With(labelsWithValues...),
+		Compactions: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "leveldb",
+			Name:      "compactions",
+			Help:      "Number of compactions, by kind.",
+		}, compactionLabels).With(labelsWithValues...),
+		CompactionReadBytes: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "leveldb",
+			Name:      "compaction_read_bytes",
+			Help:      "Number of bytes read by the compactions.",
+		}, labels).With(labelsWithValues...),
+		CompactionWriteBytes: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "leveldb",
+			Name:      "compaction_write_bytes",
+			Help:      "Number of bytes written by the compactions.",
+		}, labels).With(labelsWithValues...),
+		WriteDelay: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "leveldb",
+			Name:      "write_delay",
+			Help:      "Time spent by the writes waiting for the compactions in seconds.",
+		}, labels).With(labelsWithValues...),
+		OpenFiles: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "leveldb",
+			Name:      "open_files",
+			Help:      "Number of table files kept open.",
+		}, labels).With(labelsWithValues...),
+		LevelSize: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "leveldb",
+			Name:      "level_size",
+			Help:      "Size of the tables of the level in bytes.",
+		}, levelLabels).With(labelsWithValues...),
+	}
+}
+
+// NilMetrics will return the non operational leveldb metrics
+func NilMetrics() *Metrics {
+	return &Metrics{
+		ReadLatency:          discard.NewHistogram(),
+		WriteLatency:         discard.NewHistogram(),
+		Compactions:          discard.NewCounter(),
+		CompactionReadBytes:  discard.NewCounter(),
+		CompactionWriteBytes: discard.NewCounter(),
+		WriteDelay:           discard.NewCounter(),
+		OpenFiles:            discard.NewGauge(),
+		LevelSize:            discard.NewGauge(),
+	}
+}
+
+// withDatabase returns the metrics of the database
+func (m *Metrics) withDatabase(name string) *Metrics {
+	return &Metrics{
+		ReadLatency:          m.ReadLatency.With("database", name),
+		WriteLatency:         m.WriteLatency.With("database", name),
+		Compactions:          m.Compactions.With("database", name),
+		CompactionReadBytes:  m.CompactionReadBytes.With("database", name),
+		CompactionWriteBytes: m.CompactionWriteBytes.With("database", name),
+		WriteDelay:           m.WriteDelay.With("database", name),
+		OpenFiles:            m.OpenFiles.With("database", name),
+		LevelSize:            m.LevelSize.With("database", name),
+	}
+}
@@ -1,6 +1,10 @@
 package memory
 
 import (
+	"bytes"
+	"sort"
+	"sync"
+
 	"github.com/0xPolygon/polygon-edge/blockchain/storage"
 	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/hashicorp/go-hclog"
@@ -8,23 +12,30 @@ import (
 
 // NewMemoryStorage creates the new storage reference with inmemory
 func NewMemoryStorage(logger hclog.Logger) (storage.Storage, error) {
-	db := &memoryKV{map[string][]byte{}}
+	db := &memoryKV{db: map[string][]byte{}}
 
 	return storage.NewKeyValueStorage(logger, db), nil
 }
 
 // memoryKV is an in memory implementation of the kv storage
 type memoryKV struct {
-	db map[string][]byte
+	lock sync.RWMutex
+	db   map[string][]byte
 }
 
 func (m *memoryKV) Set(p []byte, v []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
 	m.db[hex.EncodeToHex(p)] = v
 
 	return nil
 }
 
 func (m *memoryKV) Get(p []byte) ([]byte, bool, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
 	v, ok := m.db[hex.EncodeToHex(p)]
 	if !ok {
 		return nil, false, nil
@@ -33,6 +44,113 @@ func (m *memoryKV) Get(p []byte) ([]byte, bool, error) {
 	return v, true, nil
 }
 
+func (m *memoryKV) Delete(p []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.db, hex.EncodeToHex(p))
+
+	return nil
+}
+
+func (m *memoryKV) NewBatch() storage.KVBatch {
+	return &memoryBatch{kv: m}
+}
+
+// NewIterator iterates over a copy of the key-value pairs with the prefix
+func (m *memoryKV) NewIterator(prefix []byte) storage.KVIterator {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	iter := &memoryIterator{indx: -1}
+
+	for key, value := range m.db {
+		buf, err := hex.DecodeHex(key)
+		if err != nil || !bytes.HasPrefix(buf, prefix) {
+			continue
+		}
+
+		iter.pairs = append(iter.pairs, memoryWrite{string(buf), value})
+	}
+
+	sort.Slice(iter.pairs, func(i, j int) bool {
+		return iter.pairs[i].key < iter.pairs[j].key
+	})
+
+	return iter
+}
+
 func (m *memoryKV) Close() error {
 	return nil
 }
+
+// memoryWrite is a write of the memory batch, a removal if the value is nil
+type memoryWrite struct {
+	key   string
+	value []byte
+}
+
+// memoryBatch is an in memory implementation of the kv batch,
+// the writes are applied under the lock so the readers see all or none of them
+type memoryBatch struct {
+	kv     *memoryKV
+	writes []memoryWrite
+}
+
+func (b *memoryBatch) Set(p []byte, v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+
+	b.writes = append(b.writes, memoryWrite{hex.EncodeToHex(p), v})
+}
+
+func (b *memoryBatch) Delete(p []byte) {
+	b.writes = append(b.writes, memoryWrite{hex.EncodeToHex(p), nil})
+}
+
+func (b *memoryBatch) Write() error {
+	b.kv.lock.Lock()
+	defer b.kv.lock.Unlock()
+
+	for _, write := range b.writes {
+		if write.value == nil {
+			delete(b.kv.db, write.key)
+		} else {
+			b.kv.db[write.key] = write.value
+		}
+	}
+
+	b.writes = nil
+
+	return nil
+}
+
+// memoryIterator is an in memory implementation of the kv iterator,
+// the keys of the pairs are the raw keys
+type memoryIterator struct {
+	pairs []memoryWrite
+	indx  int
+}
+
+func (i *memoryIterator) Next() bool {
+	i.indx++
+
+	return i.indx < len(i.pairs)
+}
+
+func (i *memoryIterator) Key() []byte {
+	return []byte(i.pairs[i.indx].key)
+}
+
+func (i *memoryIterator) Value() []byte {
+	return i.pairs[i.indx].value
+}
+
+func (i *memoryIterator) Error() error {
+	return nil
+}
+
+func (i *memoryIterator) Close() error {
+	return nil
+}
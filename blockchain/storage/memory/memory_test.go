@@ -18,3 +18,11 @@ func TestStorage(t *testing.T) {
 	}
 	storage.TestStorage(t, f)
 }
+
+func TestKV(t *testing.T) {
+	storage.TestKV(t, func(t *testing.T) (storage.KV, func()) {
+		t.Helper()
+
+		return &memoryKV{db: map[string][]byte{}}, func() {}
+	})
+}
@@ -0,0 +1,222 @@
+package pebble
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/cockroachdb/pebble"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// cacheSize is the size of the block cache shared by the tables
+	cacheSize = 64 << 20
+
+	// memTableSize is the size of a memtable, the writes stall if all of them are full
+	memTableSize = 32 << 20
+)
+
+// ErrClosed is returned by the operations on a closed pebble storage
+var ErrClosed = errors.New("pebble: closed")
+
+// Factory creates a pebble storage
+func Factory(config map[string]interface{}, logger hclog.Logger) (storage.Storage, error) {
+	path, ok := config["path"]
+	if !ok {
+		return nil, fmt.Errorf("path not found")
+	}
+
+	pathStr, ok := path.(string)
+	if !ok {
+		return nil, fmt.Errorf("path is not a string")
+	}
+
+	return NewPebbleStorage(pathStr, logger)
+}
+
+// NewPebbleStorage creates the new storage reference with pebble
+func NewPebbleStorage(path string, logger hclog.Logger) (storage.Storage, error) {
+	kv, err := NewPebbleKV(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.NewKeyValueStorage(logger.Named("pebble"), kv), nil
+}
+
+// NewPebbleKV opens the pebble kv storage at the path, it is created if needed
+func NewPebbleKV(path string) (storage.KV, error) {
+	cache := pebble.NewCache(cacheSize)
+	defer cache.Unref()
+
+	db, err := pebble.Open(path, &pebble.Options{
+		Cache:        cache,
+		MemTableSize: memTableSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pebbleKV{db: db}, nil
+}
+
+// pebbleKV is the pebble implementation of the kv storage.
+// The writes are not synced, like the leveldb ones
+type pebbleKV struct {
+	db     *pebble.DB
+	closed uint32
+}
+
+func (p *pebbleKV) isClosed() bool {
+	return atomic.LoadUint32(&p.closed) == 1
+}
+
+// Set sets the key-value pair in pebble storage
+func (p *pebbleKV) Set(k []byte, v []byte) error {
+	if p.isClosed() {
+		return ErrClosed
+	}
+
+	return p.db.Set(k, v, pebble.NoSync)
+}
+
+// Get retrieves the key-value pair in pebble storage
+func (p *pebbleKV) Get(k []byte) ([]byte, bool, error) {
+	if p.isClosed() {
+		return nil, false, ErrClosed
+	}
+
+	data, closer, err := p.db.Get(k)
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			return nil, false, nil
+		}
+
+		return nil, false, err
+	}
+
+	// the data is only valid until the closer is closed
+	value := make([]byte, len(data))
+	copy(value, data)
+
+	if err := closer.Close(); err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+// Delete removes the key-value pair from pebble storage
+func (p *pebbleKV) Delete(k []byte) error {
+	if p.isClosed() {
+		return ErrClosed
+	}
+
+	return p.db.Delete(k, pebble.NoSync)
+}
+
+// NewBatch creates a pebble batch, written atomically
+func (p *pebbleKV) NewBatch() storage.KVBatch {
+	return &pebbleBatch{kv: p, batch: p.db.NewBatch()}
+}
+
+// NewIterator creates an iterator over the key-value pairs with the prefix
+func (p *pebbleKV) NewIterator(prefix []byte) storage.KVIterator {
+	return &pebbleIterator{
+		iter: p.db.NewIter(&pebble.IterOptions{
+			LowerBound: prefix,
+			UpperBound: prefixUpperBound(prefix),
+		}),
+	}
+}
+
+// Close closes the pebble storage instance, the iterators have to be closed before
+func (p *pebbleKV) Close() error {
+	if !atomic.CompareAndSwapUint32(&p.closed, 0, 1) {
+		return ErrClosed
+	}
+
+	return p.db.Close()
+}
+
+// prefixUpperBound returns the smallest key greater than all the keys with the prefix,
+// nil if there is none
+func prefixUpperBound(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] < 0xff {
+			bound := make([]byte, i+1)
+			copy(bound, prefix)
+			bound[i]++
+
+			return bound
+		}
+	}
+
+	return nil
+}
+
+// pebbleBatch is the pebble implementation of the kv batch
+type pebbleBatch struct {
+	kv    *pebbleKV
+	batch *pebble.Batch
+}
+
+// Set adds the key-value pair to the batch
+func (b *pebbleBatch) Set(k []byte, v []byte) {
+	_ = b.batch.Set(k, v, nil)
+}
+
+// Delete adds the removal of the key to the batch
+func (b *pebbleBatch) Delete(k []byte) {
+	_ = b.batch.Delete(k, nil)
+}
+
+// Write writes the batch to pebble storage, the batch can't be used afterwards
+func (b *pebbleBatch) Write() error {
+	defer b.batch.Close()
+
+	if b.kv.isClosed() {
+		return ErrClosed
+	}
+
+	return b.batch.Commit(pebble.NoSync)
+}
+
+// pebbleIterator is the pebble implementation of the kv iterator
+type pebbleIterator struct {
+	iter    *pebble.Iterator
+	started bool
+}
+
+// Next moves the iterator to the next key-value pair
+func (i *pebbleIterator) Next() bool {
+	if !i.started {
+		i.started = true
+
+		return i.iter.First()
+	}
+
+	return i.iter.Next()
+}
+
+// Key returns the key of the current pair
+func (i *pebbleIterator) Key() []byte {
+	return i.iter.Key()
+}
+
+// Value returns the value of the current pair
+func (i *pebbleIterator) Value() []byte {
+	return i.iter.Value()
+}
+
+// Error returns the error of the iteration, if any
+func (i *pebbleIterator) Error() error {
+	return i.iter.Error()
+}
+
+// Close releases the iterator
+func (i *pebbleIterator) Close() error {
+	return i.iter.Close()
+}
@@ -0,0 +1,113 @@
+package pebble
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStorage(t *testing.T) (storage.Storage, func()) {
+	t.Helper()
+
+	path, err := ioutil.TempDir("/tmp", "minimal_storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewPebbleStorage(path, hclog.NewNullLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closeFn := func() {
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.RemoveAll(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return s, closeFn
+}
+
+func newKV(t *testing.T) (storage.KV, func()) {
+	t.Helper()
+
+	path, err := ioutil.TempDir("/tmp", "minimal_storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kv, err := NewPebbleKV(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closeFn := func() {
+		if err := kv.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.RemoveAll(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return kv, closeFn
+}
+
+func TestStorage(t *testing.T) {
+	storage.TestStorage(t, newStorage)
+}
+
+func TestKV(t *testing.T) {
+	storage.TestKV(t, newKV)
+}
+
+func TestClose(t *testing.T) {
+	path, err := ioutil.TempDir("/tmp", "minimal_storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(path)
+
+	kv, err := NewPebbleKV(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, kv.Set([]byte("a"), []byte{0x1}))
+
+	value, _, err := kv.Get([]byte("a"))
+	assert.NoError(t, err)
+
+	batch := kv.NewBatch()
+	batch.Set([]byte("b"), []byte{0x2})
+
+	assert.NoError(t, kv.Close())
+
+	// the values read are still valid once the storage is closed
+	assert.Equal(t, []byte{0x1}, value)
+
+	// the operations fail instead of panicking
+	assert.ErrorIs(t, kv.Set([]byte("a"), []byte{0x1}), ErrClosed)
+	assert.ErrorIs(t, batch.Write(), ErrClosed)
+	assert.ErrorIs(t, kv.Close(), ErrClosed)
+
+	_, _, err = kv.Get([]byte("a"))
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestPrefixUpperBound(t *testing.T) {
+	assert.Equal(t, []byte("b"), prefixUpperBound([]byte("a")))
+	assert.Equal(t, []byte{0x1, 0x3}, prefixUpperBound([]byte{0x1, 0x2, 0xff}))
+	assert.Nil(t, prefixUpperBound([]byte{0xff, 0xff}))
+	assert.Nil(t, prefixUpperBound(nil))
+}
@@ -9,38 +9,80 @@ import (
 
 // Storage is a generic blockchain storage
 type Storage interface {
+	Writer
+
 	ReadCanonicalHash(n uint64) (types.Hash, bool)
-	WriteCanonicalHash(n uint64, hash types.Hash) error
 
 	ReadHeadHash() (types.Hash, bool)
 	ReadHeadNumber() (uint64, bool)
+
+	ReadFinalizedHash() (types.Hash, bool)
+
+	ReadForks() ([]types.Hash, error)
+
+	ReadTotalDifficulty(hash types.Hash) (*big.Int, bool)
+
+	ReadHeader(hash types.Hash) (*types.Header, error)
+
+	ReadBody(hash types.Hash) (*types.Body, error)
+
+	ReadSnapshot(hash types.Hash) ([]byte, bool)
+
+	ReadReceipts(hash types.Hash) ([]*types.Receipt, error)
+
+	ReadInternalTxs(hash types.Hash) ([]*types.InternalTransaction, error)
+
+	ReadTxLookup(hash types.Hash) (types.Hash, uint64, bool)
+
+	ReadTxLookupVersion() (uint64, bool)
+
+	// NewBatch creates a batch of writes to the storage
+	NewBatch() Batch
+
+	Close() error
+}
+
+// Writer writes the blockchain objects to the storage
+type Writer interface {
+	WriteCanonicalHash(n uint64, hash types.Hash) error
+	DeleteCanonicalHash(n uint64) error
+
 	WriteHeadHash(h types.Hash) error
 	WriteHeadNumber(uint64) error
 
+	WriteFinalizedHash(h types.Hash) error
+
 	WriteForks(forks []types.Hash) error
-	ReadForks() ([]types.Hash, error)
 
 	WriteTotalDifficulty(hash types.Hash, diff *big.Int) error
-	ReadTotalDifficulty(hash types.Hash) (*big.Int, bool)
 
 	WriteHeader(h *types.Header) error
-	ReadHeader(hash types.Hash) (*types.Header, error)
 
 	WriteCanonicalHeader(h *types.Header, diff *big.Int) error
 
+	DeleteBlock(hash types.Hash) error
+
 	WriteBody(hash types.Hash, body *types.Body) error
-	ReadBody(hash types.Hash) (*types.Body, error)
 
 	WriteSnapshot(hash types.Hash, blob []byte) error
-	ReadSnapshot(hash types.Hash) ([]byte, bool)
 
 	WriteReceipts(hash types.Hash, receipts []*types.Receipt) error
-	ReadReceipts(hash types.Hash) ([]*types.Receipt, error)
 
-	WriteTxLookup(hash types.Hash, blockHash types.Hash) error
-	ReadTxLookup(hash types.Hash) (types.Hash, bool)
+	WriteInternalTxs(hash types.Hash, internalTxs []*types.InternalTransaction) error
 
-	Close() error
+	WriteTxLookup(hash types.Hash, blockHash types.Hash, index uint64) error
+	DeleteTxLookup(hash types.Hash) error
+
+	WriteTxLookupVersion(version uint64) error
+}
+
+// Batch is a set of writes to the storage, they are not visible
+// until Write commits all of them atomically, in the order of the calls
+type Batch interface {
+	Writer
+
+	// Write commits the writes of the batch
+	Write() error
 }
 
 // Factory is a factory method to create a blockchain storage
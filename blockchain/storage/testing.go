@@ -49,6 +49,18 @@ func TestStorage(t *testing.T, m MockStorage) {
 	t.Run("", func(t *testing.T) {
 		testReceipts(t, m)
 	})
+	t.Run("", func(t *testing.T) {
+		testInternalTxs(t, m)
+	})
+	t.Run("", func(t *testing.T) {
+		testTxLookup(t, m)
+	})
+	t.Run("", func(t *testing.T) {
+		testBatch(t, m)
+	})
+	t.Run("", func(t *testing.T) {
+		testDeleteBlock(t, m)
+	})
 }
 
 func testCanonicalChain(t *testing.T, m MockStorage) {
@@ -194,6 +206,15 @@ func testHead(t *testing.T, m MockStorage) {
 			t.Fatal("bad")
 		}
 	}
+
+	_, ok := s.ReadFinalizedHash()
+	assert.False(t, ok)
+
+	assert.NoError(t, s.WriteFinalizedHash(hash1))
+
+	finalized, ok := s.ReadFinalizedHash()
+	assert.True(t, ok)
+	assert.Equal(t, hash1, finalized)
 }
 
 func testForks(t *testing.T, m MockStorage) {
@@ -451,3 +472,235 @@ func testWriteCanonicalHeader(t *testing.T, m MockStorage) {
 		t.Fatal("canonical hash not correct")
 	}
 }
+
+func testInternalTxs(t *testing.T, m MockStorage) {
+	t.Helper()
+
+	s, closeFn := m(t)
+	defer closeFn()
+
+	_, err := s.ReadInternalTxs(hash1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	internalTxs := []*types.InternalTransaction{
+		{
+			TxHash: hash2,
+			From:   addr1,
+			To:     addr2,
+			Value:  big.NewInt(10),
+			Depth:  1,
+		},
+		{
+			TxHash: hash2,
+			From:   addr2,
+			To:     addr1,
+			Value:  big.NewInt(0),
+			Depth:  2,
+		},
+	}
+
+	assert.NoError(t, s.WriteInternalTxs(hash1, internalTxs))
+
+	found, err := s.ReadInternalTxs(hash1)
+	assert.NoError(t, err)
+	assert.Equal(t, internalTxs, found)
+
+	// the blocks without internal transactions are indexed too
+	assert.NoError(t, s.WriteInternalTxs(hash2, []*types.InternalTransaction{}))
+
+	found, err = s.ReadInternalTxs(hash2)
+	assert.NoError(t, err)
+	assert.Len(t, found, 0)
+}
+
+func testTxLookup(t *testing.T, m MockStorage) {
+	t.Helper()
+
+	s, closeFn := m(t)
+	defer closeFn()
+
+	_, _, ok := s.ReadTxLookup(hash1)
+	assert.False(t, ok)
+
+	assert.NoError(t, s.WriteTxLookup(hash1, hash2, 5))
+
+	blockHash, index, ok := s.ReadTxLookup(hash1)
+	assert.True(t, ok)
+	assert.Equal(t, hash2, blockHash)
+	assert.Equal(t, uint64(5), index)
+
+	assert.NoError(t, s.DeleteTxLookup(hash1))
+
+	_, _, ok = s.ReadTxLookup(hash1)
+	assert.False(t, ok)
+
+	_, ok = s.ReadTxLookupVersion()
+	assert.False(t, ok)
+
+	assert.NoError(t, s.WriteTxLookupVersion(1))
+
+	version, ok := s.ReadTxLookupVersion()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), version)
+}
+
+func testBatch(t *testing.T, m MockStorage) {
+	t.Helper()
+
+	s, closeFn := m(t)
+	defer closeFn()
+
+	h := &types.Header{
+		Number:    10,
+		ExtraData: []byte{0x1},
+	}
+	h.ComputeHash()
+
+	assert.NoError(t, s.WriteTxLookup(hash1, hash2, 1))
+
+	batch := s.NewBatch()
+	assert.NoError(t, batch.WriteCanonicalHeader(h, big.NewInt(10)))
+	assert.NoError(t, batch.WriteBody(h.Hash, &types.Body{}))
+	assert.NoError(t, batch.DeleteTxLookup(hash1))
+
+	// the writes are applied in order
+	assert.NoError(t, batch.WriteTxLookup(hash2, h.Hash, 0))
+	assert.NoError(t, batch.DeleteTxLookup(hash2))
+	assert.NoError(t, batch.DeleteCanonicalHash(1))
+	assert.NoError(t, batch.WriteCanonicalHash(1, hash1))
+
+	// nothing is visible before the batch is written
+	_, ok := s.ReadHeadHash()
+	assert.False(t, ok)
+
+	_, err := s.ReadHeader(h.Hash)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, _, ok = s.ReadTxLookup(hash1)
+	assert.True(t, ok)
+
+	assert.NoError(t, batch.Write())
+
+	headHash, ok := s.ReadHeadHash()
+	assert.True(t, ok)
+	assert.Equal(t, h.Hash, headHash)
+
+	diff, ok := s.ReadTotalDifficulty(h.Hash)
+	assert.True(t, ok)
+	assert.Equal(t, big.NewInt(10), diff)
+
+	_, err = s.ReadBody(h.Hash)
+	assert.NoError(t, err)
+
+	_, _, ok = s.ReadTxLookup(hash1)
+	assert.False(t, ok)
+
+	_, _, ok = s.ReadTxLookup(hash2)
+	assert.False(t, ok)
+
+	canonicalHash, ok := s.ReadCanonicalHash(1)
+	assert.True(t, ok)
+	assert.Equal(t, hash1, canonicalHash)
+}
+
+func testDeleteBlock(t *testing.T, m MockStorage) {
+	t.Helper()
+
+	s, closeFn := m(t)
+	defer closeFn()
+
+	h := &types.Header{
+		Number:    10,
+		ExtraData: []byte{0x1},
+	}
+	h.ComputeHash()
+
+	assert.NoError(t, s.WriteCanonicalHeader(h, big.NewInt(10)))
+	assert.NoError(t, s.WriteBody(h.Hash, &types.Body{}))
+	assert.NoError(t, s.WriteReceipts(h.Hash, []*types.Receipt{}))
+	assert.NoError(t, s.WriteInternalTxs(h.Hash, []*types.InternalTransaction{}))
+
+	assert.NoError(t, s.DeleteBlock(h.Hash))
+
+	_, err := s.ReadHeader(h.Hash)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = s.ReadBody(h.Hash)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = s.ReadReceipts(h.Hash)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = s.ReadInternalTxs(h.Hash)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, ok := s.ReadTotalDifficulty(h.Hash)
+	assert.False(t, ok)
+
+	// the canonical hash and the head are written apart
+	canonicalHash, ok := s.ReadCanonicalHash(h.Number)
+	assert.True(t, ok)
+	assert.Equal(t, h.Hash, canonicalHash)
+}
+
+type MockKV func(t *testing.T) (KV, func())
+
+// TestKV tests the key value pairs, the batches and the iterators of a kv storage
+func TestKV(t *testing.T, m MockKV) {
+	t.Helper()
+
+	kv, closeFn := m(t)
+	defer closeFn()
+
+	_, ok, err := kv.Get([]byte("a"))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, kv.Set([]byte("a"), []byte{0x1}))
+
+	value, ok, err := kv.Get([]byte("a"))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte{0x1}, value)
+
+	assert.NoError(t, kv.Delete([]byte("a")))
+
+	_, ok, err = kv.Get([]byte("a"))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	batch := kv.NewBatch()
+	batch.Set([]byte("b2"), []byte{0x2})
+	batch.Set([]byte("b1"), []byte{0x1})
+	batch.Set([]byte("c"), []byte{0x3})
+	batch.Set([]byte("b\xff"), []byte{0x4})
+	batch.Set([]byte("b3"), []byte{0x5})
+	batch.Delete([]byte("b3"))
+
+	_, ok, err = kv.Get([]byte("b1"))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, batch.Write())
+
+	// the pairs of the prefix are iterated in the key order
+	collect := func(prefix []byte) []string {
+		iter := kv.NewIterator(prefix)
+		defer func() {
+			assert.NoError(t, iter.Close())
+		}()
+
+		pairs := []string{}
+		for iter.Next() {
+			pairs = append(pairs, fmt.Sprintf("%s=%x", iter.Key(), iter.Value()))
+		}
+
+		assert.NoError(t, iter.Error())
+
+		return pairs
+	}
+
+	assert.Equal(t, []string{"b1=01", "b2=02", "b\xff=04"}, collect([]byte("b")))
+	assert.Equal(t, []string{"b1=01", "b2=02", "b\xff=04", "c=03"}, collect(nil))
+	assert.Equal(t, []string{}, collect([]byte("d")))
+}
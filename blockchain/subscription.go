@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
 )
 
 type void struct{}
@@ -102,6 +103,17 @@ const (
 	EventFork                   // Chain fork event
 )
 
+// The sources of the blocks of the events
+const (
+	SourceSealer  = "sealer"  // The block is sealed by the consensus of the node
+	SourceSyncer  = "syncer"  // The block is synced from a peer
+	SourceArchive = "archive" // The block is restored or imported from an archive
+)
+
+// eventBufferSize is the no.of events buffered for each subscriber of SubscribeEvents,
+// the subscriber falling behind by more events is a slow consumer, and is unsubscribed
+const eventBufferSize = 1024
+
 // Event is the blockchain event that gets passed to the listeners
 type Event struct {
 	// Old chain (removed headers) if there was a reorg
@@ -116,8 +128,8 @@ type Event struct {
 	// Type is the type of event
 	Type EventType
 
-	// Source is the source that generated the blocks for the event
-	// right now it can be either the Sealer or the Syncer. TODO
+	// Source is the source that generated the blocks for the event,
+	// one of SourceSealer, SourceSyncer or SourceArchive
 	Source string
 }
 
@@ -155,11 +167,20 @@ func (e *Event) AddOldHeader(oldHeader *types.Header) {
 	e.OldChain = append(e.OldChain, header)
 }
 
-// SubscribeEvents returns a blockchain event subscription
-func (b *Blockchain) SubscribeEvents() Subscription {
+// SubscribeEventStream returns a blockchain event subscription.
+// The stream keeps every event, it is read by the components that must process all the blocks
+func (b *Blockchain) SubscribeEventStream() Subscription {
 	return b.stream.subscribe()
 }
 
+// SubscribeEvents returns the channel of the blockchain events, and the function to unsubscribe.
+// The events are shared by the subscribers and must not be modified. The channel is buffered,
+// the subscriber falling behind by more than eventBufferSize events is unsubscribed,
+// and its channel is closed, as it is once unsubscribed or once the blockchain is closed
+func (b *Blockchain) SubscribeEvents() (<-chan *Event, func()) {
+	return b.stream.subscribeCh()
+}
+
 // eventElem contains the event, as well as the next list event
 type eventElem struct {
 	event *Event
@@ -174,6 +195,11 @@ type eventStream struct {
 
 	// channel to notify updates
 	updateCh []chan void
+
+	// channels of the subscribers of SubscribeEvents
+	subscribers map[chan *Event]void
+
+	logger hclog.Logger
 }
 
 // subscribe Creates a new blockchain event subscription
@@ -188,6 +214,47 @@ func (e *eventStream) subscribe() *subscription {
 	return s
 }
 
+// subscribeCh adds a subscriber receiving the next events on a buffered channel
+func (e *eventStream) subscribeCh() (<-chan *Event, func()) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	ch := make(chan *Event, eventBufferSize)
+
+	if e.subscribers == nil {
+		e.subscribers = map[chan *Event]void{}
+	}
+
+	e.subscribers[ch] = void{}
+
+	return ch, func() {
+		e.lock.Lock()
+		defer e.lock.Unlock()
+
+		e.unsubscribe(ch)
+	}
+}
+
+// unsubscribe removes the subscriber and closes its channel, assumes the lock is held
+func (e *eventStream) unsubscribe(ch chan *Event) {
+	if _, ok := e.subscribers[ch]; !ok {
+		return
+	}
+
+	delete(e.subscribers, ch)
+	close(ch)
+}
+
+// close unsubscribes all the subscribers
+func (e *eventStream) close() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for ch := range e.subscribers {
+		e.unsubscribe(ch)
+	}
+}
+
 // Head returns the event list head
 func (e *eventStream) Head() (*eventElem, chan void) {
 	e.lock.Lock()
@@ -228,5 +295,15 @@ func (e *eventStream) push(event *Event) {
 		}
 	}
 
+	// The subscribers are never waited for, the slow ones are unsubscribed
+	for ch := range e.subscribers {
+		select {
+		case ch <- event:
+		default:
+			e.logger.Warn("unsubscribed the slow consumer of the events", "buffered", len(ch))
+			e.unsubscribe(ch)
+		}
+	}
+
 	e.lock.Unlock()
 }
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestSubscriptionLinear(t *testing.T) {
@@ -74,3 +76,50 @@ func TestSubscriptionSlowConsumer(t *testing.T) {
 		}
 	}
 }
+
+func TestSubscribeEvents(t *testing.T) {
+	e := &eventStream{logger: hclog.NewNullLogger()}
+
+	eventCh, cancel := e.subscribeCh()
+	slowCh, _ := e.subscribeCh()
+
+	evnt := &Event{Source: SourceSealer}
+	evnt.AddNewHeader(&types.Header{Number: 1})
+	e.push(evnt)
+
+	assert.Equal(t, evnt, <-eventCh)
+
+	// the slow consumer is unsubscribed once its buffer is full
+	for i := 0; i < eventBufferSize; i++ {
+		e.push(evnt)
+
+		assert.Equal(t, evnt, <-eventCh)
+	}
+
+	assert.Len(t, slowCh, eventBufferSize)
+
+	for range slowCh {
+	}
+
+	// the channel is closed once unsubscribed, twice is a no-op
+	cancel()
+	cancel()
+
+	_, ok := <-eventCh
+	assert.False(t, ok)
+	assert.Empty(t, e.subscribers)
+}
+
+func TestSubscribeEvents_Close(t *testing.T) {
+	e := &eventStream{logger: hclog.NewNullLogger()}
+
+	eventCh, cancel := e.subscribeCh()
+
+	// the subscribers are unsubscribed once the stream is closed
+	e.close()
+
+	_, ok := <-eventCh
+	assert.False(t, ok)
+
+	cancel()
+}
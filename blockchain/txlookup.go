@@ -0,0 +1,165 @@
+package blockchain
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// txLookupVersion is the format version of the transaction lookups.
+	// Version 1 keeps the index of the transaction in the block, along with the block hash
+	txLookupVersion uint64 = 1
+
+	// txLookupMigrationLogInterval is the number of blocks between the migration progress logs
+	txLookupMigrationLogInterval uint64 = 10000
+)
+
+// writeTxLookups writes the lookups of the transactions of a canonical block
+func (b *Blockchain) writeTxLookups(batch storage.Writer, hash types.Hash, txs []*types.Transaction) error {
+	for indx, txn := range txs {
+		if err := batch.WriteTxLookup(txn.Hash, hash, uint64(indx)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadTxLookup returns the hash of the canonical block including the transaction,
+// and the index of the transaction in the block
+func (b *Blockchain) ReadTxLookup(hash types.Hash) (types.Hash, uint64, bool) {
+	return b.db.ReadTxLookup(hash)
+}
+
+// reorgTxLookups points the lookups at the blocks of the new canonical chain, but the new head
+// which is in the same batch. The lookups of the transactions only included in the old chain
+// are removed, the caller writes the lookups of the new head after them
+func (b *Blockchain) reorgTxLookups(batch storage.Writer, oldChain, newChain []*types.Header) error {
+	included := map[types.Hash]struct{}{}
+
+	for _, header := range newChain[1:] {
+		body, ok := b.readBody(header.Hash)
+		if !ok {
+			// only the header has been written
+			continue
+		}
+
+		if err := b.writeTxLookups(batch, header.Hash, body.Transactions); err != nil {
+			return err
+		}
+
+		for _, txn := range body.Transactions {
+			included[txn.Hash] = struct{}{}
+		}
+	}
+
+	for _, header := range oldChain {
+		body, ok := b.readBody(header.Hash)
+		if !ok {
+			continue
+		}
+
+		for _, txn := range body.Transactions {
+			if _, ok := included[txn.Hash]; ok {
+				continue
+			}
+
+			if err := batch.DeleteTxLookup(txn.Hash); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// chainsToAncestor returns the headers of both chains, down to their common ancestor (excluded)
+func (b *Blockchain) chainsToAncestor(oldHead, newHead *types.Header) (
+	oldChain, newChain []*types.Header,
+	err error,
+) {
+	parent := func(header *types.Header) (*types.Header, error) {
+		parentHeader, ok := b.readHeader(header.ParentHash)
+		if !ok {
+			return nil, fmt.Errorf("header '%s' not found", header.ParentHash.String())
+		}
+
+		return parentHeader, nil
+	}
+
+	oldHeader, newHeader := oldHead, newHead
+
+	for oldHeader.Number > newHeader.Number {
+		oldChain = append(oldChain, oldHeader)
+
+		if oldHeader, err = parent(oldHeader); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for newHeader.Number > oldHeader.Number {
+		newChain = append(newChain, newHeader)
+
+		if newHeader, err = parent(newHeader); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for oldHeader.Hash != newHeader.Hash {
+		oldChain = append(oldChain, oldHeader)
+		newChain = append(newChain, newHeader)
+
+		if oldHeader, err = parent(oldHeader); err != nil {
+			return nil, nil, err
+		}
+
+		if newHeader, err = parent(newHeader); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return oldChain, newChain, nil
+}
+
+// migrateTxLookups rewrites the lookups of the canonical chain in the current format,
+// once for the storages written before the lookups kept the transaction index
+func (b *Blockchain) migrateTxLookups() error {
+	if version, ok := b.db.ReadTxLookupVersion(); ok && version >= txLookupVersion {
+		return nil
+	}
+
+	head := b.Header().Number
+
+	b.logger.Info("migrating the transaction lookups", "blocks", head)
+
+	for number := uint64(1); number <= head; number++ {
+		hash, ok := b.db.ReadCanonicalHash(number)
+		if !ok {
+			return fmt.Errorf("canonical hash of block %d not found", number)
+		}
+
+		body, ok := b.readBody(hash)
+		if !ok {
+			// only the header has been written
+			continue
+		}
+
+		if err := b.writeTxLookups(b.db, hash, body.Transactions); err != nil {
+			return err
+		}
+
+		if number%txLookupMigrationLogInterval == 0 {
+			b.logger.Info("migrating the transaction lookups", "block", number, "head", head)
+		}
+	}
+
+	if err := b.db.WriteTxLookupVersion(txLookupVersion); err != nil {
+		return err
+	}
+
+	b.logger.Info("migrated the transaction lookups", "blocks", head)
+
+	return nil
+}
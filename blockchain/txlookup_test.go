@@ -0,0 +1,98 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLookupTestTx(nonce uint64) *types.Transaction {
+	return (&types.Transaction{
+		Nonce: nonce,
+		Value: big.NewInt(10),
+		V:     big.NewInt(1),
+	}).ComputeHash()
+}
+
+func TestTxLookup_Reorg(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+
+	// the canonical chain up to block 3, and a longer fork from block 1
+	headers := NewTestHeaderChain(4)
+	forkHeaders := NewTestHeaderFromChainWithSeed(headers[:2], 3, 10)
+
+	shared, oldOnly, newOnly := newLookupTestTx(1), newLookupTestTx(2), newLookupTestTx(3)
+
+	assert.NoError(t, b.db.WriteBody(headers[3].Hash, &types.Body{
+		Transactions: []*types.Transaction{shared, oldOnly},
+	}))
+	assert.NoError(t, b.db.WriteBody(forkHeaders[3].Hash, &types.Body{
+		Transactions: []*types.Transaction{newOnly, shared},
+	}))
+
+	_, err := b.advanceHead(headers[0])
+	assert.NoError(t, err)
+	assert.NoError(t, b.WriteHeaders(headers[1:]))
+	assert.NoError(t, b.writeTxLookups(b.db, headers[3].Hash, []*types.Transaction{shared, oldOnly}))
+
+	blockHash, indx, ok := b.ReadTxLookup(oldOnly.Hash)
+	assert.True(t, ok)
+	assert.Equal(t, headers[3].Hash, blockHash)
+	assert.Equal(t, uint64(1), indx)
+
+	// the fork becomes the canonical chain
+	assert.NoError(t, b.WriteHeaders(forkHeaders[2:]))
+	assert.Equal(t, forkHeaders[4].Hash, b.Header().Hash)
+
+	blockHash, indx, ok = b.ReadTxLookup(shared.Hash)
+	assert.True(t, ok)
+	assert.Equal(t, forkHeaders[3].Hash, blockHash)
+	assert.Equal(t, uint64(1), indx)
+
+	blockHash, indx, ok = b.ReadTxLookup(newOnly.Hash)
+	assert.True(t, ok)
+	assert.Equal(t, forkHeaders[3].Hash, blockHash)
+	assert.Equal(t, uint64(0), indx)
+
+	// the transactions only included in the old chain are not found anymore
+	_, _, ok = b.ReadTxLookup(oldOnly.Hash)
+	assert.False(t, ok)
+}
+
+func TestTxLookup_Migration(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+
+	version, ok := b.db.ReadTxLookupVersion()
+	assert.True(t, ok)
+	assert.Equal(t, txLookupVersion, version)
+
+	headers := NewTestHeaderChain(3)
+	txs := []*types.Transaction{newLookupTestTx(1), newLookupTestTx(2)}
+
+	assert.NoError(t, b.db.WriteBody(headers[2].Hash, &types.Body{Transactions: txs}))
+
+	_, err := b.advanceHead(headers[0])
+	assert.NoError(t, err)
+	assert.NoError(t, b.WriteHeaders(headers[1:]))
+
+	// nothing is migrated once the lookups are in the current format
+	assert.NoError(t, b.migrateTxLookups())
+
+	_, _, ok = b.ReadTxLookup(txs[1].Hash)
+	assert.False(t, ok)
+
+	// a storage written before the lookups kept the transaction index
+	assert.NoError(t, b.db.WriteTxLookupVersion(0))
+	assert.NoError(t, b.migrateTxLookups())
+
+	blockHash, indx, ok := b.ReadTxLookup(txs[1].Hash)
+	assert.True(t, ok)
+	assert.Equal(t, headers[2].Hash, blockHash)
+	assert.Equal(t, uint64(1), indx)
+
+	version, ok = b.db.ReadTxLookupVersion()
+	assert.True(t, ok)
+	assert.Equal(t, txLookupVersion, version)
+}
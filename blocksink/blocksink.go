@@ -0,0 +1,56 @@
+package blocksink
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+// SinkType is the type of a block sink
+type SinkType string
+
+const (
+	// Webhook sinks post the JSON of the blocks to an HTTP endpoint
+	Webhook SinkType = "webhook"
+
+	// GRPC sinks stream the blocks to a gRPC server implementing the BlockSink service
+	GRPC SinkType = "grpc"
+)
+
+// Sink is a destination the finalized blocks are pushed to, in the order of the chain
+type Sink interface {
+	// Deliver pushes the block, it is acknowledged once Deliver returns without an error.
+	// The delivery is at least once, the blocks may be pushed again after a failure or a restart
+	Deliver(ctx context.Context, block *types.Block) error
+
+	// Close releases the resources of the sink
+	Close() error
+}
+
+// Config is the configuration of a sink
+type Config struct {
+	// Name identifies the sink, the last block it acknowledged is persisted under it
+	Name string
+
+	Type SinkType
+
+	// URL is the endpoint the webhook sinks post the blocks to
+	URL string
+
+	// Headers are the extra HTTP headers of the webhook requests
+	Headers map[string]string
+
+	// Endpoint is the address of the gRPC server of the grpc sinks
+	Endpoint string
+
+	// TLSCAFile is the CA certificate of the gRPC server, the connection is plaintext if not set
+	TLSCAFile string
+
+	// Timeout is the time a delivery can take before it is retried, the sink default if zero
+	Timeout time.Duration
+}
+
+// Factory is the factory method for the sinks of a type
+type Factory func(config *Config, logger hclog.Logger) (Sink, error)
@@ -0,0 +1,276 @@
+package blocksink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	defaultMinRetryBackoff = time.Second
+	defaultMaxRetryBackoff = time.Minute
+)
+
+var (
+	ErrInvalidSinkName   = errors.New("invalid block sink name")
+	ErrDuplicateSinkName = errors.New("duplicate block sink name")
+	ErrUnknownSinkType   = errors.New("unknown block sink type")
+)
+
+// sinkNameRegex restricts the names to the ones usable as file names
+var sinkNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// blockchainStore is the blockchain the bus reads the finalized blocks from
+type blockchainStore interface {
+	// Header returns the head of the chain
+	Header() *types.Header
+
+	// GetBlockByNumber returns the canonical block by its number
+	GetBlockByNumber(number uint64, full bool) (*types.Block, bool)
+
+	// SubscribeEventStream subscribes to the events of the blockchain
+	SubscribeEventStream() blockchain.Subscription
+}
+
+// Bus pushes the blocks of the chain to the sinks. Every sink is delivered the blocks
+// in order from the one following its persisted cursor, the first delivery starts at
+// the head of the chain once the sink is added. The failed deliveries are retried
+// with an exponential backoff, the following blocks wait for the failed one
+type Bus struct {
+	logger hclog.Logger
+	store  blockchainStore
+
+	workers []*sinkWorker
+	sub     blockchain.Subscription
+
+	minRetryBackoff time.Duration
+	maxRetryBackoff time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// sinkWorker delivers the blocks to a sink
+type sinkWorker struct {
+	name   string
+	sink   Sink
+	outbox *outbox
+
+	// next is the number of the next block to deliver
+	next uint64
+
+	// wakeCh is notified when the chain advances
+	wakeCh chan struct{}
+}
+
+// NewBus creates the sinks of the configs, their cursors are persisted in the directory
+func NewBus(
+	logger hclog.Logger,
+	store blockchainStore,
+	dir string,
+	configs []*Config,
+	factories map[SinkType]Factory,
+) (*Bus, error) {
+	b := &Bus{
+		logger:          logger.Named("block_sink"),
+		store:           store,
+		minRetryBackoff: defaultMinRetryBackoff,
+		maxRetryBackoff: defaultMaxRetryBackoff,
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	names := map[string]struct{}{}
+
+	for _, config := range configs {
+		if !sinkNameRegex.MatchString(config.Name) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSinkName, config.Name)
+		}
+
+		if _, ok := names[config.Name]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateSinkName, config.Name)
+		}
+
+		names[config.Name] = struct{}{}
+	}
+
+	for _, config := range configs {
+		worker, err := b.newWorker(dir, config, factories)
+		if err != nil {
+			b.closeSinks()
+
+			return nil, fmt.Errorf("unable to set up the block sink %s: %w", config.Name, err)
+		}
+
+		b.workers = append(b.workers, worker)
+	}
+
+	return b, nil
+}
+
+// newWorker creates the sink of the config, and loads its cursor
+func (b *Bus) newWorker(dir string, config *Config, factories map[SinkType]Factory) (*sinkWorker, error) {
+	factory, ok := factories[config.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSinkType, config.Type)
+	}
+
+	outbox := newOutbox(dir, config.Name)
+
+	last, ok, err := outbox.load()
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		// the new sinks start at the head, the cursor is persisted
+		// so the blocks written until the first delivery are not skipped
+		last = b.store.Header().Number
+
+		if err := outbox.ack(last); err != nil {
+			return nil, err
+		}
+	}
+
+	sink, err := factory(config, b.logger.Named(config.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	return &sinkWorker{
+		name:   config.Name,
+		sink:   sink,
+		outbox: outbox,
+		next:   last + 1,
+		wakeCh: make(chan struct{}, 1),
+	}, nil
+}
+
+// Start starts the delivery to the sinks
+func (b *Bus) Start() {
+	if len(b.workers) == 0 {
+		return
+	}
+
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	b.sub = b.store.SubscribeEventStream()
+
+	for _, worker := range b.workers {
+		b.wg.Add(1)
+
+		go func(worker *sinkWorker) {
+			defer b.wg.Done()
+
+			b.run(worker)
+		}(worker)
+	}
+
+	go b.notify()
+}
+
+// Close stops the delivery, the blocks being delivered are not acknowledged
+func (b *Bus) Close() {
+	if b.cancel != nil {
+		b.cancel()
+		b.sub.Close()
+		b.wg.Wait()
+	}
+
+	b.closeSinks()
+}
+
+func (b *Bus) closeSinks() {
+	for _, worker := range b.workers {
+		if err := worker.sink.Close(); err != nil {
+			b.logger.Error("failed to close the block sink", "sink", worker.name, "err", err)
+		}
+	}
+}
+
+// notify wakes up the workers when the chain advances
+func (b *Bus) notify() {
+	for {
+		if evnt := b.sub.GetEvent(); evnt == nil {
+			return
+		}
+
+		for _, worker := range b.workers {
+			select {
+			case worker.wakeCh <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// run delivers the canonical blocks up to the head, then waits for the chain to advance
+func (b *Bus) run(worker *sinkWorker) {
+	for {
+		for worker.next <= b.store.Header().Number {
+			block, ok := b.store.GetBlockByNumber(worker.next, true)
+			if !ok {
+				b.logger.Error("block to deliver not found", "sink", worker.name, "number", worker.next)
+
+				break
+			}
+
+			if !b.deliver(worker, block) {
+				return
+			}
+
+			worker.next++
+		}
+
+		select {
+		case <-worker.wakeCh:
+		case <-b.ctx.Done():
+			return
+		}
+	}
+}
+
+// deliver pushes the block to the sink until it is acknowledged,
+// false is returned if the bus is closed first
+func (b *Bus) deliver(worker *sinkWorker, block *types.Block) bool {
+	backoff := b.minRetryBackoff
+
+	for {
+		err := worker.sink.Deliver(b.ctx, block)
+		if err == nil {
+			if err := worker.outbox.ack(block.Number()); err != nil {
+				b.logger.Error("failed to persist the block sink cursor", "sink", worker.name, "err", err)
+			}
+
+			return true
+		}
+
+		b.logger.Warn(
+			"failed to deliver the block",
+			"sink", worker.name,
+			"number", block.Number(),
+			"retry_in", backoff,
+			"err", err,
+		)
+
+		select {
+		case <-time.After(backoff):
+		case <-b.ctx.Done():
+			return false
+		}
+
+		if backoff *= 2; backoff > b.maxRetryBackoff {
+			backoff = b.maxRetryBackoff
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package blocksink
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSubscription returns the pushed events, and nil once closed
+type mockSubscription struct {
+	eventCh chan *blockchain.Event
+	closeCh chan struct{}
+	once    sync.Once
+}
+
+func (m *mockSubscription) GetEventCh() chan *blockchain.Event {
+	return m.eventCh
+}
+
+func (m *mockSubscription) GetEvent() *blockchain.Event {
+	select {
+	case evnt := <-m.eventCh:
+		return evnt
+	case <-m.closeCh:
+		return nil
+	}
+}
+
+func (m *mockSubscription) Close() {
+	m.once.Do(func() {
+		close(m.closeCh)
+	})
+}
+
+type mockStore struct {
+	lock   sync.Mutex
+	blocks []*types.Block
+	sub    *mockSubscription
+}
+
+func newMockStore(head uint64) *mockStore {
+	m := &mockStore{
+		sub: &mockSubscription{
+			eventCh: make(chan *blockchain.Event),
+			closeCh: make(chan struct{}),
+		},
+	}
+
+	for i := uint64(0); i <= head; i++ {
+		m.blocks = append(m.blocks, &types.Block{Header: &types.Header{Number: i}})
+	}
+
+	return m
+}
+
+// addBlock advances the chain and notifies the subscription
+func (m *mockStore) addBlock() {
+	m.lock.Lock()
+	m.blocks = append(m.blocks, &types.Block{Header: &types.Header{Number: uint64(len(m.blocks))}})
+	m.lock.Unlock()
+
+	m.sub.eventCh <- &blockchain.Event{Type: blockchain.EventHead}
+}
+
+func (m *mockStore) Header() *types.Header {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.blocks[len(m.blocks)-1].Header
+}
+
+func (m *mockStore) GetBlockByNumber(number uint64, full bool) (*types.Block, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if number >= uint64(len(m.blocks)) {
+		return nil, false
+	}
+
+	return m.blocks[number], true
+}
+
+func (m *mockStore) SubscribeEventStream() blockchain.Subscription {
+	return m.sub
+}
+
+// mockSink records the delivered blocks, the deliveries fail while failures is positive
+type mockSink struct {
+	lock      sync.Mutex
+	delivered []uint64
+	failures  int
+	closed    bool
+}
+
+func (m *mockSink) Deliver(ctx context.Context, block *types.Block) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.failures > 0 {
+		m.failures--
+
+		return errors.New("sink unavailable")
+	}
+
+	m.delivered = append(m.delivered, block.Number())
+
+	return nil
+}
+
+func (m *mockSink) Close() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.closed = true
+
+	return nil
+}
+
+func (m *mockSink) getDelivered() []uint64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return append([]uint64{}, m.delivered...)
+}
+
+func newTestBus(t *testing.T, store *mockStore, dir string, sink *mockSink) *Bus {
+	t.Helper()
+
+	factories := map[SinkType]Factory{
+		"mock": func(config *Config, logger hclog.Logger) (Sink, error) {
+			return sink, nil
+		},
+	}
+
+	bus, err := NewBus(hclog.NewNullLogger(), store, dir, []*Config{{Name: "test", Type: "mock"}}, factories)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bus.minRetryBackoff = time.Millisecond
+	bus.maxRetryBackoff = 5 * time.Millisecond
+
+	return bus
+}
+
+func TestBus_DeliverFromHead(t *testing.T) {
+	t.Parallel()
+
+	store := newMockStore(5)
+	sink := &mockSink{failures: 3}
+
+	bus := newTestBus(t, store, t.TempDir(), sink)
+	bus.Start()
+
+	// the new sinks start at the head, the failed deliveries are retried in order
+	store.addBlock()
+	store.addBlock()
+
+	assert.Eventually(t, func() bool {
+		return len(sink.getDelivered()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []uint64{6, 7}, sink.getDelivered())
+
+	bus.Close()
+	assert.True(t, sink.closed)
+}
+
+func TestBus_ResumeFromCursor(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := newMockStore(2)
+
+	first := &mockSink{}
+	bus := newTestBus(t, store, dir, first)
+	bus.Start()
+
+	store.addBlock()
+
+	assert.Eventually(t, func() bool {
+		return len(first.getDelivered()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	bus.Close()
+
+	// the blocks written while the node is stopped are delivered on restart
+	store.lock.Lock()
+	for i := 4; i <= 5; i++ {
+		store.blocks = append(store.blocks, &types.Block{Header: &types.Header{Number: uint64(i)}})
+	}
+	store.lock.Unlock()
+
+	store.sub = &mockSubscription{
+		eventCh: make(chan *blockchain.Event),
+		closeCh: make(chan struct{}),
+	}
+
+	second := &mockSink{}
+	bus = newTestBus(t, store, dir, second)
+	bus.Start()
+
+	assert.Eventually(t, func() bool {
+		return len(second.getDelivered()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []uint64{4, 5}, second.getDelivered())
+
+	bus.Close()
+}
+
+func TestNewBus_InvalidConfigs(t *testing.T) {
+	t.Parallel()
+
+	store := newMockStore(0)
+	factories := map[SinkType]Factory{
+		"mock": func(config *Config, logger hclog.Logger) (Sink, error) {
+			return &mockSink{}, nil
+		},
+	}
+
+	testTable := []struct {
+		name    string
+		configs []*Config
+		err     error
+	}{
+		{
+			"invalid name",
+			[]*Config{{Name: "../sink", Type: "mock"}},
+			ErrInvalidSinkName,
+		},
+		{
+			"duplicate name",
+			[]*Config{{Name: "sink", Type: "mock"}, {Name: "sink", Type: "mock"}},
+			ErrDuplicateSinkName,
+		},
+		{
+			"unknown type",
+			[]*Config{{Name: "sink", Type: "kafka"}},
+			ErrUnknownSinkType,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewBus(hclog.NewNullLogger(), store, t.TempDir(), testCase.configs, factories)
+			assert.ErrorIs(t, err, testCase.err)
+		})
+	}
+}
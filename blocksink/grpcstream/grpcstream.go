@@ -0,0 +1,166 @@
+package grpcstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blocksink"
+	"github.com/0xPolygon/polygon-edge/blocksink/grpcstream/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const defaultTimeout = 10 * time.Second
+
+var (
+	errNoEndpoint      = errors.New("the grpc endpoint is not set")
+	errDeliveryTimeout = errors.New("the block was not acknowledged in time")
+)
+
+// streamSink streams the blocks to the BlockSink service of the endpoint. The stream is
+// opened on the first delivery, and reopened after a failure. Every block waits for its
+// acknowledgement before the next one is sent
+type streamSink struct {
+	conn    *grpc.ClientConn
+	client  proto.BlockSinkClient
+	timeout time.Duration
+
+	lock   sync.Mutex
+	stream proto.BlockSink_StreamClient
+	cancel context.CancelFunc
+}
+
+// Factory implements the blocksink factory method
+func Factory(config *blocksink.Config, logger hclog.Logger) (blocksink.Sink, error) {
+	if config.Endpoint == "" {
+		return nil, errNoEndpoint
+	}
+
+	creds := insecure.NewCredentials()
+
+	if config.TLSCAFile != "" {
+		tlsCreds, err := credentials.NewClientTLSFromFile(config.TLSCAFile, "")
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the block sink CA certificate, %w", err)
+		}
+
+		creds = tlsCreds
+	}
+
+	// the connection is established in the background, the deliveries fail until it is
+	conn, err := grpc.Dial(config.Endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &streamSink{
+		conn:    conn,
+		client:  proto.NewBlockSinkClient(conn),
+		timeout: config.Timeout,
+	}
+
+	if sink.timeout == 0 {
+		sink.timeout = defaultTimeout
+	}
+
+	return sink, nil
+}
+
+// Deliver implements the Sink interface method
+func (s *streamSink) Deliver(ctx context.Context, block *types.Block) error {
+	stream, err := s.getStream()
+	if err != nil {
+		return err
+	}
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- send(stream, block)
+	}()
+
+	select {
+	case err = <-errCh:
+	case <-time.After(s.timeout):
+		err = errDeliveryTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		// the acknowledgements of the stream are not in sync with the blocks anymore
+		s.resetStream()
+	}
+
+	return err
+}
+
+// send sends the block on the stream and waits for its acknowledgement
+func send(stream proto.BlockSink_StreamClient, block *types.Block) error {
+	if err := stream.Send(&proto.Block{
+		Number: block.Number(),
+		Hash:   block.Hash().Bytes(),
+		Rlp:    block.MarshalRLP(),
+	}); err != nil {
+		return err
+	}
+
+	ack, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	if ack.Number != block.Number() {
+		return fmt.Errorf("block %d acknowledged instead of %d", ack.Number, block.Number())
+	}
+
+	return nil
+}
+
+// getStream returns the open stream, or opens a new one
+func (s *streamSink) getStream() (proto.BlockSink_StreamClient, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stream != nil {
+		return s.stream, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	stream, err := s.client.Stream(ctx)
+	if err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	s.stream, s.cancel = stream, cancel
+
+	return stream, nil
+}
+
+// resetStream cancels the open stream, if any
+func (s *streamSink) resetStream() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	s.stream, s.cancel = nil, nil
+}
+
+// Close implements the Sink interface method
+func (s *streamSink) Close() error {
+	s.resetStream()
+
+	return s.conn.Close()
+}
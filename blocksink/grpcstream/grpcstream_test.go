@@ -0,0 +1,105 @@
+package grpcstream
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blocksink"
+	"github.com/0xPolygon/polygon-edge/blocksink/grpcstream/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// mockServer acknowledges the received blocks, with the number offset by ackOffset
+type mockServer struct {
+	proto.UnimplementedBlockSinkServer
+
+	ackOffset uint64
+	blockCh   chan *proto.Block
+}
+
+func (m *mockServer) Stream(stream proto.BlockSink_StreamServer) error {
+	for {
+		block, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		m.blockCh <- block
+
+		if err := stream.Send(&proto.Ack{Number: block.Number + m.ackOffset}); err != nil {
+			return err
+		}
+	}
+}
+
+func newTestSink(t *testing.T, srv *mockServer) blocksink.Sink {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	proto.RegisterBlockSinkServer(server, srv)
+
+	go func() {
+		_ = server.Serve(lis)
+	}()
+
+	t.Cleanup(server.Stop)
+
+	sink, err := Factory(&blocksink.Config{
+		Endpoint: lis.Addr().String(),
+		Timeout:  5 * time.Second,
+	}, hclog.NewNullLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = sink.Close()
+	})
+
+	return sink
+}
+
+func TestStreamSink_Deliver(t *testing.T) {
+	t.Parallel()
+
+	srv := &mockServer{blockCh: make(chan *proto.Block, 2)}
+	sink := newTestSink(t, srv)
+
+	for i := uint64(1); i <= 2; i++ {
+		block := &types.Block{Header: &types.Header{Number: i}}
+		block.Header.ComputeHash()
+
+		assert.NoError(t, sink.Deliver(context.Background(), block))
+
+		received := <-srv.blockCh
+		assert.Equal(t, i, received.Number)
+		assert.Equal(t, block.Hash().Bytes(), received.Hash)
+		assert.Equal(t, block.MarshalRLP(), received.Rlp)
+	}
+}
+
+func TestStreamSink_AckMismatch(t *testing.T) {
+	t.Parallel()
+
+	srv := &mockServer{ackOffset: 1, blockCh: make(chan *proto.Block, 1)}
+	sink := newTestSink(t, srv)
+
+	assert.Error(t, sink.Deliver(context.Background(), &types.Block{Header: &types.Header{Number: 1}}))
+}
+
+func TestStreamSink_NoEndpoint(t *testing.T) {
+	t.Parallel()
+
+	_, err := Factory(&blocksink.Config{}, hclog.NewNullLogger())
+	assert.ErrorIs(t, err, errNoEndpoint)
+}
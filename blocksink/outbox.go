@@ -0,0 +1,55 @@
+package blocksink
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// outbox persists the number of the last block a sink acknowledged,
+// the delivery resumes from the next block when the node is restarted
+type outbox struct {
+	path string
+}
+
+// newOutbox creates the outbox of the sink in the directory
+func newOutbox(dir string, name string) *outbox {
+	return &outbox{
+		path: filepath.Join(dir, name+".cursor"),
+	}
+}
+
+// load returns the number of the last acknowledged block, if any
+func (o *outbox) load() (uint64, bool, error) {
+	data, err := ioutil.ReadFile(o.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, err
+	}
+
+	number, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid cursor %s: %w", o.path, err)
+	}
+
+	return number, true, nil
+}
+
+// ack persists the number of the acknowledged block. The cursor is replaced
+// by a rename, so a node stopped in the middle of the write keeps the previous one
+func (o *outbox) ack(number uint64) error {
+	tmpPath := o.path + ".new"
+
+	if err := ioutil.WriteFile(tmpPath, []byte(strconv.FormatUint(number, 10)), 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, o.path)
+}
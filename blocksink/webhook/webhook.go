@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blocksink"
+	"github.com/0xPolygon/polygon-edge/jsonrpc"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const defaultTimeout = 10 * time.Second
+
+var (
+	errNoURL = errors.New("the webhook url is not set")
+)
+
+// webhookSink posts the JSON of the blocks to the URL,
+// the blocks are acknowledged by the 2xx responses
+type webhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// Factory implements the blocksink factory method
+func Factory(config *blocksink.Config, logger hclog.Logger) (blocksink.Sink, error) {
+	if config.URL == "" {
+		return nil, errNoURL
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	return &webhookSink{
+		url:     config.URL,
+		headers: config.Headers,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Deliver implements the Sink interface method
+func (w *webhookSink) Deliver(ctx context.Context, block *types.Block) error {
+	body, err := jsonrpc.MarshalBlock(block)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for key, value := range w.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	// drain the body, so the connection is reused
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Close implements the Sink interface method
+func (w *webhookSink) Close() error {
+	w.client.CloseIdleConnections()
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blocksink"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSink_Deliver(t *testing.T) {
+	t.Parallel()
+
+	var (
+		lock     sync.Mutex
+		status   = http.StatusOK
+		received struct {
+			Number string `json:"number"`
+		}
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink, err := Factory(&blocksink.Config{
+		URL:     server.URL,
+		Headers: map[string]string{"X-Token": "secret"},
+	}, hclog.NewNullLogger())
+	assert.NoError(t, err)
+
+	block := &types.Block{Header: &types.Header{Number: 10}}
+
+	assert.NoError(t, sink.Deliver(context.Background(), block))
+
+	lock.Lock()
+	assert.Equal(t, "0xa", received.Number)
+
+	// the non 2xx responses are not acknowledgements
+	status = http.StatusInternalServerError
+	lock.Unlock()
+
+	assert.Error(t, sink.Deliver(context.Background(), block))
+
+	assert.NoError(t, sink.Close())
+}
+
+func TestWebhookSink_NoURL(t *testing.T) {
+	t.Parallel()
+
+	_, err := Factory(&blocksink.Config{}, hclog.NewNullLogger())
+	assert.ErrorIs(t, err, errNoURL)
+}
@@ -26,6 +26,9 @@ type Chain struct {
 	Genesis   *Genesis `json:"genesis"`
 	Params    *Params  `json:"params"`
 	Bootnodes []string `json:"bootnodes,omitempty"`
+
+	// DiscoveryDNS is the domain publishing the bootnode list in its TXT records
+	DiscoveryDNS string `json:"discoveryDNS,omitempty"`
 }
 
 // Genesis specifies the header fields, state of a genesis block
@@ -44,6 +47,10 @@ type Genesis struct {
 	// Override
 	StateRoot types.Hash
 
+	// BaseFee is the base fee of the genesis block, the base fee of the first block past
+	// the EIP-1559 fork is derived from it. The default initial base fee is used if 0
+	BaseFee uint64 `json:"baseFee,omitempty"`
+
 	// Only for testing
 	Number     uint64     `json:"number"`
 	GasUsed    uint64     `json:"gasUsed"`
@@ -67,6 +74,7 @@ func (g *Genesis) GenesisHeader() *types.Header {
 		GasLimit:     g.GasLimit,
 		GasUsed:      g.GasUsed,
 		Difficulty:   g.Difficulty,
+		BaseFee:      g.BaseFee,
 		MixHash:      g.Mixhash,
 		Miner:        g.Coinbase,
 		StateRoot:    stateRoot,
@@ -106,6 +114,7 @@ func (g *Genesis) MarshalJSON() ([]byte, error) {
 		Mixhash    types.Hash                  `json:"mixHash"`
 		Coinbase   types.Address               `json:"coinbase"`
 		Alloc      *map[string]*GenesisAccount `json:"alloc,omitempty"`
+		BaseFee    *string                     `json:"baseFee,omitempty"`
 		Number     *string                     `json:"number,omitempty"`
 		GasUsed    *string                     `json:"gasUsed,omitempty"`
 		ParentHash types.Hash                  `json:"parentHash"`
@@ -132,6 +141,10 @@ func (g *Genesis) MarshalJSON() ([]byte, error) {
 		enc.Alloc = &alloc
 	}
 
+	if g.BaseFee != 0 {
+		enc.BaseFee = types.EncodeUint64(g.BaseFee)
+	}
+
 	enc.Number = types.EncodeUint64(g.Number)
 	enc.GasUsed = types.EncodeUint64(g.GasUsed)
 	enc.ParentHash = g.ParentHash
@@ -150,6 +163,7 @@ func (g *Genesis) UnmarshalJSON(data []byte) error {
 		Mixhash    *types.Hash                `json:"mixHash"`
 		Coinbase   *types.Address             `json:"coinbase"`
 		Alloc      map[string]*GenesisAccount `json:"alloc"`
+		BaseFee    *string                    `json:"baseFee"`
 		Number     *string                    `json:"number"`
 		GasUsed    *string                    `json:"gasUsed"`
 		ParentHash *types.Hash                `json:"parentHash"`
@@ -214,6 +228,11 @@ func (g *Genesis) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	g.BaseFee, subErr = types.ParseUint64orHex(dec.BaseFee)
+	if subErr != nil {
+		parseError("basefee", subErr)
+	}
+
 	g.Number, subErr = types.ParseUint64orHex(dec.Number)
 	if subErr != nil {
 		parseError("number", subErr)
@@ -374,5 +393,17 @@ func importChain(content []byte) (*Chain, error) {
 		return nil, fmt.Errorf("expected one consensus engine but found %d", len(engines))
 	}
 
+	if err := chain.Params.ValidateBlockTimeSchedule(); err != nil {
+		return nil, fmt.Errorf("invalid block time schedule, %w", err)
+	}
+
+	if err := chain.Params.ValidateFeeCollector(); err != nil {
+		return nil, fmt.Errorf("invalid fee collector, %w", err)
+	}
+
+	if err := chain.Params.ValidateEpochReward(); err != nil {
+		return nil, fmt.Errorf("invalid epoch reward, %w", err)
+	}
+
 	return chain, nil
 }
@@ -133,6 +133,16 @@ func TestGenesisX(t *testing.T) {
 				},
 			},
 		},
+		{
+			input: `{
+				"gasLimit": "0x11",
+				"baseFee": "0x3b9aca00"
+			}`,
+			output: &Genesis{
+				GasLimit: 17,
+				BaseFee:  1000000000,
+			},
+		},
 	}
 
 	for _, c := range cases {
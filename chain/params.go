@@ -1,7 +1,25 @@
 package chain
 
 import (
+	"errors"
+	"fmt"
 	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// MinBlockTime is the lowest block time the block time schedule accepts, in seconds
+const MinBlockTime uint64 = 1
+
+var (
+	ErrBlockTimeTooLow        = fmt.Errorf("block time must be at least %d second", MinBlockTime)
+	ErrBlockTimeScheduleOrder = errors.New("block time schedule must be in strictly increasing block order")
+	ErrFeeCollectorOrder      = errors.New("fee collectors must be in strictly increasing block order")
+	ErrEpochRewardBlock       = errors.New("epoch reward block must be at least 1")
+	ErrEpochRewardAmount      = errors.New("epoch reward per block must be positive")
+	ErrUnknownFork            = errors.New("unknown fork")
+	ErrTxTooLarge             = errors.New("tx too large")
+	ErrBlockTooLarge          = errors.New("block would exceed size")
 )
 
 // Params are all the set of params for the chain
@@ -10,6 +28,207 @@ type Params struct {
 	ChainID        int                    `json:"chainID"`
 	Engine         map[string]interface{} `json:"engine"`
 	BlockGasTarget uint64                 `json:"blockGasTarget"`
+
+	// BlockTimeSchedule are the block times of the chain from the given blocks on.
+	// The block time of the node is used before the first entry
+	BlockTimeSchedule []BlockTimeFork `json:"blockTimeSchedule,omitempty"`
+
+	// AllowedFutureDrift is how far ahead of the local clock the block timestamps can be, in seconds.
+	// The blocks beyond it are retried once the local clock catches up. One block time is allowed if not set
+	AllowedFutureDrift uint64 `json:"allowedFutureDrift,omitempty"`
+
+	// Precompiles are the builtin precompiled contracts the chain declares, on top of the standard ones
+	Precompiles []*Precompile `json:"precompiles,omitempty"`
+
+	// FeeCollector are the addresses credited with the base fees past the EIP-1559 fork,
+	// from the given blocks on. The base fees are burnt before the first entry.
+	// The credits are part of the state root, so the blocks of the validators
+	// applying another destination are rejected
+	FeeCollector []FeeCollectorFork `json:"feeCollector,omitempty"`
+
+	// EpochReward is the reward the consensus credits to the validators at the epoch blocks,
+	// in proportion to the blocks of the epoch they committed to. No rewards are credited if not set
+	EpochReward *EpochReward `json:"epochReward,omitempty"`
+
+	// MaxBlockSize is the maximum total size of the RLP-encoded transactions of a block, and MaxTxSize
+	// the maximum size of a single RLP-encoded transaction. They are enforced from the size limits fork,
+	// a zero size is not limited. The header isn't counted, the seals are added to it once the block is built
+	MaxBlockSize uint64 `json:"maxBlockSize,omitempty"`
+	MaxTxSize    uint64 `json:"maxTxSize,omitempty"`
+}
+
+// SizeLimits are the maximum sizes of the RLP-encoded transactions of a block, a zero size is not limited
+type SizeLimits struct {
+	MaxBlockSize uint64
+	MaxTxSize    uint64
+}
+
+// SizeLimitsAt returns the size limits enforced at the block, none before the size limits fork
+func (p *Params) SizeLimitsAt(block uint64) SizeLimits {
+	if p == nil || p.Forks == nil || !p.Forks.IsSizeLimits(block) {
+		return SizeLimits{}
+	}
+
+	return SizeLimits{
+		MaxBlockSize: p.MaxBlockSize,
+		MaxTxSize:    p.MaxTxSize,
+	}
+}
+
+// VerifyTx checks the transaction is within the maximum transaction size, and fits in an empty block
+func (l SizeLimits) VerifyTx(tx *types.Transaction) error {
+	size := tx.Size()
+
+	if l.MaxTxSize != 0 && size > l.MaxTxSize {
+		return fmt.Errorf("%w, %d bytes over the limit of %d", ErrTxTooLarge, size, l.MaxTxSize)
+	}
+
+	return l.VerifyBlockSize(0, tx)
+}
+
+// VerifyBlockSize checks the transaction can be added to the transactions of the given total size,
+// within the maximum block size
+func (l SizeLimits) VerifyBlockSize(size uint64, tx *types.Transaction) error {
+	if l.MaxBlockSize != 0 && size+tx.Size() > l.MaxBlockSize {
+		return fmt.Errorf("%w, %d bytes over the limit of %d", ErrBlockTooLarge, size+tx.Size(), l.MaxBlockSize)
+	}
+
+	return nil
+}
+
+// VerifyTransactions checks the transactions of a block are within the size limits
+func (l SizeLimits) VerifyTransactions(txs []*types.Transaction) error {
+	var size uint64
+
+	for indx, tx := range txs {
+		if err := l.VerifyTx(tx); err != nil {
+			return fmt.Errorf("transaction %d: %w", indx, err)
+		}
+
+		if err := l.VerifyBlockSize(size, tx); err != nil {
+			return fmt.Errorf("transaction %d: %w", indx, err)
+		}
+
+		size += tx.Size()
+	}
+
+	return nil
+}
+
+// EpochReward is the reward credited to the committers of the blocks, starting from the block.
+// The headers from the block on carry the committed seals of their parents, so the committers
+// of the blocks are part of the chain instead of being collected by each node
+type EpochReward struct {
+	Block       uint64   `json:"block"`
+	BlockReward *big.Int `json:"blockReward"` // The reward of each block, shared among its committers
+
+	// Source is the account the rewards are paid from, the rewards are minted if not set.
+	// The rewards of the epoch are not paid if the source can't cover them
+	Source *types.Address `json:"source,omitempty"`
+}
+
+// FeeCollectorFork is the address credited with the base fees starting from the block.
+// The base fees are burnt again from the block if the address is the zero address
+type FeeCollectorFork struct {
+	Block   uint64        `json:"block"`
+	Address types.Address `json:"address"`
+}
+
+// Precompile is a builtin precompiled contract declared at the address
+type Precompile struct {
+	Address types.Address `json:"address"`
+	Builtin string        `json:"builtin"`
+
+	// Fork is the name of the fork activating the contract, as in the forks of the params.
+	// The contract is active from the genesis if empty
+	Fork string `json:"fork,omitempty"`
+}
+
+// BlockTimeFork is the block time of the chain starting from the block
+type BlockTimeFork struct {
+	Block     uint64 `json:"block"`
+	BlockTime uint64 `json:"blockTime"` // Block time in seconds
+}
+
+// ValidateBlockTimeSchedule checks the block time schedule is in strictly increasing block order,
+// and that every block time is at least MinBlockTime
+func (p *Params) ValidateBlockTimeSchedule() error {
+	for indx, fork := range p.BlockTimeSchedule {
+		if fork.BlockTime < MinBlockTime {
+			return fmt.Errorf("%w, block time at block %d is %d", ErrBlockTimeTooLow, fork.Block, fork.BlockTime)
+		}
+
+		if indx > 0 && fork.Block <= p.BlockTimeSchedule[indx-1].Block {
+			return fmt.Errorf("%w, block %d follows block %d", ErrBlockTimeScheduleOrder,
+				fork.Block, p.BlockTimeSchedule[indx-1].Block)
+		}
+	}
+
+	return nil
+}
+
+// BlockTimeAt returns the block time scheduled at the block, in seconds.
+// It returns false if the schedule doesn't cover the block
+func (p *Params) BlockTimeAt(block uint64) (uint64, bool) {
+	var (
+		blockTime uint64
+		found     bool
+	)
+
+	for _, fork := range p.BlockTimeSchedule {
+		if fork.Block > block {
+			break
+		}
+
+		blockTime, found = fork.BlockTime, true
+	}
+
+	return blockTime, found
+}
+
+// ValidateFeeCollector checks the fee collectors are in strictly increasing block order
+func (p *Params) ValidateFeeCollector() error {
+	for indx := 1; indx < len(p.FeeCollector); indx++ {
+		if p.FeeCollector[indx].Block <= p.FeeCollector[indx-1].Block {
+			return fmt.Errorf("%w, block %d follows block %d", ErrFeeCollectorOrder,
+				p.FeeCollector[indx].Block, p.FeeCollector[indx-1].Block)
+		}
+	}
+
+	return nil
+}
+
+// ValidateEpochReward checks the epoch reward starts past the genesis, with a positive reward per block
+func (p *Params) ValidateEpochReward() error {
+	if p.EpochReward == nil {
+		return nil
+	}
+
+	if p.EpochReward.Block == 0 {
+		return ErrEpochRewardBlock
+	}
+
+	if p.EpochReward.BlockReward == nil || p.EpochReward.BlockReward.Sign() <= 0 {
+		return ErrEpochRewardAmount
+	}
+
+	return nil
+}
+
+// FeeCollectorAt returns the address credited with the base fees of the block.
+// It returns false if the base fees of the block are burnt
+func (p *Params) FeeCollectorAt(block uint64) (types.Address, bool) {
+	var collector types.Address
+
+	for _, fork := range p.FeeCollector {
+		if fork.Block > block {
+			break
+		}
+
+		collector = fork.Address
+	}
+
+	return collector, collector != types.ZeroAddress
 }
 
 func (p *Params) GetEngine() string {
@@ -28,9 +247,22 @@ type Forks struct {
 	Constantinople *Fork `json:"constantinople,omitempty"`
 	Petersburg     *Fork `json:"petersburg,omitempty"`
 	Istanbul       *Fork `json:"istanbul,omitempty"`
+	Berlin         *Fork `json:"berlin,omitempty"`
+	London         *Fork `json:"london,omitempty"`
 	EIP150         *Fork `json:"EIP150,omitempty"`
 	EIP158         *Fork `json:"EIP158,omitempty"`
 	EIP155         *Fork `json:"EIP155,omitempty"`
+	EIP170         *Fork `json:"EIP170,omitempty"`
+	EIP1559        *Fork `json:"EIP1559,omitempty"`
+	EIP2537        *Fork `json:"EIP2537,omitempty"`
+	EIP3860        *Fork `json:"EIP3860,omitempty"`
+
+	// ReplayProtection rejects the transactions signed without the chain id, as before EIP155.
+	// The unprotected transactions of the blocks before the fork are still valid
+	ReplayProtection *Fork `json:"replayProtection,omitempty"`
+
+	// SizeLimits enforces the maximum block size and transaction size of the params
+	SizeLimits *Fork `json:"sizeLimits,omitempty"`
 }
 
 func (f *Forks) active(ff *Fork, block uint64) bool {
@@ -57,6 +289,16 @@ func (f *Forks) IsPetersburg(block uint64) bool {
 	return f.active(f.Petersburg, block)
 }
 
+// IsBerlin checks if the access lists and the access gas costs are active, as in EIP-2929 and EIP-2930
+func (f *Forks) IsBerlin(block uint64) bool {
+	return f.active(f.Berlin, block)
+}
+
+// IsLondon checks if the BASEFEE opcode and the reduced refunds are active, as in EIP-3198 and EIP-3529
+func (f *Forks) IsLondon(block uint64) bool {
+	return f.active(f.London, block)
+}
+
 func (f *Forks) IsEIP150(block uint64) bool {
 	return f.active(f.EIP150, block)
 }
@@ -69,6 +311,35 @@ func (f *Forks) IsEIP155(block uint64) bool {
 	return f.active(f.EIP155, block)
 }
 
+// IsEIP170 checks if the size of the deployed code is limited, the limit is also part of EIP158
+func (f *Forks) IsEIP170(block uint64) bool {
+	return f.active(f.EIP170, block)
+}
+
+func (f *Forks) IsEIP1559(block uint64) bool {
+	return f.active(f.EIP1559, block)
+}
+
+// IsEIP2537 checks if the BLS12-381 builtin precompiled contracts can be active
+func (f *Forks) IsEIP2537(block uint64) bool {
+	return f.active(f.EIP2537, block)
+}
+
+// IsEIP3860 checks if the size of the init code is limited and the init code words are paid
+func (f *Forks) IsEIP3860(block uint64) bool {
+	return f.active(f.EIP3860, block)
+}
+
+// IsReplayProtection checks if only the replay protected transactions are valid, as in EIP155
+func (f *Forks) IsReplayProtection(block uint64) bool {
+	return f.active(f.ReplayProtection, block)
+}
+
+// IsSizeLimits checks if the maximum block size and transaction size of the params are enforced
+func (f *Forks) IsSizeLimits(block uint64) bool {
+	return f.active(f.SizeLimits, block)
+}
+
 func (f *Forks) At(block uint64) ForksInTime {
 	return ForksInTime{
 		Homestead:      f.active(f.Homestead, block),
@@ -76,9 +347,18 @@ func (f *Forks) At(block uint64) ForksInTime {
 		Constantinople: f.active(f.Constantinople, block),
 		Petersburg:     f.active(f.Petersburg, block),
 		Istanbul:       f.active(f.Istanbul, block),
+		Berlin:         f.active(f.Berlin, block),
+		London:         f.active(f.London, block),
 		EIP150:         f.active(f.EIP150, block),
 		EIP158:         f.active(f.EIP158, block),
 		EIP155:         f.active(f.EIP155, block),
+		EIP170:         f.active(f.EIP170, block),
+		EIP1559:        f.active(f.EIP1559, block),
+		EIP2537:        f.active(f.EIP2537, block),
+		EIP3860:        f.active(f.EIP3860, block),
+
+		ReplayProtection: f.active(f.ReplayProtection, block),
+		SizeLimits:       f.active(f.SizeLimits, block),
 	}
 }
 
@@ -104,9 +384,49 @@ type ForksInTime struct {
 	Constantinople,
 	Petersburg,
 	Istanbul,
+	Berlin,
+	London,
 	EIP150,
 	EIP158,
-	EIP155 bool
+	EIP155,
+	EIP170,
+	EIP1559,
+	EIP2537,
+	EIP3860,
+	ReplayProtection,
+	SizeLimits bool
+}
+
+// Enabled checks if the fork with the name, as in the forks of the params, is enabled.
+// The empty name is always enabled
+func (f *ForksInTime) Enabled(name string) (bool, error) {
+	forks := map[string]bool{
+		"":               true,
+		"homestead":      f.Homestead,
+		"byzantium":      f.Byzantium,
+		"constantinople": f.Constantinople,
+		"petersburg":     f.Petersburg,
+		"istanbul":       f.Istanbul,
+		"berlin":         f.Berlin,
+		"london":         f.London,
+		"EIP150":         f.EIP150,
+		"EIP158":         f.EIP158,
+		"EIP155":         f.EIP155,
+		"EIP170":         f.EIP170,
+		"EIP1559":        f.EIP1559,
+		"EIP2537":        f.EIP2537,
+		"EIP3860":        f.EIP3860,
+
+		"replayProtection": f.ReplayProtection,
+		"sizeLimits":       f.SizeLimits,
+	}
+
+	enabled, ok := forks[name]
+	if !ok {
+		return false, fmt.Errorf("%w %s", ErrUnknownFork, name)
+	}
+
+	return enabled, nil
 }
 
 var AllForksEnabled = &Forks{
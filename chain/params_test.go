@@ -2,9 +2,12 @@ package chain
 
 import (
 	"encoding/json"
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
 )
 
 func TestValidateChainID(t *testing.T) {
@@ -66,3 +69,235 @@ func TestParamsForksInTime(t *testing.T) {
 	expect("constantinople", ff.Constantinople, false)
 	expect("eip150", ff.EIP150, false)
 }
+
+func TestParamsBlockTimeSchedule(t *testing.T) {
+	cases := []struct {
+		name     string
+		schedule []BlockTimeFork
+		err      error
+	}{
+		{
+			name: "valid schedule",
+			schedule: []BlockTimeFork{
+				{Block: 0, BlockTime: 2},
+				{Block: 100, BlockTime: 1},
+			},
+		},
+		{
+			name: "block time too low",
+			schedule: []BlockTimeFork{
+				{Block: 100, BlockTime: 0},
+			},
+			err: ErrBlockTimeTooLow,
+		},
+		{
+			name: "not strictly increasing",
+			schedule: []BlockTimeFork{
+				{Block: 100, BlockTime: 2},
+				{Block: 100, BlockTime: 1},
+			},
+			err: ErrBlockTimeScheduleOrder,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := &Params{BlockTimeSchedule: c.schedule}
+
+			if err := p.ValidateBlockTimeSchedule(); !errors.Is(err, c.err) {
+				t.Fatalf("expected error %v but found %v", c.err, err)
+			}
+		})
+	}
+}
+
+func TestParamsBlockTimeAt(t *testing.T) {
+	p := &Params{
+		BlockTimeSchedule: []BlockTimeFork{
+			{Block: 10, BlockTime: 2},
+			{Block: 20, BlockTime: 1},
+		},
+	}
+
+	cases := []struct {
+		block     uint64
+		blockTime uint64
+		found     bool
+	}{
+		{block: 9, blockTime: 0, found: false},
+		{block: 10, blockTime: 2, found: true},
+		{block: 19, blockTime: 2, found: true},
+		{block: 20, blockTime: 1, found: true},
+		{block: 30, blockTime: 1, found: true},
+	}
+
+	for _, c := range cases {
+		blockTime, found := p.BlockTimeAt(c.block)
+		if blockTime != c.blockTime || found != c.found {
+			t.Fatalf("block %d: expected (%d, %v) but found (%d, %v)", c.block, c.blockTime, c.found, blockTime, found)
+		}
+	}
+}
+
+func TestImportInvalidBlockTimeSchedule(t *testing.T) {
+	_, err := importChain([]byte(`{
+		"params": {
+			"engine": {"ibft": {}},
+			"blockTimeSchedule": [{"block": 10, "blockTime": 0}]
+		}
+	}`))
+
+	if !errors.Is(err, ErrBlockTimeTooLow) {
+		t.Fatalf("expected error %v but found %v", ErrBlockTimeTooLow, err)
+	}
+}
+
+func TestParamsFeeCollector(t *testing.T) {
+	collector1 := types.StringToAddress("1")
+	collector2 := types.StringToAddress("2")
+
+	p := &Params{
+		FeeCollector: []FeeCollectorFork{
+			{Block: 10, Address: collector1},
+			{Block: 20, Address: collector2},
+			{Block: 30, Address: types.ZeroAddress},
+		},
+	}
+
+	if err := p.ValidateFeeCollector(); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		block     uint64
+		collector types.Address
+		found     bool
+	}{
+		{block: 9, collector: types.ZeroAddress, found: false},
+		{block: 10, collector: collector1, found: true},
+		{block: 20, collector: collector2, found: true},
+		{block: 30, collector: types.ZeroAddress, found: false},
+	}
+
+	for _, c := range cases {
+		collector, found := p.FeeCollectorAt(c.block)
+		if collector != c.collector || found != c.found {
+			t.Fatalf("block %d: expected (%s, %v) but found (%s, %v)", c.block, c.collector, c.found, collector, found)
+		}
+	}
+
+	p.FeeCollector = append(p.FeeCollector, FeeCollectorFork{Block: 30, Address: collector1})
+
+	if err := p.ValidateFeeCollector(); !errors.Is(err, ErrFeeCollectorOrder) {
+		t.Fatalf("expected error %v but found %v", ErrFeeCollectorOrder, err)
+	}
+}
+
+func TestImportEpochReward(t *testing.T) {
+	c, err := importChain([]byte(`{
+		"params": {
+			"engine": {"ibft": {}},
+			"epochReward": {
+				"block": 10,
+				"blockReward": 1000000000000000000000,
+				"source": "0x0000000000000000000000000000000000000001"
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reward := c.Params.EpochReward
+	if reward.Block != 10 || reward.BlockReward.String() != "1000000000000000000000" {
+		t.Fatalf("unexpected epoch reward %d %s", reward.Block, reward.BlockReward)
+	}
+
+	if reward.Source == nil || *reward.Source != types.StringToAddress("1") {
+		t.Fatalf("unexpected epoch reward source %v", reward.Source)
+	}
+
+	cases := map[string]error{
+		`{"block": 0, "blockReward": 1}`:  ErrEpochRewardBlock,
+		`{"block": 1, "blockReward": 0}`:  ErrEpochRewardAmount,
+		`{"block": 1}`:                    ErrEpochRewardAmount,
+		`{"block": 1, "blockReward": -1}`: ErrEpochRewardAmount,
+	}
+
+	for raw, expected := range cases {
+		_, err := importChain([]byte(`{"params": {"engine": {"ibft": {}}, "epochReward": ` + raw + `}}`))
+		if !errors.Is(err, expected) {
+			t.Fatalf("%s: expected error %v but found %v", raw, expected, err)
+		}
+	}
+}
+
+func TestForksInTimeEnabled(t *testing.T) {
+	forks := (&Forks{EIP2537: NewFork(10)}).At(10)
+
+	enabled, err := forks.Enabled("EIP2537")
+	if err != nil || !enabled {
+		t.Fatal("the EIP2537 fork should be enabled")
+	}
+
+	enabled, err = forks.Enabled("london")
+	if err != nil || enabled {
+		t.Fatal("the london fork should be disabled")
+	}
+
+	if enabled, _ := forks.Enabled(""); !enabled {
+		t.Fatal("the empty fork should be enabled")
+	}
+
+	if _, err := forks.Enabled("unknown"); !errors.Is(err, ErrUnknownFork) {
+		t.Fatal("the unknown fork should fail")
+	}
+}
+
+func TestParamsSizeLimits(t *testing.T) {
+	c, err := importChain([]byte(`{"params": {"engine": {"ibft": {}}, "forks": {"sizeLimits": 10},
+		"maxBlockSize": 300, "maxTxSize": 200}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if limits := c.Params.SizeLimitsAt(9); limits != (SizeLimits{}) {
+		t.Fatalf("no limits expected before the fork but found %v", limits)
+	}
+
+	limits := c.Params.SizeLimitsAt(10)
+	if limits != (SizeLimits{MaxBlockSize: 300, MaxTxSize: 200}) {
+		t.Fatalf("unexpected limits %v", limits)
+	}
+
+	newTx := func(inputSize int) *types.Transaction {
+		return &types.Transaction{Input: make([]byte, inputSize)}
+	}
+
+	small, large := newTx(100), newTx(250)
+
+	if err := limits.VerifyTx(small); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := limits.VerifyTx(large); !errors.Is(err, ErrTxTooLarge) {
+		t.Fatalf("expected error %v but found %v", ErrTxTooLarge, err)
+	}
+
+	// the transaction larger than a block can't be included in any
+	if err := (SizeLimits{MaxBlockSize: 200}).VerifyTx(large); !errors.Is(err, ErrBlockTooLarge) {
+		t.Fatalf("expected error %v but found %v", ErrBlockTooLarge, err)
+	}
+
+	if err := limits.VerifyTransactions([]*types.Transaction{small, small}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := limits.VerifyTransactions([]*types.Transaction{small, small, small}); !errors.Is(err, ErrBlockTooLarge) {
+		t.Fatalf("expected error %v but found %v", ErrBlockTooLarge, err)
+	}
+
+	if err := (SizeLimits{}).VerifyTransactions([]*types.Transaction{large, large}); err != nil {
+		t.Fatalf("no limits expected but found %v", err)
+	}
+}
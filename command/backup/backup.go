@@ -16,6 +16,7 @@ func GetCommand() *cobra.Command {
 	}
 
 	helper.RegisterGRPCAddressFlag(backupCmd)
+	helper.RegisterGRPCClientFlags(backupCmd)
 
 	setFlags(backupCmd)
 	setRequiredFlags(backupCmd)
@@ -44,6 +45,14 @@ func setFlags(cmd *cobra.Command) {
 		"",
 		"the end height of the chain in backup",
 	)
+
+	cmd.Flags().BoolVar(
+		&params.snapshot,
+		snapshotFlag,
+		false,
+		"back up the blocks along with the state of the head, restored by the restore command. "+
+			"The node stops writing blocks until the snapshot is taken",
+	)
 }
 
 func setRequiredFlags(cmd *cobra.Command) {
@@ -7,12 +7,14 @@ import (
 	"github.com/0xPolygon/polygon-edge/command/helper"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
 )
 
 const (
-	outFlag  = "out"
-	fromFlag = "from"
-	toFlag   = "to"
+	outFlag      = "out"
+	fromFlag     = "from"
+	toFlag       = "to"
+	snapshotFlag = "snapshot"
 )
 
 var (
@@ -20,12 +22,14 @@ var (
 )
 
 var (
-	errDecodeRange  = errors.New("unable to decode range value")
-	errInvalidRange = errors.New(`invalid "to" value; must be >= "from"`)
+	errDecodeRange   = errors.New("unable to decode range value")
+	errInvalidRange  = errors.New(`invalid "to" value; must be >= "from"`)
+	errSnapshotRange = errors.New("the snapshot contains the whole chain, the range can't be set")
 )
 
 type backupParams struct {
-	out string
+	out      string
+	snapshot bool
 
 	fromRaw string
 	toRaw   string
@@ -38,6 +42,10 @@ type backupParams struct {
 }
 
 func (p *backupParams) validateFlags() error {
+	if p.snapshot && (p.fromRaw != "0" || p.toRaw != "") {
+		return errSnapshotRange
+	}
+
 	var parseErr error
 
 	if p.from, parseErr = types.ParseUint64orHex(&p.fromRaw); parseErr != nil {
@@ -75,6 +83,10 @@ func (p *backupParams) createBackup(grpcAddress string) error {
 		return err
 	}
 
+	if p.snapshot {
+		return p.createSnapshot(connection)
+	}
+
 	// resFrom and resTo represents the range of blocks that can be included in the file
 	resFrom, resTo, err := archive.CreateBackup(
 		connection,
@@ -96,7 +108,32 @@ func (p *backupParams) createBackup(grpcAddress string) error {
 	return nil
 }
 
+func (p *backupParams) createSnapshot(connection *grpc.ClientConn) error {
+	height, err := archive.CreateSnapshot(
+		connection,
+		hclog.New(&hclog.LoggerOptions{
+			Name:  "backup",
+			Level: hclog.LevelFromString("INFO"),
+		}),
+		p.out,
+	)
+	if err != nil {
+		return err
+	}
+
+	p.resTo = height
+
+	return nil
+}
+
 func (p *backupParams) getResult() command.CommandResult {
+	if p.snapshot {
+		return &SnapshotResult{
+			Height: p.resTo,
+			Out:    p.out,
+		}
+	}
+
 	return &BackupResult{
 		From: p.resFrom,
 		To:   p.resTo,
@@ -25,3 +25,21 @@ func (r *BackupResult) GetOutput() string {
 
 	return buffer.String()
 }
+
+type SnapshotResult struct {
+	Height uint64 `json:"height"`
+	Out    string `json:"out"`
+}
+
+func (r *SnapshotResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[BACKUP]\n")
+	buffer.WriteString("Exported snapshot file successfully:\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("File|%s", r.Out),
+		fmt.Sprintf("Height|%d", r.Height),
+	}))
+
+	return buffer.String()
+}
@@ -0,0 +1,86 @@
+package rollback
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	rollbackCmd := &cobra.Command{
+		Use: "rollback",
+		Short: "Rolls the chain of the data directory of a stopped node back to the block, deleting the blocks " +
+			"above it. The state of the block must not be pruned",
+		Args:    cobra.NoArgs,
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(rollbackCmd)
+	setRequiredFlags(rollbackCmd)
+
+	return rollbackCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64Var(
+		&params.to,
+		toFlag,
+		0,
+		"the number of the block to roll the chain back to, it becomes the head",
+	)
+
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dataDirFlag,
+		"",
+		"the data directory of the node to roll back",
+	)
+
+	cmd.Flags().StringVar(
+		&params.genesisPath,
+		genesisPathFlag,
+		"./genesis.json",
+		"the genesis file of the chain",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.confirmed,
+		yesFlag,
+		false,
+		"confirms the blocks above the target are deleted",
+	)
+
+	cmd.Flags().StringVar(
+		&params.logLevel,
+		command.LogLevelFlag,
+		"INFO",
+		"the log level for console output",
+	)
+}
+
+func setRequiredFlags(cmd *cobra.Command) {
+	for _, requiredFlag := range params.getRequiredFlags() {
+		_ = cmd.MarkFlagRequired(requiredFlag)
+	}
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	if err := params.validateFlags(); err != nil {
+		return err
+	}
+
+	return params.initGenesisConfig()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.rollbackChain(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
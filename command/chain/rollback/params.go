@@ -0,0 +1,92 @@
+package rollback
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/server"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	toFlag          = "to"
+	dataDirFlag     = "data-dir"
+	genesisPathFlag = "chain"
+	yesFlag         = "yes"
+)
+
+var (
+	params = &rollbackParams{}
+)
+
+var (
+	errNotConfirmed = errors.New("the rollback must be confirmed with --yes")
+)
+
+type rollbackParams struct {
+	to          uint64
+	dataDir     string
+	genesisPath string
+	confirmed   bool
+	logLevel    string
+
+	genesisConfig *chain.Chain
+
+	result *blockchain.RollbackResult
+}
+
+func (p *rollbackParams) getRequiredFlags() []string {
+	return []string{
+		toFlag,
+		dataDirFlag,
+	}
+}
+
+func (p *rollbackParams) validateFlags() error {
+	if !p.confirmed {
+		return fmt.Errorf("%w, it deletes the blocks above %d", errNotConfirmed, p.to)
+	}
+
+	return nil
+}
+
+func (p *rollbackParams) initGenesisConfig() error {
+	var parseErr error
+
+	if p.genesisConfig, parseErr = chain.Import(
+		p.genesisPath,
+	); parseErr != nil {
+		return parseErr
+	}
+
+	return nil
+}
+
+func (p *rollbackParams) rollbackChain() error {
+	result, err := server.RollbackChain(&server.Config{
+		Chain:    p.genesisConfig,
+		DataDir:  p.dataDir,
+		LogLevel: hclog.LevelFromString(p.logLevel),
+	}, p.to)
+	if err != nil {
+		return err
+	}
+
+	p.result = result
+
+	return nil
+}
+
+func (p *rollbackParams) getResult() command.CommandResult {
+	return &RollbackResult{
+		From:      p.result.From,
+		To:        p.result.To,
+		Head:      p.result.Head.String(),
+		Blocks:    p.result.Blocks,
+		Forks:     p.result.Forks,
+		TxLookups: p.result.TxLookups,
+	}
+}
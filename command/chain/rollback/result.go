@@ -0,0 +1,35 @@
+package rollback
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type RollbackResult struct {
+	From      uint64 `json:"from"`
+	To        uint64 `json:"to"`
+	Head      string `json:"head"`
+	Blocks    uint64 `json:"blocks"`
+	Forks     uint64 `json:"forks"`
+	TxLookups uint64 `json:"txLookups"`
+}
+
+func (r *RollbackResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[CHAIN ROLLBACK]\n")
+	buffer.WriteString("Rolled the chain back successfully:\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("From|%d", r.From),
+		fmt.Sprintf("To|%d", r.To),
+		fmt.Sprintf("Head|%s", r.Head),
+		fmt.Sprintf("Canonical blocks removed|%d", r.Blocks),
+		fmt.Sprintf("Fork blocks removed|%d", r.Forks),
+		fmt.Sprintf("Transaction lookups removed|%d", r.TxLookups),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
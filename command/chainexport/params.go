@@ -0,0 +1,115 @@
+package chainexport
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/archive"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/engine"
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	dataDirFlag = "data-dir"
+	outFlag     = "out"
+	fromFlag    = "from"
+	toFlag      = "to"
+)
+
+var (
+	params = &exportParams{}
+)
+
+var (
+	errDecodeRange  = errors.New("unable to decode range value")
+	errInvalidRange = errors.New(`invalid "to" value; must be >= "from"`)
+)
+
+type exportParams struct {
+	dataDir string
+	out     string
+
+	fromRaw string
+	toRaw   string
+
+	from uint64
+	to   *uint64
+
+	resFrom uint64
+	resTo   uint64
+}
+
+func (p *exportParams) validateFlags() error {
+	var parseErr error
+
+	if p.from, parseErr = types.ParseUint64orHex(&p.fromRaw); parseErr != nil {
+		return errDecodeRange
+	}
+
+	if p.toRaw != "" {
+		var parsedTo uint64
+
+		if parsedTo, parseErr = types.ParseUint64orHex(&p.toRaw); parseErr != nil {
+			return errDecodeRange
+		}
+
+		if p.from > parsedTo {
+			return errInvalidRange
+		}
+
+		p.to = &parsedTo
+	}
+
+	return nil
+}
+
+func (p *exportParams) getRequiredFlags() []string {
+	return []string{
+		dataDirFlag,
+		outFlag,
+	}
+}
+
+func (p *exportParams) exportChain() error {
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:  "export",
+		Level: hclog.LevelFromString("INFO"),
+	})
+
+	storePath := filepath.Join(p.dataDir, "blockchain")
+
+	// opening the storage would create an empty one otherwise
+	if _, err := os.Stat(storePath); err != nil {
+		return fmt.Errorf("unable to find the blockchain data, %w", err)
+	}
+
+	// the engine of the existing database
+	store, err := engine.NewStorage("", storePath, logger)
+	if err != nil {
+		return err
+	}
+
+	defer store.Close()
+
+	resFrom, resTo, err := archive.ExportChain(store, logger, p.from, p.to, p.out)
+	if err != nil {
+		return err
+	}
+
+	p.resFrom = resFrom
+	p.resTo = resTo
+
+	return nil
+}
+
+func (p *exportParams) getResult() command.CommandResult {
+	return &ExportResult{
+		From: p.resFrom,
+		To:   p.resTo,
+		Out:  p.out,
+	}
+}
@@ -0,0 +1,28 @@
+package chainexport
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type ExportResult struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+	Out  string `json:"out"`
+}
+
+func (r *ExportResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[EXPORT]\n")
+	buffer.WriteString("Exported chain file successfully:\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("File|%s", r.Out),
+		fmt.Sprintf("From|%d", r.From),
+		fmt.Sprintf("To|%d", r.To),
+	}))
+
+	return buffer.String()
+}
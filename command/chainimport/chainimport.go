@@ -0,0 +1,70 @@
+package chainimport
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	importCmd := &cobra.Command{
+		Use: "import [chain file]",
+		Short: "Imports the blocks of a chain file into the data directory of a stopped node. " +
+			"The blocks are verified and executed, the ones already in the chain are skipped",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(importCmd)
+	setRequiredFlags(importCmd)
+
+	return importCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dataDirFlag,
+		"",
+		"the data directory of the node to import the blocks into",
+	)
+
+	cmd.Flags().StringVar(
+		&params.genesisPath,
+		genesisPathFlag,
+		"./genesis.json",
+		"the genesis file of the chain, its genesis block must match the one of the chain file",
+	)
+
+	cmd.Flags().StringVar(
+		&params.logLevel,
+		command.LogLevelFlag,
+		"INFO",
+		"the log level for console output",
+	)
+}
+
+func setRequiredFlags(cmd *cobra.Command) {
+	for _, requiredFlag := range params.getRequiredFlags() {
+		_ = cmd.MarkFlagRequired(requiredFlag)
+	}
+}
+
+func runPreRun(_ *cobra.Command, args []string) error {
+	params.path = args[0]
+
+	return params.initGenesisConfig()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.importChain(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,71 @@
+package chainimport
+
+import (
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/server"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	dataDirFlag     = "data-dir"
+	genesisPathFlag = "chain"
+)
+
+var (
+	params = &importParams{}
+)
+
+type importParams struct {
+	path        string
+	dataDir     string
+	genesisPath string
+	logLevel    string
+
+	genesisConfig *chain.Chain
+
+	resFrom uint64
+	resTo   uint64
+}
+
+func (p *importParams) getRequiredFlags() []string {
+	return []string{
+		dataDirFlag,
+	}
+}
+
+func (p *importParams) initGenesisConfig() error {
+	var parseErr error
+
+	if p.genesisConfig, parseErr = chain.Import(
+		p.genesisPath,
+	); parseErr != nil {
+		return parseErr
+	}
+
+	return nil
+}
+
+func (p *importParams) importChain() error {
+	resFrom, resTo, err := server.ImportChain(&server.Config{
+		Chain:    p.genesisConfig,
+		DataDir:  p.dataDir,
+		LogLevel: hclog.LevelFromString(p.logLevel),
+	}, p.path)
+	if err != nil {
+		return err
+	}
+
+	p.resFrom = resFrom
+	p.resTo = resTo
+
+	return nil
+}
+
+func (p *importParams) getResult() command.CommandResult {
+	return &ImportResult{
+		From: p.resFrom,
+		To:   p.resTo,
+		File: p.path,
+	}
+}
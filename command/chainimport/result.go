@@ -0,0 +1,35 @@
+package chainimport
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type ImportResult struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+	File string `json:"file"`
+}
+
+func (r *ImportResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[IMPORT]\n")
+
+	if r.To == 0 {
+		buffer.WriteString("The chain already has all the blocks of the file\n")
+
+		return buffer.String()
+	}
+
+	buffer.WriteString("Imported chain file successfully:\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("File|%s", r.File),
+		fmt.Sprintf("From|%d", r.From),
+		fmt.Sprintf("To|%d", r.To),
+	}))
+
+	return buffer.String()
+}
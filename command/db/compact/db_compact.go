@@ -0,0 +1,36 @@
+package compact
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	compactCmd := &cobra.Command{
+		Use: "compact",
+		Short: "Compacts the blockchain and the state databases of a running node, discarding the deleted " +
+			"and the overwritten entries. The compaction competes with the node for the disk, " +
+			"so it is meant for a maintenance window. Only the leveldb databases can be compacted",
+		Args: cobra.NoArgs,
+		Run:  runCommand,
+	}
+
+	helper.RegisterGRPCAddressFlag(compactCmd)
+	helper.RegisterGRPCClientFlags(compactCmd)
+
+	return compactCmd
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.compact(helper.GetGRPCAddress(cmd)); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,38 @@
+package compact
+
+import (
+	"context"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/server/proto"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+)
+
+var (
+	params = &compactParams{}
+)
+
+type compactParams struct {
+	databases []*proto.DBCompactResponse_Database
+}
+
+func (p *compactParams) compact(grpcAddress string) error {
+	systemClient, err := helper.GetSystemClientConnection(grpcAddress)
+	if err != nil {
+		return err
+	}
+
+	resp, err := systemClient.DBCompact(context.Background(), &empty.Empty{})
+	if err != nil {
+		return err
+	}
+
+	p.databases = resp.Databases
+
+	return nil
+}
+
+func (p *compactParams) getResult() command.CommandResult {
+	return newDBCompactResult(p.databases)
+}
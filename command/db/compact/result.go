@@ -0,0 +1,50 @@
+package compact
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/server/proto"
+)
+
+type CompactedDatabase struct {
+	Name     string `json:"name"`
+	Duration string `json:"duration"`
+}
+
+type DBCompactResult struct {
+	Databases []CompactedDatabase `json:"databases"`
+}
+
+func newDBCompactResult(databases []*proto.DBCompactResponse_Database) *DBCompactResult {
+	result := &DBCompactResult{
+		Databases: make([]CompactedDatabase, len(databases)),
+	}
+
+	for i, database := range databases {
+		result.Databases[i] = CompactedDatabase{
+			Name:     database.Name,
+			Duration: (time.Duration(database.Duration) * time.Millisecond).String(),
+		}
+	}
+
+	return result
+}
+
+func (r *DBCompactResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[DATABASES COMPACTED]\n")
+
+	rows := make([]string, len(r.Databases))
+	for i, database := range r.Databases {
+		rows[i] = fmt.Sprintf("%s|%s", database.Name, database.Duration)
+	}
+
+	buffer.WriteString(helper.FormatKV(rows))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
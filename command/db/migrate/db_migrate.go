@@ -0,0 +1,61 @@
+package migrate
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use: "migrate",
+		Short: "Copies the blockchain and the state databases of the data directory of a stopped node " +
+			"to another database engine. The original databases are kept as backups next to the new ones",
+		Args:    cobra.NoArgs,
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(migrateCmd)
+	setRequiredFlags(migrateCmd)
+
+	return migrateCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dataDirFlag,
+		"",
+		"the data directory of the node to migrate",
+	)
+
+	cmd.Flags().StringVar(
+		&params.engineRaw,
+		dbEngineFlag,
+		"",
+		"the database engine to migrate to, leveldb or pebble",
+	)
+}
+
+func setRequiredFlags(cmd *cobra.Command) {
+	for _, requiredFlag := range params.getRequiredFlags() {
+		_ = cmd.MarkFlagRequired(requiredFlag)
+	}
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.validateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.migrate(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,103 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/engine"
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	dataDirFlag  = "data-dir"
+	dbEngineFlag = "db-engine"
+)
+
+// databases are the directories of the data dir databases which are migrated
+var databases = []string{"blockchain", "trie"}
+
+var (
+	params = &migrateParams{}
+)
+
+var (
+	errInvalidDBEngine = errors.New("db engine should be either leveldb or pebble")
+)
+
+type migrateParams struct {
+	dataDir   string
+	engineRaw string
+
+	engine engine.Engine
+
+	results []*MigratedDatabase
+}
+
+func (p *migrateParams) validateFlags() error {
+	if !engine.Supported(p.engineRaw) {
+		return errInvalidDBEngine
+	}
+
+	p.engine = engine.Engine(p.engineRaw)
+
+	return nil
+}
+
+func (p *migrateParams) getRequiredFlags() []string {
+	return []string{
+		dataDirFlag,
+		dbEngineFlag,
+	}
+}
+
+func (p *migrateParams) migrate() error {
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:  "migrate",
+		Level: hclog.LevelFromString("INFO"),
+	})
+
+	// all the databases are checked before any of them is migrated
+	for _, name := range databases {
+		path := filepath.Join(p.dataDir, name)
+
+		detected, ok := engine.Detect(path)
+		if !ok {
+			return fmt.Errorf("unable to find the %s database in %s", name, p.dataDir)
+		}
+
+		if detected == p.engine {
+			return fmt.Errorf("the %s database is already a %s database", name, p.engine)
+		}
+	}
+
+	for _, name := range databases {
+		path := filepath.Join(p.dataDir, name)
+
+		logger.Info("migrating the database", "path", path, "engine", p.engine)
+
+		res, err := engine.Migrate(path, p.engine, func(pairs uint64) {
+			logger.Info("copied the key value pairs", "path", path, "pairs", pairs)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to migrate the %s database: %w", name, err)
+		}
+
+		p.results = append(p.results, &MigratedDatabase{
+			Path:   path,
+			From:   string(res.From),
+			Pairs:  res.Pairs,
+			Backup: res.Backup,
+		})
+	}
+
+	return nil
+}
+
+func (p *migrateParams) getResult() command.CommandResult {
+	return &MigrateResult{
+		Engine:    string(p.engine),
+		Databases: p.results,
+	}
+}
@@ -0,0 +1,40 @@
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type MigratedDatabase struct {
+	Path   string `json:"path"`
+	From   string `json:"from"`
+	Pairs  uint64 `json:"pairs"`
+	Backup string `json:"backup"`
+}
+
+type MigrateResult struct {
+	Engine    string              `json:"engine"`
+	Databases []*MigratedDatabase `json:"databases"`
+}
+
+func (r *MigrateResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[DB MIGRATE]\n")
+	buffer.WriteString(fmt.Sprintf("Migrated the databases to %s successfully:\n", r.Engine))
+
+	for _, db := range r.Databases {
+		buffer.WriteString("\n")
+		buffer.WriteString(helper.FormatKV([]string{
+			fmt.Sprintf("Database|%s", db.Path),
+			fmt.Sprintf("From|%s", db.From),
+			fmt.Sprintf("Key value pairs|%d", db.Pairs),
+			fmt.Sprintf("Backup|%s", db.Backup),
+		}))
+		buffer.WriteString("\n")
+	}
+
+	return buffer.String()
+}
@@ -19,6 +19,14 @@ const (
 	JSONRPCFlag     = "jsonrpc"
 )
 
+// Flags of the TLS and the authentication of the operator GRPC connections
+const (
+	GRPCTLSCAFlag   = "grpc-tls-ca"
+	GRPCTLSCertFlag = "grpc-tls-cert"
+	GRPCTLSKeyFlag  = "grpc-tls-key"
+	GRPCTokenFlag   = "grpc-token"
+)
+
 // Legacy flag that needs to be present to preserve backwards
 // compatibility with running clients
 const (
@@ -0,0 +1,38 @@
+package diff
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	genesisDiffCmd := &cobra.Command{
+		Use: "diff [genesis file] [genesis file]",
+		Short: "Reports the fields that differ between two genesis files, along with their genesis hashes. " +
+			"The formatting and the order of the fields are ignored",
+		Args:    cobra.ExactArgs(2),
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	return genesisDiffCmd
+}
+
+func runPreRun(_ *cobra.Command, args []string) error {
+	params.genesisPaths = [2]string{args[0], args[1]}
+
+	return params.initChains()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.diffChains(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
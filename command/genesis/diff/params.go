@@ -0,0 +1,136 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	params = &diffParams{}
+)
+
+type diffParams struct {
+	genesisPaths [2]string
+
+	genesisConfigs [2]*chain.Chain
+	hashes         [2]types.Hash
+	differences    []FieldDiff
+}
+
+func (p *diffParams) initChains() error {
+	for i, genesisPath := range p.genesisPaths {
+		cc, err := chain.Import(genesisPath)
+		if err != nil {
+			return fmt.Errorf(
+				"failed to load chain config from %s: %w",
+				genesisPath,
+				err,
+			)
+		}
+
+		p.genesisConfigs[i] = cc
+	}
+
+	return nil
+}
+
+func (p *diffParams) diffChains() error {
+	// The configs are compared in their canonical encoding, so the formatting
+	// and the order of the fields of the files don't matter
+	var values [2]interface{}
+
+	for i, cc := range p.genesisConfigs {
+		raw, err := json.Marshal(cc)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", p.genesisPaths[i], err)
+		}
+
+		if err := json.Unmarshal(raw, &values[i]); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", p.genesisPaths[i], err)
+		}
+	}
+
+	p.differences = diffValues("", values[0], values[1])
+
+	for i, cc := range p.genesisConfigs {
+		hash, err := helper.ComputeGenesisHash(cc)
+		if err != nil {
+			return fmt.Errorf("failed to compute the genesis hash of %s: %w", p.genesisPaths[i], err)
+		}
+
+		p.hashes[i] = hash
+	}
+
+	return nil
+}
+
+// diffValues returns the differences between the decoded JSON values, the fields of the objects
+// are compared one by one, and the other values as a whole
+func diffValues(field string, a, b interface{}) []FieldDiff {
+	objectA, okA := a.(map[string]interface{})
+	objectB, okB := b.(map[string]interface{})
+
+	if !okA || !okB {
+		if reflect.DeepEqual(a, b) {
+			return nil
+		}
+
+		return []FieldDiff{{Field: field, A: formatValue(a), B: formatValue(b)}}
+	}
+
+	keys := make([]string, 0, len(objectA)+len(objectB))
+
+	for key := range objectA {
+		keys = append(keys, key)
+	}
+
+	for key := range objectB {
+		if _, ok := objectA[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	differences := []FieldDiff{}
+
+	for _, key := range keys {
+		subField := key
+		if field != "" {
+			subField = field + "." + key
+		}
+
+		differences = append(differences, diffValues(subField, objectA[key], objectB[key])...)
+	}
+
+	return differences
+}
+
+// formatValue formats the decoded JSON value, the missing values are empty
+func formatValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		raw, _ := json.Marshal(value)
+
+		return string(raw)
+	}
+}
+
+func (p *diffParams) getResult() command.CommandResult {
+	return &GenesisDiffResult{
+		Chains:      p.genesisPaths,
+		Hashes:      p.hashes,
+		Differences: p.differences,
+	}
+}
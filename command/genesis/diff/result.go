@@ -0,0 +1,56 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// FieldDiff is a field of the genesis files with different values, empty if the field is missing
+type FieldDiff struct {
+	Field string `json:"field"`
+	A     string `json:"a"`
+	B     string `json:"b"`
+}
+
+type GenesisDiffResult struct {
+	Chains      [2]string     `json:"chains"`
+	Hashes      [2]types.Hash `json:"hashes"`
+	Differences []FieldDiff   `json:"differences"`
+}
+
+func (r *GenesisDiffResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[GENESIS DIFF]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Chain A|%s", r.Chains[0]),
+		fmt.Sprintf("Hash A|%s", r.Hashes[0]),
+		fmt.Sprintf("Chain B|%s", r.Chains[1]),
+		fmt.Sprintf("Hash B|%s", r.Hashes[1]),
+		fmt.Sprintf("Same genesis block|%t", r.Hashes[0] == r.Hashes[1]),
+	}))
+	buffer.WriteString("\n")
+
+	buffer.WriteString("\n[DIFFERENCES]\n")
+
+	if len(r.Differences) == 0 {
+		buffer.WriteString("No differences\n")
+
+		return buffer.String()
+	}
+
+	rows := make([]string, len(r.Differences)+1)
+	rows[0] = "Field|A|B"
+
+	for i, difference := range r.Differences {
+		rows[i+1] = fmt.Sprintf("%s|%s|%s", difference.Field, difference.A, difference.B)
+	}
+
+	buffer.WriteString(helper.FormatList(rows))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
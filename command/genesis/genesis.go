@@ -3,6 +3,9 @@ package genesis
 import (
 	"fmt"
 	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/genesis/diff"
+	"github.com/0xPolygon/polygon-edge/command/genesis/hash"
+	"github.com/0xPolygon/polygon-edge/command/genesis/predeploy"
 	"github.com/0xPolygon/polygon-edge/command/helper"
 	"github.com/0xPolygon/polygon-edge/consensus/ibft"
 	"github.com/0xPolygon/polygon-edge/helper/common"
@@ -19,6 +22,15 @@ func GetCommand() *cobra.Command {
 
 	helper.RegisterGRPCAddressFlag(genesisCmd)
 
+	genesisCmd.AddCommand(
+		// genesis predeploy
+		predeploy.GetCommand(),
+		// genesis hash
+		hash.GetCommand(),
+		// genesis diff
+		diff.GetCommand(),
+	)
+
 	setFlags(genesisCmd)
 	setLegacyFlags(genesisCmd)
 	setRequiredFlags(genesisCmd)
@@ -82,6 +94,14 @@ func setFlags(cmd *cobra.Command) {
 		"multiAddr URL for p2p discovery bootstrap. This flag can be used multiple times",
 	)
 
+	cmd.Flags().StringVar(
+		&params.discoveryDNS,
+		discoveryDNSFlag,
+		"",
+		"the domain publishing the bootnode multiaddrs or enodes in its TXT records, "+
+			"resolved by the nodes in addition to the bootnodes",
+	)
+
 	cmd.Flags().StringArrayVar(
 		&params.ibftValidatorsRaw,
 		ibftValidatorFlag,
@@ -118,6 +138,43 @@ func setFlags(cmd *cobra.Command) {
 		),
 	)
 
+	cmd.Flags().StringVar(
+		&params.proposerSelectorRaw,
+		proposerSelectorFlag,
+		ibft.RoundRobin.String(),
+		fmt.Sprintf(
+			"the IBFT proposer selection (%s, %s). Default: %s",
+			ibft.RoundRobin,
+			ibft.StakeWeighted,
+			ibft.RoundRobin,
+		),
+	)
+
+	cmd.Flags().StringVar(
+		&params.proposerVerificationRaw,
+		proposerVerifyFlag,
+		ibft.LenientProposerVerification.String(),
+		fmt.Sprintf(
+			"the IBFT verification of the block proposers (%s, %s), strict only accepts the blocks "+
+				"proposed in turn. Default: %s",
+			ibft.LenientProposerVerification,
+			ibft.StrictProposerVerification,
+			ibft.LenientProposerVerification,
+		),
+	)
+
+	cmd.Flags().StringVar(
+		&params.protocolRaw,
+		protocolFlag,
+		ibft.IBFTProtocol.String(),
+		fmt.Sprintf(
+			"the IBFT extra data, seal and message format (%s, %s). Default: %s",
+			ibft.IBFTProtocol,
+			ibft.QBFTProtocol,
+			ibft.IBFTProtocol,
+		),
+	)
+
 	cmd.Flags().Uint64Var(
 		&params.blockGasLimit,
 		blockGasLimitFlag,
@@ -127,6 +184,37 @@ func setFlags(cmd *cobra.Command) {
 			command.DefaultGenesisGasLimit,
 		),
 	)
+
+	cmd.Flags().Uint64Var(
+		&params.baseFee,
+		baseFeeFlag,
+		0,
+		"the base fee of the genesis block, enables the EIP-1559 fork from the genesis. "+
+			"Defaults to the fork being disabled",
+	)
+
+	cmd.Flags().StringVar(
+		&params.feeCollectorRaw,
+		feeCollectorFlag,
+		"",
+		"the address credited with the base fees, instead of burning them. Requires the base fee",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.maxBlockSize,
+		maxBlockSizeFlag,
+		0,
+		"the maximum total size in bytes of the RLP-encoded transactions of a block, "+
+			"enforced from the genesis. Default: no limit",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.maxTxSize,
+		maxTxSizeFlag,
+		0,
+		"the maximum size in bytes of a RLP-encoded transaction, enforced from the genesis. Default: no limit",
+	)
+
 	cmd.Flags().Uint64Var(
 		&params.minNumValidators,
 		minValidatorCount,
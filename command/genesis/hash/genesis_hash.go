@@ -0,0 +1,51 @@
+package hash
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	genesisHashCmd := &cobra.Command{
+		Use: "hash",
+		Short: "Computes the hash of the genesis block of the genesis file. " +
+			"The nodes only connect with the peers of the same genesis hash",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(genesisHashCmd)
+
+	return genesisHashCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.genesisPath,
+		chainFlag,
+		fmt.Sprintf("./%s", command.DefaultGenesisFileName),
+		fmt.Sprintf(
+			"the genesis file to hash. Default: ./%s",
+			command.DefaultGenesisFileName,
+		),
+	)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.initChain()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.computeHash(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
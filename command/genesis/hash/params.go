@@ -0,0 +1,59 @@
+package hash
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	chainFlag = "chain"
+)
+
+var (
+	params = &hashParams{}
+)
+
+type hashParams struct {
+	genesisPath string
+
+	genesisConfig *chain.Chain
+	hash          types.Hash
+}
+
+func (p *hashParams) initChain() error {
+	cc, err := chain.Import(p.genesisPath)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to load chain config from %s: %w",
+			p.genesisPath,
+			err,
+		)
+	}
+
+	p.genesisConfig = cc
+
+	return nil
+}
+
+func (p *hashParams) computeHash() error {
+	hash, err := helper.ComputeGenesisHash(p.genesisConfig)
+	if err != nil {
+		return fmt.Errorf("failed to compute the genesis hash: %w", err)
+	}
+
+	p.hash = hash
+
+	return nil
+}
+
+func (p *hashParams) getResult() command.CommandResult {
+	return &GenesisHashResult{
+		Chain:   p.genesisPath,
+		ChainID: p.genesisConfig.Params.ChainID,
+		Hash:    p.hash,
+	}
+}
@@ -0,0 +1,29 @@
+package hash
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+type GenesisHashResult struct {
+	Chain   string     `json:"chain"`
+	ChainID int        `json:"chainID"`
+	Hash    types.Hash `json:"hash"`
+}
+
+func (r *GenesisHashResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[GENESIS HASH]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Chain|%s", r.Chain),
+		fmt.Sprintf("Chain ID|%d", r.ChainID),
+		fmt.Sprintf("Hash|%s", r.Hash),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
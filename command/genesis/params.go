@@ -9,6 +9,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/command/helper"
 	"github.com/0xPolygon/polygon-edge/consensus/ibft"
 	"github.com/0xPolygon/polygon-edge/contracts/staking"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
 	stakingHelper "github.com/0xPolygon/polygon-edge/helper/staking"
 	"github.com/0xPolygon/polygon-edge/server"
 	"github.com/0xPolygon/polygon-edge/types"
@@ -26,6 +27,14 @@ const (
 	posFlag                 = "pos"
 	minValidatorCount       = "min-validator-count"
 	maxValidatorCount       = "max-validator-count"
+	proposerSelectorFlag    = "ibft-proposer-selector"
+	proposerVerifyFlag      = "ibft-proposer-verification"
+	protocolFlag            = "ibft-protocol"
+	discoveryDNSFlag        = "discovery-dns"
+	baseFeeFlag             = "base-fee"
+	feeCollectorFlag        = "fee-collector"
+	maxBlockSizeFlag        = "max-block-size"
+	maxTxSizeFlag           = "max-tx-size"
 )
 
 // Legacy flags that need to be preserved for running clients
@@ -42,8 +51,11 @@ var (
 	errValidatorsSpecifiedIncorrectly = errors.New("validator information specified through mutually exclusive flags")
 	errValidatorNumberExceedsMax      = errors.New("validator number exceeds max validator number")
 	errUnsupportedConsensus           = errors.New("specified consensusRaw not supported")
-	errMissingBootnode                = errors.New("at least 1 bootnode is required")
+	errMissingBootnode                = errors.New("at least 1 bootnode or the discovery DNS is required")
 	errInvalidEpochSize               = errors.New("epoch size must be greater than 1")
+	errInvalidFeeCollector            = errors.New("invalid fee collector address")
+	errFeeCollectorWithoutBaseFee     = errors.New("the fee collector requires the base fee to be set")
+	errStakeWeightedWithoutPoS        = errors.New("the stake-weighted proposer selector requires the PoS mechanism")
 )
 
 type genesisParams struct {
@@ -53,6 +65,7 @@ type genesisParams struct {
 	validatorPrefixPath string
 	premine             []string
 	bootnodes           []string
+	discoveryDNS        string
 	ibftValidators      []types.Address
 
 	ibftValidatorsRaw []string
@@ -62,9 +75,19 @@ type genesisParams struct {
 	blockGasLimit uint64
 	isPos         bool
 
+	baseFee         uint64
+	feeCollectorRaw string
+
+	maxBlockSize uint64
+	maxTxSize    uint64
+
 	minNumValidators uint64
 	maxNumValidators uint64
 
+	proposerSelectorRaw     string
+	proposerVerificationRaw string
+	protocolRaw             string
+
 	extraData []byte
 	consensus server.ConsensusType
 
@@ -75,7 +98,7 @@ type genesisParams struct {
 
 func (p *genesisParams) validateFlags() error {
 	// Check if the correct number of bootnodes is provided
-	if len(p.bootnodes) < 1 {
+	if len(p.bootnodes) < 1 && p.discoveryDNS == "" {
 		return errMissingBootnode
 	}
 
@@ -111,11 +134,43 @@ func (p *genesisParams) validateFlags() error {
 		return errInvalidEpochSize
 	}
 
+	// Check that the proposer selector is supported
+	if p.isIBFTConsensus() {
+		selectorType, err := ibft.ParseProposerSelectorType(p.proposerSelectorRaw)
+		if err != nil {
+			return err
+		}
+
+		// the stakes are only read from the Staking SC of the PoS chains
+		if selectorType == ibft.StakeWeighted && !p.isPos {
+			return errStakeWeightedWithoutPoS
+		}
+
+		if _, err := ibft.ParseProposerVerification(p.proposerVerificationRaw); err != nil {
+			return err
+		}
+
+		if _, err := ibft.ParseProtocol(p.protocolRaw); err != nil {
+			return err
+		}
+	}
+
 	// Validate min and max validators number
 	if err := command.ValidateMinMaxValidatorsNumber(p.minNumValidators, p.maxNumValidators); err != nil {
 		return err
 	}
 
+	// Check that the fee collector is an address, collecting the base fees of the EIP-1559 fork
+	if p.feeCollectorRaw != "" {
+		if p.baseFee == 0 {
+			return errFeeCollectorWithoutBaseFee
+		}
+
+		if buf, err := hex.DecodeHex(p.feeCollectorRaw); err != nil || len(buf) != types.AddressLength {
+			return errInvalidFeeCollector
+		}
+	}
+
 	return nil
 }
 
@@ -211,6 +266,14 @@ func (p *genesisParams) initIBFTExtraData() {
 		CommittedSeal: [][]byte{},
 	}
 
+	// the QBFT extra data has the vanity as its first item
+	if ibft.Protocol(p.protocolRaw) == ibft.QBFTProtocol {
+		ibftExtra.Vanity = make([]byte, ibft.IstanbulExtraVanity)
+		p.extraData = ibftExtra.MarshalRLPTo(nil)
+
+		return
+	}
+
 	p.extraData = make([]byte, ibft.IstanbulExtraVanity)
 	p.extraData = ibftExtra.MarshalRLPTo(p.extraData)
 }
@@ -236,8 +299,11 @@ func (p *genesisParams) initConsensusEngineConfig() {
 func (p *genesisParams) initIBFTEngineMap(mechanism ibft.MechanismType) {
 	p.consensusEngineConfig = map[string]interface{}{
 		string(server.IBFTConsensus): map[string]interface{}{
-			"type":      mechanism,
-			"epochSize": p.epochSize,
+			"type":                 mechanism,
+			"epochSize":            p.epochSize,
+			"proposerSelector":     p.proposerSelectorRaw,
+			"proposerVerification": p.proposerVerificationRaw,
+			"protocol":             p.protocolRaw,
 		},
 	}
 }
@@ -266,13 +332,39 @@ func (p *genesisParams) initGenesisConfig() error {
 			Alloc:      map[types.Address]*chain.GenesisAccount{},
 			ExtraData:  p.extraData,
 			GasUsed:    command.DefaultGenesisGasUsed,
+			BaseFee:    p.baseFee,
 		},
 		Params: &chain.Params{
 			ChainID: int(p.chainID),
 			Forks:   chain.AllForksEnabled,
 			Engine:  p.consensusEngineConfig,
 		},
-		Bootnodes: p.bootnodes,
+		Bootnodes:    p.bootnodes,
+		DiscoveryDNS: p.discoveryDNS,
+	}
+
+	// The base fee enables the EIP-1559 fork from the genesis, and the size limits enable their fork
+	if p.baseFee != 0 || p.maxBlockSize != 0 || p.maxTxSize != 0 {
+		forks := *chain.AllForksEnabled
+
+		if p.baseFee != 0 {
+			forks.EIP1559 = chain.NewFork(0)
+		}
+
+		if p.maxBlockSize != 0 || p.maxTxSize != 0 {
+			forks.SizeLimits = chain.NewFork(0)
+		}
+
+		chainConfig.Params.Forks = &forks
+	}
+
+	chainConfig.Params.MaxBlockSize = p.maxBlockSize
+	chainConfig.Params.MaxTxSize = p.maxTxSize
+
+	if p.feeCollectorRaw != "" {
+		chainConfig.Params.FeeCollector = []chain.FeeCollectorFork{
+			{Block: 0, Address: types.StringToAddress(p.feeCollectorRaw)},
+		}
 	}
 
 	// Predeploy staking smart contract if needed
@@ -0,0 +1,33 @@
+package genesis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/consensus/ibft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFlags_ProposerSelector(t *testing.T) {
+	newParams := func(selector ibft.ProposerSelectorType, isPos bool) *genesisParams {
+		return &genesisParams{
+			genesisPath:             filepath.Join(t.TempDir(), "genesis.json"),
+			consensusRaw:            "ibft",
+			bootnodes:               []string{"/ip4/127.0.0.1/tcp/1478/p2p/16Uiu2HAmJxxH1tScDX2rLGSU9exnuvZKNM9SoK3v315azp68DLPW"},
+			ibftValidatorsRaw:       []string{"0x1"},
+			epochSize:               10,
+			isPos:                   isPos,
+			minNumValidators:        1,
+			maxNumValidators:        10,
+			proposerSelectorRaw:     selector.String(),
+			proposerVerificationRaw: string(ibft.LenientProposerVerification),
+			protocolRaw:             string(ibft.IBFTProtocol),
+		}
+	}
+
+	assert.NoError(t, newParams(ibft.RoundRobin, false).validateFlags())
+	assert.NoError(t, newParams(ibft.StakeWeighted, true).validateFlags())
+
+	// the stakes are only on the Staking SC of the PoS chains
+	assert.ErrorIs(t, newParams(ibft.StakeWeighted, false).validateFlags(), errStakeWeightedWithoutPoS)
+}
@@ -0,0 +1,86 @@
+package predeploy
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	genesisPredeployCmd := &cobra.Command{
+		Use: "predeploy",
+		Short: "Runs the constructor of the contract artifact, " +
+			"and adds the resulting code and storage to the genesis alloc",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(genesisPredeployCmd)
+	setRequiredFlags(genesisPredeployCmd)
+
+	return genesisPredeployCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.genesisPath,
+		chainFlag,
+		fmt.Sprintf("./%s", command.DefaultGenesisFileName),
+		fmt.Sprintf(
+			"the genesis file to update. Default: ./%s",
+			command.DefaultGenesisFileName,
+		),
+	)
+
+	cmd.Flags().StringVar(
+		&params.artifactPath,
+		artifactFlag,
+		"",
+		"the path of the contract artifact JSON, with the ABI and the creation bytecode",
+	)
+
+	cmd.Flags().StringVar(
+		&params.addressRaw,
+		predeployAddressFlag,
+		"",
+		"the address the contract is deployed at",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.constructorArgs,
+		constructorArgsFlag,
+		[]string{},
+		"the constructor arguments, in the order of the constructor inputs. "+
+			"The array arguments are JSON arrays of strings. This flag can be used multiple times",
+	)
+}
+
+func setRequiredFlags(cmd *cobra.Command) {
+	for _, requiredFlag := range params.getRequiredFlags() {
+		_ = cmd.MarkFlagRequired(requiredFlag)
+	}
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.initRawParams()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.updateGenesisConfig(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	if err := params.overrideGenesisConfig(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,145 @@
+package predeploy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/helper/predeployment"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	chainFlag            = "chain"
+	artifactFlag         = "artifact"
+	predeployAddressFlag = "predeploy-address"
+	constructorArgsFlag  = "constructor-args"
+)
+
+var (
+	errInvalidPredeployAddress = errors.New("invalid predeploy address")
+	errPredeployAddressInUse   = errors.New("the predeploy address is already in the genesis alloc")
+)
+
+var (
+	params = &predeployParams{}
+)
+
+type predeployParams struct {
+	genesisPath     string
+	artifactPath    string
+	addressRaw      string
+	constructorArgs []string
+
+	address       types.Address
+	artifact      *predeployment.ContractArtifact
+	genesisConfig *chain.Chain
+	account       *chain.GenesisAccount
+}
+
+func (p *predeployParams) getRequiredFlags() []string {
+	return []string{
+		artifactFlag,
+		predeployAddressFlag,
+	}
+}
+
+func (p *predeployParams) initRawParams() error {
+	if err := p.initAddress(); err != nil {
+		return err
+	}
+
+	if err := p.initArtifact(); err != nil {
+		return err
+	}
+
+	return p.initChain()
+}
+
+func (p *predeployParams) initAddress() error {
+	if err := p.address.UnmarshalText([]byte(p.addressRaw)); err != nil {
+		return fmt.Errorf("%w, %v", errInvalidPredeployAddress, err)
+	}
+
+	if p.address == types.ZeroAddress {
+		return errInvalidPredeployAddress
+	}
+
+	return nil
+}
+
+func (p *predeployParams) initArtifact() error {
+	artifact, err := predeployment.ReadContractArtifact(p.artifactPath)
+	if err != nil {
+		return fmt.Errorf("failed to read the contract artifact from %s: %w", p.artifactPath, err)
+	}
+
+	p.artifact = artifact
+
+	return nil
+}
+
+func (p *predeployParams) initChain() error {
+	cc, err := chain.Import(p.genesisPath)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to load chain config from %s: %w",
+			p.genesisPath,
+			err,
+		)
+	}
+
+	p.genesisConfig = cc
+
+	return nil
+}
+
+func (p *predeployParams) updateGenesisConfig() error {
+	if p.genesisConfig.Genesis.Alloc == nil {
+		p.genesisConfig.Genesis.Alloc = map[types.Address]*chain.GenesisAccount{}
+	}
+
+	if _, ok := p.genesisConfig.Genesis.Alloc[p.address]; ok {
+		return errPredeployAddressInUse
+	}
+
+	account, err := predeployment.GenerateGenesisAccount(
+		p.artifact,
+		p.constructorArgs,
+		p.address,
+		p.genesisConfig.Params.ChainID,
+	)
+	if err != nil {
+		return err
+	}
+
+	p.account = account
+	p.genesisConfig.Genesis.Alloc[p.address] = account
+
+	return nil
+}
+
+func (p *predeployParams) overrideGenesisConfig() error {
+	// Remove the current genesis configuration from disk
+	if err := os.Remove(p.genesisPath); err != nil {
+		return err
+	}
+
+	// Save the new genesis configuration
+	return helper.WriteGenesisConfigToDisk(
+		p.genesisConfig,
+		p.genesisPath,
+	)
+}
+
+func (p *predeployParams) getResult() command.CommandResult {
+	return &GenesisPredeployResult{
+		Chain:        p.genesisPath,
+		Address:      p.address,
+		CodeSize:     len(p.account.Code),
+		StorageSlots: len(p.account.Storage),
+	}
+}
@@ -0,0 +1,31 @@
+package predeploy
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+type GenesisPredeployResult struct {
+	Chain        string        `json:"chain"`
+	Address      types.Address `json:"address"`
+	CodeSize     int           `json:"codeSize"`
+	StorageSlots int           `json:"storageSlots"`
+}
+
+func (r *GenesisPredeployResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[GENESIS PREDEPLOY]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Chain|%s", r.Chain),
+		fmt.Sprintf("Address|%s", r.Address),
+		fmt.Sprintf("Code size|%d", r.CodeSize),
+		fmt.Sprintf("Storage slots|%d", r.StorageSlots),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
@@ -1,22 +1,34 @@
 package helper
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/url"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft"
 	ibftOp "github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
 	"github.com/0xPolygon/polygon-edge/server"
 	"github.com/0xPolygon/polygon-edge/server/proto"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
 	txpoolOp "github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/0xPolygon/polygon-edge/helper/common"
@@ -39,10 +51,12 @@ const (
 )
 
 // HandleSignals is a helper method for handling signals sent to the console
-// Like stop, error, etc.
+// Like stop, error, etc. The close callback is given up on after the timeout,
+// or a second signal
 func HandleSignals(
 	closeFn func(),
 	outputter command.OutputFormatter,
+	timeout time.Duration,
 ) error {
 	signalCh := common.GetTerminationSignalCh()
 	sig := <-signalCh
@@ -71,7 +85,7 @@ func HandleSignals(
 	select {
 	case <-signalCh:
 		return errors.New("shutdown by signal channel")
-	case <-time.After(5 * time.Second):
+	case <-time.After(timeout):
 		return errors.New("shutdown by timeout")
 	case <-gracefulCh:
 		return nil
@@ -138,9 +152,35 @@ func GetIBFTOperatorClientConnection(address string) (
 	return ibftOp.NewIbftOperatorClient(conn), nil
 }
 
-// GetGRPCConnection returns a grpc client connection
+// grpcClientConfig holds the TLS and the authentication params of the operator GRPC connections
+type grpcClientConfig struct {
+	tlsCAFile   string
+	tlsCertFile string
+	tlsKeyFile  string
+	token       string
+}
+
+// grpcClient is set by the GRPC client flags of the command
+var grpcClient = &grpcClientConfig{}
+
+// GetGRPCConnection returns a grpc client connection,
+// encrypted and authenticated if the GRPC client flags are set
 func GetGRPCConnection(address string) (*grpc.ClientConn, error) {
-	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	creds, err := grpcClient.transportCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+
+	if grpcClient.token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(&tokenCredentials{
+			token:     grpcClient.token,
+			encrypted: grpcClient.tlsCAFile != "",
+		}))
+	}
+
+	conn, err := grpc.Dial(address, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to server: %w", err)
 	}
@@ -148,6 +188,60 @@ func GetGRPCConnection(address string) (*grpc.ClientConn, error) {
 	return conn, nil
 }
 
+// transportCredentials returns the TLS credentials of the connection if the CA of the server is set
+func (c *grpcClientConfig) transportCredentials() (credentials.TransportCredentials, error) {
+	if c.tlsCAFile == "" {
+		if c.tlsCertFile != "" || c.tlsKeyFile != "" {
+			return nil, errGRPCClientCertWithoutCA
+		}
+
+		return insecure.NewCredentials(), nil
+	}
+
+	rawCA, err := ioutil.ReadFile(c.tlsCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the GRPC CA certificate, %w", err)
+	}
+
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(rawCA) {
+		return nil, errInvalidGRPCCA
+	}
+
+	tlsConfig := &tls.Config{
+		RootCAs:    rootCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if c.tlsCertFile != "" || c.tlsKeyFile != "" {
+		// the client certificate of the mTLS authentication
+		cert, err := tls.LoadX509KeyPair(c.tlsCertFile, c.tlsKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the GRPC client key pair, %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// tokenCredentials adds the operator bearer token to the GRPC requests
+type tokenCredentials struct {
+	token     string
+	encrypted bool
+}
+
+func (c *tokenCredentials) GetRequestMetadata(_ context.Context, _ ...string) (map[string]string, error) {
+	return map[string]string{
+		server.GRPCAuthHeader: server.GRPCAuthScheme + c.token,
+	}, nil
+}
+
+func (c *tokenCredentials) RequireTransportSecurity() bool {
+	return c.encrypted
+}
+
 // GetGRPCAddress extracts the set GRPC address
 func GetGRPCAddress(cmd *cobra.Command) string {
 	if cmd.Flags().Changed(command.GRPCAddressFlagLEGACY) {
@@ -181,6 +275,38 @@ func RegisterGRPCAddressFlag(cmd *cobra.Command) {
 	)
 }
 
+// RegisterGRPCClientFlags registers the TLS and the authentication flags
+// of the operator GRPC connections for all child commands
+func RegisterGRPCClientFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(
+		&grpcClient.tlsCAFile,
+		command.GRPCTLSCAFlag,
+		"",
+		"the CA certificate the GRPC server is verified with, the connection is not encrypted if not set",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&grpcClient.tlsCertFile,
+		command.GRPCTLSCertFlag,
+		"",
+		"the client certificate of the GRPC connection, if the server requires the client certificates",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&grpcClient.tlsKeyFile,
+		command.GRPCTLSKeyFlag,
+		"",
+		"the client key of the GRPC connection",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&grpcClient.token,
+		command.GRPCTokenFlag,
+		"",
+		"the bearer token of the GRPC operator methods, the admin token for the sensitive methods",
+	)
+}
+
 // RegisterLegacyGRPCAddressFlag registers the legacy GRPC address flag for all child commands
 func RegisterLegacyGRPCAddressFlag(cmd *cobra.Command) {
 	cmd.PersistentFlags().String(
@@ -242,3 +368,106 @@ func WriteGenesisConfigToDisk(genesisConfig *chain.Chain, genesisPath string) er
 
 	return nil
 }
+
+// ComputeGenesisHash computes the hash of the genesis block of the chain, as the server does on its first start.
+// The state root of the alloc is computed on an in-memory trie, and the header is hashed as the consensus does
+func ComputeGenesisHash(genesisConfig *chain.Chain) (types.Hash, error) {
+	defaultHeaderHash := types.HeaderHash
+	defer func() {
+		types.HeaderHash = defaultHeaderHash
+	}()
+
+	if engineName := genesisConfig.Params.GetEngine(); engineName == string(server.IBFTConsensus) {
+		engineConfig, ok := genesisConfig.Params.Engine[engineName].(map[string]interface{})
+		if !ok {
+			engineConfig = map[string]interface{}{}
+		}
+
+		if err := ibft.SetHeaderHash(engineConfig); err != nil {
+			return types.Hash{}, err
+		}
+	}
+
+	executor := state.NewExecutor(
+		genesisConfig.Params,
+		itrie.NewState(itrie.NewMemoryStorage()),
+		hclog.NewNullLogger(),
+	)
+
+	genesisConfig.Genesis.StateRoot = executor.WriteGenesis(genesisConfig.Genesis.Alloc)
+
+	return genesisConfig.Genesis.Hash(), nil
+}
+
+var (
+	errGRPCClientCertWithoutCA = errors.New("the GRPC client certificate requires the CA certificate of the server")
+	errInvalidGRPCCA           = errors.New("no certificates found in the GRPC CA file")
+)
+
+var (
+	errEmptyPassphrase    = errors.New("the passphrase of the secrets can't be empty")
+	errPassphraseMismatch = errors.New("the passphrases don't match")
+)
+
+// ReadPassphraseFile reads the passphrase of the encrypted secrets from the first line of the file
+func ReadPassphraseFile(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the password file, %w", err)
+	}
+
+	passphrase := strings.TrimRight(strings.SplitN(string(data), "\n", 2)[0], "\r")
+	if passphrase == "" {
+		return "", errEmptyPassphrase
+	}
+
+	return passphrase, nil
+}
+
+// IsTerminal checks if the standard input is a terminal the passphrase can be prompted on
+func IsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// PromptPassphrase reads the passphrase of the encrypted secrets from the terminal without echoing it.
+// The passphrase is asked twice if it's a new one
+func PromptPassphrase(confirm bool) (string, error) {
+	passphrase, err := promptLine("Passphrase of the secrets: ")
+	if err != nil {
+		return "", err
+	}
+
+	if passphrase == "" {
+		return "", errEmptyPassphrase
+	}
+
+	if !confirm {
+		return passphrase, nil
+	}
+
+	repeated, err := promptLine("Repeat the passphrase: ")
+	if err != nil {
+		return "", err
+	}
+
+	if repeated != passphrase {
+		return "", errPassphraseMismatch
+	}
+
+	return passphrase, nil
+}
+
+// promptLine prints the prompt to the standard error, so it isn't mixed with the command output
+func promptLine(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+
+	line, err := term.ReadPassword(int(os.Stdin.Fd()))
+
+	fmt.Fprintln(os.Stderr)
+
+	if err != nil {
+		return "", fmt.Errorf("unable to read the passphrase, %w", err)
+	}
+
+	return string(line), nil
+}
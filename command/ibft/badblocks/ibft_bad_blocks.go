@@ -0,0 +1,46 @@
+package badblocks
+
+import (
+	"context"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	ibftOp "github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/spf13/cobra"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+)
+
+func GetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "bad-blocks",
+		Short: "Returns the last blocks rejected by the node, with the reason, the RLP and the decoded IBFT extra data",
+		Run:   runCommand,
+	}
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	badBlocksResponse, err := getIBFTBadBlocks(helper.GetGRPCAddress(cmd))
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(
+		newIBFTBadBlocksResult(badBlocksResponse),
+	)
+}
+
+func getIBFTBadBlocks(grpcAddress string) (*ibftOp.BadBlocksResp, error) {
+	client, err := helper.GetIBFTOperatorClientConnection(
+		grpcAddress,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.BadBlocks(context.Background(), &empty.Empty{})
+}
@@ -0,0 +1,100 @@
+package badblocks
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	ibftOp "github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+)
+
+type IBFTBadBlock struct {
+	Number     uint64   `json:"number"`
+	Hash       string   `json:"hash"`
+	Reason     string   `json:"reason"`
+	Time       string   `json:"time"`
+	RLP        string   `json:"rlp"`
+	Proposer   string   `json:"proposer,omitempty"`
+	Validators []string `json:"validators,omitempty"`
+	Committers []string `json:"committers,omitempty"`
+	Quorum     uint64   `json:"quorum,omitempty"`
+}
+
+type IBFTBadBlocksResult struct {
+	Blocks []IBFTBadBlock `json:"blocks"`
+}
+
+func newIBFTBadBlocksResult(resp *ibftOp.BadBlocksResp) *IBFTBadBlocksResult {
+	res := &IBFTBadBlocksResult{
+		Blocks: make([]IBFTBadBlock, len(resp.Blocks)),
+	}
+
+	for i, b := range resp.Blocks {
+		res.Blocks[i] = IBFTBadBlock{
+			Number: b.Number,
+			Hash:   b.Hash,
+			Reason: b.Reason,
+			Time:   time.Unix(b.Time, 0).UTC().Format(time.RFC3339),
+			RLP:    hex.EncodeToHex(b.Rlp),
+		}
+
+		if b.Extra != nil {
+			res.Blocks[i].Proposer = b.Extra.Proposer
+			res.Blocks[i].Validators = b.Extra.Validators
+			res.Blocks[i].Committers = b.Extra.Committers
+			res.Blocks[i].Quorum = b.Extra.Quorum
+		}
+	}
+
+	return res
+}
+
+func (r *IBFTBadBlocksResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[IBFT BAD BLOCKS]\n")
+
+	if len(r.Blocks) == 0 {
+		buffer.WriteString("No bad blocks found\n")
+
+		return buffer.String()
+	}
+
+	for _, b := range r.Blocks {
+		buffer.WriteString("\n")
+		buffer.WriteString(formatBadBlock(b))
+		buffer.WriteString("\n")
+	}
+
+	return buffer.String()
+}
+
+func formatBadBlock(b IBFTBadBlock) string {
+	proposer := b.Proposer
+	if proposer == "" {
+		proposer = "-"
+	}
+
+	rows := []string{
+		fmt.Sprintf("Block|%d", b.Number),
+		fmt.Sprintf("Hash|%s", b.Hash),
+		fmt.Sprintf("Rejected at|%s", b.Time),
+		fmt.Sprintf("Reason|%s", b.Reason),
+		fmt.Sprintf("Proposer|%s", proposer),
+		fmt.Sprintf("Committed seals|%d/%d", len(b.Committers), b.Quorum),
+	}
+
+	for _, validator := range b.Validators {
+		rows = append(rows, fmt.Sprintf("Validator|%s", validator))
+	}
+
+	for _, committer := range b.Committers {
+		rows = append(rows, fmt.Sprintf("Committer|%s", committer))
+	}
+
+	rows = append(rows, fmt.Sprintf("RLP|%s", b.RLP))
+
+	return helper.FormatKV(rows)
+}
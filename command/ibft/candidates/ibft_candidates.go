@@ -11,9 +11,10 @@ import (
 
 func GetCommand() *cobra.Command {
 	return &cobra.Command{
-		Use:   "candidates",
-		Short: "Queries the current set of proposed candidates, as well as candidates that have not been included yet",
-		Run:   runCommand,
+		Use: "candidates",
+		Short: "Queries the current set of proposed candidates, as well as candidates that have not been included yet, " +
+			"and the pending votes of the validators",
+		Run: runCommand,
 	}
 }
 
@@ -21,7 +22,7 @@ func runCommand(cmd *cobra.Command, _ []string) {
 	outputter := command.InitializeOutputter(cmd)
 	defer outputter.WriteOutput()
 
-	candidatesResponse, err := getIBFTCandidates(helper.GetGRPCAddress(cmd))
+	candidatesResponse, votesResponse, err := getIBFTCandidates(helper.GetGRPCAddress(cmd))
 	if err != nil {
 		outputter.SetError(err)
 
@@ -29,17 +30,27 @@ func runCommand(cmd *cobra.Command, _ []string) {
 	}
 
 	outputter.SetCommandResult(
-		newIBFTCandidatesResult(candidatesResponse),
+		newIBFTCandidatesResult(candidatesResponse, votesResponse),
 	)
 }
 
-func getIBFTCandidates(grpcAddress string) (*ibftOp.CandidatesResp, error) {
+func getIBFTCandidates(grpcAddress string) (*ibftOp.CandidatesResp, *ibftOp.PendingVotesResp, error) {
 	client, err := helper.GetIBFTOperatorClientConnection(
 		grpcAddress,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return client.Candidates(context.Background(), &empty.Empty{})
+	candidates, err := client.Candidates(context.Background(), &empty.Empty{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	votes, err := client.ListPendingVotes(context.Background(), &empty.Empty{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return candidates, votes, nil
 }
@@ -13,13 +13,24 @@ type IBFTCandidate struct {
 	Vote    ibftHelper.Vote `json:"vote"`
 }
 
+// IBFTPendingVote is a vote of the latest snapshot that is not tallied yet
+type IBFTPendingVote struct {
+	Address  string          `json:"address"`
+	Proposer string          `json:"proposer"`
+	Vote     ibftHelper.Vote `json:"vote"`
+	Tally    uint64          `json:"tally"`
+	Needed   uint64          `json:"needed"`
+}
+
 type IBFTCandidatesResult struct {
-	Candidates []IBFTCandidate `json:"candidates"`
+	Candidates   []IBFTCandidate   `json:"candidates"`
+	PendingVotes []IBFTPendingVote `json:"pendingVotes"`
 }
 
-func newIBFTCandidatesResult(resp *ibftOp.CandidatesResp) *IBFTCandidatesResult {
+func newIBFTCandidatesResult(resp *ibftOp.CandidatesResp, votes *ibftOp.PendingVotesResp) *IBFTCandidatesResult {
 	res := &IBFTCandidatesResult{
-		Candidates: make([]IBFTCandidate, len(resp.Candidates)),
+		Candidates:   make([]IBFTCandidate, len(resp.Candidates)),
+		PendingVotes: make([]IBFTPendingVote, len(votes.Votes)),
 	}
 
 	for i, c := range resp.Candidates {
@@ -27,6 +38,16 @@ func newIBFTCandidatesResult(resp *ibftOp.CandidatesResp) *IBFTCandidatesResult
 		res.Candidates[i].Vote = ibftHelper.BoolToVote(c.Auth)
 	}
 
+	for i, v := range votes.Votes {
+		res.PendingVotes[i] = IBFTPendingVote{
+			Address:  v.Address,
+			Proposer: v.Proposer,
+			Vote:     ibftHelper.BoolToVote(v.Auth),
+			Tally:    v.Tally,
+			Needed:   v.Needed,
+		}
+	}
+
 	return res
 }
 
@@ -42,6 +63,15 @@ func (r *IBFTCandidatesResult) GetOutput() string {
 		buffer.WriteString(formatCandidates(r.Candidates))
 	}
 
+	buffer.WriteString("\n\n[IBFT PENDING VOTES]\n")
+
+	if num := len(r.PendingVotes); num == 0 {
+		buffer.WriteString("No pending votes found")
+	} else {
+		buffer.WriteString(fmt.Sprintf("Number of pending votes: %d\n\n", num))
+		buffer.WriteString(formatPendingVotes(r.PendingVotes))
+	}
+
 	buffer.WriteString("\n")
 
 	return buffer.String()
@@ -57,3 +87,17 @@ func formatCandidates(candidates []IBFTCandidate) string {
 
 	return helper.FormatKV(generatedCandidates)
 }
+
+func formatPendingVotes(votes []IBFTPendingVote) string {
+	generatedVotes := make([]string, 0, len(votes)+1)
+
+	generatedVotes = append(generatedVotes, "Address|Proposer|Vote|Tally|Needed")
+	for _, v := range votes {
+		generatedVotes = append(
+			generatedVotes,
+			fmt.Sprintf("%s|%s|%s|%d|%d", v.Address, v.Proposer, v.Vote, v.Tally, v.Needed),
+		)
+	}
+
+	return helper.FormatList(generatedVotes)
+}
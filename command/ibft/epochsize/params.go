@@ -0,0 +1,171 @@
+package epochsize
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft"
+	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	chainFlag     = "chain"
+	fromFlag      = "from"
+	epochSizeFlag = "epoch-size"
+)
+
+var (
+	ErrFromPositive = errors.New(`"from" must be positive number`)
+)
+
+var (
+	params = &epochSizeParams{}
+)
+
+type epochSizeParams struct {
+	fromRaw      string
+	epochSizeRaw string
+	genesisPath  string
+
+	from          uint64
+	epochSize     uint64
+	genesisConfig *chain.Chain
+}
+
+func (p *epochSizeParams) getRequiredFlags() []string {
+	return []string{
+		fromFlag,
+		epochSizeFlag,
+	}
+}
+
+func (p *epochSizeParams) initRawParams() error {
+	if err := p.initFrom(); err != nil {
+		return err
+	}
+
+	if err := p.initEpochSize(); err != nil {
+		return err
+	}
+
+	if err := p.initChain(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *epochSizeParams) initFrom() error {
+	from, err := types.ParseUint64orHex(&p.fromRaw)
+	if err != nil {
+		return fmt.Errorf("unable to parse from value, %w", err)
+	}
+
+	if from <= 0 {
+		return ErrFromPositive
+	}
+
+	p.from = from
+
+	return nil
+}
+
+func (p *epochSizeParams) initEpochSize() error {
+	epochSize, err := types.ParseUint64orHex(&p.epochSizeRaw)
+	if err != nil {
+		return fmt.Errorf("unable to parse epoch size value, %w", err)
+	}
+
+	p.epochSize = epochSize
+
+	return nil
+}
+
+func (p *epochSizeParams) initChain() error {
+	cc, err := chain.Import(p.genesisPath)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to load chain config from %s: %w",
+			p.genesisPath,
+			err,
+		)
+	}
+
+	p.genesisConfig = cc
+
+	return nil
+}
+
+func (p *epochSizeParams) updateGenesisConfig() error {
+	return appendEpochSizeOverride(
+		p.genesisConfig,
+		p.from,
+		p.epochSize,
+	)
+}
+
+func (p *epochSizeParams) overrideGenesisConfig() error {
+	// Remove the current genesis configuration from disk
+	if err := os.Remove(p.genesisPath); err != nil {
+		return err
+	}
+
+	// Save the new genesis configuration
+	if err := helper.WriteGenesisConfigToDisk(
+		p.genesisConfig,
+		p.genesisPath,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *epochSizeParams) getResult() command.CommandResult {
+	return &IBFTEpochSizeResult{
+		Chain:     p.genesisPath,
+		From:      common.JSONNumber{Value: p.from},
+		EpochSize: common.JSONNumber{Value: p.epochSize},
+	}
+}
+
+func appendEpochSizeOverride(
+	cc *chain.Chain,
+	from uint64,
+	epochSize uint64,
+) error {
+	ibftConfig, ok := cc.Params.Engine["ibft"].(map[string]interface{})
+	if !ok {
+		return errors.New(`"ibft" setting doesn't exist in "engine" of genesis.json'`)
+	}
+
+	genesisEpochSize, err := ibft.GetEpochSize(ibftConfig)
+	if err != nil {
+		return err
+	}
+
+	schedule, err := ibft.GetEpochSizeSchedule(ibftConfig)
+	if err != nil {
+		return err
+	}
+
+	schedule = append(schedule, ibft.EpochSizeOverride{
+		Block:     common.JSONNumber{Value: from},
+		EpochSize: common.JSONNumber{Value: epochSize},
+	})
+
+	// the new override needs to be activated at an epoch block, after the previous ones
+	if err := ibft.ValidateEpochSizeSchedule(genesisEpochSize, schedule); err != nil {
+		return err
+	}
+
+	ibftConfig["epochSizeSchedule"] = schedule
+	cc.Params.Engine["ibft"] = ibftConfig
+
+	return nil
+}
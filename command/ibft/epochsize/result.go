@@ -0,0 +1,29 @@
+package epochsize
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/helper/common"
+)
+
+type IBFTEpochSizeResult struct {
+	Chain     string            `json:"chain"`
+	From      common.JSONNumber `json:"from"`
+	EpochSize common.JSONNumber `json:"epochSize"`
+}
+
+func (r *IBFTEpochSizeResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[NEW IBFT EPOCH SIZE]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Chain|%s", r.Chain),
+		fmt.Sprintf("From|%d", r.From.Value),
+		fmt.Sprintf("EpochSize|%d", r.EpochSize.Value),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
@@ -2,8 +2,13 @@ package ibft
 
 import (
 	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/command/ibft/badblocks"
 	"github.com/0xPolygon/polygon-edge/command/ibft/candidates"
+	"github.com/0xPolygon/polygon-edge/command/ibft/epochsize"
+	"github.com/0xPolygon/polygon-edge/command/ibft/inspect"
 	"github.com/0xPolygon/polygon-edge/command/ibft/propose"
+	"github.com/0xPolygon/polygon-edge/command/ibft/retract"
+	"github.com/0xPolygon/polygon-edge/command/ibft/rotatekey"
 	"github.com/0xPolygon/polygon-edge/command/ibft/snapshot"
 	"github.com/0xPolygon/polygon-edge/command/ibft/status"
 	_switch "github.com/0xPolygon/polygon-edge/command/ibft/switch"
@@ -17,6 +22,7 @@ func GetCommand() *cobra.Command {
 	}
 
 	helper.RegisterGRPCAddressFlag(ibftCmd)
+	helper.RegisterGRPCClientFlags(ibftCmd)
 
 	registerSubcommands(ibftCmd)
 
@@ -33,7 +39,17 @@ func registerSubcommands(baseCmd *cobra.Command) {
 		propose.GetCommand(),
 		// ibft candidates
 		candidates.GetCommand(),
+		// ibft retract
+		retract.GetCommand(),
 		// ibft switch
 		_switch.GetCommand(),
+		// ibft inspect
+		inspect.GetCommand(),
+		// ibft epoch-size
+		epochsize.GetCommand(),
+		// ibft rotate-key
+		rotatekey.GetCommand(),
+		// ibft bad-blocks
+		badblocks.GetCommand(),
 	)
 }
@@ -0,0 +1,41 @@
+package inspect
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	ibftInspectCmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Decodes the IBFT extra data of the latest block, unless a block number is specified",
+		Run:   runCommand,
+	}
+
+	setFlags(ibftInspectCmd)
+
+	return ibftInspectCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(
+		&params.blockNumber,
+		numberFlag,
+		-1,
+		"the block height (number) to inspect",
+	)
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.initExtra(helper.GetGRPCAddress(cmd)); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,59 @@
+package inspect
+
+import (
+	"context"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	ibftOp "github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+)
+
+const (
+	numberFlag = "number"
+)
+
+var (
+	params = &inspectParams{}
+)
+
+type inspectParams struct {
+	blockNumber int
+
+	extra *ibftOp.InspectResp
+}
+
+func (p *inspectParams) initExtra(grpcAddress string) error {
+	ibftClient, err := helper.GetIBFTOperatorClientConnection(grpcAddress)
+	if err != nil {
+		return err
+	}
+
+	extra, err := ibftClient.Inspect(
+		context.Background(),
+		p.getInspectRequest(),
+	)
+	if err != nil {
+		return err
+	}
+
+	p.extra = extra
+
+	return nil
+}
+
+func (p *inspectParams) getInspectRequest() *ibftOp.InspectReq {
+	req := &ibftOp.InspectReq{
+		Latest: true,
+	}
+
+	if p.blockNumber >= 0 {
+		req.Latest = false
+		req.Number = uint64(p.blockNumber)
+	}
+
+	return req
+}
+
+func (p *inspectParams) getResult() command.CommandResult {
+	return newIBFTInspectResult(p.extra)
+}
@@ -0,0 +1,82 @@
+package inspect
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	ibftOp "github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+)
+
+type IBFTInspectResult struct {
+	Number      uint64   `json:"number"`
+	Hash        string   `json:"hash"`
+	Vanity      string   `json:"vanity"`
+	Validators  []string `json:"validators"`
+	Proposer    string   `json:"proposer"`
+	Committers  []string `json:"committers"`
+	Quorum      uint64   `json:"quorum"`
+	BelowQuorum bool     `json:"below_quorum"`
+}
+
+func newIBFTInspectResult(resp *ibftOp.InspectResp) *IBFTInspectResult {
+	return &IBFTInspectResult{
+		Number:     resp.Number,
+		Hash:       resp.Hash,
+		Vanity:     resp.Vanity,
+		Validators: resp.Validators,
+		Proposer:   resp.Proposer,
+		Committers: resp.Committers,
+		Quorum:     resp.Quorum,
+		// the genesis block doesn't have any committed seals
+		BelowQuorum: resp.Number > 0 && uint64(len(resp.Committers)) < resp.Quorum,
+	}
+}
+
+func (r *IBFTInspectResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[IBFT EXTRA]\n")
+	r.writeBlockData(&buffer)
+	writeAddressList(&buffer, "VALIDATORS", "No validators found", r.Validators)
+	writeAddressList(&buffer, "COMMITTERS", "No committers found", r.Committers)
+
+	return buffer.String()
+}
+
+func (r *IBFTInspectResult) writeBlockData(buffer *bytes.Buffer) {
+	proposer := r.Proposer
+	if proposer == "" {
+		proposer = "-"
+	}
+
+	quorum := fmt.Sprintf("%d/%d", len(r.Committers), r.Quorum)
+	if r.BelowQuorum {
+		quorum += " (BELOW QUORUM)"
+	}
+
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Block|%d", r.Number),
+		fmt.Sprintf("Hash|%s", r.Hash),
+		fmt.Sprintf("Vanity|%s", r.Vanity),
+		fmt.Sprintf("Proposer|%s", proposer),
+		fmt.Sprintf("Committed seals|%s", quorum),
+	}))
+	buffer.WriteString("\n")
+}
+
+func writeAddressList(buffer *bytes.Buffer, title, emptyMsg string, addresses []string) {
+	rows := make([]string, len(addresses)+1)
+	rows[0] = emptyMsg
+
+	if len(addresses) > 0 {
+		rows[0] = "ADDRESS"
+		for i, d := range addresses {
+			rows[i+1] = d
+		}
+	}
+
+	buffer.WriteString(fmt.Sprintf("\n[%s]\n", title))
+	buffer.WriteString(helper.FormatList(rows))
+	buffer.WriteString("\n")
+}
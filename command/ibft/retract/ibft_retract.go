@@ -0,0 +1,50 @@
+package retract
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/spf13/cobra"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+func GetCommand() *cobra.Command {
+	ibftRetractCmd := &cobra.Command{
+		Use: "retract",
+		Short: "Retracts the vote for a candidate. The candidate not voted for yet is dropped, " +
+			"the vote already cast is withdrawn in the next blocks the node proposes",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(ibftRetractCmd)
+
+	_ = ibftRetractCmd.MarkFlagRequired(addressFlag)
+
+	return ibftRetractCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.addressRaw,
+		addressFlag,
+		"",
+		"the address of the account the vote is retracted for",
+	)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.initRawParams()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.retractVote(helper.GetGRPCAddress(cmd)); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,62 @@
+package retract
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	ibftOp "github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	addressFlag = "addr"
+)
+
+var (
+	errInvalidAddressFormat = errors.New("invalid address format")
+)
+
+var (
+	params = &retractParams{}
+)
+
+type retractParams struct {
+	addressRaw string
+
+	address types.Address
+}
+
+func (p *retractParams) initRawParams() error {
+	p.address = types.Address{}
+	if err := p.address.UnmarshalText([]byte(p.addressRaw)); err != nil {
+		return errInvalidAddressFormat
+	}
+
+	return nil
+}
+
+func (p *retractParams) retractVote(grpcAddress string) error {
+	ibftClient, err := helper.GetIBFTOperatorClientConnection(grpcAddress)
+	if err != nil {
+		return err
+	}
+
+	if _, err := ibftClient.RetractVote(
+		context.Background(),
+		&ibftOp.RetractVoteReq{
+			Address: p.address.String(),
+		},
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *retractParams) getResult() command.CommandResult {
+	return &IBFTRetractResult{
+		Address: p.address.String(),
+	}
+}
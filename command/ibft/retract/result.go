@@ -0,0 +1,28 @@
+package retract
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type IBFTRetractResult struct {
+	Address string `json:"-"`
+}
+
+func (r *IBFTRetractResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[IBFT RETRACT]\n")
+	buffer.WriteString(r.Message())
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
+
+func (r *IBFTRetractResult) Message() string {
+	return fmt.Sprintf("Successfully retracted the vote for address [%s]", r.Address)
+}
+
+func (r *IBFTRetractResult) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`{"message": "%s"}`, r.Message())), nil
+}
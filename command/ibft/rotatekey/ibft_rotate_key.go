@@ -0,0 +1,54 @@
+package rotatekey
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	ibftRotateKeyCmd := &cobra.Command{
+		Use: "rotate-key",
+		Short: "Rotates the validator key of the node without downtime. The node votes the new address in, " +
+			"signs with the new key from the rotation height, and votes the old address out",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(ibftRotateKeyCmd)
+
+	return ibftRotateKeyCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.key,
+		keyFlag,
+		"",
+		"the hex encoded new validator key. A new key is generated if not set",
+	)
+
+	cmd.Flags().StringVar(
+		&params.heightRaw,
+		heightFlag,
+		"",
+		"the first block signed with the new key. Defaults to the first block of the next epoch",
+	)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.initRawParams()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.rotateKey(helper.GetGRPCAddress(cmd)); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,74 @@
+package rotatekey
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	ibftOp "github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	keyFlag    = "key"
+	heightFlag = "height"
+)
+
+var (
+	params = &rotateKeyParams{}
+)
+
+type rotateKeyParams struct {
+	key       string
+	heightRaw string
+
+	height uint64
+
+	rotation *ibftOp.RotateValidatorKeyResp
+}
+
+func (p *rotateKeyParams) initRawParams() error {
+	if p.heightRaw == "" {
+		return nil
+	}
+
+	height, err := types.ParseUint64orHex(&p.heightRaw)
+	if err != nil {
+		return fmt.Errorf("unable to parse the height, %w", err)
+	}
+
+	p.height = height
+
+	return nil
+}
+
+func (p *rotateKeyParams) rotateKey(grpcAddress string) error {
+	ibftClient, err := helper.GetIBFTOperatorClientConnection(grpcAddress)
+	if err != nil {
+		return err
+	}
+
+	rotation, err := ibftClient.RotateValidatorKey(
+		context.Background(),
+		&ibftOp.RotateValidatorKeyReq{
+			Key:    p.key,
+			Height: p.height,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	p.rotation = rotation
+
+	return nil
+}
+
+func (p *rotateKeyParams) getResult() command.CommandResult {
+	return &IBFTRotateKeyResult{
+		OldAddress: p.rotation.OldAddress,
+		NewAddress: p.rotation.NewAddress,
+		Height:     p.rotation.Height,
+	}
+}
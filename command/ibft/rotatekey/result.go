@@ -0,0 +1,28 @@
+package rotatekey
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type IBFTRotateKeyResult struct {
+	OldAddress string `json:"old_address"`
+	NewAddress string `json:"new_address"`
+	Height     uint64 `json:"height"`
+}
+
+func (r *IBFTRotateKeyResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[IBFT ROTATE KEY]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Old address|%s", r.OldAddress),
+		fmt.Sprintf("New address|%s", r.NewAddress),
+		fmt.Sprintf("Height|%d", r.Height),
+	}))
+	buffer.WriteString("\n\nThe other validators have to vote the new address in with ibft propose\n")
+
+	return buffer.String()
+}
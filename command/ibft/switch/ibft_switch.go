@@ -42,7 +42,7 @@ func setFlags(cmd *cobra.Command) {
 		&params.deploymentRaw,
 		deploymentFlag,
 		"",
-		"the height to deploy the contract in PoS",
+		"the height to deploy the staking contract in PoS, lower than the height to switch",
 	)
 
 	cmd.Flags().StringVar(
@@ -22,7 +22,9 @@ const (
 )
 
 var (
-	ErrFromPositive = errors.New(`"from" must be positive number`)
+	ErrFromPositive       = errors.New(`"from" must be positive number`)
+	ErrMissingDeployment  = errors.New(`"deployment" must be specified to switch to PoS`)
+	ErrDeploymentNotFirst = errors.New(`"deployment" must be less than "from"`)
 )
 
 var (
@@ -66,6 +68,10 @@ func (p *switchParams) initRawParams() error {
 		return err
 	}
 
+	if err := p.validateDeployment(); err != nil {
+		return err
+	}
+
 	if err := p.initChain(); err != nil {
 		return err
 	}
@@ -186,6 +192,24 @@ func (p *switchParams) initFrom() error {
 	return nil
 }
 
+// validateDeployment checks the staking contract is deployed before the switch to PoS,
+// the validator set of the first PoS block is read from the state of its parent
+func (p *switchParams) validateDeployment() error {
+	if p.mechanismType != ibft.PoS {
+		return nil
+	}
+
+	if p.deployment == nil {
+		return ErrMissingDeployment
+	}
+
+	if *p.deployment >= p.from {
+		return ErrDeploymentNotFirst
+	}
+
+	return nil
+}
+
 func (p *switchParams) initChain() error {
 	cc, err := chain.Import(p.genesisPath)
 	if err != nil {
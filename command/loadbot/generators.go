@@ -5,12 +5,11 @@ import (
 	"os"
 	"strings"
 
+	"github.com/0xPolygon/polygon-edge/command/helper"
 	"github.com/0xPolygon/polygon-edge/crypto"
 	txpoolOp "github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/umbracle/go-web3/jsonrpc"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 func createJSONRPCClient(endpoint string, maxConns int) (*jsonrpc.Client, error) {
@@ -25,12 +24,7 @@ func createJSONRPCClient(endpoint string, maxConns int) (*jsonrpc.Client, error)
 }
 
 func createGRPCClient(endpoint string) (txpoolOp.TxnPoolOperatorClient, error) {
-	conn, err := grpc.Dial(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return nil, err
-	}
-
-	return txpoolOp.NewTxnPoolOperatorClient(conn), nil
+	return helper.GetTxPoolClientConnection(endpoint)
 }
 
 func extractSenderAccount(address types.Address) (*Account, error) {
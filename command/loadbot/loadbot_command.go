@@ -17,6 +17,7 @@ func GetCommand() *cobra.Command {
 	}
 
 	helper.RegisterGRPCAddressFlag(loadbotCmd)
+	helper.RegisterGRPCClientFlags(loadbotCmd)
 	helper.RegisterJSONRPCFlag(loadbotCmd)
 
 	setFlags(loadbotCmd)
@@ -22,6 +22,7 @@ func GetCommand() *cobra.Command {
 	}
 
 	helper.RegisterGRPCAddressFlag(monitorCmd)
+	helper.RegisterGRPCClientFlags(monitorCmd)
 
 	return monitorCmd
 }
@@ -20,11 +20,13 @@ var (
 )
 
 const (
-	addrFlag = "addr"
+	addrFlag   = "addr"
+	staticFlag = "static"
 )
 
 type addParams struct {
 	peerAddresses []string
+	static        bool
 
 	systemClient proto.SystemClient
 
@@ -70,7 +72,12 @@ func (p *addParams) addPeers() {
 }
 
 func (p *addParams) addPeer(peerAddress string) error {
-	if _, err := p.systemClient.PeersAdd(
+	addFn := p.systemClient.PeersAdd
+	if p.static {
+		addFn = p.systemClient.PeersAddStatic
+	}
+
+	if _, err := addFn(
 		context.Background(),
 		&proto.PeersAddRequest{
 			Id: peerAddress,
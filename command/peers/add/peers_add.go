@@ -27,6 +27,13 @@ func setFlags(cmd *cobra.Command) {
 		[]string{},
 		"the libp2p addresses of the peers",
 	)
+
+	cmd.Flags().BoolVar(
+		&params.static,
+		staticFlag,
+		false,
+		"keep the node connected with the peers at all times, until they are removed with 'peers remove-static'",
+	)
 }
 
 func setRequiredFlags(cmd *cobra.Command) {
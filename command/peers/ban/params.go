@@ -0,0 +1,61 @@
+package ban
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/server/proto"
+)
+
+var (
+	params = &banParams{}
+)
+
+const (
+	peerIDFlag   = "peer-id"
+	durationFlag = "duration"
+)
+
+type banParams struct {
+	peerID   string
+	duration uint64
+
+	bannedUntil int64
+}
+
+func (p *banParams) getRequiredFlags() []string {
+	return []string{
+		peerIDFlag,
+	}
+}
+
+func (p *banParams) banPeer(grpcAddress string) error {
+	systemClient, err := helper.GetSystemClientConnection(grpcAddress)
+	if err != nil {
+		return err
+	}
+
+	resp, err := systemClient.PeersBan(
+		context.Background(),
+		&proto.PeersBanRequest{
+			Id:       p.peerID,
+			Duration: p.duration,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	p.bannedUntil = resp.BannedUntil
+
+	return nil
+}
+
+func (p *banParams) getResult() command.CommandResult {
+	return &PeersBanResult{
+		ID:          p.peerID,
+		BannedUntil: time.Unix(p.bannedUntil, 0).UTC().Format(time.RFC3339),
+	}
+}
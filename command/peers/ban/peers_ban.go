@@ -0,0 +1,55 @@
+package ban
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	peersBanCmd := &cobra.Command{
+		Use:   "ban",
+		Short: "Bans the specified peer and closes its connections, using the libp2p ID of the peer node",
+		Run:   runCommand,
+	}
+
+	setFlags(peersBanCmd)
+	setRequiredFlags(peersBanCmd)
+
+	return peersBanCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.peerID,
+		peerIDFlag,
+		"",
+		"libp2p node ID of a specific peer within p2p network",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.duration,
+		durationFlag,
+		0,
+		"the ban duration in seconds. If omitted, the ban lasts longer for every ban of the peer",
+	)
+}
+
+func setRequiredFlags(cmd *cobra.Command) {
+	for _, requiredFlag := range params.getRequiredFlags() {
+		_ = cmd.MarkFlagRequired(requiredFlag)
+	}
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.banPeer(helper.GetGRPCAddress(cmd)); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,26 @@
+package ban
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type PeersBanResult struct {
+	ID          string `json:"id"`
+	BannedUntil string `json:"banned_until"`
+}
+
+func (r *PeersBanResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[PEER BANNED]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("ID|%s", r.ID),
+		fmt.Sprintf("Banned until|%s", r.BannedUntil),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
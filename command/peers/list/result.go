@@ -8,17 +8,22 @@ import (
 )
 
 type PeersListResult struct {
-	Peers []string `json:"peers"`
+	Peers  []string         `json:"peers"`
+	Scores map[string]int64 `json:"scores"`
 }
 
 func newPeersListResult(peers []*proto.Peer) *PeersListResult {
 	resultPeers := make([]string, len(peers))
+	scores := make(map[string]int64, len(peers))
+
 	for i, p := range peers {
 		resultPeers[i] = p.Id
+		scores[p.Id] = p.Score
 	}
 
 	return &PeersListResult{
-		Peers: resultPeers,
+		Peers:  resultPeers,
+		Scores: scores,
 	}
 }
 
@@ -34,7 +39,7 @@ func (r *PeersListResult) GetOutput() string {
 
 		rows := make([]string, len(r.Peers))
 		for i, p := range r.Peers {
-			rows[i] = fmt.Sprintf("[%d]|%s", i, p)
+			rows[i] = fmt.Sprintf("[%d]|%s|score %d", i, p, r.Scores[p])
 		}
 		buffer.WriteString(helper.FormatKV(rows))
 	}
@@ -0,0 +1,57 @@
+package removestatic
+
+import (
+	"context"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/server/proto"
+)
+
+var (
+	params = &removeStaticParams{}
+)
+
+const (
+	peerIDFlag = "peer-id"
+)
+
+type removeStaticParams struct {
+	peerID string
+
+	removed bool
+}
+
+func (p *removeStaticParams) getRequiredFlags() []string {
+	return []string{
+		peerIDFlag,
+	}
+}
+
+func (p *removeStaticParams) removeStaticPeer(grpcAddress string) error {
+	systemClient, err := helper.GetSystemClientConnection(grpcAddress)
+	if err != nil {
+		return err
+	}
+
+	resp, err := systemClient.PeersRemoveStatic(
+		context.Background(),
+		&proto.PeersRemoveStaticRequest{
+			Id: p.peerID,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	p.removed = resp.Removed
+
+	return nil
+}
+
+func (p *removeStaticParams) getResult() command.CommandResult {
+	return &PeersRemoveStaticResult{
+		ID:      p.peerID,
+		Removed: p.removed,
+	}
+}
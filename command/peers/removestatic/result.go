@@ -0,0 +1,26 @@
+package removestatic
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type PeersRemoveStaticResult struct {
+	ID      string `json:"id"`
+	Removed bool   `json:"removed"`
+}
+
+func (r *PeersRemoveStaticResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[STATIC PEER REMOVED]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("ID|%s", r.ID),
+		fmt.Sprintf("Was static|%t", r.Removed),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/command"
 	"github.com/0xPolygon/polygon-edge/command/helper"
 	"github.com/0xPolygon/polygon-edge/server/proto"
+	"time"
 )
 
 var (
@@ -49,9 +50,18 @@ func (p *statusParams) initPeerInfo(grpcAddress string) error {
 }
 
 func (p *statusParams) getResult() command.CommandResult {
-	return &PeersStatusResult{
+	result := &PeersStatusResult{
 		ID:        p.peerStatus.Id,
 		Protocols: p.peerStatus.Protocols,
 		Addresses: p.peerStatus.Addrs,
+		Score:     p.peerStatus.Score,
+		Static:    p.peerStatus.Static,
+		Trusted:   p.peerStatus.Trusted,
 	}
+
+	if p.peerStatus.BannedUntil != 0 {
+		result.BannedUntil = time.Unix(p.peerStatus.BannedUntil, 0).UTC().Format(time.RFC3339)
+	}
+
+	return result
 }
@@ -7,20 +7,33 @@ import (
 )
 
 type PeersStatusResult struct {
-	ID        string   `json:"id"`
-	Protocols []string `json:"protocols"`
-	Addresses []string `json:"addresses"`
+	ID          string   `json:"id"`
+	Protocols   []string `json:"protocols"`
+	Addresses   []string `json:"addresses"`
+	Score       int64    `json:"score"`
+	Static      bool     `json:"static"`
+	Trusted     bool     `json:"trusted"`
+	BannedUntil string   `json:"banned_until,omitempty"`
 }
 
 func (r *PeersStatusResult) GetOutput() string {
 	var buffer bytes.Buffer
 
 	buffer.WriteString("\n[PEER STATUS]\n")
-	buffer.WriteString(helper.FormatKV([]string{
+	rows := []string{
 		fmt.Sprintf("ID|%s", r.ID),
 		fmt.Sprintf("Protocols|%s", r.Protocols),
 		fmt.Sprintf("Addresses|%s", r.Addresses),
-	}))
+		fmt.Sprintf("Score|%d", r.Score),
+		fmt.Sprintf("Static|%t", r.Static),
+		fmt.Sprintf("Trusted|%t", r.Trusted),
+	}
+
+	if r.BannedUntil != "" {
+		rows = append(rows, fmt.Sprintf("Banned until|%s", r.BannedUntil))
+	}
+
+	buffer.WriteString(helper.FormatKV(rows))
 	buffer.WriteString("\n")
 
 	return buffer.String()
@@ -0,0 +1,26 @@
+package unban
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type PeersUnbanResult struct {
+	ID        string `json:"id"`
+	WasBanned bool   `json:"was_banned"`
+}
+
+func (r *PeersUnbanResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[PEER UNBANNED]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("ID|%s", r.ID),
+		fmt.Sprintf("Was banned|%t", r.WasBanned),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
@@ -0,0 +1,103 @@
+package restore
+
+import (
+	"errors"
+
+	"github.com/0xPolygon/polygon-edge/archive"
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/0xPolygon/polygon-edge/server"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	dataDirFlag     = "data-dir"
+	genesisPathFlag = "chain"
+	verifyFlag      = "verify"
+)
+
+var (
+	params = &restoreParams{}
+)
+
+var (
+	errDataDirMissing = errors.New(`the "data-dir" is required to restore the snapshot`)
+)
+
+type restoreParams struct {
+	path        string
+	dataDir     string
+	genesisPath string
+	verify      bool
+	logLevel    string
+
+	genesisConfig *chain.Chain
+
+	manifest *archive.SnapshotManifest
+}
+
+func (p *restoreParams) initRawParams() error {
+	if p.verify {
+		return nil
+	}
+
+	if p.dataDir == "" {
+		return errDataDirMissing
+	}
+
+	return p.initGenesisConfig()
+}
+
+func (p *restoreParams) initGenesisConfig() error {
+	var parseErr error
+
+	if p.genesisConfig, parseErr = chain.Import(
+		p.genesisPath,
+	); parseErr != nil {
+		return parseErr
+	}
+
+	return nil
+}
+
+func (p *restoreParams) restoreSnapshot() error {
+	var (
+		manifest *archive.SnapshotManifest
+		err      error
+	)
+
+	if p.verify {
+		manifest, err = archive.VerifySnapshot(
+			hclog.New(&hclog.LoggerOptions{
+				Name:  "restore",
+				Level: hclog.LevelFromString(p.logLevel),
+			}),
+			p.path,
+		)
+	} else {
+		manifest, err = server.RestoreSnapshot(&server.Config{
+			Chain:    p.genesisConfig,
+			DataDir:  p.dataDir,
+			LogLevel: hclog.LevelFromString(p.logLevel),
+		}, p.path)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	p.manifest = manifest
+
+	return nil
+}
+
+func (p *restoreParams) getResult() command.CommandResult {
+	return &RestoreResult{
+		File:      p.path,
+		Verified:  p.verify,
+		ChainID:   p.manifest.ChainID,
+		Height:    p.manifest.Height,
+		HeadHash:  p.manifest.HeadHash.String(),
+		StateRoot: p.manifest.StateRoot.String(),
+	}
+}
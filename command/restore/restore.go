@@ -0,0 +1,70 @@
+package restore
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	restoreCmd := &cobra.Command{
+		Use: "restore [snapshot file]",
+		Short: "Rebuilds the data directory of a stopped node from a snapshot taken by the backup command. " +
+			"The blocks are verified, and the state of the head is checked against its state root",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(restoreCmd)
+
+	return restoreCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dataDirFlag,
+		"",
+		"the data directory of the node to restore",
+	)
+
+	cmd.Flags().StringVar(
+		&params.genesisPath,
+		genesisPathFlag,
+		"./genesis.json",
+		"the genesis file of the chain, its genesis block must match the one of the snapshot",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.verify,
+		verifyFlag,
+		false,
+		"only check the integrity of the snapshot, without restoring it",
+	)
+
+	cmd.Flags().StringVar(
+		&params.logLevel,
+		command.LogLevelFlag,
+		"INFO",
+		"the log level for console output",
+	)
+}
+
+func runPreRun(_ *cobra.Command, args []string) error {
+	params.path = args[0]
+
+	return params.initRawParams()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.restoreSnapshot(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,39 @@
+package restore
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type RestoreResult struct {
+	File      string `json:"file"`
+	Verified  bool   `json:"verified"`
+	ChainID   uint64 `json:"chain_id"`
+	Height    uint64 `json:"height"`
+	HeadHash  string `json:"head_hash"`
+	StateRoot string `json:"state_root"`
+}
+
+func (r *RestoreResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[RESTORE]\n")
+
+	if r.Verified {
+		buffer.WriteString("Verified snapshot file successfully:\n")
+	} else {
+		buffer.WriteString("Restored snapshot file successfully:\n")
+	}
+
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("File|%s", r.File),
+		fmt.Sprintf("Chain ID|%d", r.ChainID),
+		fmt.Sprintf("Height|%d", r.Height),
+		fmt.Sprintf("Head Hash|%s", r.HeadHash),
+		fmt.Sprintf("State Root|%s", r.StateRoot),
+	}))
+
+	return buffer.String()
+}
@@ -3,6 +3,10 @@ package root
 import (
 	"fmt"
 	"github.com/0xPolygon/polygon-edge/command/backup"
+	"github.com/0xPolygon/polygon-edge/command/chain"
+	"github.com/0xPolygon/polygon-edge/command/chainexport"
+	"github.com/0xPolygon/polygon-edge/command/chainimport"
+	"github.com/0xPolygon/polygon-edge/command/db"
 	"github.com/0xPolygon/polygon-edge/command/genesis"
 	"github.com/0xPolygon/polygon-edge/command/helper"
 	"github.com/0xPolygon/polygon-edge/command/ibft"
@@ -10,6 +14,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/command/loadbot"
 	"github.com/0xPolygon/polygon-edge/command/monitor"
 	"github.com/0xPolygon/polygon-edge/command/peers"
+	"github.com/0xPolygon/polygon-edge/command/restore"
 	"github.com/0xPolygon/polygon-edge/command/secrets"
 	"github.com/0xPolygon/polygon-edge/command/server"
 	"github.com/0xPolygon/polygon-edge/command/status"
@@ -48,6 +53,11 @@ func (rc *RootCommand) registerSubCommands() {
 		loadbot.GetCommand(),
 		ibft.GetCommand(),
 		backup.GetCommand(),
+		restore.GetCommand(),
+		chainexport.GetCommand(),
+		chainimport.GetCommand(),
+		chain.GetCommand(),
+		db.GetCommand(),
 		genesis.GetCommand(),
 		server.GetCommand(),
 		license.GetCommand(),
@@ -0,0 +1,67 @@
+package encrypt
+
+import (
+	"errors"
+
+	"github.com/0xPolygon/polygon-edge/command"
+	cmdHelper "github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/secrets/helper"
+)
+
+const (
+	dataDirFlag      = "data-dir"
+	passwordFileFlag = "password-file"
+)
+
+var (
+	params = &encryptParams{}
+)
+
+var (
+	errInvalidParams = errors.New("no data directory passed in")
+)
+
+type encryptParams struct {
+	dataDir      string
+	passwordFile string
+
+	encrypted []string
+}
+
+func (ep *encryptParams) validateFlags() error {
+	if ep.dataDir == "" {
+		return errInvalidParams
+	}
+
+	return nil
+}
+
+func (ep *encryptParams) getPassphrase() (string, error) {
+	if ep.passwordFile != "" {
+		return cmdHelper.ReadPassphraseFile(ep.passwordFile)
+	}
+
+	return cmdHelper.PromptPassphrase(true)
+}
+
+func (ep *encryptParams) encryptSecrets() error {
+	passphrase, err := ep.getPassphrase()
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := helper.EncryptLocalSecrets(ep.dataDir, passphrase)
+	if err != nil {
+		return err
+	}
+
+	ep.encrypted = encrypted
+
+	return nil
+}
+
+func (ep *encryptParams) getResult() command.CommandResult {
+	return &SecretsEncryptResult{
+		Encrypted: ep.encrypted,
+	}
+}
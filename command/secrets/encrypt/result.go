@@ -0,0 +1,30 @@
+package encrypt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/0xPolygon/polygon-edge/command/helper"
+)
+
+type SecretsEncryptResult struct {
+	Encrypted []string `json:"encrypted"`
+}
+
+func (r *SecretsEncryptResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	encrypted := "none, the secrets were encrypted already"
+	if len(r.Encrypted) > 0 {
+		encrypted = strings.Join(r.Encrypted, ", ")
+	}
+
+	buffer.WriteString("\n[SECRETS ENCRYPT]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Encrypted secrets|%s", encrypted),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
@@ -0,0 +1,54 @@
+package encrypt
+
+import (
+	"github.com/0xPolygon/polygon-edge/command"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	secretsEncryptCmd := &cobra.Command{
+		Use: "encrypt",
+		Short: "Encrypts the plaintext local secrets (Validator + Networking) of the data directory in place, " +
+			"in the keystore V3 format",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	setFlags(secretsEncryptCmd)
+
+	return secretsEncryptCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.dataDir,
+		dataDirFlag,
+		"",
+		"the directory for the Polygon Edge data",
+	)
+
+	cmd.Flags().StringVar(
+		&params.passwordFile,
+		passwordFileFlag,
+		"",
+		"the path to the file with the passphrase the secrets are encrypted with. "+
+			"If omitted, the passphrase is prompted for",
+	)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.validateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.encryptSecrets(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -10,9 +10,10 @@ import (
 
 func GetCommand() *cobra.Command {
 	secretsGenerateCmd := &cobra.Command{
-		Use:   "generate",
-		Short: "Initializes the secrets manager configuration in the provided directory. Used for Hashicorp Vault",
-		Run:   runCommand,
+		Use: "generate",
+		Short: "Initializes the secrets manager configuration in the provided directory. " +
+			"Used for Hashicorp Vault, AWS SSM, AWS KMS and GCP KMS",
+		Run: runCommand,
 	}
 
 	setFlags(secretsGenerateCmd)
@@ -80,7 +81,9 @@ func setFlags(cmd *cobra.Command) {
 		&params.extra,
 		extraFlag,
 		"",
-		"Specifies the extra fields map in string format 'key1=val1,key2=val2'",
+		"Specifies the extra fields map in string format 'key1=val1,key2=val2'. "+
+			"AWS KMS: region, profile, alias-prefix. GCP KMS: key-ring, credentials-file. "+
+			"Both KMS reference the existing keys with validator-key and network-key",
 	)
 }
 
@@ -1,19 +1,24 @@
 package init
 
 import (
-	"crypto/ecdsa"
 	"errors"
 	"github.com/0xPolygon/polygon-edge/command"
+	cmdHelper "github.com/0xPolygon/polygon-edge/command/helper"
 	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/0xPolygon/polygon-edge/secrets/helper"
+	"github.com/0xPolygon/polygon-edge/types"
 	libp2pCrypto "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
 )
 
 const (
-	dataDirFlag = "data-dir"
-	configFlag  = "config"
+	dataDirFlag      = "data-dir"
+	configFlag       = "config"
+	passwordFileFlag = "password-file"
+	encryptFlag      = "encrypt"
+	blsFlag          = "bls"
 )
 
 var (
@@ -24,16 +29,23 @@ var (
 	errInvalidConfig   = errors.New("invalid secrets configuration")
 	errInvalidParams   = errors.New("no config file or data directory passed in")
 	errUnsupportedType = errors.New("unsupported secrets manager")
+	errEncryptedConfig = errors.New("only the local secrets are encrypted with a passphrase")
+	errBLSKeyKMS       = errors.New("the BLS key can't be held by the KMS")
 )
 
 type initParams struct {
-	dataDir    string
-	configPath string
+	dataDir      string
+	configPath   string
+	passwordFile string
+	encrypt      bool
+	bls          bool
 
 	secretsManager secrets.SecretsManager
 	secretsConfig  *secrets.SecretsManagerConfig
 
-	validatorPrivateKey  *ecdsa.PrivateKey
+	validatorAddress     types.Address
+	blsPublicKey         string
+	blsProof             string
 	networkingPrivateKey libp2pCrypto.PrivKey
 
 	nodeID peer.ID
@@ -44,6 +56,10 @@ func (ip *initParams) validateFlags() error {
 		return errInvalidParams
 	}
 
+	if ip.configPath != "" && (ip.encrypt || ip.passwordFile != "") {
+		return errEncryptedConfig
+	}
+
 	return nil
 }
 
@@ -56,6 +72,10 @@ func (ip *initParams) initSecrets() error {
 		return err
 	}
 
+	if err := ip.initBLSKey(); err != nil {
+		return err
+	}
+
 	return ip.initNetworkingKey()
 }
 
@@ -93,6 +113,20 @@ func (ip *initParams) initFromConfig() error {
 		}
 
 		secretsManager = AWSSSM
+	case secrets.AWSKMS:
+		AWSKMS, err := helper.SetupAWSKMS(ip.secretsConfig)
+		if err != nil {
+			return err
+		}
+
+		secretsManager = AWSKMS
+	case secrets.GCPKMS:
+		GCPKMS, err := helper.SetupGCPKMS(ip.secretsConfig)
+		if err != nil {
+			return err
+		}
+
+		secretsManager = GCPKMS
 	default:
 		return errUnsupportedType
 	}
@@ -118,7 +152,12 @@ func (ip *initParams) parseConfig() error {
 }
 
 func (ip *initParams) initLocalSecretsManager() error {
-	local, err := helper.SetupLocalSecretsManager(ip.dataDir)
+	passphrase, err := ip.getPassphrase()
+	if err != nil {
+		return err
+	}
+
+	local, err := helper.SetupLocalSecretsManager(ip.dataDir, passphrase)
 	if err != nil {
 		return err
 	}
@@ -128,19 +167,82 @@ func (ip *initParams) initLocalSecretsManager() error {
 	return nil
 }
 
+// isKMS checks if the keys are held by the KMS of the secrets manager,
+// they are created in the KMS instead of the node
+func (ip *initParams) isKMS() bool {
+	_, ok := ip.secretsManager.(secrets.KeySigner)
+
+	return ok
+}
+
+// getPassphrase returns the passphrase the local secrets are encrypted with, if any
+func (ip *initParams) getPassphrase() (string, error) {
+	if ip.passwordFile != "" {
+		return cmdHelper.ReadPassphraseFile(ip.passwordFile)
+	}
+
+	if !ip.encrypt {
+		return "", nil
+	}
+
+	return cmdHelper.PromptPassphrase(true)
+}
+
 func (ip *initParams) initValidatorKey() error {
+	if ip.isKMS() {
+		validatorAddress, err := helper.InitKMSValidatorKey(ip.secretsManager)
+		if err != nil {
+			return err
+		}
+
+		ip.validatorAddress = validatorAddress
+
+		return nil
+	}
+
 	validatorKey, err := helper.InitValidatorKey(ip.secretsManager)
 	if err != nil {
 		return err
 	}
 
-	ip.validatorPrivateKey = validatorKey
+	ip.validatorAddress = crypto.PubKeyToAddress(&validatorKey.PublicKey)
+
+	return nil
+}
+
+// initBLSKey generates the BLS key of the aggregated committed seals, if requested
+func (ip *initParams) initBLSKey() error {
+	if !ip.bls {
+		return nil
+	}
+
+	if ip.isKMS() {
+		return errBLSKeyKMS
+	}
+
+	blsKey, err := helper.InitBLSKey(ip.secretsManager)
+	if err != nil {
+		return err
+	}
+
+	proof, err := blsKey.ProvePossession()
+	if err != nil {
+		return err
+	}
+
+	ip.blsPublicKey = hex.EncodeToHex(blsKey.PublicKey().Marshal())
+	ip.blsProof = hex.EncodeToHex(proof.Marshal())
 
 	return nil
 }
 
 func (ip *initParams) initNetworkingKey() error {
-	networkingKey, err := helper.InitNetworkingPrivateKey(ip.secretsManager)
+	initNetworkingKey := helper.InitNetworkingPrivateKey
+	if ip.isKMS() {
+		initNetworkingKey = helper.InitKMSNetworkingPrivateKey
+	}
+
+	networkingKey, err := initNetworkingKey(ip.secretsManager)
 	if err != nil {
 		return err
 	}
@@ -163,7 +265,9 @@ func (ip *initParams) initNodeID() error {
 
 func (ip *initParams) getResult() command.CommandResult {
 	return &SecretsInitResult{
-		Address: crypto.PubKeyToAddress(&ip.validatorPrivateKey.PublicKey),
-		NodeID:  ip.nodeID.String(),
+		Address:      ip.validatorAddress,
+		BLSPublicKey: ip.blsPublicKey,
+		BLSProof:     ip.blsProof,
+		NodeID:       ip.nodeID.String(),
 	}
 }
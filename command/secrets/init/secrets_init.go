@@ -34,6 +34,27 @@ func setFlags(cmd *cobra.Command) {
 		"the path to the SecretsManager config file, "+
 			"if omitted, the local FS secrets manager is used",
 	)
+
+	cmd.Flags().StringVar(
+		&params.passwordFile,
+		passwordFileFlag,
+		"",
+		"the path to the file with the passphrase the local secrets are encrypted with",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.encrypt,
+		encryptFlag,
+		false,
+		"encrypt the local secrets in the keystore V3 format, with the passphrase prompted for",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.bls,
+		blsFlag,
+		false,
+		"generate the BLS key the aggregated committed seals are signed with",
+	)
 }
 
 func runPreRun(_ *cobra.Command, _ []string) error {
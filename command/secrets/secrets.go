@@ -2,6 +2,7 @@ package secrets
 
 import (
 	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/command/secrets/encrypt"
 	"github.com/0xPolygon/polygon-edge/command/secrets/generate"
 	initCmd "github.com/0xPolygon/polygon-edge/command/secrets/init"
 	"github.com/spf13/cobra"
@@ -26,5 +27,7 @@ func registerSubcommands(baseCmd *cobra.Command) {
 		initCmd.GetCommand(),
 		// secrets generate
 		generate.GetCommand(),
+		// secrets encrypt
+		encrypt.GetCommand(),
 	)
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/0xPolygon/polygon-edge/network"
 
@@ -15,6 +16,7 @@ import (
 type Config struct {
 	GenesisPath       string     `json:"chain_config"`
 	SecretsConfigPath string     `json:"secrets_config"`
+	PasswordFile      string     `json:"password_file"`
 	DataDir           string     `json:"data_dir"`
 	BlockGasTarget    string     `json:"block_gas_target"`
 	GRPCAddr          string     `json:"grpc_addr"`
@@ -24,9 +26,79 @@ type Config struct {
 	ShouldSeal        bool       `json:"seal"`
 	TxPool            *TxPool    `json:"tx_pool"`
 	LogLevel          string     `json:"log_level"`
+	LogFormat         string     `json:"log_format"`
+	LogTo             string     `json:"log_to"`
+	LogMaxSize        uint64     `json:"log_max_size_mb"`
+	LogMaxBackups     uint64     `json:"log_max_backups"`
+	ShutdownTimeout   uint64     `json:"shutdown_timeout_s"`
 	RestoreFile       string     `json:"restore_file"`
 	BlockTime         uint64     `json:"block_time_s"`
 	Headers           *Headers   `json:"headers"`
+
+	IBFTSnapshotRetention uint64 `json:"ibft_snapshot_retention"`
+	IBFTMsgRateLimit      uint64 `json:"ibft_msg_rate_limit"`
+
+	GRPCSecurity *GRPCSecurity `json:"grpc_security"`
+
+	IBFTRemoteSigner *RemoteSigner `json:"ibft_remote_signer"`
+	IBFTWALDir       string        `json:"ibft_wal_dir"`
+
+	BlockVanity string `json:"block_vanity"`
+
+	AllowedFutureDrift uint64 `json:"allowed_future_drift_s"`
+
+	SyncMode string `json:"sync_mode"`
+
+	ConsensusRole string `json:"consensus_role"`
+
+	GCMode      string `json:"gc_mode"`
+	GCRetention uint64 `json:"gc_retention"`
+
+	DBEngine string `json:"db_engine"`
+
+	Cache uint64 `json:"cache"`
+
+	LevelDBWriteBuffer uint64 `json:"leveldb_write_buffer"`
+	LevelDBBlockCache  uint64 `json:"leveldb_block_cache"`
+	LevelDBOpenFiles   uint64 `json:"leveldb_open_files"`
+
+	IndexInternalTxs  bool `json:"index_internal_txs"`
+	ParallelExecution bool `json:"parallel_execution"`
+
+	BadBlockDir string `json:"bad_block_dir"`
+
+	BlockSinks []*BlockSink `json:"block_sinks"`
+
+	Alerts *Alerts `json:"alerts,omitempty"`
+
+	JSONRPCFeeHistoryLimit        uint64 `json:"json_rpc_fee_history_limit"`
+	JSONRPCBlockRangeLimit        uint64 `json:"json_rpc_block_range_limit"`
+	JSONRPCLogsLimit              uint64 `json:"json_rpc_logs_limit"`
+	JSONRPCSubscriptionBufferSize uint64 `json:"json_rpc_subscription_buffer_size"`
+
+	JSONRPCAllowedMethods   []string         `json:"json_rpc_allowed_methods"`
+	JSONRPCDeniedMethods    []string         `json:"json_rpc_denied_methods"`
+	JSONRPCRateLimit        uint64           `json:"json_rpc_rate_limit"`
+	JSONRPCMethodRateLimits map[string]int64 `json:"json_rpc_method_rate_limits"`
+	JSONRPCBatchLimit       uint64           `json:"json_rpc_batch_limit"`
+	JSONRPCBatchWorkers     uint64           `json:"json_rpc_batch_workers"`
+	JSONRPCFilterTimeout    uint64           `json:"json_rpc_filter_timeout"`
+	JSONRPCSyncDistance     uint64           `json:"json_rpc_sync_distance"`
+
+	JSONRPCExecutionTimeout        uint64           `json:"json_rpc_execution_timeout"`
+	JSONRPCMethodExecutionTimeouts map[string]int64 `json:"json_rpc_method_execution_timeouts"`
+	JSONRPCExecutionLimit          uint64           `json:"json_rpc_execution_limit"`
+
+	JSONRPCReadCIDRs      []string `json:"json_rpc_read_cidrs"`
+	JSONRPCWriteCIDRs     []string `json:"json_rpc_write_cidrs"`
+	JSONRPCAdminCIDRs     []string `json:"json_rpc_admin_cidrs"`
+	JSONRPCTrustedProxies []string `json:"json_rpc_trusted_proxies"`
+
+	GraphQLAddr string `json:"graphql_addr"`
+
+	HealthAddr        string `json:"health_addr"`
+	HealthMaxBlockLag uint64 `json:"health_max_block_lag"`
+	HealthMinPeers    uint64 `json:"health_min_peers"`
 }
 
 // Telemetry holds the config details for metric services.
@@ -43,15 +115,78 @@ type Network struct {
 	MaxPeers         int64  `json:"max_peers,omitempty"`
 	MaxOutboundPeers int64  `json:"max_outbound_peers,omitempty"`
 	MaxInboundPeers  int64  `json:"max_inbound_peers,omitempty"`
+
+	PeerPenalties    map[string]int64 `json:"peer_penalties,omitempty"`
+	PeerBanThreshold int64            `json:"peer_ban_threshold,omitempty"`
+	PeerBanDuration  uint64           `json:"peer_ban_duration_s,omitempty"`
+
+	StaticPeers  []string `json:"static_peers,omitempty"`
+	TrustedPeers []string `json:"trusted_peers,omitempty"`
+	DiscoveryDNS string   `json:"discovery_dns,omitempty"`
 }
 
 // TxPool defines the TxPool configuration params
 type TxPool struct {
-	PriceLimit uint64 `json:"price_limit"`
-	MaxSlots   uint64 `json:"max_slots"`
+	PriceLimit         uint64 `json:"price_limit"`
+	PriceBump          uint64 `json:"price_bump"`
+	MaxSlots           uint64 `json:"max_slots"`
+	MaxAccountEnqueued uint64 `json:"max_account_enqueued"`
+	MaxAccountPending  uint64 `json:"max_account_pending"`
+	Journal            string `json:"journal"`
+	JournalRotate      uint64 `json:"journal_rotate_s"`
+	NoJournal          bool   `json:"no_journal"`
+
+	AllowLocalUnderpriced     bool   `json:"allow_local_underpriced"`
+	UnderpricedEvictionBlocks uint64 `json:"underpriced_eviction_blocks"`
+}
+
+// RemoteSigner defines the remote signer configuration params
+type RemoteSigner struct {
+	Endpoint   string `json:"endpoint"`
+	TLSCAFile  string `json:"tls_ca_file"`
+	TimeoutMs  uint64 `json:"timeout_ms"`
+	MaxRetries uint64 `json:"max_retries"`
+}
+
+// BlockSink defines a sink the finalized blocks are pushed to,
+// the webhook sinks post them to the URL and the grpc sinks stream them to the endpoint
+type BlockSink struct {
+	Name      string            `json:"name"`
+	Type      string            `json:"type"`
+	URL       string            `json:"url,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Endpoint  string            `json:"endpoint,omitempty"`
+	TLSCAFile string            `json:"tls_ca_file,omitempty"`
+	TimeoutMs uint64            `json:"timeout_ms,omitempty"`
+}
+
+// Alerts defines the webhooks the reorgs deeper than the reorg depth, and the head not advancing
+// for the stale timeout in seconds, are posted to. The alerts are disabled if no webhook is set
+type Alerts struct {
+	Webhooks     []*AlertWebhook `json:"webhooks"`
+	ReorgDepth   uint64          `json:"reorg_depth"`
+	StaleTimeout uint64          `json:"stale_timeout"`
+	MinInterval  uint64          `json:"min_interval"`
+}
+
+// AlertWebhook defines an endpoint the alerts are posted to
+type AlertWebhook struct {
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	TimeoutMs uint64            `json:"timeout_ms,omitempty"`
+}
+
+// GRPCSecurity defines the TLS and the authentication params of the operator GRPC server
+type GRPCSecurity struct {
+	TLSCertFile     string `json:"tls_cert_file"`
+	TLSKeyFile      string `json:"tls_key_file"`
+	TLSClientCAFile string `json:"tls_client_ca_file"`
+	Token           string `json:"token"`
+	AdminToken      string `json:"admin_token"`
 }
 
-// Headers defines the HTTP response headers required to enable CORS.
+// Headers defines the HTTP response headers required to enable CORS,
+// no origin is allowed if none is set
 type Headers struct {
 	AccessControlAllowOrigins []string `json:"access_control_allow_origins"`
 }
@@ -59,6 +194,102 @@ type Headers struct {
 // minimum block generation time in seconds
 const defaultBlockTime uint64 = 2
 
+// maximum number of IBFT messages per second accepted from a single peer
+const defaultIBFTMsgRateLimit uint64 = 100
+
+// timeout of a single remote signer request in milliseconds
+const defaultRemoteSignerTimeoutMs uint64 = 2000
+
+// number of times a failed remote signer request is retried
+const defaultRemoteSignerMaxRetries uint64 = 2
+
+// minimum gas price increase (in percent) of the replacement transactions
+const defaultPriceBump uint64 = 10
+
+// maximum number of enqueued transactions of a single account
+const defaultMaxAccountEnqueued uint64 = 128
+
+// number of blocks a transaction can stay in the pool below the base fee
+const defaultUnderpricedEvictionBlocks uint64 = 10
+
+// interval of the txpool journal rotation in seconds
+const defaultJournalRotate uint64 = 3600
+
+// maximum number of blocks returned by a single eth_feeHistory request
+const defaultJSONRPCFeeHistoryLimit uint64 = 1024
+
+// maximum range of blocks queried by a single eth_getLogs request
+const defaultJSONRPCBlockRangeLimit uint64 = 1000
+
+// maximum number of logs returned by a single eth_getLogs request
+const defaultJSONRPCLogsLimit uint64 = 10000
+
+// number of updates buffered for a single web socket subscription
+const defaultJSONRPCSubscriptionBufferSize uint64 = 1024
+
+// maximum number of requests of a single JSON-RPC batch
+const defaultJSONRPCBatchLimit uint64 = 1000
+
+// number of requests of a JSON-RPC batch handled at the same time
+const defaultJSONRPCBatchWorkers uint64 = 1
+
+// time in seconds after which the JSON-RPC filters that are not polled are removed
+const defaultJSONRPCFilterTimeout uint64 = 60
+
+// number of blocks the node can be behind the network head, and not be reported as syncing by eth_syncing
+const defaultJSONRPCSyncDistance uint64 = 2
+
+// time in seconds after which the JSON-RPC executions of the EVM, such as eth_call, are aborted
+const defaultJSONRPCExecutionTimeout uint64 = 5
+
+// number of JSON-RPC executions of the EVM running at the same time
+const defaultJSONRPCExecutionLimit uint64 = 32
+
+// number of blocks the node can be behind its best peer and be reported as ready
+const defaultHealthMaxBlockLag uint64 = 5
+
+// number of peers the node has to be connected to to be reported as ready
+const defaultHealthMinPeers uint64 = 1
+
+// time in seconds given to the node to shut down gracefully
+const defaultShutdownTimeout uint64 = 30
+
+// maximum size of the log file in megabytes before it is rotated
+const defaultLogMaxSize uint64 = 100
+
+// number of rotated log files kept
+const defaultLogMaxBackups uint64 = 5
+
+// formats of the logs
+const (
+	textLogFormat = "text"
+	jsonLogFormat = "json"
+)
+
+// sync modes of the node
+const (
+	fullSyncMode = "full"
+	fastSyncMode = "fast"
+)
+
+// consensus roles of the node
+const (
+	fullConsensusRole = "full"
+	noneConsensusRole = "none"
+)
+
+// state garbage collection modes of the node
+const (
+	archiveGCMode = "archive"
+	prunedGCMode  = "pruned"
+)
+
+// number of latest blocks whose state is kept by the pruned nodes
+const defaultGCRetention uint64 = 128
+
+// memory budget of the state cache in MB
+const defaultCache uint64 = 256
+
 // DefaultConfig returns the default server configuration
 func DefaultConfig() *Config {
 	defaultNetworkConfig := network.DefaultConfig()
@@ -72,19 +303,54 @@ func DefaultConfig() *Config {
 			MaxPeers:         defaultNetworkConfig.MaxPeers,
 			MaxOutboundPeers: defaultNetworkConfig.MaxOutboundPeers,
 			MaxInboundPeers:  defaultNetworkConfig.MaxInboundPeers,
+			PeerBanThreshold: defaultNetworkConfig.Scoring.BanThreshold,
+			PeerBanDuration:  uint64(defaultNetworkConfig.Scoring.BanDuration / time.Second),
 		},
 		Telemetry:  &Telemetry{},
 		ShouldSeal: false,
 		TxPool: &TxPool{
-			PriceLimit: 0,
-			MaxSlots:   4096,
+			PriceLimit:         0,
+			PriceBump:          defaultPriceBump,
+			MaxSlots:           4096,
+			MaxAccountEnqueued: defaultMaxAccountEnqueued,
+			MaxAccountPending:  0,
+			JournalRotate:      defaultJournalRotate,
+
+			UnderpricedEvictionBlocks: defaultUnderpricedEvictionBlocks,
 		},
-		LogLevel:    "INFO",
-		RestoreFile: "",
-		BlockTime:   defaultBlockTime,
+		LogLevel:        "INFO",
+		LogFormat:       textLogFormat,
+		LogMaxSize:      defaultLogMaxSize,
+		LogMaxBackups:   defaultLogMaxBackups,
+		ShutdownTimeout: defaultShutdownTimeout,
+		RestoreFile:     "",
+		BlockTime:       defaultBlockTime,
 		Headers: &Headers{
-			AccessControlAllowOrigins: []string{"*"},
+			AccessControlAllowOrigins: []string{},
+		},
+		GRPCSecurity:     &GRPCSecurity{},
+		IBFTMsgRateLimit: defaultIBFTMsgRateLimit,
+		IBFTRemoteSigner: &RemoteSigner{
+			TimeoutMs:  defaultRemoteSignerTimeoutMs,
+			MaxRetries: defaultRemoteSignerMaxRetries,
 		},
+		JSONRPCFeeHistoryLimit:        defaultJSONRPCFeeHistoryLimit,
+		JSONRPCBlockRangeLimit:        defaultJSONRPCBlockRangeLimit,
+		JSONRPCLogsLimit:              defaultJSONRPCLogsLimit,
+		JSONRPCSubscriptionBufferSize: defaultJSONRPCSubscriptionBufferSize,
+		JSONRPCBatchLimit:             defaultJSONRPCBatchLimit,
+		JSONRPCBatchWorkers:           defaultJSONRPCBatchWorkers,
+		JSONRPCFilterTimeout:          defaultJSONRPCFilterTimeout,
+		JSONRPCSyncDistance:           defaultJSONRPCSyncDistance,
+		JSONRPCExecutionTimeout:       defaultJSONRPCExecutionTimeout,
+		JSONRPCExecutionLimit:         defaultJSONRPCExecutionLimit,
+		HealthMaxBlockLag:             defaultHealthMaxBlockLag,
+		HealthMinPeers:                defaultHealthMinPeers,
+		SyncMode:                      fullSyncMode,
+		ConsensusRole:                 fullConsensusRole,
+		GCMode:                        archiveGCMode,
+		GCRetention:                   defaultGCRetention,
+		Cache:                         defaultCache,
 	}
 }
 
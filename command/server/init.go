@@ -5,13 +5,18 @@ import (
 	"github.com/0xPolygon/polygon-edge/network/common"
 	"math"
 	"net"
+	"strings"
 
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/command/helper"
+	"github.com/0xPolygon/polygon-edge/helper/logging"
+	"github.com/0xPolygon/polygon-edge/jsonrpc"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/secrets"
+	"github.com/0xPolygon/polygon-edge/secrets/local"
 	"github.com/0xPolygon/polygon-edge/server"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
 )
 
 func (p *serverParams) initConfigFromFile() error {
@@ -25,6 +30,10 @@ func (p *serverParams) initConfigFromFile() error {
 }
 
 func (p *serverParams) initRawParams() error {
+	if err := p.initLogLevels(); err != nil {
+		return err
+	}
+
 	if err := p.initBlockGasTarget(); err != nil {
 		return err
 	}
@@ -33,19 +42,69 @@ func (p *serverParams) initRawParams() error {
 		return err
 	}
 
+	if err := p.initSecretsPassphrase(); err != nil {
+		return err
+	}
+
 	if err := p.initGenesisConfig(); err != nil {
 		return err
 	}
 
+	if err := p.initJSONRPCAccessControl(); err != nil {
+		return err
+	}
+
 	if p.isDevMode {
 		p.initDevMode()
 	}
 
 	p.initPeerLimits()
 
+	if err := p.initPinnedPeers(); err != nil {
+		return err
+	}
+
 	return p.initAddresses()
 }
 
+// initJSONRPCAccessControl parses the networks allowed to call the JSON-RPC method groups,
+// and the networks of the trusted proxies
+func (p *serverParams) initJSONRPCAccessControl() error {
+	accessControl := &jsonrpc.AccessControl{}
+
+	for _, cidrs := range []struct {
+		flag   string
+		values []string
+		cidrs  *[]*net.IPNet
+	}{
+		{jsonRPCReadCIDRsFlag, p.rawConfig.JSONRPCReadCIDRs, &accessControl.ReadCIDRs},
+		{jsonRPCWriteCIDRsFlag, p.rawConfig.JSONRPCWriteCIDRs, &accessControl.WriteCIDRs},
+		{jsonRPCAdminCIDRsFlag, p.rawConfig.JSONRPCAdminCIDRs, &accessControl.AdminCIDRs},
+		{jsonRPCTrustedProxiesFlag, p.rawConfig.JSONRPCTrustedProxies, &accessControl.TrustedProxies},
+	} {
+		parsed, err := jsonrpc.ParseCIDRs(cidrs.values)
+		if err != nil {
+			return fmt.Errorf("invalid %s, %w", cidrs.flag, err)
+		}
+
+		*cidrs.cidrs = parsed
+	}
+
+	p.jsonRPCAccessControl = accessControl
+
+	return nil
+}
+
+func (p *serverParams) initLogLevels() error {
+	var parseErr error
+
+	if p.logLevels, parseErr = logging.ParseLevels(p.rawConfig.LogLevel); parseErr != nil {
+		return parseErr
+	}
+
+	return nil
+}
+
 func (p *serverParams) initBlockGasTarget() error {
 	var parseErr error
 
@@ -74,6 +133,30 @@ func (p *serverParams) initSecretsConfig() error {
 	return nil
 }
 
+// initSecretsPassphrase reads the passphrase of the encrypted local secrets.
+// The node fails to start without it, instead of running without the keys
+func (p *serverParams) initSecretsPassphrase() error {
+	var err error
+
+	if p.rawConfig.PasswordFile != "" {
+		p.secretsPassphrase, err = helper.ReadPassphraseFile(p.rawConfig.PasswordFile)
+
+		return err
+	}
+
+	if p.isSecretsConfigPathSet() || !local.HasEncryptedSecrets(p.rawConfig.DataDir) {
+		return nil
+	}
+
+	if !helper.IsTerminal() {
+		return errPassphraseRequired
+	}
+
+	p.secretsPassphrase, err = helper.PromptPassphrase(false)
+
+	return err
+}
+
 func (p *serverParams) initGenesisConfig() error {
 	var parseErr error
 
@@ -90,8 +173,10 @@ func (p *serverParams) initDevMode() {
 	// Dev mode:
 	// - disables peer discovery
 	// - enables all forks
+	// - reports the node as ready without peers
 	p.rawConfig.ShouldSeal = true
 	p.rawConfig.Network.NoDiscover = true
+	p.rawConfig.HealthMinPeers = 0
 	p.genesisConfig.Params.Forks = chain.AllForksEnabled
 
 	p.initDevConsensusConfig()
@@ -163,11 +248,45 @@ func (p *serverParams) initUsingMaxPeers() {
 	p.rawConfig.Network.MaxInboundPeers = p.rawConfig.Network.MaxPeers - p.rawConfig.Network.MaxOutboundPeers
 }
 
+// initPinnedPeers parses the static peers, that have to be full libp2p addresses,
+// and the trusted peers, that are either libp2p addresses or peer IDs
+func (p *serverParams) initPinnedPeers() error {
+	for _, rawAddr := range p.rawConfig.Network.StaticPeers {
+		info, err := common.StringToAddrInfo(rawAddr)
+		if err != nil {
+			return fmt.Errorf("invalid static peer %s, %w", rawAddr, err)
+		}
+
+		p.staticPeers = append(p.staticPeers, info)
+	}
+
+	for _, rawPeer := range p.rawConfig.Network.TrustedPeers {
+		if peerID, err := peer.Decode(rawPeer); err == nil {
+			p.trustedPeers = append(p.trustedPeers, peerID)
+
+			continue
+		}
+
+		info, err := common.StringToAddrInfo(rawPeer)
+		if err != nil {
+			return fmt.Errorf("invalid trusted peer %s, %w", rawPeer, err)
+		}
+
+		p.trustedPeers = append(p.trustedPeers, info.ID)
+	}
+
+	return nil
+}
+
 func (p *serverParams) initAddresses() error {
 	if err := p.initPrometheusAddress(); err != nil {
 		return err
 	}
 
+	if err := p.initHealthAddress(); err != nil {
+		return err
+	}
+
 	if err := p.initLibp2pAddress(); err != nil {
 		return err
 	}
@@ -184,6 +303,10 @@ func (p *serverParams) initAddresses() error {
 		return err
 	}
 
+	if err := p.initGraphQLAddress(); err != nil {
+		return err
+	}
+
 	return p.initGRPCAddress()
 }
 
@@ -204,6 +327,40 @@ func (p *serverParams) initPrometheusAddress() error {
 	return nil
 }
 
+func (p *serverParams) initHealthAddress() error {
+	if !p.isHealthAddressSet() {
+		return nil
+	}
+
+	var parseErr error
+
+	if p.healthAddress, parseErr = helper.ResolveAddr(
+		p.rawConfig.HealthAddr,
+		helper.AllInterfacesBinding,
+	); parseErr != nil {
+		return parseErr
+	}
+
+	return nil
+}
+
+func (p *serverParams) initGraphQLAddress() error {
+	if !p.isGraphQLAddressSet() {
+		return nil
+	}
+
+	var parseErr error
+
+	if p.graphQLAddress, parseErr = helper.ResolveAddr(
+		p.rawConfig.GraphQLAddr,
+		helper.AllInterfacesBinding,
+	); parseErr != nil {
+		return parseErr
+	}
+
+	return nil
+}
+
 func (p *serverParams) initLibp2pAddress() error {
 	var parseErr error
 
@@ -217,18 +374,31 @@ func (p *serverParams) initLibp2pAddress() error {
 	return nil
 }
 
+// initNATAddress parses the NAT mode, that is either upnp, pmp, none,
+// or the external IP address of the node, as extip:<ip> or as a plain IP address
 func (p *serverParams) initNATAddress() error {
+	p.natMode = network.NATNone
+
 	if !p.isNATAddressSet() {
 		return nil
 	}
 
-	if p.natAddress = net.ParseIP(
-		p.rawConfig.Network.NatAddr,
-	); p.natAddress == nil {
-		return errInvalidNATAddress
-	}
+	switch rawNAT := p.rawConfig.Network.NatAddr; rawNAT {
+	case string(network.NATNone):
+		return nil
+	case string(network.NATUPnP), string(network.NATPMP):
+		p.natMode = network.NATMode(rawNAT)
 
-	return nil
+		return nil
+	default:
+		if p.natAddress = net.ParseIP(
+			strings.TrimPrefix(rawNAT, natExtIPPrefix),
+		); p.natAddress == nil {
+			return errInvalidNATAddress
+		}
+
+		return nil
+	}
 }
 
 func (p *serverParams) initDNSAddress() error {
@@ -2,40 +2,140 @@ package server
 
 import (
 	"errors"
+	"net"
+	"path/filepath"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/alerting"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/engine"
+	"github.com/0xPolygon/polygon-edge/blocksink"
 	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/helper/logging"
+	"github.com/0xPolygon/polygon-edge/jsonrpc"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/0xPolygon/polygon-edge/server"
-	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/multiformats/go-multiaddr"
-	"net"
 )
 
 const (
-	configFlag            = "config"
-	genesisPathFlag       = "chain"
-	dataDirFlag           = "data-dir"
-	libp2pAddressFlag     = "libp2p"
-	prometheusAddressFlag = "prometheus"
-	natFlag               = "nat"
-	dnsFlag               = "dns"
-	sealFlag              = "seal"
-	maxPeersFlag          = "max-peers"
-	maxInboundPeersFlag   = "max-inbound-peers"
-	maxOutboundPeersFlag  = "max-outbound-peers"
-	priceLimitFlag        = "price-limit"
-	maxSlotsFlag          = "max-slots"
-	blockGasTargetFlag    = "block-gas-target"
-	secretsConfigFlag     = "secrets-config"
-	restoreFlag           = "restore"
-	blockTimeFlag         = "block-time"
-	devIntervalFlag       = "dev-interval"
-	devFlag               = "dev"
-	corsOriginFlag        = "access-control-allow-origins"
+	configFlag             = "config"
+	genesisPathFlag        = "chain"
+	dataDirFlag            = "data-dir"
+	libp2pAddressFlag      = "libp2p"
+	prometheusAddressFlag  = "prometheus"
+	natFlag                = "nat"
+	dnsFlag                = "dns"
+	sealFlag               = "seal"
+	maxPeersFlag           = "max-peers"
+	maxInboundPeersFlag    = "max-inbound-peers"
+	maxOutboundPeersFlag   = "max-outbound-peers"
+	peerPenaltiesFlag      = "peer-penalties"
+	peerBanThresholdFlag   = "peer-ban-threshold"
+	peerBanDurationFlag    = "peer-ban-duration"
+	staticPeerFlag         = "static-peer"
+	trustedPeerFlag        = "trusted-peer"
+	discoveryDNSFlag       = "discovery-dns"
+	priceLimitFlag         = "price-limit"
+	priceBumpFlag          = "price-bump"
+	maxSlotsFlag           = "max-slots"
+	maxAccountEnqueuedFlag = "max-account-enqueued"
+	maxAccountPendingFlag  = "max-account-pending"
+	journalFlag            = "journal"
+	journalRotateFlag      = "journal-rotate"
+	noJournalFlag          = "no-journal"
+	blockGasTargetFlag     = "block-gas-target"
+	secretsConfigFlag      = "secrets-config"
+	passwordFileFlag       = "password-file"
+	restoreFlag            = "restore"
+	blockTimeFlag          = "block-time"
+	devIntervalFlag        = "dev-interval"
+	devFlag                = "dev"
+	corsOriginFlag         = "access-control-allow-origins"
+
+	allowLocalUnderpricedFlag     = "allow-local-underpriced"
+	underpricedEvictionBlocksFlag = "underpriced-eviction-blocks"
+
+	ibftSnapshotRetentionFlag = "ibft-snapshot-retention"
+	ibftMsgRateLimitFlag      = "ibft-msg-rate-limit"
+
+	grpcTLSCertFlag     = "grpc-tls-cert"
+	grpcTLSKeyFlag      = "grpc-tls-key"
+	grpcTLSClientCAFlag = "grpc-tls-client-ca"
+	grpcTokenFlag       = "grpc-token"
+	grpcAdminTokenFlag  = "grpc-admin-token"
+
+	ibftRemoteSignerFlag        = "ibft-remote-signer"
+	ibftRemoteSignerTLSCAFlag   = "ibft-remote-signer-tls-ca"
+	ibftRemoteSignerTimeoutFlag = "ibft-remote-signer-timeout"
+	ibftRemoteSignerRetriesFlag = "ibft-remote-signer-retries"
+
+	ibftWALDirFlag = "ibft-wal-dir"
+
+	blockVanityFlag = "block-vanity"
+
+	allowedFutureDriftFlag = "allowed-future-drift"
+
+	syncModeFlag = "sync-mode"
+
+	consensusRoleFlag = "consensus-role"
+
+	gcModeFlag      = "gc-mode"
+	gcRetentionFlag = "gc-retention"
+
+	dbEngineFlag = "db-engine"
+
+	cacheFlag = "cache"
+
+	levelDBWriteBufferFlag = "leveldb-write-buffer"
+	levelDBBlockCacheFlag  = "leveldb-block-cache"
+	levelDBOpenFilesFlag   = "leveldb-open-files"
+
+	indexInternalTxsFlag  = "index-internal-txs"
+	parallelExecutionFlag = "parallel-execution"
+
+	badBlockDirFlag = "bad-block-dir"
+
+	jsonRPCFeeHistoryLimitFlag        = "json-rpc-fee-history-limit"
+	jsonRPCBlockRangeLimitFlag        = "json-rpc-block-range-limit"
+	jsonRPCLogsLimitFlag              = "json-rpc-logs-limit"
+	jsonRPCSubscriptionBufferSizeFlag = "json-rpc-subscription-buffer-size"
+	jsonRPCAllowedMethodsFlag         = "json-rpc-allowed-methods"
+	jsonRPCDeniedMethodsFlag          = "json-rpc-denied-methods"
+	jsonRPCRateLimitFlag              = "json-rpc-rate-limit"
+	jsonRPCMethodRateLimitsFlag       = "json-rpc-method-rate-limits"
+	jsonRPCBatchLimitFlag             = "json-rpc-batch-limit"
+	jsonRPCBatchWorkersFlag           = "json-rpc-batch-workers"
+	jsonRPCFilterTimeoutFlag          = "json-rpc-filter-timeout"
+	jsonRPCSyncDistanceFlag           = "json-rpc-sync-distance"
+	jsonRPCExecutionTimeoutFlag       = "json-rpc-execution-timeout"
+	jsonRPCMethodExecTimeoutsFlag     = "json-rpc-method-execution-timeouts"
+	jsonRPCExecutionLimitFlag         = "json-rpc-execution-limit"
+	jsonRPCReadCIDRsFlag              = "json-rpc-read-cidrs"
+	jsonRPCWriteCIDRsFlag             = "json-rpc-write-cidrs"
+	jsonRPCAdminCIDRsFlag             = "json-rpc-admin-cidrs"
+	jsonRPCTrustedProxiesFlag         = "json-rpc-trusted-proxies"
+
+	graphQLAddrFlag = "graphql"
+
+	healthAddrFlag        = "health"
+	healthMaxBlockLagFlag = "health-max-block-lag"
+	healthMinPeersFlag    = "health-min-peers"
+
+	shutdownTimeoutFlag = "shutdown-timeout"
+
+	logFormatFlag     = "log-format"
+	logToFlag         = "log-to"
+	logMaxSizeFlag    = "log-max-size"
+	logMaxBackupsFlag = "log-max-backups"
 )
 
 const (
 	unsetPeersValue = -1
+
+	natExtIPPrefix = "extip:"
 )
 
 var (
@@ -44,13 +144,34 @@ var (
 			Telemetry: &Telemetry{},
 			Network:   &Network{},
 			TxPool:    &TxPool{},
+			Headers:   &Headers{},
+
+			GRPCSecurity:     &GRPCSecurity{},
+			IBFTRemoteSigner: &RemoteSigner{},
 		},
 	}
 )
 
 var (
-	errInvalidPeerParams = errors.New("both max-peers and max-inbound/outbound flags are set")
-	errInvalidNATAddress = errors.New("could not parse NAT IP address")
+	errInvalidPeerParams  = errors.New("both max-peers and max-inbound/outbound flags are set")
+	errInvalidNATAddress  = errors.New("nat should be either upnp, pmp, none, extip:<ip> or an IP address")
+	errInvalidSyncMode    = errors.New("sync mode should be either fast or full")
+	errInvalidRole        = errors.New("consensus role should be either full or none")
+	errNonValidatorSeal   = errors.New("the nodes with the none consensus role can't seal blocks")
+	errPassphraseRequired = errors.New(
+		"the local secrets are encrypted, the passphrase is required. Use --password-file or run in a terminal",
+	)
+	errInvalidGCMode      = errors.New("gc mode should be either archive or pruned")
+	errInvalidGCRetention = errors.New("gc retention should be at least one block")
+	errInvalidDBEngine    = errors.New("db engine should be either leveldb or pebble")
+
+	errInvalidMethodRateLimit = errors.New("json-rpc method rate limits should not be negative")
+	errInvalidMethodTimeout   = errors.New("json-rpc method execution timeouts should not be negative")
+
+	errInvalidPeerPenalty      = errors.New("peer penalties should not be negative")
+	errInvalidPeerBanThreshold = errors.New("peer ban threshold should be negative")
+
+	errInvalidLogFormat = errors.New("log format should be either text or json")
 )
 
 type serverParams struct {
@@ -60,18 +181,27 @@ type serverParams struct {
 	libp2pAddress     *net.TCPAddr
 	prometheusAddress *net.TCPAddr
 	natAddress        net.IP
+	natMode           network.NATMode
 	dnsAddress        multiaddr.Multiaddr
+	staticPeers       []*peer.AddrInfo
+	trustedPeers      []peer.ID
 	grpcAddress       *net.TCPAddr
 	jsonRPCAddress    *net.TCPAddr
+	graphQLAddress    *net.TCPAddr
+	healthAddress     *net.TCPAddr
 
 	blockGasTarget uint64
 	devInterval    uint64
 	isDevMode      bool
 
-	corsAllowedOrigins []string
+	jsonRPCAccessControl *jsonrpc.AccessControl
 
 	genesisConfig *chain.Chain
 	secretsConfig *secrets.SecretsManagerConfig
+
+	secretsPassphrase string
+
+	logLevels *logging.Levels
 }
 
 func (p *serverParams) validateFlags() error {
@@ -80,6 +210,63 @@ func (p *serverParams) validateFlags() error {
 		return errInvalidPeerParams
 	}
 
+	// Validate the sync mode
+	if p.rawConfig.SyncMode != fullSyncMode && p.rawConfig.SyncMode != fastSyncMode {
+		return errInvalidSyncMode
+	}
+
+	// Validate the consensus role
+	if p.rawConfig.ConsensusRole != fullConsensusRole && p.rawConfig.ConsensusRole != noneConsensusRole {
+		return errInvalidRole
+	}
+
+	if p.rawConfig.ConsensusRole == noneConsensusRole && p.rawConfig.ShouldSeal {
+		return errNonValidatorSeal
+	}
+
+	// Validate the state garbage collection
+	if p.rawConfig.GCMode != archiveGCMode && p.rawConfig.GCMode != prunedGCMode {
+		return errInvalidGCMode
+	}
+
+	if p.rawConfig.GCMode == prunedGCMode && p.rawConfig.GCRetention == 0 {
+		return errInvalidGCRetention
+	}
+
+	if p.rawConfig.DBEngine != "" && !engine.Supported(p.rawConfig.DBEngine) {
+		return errInvalidDBEngine
+	}
+
+	for _, limit := range p.rawConfig.JSONRPCMethodRateLimits {
+		if limit < 0 {
+			return errInvalidMethodRateLimit
+		}
+	}
+
+	for _, timeout := range p.rawConfig.JSONRPCMethodExecutionTimeouts {
+		if timeout < 0 {
+			return errInvalidMethodTimeout
+		}
+	}
+
+	for name, penalty := range p.rawConfig.Network.PeerPenalties {
+		if _, err := network.ParsePeerPenalty(name); err != nil {
+			return err
+		}
+
+		if penalty < 0 {
+			return errInvalidPeerPenalty
+		}
+	}
+
+	if p.rawConfig.Network.PeerBanThreshold > 0 {
+		return errInvalidPeerBanThreshold
+	}
+
+	if p.rawConfig.LogFormat != textLogFormat && p.rawConfig.LogFormat != jsonLogFormat {
+		return errInvalidLogFormat
+	}
+
 	return nil
 }
 
@@ -100,6 +287,14 @@ func (p *serverParams) isPrometheusAddressSet() bool {
 	return p.rawConfig.Telemetry.PrometheusAddr != ""
 }
 
+func (p *serverParams) isGraphQLAddressSet() bool {
+	return p.rawConfig.GraphQLAddr != ""
+}
+
+func (p *serverParams) isHealthAddressSet() bool {
+	return p.rawConfig.HealthAddr != ""
+}
+
 func (p *serverParams) isNATAddressSet() bool {
 	return p.rawConfig.Network.NatAddr != ""
 }
@@ -112,6 +307,79 @@ func (p *serverParams) isDevConsensus() bool {
 	return server.ConsensusType(p.genesisConfig.Params.GetEngine()) == server.DevConsensus
 }
 
+func (p *serverParams) getRemoteSignerConfig() *consensus.RemoteSignerConfig {
+	remoteSigner := p.rawConfig.IBFTRemoteSigner
+	if remoteSigner == nil || remoteSigner.Endpoint == "" {
+		// the local validator key is used
+		return nil
+	}
+
+	return &consensus.RemoteSignerConfig{
+		Endpoint:   remoteSigner.Endpoint,
+		TLSCAFile:  remoteSigner.TLSCAFile,
+		Timeout:    time.Duration(remoteSigner.TimeoutMs) * time.Millisecond,
+		MaxRetries: remoteSigner.MaxRetries,
+	}
+}
+
+func (p *serverParams) getBlockSinksConfig() []*blocksink.Config {
+	sinks := make([]*blocksink.Config, 0, len(p.rawConfig.BlockSinks))
+
+	for _, sink := range p.rawConfig.BlockSinks {
+		sinks = append(sinks, &blocksink.Config{
+			Name:      sink.Name,
+			Type:      blocksink.SinkType(sink.Type),
+			URL:       sink.URL,
+			Headers:   sink.Headers,
+			Endpoint:  sink.Endpoint,
+			TLSCAFile: sink.TLSCAFile,
+			Timeout:   time.Duration(sink.TimeoutMs) * time.Millisecond,
+		})
+	}
+
+	return sinks
+}
+
+// getAlertsConfig returns the configuration of the alerts, nil if they are disabled
+func (p *serverParams) getAlertsConfig() *alerting.Config {
+	alerts := p.rawConfig.Alerts
+	if alerts == nil || len(alerts.Webhooks) == 0 {
+		return nil
+	}
+
+	webhooks := make([]*alerting.WebhookConfig, 0, len(alerts.Webhooks))
+
+	for _, webhook := range alerts.Webhooks {
+		webhooks = append(webhooks, &alerting.WebhookConfig{
+			URL:     webhook.URL,
+			Headers: webhook.Headers,
+			Timeout: time.Duration(webhook.TimeoutMs) * time.Millisecond,
+		})
+	}
+
+	return &alerting.Config{
+		Webhooks:     webhooks,
+		ReorgDepth:   alerts.ReorgDepth,
+		StaleTimeout: time.Duration(alerts.StaleTimeout) * time.Second,
+		MinInterval:  time.Duration(alerts.MinInterval) * time.Second,
+	}
+}
+
+func (p *serverParams) getGRPCSecurityConfig() *server.GRPCSecurity {
+	security := p.rawConfig.GRPCSecurity
+	if security == nil {
+		return nil
+	}
+
+	return &server.GRPCSecurity{
+		TLSCertFile:     security.TLSCertFile,
+		TLSKeyFile:      security.TLSKeyFile,
+		TLSClientCAFile: security.TLSClientCAFile,
+		Token:           security.Token,
+		AdminToken:      security.AdminToken,
+	}
+}
+
 func (p *serverParams) getRestoreFilePath() *string {
 	if p.rawConfig.RestoreFile != "" {
 		return &p.rawConfig.RestoreFile
@@ -120,6 +388,19 @@ func (p *serverParams) getRestoreFilePath() *string {
 	return nil
 }
 
+// getJournalPath returns the path of the txpool journal, empty if journaling is disabled
+func (p *serverParams) getJournalPath() string {
+	if p.rawConfig.TxPool.NoJournal {
+		return ""
+	}
+
+	if p.rawConfig.TxPool.Journal != "" {
+		return p.rawConfig.TxPool.Journal
+	}
+
+	return filepath.Join(p.rawConfig.DataDir, "txpool", "transactions.rlp")
+}
+
 func (p *serverParams) setRawGRPCAddress(grpcAddress string) {
 	p.rawConfig.GRPCAddr = grpcAddress
 }
@@ -128,36 +409,168 @@ func (p *serverParams) setRawJSONRPCAddress(jsonRPCAddress string) {
 	p.rawConfig.JSONRPCAddr = jsonRPCAddress
 }
 
+// getLogFile returns the log file config, nil if the logs are written to the console
+func (p *serverParams) getLogFile() *server.LogFile {
+	if p.rawConfig.LogTo == "" {
+		return nil
+	}
+
+	return &server.LogFile{
+		Path:       p.rawConfig.LogTo,
+		MaxSize:    int64(p.rawConfig.LogMaxSize) * 1024 * 1024,
+		MaxBackups: p.rawConfig.LogMaxBackups,
+	}
+}
+
+// getMethodRateLimits returns the JSON-RPC rate limits per method, validated to be positive
+func (p *serverParams) getMethodRateLimits() map[string]uint64 {
+	limits := make(map[string]uint64, len(p.rawConfig.JSONRPCMethodRateLimits))
+
+	for method, limit := range p.rawConfig.JSONRPCMethodRateLimits {
+		limits[method] = uint64(limit)
+	}
+
+	return limits
+}
+
+// getMethodExecutionTimeouts returns the JSON-RPC execution timeouts per method, validated to be positive
+func (p *serverParams) getMethodExecutionTimeouts() map[string]time.Duration {
+	timeouts := make(map[string]time.Duration, len(p.rawConfig.JSONRPCMethodExecutionTimeouts))
+
+	for method, timeout := range p.rawConfig.JSONRPCMethodExecutionTimeouts {
+		timeouts[method] = time.Duration(timeout) * time.Second
+	}
+
+	return timeouts
+}
+
+// getScoringConfig returns the peer reputation params, the unset ones are taken from the defaults
+func (p *serverParams) getScoringConfig() *network.ScoringConfig {
+	config := network.DefaultScoringConfig()
+
+	for name, penalty := range p.rawConfig.Network.PeerPenalties {
+		config.Penalties[network.PeerPenalty(name)] = penalty
+	}
+
+	if p.rawConfig.Network.PeerBanThreshold != 0 {
+		config.BanThreshold = p.rawConfig.Network.PeerBanThreshold
+	}
+
+	if p.rawConfig.Network.PeerBanDuration != 0 {
+		config.BanDuration = time.Duration(p.rawConfig.Network.PeerBanDuration) * time.Second
+	}
+
+	return config
+}
+
 func (p *serverParams) generateConfig() *server.Config {
+	chainCfg := p.genesisConfig
+
+	// the proposer moves the gas limit of the genesis towards the target
+	if p.blockGasTarget > 0 {
+		chainCfg.Params.BlockGasTarget = p.blockGasTarget
+	}
+
 	return &server.Config{
-		Chain: p.genesisConfig,
+		Chain: chainCfg,
 		JSONRPC: &server.JSONRPC{
 			JSONRPCAddr:              p.jsonRPCAddress,
-			AccessControlAllowOrigin: p.corsAllowedOrigins,
+			AccessControlAllowOrigin: p.rawConfig.Headers.AccessControlAllowOrigins,
+			FeeHistoryLimit:          p.rawConfig.JSONRPCFeeHistoryLimit,
+			BlockRangeLimit:          p.rawConfig.JSONRPCBlockRangeLimit,
+			LogsLimit:                p.rawConfig.JSONRPCLogsLimit,
+			SubscriptionBufferSize:   p.rawConfig.JSONRPCSubscriptionBufferSize,
+			AllowedMethods:           p.rawConfig.JSONRPCAllowedMethods,
+			DeniedMethods:            p.rawConfig.JSONRPCDeniedMethods,
+			RateLimit:                p.rawConfig.JSONRPCRateLimit,
+			MethodRateLimits:         p.getMethodRateLimits(),
+			BatchLimit:               p.rawConfig.JSONRPCBatchLimit,
+			BatchWorkers:             p.rawConfig.JSONRPCBatchWorkers,
+			FilterTimeout:            time.Duration(p.rawConfig.JSONRPCFilterTimeout) * time.Second,
+			SyncDistance:             p.rawConfig.JSONRPCSyncDistance,
+			ExecutionTimeout:         time.Duration(p.rawConfig.JSONRPCExecutionTimeout) * time.Second,
+			MethodExecutionTimeouts:  p.getMethodExecutionTimeouts(),
+			ExecutionLimit:           p.rawConfig.JSONRPCExecutionLimit,
+			AccessControl:            p.jsonRPCAccessControl,
+			GraphQLAddr:              p.graphQLAddress,
 		},
-		GRPCAddr:   p.grpcAddress,
-		LibP2PAddr: p.libp2pAddress,
+		GRPCAddr:     p.grpcAddress,
+		GRPCSecurity: p.getGRPCSecurityConfig(),
+		LibP2PAddr:   p.libp2pAddress,
 		Telemetry: &server.Telemetry{
 			PrometheusAddr: p.prometheusAddress,
 		},
+		Health: &server.Health{
+			Addr:        p.healthAddress,
+			MaxBlockLag: p.rawConfig.HealthMaxBlockLag,
+			MinPeers:    p.rawConfig.HealthMinPeers,
+		},
 		Network: &network.Config{
 			NoDiscover:       p.rawConfig.Network.NoDiscover,
 			Addr:             p.libp2pAddress,
 			NatAddr:          p.natAddress,
+			NATMode:          p.natMode,
 			DNS:              p.dnsAddress,
 			DataDir:          p.rawConfig.DataDir,
 			MaxPeers:         p.rawConfig.Network.MaxPeers,
 			MaxInboundPeers:  p.rawConfig.Network.MaxInboundPeers,
 			MaxOutboundPeers: p.rawConfig.Network.MaxOutboundPeers,
-			Chain:            p.genesisConfig,
+			Chain:            chainCfg,
+			Scoring:          p.getScoringConfig(),
+			StaticPeers:      p.staticPeers,
+			TrustedPeers:     p.trustedPeers,
+			DiscoveryDNS:     p.rawConfig.Network.DiscoveryDNS,
 		},
-		DataDir:        p.rawConfig.DataDir,
-		Seal:           p.rawConfig.ShouldSeal,
-		PriceLimit:     p.rawConfig.TxPool.PriceLimit,
-		MaxSlots:       p.rawConfig.TxPool.MaxSlots,
-		SecretsManager: p.secretsConfig,
-		RestoreFile:    p.getRestoreFilePath(),
-		BlockTime:      p.rawConfig.BlockTime,
-		LogLevel:       hclog.LevelFromString(p.rawConfig.LogLevel),
+		DataDir:            p.rawConfig.DataDir,
+		Seal:               p.rawConfig.ShouldSeal,
+		PriceLimit:         p.rawConfig.TxPool.PriceLimit,
+		PriceBump:          p.rawConfig.TxPool.PriceBump,
+		MaxSlots:           p.rawConfig.TxPool.MaxSlots,
+		MaxAccountEnqueued: p.rawConfig.TxPool.MaxAccountEnqueued,
+		MaxAccountPromoted: p.rawConfig.TxPool.MaxAccountPending,
+		JournalPath:        p.getJournalPath(),
+		JournalRotate:      time.Duration(p.rawConfig.TxPool.JournalRotate) * time.Second,
+		SecretsManager:     p.secretsConfig,
+		SecretsPassphrase:  p.secretsPassphrase,
+		RestoreFile:        p.getRestoreFilePath(),
+		BlockTime:          p.rawConfig.BlockTime,
+		LogLevel:           p.logLevels.Default,
+		LogModuleLevels:    p.logLevels.Modules,
+		JSONLogFormat:      p.rawConfig.LogFormat == jsonLogFormat,
+		LogFile:            p.getLogFile(),
+
+		AllowLocalUnderpriced:     p.rawConfig.TxPool.AllowLocalUnderpriced,
+		UnderpricedEvictionBlocks: p.rawConfig.TxPool.UnderpricedEvictionBlocks,
+
+		IBFTSnapshotRetention: p.rawConfig.IBFTSnapshotRetention,
+		IBFTMsgRateLimit:      p.rawConfig.IBFTMsgRateLimit,
+		IBFTRemoteSigner:      p.getRemoteSignerConfig(),
+		IBFTWALDir:            p.rawConfig.IBFTWALDir,
+		BlockVanity:           p.rawConfig.BlockVanity,
+
+		AllowedFutureDrift: p.rawConfig.AllowedFutureDrift,
+
+		FastSync: p.rawConfig.SyncMode == fastSyncMode,
+
+		NonValidator: p.rawConfig.ConsensusRole == noneConsensusRole,
+
+		PruneState:     p.rawConfig.GCMode == prunedGCMode,
+		PruneRetention: p.rawConfig.GCRetention,
+
+		DBEngine: p.rawConfig.DBEngine,
+
+		StateCacheSize: p.rawConfig.Cache,
+
+		LevelDBWriteBuffer: p.rawConfig.LevelDBWriteBuffer,
+		LevelDBBlockCache:  p.rawConfig.LevelDBBlockCache,
+		LevelDBOpenFiles:   p.rawConfig.LevelDBOpenFiles,
+
+		IndexInternalTxs:  p.rawConfig.IndexInternalTxs,
+		ParallelExecution: p.rawConfig.ParallelExecution,
+
+		BadBlockDir: p.rawConfig.BadBlockDir,
+
+		BlockSinks: p.getBlockSinksConfig(),
+		Alerts:     p.getAlertsConfig(),
 	}
 }
@@ -3,6 +3,7 @@ package server
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/0xPolygon/polygon-edge/command"
 	"github.com/spf13/cobra"
@@ -36,7 +37,43 @@ func setFlags(cmd *cobra.Command) {
 		&params.rawConfig.LogLevel,
 		command.LogLevelFlag,
 		defaultConfig.LogLevel,
-		"the log level for console output",
+		"the log level, with optional levels per module such as info,ibft=debug,txpool=warn",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.ShutdownTimeout,
+		shutdownTimeoutFlag,
+		defaultConfig.ShutdownTimeout,
+		"the time in seconds given to the node to finish the block in progress and close "+
+			"the storage on shutdown, after which it exits anyway",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.LogFormat,
+		logFormatFlag,
+		defaultConfig.LogFormat,
+		"the format of the logs, either text or json",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.LogTo,
+		logToFlag,
+		defaultConfig.LogTo,
+		"the file the logs are written to instead of the console",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.LogMaxSize,
+		logMaxSizeFlag,
+		defaultConfig.LogMaxSize,
+		"the maximum size in megabytes of the log file before it is rotated, 0 to disable the rotation",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.LogMaxBackups,
+		logMaxBackupsFlag,
+		defaultConfig.LogMaxBackups,
+		"the number of rotated log files kept",
 	)
 
 	cmd.Flags().StringVar(
@@ -79,7 +116,8 @@ func setFlags(cmd *cobra.Command) {
 		&params.rawConfig.Network.NatAddr,
 		natFlag,
 		"",
-		"the external IP address without port, as can be seen by peers",
+		"the NAT traversal of the libp2p port: upnp or pmp to map the port on the gateway, "+
+			"extip:<ip> or the plain external IP address without port, as can be seen by peers, or none",
 	)
 
 	cmd.Flags().StringVar(
@@ -104,6 +142,14 @@ func setFlags(cmd *cobra.Command) {
 			"If omitted, the local FS secrets manager is used",
 	)
 
+	cmd.Flags().StringVar(
+		&params.rawConfig.PasswordFile,
+		passwordFileFlag,
+		"",
+		"the path to the file with the passphrase of the encrypted local secrets. "+
+			"If omitted, the passphrase is prompted for in the terminal",
+	)
+
 	cmd.Flags().StringVar(
 		&params.rawConfig.RestoreFile,
 		restoreFlag,
@@ -152,6 +198,50 @@ func setFlags(cmd *cobra.Command) {
 	// override default usage value
 	cmd.Flag(maxOutboundPeersFlag).DefValue = fmt.Sprintf("%d", defaultConfig.Network.MaxOutboundPeers)
 
+	cmd.Flags().StringToInt64Var(
+		&params.rawConfig.Network.PeerPenalties,
+		peerPenaltiesFlag,
+		defaultConfig.Network.PeerPenalties,
+		"the score decrements of the peer protocol violations, such as invalid_block=50. "+
+			"The categories are invalid_block, invalid_message and malformed_gossip",
+	)
+
+	cmd.Flags().Int64Var(
+		&params.rawConfig.Network.PeerBanThreshold,
+		peerBanThresholdFlag,
+		defaultConfig.Network.PeerBanThreshold,
+		"the peers whose score drops below the threshold are banned",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Network.PeerBanDuration,
+		peerBanDurationFlag,
+		defaultConfig.Network.PeerBanDuration,
+		"the duration of the first ban of a peer in seconds, doubled for every repeated ban",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.Network.StaticPeers,
+		staticPeerFlag,
+		[]string{},
+		"the libp2p address of a peer the client keeps connected with at all times, redialed as soon as it disconnects",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.Network.TrustedPeers,
+		trustedPeerFlag,
+		[]string{},
+		"the libp2p address or the ID of a peer exempt from the max peer limits and the banning",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.Network.DiscoveryDNS,
+		discoveryDNSFlag,
+		"",
+		"the domain publishing the bootnode multiaddrs or enodes in its TXT records, re-resolved periodically. "+
+			"Overrides the discovery DNS of the genesis",
+	)
+
 	cmd.Flags().Uint64Var(
 		&params.rawConfig.TxPool.PriceLimit,
 		priceLimitFlag,
@@ -162,6 +252,13 @@ func setFlags(cmd *cobra.Command) {
 		),
 	)
 
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.TxPool.PriceBump,
+		priceBumpFlag,
+		defaultConfig.TxPool.PriceBump,
+		"the minimum gas price increase (in percent) for a transaction to replace another one with the same nonce",
+	)
+
 	cmd.Flags().Uint64Var(
 		&params.rawConfig.TxPool.MaxSlots,
 		maxSlotsFlag,
@@ -169,18 +266,434 @@ func setFlags(cmd *cobra.Command) {
 		"maximum slots in the pool",
 	)
 
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.TxPool.MaxAccountEnqueued,
+		maxAccountEnqueuedFlag,
+		defaultConfig.TxPool.MaxAccountEnqueued,
+		"maximum number of enqueued transactions per account, 0 for no limit",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.TxPool.Journal,
+		journalFlag,
+		"",
+		"the file the locally submitted transactions are journaled to, replayed after a restart. "+
+			"Defaults to the txpool directory in the data directory",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.TxPool.JournalRotate,
+		journalRotateFlag,
+		defaultConfig.TxPool.JournalRotate,
+		"the interval in seconds the transaction journal is rewritten with the transactions still in the pool",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.TxPool.NoJournal,
+		noJournalFlag,
+		false,
+		"disable the journaling of the locally submitted transactions",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.TxPool.AllowLocalUnderpriced,
+		allowLocalUnderpricedFlag,
+		false,
+		"accept the locally submitted transactions below the price limit, "+
+			"and keep them in the pool below the base fee or when the pool is full",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.TxPool.UnderpricedEvictionBlocks,
+		underpricedEvictionBlocksFlag,
+		defaultConfig.TxPool.UnderpricedEvictionBlocks,
+		"the number of blocks a transaction can stay in the pool with its fee cap below the base fee "+
+			"before it is evicted, 0 for no eviction",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.TxPool.MaxAccountPending,
+		maxAccountPendingFlag,
+		defaultConfig.TxPool.MaxAccountPending,
+		"maximum number of pending (promoted) transactions per account, 0 for no limit",
+	)
+
 	cmd.Flags().Uint64Var(
 		&params.rawConfig.BlockTime,
 		blockTimeFlag,
 		defaultConfig.BlockTime,
-		"minimum block time in seconds",
+		"minimum block time in seconds, used until the block time schedule of the chain takes over",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.IBFTSnapshotRetention,
+		ibftSnapshotRetentionFlag,
+		defaultConfig.IBFTSnapshotRetention,
+		"the number of epoch boundary IBFT snapshots to keep, 0 keeps all of them",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.IBFTMsgRateLimit,
+		ibftMsgRateLimitFlag,
+		defaultConfig.IBFTMsgRateLimit,
+		"the maximum number of IBFT messages per second accepted from a single peer, 0 disables the limit",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.GRPCSecurity.TLSCertFile,
+		grpcTLSCertFlag,
+		"",
+		"the TLS certificate of the GRPC server, the GRPC connections are not encrypted if not set",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.GRPCSecurity.TLSKeyFile,
+		grpcTLSKeyFlag,
+		"",
+		"the TLS key of the GRPC server",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.GRPCSecurity.TLSClientCAFile,
+		grpcTLSClientCAFlag,
+		"",
+		"the CA the GRPC client certificates are verified with, the client certificates are not required if not set",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.GRPCSecurity.Token,
+		grpcTokenFlag,
+		"",
+		"the bearer token required by the GRPC operator methods, the methods are open if not set",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.GRPCSecurity.AdminToken,
+		grpcAdminTokenFlag,
+		"",
+		"the bearer token required by the GRPC operator methods that aren't read-only or the transaction submission",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.IBFTRemoteSigner.Endpoint,
+		ibftRemoteSignerFlag,
+		"",
+		"the gRPC address of the remote signer holding the validator key, the local validator key is used if not set",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.IBFTRemoteSigner.TLSCAFile,
+		ibftRemoteSignerTLSCAFlag,
+		"",
+		"the CA certificate used to verify the remote signer, the connection is not encrypted if not set",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.IBFTRemoteSigner.TimeoutMs,
+		ibftRemoteSignerTimeoutFlag,
+		defaultConfig.IBFTRemoteSigner.TimeoutMs,
+		"the timeout of a single remote signer request in milliseconds",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.IBFTRemoteSigner.MaxRetries,
+		ibftRemoteSignerRetriesFlag,
+		defaultConfig.IBFTRemoteSigner.MaxRetries,
+		"the number of times a failed remote signer request is retried",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.IBFTWALDir,
+		ibftWALDirFlag,
+		"",
+		"the directory of the log of the sent IBFT messages, replayed after a restart. "+
+			"Defaults to the wal directory in the consensus data directory",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.BlockVanity,
+		blockVanityFlag,
+		"",
+		"the vanity data written into the proposed blocks, as a 0x prefixed hex or UTF-8 string "+
+			"truncated or padded to 32 bytes",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.AllowedFutureDrift,
+		allowedFutureDriftFlag,
+		0,
+		"how far ahead of the local clock the block timestamps can be in seconds, the blocks beyond it "+
+			"are retried once the local clock catches up. Defaults to the drift of the chain, or one block time",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.SyncMode,
+		syncModeFlag,
+		defaultConfig.SyncMode,
+		"the sync mode of the node, fast or full. The fast sync writes the blocks up to a recent pivot "+
+			"block without executing them, and downloads its state from the peers",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.ConsensusRole,
+		consensusRoleFlag,
+		defaultConfig.ConsensusRole,
+		"the consensus role of the node, full or none. The nodes with the none role follow and relay "+
+			"the chain without a validator key, and never seal blocks",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.GCMode,
+		gcModeFlag,
+		defaultConfig.GCMode,
+		"the state garbage collection mode of the node, archive or pruned. The pruned nodes keep "+
+			"the state of the latest blocks only, the older states are not available",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.GCRetention,
+		gcRetentionFlag,
+		defaultConfig.GCRetention,
+		"the number of latest blocks whose state is kept in the pruned gc mode",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.DBEngine,
+		dbEngineFlag,
+		defaultConfig.DBEngine,
+		"the database engine of the data dir, leveldb or pebble. The engine of the existing databases "+
+			"is used if not set, leveldb for a new data dir. The existing databases are migrated "+
+			"to another engine by the db migrate command",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.Cache,
+		cacheFlag,
+		defaultConfig.Cache,
+		"the memory budget in MB of the cache of the state trie nodes and the contract code, "+
+			"a quarter of it holds the code. The cache is disabled if set to 0",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.LevelDBWriteBuffer,
+		levelDBWriteBufferFlag,
+		defaultConfig.LevelDBWriteBuffer,
+		"the size in MB of the memtable of the leveldb databases, a larger one is flushed less often. "+
+			"The leveldb default of 4 MB is used if set to 0",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.LevelDBBlockCache,
+		levelDBBlockCacheFlag,
+		defaultConfig.LevelDBBlockCache,
+		"the size in MB of the cache of the table blocks of the leveldb databases. "+
+			"The leveldb default of 8 MB is used if set to 0",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.LevelDBOpenFiles,
+		levelDBOpenFilesFlag,
+		defaultConfig.LevelDBOpenFiles,
+		"the number of the table files each leveldb database keeps open. "+
+			"The leveldb default of 500 is used if set to 0",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.IndexInternalTxs,
+		indexInternalTxsFlag,
+		defaultConfig.IndexInternalTxs,
+		"index the value transfers of the call frames of the executed blocks, served by "+
+			"edge_getInternalTransactions. The calls of the transactions are traced while the blocks "+
+			"are imported, the blocks written before it was set or by the fast sync are not indexed",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.ParallelExecution,
+		parallelExecutionFlag,
+		defaultConfig.ParallelExecution,
+		"execute the transactions of the blocks optimistically in parallel, the transactions reading "+
+			"the state written by the ones before them in the block are executed again serially",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.BadBlockDir,
+		badBlockDirFlag,
+		"",
+		"the directory the rejected blocks are dumped to, along with the rejection reason and "+
+			"the decoded extra data. The last rejected blocks are only kept in memory if not set",
 	)
 
 	cmd.Flags().StringArrayVar(
-		&params.corsAllowedOrigins,
+		&params.rawConfig.Headers.AccessControlAllowOrigins,
 		corsOriginFlag,
 		defaultConfig.Headers.AccessControlAllowOrigins,
-		"the CORS header indicating whether any JSON-RPC response can be shared with the specified origin",
+		"the origins the JSON-RPC responses can be shared with and the web sockets can be opened from, "+
+			"such as * for any origin. No origin is allowed if none is set",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCFeeHistoryLimit,
+		jsonRPCFeeHistoryLimitFlag,
+		defaultConfig.JSONRPCFeeHistoryLimit,
+		"the maximum number of blocks returned by a single eth_feeHistory request, 0 for no limit",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCBlockRangeLimit,
+		jsonRPCBlockRangeLimitFlag,
+		defaultConfig.JSONRPCBlockRangeLimit,
+		"the maximum range of blocks queried by a single eth_getLogs request, 0 for no limit",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCLogsLimit,
+		jsonRPCLogsLimitFlag,
+		defaultConfig.JSONRPCLogsLimit,
+		"the maximum number of logs returned by a single eth_getLogs request, 0 for no limit",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCSubscriptionBufferSize,
+		jsonRPCSubscriptionBufferSizeFlag,
+		defaultConfig.JSONRPCSubscriptionBufferSize,
+		"the number of updates buffered for a web socket subscription before the new ones are dropped",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.JSONRPCAllowedMethods,
+		jsonRPCAllowedMethodsFlag,
+		defaultConfig.JSONRPCAllowedMethods,
+		"the JSON-RPC methods served, by name or namespace such as eth_*. All the methods are served if none is set",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.JSONRPCDeniedMethods,
+		jsonRPCDeniedMethodsFlag,
+		defaultConfig.JSONRPCDeniedMethods,
+		"the JSON-RPC methods not served, by name or namespace such as debug_*. They are not served even if allowed",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCRateLimit,
+		jsonRPCRateLimitFlag,
+		defaultConfig.JSONRPCRateLimit,
+		"the maximum number of JSON-RPC requests per second accepted from a single IP address, 0 for no limit",
+	)
+
+	cmd.Flags().StringToInt64Var(
+		&params.rawConfig.JSONRPCMethodRateLimits,
+		jsonRPCMethodRateLimitsFlag,
+		defaultConfig.JSONRPCMethodRateLimits,
+		"the maximum number of requests per second accepted from a single IP address for the methods, "+
+			"such as eth_getLogs=10",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCBatchLimit,
+		jsonRPCBatchLimitFlag,
+		defaultConfig.JSONRPCBatchLimit,
+		"the maximum number of requests of a single JSON-RPC batch, 0 for no limit",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCBatchWorkers,
+		jsonRPCBatchWorkersFlag,
+		defaultConfig.JSONRPCBatchWorkers,
+		"the number of requests of a JSON-RPC batch handled at the same time, 1 to handle them in sequence",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCFilterTimeout,
+		jsonRPCFilterTimeoutFlag,
+		defaultConfig.JSONRPCFilterTimeout,
+		"the time in seconds after which the JSON-RPC filters that are not polled are removed",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCSyncDistance,
+		jsonRPCSyncDistanceFlag,
+		defaultConfig.JSONRPCSyncDistance,
+		"the number of blocks the node can be behind the network head, and not be reported as syncing by eth_syncing",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCExecutionTimeout,
+		jsonRPCExecutionTimeoutFlag,
+		defaultConfig.JSONRPCExecutionTimeout,
+		"the time in seconds after which the JSON-RPC executions of the EVM, such as eth_call, are aborted, 0 for no timeout",
+	)
+
+	cmd.Flags().StringToInt64Var(
+		&params.rawConfig.JSONRPCMethodExecutionTimeouts,
+		jsonRPCMethodExecTimeoutsFlag,
+		defaultConfig.JSONRPCMethodExecutionTimeouts,
+		"the execution timeouts in seconds of the methods, overriding the default one, such as debug_traceTransaction=30",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.JSONRPCExecutionLimit,
+		jsonRPCExecutionLimitFlag,
+		defaultConfig.JSONRPCExecutionLimit,
+		"the maximum number of JSON-RPC executions of the EVM running at the same time, the others are queued. "+
+			"0 for no limit",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.JSONRPCReadCIDRs,
+		jsonRPCReadCIDRsFlag,
+		defaultConfig.JSONRPCReadCIDRs,
+		"the networks allowed to call the JSON-RPC read methods, such as 10.0.0.0/8. Any client is allowed if none is set",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.JSONRPCWriteCIDRs,
+		jsonRPCWriteCIDRsFlag,
+		defaultConfig.JSONRPCWriteCIDRs,
+		"the networks allowed to call the JSON-RPC methods sending transactions. Any client is allowed if none is set",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.JSONRPCAdminCIDRs,
+		jsonRPCAdminCIDRsFlag,
+		defaultConfig.JSONRPCAdminCIDRs,
+		"the networks allowed to call the JSON-RPC debug_* and evm_* methods. Any client is allowed if none is set",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.JSONRPCTrustedProxies,
+		jsonRPCTrustedProxiesFlag,
+		defaultConfig.JSONRPCTrustedProxies,
+		"the networks of the proxies the JSON-RPC client address is read from the X-Forwarded-For header of",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.GraphQLAddr,
+		graphQLAddrFlag,
+		"",
+		"the address and port for the GraphQL server of the block, transaction, account and logs queries "+
+			"(address:port), not served if not set. If only port is defined (:port) it will bind to 0.0.0.0:port",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.HealthAddr,
+		healthAddrFlag,
+		"",
+		"the address and port for the /health and /ready HTTP endpoints (address:port). "+
+			"If only port is defined (:port) it will bind to 0.0.0.0:port",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.HealthMaxBlockLag,
+		healthMaxBlockLagFlag,
+		defaultConfig.HealthMaxBlockLag,
+		"the number of blocks the node can be behind its best peer, and be reported as ready",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.HealthMinPeers,
+		healthMinPeersFlag,
+		defaultConfig.HealthMinPeers,
+		"the number of peers the node has to be connected to, to be reported as ready",
 	)
 
 	setDevFlags(cmd)
@@ -255,5 +768,9 @@ func runServerLoop(
 		return err
 	}
 
-	return helper.HandleSignals(serverInstance.Close, outputter)
+	return helper.HandleSignals(
+		serverInstance.Close,
+		outputter,
+		time.Duration(params.rawConfig.ShutdownTimeout)*time.Second,
+	)
 }
@@ -7,10 +7,16 @@ import (
 )
 
 type StatusResult struct {
-	ChainID            int64  `json:"chain_id"`
-	CurrentBlockNumber int64  `json:"current_block_number"`
-	CurrentBlockHash   string `json:"current_block_hash"`
-	LibP2PAddress      string `json:"libp2p_address"`
+	ChainID            int64    `json:"chain_id"`
+	CurrentBlockNumber int64    `json:"current_block_number"`
+	CurrentBlockHash   string   `json:"current_block_hash"`
+	LibP2PAddress      string   `json:"libp2p_address"`
+	AdvertisedAddrs    []string `json:"advertised_addresses"`
+	Healthy            bool     `json:"healthy"`
+	Ready              bool     `json:"ready"`
+	BestPeerNumber     int64    `json:"best_peer_number"`
+	Peers              int64    `json:"peers"`
+	HealthFailures     []string `json:"health_failures"`
 }
 
 func (r *StatusResult) GetOutput() string {
@@ -22,6 +28,12 @@ func (r *StatusResult) GetOutput() string {
 		fmt.Sprintf("Current Block Number (base 10)|%d", r.CurrentBlockNumber),
 		fmt.Sprintf("Current Block Hash|%s", r.CurrentBlockHash),
 		fmt.Sprintf("Libp2p Address|%s", r.LibP2PAddress),
+		fmt.Sprintf("Advertised Addresses|%s", r.AdvertisedAddrs),
+		fmt.Sprintf("Healthy|%t", r.Healthy),
+		fmt.Sprintf("Ready|%t", r.Ready),
+		fmt.Sprintf("Best Peer Block Number|%d", r.BestPeerNumber),
+		fmt.Sprintf("Peers|%d", r.Peers),
+		fmt.Sprintf("Health Failures|%s", r.HealthFailures),
 	}))
 
 	return buffer.String()
@@ -19,6 +19,7 @@ func GetCommand() *cobra.Command {
 	}
 
 	helper.RegisterGRPCAddressFlag(statusCmd)
+	helper.RegisterGRPCClientFlags(statusCmd)
 
 	return statusCmd
 }
@@ -39,6 +40,12 @@ func runCommand(cmd *cobra.Command, _ []string) {
 		CurrentBlockNumber: statusResponse.Current.Number,
 		CurrentBlockHash:   statusResponse.Current.Hash,
 		LibP2PAddress:      statusResponse.P2PAddr,
+		AdvertisedAddrs:    statusResponse.AdvertisedAddrs,
+		Healthy:            statusResponse.Health.GetHealthy(),
+		Ready:              statusResponse.Health.GetReady(),
+		BestPeerNumber:     statusResponse.Health.GetBestPeer(),
+		Peers:              statusResponse.Health.GetPeers(),
+		HealthFailures:     statusResponse.Health.GetFailures(),
 	})
 }
 
@@ -8,6 +8,8 @@ import (
 
 type TxPoolStatusResult struct {
 	Transactions uint64 `json:"transactions"`
+	Slots        uint64 `json:"slots"`
+	MaxSlots     uint64 `json:"max_slots"`
 }
 
 func (r *TxPoolStatusResult) GetOutput() string {
@@ -16,6 +18,7 @@ func (r *TxPoolStatusResult) GetOutput() string {
 	buffer.WriteString("\n[TXPOOL STATUS]\n")
 	buffer.WriteString(helper.FormatKV([]string{
 		fmt.Sprintf("Number of transactions in pool:|%d", r.Transactions),
+		fmt.Sprintf("Slots used in pool:|%d / %d", r.Slots, r.MaxSlots),
 	}))
 	buffer.WriteString("\n")
 
@@ -31,6 +31,8 @@ func runCommand(cmd *cobra.Command, _ []string) {
 
 	outputter.SetCommandResult(&TxPoolStatusResult{
 		Transactions: statusResponse.Length,
+		Slots:        statusResponse.Slots,
+		MaxSlots:     statusResponse.MaxSlots,
 	})
 }
 
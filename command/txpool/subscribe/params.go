@@ -7,13 +7,15 @@ var (
 )
 
 var (
-	addedFlag          = "added"
-	promotedFlag       = "promoted"
-	demotedFlag        = "demoted"
-	enqueuedFlag       = "enqueued"
-	droppedFlag        = "dropped"
-	prunedPromotedFlag = "pruned-promoted"
-	prunedEnqueuedFlag = "pruned-enqueued"
+	addedFlag              = "added"
+	promotedFlag           = "promoted"
+	demotedFlag            = "demoted"
+	enqueuedFlag           = "enqueued"
+	droppedFlag            = "dropped"
+	prunedPromotedFlag     = "pruned-promoted"
+	prunedEnqueuedFlag     = "pruned-enqueued"
+	replacedFlag           = "replaced"
+	evictedUnderpricedFlag = "evicted-underpriced"
 )
 
 type subscribeParams struct {
@@ -24,13 +26,15 @@ type subscribeParams struct {
 func (sp *subscribeParams) initEventMap() {
 	falseRaw := false
 	sp.eventSubscriptionMap = map[proto.EventType]*bool{
-		proto.EventType_ADDED:           &falseRaw,
-		proto.EventType_ENQUEUED:        &falseRaw,
-		proto.EventType_PROMOTED:        &falseRaw,
-		proto.EventType_DROPPED:         &falseRaw,
-		proto.EventType_DEMOTED:         &falseRaw,
-		proto.EventType_PRUNED_PROMOTED: &falseRaw,
-		proto.EventType_PRUNED_ENQUEUED: &falseRaw,
+		proto.EventType_ADDED:               &falseRaw,
+		proto.EventType_ENQUEUED:            &falseRaw,
+		proto.EventType_PROMOTED:            &falseRaw,
+		proto.EventType_DROPPED:             &falseRaw,
+		proto.EventType_DEMOTED:             &falseRaw,
+		proto.EventType_PRUNED_PROMOTED:     &falseRaw,
+		proto.EventType_PRUNED_ENQUEUED:     &falseRaw,
+		proto.EventType_REPLACED:            &falseRaw,
+		proto.EventType_EVICTED_UNDERPRICED: &falseRaw,
 	}
 }
 
@@ -65,5 +69,7 @@ func (sp *subscribeParams) setAllEvents() {
 		proto.EventType_DEMOTED,
 		proto.EventType_PRUNED_PROMOTED,
 		proto.EventType_PRUNED_ENQUEUED,
+		proto.EventType_REPLACED,
+		proto.EventType_EVICTED_UNDERPRICED,
 	}
 }
@@ -69,6 +69,18 @@ func setFlags(cmd *cobra.Command) {
 		false,
 		"should subscribe to pruned enqueued tx events in the TxPool",
 	)
+	cmd.Flags().BoolVar(
+		params.eventSubscriptionMap[txpoolProto.EventType_REPLACED],
+		replacedFlag,
+		false,
+		"should subscribe to replaced tx events in the TxPool",
+	)
+	cmd.Flags().BoolVar(
+		params.eventSubscriptionMap[txpoolProto.EventType_EVICTED_UNDERPRICED],
+		evictedUnderpricedFlag,
+		false,
+		"should subscribe to tx events evicted below the base fee in the TxPool",
+	)
 }
 
 func runCommand(cmd *cobra.Command, _ []string) {
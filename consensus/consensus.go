@@ -3,13 +3,16 @@ package consensus
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
 	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/protocol"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
 	"github.com/0xPolygon/polygon-edge/txpool"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
@@ -70,8 +73,58 @@ type ConsensusParams struct {
 	Grpc           *grpc.Server
 	Logger         hclog.Logger
 	Metrics        *Metrics
+	SyncerMetrics  *protocol.Metrics
 	SecretsManager secrets.SecretsManager
 	BlockTime      uint64
+
+	// SnapshotRetention is the number of epoch boundary snapshots
+	// the consensus keeps, if it uses snapshots. 0 keeps all of them
+	SnapshotRetention uint64
+
+	// MessageRateLimit is the number of consensus messages per second
+	// accepted from a single peer. 0 disables the limit
+	MessageRateLimit uint64
+
+	// RemoteSigner is the remote signer holding the validator key, if set.
+	// The local validator key is used otherwise
+	RemoteSigner *RemoteSignerConfig
+
+	// WALDir is the directory of the log of the consensus messages sent by the node.
+	// The consensus picks a directory in its data directory if not set
+	WALDir string
+
+	// AllowedFutureDrift is how far ahead of the local clock the block timestamps can be, in seconds.
+	// The drift of the chain is used if not set
+	AllowedFutureDrift uint64
+
+	// BlockVanity is the vanity data the proposer writes into the built blocks, in hex or UTF-8.
+	// Its format is defined by the consensus
+	BlockVanity string
+
+	// FastSync enables the fast sync of the chain, if the consensus supports it
+	FastSync bool
+
+	// NonValidator runs the consensus without a validator key. The node follows
+	// and relays the chain, but never takes part in the consensus
+	NonValidator bool
+
+	// StateStorage is the storage of the state trie, served to the peers and written by the fast sync
+	StateStorage itrie.Storage
+}
+
+// RemoteSignerConfig is the configuration of the remote signer
+type RemoteSignerConfig struct {
+	// Endpoint is the gRPC address of the remote signer
+	Endpoint string
+
+	// TLSCAFile is the CA certificate used to verify the remote signer, if set
+	TLSCAFile string
+
+	// Timeout is the timeout of a single signing request
+	Timeout time.Duration
+
+	// MaxRetries is the number of times a failed signing request is retried
+	MaxRetries uint64
 }
 
 // Factory is the factory function to create a discovery backend
@@ -2,10 +2,13 @@ package dev
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/consensus"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
 	"github.com/0xPolygon/polygon-edge/state"
@@ -18,14 +21,36 @@ import (
 type Dev struct {
 	logger hclog.Logger
 
-	notifyCh chan struct{}
-	closeCh  chan struct{}
+	notifyCh   chan struct{}
+	closeCh    chan struct{}
+	loopDoneCh chan struct{}
 
 	interval uint64
 	txpool   *txpool.TxPool
 
 	blockchain *blockchain.Blockchain
 	executor   *state.Executor
+
+	// sealLock is held while a block is sealed, by the loop or on demand,
+	// and guards the time settings and the snapshots
+	sealLock      sync.Mutex
+	nextTimestamp uint64 // The timestamp of the next block, if set, used once
+	timeOffset    uint64 // The seconds added to the clock for the timestamps of the blocks
+	snapshots     []*snapshot
+	lastSnapshot  uint64
+}
+
+var (
+	ErrTimestampNotAfterHead = errors.New("the timestamp is not after the head block")
+)
+
+// snapshot is the state of the chain and the pool the dev mode can revert to
+type snapshot struct {
+	id            uint64
+	header        *types.Header
+	txs           []*types.Transaction
+	nextTimestamp uint64
+	timeOffset    uint64
 }
 
 // Factory implements the base factory method
@@ -63,6 +88,8 @@ func (d *Dev) Initialize() error {
 
 // Start starts the consensus mechanism
 func (d *Dev) Start() error {
+	d.loopDoneCh = make(chan struct{})
+
 	go d.run()
 
 	return nil
@@ -82,6 +109,8 @@ func (d *Dev) nextNotify() chan struct{} {
 }
 
 func (d *Dev) run() {
+	defer close(d.loopDoneCh)
+
 	d.logger.Info("consensus started")
 
 	for {
@@ -93,21 +122,157 @@ func (d *Dev) run() {
 		}
 
 		// There are new transactions in the pool, try to seal them
-		header := d.blockchain.Header()
-		if err := d.writeNewBlock(header); err != nil {
+		d.sealLock.Lock()
+
+		if err := d.writeNewBlock(d.blockchain.Header()); err != nil {
 			d.logger.Error("failed to mine block", "err", err)
 		}
+
+		d.sealLock.Unlock()
+	}
+}
+
+// Mine seals a block on demand, with the timestamp if not zero
+func (d *Dev) Mine(timestamp uint64) error {
+	d.sealLock.Lock()
+	defer d.sealLock.Unlock()
+
+	if timestamp != 0 {
+		if err := d.checkTimestamp(timestamp); err != nil {
+			return err
+		}
+
+		d.nextTimestamp = timestamp
+	}
+
+	return d.writeNewBlock(d.blockchain.Header())
+}
+
+// SetNextBlockTimestamp sets the timestamp of the next sealed block
+func (d *Dev) SetNextBlockTimestamp(timestamp uint64) error {
+	d.sealLock.Lock()
+	defer d.sealLock.Unlock()
+
+	if err := d.checkTimestamp(timestamp); err != nil {
+		return err
 	}
+
+	d.nextTimestamp = timestamp
+
+	return nil
+}
+
+// IncreaseTime moves the clock of the sealed blocks forward by the seconds,
+// and returns the total of the seconds added so far
+func (d *Dev) IncreaseTime(seconds uint64) uint64 {
+	d.sealLock.Lock()
+	defer d.sealLock.Unlock()
+
+	d.timeOffset += seconds
+
+	return d.timeOffset
+}
+
+// Snapshot saves the head block, the transactions of the pool and the time settings,
+// and returns the id to revert to them
+func (d *Dev) Snapshot() uint64 {
+	d.sealLock.Lock()
+	defer d.sealLock.Unlock()
+
+	d.lastSnapshot++
+
+	d.snapshots = append(d.snapshots, &snapshot{
+		id:            d.lastSnapshot,
+		header:        d.blockchain.Header(),
+		txs:           d.txpool.Snapshot(),
+		nextTimestamp: d.nextTimestamp,
+		timeOffset:    d.timeOffset,
+	})
+
+	return d.lastSnapshot
+}
+
+// Revert moves the chain back to the head block of the snapshot, with its state,
+// and restores the transactions of the pool and the time settings.
+// The snapshot and the later ones are dropped, false is returned if it is not found
+func (d *Dev) Revert(id uint64) (bool, error) {
+	d.sealLock.Lock()
+	defer d.sealLock.Unlock()
+
+	index := -1
+
+	for i, snap := range d.snapshots {
+		if snap.id == id {
+			index = i
+
+			break
+		}
+	}
+
+	if index == -1 {
+		return false, nil
+	}
+
+	snap := d.snapshots[index]
+
+	if err := d.blockchain.RewindHead(snap.header.Hash); err != nil {
+		return false, err
+	}
+
+	d.txpool.Restore(snap.txs)
+
+	d.nextTimestamp = snap.nextTimestamp
+	d.timeOffset = snap.timeOffset
+	d.snapshots = d.snapshots[:index]
+
+	d.logger.Info("reverted to snapshot", "id", id, "number", snap.header.Number)
+
+	return true, nil
+}
+
+// checkTimestamp checks if the timestamp is after the one of the head block
+func (d *Dev) checkTimestamp(timestamp uint64) error {
+	if head := d.blockchain.Header(); timestamp <= head.Timestamp {
+		return fmt.Errorf("%w: %d <= %d", ErrTimestampNotAfterHead, timestamp, head.Timestamp)
+	}
+
+	return nil
+}
+
+// blockTimestamp returns the timestamp of the block sealed on top of the parent,
+// the one set for the next block if any, or the clock moved by the time offset
+func (d *Dev) blockTimestamp(parent *types.Header) uint64 {
+	if d.nextTimestamp != 0 {
+		timestamp := d.nextTimestamp
+		d.nextTimestamp = 0
+
+		return timestamp
+	}
+
+	timestamp := uint64(time.Now().Unix()) + d.timeOffset
+	if timestamp < parent.Timestamp {
+		timestamp = parent.Timestamp
+	}
+
+	return timestamp
 }
 
 type transitionInterface interface {
 	Write(txn *types.Transaction) error
 }
 
-func (d *Dev) writeTransactions(gasLimit uint64, transition transitionInterface) []*types.Transaction {
+func (d *Dev) writeTransactions(
+	gasLimit uint64,
+	baseFee uint64,
+	sizeLimits chain.SizeLimits,
+	transition transitionInterface,
+) []*types.Transaction {
 	var successful []*types.Transaction
 
-	d.txpool.Prepare()
+	// size is the total size of the transactions of the block
+	size := uint64(0)
+
+	d.txpool.Prepare(baseFee)
 
 	for {
 		tx := d.txpool.Peek()
@@ -115,6 +280,17 @@ func (d *Dev) writeTransactions(gasLimit uint64, transition transitionInterface)
 			break
 		}
 
+		if err := sizeLimits.VerifyTx(tx); err != nil {
+			d.txpool.Drop(tx)
+
+			continue
+		}
+
+		// the block is full, as when the gas limit is reached
+		if err := sizeLimits.VerifyBlockSize(size, tx); err != nil {
+			break
+		}
+
 		if tx.ExceedsBlockGasLimit(gasLimit) {
 			d.txpool.Drop(tx)
 
@@ -137,6 +313,7 @@ func (d *Dev) writeTransactions(gasLimit uint64, transition transitionInterface)
 		d.txpool.Pop(tx)
 
 		successful = append(successful, tx)
+		size += tx.Size()
 	}
 
 	d.logger.Info("picked out txns from pool", "num", len(successful), "remaining", d.txpool.Length())
@@ -145,7 +322,7 @@ func (d *Dev) writeTransactions(gasLimit uint64, transition transitionInterface)
 }
 
 // writeNewBLock generates a new block based on transactions from the pool,
-// and writes them to the blockchain
+// and writes them to the blockchain. The seal lock is held by the caller
 func (d *Dev) writeNewBlock(parent *types.Header) error {
 	// Generate the base block
 	num := parent.Number
@@ -153,7 +330,7 @@ func (d *Dev) writeNewBlock(parent *types.Header) error {
 		ParentHash: parent.Hash,
 		Number:     num + 1,
 		GasLimit:   parent.GasLimit, // Inherit from parent for now, will need to adjust dynamically later.
-		Timestamp:  uint64(time.Now().Unix()),
+		Timestamp:  d.blockTimestamp(parent),
 	}
 
 	// calculate gas limit based on parent header
@@ -164,6 +341,9 @@ func (d *Dev) writeNewBlock(parent *types.Header) error {
 
 	header.GasLimit = gasLimit
 
+	// set the base fee past the EIP-1559 fork
+	header.BaseFee = d.blockchain.CalculateBaseFee(parent)
+
 	miner, err := d.GetBlockCreator(header)
 	if err != nil {
 		return err
@@ -175,7 +355,8 @@ func (d *Dev) writeNewBlock(parent *types.Header) error {
 		return err
 	}
 
-	txns := d.writeTransactions(gasLimit, transition)
+	sizeLimits := d.blockchain.Config().SizeLimitsAt(header.Number)
+	txns := d.writeTransactions(gasLimit, header.BaseFee, sizeLimits, transition)
 
 	// Commit the changes
 	_, root := transition.Commit()
@@ -193,7 +374,7 @@ func (d *Dev) writeNewBlock(parent *types.Header) error {
 	})
 
 	// Write the block to the blockchain
-	if err := d.blockchain.WriteBlock(block); err != nil {
+	if err := d.blockchain.WriteBlock(block, blockchain.SourceSealer); err != nil {
 		return err
 	}
 
@@ -238,8 +419,13 @@ func (d *Dev) Seal(block *types.Block, ctx context.Context) (*types.Block, error
 	return nil, nil
 }
 
+// Close stops the consensus, once the block being sealed, if any, is written
 func (d *Dev) Close() error {
 	close(d.closeCh)
 
+	if d.loopDoneCh != nil {
+		<-d.loopDoneCh
+	}
+
 	return nil
 }
@@ -0,0 +1,351 @@
+package ibft
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/crypto/bls"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/secrets"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	errMissingBLSPublicKey   = errors.New("no BLS public key for the validator")
+	errInvalidBLSPossession  = errors.New("invalid BLS proof of possession")
+	errInvalidAggregatedSeal = errors.New("invalid aggregated committed seal")
+)
+
+// blsSealVerifier verifies the aggregated committed seals against the BLS public keys of the validators
+type blsSealVerifier struct {
+	keys map[types.Address]*bls.PublicKey
+}
+
+// VerifyAggregatedSeal checks the signature is the aggregation of the BLS signatures of all the signers
+func (v *blsSealVerifier) VerifyAggregatedSeal(signers []types.Address, msg, signature []byte) error {
+	pubs := make([]*bls.PublicKey, len(signers))
+
+	for indx, signer := range signers {
+		pub, ok := v.keys[signer]
+		if !ok {
+			return fmt.Errorf("%w %s", errMissingBLSPublicKey, signer)
+		}
+
+		pubs[indx] = pub
+	}
+
+	sig, err := bls.UnmarshalSignature(signature)
+	if err != nil {
+		return err
+	}
+
+	if !sig.VerifyAggregated(pubs, msg) {
+		return errInvalidAggregatedSeal
+	}
+
+	return nil
+}
+
+// hasKey checks if the BLS public key of the validator is known
+func (v *blsSealVerifier) hasKey(validator types.Address) bool {
+	_, ok := v.keys[validator]
+
+	return ok
+}
+
+// getBLSPublicKeys reads the BLS public keys of the validators, keyed by the validator addresses.
+// Each key comes with the proof of possession of its secret key, so that no validator can register
+// a key derived from the keys of the others, and forge their aggregated seals alone
+func getBLSPublicKeys(ibftConfig map[string]interface{}) (map[types.Address]*bls.PublicKey, error) {
+	rawKeys, ok := ibftConfig["blsPublicKeys"]
+	if !ok {
+		return nil, errors.New("no BLS public keys of the validators")
+	}
+
+	entries, ok := rawKeys.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid type assertion")
+	}
+
+	keys := make(map[types.Address]*bls.PublicKey, len(entries))
+
+	for addr, rawEntry := range entries {
+		entry, ok := rawEntry.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("invalid type assertion")
+		}
+
+		encodedKey, ok := entry["publicKey"].(string)
+		if !ok {
+			return nil, errors.New("invalid type assertion")
+		}
+
+		encodedProof, ok := entry["proofOfPossession"].(string)
+		if !ok {
+			return nil, errors.New("invalid type assertion")
+		}
+
+		buf, err := hex.DecodeHex(encodedKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BLS public key of %s, %w", addr, err)
+		}
+
+		pub, err := bls.UnmarshalPublicKey(buf)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BLS public key of %s, %w", addr, err)
+		}
+
+		if buf, err = hex.DecodeHex(encodedProof); err != nil {
+			return nil, fmt.Errorf("%w of %s, %v", errInvalidBLSPossession, addr, err)
+		}
+
+		proof, err := bls.UnmarshalSignature(buf)
+		if err != nil {
+			return nil, fmt.Errorf("%w of %s, %v", errInvalidBLSPossession, addr, err)
+		}
+
+		if !proof.VerifyPossession(pub) {
+			return nil, fmt.Errorf("%w of %s", errInvalidBLSPossession, addr)
+		}
+
+		keys[types.StringToAddress(addr)] = pub
+	}
+
+	return keys, nil
+}
+
+// setupAggregatedSeal reads the aggregated seal fork in params, and sets up the verifier
+// of the aggregated committed seals against the BLS public keys of the validators
+func (i *Ibft) setupAggregatedSeal() error {
+	i.aggregatedSealBlock = nil
+	i.blsVerifier = nil
+
+	rawBlock, ok := i.config.Config["aggregatedSealBlock"]
+	if !ok {
+		return nil
+	}
+
+	readBlock, ok := rawBlock.(float64)
+	if !ok {
+		return errors.New("invalid type assertion")
+	}
+
+	if i.protocol == QBFTProtocol {
+		return errors.New("the aggregated committed seals are not supported by the QBFT extra data")
+	}
+
+	// the aggregated seals can't be copied to the child blocks
+	if i.epochReward != nil {
+		return errors.New("the aggregated committed seals are not supported along with the epoch reward")
+	}
+
+	// the validators have to be voted in, so that the ones without BLS public keys are refused
+	for _, mechanism := range i.mechanisms {
+		if mechanism.GetType() != PoA {
+			return errors.New("the aggregated committed seals are only supported by the PoA mechanism")
+		}
+	}
+
+	keys, err := getBLSPublicKeys(i.config.Config)
+	if err != nil {
+		return err
+	}
+
+	forkBlock := uint64(readBlock)
+
+	i.aggregatedSealBlock = &forkBlock
+	i.blsVerifier = &blsSealVerifier{keys: keys}
+
+	return nil
+}
+
+// aggregatesSeals checks if the committed seals are aggregated at the given height
+func (i *Ibft) aggregatesSeals(height uint64) bool {
+	return i.aggregatedSealBlock != nil && height >= *i.aggregatedSealBlock
+}
+
+// aggregatedSealVerifier returns the verifier of the aggregated committed seals, nil if they aren't supported
+func (i *Ibft) aggregatedSealVerifier() AggregatedSealVerifier {
+	if i.blsVerifier == nil {
+		return nil
+	}
+
+	return i.blsVerifier
+}
+
+// canVoteIn checks if the candidate can be voted in the validator set at the given height.
+// Past the aggregated seal fork, the seals of the validators without BLS public keys can't be aggregated
+func (i *Ibft) canVoteIn(height uint64, candidate types.Address) bool {
+	return !i.aggregatesSeals(height) || i.blsVerifier.hasKey(candidate)
+}
+
+// verifyCandidateBLSKey checks that the candidate voted in by the header has a BLS public key,
+// past the aggregated seal fork
+func (i *Ibft) verifyCandidateBLSKey(snap *Snapshot, header *types.Header) error {
+	candidate, nonce, err := getHeaderVote(header)
+	if err != nil {
+		return err
+	}
+
+	if candidate == types.ZeroAddress || nonce != nonceAuthVote || snap.Set.Includes(candidate) {
+		return nil
+	}
+
+	if !i.canVoteIn(header.Number, candidate) {
+		return fmt.Errorf("%w %s", errMissingBLSPublicKey, candidate)
+	}
+
+	return nil
+}
+
+// createBLSKey sets the BLS key of the validator, generated if not present in the secrets manager
+func (i *Ibft) createBLSKey() error {
+	if i.aggregatedSealBlock == nil || i.blsKey != nil {
+		return nil
+	}
+
+	if i.secretsManager.HasSecret(secrets.ValidatorBLSKey) {
+		key, err := bls.ReadValidatorKey(i.secretsManager)
+		if err != nil {
+			return fmt.Errorf("unable to read validator BLS key from Secrets Manager, %w", err)
+		}
+
+		i.blsKey = key
+	} else {
+		key, encodedKey, err := bls.GenerateAndEncodeKey()
+		if err != nil {
+			return fmt.Errorf("unable to generate validator BLS key for Secrets Manager, %w", err)
+		}
+
+		if err := i.secretsManager.SetSecret(secrets.ValidatorBLSKey, encodedKey); err != nil {
+			return fmt.Errorf("unable to save validator BLS key to Secrets Manager, %w", err)
+		}
+
+		i.blsKey = key
+	}
+
+	proof, err := i.blsKey.ProvePossession()
+	if err != nil {
+		return err
+	}
+
+	i.logger.Info(
+		"validator BLS key",
+		"public", hex.EncodeToHex(i.blsKey.PublicKey().Marshal()),
+		"proof of possession", hex.EncodeToHex(proof.Marshal()),
+	)
+
+	return nil
+}
+
+// signCommittedSeal signs the commit message of the header with the validator key,
+// or with the BLS key past the aggregated seal fork
+func (i *Ibft) signCommittedSeal(h *types.Header, round *uint64) ([]byte, error) {
+	if !i.aggregatesSeals(h.Number) {
+		return writeCommittedSeal(i.signer, h, round, i.protocol)
+	}
+
+	return writeBLSCommittedSeal(i.blsKey, h, round)
+}
+
+// writeBLSCommittedSeal signs the commit message for the header with the BLS key.
+// The aggregated committed seals are only supported by the IBFT protocol
+func writeBLSCommittedSeal(key *bls.SecretKey, h *types.Header, round *uint64) ([]byte, error) {
+	msg, err := committedSealMsg(h, round, IBFTProtocol)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := key.Sign(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return sig.Marshal(), nil
+}
+
+// aggregateCommittedSeals aggregates the valid BLS committed seals of the validators.
+// The invalid seals are left out, there must be at least 2F+1 valid ones
+func (v *blsSealVerifier) aggregateCommittedSeals(
+	validators ValidatorSet,
+	seals map[types.Address][]byte,
+	msg []byte,
+) (*AggregatedSeal, error) {
+	aggregated := &AggregatedSeal{}
+	sigs := []*bls.Signature{}
+
+	for indx, validator := range validators {
+		seal, ok := seals[validator]
+		if !ok {
+			continue
+		}
+
+		pub, ok := v.keys[validator]
+		if !ok {
+			continue
+		}
+
+		sig, err := bls.UnmarshalSignature(seal)
+		if err != nil || !sig.Verify(pub, msg) {
+			continue
+		}
+
+		aggregated.Bitmap = bitmapSet(aggregated.Bitmap, indx)
+		sigs = append(sigs, sig)
+	}
+
+	if len(sigs) < validators.QuorumSize() {
+		return nil, errNotEnoughCommittedSeals
+	}
+
+	sig, err := bls.AggregateSignatures(sigs)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregated.Signature = sig.Marshal()
+
+	return aggregated, nil
+}
+
+// writeAggregatedCommittedSeal aggregates the committed seals of the current state,
+// and writes the aggregated seal, and the round it was signed in (if not nil), to the extra data of the header
+func (i *Ibft) writeAggregatedCommittedSeal(h *types.Header, round *uint64) (*types.Header, error) {
+	msg, err := committedSealMsg(h, round, i.protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	seals := make(map[types.Address][]byte, len(i.state.committed))
+
+	for addr, commit := range i.state.committed {
+		seal, err := hex.DecodeHex(commit.Seal)
+		if err != nil {
+			continue
+		}
+
+		seals[addr] = seal
+	}
+
+	aggregated, err := i.blsVerifier.aggregateCommittedSeals(i.state.validators, seals, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	h = h.Copy()
+
+	extra, err := GetIbftExtra(h)
+	if err != nil {
+		return nil, err
+	}
+
+	extra.CommittedSeal = nil
+	extra.AggregatedCommittedSeal = aggregated
+	extra.RoundNumber = round
+
+	if err := PutIbftExtra(h, extra); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
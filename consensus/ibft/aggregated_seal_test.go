@@ -0,0 +1,315 @@
+package ibft
+
+import (
+	"context"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/crypto/bls"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// newAggregatedSealConfig returns the IBFT params aggregating the committed seals from the given block,
+// along with the BLS keys of the accounts and their proofs of possession
+func newAggregatedSealConfig(
+	t *testing.T,
+	pool *testerAccountPool,
+	block uint64,
+) (map[string]interface{}, map[string]*bls.SecretKey) {
+	t.Helper()
+
+	keys := map[string]*bls.SecretKey{}
+	publicKeys := map[string]interface{}{}
+
+	for _, account := range pool.accounts {
+		key, err := bls.GenerateKey()
+		assert.NoError(t, err)
+
+		proof, err := key.ProvePossession()
+		assert.NoError(t, err)
+
+		keys[account.alias] = key
+		publicKeys[account.Address().String()] = map[string]interface{}{
+			"publicKey":         hex.EncodeToHex(key.PublicKey().Marshal()),
+			"proofOfPossession": hex.EncodeToHex(proof.Marshal()),
+		}
+	}
+
+	return map[string]interface{}{
+		"aggregatedSealBlock": float64(block),
+		"blsPublicKeys":       publicKeys,
+	}, keys
+}
+
+func TestAggregatedSeal_SealAndVerify(t *testing.T) {
+	i := newMockIbft(t, []string{"A", "B", "C", "D"}, "A")
+
+	config, keys := newAggregatedSealConfig(t, i.pool, 1)
+	i.config = &consensus.Config{Config: config}
+
+	assert.NoError(t, i.setupAggregatedSeal())
+
+	i.blsKey = keys["A"]
+	i.syncer = &mockSyncer{}
+	i.txpool = &mockTxPool{}
+
+	i.setState(ValidateState)
+	i.state.block = i.DummyBlock()
+
+	// the commit message of the node carries its BLS seal
+	i.sendCommitMsg()
+	assert.Len(t, i.respMsg, 1)
+
+	i.addMessage(&proto.MessageReq{
+		From: "A",
+		Type: proto.MessageReq_Commit,
+		View: proto.ViewMsg(1, 0),
+		Seal: i.respMsg[0].Seal,
+	})
+
+	// B and C sign the commit message, D signs it with a key that isn't its own
+	for alias, key := range map[string]*bls.SecretKey{
+		"B": keys["B"],
+		"C": keys["C"],
+		"D": keys["A"],
+	} {
+		seal, err := writeBLSCommittedSeal(key, i.state.block.Header, nil)
+		assert.NoError(t, err)
+
+		i.addMessage(&proto.MessageReq{
+			From: alias,
+			Type: proto.MessageReq_Commit,
+			View: proto.ViewMsg(1, 0),
+			Seal: hex.EncodeToHex(seal),
+		})
+	}
+
+	block := i.state.block
+	assert.NoError(t, i.insertBlock(block))
+
+	extra, err := GetIbftExtra(block.Header)
+	assert.NoError(t, err)
+	assert.Nil(t, extra.CommittedSeal)
+	assert.NotNil(t, extra.AggregatedCommittedSeal)
+
+	// the invalid seal of D is left out of the aggregated seal
+	validators := i.pool.ValidatorSet()
+
+	signers, err := extra.AggregatedCommittedSeal.Signers(validators)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Address{validators[0], validators[1], validators[2]}, signers)
+
+	committers, err := RecoverCommitters(block.Header, IBFTProtocol)
+	assert.NoError(t, err)
+	assert.Equal(t, signers, committers)
+
+	// the sealed block is verified once decoded
+	decoded := &types.Block{}
+	assert.NoError(t, decoded.UnmarshalRLP(block.MarshalRLP()))
+	assert.NoError(t, verifyCommittedSeals(decoded.Header, validators, IBFTProtocol, i.aggregatedSealVerifier()))
+
+	// Failed - D is added to the signers of the aggregated seal
+	extra.AggregatedCommittedSeal.Bitmap = bitmapSet(extra.AggregatedCommittedSeal.Bitmap, 3)
+
+	tampered := block.Header.Copy()
+	assert.NoError(t, PutIbftExtra(tampered, extra))
+	assert.ErrorIs(
+		t,
+		verifyCommittedSeals(tampered, validators, IBFTProtocol, i.aggregatedSealVerifier()),
+		errInvalidAggregatedSeal,
+	)
+}
+
+func TestAggregatedSeal_NotEnoughSeals(t *testing.T) {
+	i := newMockIbft(t, []string{"A", "B", "C", "D"}, "A")
+
+	config, keys := newAggregatedSealConfig(t, i.pool, 0)
+	i.config = &consensus.Config{Config: config}
+
+	assert.NoError(t, i.setupAggregatedSeal())
+
+	i.state.block = i.DummyBlock()
+
+	for _, alias := range []string{"A", "B"} {
+		seal, err := writeBLSCommittedSeal(keys[alias], i.state.block.Header, nil)
+		assert.NoError(t, err)
+
+		i.addMessage(&proto.MessageReq{
+			From: alias,
+			Type: proto.MessageReq_Commit,
+			View: proto.ViewMsg(1, 0),
+			Seal: hex.EncodeToHex(seal),
+		})
+	}
+
+	_, err := i.writeStateCommittedSeals(i.state.block.Header, nil)
+	assert.ErrorIs(t, err, errNotEnoughCommittedSeals)
+}
+
+func TestAggregatedSeal_Setup(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	setup := func(config map[string]interface{}) (*Ibft, error) {
+		i := &Ibft{
+			config: &consensus.Config{Config: config},
+		}
+
+		return i, i.setupAggregatedSeal()
+	}
+
+	// the seals aren't aggregated by default
+	i, err := setup(map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.False(t, i.aggregatesSeals(100))
+	assert.Nil(t, i.aggregatedSealVerifier())
+
+	config, _ := newAggregatedSealConfig(t, pool, 10)
+
+	i, err = setup(config)
+	assert.NoError(t, err)
+	assert.False(t, i.aggregatesSeals(9))
+	assert.True(t, i.aggregatesSeals(10))
+	assert.Equal(t, i.blsVerifier, i.aggregatedSealVerifier())
+
+	// the BLS public keys of the validators are required
+	_, err = setup(map[string]interface{}{
+		"aggregatedSealBlock": float64(10),
+	})
+	assert.Error(t, err)
+
+	keys := config["blsPublicKeys"].(map[string]interface{})
+	keyA := keys[pool.get("A").Address().String()].(map[string]interface{})
+	keyB := keys[pool.get("B").Address().String()].(map[string]interface{})
+
+	_, err = setup(map[string]interface{}{
+		"aggregatedSealBlock": float64(10),
+		"blsPublicKeys": map[string]interface{}{
+			pool.get("A").Address().String(): map[string]interface{}{
+				"publicKey":         "0x1234",
+				"proofOfPossession": keyA["proofOfPossession"],
+			},
+		},
+	})
+	assert.ErrorIs(t, err, bls.ErrInvalidPublicKey)
+
+	// the BLS public keys have to come with the proofs of possession of their secret keys
+	_, err = setup(map[string]interface{}{
+		"aggregatedSealBlock": float64(10),
+		"blsPublicKeys": map[string]interface{}{
+			pool.get("A").Address().String(): map[string]interface{}{
+				"publicKey": keyA["publicKey"],
+			},
+		},
+	})
+	assert.Error(t, err)
+
+	_, err = setup(map[string]interface{}{
+		"aggregatedSealBlock": float64(10),
+		"blsPublicKeys": map[string]interface{}{
+			pool.get("A").Address().String(): map[string]interface{}{
+				"publicKey":         keyA["publicKey"],
+				"proofOfPossession": keyB["proofOfPossession"],
+			},
+		},
+	})
+	assert.ErrorIs(t, err, errInvalidBLSPossession)
+
+	// the aggregated seals can't be copied to the child blocks
+	i = &Ibft{
+		config:      &consensus.Config{Config: config},
+		epochReward: &chain.EpochReward{},
+	}
+	assert.Error(t, i.setupAggregatedSeal())
+
+	// the validators have to be voted in
+	i = &Ibft{
+		config: &consensus.Config{Config: config},
+	}
+	initIbftMechanism(PoS, i)
+	assert.Error(t, i.setupAggregatedSeal())
+}
+
+func TestAggregatedSeal_ValidatorJoinsAfterFork(t *testing.T) {
+	i := newMockIbft(t, []string{"A", "B", "C", "D"}, "A")
+
+	// E registers its BLS public key, F doesn't
+	i.pool.add("E")
+
+	config, keys := newAggregatedSealConfig(t, i.pool, 1)
+	i.config = &consensus.Config{Config: config}
+
+	assert.NoError(t, i.setupAggregatedSeal())
+
+	i.pool.add("F")
+
+	snap, err := i.getLatestSnapshot()
+	assert.NoError(t, err)
+
+	vote := func(alias string, number uint64) *types.Header {
+		return &types.Header{
+			Number: number,
+			Miner:  i.pool.get(alias).Address(),
+			Nonce:  nonceAuthVote,
+		}
+	}
+
+	// the validators without BLS public keys can't be voted in past the fork
+	assert.NoError(t, i.verifyCandidateBLSKey(snap, vote("E", 1)))
+	assert.ErrorIs(t, i.verifyCandidateBLSKey(snap, vote("F", 1)), errMissingBLSPublicKey)
+
+	i.aggregatedSealBlock = nil
+	assert.NoError(t, i.verifyCandidateBLSKey(snap, vote("F", 1)))
+	assert.NoError(t, i.setupAggregatedSeal())
+
+	// the node doesn't propose them either
+	o := &operator{ibft: i.Ibft}
+
+	_, err = o.Propose(context.Background(), &proto.Candidate{
+		Address: i.pool.get("E").Address().String(),
+		Auth:    true,
+	})
+	assert.NoError(t, err)
+
+	_, err = o.Propose(context.Background(), &proto.Candidate{
+		Address: i.pool.get("F").Address().String(),
+		Auth:    true,
+	})
+	assert.ErrorIs(t, err, errMissingBLSPublicKey)
+
+	// once voted in, the seal of E is aggregated and verified along with the others
+	validators := i.pool.ValidatorSet()[:5]
+
+	h := &types.Header{Number: 2}
+	putIbftExtraValidators(h, validators)
+
+	msg, err := committedSealMsg(h, nil, IBFTProtocol)
+	assert.NoError(t, err)
+
+	seals := map[types.Address][]byte{}
+
+	for _, alias := range []string{"B", "C", "D", "E"} {
+		seal, err := writeBLSCommittedSeal(keys[alias], h, nil)
+		assert.NoError(t, err)
+
+		seals[i.pool.get(alias).Address()] = seal
+	}
+
+	seal, err := i.blsVerifier.aggregateCommittedSeals(validators, seals, msg)
+	assert.NoError(t, err)
+
+	signers, err := seal.Signers(validators)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Address(validators[1:]), signers)
+
+	extra, err := GetIbftExtra(h)
+	assert.NoError(t, err)
+
+	extra.AggregatedCommittedSeal = seal
+	assert.NoError(t, PutIbftExtra(h, extra))
+	assert.NoError(t, verifyCommittedSeals(h, validators, IBFTProtocol, i.aggregatedSealVerifier()))
+}
@@ -0,0 +1,134 @@
+package ibft
+
+import (
+	"context"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/types"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// DecodedExtra is the IBFT extra data of a header in a readable form,
+// along with the signers recovered from its seals
+type DecodedExtra struct {
+	Vanity              string          `json:"vanity"`
+	Validators          []types.Address `json:"validators"`
+	Seal                string          `json:"seal"`
+	CommittedSeals      []string        `json:"committedSeals"`
+	AggregatedBitmap    string          `json:"aggregatedBitmap,omitempty"`
+	AggregatedSignature string          `json:"aggregatedSignature,omitempty"`
+	RoundNumber         *uint64         `json:"roundNumber,omitempty"`
+
+	// Proposer and Committers are not set if the seals can't be recovered,
+	// RecoveryError is the reason then
+	Proposer      *types.Address  `json:"proposer,omitempty"`
+	Committers    []types.Address `json:"committers,omitempty"`
+	RecoveryError string          `json:"recoveryError,omitempty"`
+}
+
+// DecodeExtra implements the blockchain.ExtraDecoder interface method,
+// the extra data of the rejected blocks is decoded for the diagnostics
+func (i *Ibft) DecodeExtra(header *types.Header) (interface{}, error) {
+	return decodeExtra(header, i.protocol)
+}
+
+// decodeExtra decodes the extra data of the header of the protocol, and recovers its signers.
+// The failure to recover the signers is part of the decoded extra data
+func decodeExtra(header *types.Header, protocol Protocol) (*DecodedExtra, error) {
+	extra, err := getExtra(header, protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := &DecodedExtra{
+		Vanity:         hex.EncodeToHex(GetVanity(header)),
+		Validators:     extra.Validators,
+		Seal:           hex.EncodeToHex(extra.Seal),
+		CommittedSeals: make([]string, len(extra.CommittedSeal)),
+		RoundNumber:    extra.RoundNumber,
+	}
+
+	for indx, seal := range extra.CommittedSeal {
+		decoded.CommittedSeals[indx] = hex.EncodeToHex(seal)
+	}
+
+	if extra.AggregatedCommittedSeal != nil {
+		decoded.AggregatedBitmap = hex.EncodeToHex(extra.AggregatedCommittedSeal.Bitmap)
+		decoded.AggregatedSignature = hex.EncodeToHex(extra.AggregatedCommittedSeal.Signature)
+	}
+
+	// the genesis block is not sealed
+	if header.Number == 0 {
+		return decoded, nil
+	}
+
+	proposer, err := ecrecoverFromHeader(header)
+	if err != nil {
+		decoded.RecoveryError = err.Error()
+
+		return decoded, nil
+	}
+
+	decoded.Proposer = &proposer
+
+	if decoded.Committers, err = RecoverCommitters(header, protocol); err != nil {
+		decoded.RecoveryError = err.Error()
+	}
+
+	return decoded, nil
+}
+
+// BadBlocks returns the last blocks rejected by the node, along with their decoded IBFT extra data
+func (o *operator) BadBlocks(ctx context.Context, req *empty.Empty) (*proto.BadBlocksResp, error) {
+	badBlocks := o.ibft.blockchain.BadBlocks()
+
+	resp := &proto.BadBlocksResp{
+		Blocks: make([]*proto.BadBlock, len(badBlocks)),
+	}
+
+	for indx, bad := range badBlocks {
+		resp.Blocks[indx] = toProtoBadBlock(bad)
+	}
+
+	return resp, nil
+}
+
+func toProtoBadBlock(bad *blockchain.BadBlock) *proto.BadBlock {
+	block := &proto.BadBlock{
+		Number: bad.Block.Number(),
+		Hash:   bad.Block.Hash().String(),
+		Reason: bad.Reason,
+		Time:   bad.Time.Unix(),
+		Rlp:    bad.Block.MarshalRLP(),
+	}
+
+	extra, ok := bad.Extra.(*DecodedExtra)
+	if !ok {
+		return block
+	}
+
+	block.Extra = &proto.InspectResp{
+		Number:     block.Number,
+		Hash:       block.Hash,
+		Vanity:     extra.Vanity,
+		Validators: make([]string, len(extra.Validators)),
+		Committers: make([]string, len(extra.Committers)),
+		Quorum:     uint64(ValidatorSet(extra.Validators).QuorumSize()),
+	}
+
+	for indx, validator := range extra.Validators {
+		block.Extra.Validators[indx] = validator.String()
+	}
+
+	if extra.Proposer != nil {
+		block.Extra.Proposer = extra.Proposer.String()
+	}
+
+	for indx, committer := range extra.Committers {
+		block.Extra.Committers[indx] = committer.String()
+	}
+
+	return block
+}
@@ -0,0 +1,267 @@
+package ibft
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/contracts/staking"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	stakingHelper "github.com/0xPolygon/polygon-edge/helper/staking"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	errDuplicateValidator     = errors.New("the validator set has duplicate validators")
+	errTooManyValidators      = errors.New("the validator set is larger than the max validator count")
+	errInvalidEpochValidators = errors.New("the validators of the epoch block do not match the validator contract")
+)
+
+// ContractPoSMechanism defines specific hooks for the contract based Proof of Stake IBFT mechanism.
+// The epoch blocks carry the validator set of the next epoch in their extra data, read from the
+// validator contract at the state of their parent
+type ContractPoSMechanism struct {
+	BaseConsensusMechanism
+	// Params
+	Contract          types.Address // The contract returning the validator set
+	Selector          []byte        // The selector of the contract method returning the validator set
+	MaxValidatorCount uint64
+}
+
+// ContractPoSFactory initializes the required data
+// for the contract based Proof of Stake mechanism
+func ContractPoSFactory(ibft *Ibft, params *IBFTFork) (ConsensusMechanism, error) {
+	pos := &ContractPoSMechanism{
+		BaseConsensusMechanism: BaseConsensusMechanism{
+			mechanismType: ContractPoS,
+			ibft:          ibft,
+		},
+	}
+
+	if err := pos.initializeParams(params); err != nil {
+		return nil, err
+	}
+
+	pos.initializeHookMap()
+
+	return pos, nil
+}
+
+// IsAvailable returns indicates if mechanism should be called at given height
+func (pos *ContractPoSMechanism) IsAvailable(hookType HookType, height uint64) bool {
+	switch hookType {
+	case AcceptStateLogHook, VerifyBlockHook, CalculateProposerHook:
+		return pos.IsInRange(height)
+	case EpochValidatorsHook, VerifyEpochValidatorsHook, ProcessHeadersHook:
+		return pos.IsInRange(height) && pos.ibft.IsLastOfEpoch(height)
+	default:
+		return false
+	}
+}
+
+// initializeParams initializes mechanism parameters from chain config
+func (pos *ContractPoSMechanism) initializeParams(params *IBFTFork) error {
+	if err := pos.BaseConsensusMechanism.initializeParams(params); err != nil {
+		return err
+	}
+
+	if params == nil || params.ValidatorContract == nil {
+		return errors.New(`"validatorContract" must be specified in ContractPoS fork`)
+	}
+
+	selector, err := hex.DecodeHex(params.ValidatorSelector)
+	if err != nil || len(selector) != 4 {
+		return fmt.Errorf(`"validatorSelector" must be a 4 bytes hex selector: %q`, params.ValidatorSelector)
+	}
+
+	pos.Contract = *params.ValidatorContract
+	pos.Selector = selector
+
+	if params.MaxValidatorCount == nil {
+		pos.MaxValidatorCount = stakingHelper.MaxValidatorCount
+	} else {
+		pos.MaxValidatorCount = params.MaxValidatorCount.Value
+	}
+
+	return nil
+}
+
+// calculateProposerHook calculates the next proposer based on the last
+func (pos *ContractPoSMechanism) calculateProposerHook(hookParam interface{}) error {
+	params, ok := hookParam.(*calculateProposerHookParams)
+	if !ok {
+		return ErrInvalidHookParam
+	}
+
+	return pos.ibft.calcProposer(params.parent, params.lastProposer)
+}
+
+// acceptStateLogHook logs the current snapshot
+func (pos *ContractPoSMechanism) acceptStateLogHook(snapParam interface{}) error {
+	// Cast the param to a *Snapshot
+	snap, ok := snapParam.(*Snapshot)
+	if !ok {
+		return ErrInvalidHookParam
+	}
+
+	// Log the info message
+	pos.ibft.logger.Info(
+		"current snapshot",
+		"validators",
+		len(snap.Set),
+	)
+
+	return nil
+}
+
+// verifyBlockHook checks if the block is an epoch block and if it has any transactions
+func (pos *ContractPoSMechanism) verifyBlockHook(blockParam interface{}) error {
+	block, ok := blockParam.(*types.Block)
+	if !ok {
+		return ErrInvalidHookParam
+	}
+
+	if pos.ibft.IsLastOfEpoch(block.Number()) && len(block.Transactions) > 0 {
+		return errBlockVerificationFailed
+	}
+
+	return nil
+}
+
+// epochValidatorsHookParams are the params passed into the epoch validators hooks
+type epochValidatorsHookParams struct {
+	parent *types.Header
+	header *types.Header
+}
+
+// epochValidatorsHook writes the validator set of the next epoch into the epoch block
+func (pos *ContractPoSMechanism) epochValidatorsHook(hookParam interface{}) error {
+	params, ok := hookParam.(*epochValidatorsHookParams)
+	if !ok {
+		return ErrInvalidHookParam
+	}
+
+	validators, err := pos.getNextValidators(params.parent)
+	if err != nil {
+		return err
+	}
+
+	putIbftExtraValidators(params.header, validators)
+
+	return nil
+}
+
+// verifyEpochValidatorsHook checks that the validator set of the epoch block is the one of the contract
+func (pos *ContractPoSMechanism) verifyEpochValidatorsHook(hookParam interface{}) error {
+	params, ok := hookParam.(*epochValidatorsHookParams)
+	if !ok {
+		return ErrInvalidHookParam
+	}
+
+	extra, err := GetIbftExtra(params.header)
+	if err != nil {
+		return err
+	}
+
+	validators, err := pos.getNextValidators(params.parent)
+	if err != nil {
+		return err
+	}
+
+	if !validators.Equal((*ValidatorSet)(&extra.Validators)) {
+		return errInvalidEpochValidators
+	}
+
+	return nil
+}
+
+// processHeadersHook sets the validator set of the epoch block into the snapshot
+func (pos *ContractPoSMechanism) processHeadersHook(hookParam interface{}) error {
+	params, ok := hookParam.(*processHeadersHookParams)
+	if !ok {
+		return ErrInvalidHookParam
+	}
+
+	validators, err := unpackValidatorsFromIbftExtra(params.header)
+	if err != nil {
+		return err
+	}
+
+	params.snap.Set = validators
+
+	return nil
+}
+
+// initializeHookMap registers the hooks that the contract based PoS mechanism
+// should have
+func (pos *ContractPoSMechanism) initializeHookMap() {
+	// Create the hook map
+	pos.hookMap = make(map[HookType]func(interface{}) error)
+
+	// Register the AcceptStateLogHook
+	pos.hookMap[AcceptStateLogHook] = pos.acceptStateLogHook
+
+	// Register the VerifyBlockHook
+	pos.hookMap[VerifyBlockHook] = pos.verifyBlockHook
+
+	// Register the CalculateProposerHook
+	pos.hookMap[CalculateProposerHook] = pos.calculateProposerHook
+
+	// Register the EpochValidatorsHook
+	pos.hookMap[EpochValidatorsHook] = pos.epochValidatorsHook
+
+	// Register the VerifyEpochValidatorsHook
+	pos.hookMap[VerifyEpochValidatorsHook] = pos.verifyEpochValidatorsHook
+
+	// Register the ProcessHeadersHook
+	pos.hookMap[ProcessHeadersHook] = pos.processHeadersHook
+}
+
+// ShouldWriteTransactions indicates if transactions should be written to a block
+func (pos *ContractPoSMechanism) ShouldWriteTransactions(blockNumber uint64) bool {
+	// Epoch blocks should be empty
+	return pos.IsInRange(blockNumber) && !pos.ibft.IsLastOfEpoch(blockNumber)
+}
+
+// getNextValidators reads the validator set of the next epoch from the contract
+// at the state of the parent of the epoch block, and validates it
+func (pos *ContractPoSMechanism) getNextValidators(parent *types.Header) (ValidatorSet, error) {
+	transition, err := pos.ibft.executor.BeginTxn(parent.StateRoot, parent, types.ZeroAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// the call is made from the zero address, so all the nodes read the same set
+	validators, err := staking.QueryContractValidators(transition, types.ZeroAddress, pos.Contract, pos.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the validator set from %s: %w", pos.Contract, err)
+	}
+
+	if err := pos.validateValidators(validators); err != nil {
+		return nil, err
+	}
+
+	return validators, nil
+}
+
+// validateValidators checks the validator set read from the contract
+func (pos *ContractPoSMechanism) validateValidators(validators ValidatorSet) error {
+	if len(validators) == 0 {
+		return errEmptyValidatorSet
+	}
+
+	if uint64(len(validators)) > pos.MaxValidatorCount {
+		return fmt.Errorf("%w, %d > %d", errTooManyValidators, len(validators), pos.MaxValidatorCount)
+	}
+
+	seen := make(map[types.Address]struct{}, len(validators))
+
+	for _, validator := range validators {
+		if _, ok := seen[validator]; ok {
+			return fmt.Errorf("%w, %s", errDuplicateValidator, validator)
+		}
+
+		seen[validator] = struct{}{}
+	}
+
+	return nil
+}
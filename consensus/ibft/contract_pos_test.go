@@ -0,0 +1,263 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/helper/common"
+	stakingHelper "github.com/0xPolygon/polygon-edge/helper/staking"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	validatorContract = types.StringToAddress("1000")
+	validatorSelector = "0xca1e7814"
+
+	addr1 = types.StringToAddress("1")
+	addr2 = types.StringToAddress("2")
+	addr3 = types.StringToAddress("3")
+)
+
+// validatorSetCode returns the code of a contract returning the ABI encoded validator set
+// on any call
+func validatorSetCode(validators []types.Address) []byte {
+	code := []byte{
+		0x60, 0x20, 0x60, 0x00, 0x52, // offset of the array
+		0x60, byte(len(validators)), 0x60, 0x20, 0x52, // length of the array
+	}
+
+	for idx, validator := range validators {
+		code = append(code, 0x73) // PUSH20
+		code = append(code, validator.Bytes()...)
+		code = append(code, 0x60, byte(0x40+32*idx), 0x52)
+	}
+
+	return append(code, 0x60, byte(0x40+32*len(validators)), 0x60, 0x00, 0xf3)
+}
+
+// revertCode is the code of a contract reverting on any call
+var revertCode = []byte{0x60, 0x00, 0x60, 0x00, 0xfd}
+
+// newContractPoSIbft returns an IBFT using the contract based PoS mechanism with the validator contract
+// deployed at the genesis, and the genesis header
+func newContractPoSIbft(t *testing.T, code []byte, maxValidatorCount uint64) (*Ibft, *types.Header) {
+	t.Helper()
+
+	executor := state.NewExecutor(&chain.Params{
+		Forks:   chain.AllForksEnabled,
+		ChainID: 100,
+	}, itrie.NewState(itrie.NewMemoryStorage()), hclog.NewNullLogger())
+	executor.SetRuntime(evm.NewEVM())
+	executor.GetHash = func(*types.Header) state.GetHashByNumber {
+		return func(uint64) types.Hash {
+			return types.ZeroHash
+		}
+	}
+
+	genesis := &types.Header{
+		GasLimit: 5000000,
+		StateRoot: executor.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+			validatorContract: {
+				Code: code,
+			},
+		}),
+	}
+
+	ibft := &Ibft{
+		logger:    hclog.NewNullLogger(),
+		executor:  executor,
+		epochSize: TestEpochSize,
+	}
+
+	mechanism, err := ContractPoSFactory(ibft, &IBFTFork{
+		Type:              ContractPoS,
+		From:              common.JSONNumber{Value: 0},
+		ValidatorContract: &validatorContract,
+		ValidatorSelector: validatorSelector,
+		MaxValidatorCount: &common.JSONNumber{Value: maxValidatorCount},
+	})
+	assert.NoError(t, err)
+
+	ibft.mechanisms = []ConsensusMechanism{mechanism}
+
+	return ibft, genesis
+}
+
+func TestContractPoSFactory(t *testing.T) {
+	tests := []struct {
+		name              string
+		params            *IBFTFork
+		selector          []byte
+		maxValidatorCount uint64
+		err               bool
+	}{
+		{
+			name: "should use the default max validator count",
+			params: &IBFTFork{
+				ValidatorContract: &validatorContract,
+				ValidatorSelector: validatorSelector,
+			},
+			selector:          []byte{0xca, 0x1e, 0x78, 0x14},
+			maxValidatorCount: stakingHelper.MaxValidatorCount,
+		},
+		{
+			name: "should use the max validator count of the fork",
+			params: &IBFTFork{
+				ValidatorContract: &validatorContract,
+				ValidatorSelector: validatorSelector,
+				MaxValidatorCount: &common.JSONNumber{Value: 10},
+			},
+			selector:          []byte{0xca, 0x1e, 0x78, 0x14},
+			maxValidatorCount: 10,
+		},
+		{
+			name: "should return error if the contract is missing",
+			params: &IBFTFork{
+				ValidatorSelector: validatorSelector,
+			},
+			err: true,
+		},
+		{
+			name: "should return error if the selector is not 4 bytes",
+			params: &IBFTFork{
+				ValidatorContract: &validatorContract,
+				ValidatorSelector: "0xca1e78",
+			},
+			err: true,
+		},
+	}
+
+	for _, testcase := range tests {
+		t.Run(testcase.name, func(t *testing.T) {
+			mechanism, err := ContractPoSFactory(&Ibft{}, testcase.params)
+			if testcase.err {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			pos, ok := mechanism.(*ContractPoSMechanism)
+			assert.True(t, ok)
+
+			assert.Equal(t, validatorContract, pos.Contract)
+			assert.Equal(t, testcase.selector, pos.Selector)
+			assert.Equal(t, testcase.maxValidatorCount, pos.MaxValidatorCount)
+		})
+	}
+}
+
+func TestContractPoS_IsAvailable(t *testing.T) {
+	ibft, _ := newContractPoSIbft(t, nil, 10)
+	mechanism := ibft.mechanisms[0]
+
+	// the validator set is only rotated at the epoch blocks
+	assert.False(t, mechanism.IsAvailable(EpochValidatorsHook, TestEpochSize-1))
+	assert.True(t, mechanism.IsAvailable(EpochValidatorsHook, TestEpochSize))
+	assert.True(t, mechanism.IsAvailable(VerifyEpochValidatorsHook, TestEpochSize))
+	assert.True(t, mechanism.IsAvailable(ProcessHeadersHook, TestEpochSize))
+
+	// the epoch blocks are empty
+	assert.True(t, mechanism.ShouldWriteTransactions(TestEpochSize-1))
+	assert.False(t, mechanism.ShouldWriteTransactions(TestEpochSize))
+}
+
+func TestContractPoS_getNextValidators(t *testing.T) {
+	tests := []struct {
+		name              string
+		code              []byte
+		maxValidatorCount uint64
+		validators        ValidatorSet
+		err               error
+	}{
+		{
+			name:              "should return the validator set of the contract",
+			code:              validatorSetCode([]types.Address{addr1, addr2}),
+			maxValidatorCount: 10,
+			validators:        ValidatorSet{addr1, addr2},
+		},
+		{
+			name:              "should return error if the validator set is empty",
+			code:              validatorSetCode(nil),
+			maxValidatorCount: 10,
+			err:               errEmptyValidatorSet,
+		},
+		{
+			name:              "should return error if the validator set has duplicates",
+			code:              validatorSetCode([]types.Address{addr1, addr1}),
+			maxValidatorCount: 10,
+			err:               errDuplicateValidator,
+		},
+		{
+			name:              "should return error if the validator set is too large",
+			code:              validatorSetCode([]types.Address{addr1, addr2}),
+			maxValidatorCount: 1,
+			err:               errTooManyValidators,
+		},
+	}
+
+	for _, testcase := range tests {
+		t.Run(testcase.name, func(t *testing.T) {
+			ibft, genesis := newContractPoSIbft(t, testcase.code, testcase.maxValidatorCount)
+
+			pos, ok := ibft.mechanisms[0].(*ContractPoSMechanism)
+			assert.True(t, ok)
+
+			validators, err := pos.getNextValidators(genesis)
+			assert.ErrorIs(t, err, testcase.err)
+			assert.Equal(t, testcase.validators, validators)
+		})
+	}
+
+	t.Run("should return error if the call reverts", func(t *testing.T) {
+		ibft, genesis := newContractPoSIbft(t, revertCode, 10)
+
+		pos, ok := ibft.mechanisms[0].(*ContractPoSMechanism)
+		assert.True(t, ok)
+
+		_, err := pos.getNextValidators(genesis)
+		assert.Error(t, err)
+	})
+}
+
+func TestContractPoS_EpochValidators(t *testing.T) {
+	ibft, genesis := newContractPoSIbft(t, validatorSetCode([]types.Address{addr1, addr2}), 10)
+
+	header := &types.Header{
+		Number: TestEpochSize,
+	}
+	putIbftExtraValidators(header, []types.Address{addr3})
+
+	params := &epochValidatorsHookParams{
+		parent: genesis,
+		header: header,
+	}
+
+	// the proposer writes the validator set of the contract into the epoch block
+	assert.NoError(t, ibft.runHook(EpochValidatorsHook, header.Number, params))
+
+	validators, err := unpackValidatorsFromIbftExtra(header)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Address{addr1, addr2}, validators)
+
+	// the receiving nodes accept it
+	assert.NoError(t, ibft.runHook(VerifyEpochValidatorsHook, header.Number, params))
+
+	// the snapshot after the epoch block has the new validator set
+	snap := &Snapshot{Set: ValidatorSet{addr3}}
+	assert.NoError(t, ibft.runHook(ProcessHeadersHook, header.Number, &processHeadersHookParams{
+		header: header,
+		snap:   snap,
+	}))
+	assert.Equal(t, ValidatorSet{addr1, addr2}, snap.Set)
+
+	// the receiving nodes reject an epoch block with another validator set
+	putIbftExtraValidators(header, []types.Address{addr1, addr3})
+	assert.ErrorIs(t, ibft.runHook(VerifyEpochValidatorsHook, header.Number, params), errInvalidEpochValidators)
+}
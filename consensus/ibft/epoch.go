@@ -0,0 +1,188 @@
+package ibft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/helper/common"
+)
+
+var (
+	ErrInvalidEpochSize         = errors.New("epoch size must be greater than 1")
+	ErrEpochSizeOverrideOrder   = errors.New("epoch size overrides must be in increasing block order")
+	ErrEpochSizeOverrideAtBlock = errors.New("epoch size override must be activated at an epoch block")
+)
+
+// EpochSizeOverride changes the epoch size from the given block on
+type EpochSizeOverride struct {
+	Block     common.JSONNumber `json:"block"`
+	EpochSize common.JSONNumber `json:"epochSize"`
+}
+
+// GetEpochSize returns the genesis epoch size defined in the IBFT config
+func GetEpochSize(ibftConfig map[string]interface{}) (uint64, error) {
+	definedEpochSize, ok := ibftConfig["epochSize"]
+	if !ok {
+		// No epoch size defined, use the default one
+		return DefaultEpochSize, nil
+	}
+
+	// Epoch size is defined, use the passed in one
+	readSize, ok := definedEpochSize.(float64)
+	if !ok {
+		return 0, errors.New("invalid type assertion")
+	}
+
+	return uint64(readSize), nil
+}
+
+// GetEpochSizeSchedule returns the epoch size overrides defined in the IBFT config,
+// in the order they are activated
+func GetEpochSizeSchedule(ibftConfig map[string]interface{}) ([]EpochSizeOverride, error) {
+	rawSchedule, ok := ibftConfig["epochSizeSchedule"]
+	if !ok {
+		return nil, nil
+	}
+
+	bytes, err := json.Marshal(rawSchedule)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule []EpochSizeOverride
+	if err := json.Unmarshal(bytes, &schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// ValidateEpochSizeSchedule checks that the overrides can be applied on top of the genesis epoch size
+func ValidateEpochSizeSchedule(epochSize uint64, overrides []EpochSizeOverride) error {
+	_, err := newEpochSchedule(epochSize, overrides)
+
+	return err
+}
+
+// epochSegment is a range of blocks sharing the same epoch size
+type epochSegment struct {
+	// from is the first block of the segment, always an epoch block
+	from uint64
+
+	// size is the epoch size within the segment
+	size uint64
+
+	// startEpoch is the epoch of the first block of the segment
+	startEpoch uint64
+}
+
+// epochSchedule is the list of the epoch sizes used by the chain, in increasing block order.
+// The first segment always starts at genesis
+type epochSchedule []epochSegment
+
+// newEpochSchedule creates the epoch schedule from the genesis epoch size and the overrides.
+// Every override must be activated at an epoch block of the epoch size it replaces,
+// so the blocks before it keep their epochs
+func newEpochSchedule(epochSize uint64, overrides []EpochSizeOverride) (epochSchedule, error) {
+	if epochSize == 0 {
+		return nil, ErrInvalidEpochSize
+	}
+
+	schedule := epochSchedule{
+		{from: 0, size: epochSize, startEpoch: 0},
+	}
+
+	for _, override := range overrides {
+		last := schedule[len(schedule)-1]
+		block, size := override.Block.Value, override.EpochSize.Value
+
+		if size < 2 {
+			return nil, fmt.Errorf("%w, override at block %d", ErrInvalidEpochSize, block)
+		}
+
+		if block <= last.from {
+			return nil, fmt.Errorf("%w, override at block %d", ErrEpochSizeOverrideOrder, block)
+		}
+
+		if (block-last.from)%last.size != 0 {
+			return nil, fmt.Errorf(
+				"%w, block %d is not a multiple of the epoch size %d from block %d",
+				ErrEpochSizeOverrideAtBlock,
+				block,
+				last.size,
+				last.from,
+			)
+		}
+
+		schedule = append(schedule, epochSegment{
+			from:       block,
+			size:       size,
+			startEpoch: last.startEpoch + (block-last.from)/last.size,
+		})
+	}
+
+	return schedule, nil
+}
+
+// segmentAt returns the segment the block belongs to
+func (s epochSchedule) segmentAt(number uint64) epochSegment {
+	for i := len(s) - 1; i > 0; i-- {
+		if number >= s[i].from {
+			return s[i]
+		}
+	}
+
+	return s[0]
+}
+
+// epochSizeAt returns the epoch size of the block
+func (s epochSchedule) epochSizeAt(number uint64) uint64 {
+	return s.segmentAt(number).size
+}
+
+// epoch returns the epoch of the block, the epoch block being the last block of its epoch
+func (s epochSchedule) epoch(number uint64) uint64 {
+	segment := s.segmentAt(number)
+	offset := number - segment.from
+
+	if offset%segment.size == 0 {
+		return segment.startEpoch + offset/segment.size
+	}
+
+	return segment.startEpoch + offset/segment.size + 1
+}
+
+// isEpochBlock checks if the block is an epoch block (genesis included)
+func (s epochSchedule) isEpochBlock(number uint64) bool {
+	segment := s.segmentAt(number)
+
+	return (number-segment.from)%segment.size == 0
+}
+
+// lastEpochBlock returns the latest epoch block that is not higher than the given block
+func (s epochSchedule) lastEpochBlock(number uint64) uint64 {
+	segment := s.segmentAt(number)
+
+	return number - (number-segment.from)%segment.size
+}
+
+// previousEpochBlock returns the latest epoch block that is lower than the given block
+func (s epochSchedule) previousEpochBlock(number uint64) uint64 {
+	if number == 0 {
+		return 0
+	}
+
+	return s.lastEpochBlock(number - 1)
+}
+
+// epochBlock returns the epoch block of the epoch, the genesis for the epoch 0
+func (s epochSchedule) epochBlock(epoch uint64) uint64 {
+	for i := len(s) - 1; i > 0; i-- {
+		if epoch >= s[i].startEpoch {
+			return s[i].from + (epoch-s[i].startEpoch)*s[i].size
+		}
+	}
+
+	return s[0].from + epoch*s[0].size
+}
@@ -0,0 +1,125 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEpochSizeOverride(block, epochSize uint64) EpochSizeOverride {
+	return EpochSizeOverride{
+		Block:     common.JSONNumber{Value: block},
+		EpochSize: common.JSONNumber{Value: epochSize},
+	}
+}
+
+func TestEpochSchedule_Validation(t *testing.T) {
+	cases := []struct {
+		name      string
+		overrides []EpochSizeOverride
+		err       error
+	}{
+		{
+			name: "valid schedule",
+			overrides: []EpochSizeOverride{
+				newEpochSizeOverride(20, 5),
+				newEpochSizeOverride(35, 2),
+			},
+		},
+		{
+			name: "epoch size too small",
+			overrides: []EpochSizeOverride{
+				newEpochSizeOverride(20, 1),
+			},
+			err: ErrInvalidEpochSize,
+		},
+		{
+			name: "not in increasing block order",
+			overrides: []EpochSizeOverride{
+				newEpochSizeOverride(20, 5),
+				newEpochSizeOverride(20, 2),
+			},
+			err: ErrEpochSizeOverrideOrder,
+		},
+		{
+			name: "not activated at an epoch block",
+			overrides: []EpochSizeOverride{
+				newEpochSizeOverride(20, 5),
+				newEpochSizeOverride(32, 2),
+			},
+			err: ErrEpochSizeOverrideAtBlock,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateEpochSizeSchedule(10, c.overrides)
+			if c.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, c.err)
+			}
+		})
+	}
+}
+
+func TestEpochSchedule_Epochs(t *testing.T) {
+	// epochs of 10 blocks until block 20, and of 5 blocks from then on
+	epochs, err := newEpochSchedule(10, []EpochSizeOverride{
+		newEpochSizeOverride(20, 5),
+	})
+	assert.NoError(t, err)
+
+	cases := []struct {
+		number       uint64
+		epoch        uint64
+		isEpochBlock bool
+		lastEpoch    uint64
+	}{
+		{number: 0, epoch: 0, isEpochBlock: true, lastEpoch: 0},
+		{number: 9, epoch: 1, isEpochBlock: false, lastEpoch: 0},
+		// the blocks before the override keep the genesis epoch size
+		{number: 10, epoch: 1, isEpochBlock: true, lastEpoch: 10},
+		{number: 15, epoch: 2, isEpochBlock: false, lastEpoch: 10},
+		{number: 20, epoch: 2, isEpochBlock: true, lastEpoch: 20},
+		// the blocks past the override use the new epoch size
+		{number: 21, epoch: 3, isEpochBlock: false, lastEpoch: 20},
+		{number: 25, epoch: 3, isEpochBlock: true, lastEpoch: 25},
+		{number: 30, epoch: 4, isEpochBlock: true, lastEpoch: 30},
+		{number: 34, epoch: 5, isEpochBlock: false, lastEpoch: 30},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.epoch, epochs.epoch(c.number), "epoch of %d", c.number)
+		assert.Equal(t, c.isEpochBlock, epochs.isEpochBlock(c.number), "epoch block %d", c.number)
+		assert.Equal(t, c.lastEpoch, epochs.lastEpochBlock(c.number), "last epoch block of %d", c.number)
+
+		if c.isEpochBlock {
+			assert.Equal(t, c.number, epochs.epochBlock(c.epoch), "epoch block of epoch %d", c.epoch)
+		}
+	}
+
+	assert.Equal(t, uint64(10), epochs.epochSizeAt(19))
+	assert.Equal(t, uint64(5), epochs.epochSizeAt(20))
+
+	// two epochs before 30 crosses the override
+	assert.Equal(t, uint64(20), epochs.previousEpochBlock(epochs.previousEpochBlock(30)))
+}
+
+func TestGetEpochSizeSchedule(t *testing.T) {
+	schedule, err := GetEpochSizeSchedule(map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Nil(t, schedule)
+
+	schedule, err = GetEpochSizeSchedule(map[string]interface{}{
+		"epochSizeSchedule": []interface{}{
+			map[string]interface{}{
+				"block":     "0x14",
+				"epochSize": float64(5),
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []EpochSizeOverride{newEpochSizeOverride(20, 5)}, schedule)
+}
@@ -1,8 +1,12 @@
 package ibft
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"strings"
 
+	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/umbracle/fastrlp"
 )
@@ -21,8 +25,172 @@ var (
 
 var zeroBytes = make([]byte, 32)
 
+// extraDataMargin is the room left in the extra data past the largest istanbul extra data of the validator set,
+// for the validators joining the set at the epoch blocks and the aggregated seals of the small sets
+const extraDataMargin = 4096
+
+var ErrExtraDataTooLarge = errors.New("extra data too large")
+
+const (
+	// maxExtraListLength is the max no.of validators, or of committed seals, of the istanbul extra data
+	maxExtraListLength = 4096
+
+	// maxExtraNesting is the deepest nesting of the lists of the istanbul extra data, as the committed seals
+	// of the parent, or the aggregated seal, nested in the list of the extra data
+	maxExtraNesting = 3
+)
+
+var errExtraTooLong = errors.New("istanbul extra list too long")
+
+// getMaxExtraDataSize returns the size limit of the extra data defined in the IBFT config, 0 if not defined
+func getMaxExtraDataSize(ibftConfig map[string]interface{}) (uint64, error) {
+	defined, ok := ibftConfig["maxExtraDataSize"]
+	if !ok {
+		return 0, nil
+	}
+
+	size, ok := defined.(float64)
+	if !ok {
+		return 0, errors.New("invalid type assertion")
+	}
+
+	if int(size) < IstanbulExtraVanity {
+		return 0, fmt.Errorf("the max extra data size %d is lower than the vanity", uint64(size))
+	}
+
+	return uint64(size), nil
+}
+
+// maxIstanbulExtraSize returns the size of the largest extra data of the set of validators, in either layout:
+// the vanity, the addresses, the seals of all the validators, the copy of the seals of the parent
+// and the largest round
+func maxIstanbulExtraSize(validators int) int {
+	seals := make([][]byte, validators)
+	for indx := range seals {
+		seals[indx] = make([]byte, IstanbulExtraSeal)
+	}
+
+	round := uint64(math.MaxUint64)
+
+	ibftExtra := &IstanbulExtra{
+		Validators:    make([]types.Address, validators),
+		Seal:          make([]byte, IstanbulExtraSeal),
+		CommittedSeal: seals,
+		RoundNumber:   &round,
+		ParentCommittedSeal: &ParentSeal{
+			Round:         round,
+			CommittedSeal: seals,
+		},
+	}
+
+	qbftExtra := &IstanbulExtra{
+		Validators:    ibftExtra.Validators,
+		CommittedSeal: seals,
+		RoundNumber:   &round,
+		Vanity:        make([]byte, IstanbulExtraVanity),
+		Vote:          &QbftVote{Authorize: true},
+	}
+
+	size := IstanbulExtraVanity + len(ibftExtra.MarshalRLPTo(nil))
+	if qbftSize := len(qbftExtra.MarshalRLPTo(nil)); qbftSize > size {
+		size = qbftSize
+	}
+
+	return size
+}
+
+// verifyExtraDataSize checks the extra data of the header fits in the size limit, the configured one
+// or the one of the validator set of the parent, so the oversized extra data is not decoded
+func (i *Ibft) verifyExtraDataSize(snap *Snapshot, header *types.Header) error {
+	limit := i.maxExtraDataSize
+	if limit == 0 {
+		limit = uint64(maxIstanbulExtraSize(len(snap.Set)) + extraDataMargin)
+	}
+
+	if size := uint64(len(header.ExtraData)); size > limit {
+		return fmt.Errorf("%w, %d bytes over the limit of %d bytes", ErrExtraDataTooLarge, size, limit)
+	}
+
+	return nil
+}
+
+// ParseVanity converts the vanity string, in hex (0x prefixed) or UTF-8, to the vanity bytes.
+// A 0x prefixed string that is not valid hex is used as UTF-8.
+// The vanity is truncated or zero padded to IstanbulExtraVanity bytes
+func ParseVanity(raw string) []byte {
+	data := []byte(raw)
+
+	if strings.HasPrefix(raw, "0x") {
+		if decoded, err := hex.DecodeHex(raw); err == nil {
+			data = decoded
+		}
+	}
+
+	vanity := make([]byte, IstanbulExtraVanity)
+	copy(vanity, data)
+
+	return vanity
+}
+
+// GetVanity returns the vanity bytes from the extra data of the header
+func GetVanity(h *types.Header) []byte {
+	// the QBFT vanity is the first item of the extra data, once it's encoded
+	if isQBFT() {
+		if extra, err := GetIbftExtra(h); err == nil {
+			vanity := make([]byte, IstanbulExtraVanity)
+			copy(vanity, extra.Vanity)
+
+			return vanity
+		}
+	}
+
+	if len(h.ExtraData) < IstanbulExtraVanity {
+		return nil
+	}
+
+	vanity := make([]byte, IstanbulExtraVanity)
+	copy(vanity, h.ExtraData[:IstanbulExtraVanity])
+
+	return vanity
+}
+
+// putIbftVanity sets the vanity bytes of the header, before the istanbul extra data is added
+func putIbftVanity(h *types.Header, vanity []byte) {
+	if isQBFT() {
+		if istanbulExtra, err := GetIbftExtra(h); err == nil {
+			istanbulExtra.Vanity = make([]byte, IstanbulExtraVanity)
+			copy(istanbulExtra.Vanity, vanity)
+
+			h.ExtraData = istanbulExtra.MarshalRLPTo(nil)
+
+			return
+		}
+	}
+
+	extra := make([]byte, IstanbulExtraVanity)
+	copy(extra, vanity)
+
+	if len(h.ExtraData) > IstanbulExtraVanity {
+		extra = append(extra, h.ExtraData[IstanbulExtraVanity:]...)
+	}
+
+	h.ExtraData = extra
+}
+
 // putIbftExtraValidators is a helper method that adds validators to the extra field in the header
 func putIbftExtraValidators(h *types.Header, validators []types.Address) {
+	if isQBFT() {
+		putQbftExtraValidators(h, validators)
+
+		return
+	}
+
+	putIstanbulExtraValidators(h, validators)
+}
+
+// putIstanbulExtraValidators sets the validators of the istanbul extra data, keeping the vanity
+// and the committed seals of the parent. The seal and the committed seals are removed
+func putIstanbulExtraValidators(h *types.Header, validators []types.Address) {
 	// Pad zeros to the right up to istanbul vanity
 	extra := h.ExtraData
 	if len(extra) < IstanbulExtraVanity {
@@ -37,12 +205,52 @@ func putIbftExtraValidators(h *types.Header, validators []types.Address) {
 		CommittedSeal: [][]byte{},
 	}
 
+	// the committed seals of the parent are kept, they are covered by the seals
+	if existing, err := getIstanbulExtra(h); err == nil {
+		ibftExtra.ParentCommittedSeal = existing.ParentCommittedSeal
+	}
+
 	extra = ibftExtra.MarshalRLPTo(extra)
 	h.ExtraData = extra
 }
 
+// putQbftExtraValidators sets the validators of the QBFT extra data, keeping the vanity and the vote.
+// The committed seals and the round are removed
+func putQbftExtraValidators(h *types.Header, validators []types.Address) {
+	istanbulExtra := &IstanbulExtra{
+		Validators:    validators,
+		CommittedSeal: [][]byte{},
+		Vanity:        getQbftVanity(h),
+	}
+
+	if extra, err := getQbftExtra(h); err == nil {
+		istanbulExtra.Vote = extra.Vote
+	}
+
+	h.ExtraData = istanbulExtra.MarshalRLPTo(nil)
+}
+
+// getQbftVanity returns the vanity of the header, zero bytes if the header has none yet
+func getQbftVanity(h *types.Header) []byte {
+	vanity := make([]byte, IstanbulExtraVanity)
+
+	if extra, err := getQbftExtra(h); err == nil {
+		copy(vanity, extra.Vanity)
+	} else if len(h.ExtraData) >= IstanbulExtraVanity {
+		copy(vanity, h.ExtraData[:IstanbulExtraVanity])
+	}
+
+	return vanity
+}
+
 // PutIbftExtra sets the extra data field in the header to the passed in istanbul extra data
 func PutIbftExtra(h *types.Header, istanbulExtra *IstanbulExtra) error {
+	if isQBFT() {
+		putQbftExtra(h, istanbulExtra)
+
+		return nil
+	}
+
 	// Pad zeros to the right up to istanbul vanity
 	extra := h.ExtraData
 	if len(extra) < IstanbulExtraVanity {
@@ -58,8 +266,32 @@ func PutIbftExtra(h *types.Header, istanbulExtra *IstanbulExtra) error {
 	return nil
 }
 
-// getIbftExtra returns the istanbul extra data field from the passed in header
-func getIbftExtra(h *types.Header) (*IstanbulExtra, error) {
+// putQbftExtra sets the extra data field in the header to the passed in QBFT extra data,
+// keeping the vanity of the header
+func putQbftExtra(h *types.Header, istanbulExtra *IstanbulExtra) {
+	qbftExtra := *istanbulExtra
+	qbftExtra.Vanity = getQbftVanity(h)
+
+	h.ExtraData = qbftExtra.MarshalRLPTo(nil)
+}
+
+// GetIbftExtra returns the istanbul extra data field from the passed in header
+func GetIbftExtra(h *types.Header) (*IstanbulExtra, error) {
+	return getExtra(h, consensusProtocol)
+}
+
+// getExtra returns the extra data field from the passed in header, encoded with the protocol
+func getExtra(h *types.Header, protocol Protocol) (*IstanbulExtra, error) {
+	// the QBFT extra data is RLP encoded as a whole, the vanity included
+	if protocol == QBFTProtocol {
+		return getQbftExtra(h)
+	}
+
+	return getIstanbulExtra(h)
+}
+
+// getIstanbulExtra returns the istanbul extra data field from the passed in header, after the vanity
+func getIstanbulExtra(h *types.Header) (*IstanbulExtra, error) {
 	if len(h.ExtraData) < IstanbulExtraVanity {
 		return nil, fmt.Errorf("wrong extra size: %d", len(h.ExtraData))
 	}
@@ -74,11 +306,115 @@ func getIbftExtra(h *types.Header) (*IstanbulExtra, error) {
 	return extra, nil
 }
 
-// IstanbulExtra defines the structure of the extra field for Istanbul
+// getQbftExtra returns the QBFT extra data field from the passed in header
+func getQbftExtra(h *types.Header) (*IstanbulExtra, error) {
+	extra := &IstanbulExtra{}
+
+	if err := extra.UnmarshalRLP(h.ExtraData); err != nil {
+		return nil, err
+	}
+
+	if extra.Vanity == nil {
+		return nil, fmt.Errorf("extra data is not in the QBFT layout")
+	}
+
+	return extra, nil
+}
+
+// unpackValidatorsFromIbftExtra returns the validators from the istanbul extra data of the header
+func unpackValidatorsFromIbftExtra(h *types.Header) ([]types.Address, error) {
+	extra, err := GetIbftExtra(h)
+	if err != nil {
+		return nil, err
+	}
+
+	return extra.Validators, nil
+}
+
+// IstanbulExtra defines the structure of the extra field for Istanbul.
+// The committed seals of zero length are encoded as empty strings at their index,
+// so the seals keep their order, and they are decoded as nil
 type IstanbulExtra struct {
 	Validators    []types.Address
 	Seal          []byte
 	CommittedSeal [][]byte
+
+	// AggregatedCommittedSeal replaces CommittedSeal when the committed seals
+	// are aggregated into a single BLS signature
+	AggregatedCommittedSeal *AggregatedSeal
+
+	// RoundNumber is the round in which the block was committed.
+	// It is only encoded for blocks past the round number fork
+	RoundNumber *uint64
+
+	// Vanity is only set for the QBFT layout, where the proposer vanity is the first item of the extra data.
+	// The QBFT layout has no proposer seal, and always encodes the round number
+	Vanity []byte
+
+	// Vote is the validator vote of the QBFT layout
+	Vote *QbftVote
+
+	// ParentCommittedSeal is the copy of the committed seals of the parent block.
+	// It is only encoded for blocks past the epoch reward block, along with the round number
+	ParentCommittedSeal *ParentSeal
+}
+
+// ParentSeal are the committed seals of a block, and the round they were signed in,
+// as copied to the extra data of its child
+type ParentSeal struct {
+	Round         uint64
+	CommittedSeal [][]byte
+}
+
+// AggregatedSeal is a single aggregated signature over the commit message,
+// together with a bitmap of the validators that took part in it
+type AggregatedSeal struct {
+	// Bitmap has the bit at index N set if the N'th validator
+	// in the validator set signed the commit message
+	Bitmap []byte
+
+	// Signature is the aggregated signature of all the signers
+	Signature []byte
+}
+
+// Signers returns the validators whose bits are set in the bitmap
+func (a *AggregatedSeal) Signers(validators ValidatorSet) ([]types.Address, error) {
+	// make sure there are no bits set past the size of the validator set
+	for indx := len(validators); indx < len(a.Bitmap)*8; indx++ {
+		if bitmapIsSet(a.Bitmap, indx) {
+			return nil, fmt.Errorf("bitmap index %d out of validator set range", indx)
+		}
+	}
+
+	signers := []types.Address{}
+
+	for indx, validator := range validators {
+		if bitmapIsSet(a.Bitmap, indx) {
+			signers = append(signers, validator)
+		}
+	}
+
+	return signers, nil
+}
+
+// bitmapSet sets the bit at the passed in index, growing the bitmap if needed
+func bitmapSet(bitmap []byte, indx int) []byte {
+	if pos := indx / 8; pos >= len(bitmap) {
+		bitmap = append(bitmap, make([]byte, pos-len(bitmap)+1)...)
+	}
+
+	bitmap[indx/8] |= 1 << (indx % 8)
+
+	return bitmap
+}
+
+// bitmapIsSet checks if the bit at the passed in index is set
+func bitmapIsSet(bitmap []byte, indx int) bool {
+	if indx/8 >= len(bitmap) {
+		return false
+	}
+
+	return bitmap[indx/8]&(1<<(indx%8)) != 0
 }
 
 // MarshalRLPTo defines the marshal function wrapper for IstanbulExtra
@@ -88,6 +424,10 @@ func (i *IstanbulExtra) MarshalRLPTo(dst []byte) []byte {
 
 // MarshalRLPWith defines the marshal function implementation for IstanbulExtra
 func (i *IstanbulExtra) MarshalRLPWith(ar *fastrlp.Arena) *fastrlp.Value {
+	if i.Vanity != nil {
+		return i.marshalQbftRLPWith(ar)
+	}
+
 	vv := ar.NewArray()
 
 	// Validators
@@ -98,34 +438,137 @@ func (i *IstanbulExtra) MarshalRLPWith(ar *fastrlp.Arena) *fastrlp.Value {
 
 	vv.Set(vals)
 
-	// Seal
+	// Seal, the seals are copied since the arena values are reused
 	if len(i.Seal) == 0 {
 		vv.Set(ar.NewNull())
 	} else {
-		vv.Set(ar.NewBytes(i.Seal))
+		vv.Set(ar.NewCopyBytes(i.Seal))
 	}
 
 	// CommittedSeal
-	if len(i.CommittedSeal) == 0 {
+	if i.AggregatedCommittedSeal != nil {
+		// The aggregated seal is wrapped in a nested list, so it can't be
+		// mistaken for a list of byte encoded ECDSA seals when decoding
+		aggregated := ar.NewArray()
+		aggregated.Set(ar.NewCopyBytes(i.AggregatedCommittedSeal.Bitmap))
+		aggregated.Set(ar.NewCopyBytes(i.AggregatedCommittedSeal.Signature))
+
+		committed := ar.NewArray()
+		committed.Set(aggregated)
+		vv.Set(committed)
+	} else if len(i.CommittedSeal) == 0 {
 		vv.Set(ar.NewNullArray())
 	} else {
 		committed := ar.NewArray()
 		for _, a := range i.CommittedSeal {
 			if len(a) == 0 {
-				vv.Set(ar.NewNull())
+				committed.Set(ar.NewNull())
 			} else {
-				committed.Set(ar.NewBytes(a))
+				committed.Set(ar.NewCopyBytes(a))
 			}
 		}
 		vv.Set(committed)
 	}
 
+	// RoundNumber, an empty list stands for the missing round
+	// if the committed seals of the parent follow
+	if i.RoundNumber != nil {
+		vv.Set(ar.NewUint(*i.RoundNumber))
+	} else if i.ParentCommittedSeal != nil {
+		vv.Set(ar.NewNullArray())
+	}
+
+	// ParentCommittedSeal
+	if i.ParentCommittedSeal != nil {
+		parent := ar.NewArray()
+		parent.Set(ar.NewUint(i.ParentCommittedSeal.Round))
+
+		if len(i.ParentCommittedSeal.CommittedSeal) == 0 {
+			parent.Set(ar.NewNullArray())
+		} else {
+			committed := ar.NewArray()
+			for _, seal := range i.ParentCommittedSeal.CommittedSeal {
+				committed.Set(ar.NewCopyBytes(seal))
+			}
+			parent.Set(committed)
+		}
+
+		vv.Set(parent)
+	}
+
 	return vv
 }
 
-// UnmarshalRLP defines the unmarshal function wrapper for IstanbulExtra
+// marshalQbftRLPWith encodes the extra data in the QBFT layout:
+// vanity, validators, vote, round and committed seals
+func (i *IstanbulExtra) marshalQbftRLPWith(ar *fastrlp.Arena) *fastrlp.Value {
+	vv := ar.NewArray()
+
+	// Vanity
+	vv.Set(ar.NewCopyBytes(i.Vanity))
+
+	// Validators
+	vals := ar.NewArray()
+	for _, a := range i.Validators {
+		vals.Set(ar.NewBytes(a.Bytes()))
+	}
+
+	vv.Set(vals)
+
+	// Vote
+	if i.Vote == nil {
+		vv.Set(ar.NewNullArray())
+	} else {
+		voteType := qbftDropVote
+		if i.Vote.Authorize {
+			voteType = qbftAuthVote
+		}
+
+		vote := ar.NewArray()
+		vote.Set(ar.NewBytes(i.Vote.Recipient.Bytes()))
+		vote.Set(ar.NewBytes([]byte{voteType}))
+		vv.Set(vote)
+	}
+
+	// RoundNumber
+	if i.RoundNumber == nil {
+		vv.Set(ar.NewUint(0))
+	} else {
+		vv.Set(ar.NewUint(*i.RoundNumber))
+	}
+
+	// CommittedSeal
+	if len(i.CommittedSeal) == 0 {
+		vv.Set(ar.NewNullArray())
+	} else {
+		committed := ar.NewArray()
+		for _, a := range i.CommittedSeal {
+			committed.Set(ar.NewCopyBytes(a))
+		}
+		vv.Set(committed)
+	}
+
+	return vv
+}
+
+// UnmarshalRLP defines the unmarshal function wrapper for IstanbulExtra.
+// The truncated and the too deeply nested inputs are rejected before they are parsed
 func (i *IstanbulExtra) UnmarshalRLP(input []byte) error {
-	return types.UnmarshalRlp(i.UnmarshalRLPFrom, input)
+	return types.UnmarshalRlpNested(i.UnmarshalRLPFrom, input, maxExtraNesting)
+}
+
+// getListElems returns the elements of the list of the istanbul extra, at most maxExtraListLength of them
+func getListElems(v *fastrlp.Value, name string) ([]*fastrlp.Value, error) {
+	elems, err := v.GetElems()
+	if err != nil {
+		return nil, fmt.Errorf("list expected for %s", name)
+	}
+
+	if len(elems) > maxExtraListLength {
+		return nil, fmt.Errorf("%w, %d %s over the limit of %d", errExtraTooLong, len(elems), name, maxExtraListLength)
+	}
+
+	return elems, nil
 }
 
 // UnmarshalRLPFrom defines the unmarshal implementation for IstanbulExtra
@@ -135,15 +578,22 @@ func (i *IstanbulExtra) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) er
 		return err
 	}
 
-	if num := len(elems); num != 3 {
-		return fmt.Errorf("not enough elements to decode istambul extra, expected 3 but found %d", num)
+	// the QBFT layout starts with the vanity instead of the validators list
+	if len(elems) > 0 && elems[0].Type() == fastrlp.TypeBytes {
+		return i.unmarshalQbftRLPFrom(elems)
+	}
+
+	// the round number is only present in blocks past the round number fork,
+	// and the committed seals of the parent in blocks past the epoch reward block
+	if num := len(elems); num < 3 || num > 5 {
+		return fmt.Errorf("not enough elements to decode istambul extra, expected 3 to 5 but found %d", num)
 	}
 
 	// Validators
 	{
-		vals, err := elems[0].GetElems()
+		vals, err := getListElems(elems[0], "validators")
 		if err != nil {
-			return fmt.Errorf("list expected for validators")
+			return err
 		}
 		i.Validators = make([]types.Address, len(vals))
 		for indx, val := range vals {
@@ -162,17 +612,202 @@ func (i *IstanbulExtra) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) er
 
 	// Committed
 	{
-		vals, err := elems[2].GetElems()
+		vals, err := getListElems(elems[2], "committed")
 		if err != nil {
-			return fmt.Errorf("list expected for committed")
+			return err
 		}
-		i.CommittedSeal = make([][]byte, len(vals))
-		for indx, val := range vals {
-			if i.CommittedSeal[indx], err = val.GetBytes(i.CommittedSeal[indx]); err != nil {
+
+		if len(vals) == 1 && vals[0].Type() == fastrlp.TypeArray {
+			// Aggregated committed seal
+			if err := i.unmarshalAggregatedSeal(vals[0]); err != nil {
 				return err
 			}
+		} else {
+			i.CommittedSeal = make([][]byte, len(vals))
+			for indx, val := range vals {
+				if i.CommittedSeal[indx], err = val.GetBytes(i.CommittedSeal[indx]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// RoundNumber, the empty list of the missing round is only followed by the committed seals of the parent
+	if len(elems) >= 4 && elems[3].Type() == fastrlp.TypeArray {
+		if missing, err := elems[3].GetElems(); err != nil || len(missing) != 0 || len(elems) != 5 {
+			return fmt.Errorf("invalid round number")
+		}
+	} else if len(elems) >= 4 {
+		round, err := elems[3].GetUint64()
+		if err != nil {
+			return err
+		}
+
+		i.RoundNumber = &round
+	}
+
+	// ParentCommittedSeal
+	if len(elems) == 5 {
+		if err := i.unmarshalParentSeal(elems[4]); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
+
+// unmarshalQbftRLPFrom decodes the extra data in the QBFT layout
+func (i *IstanbulExtra) unmarshalQbftRLPFrom(elems []*fastrlp.Value) error {
+	if num := len(elems); num != 5 {
+		return fmt.Errorf("not enough elements to decode qbft extra, expected 5 but found %d", num)
+	}
+
+	// Vanity
+	vanity, err := elems[0].GetBytes(nil)
+	if err != nil {
+		return err
+	}
+
+	i.Vanity = append([]byte{}, vanity...)
+
+	// Validators
+	vals, err := getListElems(elems[1], "validators")
+	if err != nil {
+		return err
+	}
+
+	i.Validators = make([]types.Address, len(vals))
+	for indx, val := range vals {
+		if err = val.GetAddr(i.Validators[indx][:]); err != nil {
+			return err
+		}
+	}
+
+	// Vote
+	if err := i.unmarshalQbftVote(elems[2]); err != nil {
+		return err
+	}
+
+	// RoundNumber
+	round, err := elems[3].GetUint64()
+	if err != nil {
+		return err
+	}
+
+	i.RoundNumber = &round
+
+	// Committed
+	seals, err := getListElems(elems[4], "committed")
+	if err != nil {
+		return err
+	}
+
+	i.CommittedSeal = make([][]byte, len(seals))
+	for indx, seal := range seals {
+		if i.CommittedSeal[indx], err = seal.GetBytes(i.CommittedSeal[indx]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unmarshalQbftVote decodes the vote of the QBFT layout, an empty list if there is no vote
+func (i *IstanbulExtra) unmarshalQbftVote(v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return fmt.Errorf("list expected for vote")
+	}
+
+	if len(elems) == 0 {
+		return nil
+	}
+
+	if num := len(elems); num != 2 {
+		return fmt.Errorf("not enough elements to decode qbft vote, expected 2 but found %d", num)
+	}
+
+	vote := &QbftVote{}
+
+	if err := elems[0].GetAddr(vote.Recipient[:]); err != nil {
+		return err
+	}
+
+	voteType, err := elems[1].GetBytes(nil)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(voteType) == 1 && voteType[0] == qbftAuthVote:
+		vote.Authorize = true
+	case len(voteType) == 0, len(voteType) == 1 && voteType[0] == qbftDropVote:
+		vote.Authorize = false
+	default:
+		return fmt.Errorf("invalid qbft vote type 0x%x", voteType)
+	}
+
+	i.Vote = vote
+
+	return nil
+}
+
+// unmarshalAggregatedSeal decodes the aggregated committed seal from the nested list
+func (i *IstanbulExtra) unmarshalAggregatedSeal(v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	if num := len(elems); num != 2 {
+		return fmt.Errorf("not enough elements to decode aggregated seal, expected 2 but found %d", num)
+	}
+
+	seal := &AggregatedSeal{}
+
+	if seal.Bitmap, err = elems[0].GetBytes(seal.Bitmap); err != nil {
+		return err
+	}
+
+	if seal.Signature, err = elems[1].GetBytes(seal.Signature); err != nil {
+		return err
+	}
+
+	i.AggregatedCommittedSeal = seal
+
+	return nil
+}
+
+// unmarshalParentSeal decodes the committed seals of the parent, and their round
+func (i *IstanbulExtra) unmarshalParentSeal(v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return fmt.Errorf("list expected for parent committed seal")
+	}
+
+	if num := len(elems); num != 2 {
+		return fmt.Errorf("not enough elements to decode parent committed seal, expected 2 but found %d", num)
+	}
+
+	seal := &ParentSeal{}
+
+	if seal.Round, err = elems[0].GetUint64(); err != nil {
+		return err
+	}
+
+	seals, err := getListElems(elems[1], "parent committed")
+	if err != nil {
+		return err
+	}
+
+	seal.CommittedSeal = make([][]byte, len(seals))
+	for indx, val := range seals {
+		if seal.CommittedSeal[indx], err = val.GetBytes(nil); err != nil {
+			return err
+		}
+	}
+
+	i.ParentCommittedSeal = seal
+
+	return nil
+}
@@ -0,0 +1,99 @@
+//go:build go1.18
+// +build go1.18
+
+package ibft
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// FuzzIstanbulExtra round-trips the istanbul extra data of the random fields,
+// the decoded extra data has to be encoded as the original one
+func FuzzIstanbulExtra(f *testing.F) {
+	seal := types.StringToHash("1").Bytes()
+
+	f.Add([]byte{0x1, 0x2}, seal, seal, uint8(1), uint64(0), uint8(0))
+	f.Add([]byte{}, []byte{}, []byte{}, uint8(3), uint64(7), uint8(1))
+	f.Add([]byte{0x1}, seal, seal, uint8(2), uint64(1), uint8(2))
+	f.Add([]byte{0x1}, []byte{}, seal, uint8(1), uint64(1), uint8(3))
+
+	f.Fuzz(func(t *testing.T, validators, proposerSeal, committedSeal []byte, seals uint8, round uint64, layout uint8) {
+		// the long inputs only slow down the fuzzing
+		if len(validators) > 64 || len(proposerSeal) > 256 || len(committedSeal) > 256 {
+			return
+		}
+
+		extra := &IstanbulExtra{
+			Seal: proposerSeal,
+		}
+
+		for _, b := range validators {
+			extra.Validators = append(extra.Validators, types.BytesToAddress([]byte{b}))
+		}
+
+		// the seals of zero length are kept at their index, and the seals are cut to vary their length
+		for indx := 0; indx < int(seals%8); indx++ {
+			extra.CommittedSeal = append(extra.CommittedSeal, committedSeal[:len(committedSeal)*indx/8])
+		}
+
+		switch layout % 4 {
+		case 1:
+			extra.RoundNumber = &round
+		case 2:
+			extra.RoundNumber = &round
+			extra.ParentCommittedSeal = &ParentSeal{Round: round, CommittedSeal: extra.CommittedSeal}
+		case 3:
+			extra.CommittedSeal = nil
+			extra.AggregatedCommittedSeal = &AggregatedSeal{Bitmap: validators, Signature: committedSeal}
+		}
+
+		encoded := extra.MarshalRLPTo(nil)
+
+		decoded := &IstanbulExtra{}
+		if !assert.NoError(t, decoded.UnmarshalRLP(encoded)) {
+			return
+		}
+
+		if !bytes.Equal(encoded, decoded.MarshalRLPTo(nil)) {
+			t.Fatalf("the decoded extra data %x is encoded differently", encoded)
+		}
+
+		assert.Equal(t, len(extra.Validators), len(decoded.Validators))
+		assert.Equal(t, len(extra.CommittedSeal), len(decoded.CommittedSeal))
+		assert.Equal(t, extra.RoundNumber, decoded.RoundNumber)
+	})
+}
+
+// FuzzIstanbulExtra_Decode decodes the random extra data, the decoded one has to round-trip
+func FuzzIstanbulExtra_Decode(f *testing.F) {
+	seal := types.StringToHash("1").Bytes()
+	round := uint64(1)
+
+	f.Add((&IstanbulExtra{
+		Validators:    []types.Address{types.StringToAddress("1")},
+		Seal:          seal,
+		CommittedSeal: [][]byte{seal, {}},
+		RoundNumber:   &round,
+	}).MarshalRLPTo(nil))
+	f.Add([]byte{0xc3, 0xc0, 0x80, 0xc0})
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		extra := &IstanbulExtra{}
+		if err := extra.UnmarshalRLP(input); err != nil {
+			return
+		}
+
+		decoded := &IstanbulExtra{}
+		if !assert.NoError(t, decoded.UnmarshalRLP(extra.MarshalRLPTo(nil))) {
+			return
+		}
+
+		if !bytes.Equal(extra.MarshalRLPTo(nil), decoded.MarshalRLPTo(nil)) {
+			t.Fatalf("the decoded extra data %x is encoded differently", input)
+		}
+	})
+}
@@ -1,14 +1,20 @@
 package ibft
 
 import (
+	"math"
+	"math/rand"
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestExtraEncoding(t *testing.T) {
 	seal1 := types.StringToHash("1").Bytes()
+	round := uint64(2)
 
 	cases := []struct {
 		extra []byte
@@ -25,6 +31,73 @@ func TestExtraEncoding(t *testing.T) {
 				},
 			},
 		},
+		{
+			data: &IstanbulExtra{
+				Validators: []types.Address{
+					types.StringToAddress("1"),
+					types.StringToAddress("2"),
+				},
+				Seal: seal1,
+				AggregatedCommittedSeal: &AggregatedSeal{
+					Bitmap:    []byte{0x3},
+					Signature: seal1,
+				},
+			},
+		},
+		{
+			data: &IstanbulExtra{
+				Validators: []types.Address{
+					types.StringToAddress("1"),
+				},
+				Seal: seal1,
+				CommittedSeal: [][]byte{
+					seal1,
+				},
+				RoundNumber: &round,
+			},
+		},
+		{
+			data: &IstanbulExtra{
+				Validators: []types.Address{
+					types.StringToAddress("1"),
+					types.StringToAddress("2"),
+				},
+				Seal: seal1,
+				AggregatedCommittedSeal: &AggregatedSeal{
+					Bitmap:    []byte{0x3},
+					Signature: seal1,
+				},
+				RoundNumber: &round,
+			},
+		},
+		{
+			data: &IstanbulExtra{
+				Validators: []types.Address{
+					types.StringToAddress("1"),
+				},
+				Seal: seal1,
+				CommittedSeal: [][]byte{
+					seal1,
+				},
+				RoundNumber: &round,
+				ParentCommittedSeal: &ParentSeal{
+					Round:         1,
+					CommittedSeal: [][]byte{seal1},
+				},
+			},
+		},
+		{
+			data: &IstanbulExtra{
+				Validators: []types.Address{
+					types.StringToAddress("1"),
+				},
+				Seal:          seal1,
+				CommittedSeal: [][]byte{},
+				ParentCommittedSeal: &ParentSeal{
+					CommittedSeal: [][]byte{},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -40,3 +113,377 @@ func TestExtraEncoding(t *testing.T) {
 		}
 	}
 }
+
+func TestExtraEncoding_EmptyCommittedSeal(t *testing.T) {
+	seal1 := types.StringToHash("1").Bytes()
+	round := uint64(2)
+
+	extra := &IstanbulExtra{
+		Validators:    []types.Address{types.StringToAddress("1"), types.StringToAddress("2")},
+		Seal:          seal1,
+		CommittedSeal: [][]byte{seal1, {}, seal1},
+		RoundNumber:   &round,
+		ParentCommittedSeal: &ParentSeal{
+			Round:         1,
+			CommittedSeal: [][]byte{seal1},
+		},
+	}
+
+	decoded := &IstanbulExtra{}
+	assert.NoError(t, decoded.UnmarshalRLP(extra.MarshalRLPTo(nil)))
+
+	// the empty seal keeps its index, and the fields following the seals are not shifted
+	assert.Equal(t, [][]byte{seal1, nil, seal1}, decoded.CommittedSeal)
+	assert.Equal(t, extra.Validators, decoded.Validators)
+	assert.Equal(t, extra.Seal, decoded.Seal)
+	assert.Equal(t, extra.RoundNumber, decoded.RoundNumber)
+	assert.Equal(t, extra.ParentCommittedSeal, decoded.ParentCommittedSeal)
+
+	// the decoded extra data is encoded as the original one
+	assert.Equal(t, extra.MarshalRLPTo(nil), decoded.MarshalRLPTo(nil))
+}
+
+func TestExtraDecoding_Malformed(t *testing.T) {
+	seal1 := types.StringToHash("1").Bytes()
+
+	valid := (&IstanbulExtra{
+		Validators:    []types.Address{types.StringToAddress("1")},
+		Seal:          seal1,
+		CommittedSeal: [][]byte{seal1},
+	}).MarshalRLPTo(nil)
+
+	nested := []byte{0xc0}
+	for depth := 0; depth < maxExtraNesting; depth++ {
+		nested = append(rlpPrefix(0xc0, len(nested)), nested...)
+	}
+
+	validators := make([]byte, 0, (maxExtraListLength+1)*21)
+	for indx := 0; indx <= maxExtraListLength; indx++ {
+		validators = append(append(validators, 0x94), make([]byte, 20)...)
+	}
+
+	validators = append(rlpPrefix(0xc0, len(validators)), validators...)
+	tooManyValidators := append(append(validators, 0x80), 0xc0)
+	tooManyValidators = append(rlpPrefix(0xc0, len(tooManyValidators)), tooManyValidators...)
+
+	cases := []struct {
+		name  string
+		input []byte
+		err   error
+	}{
+		{"empty", []byte{}, types.ErrRLPTruncated},
+		{"truncated", valid[:len(valid)-1], types.ErrRLPTruncated},
+		{"truncated length", []byte{0xf9, 0x01}, types.ErrRLPTruncated},
+		{"inflated length", append([]byte{0xbb, 0xff, 0xff, 0xff, 0xff}, valid...), types.ErrRLPTruncated},
+		{"item past its list", []byte{0xc2, 0x83, 0x01, 0x02}, types.ErrRLPTruncated},
+		{"trailing data", append(append([]byte{}, valid...), 0x80), types.ErrRLPTrailing},
+		{"nested too deep", nested, types.ErrRLPTooDeep},
+		{"too many validators", tooManyValidators, errExtraTooLong},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.ErrorIs(t, (&IstanbulExtra{}).UnmarshalRLP(c.input), c.err)
+		})
+	}
+}
+
+func TestParentSeal_HeaderHash(t *testing.T) {
+	seal1, seal2 := types.StringToHash("1").Bytes(), types.StringToHash("2").Bytes()
+	round := uint64(1)
+
+	header := &types.Header{}
+	putIbftExtraValidators(header, []types.Address{types.StringToAddress("1")})
+
+	sealHeader := func(parentSeals [][]byte, seals [][]byte) types.Hash {
+		h := header.Copy()
+		assert.NoError(t, PutIbftExtra(h, &IstanbulExtra{
+			Validators:    []types.Address{types.StringToAddress("1")},
+			Seal:          seal1,
+			CommittedSeal: seals,
+			RoundNumber:   &round,
+			ParentCommittedSeal: &ParentSeal{
+				CommittedSeal: parentSeals,
+			},
+		}))
+
+		// the committed seals of the parent are kept when the seals are removed
+		extra, err := GetIbftExtra(h)
+		assert.NoError(t, err)
+
+		putIbftExtraValidators(h, extra.Validators)
+
+		extra, err = GetIbftExtra(h)
+		assert.NoError(t, err)
+		assert.Equal(t, parentSeals, extra.ParentCommittedSeal.CommittedSeal)
+		assert.Nil(t, extra.RoundNumber)
+
+		return istanbulHeaderHash(h)
+	}
+
+	// the hash covers the committed seals of the parent, but not the ones of the block
+	assert.Equal(t, sealHeader([][]byte{seal1}, [][]byte{seal1}), sealHeader([][]byte{seal1}, [][]byte{seal2}))
+	assert.NotEqual(t, sealHeader([][]byte{seal1}, [][]byte{seal1}), sealHeader([][]byte{seal2}, [][]byte{seal1}))
+}
+
+func TestAggregatedSeal_Signers(t *testing.T) {
+	validators := ValidatorSet{
+		types.StringToAddress("1"),
+		types.StringToAddress("2"),
+		types.StringToAddress("3"),
+	}
+
+	var bitmap []byte
+
+	bitmap = bitmapSet(bitmap, 0)
+	bitmap = bitmapSet(bitmap, 2)
+
+	seal := &AggregatedSeal{Bitmap: bitmap}
+
+	signers, err := seal.Signers(validators)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(signers, []types.Address{validators[0], validators[2]}) {
+		t.Fatal("bad signers")
+	}
+
+	// bit set outside of the validator set
+	seal.Bitmap = bitmapSet(seal.Bitmap, 9)
+
+	if _, err := seal.Signers(validators); err == nil {
+		t.Fatal("expected out of range error")
+	}
+}
+
+func TestParseVanity(t *testing.T) {
+	padded := func(data []byte) []byte {
+		vanity := make([]byte, IstanbulExtraVanity)
+		copy(vanity, data)
+
+		return vanity
+	}
+
+	cases := []struct {
+		raw    string
+		vanity []byte
+	}{
+		{raw: "0x0102", vanity: padded([]byte{0x1, 0x2})},
+		{raw: "validator", vanity: padded([]byte("validator"))},
+		// not a valid hex string, used as UTF-8
+		{raw: "0xzz", vanity: padded([]byte("0xzz"))},
+		// truncated to the vanity size
+		{raw: strings.Repeat("a", 40), vanity: []byte(strings.Repeat("a", IstanbulExtraVanity))},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.vanity, ParseVanity(c.raw), c.raw)
+	}
+}
+
+func TestVanity_Seals(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B")
+
+	vanity := ParseVanity("validator A")
+
+	h := &types.Header{}
+	putIbftVanity(h, vanity)
+	putIbftExtraValidators(h, pool.ValidatorSet())
+
+	assert.Equal(t, vanity, GetVanity(h))
+
+	// the vanity is kept when sealing, and the seals don't depend on its content
+	sealed, err := writeSeal(pool.get("A").signer(), h)
+	assert.NoError(t, err)
+	assert.Equal(t, vanity, GetVanity(sealed))
+
+	proposer, err := ecrecoverFromHeader(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, pool.get("A").Address(), proposer)
+
+	extra, err := GetIbftExtra(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Address(pool.ValidatorSet()), extra.Validators)
+
+	// headers without extra data have no vanity
+	assert.Nil(t, GetVanity(&types.Header{}))
+}
+
+func TestVerifyExtraDataSize(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	snap := &Snapshot{Set: pool.ValidatorSet()}
+	ibft := &Ibft{}
+
+	// the largest extra data of the set, every seal included
+	header := &types.Header{}
+	putIbftExtraValidators(header, pool.ValidatorSet())
+
+	round := uint64(1)
+	seals := make([][]byte, len(snap.Set))
+
+	for indx := range seals {
+		seals[indx] = make([]byte, IstanbulExtraSeal)
+	}
+
+	assert.NoError(t, PutIbftExtra(header, &IstanbulExtra{
+		Validators:          snap.Set,
+		Seal:                make([]byte, IstanbulExtraSeal),
+		CommittedSeal:       seals,
+		RoundNumber:         &round,
+		ParentCommittedSeal: &ParentSeal{Round: round, CommittedSeal: seals},
+	}))
+	assert.LessOrEqual(t, len(header.ExtraData), maxIstanbulExtraSize(len(snap.Set)))
+	assert.NoError(t, ibft.verifyExtraDataSize(snap, header))
+
+	// the margin is left past the largest extra data
+	limit := maxIstanbulExtraSize(len(snap.Set)) + extraDataMargin
+
+	header.ExtraData = make([]byte, limit)
+	assert.NoError(t, ibft.verifyExtraDataSize(snap, header))
+
+	header.ExtraData = make([]byte, limit+1)
+	assert.ErrorIs(t, ibft.verifyExtraDataSize(snap, header), ErrExtraDataTooLarge)
+
+	// the oversized extra data is rejected before it is decoded
+	assert.ErrorIs(t, ibft.verifyHeaderImpl(snap, &types.Header{}, header), ErrExtraDataTooLarge)
+
+	// the configured limit replaces the one of the validator set
+	size, err := getMaxExtraDataSize(map[string]interface{}{"maxExtraDataSize": float64(100)})
+	assert.NoError(t, err)
+
+	ibft.maxExtraDataSize = size
+
+	header.ExtraData = make([]byte, 101)
+	assert.ErrorIs(t, ibft.verifyExtraDataSize(snap, header), ErrExtraDataTooLarge)
+
+	_, err = getMaxExtraDataSize(map[string]interface{}{"maxExtraDataSize": float64(10)})
+	assert.Error(t, err)
+
+	size, err = getMaxExtraDataSize(map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), size)
+}
+
+// rlpPrefix returns the RLP length prefix of the string or the list of the size
+func rlpPrefix(offset byte, size int) []byte {
+	if size < 56 {
+		return []byte{offset + byte(size)}
+	}
+
+	length := []byte{}
+	for ; size > 0; size >>= 8 {
+		length = append([]byte{byte(size)}, length...)
+	}
+
+	return append([]byte{offset + 55 + byte(len(length))}, length...)
+}
+
+// adversarialExtraData returns the extra data payloads crafted to make the decoding allocate or recurse,
+// and the random mutations of the valid ones
+func adversarialExtraData(t *testing.T, rnd *rand.Rand) [][]byte {
+	t.Helper()
+
+	round := uint64(1)
+	valid := (&IstanbulExtra{
+		Validators:          []types.Address{types.StringToAddress("1"), types.StringToAddress("2")},
+		Seal:                make([]byte, IstanbulExtraSeal),
+		CommittedSeal:       [][]byte{make([]byte, IstanbulExtraSeal)},
+		RoundNumber:         &round,
+		ParentCommittedSeal: &ParentSeal{Round: round, CommittedSeal: [][]byte{make([]byte, IstanbulExtraSeal)}},
+	}).MarshalRLPTo(nil)
+
+	payloads := [][]byte{
+		// the length prefixes claiming up to 2^64 bytes
+		{0xbb, 0xff, 0xff, 0xff, 0xff},
+		{0xfb, 0xff, 0xff, 0xff, 0xff},
+		{0xbf, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		append([]byte{0xf8, 0x03}, 0xfb, 0xff, 0xff),
+	}
+
+	// the list of many empty elements as the validators and the committed seals
+	many := make([]byte, 16*1024)
+	for indx := range many {
+		many[indx] = 0x80
+	}
+
+	manyList := append(rlpPrefix(0xc0, len(many)), many...)
+	body := append(append(append([]byte{}, manyList...), 0x80), manyList...)
+	payloads = append(payloads, append(rlpPrefix(0xc0, len(body)), body...))
+
+	// the deeply nested lists
+	nested := []byte{0xc0}
+	for depth := 0; depth < 2048; depth++ {
+		nested = append(rlpPrefix(0xc0, len(nested)), nested...)
+	}
+
+	payloads = append(payloads, nested)
+
+	// the random payloads, and the valid payload truncated, with flipped bytes or inflated prefixes
+	for indx := 0; indx < 2000; indx++ {
+		random := make([]byte, rnd.Intn(512))
+		rnd.Read(random)
+
+		mutated := append([]byte{}, valid...)
+
+		switch indx % 3 {
+		case 0:
+			mutated = mutated[:rnd.Intn(len(mutated))]
+		case 1:
+			for flips := rnd.Intn(8) + 1; flips > 0; flips-- {
+				mutated[rnd.Intn(len(mutated))] ^= byte(rnd.Intn(255) + 1)
+			}
+		case 2:
+			pos := rnd.Intn(len(mutated))
+			mutated = append(append(append([]byte{}, mutated[:pos]...), 0xbb, 0xff, 0xff, 0xff, 0xff), mutated[pos:]...)
+		}
+
+		payloads = append(payloads, random, mutated)
+	}
+
+	return payloads
+}
+
+func TestGetIbftExtra_AdversarialExtraData(t *testing.T) {
+	// the allocations of a decoding are bounded by the size of its payload
+	const (
+		allocPerByte  = 512
+		allocConstant = 64 * 1024
+	)
+
+	// the least allocated of a few decodings, the pools refilled after a collection add to some of them
+	decodingAlloc := func(payload []byte) uint64 {
+		header := &types.Header{
+			ExtraData: append(make([]byte, IstanbulExtraVanity), payload...),
+		}
+
+		least := uint64(math.MaxUint64)
+
+		for run := 0; run < 3; run++ {
+			var before, after runtime.MemStats
+
+			runtime.ReadMemStats(&before)
+
+			_, _ = GetIbftExtra(header)
+			_ = (&IstanbulExtra{}).UnmarshalRLP(payload)
+
+			runtime.ReadMemStats(&after)
+
+			if allocated := after.TotalAlloc - before.TotalAlloc; allocated < least {
+				least = allocated
+			}
+		}
+
+		return least
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+
+	for _, payload := range adversarialExtraData(t, rnd) {
+		assert.LessOrEqual(t, decodingAlloc(payload), uint64(allocPerByte*len(payload)+allocConstant), "payload %x", payload)
+	}
+}
@@ -0,0 +1,52 @@
+package ibft
+
+import (
+	"bytes"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// PreferHeader implements the fork choice of the blockchain between two blocks of the same height,
+// sealed by partitioned validators. The block with more committed seals is preferred,
+// and the ties are broken by the lower hash, so all the nodes pick the same block
+func (i *Ibft) PreferHeader(current, incoming *types.Header) bool {
+	incomingSeals, err := countCommittedSeals(incoming)
+	if err != nil {
+		i.logger.Debug("failed to count the committed seals", "hash", incoming.Hash, "err", err)
+
+		return false
+	}
+
+	currentSeals, err := countCommittedSeals(current)
+	if err != nil {
+		i.logger.Debug("failed to count the committed seals", "hash", current.Hash, "err", err)
+
+		return true
+	}
+
+	if incomingSeals != currentSeals {
+		return incomingSeals > currentSeals
+	}
+
+	return bytes.Compare(incoming.Hash.Bytes(), current.Hash.Bytes()) < 0
+}
+
+// countCommittedSeals returns the number of committed seals of the header.
+// The seals are verified along with the header, so they come from distinct validators
+func countCommittedSeals(header *types.Header) (int, error) {
+	extra, err := GetIbftExtra(header)
+	if err != nil {
+		return 0, err
+	}
+
+	if extra.AggregatedCommittedSeal != nil {
+		signers, err := extra.AggregatedCommittedSeal.Signers(extra.Validators)
+		if err != nil {
+			return 0, err
+		}
+
+		return len(signers), nil
+	}
+
+	return len(extra.CommittedSeal), nil
+}
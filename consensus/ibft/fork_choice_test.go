@@ -0,0 +1,90 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreferHeader(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	// sealHeader builds a header of the same height committed by the passed in validators
+	sealHeader := func(hash types.Hash, accounts ...string) *types.Header {
+		h := &types.Header{
+			Number: 10,
+		}
+		putIbftExtraValidators(h, pool.ValidatorSet())
+
+		seals := [][]byte{}
+
+		for _, accnt := range accounts {
+			seal, err := writeCommittedSeal(pool.get(accnt).signer(), h, nil, IBFTProtocol)
+			assert.NoError(t, err)
+
+			seals = append(seals, seal)
+		}
+
+		sealed, err := writeCommittedSeals(h, seals, nil)
+		assert.NoError(t, err)
+
+		sealed.Hash = hash
+
+		return sealed
+	}
+
+	i := &Ibft{
+		logger: hclog.NewNullLogger(),
+	}
+
+	low, high := types.StringToHash("1"), types.StringToHash("2")
+
+	cases := []struct {
+		name     string
+		current  *types.Header
+		incoming *types.Header
+		prefer   bool
+	}{
+		{
+			name:     "more committed seals",
+			current:  sealHeader(low, "A", "B", "C"),
+			incoming: sealHeader(high, "A", "B", "C", "D"),
+			prefer:   true,
+		},
+		{
+			name:     "less committed seals",
+			current:  sealHeader(high, "A", "B", "C", "D"),
+			incoming: sealHeader(low, "A", "B", "C"),
+			prefer:   false,
+		},
+		{
+			name:     "same committed seals, lower hash",
+			current:  sealHeader(high, "A", "B", "C"),
+			incoming: sealHeader(low, "B", "C", "D"),
+			prefer:   true,
+		},
+		{
+			name:     "same committed seals, higher hash",
+			current:  sealHeader(low, "A", "B", "C"),
+			incoming: sealHeader(high, "B", "C", "D"),
+			prefer:   false,
+		},
+		{
+			name:     "invalid extra data",
+			current:  sealHeader(high, "A", "B", "C"),
+			incoming: &types.Header{Number: 10, Hash: low},
+			prefer:   false,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.prefer, i.PreferHeader(c.current, c.incoming))
+		})
+	}
+}
@@ -9,6 +9,16 @@ import (
 // istanbulHeaderHash defines the custom implementation for getting the header hash,
 // because of the extraData field
 func istanbulHeaderHash(h *types.Header) types.Hash {
+	// the QBFT hash covers all the header fields
+	if isQBFT() {
+		hash, err := qbftHeaderHash(h)
+		if err != nil {
+			return types.Hash{}
+		}
+
+		return types.BytesToHash(hash)
+	}
+
 	// this function replaces extra so we need to make a copy
 	h = h.Copy() // Remove later
 
@@ -17,7 +27,7 @@ func istanbulHeaderHash(h *types.Header) types.Hash {
 
 	// when hashing the block for signing we have to remove from
 	// the extra field the seal and committed seal items
-	extra, err := getIbftExtra(h)
+	extra, err := GetIbftExtra(h)
 	if err != nil {
 		return types.Hash{}
 	}
@@ -39,7 +49,26 @@ func istanbulHeaderHash(h *types.Header) types.Hash {
 	vv.Set(arena.NewUint(h.Timestamp))
 	vv.Set(arena.NewCopyBytes(h.ExtraData))
 
+	// the base fee is only part of the headers past the EIP-1559 fork
+	if h.BaseFee != 0 {
+		vv.Set(arena.NewUint(h.BaseFee))
+	}
+
 	buf := keccak.Keccak256Rlp(nil, vv)
 
 	return types.BytesToHash(buf)
 }
+
+// SetHeaderHash sets the IBFT header hash, for the protocol of the engine config.
+// The hashes of the IBFT blocks are computed outside of the consensus once it is set
+func SetHeaderHash(config map[string]interface{}) error {
+	protocol, err := getProtocol(config)
+	if err != nil {
+		return err
+	}
+
+	consensusProtocol = protocol
+	types.HeaderHash = istanbulHeaderHash
+
+	return nil
+}
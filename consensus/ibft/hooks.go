@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/types"
 )
 
 // Define the type of the IBFT consensus
@@ -18,12 +19,17 @@ const (
 	// PoS defines the Proof of Stake IBFT type,
 	// where the validator set it changed through staking on the Staking SC
 	PoS MechanismType = "PoS"
+
+	// ContractPoS defines the contract based Proof of Stake IBFT type,
+	// where the validator set is read from a staking contract of the chain at the end of the epochs
+	ContractPoS MechanismType = "ContractPoS"
 )
 
 // mechanismTypes is the map used for easy string -> mechanism MechanismType lookups
 var mechanismTypes = map[string]MechanismType{
-	"PoA": PoA,
-	"PoS": PoS,
+	"PoA":         PoA,
+	"PoS":         PoS,
+	"ContractPoS": ContractPoS,
 }
 
 // String is a helper method for casting a MechanismType to a string representation
@@ -82,6 +88,16 @@ const (
 	// CalculateProposerHook defines what is the next proposer
 	// based on the previous
 	CalculateProposerHook = "CalculateProposerHook"
+
+	// CONTRACT POS //
+
+	// EpochValidatorsHook defines the validator set written
+	// into the extra data of the epoch blocks
+	EpochValidatorsHook HookType = "EpochValidatorsHook"
+
+	// VerifyEpochValidatorsHook defines the verification of the validator set
+	// in the extra data of the epoch blocks
+	VerifyEpochValidatorsHook HookType = "VerifyEpochValidatorsHook"
 )
 
 type ConsensusMechanism interface {
@@ -173,12 +189,15 @@ type IBFTFork struct {
 	To                *common.JSONNumber `json:"to,omitempty"`
 	MaxValidatorCount *common.JSONNumber `json:"maxValidatorCount,omitempty"`
 	MinValidatorCount *common.JSONNumber `json:"minValidatorCount,omitempty"`
+	ValidatorContract *types.Address     `json:"validatorContract,omitempty"`
+	ValidatorSelector string             `json:"validatorSelector,omitempty"`
 }
 
 // ConsensusMechanismFactory is the factory function to create a consensus mechanism
 type ConsensusMechanismFactory func(ibft *Ibft, params *IBFTFork) (ConsensusMechanism, error)
 
 var mechanismBackends = map[MechanismType]ConsensusMechanismFactory{
-	PoA: PoAFactory,
-	PoS: PoSFactory,
+	PoA:         PoAFactory,
+	PoS:         PoSFactory,
+	ContractPoS: ContractPoSFactory,
 }
@@ -5,12 +5,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"reflect"
+	"sync/atomic"
 	"time"
 
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/consensus"
 	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/contracts/staking"
 	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/crypto/bls"
 	"github.com/0xPolygon/polygon-edge/helper/common"
 	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
@@ -29,20 +35,27 @@ const (
 )
 
 var (
-	ErrInvalidHookParam     = errors.New("invalid IBFT hook param passed in")
-	ErrInvalidMechanismType = errors.New("invalid consensus mechanism type in params")
-	ErrMissingMechanismType = errors.New("missing consensus mechanism type in params")
+	ErrInvalidHookParam      = errors.New("invalid IBFT hook param passed in")
+	ErrInvalidMechanismType  = errors.New("invalid consensus mechanism type in params")
+	ErrMissingMechanismType  = errors.New("missing consensus mechanism type in params")
+	ErrMissingRoundNumber    = errors.New("round number missing from the extra data")
+	ErrUnexpectedRoundNumber = errors.New("round number present in the extra data before the fork")
+	ErrInvalidTimestamp      = errors.New("invalid block timestamp")
+	ErrUnexpectedProposer    = errors.New("the header is not proposed by the proposer of its round")
 )
 
 type blockchainInterface interface {
 	Header() *types.Header
 	GetHeaderByNumber(i uint64) (*types.Header, bool)
-	WriteBlock(block *types.Block) error
+	GetHeaderByHash(hash types.Hash) (*types.Header, bool)
+	WriteBlock(block *types.Block, source string) error
 	CalculateGasLimit(number uint64) (uint64, error)
+	CalculateBaseFee(parent *types.Header) uint64
+	BadBlocks() []*blockchain.BadBlock
 }
 
 type txPoolInterface interface {
-	Prepare()
+	Prepare(baseFee uint64)
 	Length() uint64
 	Peek() *types.Transaction
 	Pop(tx *types.Transaction)
@@ -54,7 +67,8 @@ type txPoolInterface interface {
 type syncerInterface interface {
 	Start()
 	BestPeer() *protocol.SyncPeer
-	BulkSyncWithPeer(p *protocol.SyncPeer, newBlockHandler func(block *types.Block)) error
+	BulkSyncWithPeer(p *protocol.SyncPeer, newBlockHandler func(block *types.Block) bool) error
+	FastSyncWithPeer(p *protocol.SyncPeer) error
 	WatchSyncWithPeer(p *protocol.SyncPeer, newBlockHandler func(b *types.Block) bool)
 	GetSyncProgression() *progress.Progression
 	Broadcast(b *types.Block)
@@ -62,7 +76,8 @@ type syncerInterface interface {
 
 // Ibft represents the IBFT consensus mechanism object
 type Ibft struct {
-	sealing bool // Flag indicating if the node is a sealer
+	sealing      bool // Flag indicating if the node is a sealer
+	nonValidator bool // Flag indicating if the node follows the chain without a validator key
 
 	logger hclog.Logger      // Output logger
 	config *consensus.Config // Consensus configuration
@@ -72,23 +87,37 @@ type Ibft struct {
 	blockchain blockchainInterface // Interface exposed by the blockchain layer
 	executor   *state.Executor     // Reference to the state executor
 	closeCh    chan struct{}       // Channel for closing
+	loopDoneCh chan struct{}       // Closed once the state machine loop has stopped, nil if not started
 
-	validatorKey     *ecdsa.PrivateKey // Private key for the validator
+	signer           Signer // Signer of the seals and the messages of the validator
 	validatorKeyAddr types.Address
+	rotator          keyRotator // Pending rotation of the validator key, and the previous key after it
+
+	remoteSignerConfig *consensus.RemoteSignerConfig // Remote signer holding the validator key, if set
 
 	txpool txPoolInterface // Reference to the transaction pool
 
-	store     *snapshotStore // Snapshot store that keeps track of all snapshots
-	epochSize uint64
+	store      *snapshotStore  // Snapshot store that keeps track of all snapshots
+	validators *validatorIndex // Validator sets of the epochs, for the queries of the past blocks
+	epochSize  uint64
+	epochs     epochSchedule // Epoch sizes of the chain, including the overrides of the genesis epoch size
+
+	snapshotRetention uint64 // Number of epoch boundary snapshots to keep, 0 keeps all of them
 
 	msgQueue *msgQueue     // Structure containing different message queues
 	updateCh chan struct{} // Update channel
 
-	syncer syncerInterface // Reference to the sync protocol
+	syncer   syncerInterface // Reference to the sync protocol
+	fastSync bool            // Flag indicating if the node fast syncs before the bulk sync
 
 	network   *network.Server // Reference to the networking layer
 	transport transport       // Reference to the transport protocol
 
+	msgRateLimit uint64 // Number of messages per second accepted from a single peer, 0 disables the limit
+
+	protocol Protocol     // Protocol of the extra data, the seals and the messages
+	signers  *signerCache // Signers of the last recovered blocks
+
 	operator *operator
 
 	// aux test methods
@@ -100,7 +129,39 @@ type Ibft struct {
 
 	mechanisms []ConsensusMechanism // IBFT ConsensusMechanism used (PoA / PoS)
 
-	blockTime time.Duration // Minimum block generation time in seconds
+	proposerSelector ProposerSelector // Selects the proposer of every round
+	strictProposer   bool             // Checks the headers are proposed by the proposers of their rounds
+
+	blockTime time.Duration // Minimum block generation time, used before the block time schedule of the chain
+
+	roundTimeoutIncludesBlockTime bool // Extends the round timeouts by the block time the proposers wait for
+
+	allowedFutureDrift time.Duration // Drift of the timestamps ahead of the local clock, the chain's one if not set
+
+	roundNumberBlock *uint64 // Block from which the commit round is part of the extra data, if set
+
+	aggregatedSealBlock *uint64          // Block from which the committed seals are aggregated, if set
+	blsVerifier         *blsSealVerifier // Verifier of the aggregated committed seals, if aggregated
+	blsKey              *bls.SecretKey   // BLS key the committed seals are signed with, if aggregated
+
+	maxExtraDataSize uint64 // Size limit of the extra data of the headers, derived from the validator set if not set
+
+	blockVanity []byte // Vanity bytes written into the extra data of the built blocks, if set
+
+	epochReward *chain.EpochReward // Reward of the committers credited at the epoch blocks, if set
+
+	wal    *msgWAL // Write-ahead log of the sent messages, if enabled
+	walDir string  // Directory of the write-ahead log, the consensus data directory is used if not set
+
+	status             atomic.Value // Latest published *Status, read by the status readers
+	lastCommittedRound *uint64      // Round in which the last block was committed by the node
+	roundStart         time.Time    // Start time of the current round, zero if not in a round
+}
+
+// calculateProposerHookParams are the params passed into the CalculateProposerHook
+type calculateProposerHookParams struct {
+	parent       *types.Header
+	lastProposer types.Address
 }
 
 // runHook runs a specified hook if it is present in the hook map
@@ -133,18 +194,36 @@ func (i *Ibft) runHook(hookName HookType, height uint64, hookParam interface{})
 func Factory(
 	params *consensus.ConsensusParams,
 ) (consensus.Consensus, error) {
-	var epochSize uint64
-	if definedEpochSize, ok := params.Config.Config["epochSize"]; !ok {
-		// No epoch size defined, use the default one
-		epochSize = DefaultEpochSize
-	} else {
-		// Epoch size is defined, use the passed in one
-		readSize, ok := definedEpochSize.(float64)
+	epochSize, err := GetEpochSize(params.Config.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	epochSizeOverrides, err := GetEpochSizeSchedule(params.Config.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	epochs, err := newEpochSchedule(epochSize, epochSizeOverrides)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IBFT epoch size schedule, %w", err)
+	}
+
+	var roundNumberBlock *uint64
+	if definedBlock, ok := params.Config.Config["roundNumberBlock"]; ok {
+		// Round number fork is defined, the round is written to the blocks past it
+		readBlock, ok := definedBlock.(float64)
 		if !ok {
 			return nil, errors.New("invalid type assertion")
 		}
 
-		epochSize = uint64(readSize)
+		forkBlock := uint64(readBlock)
+		roundNumberBlock = &forkBlock
+	}
+
+	maxExtraDataSize, err := getMaxExtraDataSize(params.Config.Config)
+	if err != nil {
+		return nil, err
 	}
 
 	p := &Ibft{
@@ -158,25 +237,116 @@ func Factory(
 		state:          &currentState{},
 		network:        params.Network,
 		epochSize:      epochSize,
-		sealing:        params.Seal,
+		epochs:         epochs,
+		sealing:        params.Seal && !params.NonValidator,
+		nonValidator:   params.NonValidator,
 		metrics:        params.Metrics,
 		secretsManager: params.SecretsManager,
 		blockTime:      time.Duration(params.BlockTime) * time.Second,
+
+		allowedFutureDrift: time.Duration(params.AllowedFutureDrift) * time.Second,
+
+		roundNumberBlock:  roundNumberBlock,
+		maxExtraDataSize:  maxExtraDataSize,
+		snapshotRetention: params.SnapshotRetention,
+		msgRateLimit:      params.MessageRateLimit,
+
+		remoteSignerConfig: params.RemoteSigner,
+		walDir:             params.WALDir,
+	}
+
+	if params.BlockVanity != "" {
+		p.blockVanity = ParseVanity(params.BlockVanity)
+	}
+
+	// Initialize the protocol of the extra data, the seals and the messages
+	if err := p.setupProtocol(); err != nil {
+		return nil, err
+	}
+
+	signers, err := newSignerCache(params.Metrics, maxCachedSigners, p.protocol)
+	if err != nil {
+		return nil, err
 	}
 
+	p.signers = signers
+
 	// Initialize the mechanism
 	if err := p.setupMechanism(); err != nil {
 		return nil, err
 	}
 
+	// Initialize the proposer selection
+	if err := p.setupProposerSelector(); err != nil {
+		return nil, err
+	}
+
+	// Initialize the round timeouts
+	if err := p.setupRoundTimeout(); err != nil {
+		return nil, err
+	}
+
+	// Initialize the verification of the proposers
+	if err := p.setupProposerVerification(); err != nil {
+		return nil, err
+	}
+
+	// Initialize the epoch reward of the committers
+	if err := p.setupEpochReward(); err != nil {
+		return nil, err
+	}
+
+	// Initialize the aggregation of the committed seals
+	if err := p.setupAggregatedSeal(); err != nil {
+		return nil, err
+	}
+
 	// Istanbul requires a different header hash function
 	types.HeaderHash = istanbulHeaderHash
 
-	p.syncer = protocol.NewSyncer(params.Logger, params.Network, params.Blockchain)
+	syncer := protocol.NewSyncer(params.Logger, params.Network, params.Blockchain)
+	syncer.SetStateStorage(params.StateStorage)
+
+	if params.SyncerMetrics != nil {
+		syncer.SetMetrics(params.SyncerMetrics)
+	}
+
+	p.syncer = syncer
+
+	if params.FastSync {
+		if p.supportsFastSync() {
+			p.fastSync = true
+		} else {
+			p.logger.Warn("fast sync is only supported by the PoA chains, using full sync")
+		}
+	}
 
 	return p, nil
 }
 
+// supportsFastSync checks if the validator sets of the chain can be followed without executing the blocks.
+// The PoA validator sets are voted in the headers, while the PoS ones are read from the state
+func (i *Ibft) supportsFastSync() bool {
+	for _, mechanism := range i.mechanisms {
+		if mechanism.GetType() != PoA {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isPoSOnly checks if all the mechanisms of the chain are PoS
+func (i *Ibft) isPoSOnly() bool {
+	for _, mechanism := range i.mechanisms {
+		if mechanism.GetType() != PoS {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Start starts the IBFT consensus
 func (i *Ibft) Initialize() error {
 	// Set up the snapshots
@@ -193,6 +363,7 @@ func (i *Ibft) Start() error {
 	if i.Grpc != nil {
 		i.operator = &operator{ibft: i}
 		proto.RegisterIbftOperatorServer(i.Grpc, i.operator)
+		proto.RegisterIbftLightClientServer(i.Grpc, &lightClientService{ibft: i})
 	}
 
 	// Set up the node's validator key
@@ -200,7 +371,16 @@ func (i *Ibft) Start() error {
 		return err
 	}
 
-	i.logger.Info("validator key", "addr", i.validatorKeyAddr.String())
+	if i.nonValidator {
+		i.logger.Info("non-validator node, the node doesn't take part in the consensus")
+	} else {
+		i.logger.Info("validator key", "addr", i.validatorKeyAddr.String())
+
+		// open the log of the sent messages
+		if err := i.setupWAL(); err != nil {
+			return err
+		}
+	}
 
 	// start the transport protocol
 	if err := i.setupTransport(); err != nil {
@@ -211,6 +391,8 @@ func (i *Ibft) Start() error {
 	i.syncer.Start()
 
 	// Start the actual IBFT protocol
+	i.loopDoneCh = make(chan struct{})
+
 	go i.start()
 
 	return nil
@@ -221,6 +403,16 @@ func (i *Ibft) GetSyncProgression() *progress.Progression {
 	return i.syncer.GetSyncProgression()
 }
 
+// BestPeerNumber returns the height of the best peer, false if no peer is at the height of the node or above
+func (i *Ibft) BestPeerNumber() (uint64, bool) {
+	peer := i.syncer.BestPeer()
+	if peer == nil {
+		return 0, false
+	}
+
+	return peer.Number(), true
+}
+
 type transport interface {
 	Gossip(msg *proto.MessageReq) error
 }
@@ -246,14 +438,21 @@ func GetIBFTForks(ibftConfig map[string]interface{}) ([]IBFTFork, error) {
 			return nil, err
 		}
 
-		return []IBFTFork{
-			{
-				Type:       typ,
-				Deployment: nil,
-				From:       common.JSONNumber{Value: 0},
-				To:         nil,
-			},
-		}, nil
+		fork := IBFTFork{
+			Type:       typ,
+			Deployment: nil,
+			From:       common.JSONNumber{Value: 0},
+			To:         nil,
+		}
+
+		// the validator contract is specified next to the type
+		if typ == ContractPoS {
+			if err := readContractPoSParams(ibftConfig, &fork); err != nil {
+				return nil, err
+			}
+		}
+
+		return []IBFTFork{fork}, nil
 	}
 
 	// with forks
@@ -274,6 +473,167 @@ func GetIBFTForks(ibftConfig map[string]interface{}) ([]IBFTFork, error) {
 	return nil, errors.New("current IBFT type not found")
 }
 
+// readContractPoSParams reads the params of the contract based PoS mechanism from the IBFT config
+func readContractPoSParams(ibftConfig map[string]interface{}, fork *IBFTFork) error {
+	params := map[string]interface{}{}
+
+	for _, key := range []string{"validatorContract", "validatorSelector", "maxValidatorCount"} {
+		if value, ok := ibftConfig[key]; ok {
+			params[key] = value
+		}
+	}
+
+	bytes, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, fork)
+}
+
+// setupProtocol reads the protocol in params and sets it up, along with the header hash of the chain
+func (i *Ibft) setupProtocol() error {
+	protocol, err := getProtocol(i.config.Config)
+	if err != nil {
+		return err
+	}
+
+	i.protocol = protocol
+	consensusProtocol = protocol
+
+	return nil
+}
+
+// getProtocol reads the protocol in params, the IBFT protocol is used if not set
+func getProtocol(config map[string]interface{}) (Protocol, error) {
+	protocol := IBFTProtocol
+
+	if rawProtocol, ok := config["protocol"]; ok {
+		protocolStr, ok := rawProtocol.(string)
+		if !ok {
+			return protocol, errors.New("invalid type assertion")
+		}
+
+		var err error
+		if protocol, err = ParseProtocol(protocolStr); err != nil {
+			return protocol, err
+		}
+	}
+
+	return protocol, nil
+}
+
+// setupProposerSelector reads the proposer selector type in params and sets up the proposer selector
+func (i *Ibft) setupProposerSelector() error {
+	selectorType := RoundRobin
+
+	if rawType, ok := i.config.Config["proposerSelector"]; ok {
+		typeStr, ok := rawType.(string)
+		if !ok {
+			return errors.New("invalid type assertion")
+		}
+
+		var err error
+		if selectorType, err = ParseProposerSelectorType(typeStr); err != nil {
+			return err
+		}
+	}
+
+	switch selectorType {
+	case StakeWeighted:
+		// the stakes are only read from the Staking SC of the PoS mechanism
+		if !i.isPoSOnly() {
+			return errStakeWeightedWithoutPoS
+		}
+
+		i.proposerSelector = &stakeWeightedSelector{
+			getStakes: i.getValidatorStakes,
+		}
+	default:
+		i.proposerSelector = &roundRobinSelector{}
+	}
+
+	return nil
+}
+
+// getValidatorStakes returns the stakes of the validators from the Staking SC at the state of the parent block
+func (i *Ibft) getValidatorStakes(parent *types.Header, validators ValidatorSet) ([]*big.Int, error) {
+	transition, err := i.executor.BeginTxn(parent.StateRoot, parent, types.ZeroAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	stakes := make([]*big.Int, len(validators))
+
+	for indx, validator := range validators {
+		if stakes[indx], err = staking.QueryAccountStake(transition, i.validatorKeyAddr, validator); err != nil {
+			return nil, err
+		}
+	}
+
+	return stakes, nil
+}
+
+// calcProposer selects the proposer of the current round and sets it to the state.
+// The selection errors are returned, the headers are verified with the same selection
+// so that no other proposer can be used instead
+func (i *Ibft) calcProposer(parent *types.Header, lastProposer types.Address) error {
+	proposer, err := i.proposerSelector.SelectProposer(
+		i.state.validators,
+		parent,
+		lastProposer,
+		i.state.view.Round,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to select the proposer: %w", err)
+	}
+
+	i.state.setProposer(proposer)
+
+	return nil
+}
+
+// setupRoundTimeout reads in params if the block time is part of the round timeouts, it is by default
+func (i *Ibft) setupRoundTimeout() error {
+	i.roundTimeoutIncludesBlockTime = true
+
+	rawBlockTime, ok := i.config.Config["roundTimeoutBlockTime"]
+	if !ok {
+		return nil
+	}
+
+	includesBlockTime, ok := rawBlockTime.(bool)
+	if !ok {
+		return errors.New("invalid type assertion")
+	}
+
+	i.roundTimeoutIncludesBlockTime = includesBlockTime
+
+	return nil
+}
+
+// setupProposerVerification reads the proposer verification in params, the verification is lenient by default
+func (i *Ibft) setupProposerVerification() error {
+	rawVerification, ok := i.config.Config["proposerVerification"]
+	if !ok {
+		return nil
+	}
+
+	verificationStr, ok := rawVerification.(string)
+	if !ok {
+		return errors.New("invalid type assertion")
+	}
+
+	verification, err := ParseProposerVerification(verificationStr)
+	if err != nil {
+		return err
+	}
+
+	i.strictProposer = verification == StrictProposerVerification
+
+	return nil
+}
+
 //  setupTransport read current mechanism in params and sets up consensus mechanism
 func (i *Ibft) setupMechanism() error {
 	ibftForks, err := GetIBFTForks(i.config.Config)
@@ -328,7 +688,7 @@ func (i *Ibft) setupTransport() error {
 			return
 		}
 
-		if msg.From == i.validatorKeyAddr.String() {
+		if i.isOwnAddress(types.StringToAddress(msg.From)) {
 			// we are the sender, skip this message since we already
 			// relay our own messages internally.
 			return
@@ -341,18 +701,63 @@ func (i *Ibft) setupTransport() error {
 		return err
 	}
 
+	// drop duplicate messages, and messages from peers that go over the rate limit,
+	// before they are processed or relayed
+	filter, err := newMsgFilter(i.logger, i.metrics, i.network.AddrInfo().ID, i.msgRateLimit)
+	if err != nil {
+		return err
+	}
+
+	if err := topic.RegisterValidator(filter.validate); err != nil {
+		return err
+	}
+
 	i.transport = &gossipTransport{topic: topic}
 
 	return nil
 }
 
-// createKey sets the validator's private key from the secrets manager
+// createKey sets the validator's signer, using the remote signer if one is configured,
+// or the private key from the secrets manager otherwise
 func (i *Ibft) createKey() error {
 	i.msgQueue = newMsgQueue()
 	i.closeCh = make(chan struct{})
 	i.updateCh = make(chan struct{})
 
-	if i.validatorKey == nil {
+	// the non-validator nodes don't have a validator key
+	if i.nonValidator {
+		return nil
+	}
+
+	if i.signer == nil && i.remoteSignerConfig != nil {
+		// The validator key is held by the remote signer
+		signer, err := newRemoteSigner(i.logger, i.remoteSignerConfig)
+		if err != nil {
+			return err
+		}
+
+		i.signer = signer
+		i.validatorKeyAddr = signer.Address()
+	}
+
+	if keySigner, ok := i.secretsManager.(secrets.KeySigner); ok && i.signer == nil {
+		// The validator key is held by the KMS, create it if not present
+		if !i.secretsManager.HasSecret(secrets.ValidatorKey) {
+			if err := keySigner.CreateKey(secrets.ValidatorKey); err != nil {
+				return fmt.Errorf("unable to create validator key in Secrets Manager, %w", err)
+			}
+		}
+
+		signer, err := newKMSSigner(keySigner)
+		if err != nil {
+			return fmt.Errorf("unable to read validator key from Secrets Manager, %w", err)
+		}
+
+		i.signer = signer
+		i.validatorKeyAddr = signer.Address()
+	}
+
+	if i.signer == nil {
 		// Check if the validator key is initialized
 		var key *ecdsa.PrivateKey
 
@@ -380,17 +785,24 @@ func (i *Ibft) createKey() error {
 			key = validatorKey
 		}
 
-		i.validatorKey = key
-		i.validatorKeyAddr = crypto.PubKeyToAddress(&key.PublicKey)
+		i.signer = NewLocalSigner(key)
+		i.validatorKeyAddr = i.signer.Address()
+
+		// resume the rotation of the validator key, if any
+		if err := i.restoreKeyRotation(); err != nil {
+			return fmt.Errorf("unable to read the next validator key from Secrets Manager, %w", err)
+		}
 	}
 
-	return nil
+	return i.createBLSKey()
 }
 
 const IbftKeyName = "validator.key"
 
 // start starts the IBFT consensus state machine
 func (i *Ibft) start() {
+	defer close(i.loopDoneCh)
+
 	// consensus always starts in SyncState mode in case it needs
 	// to sync with other nodes.
 	i.setState(SyncState)
@@ -448,6 +860,8 @@ func (i *Ibft) isValidSnapshot() bool {
 		return false
 	}
 
+	i.applyKeyRotation(header.Number+1, snap)
+
 	if snap.Set.Includes(i.validatorKeyAddr) {
 		i.state.view = &proto.View{
 			Sequence: header.Number + 1,
@@ -472,7 +886,7 @@ func (i *Ibft) runSyncState() {
 		}
 	}
 
-	for i.isState(SyncState) {
+	for i.isState(SyncState) && !i.isClosing() {
 		// try to sync with the best-suited peer
 		p := i.syncer.BestPeer()
 		if p == nil {
@@ -491,15 +905,37 @@ func (i *Ibft) runSyncState() {
 
 				i.setState(AcceptState)
 			} else {
-				time.Sleep(1 * time.Second)
+				select {
+				case <-time.After(1 * time.Second):
+				case <-i.closeCh:
+				}
 			}
 
 			continue
 		}
 
-		if err := i.syncer.BulkSyncWithPeer(p, func(newBlock *types.Block) {
+		if i.fastSync {
+			if err := i.syncer.FastSyncWithPeer(p); err != nil {
+				if !errors.Is(err, protocol.ErrFastSyncUnavailable) {
+					i.logger.Error("failed to fast sync", "err", err)
+
+					continue
+				}
+
+				i.logger.Warn("falling back to full sync", "err", err)
+			}
+
+			// the remaining blocks are executed
+			i.fastSync = false
+		}
+
+		if err := i.syncer.BulkSyncWithPeer(p, func(newBlock *types.Block) bool {
 			callInsertBlockHook(newBlock.Number())
+			i.updateSealMetrics(newBlock.Header)
 			i.txpool.ResetWithHeaders(newBlock.Header)
+
+			// stop after the written block if the node is shutting down
+			return i.isClosing()
 		}); err != nil {
 			i.logger.Error("failed to bulk sync", "err", err)
 
@@ -521,12 +957,13 @@ func (i *Ibft) runSyncState() {
 			// After each written block, update the snapshot store for PoS.
 			// The snapshot store is currently updated for PoA inside the ProcessHeadersHook
 			callInsertBlockHook(newBlock.Number())
+			i.updateSealMetrics(newBlock.Header)
 
 			i.syncer.Broadcast(newBlock)
 			i.txpool.ResetWithHeaders(newBlock.Header)
 			isValidator = i.isValidSnapshot()
 
-			return isValidator
+			return isValidator || i.isClosing()
 		})
 
 		if isValidator {
@@ -536,6 +973,13 @@ func (i *Ibft) runSyncState() {
 			i.setState(AcceptState)
 		}
 	}
+
+	if i.isState(AcceptState) && !i.isClosing() {
+		// pick up the round the node was in before a restart, if any
+		i.restoreFromWAL()
+
+		i.roundStart = time.Now()
+	}
 }
 
 // shouldWriteTransactions checks if each consensus mechanism accepts a block with transactions at given height
@@ -574,6 +1018,9 @@ func (i *Ibft) buildBlock(snap *Snapshot, parent *types.Header) (*types.Block, e
 
 	header.GasLimit = gasLimit
 
+	// set the base fee past the EIP-1559 fork
+	header.BaseFee = i.blockchain.CalculateBaseFee(parent)
+
 	if hookErr := i.runHook(CandidateVoteHook, header.Number, &candidateVoteHookParams{
 		header: header,
 		snap:   snap,
@@ -586,7 +1033,7 @@ func (i *Ibft) buildBlock(snap *Snapshot, parent *types.Header) (*types.Block, e
 
 	// set the timestamp
 	parentTime := time.Unix(int64(parent.Timestamp), 0)
-	headerTime := parentTime.Add(i.blockTime)
+	headerTime := parentTime.Add(i.getBlockTime(header.Number))
 
 	if headerTime.Before(time.Now()) {
 		headerTime = time.Now()
@@ -594,9 +1041,34 @@ func (i *Ibft) buildBlock(snap *Snapshot, parent *types.Header) (*types.Block, e
 
 	header.Timestamp = uint64(headerTime.Unix())
 
+	// set the vanity of the proposer before the validators are included
+	if i.blockVanity != nil {
+		putIbftVanity(header, i.blockVanity)
+	}
+
 	// we need to include in the extra field the current set of validators
 	putIbftExtraValidators(header, snap.Set)
 
+	// the QBFT blocks carry the vote in the extra field, and the proposer in the miner field
+	if isQBFT() {
+		if err := putQbftVote(header, i.validatorKeyAddr); err != nil {
+			return nil, err
+		}
+	}
+
+	// the epoch blocks of the contract based PoS carry the validator set of the next epoch instead
+	if hookErr := i.runHook(EpochValidatorsHook, header.Number, &epochValidatorsHookParams{
+		parent: parent,
+		header: header,
+	}); hookErr != nil {
+		return nil, hookErr
+	}
+
+	// the committed seals of the parent are copied, the committers are rewarded at the epoch blocks
+	if err := i.putParentCommittedSeal(parent, header); err != nil {
+		return nil, err
+	}
+
 	transition, err := i.executor.BeginTxn(parent.StateRoot, header, i.validatorKeyAddr)
 	if err != nil {
 		return nil, err
@@ -605,7 +1077,7 @@ func (i *Ibft) buildBlock(snap *Snapshot, parent *types.Header) (*types.Block, e
 	// If the mechanism is PoA -> always build a regular block, regardless of epoch
 	txns := []*types.Transaction{}
 	if i.shouldWriteTransactions(header.Number) {
-		txns = i.writeTransactions(gasLimit, transition)
+		txns = i.writeTransactions(gasLimit, header.BaseFee, i.getSizeLimits(header.Number), transition)
 	}
 
 	if err := i.PreStateCommit(header, transition); err != nil {
@@ -624,7 +1096,7 @@ func (i *Ibft) buildBlock(snap *Snapshot, parent *types.Header) (*types.Block, e
 	})
 
 	// write the seal of the block after all the fields are completed
-	header, err = writeSeal(i.validatorKey, block.Header)
+	header, err = writeSeal(i.signer, block.Header)
 	if err != nil {
 		return nil, err
 	}
@@ -647,13 +1119,21 @@ type transitionInterface interface {
 
 // writeTransactions writes transactions from the txpool to the transition object
 // and returns transactions that were included in the transition (new block)
-func (i *Ibft) writeTransactions(gasLimit uint64, transition transitionInterface) []*types.Transaction {
+func (i *Ibft) writeTransactions(
+	gasLimit uint64,
+	baseFee uint64,
+	sizeLimits chain.SizeLimits,
+	transition transitionInterface,
+) []*types.Transaction {
 	var transactions []*types.Transaction
 
 	successTxCount := 0
 	failedTxCount := 0
 
-	i.txpool.Prepare()
+	// size is the total size of the transactions of the block
+	size := uint64(0)
+
+	i.txpool.Prepare(baseFee)
 
 	for {
 		tx := i.txpool.Peek()
@@ -661,6 +1141,19 @@ func (i *Ibft) writeTransactions(gasLimit uint64, transition transitionInterface
 			break
 		}
 
+		if err := sizeLimits.VerifyTx(tx); err != nil {
+			failedTxCount++
+
+			i.txpool.Drop(tx)
+
+			continue
+		}
+
+		// the block is full, as when the gas limit is reached
+		if err := sizeLimits.VerifyBlockSize(size, tx); err != nil {
+			break
+		}
+
 		if tx.ExceedsBlockGasLimit(gasLimit) {
 			if err := transition.WriteFailedReceipt(tx); err != nil {
 				failedTxCount++
@@ -673,6 +1166,7 @@ func (i *Ibft) writeTransactions(gasLimit uint64, transition transitionInterface
 			failedTxCount++
 
 			transactions = append(transactions, tx)
+			size += tx.Size()
 			i.txpool.Drop(tx)
 
 			continue
@@ -697,6 +1191,7 @@ func (i *Ibft) writeTransactions(gasLimit uint64, transition transitionInterface
 		successTxCount++
 
 		transactions = append(transactions, tx)
+		size += tx.Size()
 	}
 
 	//nolint:lll
@@ -738,6 +1233,8 @@ func (i *Ibft) runAcceptState() { // start new round
 		return
 	}
 
+	i.applyKeyRotation(number, snap)
+
 	if !snap.Set.Includes(i.validatorKeyAddr) {
 		// we are not a validator anymore, move back to sync state
 		i.logger.Info("we are not a validator anymore")
@@ -760,13 +1257,26 @@ func (i *Ibft) runAcceptState() { // start new round
 	// select the proposer of the block
 	var lastProposer types.Address
 	if parent.Number != 0 {
-		lastProposer, _ = ecrecoverFromHeader(parent)
-	}
-
-	if hookErr := i.runHook(CalculateProposerHook, i.state.view.Sequence, lastProposer); hookErr != nil {
+		lastProposer, _ = i.signers.proposer(parent)
+	}
+
+	if hookErr := i.runHook(
+		CalculateProposerHook,
+		i.state.view.Sequence,
+		&calculateProposerHookParams{
+			parent:       parent,
+			lastProposer: lastProposer,
+		},
+	); hookErr != nil {
+		// the round is skipped, no block can be proposed or accepted without its proposer
 		i.logger.Error(fmt.Sprintf("Unable to run hook %s, %v", CalculateProposerHook, hookErr))
+		i.setState(RoundChangeState)
+
+		return
 	}
 
+	i.publishStatus()
+
 	if i.state.proposer == i.validatorKeyAddr {
 		logger.Info("we are the proposer", "block", number)
 
@@ -807,7 +1317,7 @@ func (i *Ibft) runAcceptState() { // start new round
 	// we are NOT a proposer for the block. Then, we have to wait
 	// for a pre-prepare message from the proposer
 
-	timeout := exponentialTimeout(i.state.view.Round)
+	timeout := i.roundTimeout()
 	for i.getState() == AcceptState {
 		msg, ok := i.getNextMessage(timeout)
 		if !ok {
@@ -845,6 +1355,14 @@ func (i *Ibft) runAcceptState() { // start new round
 				i.handleStateErr(errIncorrectBlockLocked)
 			}
 		} else {
+			// the QBFT blocks aren't sealed, the miner has to be the proposer instead
+			if isQBFT() && block.Header.Miner != i.state.proposer {
+				i.logger.Error("block verification failed", "err", errIncorrectQbftMiner)
+				i.handleStateErr(errBlockVerificationFailed)
+
+				continue
+			}
+
 			// since it's a new block, we have to verify it first
 			if err := i.verifyHeaderImpl(snap, parent, block.Header); err != nil {
 				i.logger.Error("block verification failed", "err", err)
@@ -891,7 +1409,7 @@ func (i *Ibft) runValidateState() {
 		}
 	}
 
-	timeout := exponentialTimeout(i.state.view.Round)
+	timeout := i.roundTimeout()
 	for i.getState() == ValidateState {
 		msg, ok := i.getNextMessage(timeout)
 		if !ok {
@@ -967,15 +1485,76 @@ func (i *Ibft) updateMetrics(block *types.Block) {
 
 	//Update the Number of transactions in the block metric
 	i.metrics.NumTxs.Set(float64(len(block.Body().Transactions)))
+
+	//Update the round metrics, the round is not in the extra data before the fork
+	i.observeRound()
+
+	if i.lastCommittedRound != nil {
+		i.metrics.CommittedRound.Set(float64(*i.lastCommittedRound))
+	}
+
+	i.updateSealMetrics(block.Header)
 }
-func (i *Ibft) insertBlock(block *types.Block) error {
+
+// observeRound records the time spent in the current round, and starts the next one
+func (i *Ibft) observeRound() {
+	now := time.Now()
+
+	if !i.roundStart.IsZero() {
+		i.metrics.RoundDuration.Observe(now.Sub(i.roundStart).Seconds())
+	}
+
+	i.roundStart = now
+}
+
+// updateSealMetrics updates the metrics derived from the seals of a written header,
+// the last height sealed by each committer tells which validators are offline
+func (i *Ibft) updateSealMetrics(header *types.Header) {
+	extra, err := GetIbftExtra(header)
+	if err != nil {
+		return
+	}
+
+	if extra.RoundNumber != nil {
+		i.metrics.CommittedRound.Set(float64(*extra.RoundNumber))
+	}
+
+	if proposer, err := i.signers.proposer(header); err == nil {
+		i.metrics.ProposedBlocks.With("validator", proposer.String()).Add(1)
+	}
+
+	committers, err := i.signers.committers(header)
+	if err != nil {
+		i.logger.Debug("failed to recover the committers", "number", header.Number, "err", err)
+
+		return
+	}
+
+	i.metrics.CommittedSeals.Set(float64(len(committers)))
+
+	for _, committer := range committers {
+		i.metrics.ValidatorLastSeal.With("validator", committer.String()).Set(float64(header.Number))
+	}
+}
+
+// writeStateCommittedSeals writes the committed seals of the current state to the header,
+// aggregated past the aggregated seal fork
+func (i *Ibft) writeStateCommittedSeals(h *types.Header, round *uint64) (*types.Header, error) {
+	if i.aggregatesSeals(h.Number) {
+		return i.writeAggregatedCommittedSeal(h, round)
+	}
+
 	committedSeals := [][]byte{}
 	for _, commit := range i.state.committed {
 		// no need to check the format of seal here because writeCommittedSeals will check
 		committedSeals = append(committedSeals, hex.MustDecodeHex(commit.Seal))
 	}
 
-	header, err := writeCommittedSeals(block.Header, committedSeals)
+	return writeCommittedSeals(h, committedSeals, round)
+}
+
+func (i *Ibft) insertBlock(block *types.Block) error {
+	header, err := i.writeStateCommittedSeals(block.Header, i.commitRound(block.Number()))
 	if err != nil {
 		return err
 	}
@@ -984,10 +1563,16 @@ func (i *Ibft) insertBlock(block *types.Block) error {
 	block.Header = header
 	block.Header.ComputeHash()
 
-	if err := i.blockchain.WriteBlock(block); err != nil {
+	if err := i.blockchain.WriteBlock(block, blockchain.SourceSealer); err != nil {
 		return err
 	}
 
+	if i.wal != nil {
+		if err := i.wal.truncate(header.Number); err != nil {
+			i.logger.Error("failed to truncate the message log", "err", err)
+		}
+	}
+
 	if hookErr := i.runHook(InsertBlockHook, header.Number, header.Number); hookErr != nil {
 		return hookErr
 	}
@@ -1001,6 +1586,9 @@ func (i *Ibft) insertBlock(block *types.Block) error {
 		"committed", i.state.numCommitted(),
 	)
 
+	committedRound := i.state.view.Round
+	i.lastCommittedRound = &committedRound
+
 	// increase the sequence number and reset the round if any
 	i.state.view = &proto.View{
 		Sequence: header.Number + 1,
@@ -1021,6 +1609,7 @@ var (
 	errIncorrectBlockLocked    = fmt.Errorf("block locked is incorrect")
 	errBlockVerificationFailed = fmt.Errorf("block verification failed")
 	errFailedToInsertBlock     = fmt.Errorf("failed to insert block")
+	errIncorrectQbftMiner      = fmt.Errorf("miner of the block is not the proposer")
 )
 
 func (i *Ibft) handleStateErr(err error) {
@@ -1031,9 +1620,11 @@ func (i *Ibft) handleStateErr(err error) {
 func (i *Ibft) runRoundChangeState() {
 	sendRoundChange := func(round uint64) {
 		i.logger.Debug("local round change", "round", round+1)
-		// set the new round and update the round metric
+		// set the new round and update the round metrics
+		i.observeRound()
 		i.state.view.Round = round
 		i.metrics.Rounds.Set(float64(round))
+		i.publishStatus()
 		// clean the round
 		i.state.cleanRound(round)
 		// send the round change message
@@ -1082,7 +1673,7 @@ func (i *Ibft) runRoundChangeState() {
 	}
 
 	// create a timer for the round change
-	timeout := exponentialTimeout(i.state.view.Round)
+	timeout := i.roundTimeout()
 	for i.getState() == RoundChangeState {
 		msg, ok := i.getNextMessage(timeout)
 		if !ok {
@@ -1094,7 +1685,7 @@ func (i *Ibft) runRoundChangeState() {
 			i.logger.Debug("round change timeout")
 			checkTimeout()
 			// update the timeout duration
-			timeout = exponentialTimeout(i.state.view.Round)
+			timeout = i.roundTimeout()
 
 			continue
 		}
@@ -1105,7 +1696,7 @@ func (i *Ibft) runRoundChangeState() {
 		if num == i.state.validators.MaxFaultyNodes()+1 && i.state.view.Round < msg.View.Round {
 			// weak certificate, try to catch up if our round number is smaller
 			// update timer
-			timeout = exponentialTimeout(i.state.view.Round)
+			timeout = i.roundTimeout()
 			sendRoundChange(msg.View.Round)
 		} else if num == i.state.validators.QuorumSize() {
 			// start a new round immediately
@@ -1141,6 +1732,35 @@ func (i *Ibft) gossip(typ proto.MessageReq_Type) {
 	// add View
 	msg.View = i.state.view.Copy()
 
+	// the block the message refers to, kept in the log of the sent messages
+	var proposal *types.Block
+	if msg.Type != proto.MessageReq_RoundChange {
+		proposal = i.state.block
+	}
+
+	if i.wal != nil && proposal != nil {
+		// never send messages for two different proposals in the same round,
+		// even if the node was restarted in between
+		conflicting, err := i.isConflictingMsg(msg.View, proposal)
+		if err != nil {
+			i.logger.Error("failed to read the message log", "err", err)
+
+			return
+		}
+
+		if conflicting {
+			i.logger.Error(
+				"refusing to send message",
+				"type", msg.Type,
+				"sequence", msg.View.Sequence,
+				"round", msg.View.Round,
+				"err", errConflictingMsg,
+			)
+
+			return
+		}
+	}
+
 	// if we are sending a preprepare message we need to include the proposed block
 	if msg.Type == proto.MessageReq_Preprepare {
 		msg.Proposal = &anypb.Any{
@@ -1150,7 +1770,10 @@ func (i *Ibft) gossip(typ proto.MessageReq_Type) {
 
 	// if the message is commit, we need to add the committed seal
 	if msg.Type == proto.MessageReq_Commit {
-		seal, err := writeCommittedSeal(i.validatorKey, i.state.block.Header)
+		seal, err := i.signCommittedSeal(
+			i.state.block.Header,
+			i.commitRound(i.state.block.Number()),
+		)
 		if err != nil {
 			i.logger.Error("failed to commit seal", "err", err)
 
@@ -1160,19 +1783,32 @@ func (i *Ibft) gossip(typ proto.MessageReq_Type) {
 		msg.Seal = hex.EncodeToHex(seal)
 	}
 
+	// a copy is sent to ourselves so that we can process this message as well
+	var msg2 *proto.MessageReq
 	if msg.Type != proto.MessageReq_Preprepare {
-		// send a copy to ourselves so that we can process this message as well
-		msg2 := msg.Copy()
+		msg2 = msg.Copy()
 		msg2.From = i.validatorKeyAddr.String()
-		i.pushMessage(msg2)
 	}
 
-	if err := signMsg(i.validatorKey, msg); err != nil {
+	if err := signMsg(i.signer, msg); err != nil {
 		i.logger.Error("failed to sign message", "err", err)
 
 		return
 	}
 
+	if i.wal != nil {
+		// log the message before the node acts on it, or it leaves the node
+		if err := i.wal.write(msg, proposal); err != nil {
+			i.logger.Error("failed to write the message log", "err", err)
+
+			return
+		}
+	}
+
+	if msg2 != nil {
+		i.pushMessage(msg2)
+	}
+
 	if err := i.transport.Gossip(msg); err != nil {
 		i.logger.Error("failed to gossip", "err", err)
 	}
@@ -1192,6 +1828,7 @@ func (i *Ibft) isState(s IbftState) bool {
 func (i *Ibft) setState(s IbftState) {
 	i.logger.Info("state change", "new", s)
 	i.state.setState(s)
+	i.publishStatus()
 }
 
 // forceTimeout sets the forceTimeoutCh flag to true
@@ -1206,8 +1843,13 @@ func (i *Ibft) isSealing() bool {
 
 // verifyHeaderImpl implements the actual header verification logic
 func (i *Ibft) verifyHeaderImpl(snap *Snapshot, parent, header *types.Header) error {
+	// reject the oversized extra data before decoding it
+	if err := i.verifyExtraDataSize(snap, header); err != nil {
+		return err
+	}
+
 	// ensure the extra data is correctly formatted
-	if _, err := getIbftExtra(header); err != nil {
+	if _, err := GetIbftExtra(header); err != nil {
 		return err
 	}
 
@@ -1228,8 +1870,35 @@ func (i *Ibft) verifyHeaderImpl(snap *Snapshot, parent, header *types.Header) er
 		return fmt.Errorf("wrong difficulty")
 	}
 
+	// the block time of the chain has to pass between the blocks, which can't be in the future
+	if err := i.verifyTimestamp(parent, header); err != nil {
+		return err
+	}
+
 	// verify the sealer
-	if err := verifySigner(snap, header); err != nil {
+	proposer, err := i.signers.proposer(header)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyProposer(snap, proposer); err != nil {
+		return err
+	}
+
+	// the validators voted in past the aggregated seal fork have to aggregate their seals
+	if err := i.verifyCandidateBLSKey(snap, header); err != nil {
+		return err
+	}
+
+	if hookErr := i.runHook(VerifyEpochValidatorsHook, header.Number, &epochValidatorsHookParams{
+		parent: parent,
+		header: header,
+	}); hookErr != nil {
+		return hookErr
+	}
+
+	// the committed seals of the parent are the proof of the committers rewarded at the epoch blocks
+	if err := i.verifyParentCommittedSeal(parent, header); err != nil {
 		return err
 	}
 
@@ -1248,11 +1917,185 @@ func (i *Ibft) VerifyHeader(parent, header *types.Header) error {
 		return err
 	}
 
+	// verify the round number is present only past the fork
+	if err := i.verifyRoundNumber(header); err != nil {
+		return err
+	}
+
+	// verify the header is proposed in turn
+	if err := i.verifyProposerSlot(snap.Set, parent, header); err != nil {
+		return err
+	}
+
 	// verify the committed seals
-	if err := verifyCommitedFields(snap, header); err != nil {
+	if err := i.verifyCommittedSeals(header, snap.Set); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getBlockTime returns the block time at the given height.
+// The block time schedule of the chain takes precedence over the block time of the node
+func (i *Ibft) getBlockTime(height uint64) time.Duration {
+	if i.config.Params != nil {
+		if blockTime, ok := i.config.Params.BlockTimeAt(height); ok {
+			return time.Duration(blockTime) * time.Second
+		}
+	}
+
+	return i.blockTime
+}
+
+// getSizeLimits returns the size limits of the transactions of the block at the given height
+func (i *Ibft) getSizeLimits(height uint64) chain.SizeLimits {
+	if i.config.Params == nil {
+		return chain.SizeLimits{}
+	}
+
+	return i.config.Params.SizeLimitsAt(height)
+}
+
+// getAllowedFutureDrift returns how far ahead of the local clock the timestamp of the block can be.
+// The drift of the node takes precedence over the drift of the chain, one block time is allowed if neither is set
+func (i *Ibft) getAllowedFutureDrift(height uint64) time.Duration {
+	if i.allowedFutureDrift != 0 {
+		return i.allowedFutureDrift
+	}
+
+	if i.config.Params != nil && i.config.Params.AllowedFutureDrift != 0 {
+		return time.Duration(i.config.Params.AllowedFutureDrift) * time.Second
+	}
+
+	return i.getBlockTime(height)
+}
+
+// verifyFutureTimestamp checks the header is not further ahead of the local clock than the allowed drift.
+// Such a header is not invalid, it is retried once the local clock catches up
+func (i *Ibft) verifyFutureTimestamp(header *types.Header, now time.Time) error {
+	drift := i.getAllowedFutureDrift(header.Number)
+
+	if headerTime := time.Unix(int64(header.Timestamp), 0); headerTime.After(now.Add(drift)) {
+		return fmt.Errorf(
+			"%w, timestamp %d is %s ahead of the local clock, the allowed drift is %s",
+			blockchain.ErrFutureBlock,
+			header.Timestamp,
+			headerTime.Sub(now).Round(time.Second),
+			drift,
+		)
+	}
+
+	return nil
+}
+
+// verifyTimestamp checks the header is not too far ahead of the local clock, and that it is
+// at least the scheduled block time past its parent.
+// The block times of the blocks the block time schedule doesn't cover are not checked
+func (i *Ibft) verifyTimestamp(parent, header *types.Header) error {
+	if err := i.verifyFutureTimestamp(header, time.Now()); err != nil {
 		return err
 	}
 
+	if i.config.Params == nil {
+		return nil
+	}
+
+	blockTime, ok := i.config.Params.BlockTimeAt(header.Number)
+	if !ok {
+		return nil
+	}
+
+	if header.Timestamp < parent.Timestamp+blockTime {
+		return fmt.Errorf(
+			"%w, timestamp %d is less than %d seconds past the parent timestamp %d",
+			ErrInvalidTimestamp,
+			header.Timestamp,
+			blockTime,
+			parent.Timestamp,
+		)
+	}
+
+	return nil
+}
+
+// isRoundNumberFork checks if the round number is part of the extra data at the given height.
+// The QBFT extra data always has the round number
+func (i *Ibft) isRoundNumberFork(height uint64) bool {
+	return isQBFT() || i.roundNumberBlock != nil && height >= *i.roundNumberBlock
+}
+
+// commitRound returns the current round if it needs to be committed to at the given height,
+// nil otherwise
+func (i *Ibft) commitRound(height uint64) *uint64 {
+	if !i.isRoundNumberFork(height) {
+		return nil
+	}
+
+	round := i.state.view.Round
+
+	return &round
+}
+
+// verifyProposerSlot checks that the header is proposed by the proposer selected for its round
+// on top of the parent, if the proposer verification is strict.
+// The headers without the round number in the extra data are of round 0
+func (i *Ibft) verifyProposerSlot(validators ValidatorSet, parent, header *types.Header) error {
+	if !i.strictProposer {
+		return nil
+	}
+
+	extra, err := GetIbftExtra(header)
+	if err != nil {
+		return err
+	}
+
+	var round uint64
+	if extra.RoundNumber != nil {
+		round = *extra.RoundNumber
+	}
+
+	proposer, err := i.signers.proposer(header)
+	if err != nil {
+		return err
+	}
+
+	var lastProposer types.Address
+	if parent.Number != 0 {
+		if lastProposer, err = i.signers.proposer(parent); err != nil {
+			return err
+		}
+	}
+
+	expected, err := i.proposerSelector.SelectProposer(validators, parent, lastProposer, round)
+	if err != nil {
+		return err
+	}
+
+	if proposer != expected {
+		return fmt.Errorf("%w: proposed by %s in round %d, expected %s", ErrUnexpectedProposer, proposer, round, expected)
+	}
+
+	return nil
+}
+
+// verifyRoundNumber checks that the round number is present in the extra data
+// if and only if the header is past the round number fork
+func (i *Ibft) verifyRoundNumber(header *types.Header) error {
+	extra, err := GetIbftExtra(header)
+	if err != nil {
+		return err
+	}
+
+	isFork := i.isRoundNumberFork(header.Number)
+
+	if isFork && extra.RoundNumber == nil {
+		return ErrMissingRoundNumber
+	}
+
+	if !isFork && extra.RoundNumber != nil {
+		return ErrUnexpectedRoundNumber
+	}
+
 	return nil
 }
 
@@ -1263,7 +2106,7 @@ func (i *Ibft) ProcessHeaders(headers []*types.Header) error {
 
 // GetBlockCreator retrieves the block signer from the extra data field
 func (i *Ibft) GetBlockCreator(header *types.Header) (types.Address, error) {
-	return ecrecoverFromHeader(header)
+	return i.signers.proposer(header)
 }
 
 // PreStateCommit a hook to be called before finalizing state transition on inserting block
@@ -1276,33 +2119,76 @@ func (i *Ibft) PreStateCommit(header *types.Header, txn *state.Transition) error
 		return hookErr
 	}
 
-	return nil
+	return i.applyEpochReward(header, txn)
 }
 
-// GetEpoch returns the current epoch
-func (i *Ibft) GetEpoch(number uint64) uint64 {
-	if number%i.epochSize == 0 {
-		return number / i.epochSize
+// getEpochSchedule returns the epoch schedule of the chain
+func (i *Ibft) getEpochSchedule() epochSchedule {
+	if i.epochs == nil {
+		// no schedule set up, the genesis epoch size is used for all the blocks
+		return epochSchedule{{from: 0, size: i.epochSize}}
 	}
 
-	return number/i.epochSize + 1
+	return i.epochs
+}
+
+// GetEpoch returns the current epoch
+func (i *Ibft) GetEpoch(number uint64) uint64 {
+	return i.getEpochSchedule().epoch(number)
 }
 
 // IsLastOfEpoch checks if the block number is the last of the epoch
 func (i *Ibft) IsLastOfEpoch(number uint64) bool {
-	return number > 0 && number%i.epochSize == 0
+	return number > 0 && i.getEpochSchedule().isEpochBlock(number)
 }
 
-// Close closes the IBFT consensus mechanism, and does write back to disk
+// isClosing checks if the consensus is shutting down
+func (i *Ibft) isClosing() bool {
+	select {
+	case <-i.closeCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close closes the IBFT consensus mechanism, and does write back to disk.
+// The current sequence is either committed or aborted before the state machine stops
 func (i *Ibft) Close() error {
 	close(i.closeCh)
 
+	if i.loopDoneCh != nil {
+		<-i.loopDoneCh
+	}
+
+	if signer, ok := i.signer.(*remoteSigner); ok {
+		if err := signer.Close(); err != nil {
+			i.logger.Error("failed to close the remote signer connection", "err", err)
+		}
+	}
+
+	if i.wal != nil {
+		if err := i.wal.close(); err != nil {
+			i.logger.Error("failed to close the message log", "err", err)
+		}
+	}
+
 	if i.config.Path != "" {
+		// rewrite only the retained snapshots
+		i.store.prune(i.getEpochSchedule(), i.snapshotRetention)
+		i.store.compact(i.getEpochSchedule())
+
 		err := i.store.saveToPath(i.config.Path)
 
 		if err != nil {
 			return err
 		}
+
+		if i.validators != nil {
+			if err := i.validators.saveToPath(i.config.Path); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
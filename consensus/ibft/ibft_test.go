@@ -3,10 +3,12 @@ package ibft
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"testing"
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/consensus"
 	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
 	"github.com/0xPolygon/polygon-edge/helper/common"
@@ -15,6 +17,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/protocol"
 	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/go-kit/kit/metrics"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
 	anypb "google.golang.org/protobuf/types/known/anypb"
@@ -153,13 +156,33 @@ func TestTransition_AcceptState_Proposer_Locked(t *testing.T) {
 	}
 }
 
+func TestTransition_AcceptState_ProposerSelectionFails(t *testing.T) {
+	// A is the proposer of the round robin, but the stakes can't be queried
+	i := newMockIbft(t, []string{"A", "B", "C", "D"}, "A")
+	i.proposerSelector = &stakeWeightedSelector{
+		getStakes: func(_ *types.Header, _ ValidatorSet) ([]*big.Int, error) {
+			return nil, errors.New("stakes query failed")
+		},
+	}
+	i.setState(AcceptState)
+
+	i.runCycle()
+
+	// the round is skipped, no other proposer is used
+	i.expect(expectResult{
+		sequence: 1,
+		state:    RoundChangeState,
+	})
+	assert.Equal(t, types.ZeroAddress, i.state.proposer)
+}
+
 func TestTransition_AcceptState_Validator_VerifyCorrect(t *testing.T) {
 	i := newMockIbft(t, []string{"A", "B", "C"}, "B")
 	i.state.view = proto.ViewMsg(1, 0)
 	i.setState(AcceptState)
 
 	block := i.DummyBlock()
-	header, err := writeSeal(i.pool.get("A").priv, block.Header)
+	header, err := writeSeal(i.pool.get("A").signer(), block.Header)
 
 	assert.NoError(t, err)
 
@@ -192,7 +215,7 @@ func TestTransition_AcceptState_Validator_VerifyFails(t *testing.T) {
 	block := i.DummyBlock()
 	block.Header.MixHash = types.Hash{} // invalidates the block
 
-	header, err := writeSeal(i.pool.get("A").priv, block.Header)
+	header, err := writeSeal(i.pool.get("A").signer(), block.Header)
 
 	assert.NoError(t, err)
 
@@ -594,7 +617,7 @@ func TestWriteTransactions(t *testing.T) {
 			m.txpool = mockTxPool
 			mockTransition := setupMockTransition(test, mockTxPool)
 
-			included := m.writeTransactions(1000, mockTransition)
+			included := m.writeTransactions(1000, 0, chain.SizeLimits{}, mockTransition)
 
 			assert.Equal(t, uint64(test.params.expectedTxPoolLength), m.txpool.Length())
 			assert.Equal(t, test.params.expectedFailReceiptsWritten, len(mockTransition.failReceiptsWritten))
@@ -609,6 +632,32 @@ func TestWriteTransactions(t *testing.T) {
 	}
 }
 
+func TestWriteTransactions_SizeLimits(t *testing.T) {
+	newTx := func(nonce uint64, inputSize int) *types.Transaction {
+		return &types.Transaction{Nonce: nonce, Gas: 1, Input: make([]byte, inputSize)}
+	}
+
+	oversized := newTx(0, 300)
+	txns := []*types.Transaction{oversized, newTx(1, 100), newTx(2, 100), newTx(3, 100)}
+
+	m := newMockIbft(t, []string{"A", "B", "C"}, "A")
+	mockTxPool := &mockTxPool{}
+	mockTxPool.transactions = append(mockTxPool.transactions, txns...)
+	m.txpool = mockTxPool
+
+	limits := chain.SizeLimits{
+		MaxBlockSize: txns[1].Size() + txns[2].Size(),
+		MaxTxSize:    200,
+	}
+
+	included := m.writeTransactions(1000, 0, limits, &mockTransition{})
+
+	// the oversized transaction is dropped, the block is full before the last one which stays in the pool
+	assert.Equal(t, txns[1:3], included)
+	assert.True(t, mockTxPool.nonceDecreased[oversized])
+	assert.Equal(t, []*types.Transaction{txns[3]}, mockTxPool.transactions)
+}
+
 func TestRunSyncState_NewHeadReceivedFromPeer_CallsTxPoolResetWithHeaders(t *testing.T) {
 	m := newMockIbft(t, []string{"A", "B", "C"}, "A")
 	m.setState(SyncState)
@@ -668,11 +717,46 @@ func TestRunSyncState_BulkSyncWithPeer_CallsTxPoolResetWithHeaders(t *testing.T)
 	)
 }
 
+func TestRunSyncState_FastSyncUnavailable_FallsBackToBulkSync(t *testing.T) {
+	m := newMockIbft(t, []string{"A", "B", "C"}, "A")
+	m.setState(SyncState)
+	m.fastSync = true
+
+	expectedNewBlocksToSync := []*types.Block{
+		{Header: &types.Header{Number: 1}},
+	}
+	mockSyncer := &mockSyncer{}
+	mockSyncer.bulkSyncBlocksFromPeer = expectedNewBlocksToSync
+	mockSyncer.fastSyncErr = fmt.Errorf("%w, no peer serves the state", protocol.ErrFastSyncUnavailable)
+	m.syncer = mockSyncer
+	mockTxPool := &mockTxPool{}
+	m.txpool = mockTxPool
+
+	// we need to change state from Sync in order to break from the loop inside runSyncState
+	stateChangeDelay := time.After(100 * time.Millisecond)
+
+	go func() {
+		<-stateChangeDelay
+		m.setState(AcceptState)
+	}()
+
+	m.runSyncState()
+
+	assert.True(t, mockSyncer.fastSyncCalled)
+	assert.False(t, m.fastSync)
+
+	// the blocks are executed by the bulk sync
+	assert.True(t, mockTxPool.resetWithHeaderCalled)
+	assert.Equal(t, expectedNewBlocksToSync[0].Header, mockTxPool.resetWithHeadersParam[0])
+}
+
 type mockSyncer struct {
 	bulkSyncBlocksFromPeer  []*types.Block
 	receivedNewHeadFromPeer *types.Block
 	broadcastedBlock        *types.Block
 	broadcastCalled         bool
+	fastSyncCalled          bool
+	fastSyncErr             error
 }
 
 func (s *mockSyncer) Start() {}
@@ -681,14 +765,22 @@ func (s *mockSyncer) BestPeer() *protocol.SyncPeer {
 	return &protocol.SyncPeer{}
 }
 
-func (s *mockSyncer) BulkSyncWithPeer(p *protocol.SyncPeer, handler func(block *types.Block)) error {
+func (s *mockSyncer) BulkSyncWithPeer(p *protocol.SyncPeer, handler func(block *types.Block) bool) error {
 	for _, block := range s.bulkSyncBlocksFromPeer {
-		handler(block)
+		if handler(block) {
+			break
+		}
 	}
 
 	return nil
 }
 
+func (s *mockSyncer) FastSyncWithPeer(p *protocol.SyncPeer) error {
+	s.fastSyncCalled = true
+
+	return s.fastSyncErr
+}
+
 func (s *mockSyncer) WatchSyncWithPeer(p *protocol.SyncPeer, handler func(b *types.Block) bool) {
 	if s.receivedNewHeadFromPeer != nil {
 		handler(s.receivedNewHeadFromPeer)
@@ -712,7 +804,7 @@ type mockTxPool struct {
 	resetWithHeadersParam []*types.Header
 }
 
-func (p *mockTxPool) Prepare() {
+func (p *mockTxPool) Prepare(baseFee uint64) {
 
 }
 
@@ -831,7 +923,11 @@ func (m *mockIbft) GetHeaderByNumber(i uint64) (*types.Header, bool) {
 	return m.blockchain.GetHeaderByNumber(i)
 }
 
-func (m *mockIbft) WriteBlock(block *types.Block) error {
+func (m *mockIbft) GetHeaderByHash(hash types.Hash) (*types.Header, bool) {
+	return m.blockchain.GetHeaderByHash(hash)
+}
+
+func (m *mockIbft) WriteBlock(block *types.Block, _ string) error {
 	return nil
 }
 
@@ -861,6 +957,14 @@ func (m *mockIbft) CalculateGasLimit(number uint64) (uint64, error) {
 	return m.blockchain.CalculateGasLimit(number)
 }
 
+func (m *mockIbft) CalculateBaseFee(parent *types.Header) uint64 {
+	return m.blockchain.CalculateBaseFee(parent)
+}
+
+func (m *mockIbft) BadBlocks() []*blockchain.BadBlock {
+	return m.blockchain.BadBlocks()
+}
+
 func newMockIbft(t *testing.T, accounts []string, account string) *mockIbft {
 	t.Helper()
 
@@ -889,7 +993,7 @@ func newMockIbft(t *testing.T, accounts []string, account string) *mockIbft {
 		logger:           hclog.NewNullLogger(),
 		config:           &consensus.Config{},
 		blockchain:       m,
-		validatorKey:     addr.priv,
+		signer:           addr.signer(),
 		validatorKeyAddr: addr.Address(),
 		closeCh:          make(chan struct{}),
 		updateCh:         make(chan struct{}),
@@ -897,6 +1001,9 @@ func newMockIbft(t *testing.T, accounts []string, account string) *mockIbft {
 		state:            newState(),
 		epochSize:        DefaultEpochSize,
 		metrics:          consensus.NilMetrics(),
+		proposerSelector: &roundRobinSelector{},
+
+		roundTimeoutIncludesBlockTime: true,
 	}
 
 	initIbftMechanism(PoA, ibft)
@@ -1249,6 +1356,25 @@ func TestGetIBFTForks(t *testing.T) {
 			},
 			err: nil,
 		},
+		{
+			name: "should return a IBFTFork with the validator contract when ibftConfig has ContractPoS type",
+			ibftConfig: map[string]interface{}{
+				"type":              string(ContractPoS),
+				"validatorContract": "0x0000000000000000000000000000000000001000",
+				"validatorSelector": "0xca1e7814",
+				"maxValidatorCount": "0xa",
+			},
+			forks: []IBFTFork{
+				{
+					Type:              ContractPoS,
+					From:              common.JSONNumber{Value: 0},
+					ValidatorContract: &validatorContract,
+					ValidatorSelector: "0xca1e7814",
+					MaxValidatorCount: &common.JSONNumber{Value: 10},
+				},
+			},
+			err: nil,
+		},
 		{
 			name: "should return multiple IBFTForks when ibftConfig has types",
 			ibftConfig: map[string]interface{}{
@@ -1297,3 +1423,378 @@ func TestGetIBFTForks(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyRoundNumber(t *testing.T) {
+	forkBlock := uint64(10)
+	round := uint64(1)
+
+	tests := []struct {
+		name             string
+		roundNumberBlock *uint64
+		number           uint64
+		round            *uint64
+		err              error
+	}{
+		{
+			name:             "should succeed without round number if fork is not set",
+			roundNumberBlock: nil,
+			number:           20,
+			round:            nil,
+			err:              nil,
+		},
+		{
+			name:             "should succeed without round number before the fork",
+			roundNumberBlock: &forkBlock,
+			number:           9,
+			round:            nil,
+			err:              nil,
+		},
+		{
+			name:             "should succeed with round number after the fork",
+			roundNumberBlock: &forkBlock,
+			number:           10,
+			round:            &round,
+			err:              nil,
+		},
+		{
+			name:             "should return error if round number is missing after the fork",
+			roundNumberBlock: &forkBlock,
+			number:           10,
+			round:            nil,
+			err:              ErrMissingRoundNumber,
+		},
+		{
+			name:             "should return error if round number is present before the fork",
+			roundNumberBlock: &forkBlock,
+			number:           9,
+			round:            &round,
+			err:              ErrUnexpectedRoundNumber,
+		},
+	}
+
+	for _, testcase := range tests {
+		t.Run(testcase.name, func(t *testing.T) {
+			i := &Ibft{roundNumberBlock: testcase.roundNumberBlock}
+
+			header := &types.Header{Number: testcase.number}
+			assert.NoError(t, PutIbftExtra(header, &IstanbulExtra{
+				Validators:    []types.Address{},
+				Seal:          []byte{},
+				CommittedSeal: [][]byte{},
+				RoundNumber:   testcase.round,
+			}))
+
+			assert.Equal(t, testcase.err, i.verifyRoundNumber(header))
+		})
+	}
+}
+
+func TestVerifyProposerSlot(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D", "E")
+
+	validators := pool.ValidatorSet()
+
+	// buildHeader builds the header on top of the parent, sealed by the validator
+	buildHeader := func(parent *types.Header, proposer int, round *uint64) *types.Header {
+		h := &types.Header{
+			Number:     parent.Number + 1,
+			ParentHash: parent.Hash,
+			MixHash:    IstanbulDigest,
+		}
+		assert.NoError(t, PutIbftExtra(h, &IstanbulExtra{
+			Validators:    validators,
+			Seal:          []byte{},
+			CommittedSeal: [][]byte{},
+			RoundNumber:   round,
+		}))
+
+		h = pool.accounts[proposer].sign(h)
+		h.ComputeHash()
+
+		return h
+	}
+
+	genesis := &types.Header{Number: 0, Hash: types.StringToHash("genesis")}
+
+	// the parent is proposed by C, the proposers of the next rounds follow it
+	parent := buildHeader(genesis, 0, nil)
+	parent = buildHeader(parent, 2, nil)
+
+	strict := &Ibft{strictProposer: true, proposerSelector: &roundRobinSelector{}}
+	lenient := &Ibft{proposerSelector: &roundRobinSelector{}}
+
+	for round := uint64(0); round < 7; round++ {
+		round := round
+		expected := int(3+round) % len(validators)
+
+		for proposer := range validators {
+			header := buildHeader(parent, proposer, &round)
+
+			assert.NoError(t, lenient.verifyProposerSlot(validators, parent, header))
+
+			if proposer == expected {
+				assert.NoError(t, strict.verifyProposerSlot(validators, parent, header), "round %d", round)
+			} else {
+				assert.ErrorIs(t, strict.verifyProposerSlot(validators, parent, header), ErrUnexpectedProposer)
+			}
+		}
+	}
+
+	// the headers without the round number are of round 0
+	assert.NoError(t, strict.verifyProposerSlot(validators, parent, buildHeader(parent, 3, nil)))
+	assert.ErrorIs(t, strict.verifyProposerSlot(validators, parent, buildHeader(parent, 4, nil)), ErrUnexpectedProposer)
+
+	// the proposers on top of the genesis start from the first validator
+	round := uint64(1)
+	assert.NoError(t, strict.verifyProposerSlot(validators, genesis, buildHeader(genesis, 1, &round)))
+	assert.ErrorIs(
+		t,
+		strict.verifyProposerSlot(validators, genesis, buildHeader(genesis, 0, &round)),
+		ErrUnexpectedProposer,
+	)
+}
+
+func TestVerifyTimestamp(t *testing.T) {
+	// the block time goes down from 2 to 1 second at block 10
+	params := &chain.Params{
+		BlockTimeSchedule: []chain.BlockTimeFork{
+			{Block: 5, BlockTime: 2},
+			{Block: 10, BlockTime: 1},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		params      *chain.Params
+		number      uint64
+		secondsPast uint64
+		err         error
+	}{
+		{
+			name:        "should succeed without schedule",
+			params:      nil,
+			number:      9,
+			secondsPast: 0,
+		},
+		{
+			name:        "should succeed before the schedule",
+			params:      params,
+			number:      4,
+			secondsPast: 0,
+		},
+		{
+			name:        "should succeed with the block time before the boundary",
+			params:      params,
+			number:      9,
+			secondsPast: 2,
+		},
+		{
+			name:        "should return error with the new block time before the boundary",
+			params:      params,
+			number:      9,
+			secondsPast: 1,
+			err:         ErrInvalidTimestamp,
+		},
+		{
+			name:        "should succeed with the new block time at the boundary",
+			params:      params,
+			number:      10,
+			secondsPast: 1,
+		},
+		{
+			name:        "should return error with the same timestamp past the boundary",
+			params:      params,
+			number:      11,
+			secondsPast: 0,
+			err:         ErrInvalidTimestamp,
+		},
+	}
+
+	for _, testcase := range tests {
+		t.Run(testcase.name, func(t *testing.T) {
+			i := &Ibft{config: &consensus.Config{Params: testcase.params}}
+
+			parent := &types.Header{Number: testcase.number - 1, Timestamp: 100}
+			header := &types.Header{Number: testcase.number, Timestamp: 100 + testcase.secondsPast}
+
+			assert.ErrorIs(t, i.verifyTimestamp(parent, header), testcase.err)
+		})
+	}
+}
+
+func TestGetBlockTime(t *testing.T) {
+	i := &Ibft{
+		config: &consensus.Config{
+			Params: &chain.Params{
+				BlockTimeSchedule: []chain.BlockTimeFork{
+					{Block: 10, BlockTime: 1},
+				},
+			},
+		},
+		blockTime: 2 * time.Second,
+	}
+
+	// the block time of the node is used before the schedule
+	assert.Equal(t, 2*time.Second, i.getBlockTime(9))
+	assert.Equal(t, time.Second, i.getBlockTime(10))
+	assert.Equal(t, time.Second, i.getBlockTime(11))
+}
+
+func TestGetAllowedFutureDrift(t *testing.T) {
+	i := &Ibft{
+		config:    &consensus.Config{Params: &chain.Params{}},
+		blockTime: 2 * time.Second,
+	}
+
+	// one block time is allowed by default
+	assert.Equal(t, 2*time.Second, i.getAllowedFutureDrift(1))
+
+	// the drift of the node takes precedence over the drift of the chain
+	i.config.Params.AllowedFutureDrift = 5
+	assert.Equal(t, 5*time.Second, i.getAllowedFutureDrift(1))
+
+	i.allowedFutureDrift = 3 * time.Second
+	assert.Equal(t, 3*time.Second, i.getAllowedFutureDrift(1))
+}
+
+func TestVerifyFutureTimestamp(t *testing.T) {
+	i := &Ibft{
+		config:    &consensus.Config{},
+		blockTime: 2 * time.Second,
+	}
+
+	now := time.Unix(1000, 0)
+
+	// the timestamps up to the drift ahead of the local clock are accepted
+	for _, timestamp := range []uint64{900, 1000, 1002} {
+		assert.NoError(t, i.verifyFutureTimestamp(&types.Header{Number: 1, Timestamp: timestamp}, now))
+	}
+
+	err := i.verifyFutureTimestamp(&types.Header{Number: 1, Timestamp: 1003}, now)
+	assert.ErrorIs(t, err, blockchain.ErrFutureBlock)
+	assert.Contains(t, err.Error(), "3s ahead of the local clock")
+}
+
+// mockValidatorMetric records the last value set and the sum added per validator label
+type mockValidatorMetric struct {
+	values    map[string]float64
+	validator string
+}
+
+func (m *mockValidatorMetric) With(labelValues ...string) *mockValidatorMetric {
+	metric := *m
+
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		if labelValues[i] == "validator" {
+			metric.validator = labelValues[i+1]
+		}
+	}
+
+	return &metric
+}
+
+func (m *mockValidatorMetric) Set(value float64) {
+	m.values[m.validator] = value
+}
+
+func (m *mockValidatorMetric) Add(delta float64) {
+	m.values[m.validator] += delta
+}
+
+type mockValidatorGauge struct{ *mockValidatorMetric }
+
+func (g mockValidatorGauge) With(labelValues ...string) metrics.Gauge {
+	return mockValidatorGauge{g.mockValidatorMetric.With(labelValues...)}
+}
+
+type mockValidatorCounter struct{ *mockValidatorMetric }
+
+func (c mockValidatorCounter) With(labelValues ...string) metrics.Counter {
+	return mockValidatorCounter{c.mockValidatorMetric.With(labelValues...)}
+}
+
+func newMockValidatorMetric() *mockValidatorMetric {
+	return &mockValidatorMetric{values: map[string]float64{}}
+}
+
+func TestUpdateSealMetrics(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	var (
+		round     = uint64(2)
+		seals     = newMockValidatorMetric()
+		lastSeal  = newMockValidatorMetric()
+		proposed  = newMockValidatorMetric()
+		committed = newMockValidatorMetric()
+	)
+
+	consensusMetrics := consensus.NilMetrics()
+	consensusMetrics.CommittedRound = mockValidatorGauge{committed}
+	consensusMetrics.CommittedSeals = mockValidatorGauge{seals}
+	consensusMetrics.ProposedBlocks = mockValidatorCounter{proposed}
+	consensusMetrics.ValidatorLastSeal = mockValidatorGauge{lastSeal}
+
+	i := &Ibft{
+		logger:  hclog.NewNullLogger(),
+		metrics: consensusMetrics,
+	}
+
+	sealHeader := func(number uint64, proposer string, committers ...string) *types.Header {
+		h := &types.Header{Number: number}
+		putIbftExtraValidators(h, pool.ValidatorSet())
+
+		sealed, err := writeSeal(pool.get(proposer).signer(), h)
+		assert.NoError(t, err)
+
+		committedSeals := [][]byte{}
+
+		for _, accnt := range committers {
+			seal, err := writeCommittedSeal(pool.get(accnt).signer(), sealed, &round, IBFTProtocol)
+			assert.NoError(t, err)
+
+			committedSeals = append(committedSeals, seal)
+		}
+
+		sealed, err = writeCommittedSeals(sealed, committedSeals, &round)
+		assert.NoError(t, err)
+
+		return sealed
+	}
+
+	i.updateSealMetrics(sealHeader(1, "A", "A", "B", "C", "D"))
+	i.updateSealMetrics(sealHeader(2, "B", "A", "B", "C"))
+
+	assert.Equal(t, float64(2), committed.values[""])
+	assert.Equal(t, float64(3), seals.values[""])
+
+	// D is behind since it did not seal the last block
+	assert.Equal(t, map[string]float64{
+		pool.get("A").Address().String(): 2,
+		pool.get("B").Address().String(): 2,
+		pool.get("C").Address().String(): 2,
+		pool.get("D").Address().String(): 1,
+	}, lastSeal.values)
+
+	assert.Equal(t, map[string]float64{
+		pool.get("A").Address().String(): 1,
+		pool.get("B").Address().String(): 1,
+	}, proposed.values)
+}
+
+func TestCreateKey_NonValidator(t *testing.T) {
+	// the non-validator nodes don't read the validator key from the secrets manager
+	ibft := &Ibft{
+		logger:       hclog.NewNullLogger(),
+		nonValidator: true,
+	}
+
+	assert.NoError(t, ibft.createKey())
+	assert.Nil(t, ibft.signer)
+	assert.Equal(t, types.ZeroAddress, ibft.validatorKeyAddr)
+	assert.NotNil(t, ibft.msgQueue)
+
+	// and they never enter the consensus
+	assert.False(t, ibft.isValidSnapshot())
+}
@@ -0,0 +1,262 @@
+package ibft
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/secrets"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	errRotationPending      = errors.New("a validator key rotation is already pending")
+	errRotationRemoteSigner = errors.New("the validator key held outside of the node can't be rotated by the node")
+	errRotationNotPoA       = errors.New("the validator key can only be rotated on the PoA chains")
+	errRotationSameKey      = errors.New("the new validator key is the current one")
+	errRotationPastHeight   = errors.New("the rotation height has to be past the latest block")
+)
+
+// keyRotation is a pending rotation of the validator key. The new key signs the blocks
+// from the rotation height on, once its address was voted into the validator set
+type keyRotation struct {
+	signer Signer
+	height uint64
+}
+
+// keyRotator keeps the pending rotation of the validator key, and the signer
+// of the previous key for the transition window after the rotation
+type keyRotator struct {
+	lock sync.Mutex
+
+	pending  *keyRotation
+	previous Signer
+}
+
+// rotateValidatorKey rotates the validator key to the passed in key, or to a new generated key.
+// The node votes the new address in, and signs with the new key from the rotation height
+// once the new address is a validator. The old address is voted out after the rotation
+func (i *Ibft) rotateValidatorKey(rawKey string, height uint64) (*keyRotation, error) {
+	if _, ok := i.signer.(*localSigner); !ok {
+		return nil, errRotationRemoteSigner
+	}
+
+	header := i.blockchain.Header()
+
+	// the validator set of the PoS chains is read from the staking contract, it isn't voted
+	if !i.castsVotes(header.Number + 1) {
+		return nil, errRotationNotPoA
+	}
+
+	if height == 0 {
+		// swap the validators at the start of the next epoch
+		height = i.nextEpochBlock(header.Number) + 1
+	} else if height <= header.Number {
+		return nil, errRotationPastHeight
+	}
+
+	i.rotator.lock.Lock()
+	defer i.rotator.lock.Unlock()
+
+	if i.rotator.pending != nil {
+		return nil, errRotationPending
+	}
+
+	var (
+		encodedKey []byte
+		err        error
+	)
+
+	if rawKey == "" {
+		_, encodedKey, err = crypto.GenerateAndEncodePrivateKey()
+		if err != nil {
+			return nil, fmt.Errorf("unable to generate the new validator key, %w", err)
+		}
+	} else {
+		encodedKey = []byte(strings.TrimPrefix(rawKey, "0x"))
+	}
+
+	key, err := crypto.BytesToPrivateKey(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid validator key, %w", err)
+	}
+
+	signer := NewLocalSigner(key)
+	if signer.Address() == i.validatorKeyAddr {
+		return nil, errRotationSameKey
+	}
+
+	// the new key is kept in the secrets manager, so the rotation survives a restart
+	if err := writeSecret(i.secretsManager, secrets.NextValidatorKey, encodedKey); err != nil {
+		return nil, fmt.Errorf("unable to save the new validator key to Secrets Manager, %w", err)
+	}
+
+	rotation := &keyRotation{
+		signer: signer,
+		height: height,
+	}
+
+	i.rotator.pending = rotation
+
+	i.voteValidator(signer.Address(), true)
+
+	i.logger.Info(
+		"validator key rotation",
+		"old", i.validatorKeyAddr,
+		"new", signer.Address(),
+		"height", height,
+	)
+
+	return rotation, nil
+}
+
+// restoreKeyRotation resumes the rotation to the next validator key in the secrets manager, if any.
+// The rotation height isn't kept, the new key signs the blocks once it's voted in
+func (i *Ibft) restoreKeyRotation() error {
+	if !i.secretsManager.HasSecret(secrets.NextValidatorKey) {
+		return nil
+	}
+
+	encodedKey, err := i.secretsManager.GetSecret(secrets.NextValidatorKey)
+	if err != nil {
+		return err
+	}
+
+	key, err := crypto.BytesToPrivateKey(encodedKey)
+	if err != nil {
+		return err
+	}
+
+	// the next key is the current one past the rotation
+	signer := NewLocalSigner(key)
+	if signer.Address() == i.validatorKeyAddr {
+		return nil
+	}
+
+	i.rotator.lock.Lock()
+	defer i.rotator.lock.Unlock()
+
+	i.rotator.pending = &keyRotation{
+		signer: signer,
+	}
+
+	i.logger.Info("pending validator key rotation", "old", i.validatorKeyAddr, "new", signer.Address())
+
+	return nil
+}
+
+// applyKeyRotation switches to the new validator key before the block is signed,
+// if the rotation height is reached and the new address is in the validator set of the block
+func (i *Ibft) applyKeyRotation(number uint64, snap *Snapshot) {
+	i.rotator.lock.Lock()
+	defer i.rotator.lock.Unlock()
+
+	rotation := i.rotator.pending
+	if rotation == nil || number < rotation.height {
+		return
+	}
+
+	if !snap.Set.Includes(rotation.signer.Address()) {
+		i.logger.Debug("the new validator key isn't voted in yet", "addr", rotation.signer.Address())
+
+		return
+	}
+
+	encodedKey, err := i.secretsManager.GetSecret(secrets.NextValidatorKey)
+	if err != nil {
+		i.logger.Error("unable to read the new validator key", "err", err)
+
+		return
+	}
+
+	// the node restarts with the new key
+	if err := writeSecret(i.secretsManager, secrets.ValidatorKey, encodedKey); err != nil {
+		i.logger.Error("unable to save the new validator key", "err", err)
+
+		return
+	}
+
+	i.rotator.previous = i.signer
+	i.rotator.pending = nil
+
+	i.signer = rotation.signer
+	i.validatorKeyAddr = rotation.signer.Address()
+
+	i.voteValidator(i.rotator.previous.Address(), false)
+
+	i.logger.Info(
+		"rotated the validator key",
+		"old", i.rotator.previous.Address(),
+		"new", i.validatorKeyAddr,
+		"height", number,
+	)
+}
+
+// isOwnAddress checks if the address is the validator address of the node,
+// or its previous address in the transition window after a key rotation
+func (i *Ibft) isOwnAddress(addr types.Address) bool {
+	if addr == i.validatorKeyAddr {
+		return true
+	}
+
+	i.rotator.lock.Lock()
+	defer i.rotator.lock.Unlock()
+
+	return i.rotator.previous != nil && i.rotator.previous.Address() == addr
+}
+
+// voteValidator adds the vote for the validator to the candidates of the operator,
+// so it is cast in the next blocks proposed by the node
+func (i *Ibft) voteValidator(addr types.Address, auth bool) {
+	if i.operator == nil {
+		return
+	}
+
+	i.operator.candidatesLock.Lock()
+	defer i.operator.candidatesLock.Unlock()
+
+	i.operator.candidates = append(i.operator.candidates, &proto.Candidate{
+		Address: addr.String(),
+		Auth:    auth,
+	})
+}
+
+// castsVotes checks if the validator set is voted in the blocks at the given height
+func (i *Ibft) castsVotes(height uint64) bool {
+	for _, mechanism := range i.mechanisms {
+		if mechanism.GetType() == PoA && mechanism.IsAvailable(CandidateVoteHook, height) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nextEpochBlock returns the first epoch block that is higher than the given block
+func (i *Ibft) nextEpochBlock(number uint64) uint64 {
+	schedule := i.getEpochSchedule()
+
+	number++
+	for !schedule.isEpochBlock(number) {
+		number++
+	}
+
+	return number
+}
+
+// writeSecret sets the secret, replacing its value
+// in the secrets managers that don't overwrite the secrets
+func writeSecret(manager secrets.SecretsManager, name string, value []byte) error {
+	if err := manager.SetSecret(name, value); err == nil {
+		return nil
+	}
+
+	if err := manager.RemoveSecret(name); err != nil {
+		return err
+	}
+
+	return manager.SetSecret(name, value)
+}
@@ -0,0 +1,155 @@
+package ibft
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/secrets"
+	"github.com/0xPolygon/polygon-edge/secrets/local"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRotationMockIbft(t *testing.T) *mockIbft {
+	t.Helper()
+
+	m := newMockIbft(t, []string{"A", "B", "C", "D"}, "A")
+
+	secretsManager, err := local.SecretsManagerFactory(nil, &secrets.SecretsManagerParams{
+		Logger: hclog.NewNullLogger(),
+		Extra: map[string]interface{}{
+			secrets.Path: t.TempDir(),
+		},
+	})
+	assert.NoError(t, err)
+
+	m.secretsManager = secretsManager
+
+	return m
+}
+
+// encodeTestKey returns the hex encoded private key of the account
+func encodeTestKey(t *testing.T, account *testerAccount) string {
+	t.Helper()
+
+	key, err := crypto.MarshalPrivateKey(account.priv)
+	assert.NoError(t, err)
+
+	return hex.EncodeToString(key)
+}
+
+func TestKeyRotation(t *testing.T) {
+	t.Run("should swap the key once the new address is voted in", func(t *testing.T) {
+		m := newRotationMockIbft(t)
+		oldAddr := m.validatorKeyAddr
+
+		rotation, err := m.rotateValidatorKey("", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(2), rotation.height)
+		assert.True(t, m.secretsManager.HasSecret(secrets.NextValidatorKey))
+
+		newAddr := rotation.signer.Address()
+		snap := &Snapshot{
+			Set: m.pool.ValidatorSet(),
+		}
+
+		// the rotation height isn't reached
+		m.applyKeyRotation(1, snap)
+		assert.Equal(t, oldAddr, m.validatorKeyAddr)
+
+		// the new address isn't voted in
+		m.applyKeyRotation(2, snap)
+		assert.Equal(t, oldAddr, m.validatorKeyAddr)
+
+		snap.Set = append(snap.Set, newAddr)
+
+		m.applyKeyRotation(2, snap)
+		assert.Equal(t, newAddr, m.validatorKeyAddr)
+		assert.Equal(t, newAddr, m.signer.Address())
+
+		// the old address is still own in the transition window
+		assert.True(t, m.isOwnAddress(oldAddr))
+		assert.True(t, m.isOwnAddress(newAddr))
+		assert.False(t, m.isOwnAddress(m.pool.get("B").Address()))
+
+		// the node restarts with the new key
+		validatorKey, err := m.secretsManager.GetSecret(secrets.ValidatorKey)
+		assert.NoError(t, err)
+
+		nextValidatorKey, err := m.secretsManager.GetSecret(secrets.NextValidatorKey)
+		assert.NoError(t, err)
+		assert.Equal(t, nextValidatorKey, validatorKey)
+
+		// the new address is voted in, the old address out
+		assert.Len(t, m.operator.candidates, 2)
+		assert.Equal(t, newAddr.String(), m.operator.candidates[0].Address)
+		assert.True(t, m.operator.candidates[0].Auth)
+		assert.Equal(t, oldAddr.String(), m.operator.candidates[1].Address)
+		assert.False(t, m.operator.candidates[1].Auth)
+	})
+
+	t.Run("should rotate to the passed in key", func(t *testing.T) {
+		m := newRotationMockIbft(t)
+		rotation, err := m.rotateValidatorKey("0x"+encodeTestKey(t, m.pool.get("B")), 0)
+		assert.NoError(t, err)
+		assert.Equal(t, m.pool.get("B").Address(), rotation.signer.Address())
+
+		// the rotation defaults to the first block after the next epoch block
+		assert.Equal(t, m.nextEpochBlock(0)+1, rotation.height)
+	})
+
+	t.Run("should resume the pending rotation", func(t *testing.T) {
+		m := newRotationMockIbft(t)
+
+		rotation, err := m.rotateValidatorKey("", 10)
+		assert.NoError(t, err)
+
+		m.rotator.pending = nil
+
+		assert.NoError(t, m.restoreKeyRotation())
+		assert.NotNil(t, m.rotator.pending)
+		assert.Equal(t, rotation.signer.Address(), m.rotator.pending.signer.Address())
+	})
+
+	t.Run("should not rotate the key twice", func(t *testing.T) {
+		m := newRotationMockIbft(t)
+
+		_, err := m.rotateValidatorKey("", 10)
+		assert.NoError(t, err)
+
+		_, err = m.rotateValidatorKey("", 10)
+		assert.ErrorIs(t, err, errRotationPending)
+	})
+
+	t.Run("should not rotate to the current key", func(t *testing.T) {
+		m := newRotationMockIbft(t)
+
+		_, err := m.rotateValidatorKey(encodeTestKey(t, m.pool.get("A")), 10)
+		assert.ErrorIs(t, err, errRotationSameKey)
+	})
+
+	t.Run("should not rotate the key of the remote signer", func(t *testing.T) {
+		m := newRotationMockIbft(t)
+		m.signer = &remoteSigner{}
+
+		_, err := m.rotateValidatorKey("", 10)
+		assert.ErrorIs(t, err, errRotationRemoteSigner)
+	})
+
+	t.Run("should not rotate the key on the PoS chains", func(t *testing.T) {
+		m := newRotationMockIbft(t)
+		initIbftMechanism(PoS, m.Ibft)
+
+		_, err := m.rotateValidatorKey("", 10)
+		assert.ErrorIs(t, err, errRotationNotPoA)
+	})
+
+	t.Run("should keep the own addresses without a rotation", func(t *testing.T) {
+		m := newRotationMockIbft(t)
+
+		assert.True(t, m.isOwnAddress(m.validatorKeyAddr))
+		assert.False(t, m.isOwnAddress(types.ZeroAddress))
+	})
+}
@@ -0,0 +1,151 @@
+package ibft
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// maxHeadersWithSeals is the number of the headers served by a single request
+const maxHeadersWithSeals = 1024
+
+var errInvalidHeadersCount = fmt.Errorf("the count of the headers should be between 1 and %d", maxHeadersWithSeals)
+
+// lightClientService serves the headers along with their verified committers to the light clients.
+// A light client trusting the validator set of an epoch verifies the quorum of the committers of the headers,
+// and moves to the next validator set with the proof of the epoch block
+type lightClientService struct {
+	ibft *Ibft
+
+	proto.UnimplementedIbftLightClientServer
+}
+
+// GetHeadersWithSeals streams the headers from the given block on, up to the head
+func (l *lightClientService) GetHeadersWithSeals(
+	req *proto.HeadersWithSealsReq,
+	stream proto.IbftLightClient_GetHeadersWithSealsServer,
+) error {
+	if req.Count == 0 || req.Count > maxHeadersWithSeals {
+		return errInvalidHeadersCount
+	}
+
+	head := l.ibft.blockchain.Header().Number
+	if req.From > head {
+		return fmt.Errorf("block %d is past the head %d", req.From, head)
+	}
+
+	to := req.From + req.Count - 1
+	if to > head {
+		to = head
+	}
+
+	for number := req.From; number <= to; number++ {
+		if err := stream.Context().Err(); err != nil {
+			return err
+		}
+
+		header, err := l.headerWithSeals(number)
+		if err != nil {
+			return err
+		}
+
+		if err := stream.Send(header); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetValidatorSetProof returns the epoch block of the epoch, committed by the validators of the epoch,
+// and the validator set that follows it
+func (l *lightClientService) GetValidatorSetProof(
+	_ context.Context,
+	req *proto.ValidatorSetProofReq,
+) (*proto.ValidatorSetProof, error) {
+	number := l.ibft.getEpochSchedule().epochBlock(req.Epoch)
+	if number > l.ibft.blockchain.Header().Number {
+		return nil, fmt.Errorf("the epoch %d is not over, its epoch block is %d", req.Epoch, number)
+	}
+
+	header, err := l.headerWithSeals(number)
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err := l.ibft.getSnapshot(number)
+	if err != nil {
+		return nil, err
+	}
+
+	if snap == nil {
+		return nil, fmt.Errorf("no snapshot found for block %d", number)
+	}
+
+	return &proto.ValidatorSetProof{
+		Epoch:      req.Epoch,
+		Header:     header,
+		Validators: addressesToStrings(snap.Set),
+	}, nil
+}
+
+// headerWithSeals returns the header of the block with its proposer and its committers,
+// once the committed seals are verified against the validators of the parent
+func (l *lightClientService) headerWithSeals(number uint64) (*proto.HeaderWithSeals, error) {
+	header, ok := l.ibft.blockchain.GetHeaderByNumber(number)
+	if !ok {
+		return nil, fmt.Errorf("header %d not found", number)
+	}
+
+	resp := &proto.HeaderWithSeals{
+		Number:     header.Number,
+		Hash:       header.Hash.String(),
+		Header:     header.MarshalRLP(),
+		Committers: []string{},
+	}
+
+	// the genesis block is not sealed
+	if header.Number == 0 {
+		return resp, nil
+	}
+
+	snap, err := l.ibft.getSnapshot(header.Number - 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if snap == nil {
+		return nil, fmt.Errorf("no snapshot found for block %d", header.Number-1)
+	}
+
+	if err := l.ibft.verifyCommittedSeals(header, snap.Set); err != nil {
+		return nil, fmt.Errorf("invalid committed seals of block %d, %w", header.Number, err)
+	}
+
+	proposer, err := l.ibft.signers.proposer(header)
+	if err != nil {
+		return nil, err
+	}
+
+	committers, err := l.ibft.signers.committers(header)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Proposer = proposer.String()
+	resp.Committers = addressesToStrings(committers)
+
+	return resp, nil
+}
+
+// addressesToStrings returns the hex encoding of the addresses
+func addressesToStrings(addresses []types.Address) []string {
+	result := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		result = append(result, address.String())
+	}
+
+	return result
+}
@@ -0,0 +1,116 @@
+package ibft
+
+import (
+	"context"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// headersWithSealsStream is the mock stream collecting the sent headers
+type headersWithSealsStream struct {
+	grpc.ServerStream
+
+	headers []*proto.HeaderWithSeals
+}
+
+func (s *headersWithSealsStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *headersWithSealsStream) Send(header *proto.HeaderWithSeals) error {
+	s.headers = append(s.headers, header)
+
+	return nil
+}
+
+func TestLightClient_GetHeadersWithSeals(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	// epochs of 2 blocks, the parent committed seals are not part of the extra data
+	ibft := newRewardIbft(t, pool, 100, 2)
+	headers := buildRewardedHeaders(t, ibft, pool, ibft.blockchain.Header(),
+		rewardedBlock{committers: []string{"A", "B", "C"}},
+		rewardedBlock{committers: []string{"A", "B", "C", "D"}, round: 1},
+		rewardedBlock{committers: []string{"B", "C", "D"}},
+	)
+	assert.NoError(t, ibft.blockchain.(*blockchain.Blockchain).WriteHeaders(headers))
+	assert.NoError(t, ibft.processHeaders(headers))
+
+	service := &lightClientService{ibft: ibft}
+	stream := &headersWithSealsStream{}
+
+	assert.NoError(t, service.GetHeadersWithSeals(&proto.HeadersWithSealsReq{From: 0, Count: 10}, stream))
+	assert.Len(t, stream.headers, 4)
+
+	// the genesis is not sealed
+	assert.Equal(t, uint64(0), stream.headers[0].Number)
+	assert.Empty(t, stream.headers[0].Proposer)
+	assert.Empty(t, stream.headers[0].Committers)
+
+	for i, header := range headers {
+		resp := stream.headers[i+1]
+
+		decoded := &types.Header{}
+		assert.NoError(t, decoded.UnmarshalRLP(resp.Header))
+		assert.Equal(t, header.Hash, decoded.Hash)
+
+		assert.Equal(t, header.Number, resp.Number)
+		assert.Equal(t, header.Hash.String(), resp.Hash)
+		assert.Equal(t, pool.get("A").Address().String(), resp.Proposer)
+	}
+
+	assert.Equal(t, []string{
+		pool.get("B").Address().String(),
+		pool.get("C").Address().String(),
+		pool.get("D").Address().String(),
+	}, stream.headers[3].Committers)
+
+	// the headers are served up to the head
+	stream = &headersWithSealsStream{}
+
+	assert.NoError(t, service.GetHeadersWithSeals(&proto.HeadersWithSealsReq{From: 2, Count: 1}, stream))
+	assert.Len(t, stream.headers, 1)
+	assert.Equal(t, uint64(2), stream.headers[0].Number)
+
+	assert.ErrorIs(t, service.GetHeadersWithSeals(&proto.HeadersWithSealsReq{From: 0, Count: 0}, stream),
+		errInvalidHeadersCount)
+	assert.ErrorIs(t, service.GetHeadersWithSeals(&proto.HeadersWithSealsReq{From: 0, Count: 2000}, stream),
+		errInvalidHeadersCount)
+	assert.Error(t, service.GetHeadersWithSeals(&proto.HeadersWithSealsReq{From: 4, Count: 1}, stream))
+
+	// the epoch block 2 proves the validator set of the epoch 2
+	proof, err := service.GetValidatorSetProof(context.Background(), &proto.ValidatorSetProofReq{Epoch: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), proof.Epoch)
+	assert.Equal(t, uint64(2), proof.Header.Number)
+	assert.Len(t, proof.Header.Committers, 4)
+	assert.Equal(t, addressesToStrings(pool.ValidatorSet()), proof.Validators)
+
+	// the epoch 2 ends at the block 4
+	_, err = service.GetValidatorSetProof(context.Background(), &proto.ValidatorSetProofReq{Epoch: 2})
+	assert.Error(t, err)
+}
+
+func TestLightClient_InvalidCommittedSeals(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	ibft := newRewardIbft(t, pool, 100, 10)
+
+	// the committed seals of 2 validators are not a quorum
+	headers := buildRewardedHeaders(t, ibft, pool, ibft.blockchain.Header(),
+		rewardedBlock{committers: []string{"A", "B"}},
+	)
+	assert.NoError(t, ibft.blockchain.(*blockchain.Blockchain).WriteHeaders(headers))
+
+	service := &lightClientService{ibft: ibft}
+
+	err := service.GetHeadersWithSeals(&proto.HeadersWithSealsReq{From: 1, Count: 1}, &headersWithSealsStream{})
+	assert.ErrorIs(t, err, errNotEnoughCommittedSeals)
+}
@@ -0,0 +1,177 @@
+package ibft
+
+import (
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"golang.org/x/time/rate"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+const (
+	// maxFilteredPeers is the number of peers the message filter keeps track of
+	maxFilteredPeers = 256
+
+	// maxSeenMsgsPerPeer is the number of message hashes remembered for every peer
+	maxSeenMsgsPerPeer = 1024
+)
+
+// peerMsgState is the state the message filter keeps for a single peer
+type peerMsgState struct {
+	// seen holds the hashes of the last messages published by the peer
+	seen *lru.Cache
+
+	// limiter limits the number of messages per second accepted from the peer
+	limiter *rate.Limiter
+
+	// limited is set while the peer is over the rate limit
+	limited bool
+}
+
+// msgFilter drops the IBFT messages that were already seen from a peer,
+// as well as the messages of the peers that go over the rate limit, and rejects the messages
+// with a malformed signature. Dropped messages are neither processed nor relayed to other peers
+type msgFilter struct {
+	logger  hclog.Logger
+	metrics *consensus.Metrics
+
+	// localID is the ID of the local node, whose messages are never dropped
+	localID peer.ID
+
+	// rateLimit is the number of messages per second accepted from a single peer,
+	// 0 disables the rate limit
+	rateLimit uint64
+
+	lock  sync.Mutex
+	peers *lru.Cache
+}
+
+// newMsgFilter creates a new IBFT message filter
+func newMsgFilter(
+	logger hclog.Logger,
+	metrics *consensus.Metrics,
+	localID peer.ID,
+	rateLimit uint64,
+) (*msgFilter, error) {
+	peers, err := lru.New(maxFilteredPeers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &msgFilter{
+		logger:    logger,
+		metrics:   metrics,
+		localID:   localID,
+		rateLimit: rateLimit,
+		peers:     peers,
+	}, nil
+}
+
+// validate checks if the message published by the peer should be processed and relayed.
+// The duplicate and the rate limited messages are ignored, the messages with a malformed signature are rejected
+func (f *msgFilter) validate(from peer.ID, obj interface{}) network.ValidationResult {
+	msg, ok := obj.(*proto.MessageReq)
+	if !ok {
+		return network.ValidationReject
+	}
+
+	if from == f.localID {
+		return network.ValidationAccept
+	}
+
+	hash, err := msgHash(msg)
+	if err != nil {
+		return network.ValidationReject
+	}
+
+	if result := f.filter(from, hash); result != network.ValidationAccept {
+		return result
+	}
+
+	// the sender is recovered on a copy, the message is left as published
+	if err := validateMsg(msg.Copy()); err != nil {
+		f.logger.Debug("malformed IBFT message signature", "peer", from, "err", err)
+
+		return network.ValidationReject
+	}
+
+	return network.ValidationAccept
+}
+
+// filter checks if the message hash was already seen from the peer, or if the peer is over the rate limit
+func (f *msgFilter) filter(from peer.ID, hash types.Hash) network.ValidationResult {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	state, err := f.getPeerState(from)
+	if err != nil {
+		f.logger.Error("failed to create the message filter state", "err", err)
+
+		return network.ValidationIgnore
+	}
+
+	if state.seen.Contains(hash) {
+		f.metrics.DroppedDuplicateMsgs.Add(1)
+
+		return network.ValidationIgnore
+	}
+
+	if state.limiter != nil && !state.limiter.Allow() {
+		if !state.limited {
+			// only log once the peer goes over the limit, not for every dropped message
+			f.logger.Warn("peer went over the IBFT message rate limit", "peer", from, "limit", f.rateLimit)
+		}
+
+		state.limited = true
+
+		f.metrics.DroppedRateLimitedMsgs.Add(1)
+
+		return network.ValidationIgnore
+	}
+
+	state.limited = false
+	state.seen.Add(hash, struct{}{})
+
+	return network.ValidationAccept
+}
+
+// getPeerState returns the filter state of the peer, creating it if needed
+func (f *msgFilter) getPeerState(from peer.ID) (*peerMsgState, error) {
+	if state, ok := f.peers.Get(from); ok {
+		return state.(*peerMsgState), nil // nolint:forcetypeassert
+	}
+
+	seen, err := lru.New(maxSeenMsgsPerPeer)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &peerMsgState{
+		seen: seen,
+	}
+
+	if f.rateLimit > 0 {
+		state.limiter = rate.NewLimiter(rate.Limit(f.rateLimit), int(f.rateLimit))
+	}
+
+	f.peers.Add(from, state)
+
+	return state, nil
+}
+
+// msgHash returns the hash of the encoded message
+func msgHash(msg *proto.MessageReq) (types.Hash, error) {
+	data, err := protobuf.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	return types.BytesToHash(crypto.Keccak256(data)), nil
+}
@@ -0,0 +1,104 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/go-kit/kit/metrics"
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCounter is a metrics counter that keeps track of the count
+type mockCounter struct {
+	value float64
+}
+
+func (c *mockCounter) With(_ ...string) metrics.Counter {
+	return c
+}
+
+func (c *mockCounter) Add(delta float64) {
+	c.value += delta
+}
+
+func newTestMsgFilter(t *testing.T, rateLimit uint64) (*msgFilter, *mockCounter, *mockCounter) {
+	t.Helper()
+
+	duplicates, rateLimited := &mockCounter{}, &mockCounter{}
+
+	consensusMetrics := consensus.NilMetrics()
+	consensusMetrics.DroppedDuplicateMsgs = duplicates
+	consensusMetrics.DroppedRateLimitedMsgs = rateLimited
+
+	filter, err := newMsgFilter(hclog.NewNullLogger(), consensusMetrics, peer.ID("local"), rateLimit)
+	assert.NoError(t, err)
+
+	return filter, duplicates, rateLimited
+}
+
+func newFilterTestMsg(t *testing.T, sequence uint64) *proto.MessageReq {
+	t.Helper()
+
+	pool := newTesterAccountPool()
+	pool.add("A")
+
+	msg := &proto.MessageReq{
+		Type: proto.MessageReq_Prepare,
+		View: proto.ViewMsg(sequence, 0),
+	}
+	assert.NoError(t, signMsg(pool.get("A").signer(), msg))
+
+	return msg
+}
+
+func TestMsgFilter_Duplicates(t *testing.T) {
+	filter, duplicates, _ := newTestMsgFilter(t, 0)
+
+	msg := newFilterTestMsg(t, 1)
+
+	assert.Equal(t, network.ValidationAccept, filter.validate(peer.ID("A"), msg))
+
+	// the same message from the same peer is dropped
+	assert.Equal(t, network.ValidationIgnore, filter.validate(peer.ID("A"), msg.Copy()))
+	assert.Equal(t, float64(1), duplicates.value)
+
+	// the same message is accepted once from every peer
+	assert.Equal(t, network.ValidationAccept, filter.validate(peer.ID("B"), msg))
+
+	// a different message from the same peer is accepted
+	assert.Equal(t, network.ValidationAccept, filter.validate(peer.ID("A"), newFilterTestMsg(t, 2)))
+
+	// messages published by the local node are never dropped
+	assert.Equal(t, network.ValidationAccept, filter.validate(peer.ID("local"), msg))
+	assert.Equal(t, network.ValidationAccept, filter.validate(peer.ID("local"), msg))
+}
+
+func TestMsgFilter_RateLimit(t *testing.T) {
+	filter, _, rateLimited := newTestMsgFilter(t, 2)
+
+	assert.Equal(t, network.ValidationAccept, filter.validate(peer.ID("A"), newFilterTestMsg(t, 1)))
+	assert.Equal(t, network.ValidationAccept, filter.validate(peer.ID("A"), newFilterTestMsg(t, 2)))
+
+	// the peer went over the limit
+	assert.Equal(t, network.ValidationIgnore, filter.validate(peer.ID("A"), newFilterTestMsg(t, 3)))
+	assert.Equal(t, float64(1), rateLimited.value)
+
+	// the other peers are not affected
+	assert.Equal(t, network.ValidationAccept, filter.validate(peer.ID("B"), newFilterTestMsg(t, 3)))
+}
+
+func TestMsgFilter_InvalidMessage(t *testing.T) {
+	filter, _, _ := newTestMsgFilter(t, 0)
+
+	assert.Equal(t, network.ValidationReject, filter.validate(peer.ID("A"), &proto.View{}))
+
+	// the message with a malformed signature
+	msg := newFilterTestMsg(t, 1)
+	msg.Signature = msg.Signature[:10]
+
+	assert.Equal(t, network.ValidationReject, filter.validate(peer.ID("A"), msg))
+}
@@ -2,25 +2,38 @@ package ibft
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 
 	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/types"
 	empty "google.golang.org/protobuf/types/known/emptypb"
 )
 
+var (
+	errNotValidator = errors.New("not a validator, the consensus role of the node is none")
+	errNoVote       = errors.New("no vote for this address")
+	errRetracted    = errors.New("the vote for this address is already retracted")
+)
+
 type operator struct {
 	ibft *Ibft
 
 	candidatesLock sync.Mutex
 	candidates     []*proto.Candidate
+	retractions    []types.Address // Candidates the cast votes are withdrawn for, in the retraction order
 
 	proto.UnimplementedIbftOperatorServer
 }
 
 // Status returns the status of the IBFT client
 func (o *operator) Status(ctx context.Context, req *empty.Empty) (*proto.IbftStatusResp, error) {
+	if o.ibft.nonValidator {
+		return nil, errNotValidator
+	}
+
 	resp := &proto.IbftStatusResp{
 		Key: o.ibft.validatorKeyAddr.String(),
 	}
@@ -54,7 +67,19 @@ func (o *operator) getNextCandidate(snap *Snapshot) *proto.Candidate {
 		// Check if the candidate is not in the validator set, and wants to be removed
 		if !o.candidates[i].Auth && !snap.Set.Includes(addr) {
 			deleteFn()
+
+			continue
 		}
+
+		// Check if the candidate can't aggregate its seals past the aggregated seal fork
+		if o.candidates[i].Auth && !o.ibft.canVoteIn(snap.Number+1, addr) {
+			deleteFn()
+		}
+	}
+
+	// the retractions go first, they withdraw the votes already cast
+	if retraction := o.nextRetraction(snap); retraction != nil {
+		return retraction
 	}
 
 	var candidate *proto.Candidate
@@ -78,6 +103,28 @@ func (o *operator) getNextCandidate(snap *Snapshot) *proto.Candidate {
 	return candidate
 }
 
+// nextRetraction returns the opposite vote of the first retracted vote that is still in the snapshot,
+// the opposite vote withdraws the vote. The retractions of the votes gone from the snapshot are done.
+// The candidates lock has to be held
+func (o *operator) nextRetraction(snap *Snapshot) *proto.Candidate {
+	for len(o.retractions) > 0 {
+		addr := o.retractions[0]
+
+		for _, v := range snap.Votes {
+			if v.Validator == o.ibft.validatorKeyAddr && v.Address == addr {
+				return &proto.Candidate{
+					Address: addr.String(),
+					Auth:    !v.Authorize,
+				}
+			}
+		}
+
+		o.retractions = o.retractions[1:]
+	}
+
+	return nil
+}
+
 // GetSnapshot returns the snapshot, based on the passed in request
 func (o *operator) GetSnapshot(ctx context.Context, req *proto.SnapshotReq) (*proto.Snapshot, error) {
 	var snap *Snapshot
@@ -101,6 +148,10 @@ func (o *operator) GetSnapshot(ctx context.Context, req *proto.SnapshotReq) (*pr
 
 // Propose proposes a new candidate to be added / removed from the validator set
 func (o *operator) Propose(ctx context.Context, req *proto.Candidate) (*empty.Empty, error) {
+	if o.ibft.nonValidator {
+		return nil, errNotValidator
+	}
+
 	var addr types.Address
 	if err := addr.UnmarshalText([]byte(req.Address)); err != nil {
 		return nil, err
@@ -125,6 +176,10 @@ func (o *operator) Propose(ctx context.Context, req *proto.Candidate) (*empty.Em
 		if snap.Set.Includes(addr) {
 			return nil, fmt.Errorf("the candidate is already a validator")
 		}
+
+		if !o.ibft.canVoteIn(snap.Number+1, addr) {
+			return nil, fmt.Errorf("%w %s", errMissingBLSPublicKey, addr)
+		}
 	}
 
 	if !req.Auth {
@@ -148,6 +203,10 @@ func (o *operator) Propose(ctx context.Context, req *proto.Candidate) (*empty.Em
 
 // Candidates returns the validator candidates list
 func (o *operator) Candidates(ctx context.Context, req *empty.Empty) (*proto.CandidatesResp, error) {
+	if o.ibft.nonValidator {
+		return nil, errNotValidator
+	}
+
 	o.candidatesLock.Lock()
 	defer o.candidatesLock.Unlock()
 
@@ -159,3 +218,180 @@ func (o *operator) Candidates(ctx context.Context, req *empty.Empty) (*proto.Can
 
 	return resp, nil
 }
+
+// RetractVote withdraws the vote of the node for the candidate. The candidate not voted for yet is dropped,
+// the vote already cast is withdrawn by the opposite vote in the next blocks the node proposes
+func (o *operator) RetractVote(ctx context.Context, req *proto.RetractVoteReq) (*empty.Empty, error) {
+	if o.ibft.nonValidator {
+		return nil, errNotValidator
+	}
+
+	var addr types.Address
+	if err := addr.UnmarshalText([]byte(req.Address)); err != nil {
+		return nil, err
+	}
+
+	o.candidatesLock.Lock()
+	defer o.candidatesLock.Unlock()
+
+	snap, err := o.ibft.getLatestSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	dropped := false
+
+	for indx, c := range o.candidates {
+		if types.StringToAddress(c.Address) == addr {
+			o.candidates = append(o.candidates[:indx], o.candidates[indx+1:]...)
+			dropped = true
+
+			break
+		}
+	}
+
+	// check if the vote is already cast
+	count := snap.Count(func(v *Vote) bool {
+		return v.Address == addr && v.Validator == o.ibft.validatorKeyAddr
+	})
+	if count == 0 {
+		if !dropped {
+			return nil, errNoVote
+		}
+
+		return &empty.Empty{}, nil
+	}
+
+	for _, retraction := range o.retractions {
+		if retraction == addr {
+			return nil, errRetracted
+		}
+	}
+
+	o.retractions = append(o.retractions, addr)
+
+	return &empty.Empty{}, nil
+}
+
+// ListPendingVotes returns the votes of the latest snapshot that are not tallied yet,
+// along with the tally of their candidates
+func (o *operator) ListPendingVotes(ctx context.Context, req *empty.Empty) (*proto.PendingVotesResp, error) {
+	snap, err := o.ibft.getLatestSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	// more than a half of the validators have to vote for the candidate
+	quorum := snap.Set.Len()/2 + 1
+
+	resp := &proto.PendingVotesResp{
+		Votes: make([]*proto.PendingVote, len(snap.Votes)),
+	}
+
+	for indx, vote := range snap.Votes {
+		addr := vote.Address
+
+		tally := snap.Count(func(v *Vote) bool {
+			return v.Address == addr
+		})
+
+		needed := 0
+		if tally < quorum {
+			needed = quorum - tally
+		}
+
+		resp.Votes[indx] = &proto.PendingVote{
+			Address:  addr.String(),
+			Proposer: vote.Validator.String(),
+			Auth:     vote.Authorize,
+			Tally:    uint64(tally),
+			Needed:   uint64(needed),
+		}
+	}
+
+	return resp, nil
+}
+
+// RotateValidatorKey rotates the validator key of the node to the passed in key, or to a new generated key
+func (o *operator) RotateValidatorKey(
+	ctx context.Context,
+	req *proto.RotateValidatorKeyReq,
+) (*proto.RotateValidatorKeyResp, error) {
+	if o.ibft.nonValidator {
+		return nil, errNotValidator
+	}
+
+	oldAddress := o.ibft.validatorKeyAddr
+
+	rotation, err := o.ibft.rotateValidatorKey(req.Key, req.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.RotateValidatorKeyResp{
+		OldAddress: oldAddress.String(),
+		NewAddress: rotation.signer.Address().String(),
+		Height:     rotation.height,
+	}, nil
+}
+
+// Inspect returns the decoded IBFT extra data of the block, based on the passed in request
+func (o *operator) Inspect(ctx context.Context, req *proto.InspectReq) (*proto.InspectResp, error) {
+	header := o.ibft.blockchain.Header()
+
+	if !req.Latest {
+		var ok bool
+		if header, ok = o.ibft.blockchain.GetHeaderByNumber(req.Number); !ok {
+			return nil, fmt.Errorf("header %d not found", req.Number)
+		}
+	}
+
+	extra, err := GetIbftExtra(header)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.InspectResp{
+		Number:     header.Number,
+		Hash:       header.Hash.String(),
+		Vanity:     hex.EncodeToHex(GetVanity(header)),
+		Validators: []string{},
+		Committers: []string{},
+		Quorum:     uint64(ValidatorSet(extra.Validators).QuorumSize()),
+	}
+
+	for _, val := range extra.Validators {
+		resp.Validators = append(resp.Validators, val.String())
+	}
+
+	// the genesis block is not sealed
+	if header.Number == 0 {
+		return resp, nil
+	}
+
+	proposer, err := o.ibft.signers.proposer(header)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Proposer = proposer.String()
+
+	committers, err := o.ibft.signers.committers(header)
+	if err != nil {
+		return nil, err
+	}
+
+	// the repeated committed seals are listed once, they are counted once against the quorum
+	visited := map[types.Address]struct{}{}
+
+	for _, committer := range committers {
+		if _, ok := visited[committer]; ok {
+			continue
+		}
+
+		visited[committer] = struct{}{}
+		resp.Committers = append(resp.Committers, committer.String())
+	}
+
+	return resp, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/stretchr/testify/assert"
+	empty "google.golang.org/protobuf/types/known/emptypb"
 )
 
 func TestOperator_GetNextCandidate(t *testing.T) {
@@ -125,3 +126,283 @@ func TestOperator_Propose(t *testing.T) {
 	})
 	assert.Error(t, err)
 }
+
+func TestOperator_RetractVote(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C")
+
+	ibft := &Ibft{
+		blockchain:       blockchain.TestBlockchain(t, pool.genesis()),
+		config:           &consensus.Config{},
+		epochSize:        DefaultEpochSize,
+		validatorKeyAddr: pool.get("A").Address(),
+	}
+	assert.NoError(t, ibft.setupSnapshot())
+
+	pool.add("X", "Y")
+
+	snap, err := ibft.getLatestSnapshot()
+	assert.NoError(t, err)
+
+	// A has voted to add X, and has queued the vote to add Y
+	snap.Votes = []*Vote{
+		{Validator: pool.get("A").Address(), Address: pool.get("X").Address(), Authorize: true},
+	}
+
+	o := &operator{
+		ibft: ibft,
+		candidates: []*proto.Candidate{
+			{Address: pool.get("Y").Address().String(), Auth: true},
+		},
+	}
+
+	retract := func(name string) error {
+		_, err := o.RetractVote(context.Background(), &proto.RetractVoteReq{
+			Address: pool.get(name).Address().String(),
+		})
+
+		return err
+	}
+
+	// the vote not cast yet is dropped
+	assert.NoError(t, retract("Y"))
+	assert.Len(t, o.candidates, 0)
+	assert.Empty(t, o.retractions)
+
+	assert.ErrorIs(t, retract("Y"), errNoVote)
+
+	// the cast vote is withdrawn by the opposite vote
+	assert.NoError(t, retract("X"))
+	assert.ErrorIs(t, retract("X"), errRetracted)
+
+	assert.Equal(t, &proto.Candidate{
+		Address: pool.get("X").Address().String(),
+		Auth:    false,
+	}, o.getNextCandidate(snap))
+
+	// the retraction is done once the vote is gone from the snapshot
+	snap.Votes = nil
+
+	assert.Nil(t, o.getNextCandidate(snap))
+	assert.Empty(t, o.retractions)
+}
+
+func TestOperator_ListPendingVotes(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	ibft := &Ibft{
+		blockchain: blockchain.TestBlockchain(t, pool.genesis()),
+		config:     &consensus.Config{},
+		epochSize:  DefaultEpochSize,
+	}
+	assert.NoError(t, ibft.setupSnapshot())
+
+	pool.add("X")
+
+	snap, err := ibft.getLatestSnapshot()
+	assert.NoError(t, err)
+
+	snap.Votes = []*Vote{
+		{Validator: pool.get("A").Address(), Address: pool.get("X").Address(), Authorize: true},
+		{Validator: pool.get("B").Address(), Address: pool.get("X").Address(), Authorize: true},
+		{Validator: pool.get("A").Address(), Address: pool.get("D").Address(), Authorize: false},
+	}
+
+	resp, err := (&operator{ibft: ibft}).ListPendingVotes(context.Background(), nil)
+	assert.NoError(t, err)
+
+	// 3 of the 4 validators have to vote for the candidates
+	assert.Equal(t, []*proto.PendingVote{
+		{
+			Address:  pool.get("X").Address().String(),
+			Proposer: pool.get("A").Address().String(),
+			Auth:     true,
+			Tally:    2,
+			Needed:   1,
+		},
+		{
+			Address:  pool.get("X").Address().String(),
+			Proposer: pool.get("B").Address().String(),
+			Auth:     true,
+			Tally:    2,
+			Needed:   1,
+		},
+		{
+			Address:  pool.get("D").Address().String(),
+			Proposer: pool.get("A").Address().String(),
+			Auth:     false,
+			Tally:    1,
+			Needed:   2,
+		},
+	}, resp.Votes)
+}
+
+func TestOperator_Inspect(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	ibft := &Ibft{
+		blockchain: blockchain.TestBlockchain(t, pool.genesis()),
+		config:     &consensus.Config{},
+		epochSize:  DefaultEpochSize,
+	}
+
+	o := &operator{ibft: ibft}
+
+	resp, err := o.Inspect(context.Background(), &proto.InspectReq{Latest: true})
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(0), resp.Number)
+	assert.Equal(t, uint64(3), resp.Quorum)
+	assert.Len(t, resp.Validators, 4)
+	assert.Empty(t, resp.Proposer)
+	assert.Empty(t, resp.Committers)
+
+	// the block doesn't exist
+	_, err = o.Inspect(context.Background(), &proto.InspectReq{Number: 10})
+	assert.Error(t, err)
+}
+
+func TestOperator_Inspect_RepeatedCommittedSeal(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	ibft := newRewardIbft(t, pool, 100, DefaultEpochSize)
+
+	// the seal of B is repeated, only 2 of the 3 validators of the quorum committed
+	headers := buildRewardedHeaders(t, ibft, pool, ibft.blockchain.Header(),
+		rewardedBlock{committers: []string{"A", "B", "B"}},
+	)
+	assert.NoError(t, ibft.blockchain.(*blockchain.Blockchain).WriteHeaders(headers))
+
+	resp, err := (&operator{ibft: ibft}).Inspect(context.Background(), &proto.InspectReq{Number: 1})
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(3), resp.Quorum)
+	assert.Equal(t, []string{pool.get("A").Address().String(), pool.get("B").Address().String()}, resp.Committers)
+}
+
+// extraDecodingVerifier is the mock verifier decoding the extra data of the rejected blocks as IBFT does
+type extraDecodingVerifier struct {
+	blockchain.MockVerifier
+
+	ibft *Ibft
+}
+
+func (v *extraDecodingVerifier) DecodeExtra(header *types.Header) (interface{}, error) {
+	return v.ibft.DecodeExtra(header)
+}
+
+func TestOperator_BadBlocks(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	ibft := &Ibft{
+		blockchain: blockchain.TestBlockchain(t, pool.genesis()),
+		config:     &consensus.Config{},
+		epochSize:  DefaultEpochSize,
+	}
+
+	// nolint:forcetypeassert
+	chain := ibft.blockchain.(*blockchain.Blockchain)
+	chain.SetConsensus(&extraDecodingVerifier{ibft: ibft})
+
+	o := &operator{ibft: ibft}
+
+	resp, err := o.BadBlocks(context.Background(), &empty.Empty{})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Blocks)
+
+	// the block sealed by A with the committed seals of A, B and C doesn't match its transactions root
+	validators := pool.ValidatorSet()
+	round := uint64(0)
+
+	header := &types.Header{
+		ParentHash: chain.Header().Hash,
+		Number:     1,
+		Difficulty: 1,
+		MixHash:    IstanbulDigest,
+		Sha3Uncles: types.EmptyUncleHash,
+		TxRoot:     types.StringToHash("1"),
+	}
+	putIbftExtraValidators(header, validators)
+
+	header, err = writeSeal(pool.get("A").signer(), header)
+	assert.NoError(t, err)
+
+	seals := [][]byte{}
+
+	for _, accnt := range pool.accounts[:validators.QuorumSize()] {
+		seal, err := writeCommittedSeal(accnt.signer(), header, &round, IBFTProtocol)
+		assert.NoError(t, err)
+
+		seals = append(seals, seal)
+	}
+
+	header, err = writeCommittedSeals(header, seals, &round)
+	assert.NoError(t, err)
+
+	block := &types.Block{Header: header.ComputeHash()}
+	assert.Error(t, chain.WriteBlock(block, blockchain.SourceSealer))
+
+	resp, err = o.BadBlocks(context.Background(), &empty.Empty{})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Blocks, 1)
+
+	bad := resp.Blocks[0]
+	assert.Equal(t, uint64(1), bad.Number)
+	assert.Equal(t, block.Hash().String(), bad.Hash)
+	assert.Contains(t, bad.Reason, "transaction root hash mismatch")
+	assert.Equal(t, block.MarshalRLP(), bad.Rlp)
+
+	assert.Equal(t, pool.get("A").Address().String(), bad.Extra.Proposer)
+	assert.Len(t, bad.Extra.Validators, 4)
+	assert.Len(t, bad.Extra.Committers, 3)
+	assert.Equal(t, uint64(3), bad.Extra.Quorum)
+}
+
+func TestDecodeExtra_RecoveryError(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	// the header is not sealed, the signers can't be recovered
+	header := &types.Header{Number: 1}
+	putIbftExtraValidators(header, pool.ValidatorSet())
+
+	decoded, err := decodeExtra(header, IBFTProtocol)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []types.Address(pool.ValidatorSet()), decoded.Validators)
+	assert.Nil(t, decoded.Proposer)
+	assert.NotEmpty(t, decoded.RecoveryError)
+
+	// the extra data is not IBFT
+	_, err = decodeExtra(&types.Header{Number: 1, ExtraData: []byte{0x1}}, IBFTProtocol)
+	assert.Error(t, err)
+}
+
+func TestOperator_NonValidator(t *testing.T) {
+	o := &operator{
+		ibft: &Ibft{
+			nonValidator: true,
+		},
+	}
+
+	_, err := o.Status(context.Background(), nil)
+	assert.ErrorIs(t, err, errNotValidator)
+
+	_, err = o.Propose(context.Background(), &proto.Candidate{
+		Address: types.StringToAddress("1").String(),
+		Auth:    true,
+	})
+	assert.ErrorIs(t, err, errNotValidator)
+
+	_, err = o.Candidates(context.Background(), nil)
+	assert.ErrorIs(t, err, errNotValidator)
+
+	_, err = o.RetractVote(context.Background(), &proto.RetractVoteReq{
+		Address: types.StringToAddress("1").String(),
+	})
+	assert.ErrorIs(t, err, errNotValidator)
+}
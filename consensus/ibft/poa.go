@@ -110,31 +110,37 @@ func (poa *PoAMechanism) processHeadersHook(hookParam interface{}) error {
 	}
 
 	number := params.header.Number
-	if number%poa.ibft.epochSize == 0 {
+	epochs := poa.ibft.getEpochSchedule()
+	if epochs.isEpochBlock(number) {
 		// during a checkpoint block, we reset the votes
 		// and there cannot be any proposals
 		params.snap.Votes = nil
 		params.saveSnap(params.header)
 
 		// remove in-memory snapshots from two epochs before this one
-		epoch := int(number/poa.ibft.epochSize) - 2
-		if epoch > 0 {
-			purgeBlock := uint64(epoch) * poa.ibft.epochSize
+		purgeBlock := epochs.previousEpochBlock(epochs.previousEpochBlock(number))
+		if purgeBlock > 0 {
 			poa.ibft.store.deleteLower(purgeBlock)
 		}
 
 		return nil
 	}
 
+	// the candidate is in the miner field, or in the extra data of the QBFT blocks
+	candidate, nonce, err := getHeaderVote(params.header)
+	if err != nil {
+		return err
+	}
+
 	// if we have a miner address, this might be a vote
-	if params.header.Miner == types.ZeroAddress {
+	if candidate == types.ZeroAddress {
 		return nil
 	}
 
 	// the nonce selects the action
 	var authorize bool
 
-	switch params.header.Nonce {
+	switch nonce {
 	case nonceAuthVote:
 		authorize = true
 	case nonceDropVote:
@@ -143,21 +149,34 @@ func (poa *PoAMechanism) processHeadersHook(hookParam interface{}) error {
 		return fmt.Errorf("incorrect vote nonce")
 	}
 
+	// the opposite vote of the proposer withdraws its vote for the candidate
+	retracted := params.snap.Count(func(v *Vote) bool {
+		return v.Validator == params.proposer && v.Address == candidate && v.Authorize != authorize
+	})
+
+	if retracted > 0 {
+		params.snap.RemoveVotes(func(v *Vote) bool {
+			return v.Validator == params.proposer && v.Address == candidate
+		})
+
+		return nil
+	}
+
 	// validate the vote
 	if authorize {
 		// we can only authorize if they are not on the validators list
-		if params.snap.Set.Includes(params.header.Miner) {
+		if params.snap.Set.Includes(candidate) {
 			return nil
 		}
 	} else {
 		// we can only remove if they are part of the validators list
-		if !params.snap.Set.Includes(params.header.Miner) {
+		if !params.snap.Set.Includes(candidate) {
 			return nil
 		}
 	}
 
 	voteCount := params.snap.Count(func(v *Vote) bool {
-		return v.Validator == params.proposer && v.Address == params.header.Miner
+		return v.Validator == params.proposer && v.Address == candidate
 	})
 
 	if voteCount > 1 {
@@ -169,34 +188,34 @@ func (poa *PoAMechanism) processHeadersHook(hookParam interface{}) error {
 		// cast the new vote since there is no one yet
 		params.snap.Votes = append(params.snap.Votes, &Vote{
 			Validator: params.proposer,
-			Address:   params.header.Miner,
+			Address:   candidate,
 			Authorize: authorize,
 		})
 	}
 
 	// check the tally for the proposed validator
 	tally := params.snap.Count(func(v *Vote) bool {
-		return v.Address == params.header.Miner
+		return v.Address == candidate
 	})
 
 	// If more than a half of all validators voted
 	if tally > params.snap.Set.Len()/2 {
 		if authorize {
 			// add the candidate to the validators list
-			params.snap.Set.Add(params.header.Miner)
+			params.snap.Set.Add(candidate)
 		} else {
 			// remove the candidate from the validators list
-			params.snap.Set.Del(params.header.Miner)
+			params.snap.Set.Del(candidate)
 
 			// remove any votes casted by the removed validator
 			params.snap.RemoveVotes(func(v *Vote) bool {
-				return v.Validator == params.header.Miner
+				return v.Validator == candidate
 			})
 		}
 
 		// remove all the votes that promoted this validator
 		params.snap.RemoveVotes(func(v *Vote) bool {
-			return v.Address == params.header.Miner
+			return v.Address == candidate
 		})
 	}
 
@@ -232,15 +251,13 @@ func (poa *PoAMechanism) candidateVoteHook(hookParams interface{}) error {
 }
 
 // calculateProposerHook calculates the next proposer based on the last
-func (poa *PoAMechanism) calculateProposerHook(lastProposerParam interface{}) error {
-	lastProposer, ok := lastProposerParam.(types.Address)
+func (poa *PoAMechanism) calculateProposerHook(hookParam interface{}) error {
+	params, ok := hookParam.(*calculateProposerHookParams)
 	if !ok {
 		return ErrInvalidHookParam
 	}
 
-	poa.ibft.state.CalcProposer(lastProposer)
-
-	return nil
+	return poa.ibft.calcProposer(params.parent, params.lastProposer)
 }
 
 // initializeHookMap registers the hooks that the PoA mechanism
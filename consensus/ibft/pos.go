@@ -9,6 +9,8 @@ import (
 	"github.com/0xPolygon/polygon-edge/types"
 )
 
+var errInvalidSwitchValidators = errors.New("the validators of the switch block do not match the staking contract")
+
 // PoSMechanism defines specific hooks for the Proof of Stake IBFT mechanism
 type PoSMechanism struct {
 	BaseConsensusMechanism
@@ -48,6 +50,9 @@ func (pos *PoSMechanism) IsAvailable(hookType HookType, height uint64) bool {
 	case InsertBlockHook:
 		// update validators when the one before the beginning or the end of epoch
 		return height+1 == pos.From || pos.IsInRange(height) && pos.ibft.IsLastOfEpoch(height)
+	case EpochValidatorsHook, VerifyEpochValidatorsHook, ProcessHeadersHook:
+		// the first block of the fork carries the validator set of the staking contract
+		return pos.isSwitchBlock(height)
 	default:
 		return false
 	}
@@ -64,9 +69,10 @@ func (pos *PoSMechanism) initializeParams(params *IBFTFork) error {
 			return errors.New(`"deployment" must be specified in PoS fork`)
 		}
 
-		if params.Deployment.Value > pos.From {
+		// the validator set of the first block of the fork is read from the state of its parent
+		if params.Deployment.Value >= pos.From {
 			return fmt.Errorf(
-				`"deployment" must be less than or equal to "from": deployment=%d, from=%d`,
+				`"deployment" must be less than "from": deployment=%d, from=%d`,
 				params.Deployment.Value,
 				pos.From,
 			)
@@ -90,16 +96,19 @@ func (pos *PoSMechanism) initializeParams(params *IBFTFork) error {
 	return nil
 }
 
+// isSwitchBlock checks if the block is the first block of the fork switching a running chain to PoS
+func (pos *PoSMechanism) isSwitchBlock(height uint64) bool {
+	return pos.From != 0 && height == pos.From
+}
+
 // calculateProposerHook calculates the next proposer based on the last
-func (pos *PoSMechanism) calculateProposerHook(lastProposerParam interface{}) error {
-	lastProposer, ok := lastProposerParam.(types.Address)
+func (pos *PoSMechanism) calculateProposerHook(hookParam interface{}) error {
+	params, ok := hookParam.(*calculateProposerHookParams)
 	if !ok {
 		return ErrInvalidHookParam
 	}
 
-	pos.ibft.state.CalcProposer(lastProposer)
-
-	return nil
+	return pos.ibft.calcProposer(params.parent, params.lastProposer)
 }
 
 // acceptStateLogHook logs the current snapshot
@@ -145,6 +154,65 @@ func (pos *PoSMechanism) verifyBlockHook(blockParam interface{}) error {
 	return nil
 }
 
+// epochValidatorsHook writes the validator set of the staking contract into the switch block
+func (pos *PoSMechanism) epochValidatorsHook(hookParam interface{}) error {
+	params, ok := hookParam.(*epochValidatorsHookParams)
+	if !ok {
+		return ErrInvalidHookParam
+	}
+
+	validators, err := pos.getNextValidators(params.parent)
+	if err != nil {
+		return err
+	}
+
+	putIbftExtraValidators(params.header, validators)
+
+	return nil
+}
+
+// verifyEpochValidatorsHook checks that the validator set of the switch block is the one of the staking contract,
+// so the nodes syncing the chain verify the transition from the state they executed
+func (pos *PoSMechanism) verifyEpochValidatorsHook(hookParam interface{}) error {
+	params, ok := hookParam.(*epochValidatorsHookParams)
+	if !ok {
+		return ErrInvalidHookParam
+	}
+
+	extra, err := GetIbftExtra(params.header)
+	if err != nil {
+		return err
+	}
+
+	validators, err := pos.getNextValidators(params.parent)
+	if err != nil {
+		return err
+	}
+
+	if !validators.Equal((*ValidatorSet)(&extra.Validators)) {
+		return errInvalidSwitchValidators
+	}
+
+	return nil
+}
+
+// processHeadersHook sets the validator set of the switch block into the snapshot
+func (pos *PoSMechanism) processHeadersHook(hookParam interface{}) error {
+	params, ok := hookParam.(*processHeadersHookParams)
+	if !ok {
+		return ErrInvalidHookParam
+	}
+
+	validators, err := unpackValidatorsFromIbftExtra(params.header)
+	if err != nil {
+		return err
+	}
+
+	params.snap.Set = validators
+
+	return nil
+}
+
 // preStateCommitHookParams are the params passed into the preStateCommitHook
 type preStateCommitHookParams struct {
 	header *types.Header
@@ -194,6 +262,15 @@ func (pos *PoSMechanism) initializeHookMap() {
 
 	// Register the CalculateProposerHook
 	pos.hookMap[CalculateProposerHook] = pos.calculateProposerHook
+
+	// Register the EpochValidatorsHook
+	pos.hookMap[EpochValidatorsHook] = pos.epochValidatorsHook
+
+	// Register the VerifyEpochValidatorsHook
+	pos.hookMap[VerifyEpochValidatorsHook] = pos.verifyEpochValidatorsHook
+
+	// Register the ProcessHeadersHook
+	pos.hookMap[ProcessHeadersHook] = pos.processHeadersHook
 }
 
 // ShouldWriteTransactions indicates if transactions should be written to a block
@@ -203,14 +280,24 @@ func (pos *PoSMechanism) ShouldWriteTransactions(blockNumber uint64) bool {
 }
 
 // getNextValidators is a helper function for fetching the validator set
-// from the Staking SC
+// from the Staking SC at the state of the header
 func (pos *PoSMechanism) getNextValidators(header *types.Header) (ValidatorSet, error) {
 	transition, err := pos.ibft.executor.BeginTxn(header.StateRoot, header, types.ZeroAddress)
 	if err != nil {
 		return nil, err
 	}
 
-	return staking.QueryValidators(transition, pos.ibft.validatorKeyAddr)
+	// the call is made from the zero address, so all the nodes read the same set
+	validators, err := staking.QueryValidators(transition, types.ZeroAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the validator set from the staking contract: %w", err)
+	}
+
+	if len(validators) == 0 {
+		return nil, errEmptyValidatorSet
+	}
+
+	return validators, nil
 }
 
 // updateSnapshotValidators updates validators in snapshot at given height
@@ -4,6 +4,15 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/contracts/staking"
+	"github.com/0xPolygon/polygon-edge/helper/common"
+	stakingHelper "github.com/0xPolygon/polygon-edge/helper/staking"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -143,3 +152,124 @@ func TestIsLastOfEpoch(t *testing.T) {
 		})
 	}
 }
+
+// newSwitchPoSIbft returns an IBFT switching from PoA to PoS at the given height, with the staking contract
+// of the validators deployed at the genesis, and the genesis header
+func newSwitchPoSIbft(t *testing.T, from uint64, validators []types.Address) (*Ibft, *types.Header) {
+	t.Helper()
+
+	executor := state.NewExecutor(&chain.Params{
+		Forks:   chain.AllForksEnabled,
+		ChainID: 100,
+	}, itrie.NewState(itrie.NewMemoryStorage()), hclog.NewNullLogger())
+	executor.SetRuntime(evm.NewEVM())
+	executor.GetHash = func(*types.Header) state.GetHashByNumber {
+		return func(uint64) types.Hash {
+			return types.ZeroHash
+		}
+	}
+
+	stakingAccount, err := stakingHelper.PredeployStakingSC(validators, stakingHelper.PredeployParams{
+		MinValidatorCount: stakingHelper.MinValidatorCount,
+		MaxValidatorCount: stakingHelper.MaxValidatorCount,
+	})
+	assert.NoError(t, err)
+
+	genesis := &types.Header{
+		GasLimit: 5000000,
+		StateRoot: executor.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+			staking.AddrStakingContract: stakingAccount,
+		}),
+	}
+
+	ibft := &Ibft{
+		logger:    hclog.NewNullLogger(),
+		executor:  executor,
+		epochSize: TestEpochSize,
+	}
+
+	poa, err := PoAFactory(ibft, &IBFTFork{
+		Type: PoA,
+		From: common.JSONNumber{Value: 0},
+		To:   &common.JSONNumber{Value: from - 1},
+	})
+	assert.NoError(t, err)
+
+	pos, err := PoSFactory(ibft, &IBFTFork{
+		Type:       PoS,
+		Deployment: &common.JSONNumber{Value: 0},
+		From:       common.JSONNumber{Value: from},
+	})
+	assert.NoError(t, err)
+
+	ibft.mechanisms = []ConsensusMechanism{poa, pos}
+
+	return ibft, genesis
+}
+
+func TestPoSFactory_Deployment(t *testing.T) {
+	// the staking contract has to be deployed before the switch
+	_, err := PoSFactory(&Ibft{}, &IBFTFork{
+		Type:       PoS,
+		Deployment: &common.JSONNumber{Value: 10},
+		From:       common.JSONNumber{Value: 10},
+	})
+	assert.Error(t, err)
+
+	_, err = PoSFactory(&Ibft{}, &IBFTFork{
+		Type: PoS,
+		From: common.JSONNumber{Value: 10},
+	})
+	assert.Error(t, err)
+
+	_, err = PoSFactory(&Ibft{}, &IBFTFork{
+		Type:       PoS,
+		Deployment: &common.JSONNumber{Value: 9},
+		From:       common.JSONNumber{Value: 10},
+	})
+	assert.NoError(t, err)
+}
+
+func TestPoS_SwitchBlockValidators(t *testing.T) {
+	ibft, genesis := newSwitchPoSIbft(t, 1, []types.Address{addr1, addr2})
+
+	// only the switch block carries the validator set of the contract, the PoA votes stop with the fork
+	for _, mechanism := range ibft.mechanisms {
+		assert.False(t, mechanism.IsAvailable(VerifyEpochValidatorsHook, TestEpochSize))
+	}
+
+	assert.True(t, ibft.mechanisms[1].IsAvailable(VerifyEpochValidatorsHook, 1))
+	assert.False(t, ibft.mechanisms[0].IsAvailable(VerifyHeadersHook, 1))
+	assert.False(t, ibft.mechanisms[0].IsAvailable(ProcessHeadersHook, 1))
+
+	header := &types.Header{
+		Number: 1,
+	}
+	putIbftExtraValidators(header, []types.Address{addr3})
+
+	params := &epochValidatorsHookParams{
+		parent: genesis,
+		header: header,
+	}
+
+	// the proposer writes the validator set of the staking contract into the switch block
+	assert.NoError(t, ibft.runHook(EpochValidatorsHook, header.Number, params))
+
+	validators, err := unpackValidatorsFromIbftExtra(header)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Address{addr1, addr2}, validators)
+
+	// the syncing nodes accept it, and take it into the snapshot
+	assert.NoError(t, ibft.runHook(VerifyEpochValidatorsHook, header.Number, params))
+
+	snap := &Snapshot{Set: ValidatorSet{addr3}}
+	assert.NoError(t, ibft.runHook(ProcessHeadersHook, header.Number, &processHeadersHookParams{
+		header: header,
+		snap:   snap,
+	}))
+	assert.Equal(t, ValidatorSet{addr1, addr2}, snap.Set)
+
+	// the syncing nodes reject a switch block with another validator set
+	putIbftExtraValidators(header, []types.Address{addr1, addr3})
+	assert.ErrorIs(t, ibft.runHook(VerifyEpochValidatorsHook, header.Number, params), errInvalidSwitchValidators)
+}
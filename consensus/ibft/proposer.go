@@ -0,0 +1,215 @@
+package ibft
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// Define the type of the proposer selection
+
+type ProposerSelectorType string
+
+const (
+	// RoundRobin defines the proposer selection where the validators
+	// take turns proposing blocks, in the order of the validator set
+	RoundRobin ProposerSelectorType = "round-robin"
+
+	// StakeWeighted defines the proposer selection where the validators
+	// are picked in proportion to their stake on the Staking SC
+	StakeWeighted ProposerSelectorType = "stake-weighted"
+)
+
+// proposerSelectorTypes is the map used for easy string -> ProposerSelectorType lookups
+var proposerSelectorTypes = map[string]ProposerSelectorType{
+	"round-robin":    RoundRobin,
+	"stake-weighted": StakeWeighted,
+}
+
+// String is a helper method for casting a ProposerSelectorType to a string representation
+func (t ProposerSelectorType) String() string {
+	return string(t)
+}
+
+// ParseProposerSelectorType converts a proposer selection string representation to a ProposerSelectorType
+func ParseProposerSelectorType(selector string) (ProposerSelectorType, error) {
+	// Check if the cast is possible
+	castType, ok := proposerSelectorTypes[selector]
+	if !ok {
+		return castType, fmt.Errorf("invalid IBFT proposer selector type %s", selector)
+	}
+
+	return castType, nil
+}
+
+// ProposerVerification defines how the proposers of the synced headers are verified
+type ProposerVerification string
+
+const (
+	// LenientProposerVerification accepts the headers proposed by any validator of the set,
+	// as the chains may have headers proposed out of turn in their history
+	LenientProposerVerification ProposerVerification = "lenient"
+
+	// StrictProposerVerification only accepts the headers proposed by the proposer
+	// selected for the round of the header
+	StrictProposerVerification ProposerVerification = "strict"
+)
+
+// proposerVerificationTypes is the map used for easy string -> ProposerVerification lookups
+var proposerVerificationTypes = map[string]ProposerVerification{
+	"lenient": LenientProposerVerification,
+	"strict":  StrictProposerVerification,
+}
+
+// String is a helper method for casting a ProposerVerification to a string representation
+func (v ProposerVerification) String() string {
+	return string(v)
+}
+
+// ParseProposerVerification converts a proposer verification string representation to a ProposerVerification
+func ParseProposerVerification(verification string) (ProposerVerification, error) {
+	castType, ok := proposerVerificationTypes[verification]
+	if !ok {
+		return castType, fmt.Errorf("invalid IBFT proposer verification %s", verification)
+	}
+
+	return castType, nil
+}
+
+var (
+	errEmptyValidatorSet = errors.New("empty validator set")
+	errInvalidStakes     = errors.New("number of stakes doesn't match the number of validators")
+
+	errStakeWeightedWithoutPoS = errors.New("the stake-weighted proposer selector requires the PoS mechanism")
+)
+
+// ProposerSelector selects the proposer of a round.
+// The selection needs to be deterministic, so all the nodes agree on the proposer
+type ProposerSelector interface {
+	// SelectProposer returns the proposer of the given round of the block on top of the parent.
+	// The last proposer is the proposer of the parent block, or the zero address for the genesis parent
+	SelectProposer(
+		validators ValidatorSet,
+		parent *types.Header,
+		lastProposer types.Address,
+		round uint64,
+	) (types.Address, error)
+}
+
+// roundRobinSelector picks the validator after the last proposer, moving on by one for every round
+type roundRobinSelector struct{}
+
+// SelectProposer implements the ProposerSelector interface method
+func (r *roundRobinSelector) SelectProposer(
+	validators ValidatorSet,
+	_ *types.Header,
+	lastProposer types.Address,
+	round uint64,
+) (types.Address, error) {
+	if validators.Len() == 0 {
+		return types.ZeroAddress, errEmptyValidatorSet
+	}
+
+	return validators.CalcProposer(round, lastProposer), nil
+}
+
+// stakesQueryFn returns the stakes of the validators, in the order of the validator set,
+// at the state of the parent block
+type stakesQueryFn func(parent *types.Header, validators ValidatorSet) ([]*big.Int, error)
+
+// stakeWeightedSelector picks the proposer with a probability proportional to its stake.
+// The pick is seeded by the parent hash and the round, so every round gets a new draw
+type stakeWeightedSelector struct {
+	getStakes stakesQueryFn
+
+	// the stakes of the last parent, the rounds of the same height don't query them again
+	cacheLock  sync.Mutex
+	cachedHash types.Hash
+	cached     []*big.Int
+}
+
+// stakesAt returns the stakes of the validators at the given parent, from the cache
+// if they were already queried for the same parent
+func (s *stakeWeightedSelector) stakesAt(parent *types.Header, validators ValidatorSet) ([]*big.Int, error) {
+	s.cacheLock.Lock()
+	defer s.cacheLock.Unlock()
+
+	if s.cached != nil && s.cachedHash == parent.Hash {
+		return s.cached, nil
+	}
+
+	stakes, err := s.getStakes(parent, validators)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cachedHash, s.cached = parent.Hash, stakes
+
+	return stakes, nil
+}
+
+// SelectProposer implements the ProposerSelector interface method
+func (s *stakeWeightedSelector) SelectProposer(
+	validators ValidatorSet,
+	parent *types.Header,
+	lastProposer types.Address,
+	round uint64,
+) (types.Address, error) {
+	if validators.Len() == 0 {
+		return types.ZeroAddress, errEmptyValidatorSet
+	}
+
+	stakes, err := s.stakesAt(parent, validators)
+	if err != nil {
+		return types.ZeroAddress, err
+	}
+
+	if len(stakes) != validators.Len() {
+		return types.ZeroAddress, errInvalidStakes
+	}
+
+	totalStake := big.NewInt(0)
+
+	for _, stake := range stakes {
+		if stake != nil && stake.Sign() > 0 {
+			totalStake.Add(totalStake, stake)
+		}
+	}
+
+	// nobody has any stake, rotate through the validators instead
+	if totalStake.Sign() == 0 {
+		return validators.CalcProposer(round, lastProposer), nil
+	}
+
+	// draw a point in [0, totalStake) and find the validator whose stake range covers it
+	point := new(big.Int).Mod(proposerSeed(parent.Hash, round), totalStake)
+	cumulative := big.NewInt(0)
+
+	for indx, stake := range stakes {
+		if stake == nil || stake.Sign() <= 0 {
+			continue
+		}
+
+		cumulative.Add(cumulative, stake)
+
+		if point.Cmp(cumulative) < 0 {
+			return validators[indx], nil
+		}
+	}
+
+	// unreachable, the point is always lower than the total stake
+	return types.ZeroAddress, errInvalidStakes
+}
+
+// proposerSeed returns the seed used for the stake weighted pick, keccak(parentHash . round)
+func proposerSeed(parentHash types.Hash, round uint64) *big.Int {
+	roundBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(roundBytes, round)
+
+	return new(big.Int).SetBytes(crypto.Keccak256(parentHash.Bytes(), roundBytes))
+}
@@ -0,0 +1,215 @@
+package ibft
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStakesQuery(stakes ...int64) stakesQueryFn {
+	return func(_ *types.Header, _ ValidatorSet) ([]*big.Int, error) {
+		res := make([]*big.Int, len(stakes))
+		for indx, stake := range stakes {
+			res[indx] = big.NewInt(stake)
+		}
+
+		return res, nil
+	}
+}
+
+func TestParseProposerSelectorType(t *testing.T) {
+	selectorType, err := ParseProposerSelectorType("stake-weighted")
+	assert.NoError(t, err)
+	assert.Equal(t, StakeWeighted, selectorType)
+
+	_, err = ParseProposerSelectorType("random")
+	assert.Error(t, err)
+}
+
+func TestSetupProposerSelector(t *testing.T) {
+	setup := func(mechanismType MechanismType, selector string) (*Ibft, error) {
+		i := &Ibft{
+			config: &consensus.Config{
+				Config: map[string]interface{}{
+					"proposerSelector": selector,
+				},
+			},
+		}
+		initIbftMechanism(mechanismType, i)
+
+		return i, i.setupProposerSelector()
+	}
+
+	i, err := setup(PoS, "stake-weighted")
+	assert.NoError(t, err)
+	assert.IsType(t, &stakeWeightedSelector{}, i.proposerSelector)
+
+	i, err = setup(PoA, "round-robin")
+	assert.NoError(t, err)
+	assert.IsType(t, &roundRobinSelector{}, i.proposerSelector)
+
+	// the stakes are only on the Staking SC of the PoS chains
+	_, err = setup(PoA, "stake-weighted")
+	assert.ErrorIs(t, err, errStakeWeightedWithoutPoS)
+}
+
+func TestParseProposerVerification(t *testing.T) {
+	verification, err := ParseProposerVerification("strict")
+	assert.NoError(t, err)
+	assert.Equal(t, StrictProposerVerification, verification)
+
+	_, err = ParseProposerVerification("none")
+	assert.Error(t, err)
+}
+
+func TestRoundRobinSelector_RoundChanges(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C")
+
+	validators := pool.ValidatorSet()
+	selector := &roundRobinSelector{}
+	parent := &types.Header{}
+
+	// every round change moves on to the next validator after the last proposer
+	for round, expected := range []types.Address{validators[1], validators[2], validators[0], validators[1]} {
+		proposer, err := selector.SelectProposer(validators, parent, validators[0], uint64(round))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, proposer)
+	}
+
+	_, err := selector.SelectProposer(ValidatorSet{}, parent, types.ZeroAddress, 0)
+	assert.ErrorIs(t, err, errEmptyValidatorSet)
+}
+
+func TestStakeWeightedSelector_Deterministic(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	validators := pool.ValidatorSet()
+	parent := &types.Header{Hash: types.StringToHash("1")}
+
+	selector1 := &stakeWeightedSelector{getStakes: newStakesQuery(10, 20, 30, 40)}
+	selector2 := &stakeWeightedSelector{getStakes: newStakesQuery(10, 20, 30, 40)}
+
+	proposers := map[types.Address]struct{}{}
+
+	for round := uint64(0); round < 20; round++ {
+		proposer1, err := selector1.SelectProposer(validators, parent, validators[0], round)
+		assert.NoError(t, err)
+
+		proposer2, err := selector2.SelectProposer(validators, parent, validators[0], round)
+		assert.NoError(t, err)
+
+		// all the nodes agree on the proposer of the round
+		assert.Equal(t, proposer1, proposer2)
+
+		proposers[proposer1] = struct{}{}
+	}
+
+	// the proposer is drawn again on round changes
+	assert.Greater(t, len(proposers), 1)
+}
+
+func TestStakeWeightedSelector_StakesCache(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C")
+
+	validators := pool.ValidatorSet()
+	queries := 0
+
+	selector := &stakeWeightedSelector{
+		getStakes: func(parent *types.Header, validators ValidatorSet) ([]*big.Int, error) {
+			queries++
+
+			return newStakesQuery(1, 2, 3)(parent, validators)
+		},
+	}
+
+	// the rounds of the same height query the stakes once
+	parent := &types.Header{Hash: types.StringToHash("1")}
+
+	for round := uint64(0); round < 5; round++ {
+		_, err := selector.SelectProposer(validators, parent, validators[0], round)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, queries)
+
+	// the cache is reset at a new parent
+	parent = &types.Header{Hash: types.StringToHash("2")}
+
+	for round := uint64(0); round < 5; round++ {
+		_, err := selector.SelectProposer(validators, parent, validators[0], round)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, queries)
+
+	// the failed queries are not cached
+	selector.getStakes = func(_ *types.Header, _ ValidatorSet) ([]*big.Int, error) {
+		queries++
+
+		return nil, errors.New("query failed")
+	}
+
+	parent = &types.Header{Hash: types.StringToHash("3")}
+
+	for round := uint64(0); round < 2; round++ {
+		_, err := selector.SelectProposer(validators, parent, validators[0], round)
+		assert.Error(t, err)
+	}
+
+	assert.Equal(t, 4, queries)
+}
+
+func TestStakeWeightedSelector_Distribution(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C")
+
+	validators := pool.ValidatorSet()
+	selector := &stakeWeightedSelector{getStakes: newStakesQuery(1, 3, 0)}
+
+	picks := map[types.Address]int{}
+
+	for i := 0; i < 1000; i++ {
+		parent := &types.Header{Hash: types.StringToHash(strconv.Itoa(i))}
+
+		proposer, err := selector.SelectProposer(validators, parent, types.ZeroAddress, 0)
+		assert.NoError(t, err)
+
+		picks[proposer]++
+	}
+
+	// validators without stake are never picked
+	assert.Equal(t, 0, picks[validators[2]])
+
+	// the validator with 3 times the stake gets picked roughly 3 times as often
+	assert.InDelta(t, 250, picks[validators[0]], 50)
+	assert.InDelta(t, 750, picks[validators[1]], 50)
+}
+
+func TestStakeWeightedSelector_NoStake(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C")
+
+	validators := pool.ValidatorSet()
+	parent := &types.Header{Hash: types.StringToHash("1")}
+
+	// without any stake the round robin order is used
+	selector := &stakeWeightedSelector{getStakes: newStakesQuery(0, 0, 0)}
+
+	proposer, err := selector.SelectProposer(validators, parent, validators[0], 1)
+	assert.NoError(t, err)
+	assert.Equal(t, validators.CalcProposer(1, validators[0]), proposer)
+
+	// the stakes need to match the validator set
+	selector = &stakeWeightedSelector{getStakes: newStakesQuery(1, 2)}
+
+	_, err = selector.SelectProposer(validators, parent, validators[0], 0)
+	assert.ErrorIs(t, err, errInvalidStakes)
+}
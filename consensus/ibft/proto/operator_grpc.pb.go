@@ -23,6 +23,11 @@ type IbftOperatorClient interface {
 	Propose(ctx context.Context, in *Candidate, opts ...grpc.CallOption) (*empty.Empty, error)
 	Candidates(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*CandidatesResp, error)
 	Status(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*IbftStatusResp, error)
+	Inspect(ctx context.Context, in *InspectReq, opts ...grpc.CallOption) (*InspectResp, error)
+	RotateValidatorKey(ctx context.Context, in *RotateValidatorKeyReq, opts ...grpc.CallOption) (*RotateValidatorKeyResp, error)
+	BadBlocks(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*BadBlocksResp, error)
+	ListPendingVotes(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*PendingVotesResp, error)
+	RetractVote(ctx context.Context, in *RetractVoteReq, opts ...grpc.CallOption) (*empty.Empty, error)
 }
 
 type ibftOperatorClient struct {
@@ -69,6 +74,51 @@ func (c *ibftOperatorClient) Status(ctx context.Context, in *empty.Empty, opts .
 	return out, nil
 }
 
+func (c *ibftOperatorClient) Inspect(ctx context.Context, in *InspectReq, opts ...grpc.CallOption) (*InspectResp, error) {
+	out := new(InspectResp)
+	err := c.cc.Invoke(ctx, "/v1.IbftOperator/Inspect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ibftOperatorClient) RotateValidatorKey(ctx context.Context, in *RotateValidatorKeyReq, opts ...grpc.CallOption) (*RotateValidatorKeyResp, error) {
+	out := new(RotateValidatorKeyResp)
+	err := c.cc.Invoke(ctx, "/v1.IbftOperator/RotateValidatorKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ibftOperatorClient) BadBlocks(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*BadBlocksResp, error) {
+	out := new(BadBlocksResp)
+	err := c.cc.Invoke(ctx, "/v1.IbftOperator/BadBlocks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ibftOperatorClient) ListPendingVotes(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*PendingVotesResp, error) {
+	out := new(PendingVotesResp)
+	err := c.cc.Invoke(ctx, "/v1.IbftOperator/ListPendingVotes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ibftOperatorClient) RetractVote(ctx context.Context, in *RetractVoteReq, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/v1.IbftOperator/RetractVote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // IbftOperatorServer is the server API for IbftOperator service.
 // All implementations must embed UnimplementedIbftOperatorServer
 // for forward compatibility
@@ -77,6 +127,11 @@ type IbftOperatorServer interface {
 	Propose(context.Context, *Candidate) (*empty.Empty, error)
 	Candidates(context.Context, *empty.Empty) (*CandidatesResp, error)
 	Status(context.Context, *empty.Empty) (*IbftStatusResp, error)
+	Inspect(context.Context, *InspectReq) (*InspectResp, error)
+	RotateValidatorKey(context.Context, *RotateValidatorKeyReq) (*RotateValidatorKeyResp, error)
+	BadBlocks(context.Context, *empty.Empty) (*BadBlocksResp, error)
+	ListPendingVotes(context.Context, *empty.Empty) (*PendingVotesResp, error)
+	RetractVote(context.Context, *RetractVoteReq) (*empty.Empty, error)
 	mustEmbedUnimplementedIbftOperatorServer()
 }
 
@@ -96,6 +151,21 @@ func (UnimplementedIbftOperatorServer) Candidates(context.Context, *empty.Empty)
 func (UnimplementedIbftOperatorServer) Status(context.Context, *empty.Empty) (*IbftStatusResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
 }
+func (UnimplementedIbftOperatorServer) Inspect(context.Context, *InspectReq) (*InspectResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Inspect not implemented")
+}
+func (UnimplementedIbftOperatorServer) RotateValidatorKey(context.Context, *RotateValidatorKeyReq) (*RotateValidatorKeyResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateValidatorKey not implemented")
+}
+func (UnimplementedIbftOperatorServer) BadBlocks(context.Context, *empty.Empty) (*BadBlocksResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BadBlocks not implemented")
+}
+func (UnimplementedIbftOperatorServer) ListPendingVotes(context.Context, *empty.Empty) (*PendingVotesResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPendingVotes not implemented")
+}
+func (UnimplementedIbftOperatorServer) RetractVote(context.Context, *RetractVoteReq) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetractVote not implemented")
+}
 func (UnimplementedIbftOperatorServer) mustEmbedUnimplementedIbftOperatorServer() {}
 
 // UnsafeIbftOperatorServer may be embedded to opt out of forward compatibility for this service.
@@ -181,6 +251,96 @@ func _IbftOperator_Status_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IbftOperator_Inspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InspectReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IbftOperatorServer).Inspect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.IbftOperator/Inspect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IbftOperatorServer).Inspect(ctx, req.(*InspectReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IbftOperator_RotateValidatorKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateValidatorKeyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IbftOperatorServer).RotateValidatorKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.IbftOperator/RotateValidatorKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IbftOperatorServer).RotateValidatorKey(ctx, req.(*RotateValidatorKeyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IbftOperator_BadBlocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IbftOperatorServer).BadBlocks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.IbftOperator/BadBlocks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IbftOperatorServer).BadBlocks(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IbftOperator_ListPendingVotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IbftOperatorServer).ListPendingVotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.IbftOperator/ListPendingVotes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IbftOperatorServer).ListPendingVotes(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IbftOperator_RetractVote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetractVoteReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IbftOperatorServer).RetractVote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.IbftOperator/RetractVote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IbftOperatorServer).RetractVote(ctx, req.(*RetractVoteReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // IbftOperator_ServiceDesc is the grpc.ServiceDesc for IbftOperator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -204,6 +364,26 @@ var IbftOperator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Status",
 			Handler:    _IbftOperator_Status_Handler,
 		},
+		{
+			MethodName: "Inspect",
+			Handler:    _IbftOperator_Inspect_Handler,
+		},
+		{
+			MethodName: "RotateValidatorKey",
+			Handler:    _IbftOperator_RotateValidatorKey_Handler,
+		},
+		{
+			MethodName: "BadBlocks",
+			Handler:    _IbftOperator_BadBlocks_Handler,
+		},
+		{
+			MethodName: "ListPendingVotes",
+			Handler:    _IbftOperator_ListPendingVotes_Handler,
+		},
+		{
+			MethodName: "RetractVote",
+			Handler:    _IbftOperator_RetractVote_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "consensus/ibft/proto/operator.proto",
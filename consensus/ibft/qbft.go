@@ -0,0 +1,254 @@
+package ibft
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/umbracle/fastrlp"
+)
+
+// Define the consensus protocol of the chain
+
+type Protocol string
+
+const (
+	// IBFTProtocol defines the Istanbul extra data layout and messages of the node
+	IBFTProtocol Protocol = "ibft"
+
+	// QBFTProtocol defines the extra data layout, the seals and the message payloads
+	// of the QBFT validators of Hyperledger Besu
+	QBFTProtocol Protocol = "qbft"
+)
+
+// protocolTypes is the map used for easy string -> Protocol lookups
+var protocolTypes = map[string]Protocol{
+	"ibft": IBFTProtocol,
+	"qbft": QBFTProtocol,
+}
+
+// String is a helper method for casting a Protocol to a string representation
+func (p Protocol) String() string {
+	return string(p)
+}
+
+// ParseProtocol converts a protocol string representation to a Protocol
+func ParseProtocol(protocol string) (Protocol, error) {
+	// Check if the cast is possible
+	castType, ok := protocolTypes[protocol]
+	if !ok {
+		return castType, fmt.Errorf("invalid IBFT protocol %s", protocol)
+	}
+
+	return castType, nil
+}
+
+// consensusProtocol is the protocol the header hash, and the extra data read outside of the engine,
+// are encoded with. It's set up from the chain params along with the header hash function
+var consensusProtocol = IBFTProtocol
+
+// isQBFT checks if the chain uses the QBFT protocol
+func isQBFT() bool {
+	return consensusProtocol == QBFTProtocol
+}
+
+var (
+	// qbftAuthVote is the vote type of the QBFT votes adding a validator
+	qbftAuthVote = byte(0xff)
+
+	// qbftDropVote is the vote type of the QBFT votes removing a validator
+	qbftDropVote = byte(0x00)
+)
+
+// QBFT message codes, the type of the message is part of its signed payload
+const (
+	qbftProposalCode    = 0x12
+	qbftPrepareCode     = 0x13
+	qbftCommitCode      = 0x14
+	qbftRoundChangeCode = 0x15
+)
+
+// QbftVote is the validator vote carried in the QBFT extra data
+type QbftVote struct {
+	// Recipient is the address voted in or out of the validator set
+	Recipient types.Address
+
+	// Authorize is true if the vote adds the recipient to the validator set
+	Authorize bool
+}
+
+// putQbftVote moves the vote of the header from the miner and nonce fields
+// into the extra data, and sets the miner to the proposer of the block.
+// QBFT blocks aren't sealed by the proposer, they are identified by the miner instead
+func putQbftVote(h *types.Header, proposer types.Address) error {
+	extra, err := GetIbftExtra(h)
+	if err != nil {
+		return err
+	}
+
+	extra.Vote = nil
+
+	if h.Miner != types.ZeroAddress {
+		extra.Vote = &QbftVote{
+			Recipient: h.Miner,
+			Authorize: h.Nonce == nonceAuthVote,
+		}
+	}
+
+	h.Miner = proposer
+	h.Nonce = types.Nonce{}
+
+	return PutIbftExtra(h, extra)
+}
+
+// getHeaderVote returns the candidate and the vote nonce of the header.
+// The QBFT votes are read from the extra data, and mapped to the vote nonces
+func getHeaderVote(h *types.Header) (types.Address, types.Nonce, error) {
+	if !isQBFT() {
+		return h.Miner, h.Nonce, nil
+	}
+
+	extra, err := GetIbftExtra(h)
+	if err != nil {
+		return types.ZeroAddress, types.Nonce{}, err
+	}
+
+	if extra.Vote == nil {
+		return types.ZeroAddress, types.Nonce{}, nil
+	}
+
+	if extra.Vote.Authorize {
+		return extra.Vote.Recipient, nonceAuthVote, nil
+	}
+
+	return extra.Vote.Recipient, nonceDropVote, nil
+}
+
+// qbftHeaderHash returns the QBFT hash of the header, over its extra data
+// without the committed seals and the round
+func qbftHeaderHash(h *types.Header) ([]byte, error) {
+	h = h.Copy()
+
+	extra, err := getQbftExtra(h)
+	if err != nil {
+		return nil, err
+	}
+
+	putQbftExtraValidators(h, extra.Validators)
+
+	return keccak.Keccak256(nil, marshalQbftHeader(h)), nil
+}
+
+// qbftCommitPayload returns the payload the QBFT committed seals sign,
+// the header with its extra data without the committed seals but with the commit round
+func qbftCommitPayload(h *types.Header, round *uint64) ([]byte, error) {
+	h = h.Copy()
+
+	extra, err := getQbftExtra(h)
+	if err != nil {
+		return nil, err
+	}
+
+	extra.CommittedSeal = [][]byte{}
+	extra.RoundNumber = round
+
+	putQbftExtra(h, extra)
+
+	return marshalQbftHeader(h), nil
+}
+
+// marshalQbftHeader encodes all the fields of the header, as hashed by the QBFT validators
+func marshalQbftHeader(h *types.Header) []byte {
+	arena := fastrlp.DefaultArenaPool.Get()
+	defer fastrlp.DefaultArenaPool.Put(arena)
+
+	vv := arena.NewArray()
+	vv.Set(arena.NewBytes(h.ParentHash.Bytes()))
+	vv.Set(arena.NewBytes(h.Sha3Uncles.Bytes()))
+	vv.Set(arena.NewBytes(h.Miner.Bytes()))
+	vv.Set(arena.NewBytes(h.StateRoot.Bytes()))
+	vv.Set(arena.NewBytes(h.TxRoot.Bytes()))
+	vv.Set(arena.NewBytes(h.ReceiptsRoot.Bytes()))
+	vv.Set(arena.NewCopyBytes(h.LogsBloom[:]))
+	vv.Set(arena.NewUint(h.Difficulty))
+	vv.Set(arena.NewUint(h.Number))
+	vv.Set(arena.NewUint(h.GasLimit))
+	vv.Set(arena.NewUint(h.GasUsed))
+	vv.Set(arena.NewUint(h.Timestamp))
+	vv.Set(arena.NewCopyBytes(h.ExtraData))
+	vv.Set(arena.NewBytes(h.MixHash.Bytes()))
+	vv.Set(arena.NewCopyBytes(h.Nonce[:]))
+
+	// the base fee is only part of the headers past the EIP-1559 fork
+	if h.BaseFee != 0 {
+		vv.Set(arena.NewUint(h.BaseFee))
+	}
+
+	return vv.MarshalTo(nil)
+}
+
+// qbftMessagePayload returns the signed payload of the message in the QBFT format,
+// the message code followed by the round identifier and the content of the message
+func qbftMessagePayload(msg *proto.MessageReq) ([]byte, error) {
+	if msg.View == nil {
+		return nil, fmt.Errorf("view not found in %s message", msg.Type)
+	}
+
+	arena := fastrlp.DefaultArenaPool.Get()
+	defer fastrlp.DefaultArenaPool.Put(arena)
+
+	payload := arena.NewArray()
+	payload.Set(arena.NewUint(msg.View.Sequence))
+	payload.Set(arena.NewUint(msg.View.Round))
+
+	var code uint64
+
+	switch msg.Type {
+	case proto.MessageReq_Preprepare:
+		code = qbftProposalCode
+
+		if msg.Proposal == nil {
+			return nil, fmt.Errorf("proposal not found in %s message", msg.Type)
+		}
+
+		// the proposed block is embedded as is
+		parser := fastrlp.DefaultParserPool.Get()
+		defer fastrlp.DefaultParserPool.Put(parser)
+
+		block, err := parser.Parse(msg.Proposal.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		payload.Set(block)
+	case proto.MessageReq_Prepare:
+		code = qbftPrepareCode
+
+		payload.Set(arena.NewBytes(types.StringToHash(msg.Digest).Bytes()))
+	case proto.MessageReq_Commit:
+		code = qbftCommitCode
+
+		seal, err := hex.DecodeHex(msg.Seal)
+		if err != nil {
+			return nil, err
+		}
+
+		payload.Set(arena.NewBytes(types.StringToHash(msg.Digest).Bytes()))
+		payload.Set(arena.NewBytes(seal))
+	case proto.MessageReq_RoundChange:
+		code = qbftRoundChangeCode
+
+		// the round changes don't carry a prepared certificate
+		payload.Set(arena.NewNullArray())
+	default:
+		return nil, fmt.Errorf("unknown message type %s", msg.Type)
+	}
+
+	vv := arena.NewArray()
+	vv.Set(arena.NewUint(code))
+	vv.Set(payload)
+
+	return vv.MarshalTo(nil), nil
+}
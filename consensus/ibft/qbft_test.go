@@ -0,0 +1,304 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+)
+
+// useQBFT switches the package to the QBFT protocol for the duration of the test
+func useQBFT(t *testing.T) {
+	t.Helper()
+
+	consensusProtocol = QBFTProtocol
+	t.Cleanup(func() {
+		consensusProtocol = IBFTProtocol
+	})
+}
+
+func TestParseProtocol(t *testing.T) {
+	protocol, err := ParseProtocol("qbft")
+	assert.NoError(t, err)
+	assert.Equal(t, QBFTProtocol, protocol)
+
+	protocol, err = ParseProtocol("ibft")
+	assert.NoError(t, err)
+	assert.Equal(t, IBFTProtocol, protocol)
+
+	_, err = ParseProtocol("clique")
+	assert.Error(t, err)
+}
+
+func TestQbftExtra_BesuFixtures(t *testing.T) {
+	round := uint64(2)
+
+	tests := []struct {
+		name  string
+		extra string
+		// expected decoded extra
+		validators []types.Address
+		vote       *QbftVote
+		round      uint64
+		seals      int
+	}{
+		{
+			// genesis extra data generated by besu operator generate-blockchain-config
+			name: "genesis of a 4 validator network",
+			extra: "0xf87aa00000000000000000000000000000000000000000000000000000000000000000" +
+				"f8549464a702e6263b7297a96638cac6ae65e6541f4169943923390ad55e90c237593b3b0e401f3b08a03185" +
+				"94aefdb9a738c9f433e5b6b212a6d62f6370c2f69294c7eeb9a4e00ce683cf93039b212648e01c6c6b78c080c0",
+			validators: []types.Address{
+				types.StringToAddress("0x64a702e6263b7297a96638cac6ae65e6541f4169"),
+				types.StringToAddress("0x3923390ad55e90c237593b3b0e401f3b08a03185"),
+				types.StringToAddress("0xaefdb9a738c9f433e5b6b212a6d62f6370c2f692"),
+				types.StringToAddress("0xc7eeb9a4e00ce683cf93039b212648e01c6c6b78"),
+			},
+			round: 0,
+		},
+		{
+			// sealed block layout of the Besu codec: an add vote, the round and the committed seals
+			name: "sealed block with an add vote",
+			extra: hex.EncodeToHex((&IstanbulExtra{
+				Vanity:     make([]byte, IstanbulExtraVanity),
+				Validators: []types.Address{addr1, addr2},
+				Vote: &QbftVote{
+					Recipient: addr3,
+					Authorize: true,
+				},
+				RoundNumber:   &round,
+				CommittedSeal: [][]byte{make([]byte, IstanbulExtraSeal), make([]byte, IstanbulExtraSeal)},
+			}).MarshalRLPTo(nil)),
+			validators: []types.Address{addr1, addr2},
+			vote: &QbftVote{
+				Recipient: addr3,
+				Authorize: true,
+			},
+			round: 2,
+			seals: 2,
+		},
+		{
+			// the drop vote type is the single 0x00 byte
+			name: "block with a drop vote",
+			extra: "0xf850a00000000000000000000000000000000000000000000000000000000000000000" +
+				"d5940000000000000000000000000000000000000001" +
+				"d6940000000000000000000000000000000000000003" + "00" + "80c0",
+			validators: []types.Address{addr1},
+			vote: &QbftVote{
+				Recipient: addr3,
+				Authorize: false,
+			},
+			round: 0,
+		},
+	}
+
+	for _, testcase := range tests {
+		t.Run(testcase.name, func(t *testing.T) {
+			data, err := hex.DecodeHex(testcase.extra)
+			assert.NoError(t, err)
+
+			extra := &IstanbulExtra{}
+			assert.NoError(t, extra.UnmarshalRLP(data))
+
+			assert.Equal(t, make([]byte, IstanbulExtraVanity), extra.Vanity)
+			assert.Equal(t, testcase.validators, extra.Validators)
+			assert.Equal(t, testcase.vote, extra.Vote)
+			assert.Equal(t, testcase.round, *extra.RoundNumber)
+			assert.Len(t, extra.CommittedSeal, testcase.seals)
+			assert.Empty(t, extra.Seal)
+
+			// the extra data is encoded back to the same bytes
+			assert.Equal(t, data, extra.MarshalRLPTo(nil))
+		})
+	}
+}
+
+func TestQbftExtra_Header(t *testing.T) {
+	useQBFT(t)
+
+	vanity := ParseVanity("besu")
+
+	h := &types.Header{
+		Miner: addr3,
+		Nonce: nonceAuthVote,
+	}
+
+	putIbftVanity(h, vanity)
+	putIbftExtraValidators(h, []types.Address{addr1, addr2})
+	assert.NoError(t, putQbftVote(h, addr1))
+
+	// the extra data is RLP encoded as a whole
+	extra := &IstanbulExtra{}
+	assert.NoError(t, extra.UnmarshalRLP(h.ExtraData))
+	assert.Equal(t, vanity, extra.Vanity)
+	assert.Equal(t, vanity, GetVanity(h))
+
+	// the vote moved from the miner to the extra data
+	candidate, nonce, err := getHeaderVote(h)
+	assert.NoError(t, err)
+	assert.Equal(t, addr3, candidate)
+	assert.Equal(t, nonceAuthVote, nonce)
+	assert.Equal(t, types.Nonce{}, h.Nonce)
+
+	// the miner is the proposer
+	proposer, err := RecoverProposer(h)
+	assert.NoError(t, err)
+	assert.Equal(t, addr1, proposer)
+
+	// the validators are replaced without losing the vanity and the vote
+	putIbftExtraValidators(h, []types.Address{addr2})
+
+	extra, err = GetIbftExtra(h)
+	assert.NoError(t, err)
+	assert.Equal(t, vanity, extra.Vanity)
+	assert.Equal(t, []types.Address{addr2}, extra.Validators)
+	assert.Equal(t, &QbftVote{Recipient: addr3, Authorize: true}, extra.Vote)
+
+	// the istanbul layout isn't accepted
+	consensusProtocol = IBFTProtocol
+
+	istanbul := &types.Header{}
+	putIbftExtraValidators(istanbul, []types.Address{addr1})
+
+	consensusProtocol = QBFTProtocol
+
+	_, err = GetIbftExtra(istanbul)
+	assert.Error(t, err)
+}
+
+func TestQbft_CommittedSeals(t *testing.T) {
+	useQBFT(t)
+
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	h := &types.Header{
+		Number:  1,
+		Miner:   pool.get("A").Address(),
+		MixHash: IstanbulDigest,
+	}
+	putIbftExtraValidators(h, pool.ValidatorSet())
+
+	round := uint64(3)
+	hash := istanbulHeaderHash(h)
+
+	seals := [][]byte{}
+
+	for _, accnt := range []string{"A", "B", "C"} {
+		seal, err := writeCommittedSeal(pool.get(accnt).signer(), h, &round, QBFTProtocol)
+		assert.NoError(t, err)
+
+		seals = append(seals, seal)
+	}
+
+	sealed, err := writeCommittedSeals(h, seals, &round)
+	assert.NoError(t, err)
+
+	assert.NoError(t, verifyCommittedSeals(sealed, pool.ValidatorSet(), QBFTProtocol, nil))
+
+	// the committed seals sign the hash of the header with the round, but without the committed seals
+	payload, err := qbftCommitPayload(sealed, &round)
+	assert.NoError(t, err)
+
+	pub, err := crypto.RecoverPubkey(seals[0], crypto.Keccak256(payload))
+	assert.NoError(t, err)
+	assert.Equal(t, pool.get("A").Address(), crypto.PubKeyToAddress(pub))
+
+	// the hash of the block doesn't cover the committed seals and the round
+	assert.Equal(t, hash, istanbulHeaderHash(sealed))
+
+	// the seals are bound to the round
+	other := uint64(4)
+	sealed, err = writeCommittedSeals(h, seals, &other)
+	assert.NoError(t, err)
+
+	assert.Error(t, verifyCommittedSeals(sealed, pool.ValidatorSet(), QBFTProtocol, nil))
+
+	// the committers are recovered in the protocol passed in, whatever the header hash of the package
+	sealed, err = writeCommittedSeals(h, seals, &round)
+	assert.NoError(t, err)
+
+	consensusProtocol = IBFTProtocol
+
+	committers, err := RecoverCommitters(sealed, QBFTProtocol)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Address(pool.ValidatorSet()[:3]), committers)
+
+	_, err = RecoverCommitters(sealed, IBFTProtocol)
+	assert.Error(t, err)
+}
+
+func TestQbft_MessagePayload(t *testing.T) {
+	digest := types.StringToHash("1")
+
+	t.Run("should encode the prepare message", func(t *testing.T) {
+		payload, err := qbftMessagePayload(&proto.MessageReq{
+			Type:   proto.MessageReq_Prepare,
+			View:   proto.ViewMsg(10, 1),
+			Digest: digest.String(),
+		})
+		assert.NoError(t, err)
+
+		// [code, [sequence, round, digest]]
+		expected := append([]byte{0xe5, 0x13, 0xe3, 0x0a, 0x01, 0xa0}, digest.Bytes()...)
+		assert.Equal(t, expected, payload)
+	})
+
+	t.Run("should embed the proposed block", func(t *testing.T) {
+		block := &types.Block{
+			Header: &types.Header{
+				Number: 10,
+			},
+		}
+
+		payload, err := qbftMessagePayload(&proto.MessageReq{
+			Type: proto.MessageReq_Preprepare,
+			View: proto.ViewMsg(10, 0),
+			Proposal: &anypb.Any{
+				Value: block.MarshalRLP(),
+			},
+		})
+		assert.NoError(t, err)
+		assert.Contains(t, string(payload), string(block.MarshalRLP()))
+	})
+
+	t.Run("should sign and validate the messages", func(t *testing.T) {
+		useQBFT(t)
+
+		pool := newTesterAccountPool()
+		pool.add("A")
+
+		msgs := []*proto.MessageReq{
+			{
+				Type:   proto.MessageReq_Prepare,
+				View:   proto.ViewMsg(10, 1),
+				Digest: digest.String(),
+			},
+			{
+				Type:   proto.MessageReq_Commit,
+				View:   proto.ViewMsg(10, 1),
+				Digest: digest.String(),
+				Seal:   hex.EncodeToHex(make([]byte, IstanbulExtraSeal)),
+			},
+			{
+				Type: proto.MessageReq_RoundChange,
+				View: proto.ViewMsg(10, 2),
+			},
+		}
+
+		for _, msg := range msgs {
+			assert.NoError(t, signMsg(pool.get("A").signer(), msg))
+			assert.NoError(t, validateMsg(msg))
+			assert.Equal(t, pool.get("A").Address().String(), msg.From)
+
+			// the view is part of the signed payload
+			msg.View.Round++
+			assert.NoError(t, validateMsg(msg))
+			assert.NotEqual(t, pool.get("A").Address().String(), msg.From)
+		}
+	})
+}
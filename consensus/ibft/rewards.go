@@ -0,0 +1,287 @@
+package ibft
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	ErrMissingParentSeal    = errors.New("parent committed seal missing from the extra data")
+	ErrUnexpectedParentSeal = errors.New("parent committed seal present in the extra data before the epoch reward block")
+
+	errGenesisParentSeal    = errors.New("the genesis has no committed seals")
+	errAggregatedParentSeal = errors.New("the aggregated committed seals can't be copied to the child block")
+)
+
+// setupEpochReward reads the epoch reward of the chain.
+// The QBFT extra data has no room for the committed seals of the parent, so it can't be rewarded
+func (i *Ibft) setupEpochReward() error {
+	if i.config.Params == nil || i.config.Params.EpochReward == nil {
+		return nil
+	}
+
+	if err := i.config.Params.ValidateEpochReward(); err != nil {
+		return fmt.Errorf("invalid epoch reward, %w", err)
+	}
+
+	if isQBFT() {
+		return errors.New("the epoch reward is not supported by the QBFT extra data")
+	}
+
+	i.epochReward = i.config.Params.EpochReward
+
+	return nil
+}
+
+// hasParentCommittedSeal checks if the committed seals of the parent are part of the extra data at the given height
+func (i *Ibft) hasParentCommittedSeal(height uint64) bool {
+	return i.epochReward != nil && height >= i.epochReward.Block
+}
+
+// putParentCommittedSeal copies the committed seals of the parent, and their round,
+// to the extra data of the header past the epoch reward block
+func (i *Ibft) putParentCommittedSeal(parent, header *types.Header) error {
+	if !i.hasParentCommittedSeal(header.Number) {
+		return nil
+	}
+
+	extra, err := GetIbftExtra(header)
+	if err != nil {
+		return err
+	}
+
+	seal := &ParentSeal{
+		CommittedSeal: [][]byte{},
+	}
+
+	if parent.Number != 0 {
+		parentExtra, err := GetIbftExtra(parent)
+		if err != nil {
+			return err
+		}
+
+		if parentExtra.AggregatedCommittedSeal != nil {
+			return errAggregatedParentSeal
+		}
+
+		seal.CommittedSeal = parentExtra.CommittedSeal
+
+		if parentExtra.RoundNumber != nil {
+			seal.Round = *parentExtra.RoundNumber
+		}
+	}
+
+	extra.ParentCommittedSeal = seal
+
+	return PutIbftExtra(header, extra)
+}
+
+// verifyParentCommittedSeal checks the committed seals of the parent are present in the extra data
+// if and only if the header is past the epoch reward block, and that they are a quorum
+// of the validators of the parent
+func (i *Ibft) verifyParentCommittedSeal(parent, header *types.Header) error {
+	extra, err := GetIbftExtra(header)
+	if err != nil {
+		return err
+	}
+
+	if !i.hasParentCommittedSeal(header.Number) {
+		if extra.ParentCommittedSeal != nil {
+			return ErrUnexpectedParentSeal
+		}
+
+		return nil
+	}
+
+	seal := extra.ParentCommittedSeal
+	if seal == nil {
+		return ErrMissingParentSeal
+	}
+
+	if parent.Number == 0 {
+		if len(seal.CommittedSeal) != 0 || seal.Round != 0 {
+			return errGenesisParentSeal
+		}
+
+		return nil
+	}
+
+	committers, err := i.parentCommitters(parent, seal)
+	if err != nil {
+		return err
+	}
+
+	snap, err := i.getSnapshot(parent.Number - 1)
+	if err != nil {
+		return err
+	}
+
+	if snap == nil {
+		return fmt.Errorf("no snapshot found for block %d", parent.Number-1)
+	}
+
+	if err := verifyCommitters(committers, snap.Set); err != nil {
+		return fmt.Errorf("invalid parent committed seal, %w", err)
+	}
+
+	return nil
+}
+
+// parentCommitters returns the validators that provided the committed seals of the parent,
+// as copied to the extra data of its child.
+// The round is only part of the signed message if the parent is past the round number fork
+func (i *Ibft) parentCommitters(parent *types.Header, seal *ParentSeal) ([]types.Address, error) {
+	extra, err := GetIbftExtra(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := parent.Copy()
+
+	extra.CommittedSeal = seal.CommittedSeal
+	extra.AggregatedCommittedSeal = nil
+	extra.RoundNumber = nil
+
+	if i.isRoundNumberFork(parent.Number) {
+		round := seal.Round
+		extra.RoundNumber = &round
+	} else if seal.Round != 0 {
+		return nil, ErrUnexpectedRoundNumber
+	}
+
+	if err := PutIbftExtra(sealed, extra); err != nil {
+		return nil, err
+	}
+
+	return i.signers.committers(sealed)
+}
+
+// applyEpochReward credits the reward of the epoch to the validators at the epoch blocks past the epoch reward block.
+// Every header of the epoch carries the committed seals of its parent, the reward of the blocks is shared
+// in proportion to the committed seals of each validator
+func (i *Ibft) applyEpochReward(header *types.Header, txn *state.Transition) error {
+	if !i.hasParentCommittedSeal(header.Number) || !i.IsLastOfEpoch(header.Number) {
+		return nil
+	}
+
+	seals, blocks, err := i.countEpochSeals(header)
+	if err != nil {
+		return err
+	}
+
+	rewards := splitEpochReward(i.epochReward.BlockReward, seals, blocks)
+	if len(rewards) == 0 {
+		return nil
+	}
+
+	total := big.NewInt(0)
+	for _, reward := range rewards {
+		total.Add(total, reward.amount)
+	}
+
+	if source := i.epochReward.Source; source != nil {
+		if err := txn.Txn().SubBalance(*source, total); err != nil {
+			i.logger.Warn("the epoch reward source can't cover the rewards, skipping them",
+				"number", header.Number, "source", source, "rewards", total, "err", err)
+
+			return nil
+		}
+	}
+
+	for _, reward := range rewards {
+		txn.Txn().AddBalance(reward.validator, reward.amount)
+	}
+
+	return nil
+}
+
+// countEpochSeals counts the committed seals of each validator the headers of the epoch carry,
+// from the epoch block back to the first header of the epoch past the epoch reward block.
+// It returns the number of the blocks the seals are counted of, the genesis has none
+func (i *Ibft) countEpochSeals(header *types.Header) (map[types.Address]uint64, uint64, error) {
+	from := i.getEpochSchedule().previousEpochBlock(header.Number) + 1
+	if from < i.epochReward.Block {
+		from = i.epochReward.Block
+	}
+
+	seals := map[types.Address]uint64{}
+	blocks := uint64(0)
+
+	for current := header; current.Number >= from; {
+		parent, ok := i.blockchain.GetHeaderByHash(current.ParentHash)
+		if !ok {
+			return nil, 0, fmt.Errorf("parent of block %d not found", current.Number)
+		}
+
+		if parent.Number != 0 {
+			extra, err := GetIbftExtra(current)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			if extra.ParentCommittedSeal == nil {
+				return nil, 0, ErrMissingParentSeal
+			}
+
+			committers, err := i.parentCommitters(parent, extra.ParentCommittedSeal)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			for _, committer := range committers {
+				seals[committer]++
+			}
+
+			blocks++
+		}
+
+		current = parent
+	}
+
+	return seals, blocks, nil
+}
+
+// validatorReward is the reward credited to the validator
+type validatorReward struct {
+	validator types.Address
+	amount    *big.Int
+}
+
+// splitEpochReward shares the reward of the blocks among the validators, in proportion to their committed seals.
+// The rewards are rounded down, and sorted by the address of the validators
+func splitEpochReward(blockReward *big.Int, seals map[types.Address]uint64, blocks uint64) []validatorReward {
+	totalSeals := uint64(0)
+	for _, count := range seals {
+		totalSeals += count
+	}
+
+	if totalSeals == 0 || blocks == 0 {
+		return nil
+	}
+
+	total := new(big.Int).Mul(blockReward, new(big.Int).SetUint64(blocks))
+	rewards := make([]validatorReward, 0, len(seals))
+
+	for validator, count := range seals {
+		amount := new(big.Int).Mul(total, new(big.Int).SetUint64(count))
+		amount.Div(amount, new(big.Int).SetUint64(totalSeals))
+
+		if amount.Sign() == 0 {
+			continue
+		}
+
+		rewards = append(rewards, validatorReward{validator: validator, amount: amount})
+	}
+
+	sort.Slice(rewards, func(x, y int) bool {
+		return bytes.Compare(rewards[x].validator.Bytes(), rewards[y].validator.Bytes()) < 0
+	})
+
+	return rewards
+}
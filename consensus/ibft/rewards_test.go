@@ -0,0 +1,255 @@
+package ibft
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// rewardedBlock are the accounts committing to the block, and the round they commit in
+type rewardedBlock struct {
+	committers []string
+	round      uint64
+}
+
+// newRewardIbft returns an IBFT rewarding the committers from the block, with epochs of the given size
+func newRewardIbft(t *testing.T, pool *testerAccountPool, block, epochSize uint64) *Ibft {
+	t.Helper()
+
+	roundNumberBlock := uint64(0)
+
+	ibft := &Ibft{
+		logger:           hclog.NewNullLogger(),
+		config:           &consensus.Config{},
+		blockchain:       blockchain.TestBlockchain(t, pool.genesis()),
+		epochSize:        epochSize,
+		roundNumberBlock: &roundNumberBlock,
+		epochReward: &chain.EpochReward{
+			Block:       block,
+			BlockReward: big.NewInt(10),
+		},
+	}
+
+	initIbftMechanism(PoA, ibft)
+	assert.NoError(t, ibft.setupSnapshot())
+
+	return ibft
+}
+
+// buildRewardedHeaders builds the chain of headers on top of the parent, proposed by the first account
+// of the pool and committed as given, each of them carrying the committed seals of its parent
+func buildRewardedHeaders(
+	t *testing.T,
+	ibft *Ibft,
+	pool *testerAccountPool,
+	parent *types.Header,
+	blocks ...rewardedBlock,
+) []*types.Header {
+	t.Helper()
+
+	headers := make([]*types.Header, 0, len(blocks))
+
+	for _, block := range blocks {
+		h := &types.Header{
+			ParentHash: parent.Hash,
+			Number:     parent.Number + 1,
+			Difficulty: parent.Number + 1,
+			MixHash:    IstanbulDigest,
+			Sha3Uncles: types.EmptyUncleHash,
+		}
+		putIbftExtraValidators(h, pool.ValidatorSet())
+		assert.NoError(t, ibft.putParentCommittedSeal(parent, h))
+
+		sealed, err := writeSeal(pool.accounts[0].signer(), h)
+		assert.NoError(t, err)
+
+		round := block.round
+		seals := make([][]byte, 0, len(block.committers))
+
+		for _, committer := range block.committers {
+			seal, err := writeCommittedSeal(pool.get(committer).signer(), sealed, &round, IBFTProtocol)
+			assert.NoError(t, err)
+
+			seals = append(seals, seal)
+		}
+
+		sealed, err = writeCommittedSeals(sealed, seals, &round)
+		assert.NoError(t, err)
+
+		sealed.ComputeHash()
+
+		parent = sealed
+		headers = append(headers, sealed)
+	}
+
+	return headers
+}
+
+func TestSplitEpochReward(t *testing.T) {
+	a, b, c := types.StringToAddress("1"), types.StringToAddress("2"), types.StringToAddress("3")
+
+	// the rewards of the blocks are shared in proportion to the seals, sorted by address
+	rewards := splitEpochReward(big.NewInt(10), map[types.Address]uint64{c: 1, a: 3, b: 2}, 3)
+	assert.Equal(t, []validatorReward{
+		{validator: a, amount: big.NewInt(15)},
+		{validator: b, amount: big.NewInt(10)},
+		{validator: c, amount: big.NewInt(5)},
+	}, rewards)
+
+	// the rewards are rounded down, the zero rewards are left out
+	rewards = splitEpochReward(big.NewInt(10), map[types.Address]uint64{a: 1, b: 2, c: 0}, 1)
+	assert.Equal(t, []validatorReward{
+		{validator: a, amount: big.NewInt(3)},
+		{validator: b, amount: big.NewInt(6)},
+	}, rewards)
+
+	assert.Empty(t, splitEpochReward(big.NewInt(10), map[types.Address]uint64{}, 0))
+}
+
+func TestParentCommittedSeal_Verify(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	ibft := newRewardIbft(t, pool, 1, 10)
+	genesis := ibft.blockchain.Header()
+
+	headers := buildRewardedHeaders(t, ibft, pool, genesis,
+		rewardedBlock{committers: []string{"A", "B", "C"}, round: 1},
+		rewardedBlock{committers: []string{"A", "B", "C", "D"}},
+	)
+
+	// the genesis has no committed seals to copy
+	assert.NoError(t, ibft.verifyParentCommittedSeal(genesis, headers[0]))
+	assert.NoError(t, ibft.verifyParentCommittedSeal(headers[0], headers[1]))
+
+	committers, err := ibft.parentCommitters(headers[0], mustGetIbftExtra(t, headers[1]).ParentCommittedSeal)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Address{pool.get("A").Address(), pool.get("B").Address(), pool.get("C").Address()}, committers)
+
+	tamper := func(h *types.Header, modify func(seal *ParentSeal)) *types.Header {
+		h = h.Copy()
+
+		extra := mustGetIbftExtra(t, h)
+		modify(extra.ParentCommittedSeal)
+		assert.NoError(t, PutIbftExtra(h, extra))
+
+		return h
+	}
+
+	// a quorum of the seals of the parent has to be copied
+	header := tamper(headers[1], func(seal *ParentSeal) {
+		seal.CommittedSeal = seal.CommittedSeal[:2]
+	})
+	assert.ErrorIs(t, ibft.verifyParentCommittedSeal(headers[0], header), errNotEnoughCommittedSeals)
+
+	// the seals are signed in the round of the parent
+	header = tamper(headers[1], func(seal *ParentSeal) {
+		seal.Round = 0
+	})
+	assert.ErrorIs(t, ibft.verifyParentCommittedSeal(headers[0], header), errNonValidatorCommittedSeal)
+
+	// the genesis has no seals
+	header = tamper(headers[0], func(seal *ParentSeal) {
+		seal.CommittedSeal = mustGetIbftExtra(t, headers[0]).CommittedSeal
+	})
+	assert.ErrorIs(t, ibft.verifyParentCommittedSeal(genesis, header), errGenesisParentSeal)
+
+	// the seals of the parent are only copied from the epoch reward block
+	header = headers[1].Copy()
+	putIbftExtraValidators(header, pool.ValidatorSet())
+
+	extra := mustGetIbftExtra(t, header)
+	extra.ParentCommittedSeal = nil
+	assert.NoError(t, PutIbftExtra(header, extra))
+	assert.ErrorIs(t, ibft.verifyParentCommittedSeal(headers[0], header), ErrMissingParentSeal)
+
+	ibft.epochReward.Block = 3
+	assert.NoError(t, ibft.verifyParentCommittedSeal(headers[0], header))
+	assert.ErrorIs(t, ibft.verifyParentCommittedSeal(headers[0], headers[1]), ErrUnexpectedParentSeal)
+}
+
+func TestApplyEpochReward(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	source := types.StringToAddress("source")
+
+	// epochs of 3 blocks, the genesis epoch block is not rewarded
+	ibft := newRewardIbft(t, pool, 1, 3)
+	headers := buildRewardedHeaders(t, ibft, pool, ibft.blockchain.Header(),
+		rewardedBlock{committers: []string{"A", "B", "C"}},
+		rewardedBlock{committers: []string{"A", "B", "D"}, round: 2},
+		rewardedBlock{committers: []string{"A", "B", "C", "D"}},
+	)
+	assert.NoError(t, ibft.blockchain.(*blockchain.Blockchain).WriteHeaders(headers[:2]))
+
+	applyReward := func(header *types.Header, initial int64) *state.Transition {
+		executor := state.NewExecutor(&chain.Params{
+			Forks:   chain.AllForksEnabled,
+			ChainID: 100,
+		}, itrie.NewState(itrie.NewMemoryStorage()), hclog.NewNullLogger())
+		executor.GetHash = func(*types.Header) state.GetHashByNumber {
+			return func(uint64) types.Hash {
+				return types.ZeroHash
+			}
+		}
+
+		root := executor.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+			source: {Balance: big.NewInt(initial)},
+		})
+
+		transition, err := executor.BeginTxn(root, header, types.ZeroAddress)
+		assert.NoError(t, err)
+		assert.NoError(t, ibft.applyEpochReward(header, transition))
+
+		return transition
+	}
+
+	// the seals of the blocks 1 and 2 are counted at the epoch block 3, 20 are shared among 6 seals
+	expected := map[string]int64{"A": 6, "B": 6, "C": 3, "D": 3}
+
+	transition := applyReward(headers[2], 0)
+	for name, reward := range expected {
+		assert.Equal(t, big.NewInt(reward), transition.GetBalance(pool.get(name).Address()), name)
+	}
+
+	// the rewards are paid from the source
+	ibft.epochReward.Source = &source
+
+	transition = applyReward(headers[2], 100)
+	assert.Equal(t, big.NewInt(82), transition.GetBalance(source))
+	assert.Equal(t, big.NewInt(6), transition.GetBalance(pool.get("A").Address()))
+
+	// the rewards are skipped if the source can't cover them
+	transition = applyReward(headers[2], 10)
+	assert.Equal(t, big.NewInt(10), transition.GetBalance(source))
+	assert.Equal(t, big.NewInt(0), transition.GetBalance(pool.get("A").Address()))
+
+	// the blocks within the epoch are not rewarded
+	transition = applyReward(headers[1], 100)
+	assert.Equal(t, big.NewInt(100), transition.GetBalance(source))
+
+	// only the seals carried from the epoch reward block on are counted, 10 are shared among 3 seals
+	ibft.epochReward.Block = 3
+
+	transition = applyReward(headers[2], 100)
+	assert.Equal(t, big.NewInt(91), transition.GetBalance(source))
+	assert.Equal(t, big.NewInt(0), transition.GetBalance(pool.get("C").Address()))
+}
+
+func mustGetIbftExtra(t *testing.T, h *types.Header) *IstanbulExtra {
+	t.Helper()
+
+	extra, err := GetIbftExtra(h)
+	assert.NoError(t, err)
+
+	return extra
+}
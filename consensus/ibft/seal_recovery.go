@@ -0,0 +1,134 @@
+package ibft
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// maxRecoveredSeals is the number of seal signers kept by the seal cache,
+// enough for the batches of headers of the bulk sync on chains with a hundred validators
+const maxRecoveredSeals = 8192
+
+// sealCache holds the signers of the seals recovered ahead of the header verification.
+// The recovery is a pure function of the seal and the message, so the cache is shared by the package
+var sealCache *lru.Cache
+
+func init() {
+	cache, err := lru.New(maxRecoveredSeals)
+	if err != nil {
+		panic(err)
+	}
+
+	sealCache = cache
+}
+
+// sealJob is the recovery of a single seal
+type sealJob struct {
+	sig []byte
+	msg []byte
+}
+
+// sealCacheKey returns the key of the seal signed over the hash in the seal cache
+func sealCacheKey(sig, hash []byte) string {
+	return string(hash) + string(sig)
+}
+
+// ecrecoverSeal recovers the signer of a header seal. The signers recovered ahead by recoverSeals
+// are taken from the seal cache, every seal is verified once so the entry is removed
+func ecrecoverSeal(sig, msg []byte) (types.Address, error) {
+	hash := crypto.Keccak256(msg)
+	key := sealCacheKey(sig, hash)
+
+	if signer, ok := sealCache.Get(key); ok {
+		sealCache.Remove(key)
+
+		return signer.(types.Address), nil
+	}
+
+	pub, err := crypto.RecoverPubkey(sig, hash)
+	if err != nil {
+		return types.Address{}, err
+	}
+
+	return crypto.PubKeyToAddress(pub), nil
+}
+
+// recoverSeals recovers the signers of the proposer seals and the individual committed seals
+// of the headers of the protocol on the given number of workers, and keeps them in the seal cache.
+// The headers are verified in order afterwards, so the failures are left to the verification
+func recoverSeals(headers []*types.Header, workers int, protocol Protocol) {
+	jobs := make(chan sealJob)
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for job := range jobs {
+				hash := crypto.Keccak256(job.msg)
+
+				pub, err := crypto.RecoverPubkey(job.sig, hash)
+				if err != nil {
+					continue
+				}
+
+				sealCache.Add(sealCacheKey(job.sig, hash), crypto.PubKeyToAddress(pub))
+			}
+		}()
+	}
+
+	for _, header := range headers {
+		for _, job := range headerSealJobs(header, protocol) {
+			jobs <- job
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+}
+
+// headerSealJobs returns the seals of the header to recover, with the messages they sign in the protocol
+func headerSealJobs(header *types.Header, protocol Protocol) []sealJob {
+	extra, err := getExtra(header, protocol)
+	if err != nil {
+		return nil
+	}
+
+	jobs := make([]sealJob, 0, len(extra.CommittedSeal)+1)
+
+	// the QBFT blocks have no proposer seal
+	if protocol != QBFTProtocol {
+		if msg, err := calculateHeaderHash(header); err == nil {
+			jobs = append(jobs, sealJob{sig: extra.Seal, msg: msg})
+		}
+	}
+
+	// the aggregated seal isn't recovered
+	if extra.AggregatedCommittedSeal != nil {
+		return jobs
+	}
+
+	msg, err := committedSealMsg(header, extra.RoundNumber, protocol)
+	if err != nil {
+		return jobs
+	}
+
+	for _, seal := range extra.CommittedSeal {
+		jobs = append(jobs, sealJob{sig: seal, msg: msg})
+	}
+
+	return jobs
+}
+
+// RecoverSeals recovers the seals of the batch of headers concurrently,
+// ahead of their verification in order during the bulk sync
+func (i *Ibft) RecoverSeals(headers []*types.Header) {
+	recoverSeals(headers, runtime.NumCPU(), i.protocol)
+}
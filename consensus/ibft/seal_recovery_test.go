@@ -0,0 +1,143 @@
+package ibft
+
+import (
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSealedHeaders creates the chain of headers sealed by the proposer
+// and committed by a quorum of the validators of the pool
+func newSealedHeaders(t testing.TB, pool *testerAccountPool, num int) []*types.Header {
+	t.Helper()
+
+	validators := pool.ValidatorSet()
+	headers := make([]*types.Header, 0, num)
+	round := uint64(0)
+
+	for indx := 0; indx < num; indx++ {
+		h := &types.Header{
+			Number:     uint64(indx + 1),
+			Difficulty: uint64(indx + 1),
+			MixHash:    IstanbulDigest,
+			Sha3Uncles: types.EmptyUncleHash,
+		}
+		putIbftExtraValidators(h, validators)
+
+		sealed, err := writeSeal(pool.accounts[0].signer(), h)
+		assert.NoError(t, err)
+
+		seals := make([][]byte, 0, validators.QuorumSize())
+
+		for _, accnt := range pool.accounts[:validators.QuorumSize()] {
+			seal, err := writeCommittedSeal(accnt.signer(), sealed, &round, IBFTProtocol)
+			assert.NoError(t, err)
+
+			seals = append(seals, seal)
+		}
+
+		sealed, err = writeCommittedSeals(sealed, seals, &round)
+		assert.NoError(t, err)
+
+		sealed.ComputeHash()
+		headers = append(headers, sealed)
+	}
+
+	return headers
+}
+
+// verifyHeaderSeals verifies the proposer seal and the committed seals of the headers in order
+func verifyHeaderSeals(snap *Snapshot, headers []*types.Header) error {
+	for _, header := range headers {
+		if err := verifySigner(snap, header); err != nil {
+			return err
+		}
+
+		if err := verifyCommittedSeals(header, snap.Set, IBFTProtocol, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestRecoverSeals(t *testing.T) {
+	pool := newTesterAccountPool(4)
+	snap := &Snapshot{
+		Set: pool.ValidatorSet(),
+	}
+
+	headers := newSealedHeaders(t, pool, 10)
+
+	recoverSeals(headers, 4, IBFTProtocol)
+
+	// every recovered seal is in the cache
+	for _, header := range headers {
+		for _, job := range headerSealJobs(header, IBFTProtocol) {
+			assert.True(t, sealCache.Contains(sealCacheKey(job.sig, crypto.Keccak256(job.msg))))
+		}
+	}
+
+	assert.NoError(t, verifyHeaderSeals(snap, headers))
+
+	// the verification consumed the recovered seals
+	assert.Equal(t, 0, sealCache.Len())
+
+	t.Run("should recover the seals of the proposer and the committers", func(t *testing.T) {
+		recoverSeals(headers[:1], 2, IBFTProtocol)
+
+		proposer, err := RecoverProposer(headers[0])
+		assert.NoError(t, err)
+		assert.Equal(t, pool.accounts[0].Address(), proposer)
+
+		committers, err := RecoverCommitters(headers[0], IBFTProtocol)
+		assert.NoError(t, err)
+		assert.Equal(t, []types.Address(pool.ValidatorSet()[:snap.Set.QuorumSize()]), committers)
+	})
+
+	t.Run("should not accept the seals of a tampered header", func(t *testing.T) {
+		header := headers[0].Copy()
+		header.Timestamp = 1
+
+		recoverSeals([]*types.Header{header}, 2, IBFTProtocol)
+
+		// the proposer seal doesn't sign the tampered header
+		assert.Error(t, verifySigner(snap, header))
+	})
+}
+
+// BenchmarkVerifyHeaderSeals measures the headers per second verified during the bulk sync
+// of a 64 validator chain, with the seals recovered serially and on a worker pool
+func BenchmarkVerifyHeaderSeals(b *testing.B) {
+	pool := newTesterAccountPool(64)
+	snap := &Snapshot{
+		Set: pool.ValidatorSet(),
+	}
+
+	headers := newSealedHeaders(b, pool, 50)
+
+	for _, workers := range []int{0, runtime.NumCPU()} {
+		workers := workers
+
+		b.Run("workers="+strconv.Itoa(workers), func(b *testing.B) {
+			start := time.Now()
+
+			for i := 0; i < b.N; i++ {
+				if workers > 0 {
+					recoverSeals(headers, workers, IBFTProtocol)
+				}
+
+				if err := verifyHeaderSeals(snap, headers); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ReportMetric(float64(b.N*len(headers))/time.Since(start).Seconds(), "headers/s")
+		})
+	}
+}
@@ -1,7 +1,8 @@
 package ibft
 
 import (
-	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
 	"fmt"
 
 	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
@@ -12,10 +13,19 @@ import (
 	"github.com/umbracle/fastrlp"
 )
 
-func commitMsg(b []byte) []byte {
+func commitMsg(b []byte, round *uint64) []byte {
 	// message that the nodes need to sign to commit to a block
 	// hash with COMMIT_MSG_CODE which is the same value used in quorum
-	return crypto.Keccak256(b, []byte{byte(proto.MessageReq_Commit)})
+	if round == nil {
+		return crypto.Keccak256(b, []byte{byte(proto.MessageReq_Commit)})
+	}
+
+	// past the round number fork the round is part of the signed message,
+	// so the commit message can be reproduced from the sealed block
+	roundBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(roundBytes, *round)
+
+	return crypto.Keccak256(b, roundBytes, []byte{byte(proto.MessageReq_Commit)})
 }
 
 func ecrecoverImpl(sig, msg []byte) (types.Address, error) {
@@ -29,49 +39,109 @@ func ecrecoverImpl(sig, msg []byte) (types.Address, error) {
 
 func ecrecoverFromHeader(h *types.Header) (types.Address, error) {
 	// get the extra part that contains the seal
-	extra, err := getIbftExtra(h)
+	extra, err := GetIbftExtra(h)
 	if err != nil {
 		return types.Address{}, err
 	}
+
+	// the QBFT blocks have no proposer seal, the proposer is the miner of the block
+	if isQBFT() {
+		return h.Miner, nil
+	}
+
 	// get the sig
 	msg, err := calculateHeaderHash(h)
 	if err != nil {
 		return types.Address{}, err
 	}
 
-	return ecrecoverImpl(extra.Seal, msg)
+	return ecrecoverSeal(extra.Seal, msg)
 }
 
-func signSealImpl(prv *ecdsa.PrivateKey, h *types.Header, committed bool) ([]byte, error) {
-	hash, err := calculateHeaderHash(h)
+// RecoverProposer returns the address of the proposer that sealed the header
+func RecoverProposer(h *types.Header) (types.Address, error) {
+	return ecrecoverFromHeader(h)
+}
+
+// RecoverCommitters returns the addresses of the validators
+// that provided the committed seals in the header, encoded with the protocol
+func RecoverCommitters(h *types.Header, protocol Protocol) ([]types.Address, error) {
+	extra, err := getExtra(h, protocol)
 	if err != nil {
 		return nil, err
 	}
 
+	// the aggregated seal can't be recovered, but the signers
+	// are listed in the bitmap against the validators in the header
+	if extra.AggregatedCommittedSeal != nil {
+		return extra.AggregatedCommittedSeal.Signers(extra.Validators)
+	}
+
+	rawMsg, err := committedSealMsg(h, extra.RoundNumber, protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	committers := make([]types.Address, len(extra.CommittedSeal))
+
+	for indx, seal := range extra.CommittedSeal {
+		if committers[indx], err = ecrecoverSeal(seal, rawMsg); err != nil {
+			return nil, err
+		}
+	}
+
+	return committers, nil
+}
+
+func signSealImpl(signer Signer, h *types.Header, committed bool, round *uint64, protocol Protocol) ([]byte, error) {
 	// if we are singing the committed seals we need to do something more
-	msg := hash
 	if committed {
-		msg = commitMsg(hash)
+		msg, err := committedSealMsg(h, round, protocol)
+		if err != nil {
+			return nil, err
+		}
+
+		return signer.SignCommittedSeal(msg)
+	}
+
+	hash, err := calculateHeaderHash(h)
+	if err != nil {
+		return nil, err
 	}
 
-	seal, err := crypto.Sign(prv, crypto.Keccak256(msg))
+	return signer.SignSeal(hash)
+}
 
+// committedSealMsg returns the message the committed seals of the header sign, in the protocol
+func committedSealMsg(h *types.Header, round *uint64, protocol Protocol) ([]byte, error) {
+	// the QBFT committed seals sign the header itself, hashed when signing
+	if protocol == QBFTProtocol {
+		return qbftCommitPayload(h, round)
+	}
+
+	hash, err := calculateHeaderHash(h)
 	if err != nil {
 		return nil, err
 	}
 
-	return seal, nil
+	return commitMsg(hash, round), nil
 }
 
-func writeSeal(prv *ecdsa.PrivateKey, h *types.Header) (*types.Header, error) {
+func writeSeal(signer Signer, h *types.Header) (*types.Header, error) {
 	h = h.Copy()
-	seal, err := signSealImpl(prv, h, false)
+
+	// the QBFT blocks aren't sealed by the proposer
+	if isQBFT() {
+		return h, nil
+	}
+
+	seal, err := signSealImpl(signer, h, false, nil, IBFTProtocol)
 
 	if err != nil {
 		return nil, err
 	}
 
-	extra, err := getIbftExtra(h)
+	extra, err := GetIbftExtra(h)
 	if err != nil {
 		return nil, err
 	}
@@ -84,11 +154,15 @@ func writeSeal(prv *ecdsa.PrivateKey, h *types.Header) (*types.Header, error) {
 	return h, nil
 }
 
-func writeCommittedSeal(prv *ecdsa.PrivateKey, h *types.Header) ([]byte, error) {
-	return signSealImpl(prv, h, true)
+// writeCommittedSeal signs the commit message of the protocol for the header.
+// The round is only included in the signed message if it's not nil
+func writeCommittedSeal(signer Signer, h *types.Header, round *uint64, protocol Protocol) ([]byte, error) {
+	return signSealImpl(signer, h, true, round, protocol)
 }
 
-func writeCommittedSeals(h *types.Header, seals [][]byte) (*types.Header, error) {
+// writeCommittedSeals writes the committed seals, and the round they were signed in (if not nil),
+// to the extra data of the header
+func writeCommittedSeals(h *types.Header, seals [][]byte, round *uint64) (*types.Header, error) {
 	h = h.Copy()
 
 	if len(seals) == 0 {
@@ -101,12 +175,14 @@ func writeCommittedSeals(h *types.Header, seals [][]byte) (*types.Header, error)
 		}
 	}
 
-	extra, err := getIbftExtra(h)
+	extra, err := GetIbftExtra(h)
 	if err != nil {
 		return nil, err
 	}
 
 	extra.CommittedSeal = seals
+	extra.RoundNumber = round
+
 	if err := PutIbftExtra(h, extra); err != nil {
 		return nil, err
 	}
@@ -122,7 +198,7 @@ func calculateHeaderHash(h *types.Header) ([]byte, error) {
 
 	// when hashing the block for signing we have to remove from
 	// the extra field the seal and committed seal items
-	extra, err := getIbftExtra(h)
+	extra, err := getIstanbulExtra(h)
 	if err != nil {
 		return nil, err
 	}
@@ -130,7 +206,7 @@ func calculateHeaderHash(h *types.Header) ([]byte, error) {
 	// This will effectively remove the Seal and Committed Seal fields,
 	// while keeping proposer vanity and validator set
 	// because extra.Validators is what we got from `h` in the first place.
-	putIbftExtraValidators(h, extra.Validators)
+	putIstanbulExtraValidators(h, extra.Validators)
 
 	vv := arena.NewArray()
 	vv.Set(arena.NewBytes(h.ParentHash.Bytes()))
@@ -147,75 +223,158 @@ func calculateHeaderHash(h *types.Header) ([]byte, error) {
 	vv.Set(arena.NewUint(h.Timestamp))
 	vv.Set(arena.NewCopyBytes(h.ExtraData))
 
+	// the base fee is only part of the headers past the EIP-1559 fork
+	if h.BaseFee != 0 {
+		vv.Set(arena.NewUint(h.BaseFee))
+	}
+
 	buf := keccak.Keccak256Rlp(nil, vv)
 
 	return buf, nil
 }
 
+// AggregatedSealVerifier verifies aggregated committed seals.
+// The signature scheme used for the aggregation is up to the implementation
+type AggregatedSealVerifier interface {
+	// VerifyAggregatedSeal checks that the signature is a valid aggregation
+	// of the signers' signatures over the passed in message
+	VerifyAggregatedSeal(signers []types.Address, msg, signature []byte) error
+}
+
+var errAggregatedSealNotSupported = errors.New("no verifier of the aggregated committed seals")
+
+var (
+	errEmptyCommittedSeals       = errors.New("empty committed seals")
+	errRepeatedCommittedSeal     = errors.New("repeated seal")
+	errNonValidatorCommittedSeal = errors.New("signed by non validator")
+	errNotEnoughCommittedSeals   = errors.New("not enough seals to seal block")
+)
+
 func verifySigner(snap *Snapshot, header *types.Header) error {
 	signer, err := ecrecoverFromHeader(header)
 	if err != nil {
 		return err
 	}
 
-	if !snap.Set.Includes(signer) {
+	return verifyProposer(snap, signer)
+}
+
+// verifyProposer checks the proposer of the header is a validator of the snapshot
+func verifyProposer(snap *Snapshot, proposer types.Address) error {
+	if !snap.Set.Includes(proposer) {
 		return fmt.Errorf("not found signer")
 	}
 
 	return nil
 }
 
-// verifyCommitedFields is checking for consensus proof in the header
-func verifyCommitedFields(snap *Snapshot, header *types.Header) error {
-	extra, err := getIbftExtra(header)
+// verifyCommittedSeals is checking for consensus proof in the header.
+// Every committed seal must come from a distinct member of the passed in validator set,
+// and there must be at least 2F+1 of them. The aggregated seals are checked by the verifier, if any
+func verifyCommittedSeals(
+	header *types.Header,
+	validators ValidatorSet,
+	protocol Protocol,
+	verifier AggregatedSealVerifier,
+) error {
+	extra, err := getExtra(header, protocol)
 	if err != nil {
 		return err
 	}
 
 	// Committed seals shouldn't be empty
-	if len(extra.CommittedSeal) == 0 {
-		return fmt.Errorf("empty committed seals")
+	if len(extra.CommittedSeal) == 0 && extra.AggregatedCommittedSeal == nil {
+		return errEmptyCommittedSeals
 	}
 
 	// get the message that needs to be signed
 	// this not signing! just removing the fields that should be signed
-	hash, err := calculateHeaderHash(header)
+	rawMsg, err := committedSealMsg(header, extra.RoundNumber, protocol)
 	if err != nil {
 		return err
 	}
 
-	rawMsg := commitMsg(hash)
+	if extra.AggregatedCommittedSeal != nil {
+		return verifyAggregatedCommittedSeal(validators, extra.AggregatedCommittedSeal, rawMsg, verifier)
+	}
 
-	visited := map[types.Address]struct{}{}
+	committers := make([]types.Address, len(extra.CommittedSeal))
 
-	for _, seal := range extra.CommittedSeal {
-		addr, err := ecrecoverImpl(seal, rawMsg)
-		if err != nil {
+	for indx, seal := range extra.CommittedSeal {
+		if committers[indx], err = ecrecoverSeal(seal, rawMsg); err != nil {
 			return err
 		}
+	}
+
+	return verifyCommitters(committers, validators)
+}
+
+// verifyCommitters checks the committers of the header are distinct members
+// of the passed in validator set, and that there are at least 2F+1 of them
+func verifyCommitters(committers []types.Address, validators ValidatorSet) error {
+	if len(committers) == 0 {
+		return errEmptyCommittedSeals
+	}
+
+	visited := map[types.Address]struct{}{}
 
+	for _, addr := range committers {
 		if _, ok := visited[addr]; ok {
-			return fmt.Errorf("repeated seal")
-		} else {
-			if !snap.Set.Includes(addr) {
-				return fmt.Errorf("signed by non validator")
-			}
-			visited[addr] = struct{}{}
+			return errRepeatedCommittedSeal
+		}
+
+		if !validators.Includes(addr) {
+			return errNonValidatorCommittedSeal
 		}
+
+		visited[addr] = struct{}{}
 	}
 
 	// Valid committed seals must be at least 2F+1
 	// 	2F 	is the required number of honest validators who provided the committed seals
 	// 	+1	is the proposer
-	if validSeals := len(visited); validSeals < snap.Set.QuorumSize() {
-		return fmt.Errorf("not enough seals to seal block")
+	if validSeals := len(visited); validSeals < validators.QuorumSize() {
+		return errNotEnoughCommittedSeals
 	}
 
 	return nil
 }
 
+// verifyAggregatedCommittedSeal checks that the aggregated seal was signed by
+// at least 2F+1 validators, and that the aggregated signature is valid
+func verifyAggregatedCommittedSeal(
+	validators ValidatorSet,
+	seal *AggregatedSeal,
+	rawMsg []byte,
+	verifier AggregatedSealVerifier,
+) error {
+	signers, err := seal.Signers(validators)
+	if err != nil {
+		return err
+	}
+
+	if len(signers) < validators.QuorumSize() {
+		return errNotEnoughCommittedSeals
+	}
+
+	if verifier == nil {
+		return errAggregatedSealNotSupported
+	}
+
+	return verifier.VerifyAggregatedSeal(signers, rawMsg, seal.Signature)
+}
+
+// messagePayloadNoSig returns the signed payload of the message
+func messagePayloadNoSig(msg *proto.MessageReq) ([]byte, error) {
+	if isQBFT() {
+		return qbftMessagePayload(msg)
+	}
+
+	return msg.PayloadNoSig()
+}
+
 func validateMsg(msg *proto.MessageReq) error {
-	signMsg, err := msg.PayloadNoSig()
+	signMsg, err := messagePayloadNoSig(msg)
 	if err != nil {
 		return err
 	}
@@ -235,13 +394,13 @@ func validateMsg(msg *proto.MessageReq) error {
 	return nil
 }
 
-func signMsg(key *ecdsa.PrivateKey, msg *proto.MessageReq) error {
-	signMsg, err := msg.PayloadNoSig()
+func signMsg(signer Signer, msg *proto.MessageReq) error {
+	signMsg, err := messagePayloadNoSig(msg)
 	if err != nil {
 		return err
 	}
 
-	sig, err := crypto.Sign(key, crypto.Keccak256(signMsg))
+	sig, err := signer.SignMessage(signMsg)
 	if err != nil {
 		return err
 	}
@@ -22,14 +22,44 @@ func TestSign_Sealer(t *testing.T) {
 	// non-validator address
 	pool.add("X")
 
-	badSealedBlock, _ := writeSeal(pool.get("X").priv, h)
+	badSealedBlock, _ := writeSeal(pool.get("X").signer(), h)
 	assert.Error(t, verifySigner(snap, badSealedBlock))
 
 	// seal the block with a validator
-	goodSealedBlock, _ := writeSeal(pool.get("A").priv, h)
+	goodSealedBlock, _ := writeSeal(pool.get("A").signer(), h)
 	assert.NoError(t, verifySigner(snap, goodSealedBlock))
 }
 
+func TestSign_Sealer_BaseFee(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A")
+
+	snap := &Snapshot{
+		Set: pool.ValidatorSet(),
+	}
+
+	h := &types.Header{}
+	putIbftExtraValidators(h, pool.ValidatorSet())
+
+	legacyHash, err := calculateHeaderHash(h)
+	assert.NoError(t, err)
+
+	// the base fee is part of the seal hash
+	h.BaseFee = 1000
+
+	hash, err := calculateHeaderHash(h)
+	assert.NoError(t, err)
+	assert.NotEqual(t, legacyHash, hash)
+
+	sealed, err := writeSeal(pool.get("A").signer(), h)
+	assert.NoError(t, err)
+	assert.NoError(t, verifySigner(snap, sealed))
+
+	// the seal doesn't cover another base fee
+	sealed.BaseFee = 2000
+	assert.Error(t, verifySigner(snap, sealed))
+}
+
 func TestSign_CommittedSeals(t *testing.T) {
 	pool := newTesterAccountPool()
 	pool.add("A", "B", "C", "D", "E")
@@ -48,31 +78,206 @@ func TestSign_CommittedSeals(t *testing.T) {
 		seals := [][]byte{}
 
 		for _, accnt := range accnt {
-			seal, err := writeCommittedSeal(pool.get(accnt).priv, h)
+			seal, err := writeCommittedSeal(pool.get(accnt).signer(), h, nil, IBFTProtocol)
 
 			assert.NoError(t, err)
 
 			seals = append(seals, seal)
 		}
 
-		sealed, err := writeCommittedSeals(h, seals)
+		sealed, err := writeCommittedSeals(h, seals, nil)
 
 		assert.NoError(t, err)
 
-		return verifyCommitedFields(snap, sealed)
+		return verifyCommittedSeals(sealed, snap.Set, IBFTProtocol, nil)
 	}
 
 	// Correct
 	assert.NoError(t, buildCommittedSeal([]string{"A", "B", "C", "D"}))
 
 	// Failed - Repeated signature
-	assert.Error(t, buildCommittedSeal([]string{"A", "A"}))
+	assert.ErrorIs(t, buildCommittedSeal([]string{"A", "A"}), errRepeatedCommittedSeal)
 
 	// Failed - Non validator signature
-	assert.Error(t, buildCommittedSeal([]string{"A", "X"}))
+	assert.ErrorIs(t, buildCommittedSeal([]string{"A", "X"}), errNonValidatorCommittedSeal)
+
+	// Failed - Not enough signatures
+	assert.ErrorIs(t, buildCommittedSeal([]string{"A"}), errNotEnoughCommittedSeals)
+}
+
+func TestSign_CommittedSeals_DistinctSigners(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	validators := pool.ValidatorSet()
+
+	h := &types.Header{}
+	putIbftExtraValidators(h, validators)
+
+	buildCommittedSeal := func(accnt []string) error {
+		seals := [][]byte{}
+
+		for _, accnt := range accnt {
+			seal, err := writeCommittedSeal(pool.get(accnt).signer(), h, nil, IBFTProtocol)
+
+			assert.NoError(t, err)
+
+			seals = append(seals, seal)
+		}
+
+		sealed, err := writeCommittedSeals(h, seals, nil)
+
+		assert.NoError(t, err)
+
+		return verifyCommittedSeals(sealed, validators, IBFTProtocol, nil)
+	}
+
+	// Correct - 3 distinct validators reach the quorum of 3
+	assert.NoError(t, buildCommittedSeal([]string{"A", "B", "C"}))
+
+	// Failed - 3 seals, but only from 2 distinct validators
+	assert.ErrorIs(t, buildCommittedSeal([]string{"A", "B", "A"}), errRepeatedCommittedSeal)
+	assert.ErrorIs(t, buildCommittedSeal([]string{"A", "A", "B"}), errRepeatedCommittedSeal)
+
+	// Failed - only 2 distinct validators
+	assert.ErrorIs(t, buildCommittedSeal([]string{"A", "B"}), errNotEnoughCommittedSeals)
+}
+
+func TestSign_RecoverSigners(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	h := &types.Header{}
+	putIbftExtraValidators(h, pool.ValidatorSet())
+
+	sealed, err := writeSeal(pool.get("A").signer(), h)
+	assert.NoError(t, err)
+
+	seals := [][]byte{}
+
+	for _, accnt := range []string{"B", "C", "D"} {
+		seal, err := writeCommittedSeal(pool.get(accnt).signer(), sealed, nil, IBFTProtocol)
+		assert.NoError(t, err)
+
+		seals = append(seals, seal)
+	}
+
+	sealed, err = writeCommittedSeals(sealed, seals, nil)
+	assert.NoError(t, err)
+
+	proposer, err := RecoverProposer(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, pool.get("A").Address(), proposer)
+
+	committers, err := RecoverCommitters(sealed, IBFTProtocol)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Address{
+		pool.get("B").Address(),
+		pool.get("C").Address(),
+		pool.get("D").Address(),
+	}, committers)
+}
+
+func TestSign_CommittedSeals_RoundNumber(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	snap := &Snapshot{
+		Set: pool.ValidatorSet(),
+	}
+
+	h := &types.Header{}
+	putIbftExtraValidators(h, pool.ValidatorSet())
+
+	buildCommittedSeal := func(signedRound, writtenRound *uint64) error {
+		seals := [][]byte{}
+
+		for _, accnt := range []string{"A", "B", "C"} {
+			seal, err := writeCommittedSeal(pool.get(accnt).signer(), h, signedRound, IBFTProtocol)
+
+			assert.NoError(t, err)
+
+			seals = append(seals, seal)
+		}
+
+		sealed, err := writeCommittedSeals(h, seals, writtenRound)
+
+		assert.NoError(t, err)
+
+		return verifyCommittedSeals(sealed, snap.Set, IBFTProtocol, nil)
+	}
+
+	round1, round2 := uint64(1), uint64(2)
+
+	// Correct
+	assert.NoError(t, buildCommittedSeal(&round1, &round1))
+
+	// Failed - the seals were signed in a different round
+	assert.ErrorIs(t, buildCommittedSeal(&round1, &round2), errNonValidatorCommittedSeal)
+
+	// Failed - the seals were signed without the round
+	assert.ErrorIs(t, buildCommittedSeal(nil, &round1), errNonValidatorCommittedSeal)
+}
+
+type mockAggregatedSealVerifier struct {
+	signers []types.Address
+}
+
+func (m *mockAggregatedSealVerifier) VerifyAggregatedSeal(signers []types.Address, msg, signature []byte) error {
+	m.signers = signers
+
+	return nil
+}
+
+func TestSign_AggregatedCommittedSeals(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C", "D")
+
+	snap := &Snapshot{
+		Set: pool.ValidatorSet(),
+	}
+
+	h := &types.Header{}
+	putIbftExtraValidators(h, pool.ValidatorSet())
+
+	var verifier AggregatedSealVerifier
+
+	buildAggregatedSeal := func(indexes []int) error {
+		var bitmap []byte
+
+		for _, indx := range indexes {
+			bitmap = bitmapSet(bitmap, indx)
+		}
+
+		extra, err := GetIbftExtra(h)
+		assert.NoError(t, err)
+
+		extra.AggregatedCommittedSeal = &AggregatedSeal{
+			Bitmap:    bitmap,
+			Signature: []byte{0x1},
+		}
+
+		sealed := h.Copy()
+		assert.NoError(t, PutIbftExtra(sealed, extra))
+
+		return verifyCommittedSeals(sealed, snap.Set, IBFTProtocol, verifier)
+	}
+
+	// Failed - No verifier
+	assert.ErrorIs(t, buildAggregatedSeal([]int{0, 1, 2}), errAggregatedSealNotSupported)
+
+	mock := &mockAggregatedSealVerifier{}
+	verifier = mock
+
+	// Correct
+	assert.NoError(t, buildAggregatedSeal([]int{0, 1, 2}))
+	assert.Equal(t, []types.Address{snap.Set[0], snap.Set[1], snap.Set[2]}, mock.signers)
 
 	// Failed - Not enough signatures
-	assert.Error(t, buildCommittedSeal([]string{"A"}))
+	assert.Error(t, buildAggregatedSeal([]int{0, 1}))
+
+	// Failed - Signer outside of the validator set
+	assert.Error(t, buildAggregatedSeal([]int{0, 1, 2, 4}))
 }
 
 func TestSign_Messages(t *testing.T) {
@@ -80,7 +285,7 @@ func TestSign_Messages(t *testing.T) {
 	pool.add("A")
 
 	msg := &proto.MessageReq{}
-	assert.NoError(t, signMsg(pool.get("A").priv, msg))
+	assert.NoError(t, signMsg(pool.get("A").signer(), msg))
 	assert.NoError(t, validateMsg(msg))
 
 	assert.Equal(t, msg.From, pool.get("A").Address().String())
@@ -0,0 +1,286 @@
+package ibft
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/secrets"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	// defaultRemoteSignerTimeout is the timeout of a single remote signer request
+	defaultRemoteSignerTimeout = 2 * time.Second
+
+	// remoteSignerRetryDelay is the delay between the retries of a failed remote signer request
+	remoteSignerRetryDelay = 100 * time.Millisecond
+)
+
+var (
+	errInvalidRemoteSignature = errors.New("remote signer returned a signature of a different address")
+)
+
+// Signer signs the seals and the messages of the validator
+type Signer interface {
+	// Address returns the address of the validator
+	Address() types.Address
+
+	// SignSeal signs the proposer seal over the header hash
+	SignSeal(hash []byte) ([]byte, error)
+
+	// SignCommittedSeal signs the commit message of the header
+	SignCommittedSeal(msg []byte) ([]byte, error)
+
+	// SignMessage signs the payload of an IBFT message
+	SignMessage(payload []byte) ([]byte, error)
+}
+
+// localSigner signs with the validator key held by the node
+type localSigner struct {
+	key  *ecdsa.PrivateKey
+	addr types.Address
+}
+
+// NewLocalSigner returns a signer using the passed in validator key
+func NewLocalSigner(key *ecdsa.PrivateKey) Signer {
+	return &localSigner{
+		key:  key,
+		addr: crypto.PubKeyToAddress(&key.PublicKey),
+	}
+}
+
+// Address implements the Signer interface method
+func (s *localSigner) Address() types.Address {
+	return s.addr
+}
+
+// SignSeal implements the Signer interface method
+func (s *localSigner) SignSeal(hash []byte) ([]byte, error) {
+	return s.sign(hash)
+}
+
+// SignCommittedSeal implements the Signer interface method
+func (s *localSigner) SignCommittedSeal(msg []byte) ([]byte, error) {
+	return s.sign(msg)
+}
+
+// SignMessage implements the Signer interface method
+func (s *localSigner) SignMessage(payload []byte) ([]byte, error) {
+	return s.sign(payload)
+}
+
+func (s *localSigner) sign(data []byte) ([]byte, error) {
+	return crypto.Sign(s.key, crypto.Keccak256(data))
+}
+
+// kmsSigner signs with the validator key held by the KMS of the secrets manager
+type kmsSigner struct {
+	signer secrets.KeySigner
+
+	pub  *ecdsa.PublicKey
+	addr types.Address
+}
+
+// newKMSSigner fetches the public key of the validator from the KMS of the secrets manager
+func newKMSSigner(signer secrets.KeySigner) (*kmsSigner, error) {
+	pub, err := signer.PublicKey(secrets.ValidatorKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kmsSigner{
+		signer: signer,
+		pub:    pub,
+		addr:   crypto.PubKeyToAddress(pub),
+	}, nil
+}
+
+// Address implements the Signer interface method
+func (s *kmsSigner) Address() types.Address {
+	return s.addr
+}
+
+// SignSeal implements the Signer interface method
+func (s *kmsSigner) SignSeal(hash []byte) ([]byte, error) {
+	return s.sign(hash)
+}
+
+// SignCommittedSeal implements the Signer interface method
+func (s *kmsSigner) SignCommittedSeal(msg []byte) ([]byte, error) {
+	return s.sign(msg)
+}
+
+// SignMessage implements the Signer interface method
+func (s *kmsSigner) SignMessage(payload []byte) ([]byte, error) {
+	return s.sign(payload)
+}
+
+// sign signs the hash of the data in the KMS,
+// and converts the DER signature to the compact signature of the seals
+func (s *kmsSigner) sign(data []byte) ([]byte, error) {
+	hash := crypto.Keccak256(data)
+
+	der, err := s.signer.SignDigest(secrets.ValidatorKey, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.CompactSignatureFromDER(der, hash, s.pub)
+}
+
+// remoteSigner signs through a remote signer service holding the validator key
+type remoteSigner struct {
+	logger hclog.Logger
+
+	conn   *grpc.ClientConn
+	client proto.RemoteSignerClient
+
+	timeout    time.Duration
+	maxRetries uint64
+
+	addr types.Address
+}
+
+// newRemoteSigner connects to the remote signer, and fetches the address of the validator
+func newRemoteSigner(logger hclog.Logger, config *consensus.RemoteSignerConfig) (*remoteSigner, error) {
+	creds := insecure.NewCredentials()
+
+	if config.TLSCAFile != "" {
+		tlsCreds, err := credentials.NewClientTLSFromFile(config.TLSCAFile, "")
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the remote signer CA certificate, %w", err)
+		}
+
+		creds = tlsCreds
+	}
+
+	conn, err := grpc.Dial(config.Endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+
+	signer := &remoteSigner{
+		logger:     logger.Named("remote_signer"),
+		conn:       conn,
+		client:     proto.NewRemoteSignerClient(conn),
+		timeout:    config.Timeout,
+		maxRetries: config.MaxRetries,
+	}
+
+	if signer.timeout == 0 {
+		signer.timeout = defaultRemoteSignerTimeout
+	}
+
+	if err := signer.fetchAddress(); err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("unable to fetch the validator address from the remote signer, %w", err)
+	}
+
+	return signer, nil
+}
+
+// fetchAddress fetches the address of the validator from the remote signer
+func (s *remoteSigner) fetchAddress() error {
+	return s.withRetry(func(ctx context.Context) error {
+		resp, err := s.client.GetAddress(ctx, &empty.Empty{})
+		if err != nil {
+			return err
+		}
+
+		return s.addr.UnmarshalText([]byte(resp.Address))
+	})
+}
+
+// Address implements the Signer interface method
+func (s *remoteSigner) Address() types.Address {
+	return s.addr
+}
+
+// SignSeal implements the Signer interface method
+func (s *remoteSigner) SignSeal(hash []byte) ([]byte, error) {
+	return s.sign(proto.SignReq_Seal, hash)
+}
+
+// SignCommittedSeal implements the Signer interface method
+func (s *remoteSigner) SignCommittedSeal(msg []byte) ([]byte, error) {
+	return s.sign(proto.SignReq_CommittedSeal, msg)
+}
+
+// SignMessage implements the Signer interface method
+func (s *remoteSigner) SignMessage(payload []byte) ([]byte, error) {
+	return s.sign(proto.SignReq_Message, payload)
+}
+
+// sign requests the signature from the remote signer,
+// and checks that it was signed by the validator
+func (s *remoteSigner) sign(typ proto.SignReq_Type, data []byte) ([]byte, error) {
+	var signature []byte
+
+	err := s.withRetry(func(ctx context.Context) error {
+		resp, err := s.client.Sign(ctx, &proto.SignReq{
+			Type: typ,
+			Data: data,
+		})
+		if err != nil {
+			return err
+		}
+
+		signature = resp.Signature
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := ecrecoverImpl(signature, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if signer != s.addr {
+		return nil, errInvalidRemoteSignature
+	}
+
+	return signature, nil
+}
+
+// withRetry runs the request with the configured timeout, retrying it if it fails
+func (s *remoteSigner) withRetry(request func(ctx context.Context) error) error {
+	var err error
+
+	for attempt := uint64(0); attempt <= s.maxRetries; attempt++ {
+		if attempt > 0 {
+			s.logger.Debug("retrying the remote signer request", "attempt", attempt, "err", err)
+			time.Sleep(remoteSignerRetryDelay)
+		}
+
+		ctx, cancelFn := context.WithTimeout(context.Background(), s.timeout)
+		err = request(ctx)
+
+		cancelFn()
+
+		if err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// Close closes the connection to the remote signer
+func (s *remoteSigner) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,129 @@
+package ibft
+
+import (
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// maxCachedSigners is the number of blocks whose signers are kept by the signer cache
+const maxCachedSigners = 1024
+
+// headerSigners are the proposer and the committers recovered from the seals of a block
+type headerSigners struct {
+	// extra is the hash of the extra data the signers were recovered from.
+	// The block hash doesn't cover the seals, so the same block can be sealed differently
+	extra types.Hash
+
+	proposer   types.Address
+	committers []types.Address
+}
+
+// signerCache keeps the signers of the last recovered blocks by block hash,
+// so the verification, the snapshot processing and the operator service recover the seals once
+type signerCache struct {
+	metrics  *consensus.Metrics
+	cache    *lru.Cache
+	protocol Protocol // Protocol the committed seals are signed in
+}
+
+// newSignerCache creates a new signer cache of the given number of blocks of the protocol
+func newSignerCache(metrics *consensus.Metrics, size int, protocol Protocol) (*signerCache, error) {
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &signerCache{
+		metrics:  metrics,
+		cache:    cache,
+		protocol: protocol,
+	}, nil
+}
+
+// get returns the signers of the header, recovered from its seals if not cached.
+// A nil signer cache, or a header without hash, recovers the seals every time.
+// The nil signer cache recovers the seals of the IBFT protocol
+func (c *signerCache) get(h *types.Header) (*headerSigners, error) {
+	protocol := IBFTProtocol
+	if c != nil {
+		protocol = c.protocol
+	}
+
+	cached := c != nil && h.Hash != types.ZeroHash
+
+	extra := types.BytesToHash(crypto.Keccak256(h.ExtraData))
+
+	if cached {
+		if cached, ok := c.cache.Get(h.Hash); ok && cached.(*headerSigners).extra == extra {
+			c.metrics.SignerCacheHits.Add(1)
+
+			return cached.(*headerSigners), nil
+		}
+
+		c.metrics.SignerCacheMisses.Add(1)
+	}
+
+	proposer, err := ecrecoverFromHeader(h)
+	if err != nil {
+		return nil, err
+	}
+
+	committers, err := RecoverCommitters(h, protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	signers := &headerSigners{
+		extra:      extra,
+		proposer:   proposer,
+		committers: committers,
+	}
+
+	if cached {
+		c.cache.Add(h.Hash, signers)
+	}
+
+	return signers, nil
+}
+
+// proposer returns the proposer of the header
+func (c *signerCache) proposer(h *types.Header) (types.Address, error) {
+	signers, err := c.get(h)
+	if err != nil {
+		return types.ZeroAddress, err
+	}
+
+	return signers.proposer, nil
+}
+
+// committers returns the validators that provided the committed seals of the header
+func (c *signerCache) committers(h *types.Header) ([]types.Address, error) {
+	signers, err := c.get(h)
+	if err != nil {
+		return nil, err
+	}
+
+	return signers.committers, nil
+}
+
+// verifyCommittedSeals verifies the committed seals of the header against the validators,
+// with the committers of the signer cache. The aggregated seals are verified against their signature
+func (i *Ibft) verifyCommittedSeals(header *types.Header, validators ValidatorSet) error {
+	extra, err := getExtra(header, i.protocol)
+	if err != nil {
+		return err
+	}
+
+	if extra.AggregatedCommittedSeal != nil {
+		return verifyCommittedSeals(header, validators, i.protocol, i.aggregatedSealVerifier())
+	}
+
+	committers, err := i.signers.committers(header)
+	if err != nil {
+		return err
+	}
+
+	return verifyCommitters(committers, validators)
+}
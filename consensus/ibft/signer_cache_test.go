@@ -0,0 +1,99 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSignerCache(t *testing.T) (*signerCache, *mockCounter, *mockCounter) {
+	t.Helper()
+
+	hits, misses := &mockCounter{}, &mockCounter{}
+
+	consensusMetrics := consensus.NilMetrics()
+	consensusMetrics.SignerCacheHits = hits
+	consensusMetrics.SignerCacheMisses = misses
+
+	cache, err := newSignerCache(consensusMetrics, 2, IBFTProtocol)
+	assert.NoError(t, err)
+
+	return cache, hits, misses
+}
+
+func TestSignerCache(t *testing.T) {
+	pool := newTesterAccountPool(4)
+	headers := newSealedHeaders(t, pool, 3)
+	quorum := pool.ValidatorSet()[:pool.ValidatorSet().QuorumSize()]
+
+	t.Run("should recover the signers once", func(t *testing.T) {
+		cache, hits, misses := newTestSignerCache(t)
+
+		for indx := 0; indx < 2; indx++ {
+			proposer, err := cache.proposer(headers[0])
+			assert.NoError(t, err)
+			assert.Equal(t, pool.accounts[0].Address(), proposer)
+
+			committers, err := cache.committers(headers[0])
+			assert.NoError(t, err)
+			assert.Equal(t, []types.Address(quorum), committers)
+		}
+
+		assert.Equal(t, float64(3), hits.value)
+		assert.Equal(t, float64(1), misses.value)
+	})
+
+	t.Run("should evict the least recently used blocks", func(t *testing.T) {
+		cache, _, misses := newTestSignerCache(t)
+
+		for _, header := range headers {
+			_, err := cache.get(header)
+			assert.NoError(t, err)
+		}
+
+		_, err := cache.get(headers[0])
+		assert.NoError(t, err)
+
+		assert.Equal(t, float64(4), misses.value)
+	})
+
+	t.Run("should recover the signers of the differently sealed block", func(t *testing.T) {
+		cache, hits, _ := newTestSignerCache(t)
+
+		_, err := cache.get(headers[0])
+		assert.NoError(t, err)
+
+		// the same block, committed by other validators
+		round := uint64(0)
+		seals := [][]byte{}
+
+		for _, accnt := range pool.accounts[1:] {
+			seal, err := writeCommittedSeal(accnt.signer(), headers[0], &round, IBFTProtocol)
+			assert.NoError(t, err)
+
+			seals = append(seals, seal)
+		}
+
+		resealed, err := writeCommittedSeals(headers[0], seals, &round)
+		assert.NoError(t, err)
+
+		// the istanbul hash of the block doesn't cover the committed seals
+		resealed.Hash = headers[0].Hash
+
+		committers, err := cache.committers(resealed)
+		assert.NoError(t, err)
+		assert.Equal(t, []types.Address(pool.ValidatorSet()[1:]), committers)
+
+		assert.Equal(t, float64(0), hits.value)
+	})
+
+	t.Run("should recover the signers without a cache", func(t *testing.T) {
+		var cache *signerCache
+
+		proposer, err := cache.proposer(headers[0])
+		assert.NoError(t, err)
+		assert.Equal(t, pool.accounts[0].Address(), proposer)
+	})
+}
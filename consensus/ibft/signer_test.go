@@ -0,0 +1,236 @@
+package ibft
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// mockRemoteSigner is a remote signer service signing with the given key
+type mockRemoteSigner struct {
+	proto.UnimplementedRemoteSignerServer
+
+	addr types.Address
+	key  *ecdsa.PrivateKey
+
+	// failures is the number of sign requests that fail before the signer responds
+	failures int
+}
+
+func (m *mockRemoteSigner) GetAddress(_ context.Context, _ *empty.Empty) (*proto.SignerAddressResp, error) {
+	return &proto.SignerAddressResp{Address: m.addr.String()}, nil
+}
+
+func (m *mockRemoteSigner) Sign(_ context.Context, req *proto.SignReq) (*proto.SignResp, error) {
+	if m.failures > 0 {
+		m.failures--
+
+		return nil, errors.New("signer unavailable")
+	}
+
+	signature, err := crypto.Sign(m.key, crypto.Keccak256(req.Data))
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.SignResp{Signature: signature}, nil
+}
+
+func newTestRemoteSigner(t *testing.T, server *mockRemoteSigner, maxRetries uint64) *remoteSigner {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	proto.RegisterRemoteSignerServer(grpcServer, server)
+
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+
+	t.Cleanup(grpcServer.Stop)
+
+	signer, err := newRemoteSigner(hclog.NewNullLogger(), &consensus.RemoteSignerConfig{
+		Endpoint:   listener.Addr().String(),
+		Timeout:    time.Second,
+		MaxRetries: maxRetries,
+	})
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = signer.Close()
+	})
+
+	return signer
+}
+
+func TestLocalSigner(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A")
+
+	signer := pool.get("A").signer()
+	assert.Equal(t, pool.get("A").Address(), signer.Address())
+
+	data := []byte("data")
+
+	signature, err := signer.SignMessage(data)
+	assert.NoError(t, err)
+
+	addr, err := ecrecoverImpl(signature, data)
+	assert.NoError(t, err)
+	assert.Equal(t, signer.Address(), addr)
+}
+
+func TestRemoteSigner_Seals(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B")
+
+	signer := newTestRemoteSigner(t, &mockRemoteSigner{
+		addr: pool.get("A").Address(),
+		key:  pool.get("A").priv,
+	}, 0)
+
+	assert.Equal(t, pool.get("A").Address(), signer.Address())
+
+	h := &types.Header{
+		ExtraData: make([]byte, IstanbulExtraVanity),
+	}
+	putIbftExtraValidators(h, pool.ValidatorSet())
+
+	// the proposer seal is signed remotely
+	sealed, err := writeSeal(signer, h)
+	assert.NoError(t, err)
+
+	proposer, err := ecrecoverFromHeader(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, pool.get("A").Address(), proposer)
+
+	// the committed seal signed remotely matches the one signed locally,
+	// as the signatures are deterministic
+	round := uint64(2)
+
+	remoteSeal, err := writeCommittedSeal(signer, sealed, &round, IBFTProtocol)
+	assert.NoError(t, err)
+
+	localSeal, err := writeCommittedSeal(pool.get("A").signer(), sealed, &round, IBFTProtocol)
+	assert.NoError(t, err)
+	assert.Equal(t, localSeal, remoteSeal)
+
+	// the messages are signed remotely
+	msg := &proto.MessageReq{
+		Type: proto.MessageReq_Commit,
+		View: proto.ViewMsg(1, 0),
+	}
+	assert.NoError(t, signMsg(signer, msg))
+	assert.NoError(t, validateMsg(msg))
+}
+
+func TestRemoteSigner_WrongKey(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B")
+
+	// the remote signer reports the address of A, but signs with the key of B
+	signer := newTestRemoteSigner(t, &mockRemoteSigner{
+		addr: pool.get("A").Address(),
+		key:  pool.get("B").priv,
+	}, 0)
+
+	_, err := signer.SignSeal(crypto.Keccak256([]byte("hash")))
+	assert.ErrorIs(t, err, errInvalidRemoteSignature)
+}
+
+func TestRemoteSigner_Retries(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A")
+
+	// the request succeeds if the signer recovers within the retries
+	signer := newTestRemoteSigner(t, &mockRemoteSigner{
+		addr:     pool.get("A").Address(),
+		key:      pool.get("A").priv,
+		failures: 2,
+	}, 2)
+
+	_, err := signer.SignMessage([]byte("data"))
+	assert.NoError(t, err)
+
+	// the request fails once the retries run out
+	signer = newTestRemoteSigner(t, &mockRemoteSigner{
+		addr:     pool.get("A").Address(),
+		key:      pool.get("A").priv,
+		failures: 2,
+	}, 1)
+
+	_, err = signer.SignMessage([]byte("data"))
+	assert.Error(t, err)
+}
+
+// mockKeySigner is a KMS signing the digests with the given key
+type mockKeySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (m *mockKeySigner) CreateKey(_ string) error {
+	return nil
+}
+
+func (m *mockKeySigner) PublicKey(_ string) (*ecdsa.PublicKey, error) {
+	return &m.key.PublicKey, nil
+}
+
+func (m *mockKeySigner) SignDigest(_ string, digest []byte) ([]byte, error) {
+	sig, err := (*btcec.PrivateKey)(m.key).Sign(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return sig.Serialize(), nil
+}
+
+func TestKMSSigner_Seals(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B")
+
+	signer, err := newKMSSigner(&mockKeySigner{
+		key: pool.get("A").priv,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, pool.get("A").Address(), signer.Address())
+
+	h := &types.Header{
+		ExtraData: make([]byte, IstanbulExtraVanity),
+	}
+	putIbftExtraValidators(h, pool.ValidatorSet())
+
+	// the proposer seal is signed by the KMS
+	sealed, err := writeSeal(signer, h)
+	assert.NoError(t, err)
+
+	proposer, err := ecrecoverFromHeader(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, pool.get("A").Address(), proposer)
+
+	// the committed seal signed by the KMS matches the one signed locally
+	round := uint64(2)
+
+	kmsSeal, err := writeCommittedSeal(signer, sealed, &round, IBFTProtocol)
+	assert.NoError(t, err)
+
+	localSeal, err := writeCommittedSeal(pool.get("A").signer(), sealed, &round, IBFTProtocol)
+	assert.NoError(t, err)
+	assert.Equal(t, localSeal, kmsSeal)
+}
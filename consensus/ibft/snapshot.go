@@ -24,15 +24,25 @@ func (i *Ibft) setupSnapshot() error {
 		if err := i.store.loadFromPath(i.config.Path, i.logger); err != nil {
 			return err
 		}
+
+		i.store.prune(i.getEpochSchedule(), i.snapshotRetention)
 	}
 
 	header := i.blockchain.Header()
+
+	// the snapshots above the head are left behind by a rollback of the chain
+	i.store.deleteHigher(header.Number)
+
 	meta, err := i.getSnapshotMetadata()
 
 	if err != nil {
 		return err
 	}
 
+	if err := i.setupValidatorIndex(header.Number); err != nil {
+		return err
+	}
+
 	if header.Number == 0 {
 		// Add genesis
 		if err := i.addHeaderSnap(header); err != nil {
@@ -45,16 +55,19 @@ func (i *Ibft) setupSnapshot() error {
 	// in order to have all the votes and validators correctly set in the snapshot,
 	// since they reset every epoch.
 
-	// Get epoch of latest header and saved metadata
-	currentEpoch := header.Number / i.epochSize
-	metaEpoch := meta.LastBlock / i.epochSize
+	// Get the beginning of the epoch of latest header and saved metadata
+	epochs := i.getEpochSchedule()
+	beginHeight := epochs.lastEpochBlock(header.Number)
+	metaBeginHeight := epochs.lastEpochBlock(meta.LastBlock)
 	snapshot, _ := i.getSnapshot(header.Number)
 
-	if snapshot == nil || metaEpoch < currentEpoch {
+	if snapshot == nil || metaBeginHeight < beginHeight {
 		// Restore snapshot at the beginning of the current epoch by block header
 		// if list doesn't have any snapshots to calculate snapshot for the next header
-		i.logger.Info("snapshot was not found, restore snapshot at beginning of current epoch", "current epoch", currentEpoch)
-		beginHeight := currentEpoch * i.epochSize
+		i.logger.Info(
+			"snapshot was not found, restore snapshot at beginning of current epoch",
+			"current epoch", epochs.epoch(header.Number),
+		)
 		beginHeader, ok := i.blockchain.GetHeaderByNumber(beginHeight)
 
 		if !ok {
@@ -99,7 +112,7 @@ func (i *Ibft) setupSnapshot() error {
 func (i *Ibft) addHeaderSnap(header *types.Header) error {
 	// Genesis header needs to be set by hand, all the other
 	// snapshots are set as part of processHeaders
-	extra, err := getIbftExtra(header)
+	extra, err := GetIbftExtra(header)
 	if err != nil {
 		return err
 	}
@@ -160,7 +173,7 @@ func (i *Ibft) processHeaders(headers []*types.Header) error {
 	}
 
 	for _, h := range headers {
-		proposer, err := ecrecoverFromHeader(h)
+		proposer, err := i.signers.proposer(h)
 		if err != nil {
 			return err
 		}
@@ -191,6 +204,11 @@ func (i *Ibft) processHeaders(headers []*types.Header) error {
 	// update the metadata
 	i.store.updateLastBlock(headers[len(headers)-1].Number)
 
+	// the validator index only serves the queries, the headers are processed regardless
+	if err := i.indexValidators(headers); err != nil {
+		i.logger.Error("failed to index the validators", "err", err)
+	}
+
 	return nil
 }
 
@@ -392,9 +410,11 @@ func (s *snapshotStore) loadFromPath(path string, l hclog.Logger) error {
 		l.Error("Removed invalid snapshot store file")
 	}
 
-	for _, snap := range snaps {
-		s.add(snap)
-	}
+	// sort the list once, instead of on every insertion
+	s.lock.Lock()
+	s.list = append(s.list, snaps...)
+	sort.Sort(&s.list)
+	s.lock.Unlock()
 
 	return nil
 }
@@ -438,6 +458,74 @@ func (s *snapshotStore) deleteLower(num uint64) {
 	s.list = s.list[i:]
 }
 
+// deleteHigher deletes snapshots that have a block number higher than the passed in parameter,
+// and lowers the latest block number to it
+func (s *snapshotStore) deleteHigher(num uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	i := sort.Search(len(s.list), func(i int) bool {
+		return s.list[i].Number > num
+	})
+	s.list = s.list[:i]
+
+	if s.getLastBlock() > num {
+		s.updateLastBlock(num)
+	}
+}
+
+// prune deletes the snapshots older than the last retention epoch boundary snapshots.
+// Every snapshot from the oldest retained epoch boundary onwards is kept,
+// since the snapshots of the epochs that are still being processed are based on them.
+// A retention of 0 keeps all the snapshots
+func (s *snapshotStore) prune(epochs epochSchedule, retention uint64) {
+	if retention == 0 || len(epochs) == 0 {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var boundaries uint64
+
+	for i := len(s.list) - 1; i >= 0; i-- {
+		if !epochs.isEpochBlock(s.list[i].Number) {
+			continue
+		}
+
+		if boundaries++; boundaries == retention {
+			s.list = s.list[i:]
+
+			return
+		}
+	}
+}
+
+// compact removes the snapshots that are equal to their predecessor,
+// since looking them up returns the same validator set and votes.
+// Epoch boundary snapshots are always kept
+func (s *snapshotStore) compact(epochs epochSchedule) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.list) == 0 {
+		return
+	}
+
+	compacted := snapshotSortedList{s.list[0]}
+
+	for _, snap := range s.list[1:] {
+		isBoundary := len(epochs) != 0 && epochs.isEpochBlock(snap.Number)
+		if !isBoundary && snap.Equal(compacted[len(compacted)-1]) {
+			continue
+		}
+
+		compacted = append(compacted, snap)
+	}
+
+	s.list = compacted
+}
+
 // find returns the index of the first closest snapshot to the number specified
 func (s *snapshotStore) find(num uint64) *Snapshot {
 	s.lock.Lock()
@@ -50,8 +50,12 @@ func (t *testerAccount) Address() types.Address {
 	return crypto.PubKeyToAddress(&t.priv.PublicKey)
 }
 
+func (t *testerAccount) signer() Signer {
+	return NewLocalSigner(t.priv)
+}
+
 func (t *testerAccount) sign(h *types.Header) *types.Header {
-	h, _ = writeSeal(t.priv, h)
+	h, _ = writeSeal(t.signer(), h)
 
 	return h
 }
@@ -536,7 +540,22 @@ func TestSnapshot_ProcessHeaders(t *testing.T) {
 					},
 				},
 				{
-					action: vote("A", "C", true),
+					action: vote("A", "C", false),
+					snapshot: &mockSnapshot{
+						validators: []string{"A", "B", "C"},
+						votes: []mockVote{
+							vote("A", "C", false),
+						},
+					},
+				},
+			},
+		},
+		{
+			name:       "opposite votes retract the votes of the validator",
+			validators: []string{"A", "B", "C"},
+			headers: []mockHeader{
+				{
+					action: vote("A", "C", false),
 					snapshot: &mockSnapshot{
 						validators: []string{"A", "B", "C"},
 						votes: []mockVote{
@@ -544,6 +563,42 @@ func TestSnapshot_ProcessHeaders(t *testing.T) {
 						},
 					},
 				},
+				{
+					action: vote("B", "D", true),
+					snapshot: &mockSnapshot{
+						validators: []string{"A", "B", "C"},
+						votes: []mockVote{
+							vote("A", "C", false),
+							vote("B", "D", true),
+						},
+					},
+				},
+				{
+					// the vote of B is kept
+					action: vote("A", "C", true),
+					snapshot: &mockSnapshot{
+						validators: []string{"A", "B", "C"},
+						votes: []mockVote{
+							vote("B", "D", true),
+						},
+					},
+				},
+				{
+					action: vote("B", "D", false),
+					snapshot: &mockSnapshot{
+						validators: []string{"A", "B", "C"},
+					},
+				},
+				{
+					// the retracted vote doesn't count toward the tally
+					action: vote("C", "D", true),
+					snapshot: &mockSnapshot{
+						validators: []string{"A", "B", "C"},
+						votes: []mockVote{
+							vote("C", "D", true),
+						},
+					},
+				},
 			},
 		},
 		{
@@ -872,3 +927,98 @@ func TestSnapshot_Store_Find(t *testing.T) {
 	check(21, 20)
 	check(1000, 100)
 }
+
+func TestSnapshot_Store_DeleteHigher(t *testing.T) {
+	store := newSnapshotStore()
+
+	for i := 0; i <= 40; i += 10 {
+		store.add(&Snapshot{
+			Number: uint64(i),
+		})
+	}
+
+	store.updateLastBlock(45)
+
+	store.deleteHigher(25)
+	assert.Len(t, store.list, 3)
+	assert.Equal(t, uint64(20), store.find(25).Number)
+	assert.Equal(t, uint64(25), store.getLastBlock())
+
+	// the last block is never raised
+	store.deleteHigher(30)
+	assert.Len(t, store.list, 3)
+	assert.Equal(t, uint64(25), store.getLastBlock())
+}
+
+func TestSnapshot_Store_Prune(t *testing.T) {
+	newStore := func() *snapshotStore {
+		store := newSnapshotStore()
+
+		for i := 0; i <= 45; i += 5 {
+			store.add(&Snapshot{
+				Number: uint64(i),
+			})
+		}
+
+		return store
+	}
+
+	numbers := func(store *snapshotStore) []uint64 {
+		res := []uint64{}
+		for _, snap := range store.list {
+			res = append(res, snap.Number)
+		}
+
+		return res
+	}
+
+	// keep all the snapshots
+	store := newStore()
+	store.prune(epochSchedule{{size: 10}}, 0)
+	assert.Len(t, store.list, 10)
+
+	// keep the last 2 epoch boundaries and everything after them
+	store = newStore()
+	store.prune(epochSchedule{{size: 10}}, 2)
+	assert.Equal(t, []uint64{30, 35, 40, 45}, numbers(store))
+
+	// not enough epoch boundaries to prune
+	store = newStore()
+	store.prune(epochSchedule{{size: 10}}, 20)
+	assert.Len(t, store.list, 10)
+}
+
+func TestSnapshot_Store_Compact(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B")
+
+	store := newSnapshotStore()
+	setA := ValidatorSet{pool.get("A").Address()}
+	setAB := pool.ValidatorSet()
+
+	for num, set := range map[uint64]ValidatorSet{
+		0:  setA,
+		3:  setA,
+		5:  setAB,
+		7:  setAB,
+		10: setAB,
+		12: setAB,
+	} {
+		store.add(&Snapshot{
+			Number: num,
+			Set:    set,
+			Votes:  []*Vote{},
+		})
+	}
+
+	store.compact(epochSchedule{{size: 10}})
+
+	numbers := []uint64{}
+	for _, snap := range store.list {
+		numbers = append(numbers, snap.Number)
+	}
+
+	assert.Equal(t, []uint64{0, 5, 10}, numbers)
+	assert.Equal(t, setAB, store.find(12).Set)
+	assert.Equal(t, setA, store.find(4).Set)
+}
@@ -134,6 +134,11 @@ func (c *currentState) CalcProposer(lastProposer types.Address) {
 	c.proposer = c.validators.CalcProposer(c.view.Round, lastProposer)
 }
 
+// setProposer sets the proposer of the current round
+func (c *currentState) setProposer(proposer types.Address) {
+	c.proposer = proposer
+}
+
 func (c *currentState) lock() {
 	c.locked = true
 }
@@ -0,0 +1,102 @@
+package ibft
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+)
+
+// Status is a read-only copy of the live IBFT consensus state
+type Status struct {
+	// State is the current state of the IBFT state machine
+	State IbftState
+
+	// Sequence and Round are the current view
+	Sequence uint64
+	Round    uint64
+
+	// Proposer is the proposer of the current round
+	Proposer types.Address
+
+	// IsProposer is set if the node is the proposer of the current round
+	IsProposer bool
+
+	// LockedProposal is the hash of the locked proposal, if any
+	LockedProposal *types.Hash
+
+	// LastCommittedRound is the round in which the last block was committed by the node, if any
+	LastCommittedRound *uint64
+}
+
+// publishStatus makes a copy of the current state available to the status readers.
+// It needs to be called from the IBFT loop, which owns the state
+func (i *Ibft) publishStatus() {
+	status := &Status{
+		State:      i.state.getState(),
+		Proposer:   i.state.proposer,
+		IsProposer: i.state.proposer != types.ZeroAddress && i.state.proposer == i.validatorKeyAddr,
+	}
+
+	if view := i.state.view; view != nil {
+		status.Sequence = view.Sequence
+		status.Round = view.Round
+	}
+
+	if i.state.locked && i.state.block != nil {
+		hash := i.state.block.Hash()
+		status.LockedProposal = &hash
+	}
+
+	if i.lastCommittedRound != nil {
+		round := *i.lastCommittedRound
+		status.LastCommittedRound = &round
+	}
+
+	i.status.Store(status)
+}
+
+// GetStatus returns the latest published state of the IBFT consensus
+func (i *Ibft) GetStatus() *Status {
+	status, ok := i.status.Load().(*Status)
+	if !ok {
+		// the consensus is not running yet
+		return &Status{
+			State: SyncState,
+		}
+	}
+
+	return status
+}
+
+// GetValidatorsByBlockNumber returns the validators from the extra data of the block
+func (i *Ibft) GetValidatorsByBlockNumber(number uint64) ([]types.Address, error) {
+	header, ok := i.blockchain.GetHeaderByNumber(number)
+	if !ok {
+		return nil, fmt.Errorf("header at %d not found", number)
+	}
+
+	return unpackValidatorsFromIbftExtra(header)
+}
+
+// GetSnapshotByNumber returns a copy of the snapshot at the block, including the pending votes
+func (i *Ibft) GetSnapshotByNumber(number uint64) (*Snapshot, error) {
+	snap, err := i.getSnapshot(number)
+	if err != nil {
+		return nil, err
+	}
+
+	if snap == nil {
+		return nil, ErrSnapshotNotFound
+	}
+
+	copied := snap.Copy()
+	copied.Number = snap.Number
+	copied.Hash = snap.Hash
+
+	return copied, nil
+}
@@ -0,0 +1,84 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus_Publish(t *testing.T) {
+	i := newMockIbft(t, []string{"A", "B", "C"}, "A")
+
+	// nothing is published before the IBFT loop starts
+	assert.Equal(t, SyncState, i.GetStatus().State)
+
+	block := i.DummyBlock()
+	round := uint64(1)
+
+	i.state.view = proto.ViewMsg(2, 3)
+	i.state.proposer = i.pool.get("A").Address()
+	i.state.block = block
+	i.state.lock()
+	i.lastCommittedRound = &round
+
+	i.setState(ValidateState)
+
+	status := i.GetStatus()
+	assert.Equal(t, ValidateState, status.State)
+	assert.Equal(t, uint64(2), status.Sequence)
+	assert.Equal(t, uint64(3), status.Round)
+	assert.True(t, status.IsProposer)
+
+	hash := block.Hash()
+	assert.Equal(t, &hash, status.LockedProposal)
+	assert.Equal(t, &round, status.LastCommittedRound)
+
+	// the published status is not affected by later changes of the state
+	i.state.view.Round = 4
+	i.state.proposer = i.pool.get("B").Address()
+	i.state.unlock()
+
+	assert.Equal(t, uint64(3), status.Round)
+	assert.Equal(t, uint64(3), i.GetStatus().Round)
+
+	i.publishStatus()
+
+	status = i.GetStatus()
+	assert.Equal(t, uint64(4), status.Round)
+	assert.False(t, status.IsProposer)
+	assert.Nil(t, status.LockedProposal)
+}
+
+func TestStatus_GetValidatorsByBlockNumber(t *testing.T) {
+	i := newMockIbft(t, []string{"A", "B", "C"}, "A")
+
+	validators, err := i.GetValidatorsByBlockNumber(0)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Address(i.pool.ValidatorSet()), validators)
+
+	_, err = i.GetValidatorsByBlockNumber(1)
+	assert.Error(t, err)
+}
+
+func TestStatus_GetSnapshotByNumber(t *testing.T) {
+	i := newMockIbft(t, []string{"A", "B", "C"}, "A")
+
+	snap, err := i.GetSnapshotByNumber(0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), snap.Number)
+	assert.Equal(t, i.pool.ValidatorSet(), snap.Set)
+
+	// the snapshot is a copy of the stored one
+	snap.Set = ValidatorSet{}
+
+	stored, err := i.getSnapshot(0)
+	assert.NoError(t, err)
+	assert.Equal(t, i.pool.ValidatorSet(), stored.Set)
+
+	i.store = newSnapshotStore()
+
+	_, err = i.GetSnapshotByNumber(0)
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+}
@@ -26,3 +26,14 @@ func exponentialTimeout(exponent uint64) time.Duration {
 
 	return timeout
 }
+
+// roundTimeout returns the timeout of the current round.
+// The proposer waits for the block time before proposing, so it is part of the timeout unless disabled in params
+func (i *Ibft) roundTimeout() time.Duration {
+	timeout := exponentialTimeout(i.state.view.Round)
+	if !i.roundTimeoutIncludesBlockTime || timeout == maxTimeout {
+		return timeout
+	}
+
+	return timeout + i.getBlockTime(i.state.view.Sequence)
+}
@@ -1,9 +1,11 @@
 package ibft
 
 import (
-	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
+
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestExponentialTimeout(t *testing.T) {
@@ -28,3 +30,44 @@ func TestExponentialTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestRoundTimeout(t *testing.T) {
+	i := newMockIbft(t, []string{"A", "B", "C"}, "A")
+	i.blockTime = 2 * time.Second
+
+	// the block time is part of the timeout by default
+	i.state.view = proto.ViewMsg(1, 1)
+	assert.Equal(t, (10+2+2)*time.Second, i.roundTimeout())
+
+	// the maximum timeout is not extended
+	i.state.view = proto.ViewMsg(1, 9)
+	assert.Equal(t, maxTimeout, i.roundTimeout())
+
+	// the timeout is only exponential if disabled in params
+	i.config.Config = map[string]interface{}{"roundTimeoutBlockTime": false}
+	assert.NoError(t, i.setupRoundTimeout())
+
+	i.state.view = proto.ViewMsg(1, 1)
+	assert.Equal(t, exponentialTimeout(1), i.roundTimeout())
+}
+
+func TestSetupRoundTimeout(t *testing.T) {
+	i := newMockIbft(t, []string{"A", "B", "C"}, "A")
+
+	// the block time is part of the round timeouts by default
+	i.roundTimeoutIncludesBlockTime = false
+
+	assert.NoError(t, i.setupRoundTimeout())
+	assert.True(t, i.roundTimeoutIncludesBlockTime)
+
+	i.config.Config = map[string]interface{}{"roundTimeoutBlockTime": false}
+	assert.NoError(t, i.setupRoundTimeout())
+	assert.False(t, i.roundTimeoutIncludesBlockTime)
+
+	i.config.Config = map[string]interface{}{"roundTimeoutBlockTime": true}
+	assert.NoError(t, i.setupRoundTimeout())
+	assert.True(t, i.roundTimeoutIncludesBlockTime)
+
+	i.config.Config = map[string]interface{}{"roundTimeoutBlockTime": "true"}
+	assert.Error(t, i.setupRoundTimeout())
+}
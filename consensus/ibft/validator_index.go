@@ -0,0 +1,264 @@
+package ibft
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+var (
+	ErrValidatorsNotIndexed = errors.New("the validators of the block are not indexed")
+)
+
+// validatorSetChange is the validator set of the blocks from the block number,
+// until the next change
+type validatorSetChange struct {
+	From uint64
+	Set  ValidatorSet
+}
+
+// validatorIndexData is the persisted form of the validator index
+type validatorIndexData struct {
+	NextBlock uint64
+	Epochs    map[uint64][]*validatorSetChange
+}
+
+// validatorIndex keeps the validator sets of every epoch, as written in the extra
+// of the headers, so the validators of any block are looked up without the headers.
+// The sets change at the epoch boundaries, and in the middle of the epochs for the PoA votes
+type validatorIndex struct {
+	lock sync.RWMutex
+
+	// nextBlock is the number of the next block to index, the blocks are indexed in order
+	nextBlock uint64
+
+	// epochs are the changes of the validator set in each epoch, in block order
+	epochs map[uint64][]*validatorSetChange
+}
+
+// newValidatorIndex returns a new empty validator index
+func newValidatorIndex() *validatorIndex {
+	return &validatorIndex{
+		epochs: map[uint64][]*validatorSetChange{},
+	}
+}
+
+// loadFromPath loads a saved validator index from the specified file system path
+func (v *validatorIndex) loadFromPath(path string, l hclog.Logger) error {
+	var data *validatorIndexData
+	if err := readDataStore(filepath.Join(path, "validators"), &data); err != nil {
+		// the index is backfilled from the headers if it can't be read
+		l.Error("Could not read validator index file", "err", err.Error())
+		os.Remove(filepath.Join(path, "validators"))
+		l.Error("Removed invalid validator index file")
+	}
+
+	if data == nil || data.Epochs == nil {
+		return nil
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	v.nextBlock = data.NextBlock
+	v.epochs = data.Epochs
+
+	return nil
+}
+
+// saveToPath saves the validator index as a file to the specified path
+func (v *validatorIndex) saveToPath(path string) error {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+
+	return writeDataStore(filepath.Join(path, "validators"), &validatorIndexData{
+		NextBlock: v.nextBlock,
+		Epochs:    v.epochs,
+	})
+}
+
+// next returns the number of the next block to index
+func (v *validatorIndex) next() uint64 {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+
+	return v.nextBlock
+}
+
+// add indexes the validators of the next block, the epoch being the one of the block
+func (v *validatorIndex) add(epoch uint64, number uint64, validators ValidatorSet) error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if number != v.nextBlock {
+		return fmt.Errorf("block %d indexed out of order, expected %d", number, v.nextBlock)
+	}
+
+	changes := v.epochs[epoch]
+	if len(changes) == 0 || !changes[len(changes)-1].Set.Equal(&validators) {
+		v.epochs[epoch] = append(changes, &validatorSetChange{
+			From: number,
+			Set:  append(ValidatorSet{}, validators...),
+		})
+	}
+
+	v.nextBlock++
+
+	return nil
+}
+
+// truncate drops the validators of the blocks from the number, which are left behind
+// by a rollback of the chain, so the new blocks are indexed in their place
+func (v *validatorIndex) truncate(number uint64) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if number >= v.nextBlock {
+		return
+	}
+
+	for epoch, changes := range v.epochs {
+		indx := len(changes)
+		for indx > 0 && changes[indx-1].From >= number {
+			indx--
+		}
+
+		if indx == 0 {
+			delete(v.epochs, epoch)
+		} else {
+			v.epochs[epoch] = changes[:indx]
+		}
+	}
+
+	v.nextBlock = number
+}
+
+// get returns a copy of the validators of the block in the epoch
+func (v *validatorIndex) get(epoch uint64, number uint64) (ValidatorSet, error) {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+
+	if number >= v.nextBlock {
+		return nil, fmt.Errorf("%w: %d", ErrValidatorsNotIndexed, number)
+	}
+
+	changes := v.epochs[epoch]
+	for indx := len(changes) - 1; indx >= 0; indx-- {
+		if changes[indx].From <= number {
+			return append(ValidatorSet{}, changes[indx].Set...), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %d", ErrValidatorsNotIndexed, number)
+}
+
+// GetValidatorsAt returns the validators of the block from the validator index,
+// they are the ones written in the extra of the block
+func (i *Ibft) GetValidatorsAt(height uint64) ([]types.Address, error) {
+	if i.validators == nil {
+		return nil, fmt.Errorf("%w: %d", ErrValidatorsNotIndexed, height)
+	}
+
+	return i.validators.get(i.getEpochSchedule().epoch(height), height)
+}
+
+// indexValidators indexes the validators of the headers, the ones already indexed are skipped.
+// The index is saved once the last block of an epoch is indexed
+func (i *Ibft) indexValidators(headers []*types.Header) error {
+	if i.validators == nil {
+		return nil
+	}
+
+	epochs := i.getEpochSchedule()
+
+	for _, header := range headers {
+		next := i.validators.next()
+		if header.Number < next {
+			continue
+		}
+
+		// the blocks written without being processed, as by the fast sync
+		if header.Number > next {
+			if err := i.backfillValidatorIndex(header.Number - 1); err != nil {
+				return err
+			}
+		}
+
+		validators, err := unpackValidatorsFromIbftExtra(header)
+		if err != nil {
+			return err
+		}
+
+		if err := i.validators.add(epochs.epoch(header.Number), header.Number, validators); err != nil {
+			return err
+		}
+
+		if i.config.Path != "" && epochs.isEpochBlock(header.Number) {
+			if err := i.validators.saveToPath(i.config.Path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// backfillValidatorIndex indexes the validators of the canonical headers up to the block,
+// from the first block that is not indexed yet. It runs once on the chains synced before
+// the index is kept
+func (i *Ibft) backfillValidatorIndex(to uint64) error {
+	from := i.validators.next()
+	if from > to {
+		return nil
+	}
+
+	if to-from > 1 {
+		i.logger.Info("backfilling the validator index", "from", from, "to", to)
+	}
+
+	epochs := i.getEpochSchedule()
+
+	for num := from; num <= to; num++ {
+		header, ok := i.blockchain.GetHeaderByNumber(num)
+		if !ok {
+			return fmt.Errorf("header %d not found", num)
+		}
+
+		validators, err := unpackValidatorsFromIbftExtra(header)
+		if err != nil {
+			return err
+		}
+
+		if err := i.validators.add(epochs.epoch(num), num, validators); err != nil {
+			return err
+		}
+	}
+
+	if i.config.Path != "" {
+		return i.validators.saveToPath(i.config.Path)
+	}
+
+	return nil
+}
+
+// setupValidatorIndex sets up the validator index, and backfills it up to the head
+// for the chains synced before the index is kept
+func (i *Ibft) setupValidatorIndex(head uint64) error {
+	i.validators = newValidatorIndex()
+
+	if i.config.Path != "" {
+		if err := i.validators.loadFromPath(i.config.Path, i.logger); err != nil {
+			return err
+		}
+
+		// the blocks above the head are left behind by a rollback of the chain
+		i.validators.truncate(head + 1)
+	}
+
+	return i.backfillValidatorIndex(head)
+}
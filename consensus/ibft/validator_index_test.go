@@ -0,0 +1,183 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// buildValidatorHeaders builds a chain of headers, each of them with the validators in its extra
+func buildValidatorHeaders(genesis *types.Header, sets ...ValidatorSet) []*types.Header {
+	headers := make([]*types.Header, 0, len(sets))
+	parent := genesis
+
+	for indx, set := range sets {
+		h := &types.Header{
+			Number:     uint64(indx + 1),
+			ParentHash: parent.Hash,
+			MixHash:    IstanbulDigest,
+		}
+		putIbftExtraValidators(h, set)
+		h.ComputeHash()
+
+		parent = h
+		headers = append(headers, h)
+	}
+
+	return headers
+}
+
+func TestValidatorIndex_AddGet(t *testing.T) {
+	a, b, c := types.StringToAddress("a"), types.StringToAddress("b"), types.StringToAddress("c")
+
+	index := newValidatorIndex()
+
+	// epochs of 3 blocks, the set changes in the middle of the second epoch
+	sets := []ValidatorSet{{a}, {a}, {a}, {a, b}, {a, b}, {a, b, c}, {b, c}}
+	for num, set := range sets {
+		assert.NoError(t, index.add(uint64(num)/3, uint64(num), set))
+	}
+
+	// the blocks are indexed in order
+	assert.Error(t, index.add(2, 8, ValidatorSet{a}))
+	assert.Equal(t, uint64(7), index.next())
+
+	assert.Len(t, index.epochs[1], 2)
+
+	for num, set := range sets {
+		validators, err := index.get(uint64(num)/3, uint64(num))
+		assert.NoError(t, err)
+		assert.Equal(t, set, validators)
+	}
+
+	// the returned set is a copy
+	validators, _ := index.get(2, 6)
+	validators[0] = a
+
+	validators, _ = index.get(2, 6)
+	assert.Equal(t, ValidatorSet{b, c}, validators)
+
+	_, err := index.get(2, 7)
+	assert.ErrorIs(t, err, ErrValidatorsNotIndexed)
+}
+
+func TestValidatorIndex_Truncate(t *testing.T) {
+	a, b, c := types.StringToAddress("a"), types.StringToAddress("b"), types.StringToAddress("c")
+
+	index := newValidatorIndex()
+
+	sets := []ValidatorSet{{a}, {a}, {a}, {a, b}, {a, b}, {a, b, c}, {b, c}}
+	for num, set := range sets {
+		assert.NoError(t, index.add(uint64(num)/3, uint64(num), set))
+	}
+
+	// the blocks above the number are not indexed anymore
+	index.truncate(5)
+	assert.Equal(t, uint64(5), index.next())
+	assert.Len(t, index.epochs[1], 1)
+	assert.NotContains(t, index.epochs, uint64(2))
+
+	_, err := index.get(1, 5)
+	assert.ErrorIs(t, err, ErrValidatorsNotIndexed)
+
+	// the other blocks are indexed in their place
+	assert.NoError(t, index.add(1, 5, ValidatorSet{c}))
+
+	validators, err := index.get(1, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, ValidatorSet{c}, validators)
+
+	validators, err = index.get(1, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, ValidatorSet{a, b}, validators)
+
+	// the index is never advanced
+	index.truncate(10)
+	assert.Equal(t, uint64(6), index.next())
+}
+
+func TestValidatorIndex_SaveLoad(t *testing.T) {
+	tmpDir := getTempDir(t)
+
+	index0 := newValidatorIndex()
+	assert.NoError(t, index0.add(0, 0, ValidatorSet{types.StringToAddress("a")}))
+	assert.NoError(t, index0.add(0, 1, ValidatorSet{types.StringToAddress("b")}))
+	assert.NoError(t, index0.saveToPath(tmpDir))
+
+	index1 := newValidatorIndex()
+	assert.NoError(t, index1.loadFromPath(tmpDir, hclog.NewNullLogger()))
+
+	assert.Equal(t, index0.next(), index1.next())
+	assert.Equal(t, index0.epochs, index1.epochs)
+
+	// the missing file leaves the index empty
+	index2 := newValidatorIndex()
+	assert.NoError(t, index2.loadFromPath(getTempDir(t), hclog.NewNullLogger()))
+	assert.Equal(t, uint64(0), index2.next())
+}
+
+func TestIbft_GetValidatorsAt(t *testing.T) {
+	pool := newTesterAccountPool()
+	pool.add("A", "B", "C")
+
+	genesis := pool.genesis()
+	a, b, c := pool.get("A").Address(), pool.get("B").Address(), pool.get("C").Address()
+
+	chain := blockchain.TestBlockchain(t, genesis)
+	headers := buildValidatorHeaders(
+		chain.Header(),
+		ValidatorSet{a, b, c},
+		ValidatorSet{a, b},
+		ValidatorSet{a, b},
+		ValidatorSet{b},
+		ValidatorSet{b},
+	)
+
+	tmpDir := getTempDir(t)
+	newIbft := func() *Ibft {
+		return &Ibft{
+			epochSize:  2,
+			blockchain: chain,
+			config: &consensus.Config{
+				Path: tmpDir,
+			},
+			logger: hclog.NewNullLogger(),
+		}
+	}
+
+	// the chain synced before the index is kept is backfilled on the startup
+	assert.NoError(t, chain.WriteHeaders(headers[:3]))
+
+	ibft := newIbft()
+	assert.NoError(t, ibft.setupValidatorIndex(chain.Header().Number))
+
+	for num, set := range []ValidatorSet{{a, b, c}, {a, b, c}, {a, b}, {a, b}} {
+		validators, err := ibft.GetValidatorsAt(uint64(num))
+		assert.NoError(t, err)
+		assert.Equal(t, []types.Address(set), validators)
+	}
+
+	_, err := ibft.GetValidatorsAt(4)
+	assert.ErrorIs(t, err, ErrValidatorsNotIndexed)
+
+	// the written headers are indexed, along with the ones written before without being processed
+	assert.NoError(t, chain.WriteHeaders(headers[3:]))
+	assert.NoError(t, ibft.indexValidators(headers[4:]))
+
+	validators, err := ibft.GetValidatorsAt(5)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Address{b}, validators)
+
+	// the index is saved, and loaded on the next startup
+	ibft = newIbft()
+	assert.NoError(t, ibft.setupValidatorIndex(chain.Header().Number))
+	assert.Equal(t, uint64(6), ibft.validators.next())
+
+	validators, err = ibft.GetValidatorsAt(3)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Address{a, b}, validators)
+}
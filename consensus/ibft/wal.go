@@ -0,0 +1,259 @@
+package ibft
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	protobuf "google.golang.org/protobuf/proto"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+)
+
+var (
+	errConflictingMsg = errors.New("conflicting with a message already sent for the same height and round")
+)
+
+// walKeyLength is the length of the WAL keys, height (8 bytes) . round (8 bytes) . type (1 byte)
+const walKeyLength = 17
+
+// msgWAL is the write-ahead log of the consensus messages sent by the node.
+// The messages are written before they are gossiped, so a restarted node knows what it
+// already sent, and doesn't send conflicting messages for the same height and round.
+// The messages of a height are kept until the height is finalized
+type msgWAL struct {
+	db *leveldb.DB
+}
+
+// newMsgWAL opens the write-ahead log in the passed in directory
+func newMsgWAL(path string) (*msgWAL, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &msgWAL{db: db}, nil
+}
+
+// walHeightPrefix returns the key prefix of all the messages of the height
+func walHeightPrefix(height uint64) []byte {
+	prefix := make([]byte, 8)
+	binary.BigEndian.PutUint64(prefix, height)
+
+	return prefix
+}
+
+// walKey returns the key of the message, (height, round, type)
+func walKey(msg *proto.MessageReq) []byte {
+	key := make([]byte, walKeyLength)
+	binary.BigEndian.PutUint64(key[0:8], msg.View.Sequence)
+	binary.BigEndian.PutUint64(key[8:16], msg.View.Round)
+	key[16] = byte(msg.Type)
+
+	return key
+}
+
+// write persists the message, along with the proposal it refers to.
+// The proposal is only kept in the log, it is not part of the gossiped message
+func (w *msgWAL) write(msg *proto.MessageReq, proposal *types.Block) error {
+	entry := msg.Copy()
+
+	if proposal != nil {
+		entry.Proposal = &anypb.Any{
+			Value: proposal.MarshalRLP(),
+		}
+	}
+
+	data, err := protobuf.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return w.db.Put(walKey(msg), data, &opt.WriteOptions{Sync: true})
+}
+
+// messages returns the messages sent for the height, in increasing round order
+func (w *msgWAL) messages(height uint64) ([]*proto.MessageReq, error) {
+	iter := w.db.NewIterator(util.BytesPrefix(walHeightPrefix(height)), nil)
+	defer iter.Release()
+
+	msgs := []*proto.MessageReq{}
+
+	for iter.Next() {
+		msg := &proto.MessageReq{}
+		if err := protobuf.Unmarshal(iter.Value(), msg); err != nil {
+			return nil, err
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+
+	return msgs, nil
+}
+
+// truncate removes the messages of all the heights up to the finalized height
+func (w *msgWAL) truncate(finalized uint64) error {
+	iter := w.db.NewIterator(&util.Range{Limit: walHeightPrefix(finalized + 1)}, nil)
+	defer iter.Release()
+
+	batch := new(leveldb.Batch)
+
+	for iter.Next() {
+		batch.Delete(append([]byte{}, iter.Key()...))
+	}
+
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	return w.db.Write(batch, nil)
+}
+
+// close closes the write-ahead log
+func (w *msgWAL) close() error {
+	return w.db.Close()
+}
+
+// walProposalHash returns the hash of the proposal logged with the message, if any
+func walProposalHash(msg *proto.MessageReq) (types.Hash, bool, error) {
+	if msg.Proposal == nil {
+		return types.ZeroHash, false, nil
+	}
+
+	block := &types.Block{}
+	if err := block.UnmarshalRLP(msg.Proposal.Value); err != nil {
+		return types.ZeroHash, false, err
+	}
+
+	return block.Hash(), true, nil
+}
+
+// setupWAL opens the write-ahead log of the sent messages.
+// The log is kept in the consensus data directory, unless another directory is configured
+func (i *Ibft) setupWAL() error {
+	path := i.walDir
+	if path == "" {
+		if i.config.Path == "" {
+			// no data directory, the node doesn't keep the sent messages
+			return nil
+		}
+
+		path = filepath.Join(i.config.Path, "wal")
+	}
+
+	wal, err := newMsgWAL(path)
+	if err != nil {
+		return fmt.Errorf("unable to open the IBFT message log, %w", err)
+	}
+
+	i.wal = wal
+
+	return nil
+}
+
+// isConflictingMsg checks if the node already sent messages for another proposal
+// in the same height and round as the passed in view
+func (i *Ibft) isConflictingMsg(view *proto.View, block *types.Block) (bool, error) {
+	msgs, err := i.wal.messages(view.Sequence)
+	if err != nil {
+		return false, err
+	}
+
+	for _, msg := range msgs {
+		if msg.View.Round != view.Round {
+			continue
+		}
+
+		hash, ok, err := walProposalHash(msg)
+		if err != nil {
+			return false, err
+		}
+
+		if ok && hash != block.Hash() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// restoreFromWAL restores the round and the lock of the ongoing height
+// from the messages the node sent before it was stopped
+func (i *Ibft) restoreFromWAL() {
+	if i.wal == nil {
+		return
+	}
+
+	header := i.blockchain.Header()
+
+	// the messages of the finalized heights are not needed anymore
+	if err := i.wal.truncate(header.Number); err != nil {
+		i.logger.Error("failed to truncate the message log", "err", err)
+	}
+
+	if i.state.view == nil || i.state.view.Sequence != header.Number+1 {
+		return
+	}
+
+	msgs, err := i.wal.messages(i.state.view.Sequence)
+	if err != nil {
+		i.logger.Error("failed to read the message log", "err", err)
+
+		return
+	}
+
+	if len(msgs) == 0 {
+		return
+	}
+
+	var (
+		round  uint64
+		locked *types.Block
+	)
+
+	for _, msg := range msgs {
+		if msg.View.Round > round {
+			round = msg.View.Round
+		}
+
+		if msg.Type != proto.MessageReq_Commit || msg.Proposal == nil {
+			continue
+		}
+
+		// the node committed to the proposal in this round, it stays locked on it.
+		// The messages are in increasing round order, the commit of the latest round is the lock
+		block := &types.Block{}
+		if err := block.UnmarshalRLP(msg.Proposal.Value); err != nil {
+			i.logger.Error("failed to unmarshal the logged proposal", "err", err)
+
+			continue
+		}
+
+		locked = block
+	}
+
+	if round > i.state.view.Round {
+		i.state.view.Round = round
+	}
+
+	if locked != nil && locked.Number() == i.state.view.Sequence {
+		i.state.block = locked
+		i.state.lock()
+	}
+
+	i.logger.Info(
+		"restored from the message log",
+		"sequence", i.state.view.Sequence,
+		"round", i.state.view.Round,
+		"locked", i.state.locked,
+	)
+}
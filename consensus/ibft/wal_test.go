@@ -0,0 +1,218 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+)
+
+// newWALMockIbft creates a mock IBFT logging its sent messages in the passed in directory
+func newWALMockIbft(t *testing.T, dir string, account string) *mockIbft {
+	t.Helper()
+
+	i := newMockIbft(t, []string{"A", "B", "C"}, account)
+
+	wal, err := newMsgWAL(dir)
+	assert.NoError(t, err)
+
+	i.wal = wal
+
+	return i
+}
+
+// sealedBlock returns a dummy block proposed by A
+func sealedBlock(t *testing.T, i *mockIbft, timestamp uint64) *types.Block {
+	t.Helper()
+
+	block := i.DummyBlock()
+	block.Header.Timestamp = timestamp
+
+	header, err := writeSeal(i.pool.get("A").signer(), block.Header)
+	assert.NoError(t, err)
+
+	block.Header = header
+	block.Header.ComputeHash()
+
+	return block
+}
+
+func TestWAL_RestartDoesNotSendConflictingMsgs(t *testing.T) {
+	dir := t.TempDir()
+
+	i := newWALMockIbft(t, dir, "B")
+	i.state.view = proto.ViewMsg(1, 0)
+	i.setState(AcceptState)
+
+	i.emitMsg(&proto.MessageReq{
+		From: "A",
+		Type: proto.MessageReq_Preprepare,
+		Proposal: &anypb.Any{
+			Value: sealedBlock(t, i, 0).MarshalRLP(),
+		},
+		View: proto.ViewMsg(1, 0),
+	})
+
+	i.runCycle()
+
+	i.expect(expectResult{
+		sequence: 1,
+		state:    ValidateState,
+		outgoing: 1, // prepare
+	})
+
+	// the node crashes after preparing the proposal
+	assert.NoError(t, i.wal.close())
+
+	i = newWALMockIbft(t, dir, "B")
+	i.state.view = proto.ViewMsg(1, 0)
+	i.restoreFromWAL()
+	i.setState(AcceptState)
+
+	// the proposer equivocates with another proposal in the same round
+	i.emitMsg(&proto.MessageReq{
+		From: "A",
+		Type: proto.MessageReq_Preprepare,
+		Proposal: &anypb.Any{
+			Value: sealedBlock(t, i, 1).MarshalRLP(),
+		},
+		View: proto.ViewMsg(1, 0),
+	})
+
+	i.runCycle()
+
+	// neither a prepare nor a commit is sent for the other proposal
+	i.expect(expectResult{
+		sequence: 1,
+		state:    ValidateState,
+		outgoing: 0,
+	})
+
+	i.sendCommitMsg()
+	assert.Len(t, i.respMsg, 0)
+
+	// round changes do not refer to a proposal
+	i.gossip(proto.MessageReq_RoundChange)
+	assert.Len(t, i.respMsg, 1)
+}
+
+func TestWAL_RestoreLock(t *testing.T) {
+	dir := t.TempDir()
+
+	i := newWALMockIbft(t, dir, "B")
+	block := sealedBlock(t, i, 0)
+
+	i.state.view = proto.ViewMsg(1, 2)
+	i.state.block = block
+	i.state.lock()
+
+	i.sendCommitMsg()
+	assert.Len(t, i.respMsg, 1)
+
+	// the node crashes after committing to the proposal
+	assert.NoError(t, i.wal.close())
+
+	i = newWALMockIbft(t, dir, "B")
+	i.state.view = proto.ViewMsg(1, 0)
+	i.restoreFromWAL()
+
+	assert.Equal(t, uint64(2), i.state.view.Round)
+	assert.True(t, i.state.locked)
+	assert.Equal(t, block.Hash(), i.state.block.Hash())
+}
+
+func TestWAL_RestoreLock_RoundChange(t *testing.T) {
+	dir := t.TempDir()
+
+	i := newWALMockIbft(t, dir, "B")
+	block := sealedBlock(t, i, 0)
+
+	// the node commits to the proposal in the round 0
+	i.state.view = proto.ViewMsg(1, 0)
+	i.state.block = block
+	i.state.lock()
+
+	i.sendCommitMsg()
+
+	// the round 0 times out, the node moves to the round 1 still locked
+	i.state.view = proto.ViewMsg(1, 1)
+	i.gossip(proto.MessageReq_RoundChange)
+	assert.Len(t, i.respMsg, 2)
+
+	assert.NoError(t, i.wal.close())
+
+	i = newWALMockIbft(t, dir, "B")
+	i.state.view = proto.ViewMsg(1, 0)
+	i.restoreFromWAL()
+
+	assert.Equal(t, uint64(1), i.state.view.Round)
+	assert.True(t, i.state.locked)
+	assert.Equal(t, block.Hash(), i.state.block.Hash())
+}
+
+func TestWAL_RestoreLock_LatestRound(t *testing.T) {
+	dir := t.TempDir()
+
+	i := newWALMockIbft(t, dir, "B")
+
+	// the node commits to a proposal in the round 0, and to another one in the round 2
+	i.state.view = proto.ViewMsg(1, 0)
+	i.state.block = sealedBlock(t, i, 0)
+	i.state.lock()
+	i.sendCommitMsg()
+
+	block := sealedBlock(t, i, 1)
+
+	i.state.view = proto.ViewMsg(1, 2)
+	i.state.block = block
+	i.sendCommitMsg()
+	assert.Len(t, i.respMsg, 2)
+
+	assert.NoError(t, i.wal.close())
+
+	i = newWALMockIbft(t, dir, "B")
+	i.state.view = proto.ViewMsg(1, 0)
+	i.restoreFromWAL()
+
+	assert.Equal(t, uint64(2), i.state.view.Round)
+	assert.True(t, i.state.locked)
+	assert.Equal(t, block.Hash(), i.state.block.Hash())
+}
+
+func TestWAL_WriteBeforeDelivery(t *testing.T) {
+	i := newWALMockIbft(t, t.TempDir(), "B")
+	i.state.view = proto.ViewMsg(1, 0)
+
+	// the message can't be logged
+	assert.NoError(t, i.wal.close())
+
+	i.gossip(proto.MessageReq_RoundChange)
+
+	// it is neither gossiped nor delivered to the node itself
+	assert.Len(t, i.respMsg, 0)
+	assert.Nil(t, i.msgQueue.readMessage(RoundChangeState, proto.ViewMsg(1, 0)))
+}
+
+func TestMsgWAL_Truncate(t *testing.T) {
+	wal, err := newMsgWAL(t.TempDir())
+	assert.NoError(t, err)
+
+	defer wal.close()
+
+	for height := uint64(1); height <= 3; height++ {
+		assert.NoError(t, wal.write(&proto.MessageReq{
+			Type: proto.MessageReq_RoundChange,
+			View: proto.ViewMsg(height, 0),
+		}, nil))
+	}
+
+	assert.NoError(t, wal.truncate(2))
+
+	for height, count := range map[uint64]int{1: 0, 2: 0, 3: 1} {
+		msgs, err := wal.messages(height)
+		assert.NoError(t, err)
+		assert.Len(t, msgs, count, "messages of height %d", height)
+	}
+}
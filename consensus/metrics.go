@@ -18,6 +18,28 @@ type Metrics struct {
 
 	//Time between current block and the previous block in seconds
 	BlockInterval metrics.Gauge
+
+	// No.of consensus messages dropped because they were already seen
+	DroppedDuplicateMsgs metrics.Counter
+	// No.of consensus messages dropped because the sender went over the rate limit
+	DroppedRateLimitedMsgs metrics.Counter
+
+	// Time spent per round in seconds
+	RoundDuration metrics.Histogram
+	// Round in which the last block was committed
+	CommittedRound metrics.Gauge
+	// No.of committed seals in the last block
+	CommittedSeals metrics.Gauge
+
+	// No.of blocks proposed, labeled with the validator
+	ProposedBlocks metrics.Counter
+	// Height of the last block with a committed seal, labeled with the validator
+	ValidatorLastSeal metrics.Gauge
+
+	// No.of blocks whose signers were found in the signer cache
+	SignerCacheHits metrics.Counter
+	// No.of blocks whose signers were recovered from the seals
+	SignerCacheMisses metrics.Counter
 }
 
 // GetPrometheusMetrics return the consensus metrics instance
@@ -28,6 +50,8 @@ func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics
 		labels = append(labels, labelsWithValues[i])
 	}
 
+	validatorLabels := append(append([]string{}, labels...), "validator")
+
 	return &Metrics{
 		Validators: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
 			Namespace: namespace,
@@ -54,6 +78,61 @@ func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics
 			Name:      "block_interval",
 			Help:      "Time between current block and the previous block in seconds.",
 		}, labels).With(labelsWithValues...),
+		DroppedDuplicateMsgs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consensus",
+			Name:      "dropped_duplicate_msgs",
+			Help:      "Number of consensus messages dropped as duplicates.",
+		}, labels).With(labelsWithValues...),
+		DroppedRateLimitedMsgs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consensus",
+			Name:      "dropped_rate_limited_msgs",
+			Help:      "Number of consensus messages dropped by the sender rate limit.",
+		}, labels).With(labelsWithValues...),
+		RoundDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "consensus",
+			Name:      "round_duration",
+			Help:      "Time spent per round in seconds.",
+			Buckets:   []float64{0.25, 0.5, 1, 2, 4, 8, 16, 32, 64},
+		}, labels).With(labelsWithValues...),
+		CommittedRound: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "consensus",
+			Name:      "committed_round",
+			Help:      "Round in which the last block was committed.",
+		}, labels).With(labelsWithValues...),
+		CommittedSeals: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "consensus",
+			Name:      "committed_seals",
+			Help:      "Number of committed seals in the last block.",
+		}, labels).With(labelsWithValues...),
+		ProposedBlocks: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consensus",
+			Name:      "proposed_blocks",
+			Help:      "Number of blocks proposed by the validator.",
+		}, validatorLabels).With(labelsWithValues...),
+		ValidatorLastSeal: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "consensus",
+			Name:      "validator_last_seal",
+			Help:      "Height of the last block with a committed seal of the validator.",
+		}, validatorLabels).With(labelsWithValues...),
+		SignerCacheHits: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consensus",
+			Name:      "signer_cache_hits",
+			Help:      "Number of blocks whose signers were found in the signer cache.",
+		}, labels).With(labelsWithValues...),
+		SignerCacheMisses: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consensus",
+			Name:      "signer_cache_misses",
+			Help:      "Number of blocks whose signers were recovered from the seals.",
+		}, labels).With(labelsWithValues...),
 	}
 }
 
@@ -64,5 +143,18 @@ func NilMetrics() *Metrics {
 		Rounds:        discard.NewGauge(),
 		NumTxs:        discard.NewGauge(),
 		BlockInterval: discard.NewGauge(),
+
+		DroppedDuplicateMsgs:   discard.NewCounter(),
+		DroppedRateLimitedMsgs: discard.NewCounter(),
+
+		RoundDuration:  discard.NewHistogram(),
+		CommittedRound: discard.NewGauge(),
+		CommittedSeals: discard.NewGauge(),
+
+		ProposedBlocks:    discard.NewCounter(),
+		ValidatorLastSeal: discard.NewGauge(),
+
+		SignerCacheHits:   discard.NewCounter(),
+		SignerCacheMisses: discard.NewCounter(),
 	}
 }
@@ -29,6 +29,9 @@ func BuildBlock(params BuildBlockParams) *types.Block {
 		header.ReceiptsRoot = buildroot.CalculateReceiptsRoot(params.Receipts)
 	}
 
+	// the bloom of the block logs, it lets the log queries skip the block
+	header.LogsBloom = types.CreateBloom(params.Receipts)
+
 	// TODO: Compute uncles
 	header.Sha3Uncles = types.EmptyUncleHash
 	header.ComputeHash()
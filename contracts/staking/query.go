@@ -17,6 +17,9 @@ var (
 
 	// Gas limit used when querying the validator set
 	queryGasLimit uint64 = 100000
+
+	// validatorSetType is the return type of the validator set methods of the contracts
+	validatorSetType = abi.MustNewType("tuple(address[])")
 )
 
 func DecodeValidators(method *abi.Method, returnValue []byte) ([]types.Address, error) {
@@ -76,3 +79,103 @@ func QueryValidators(t TxQueryHandler, from types.Address) ([]types.Address, err
 
 	return DecodeValidators(method, res.ReturnValue)
 }
+
+// QueryContractValidators calls the method of the contract with the selector,
+// which returns the validator set as an address array
+func QueryContractValidators(
+	t TxQueryHandler,
+	from types.Address,
+	contract types.Address,
+	selector []byte,
+) ([]types.Address, error) {
+	res, err := t.Apply(&types.Transaction{
+		From:     from,
+		To:       &contract,
+		Value:    big.NewInt(0),
+		Input:    selector,
+		GasPrice: big.NewInt(0),
+		Gas:      queryGasLimit,
+		Nonce:    t.GetNonce(from),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Failed() {
+		return nil, res.Err
+	}
+
+	decodedResults, err := abi.Decode(validatorSetType, res.ReturnValue)
+	if err != nil {
+		return nil, err
+	}
+
+	results, ok := decodedResults.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("failed type assertion from decodedResults to map")
+	}
+
+	web3Addresses, ok := results["0"].([]web3.Address)
+	if !ok {
+		return nil, errors.New("failed type assertion from results[0] to []web3.Address")
+	}
+
+	addresses := make([]types.Address, len(web3Addresses))
+	for idx, waddr := range web3Addresses {
+		addresses[idx] = types.Address(waddr)
+	}
+
+	return addresses, nil
+}
+
+func DecodeAccountStake(method *abi.Method, returnValue []byte) (*big.Int, error) {
+	decodedResults, err := method.Outputs.Decode(returnValue)
+	if err != nil {
+		return nil, err
+	}
+
+	results, ok := decodedResults.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("failed type assertion from decodedResults to map")
+	}
+
+	stake, ok := results["0"].(*big.Int)
+	if !ok {
+		return nil, errors.New("failed type assertion from results[0] to *big.Int")
+	}
+
+	return stake, nil
+}
+
+func QueryAccountStake(t TxQueryHandler, from types.Address, account types.Address) (*big.Int, error) {
+	method, ok := abis.StakingABI.Methods["accountStake"]
+	if !ok {
+		return nil, errors.New("accountStake method doesn't exist in Staking contract ABI")
+	}
+
+	input, err := method.Encode([]interface{}{web3.Address(account)})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := t.Apply(&types.Transaction{
+		From:     from,
+		To:       &AddrStakingContract,
+		Value:    big.NewInt(0),
+		Input:    input,
+		GasPrice: big.NewInt(0),
+		Gas:      queryGasLimit,
+		Nonce:    t.GetNonce(from),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Failed() {
+		return nil, res.Err
+	}
+
+	return DecodeAccountStake(method, res.ReturnValue)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/state/runtime"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/go-web3"
 )
 
 var (
@@ -220,3 +221,71 @@ func TestQueryValidators(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryContractValidators(t *testing.T) {
+	contract := types.StringToAddress("1000")
+	selector := []byte{0xca, 0x1e, 0x78, 0x14}
+
+	tx := &types.Transaction{
+		From:     addr1,
+		To:       &contract,
+		Value:    big.NewInt(0),
+		Input:    selector,
+		GasPrice: big.NewInt(0),
+		Gas:      queryGasLimit,
+		Nonce:    10,
+	}
+
+	mock := &TxMock{
+		hashToRes: map[types.Hash]*runtime.ExecutionResult{
+			tx.ComputeHash().Hash: {
+				ReturnValue: appendAll(
+					leftPad([]byte{0x20}, 32), // Offset of the beginning of array
+					leftPad([]byte{0x02}, 32), // Number of addresses
+					leftPad(addr1.Bytes(), 32),
+					leftPad(addr2.Bytes(), 32),
+				),
+			},
+		},
+		nonce: map[types.Address]uint64{
+			addr1: 10,
+		},
+	}
+
+	validators, err := QueryContractValidators(mock, addr1, contract, selector)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Address{addr1, addr2}, validators)
+}
+
+func TestQueryAccountStake(t *testing.T) {
+	method := abis.StakingABI.Methods["accountStake"]
+	assert.NotNil(t, method)
+
+	input, err := method.Encode([]interface{}{web3.Address(addr2)})
+	assert.NoError(t, err)
+
+	tx := &types.Transaction{
+		From:     addr1,
+		To:       &AddrStakingContract,
+		Value:    big.NewInt(0),
+		Input:    input,
+		GasPrice: big.NewInt(0),
+		Gas:      queryGasLimit,
+		Nonce:    10,
+	}
+
+	mock := &TxMock{
+		hashToRes: map[types.Hash]*runtime.ExecutionResult{
+			tx.ComputeHash().Hash: {
+				ReturnValue: leftPad([]byte{0x0a}, 32),
+			},
+		},
+		nonce: map[types.Address]uint64{
+			addr1: 10,
+		},
+	}
+
+	stake, err := QueryAccountStake(mock, addr1, addr2)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(10), stake)
+}
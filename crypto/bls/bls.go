@@ -0,0 +1,242 @@
+package bls
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/0xPolygon/polygon-edge/secrets"
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// The keys and the signatures follow the minimal public key size variant of the BLS signatures:
+// the public keys are points of G1, and the signatures are points of G2 hashed to with the
+// domain of the proof of possession scheme
+const (
+	SecretKeySize = 32
+	PublicKeySize = 48
+	SignatureSize = 96
+)
+
+var (
+	// domain is the domain separation tag of the hashes of the signed messages to G2
+	domain = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+	// popDomain is the domain separation tag of the proofs of possession, so that
+	// no signed message can be passed off as the proof of possession of a key
+	popDomain = []byte("BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+)
+
+var (
+	ErrInvalidSecretKey = errors.New("invalid BLS secret key")
+	ErrInvalidPublicKey = errors.New("invalid BLS public key")
+	ErrInvalidSignature = errors.New("invalid BLS signature")
+	ErrNoSignatures     = errors.New("no BLS signatures to aggregate")
+)
+
+// SecretKey is a BLS secret key, a non zero scalar of the group order
+type SecretKey struct {
+	s *big.Int
+}
+
+// PublicKey is a BLS public key, a point of G1
+type PublicKey struct {
+	p *bls12381.PointG1
+}
+
+// Signature is a BLS signature, or the aggregation of many of them, a point of G2
+type Signature struct {
+	p *bls12381.PointG2
+}
+
+// GenerateKey generates a random secret key
+func GenerateKey() (*SecretKey, error) {
+	order := bls12381.NewG1().Q()
+
+	for {
+		s, err := rand.Int(rand.Reader, order)
+		if err != nil {
+			return nil, err
+		}
+
+		if s.Sign() != 0 {
+			return &SecretKey{s: s}, nil
+		}
+	}
+}
+
+// UnmarshalSecretKey decodes the big endian encoding of the secret key
+func UnmarshalSecretKey(buf []byte) (*SecretKey, error) {
+	if len(buf) != SecretKeySize {
+		return nil, ErrInvalidSecretKey
+	}
+
+	s := new(big.Int).SetBytes(buf)
+	if s.Sign() == 0 || s.Cmp(bls12381.NewG1().Q()) >= 0 {
+		return nil, ErrInvalidSecretKey
+	}
+
+	return &SecretKey{s: s}, nil
+}
+
+// Marshal returns the big endian encoding of the secret key
+func (k *SecretKey) Marshal() []byte {
+	buf := make([]byte, SecretKeySize)
+
+	return k.s.FillBytes(buf)
+}
+
+// GenerateAndEncodeKey returns a newly generated secret key and its hex encoding, as kept by the secrets manager
+func GenerateAndEncodeKey() (*SecretKey, []byte, error) {
+	key, err := GenerateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, []byte(hex.EncodeToString(key.Marshal())), nil
+}
+
+// ReadValidatorKey reads the BLS secret key of the validator from the secrets manager
+func ReadValidatorKey(manager secrets.SecretsManager) (*SecretKey, error) {
+	encoded, err := manager.GetSecret(secrets.ValidatorBLSKey)
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := hex.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return nil, err
+	}
+
+	return UnmarshalSecretKey(buf)
+}
+
+// PublicKey returns the public key of the secret key
+func (k *SecretKey) PublicKey() *PublicKey {
+	g1 := bls12381.NewG1()
+
+	return &PublicKey{p: g1.MulScalarBig(g1.New(), g1.One(), k.s)}
+}
+
+// Sign signs the message, hashed to G2
+func (k *SecretKey) Sign(msg []byte) (*Signature, error) {
+	return k.sign(msg, domain)
+}
+
+// ProvePossession signs the public key of the secret key with the domain of the proofs of possession
+func (k *SecretKey) ProvePossession() (*Signature, error) {
+	return k.sign(k.PublicKey().Marshal(), popDomain)
+}
+
+// sign signs the message, hashed to G2 with the given domain
+func (k *SecretKey) sign(msg, dst []byte) (*Signature, error) {
+	g2 := bls12381.NewG2()
+
+	h, err := g2.HashToCurve(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signature{p: g2.MulScalarBig(h, h, k.s)}, nil
+}
+
+// UnmarshalPublicKey decodes the compressed public key.
+// The public key must be in the subgroup of G1, and not the point at infinity
+func UnmarshalPublicKey(buf []byte) (*PublicKey, error) {
+	g1 := bls12381.NewG1()
+
+	p, err := g1.FromCompressed(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrInvalidPublicKey, err)
+	}
+
+	if g1.IsZero(p) {
+		return nil, ErrInvalidPublicKey
+	}
+
+	return &PublicKey{p: p}, nil
+}
+
+// Marshal returns the compressed encoding of the public key
+func (k *PublicKey) Marshal() []byte {
+	return bls12381.NewG1().ToCompressed(k.p)
+}
+
+// UnmarshalSignature decodes the compressed signature.
+// The signature must be in the subgroup of G2
+func UnmarshalSignature(buf []byte) (*Signature, error) {
+	p, err := bls12381.NewG2().FromCompressed(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrInvalidSignature, err)
+	}
+
+	return &Signature{p: p}, nil
+}
+
+// Marshal returns the compressed encoding of the signature
+func (s *Signature) Marshal() []byte {
+	return bls12381.NewG2().ToCompressed(s.p)
+}
+
+// AggregateSignatures aggregates the signatures into a single one
+func AggregateSignatures(sigs []*Signature) (*Signature, error) {
+	if len(sigs) == 0 {
+		return nil, ErrNoSignatures
+	}
+
+	g2 := bls12381.NewG2()
+	aggregated := g2.Zero()
+
+	for _, sig := range sigs {
+		g2.Add(aggregated, aggregated, sig.p)
+	}
+
+	return &Signature{p: aggregated}, nil
+}
+
+// Verify checks the signature is the signature of the message by the public key
+func (s *Signature) Verify(pub *PublicKey, msg []byte) bool {
+	return s.verify(pub.p, msg, domain)
+}
+
+// VerifyPossession checks the signature is the proof of possession of the secret key of the public key
+func (s *Signature) VerifyPossession(pub *PublicKey) bool {
+	return s.verify(pub.p, pub.Marshal(), popDomain)
+}
+
+// VerifyAggregated checks the signature is the aggregation of the signatures of the message
+// by all the public keys. Each public key must have its proof of possession verified beforehand,
+// otherwise a rogue public key derived from the other ones could forge the aggregated signature alone
+func (s *Signature) VerifyAggregated(pubs []*PublicKey, msg []byte) bool {
+	if len(pubs) == 0 {
+		return false
+	}
+
+	g1 := bls12381.NewG1()
+	aggregated := g1.Zero()
+
+	for _, pub := range pubs {
+		g1.Add(aggregated, aggregated, pub.p)
+	}
+
+	return s.verify(aggregated, msg, domain)
+}
+
+// verify checks the signature is the signature of the message, hashed to G2 with the given domain,
+// by the public key point
+func (s *Signature) verify(pub *bls12381.PointG1, msg, dst []byte) bool {
+	h, err := bls12381.NewG2().HashToCurve(msg, dst)
+	if err != nil {
+		return false
+	}
+
+	// e(pk, H(m)) == e(g1, sig)
+	engine := bls12381.NewEngine()
+	engine.AddPair(pub, h)
+	engine.AddPairInv(bls12381.NewG1().One(), s.p)
+
+	return engine.Check()
+}
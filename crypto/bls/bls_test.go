@@ -0,0 +1,160 @@
+package bls
+
+import (
+	"testing"
+
+	bls12381 "github.com/kilic/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+func generateKeys(t *testing.T, num int) []*SecretKey {
+	t.Helper()
+
+	keys := make([]*SecretKey, num)
+
+	for i := range keys {
+		key, err := GenerateKey()
+		assert.NoError(t, err)
+
+		keys[i] = key
+	}
+
+	return keys
+}
+
+func TestBLS_SignAndVerify(t *testing.T) {
+	key := generateKeys(t, 1)[0]
+	msg := []byte("commit")
+
+	sig, err := key.Sign(msg)
+	assert.NoError(t, err)
+
+	assert.True(t, sig.Verify(key.PublicKey(), msg))
+	assert.False(t, sig.Verify(key.PublicKey(), []byte("other")))
+
+	// the signature of another key doesn't verify
+	other := generateKeys(t, 1)[0]
+	assert.False(t, sig.Verify(other.PublicKey(), msg))
+}
+
+func TestBLS_VerifyAggregated(t *testing.T) {
+	keys := generateKeys(t, 4)
+	msg := []byte("commit")
+
+	sigs := make([]*Signature, len(keys))
+	pubs := make([]*PublicKey, len(keys))
+
+	for i, key := range keys {
+		sig, err := key.Sign(msg)
+		assert.NoError(t, err)
+
+		sigs[i] = sig
+		pubs[i] = key.PublicKey()
+	}
+
+	aggregated, err := AggregateSignatures(sigs)
+	assert.NoError(t, err)
+
+	assert.True(t, aggregated.VerifyAggregated(pubs, msg))
+
+	// all the signers have to be part of the public keys
+	assert.False(t, aggregated.VerifyAggregated(pubs[:3], msg))
+	assert.False(t, aggregated.VerifyAggregated(nil, msg))
+	assert.False(t, aggregated.VerifyAggregated(pubs, []byte("other")))
+
+	_, err = AggregateSignatures(nil)
+	assert.ErrorIs(t, err, ErrNoSignatures)
+}
+
+func TestBLS_ProvePossession(t *testing.T) {
+	keys := generateKeys(t, 2)
+
+	proof, err := keys[0].ProvePossession()
+	assert.NoError(t, err)
+
+	assert.True(t, proof.VerifyPossession(keys[0].PublicKey()))
+	assert.False(t, proof.VerifyPossession(keys[1].PublicKey()))
+
+	// the proof of possession is no signature of the public key, and the reverse
+	assert.False(t, proof.Verify(keys[0].PublicKey(), keys[0].PublicKey().Marshal()))
+
+	sig, err := keys[0].Sign(keys[0].PublicKey().Marshal())
+	assert.NoError(t, err)
+	assert.False(t, sig.VerifyPossession(keys[0].PublicKey()))
+}
+
+func TestBLS_RogueKey(t *testing.T) {
+	keys := generateKeys(t, 2)
+	msg := []byte("commit")
+
+	// the rogue key is the key of the attacker minus the key of the other signer,
+	// so the aggregation of both keys is the key of the attacker
+	g1 := bls12381.NewG1()
+
+	rogue := &PublicKey{p: g1.New()}
+	g1.Sub(rogue.p, keys[0].PublicKey().p, keys[1].PublicKey().p)
+
+	sig, err := keys[0].Sign(msg)
+	assert.NoError(t, err)
+
+	// the attacker forges the aggregated signature of both keys alone
+	assert.True(t, sig.VerifyAggregated([]*PublicKey{keys[1].PublicKey(), rogue}, msg))
+
+	// but it can't prove the possession of the rogue key
+	proof, err := keys[0].ProvePossession()
+	assert.NoError(t, err)
+	assert.False(t, proof.VerifyPossession(rogue))
+}
+
+func TestBLS_Marshal(t *testing.T) {
+	key := generateKeys(t, 1)[0]
+	msg := []byte("commit")
+
+	decodedKey, err := UnmarshalSecretKey(key.Marshal())
+	assert.NoError(t, err)
+	assert.Equal(t, key.Marshal(), decodedKey.Marshal())
+
+	pub := key.PublicKey().Marshal()
+	assert.Len(t, pub, PublicKeySize)
+
+	decodedPub, err := UnmarshalPublicKey(pub)
+	assert.NoError(t, err)
+
+	sig, err := decodedKey.Sign(msg)
+	assert.NoError(t, err)
+	assert.Len(t, sig.Marshal(), SignatureSize)
+
+	decodedSig, err := UnmarshalSignature(sig.Marshal())
+	assert.NoError(t, err)
+	assert.True(t, decodedSig.Verify(decodedPub, msg))
+}
+
+func TestBLS_UnmarshalInvalid(t *testing.T) {
+	// the zero scalar, and the scalars past the group order, aren't secret keys
+	_, err := UnmarshalSecretKey(make([]byte, SecretKeySize))
+	assert.ErrorIs(t, err, ErrInvalidSecretKey)
+
+	order := make([]byte, SecretKeySize)
+	for i := range order {
+		order[i] = 0xff
+	}
+
+	_, err = UnmarshalSecretKey(order)
+	assert.ErrorIs(t, err, ErrInvalidSecretKey)
+
+	_, err = UnmarshalSecretKey([]byte{0x01})
+	assert.ErrorIs(t, err, ErrInvalidSecretKey)
+
+	// the point at infinity isn't a public key
+	infinity := make([]byte, PublicKeySize)
+	infinity[0] = 0xc0
+
+	_, err = UnmarshalPublicKey(infinity)
+	assert.ErrorIs(t, err, ErrInvalidPublicKey)
+
+	_, err = UnmarshalPublicKey(make([]byte, PublicKeySize))
+	assert.ErrorIs(t, err, ErrInvalidPublicKey)
+
+	_, err = UnmarshalSignature(make([]byte, SignatureSize))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
@@ -24,6 +24,10 @@ var (
 	big1 = big.NewInt(1)
 )
 
+var (
+	ErrEmptySignature = errors.New("empty signature")
+)
+
 // S256 is the secp256k1 elliptic curve
 var S256 = btcec.S256()
 
@@ -139,6 +143,10 @@ func Ecrecover(hash, sig []byte) ([]byte, error) {
 // secp256k1 curve.
 func RecoverPubkey(signature, hash []byte) (*ecdsa.PublicKey, error) {
 	size := len(signature)
+	if size == 0 {
+		return nil, ErrEmptySignature
+	}
+
 	term := byte(27)
 
 	if signature[size-1] == 1 {
@@ -255,3 +255,8 @@ func TestPrivateKeyGeneration(t *testing.T) {
 	assert.True(t, writtenKey.Equal(readKey))
 	assert.Equal(t, writtenAddress.String(), readAddress.String())
 }
+
+func TestRecoverPubkey_EmptySignature(t *testing.T) {
+	_, err := RecoverPubkey([]byte{}, Keccak256([]byte("hash")))
+	assert.ErrorIs(t, err, ErrEmptySignature)
+}
@@ -0,0 +1,107 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+var (
+	// oidPublicKeyECDSA is the algorithm of the elliptic curve public keys
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+
+	// oidNamedCurveSecp256k1 is the secp256k1 curve
+	oidNamedCurveSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+
+	errInvalidPKIXKey   = errors.New("public key isn't a secp256k1 key")
+	errInvalidKMSSigner = errors.New("signature isn't signed by the public key")
+)
+
+// pkixPublicKey is the ASN.1 SubjectPublicKeyInfo structure of a public key
+type pkixPublicKey struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// ParsePKIXPublicKey parses the DER encoded SubjectPublicKeyInfo of a secp256k1 public key,
+// as returned by the KMS. The x509 package doesn't support the secp256k1 curve
+func ParsePKIXPublicKey(der []byte) (*ecdsa.PublicKey, error) {
+	var info pkixPublicKey
+
+	rest, err := asn1.Unmarshal(der, &info)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the public key, %w", err)
+	}
+
+	if len(rest) != 0 {
+		return nil, errInvalidPKIXKey
+	}
+
+	var curve asn1.ObjectIdentifier
+
+	if !info.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, errInvalidPKIXKey
+	}
+
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &curve); err != nil {
+		return nil, errInvalidPKIXKey
+	}
+
+	if !curve.Equal(oidNamedCurveSecp256k1) {
+		return nil, errInvalidPKIXKey
+	}
+
+	return ParsePublicKey(info.PublicKey.RightAlign())
+}
+
+// NormalizeDERSignature parses the DER encoded ECDSA signature of the KMS,
+// and returns it with the lower S value the secp256k1 signature verifiers expect
+func NormalizeDERSignature(der []byte) (*btcec.Signature, error) {
+	sig, err := btcec.ParseDERSignature(der, S256)
+	if err != nil {
+		return nil, err
+	}
+
+	halfOrder := new(big.Int).Rsh(S256.N, 1)
+	if sig.S.Cmp(halfOrder) > 0 {
+		sig.S = new(big.Int).Sub(S256.N, sig.S)
+	}
+
+	return sig, nil
+}
+
+// CompactSignatureFromDER converts the DER encoded ECDSA signature of the hash
+// to the compact signature returned by Sign, finding the recovery id of the public key
+func CompactSignatureFromDER(der, hash []byte, pub *ecdsa.PublicKey) ([]byte, error) {
+	sig, err := NormalizeDERSignature(der)
+	if err != nil {
+		return nil, err
+	}
+
+	compact := make([]byte, 65)
+	sig.R.FillBytes(compact[:32])
+	sig.S.FillBytes(compact[32:64])
+
+	expected := MarshalPublicKey(pub)
+
+	for v := byte(0); v < 2; v++ {
+		compact[64] = v
+
+		recovered, err := RecoverPubkey(compact, hash)
+		if err != nil {
+			continue
+		}
+
+		if bytes.Equal(MarshalPublicKey(recovered), expected) {
+			return compact, nil
+		}
+	}
+
+	return nil, errInvalidKMSSigner
+}
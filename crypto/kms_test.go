@@ -0,0 +1,91 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/stretchr/testify/assert"
+)
+
+// marshalPKIXPublicKey encodes the public key as the SubjectPublicKeyInfo returned by the KMS
+func marshalPKIXPublicKey(t *testing.T, pub *ecdsa.PublicKey, curve asn1.ObjectIdentifier) []byte {
+	t.Helper()
+
+	params, err := asn1.Marshal(curve)
+	assert.NoError(t, err)
+
+	der, err := asn1.Marshal(pkixPublicKey{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPublicKeyECDSA,
+			Parameters: asn1.RawValue{FullBytes: params},
+		},
+		PublicKey: asn1.BitString{
+			Bytes:     MarshalPublicKey(pub),
+			BitLength: 8 * len(MarshalPublicKey(pub)),
+		},
+	})
+	assert.NoError(t, err)
+
+	return der
+}
+
+func TestParsePKIXPublicKey(t *testing.T) {
+	priv, err := GenerateKey()
+	assert.NoError(t, err)
+
+	pub, err := ParsePKIXPublicKey(marshalPKIXPublicKey(t, &priv.PublicKey, oidNamedCurveSecp256k1))
+	assert.NoError(t, err)
+	assert.Equal(t, PubKeyToAddress(&priv.PublicKey), PubKeyToAddress(pub))
+
+	t.Run("should not parse the keys of other curves", func(t *testing.T) {
+		p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+
+		der, err := x509.MarshalPKIXPublicKey(&p256.PublicKey)
+		assert.NoError(t, err)
+
+		_, err = ParsePKIXPublicKey(der)
+		assert.ErrorIs(t, err, errInvalidPKIXKey)
+	})
+}
+
+func TestCompactSignatureFromDER(t *testing.T) {
+	priv, err := GenerateKey()
+	assert.NoError(t, err)
+
+	other, err := GenerateKey()
+	assert.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		hash := Keccak256([]byte{byte(i)})
+
+		sig, err := (*btcec.PrivateKey)(priv).Sign(hash)
+		assert.NoError(t, err)
+
+		// the KMS doesn't normalize the S value
+		highS, err := asn1.Marshal(struct {
+			R, S *big.Int
+		}{sig.R, new(big.Int).Sub(S256.N, sig.S)})
+		assert.NoError(t, err)
+
+		for _, der := range [][]byte{sig.Serialize(), highS} {
+			compact, err := CompactSignatureFromDER(der, hash, &priv.PublicKey)
+			assert.NoError(t, err)
+
+			// the compact signature matches the one signed locally, as the signatures are deterministic
+			expected, err := Sign(priv, hash)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, compact)
+		}
+
+		_, err = CompactSignatureFromDER(sig.Serialize(), hash, &other.PublicKey)
+		assert.ErrorIs(t, err, errInvalidKMSSigner)
+	}
+}
@@ -2,9 +2,9 @@ package crypto
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
-	"math/bits"
 
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/helper/keccak"
@@ -40,6 +40,10 @@ func NewSigner(forks chain.ForksInTime, chainID uint64) TxSigner {
 	return signer
 }
 
+var (
+	ErrInvalidChainID = errors.New("invalid chain id for signer")
+)
+
 type FrontierSigner struct {
 }
 
@@ -77,6 +81,65 @@ func calcTxHash(tx *types.Transaction, chainID uint64) types.Hash {
 	return types.BytesToHash(hash)
 }
 
+// calcAccessListTxHash calculates the signing hash of the access list transaction,
+// keccak256(0x01 || rlp([chainId, nonce, gasPrice, gas, to, value, input, accessList]))
+func calcAccessListTxHash(tx *types.Transaction, chainID uint64) types.Hash {
+	a := signerPool.Get()
+
+	v := a.NewArray()
+	v.Set(a.NewUint(chainID))
+	v.Set(a.NewUint(tx.Nonce))
+	v.Set(a.NewBigInt(tx.GasPrice))
+	v.Set(a.NewUint(tx.Gas))
+
+	if tx.To == nil {
+		v.Set(a.NewNull())
+	} else {
+		v.Set(a.NewCopyBytes((*tx.To).Bytes()))
+	}
+
+	v.Set(a.NewBigInt(tx.Value))
+	v.Set(a.NewCopyBytes(tx.Input))
+	v.Set(tx.AccessList.MarshalRLPWith(a))
+
+	payload := v.MarshalTo([]byte{byte(types.AccessListTx)})
+	hash := keccak.Keccak256(nil, payload)
+
+	signerPool.Put(a)
+
+	return types.BytesToHash(hash)
+}
+
+// calcDynamicFeeTxHash calculates the signing hash of the dynamic fee transaction,
+// keccak256(0x02 || rlp([chainId, nonce, maxPriorityFeePerGas, maxFeePerGas, gas, to, value, input, accessList]))
+func calcDynamicFeeTxHash(tx *types.Transaction, chainID uint64) types.Hash {
+	a := signerPool.Get()
+
+	v := a.NewArray()
+	v.Set(a.NewUint(chainID))
+	v.Set(a.NewUint(tx.Nonce))
+	v.Set(a.NewBigInt(tx.MaxPriorityFeePerGas))
+	v.Set(a.NewBigInt(tx.MaxFeePerGas))
+	v.Set(a.NewUint(tx.Gas))
+
+	if tx.To == nil {
+		v.Set(a.NewNull())
+	} else {
+		v.Set(a.NewCopyBytes((*tx.To).Bytes()))
+	}
+
+	v.Set(a.NewBigInt(tx.Value))
+	v.Set(a.NewCopyBytes(tx.Input))
+	v.Set(tx.AccessList.MarshalRLPWith(a))
+
+	payload := v.MarshalTo([]byte{byte(types.DynamicFeeTx)})
+	hash := keccak.Keccak256(nil, payload)
+
+	signerPool.Put(a)
+
+	return types.BytesToHash(hash)
+}
+
 // Hash is a wrapper function for the calcTxHash, with chainID 0
 func (f *FrontierSigner) Hash(tx *types.Transaction) types.Hash {
 	return calcTxHash(tx, 0)
@@ -90,6 +153,10 @@ var (
 
 // Sender decodes the signature and returns the sender of the transaction
 func (f *FrontierSigner) Sender(tx *types.Transaction) (types.Address, error) {
+	if tx.Type != types.LegacyTx {
+		return types.Address{}, types.ErrTxTypeNotSupported
+	}
+
 	refV := big.NewInt(0)
 	if tx.V != nil {
 		refV.SetBytes(tx.V.Bytes())
@@ -152,26 +219,34 @@ type EIP155Signer struct {
 
 // Hash is a wrapper function that calls calcTxHash with the EIP155Signer's chainID
 func (e *EIP155Signer) Hash(tx *types.Transaction) types.Hash {
+	switch tx.Type {
+	case types.AccessListTx:
+		return calcAccessListTxHash(tx, e.chainID)
+	case types.DynamicFeeTx:
+		return calcDynamicFeeTxHash(tx, e.chainID)
+	}
+
 	return calcTxHash(tx, e.chainID)
 }
 
 // Sender returns the transaction sender
 func (e *EIP155Signer) Sender(tx *types.Transaction) (types.Address, error) {
-	protected := true
+	if tx.Type != types.LegacyTx {
+		return e.typedTxSender(tx)
+	}
 
 	// Check if v value conforms to an earlier standard (before EIP155)
+	if !tx.IsReplayProtected() {
+		return (&FrontierSigner{}).Sender(tx)
+	}
+
 	bigV := big.NewInt(0)
 	if tx.V != nil {
 		bigV.SetBytes(tx.V.Bytes())
 	}
 
-	if vv := bigV.Uint64(); bits.Len(uint(vv)) <= 8 {
-		protected = vv != 27 && vv != 28
-	}
-
-	if !protected {
-		return (&FrontierSigner{}).Sender(tx)
-	}
+	// the V of the signatures carrying a chain id is at least 35
+	withChainID := bigV.Cmp(big35) >= 0
 
 	// Reverse the V calculation to find the original V in the range [0, 1]
 	// v = CHAIN_ID * 2 + 35 + {0, 1}
@@ -179,6 +254,11 @@ func (e *EIP155Signer) Sender(tx *types.Transaction) (types.Address, error) {
 	bigV.Sub(bigV, mulOperand)
 	bigV.Sub(bigV, big35)
 
+	// the transaction is signed for another chain
+	if withChainID && (bigV.Sign() < 0 || bigV.Cmp(big.NewInt(1)) > 0) {
+		return types.Address{}, ErrInvalidChainID
+	}
+
 	sig, err := encodeSignature(tx.R, tx.S, byte(bigV.Int64()))
 	if err != nil {
 		return types.Address{}, err
@@ -194,6 +274,32 @@ func (e *EIP155Signer) Sender(tx *types.Transaction) (types.Address, error) {
 	return types.BytesToAddress(buf), nil
 }
 
+// typedTxSender returns the sender of the access list or the dynamic fee transaction.
+// The V value of its signature is the parity of the signature, without the chain id
+func (e *EIP155Signer) typedTxSender(tx *types.Transaction) (types.Address, error) {
+	if tx.ChainID == nil || !tx.ChainID.IsUint64() || tx.ChainID.Uint64() != e.chainID {
+		return types.Address{}, ErrInvalidChainID
+	}
+
+	if tx.V == nil || !tx.V.IsUint64() || tx.V.Uint64() > 1 {
+		return types.Address{}, fmt.Errorf("invalid txn signature")
+	}
+
+	sig, err := encodeSignature(tx.R, tx.S, byte(tx.V.Uint64()))
+	if err != nil {
+		return types.Address{}, err
+	}
+
+	pub, err := Ecrecover(e.Hash(tx).Bytes(), sig)
+	if err != nil {
+		return types.Address{}, err
+	}
+
+	buf := Keccak256(pub[1:])[12:]
+
+	return types.BytesToAddress(buf), nil
+}
+
 // SignTx signs the transaction using the passed in private key
 func (e *EIP155Signer) SignTx(
 	tx *types.Transaction,
@@ -201,6 +307,10 @@ func (e *EIP155Signer) SignTx(
 ) (*types.Transaction, error) {
 	tx = tx.Copy()
 
+	if tx.Type != types.LegacyTx {
+		tx.ChainID = new(big.Int).SetUint64(e.chainID)
+	}
+
 	h := e.Hash(tx)
 
 	sig, err := Sign(privateKey, h[:])
@@ -210,7 +320,12 @@ func (e *EIP155Signer) SignTx(
 
 	tx.R = new(big.Int).SetBytes(sig[:32])
 	tx.S = new(big.Int).SetBytes(sig[32:64])
-	tx.V = new(big.Int).SetBytes(e.CalculateV(sig[64]))
+
+	if tx.Type != types.LegacyTx {
+		tx.V = new(big.Int).SetUint64(uint64(sig[64]))
+	} else {
+		tx.V = new(big.Int).SetBytes(e.CalculateV(sig[64]))
+	}
 
 	return tx, nil
 }
@@ -26,6 +26,13 @@ func TestFrontierSigner(t *testing.T) {
 	from, err := signer.Sender(signedTx)
 	assert.NoError(t, err)
 	assert.Equal(t, from, PubKeyToAddress(&key.PublicKey))
+
+	// the transactions signed before EIP-155 are not replay protected, they are still recovered
+	assert.False(t, signedTx.IsReplayProtected())
+
+	from, err = NewEIP155Signer(100).Sender(signedTx)
+	assert.NoError(t, err)
+	assert.Equal(t, from, PubKeyToAddress(&key.PublicKey))
 }
 
 func TestEIP155Signer_Sender(t *testing.T) {
@@ -138,8 +145,98 @@ func TestEIP155Signer_ChainIDMismatch(t *testing.T) {
 				assert.Equal(t, recoveredSender.String(), PubKeyToAddress(&key.PublicKey).String())
 			} else {
 				// There should be an error for mismatched chain IDs
-				assert.Error(t, recoverErr)
+				assert.ErrorIs(t, recoverErr, ErrInvalidChainID)
 			}
 		}
 	}
 }
+
+func TestEIP155Signer_DynamicFeeTx(t *testing.T) {
+	toAddress := types.StringToAddress("1")
+
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+
+	txn := &types.Transaction{
+		Type:                 types.DynamicFeeTx,
+		To:                   &toAddress,
+		Value:                big.NewInt(1),
+		MaxPriorityFeePerGas: big.NewInt(1),
+		MaxFeePerGas:         big.NewInt(10),
+	}
+
+	signer := NewEIP155Signer(100)
+
+	signedTx, err := signer.SignTx(txn, key)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), signedTx.ChainID)
+
+	from, err := signer.Sender(signedTx)
+	assert.NoError(t, err)
+	assert.Equal(t, PubKeyToAddress(&key.PublicKey), from)
+
+	// the chain ID is part of the signed payload
+	_, err = NewEIP155Signer(10).Sender(signedTx)
+	assert.ErrorIs(t, err, ErrInvalidChainID)
+
+	// the typed transactions can't be signed before EIP-155
+	_, err = (&FrontierSigner{}).Sender(signedTx)
+	assert.ErrorIs(t, err, types.ErrTxTypeNotSupported)
+}
+
+func TestEIP155Signer_AccessListTx(t *testing.T) {
+	toAddress := types.StringToAddress("1")
+
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+
+	txn := &types.Transaction{
+		Type:     types.AccessListTx,
+		To:       &toAddress,
+		Value:    big.NewInt(1),
+		GasPrice: big.NewInt(10),
+		AccessList: types.AccessList{
+			{Address: toAddress, StorageKeys: []types.Hash{types.StringToHash("1")}},
+		},
+	}
+
+	signer := NewEIP155Signer(100)
+
+	signedTx, err := signer.SignTx(txn, key)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), signedTx.ChainID)
+	assert.True(t, signedTx.V.Uint64() <= 1)
+
+	from, err := signer.Sender(signedTx)
+	assert.NoError(t, err)
+	assert.Equal(t, PubKeyToAddress(&key.PublicKey), from)
+
+	// the access list is part of the signed payload
+	signedTx.AccessList[0].StorageKeys[0] = types.StringToHash("2")
+
+	other, err := signer.Sender(signedTx)
+	if err == nil {
+		assert.NotEqual(t, from, other)
+	}
+}
+
+func TestEIP155Signer_AccessListTx_Geth(t *testing.T) {
+	// the access list transaction of the go-ethereum transaction tests, along with its signing hash
+	to := types.StringToAddress("0xb94f5374fce5edbc8e2a8697c15331677e6ebf0b")
+	txn := &types.Transaction{
+		Type:     types.AccessListTx,
+		ChainID:  big.NewInt(1),
+		Nonce:    3,
+		GasPrice: big.NewInt(1),
+		Gas:      25000,
+		To:       &to,
+		Value:    big.NewInt(10),
+		Input:    []byte{0x55, 0x44},
+	}
+
+	assert.Equal(
+		t,
+		types.StringToHash("0x49b486f0ec0a60dfbbca2d30cb07c9e8ffb2a2ff41f29a1ab6737475f6ff69f3"),
+		NewEIP155Signer(1).Hash(txn),
+	)
+}
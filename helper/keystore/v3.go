@@ -0,0 +1,219 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	keystoreVersion = 3
+
+	cipherAES128CTR = "aes-128-ctr"
+	kdfScrypt       = "scrypt"
+	kdfPBKDF2       = "pbkdf2"
+
+	scryptR     = 8
+	scryptDKLen = 32
+)
+
+// The scrypt parameters of the encrypted keys, the standard parameters of the Ethereum keystore
+var (
+	ScryptN = 1 << 18
+	ScryptP = 1
+)
+
+var (
+	ErrDecrypt = errors.New("could not decrypt the key with the given passphrase")
+
+	errUnsupportedVersion = errors.New("unsupported keystore version")
+	errUnsupportedCipher  = errors.New("unsupported keystore cipher")
+	errUnsupportedKDF     = errors.New("unsupported keystore key derivation function")
+)
+
+// encryptedKeyJSON is the Ethereum keystore V3 JSON format of an encrypted key
+type encryptedKeyJSON struct {
+	Address string     `json:"address,omitempty"`
+	Crypto  cryptoJSON `json:"crypto"`
+	ID      string     `json:"id"`
+	Version int        `json:"version"`
+}
+
+type cryptoJSON struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams cipherParamsJSON       `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type cipherParamsJSON struct {
+	IV string `json:"iv"`
+}
+
+// IsEncrypted checks if the key read from disk is in the keystore V3 format
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+// EncryptKey encrypts the key with the passphrase to the keystore V3 JSON format, using scrypt.
+// The address is optional, it's set for the keys of the Ethereum accounts
+func EncryptKey(key []byte, passphrase, address string) ([]byte, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, ScryptN, scryptR, ScryptP, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	cipherText, err := aesCTRXOR(derivedKey[:16], key, iv)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(&encryptedKeyJSON{
+		Address: address,
+		Crypto: cryptoJSON{
+			Cipher:     cipherAES128CTR,
+			CipherText: hex.EncodeToString(cipherText),
+			CipherParams: cipherParamsJSON{
+				IV: hex.EncodeToString(iv),
+			},
+			KDF: kdfScrypt,
+			KDFParams: map[string]interface{}{
+				"n":     ScryptN,
+				"r":     scryptR,
+				"p":     ScryptP,
+				"dklen": scryptDKLen,
+				"salt":  hex.EncodeToString(salt),
+			},
+			MAC: hex.EncodeToString(keystoreMAC(derivedKey, cipherText)),
+		},
+		ID:      uuid.New().String(),
+		Version: keystoreVersion,
+	}, "", "  ")
+}
+
+// DecryptKey decrypts the key in the keystore V3 JSON format with the passphrase
+func DecryptKey(keyJSON []byte, passphrase string) ([]byte, error) {
+	var encrypted encryptedKeyJSON
+	if err := json.Unmarshal(keyJSON, &encrypted); err != nil {
+		return nil, fmt.Errorf("unable to parse the encrypted key, %w", err)
+	}
+
+	if encrypted.Version != keystoreVersion {
+		return nil, errUnsupportedVersion
+	}
+
+	if encrypted.Crypto.Cipher != cipherAES128CTR {
+		return nil, errUnsupportedCipher
+	}
+
+	mac, err := hex.DecodeString(encrypted.Crypto.MAC)
+	if err != nil {
+		return nil, err
+	}
+
+	iv, err := hex.DecodeString(encrypted.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, err
+	}
+
+	cipherText, err := hex.DecodeString(encrypted.Crypto.CipherText)
+	if err != nil {
+		return nil, err
+	}
+
+	derivedKey, err := deriveKey(&encrypted.Crypto, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(derivedKey) != scryptDKLen {
+		return nil, errUnsupportedKDF
+	}
+
+	if !bytes.Equal(keystoreMAC(derivedKey, cipherText), mac) {
+		return nil, ErrDecrypt
+	}
+
+	return aesCTRXOR(derivedKey[:16], cipherText, iv)
+}
+
+// deriveKey derives the encryption key from the passphrase with the key derivation function of the keystore
+func deriveKey(keyCrypto *cryptoJSON, passphrase string) ([]byte, error) {
+	salt, err := hex.DecodeString(fmt.Sprintf("%v", keyCrypto.KDFParams["salt"]))
+	if err != nil {
+		return nil, err
+	}
+
+	dkLen := kdfParam(keyCrypto.KDFParams, "dklen")
+
+	switch keyCrypto.KDF {
+	case kdfScrypt:
+		return scrypt.Key(
+			[]byte(passphrase),
+			salt,
+			kdfParam(keyCrypto.KDFParams, "n"),
+			kdfParam(keyCrypto.KDFParams, "r"),
+			kdfParam(keyCrypto.KDFParams, "p"),
+			dkLen,
+		)
+	case kdfPBKDF2:
+		if keyCrypto.KDFParams["prf"] != "hmac-sha256" {
+			return nil, errUnsupportedKDF
+		}
+
+		return pbkdf2.Key([]byte(passphrase), salt, kdfParam(keyCrypto.KDFParams, "c"), dkLen, sha256.New), nil
+	default:
+		return nil, errUnsupportedKDF
+	}
+}
+
+// kdfParam returns the integer parameter of the key derivation function, decoded from JSON as a float
+func kdfParam(params map[string]interface{}, name string) int {
+	value, _ := params[name].(float64)
+
+	return int(value)
+}
+
+// keystoreMAC returns the MAC of the cipher text, the keccak256 hash with the second half of the derived key
+func keystoreMAC(derivedKey, cipherText []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(derivedKey[16:32])
+	h.Write(cipherText)
+
+	return h.Sum(nil)
+}
+
+func aesCTRXOR(key, input, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]byte, len(input))
+	cipher.NewCTR(block, iv).XORKeyStream(output, input)
+
+	return output, nil
+}
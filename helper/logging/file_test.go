@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logging")
+	assert.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "node.log")
+
+	readFile := func(path string) string {
+		data, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+
+		return string(data)
+	}
+
+	file, err := NewRotatingFile(path, 10, 2)
+	assert.NoError(t, err)
+
+	for _, entry := range []string{"aaaaa\n", "bbbb\n", "ccccc\n", "ddddd\n", "eeeee\n"} {
+		_, err := file.Write([]byte(entry))
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, file.Close())
+
+	// the oldest logs are dropped past the number of backups
+	assert.Equal(t, "eeeee\n", readFile(path))
+	assert.Equal(t, "ddddd\n", readFile(path+".1"))
+	assert.Equal(t, "ccccc\n", readFile(path+".2"))
+
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	// the existing file is appended to, and its size counted
+	file, err = NewRotatingFile(path, 10, 0)
+	assert.NoError(t, err)
+
+	_, err = file.Write([]byte("fff\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "eeeee\nfff\n", readFile(path))
+
+	// the file is truncated without backups
+	_, err = file.Write([]byte("g\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "g\n", readFile(path))
+	assert.Equal(t, "ccccc\n", readFile(path+".2"))
+
+	assert.NoError(t, file.Close())
+
+	_, err = file.Write([]byte("h\n"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// Levels are the log levels of the modules, such as ibft and txpool.
+// The modules without a level use the default one
+type Levels struct {
+	Default hclog.Level
+	Modules map[string]hclog.Level
+}
+
+// ParseLevels parses the log levels in the form info,ibft=debug,txpool=warn.
+// The entry without a module name sets the default level, info if not set
+func ParseLevels(raw string) (*Levels, error) {
+	levels := &Levels{
+		Default: hclog.Info,
+		Modules: map[string]hclog.Level{},
+	}
+
+	defaultSet := false
+
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		module, rawLevel := "", entry
+		if indx := strings.Index(entry, "="); indx >= 0 {
+			module, rawLevel = strings.TrimSpace(entry[:indx]), entry[indx+1:]
+
+			if module == "" {
+				return nil, fmt.Errorf("missing module name in log level %s", entry)
+			}
+		}
+
+		level := hclog.LevelFromString(strings.TrimSpace(rawLevel))
+		if level == hclog.NoLevel {
+			return nil, fmt.Errorf("invalid log level %s", entry)
+		}
+
+		if module != "" {
+			levels.Modules[module] = level
+
+			continue
+		}
+
+		if defaultSet {
+			return nil, fmt.Errorf("default log level set more than once in %s", raw)
+		}
+
+		levels.Default, defaultSet = level, true
+	}
+
+	return levels, nil
+}
+
+// moduleLevel returns the level of the most specific module of the logger name,
+// the modules of the name such as polygon.consensus.ibft are separated by dots
+func (l *Levels) moduleLevel(name string) (hclog.Level, bool) {
+	modules := strings.Split(name, ".")
+
+	for i := len(modules) - 1; i >= 0; i-- {
+		if level, ok := l.Modules[modules[i]]; ok {
+			return level, true
+		}
+	}
+
+	return hclog.NoLevel, false
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLevels(t *testing.T) {
+	cases := []struct {
+		raw     string
+		levels  *Levels
+		invalid bool
+	}{
+		{
+			raw:    "",
+			levels: &Levels{Default: hclog.Info, Modules: map[string]hclog.Level{}},
+		},
+		{
+			raw:    "DEBUG",
+			levels: &Levels{Default: hclog.Debug, Modules: map[string]hclog.Level{}},
+		},
+		{
+			raw: "info, ibft=debug,txpool=WARN",
+			levels: &Levels{Default: hclog.Info, Modules: map[string]hclog.Level{
+				"ibft":   hclog.Debug,
+				"txpool": hclog.Warn,
+			}},
+		},
+		{
+			// the default level is info if only modules are set
+			raw: "syncer=trace",
+			levels: &Levels{Default: hclog.Info, Modules: map[string]hclog.Level{
+				"syncer": hclog.Trace,
+			}},
+		},
+		{raw: "verbose", invalid: true},
+		{raw: "ibft=verbose", invalid: true},
+		{raw: "=debug", invalid: true},
+		{raw: "info,debug", invalid: true},
+	}
+
+	for _, c := range cases {
+		levels, err := ParseLevels(c.raw)
+		if c.invalid {
+			assert.Error(t, err, c.raw)
+
+			continue
+		}
+
+		assert.NoError(t, err, c.raw)
+		assert.Equal(t, c.levels, levels, c.raw)
+	}
+}
@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"github.com/hashicorp/go-hclog"
+)
+
+// moduleLogger is a logger whose named sub-loggers log at the level of their module
+type moduleLogger struct {
+	hclog.Logger
+
+	levels *Levels
+}
+
+// NewLogger creates a new logger with the options, the sub-loggers created with Named
+// log at the level of their module if it is set, the level of their parent otherwise
+func NewLogger(opts *hclog.LoggerOptions, levels *Levels) hclog.Logger {
+	opts.Level = levels.Default
+	opts.IndependentLevels = true
+
+	logger := &moduleLogger{
+		Logger: hclog.New(opts),
+		levels: levels,
+	}
+
+	if level, ok := levels.moduleLevel(opts.Name); ok {
+		logger.SetLevel(level)
+	}
+
+	return logger
+}
+
+// Named creates a sub-logger within the module of the name
+func (l *moduleLogger) Named(name string) hclog.Logger {
+	sub := &moduleLogger{
+		Logger: l.Logger.Named(name),
+		levels: l.levels,
+	}
+
+	if level, ok := l.levels.moduleLevel(name); ok {
+		sub.SetLevel(level)
+	}
+
+	return sub
+}
+
+// ResetNamed creates a sub-logger with the name, at the default level if the name
+// is not within a module with a level
+func (l *moduleLogger) ResetNamed(name string) hclog.Logger {
+	sub := &moduleLogger{
+		Logger: l.Logger.ResetNamed(name),
+		levels: l.levels,
+	}
+
+	level, ok := l.levels.moduleLevel(name)
+	if !ok {
+		level = l.levels.Default
+	}
+
+	sub.SetLevel(level)
+
+	return sub
+}
+
+// With creates a sub-logger with the key value pairs, in the same module
+func (l *moduleLogger) With(args ...interface{}) hclog.Logger {
+	return &moduleLogger{
+		Logger: l.Logger.With(args...),
+		levels: l.levels,
+	}
+}
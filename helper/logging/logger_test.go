@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLogger_ModuleLevels(t *testing.T) {
+	levels, err := ParseLevels("info,consensus=warn,ibft=debug")
+	assert.NoError(t, err)
+
+	var output bytes.Buffer
+
+	logger := NewLogger(&hclog.LoggerOptions{Name: "polygon", Output: &output}, levels)
+
+	// the most specific module of the name is used
+	consensus := logger.Named("consensus")
+	ibft := consensus.Named("ibft")
+	acceptState := ibft.With("key", "value").Named("acceptState")
+
+	assert.True(t, logger.IsInfo() && !logger.IsDebug())
+	assert.True(t, consensus.IsWarn() && !consensus.IsInfo())
+	assert.True(t, ibft.IsDebug() && !ibft.IsTrace())
+	assert.True(t, acceptState.IsDebug())
+
+	// the modules without a level use the level of their parent
+	assert.True(t, consensus.Named("dev").IsWarn())
+	assert.True(t, logger.Named("txpool").IsInfo())
+	assert.True(t, ibft.ResetNamed("txpool").IsInfo())
+
+	// the levels of the sub-loggers are independent
+	ibft.Debug("ibft message")
+	consensus.Info("consensus message")
+
+	assert.Contains(t, output.String(), "polygon.consensus.ibft: ibft message")
+	assert.NotContains(t, output.String(), "consensus message")
+}
+
+func TestNewLogger_JSON(t *testing.T) {
+	levels, err := ParseLevels("info")
+	assert.NoError(t, err)
+
+	var output bytes.Buffer
+
+	logger := NewLogger(&hclog.LoggerOptions{Name: "polygon", Output: &output, JSONFormat: true}, levels)
+	logger.Named("txpool").Info("message", "key", "value")
+
+	assert.Contains(t, output.String(), `"@module":"polygon.txpool"`)
+	assert.Contains(t, output.String(), `"key":"value"`)
+}
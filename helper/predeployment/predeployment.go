@@ -0,0 +1,193 @@
+package predeployment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/umbracle/go-web3/abi"
+)
+
+const (
+	// predeployGasLimit is the gas the constructors of the predeployed contracts can use
+	predeployGasLimit uint64 = 100000000
+)
+
+var (
+	ErrEmptyBytecode        = errors.New("the contract artifact has no bytecode")
+	ErrConstructorArgsCount = errors.New("the number of the constructor arguments doesn't match the constructor")
+	ErrNoConstructorInputs  = errors.New("the ABI of the contract has no constructor inputs")
+	ErrConstructorNoCode    = errors.New("the constructor returned no contract code")
+)
+
+// ContractArtifact is the compiled contract, as written by Hardhat or Truffle.
+// Only the creation bytecode is used, the deployed code is the one returned by the constructor
+type ContractArtifact struct {
+	ABI      *abi.ABI `json:"abi"`
+	Bytecode string   `json:"bytecode"`
+}
+
+// ReadContractArtifact reads the contract artifact from the specified path
+func ReadContractArtifact(path string) (*ContractArtifact, error) {
+	rawData, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var artifact ContractArtifact
+	if err := json.Unmarshal(rawData, &artifact); err != nil {
+		return nil, err
+	}
+
+	return &artifact, nil
+}
+
+// GenerateGenesisAccount runs the constructor of the contract at the given address,
+// and returns the genesis account with the resulting code and storage.
+// The constructor runs on an empty state in the block 0, so the account only depends on
+// the artifact, the arguments and the chain ID
+func GenerateGenesisAccount(
+	artifact *ContractArtifact,
+	constructorArgs []string,
+	address types.Address,
+	chainID int,
+) (*chain.GenesisAccount, error) {
+	code, err := creationCode(artifact, constructorArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	executor := state.NewExecutor(&chain.Params{
+		Forks:   chain.AllForksEnabled,
+		ChainID: chainID,
+	}, itrie.NewState(itrie.NewMemoryStorage()), hclog.NewNullLogger())
+	executor.SetRuntime(precompiled.NewPrecompiled())
+	executor.SetRuntime(evm.NewEVM())
+	executor.GetHash = func(*types.Header) state.GetHashByNumber {
+		return func(uint64) types.Hash {
+			return types.ZeroHash
+		}
+	}
+
+	transition, err := executor.BeginTxn(
+		types.EmptyRootHash,
+		&types.Header{GasLimit: predeployGasLimit},
+		types.ZeroAddress,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := transition.CreateAt(types.ZeroAddress, address, code, big.NewInt(0), predeployGasLimit)
+	if result.Failed() {
+		return nil, fmt.Errorf("the constructor execution failed, %w", result.Err)
+	}
+
+	txn := transition.Txn()
+
+	deployedCode := txn.GetCode(address)
+	if len(deployedCode) == 0 {
+		return nil, ErrConstructorNoCode
+	}
+
+	account := &chain.GenesisAccount{
+		Code:  deployedCode,
+		Nonce: txn.GetNonce(address),
+	}
+
+	if balance := txn.GetBalance(address); balance.Sign() > 0 {
+		account.Balance = balance
+	}
+
+	if storage := txn.GetDirtyStorage(address); len(storage) != 0 {
+		account.Storage = storage
+	}
+
+	return account, nil
+}
+
+// creationCode returns the bytecode of the artifact followed by the ABI encoded constructor arguments
+func creationCode(artifact *ContractArtifact, constructorArgs []string) ([]byte, error) {
+	code, err := hex.DecodeHex(artifact.Bytecode)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the contract bytecode, %w", err)
+	}
+
+	if len(code) == 0 {
+		return nil, ErrEmptyBytecode
+	}
+
+	if artifact.ABI == nil || artifact.ABI.Constructor == nil || artifact.ABI.Constructor.Inputs == nil {
+		if len(constructorArgs) != 0 {
+			return nil, ErrNoConstructorInputs
+		}
+
+		return code, nil
+	}
+
+	inputs := artifact.ABI.Constructor.Inputs
+	if len(inputs.TupleElems()) == 0 && len(constructorArgs) == 0 {
+		return code, nil
+	}
+
+	args, err := parseConstructorArgs(inputs, constructorArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedArgs, err := abi.Encode(args, inputs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode the constructor arguments, %w", err)
+	}
+
+	return append(code, encodedArgs...), nil
+}
+
+// parseConstructorArgs matches the raw arguments with the constructor inputs.
+// The array arguments are passed as the JSON arrays of strings
+func parseConstructorArgs(inputs *abi.Type, rawArgs []string) ([]interface{}, error) {
+	elems := inputs.TupleElems()
+	if len(elems) != len(rawArgs) {
+		return nil, ErrConstructorArgsCount
+	}
+
+	args := make([]interface{}, len(rawArgs))
+
+	for i, elem := range elems {
+		if kind := elem.Elem.Kind(); kind != abi.KindSlice && kind != abi.KindArray {
+			args[i] = rawArgs[i]
+
+			continue
+		}
+
+		var items []string
+		if err := json.Unmarshal([]byte(rawArgs[i]), &items); err != nil {
+			return nil, fmt.Errorf("the argument %d should be a JSON array of strings, %w", i, err)
+		}
+
+		if elem.Elem.Kind() == abi.KindSlice {
+			args[i] = items
+
+			continue
+		}
+
+		// the fixed size arrays are encoded from the arrays only
+		array := reflect.New(reflect.ArrayOf(len(items), reflect.TypeOf(""))).Elem()
+		reflect.Copy(array, reflect.ValueOf(items))
+
+		args[i] = array.Interface()
+	}
+
+	return args, nil
+}
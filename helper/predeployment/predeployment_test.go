@@ -0,0 +1,99 @@
+package predeployment
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/go-web3/abi"
+)
+
+var predeployAddress = types.StringToAddress("0x1010")
+
+// storageCode is the creation code of a contract storing its uint256 constructor argument in the slot 0,
+// and 42 in the slot 1. The code of the deployed contract is a single STOP
+const storageCode = "0x" +
+	"602060203803600039600051600055" + // copy the argument at the end of the code to the memory, store it
+	"602a600155" + // store 42 in the slot 1
+	"6000600053" + // write STOP to the memory
+	"60016000f3" // return the 1 byte of the code
+
+const (
+	uintConstructorABI  = `[{"type":"constructor","inputs":[{"name":"value","type":"uint256"}]}]`
+	arrayConstructorABI = `[{"type":"constructor","inputs":[` +
+		`{"name":"validators","type":"address[]"},{"name":"limits","type":"uint256[2]"}]}]`
+)
+
+func TestGenerateGenesisAccount(t *testing.T) {
+	artifact := &ContractArtifact{
+		ABI:      abi.MustNewABI(uintConstructorABI),
+		Bytecode: storageCode,
+	}
+
+	account, err := GenerateGenesisAccount(artifact, []string{"0x1234"}, predeployAddress, 100)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []byte{0x00}, account.Code)
+	assert.Equal(t, uint64(1), account.Nonce)
+	assert.Nil(t, account.Balance)
+	assert.Equal(t, map[types.Hash]types.Hash{
+		types.BytesToHash([]byte{0x00}): types.BytesToHash([]byte{0x12, 0x34}),
+		types.BytesToHash([]byte{0x01}): types.BytesToHash([]byte{0x2a}),
+	}, account.Storage)
+
+	// the account doesn't depend on the node generating the genesis
+	again, err := GenerateGenesisAccount(artifact, []string{"0x1234"}, predeployAddress, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, account, again)
+}
+
+func TestGenerateGenesisAccount_Errors(t *testing.T) {
+	testTable := []struct {
+		name        string
+		abi         string
+		bytecode    string
+		args        []string
+		expectedErr error
+	}{
+		{"empty bytecode", "", "0x", nil, ErrEmptyBytecode},
+		{"missing argument", uintConstructorABI, storageCode, nil, ErrConstructorArgsCount},
+		{"arguments without the constructor", "", storageCode, []string{"1"}, ErrNoConstructorInputs},
+		{"reverting constructor", "", "0x60006000fd", nil, runtime.ErrExecutionReverted},
+		{"constructor without code", "", "0x00", nil, ErrConstructorNoCode},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			artifact := &ContractArtifact{
+				Bytecode: testCase.bytecode,
+			}
+
+			if testCase.abi != "" {
+				artifact.ABI = abi.MustNewABI(testCase.abi)
+			}
+
+			_, err := GenerateGenesisAccount(artifact, testCase.args, predeployAddress, 100)
+			assert.ErrorIs(t, err, testCase.expectedErr)
+		})
+	}
+}
+
+func TestParseConstructorArgs(t *testing.T) {
+	inputs := abi.MustNewABI(arrayConstructorABI).Constructor.Inputs
+
+	validators := `["0x0000000000000000000000000000000000000001","0x0000000000000000000000000000000000000002"]`
+
+	args, err := parseConstructorArgs(inputs, []string{validators, `["1","10"]`})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		[]string{"0x0000000000000000000000000000000000000001", "0x0000000000000000000000000000000000000002"},
+		[2]string{"1", "10"},
+	}, args)
+
+	_, err = abi.Encode(args, inputs)
+	assert.NoError(t, err)
+
+	_, err = parseConstructorArgs(inputs, []string{"0x1", `["1","10"]`})
+	assert.Error(t, err)
+}
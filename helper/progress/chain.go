@@ -11,6 +11,7 @@ type ChainSyncType string
 const (
 	ChainSyncRestore ChainSyncType = "restore"
 	ChainSyncBulk    ChainSyncType = "bulk-sync"
+	ChainSyncFast    ChainSyncType = "fast-sync"
 )
 
 // Progression defines the status of the sync
@@ -28,6 +29,11 @@ type Progression struct {
 
 	// HighestBlock is the target block in the sync batch
 	HighestBlock uint64
+
+	// KnownStates and PulledStates are the state trie nodes known and downloaded by the fast sync,
+	// zero for the other sync methods
+	KnownStates  uint64
+	PulledStates uint64
 }
 
 type ProgressionWrapper struct {
@@ -120,10 +126,16 @@ func (pw *ProgressionWrapper) UpdateHighestProgression(highestBlock uint64) {
 	pw.progression.HighestBlock = highestBlock
 }
 
-// GetProgression returns the latest sync progression
+// GetProgression returns a snapshot of the latest sync progression
 func (pw *ProgressionWrapper) GetProgression() *Progression {
 	pw.lock.RLock()
 	defer pw.lock.RUnlock()
 
-	return pw.progression
+	if pw.progression == nil {
+		return nil
+	}
+
+	progression := *pw.progression
+
+	return &progression
 }
@@ -0,0 +1,139 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// writeMethods are the methods sending transactions, and adminMethods
+// the ones tracing the execution or altering the dev chain
+var (
+	writeMethods = []string{"eth_sendRawTransaction", "eth_sendTransaction", "edge_sendRawTransactionSync"}
+	adminMethods = []string{"debug_*", "evm_*"}
+)
+
+// AccessControl restricts the clients calling the read, the write and the admin methods to the listed networks,
+// any client is allowed to call the methods of a group if no network is listed for it.
+// The address of the client is read from the X-Forwarded-For header only if the request comes from a trusted proxy
+type AccessControl struct {
+	ReadCIDRs      []*net.IPNet
+	WriteCIDRs     []*net.IPNet
+	AdminCIDRs     []*net.IPNet
+	TrustedProxies []*net.IPNet
+}
+
+// isAllowed checks if the client is allowed to call the method
+func (a *AccessControl) isAllowed(client string, method string) bool {
+	if a == nil {
+		return true
+	}
+
+	cidrs := a.ReadCIDRs
+
+	switch {
+	case matchMethod(writeMethods, method):
+		cidrs = a.WriteCIDRs
+	case matchMethod(adminMethods, method):
+		cidrs = a.AdminCIDRs
+	}
+
+	if len(cidrs) == 0 {
+		return true
+	}
+
+	ip := net.ParseIP(client)
+
+	return ip != nil && containsIP(cidrs, ip)
+}
+
+// clientIP returns the IP address of the client, the rate limits and the access control apply per address.
+// The X-Forwarded-For header is walked back from the trusted proxy the request comes from,
+// the client is the first address that is not a trusted proxy
+func (a *AccessControl) clientIP(req *http.Request) string {
+	client, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		client = req.RemoteAddr
+	}
+
+	if a == nil || len(a.TrustedProxies) == 0 {
+		return client
+	}
+
+	forwarded := strings.Split(strings.Join(req.Header.Values("X-Forwarded-For"), ","), ",")
+
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		ip := net.ParseIP(client)
+		if ip == nil || !containsIP(a.TrustedProxies, ip) {
+			break
+		}
+
+		hop := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+
+		client = hop
+	}
+
+	return client
+}
+
+// containsIP checks if the IP address is in one of the networks
+func containsIP(cidrs []*net.IPNet, ip net.IP) bool {
+	for _, cidr := range cidrs {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseCIDRs parses the networks in the CIDR notation, the plain IP addresses are the networks of a single address
+func ParseCIDRs(values []string) ([]*net.IPNet, error) {
+	cidrs := make([]*net.IPNet, 0, len(values))
+
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address %s", value)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+
+			cidrs = append(cidrs, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+
+			continue
+		}
+
+		_, cidr, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, err
+		}
+
+		cidrs = append(cidrs, cidr)
+	}
+
+	return cidrs, nil
+}
+
+// allowedOrigin returns the value of the CORS header for the origin, false if the origin isn't allowed
+func allowedOrigin(origins []string, origin string) (string, bool) {
+	for _, allowed := range origins {
+		if allowed == "*" {
+			return "*", true
+		}
+
+		if allowed == origin {
+			return origin, true
+		}
+	}
+
+	return "", false
+}
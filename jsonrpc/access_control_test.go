@@ -0,0 +1,140 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParseCIDRs(t *testing.T, values ...string) []*net.IPNet {
+	t.Helper()
+
+	cidrs, err := ParseCIDRs(values)
+	assert.NoError(t, err)
+
+	return cidrs
+}
+
+func TestParseCIDRs(t *testing.T) {
+	cidrs := mustParseCIDRs(t, "10.0.0.0/8", "192.168.1.1", "::1")
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1/32", "::1/128"}, []string{
+		cidrs[0].String(), cidrs[1].String(), cidrs[2].String(),
+	})
+
+	_, err := ParseCIDRs([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+
+	_, err = ParseCIDRs([]string{"localhost"})
+	assert.Error(t, err)
+}
+
+func TestAccessControl_IsAllowed(t *testing.T) {
+	accessControl := &AccessControl{
+		WriteCIDRs: mustParseCIDRs(t, "10.0.0.0/8"),
+		AdminCIDRs: mustParseCIDRs(t, "127.0.0.1"),
+	}
+
+	testTable := []struct {
+		client  string
+		method  string
+		allowed bool
+	}{
+		// no network is listed for the read methods
+		{"1.2.3.4", "eth_blockNumber", true},
+		{"10.1.2.3", "eth_sendRawTransaction", true},
+		{"1.2.3.4", "eth_sendRawTransaction", false},
+		{"1.2.3.4", "edge_sendRawTransactionSync", false},
+		{"10.1.2.3", "debug_traceTransaction", false},
+		{"127.0.0.1", "evm_mine", true},
+		{"invalid", "eth_sendTransaction", false},
+	}
+
+	for _, testCase := range testTable {
+		assert.Equal(t, testCase.allowed, accessControl.isAllowed(testCase.client, testCase.method),
+			"%s calling %s", testCase.client, testCase.method)
+	}
+
+	// any client is allowed without access control
+	assert.True(t, (*AccessControl)(nil).isAllowed("1.2.3.4", "debug_traceTransaction"))
+}
+
+func TestAccessControl_ClientIP(t *testing.T) {
+	newRequest := func(remoteAddr string, forwarded ...string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.RemoteAddr = remoteAddr
+
+		for _, value := range forwarded {
+			req.Header.Add("X-Forwarded-For", value)
+		}
+
+		return req
+	}
+
+	// the header is ignored without trusted proxies
+	assert.Equal(t, "10.0.0.1", (*AccessControl)(nil).clientIP(newRequest("10.0.0.1:1234", "1.2.3.4")))
+	assert.Equal(t, "10.0.0.1", (&AccessControl{}).clientIP(newRequest("10.0.0.1:1234", "1.2.3.4")))
+
+	accessControl := &AccessControl{TrustedProxies: mustParseCIDRs(t, "10.0.0.0/8")}
+
+	testTable := []struct {
+		name     string
+		req      *http.Request
+		expected string
+	}{
+		{"untrusted proxy", newRequest("1.1.1.1:1234", "1.2.3.4"), "1.1.1.1"},
+		{"trusted proxy", newRequest("10.0.0.1:1234", "1.2.3.4"), "1.2.3.4"},
+		{"chain of trusted proxies", newRequest("10.0.0.1:1234", "6.6.6.6, 1.2.3.4", "10.0.0.2"), "1.2.3.4"},
+		{"no header", newRequest("10.0.0.1:1234"), "10.0.0.1"},
+		{"invalid address", newRequest("10.0.0.1:1234", "1.2.3.4, garbage"), "10.0.0.1"},
+	}
+
+	for _, testCase := range testTable {
+		assert.Equal(t, testCase.expected, accessControl.clientIP(testCase.req), testCase.name)
+	}
+}
+
+func TestDispatcherAccessControl(t *testing.T) {
+	dispatcher, err := newDispatcher(hclog.NewNullLogger(), newMockStore(), dispatcherParams{
+		accessControl: &AccessControl{
+			ReadCIDRs: mustParseCIDRs(t, "10.0.0.0/8"),
+		},
+	})
+	assert.NoError(t, err)
+
+	request := []byte(`{"id":1,"jsonrpc":"2.0","method":"web3_clientVersion","params":[]}`)
+
+	resp, err := dispatcher.Handle(context.Background(), request, "1.2.3.4")
+	assert.NoError(t, err)
+
+	var res ErrorResponse
+
+	assert.NoError(t, json.Unmarshal(resp, &res))
+	assert.Equal(t, -32006, res.Error.Code)
+
+	resp, err = dispatcher.Handle(context.Background(), request, "10.0.0.1")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(resp), "error")
+}
+
+func TestAllowedOrigin(t *testing.T) {
+	origin, ok := allowedOrigin([]string{"https://a.com"}, "https://a.com")
+	assert.True(t, ok)
+	assert.Equal(t, "https://a.com", origin)
+
+	origin, ok = allowedOrigin([]string{"*"}, "https://b.com")
+	assert.True(t, ok)
+	assert.Equal(t, "*", origin)
+
+	_, ok = allowedOrigin([]string{"https://a.com"}, "https://b.com")
+	assert.False(t, ok)
+
+	// no origin is allowed by default
+	_, ok = allowedOrigin(nil, "https://a.com")
+	assert.False(t, ok)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/types"
 )
 
@@ -94,9 +95,10 @@ func (e *ObjectError) Error() string {
 }
 
 const (
-	PendingBlockNumber  = BlockNumber(-3)
-	LatestBlockNumber   = BlockNumber(-2)
-	EarliestBlockNumber = BlockNumber(-1)
+	FinalizedBlockNumber = BlockNumber(-4)
+	PendingBlockNumber   = BlockNumber(-3)
+	LatestBlockNumber    = BlockNumber(-2)
+	EarliestBlockNumber  = BlockNumber(-1)
 )
 
 type BlockNumber int64
@@ -109,7 +111,7 @@ type BlockNumberOrHash struct {
 // UnmarshalJSON will try to extract the filter's data.
 // Here are the possible input formats :
 //
-// 1 - "latest", "pending" or "earliest"	- self-explaining keywords
+// 1 - "latest", "pending", "earliest", "finalized" or "safe"	- self-explaining keywords, "safe" is "finalized"
 // 2 - "0x2"								- block number #2 (EIP-1898 backward compatible)
 // 3 - {blockNumber:	"0x2"}				- EIP-1898 compliant block number #2
 // 4 - {blockHash:		"0xe0e..."}			- EIP-1898 compliant block hash 0xe0e...
@@ -141,6 +143,39 @@ func (bnh *BlockNumberOrHash) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// BlockNumberOrTxHash is either a block number or the hash of a transaction,
+// the hashes are told apart from the hex numbers by their 32 bytes length
+type BlockNumberOrTxHash struct {
+	BlockNumber *BlockNumber
+	TxHash      *types.Hash
+}
+
+// UnmarshalJSON decodes the block number, or the transaction hash
+func (bnt *BlockNumberOrTxHash) UnmarshalJSON(data []byte) error {
+	str := strings.Trim(string(data), "\"")
+
+	if len(str) == 2+2*types.HashLength && strings.HasPrefix(str, "0x") {
+		buf, err := hex.DecodeHex(str)
+		if err != nil {
+			return err
+		}
+
+		hash := types.BytesToHash(buf)
+		bnt.TxHash = &hash
+
+		return nil
+	}
+
+	number, err := stringToBlockNumber(str)
+	if err != nil {
+		return err
+	}
+
+	bnt.BlockNumber = &number
+
+	return nil
+}
+
 func stringToBlockNumber(str string) (BlockNumber, error) {
 	if str == "" {
 		return 0, fmt.Errorf("value is empty")
@@ -154,6 +189,9 @@ func stringToBlockNumber(str string) (BlockNumber, error) {
 		return LatestBlockNumber, nil
 	case "earliest":
 		return EarliestBlockNumber, nil
+	case "finalized", "safe":
+		// the blocks with verified seals are final, there are no safe ones which are not final
+		return FinalizedBlockNumber, nil
 	}
 
 	n, err := types.ParseUint64orHex(&str)
@@ -205,7 +243,18 @@ func NewRPCResponse(id interface{}, jsonrpcver string, reply []byte, err Error)
 	case nil:
 		response = &SuccessResponse{JSONRPC: jsonrpcver, ID: id, Result: reply}
 	default:
-		response = NewRPCErrorResponse(id, err.ErrorCode(), err.Error(), jsonrpcver)
+		errResponse := &ErrorResponse{
+			JSONRPC: jsonrpcver,
+			ID:      id,
+			Error:   &ObjectError{err.ErrorCode(), err.Error(), nil},
+		}
+
+		// the errors with data return it along with the message
+		if dataErr, ok := err.(dataError); ok {
+			errResponse.Error.Data = dataErr.ErrorData()
+		}
+
+		response = errResponse
 	}
 
 	return response
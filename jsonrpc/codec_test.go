@@ -15,6 +15,7 @@ func TestBlockNumberOrHash_UnmarshalJSON(t *testing.T) {
 
 	blockNumberZero := BlockNumber(0x0)
 	blockNumberLatest := LatestBlockNumber
+	blockNumberFinalized := FinalizedBlockNumber
 
 	tests := []struct {
 		name        string
@@ -57,6 +58,22 @@ func TestBlockNumberOrHash_UnmarshalJSON(t *testing.T) {
 				BlockNumber: &blockNumberLatest,
 			},
 		},
+		{
+			"should unmarshal finalized block number properly",
+			`"finalized"`,
+			false,
+			BlockNumberOrHash{
+				BlockNumber: &blockNumberFinalized,
+			},
+		},
+		{
+			"should unmarshal safe as the finalized block number",
+			`"safe"`,
+			false,
+			BlockNumberOrHash{
+				BlockNumber: &blockNumberFinalized,
+			},
+		},
 		{
 			"should unmarshal block number 0 properly #1",
 			`{"blockNumber": "0x0"}`,
@@ -105,3 +122,66 @@ func TestBlockNumberOrHash_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockNumberOrTxHash_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	txHash := types.StringToHash("0xe0ee62fd4a39a6988e24df0b406b90af71932e1b01d5561400a8eab943a33d68")
+	blockNumber := BlockNumber(0x10)
+	blockNumberLatest := LatestBlockNumber
+
+	tests := []struct {
+		name        string
+		rawRequest  string
+		shouldFail  bool
+		expectedBnt BlockNumberOrTxHash
+	}{
+		{
+			"should unmarshal the transaction hash",
+			`"0xe0ee62fd4a39a6988e24df0b406b90af71932e1b01d5561400a8eab943a33d68"`,
+			false,
+			BlockNumberOrTxHash{TxHash: &txHash},
+		},
+		{
+			"should unmarshal the block number",
+			`"0x10"`,
+			false,
+			BlockNumberOrTxHash{BlockNumber: &blockNumber},
+		},
+		{
+			"should unmarshal the latest block number",
+			`"latest"`,
+			false,
+			BlockNumberOrTxHash{BlockNumber: &blockNumberLatest},
+		},
+		{
+			"should return an error for an invalid transaction hash",
+			`"0xz0ee62fd4a39a6988e24df0b406b90af71932e1b01d5561400a8eab943a33d68"`,
+			true,
+			BlockNumberOrTxHash{},
+		},
+		{
+			"should return an error for an invalid block number",
+			`"abc"`,
+			true,
+			BlockNumberOrTxHash{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			bnt := BlockNumberOrTxHash{}
+			err := bnt.UnmarshalJSON([]byte(tt.rawRequest))
+
+			if tt.shouldFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedBnt, bnt)
+			}
+		})
+	}
+}
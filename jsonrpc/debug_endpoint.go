@@ -0,0 +1,378 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/tracer"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	ErrTraceGenesis = errors.New("the genesis block is not traceable")
+)
+
+// debugStore provides access to the methods needed by the debug endpoint
+type debugStore interface {
+	// Header returns the current header of the chain (genesis if empty)
+	Header() *types.Header
+
+	// GetBlockByHash gets a block using the provided hash
+	GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool)
+
+	// GetBlockByNumber returns a block using the provided number
+	GetBlockByNumber(num uint64, full bool) (*types.Block, bool)
+
+	// ReadTxLookup returns the hash of the block in which a given txn was mined,
+	// and the index of the txn in the block
+	ReadTxLookup(txnHash types.Hash) (types.Hash, uint64, bool)
+
+	// TraceBlock re-executes the transactions of the block on the parent state,
+	// the transactions are traced by the tracer at their index, if any.
+	// The execution is aborted with runtime.ErrExecutionAborted once the context is done
+	TraceBlock(ctx context.Context, block *types.Block, tracers []runtime.Tracer) error
+
+	// GetBadBlocks returns the last blocks rejected by the node, from the latest one
+	GetBadBlocks() []*blockchain.BadBlock
+}
+
+// Debug is the debug jsonrpc endpoint
+type Debug struct {
+	store debugStore
+}
+
+// TraceConfig are the options of the tracing, the transactions
+// are traced by the struct logger if no tracer is set
+type TraceConfig struct {
+	DisableMemory  bool   `json:"disableMemory"`
+	DisableStack   bool   `json:"disableStack"`
+	DisableStorage bool   `json:"disableStorage"`
+	Tracer         string `json:"tracer"`
+}
+
+// txnTracer is a tracer of a transaction, along with the formatting of its result
+type txnTracer struct {
+	tracer runtime.Tracer
+	result func() interface{}
+}
+
+// tracers are the tracers available by name
+var tracers = map[string]func() *txnTracer{
+	"callTracer": func() *txnTracer {
+		callTracer := tracer.NewCallTracer()
+
+		return &txnTracer{callTracer, func() interface{} {
+			return toCallFrameRes(callTracer.Result())
+		}}
+	},
+	"prestateTracer": func() *txnTracer {
+		prestateTracer := tracer.NewPrestateTracer()
+
+		return &txnTracer{prestateTracer, func() interface{} {
+			return toPrestateRes(prestateTracer.Result())
+		}}
+	},
+}
+
+// newTxnTracer returns the tracer of the config, the struct logger if none is set
+func newTxnTracer(config *TraceConfig) (*txnTracer, error) {
+	if config == nil || config.Tracer == "" {
+		logger := newStructLogger(config)
+
+		return &txnTracer{logger, func() interface{} {
+			return toTraceTxnRes(logger)
+		}}, nil
+	}
+
+	newTracer, ok := tracers[config.Tracer]
+	if !ok {
+		return nil, fmt.Errorf("tracer %s not found", config.Tracer)
+	}
+
+	return newTracer(), nil
+}
+
+type structLogRes struct {
+	Pc      uint64            `json:"pc"`
+	Op      string            `json:"op"`
+	Gas     uint64            `json:"gas"`
+	GasCost uint64            `json:"gasCost"`
+	Depth   int               `json:"depth"`
+	Error   string            `json:"error,omitempty"`
+	Stack   []string          `json:"stack,omitempty"`
+	Memory  []string          `json:"memory,omitempty"`
+	Storage map[string]string `json:"storage,omitempty"`
+}
+
+type traceTxnRes struct {
+	Gas         uint64         `json:"gas"`
+	Failed      bool           `json:"failed"`
+	ReturnValue string         `json:"returnValue"`
+	StructLogs  []structLogRes `json:"structLogs"`
+}
+
+type traceBlockTxnRes struct {
+	TxHash types.Hash  `json:"txHash"`
+	Result interface{} `json:"result"`
+}
+
+type callFrameRes struct {
+	Type         string          `json:"type"`
+	From         types.Address   `json:"from"`
+	To           *types.Address  `json:"to,omitempty"`
+	Value        *argBig         `json:"value,omitempty"`
+	Gas          argUint64       `json:"gas"`
+	GasUsed      argUint64       `json:"gasUsed"`
+	Input        argBytes        `json:"input"`
+	Output       argBytes        `json:"output,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revertReason,omitempty"`
+	Calls        []*callFrameRes `json:"calls,omitempty"`
+}
+
+type prestateAccountRes struct {
+	Balance *argBig                   `json:"balance"`
+	Nonce   uint64                    `json:"nonce,omitempty"`
+	Code    argBytes                  `json:"code,omitempty"`
+	Storage map[types.Hash]types.Hash `json:"storage,omitempty"`
+}
+
+// TraceTransaction returns the trace of the transaction, re-executed
+// on the state of the block after the preceding transactions
+func (d *Debug) TraceTransaction(ctx context.Context, hash types.Hash, config *TraceConfig) (interface{}, error) {
+	blockHash, indx, ok := d.store.ReadTxLookup(hash)
+	if !ok {
+		return nil, fmt.Errorf("transaction %s not found", hash)
+	}
+
+	block, ok := d.store.GetBlockByHash(blockHash, true)
+	if !ok {
+		return nil, fmt.Errorf("block %s not found", blockHash)
+	}
+
+	if _, ok := txnAtIndex(block, hash, indx); !ok {
+		return nil, fmt.Errorf("transaction %s not found in block %d", hash, block.Number())
+	}
+
+	if block.Number() == 0 {
+		return nil, ErrTraceGenesis
+	}
+
+	txnTracer, err := newTxnTracer(config)
+	if err != nil {
+		return nil, err
+	}
+
+	tracers := make([]runtime.Tracer, indx+1)
+	tracers[indx] = txnTracer.tracer
+
+	if err := d.store.TraceBlock(ctx, block, tracers); err != nil {
+		return nil, err
+	}
+
+	return txnTracer.result(), nil
+}
+
+// TraceBlockByNumber returns the traces of all the transactions of the block
+func (d *Debug) TraceBlockByNumber(ctx context.Context, number BlockNumber, config *TraceConfig) (interface{}, error) {
+	var num uint64
+
+	switch number {
+	case LatestBlockNumber:
+		num = d.store.Header().Number
+	case EarliestBlockNumber:
+		return nil, ErrTraceGenesis
+	case PendingBlockNumber:
+		return nil, fmt.Errorf("tracing the pending block is not supported")
+	default:
+		num = uint64(number)
+	}
+
+	if num == 0 {
+		return nil, ErrTraceGenesis
+	}
+
+	block, ok := d.store.GetBlockByNumber(num, true)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", num)
+	}
+
+	txnTracers := make([]*txnTracer, len(block.Transactions))
+	tracers := make([]runtime.Tracer, len(block.Transactions))
+
+	for indx := range block.Transactions {
+		txnTracer, err := newTxnTracer(config)
+		if err != nil {
+			return nil, err
+		}
+
+		txnTracers[indx] = txnTracer
+		tracers[indx] = txnTracer.tracer
+	}
+
+	if err := d.store.TraceBlock(ctx, block, tracers); err != nil {
+		return nil, err
+	}
+
+	res := make([]traceBlockTxnRes, len(block.Transactions))
+	for indx, txn := range block.Transactions {
+		res[indx] = traceBlockTxnRes{
+			TxHash: txn.Hash,
+			Result: txnTracers[indx].result(),
+		}
+	}
+
+	return res, nil
+}
+
+type badBlockRes struct {
+	Hash   types.Hash  `json:"hash"`
+	Block  *block      `json:"block"`
+	RLP    argBytes    `json:"rlp"`
+	Reason string      `json:"reason"`
+	Time   argUint64   `json:"time"`
+	Extra  interface{} `json:"extra"`
+}
+
+// GetBadBlocks returns the last blocks rejected by the node, along with the rejection reason
+// and the extra data decoded by the consensus
+func (d *Debug) GetBadBlocks() (interface{}, error) {
+	badBlocks := d.store.GetBadBlocks()
+
+	res := make([]*badBlockRes, len(badBlocks))
+	for indx, bad := range badBlocks {
+		res[indx] = &badBlockRes{
+			Hash:   bad.Block.Hash(),
+			Block:  toBlock(bad.Block, true),
+			RLP:    bad.Block.MarshalRLP(),
+			Reason: bad.Reason,
+			Time:   argUint64(bad.Time.Unix()),
+			Extra:  bad.Extra,
+		}
+	}
+
+	return res, nil
+}
+
+func newStructLogger(config *TraceConfig) *tracer.StructLogger {
+	if config == nil {
+		config = &TraceConfig{}
+	}
+
+	return tracer.NewStructLogger(tracer.Config{
+		DisableMemory:  config.DisableMemory,
+		DisableStack:   config.DisableStack,
+		DisableStorage: config.DisableStorage,
+	})
+}
+
+// toTraceTxnRes formats the struct logs like the other clients do, the stack items
+// are hex numbers, the memory is split in 32 bytes words and the storage slots are 32 bytes hex
+func toTraceTxnRes(logger *tracer.StructLogger) *traceTxnRes {
+	res := &traceTxnRes{
+		StructLogs: make([]structLogRes, 0, len(logger.StructLogs())),
+	}
+
+	if result := logger.Result(); result != nil {
+		res.Gas = result.GasUsed
+		res.Failed = result.Failed()
+		res.ReturnValue = hex.EncodeToString(result.ReturnValue)
+	}
+
+	for _, log := range logger.StructLogs() {
+		logRes := structLogRes{
+			Pc:      log.PC,
+			Op:      log.Op,
+			Gas:     log.Gas,
+			GasCost: log.GasCost,
+			Depth:   log.Depth,
+		}
+
+		if log.Err != nil {
+			logRes.Error = log.Err.Error()
+		}
+
+		if log.Stack != nil {
+			logRes.Stack = make([]string, len(log.Stack))
+			for i, val := range log.Stack {
+				logRes.Stack[i] = hex.EncodeBig(val)
+			}
+		}
+
+		if len(log.Memory) != 0 {
+			logRes.Memory = make([]string, 0, (len(log.Memory)+31)/32)
+			for i := 0; i < len(log.Memory); i += 32 {
+				word := make([]byte, 32)
+				copy(word, log.Memory[i:])
+
+				logRes.Memory = append(logRes.Memory, hex.EncodeToString(word))
+			}
+		}
+
+		if log.Storage != nil {
+			logRes.Storage = make(map[string]string, len(log.Storage))
+			for key, val := range log.Storage {
+				logRes.Storage[hex.EncodeToString(key.Bytes())] = hex.EncodeToString(val.Bytes())
+			}
+		}
+
+		res.StructLogs = append(res.StructLogs, logRes)
+	}
+
+	return res
+}
+
+func toCallFrameRes(frame *tracer.CallFrame) *callFrameRes {
+	if frame == nil {
+		return nil
+	}
+
+	res := &callFrameRes{
+		Type:         frame.Type.String(),
+		From:         frame.From,
+		To:           frame.To,
+		Gas:          argUint64(frame.Gas),
+		GasUsed:      argUint64(frame.GasUsed),
+		Input:        frame.Input,
+		Output:       frame.Output,
+		RevertReason: frame.RevertReason,
+	}
+
+	if frame.Value != nil {
+		res.Value = argBigPtr(frame.Value)
+	}
+
+	if frame.Err != nil {
+		res.Error = frame.Err.Error()
+	}
+
+	for _, call := range frame.Calls {
+		res.Calls = append(res.Calls, toCallFrameRes(call))
+	}
+
+	return res
+}
+
+func toPrestateRes(accounts map[types.Address]*tracer.Account) map[types.Address]*prestateAccountRes {
+	res := make(map[types.Address]*prestateAccountRes, len(accounts))
+
+	for addr, account := range accounts {
+		accountRes := &prestateAccountRes{
+			Balance: argBigPtr(account.Balance),
+			Nonce:   account.Nonce,
+			Code:    account.Code,
+		}
+
+		if len(account.Storage) != 0 {
+			accountRes.Storage = account.Storage
+		}
+
+		res[addr] = accountRes
+	}
+
+	return res
+}
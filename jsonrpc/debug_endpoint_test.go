@@ -0,0 +1,254 @@
+package jsonrpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockDebugStore executes a call with a single SSTORE for every traced transaction,
+// and records the number of transactions executed per block
+type mockDebugStore struct {
+	block     *types.Block
+	executed  int
+	badBlocks []*blockchain.BadBlock
+}
+
+func (m *mockDebugStore) Header() *types.Header {
+	return m.block.Header
+}
+
+func (m *mockDebugStore) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	if hash != m.block.Hash() {
+		return nil, false
+	}
+
+	return m.block, true
+}
+
+func (m *mockDebugStore) GetBlockByNumber(num uint64, full bool) (*types.Block, bool) {
+	if num != m.block.Number() {
+		return nil, false
+	}
+
+	return m.block, true
+}
+
+func (m *mockDebugStore) ReadTxLookup(txnHash types.Hash) (types.Hash, uint64, bool) {
+	for indx, txn := range m.block.Transactions {
+		if txn.Hash == txnHash {
+			return m.block.Hash(), uint64(indx), true
+		}
+	}
+
+	return types.ZeroHash, 0, false
+}
+
+func (m *mockDebugStore) GetBadBlocks() []*blockchain.BadBlock {
+	return m.badBlocks
+}
+
+func (m *mockDebugStore) TraceBlock(ctx context.Context, block *types.Block, tracers []runtime.Tracer) error {
+	m.executed = len(tracers)
+
+	for indx, tracer := range tracers {
+		if tracer == nil {
+			continue
+		}
+
+		tracer.CaptureEnter(runtime.Call, types.ZeroAddress, types.ZeroAddress, []byte{0x1}, 50000, big.NewInt(1))
+		tracer.CaptureState(0, "SSTORE", 30000, 1, []*big.Int{big.NewInt(int64(indx)), big.NewInt(1)}, make([]byte, 40))
+		tracer.CaptureStorage(types.ZeroAddress, types.BytesToHash([]byte{1}), types.BytesToHash([]byte{byte(indx)}))
+		tracer.CaptureStateEnd(20000, nil)
+		tracer.CaptureExit([]byte{0x1}, 20000, runtime.ErrExecutionReverted)
+		tracer.CaptureEnd(&runtime.ExecutionResult{
+			ReturnValue: []byte{0x1},
+			GasUsed:     41000,
+			Err:         runtime.ErrExecutionReverted,
+		})
+	}
+
+	return nil
+}
+
+func newDebugTestBlock(number uint64, txns int) *types.Block {
+	block := &types.Block{
+		Header: &types.Header{
+			Number: number,
+		},
+	}
+
+	for i := 0; i < txns; i++ {
+		block.Transactions = append(block.Transactions, &types.Transaction{
+			Nonce: uint64(i),
+			Hash:  types.BytesToHash([]byte{byte(i + 1)}),
+		})
+	}
+
+	block.Header.ComputeHash()
+
+	return block
+}
+
+func TestDebugEndpoint_TraceTransaction(t *testing.T) {
+	store := &mockDebugStore{block: newDebugTestBlock(10, 3)}
+	debug := &Debug{store}
+
+	result, err := debug.TraceTransaction(context.Background(), store.block.Transactions[1].Hash, nil)
+	assert.NoError(t, err)
+
+	// the preceding transactions are executed, the following ones are not
+	assert.Equal(t, 2, store.executed)
+
+	// nolint:forcetypeassert
+	trace := result.(*traceTxnRes)
+
+	assert.Equal(t, uint64(41000), trace.Gas)
+	assert.True(t, trace.Failed)
+	assert.Equal(t, "01", trace.ReturnValue)
+
+	var (
+		word = "0000000000000000000000000000000000000000000000000000000000000000"
+		slot = "0000000000000000000000000000000000000000000000000000000000000001"
+	)
+
+	assert.Equal(t, []structLogRes{
+		{
+			Pc:      0,
+			Op:      "SSTORE",
+			Gas:     30000,
+			GasCost: 20000,
+			Depth:   1,
+			Stack:   []string{"0x1", "0x1"},
+			Memory:  []string{word, word},
+			Storage: map[string]string{slot: slot},
+		},
+	}, trace.StructLogs)
+}
+
+func TestDebugEndpoint_TraceTransaction_Config(t *testing.T) {
+	store := &mockDebugStore{block: newDebugTestBlock(10, 1)}
+	debug := &Debug{store}
+
+	result, err := debug.TraceTransaction(context.Background(), store.block.Transactions[0].Hash, &TraceConfig{
+		DisableMemory:  true,
+		DisableStack:   true,
+		DisableStorage: true,
+	})
+	assert.NoError(t, err)
+
+	// nolint:forcetypeassert
+	log := result.(*traceTxnRes).StructLogs[0]
+
+	assert.Nil(t, log.Stack)
+	assert.Nil(t, log.Memory)
+	assert.Nil(t, log.Storage)
+}
+
+func TestDebugEndpoint_TraceTransaction_NotFound(t *testing.T) {
+	debug := &Debug{&mockDebugStore{block: newDebugTestBlock(10, 1)}}
+
+	_, err := debug.TraceTransaction(context.Background(), types.StringToHash("1234"), nil)
+	assert.Error(t, err)
+}
+
+func TestDebugEndpoint_TraceTransaction_CallTracer(t *testing.T) {
+	store := &mockDebugStore{block: newDebugTestBlock(10, 1)}
+	debug := &Debug{store}
+
+	result, err := debug.TraceTransaction(
+		context.Background(),
+		store.block.Transactions[0].Hash,
+		&TraceConfig{Tracer: "callTracer"},
+	)
+	assert.NoError(t, err)
+
+	value := argBig(*big.NewInt(1))
+
+	assert.Equal(t, &callFrameRes{
+		Type:    "CALL",
+		From:    types.ZeroAddress,
+		To:      &types.ZeroAddress,
+		Value:   &value,
+		Gas:     41000,
+		GasUsed: 41000,
+		Input:   []byte{0x1},
+		Output:  []byte{0x1},
+		Error:   runtime.ErrExecutionReverted.Error(),
+	}, result)
+
+	_, err = debug.TraceTransaction(
+		context.Background(),
+		store.block.Transactions[0].Hash,
+		&TraceConfig{Tracer: "jsTracer"},
+	)
+	assert.Error(t, err)
+}
+
+func TestDebugEndpoint_TraceBlockByNumber(t *testing.T) {
+	store := &mockDebugStore{block: newDebugTestBlock(10, 3)}
+	debug := &Debug{store}
+
+	result, err := debug.TraceBlockByNumber(context.Background(), LatestBlockNumber, nil)
+	assert.NoError(t, err)
+
+	// nolint:forcetypeassert
+	traces := result.([]traceBlockTxnRes)
+
+	assert.Equal(t, 3, store.executed)
+
+	if !assert.Len(t, traces, 3) {
+		t.FailNow()
+	}
+
+	for indx, trace := range traces {
+		assert.Equal(t, store.block.Transactions[indx].Hash, trace.TxHash)
+		// nolint:forcetypeassert
+		assert.Len(t, trace.Result.(*traceTxnRes).StructLogs, 1)
+	}
+
+	_, err = debug.TraceBlockByNumber(context.Background(), EarliestBlockNumber, nil)
+	assert.ErrorIs(t, err, ErrTraceGenesis)
+}
+
+func TestDebugEndpoint_GetBadBlocks(t *testing.T) {
+	bad := &types.Block{
+		Header: (&types.Header{Number: 5, ExtraData: []byte{0x1}}).ComputeHash(),
+	}
+
+	debug := &Debug{&mockDebugStore{
+		badBlocks: []*blockchain.BadBlock{
+			{
+				Block:  bad,
+				Reason: "failed to verify the header: invalid committed seal",
+				Time:   time.Unix(100, 0),
+				Extra:  map[string]string{"seal": "0x1"},
+			},
+		},
+	}}
+
+	result, err := debug.GetBadBlocks()
+	assert.NoError(t, err)
+
+	// nolint:forcetypeassert
+	res := result.([]*badBlockRes)
+	assert.Len(t, res, 1)
+
+	assert.Equal(t, bad.Hash(), res[0].Hash)
+	assert.Equal(t, argUint64(5), res[0].Block.Number)
+	assert.Equal(t, argBytes(bad.MarshalRLP()), res[0].RLP)
+	assert.Equal(t, "failed to verify the header: invalid committed seal", res[0].Reason)
+	assert.Equal(t, argUint64(100), res[0].Time)
+	assert.Equal(t, map[string]string{"seal": "0x1"}, res[0].Extra)
+
+	// the endpoint returns an empty list without bad blocks
+	result, err = (&Debug{&mockDebugStore{}}).GetBadBlocks()
+	assert.NoError(t, err)
+	assert.Equal(t, []*badBlockRes{}, result)
+}
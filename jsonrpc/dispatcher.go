@@ -2,14 +2,18 @@ package jsonrpc
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 	"unicode"
 
+	"github.com/0xPolygon/polygon-edge/state/runtime"
 	"github.com/hashicorp/go-hclog"
 )
 
@@ -23,10 +27,23 @@ type funcData struct {
 	reqt  []reflect.Type
 	fv    reflect.Value
 	isDyn bool
+
+	// withContext is set if the first parameter is the context of the request, it is the case of the methods
+	// executing the EVM. Their executions are bounded by the execution limiter
+	withContext bool
+}
+
+// firstParam returns the index of the first parameter of the request, after the receiver and the context
+func (f *funcData) firstParam() int {
+	if f.withContext {
+		return 2
+	}
+
+	return 1
 }
 
 func (f *funcData) numParams() int {
-	return f.inNum - 1
+	return f.inNum - f.firstParam()
 }
 
 type endpoints struct {
@@ -34,6 +51,10 @@ type endpoints struct {
 	Web3   *Web3
 	Net    *Net
 	TxPool *TxPool
+	IBFT   *IBFT
+	Debug  *Debug
+	Edge   *Edge
+	Evm    *Evm
 }
 
 // Dispatcher handles all json rpc requests by delegating
@@ -42,36 +63,121 @@ type Dispatcher struct {
 	logger        hclog.Logger
 	serviceMap    map[string]*serviceData
 	filterManager *FilterManager
+	requestFilter *requestFilter
+	executions    *executionLimiter
 	endpoints     endpoints
-	chainID       uint64
+	params        dispatcherParams
+}
+
+// dispatcherParams are the parameters of the endpoints served by the dispatcher
+type dispatcherParams struct {
+	chainID uint64
+
+	// the limits of the eth endpoint queries, 0 for no limit
+	feeHistoryLimit uint64
+	blockRangeLimit uint64
+	logsLimit       uint64
+
+	// the number of blocks the node can be behind the network head, and not be reported as syncing
+	syncDistance uint64
+
+	// the number of updates buffered for a subscription before they are dropped
+	subscriptionBufferSize uint64
+
+	// the time after which the filters that are not polled are removed
+	filterTimeout time.Duration
+
+	// the methods enabled and disabled, by name or namespace such as debug_*
+	allowedMethods []string
+	deniedMethods  []string
+
+	// the number of requests per second accepted from a single client,
+	// in total and per method. 0 for no limit
+	rateLimit        uint64
+	methodRateLimits map[string]uint64
+
+	// the maximum number of requests of a batch, 0 for no limit, and the number
+	// of requests of a batch handled at the same time, 0 to handle them in sequence
+	batchLimit   uint64
+	batchWorkers uint64
+
+	// the timeout of the requests executing the EVM, per method if overridden, 0 for no timeout,
+	// and the number of them executed at the same time, 0 for no limit
+	executionTimeout        time.Duration
+	methodExecutionTimeouts map[string]time.Duration
+	executionLimit          uint64
+
+	// set if the node runs the dev consensus, to serve the evm endpoint
+	devMode bool
+
+	// the networks allowed to call the method groups, any client is allowed if nil
+	accessControl *AccessControl
+
+	metrics *Metrics
 }
 
-func newDispatcher(logger hclog.Logger, store JSONRPCStore, chainID uint64) *Dispatcher {
+func newDispatcher(logger hclog.Logger, store JSONRPCStore, params dispatcherParams) (*Dispatcher, error) {
+	if params.metrics == nil {
+		params.metrics = NilMetrics()
+	}
+
+	requestFilter, err := newRequestFilter(params.metrics, params)
+	if err != nil {
+		return nil, err
+	}
+
 	d := &Dispatcher{
-		logger:  logger.Named("dispatcher"),
-		chainID: chainID,
+		logger:        logger.Named("dispatcher"),
+		requestFilter: requestFilter,
+		executions: newExecutionLimiter(
+			params.executionTimeout,
+			params.methodExecutionTimeouts,
+			params.executionLimit,
+			params.metrics,
+		),
+		params: params,
 	}
 
 	if store != nil {
-		d.filterManager = NewFilterManager(logger, store)
+		d.filterManager = NewFilterManager(logger, store, params.subscriptionBufferSize, params.filterTimeout)
 		go d.filterManager.Run()
 	}
 
 	d.registerEndpoints(store)
 
-	return d
+	return d, nil
 }
 
 func (d *Dispatcher) registerEndpoints(store JSONRPCStore) {
-	d.endpoints.Eth = &Eth{d.logger, store, d.chainID, d.filterManager}
-	d.endpoints.Net = &Net{store, d.chainID}
+	d.endpoints.Eth = &Eth{
+		logger:          d.logger,
+		store:           store,
+		chainID:         d.params.chainID,
+		filterManager:   d.filterManager,
+		feeHistoryLimit: d.params.feeHistoryLimit,
+		blockRangeLimit: d.params.blockRangeLimit,
+		logsLimit:       d.params.logsLimit,
+		syncDistance:    d.params.syncDistance,
+	}
+	d.endpoints.Net = &Net{store, d.params.chainID}
 	d.endpoints.Web3 = &Web3{}
 	d.endpoints.TxPool = &TxPool{store}
+	d.endpoints.IBFT = &IBFT{store}
+	d.endpoints.Debug = &Debug{store}
+	d.endpoints.Edge = newEdge(store, d.endpoints.Eth)
 
 	d.registerService("eth", d.endpoints.Eth)
 	d.registerService("net", d.endpoints.Net)
 	d.registerService("web3", d.endpoints.Web3)
 	d.registerService("txpool", d.endpoints.TxPool)
+	d.registerService("ibft", d.endpoints.IBFT)
+	d.registerService("debug", d.endpoints.Debug)
+	d.registerService("edge", d.endpoints.Edge)
+
+	if d.params.devMode {
+		d.endpoints.Evm = &Evm{store}
+		d.registerService("evm", d.endpoints.Evm)
+	}
 }
 
 func (d *Dispatcher) getFnHandler(req Request) (*serviceData, *funcData, Error) {
@@ -136,11 +242,19 @@ func (d *Dispatcher) handleSubscribe(req Request, conn wsConn) (string, Error) {
 	var filterID string
 	if subscribeMethod == "newHeads" {
 		filterID = d.filterManager.NewBlockFilter(conn)
+	} else if subscribeMethod == "newPendingTransactions" {
+		filterID = d.filterManager.NewPendingTxFilter(conn)
 	} else if subscribeMethod == "logs" {
-		logQuery, err := decodeLogQueryFromInterface(params[1])
-		if err != nil {
-			return "", NewInternalError(err.Error())
+		// the filter criteria are optional, all the logs are sent without them
+		logQuery := &LogQuery{}
+
+		if len(params) > 1 {
+			var err error
+			if logQuery, err = decodeLogQueryFromInterface(params[1]); err != nil {
+				return "", NewInternalError(err.Error())
+			}
 		}
+
 		filterID = d.filterManager.NewLogFilter(logQuery, conn)
 	} else {
 		return "", NewSubscriptionNotFoundError(subscribeMethod)
@@ -167,12 +281,55 @@ func (d *Dispatcher) handleUnsubscribe(req Request) (bool, Error) {
 	return d.filterManager.Uninstall(filterID), nil
 }
 
-func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
+// RemoveFilterByWs removes the subscriptions of the closed web socket connection
+func (d *Dispatcher) RemoveFilterByWs(conn wsConn) {
+	if d.filterManager == nil {
+		return
+	}
+
+	d.filterManager.RemoveFilterByWs(conn)
+}
+
+// Close stops the filter manager, the filters don't get the new blocks anymore
+func (d *Dispatcher) Close() {
+	if d.filterManager == nil {
+		return
+	}
+
+	d.filterManager.Close()
+}
+
+// validateReq checks if the request of the client is to an enabled method, within the rate limits
+func (d *Dispatcher) validateReq(req Request, client string) Error {
+	// the methods that do not exist are reported under the same label
+	label := unknownMethod
+	if _, _, err := d.getFnHandler(req); err == nil || req.Method == "eth_subscribe" {
+		label = req.Method
+	}
+
+	return d.requestFilter.validate(client, req.Method, label)
+}
+
+func (d *Dispatcher) HandleWs(ctx context.Context, reqBody []byte, conn wsConn, client string) ([]byte, error) {
+	if isBatchRequest(reqBody) {
+		return d.handleBatch(reqBody, func(req Request) ([]byte, error) {
+			return d.handleWsReq(ctx, req, conn, client)
+		})
+	}
+
 	var req Request
 	if err := json.Unmarshal(reqBody, &req); err != nil {
 		return NewRPCResponse(req.ID, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
 	}
 
+	return d.handleWsReq(ctx, req, conn, client)
+}
+
+func (d *Dispatcher) handleWsReq(ctx context.Context, req Request, conn wsConn, client string) ([]byte, error) {
+	if err := d.validateReq(req, client); err != nil {
+		return NewRPCResponse(req.ID, "2.0", nil, err).Bytes()
+	}
+
 	// if the request method is eth_subscribe we need to create a
 	// new filter with ws connection
 	if req.Method == "eth_subscribe" {
@@ -193,7 +350,7 @@ func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
 	if req.Method == "eth_unsubscribe" {
 		ok, err := d.handleUnsubscribe(req)
 		if err != nil {
-			return nil, err
+			return NewRPCResponse(req.ID, "2.0", nil, err).Bytes()
 		}
 
 		res := "false"
@@ -210,56 +367,102 @@ func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
 	}
 
 	// its a normal query that we handle with the dispatcher
-	resp, err := d.handleReq(req)
-	if err != nil {
-		return nil, err
+	resp, err := d.handleReq(ctx, req)
+
+	return NewRPCResponse(req.ID, "2.0", resp, err).Bytes()
+}
+
+// Handle handles the request of the client, the context is the one of the request.
+// The methods executing the EVM are aborted once it is done
+func (d *Dispatcher) Handle(ctx context.Context, reqBody []byte, client string) ([]byte, error) {
+	if isBatchRequest(reqBody) {
+		return d.handleBatch(reqBody, func(req Request) ([]byte, error) {
+			return d.handleHTTPReq(ctx, req, client)
+		})
 	}
 
+	var req Request
+	if err := json.Unmarshal(reqBody, &req); err != nil {
+		return NewRPCResponse(nil, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
+	}
+
+	if req.Method == "" {
+		return NewRPCResponse(req.ID, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
+	}
+
+	return d.handleHTTPReq(ctx, req, client)
+}
+
+func (d *Dispatcher) handleHTTPReq(ctx context.Context, req Request, client string) ([]byte, error) {
+	if err := d.validateReq(req, client); err != nil {
+		return NewRPCResponse(req.ID, "2.0", nil, err).Bytes()
+	}
+
+	resp, err := d.handleReq(ctx, req)
+
 	return NewRPCResponse(req.ID, "2.0", resp, err).Bytes()
 }
 
-func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
+// isBatchRequest checks if the body is a JSON array of requests
+func isBatchRequest(reqBody []byte) bool {
 	x := bytes.TrimLeft(reqBody, " \t\r\n")
-	if len(x) == 0 {
+
+	return len(x) != 0 && x[0] == '['
+}
+
+// handleBatch handles the requests of a batch with a bounded pool of workers,
+// the responses are in the order of the requests. The requests that are malformed
+// or fail to be handled get an error response, without failing the whole batch
+func (d *Dispatcher) handleBatch(reqBody []byte, handle func(Request) ([]byte, error)) ([]byte, error) {
+	var rawRequests []json.RawMessage
+	if err := json.Unmarshal(reqBody, &rawRequests); err != nil {
 		return NewRPCResponse(nil, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
 	}
 
-	if x[0] == '{' {
-		var req Request
-		if err := json.Unmarshal(reqBody, &req); err != nil {
-			return NewRPCResponse(nil, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
-		}
-
-		if req.Method == "" {
-			return NewRPCResponse(req.ID, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
-		}
+	if len(rawRequests) == 0 {
+		return NewRPCResponse(nil, "2.0", nil, NewInvalidRequestError("Empty batch request")).Bytes()
+	}
 
-		resp, err := d.handleReq(req)
+	if d.params.batchLimit > 0 && uint64(len(rawRequests)) > d.params.batchLimit {
+		return NewRPCResponse(nil, "2.0", nil, NewInvalidRequestError(
+			fmt.Sprintf("batch of %d requests exceeds the limit of %d", len(rawRequests), d.params.batchLimit),
+		)).Bytes()
+	}
 
-		return NewRPCResponse(req.ID, "2.0", resp, err).Bytes()
+	workers := int(d.params.batchWorkers)
+	if workers == 0 {
+		workers = 1
 	}
 
-	// handle batch requests
-	var requests []Request
-	if err := json.Unmarshal(reqBody, &requests); err != nil {
-		return NewRPCResponse(nil, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
+	if workers > len(rawRequests) {
+		workers = len(rawRequests)
 	}
 
-	responses := make([]Response, 0)
+	var (
+		responses = make([]json.RawMessage, len(rawRequests))
+		indexes   = make(chan int)
+		wg        sync.WaitGroup
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
 
-	for _, req := range requests {
-		var response, err = d.handleReq(req)
-		if err != nil {
-			errorResponse := NewRPCResponse(req.ID, "2.0", nil, err)
-			responses = append(responses, errorResponse)
+		go func() {
+			defer wg.Done()
 
-			continue
-		}
+			for indx := range indexes {
+				responses[indx] = d.handleBatchReq(rawRequests[indx], handle)
+			}
+		}()
+	}
 
-		resp := NewRPCResponse(req.ID, "2.0", response, nil)
-		responses = append(responses, resp)
+	for indx := range rawRequests {
+		indexes <- indx
 	}
 
+	close(indexes)
+	wg.Wait()
+
 	respBytes, err := json.Marshal(responses)
 	if err != nil {
 		return NewRPCResponse(nil, "2.0", nil, NewInternalError("Internal error")).Bytes()
@@ -268,7 +471,30 @@ func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
 	return respBytes, nil
 }
 
-func (d *Dispatcher) handleReq(req Request) ([]byte, Error) {
+// handleBatchReq handles a single request of a batch, any failure is returned as its response
+func (d *Dispatcher) handleBatchReq(rawRequest json.RawMessage, handle func(Request) ([]byte, error)) []byte {
+	var (
+		req  Request
+		resp []byte
+		err  error
+	)
+
+	if err = json.Unmarshal(rawRequest, &req); err != nil || req.Method == "" {
+		resp, err = NewRPCResponse(req.ID, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
+	} else {
+		resp, err = handle(req)
+	}
+
+	if err != nil {
+		d.logInternalError(req.Method, err)
+
+		resp, _ = NewRPCResponse(req.ID, "2.0", nil, NewInternalError("Internal error")).Bytes()
+	}
+
+	return resp
+}
+
+func (d *Dispatcher) handleReq(ctx context.Context, req Request) ([]byte, Error) {
 	d.logger.Debug("request", "method", req.Method, "id", req.ID)
 
 	service, fd, ferr := d.getFnHandler(req)
@@ -281,10 +507,10 @@ func (d *Dispatcher) handleReq(req Request) ([]byte, Error) {
 
 	inputs := make([]interface{}, fd.numParams())
 
-	for i := 0; i < fd.inNum-1; i++ {
-		val := reflect.New(fd.reqt[i+1])
+	for i := range inputs {
+		val := reflect.New(fd.reqt[fd.firstParam()+i])
 		inputs[i] = val.Interface()
-		inArgs[i+1] = val.Elem()
+		inArgs[fd.firstParam()+i] = val.Elem()
 	}
 
 	if fd.numParams() > 0 {
@@ -293,10 +519,36 @@ func (d *Dispatcher) handleReq(req Request) ([]byte, Error) {
 		}
 	}
 
+	if fd.withContext {
+		execCtx, release, err := d.executions.begin(ctx, req.Method)
+		if err != nil {
+			d.params.metrics.AbortedExecutions.With("method", req.Method).Add(1)
+
+			return nil, err
+		}
+
+		defer release()
+
+		ctx = execCtx
+		inArgs[1] = reflect.ValueOf(ctx)
+	}
+
 	output := fd.fv.Call(inArgs)
 	if err := getError(output[1]); err != nil {
 		d.logInternalError(req.Method, err)
 
+		if errors.Is(err, runtime.ErrExecutionAborted) {
+			d.params.metrics.AbortedExecutions.With("method", req.Method).Add(1)
+
+			return nil, d.executions.abortedError(ctx, req.Method)
+		}
+
+		// the endpoints returning their own error codes keep them
+		var rpcErr Error
+		if errors.As(err, &rpcErr) {
+			return nil, rpcErr
+		}
+
 		return nil, NewInvalidRequestError(err.Error())
 	}
 
@@ -355,9 +607,12 @@ func (d *Dispatcher) registerService(serviceName string, service interface{}) {
 		if fd.inNum, fd.reqt, err = validateFunc(funcName, fd.fv, true); err != nil {
 			panic(fmt.Sprintf("jsonrpc: %s", err))
 		}
+		// the context of the request is not one of its parameters
+		fd.withContext = fd.inNum > 1 && fd.reqt[1] == contextType
+
 		// check if last item is a pointer
 		if fd.numParams() != 0 {
-			last := fd.reqt[fd.numParams()]
+			last := fd.reqt[fd.inNum-1]
 			if last.Kind() == reflect.Ptr {
 				fd.isDyn = true
 			}
@@ -1,12 +1,18 @@
 package jsonrpc
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/txpool"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
@@ -62,7 +68,8 @@ func TestDispatcher_HandleWebsocketConnection_EthSubscribe(t *testing.T) {
 		t.Parallel()
 
 		store := newMockStore()
-		dispatcher := newDispatcher(hclog.NewNullLogger(), store, 0)
+		dispatcher, err := newDispatcher(hclog.NewNullLogger(), store, dispatcherParams{})
+		assert.NoError(t, err)
 
 		mockConnection := &mockWsConn{
 			msgCh: make(chan []byte, 1),
@@ -72,7 +79,7 @@ func TestDispatcher_HandleWebsocketConnection_EthSubscribe(t *testing.T) {
 		"method": "eth_subscribe",
 		"params": ["newHeads"]
 	}`)
-		if _, err := dispatcher.HandleWs(req, mockConnection); err != nil {
+		if _, err := dispatcher.HandleWs(context.Background(), req, mockConnection, ""); err != nil {
 			t.Fatal(err)
 		}
 
@@ -96,7 +103,8 @@ func TestDispatcher_HandleWebsocketConnection_EthSubscribe(t *testing.T) {
 
 func TestDispatcher_WebsocketConnection_RequestFormats(t *testing.T) {
 	store := newMockStore()
-	dispatcher := newDispatcher(hclog.NewNullLogger(), store, 0)
+	dispatcher, err := newDispatcher(hclog.NewNullLogger(), store, dispatcherParams{})
+	assert.NoError(t, err)
 
 	mockConnection := &mockWsConn{
 		msgCh: make(chan []byte, 1),
@@ -147,7 +155,7 @@ func TestDispatcher_WebsocketConnection_RequestFormats(t *testing.T) {
 		},
 	}
 	for _, c := range cases {
-		data, err := dispatcher.HandleWs(c.msg, mockConnection)
+		data, err := dispatcher.HandleWs(context.Background(), c.msg, mockConnection, "")
 		resp := new(SuccessResponse)
 		merr := json.Unmarshal(data, resp)
 
@@ -197,14 +205,33 @@ func (m *mockService) Filter(f LogQuery) (interface{}, error) {
 	return nil, nil
 }
 
+func (m *mockService) Reject(tx string) (interface{}, error) {
+	return nil, NewTxRejectedError(&txpool.TxRejectedError{Reason: txpool.ErrNonceTooLow})
+}
+
+func (m *mockService) Fail() (interface{}, error) {
+	return nil, errors.New("failed")
+}
+
+// Execute runs until the execution is aborted
+func (m *mockService) Execute(ctx context.Context, f BlockNumber) (interface{}, error) {
+	m.msgCh <- f
+
+	<-ctx.Done()
+
+	return nil, fmt.Errorf("unable to execute call: %w", runtime.ErrExecutionAborted)
+}
+
 func TestDispatcherFuncDecode(t *testing.T) {
 	srv := &mockService{msgCh: make(chan interface{}, 10)}
 
-	dispatcher := newDispatcher(hclog.NewNullLogger(), newMockStore(), 0)
+	dispatcher, err := newDispatcher(hclog.NewNullLogger(), newMockStore(), dispatcherParams{})
+	assert.NoError(t, err)
+
 	dispatcher.registerService("mock", srv)
 
 	handleReq := func(typ string, msg string) interface{} {
-		_, err := dispatcher.handleReq(Request{
+		_, err := dispatcher.handleReq(context.Background(), Request{
 			Method: "mock_" + typ,
 			Params: []byte(msg),
 		})
@@ -265,17 +292,67 @@ func TestDispatcherFuncDecode(t *testing.T) {
 	}
 }
 
+func TestDispatcherErrorCodes(t *testing.T) {
+	dispatcher, err := newDispatcher(hclog.NewNullLogger(), newMockStore(), dispatcherParams{})
+	assert.NoError(t, err)
+
+	dispatcher.registerService("mock", &mockService{})
+
+	// the endpoints returning their own error codes keep them
+	_, rpcErr := dispatcher.handleReq(context.Background(), Request{Method: "mock_reject", Params: []byte(`["0x1"]`)})
+	if assert.Error(t, rpcErr) {
+		assert.Equal(t, -32010, rpcErr.ErrorCode())
+		assert.Equal(t, "nonce too low", rpcErr.Error())
+	}
+
+	_, rpcErr = dispatcher.handleReq(context.Background(), Request{Method: "mock_fail"})
+	if assert.Error(t, rpcErr) {
+		assert.Equal(t, -32600, rpcErr.ErrorCode())
+		assert.Equal(t, "failed", rpcErr.Error())
+	}
+}
+
+func TestDispatcherExecutionTimeout(t *testing.T) {
+	srv := &mockService{msgCh: make(chan interface{}, 1)}
+
+	dispatcher, err := newDispatcher(hclog.NewNullLogger(), newMockStore(), dispatcherParams{
+		executionTimeout: 10 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+
+	dispatcher.registerService("mock", srv)
+
+	// the context is not one of the params
+	_, rpcErr := dispatcher.handleReq(context.Background(), Request{Method: "mock_execute", Params: []byte(`["latest"]`)})
+	assert.Equal(t, LatestBlockNumber, <-srv.msgCh)
+
+	if assert.Error(t, rpcErr) {
+		assert.Equal(t, -32007, rpcErr.ErrorCode())
+		assert.Equal(t, "execution aborted (timeout = 10ms)", rpcErr.Error())
+	}
+
+	// the execution is aborted with the request
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, rpcErr = dispatcher.handleReq(ctx, Request{Method: "mock_execute", Params: []byte(`["latest"]`)})
+	if assert.Error(t, rpcErr) {
+		assert.Equal(t, "execution aborted (canceled)", rpcErr.Error())
+	}
+}
+
 func TestDispatcherBatchRequest(t *testing.T) {
-	dispatcher := newDispatcher(hclog.NewNullLogger(), newMockStore(), 0)
+	dispatcher, err := newDispatcher(hclog.NewNullLogger(), newMockStore(), dispatcherParams{})
+	assert.NoError(t, err)
 
 	// test with leading whitespace ("  \t\n\n\r")
 	leftBytes := []byte{0x20, 0x20, 0x09, 0x0A, 0x0A, 0x0D}
-	resp, err := dispatcher.Handle(append(leftBytes, []byte(`[
+	resp, err := dispatcher.Handle(context.Background(), append(leftBytes, []byte(`[
     {"id":1,"jsonrpc":"2.0","method":"eth_getBalance","params":["0x1", true]},
     {"id":2,"jsonrpc":"2.0","method":"eth_getBlockByNumber","params":["0x2", true]},
     {"id":3,"jsonrpc":"2.0","method":"eth_getBlockByNumber","params":["0x3", true]},
 	{"id":4,"jsonrpc":"2.0","method": "web3_sha3","params": ["0x68656c6c6f20776f726c64"]}
-]`)...))
+]`)...), "")
 	assert.NoError(t, err)
 
 	var res []SuccessResponse
@@ -288,3 +365,147 @@ func TestDispatcherBatchRequest(t *testing.T) {
 	assert.Equal(t, res[0].Error, jsonerr)
 	assert.Nil(t, res[3].Error)
 }
+
+func TestDispatcherBatchLimit(t *testing.T) {
+	dispatcher, err := newDispatcher(hclog.NewNullLogger(), newMockStore(), dispatcherParams{
+		batchLimit: 2,
+	})
+	assert.NoError(t, err)
+
+	resp, err := dispatcher.Handle(context.Background(), []byte(`[
+	{"id":1,"jsonrpc":"2.0","method":"web3_clientVersion","params":[]},
+	{"id":2,"jsonrpc":"2.0","method":"web3_clientVersion","params":[]},
+	{"id":3,"jsonrpc":"2.0","method":"web3_clientVersion","params":[]}
+]`), "")
+	assert.NoError(t, err)
+
+	var res ErrorResponse
+
+	assert.NoError(t, json.Unmarshal(resp, &res))
+	assert.Equal(t, -32600, res.Error.Code)
+}
+
+func TestDispatcherRequestFilter(t *testing.T) {
+	dispatcher, err := newDispatcher(hclog.NewNullLogger(), newMockStore(), dispatcherParams{
+		deniedMethods:    []string{"eth_*"},
+		methodRateLimits: map[string]uint64{"web3_clientVersion": 1},
+	})
+	assert.NoError(t, err)
+
+	// every request of the batch is filtered on its own
+	resp, err := dispatcher.Handle(context.Background(), []byte(`[
+	{"id":1,"jsonrpc":"2.0","method":"eth_blockNumber","params":[]},
+	{"id":2,"jsonrpc":"2.0","method":"web3_clientVersion","params":[]},
+	{"id":3,"jsonrpc":"2.0","method":"web3_clientVersion","params":[]}
+]`), "client")
+	assert.NoError(t, err)
+
+	var res []SuccessResponse
+
+	assert.NoError(t, expectBatchJSONResult(resp, &res))
+
+	if !assert.Len(t, res, 3) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, -32601, res[0].Error.Code)
+	assert.Nil(t, res[1].Error)
+	assert.Equal(t, -32005, res[2].Error.Code)
+	assert.Equal(t, map[string]interface{}{"retryAfter": float64(1)}, res[2].Error.Data)
+
+	// the web socket requests are filtered too
+	resp, err = dispatcher.HandleWs(
+		context.Background(),
+		[]byte(`{"id":1,"jsonrpc":"2.0","method":"eth_subscribe","params":["newHeads"]}`),
+		&mockWsConn{msgCh: make(chan []byte, 1)},
+		"client",
+	)
+	assert.NoError(t, err)
+	assert.Contains(t, string(resp), `"code":-32601`)
+}
+
+func TestDispatcherBatchRequest_Isolation(t *testing.T) {
+	dispatcher, err := newDispatcher(hclog.NewNullLogger(), newMockStore(), dispatcherParams{
+		batchWorkers: 4,
+	})
+	assert.NoError(t, err)
+
+	// the malformed requests only fail their own response
+	requests := []string{`1`, `{"id":"a","jsonrpc":"2.0","method":5}`, `{"id":"b","jsonrpc":"2.0"}`}
+	for i := 0; i < 20; i++ {
+		requests = append(requests, fmt.Sprintf(`{"id":%d,"jsonrpc":"2.0","method":"web3_clientVersion"}`, i))
+	}
+
+	for _, handle := range []func([]byte) ([]byte, error){
+		func(body []byte) ([]byte, error) {
+			return dispatcher.Handle(context.Background(), body, "")
+		},
+		func(body []byte) ([]byte, error) {
+			return dispatcher.HandleWs(context.Background(), body, &mockWsConn{msgCh: make(chan []byte, 1)}, "")
+		},
+	} {
+		resp, err := handle([]byte("[" + strings.Join(requests, ",") + "]"))
+		assert.NoError(t, err)
+
+		var res []SuccessResponse
+
+		assert.NoError(t, expectBatchJSONResult(resp, &res))
+
+		if !assert.Len(t, res, len(requests)) {
+			t.FailNow()
+		}
+
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, -32600, res[i].Error.Code)
+		}
+
+		assert.Equal(t, "a", res[1].ID)
+		assert.Equal(t, "b", res[2].ID)
+
+		// the responses are in the order of the requests
+		for i, response := range res[3:] {
+			assert.Nil(t, response.Error)
+			assert.Equal(t, float64(i), response.ID)
+		}
+	}
+
+	resp, err := dispatcher.Handle(context.Background(), []byte(`[]`), "")
+	assert.NoError(t, err)
+	assert.Contains(t, string(resp), `"code":-32600`)
+}
+
+func TestDispatcherFilterAPI(t *testing.T) {
+	store := newMockStore()
+
+	dispatcher, err := newDispatcher(hclog.NewNullLogger(), store, dispatcherParams{})
+	assert.NoError(t, err)
+
+	handle := func(method string, params string) []byte {
+		body := []byte(`{"id":1,"jsonrpc":"2.0","method":"` + method + `","params":` + params + `}`)
+
+		resp, err := dispatcher.Handle(context.Background(), body, "")
+		assert.NoError(t, err)
+
+		return resp
+	}
+
+	var id string
+
+	assert.NoError(t, expectJSONResult(handle("eth_newPendingTransactionFilter", `[]`), &id))
+
+	store.emitTxEvent(hash1)
+	time.Sleep(500 * time.Millisecond)
+
+	// the changes are returned as an array
+	var hashes []types.Hash
+
+	assert.NoError(t, expectJSONResult(handle("eth_getFilterChanges", `["`+id+`"]`), &hashes))
+	assert.Equal(t, []types.Hash{hash1}, hashes)
+
+	// only the log filters have logs
+	assert.EqualError(
+		t,
+		expectJSONResult(handle("eth_getFilterLogs", `["`+id+`"]`), &hashes),
+		`{"code":-32600,"message":"filter is not a log filter"}`,
+	)
+}
@@ -0,0 +1,380 @@
+package jsonrpc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/state"
+	txpoolProto "github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+var (
+	ErrInternalTxsNotIndexed = errors.New("internal transactions not indexed")
+)
+
+// stateDiffCacheSize is the number of blocks the state diffs are cached for,
+// the explorers request the same recent blocks repeatedly
+const stateDiffCacheSize = 32
+
+// maxTxSyncTimeout is the longest a transaction sent synchronously is waited for, and the default timeout,
+// so the requests don't hold the connections indefinitely
+const maxTxSyncTimeout = 60 * time.Second
+
+// edgeStore provides access to the methods needed by the edge endpoint
+type edgeStore interface {
+	// Header returns the current header of the chain (genesis if empty)
+	Header() *types.Header
+
+	// GetHeaderByNumber returns the header of the canonical block by its number
+	GetHeaderByNumber(n uint64) (*types.Header, bool)
+
+	// ReadTxLookup returns the hash of the block in which a given txn was mined,
+	// and the index of the txn in the block
+	ReadTxLookup(txnHash types.Hash) (types.Hash, uint64, bool)
+
+	// GetInternalTxsByHash returns the internal transactions of the block, if it was indexed
+	GetInternalTxsByHash(hash types.Hash) ([]*types.InternalTransaction, bool)
+
+	// GetBlockByNumber returns a block using the provided number
+	GetBlockByNumber(num uint64, full bool) (*types.Block, bool)
+
+	// GetStateDiff re-executes the transactions of the block on the parent state,
+	// and returns the accounts changed by each of them
+	GetStateDiff(block *types.Block) ([]map[types.Address]*state.AccountDiff, error)
+
+	// SubscribeEvents subscribes for chain head events
+	SubscribeEvents() (<-chan *blockchain.Event, func())
+
+	// SubscribeTxEvents subscribes for tx pool events
+	SubscribeTxEvents(eventTypes ...txpoolProto.EventType) (<-chan *txpoolProto.TxPoolEvent, func())
+}
+
+// Edge is the edge jsonrpc endpoint, it serves the data indexed by the node
+type Edge struct {
+	store edgeStore
+
+	// eth sends the transactions and formats their receipts
+	eth *Eth
+
+	// stateDiffs are the formatted state diffs of the recently requested blocks, by block hash
+	stateDiffs *lru.Cache
+}
+
+func newEdge(store edgeStore, eth *Eth) *Edge {
+	stateDiffs, _ := lru.New(stateDiffCacheSize)
+
+	return &Edge{
+		store:      store,
+		eth:        eth,
+		stateDiffs: stateDiffs,
+	}
+}
+
+type internalTxRes struct {
+	TxHash types.Hash    `json:"transactionHash"`
+	From   types.Address `json:"from"`
+	To     types.Address `json:"to"`
+	Value  *argBig       `json:"value"`
+	Depth  argUint64     `json:"depth"`
+}
+
+// GetInternalTransactions returns the value transfers made by the call frames of the transactions
+// of the canonical block, or of the transaction. The internal transactions are indexed while
+// the blocks are imported, if the node indexes them
+func (e *Edge) GetInternalTransactions(filter BlockNumberOrTxHash) (interface{}, error) {
+	if filter.TxHash != nil {
+		return e.getTxInternalTransactions(*filter.TxHash)
+	}
+
+	var num uint64
+
+	switch *filter.BlockNumber {
+	case LatestBlockNumber:
+		num = e.store.Header().Number
+	case EarliestBlockNumber:
+		num = 0
+	case PendingBlockNumber:
+		return nil, fmt.Errorf("the internal transactions of the pending block are not indexed")
+	default:
+		num = uint64(*filter.BlockNumber)
+	}
+
+	// the genesis has no transactions, it is not executed
+	if num == 0 {
+		return []*internalTxRes{}, nil
+	}
+
+	header, ok := e.store.GetHeaderByNumber(num)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", num)
+	}
+
+	internalTxs, ok := e.store.GetInternalTxsByHash(header.Hash)
+	if !ok {
+		return nil, fmt.Errorf("%w: block %d", ErrInternalTxsNotIndexed, num)
+	}
+
+	return toInternalTxsRes(internalTxs, nil), nil
+}
+
+// getTxInternalTransactions returns the internal transactions of the transaction,
+// the lookups point to the canonical block of the transaction
+func (e *Edge) getTxInternalTransactions(hash types.Hash) (interface{}, error) {
+	blockHash, _, ok := e.store.ReadTxLookup(hash)
+	if !ok {
+		return nil, fmt.Errorf("transaction %s not found", hash)
+	}
+
+	internalTxs, ok := e.store.GetInternalTxsByHash(blockHash)
+	if !ok {
+		return nil, fmt.Errorf("%w: block %s", ErrInternalTxsNotIndexed, blockHash)
+	}
+
+	return toInternalTxsRes(internalTxs, &hash), nil
+}
+
+// toInternalTxsRes formats the internal transactions, the ones of the transaction only if set
+func toInternalTxsRes(internalTxs []*types.InternalTransaction, txHash *types.Hash) []*internalTxRes {
+	res := []*internalTxRes{}
+
+	for _, internalTx := range internalTxs {
+		if txHash != nil && internalTx.TxHash != *txHash {
+			continue
+		}
+
+		res = append(res, &internalTxRes{
+			TxHash: internalTx.TxHash,
+			From:   internalTx.From,
+			To:     internalTx.To,
+			Value:  argBigPtr(internalTx.Value),
+			Depth:  argUint64(internalTx.Depth),
+		})
+	}
+
+	return res
+}
+
+// changeRes is the change of a value in the parity format, "=" if it is unchanged,
+// {"+": value} if it is created, {"-": value} if it is removed and {"*": {"from", "to"}} otherwise
+type changeRes interface{}
+
+type fromToRes struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+type accountDiffRes struct {
+	Balance changeRes                `json:"balance"`
+	Nonce   changeRes                `json:"nonce"`
+	Code    changeRes                `json:"code"`
+	Storage map[types.Hash]changeRes `json:"storage"`
+}
+
+// txStateDiffRes is the state diff of a transaction, in the format of the parity trace_replayBlockTransactions
+type txStateDiffRes struct {
+	TxHash    types.Hash                        `json:"transactionHash"`
+	StateDiff map[types.Address]*accountDiffRes `json:"stateDiff"`
+	Trace     []interface{}                     `json:"trace"`
+	VMTrace   interface{}                       `json:"vmTrace"`
+}
+
+// GetStateDiff returns the accounts and the storage slots changed by each transaction of the canonical block,
+// with their values before and after the transaction. The block is re-executed on the parent state
+func (e *Edge) GetStateDiff(number BlockNumber) (interface{}, error) {
+	var num uint64
+
+	switch number {
+	case LatestBlockNumber:
+		num = e.store.Header().Number
+	case EarliestBlockNumber:
+		num = 0
+	case PendingBlockNumber:
+		return nil, fmt.Errorf("the state diff of the pending block is not supported")
+	default:
+		if number < 0 {
+			return nil, fmt.Errorf("invalid argument 0: block number larger than int64")
+		}
+
+		num = uint64(number)
+	}
+
+	block, ok := e.store.GetBlockByNumber(num, true)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", num)
+	}
+
+	if res, ok := e.stateDiffs.Get(block.Hash()); ok {
+		return res, nil
+	}
+
+	// the genesis has no transactions, it is not executed
+	res := []*txStateDiffRes{}
+
+	if num != 0 {
+		diffs, err := e.store.GetStateDiff(block)
+		if err != nil {
+			return nil, err
+		}
+
+		for indx, diff := range diffs {
+			res = append(res, &txStateDiffRes{
+				TxHash:    block.Transactions[indx].Hash,
+				StateDiff: toStateDiffRes(diff),
+				Trace:     []interface{}{},
+			})
+		}
+	}
+
+	e.stateDiffs.Add(block.Hash(), res)
+
+	return res, nil
+}
+
+// toStateDiffRes formats the changes of the accounts
+func toStateDiffRes(diffs map[types.Address]*state.AccountDiff) map[types.Address]*accountDiffRes {
+	res := make(map[types.Address]*accountDiffRes, len(diffs))
+
+	for addr, diff := range diffs {
+		accountRes := &accountDiffRes{
+			Storage: make(map[types.Hash]changeRes, len(diff.Storage)),
+		}
+
+		switch {
+		case diff.Before == nil:
+			accountRes.Balance = map[string]interface{}{"+": argBigPtr(diff.After.Balance)}
+			accountRes.Nonce = map[string]interface{}{"+": argUint64(diff.After.Nonce)}
+			accountRes.Code = map[string]interface{}{"+": argBytes(diff.After.Code)}
+		case diff.After == nil:
+			accountRes.Balance = map[string]interface{}{"-": argBigPtr(diff.Before.Balance)}
+			accountRes.Nonce = map[string]interface{}{"-": argUint64(diff.Before.Nonce)}
+			accountRes.Code = map[string]interface{}{"-": argBytes(diff.Before.Code)}
+		default:
+			accountRes.Balance = toChangeRes(
+				diff.Before.Balance.Cmp(diff.After.Balance) == 0,
+				argBigPtr(diff.Before.Balance),
+				argBigPtr(diff.After.Balance),
+			)
+			accountRes.Nonce = toChangeRes(
+				diff.Before.Nonce == diff.After.Nonce,
+				argUint64(diff.Before.Nonce),
+				argUint64(diff.After.Nonce),
+			)
+			accountRes.Code = toChangeRes(
+				!diff.CodeChanged(),
+				argBytes(diff.Before.Code),
+				argBytes(diff.After.Code),
+			)
+		}
+
+		for slot, storageDiff := range diff.Storage {
+			switch {
+			case diff.Before == nil:
+				accountRes.Storage[slot] = map[string]interface{}{"+": storageDiff.After}
+			case diff.After == nil:
+				accountRes.Storage[slot] = map[string]interface{}{"-": storageDiff.Before}
+			default:
+				accountRes.Storage[slot] = toChangeRes(false, storageDiff.Before, storageDiff.After)
+			}
+		}
+
+		res[addr] = accountRes
+	}
+
+	return res
+}
+
+// toChangeRes formats the change of a value of an account existing before and after the transaction
+func toChangeRes(unchanged bool, from, to interface{}) changeRes {
+	if unchanged {
+		return "="
+	}
+
+	return map[string]interface{}{"*": &fromToRes{from, to}}
+}
+
+// SendRawTransactionSync sends the raw transaction and waits for its receipt, up to the timeout in milliseconds.
+// The timeout is capped by maxTxSyncTimeout, which is also the default. The transaction is kept in the pool
+// past the timeout, the error data holds its hash so the client keeps tracking it
+func (e *Edge) SendRawTransactionSync(input string, timeoutMs *evmQuantity) (interface{}, error) {
+	timeout := maxTxSyncTimeout
+	if timeoutMs != nil && *timeoutMs != 0 && uint64(*timeoutMs) < uint64(maxTxSyncTimeout/time.Millisecond) {
+		timeout = time.Duration(*timeoutMs) * time.Millisecond
+	}
+
+	// the events are subscribed for before the transaction is added, so none of them is missed
+	eventsCh, cancelEvents := e.store.SubscribeEvents()
+	defer func() {
+		cancelEvents()
+	}()
+
+	txEventsCh, cancelTxEvents := e.store.SubscribeTxEvents(
+		txpoolProto.EventType_DROPPED,
+		txpoolProto.EventType_REPLACED,
+		txpoolProto.EventType_PRUNED_PROMOTED,
+		txpoolProto.EventType_PRUNED_ENQUEUED,
+		txpoolProto.EventType_EVICTED_UNDERPRICED,
+	)
+	defer cancelTxEvents()
+
+	res, err := e.eth.SendRawTransaction(input)
+	if err != nil {
+		return nil, err
+	}
+
+	txHash, _ := res.(string)
+	hash := types.StringToHash(txHash)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case _, ok := <-eventsCh:
+			if !ok {
+				// unsubscribed by the blockchain as a slow consumer, subscribe again,
+				// the receipt is checked at once as the heads in between are missed
+				eventsCh, cancelEvents = e.store.SubscribeEvents()
+			}
+
+			// the receipt is checked at every new head
+			if receipt, err := e.eth.GetTransactionReceipt(hash); receipt != nil || err != nil {
+				return receipt, err
+			}
+
+		case evnt, ok := <-txEventsCh:
+			if !ok {
+				// the pool is closed, the receipt is still waited for at the new heads
+				txEventsCh = nil
+
+				continue
+			}
+
+			if evnt.TxHash != txHash {
+				continue
+			}
+
+			switch evnt.Type {
+			case txpoolProto.EventType_DROPPED:
+				return nil, newTxWaitError(txWaitDroppedErrorCode, txHash, "dropped from the pool")
+			case txpoolProto.EventType_REPLACED:
+				return nil, newTxWaitError(txWaitReplacedErrorCode, txHash, "replaced in the pool")
+			case txpoolProto.EventType_EVICTED_UNDERPRICED:
+				return nil, newTxWaitError(txWaitDroppedErrorCode, txHash, "evicted from the pool below the base fee")
+			default:
+				// the transaction is pruned once mined, or once its nonce is used by another transaction
+				if receipt, err := e.eth.GetTransactionReceipt(hash); receipt != nil || err != nil {
+					return receipt, err
+				}
+
+				return nil, newTxWaitError(txWaitReplacedErrorCode, txHash, "nonce used by another transaction")
+			}
+
+		case <-timer.C:
+			return nil, newTxWaitError(txWaitTimeoutErrorCode, txHash, "not mined within "+timeout.String())
+		}
+	}
+}
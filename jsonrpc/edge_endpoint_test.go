@@ -0,0 +1,351 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/state"
+	txpoolProto "github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockEdgeStore struct {
+	headers     map[uint64]*types.Header
+	lookups     map[types.Hash]types.Hash
+	internalTxs map[types.Hash][]*types.InternalTransaction
+	stateDiffs  map[types.Hash][]map[types.Address]*state.AccountDiff
+
+	events     chan *blockchain.Event
+	txEventsCh chan *txpoolProto.TxPoolEvent
+
+	// executed is the number of blocks re-executed for their state diffs
+	executed int
+}
+
+func (m *mockEdgeStore) Header() *types.Header {
+	return m.headers[uint64(len(m.headers)-1)]
+}
+
+func (m *mockEdgeStore) GetHeaderByNumber(n uint64) (*types.Header, bool) {
+	header, ok := m.headers[n]
+
+	return header, ok
+}
+
+func (m *mockEdgeStore) ReadTxLookup(txnHash types.Hash) (types.Hash, uint64, bool) {
+	blockHash, ok := m.lookups[txnHash]
+
+	return blockHash, 0, ok
+}
+
+func (m *mockEdgeStore) GetInternalTxsByHash(hash types.Hash) ([]*types.InternalTransaction, bool) {
+	internalTxs, ok := m.internalTxs[hash]
+
+	return internalTxs, ok
+}
+
+func (m *mockEdgeStore) GetBlockByNumber(n uint64, full bool) (*types.Block, bool) {
+	header, ok := m.headers[n]
+	if !ok {
+		return nil, false
+	}
+
+	block := &types.Block{Header: header}
+
+	for range m.stateDiffs[header.Hash] {
+		block.Transactions = append(block.Transactions, &types.Transaction{Hash: hash1})
+	}
+
+	return block, true
+}
+
+func (m *mockEdgeStore) GetStateDiff(block *types.Block) ([]map[types.Address]*state.AccountDiff, error) {
+	m.executed++
+
+	return m.stateDiffs[block.Hash()], nil
+}
+
+func (m *mockEdgeStore) SubscribeEvents() (<-chan *blockchain.Event, func()) {
+	return m.events, func() {}
+}
+
+func (m *mockEdgeStore) SubscribeTxEvents(
+	eventTypes ...txpoolProto.EventType,
+) (<-chan *txpoolProto.TxPoolEvent, func()) {
+	return m.txEventsCh, func() {}
+}
+
+func newMockEdgeStore() *mockEdgeStore {
+	store := &mockEdgeStore{
+		headers:     map[uint64]*types.Header{},
+		lookups:     map[types.Hash]types.Hash{},
+		internalTxs: map[types.Hash][]*types.InternalTransaction{},
+		stateDiffs:  map[types.Hash][]map[types.Address]*state.AccountDiff{},
+		events:      make(chan *blockchain.Event),
+		txEventsCh:  make(chan *txpoolProto.TxPoolEvent, 16),
+	}
+
+	for i := uint64(0); i < 3; i++ {
+		store.headers[i] = &types.Header{Number: i, Hash: types.BytesToHash([]byte{byte(i + 1)})}
+	}
+
+	blockHash := store.headers[2].Hash
+
+	store.lookups[hash1] = blockHash
+	store.lookups[hash2] = blockHash
+	store.internalTxs[blockHash] = []*types.InternalTransaction{
+		{TxHash: hash1, From: addr0, To: addr1, Value: big.NewInt(1), Depth: 1},
+		{TxHash: hash2, From: addr1, To: addr2, Value: big.NewInt(2), Depth: 2},
+	}
+	store.stateDiffs[blockHash] = []map[types.Address]*state.AccountDiff{
+		{
+			addr0: {
+				Before: &state.AccountState{Balance: big.NewInt(10), Nonce: 1},
+				After:  &state.AccountState{Balance: big.NewInt(5), Nonce: 2},
+				Storage: map[types.Hash]*state.StorageDiff{
+					hash1: {Before: types.Hash{}, After: hash2},
+				},
+			},
+			addr1: {
+				After:   &state.AccountState{Balance: big.NewInt(5), Code: []byte{0x1}},
+				Storage: map[types.Hash]*state.StorageDiff{},
+			},
+		},
+	}
+
+	return store
+}
+
+func TestEdgeEndpoint_GetInternalTransactions(t *testing.T) {
+	t.Parallel()
+
+	edge := newEdge(newMockEdgeStore(), nil)
+
+	latest := LatestBlockNumber
+	res, err := edge.GetInternalTransactions(BlockNumberOrTxHash{BlockNumber: &latest})
+	assert.NoError(t, err)
+
+	if internalTxs, ok := res.([]*internalTxRes); assert.True(t, ok) && assert.Len(t, internalTxs, 2) {
+		assert.Equal(t, hash1, internalTxs[0].TxHash)
+		assert.Equal(t, addr0, internalTxs[0].From)
+		assert.Equal(t, addr1, internalTxs[0].To)
+		assert.Equal(t, argBigPtr(big.NewInt(1)), internalTxs[0].Value)
+		assert.Equal(t, argUint64(1), internalTxs[0].Depth)
+	}
+
+	// the internal transactions of the transaction only
+	res, err = edge.GetInternalTransactions(BlockNumberOrTxHash{TxHash: &hash2})
+	assert.NoError(t, err)
+
+	if internalTxs, ok := res.([]*internalTxRes); assert.True(t, ok) && assert.Len(t, internalTxs, 1) {
+		assert.Equal(t, hash2, internalTxs[0].TxHash)
+		assert.Equal(t, argUint64(2), internalTxs[0].Depth)
+	}
+
+	// the genesis is not executed, it has no internal transactions
+	earliest := EarliestBlockNumber
+	res, err = edge.GetInternalTransactions(BlockNumberOrTxHash{BlockNumber: &earliest})
+	assert.NoError(t, err)
+	assert.Equal(t, []*internalTxRes{}, res)
+}
+
+func TestEdgeEndpoint_GetInternalTransactions_Errors(t *testing.T) {
+	t.Parallel()
+
+	edge := newEdge(newMockEdgeStore(), nil)
+
+	// the blocks written before the indexing was enabled
+	notIndexed := BlockNumber(1)
+	_, err := edge.GetInternalTransactions(BlockNumberOrTxHash{BlockNumber: &notIndexed})
+	assert.ErrorIs(t, err, ErrInternalTxsNotIndexed)
+
+	notFound := BlockNumber(10)
+	_, err = edge.GetInternalTransactions(BlockNumberOrTxHash{BlockNumber: &notFound})
+	assert.Error(t, err)
+
+	pending := PendingBlockNumber
+	_, err = edge.GetInternalTransactions(BlockNumberOrTxHash{BlockNumber: &pending})
+	assert.Error(t, err)
+
+	_, err = edge.GetInternalTransactions(BlockNumberOrTxHash{TxHash: &hash3})
+	assert.Error(t, err)
+}
+
+func TestEdgeEndpoint_GetStateDiff(t *testing.T) {
+	t.Parallel()
+
+	store := newMockEdgeStore()
+	edge := newEdge(store, nil)
+
+	res, err := edge.GetStateDiff(LatestBlockNumber)
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(res)
+	assert.NoError(t, err)
+
+	expected := `[{
+		"transactionHash": "` + hash1.String() + `",
+		"stateDiff": {
+			"` + addr0.String() + `": {
+				"balance": {"*": {"from": "0xa", "to": "0x5"}},
+				"nonce": {"*": {"from": "0x1", "to": "0x2"}},
+				"code": "=",
+				"storage": {
+					"` + hash1.String() + `": {"*": {"from": "` + types.Hash{}.String() + `", "to": "` + hash2.String() + `"}}
+				}
+			},
+			"` + addr1.String() + `": {
+				"balance": {"+": "0x5"},
+				"nonce": {"+": "0x0"},
+				"code": {"+": "0x01"},
+				"storage": {}
+			}
+		},
+		"trace": [],
+		"vmTrace": null
+	}]`
+	assert.JSONEq(t, expected, string(data))
+
+	// the state diff of the block is cached
+	_, err = edge.GetStateDiff(BlockNumber(2))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, store.executed)
+
+	// the genesis is not executed, it has no transactions
+	res, err = edge.GetStateDiff(EarliestBlockNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, []*txStateDiffRes{}, res)
+	assert.Equal(t, 1, store.executed)
+
+	_, err = edge.GetStateDiff(BlockNumber(10))
+	assert.Error(t, err)
+
+	_, err = edge.GetStateDiff(PendingBlockNumber)
+	assert.Error(t, err)
+}
+
+// mockTxSyncStore is the store of the eth endpoint the transactions are sent through,
+// the transaction is the only one of the block once mined
+type mockTxSyncStore struct {
+	mockStoreTxn
+
+	block *types.Block
+}
+
+func (m *mockTxSyncStore) ReadTxLookup(hash types.Hash) (types.Hash, uint64, bool) {
+	if m.block == nil || m.block.Transactions[0].Hash != hash {
+		return types.ZeroHash, 0, false
+	}
+
+	return m.block.Hash(), 0, true
+}
+
+func (m *mockTxSyncStore) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	return m.block, m.block != nil
+}
+
+func (m *mockTxSyncStore) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
+	status := types.ReceiptSuccess
+
+	return []*types.Receipt{{Status: &status, GasUsed: 21000}}, nil
+}
+
+func TestEdgeEndpoint_SendRawTransactionSync(t *testing.T) {
+	t.Parallel()
+
+	tx := &types.Transaction{
+		From:     addr0,
+		To:       argAddrPtr(addr1),
+		GasPrice: big.NewInt(1),
+		Gas:      21000,
+	}
+	tx.ComputeHash()
+
+	raw := hex.EncodeToHex(tx.MarshalRLP())
+	timeout := evmQuantity(50)
+
+	newTestEdge := func() (*Edge, *mockEdgeStore, *mockTxSyncStore) {
+		store, ethStore := newMockEdgeStore(), &mockTxSyncStore{}
+
+		return newEdge(store, newTestEthEndpoint(ethStore)), store, ethStore
+	}
+
+	txWaitErrorOf := func(err error) *txWaitError {
+		var waitErr *txWaitError
+		assert.True(t, errors.As(err, &waitErr))
+
+		return waitErr
+	}
+
+	t.Run("the receipt is returned once mined", func(t *testing.T) {
+		t.Parallel()
+
+		edge, store, ethStore := newTestEdge()
+
+		go func() {
+			ethStore.block = &types.Block{
+				Header:       (&types.Header{Number: 1}).ComputeHash(),
+				Transactions: []*types.Transaction{tx},
+			}
+			store.events <- &blockchain.Event{}
+		}()
+
+		res, err := edge.SendRawTransactionSync(raw, nil)
+		assert.NoError(t, err)
+
+		if receipt, ok := res.(*receipt); assert.True(t, ok) {
+			assert.Equal(t, tx.Hash, receipt.TxHash)
+			assert.Equal(t, argUint64(1), receipt.BlockNumber)
+			assert.Equal(t, argUint64(21000), receipt.GasUsed)
+		}
+	})
+
+	t.Run("the transaction is not mined within the timeout", func(t *testing.T) {
+		t.Parallel()
+
+		edge, store, _ := newTestEdge()
+
+		// the events of the other transactions are skipped
+		store.txEventsCh <- &txpoolProto.TxPoolEvent{Type: txpoolProto.EventType_DROPPED, TxHash: hash1.String()}
+
+		_, err := edge.SendRawTransactionSync(raw, &timeout)
+
+		waitErr := txWaitErrorOf(err)
+		assert.Equal(t, txWaitTimeoutErrorCode, waitErr.ErrorCode())
+		assert.Equal(t, map[string]string{"transactionHash": tx.Hash.String()}, waitErr.ErrorData())
+	})
+
+	t.Run("the transaction is dropped or replaced", func(t *testing.T) {
+		t.Parallel()
+
+		cases := map[txpoolProto.EventType]int{
+			txpoolProto.EventType_DROPPED:             txWaitDroppedErrorCode,
+			txpoolProto.EventType_REPLACED:            txWaitReplacedErrorCode,
+			txpoolProto.EventType_PRUNED_PROMOTED:     txWaitReplacedErrorCode,
+			txpoolProto.EventType_EVICTED_UNDERPRICED: txWaitDroppedErrorCode,
+		}
+
+		for eventType, code := range cases {
+			edge, store, _ := newTestEdge()
+			store.txEventsCh <- &txpoolProto.TxPoolEvent{Type: eventType, TxHash: tx.Hash.String()}
+
+			_, err := edge.SendRawTransactionSync(raw, &timeout)
+			assert.Equal(t, code, txWaitErrorOf(err).ErrorCode(), eventType.String())
+		}
+	})
+
+	t.Run("the rejected transaction is not waited for", func(t *testing.T) {
+		t.Parallel()
+
+		edge, _, ethStore := newTestEdge()
+		ethStore.err = errors.New("failed")
+
+		_, err := edge.SendRawTransactionSync(raw, nil)
+		assert.EqualError(t, err, "failed")
+	})
+}
@@ -3,7 +3,13 @@ package jsonrpc
 import (
 	"errors"
 	"fmt"
+	"math"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/txpool"
 	"github.com/umbracle/go-web3/abi"
 )
 
@@ -75,12 +81,171 @@ func (e *methodNotFoundError) ErrorCode() int {
 	return -32601
 }
 
+// limitExceededError is a request rejected by a rate limit,
+// the data tells the client in how many seconds to retry
+type limitExceededError struct {
+	err        string
+	retryAfter time.Duration
+}
+
+func (e *limitExceededError) Error() string {
+	return e.err
+}
+
+func (e *limitExceededError) ErrorCode() int {
+	return -32005
+}
+
+func (e *limitExceededError) ErrorData() interface{} {
+	return map[string]uint64{
+		"retryAfter": uint64(math.Ceil(e.retryAfter.Seconds())),
+	}
+}
+
+// unauthorizedError is a request of a client not allowed to call the method
+type unauthorizedError struct {
+	err string
+}
+
+func (e *unauthorizedError) Error() string {
+	return e.err
+}
+
+func (e *unauthorizedError) ErrorCode() int {
+	return -32006
+}
+
+// executionAbortedError is a request executing the EVM aborted on its timeout,
+// or waiting for a running execution to end until then
+type executionAbortedError struct {
+	err string
+}
+
+func (e *executionAbortedError) Error() string {
+	return e.err
+}
+
+func (e *executionAbortedError) ErrorCode() int {
+	return -32007
+}
+
+// txRejectedError is a transaction the txpool didn't accept,
+// the code tells the wallets the rejection reason
+type txRejectedError struct {
+	err  string
+	code int
+}
+
+func (e *txRejectedError) Error() string {
+	return e.err
+}
+
+func (e *txRejectedError) ErrorCode() int {
+	return e.code
+}
+
+// the generic code of the rejected transactions (EIP-1474)
+const txRejectedErrorCode = -32003
+
+// txRejectedErrorCodes are the codes of the txpool rejection reasons,
+// out of the range reserved for the server errors. The codes from -32021 to -32023
+// are the ones of the transactions sent synchronously
+var txRejectedErrorCodes = []struct {
+	reason error
+	code   int
+}{
+	{txpool.ErrNonceTooLow, -32010},
+	{txpool.ErrAlreadyKnown, -32011},
+	{txpool.ErrReplacementUnderpriced, -32012},
+	{txpool.ErrUnderpriced, -32013},
+	{txpool.ErrTxPoolOverflow, -32014},
+	{txpool.ErrMaxEnqueuedLimitReached, -32015},
+	{txpool.ErrInsufficientFunds, -32016},
+	{txpool.ErrIntrinsicGas, -32017},
+	{txpool.ErrBlockLimitExceeded, -32018},
+	{txpool.ErrOversizedData, -32019},
+	{txpool.ErrInvalidSender, -32020},
+	{txpool.ErrUnprotectedTx, -32024},
+	{txpool.ErrInvalidChainID, -32025},
+	{chain.ErrTxTooLarge, -32026},
+	{chain.ErrBlockTooLarge, -32027},
+}
+
+// NewTxRejectedError returns the JSON-RPC error of a transaction the txpool rejected.
+// Any other error is returned as is
+func NewTxRejectedError(err error) error {
+	var rejected *txpool.TxRejectedError
+	if !errors.As(err, &rejected) {
+		return err
+	}
+
+	for _, reason := range txRejectedErrorCodes {
+		if errors.Is(rejected.Reason, reason.reason) {
+			return &txRejectedError{err: rejected.Error(), code: reason.code}
+		}
+	}
+
+	return &txRejectedError{err: rejected.Error(), code: txRejectedErrorCode}
+}
+
+// txWaitError is a transaction sent synchronously whose receipt isn't returned,
+// the data holds the hash of the transaction so the client keeps tracking it
+type txWaitError struct {
+	err    string
+	code   int
+	txHash string
+}
+
+func (e *txWaitError) Error() string {
+	return e.err
+}
+
+func (e *txWaitError) ErrorCode() int {
+	return e.code
+}
+
+func (e *txWaitError) ErrorData() interface{} {
+	return map[string]string{
+		"transactionHash": e.txHash,
+	}
+}
+
+// the codes of the transactions sent synchronously, after the codes of the rejection reasons
+const (
+	txWaitTimeoutErrorCode  = -32021
+	txWaitDroppedErrorCode  = -32022
+	txWaitReplacedErrorCode = -32023
+)
+
+func newTxWaitError(code int, txHash string, msg string) *txWaitError {
+	return &txWaitError{
+		err:    fmt.Sprintf("transaction %s %s", txHash, msg),
+		code:   code,
+		txHash: txHash,
+	}
+}
+
 func NewMethodNotFoundError(method string) *methodNotFoundError {
 	return &methodNotFoundError{fmt.Sprintf("the method %s does not exist/is not available", method)}
 }
 func NewInvalidRequestError(msg string) *invalidRequestError {
 	return &invalidRequestError{msg}
 }
+func NewLimitExceededError(method string, retryAfter time.Duration) *limitExceededError {
+	return &limitExceededError{
+		err:        fmt.Sprintf("rate limit exceeded for the method %s", method),
+		retryAfter: retryAfter,
+	}
+}
+
+func NewUnauthorizedError(method string) *unauthorizedError {
+	return &unauthorizedError{fmt.Sprintf("the client is not allowed to call the method %s", method)}
+}
+
+func NewExecutionAbortedError(reason string) *executionAbortedError {
+	return &executionAbortedError{fmt.Sprintf("execution aborted (%s)", reason)}
+}
+
 func NewInvalidParamsError(msg string) *invalidParamsError {
 	return &invalidParamsError{msg}
 }
@@ -93,11 +258,53 @@ func NewSubscriptionNotFoundError(method string) *subscriptionNotFoundError {
 	return &subscriptionNotFoundError{fmt.Sprintf("subscribe method %s not found", method)}
 }
 
+// revertError is the error of a reverted execution, the data is the hex encoded
+// return value, empty if the execution reverted without any
+type revertError struct {
+	err  error
+	data string
+}
+
+func (e *revertError) Error() string {
+	return e.err.Error()
+}
+
+func (e *revertError) Unwrap() error {
+	return e.err
+}
+
+func (e *revertError) ErrorCode() int {
+	return 3
+}
+
+func (e *revertError) ErrorData() interface{} {
+	if e.data == "" {
+		return nil
+	}
+
+	return e.data
+}
+
+// dataError is an error with additional data about it
+type dataError interface {
+	Error
+	ErrorData() interface{}
+}
+
 func constructErrorFromRevert(result *runtime.ExecutionResult) error {
-	revertErrMsg, unpackErr := abi.UnpackRevertError(result.ReturnValue)
-	if unpackErr != nil {
-		return result.Err
+	err := result.Err
+
+	if revertErrMsg, unpackErr := abi.UnpackRevertError(result.ReturnValue); unpackErr == nil {
+		err = fmt.Errorf("%w: %s", result.Err, revertErrMsg)
+	}
+
+	revertErr := &revertError{
+		err: err,
+	}
+
+	if len(result.ReturnValue) != 0 {
+		revertErr.data = hex.EncodeToHex(result.ReturnValue)
 	}
 
-	return fmt.Errorf("%w: %s", result.Err, revertErrMsg)
+	return revertErr
 }
@@ -1,13 +1,16 @@
 package jsonrpc
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/0xPolygon/polygon-edge/helper/progress"
+	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/stretchr/testify/assert"
@@ -51,6 +54,95 @@ func TestEth_Block_GetBlockByNumber(t *testing.T) {
 	}
 }
 
+func TestEth_Block_GetBlockByNumber_Finalized(t *testing.T) {
+	store := &mockBlockStore{finalized: 7}
+	for i := 0; i < 10; i++ {
+		store.add(newTestBlock(uint64(i), hash1))
+	}
+
+	eth := newTestEthEndpoint(store)
+
+	res, err := eth.GetBlockByNumber(FinalizedBlockNumber, false)
+	assert.NoError(t, err)
+
+	block, ok := res.(*block)
+	assert.True(t, ok)
+	assert.Equal(t, argUint64(7), block.Number)
+
+	// the head is not moved back to the finalized block
+	num, err := eth.BlockNumber()
+	assert.NoError(t, err)
+	assert.Equal(t, argUintPtr(9), num)
+}
+
+func TestEth_Block_GetBlockByNumber_Pending(t *testing.T) {
+	store := newMockBlockStore()
+	head := newTestBlock(1, hash1)
+	head.Header.GasLimit = 50000
+	head.Header.StateRoot = hash2
+	head.Header.Timestamp = uint64(time.Now().Unix() + 100)
+	store.add(newTestBlock(0, hash3), head)
+	store.nextBaseFee = 10
+
+	txn := func(from types.Address, nonce, gas uint64) *types.Transaction {
+		tx := &types.Transaction{From: from, Nonce: nonce, Gas: gas}
+		tx.ComputeHash()
+
+		return tx
+	}
+
+	// the executable transactions by account, not in nonce order
+	first, second, third, fourth := txn(addr1, 0, 10000), txn(addr1, 1, 10000), txn(addr1, 2, 40000), txn(addr1, 3, 1000)
+	other := txn(addr0, 5, 20000)
+
+	store.promotedTxns = map[types.Address][]*types.Transaction{
+		addr1: {second, first, third, fourth},
+		addr0: {other},
+	}
+
+	eth := newTestEthEndpoint(store)
+
+	res, err := eth.GetBlockByNumber(PendingBlockNumber, false)
+	assert.NoError(t, err)
+
+	pending, ok := res.(*block)
+	assert.True(t, ok)
+
+	assert.Equal(t, argUint64(2), pending.Number)
+	assert.Equal(t, hash1, pending.ParentHash)
+	assert.Equal(t, hash2, pending.StateRoot)
+	assert.Equal(t, argUint64(50000), pending.GasLimit)
+	assert.Equal(t, argUintPtr(10), pending.BaseFee)
+	assert.Equal(t, types.ZeroHash, pending.Hash)
+
+	// the timestamp follows the head, even if it is ahead of the local clock
+	assert.Equal(t, argUint64(head.Header.Timestamp+1), pending.Timestamp)
+
+	// the accounts are taken in address order, and skipped from their first transaction not fitting in the block
+	assert.Equal(t, []transactionOrHash{
+		transactionHash(first.Hash),
+		transactionHash(second.Hash),
+		transactionHash(other.Hash),
+	}, pending.Transactions)
+
+	count, err := eth.GetBlockTransactionCountByNumber(PendingBlockNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	// the call is executed on the state of the head, in the environment of the pending block
+	pendingNumber := PendingBlockNumber
+
+	_, err = eth.Call(context.Background(), &txnArgs{From: &addr0, To: &addr1, Nonce: argUintPtr(0)}, BlockNumberOrHash{
+		BlockNumber: &pendingNumber,
+	}, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(2), store.callHeader.Number)
+	assert.Equal(t, hash2, store.callHeader.StateRoot)
+	assert.Equal(t, uint64(head.Header.Timestamp+1), store.callHeader.Timestamp)
+	assert.Equal(t, uint64(10), store.callHeader.BaseFee)
+}
+
 func TestEth_Block_GetBlockByHash(t *testing.T) {
 	store := &mockBlockStore{}
 	store.add(newTestBlock(1, hash1))
@@ -195,6 +287,86 @@ func TestEth_Block_GetLogs(t *testing.T) {
 	}
 }
 
+func TestEth_Block_GetLogs_Limits(t *testing.T) {
+	t.Parallel()
+
+	topics := []types.Hash{types.StringToHash("4"), types.StringToHash("5"), types.StringToHash("6")}
+
+	newStore := func(bloom types.Bloom) *mockBlockStore {
+		store := &mockBlockStore{}
+		store.topics = topics
+		store.setupLogs()
+
+		for i := 0; i < 5; i++ {
+			store.add(&types.Block{
+				Header: &types.Header{
+					Number:    uint64(i),
+					Hash:      types.StringToHash(strconv.Itoa(i)),
+					LogsBloom: bloom,
+				},
+				Transactions: []*types.Transaction{{}, {}, {}},
+			})
+		}
+
+		return store
+	}
+
+	query := &LogQuery{
+		fromBlock: 1,
+		toBlock:   3,
+		Topics:    [][]types.Hash{{topics[0]}, {topics[1]}, {topics[2]}},
+	}
+
+	t.Run("block range above the limit", func(t *testing.T) {
+		t.Parallel()
+
+		eth := newTestEthEndpoint(newStore(types.Bloom{}))
+		eth.blockRangeLimit = 2
+
+		_, err := eth.GetLogs(query)
+		assert.ErrorIs(t, err, ErrBlockRangeTooHigh)
+
+		eth.blockRangeLimit = 3
+
+		_, err = eth.GetLogs(query)
+		assert.NoError(t, err)
+	})
+
+	t.Run("logs above the limit", func(t *testing.T) {
+		t.Parallel()
+
+		eth := newTestEthEndpoint(newStore(types.Bloom{}))
+		eth.logsLimit = 2
+
+		_, err := eth.GetLogs(query)
+		assert.ErrorIs(t, err, ErrTooManyLogs)
+	})
+
+	t.Run("blocks skipped by the bloom", func(t *testing.T) {
+		t.Parallel()
+
+		// the blooms only include the first topic
+		bloom := types.CreateBloom([]*types.Receipt{
+			{Logs: []*types.Log{{Topics: topics[:1]}}},
+		})
+
+		eth := newTestEthEndpoint(newStore(bloom))
+
+		logs, err := eth.GetLogs(query)
+		assert.NoError(t, err)
+		assert.Len(t, logs, 0)
+
+		// the logs with the first topic may be in the blocks
+		logs, err = eth.GetLogs(&LogQuery{
+			fromBlock: 1,
+			toBlock:   3,
+			Topics:    [][]types.Hash{{topics[0]}},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, logs, 3)
+	})
+}
+
 func TestEth_GetTransactionByHash(t *testing.T) {
 	t.Parallel()
 
@@ -310,6 +482,65 @@ func TestEth_GetTransactionReceipt(t *testing.T) {
 		assert.Equal(t, block.Hash(), response.BlockHash)
 		assert.NotNil(t, response.Logs)
 	})
+
+	t.Run("returns the effective gas price, the type and the contract address of the creations", func(t *testing.T) {
+		t.Parallel()
+
+		store := newMockBlockStore()
+		eth := newTestEthEndpoint(store)
+		block := newTestBlock(1, hash4)
+		block.Header.BaseFee = 5
+		store.add(block)
+
+		dynamicTxn := &types.Transaction{
+			Type:                 types.DynamicFeeTx,
+			Nonce:                1,
+			MaxPriorityFeePerGas: big.NewInt(2),
+			MaxFeePerGas:         big.NewInt(10),
+			Value:                big.NewInt(0),
+			To:                   &addr1,
+			V:                    big.NewInt(1),
+			R:                    big.NewInt(1),
+			S:                    big.NewInt(1),
+		}
+		dynamicTxn.ComputeHash()
+
+		creationTxn := newTestTransaction(uint64(2), addr0)
+		creationTxn.To = nil
+		creationTxn.ComputeHash()
+
+		block.Transactions = append(block.Transactions, dynamicTxn, creationTxn)
+
+		// the receipt stored before the effective gas price was recorded
+		dynamicRec := &types.Receipt{}
+		dynamicRec.SetStatus(types.ReceiptSuccess)
+
+		creationRec := &types.Receipt{
+			ContractAddress:   addr2,
+			EffectiveGasPrice: big.NewInt(1),
+		}
+		creationRec.SetStatus(types.ReceiptSuccess)
+
+		store.receipts[hash4] = []*types.Receipt{dynamicRec, creationRec}
+
+		res, err := eth.GetTransactionReceipt(dynamicTxn.Hash)
+		assert.NoError(t, err)
+
+		// nolint:forcetypeassert
+		response := res.(*receipt)
+		assert.Equal(t, argBig(*big.NewInt(7)), response.EffectiveGasPrice)
+		assert.Equal(t, argUint64(types.DynamicFeeTx), response.Type)
+		assert.Nil(t, response.ContractAddress)
+
+		res, err = eth.GetTransactionReceipt(creationTxn.Hash)
+		assert.NoError(t, err)
+
+		// nolint:forcetypeassert
+		response = res.(*receipt)
+		assert.Equal(t, argBig(*big.NewInt(1)), response.EffectiveGasPrice)
+		assert.Equal(t, argUint64(types.LegacyTx), response.Type)
+		assert.Equal(t, &addr2, response.ContractAddress)
+	})
 }
 
 func TestEth_Syncing(t *testing.T) {
@@ -330,6 +561,23 @@ func TestEth_Syncing(t *testing.T) {
 		assert.Equal(t, fmt.Sprintf("0x%x", 1), response.StartingBlock)
 		assert.Equal(t, fmt.Sprintf("0x%x", 10), response.CurrentBlock)
 		assert.Equal(t, fmt.Sprintf("0x%x", 100), response.HighestBlock)
+		assert.Equal(t, "0x0", response.KnownStates)
+		assert.Equal(t, "0x0", response.PulledStates)
+	})
+
+	t.Run("returns \"false\" if the node is within the sync distance of the head", func(t *testing.T) {
+		store.isSyncing = true
+		eth.syncDistance = 90
+
+		defer func() {
+			eth.syncDistance = 0
+		}()
+
+		res, err := eth.Syncing()
+
+		assert.NoError(t, err)
+		//nolint:forcetypeassert
+		assert.False(t, res.(bool))
 	})
 
 	t.Run("returns \"false\" if sync is not progress", func(t *testing.T) {
@@ -357,6 +605,95 @@ func TestEth_GasPrice(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("0x%x", store.averageGasPrice), response)
 }
 
+func TestEth_FeeHistory(t *testing.T) {
+	store := newMockBlockStore()
+	store.nextBaseFee = 12
+
+	// blocks 0 and 1 are before the EIP-1559 fork
+	for i := uint64(0); i < 4; i++ {
+		block := newTestBlock(i, types.StringToHash(strconv.FormatUint(i, 10)))
+		block.Header.GasLimit = 100
+
+		if i >= 2 {
+			block.Header.BaseFee = 10
+		}
+
+		store.add(block)
+	}
+
+	// block 3 includes transactions paying tips of 1, 5 and 2 on top of the base fee
+	block := store.blocks[3]
+	block.Header.GasUsed = 50
+
+	for _, tip := range []int64{1, 5, 2} {
+		block.Transactions = append(block.Transactions, &types.Transaction{
+			Type:                 types.DynamicFeeTx,
+			MaxPriorityFeePerGas: big.NewInt(tip),
+			MaxFeePerGas:         big.NewInt(100),
+		})
+	}
+
+	store.receipts[block.Hash()] = []*types.Receipt{
+		{GasUsed: 10},
+		{GasUsed: 30},
+		{GasUsed: 10},
+	}
+
+	eth := newTestEthEndpoint(store)
+
+	t.Run("returns the history of the newest blocks", func(t *testing.T) {
+		res, err := eth.FeeHistory(3, LatestBlockNumber, []float64{10, 50, 100})
+		assert.NoError(t, err)
+
+		// nolint:forcetypeassert
+		history := res.(*feeHistoryResult)
+
+		assert.Equal(t, argUint64(1), history.OldestBlock)
+		assert.Equal(t, []argUint64{0, 10, 10, 12}, history.BaseFeePerGas)
+		assert.Equal(t, []float64{0, 0, 0.5}, history.GasUsedRatio)
+		assert.Equal(t, [][]argBig{
+			{argBig(*big.NewInt(0)), argBig(*big.NewInt(0)), argBig(*big.NewInt(0))},
+			{argBig(*big.NewInt(0)), argBig(*big.NewInt(0)), argBig(*big.NewInt(0))},
+			// the transactions in order of their tips use 10, 10 and 30 gas
+			{argBig(*big.NewInt(1)), argBig(*big.NewInt(5)), argBig(*big.NewInt(5))},
+		}, history.Reward)
+	})
+
+	t.Run("clamps the block count to the chain", func(t *testing.T) {
+		res, err := eth.FeeHistory(10, BlockNumber(1), nil)
+		assert.NoError(t, err)
+
+		// nolint:forcetypeassert
+		history := res.(*feeHistoryResult)
+
+		assert.Equal(t, argUint64(0), history.OldestBlock)
+		assert.Equal(t, []argUint64{0, 0, 12}, history.BaseFeePerGas)
+		assert.Nil(t, history.Reward)
+	})
+
+	t.Run("clamps the block count to the limit", func(t *testing.T) {
+		limitedEth := newTestEthEndpoint(store)
+		limitedEth.feeHistoryLimit = 2
+
+		res, err := limitedEth.FeeHistory(10, LatestBlockNumber, nil)
+		assert.NoError(t, err)
+
+		// nolint:forcetypeassert
+		history := res.(*feeHistoryResult)
+
+		assert.Equal(t, argUint64(2), history.OldestBlock)
+		assert.Len(t, history.GasUsedRatio, 2)
+	})
+
+	t.Run("rejects invalid reward percentiles", func(t *testing.T) {
+		_, err := eth.FeeHistory(1, LatestBlockNumber, []float64{50, 10})
+		assert.ErrorIs(t, err, ErrInvalidRewardPercentile)
+
+		_, err = eth.FeeHistory(1, LatestBlockNumber, []float64{101})
+		assert.ErrorIs(t, err, ErrInvalidRewardPercentile)
+	})
+}
+
 func TestEth_Call(t *testing.T) {
 	t.Parallel()
 
@@ -377,7 +714,7 @@ func TestEth_Call(t *testing.T) {
 			Nonce:    argUintPtr(0),
 		}
 
-		res, err := eth.Call(contractCall, BlockNumberOrHash{})
+		res, err := eth.Call(context.Background(), contractCall, BlockNumberOrHash{}, nil)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), store.ethCallError.Error())
@@ -401,7 +738,7 @@ func TestEth_Call(t *testing.T) {
 			Nonce:    argUintPtr(0),
 		}
 
-		res, err := eth.Call(contractCall, BlockNumberOrHash{})
+		res, err := eth.Call(context.Background(), contractCall, BlockNumberOrHash{}, nil)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, res)
@@ -417,6 +754,10 @@ type mockBlockStore struct {
 	isSyncing       bool
 	averageGasPrice int64
 	ethCallError    error
+	nextBaseFee     uint64
+	finalized       uint64
+	promotedTxns    map[types.Address][]*types.Transaction
+	callHeader      *types.Header
 }
 
 func newMockBlockStore() *mockBlockStore {
@@ -542,16 +883,20 @@ func (m *mockBlockStore) Header() *types.Header {
 	return m.blocks[len(m.blocks)-1].Header
 }
 
-func (m *mockBlockStore) ReadTxLookup(txnHash types.Hash) (types.Hash, bool) {
+func (m *mockBlockStore) FinalizedHeader() *types.Header {
+	return m.blocks[m.finalized].Header
+}
+
+func (m *mockBlockStore) ReadTxLookup(txnHash types.Hash) (types.Hash, uint64, bool) {
 	for _, block := range m.blocks {
-		for _, txn := range block.Transactions {
+		for indx, txn := range block.Transactions {
 			if txn.Hash == txnHash {
-				return block.Hash(), true
+				return block.Hash(), uint64(indx), true
 			}
 		}
 	}
 
-	return types.ZeroHash, false
+	return types.ZeroHash, 0, false
 }
 
 func (m *mockBlockStore) GetPendingTx(txHash types.Hash) (*types.Transaction, bool) {
@@ -581,7 +926,38 @@ func (m *mockBlockStore) GetAvgGasPrice() *big.Int {
 	return big.NewInt(m.averageGasPrice)
 }
 
-func (m *mockBlockStore) ApplyTxn(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error) {
+func (m *mockBlockStore) CalculateBaseFee(parent *types.Header) uint64 {
+	return m.nextBaseFee
+}
+
+func (m *mockBlockStore) CalculateGasLimit(number uint64) (uint64, error) {
+	return m.blocks[number-1].Header.GasLimit, nil
+}
+
+func (m *mockBlockStore) GetTxs(inclQueued bool) (
+	map[types.Address][]*types.Transaction,
+	map[types.Address][]*types.Transaction,
+) {
+	return m.promotedTxns, nil
+}
+
+func (m *mockBlockStore) ApplyTxn(
+	ctx context.Context,
+	header *types.Header,
+	txn *types.Transaction,
+	override state.OverrideSet,
+) (*runtime.ExecutionResult, error) {
+	m.callHeader = header
+
+	return &runtime.ExecutionResult{Err: m.ethCallError}, nil
+}
+
+func (m *mockBlockStore) TraceTxn(
+	ctx context.Context,
+	header *types.Header,
+	txn *types.Transaction,
+	tracer runtime.Tracer,
+) (*runtime.ExecutionResult, error) {
 	return &runtime.ExecutionResult{Err: m.ethCallError}, nil
 }
 
@@ -0,0 +1,478 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockExecutorStore executes the calls on the genesis state
+type mockExecutorStore struct {
+	ethStore
+	executor *state.Executor
+	forks    *chain.Forks
+	header   *types.Header
+}
+
+func newMockExecutorStore(alloc map[types.Address]*chain.GenesisAccount) *mockExecutorStore {
+	return newMockExecutorStoreWithForks(alloc, chain.AllForksEnabled)
+}
+
+func newMockExecutorStoreWithForks(
+	alloc map[types.Address]*chain.GenesisAccount,
+	forks *chain.Forks,
+) *mockExecutorStore {
+	executor := state.NewExecutor(&chain.Params{
+		Forks:   forks,
+		ChainID: 100,
+	}, itrie.NewState(itrie.NewMemoryStorage()), hclog.NewNullLogger())
+	executor.SetRuntime(evm.NewEVM())
+	executor.GetHash = func(*types.Header) state.GetHashByNumber {
+		return func(uint64) types.Hash {
+			return types.ZeroHash
+		}
+	}
+
+	return &mockExecutorStore{
+		executor: executor,
+		forks:    forks,
+		header: &types.Header{
+			GasLimit:  5000000,
+			StateRoot: executor.WriteGenesis(alloc),
+		},
+	}
+}
+
+func (m *mockExecutorStore) Header() *types.Header {
+	return m.header
+}
+
+func (m *mockExecutorStore) GetNonce(addr types.Address) uint64 {
+	return 0
+}
+
+func (m *mockExecutorStore) GetAccount(root types.Hash, addr types.Address) (*state.Account, error) {
+	snap, err := m.executor.StateAt(root)
+	if err != nil {
+		return nil, err
+	}
+
+	account, ok := state.NewTxn(m.executor.State(), snap).GetAccount(addr)
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+
+	return account, nil
+}
+
+func (m *mockExecutorStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	return m.forks.At(blockNumber)
+}
+
+func (m *mockExecutorStore) ApplyTxn(
+	ctx context.Context,
+	header *types.Header,
+	txn *types.Transaction,
+	override state.OverrideSet,
+) (*runtime.ExecutionResult, error) {
+	transition, err := m.executor.BeginTxn(header.StateRoot, header, types.ZeroAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	transition.SetAbort(ctx.Done())
+
+	if err := transition.ApplyOverride(override); err != nil {
+		return nil, err
+	}
+
+	return transition.Apply(txn)
+}
+
+func (m *mockExecutorStore) TraceTxn(
+	ctx context.Context,
+	header *types.Header,
+	txn *types.Transaction,
+	tracer runtime.Tracer,
+) (*runtime.ExecutionResult, error) {
+	transition, err := m.executor.BeginTxn(header.StateRoot, header, types.ZeroAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	transition.SetAbort(ctx.Done())
+	transition.SetTracer(tracer)
+
+	return transition.Apply(txn)
+}
+
+var (
+	// overrideCode returns the word 0x2a
+	overrideCode = []byte{
+		0x60, 0x2a, 0x60, 0x00, 0x52, // MSTORE(0, 0x2a)
+		0x60, 0x20, 0x60, 0x00, 0xf3, // RETURN(0, 32)
+	}
+
+	// overrideStorageCode returns the word in the slot 1
+	overrideStorageCode = []byte{
+		0x60, 0x01, 0x54, 0x60, 0x00, 0x52, // MSTORE(0, SLOAD(1))
+		0x60, 0x20, 0x60, 0x00, 0xf3, // RETURN(0, 32)
+	}
+)
+
+func TestEth_Call_StateOverride_Code(t *testing.T) {
+	contract := types.StringToAddress("1234")
+
+	store := newMockExecutorStore(map[types.Address]*chain.GenesisAccount{})
+	eth := newTestEthEndpoint(store)
+
+	// there is no contract at the address
+	res, err := eth.Call(context.Background(), &txnArgs{To: &contract}, BlockNumberOrHash{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, argBytesPtr(nil), res)
+
+	code := argBytes(overrideCode)
+
+	res, err = eth.Call(context.Background(), &txnArgs{To: &contract}, BlockNumberOrHash{}, stateOverrideSet{
+		contract: {Code: &code},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, argBytesPtr(types.BytesToHash([]byte{0x2a}).Bytes()), res)
+
+	// the override is not persisted
+	res, err = eth.Call(context.Background(), &txnArgs{To: &contract}, BlockNumberOrHash{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, argBytesPtr(nil), res)
+}
+
+func TestEth_Call_StateOverride_Storage(t *testing.T) {
+	contract := types.StringToAddress("1234")
+	slot1, slot2 := types.BytesToHash([]byte{1}), types.BytesToHash([]byte{2})
+
+	store := newMockExecutorStore(map[types.Address]*chain.GenesisAccount{
+		contract: {
+			Code: overrideStorageCode,
+			Storage: map[types.Hash]types.Hash{
+				slot1: types.BytesToHash([]byte{0x1}),
+			},
+		},
+	})
+	eth := newTestEthEndpoint(store)
+
+	call := func(override stateOverrideSet) interface{} {
+		t.Helper()
+
+		res, err := eth.Call(context.Background(), &txnArgs{To: &contract}, BlockNumberOrHash{}, override)
+		assert.NoError(t, err)
+
+		return res
+	}
+
+	assert.Equal(t, argBytesPtr(slot1.Bytes()), call(nil))
+
+	// the state diff replaces the given slots only
+	assert.Equal(t, argBytesPtr(slot2.Bytes()), call(stateOverrideSet{
+		contract: {StateDiff: map[types.Hash]types.Hash{slot1: slot2}},
+	}))
+
+	// the state replaces the whole storage
+	assert.Equal(t, argBytesPtr(types.ZeroHash.Bytes()), call(stateOverrideSet{
+		contract: {State: map[types.Hash]types.Hash{slot2: slot2}},
+	}))
+
+	_, err := eth.Call(context.Background(), &txnArgs{To: &contract}, BlockNumberOrHash{}, stateOverrideSet{
+		contract: {
+			State:     map[types.Hash]types.Hash{},
+			StateDiff: map[types.Hash]types.Hash{},
+		},
+	})
+	assert.Error(t, err)
+}
+
+func TestEth_Call_Aborted(t *testing.T) {
+	loop := types.StringToAddress("1234")
+
+	store := newMockExecutorStore(map[types.Address]*chain.GenesisAccount{
+		loop: {
+			Code: []byte{
+				0x5b, 0x60, 0x00, 0x56, // JUMPDEST, JUMP(0)
+			},
+		},
+	})
+	eth := newTestEthEndpoint(store)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := eth.Call(ctx, &txnArgs{To: &loop}, BlockNumberOrHash{}, nil)
+	assert.ErrorIs(t, err, runtime.ErrExecutionAborted)
+
+	_, err = eth.EstimateGas(ctx, &txnArgs{To: &loop}, nil, nil)
+	assert.ErrorIs(t, err, runtime.ErrExecutionAborted)
+}
+
+func TestEth_Call_Revert(t *testing.T) {
+	reason, bare := types.StringToAddress("1234"), types.StringToAddress("1235")
+
+	store := newMockExecutorStore(map[types.Address]*chain.GenesisAccount{
+		reason: {
+			// reverts with Error("boom")
+			Code: []byte{
+				0x63, 0x08, 0xc3, 0x79, 0xa0, 0x60, 0xe0, 0x1b, 0x60, 0x00, 0x52, // MSTORE(0, selector << 224)
+				0x60, 0x20, 0x60, 0x04, 0x52, // MSTORE(4, 32)
+				0x60, 0x04, 0x60, 0x24, 0x52, // MSTORE(36, 4)
+				0x63, 'b', 'o', 'o', 'm', 0x60, 0xe0, 0x1b, 0x60, 0x44, 0x52, // MSTORE(68, "boom" << 224)
+				0x60, 0x64, 0x60, 0x00, 0xfd, // REVERT(0, 100)
+			},
+		},
+		bare: {
+			Code: []byte{
+				0x60, 0x00, 0x60, 0x00, 0xfd, // REVERT(0, 0)
+			},
+		},
+	})
+	eth := newTestEthEndpoint(store)
+
+	_, err := eth.Call(context.Background(), &txnArgs{To: &reason}, BlockNumberOrHash{}, nil)
+	assert.ErrorIs(t, err, runtime.ErrExecutionReverted)
+	assert.EqualError(t, err, "execution was reverted: boom")
+
+	var rpcErr dataError
+	if !assert.ErrorAs(t, err, &rpcErr) {
+		t.FailNow()
+	}
+
+	data := append([]byte{0x08, 0xc3, 0x79, 0xa0}, types.BytesToHash([]byte{0x20}).Bytes()...)
+	data = append(data, types.BytesToHash([]byte{0x04}).Bytes()...)
+	data = append(data, append([]byte("boom"), make([]byte, 28)...)...)
+
+	assert.Equal(t, 3, rpcErr.ErrorCode())
+	// nolint:forcetypeassert
+	assert.Equal(t, hex.EncodeToHex(data), rpcErr.ErrorData().(string))
+
+	// the reverts without data do not return any
+	_, err = eth.Call(context.Background(), &txnArgs{To: &bare}, BlockNumberOrHash{}, nil)
+	assert.ErrorIs(t, err, runtime.ErrExecutionReverted)
+
+	if !assert.ErrorAs(t, err, &rpcErr) {
+		t.FailNow()
+	}
+
+	assert.Nil(t, rpcErr.ErrorData())
+
+	res, err := NewRPCResponse(1, "2.0", nil, rpcErr).Bytes()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(res), `"data"`)
+}
+
+func TestEth_EstimateGas_StateOverride(t *testing.T) {
+	sender := types.StringToAddress("1")
+	contract := types.StringToAddress("1234")
+
+	store := newMockExecutorStore(map[types.Address]*chain.GenesisAccount{})
+	eth := newTestEthEndpoint(store)
+
+	var override stateOverrideSet
+
+	// the sender has no funds and the nonce of the pool does not match
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"0x0000000000000000000000000000000000000001": {
+			"balance": "0x3b9aca00",
+			"nonce": "0x5"
+		},
+		"0x0000000000000000000000000000000000001234": {
+			"code": "0x602a60005260206000f3"
+		}
+	}`), &override))
+
+	value := argBytes(big.NewInt(1).Bytes())
+
+	estimate, err := eth.EstimateGas(context.Background(), &txnArgs{
+		From:  &sender,
+		To:    &contract,
+		Value: &value,
+	}, nil, override)
+	assert.NoError(t, err)
+	// the intrinsic gas, and the gas of the overridden code
+	assert.Equal(t, fmt.Sprintf("0x%x", state.TxGas+18), estimate)
+
+	_, err = eth.EstimateGas(context.Background(), &txnArgs{
+		From:  &sender,
+		To:    &contract,
+		Value: &value,
+	}, nil, nil)
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestEth_EstimateGas_CallGasRule(t *testing.T) {
+	caller := types.StringToAddress("1234")
+	callee := types.StringToAddress("1235")
+
+	store := newMockExecutorStore(map[types.Address]*chain.GenesisAccount{
+		// the caller reverts if the call to the callee fails
+		caller: {
+			Code: []byte{
+				0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, // CALL(GAS, callee, 0, 0, 0, 0, 0)
+				0x61, 0x12, 0x35, 0x5a, 0xf1,
+				0x60, 0x17, 0x57, // JUMPI(23, success)
+				0x60, 0x00, 0x60, 0x00, 0xfd, // REVERT(0, 0)
+				0x5b, 0x00, // JUMPDEST STOP
+			},
+		},
+		// the callee writes two slots
+		callee: {
+			Code: []byte{
+				0x60, 0x01, 0x60, 0x00, 0x55, // SSTORE(0, 1)
+				0x60, 0x01, 0x60, 0x01, 0x55, // SSTORE(1, 1)
+				0x00,
+			},
+		},
+	})
+	eth := newTestEthEndpoint(store)
+
+	estimate, err := eth.EstimateGas(context.Background(), &txnArgs{To: &caller}, nil, nil)
+	assert.NoError(t, err)
+
+	gas, err := strconv.ParseUint(estimate.(string), 0, 64) // nolint:forcetypeassert
+	assert.NoError(t, err)
+
+	apply := func(gas uint64) *runtime.ExecutionResult {
+		t.Helper()
+
+		result, err := store.ApplyTxn(context.Background(), store.header, &types.Transaction{
+			To:       &caller,
+			Gas:      gas,
+			GasPrice: big.NewInt(0),
+			Value:    big.NewInt(0),
+		}, nil)
+		assert.NoError(t, err)
+
+		return result
+	}
+
+	// the estimate is the lowest gas limit the call succeeds with,
+	// it is higher than the gas used because of the gas kept by the caller
+	assert.NoError(t, apply(gas).Err)
+	assert.ErrorIs(t, apply(gas-1).Err, runtime.ErrExecutionReverted)
+	assert.Greater(t, gas, apply(gas).GasUsed)
+}
+
+func TestEth_EstimateGas_Revert(t *testing.T) {
+	contract := types.StringToAddress("1234")
+
+	store := newMockExecutorStore(map[types.Address]*chain.GenesisAccount{
+		contract: {
+			Code: []byte{
+				0x60, 0x2a, 0x60, 0x00, 0x52, // MSTORE(0, 0x2a)
+				0x60, 0x20, 0x60, 0x00, 0xfd, // REVERT(0, 32)
+			},
+		},
+	})
+	eth := newTestEthEndpoint(store)
+
+	_, err := eth.EstimateGas(context.Background(), &txnArgs{To: &contract}, nil, nil)
+	assert.ErrorIs(t, err, runtime.ErrExecutionReverted)
+
+	// the revert data is returned along with the error
+	var rpcErr dataError
+	if !assert.ErrorAs(t, err, &rpcErr) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, 3, rpcErr.ErrorCode())
+	assert.Equal(t, hex.EncodeToHex(types.BytesToHash([]byte{0x2a}).Bytes()), rpcErr.ErrorData())
+
+	res, err := NewRPCResponse(1, "2.0", nil, rpcErr).Bytes()
+	assert.NoError(t, err)
+	assert.Contains(t, string(res), `"data":"0x000000000000000000000000000000000000000000000000000000000000002a"`)
+}
+
+func TestEth_EstimateGas_Allowance(t *testing.T) {
+	sender := types.StringToAddress("1")
+	recipient := types.StringToAddress("2")
+
+	store := newMockExecutorStore(map[types.Address]*chain.GenesisAccount{
+		sender: {Balance: big.NewInt(30000)},
+	})
+	eth := newTestEthEndpoint(store)
+
+	estimate := func(value int64) (interface{}, error) {
+		return eth.EstimateGas(context.Background(), &txnArgs{
+			From:     &sender,
+			To:       &recipient,
+			Value:    argBytesPtr(big.NewInt(value).Bytes()),
+			GasPrice: argBytesPtr(big.NewInt(1).Bytes()),
+		}, nil, nil)
+	}
+
+	res, err := estimate(5000)
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeUint64(state.TxGas), res)
+
+	// the funds left once the value is paid do not cover the intrinsic gas
+	_, err = estimate(10000)
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = estimate(40000)
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestEth_CreateAccessList(t *testing.T) {
+	contract := types.StringToAddress("1234")
+	other := types.StringToAddress("5678")
+	slot := types.BytesToHash([]byte{0x1})
+
+	alloc := map[types.Address]*chain.GenesisAccount{
+		contract: {
+			Code: []byte{
+				0x60, 0x01, 0x54, 0x50, // POP(SLOAD(1))
+				0x61, 0x56, 0x78, 0x31, 0x50, // POP(BALANCE(0x5678))
+				0x00, // STOP
+			},
+		},
+	}
+
+	berlin := *chain.AllForksEnabled
+	berlin.Berlin = chain.NewFork(0)
+
+	eth := newTestEthEndpoint(newMockExecutorStoreWithForks(alloc, &berlin))
+
+	res, err := eth.CreateAccessList(context.Background(), &txnArgs{To: &contract}, BlockNumberOrHash{})
+	assert.NoError(t, err)
+
+	// the recipient is warm, only its slot is listed
+	assert.Equal(t, &accessListResult{
+		AccessList: types.AccessList{
+			{Address: contract, StorageKeys: []types.Hash{slot}},
+			{Address: other, StorageKeys: []types.Hash{}},
+		},
+		// 21000 + 2 addresses + 1 key, and the warm accesses
+		GasUsed: argUint64(21000 + 2*2400 + 1900 + 3 + 100 + 2 + 3 + 100 + 2),
+	}, res)
+
+	// the call uses the gas estimated with the access list
+	accessList := res.(*accessListResult).AccessList //nolint:forcetypeassert
+
+	estimate, err := eth.EstimateGas(context.Background(), &txnArgs{To: &contract, AccessList: &accessList}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeUint64(uint64(res.(*accessListResult).GasUsed)), estimate) //nolint:forcetypeassert
+
+	// the access lists are not supported before the fork
+	eth = newTestEthEndpoint(newMockExecutorStore(alloc))
+
+	_, err = eth.CreateAccessList(context.Background(), &txnArgs{To: &contract}, BlockNumberOrHash{})
+	assert.ErrorIs(t, err, ErrAccessListNotSupported)
+}
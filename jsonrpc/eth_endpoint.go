@@ -1,15 +1,23 @@
 package jsonrpc
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"bytes"
 	"math/big"
+	"sort"
+	"time"
 
 	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
 	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/state/runtime/tracer"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/umbracle/fastrlp"
@@ -24,6 +32,9 @@ type ethTxPoolStore interface {
 
 	// GetPendingTx gets the pending transaction from the transaction pool, if it's present
 	GetPendingTx(txHash types.Hash) (*types.Transaction, bool)
+
+	// GetTxs gets the executable transactions of the pool by account, and the queued ones if requested
+	GetTxs(inclQueued bool) (map[types.Address][]*types.Transaction, map[types.Address][]*types.Transaction)
 }
 
 type ethStateStore interface {
@@ -31,12 +42,19 @@ type ethStateStore interface {
 	GetStorage(root types.Hash, addr types.Address, slot types.Hash) ([]byte, error)
 	GetForksInTime(blockNumber uint64) chain.ForksInTime
 	GetCode(hash types.Hash) ([]byte, error)
+
+	// GetProof returns the merkle proof of the key in the state trie of the root,
+	// and the value of the key, nil if it is not in the trie
+	GetProof(root types.Hash, key []byte) ([][]byte, []byte, error)
 }
 
 type ethBlockchainStore interface {
 	// Header returns the current header of the chain (genesis if empty)
 	Header() *types.Header
 
+	// FinalizedHeader returns the latest header of the chain with verified seals
+	FinalizedHeader() *types.Header
+
 	// GetHeaderByNumber returns the header by number
 	GetHeaderByNumber(block uint64) (*types.Header, bool)
 
@@ -46,8 +64,9 @@ type ethBlockchainStore interface {
 	// GetBlockByNumber returns a block using the provided number
 	GetBlockByNumber(num uint64, full bool) (*types.Block, bool)
 
-	// ReadTxLookup returns a block hash in which a given txn was mined
-	ReadTxLookup(txnHash types.Hash) (types.Hash, bool)
+	// ReadTxLookup returns the hash of the block in which a given txn was mined,
+	// and the index of the txn in the block
+	ReadTxLookup(txnHash types.Hash) (types.Hash, uint64, bool)
 
 	// GetReceiptsByHash returns the receipts for a block hash
 	GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error)
@@ -55,8 +74,31 @@ type ethBlockchainStore interface {
 	// GetAvgGasPrice returns the average gas price
 	GetAvgGasPrice() *big.Int
 
-	// ApplyTxn applies a transaction object to the blockchain
-	ApplyTxn(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error)
+	// CalculateBaseFee returns the base fee of the block following the parent
+	CalculateBaseFee(parent *types.Header) uint64
+
+	// CalculateGasLimit returns the gas limit of the block, following the one of its parent
+	CalculateGasLimit(number uint64) (uint64, error)
+
+	// ApplyTxn applies a transaction object to the blockchain,
+	// on the state of the header with the accounts overridden, if any.
+	// The execution is aborted with runtime.ErrExecutionAborted once the context is done
+	ApplyTxn(
+		ctx context.Context,
+		header *types.Header,
+		txn *types.Transaction,
+		override state.OverrideSet,
+	) (*runtime.ExecutionResult, error)
+
+	// TraceTxn applies a transaction object to the blockchain,
+	// on the state of the header with the execution recorded by the tracer.
+	// The execution is aborted with runtime.ErrExecutionAborted once the context is done
+	TraceTxn(
+		ctx context.Context,
+		header *types.Header,
+		txn *types.Transaction,
+		tracer runtime.Tracer,
+	) (*runtime.ExecutionResult, error)
 
 	// GetSyncProgression retrieves the current sync progression, if any
 	GetSyncProgression() *progress.Progression
@@ -75,11 +117,27 @@ type Eth struct {
 	store         ethStore
 	chainID       uint64
 	filterManager *FilterManager
+
+	// feeHistoryLimit is the maximum number of blocks returned by eth_feeHistory, 0 for no limit
+	feeHistoryLimit uint64
+
+	// blockRangeLimit and logsLimit are the maximum range of blocks queried
+	// and the maximum number of logs returned by eth_getLogs, 0 for no limit
+	blockRangeLimit uint64
+	logsLimit       uint64
+
+	// syncDistance is the number of blocks the node can be behind the network head,
+	// and not be reported as syncing by eth_syncing
+	syncDistance uint64
 }
 
 var (
-	ErrInsufficientFunds = errors.New("insufficient funds for execution")
-	ErrGasCapOverflow    = errors.New("unable to apply transaction for the highest gas limit")
+	ErrInsufficientFunds       = errors.New("insufficient funds for execution")
+	ErrGasCapOverflow          = errors.New("unable to apply transaction for the highest gas limit")
+	ErrInvalidRewardPercentile = errors.New("invalid reward percentile")
+	ErrBlockRangeTooHigh       = errors.New("block range too high")
+	ErrTooManyLogs             = errors.New("too many logs")
+	ErrAccessListNotSupported  = errors.New("access lists are not supported before the berlin fork")
 )
 
 // ChainId returns the chain id of the client
@@ -111,19 +169,22 @@ func (e *Eth) getHeaderFromBlockNumberOrHash(bnh *BlockNumberOrHash) (*types.Hea
 	return header, nil
 }
 
+// Syncing returns the sync progress of the node, false once the node is within the sync distance of the network head
 func (e *Eth) Syncing() (interface{}, error) {
-	if syncProgression := e.store.GetSyncProgression(); syncProgression != nil {
-		// Node is bulk syncing, return the status
-		return progression{
-			Type:          string(syncProgression.SyncType),
-			StartingBlock: hex.EncodeUint64(syncProgression.StartingBlock),
-			CurrentBlock:  hex.EncodeUint64(syncProgression.CurrentBlock),
-			HighestBlock:  hex.EncodeUint64(syncProgression.HighestBlock),
-		}, nil
+	syncProgression := e.store.GetSyncProgression()
+	if syncProgression == nil || syncProgression.HighestBlock <= syncProgression.CurrentBlock+e.syncDistance {
+		// Node is not syncing
+		return false, nil
 	}
 
-	// Node is not bulk syncing
-	return false, nil
+	return progression{
+		Type:          string(syncProgression.SyncType),
+		StartingBlock: hex.EncodeUint64(syncProgression.StartingBlock),
+		CurrentBlock:  hex.EncodeUint64(syncProgression.CurrentBlock),
+		HighestBlock:  hex.EncodeUint64(syncProgression.HighestBlock),
+		KnownStates:   hex.EncodeUint64(syncProgression.KnownStates),
+		PulledStates:  hex.EncodeUint64(syncProgression.PulledStates),
+	}, nil
 }
 
 func GetNumericBlockNumber(number BlockNumber, e *Eth) (uint64, error) {
@@ -131,6 +192,9 @@ func GetNumericBlockNumber(number BlockNumber, e *Eth) (uint64, error) {
 	case LatestBlockNumber:
 		return e.store.Header().Number, nil
 
+	case FinalizedBlockNumber:
+		return e.store.FinalizedHeader().Number, nil
+
 	case EarliestBlockNumber:
 		return 0, nil
 
@@ -148,6 +212,15 @@ func GetNumericBlockNumber(number BlockNumber, e *Eth) (uint64, error) {
 
 // GetBlockByNumber returns information about a block by block number
 func (e *Eth) GetBlockByNumber(number BlockNumber, fullTx bool) (interface{}, error) {
+	if number == PendingBlockNumber {
+		block, err := e.pendingBlock()
+		if err != nil {
+			return nil, err
+		}
+
+		return toBlock(block, fullTx), nil
+	}
+
 	num, err := GetNumericBlockNumber(number, e)
 	if err != nil {
 		return nil, err
@@ -173,6 +246,15 @@ func (e *Eth) GetBlockByHash(hash types.Hash, fullTx bool) (interface{}, error)
 }
 
 func (e *Eth) GetBlockTransactionCountByNumber(number BlockNumber) (interface{}, error) {
+	if number == PendingBlockNumber {
+		block, err := e.pendingBlock()
+		if err != nil {
+			return nil, err
+		}
+
+		return len(block.Transactions), nil
+	}
+
 	num, err := GetNumericBlockNumber(number, e)
 	if err != nil {
 		return nil, err
@@ -208,8 +290,10 @@ func (e *Eth) SendRawTransaction(input string) (interface{}, error) {
 
 	tx.ComputeHash()
 
+	// the hash is returned for the queued transactions as well,
+	// the rejected ones get the error code of the rejection reason
 	if err := e.store.AddTx(tx); err != nil {
-		return nil, err
+		return nil, NewTxRejectedError(err)
 	}
 
 	return tx.Hash.String(), nil
@@ -229,7 +313,7 @@ func (e *Eth) GetTransactionByHash(hash types.Hash) (interface{}, error) {
 	// for the transaction with the provided hash
 	findSealedTx := func() *transaction {
 		// Check the chain state for the transaction
-		blockHash, ok := e.store.ReadTxLookup(hash)
+		blockHash, indx, ok := e.store.ReadTxLookup(hash)
 		if !ok {
 			// Block not found in storage
 			return nil
@@ -242,19 +326,21 @@ func (e *Eth) GetTransactionByHash(hash types.Hash) (interface{}, error) {
 			return nil
 		}
 
-		// Find the transaction within the block
-		for idx, txn := range block.Transactions {
-			if txn.Hash == hash {
-				return toTransaction(
-					txn,
-					argUintPtr(block.Number()),
-					argHashPtr(block.Hash()),
-					&idx,
-				)
-			}
+		// The lookup holds the position of the transaction within the block
+		txn, ok := txnAtIndex(block, hash, indx)
+		if !ok {
+			return nil
 		}
 
-		return nil
+		idx := int(indx)
+
+		return toTransaction(
+			txn,
+			block.Header.GetBaseFee(),
+			argUintPtr(block.Number()),
+			argHashPtr(block.Hash()),
+			&idx,
+		)
 	}
 
 	// findPendingTx is a helper method for checking the TxPool
@@ -289,7 +375,7 @@ func (e *Eth) GetTransactionByHash(hash types.Hash) (interface{}, error) {
 
 // GetTransactionReceipt returns a transaction receipt by his hash
 func (e *Eth) GetTransactionReceipt(hash types.Hash) (interface{}, error) {
-	blockHash, ok := e.store.ReadTxLookup(hash)
+	blockHash, txIndx, ok := e.store.ReadTxLookup(hash)
 	if !ok {
 		// txn not found
 		return nil, nil
@@ -323,24 +409,14 @@ func (e *Eth) GetTransactionReceipt(hash types.Hash) (interface{}, error) {
 
 		return nil, nil
 	}
-	// find the transaction in the body
-	indx := -1
-
-	for i, txn := range block.Transactions {
-		if txn.Hash == hash {
-			indx = i
-
-			break
-		}
-	}
-
-	if indx == -1 {
+	// the lookup holds the position of the transaction in the body
+	txn, ok := txnAtIndex(block, hash, txIndx)
+	if !ok || txIndx >= uint64(len(receipts)) {
 		// txn not found
 		return nil, nil
 	}
 
-	txn := block.Transactions[indx]
-	raw := receipts[indx]
+	raw := receipts[txIndx]
 
 	logs := make([]*Log, len(raw.Logs))
 	for indx, elem := range raw.Logs {
@@ -351,31 +427,58 @@ func (e *Eth) GetTransactionReceipt(hash types.Hash) (interface{}, error) {
 			BlockHash:   block.Hash(),
 			BlockNumber: argUint64(block.Number()),
 			TxHash:      txn.Hash,
-			TxIndex:     argUint64(indx),
+			TxIndex:     argUint64(txIndx),
 			LogIndex:    argUint64(indx),
 			Removed:     false,
 		}
 	}
 
+	// the receipts stored before the effective gas price was recorded derive it from the base fee of the block
+	effectiveGasPrice := raw.EffectiveGasPrice
+	if effectiveGasPrice == nil {
+		effectiveGasPrice = txn.EffectiveGasPrice(block.Header.GetBaseFee())
+	}
+
 	res := &receipt{
 		Root:              raw.Root,
 		CumulativeGasUsed: argUint64(raw.CumulativeGasUsed),
 		LogsBloom:         raw.LogsBloom,
 		Status:            argUint64(*raw.Status),
 		TxHash:            txn.Hash,
-		TxIndex:           argUint64(indx),
+		TxIndex:           argUint64(txIndx),
 		BlockHash:         block.Hash(),
 		BlockNumber:       argUint64(block.Number()),
 		GasUsed:           argUint64(raw.GasUsed),
-		ContractAddress:   raw.ContractAddress,
+		EffectiveGasPrice: argBig(*effectiveGasPrice),
 		FromAddr:          txn.From,
 		ToAddr:            txn.To,
+		Type:              argUint64(txn.Type),
 		Logs:              logs,
 	}
 
+	// the contract address is only set for the contract creations
+	if txn.To == nil {
+		contractAddress := raw.ContractAddress
+		res.ContractAddress = &contractAddress
+	}
+
 	return res, nil
 }
 
+// txnAtIndex returns the transaction at the index of the block, if it has the passed in hash
+func txnAtIndex(block *types.Block, hash types.Hash, indx uint64) (*types.Transaction, bool) {
+	if indx >= uint64(len(block.Transactions)) {
+		return nil, false
+	}
+
+	txn := block.Transactions[indx]
+	if txn.Hash != hash {
+		return nil, false
+	}
+
+	return txn, true
+}
+
 // GetStorageAt returns the contract storage at the index position
 func (e *Eth) GetStorageAt(
 	address types.Address,
@@ -400,7 +503,7 @@ func (e *Eth) GetStorageAt(
 	// Get the storage for the passed in location
 	result, err := e.store.GetStorage(header.StateRoot, address, index)
 	if err != nil {
-		if errors.As(err, &ErrStateNotFound) {
+		if errors.Is(err, ErrStateNotFound) {
 			return argBytesPtr(types.ZeroHash[:]), nil
 		}
 
@@ -423,6 +526,90 @@ func (e *Eth) GetStorageAt(
 	return argBytesPtr(data), nil
 }
 
+// GetProof returns the merkle proof of the account and of its storage slots
+// at the referenced block (EIP-1186)
+func (e *Eth) GetProof(
+	address types.Address,
+	storageKeys []types.Hash,
+	filter BlockNumberOrHash,
+) (interface{}, error) {
+	// The filter is empty, use the latest block by default
+	if filter.BlockNumber == nil && filter.BlockHash == nil {
+		filter.BlockNumber, _ = createBlockNumberPointer("latest")
+	}
+
+	header, err := e.getHeaderFromBlockNumberOrHash(&filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header from block hash or block number")
+	}
+
+	proof, value, err := e.store.GetProof(header.StateRoot, address.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	// the proof of a missing account proves its exclusion, it is an empty account
+	account := &state.Account{
+		Balance: big.NewInt(0),
+		Root:    types.EmptyRootHash,
+	}
+
+	if value != nil {
+		if err := account.UnmarshalRlp(value); err != nil {
+			return nil, err
+		}
+	}
+
+	codeHash := types.BytesToHash(account.CodeHash)
+	if len(account.CodeHash) == 0 {
+		codeHash = types.BytesToHash(crypto.Keccak256(nil))
+	}
+
+	res := &accountProof{
+		Address:      address,
+		AccountProof: toArgBytesList(proof),
+		Balance:      argBig(*account.Balance),
+		CodeHash:     codeHash,
+		Nonce:        argUint64(account.Nonce),
+		StorageHash:  account.Root,
+		StorageProof: make([]storageProof, 0, len(storageKeys)),
+	}
+
+	for _, key := range storageKeys {
+		proof, value, err := e.store.GetProof(account.Root, key.Bytes())
+		if err != nil {
+			return nil, err
+		}
+
+		slot := new(big.Int)
+
+		if value != nil {
+			// the storage values are rlp encoded
+			p := &fastrlp.Parser{}
+
+			v, err := p.Parse(value)
+			if err != nil {
+				return nil, err
+			}
+
+			data, err := v.Bytes()
+			if err != nil {
+				return nil, err
+			}
+
+			slot.SetBytes(data)
+		}
+
+		res.StorageProof = append(res.StorageProof, storageProof{
+			Key:   key,
+			Value: argBig(*slot),
+			Proof: toArgBytesList(proof),
+		})
+	}
+
+	return res, nil
+}
+
 // GasPrice returns the average gas price based on the last x blocks
 func (e *Eth) GasPrice() (interface{}, error) {
 	// Grab the average gas price and convert it to a hex value
@@ -431,8 +618,157 @@ func (e *Eth) GasPrice() (interface{}, error) {
 	return avgGasPrice, nil
 }
 
+type feeHistoryResult struct {
+	OldestBlock   argUint64   `json:"oldestBlock"`
+	BaseFeePerGas []argUint64 `json:"baseFeePerGas"`
+	GasUsedRatio  []float64   `json:"gasUsedRatio"`
+	Reward        [][]argBig  `json:"reward,omitempty"`
+}
+
+// FeeHistory returns the base fees, the gas usage and the priority fees paid
+// at the reward percentiles, for the range of blocks ending with the newest block.
+// The base fees are 0 before the EIP-1559 fork
+func (e *Eth) FeeHistory(
+	blockCount argUint64,
+	newestBlock BlockNumber,
+	rewardPercentiles []float64,
+) (interface{}, error) {
+	for indx, percentile := range rewardPercentiles {
+		if percentile < 0 || percentile > 100 {
+			return nil, fmt.Errorf("%w: %f", ErrInvalidRewardPercentile, percentile)
+		}
+
+		if indx > 0 && percentile < rewardPercentiles[indx-1] {
+			return nil, fmt.Errorf("%w: %f is lower than %f", ErrInvalidRewardPercentile,
+				percentile, rewardPercentiles[indx-1])
+		}
+	}
+
+	// the pending block isn't built yet, the history ends with the latest block
+	if newestBlock == PendingBlockNumber {
+		newestBlock = LatestBlockNumber
+	}
+
+	newest, err := e.getBlockHeader(newestBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	count := uint64(blockCount)
+	if e.feeHistoryLimit != 0 && count > e.feeHistoryLimit {
+		count = e.feeHistoryLimit
+	}
+
+	if count > newest.Number+1 {
+		count = newest.Number + 1
+	}
+
+	res := &feeHistoryResult{
+		OldestBlock:   argUint64(newest.Number + 1 - count),
+		BaseFeePerGas: make([]argUint64, 0, count+1),
+		GasUsedRatio:  make([]float64, 0, count),
+	}
+
+	if len(rewardPercentiles) != 0 {
+		res.Reward = make([][]argBig, 0, count)
+	}
+
+	for number := uint64(res.OldestBlock); number <= newest.Number; number++ {
+		block, ok := e.store.GetBlockByNumber(number, true)
+		if !ok {
+			return nil, fmt.Errorf("error fetching block number %d", number)
+		}
+
+		res.BaseFeePerGas = append(res.BaseFeePerGas, argUint64(block.Header.BaseFee))
+
+		gasUsedRatio := float64(0)
+		if block.Header.GasLimit != 0 {
+			gasUsedRatio = float64(block.Header.GasUsed) / float64(block.Header.GasLimit)
+		}
+
+		res.GasUsedRatio = append(res.GasUsedRatio, gasUsedRatio)
+
+		if len(rewardPercentiles) == 0 {
+			continue
+		}
+
+		rewards, err := e.getBlockRewards(block, rewardPercentiles)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Reward = append(res.Reward, rewards)
+	}
+
+	// the base fee of the block following the newest block
+	if count != 0 {
+		res.BaseFeePerGas = append(res.BaseFeePerGas, argUint64(e.store.CalculateBaseFee(newest)))
+	}
+
+	return res, nil
+}
+
+// getBlockRewards returns the priority fees paid by the block transactions at the reward percentiles.
+// The percentiles are weighted by the gas used by the transactions, sorted by their priority fee
+func (e *Eth) getBlockRewards(block *types.Block, rewardPercentiles []float64) ([]argBig, error) {
+	rewards := make([]argBig, len(rewardPercentiles))
+
+	if len(block.Transactions) == 0 {
+		return rewards, nil
+	}
+
+	receipts, err := e.store.GetReceiptsByHash(block.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(receipts) != len(block.Transactions) {
+		return nil, fmt.Errorf("receipts not found for block %d", block.Number())
+	}
+
+	type txReward struct {
+		gasUsed uint64
+		reward  *big.Int
+	}
+
+	baseFee := block.Header.GetBaseFee()
+	txRewards := make([]txReward, len(block.Transactions))
+
+	for indx, txn := range block.Transactions {
+		txRewards[indx] = txReward{
+			gasUsed: receipts[indx].GasUsed,
+			reward:  txn.EffectiveTip(baseFee),
+		}
+	}
+
+	sort.Slice(txRewards, func(i, j int) bool {
+		return txRewards[i].reward.Cmp(txRewards[j].reward) < 0
+	})
+
+	txIndex := 0
+	sumGasUsed := txRewards[0].gasUsed
+
+	for indx, percentile := range rewardPercentiles {
+		thresholdGasUsed := uint64(float64(block.Header.GasUsed) * percentile / 100)
+
+		for sumGasUsed < thresholdGasUsed && txIndex < len(txRewards)-1 {
+			txIndex++
+			sumGasUsed += txRewards[txIndex].gasUsed
+		}
+
+		rewards[indx] = argBig(*txRewards[txIndex].reward)
+	}
+
+	return rewards, nil
+}
+
 // Call executes a smart contract call using the transaction object data
-func (e *Eth) Call(arg *txnArgs, filter BlockNumberOrHash) (interface{}, error) {
+func (e *Eth) Call(
+	ctx context.Context,
+	arg *txnArgs,
+	filter BlockNumberOrHash,
+	apiOverride stateOverrideSet,
+) (interface{}, error) {
 	var (
 		header *types.Header
 		err    error
@@ -448,6 +784,8 @@ func (e *Eth) Call(arg *txnArgs, filter BlockNumberOrHash) (interface{}, error)
 		return nil, fmt.Errorf("failed to get header from block hash or block number")
 	}
 
+	apiOverride.overrideNonce(arg)
+
 	transaction, err := e.decodeTxn(arg)
 
 	if err != nil {
@@ -459,7 +797,7 @@ func (e *Eth) Call(arg *txnArgs, filter BlockNumberOrHash) (interface{}, error)
 	}
 
 	// The return value of the execution is saved in the transition (returnValue field)
-	result, err := e.store.ApplyTxn(header, transaction)
+	result, err := e.store.ApplyTxn(ctx, header, transaction, apiOverride.toOverrideSet())
 	if err != nil {
 		return nil, err
 	}
@@ -476,8 +814,89 @@ func (e *Eth) Call(arg *txnArgs, filter BlockNumberOrHash) (interface{}, error)
 	return argBytesPtr(result.ReturnValue), nil
 }
 
+// CreateAccessList returns the access list of the call, the addresses and the storage slots it accesses,
+// along with the gas the call uses with it. The call is executed with the access list found, until it
+// accesses no other address or slot
+func (e *Eth) CreateAccessList(ctx context.Context, arg *txnArgs, filter BlockNumberOrHash) (interface{}, error) {
+	// The filter is empty, use the latest block by default
+	if filter.BlockNumber == nil && filter.BlockHash == nil {
+		filter.BlockNumber, _ = createBlockNumberPointer("latest")
+	}
+
+	header, err := e.getHeaderFromBlockNumberOrHash(&filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header from block hash or block number")
+	}
+
+	transaction, err := e.decodeTxn(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the caller didn't supply the gas limit in the message, then we set it to maximum possible => block gas limit
+	if transaction.Gas == 0 {
+		transaction.Gas = header.GasLimit
+	}
+
+	if transaction.Type == types.LegacyTx {
+		transaction.Type = types.AccessListTx
+	}
+
+	forksInTime := e.store.GetForksInTime(header.Number)
+	if !forksInTime.Berlin {
+		return nil, ErrAccessListNotSupported
+	}
+
+	// The sender, the recipient and the precompiled contracts are warm in any transaction
+	excluded := append(precompiled.ActiveAddresses(&forksInTime), transaction.From)
+	if transaction.To != nil {
+		excluded = append(excluded, *transaction.To)
+	} else {
+		excluded = append(excluded, crypto.CreateAddress(transaction.From, transaction.Nonce))
+	}
+
+	accessList := transaction.AccessList
+
+	for {
+		accessListTracer := tracer.NewAccessListTracer(accessList, excluded)
+
+		txn := transaction.Copy()
+		txn.AccessList = accessList.Copy()
+
+		result, err := e.store.TraceTxn(ctx, header, txn, accessListTracer)
+		if err != nil {
+			return nil, err
+		}
+
+		if accessListTracer.Equal(accessList) {
+			res := &accessListResult{
+				AccessList: accessListTracer.Result(),
+				GasUsed:    argUint64(result.GasUsed),
+			}
+
+			if result.Failed() {
+				res.Error = result.Err.Error()
+			}
+
+			return res, nil
+		}
+
+		accessList = accessListTracer.Result()
+	}
+}
+
+// callStipend is the gas the called contracts are given on top, when value is transferred
+const callStipend = 2300
+
 // EstimateGas estimates the gas needed to execute a transaction
-func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error) {
+func (e *Eth) EstimateGas(
+	ctx context.Context,
+	arg *txnArgs,
+	rawNum *BlockNumber,
+	apiOverride stateOverrideSet,
+) (interface{}, error) {
+	apiOverride.overrideNonce(arg)
+
 	transaction, err := e.decodeTxn(arg)
 	if err != nil {
 		return nil, err
@@ -494,35 +913,38 @@ func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error
 		return nil, err
 	}
 
-	forksInTime := e.store.GetForksInTime(uint64(number))
+	override := apiOverride.toOverrideSet()
 
-	var standardGas uint64
-	if transaction.IsContractCreation() && forksInTime.Homestead {
-		standardGas = state.TxGasContractCreation
-	} else {
-		standardGas = state.TxGas
+	forksInTime := e.store.GetForksInTime(header.Number)
+
+	intrinsicGas, err := state.TransactionGasCost(
+		transaction,
+		forksInTime.Homestead,
+		forksInTime.Istanbul,
+		forksInTime.EIP3860,
+	)
+	if err != nil {
+		return nil, err
 	}
 
 	var (
-		lowEnd  = standardGas
+		// lowEnd is the highest gas limit known to fail
+		lowEnd = intrinsicGas - 1
+
+		// highEnd is the lowest gas limit known to succeed, once the call is executed with it
 		highEnd uint64
 	)
 
 	// If the gas limit was passed in, use it as a ceiling
-	if transaction.Gas != 0 && transaction.Gas >= standardGas {
+	if transaction.Gas != 0 && transaction.Gas >= intrinsicGas {
 		highEnd = transaction.Gas
 	} else {
 		// If not, use the referenced block number
 		highEnd = header.GasLimit
 	}
 
-	gasPriceInt := new(big.Int).Set(transaction.GasPrice)
-	valueInt := new(big.Int).Set(transaction.Value)
-
-	var availableBalance *big.Int
-
-	// If the sender address is present, figure out how much available funds
-	// are we working with
+	// If the sender address is present, the gas is capped by the funds left
+	// once the value is paid, at the passed in gas price (if present)
 	if transaction.From != types.ZeroAddress {
 		// Get the account balance
 		// If the account is not initialized yet in state,
@@ -530,7 +952,7 @@ func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error
 		accountBalance := big.NewInt(0)
 		acc, err := e.store.GetAccount(header.StateRoot, transaction.From)
 
-		if err != nil && !errors.As(err, &ErrStateNotFound) {
+		if err != nil && !errors.Is(err, ErrStateNotFound) {
 			// An unrelated error occurred, return it
 			return nil, err
 		} else if err == nil {
@@ -539,40 +961,37 @@ func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error
 			accountBalance = acc.Balance
 		}
 
-		availableBalance = new(big.Int).Set(accountBalance)
-
-		if transaction.Value != nil {
-			if valueInt.Cmp(availableBalance) > 0 {
-				return 0, ErrInsufficientFunds
-			}
+		if account, ok := override[transaction.From]; ok && account.Balance != nil {
+			// The balance is overridden for the call
+			accountBalance = account.Balance
+		}
 
-			availableBalance.Sub(availableBalance, valueInt)
+		if transaction.Value.Cmp(accountBalance) > 0 {
+			return 0, ErrInsufficientFunds
 		}
-	}
 
-	// Recalculate the gas ceiling based on the available funds (if any)
-	// and the passed in gas price (if present)
-	if gasPriceInt.BitLen() != 0 && // Gas price has been set
-		availableBalance != nil && // Available balance is found
-		availableBalance.Cmp(big.NewInt(0)) > 0 { // Available balance > 0
-		gasAllowance := new(big.Int).Div(availableBalance, gasPriceInt)
+		availableBalance := new(big.Int).Sub(accountBalance, transaction.Value)
 
-		// Check the gas allowance for this account, make sure high end is capped to it
-		if gasAllowance.IsUint64() && highEnd > gasAllowance.Uint64() {
-			e.logger.Debug(
-				fmt.Sprintf(
-					"Gas estimation high-end capped by allowance [%d]",
-					gasAllowance.Uint64(),
-				),
-			)
+		if transaction.GasPrice.Sign() > 0 {
+			gasAllowance := new(big.Int).Div(availableBalance, transaction.GasPrice)
 
-			highEnd = gasAllowance.Uint64()
-		}
-	}
+			// Check the gas allowance for this account, make sure high end is capped to it
+			if gasAllowance.IsUint64() && highEnd > gasAllowance.Uint64() {
+				e.logger.Debug(
+					fmt.Sprintf(
+						"Gas estimation high-end capped by allowance [%d]",
+						gasAllowance.Uint64(),
+					),
+				)
+
+				highEnd = gasAllowance.Uint64()
+			}
 
-	// Checks if executor level valid gas errors occurred
-	isGasApplyError := func(err error) bool {
-		return errors.As(err, &state.ErrNotEnoughIntrinsicGas)
+			if highEnd < intrinsicGas {
+				return 0, fmt.Errorf("%w: gas allowance %d is below the intrinsic gas %d",
+					ErrInsufficientFunds, highEnd, intrinsicGas)
+			}
+		}
 	}
 
 	// Checks if EVM level valid gas errors occurred
@@ -581,86 +1000,86 @@ func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error
 			errors.Is(err, runtime.ErrCodeStoreOutOfGas)
 	}
 
-	// Checks if the EVM reverted during execution
-	isEVMRevertError := func(err error) bool {
-		return errors.Is(err, runtime.ErrExecutionReverted)
-	}
-
 	// Run the transaction with the specified gas value.
-	// Returns a status indicating if the transaction failed and the accompanying error
-	testTransaction := func(gas uint64, shouldOmitErr bool) (bool, error) {
+	// Returns a status indicating if the transaction failed, with its result.
+	// The intrinsic gas errors are failures, the other application errors are returned
+	testTransaction := func(gas uint64) (bool, *runtime.ExecutionResult, error) {
 		// Create a dummy transaction with the new gas
 		txn := transaction.Copy()
 		txn.Gas = gas
 
-		result, applyErr := e.store.ApplyTxn(header, txn)
-
+		result, applyErr := e.store.ApplyTxn(ctx, header, txn, override)
 		if applyErr != nil {
-			// Check the application error.
-			// Gas apply errors are valid, and should be ignored
-			if isGasApplyError(applyErr) && shouldOmitErr {
-				// Specifying the transaction failed, but not providing an error
-				// is an indication that a valid error occurred due to low gas,
-				// which will increase the lower bound for the search
-				return true, nil
+			if errors.Is(applyErr, state.ErrNotEnoughIntrinsicGas) {
+				return true, nil, nil
 			}
 
-			return true, applyErr
+			return true, nil, applyErr
 		}
 
-		// Check if an out of gas error happened during EVM execution
-		if result.Failed() {
-			if isGasEVMError(result.Err) && shouldOmitErr {
-				// Specifying the transaction failed, but not providing an error
-				// is an indication that a valid error occurred due to low gas,
-				// which will increase the lower bound for the search
-				return true, nil
-			}
+		return result.Failed(), result, nil
+	}
 
-			if isEVMRevertError(result.Err) {
-				// The EVM reverted during execution, attempt to extract the
-				// error message and return it
-				return true, constructErrorFromRevert(result)
-			}
+	// Execute the call with the highest gas limit first, if it fails for any reason
+	// other than the gas there is no gas limit the call would succeed with
+	failed, result, err := testTransaction(highEnd)
+	if err != nil {
+		return 0, err
+	}
+
+	if failed {
+		switch {
+		case result == nil:
+			return 0, fmt.Errorf("%w: gas limit %d", state.ErrNotEnoughIntrinsicGas, highEnd)
+		case result.Reverted():
+			// The EVM reverted during execution, return the error message and data
+			return 0, constructErrorFromRevert(result)
+		case isGasEVMError(result.Err):
+			return 0, fmt.Errorf("gas required exceeds allowance (%d)", highEnd)
+		default:
+			return 0, fmt.Errorf(
+				"unable to apply transaction even for the highest gas limit %d: %w",
+				highEnd,
+				result.Err,
+			)
+		}
+	}
 
-			return true, result.Err
+	// The calls only forward 63/64 of the gas left, the call is executed with the gas it used
+	// accounting for that and the stipend of the value transfers, which is the estimate most of the times
+	optimisticGas := (result.GasUsed + callStipend) * 64 / 63
+	if optimisticGas > lowEnd && optimisticGas < highEnd {
+		failed, _, err := testTransaction(optimisticGas)
+		if err != nil {
+			return 0, err
 		}
 
-		return false, nil
+		if failed {
+			lowEnd = optimisticGas
+		} else {
+			highEnd = optimisticGas
+		}
 	}
 
-	// Start the binary search for the lowest possible gas price
-	for lowEnd < highEnd {
-		mid := (lowEnd + highEnd) / 2
+	// Binary search for the lowest gas limit the call succeeds with. The call succeeded with the
+	// highest gas limit, so any failure, the reverts included, is caused by the gas being too low
+	for lowEnd+1 < highEnd {
+		mid := lowEnd + (highEnd-lowEnd)/2
 
-		failed, testErr := testTransaction(mid, true)
-		if testErr != nil &&
-			!isEVMRevertError(testErr) {
-			// Reverts are ignored in the binary search, but are checked later on
-			// during the execution for the optimal gas limit found
-			return 0, testErr
+		failed, _, err := testTransaction(mid)
+		if err != nil {
+			return 0, err
 		}
 
 		if failed {
 			// If the transaction failed => increase the gas
-			lowEnd = mid + 1
+			lowEnd = mid
 		} else {
 			// If the transaction didn't fail => make this ok value the high end
 			highEnd = mid
 		}
 	}
 
-	// Check if the highEnd is a good value to make the transaction pass
-	failed, err := testTransaction(highEnd, false)
-	if failed {
-		// The transaction shouldn't fail, for whatever reason, at highEnd
-		return 0, fmt.Errorf(
-			"unable to apply transaction even for the highest gas limit %d: %w",
-			highEnd,
-			err,
-		)
-	}
-
 	return hex.EncodeUint64(highEnd), nil
 }
 
@@ -675,18 +1094,24 @@ func (e *Eth) GetLogs(query *LogQuery) (interface{}, error) {
 
 		for indx, receipt := range receipts {
 			for logIndx, log := range receipt.Logs {
-				if query.Match(log) {
-					result = append(result, &Log{
-						Address:     log.Address,
-						Topics:      log.Topics,
-						Data:        argBytes(log.Data),
-						BlockNumber: argUint64(block.Header.Number),
-						BlockHash:   block.Header.Hash,
-						TxHash:      block.Transactions[indx].Hash,
-						TxIndex:     argUint64(indx),
-						LogIndex:    argUint64(logIndx),
-					})
+				if !query.Match(log) {
+					continue
 				}
+
+				if e.logsLimit != 0 && uint64(len(result)) >= e.logsLimit {
+					return fmt.Errorf("%w: the query returns more than %d logs", ErrTooManyLogs, e.logsLimit)
+				}
+
+				result = append(result, &Log{
+					Address:     log.Address,
+					Topics:      log.Topics,
+					Data:        argBytes(log.Data),
+					BlockNumber: argUint64(block.Header.Number),
+					BlockHash:   block.Header.Hash,
+					TxHash:      block.Transactions[indx].Hash,
+					TxIndex:     argUint64(indx),
+					LogIndex:    argUint64(logIndx),
+				})
 			}
 		}
 
@@ -699,8 +1124,8 @@ func (e *Eth) GetLogs(query *LogQuery) (interface{}, error) {
 			return nil, fmt.Errorf("not found")
 		}
 
-		if len(block.Transactions) == 0 {
-			// no txs in block, return empty response
+		if len(block.Transactions) == 0 || !query.MatchBloom(block.Header.LogsBloom) {
+			// no matching logs in block, return empty response
 			return result, nil
 		}
 
@@ -712,6 +1137,7 @@ func (e *Eth) GetLogs(query *LogQuery) (interface{}, error) {
 	}
 
 	head := e.store.Header().Number
+	finalized := e.store.FinalizedHeader().Number
 
 	resolveNum := func(num BlockNumber) uint64 {
 		if num == PendingBlockNumber {
@@ -726,6 +1152,10 @@ func (e *Eth) GetLogs(query *LogQuery) (interface{}, error) {
 			return head
 		}
 
+		if num == FinalizedBlockNumber {
+			return finalized
+		}
+
 		return uint64(num)
 	}
 
@@ -736,14 +1166,32 @@ func (e *Eth) GetLogs(query *LogQuery) (interface{}, error) {
 		return nil, fmt.Errorf("incorrect range")
 	}
 
+	if e.blockRangeLimit != 0 && to-from >= e.blockRangeLimit {
+		return nil, fmt.Errorf(
+			"%w: the query covers %d blocks, the limit is %d",
+			ErrBlockRangeTooHigh,
+			to-from+1,
+			e.blockRangeLimit,
+		)
+	}
+
 	for i := from; i <= to; i++ {
+		header, ok := e.store.GetHeaderByNumber(i)
+		if !ok {
+			break
+		}
+
+		if header.Number == 0 || !header.HasReceipts() || !query.MatchBloom(header.LogsBloom) {
+			// do not check logs in genesis, and skip the blocks that can't contain matching logs
+			continue
+		}
+
 		block, ok := e.store.GetBlockByNumber(i, true)
 		if !ok {
 			break
 		}
 
-		if block.Header.Number == 0 || len(block.Transactions) == 0 {
-			// do not check logs in genesis and skip if no txs
+		if len(block.Transactions) == 0 {
 			continue
 		}
 
@@ -774,7 +1222,7 @@ func (e *Eth) GetBalance(address types.Address, filter BlockNumberOrHash) (inter
 
 	// Extract the account balance
 	acc, err := e.store.GetAccount(header.StateRoot, address)
-	if errors.As(err, &ErrStateNotFound) {
+	if errors.Is(err, ErrStateNotFound) {
 		// Account not found, return an empty account
 		return argUintPtr(0), nil
 	} else if err != nil {
@@ -840,7 +1288,7 @@ func (e *Eth) GetCode(address types.Address, filter BlockNumberOrHash) (interfac
 	emptySlice := []byte{}
 	acc, err := e.store.GetAccount(header.StateRoot, address)
 
-	if errors.As(err, &ErrStateNotFound) {
+	if errors.Is(err, ErrStateNotFound) {
 		// If the account doesn't exist / is not initialized yet,
 		// return the default value
 		return "0x", nil
@@ -867,9 +1315,30 @@ func (e *Eth) NewBlockFilter() (interface{}, error) {
 	return e.filterManager.NewBlockFilter(nil), nil
 }
 
+// NewPendingTransactionFilter creates a filter in the node, to notify when new pending transactions arrive
+func (e *Eth) NewPendingTransactionFilter() (interface{}, error) {
+	return e.filterManager.NewPendingTxFilter(nil), nil
+}
+
 // GetFilterChanges is a polling method for a filter, which returns an array of logs which occurred since last poll.
 func (e *Eth) GetFilterChanges(id string) (interface{}, error) {
-	return e.filterManager.GetFilterChanges(id)
+	changes, err := e.filterManager.GetFilterChanges(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the changes are already encoded
+	return json.RawMessage(changes), nil
+}
+
+// GetFilterLogs returns all the logs matching the log filter with given ID
+func (e *Eth) GetFilterLogs(id string) (interface{}, error) {
+	query, err := e.filterManager.GetLogQuery(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.GetLogs(query)
 }
 
 // UninstallFilter uninstalls a filter with given ID
@@ -891,6 +1360,9 @@ func (e *Eth) getBlockHeader(number BlockNumber) (*types.Header, error) {
 	case LatestBlockNumber:
 		return e.store.Header(), nil
 
+	case FinalizedBlockNumber:
+		return e.store.FinalizedHeader(), nil
+
 	case EarliestBlockNumber:
 		header, ok := e.store.GetHeaderByNumber(uint64(0))
 		if !ok {
@@ -900,7 +1372,7 @@ func (e *Eth) getBlockHeader(number BlockNumber) (*types.Header, error) {
 		return header, nil
 
 	case PendingBlockNumber:
-		return nil, fmt.Errorf("fetching the pending header is not supported")
+		return e.pendingHeader()
 
 	default:
 		// Convert the block number from hex to uint64
@@ -913,6 +1385,80 @@ func (e *Eth) getBlockHeader(number BlockNumber) (*types.Header, error) {
 	}
 }
 
+// pendingHeader returns the header of the block following the head. It is neither sealed nor executed,
+// so it has the state of the head and the zero roots and hash, and its timestamp is an estimate
+func (e *Eth) pendingHeader() (*types.Header, error) {
+	head := e.store.Header()
+
+	gasLimit, err := e.store.CalculateGasLimit(head.Number + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := uint64(time.Now().Unix())
+	if timestamp <= head.Timestamp {
+		timestamp = head.Timestamp + 1
+	}
+
+	return &types.Header{
+		ParentHash: head.Hash,
+		Sha3Uncles: types.EmptyUncleHash,
+		StateRoot:  head.StateRoot,
+		Difficulty: head.Number + 1,
+		Number:     head.Number + 1,
+		GasLimit:   gasLimit,
+		Timestamp:  timestamp,
+		BaseFee:    e.store.CalculateBaseFee(head),
+	}, nil
+}
+
+// pendingBlock returns the provisional block following the head, with the executable transactions
+// of the pool that fit in its gas limit. The accounts are taken in address order, the transactions
+// of each account in nonce order, and an account is skipped from its first transaction that doesn't fit
+func (e *Eth) pendingBlock() (*types.Block, error) {
+	header, err := e.pendingHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	promoted, _ := e.store.GetTxs(false)
+
+	accounts := make([]types.Address, 0, len(promoted))
+	for addr := range promoted {
+		accounts = append(accounts, addr)
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return bytes.Compare(accounts[i].Bytes(), accounts[j].Bytes()) < 0
+	})
+
+	block := &types.Block{
+		Header:       header,
+		Transactions: []*types.Transaction{},
+	}
+
+	gasLeft := header.GasLimit
+
+	for _, addr := range accounts {
+		txs := promoted[addr]
+
+		sort.Slice(txs, func(i, j int) bool {
+			return txs[i].Nonce < txs[j].Nonce
+		})
+
+		for _, tx := range txs {
+			if tx.Gas > gasLeft {
+				break
+			}
+
+			gasLeft -= tx.Gas
+			block.Transactions = append(block.Transactions, tx)
+		}
+	}
+
+	return block, nil
+}
+
 // getNextNonce returns the next nonce for the account for the specified block
 func (e *Eth) getNextNonce(address types.Address, number BlockNumber) (uint64, error) {
 	if number == PendingBlockNumber {
@@ -932,7 +1478,7 @@ func (e *Eth) getNextNonce(address types.Address, number BlockNumber) (uint64, e
 
 	acc, err := e.store.GetAccount(header.StateRoot, address)
 
-	if errors.As(err, &ErrStateNotFound) {
+	if errors.Is(err, ErrStateNotFound) {
 		// If the account doesn't exist / isn't initialized,
 		// return a nonce value of 0
 		return 0, nil
@@ -998,6 +1544,11 @@ func (e *Eth) decodeTxn(arg *txnArgs) (*types.Transaction, error) {
 		txn.To = arg.To
 	}
 
+	if arg.AccessList != nil {
+		txn.Type = types.AccessListTx
+		txn.AccessList = arg.AccessList.Copy()
+	}
+
 	txn.ComputeHash()
 
 	return txn, nil
@@ -232,6 +232,13 @@ func TestEth_GetNextNonce(t *testing.T) {
 	}
 }
 
+const defaultTestFeeHistoryLimit uint64 = 1024
+
 func newTestEthEndpoint(store ethStore) *Eth {
-	return &Eth{hclog.NewNullLogger(), store, 100, nil}
+	return &Eth{
+		logger:          hclog.NewNullLogger(),
+		store:           store,
+		chainID:         100,
+		feeHistoryLimit: defaultTestFeeHistoryLimit,
+	}
 }
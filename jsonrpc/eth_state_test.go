@@ -2,14 +2,17 @@ package jsonrpc
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
 	"testing"
 
 	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/stretchr/testify/assert"
@@ -154,6 +157,38 @@ func TestEth_State_GetBalance(t *testing.T) {
 	}
 }
 
+// mockPrunedStore is a store whose state was pruned
+type mockPrunedStore struct {
+	*mockSpecialStore
+}
+
+func (m *mockPrunedStore) GetAccount(root types.Hash, addr types.Address) (*state.Account, error) {
+	return nil, fmt.Errorf("%w, root %s", state.ErrStatePruned, root)
+}
+
+func TestEth_State_GetBalance_Pruned(t *testing.T) {
+	store := &mockPrunedStore{
+		mockSpecialStore: &mockSpecialStore{
+			block: &types.Block{
+				Header: &types.Header{
+					Hash:      types.ZeroHash,
+					Number:    0,
+					StateRoot: types.EmptyRootHash,
+				},
+			},
+		},
+	}
+
+	eth := newTestEthEndpoint(store)
+	blockNumberZero := BlockNumber(0x0)
+
+	// the pruned state is not reported as an empty account
+	balance, err := eth.GetBalance(addr0, BlockNumberOrHash{BlockNumber: &blockNumberZero})
+
+	assert.ErrorIs(t, err, state.ErrStatePruned)
+	assert.Nil(t, balance)
+}
+
 func TestEth_State_GetTransactionCount(t *testing.T) {
 	store := &mockSpecialStore{
 		account: &mockAccount{
@@ -683,7 +718,7 @@ func TestEth_EstimateGas_GasLimit(t *testing.T) {
 			}
 
 			// Run the estimation
-			estimate, estimateErr := ethEndpoint.EstimateGas(testCase.transaction, nil)
+			estimate, estimateErr := ethEndpoint.EstimateGas(context.Background(), testCase.transaction, nil, nil)
 
 			if testCase.expectedError != nil {
 				if estimateErr == nil {
@@ -732,8 +767,10 @@ func TestEth_EstimateGas_Reverts(t *testing.T) {
 
 	// Run the estimation
 	estimate, estimateErr := ethEndpoint.EstimateGas(
+		context.Background(),
 		constructMockTx(nil, nil),
 		nil,
+		nil,
 	)
 
 	assert.Equal(t, 0, estimate)
@@ -758,8 +795,10 @@ func TestEth_EstimateGas_Errors(t *testing.T) {
 
 	// Run the estimation
 	estimate, estimateErr := ethEndpoint.EstimateGas(
+		context.Background(),
 		mockTx,
 		nil,
+		nil,
 	)
 
 	assert.Equal(t, 0, estimate)
@@ -835,10 +874,171 @@ func (m *mockSpecialStore) GetForksInTime(blockNumber uint64) chain.ForksInTime
 	return chain.ForksInTime{}
 }
 
-func (m *mockSpecialStore) ApplyTxn(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error) {
+func (m *mockSpecialStore) ApplyTxn(
+	ctx context.Context,
+	header *types.Header,
+	txn *types.Transaction,
+	override state.OverrideSet,
+) (*runtime.ExecutionResult, error) {
 	if m.applyTxnHook != nil {
 		return m.applyTxnHook(header, txn)
 	}
 
 	return &runtime.ExecutionResult{}, nil
 }
+
+func (m *mockSpecialStore) TraceTxn(
+	ctx context.Context,
+	header *types.Header,
+	txn *types.Transaction,
+	tracer runtime.Tracer,
+) (*runtime.ExecutionResult, error) {
+	return m.ApplyTxn(ctx, header, txn, nil)
+}
+
+// mockProofStore is a store proving the keys of a state trie
+type mockProofStore struct {
+	*mockSpecialStore
+
+	storage itrie.Storage
+	headers []*types.Header
+}
+
+func (m *mockProofStore) Header() *types.Header {
+	return m.headers[len(m.headers)-1]
+}
+
+func (m *mockProofStore) GetHeaderByNumber(blockNumber uint64) (*types.Header, bool) {
+	if blockNumber >= uint64(len(m.headers)) {
+		return nil, false
+	}
+
+	return m.headers[blockNumber], true
+}
+
+func (m *mockProofStore) GetProof(root types.Hash, key []byte) ([][]byte, []byte, error) {
+	return itrie.Prove(root, crypto.Keccak256(key), m.storage)
+}
+
+func TestEth_State_GetProof(t *testing.T) {
+	storage := itrie.NewMemoryStorage()
+	st := itrie.NewState(storage)
+	slot := types.StringToHash("1")
+
+	// the balance and the slot of the account change in the second block
+	commit := func(parent types.Hash, balance int64, value byte) types.Hash {
+		snap, err := st.NewSnapshotAt(parent)
+		assert.NoError(t, err)
+
+		txn := state.NewTxn(st, snap)
+		txn.SetBalance(addr0, big.NewInt(balance))
+		txn.SetState(addr0, slot, types.BytesToHash([]byte{value}))
+
+		_, root := txn.Commit(false)
+
+		return types.BytesToHash(root)
+	}
+
+	root0 := commit(types.EmptyRootHash, 10, 1)
+	root1 := commit(root0, 20, 2)
+
+	store := &mockProofStore{
+		mockSpecialStore: &mockSpecialStore{},
+		storage:          storage,
+		headers: []*types.Header{
+			{Number: 0, StateRoot: root0},
+			{Number: 1, StateRoot: root1},
+		},
+	}
+
+	eth := newTestEthEndpoint(store)
+
+	// verifyProof checks the proof of the account and of the slot against the root
+	verifyProof := func(t *testing.T, root types.Hash, address types.Address, res *accountProof) {
+		t.Helper()
+
+		proof := make([][]byte, len(res.AccountProof))
+		for i, node := range res.AccountProof {
+			proof[i] = node
+		}
+
+		value, err := itrie.VerifyProof(root, crypto.Keccak256(address.Bytes()), proof)
+		assert.NoError(t, err)
+
+		if value == nil {
+			return
+		}
+
+		var account state.Account
+		assert.NoError(t, account.UnmarshalRlp(value))
+		assert.Equal(t, account.Root, res.StorageHash)
+
+		for _, slot := range res.StorageProof {
+			proof := make([][]byte, len(slot.Proof))
+			for i, node := range slot.Proof {
+				proof[i] = node
+			}
+
+			_, err := itrie.VerifyProof(res.StorageHash, crypto.Keccak256(slot.Key.Bytes()), proof)
+			assert.NoError(t, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		number  BlockNumber
+		balance int64
+		value   int64
+	}{
+		{"latest block", LatestBlockNumber, 20, 2},
+		{"historical block", 0, 10, 1},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			number := tt.number
+
+			res, err := eth.GetProof(addr0, []types.Hash{slot, types.StringToHash("2")}, BlockNumberOrHash{BlockNumber: &number})
+			assert.NoError(t, err)
+
+			proof, ok := res.(*accountProof)
+			assert.True(t, ok)
+
+			header := store.headers[len(store.headers)-1]
+			if tt.number != LatestBlockNumber {
+				header = store.headers[tt.number]
+			}
+
+			assert.Equal(t, *argBigPtr(big.NewInt(tt.balance)), proof.Balance)
+			assert.NotEqual(t, types.EmptyRootHash, proof.StorageHash)
+			assert.Len(t, proof.StorageProof, 2)
+
+			// the second slot is not set
+			assert.Equal(t, *argBigPtr(big.NewInt(tt.value)), proof.StorageProof[0].Value)
+			assert.Equal(t, *argBigPtr(big.NewInt(0)), proof.StorageProof[1].Value)
+
+			verifyProof(t, header.StateRoot, addr0, proof)
+		})
+	}
+
+	t.Run("missing account", func(t *testing.T) {
+		res, err := eth.GetProof(uninitializedAddress, []types.Hash{slot}, BlockNumberOrHash{})
+		assert.NoError(t, err)
+
+		proof, ok := res.(*accountProof)
+		assert.True(t, ok)
+
+		// the exclusion proof of an empty account
+		assert.NotEmpty(t, proof.AccountProof)
+		assert.Equal(t, *argBigPtr(big.NewInt(0)), proof.Balance)
+		assert.Equal(t, argUint64(0), proof.Nonce)
+		assert.Equal(t, types.BytesToHash(crypto.Keccak256(nil)), proof.CodeHash)
+		assert.Equal(t, types.EmptyRootHash, proof.StorageHash)
+		assert.Len(t, proof.StorageProof, 1)
+		assert.Empty(t, proof.StorageProof[0].Proof)
+
+		verifyProof(t, root1, uninitializedAddress, proof)
+	})
+}
@@ -1,11 +1,13 @@
 package jsonrpc
 
 import (
+	"errors"
 	"math/big"
 	"testing"
 
 	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/txpool"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -31,6 +33,42 @@ func TestEth_TxnPool_SendRawTransaction(t *testing.T) {
 	}
 }
 
+func TestEth_TxnPool_SendRawTransaction_Rejected(t *testing.T) {
+	cases := []struct {
+		reason error
+		code   int
+	}{
+		{txpool.ErrNonceTooLow, -32010},
+		{txpool.ErrAlreadyKnown, -32011},
+		{txpool.ErrReplacementUnderpriced, -32012},
+		{txpool.ErrTxPoolOverflow, -32014},
+		{txpool.ErrMaxEnqueuedLimitReached, -32015},
+		{txpool.ErrNegativeValue, txRejectedErrorCode},
+	}
+
+	data := (&types.Transaction{From: addr0, V: big.NewInt(1)}).MarshalRLP()
+
+	for _, c := range cases {
+		store := &mockStoreTxn{err: &txpool.TxRejectedError{Reason: c.reason}}
+		eth := newTestEthEndpoint(store)
+
+		_, err := eth.SendRawTransaction(hex.EncodeToHex(data))
+
+		var rpcErr Error
+		if assert.ErrorAs(t, err, &rpcErr) {
+			assert.Equal(t, c.code, rpcErr.ErrorCode())
+			assert.Equal(t, c.reason.Error(), rpcErr.Error())
+		}
+	}
+
+	// the errors other than rejections are returned as is
+	store := &mockStoreTxn{err: errors.New("failed")}
+	eth := newTestEthEndpoint(store)
+
+	_, err := eth.SendRawTransaction(hex.EncodeToHex(data))
+	assert.EqualError(t, err, "failed")
+}
+
 func TestEth_TxnPool_SendTransaction(t *testing.T) {
 	store := &mockStoreTxn{}
 	store.AddAccount(addr0)
@@ -52,12 +90,13 @@ type mockStoreTxn struct {
 	ethStore
 	accounts map[types.Address]*mockAccount
 	txn      *types.Transaction
+	err      error
 }
 
 func (m *mockStoreTxn) AddTx(tx *types.Transaction) error {
 	m.txn = tx
 
-	return nil
+	return m.err
 }
 
 func (m *mockStoreTxn) GetNonce(addr types.Address) uint64 {
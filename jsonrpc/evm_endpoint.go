@@ -0,0 +1,115 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// evmStore provides access to the methods needed by the evm endpoint, served by the dev consensus
+type evmStore interface {
+	// DevMine seals a block, with the timestamp if not zero
+	DevMine(timestamp uint64) error
+
+	// DevSetNextBlockTimestamp sets the timestamp of the next sealed block
+	DevSetNextBlockTimestamp(timestamp uint64) error
+
+	// DevIncreaseTime moves the clock of the sealed blocks forward, and returns the total of the seconds added
+	DevIncreaseTime(seconds uint64) (uint64, error)
+
+	// DevSnapshot saves the state of the chain and the pool, and returns the id of the snapshot
+	DevSnapshot() (uint64, error)
+
+	// DevRevert reverts the chain and the pool to the snapshot, and drops it along with the later ones
+	DevRevert(id uint64) (bool, error)
+}
+
+// Evm is the evm jsonrpc endpoint of the dev mode, for the contract development tools.
+// It is registered only if the node runs the dev consensus
+type Evm struct {
+	store evmStore
+}
+
+// evmQuantity is a quantity argument of the evm and edge endpoints, either a number
+// or a decimal or hex string as the development tools provide them
+type evmQuantity uint64
+
+func (q *evmQuantity) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		var num uint64
+		if err := json.Unmarshal(data, &num); err != nil {
+			return fmt.Errorf("invalid quantity %s", string(data))
+		}
+
+		*q = evmQuantity(num)
+
+		return nil
+	}
+
+	num, err := types.ParseUint64orHex(&str)
+	if err != nil {
+		return err
+	}
+
+	*q = evmQuantity(num)
+
+	return nil
+}
+
+// Mine seals a block, with the timestamp if provided
+func (e *Evm) Mine(timestamp *evmQuantity) (interface{}, error) {
+	var ts uint64
+	if timestamp != nil {
+		ts = uint64(*timestamp)
+	}
+
+	if err := e.store.DevMine(ts); err != nil {
+		return nil, err
+	}
+
+	return "0x0", nil
+}
+
+// SetNextBlockTimestamp sets the timestamp of the next sealed block, it has to be after the head block
+func (e *Evm) SetNextBlockTimestamp(timestamp evmQuantity) (interface{}, error) {
+	if err := e.store.DevSetNextBlockTimestamp(uint64(timestamp)); err != nil {
+		return nil, err
+	}
+
+	return uint64(timestamp), nil
+}
+
+// IncreaseTime moves the clock of the sealed blocks forward by the seconds,
+// and returns the total of the seconds added so far
+func (e *Evm) IncreaseTime(seconds evmQuantity) (interface{}, error) {
+	total, err := e.store.DevIncreaseTime(uint64(seconds))
+	if err != nil {
+		return nil, err
+	}
+
+	return total, nil
+}
+
+// Snapshot saves the head block, its state and the transactions of the pool,
+// and returns the id to revert to them
+func (e *Evm) Snapshot() (interface{}, error) {
+	id, err := e.store.DevSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	return argUint64(id), nil
+}
+
+// Revert moves the chain and the pool back to the snapshot, which can't be reverted to again.
+// It returns false if the snapshot is not found
+func (e *Evm) Revert(id evmQuantity) (interface{}, error) {
+	reverted, err := e.store.DevRevert(uint64(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return reverted, nil
+}
@@ -0,0 +1,189 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockEvmStore struct {
+	headTimestamp uint64
+	nextTimestamp uint64
+	mined         []uint64
+	timeOffset    uint64
+	snapshots     uint64
+	reverted      []uint64
+}
+
+func (m *mockEvmStore) DevMine(timestamp uint64) error {
+	if timestamp != 0 && timestamp <= m.headTimestamp {
+		return errors.New("the timestamp is not after the head block")
+	}
+
+	m.mined = append(m.mined, timestamp)
+
+	return nil
+}
+
+func (m *mockEvmStore) DevSetNextBlockTimestamp(timestamp uint64) error {
+	if timestamp <= m.headTimestamp {
+		return errors.New("the timestamp is not after the head block")
+	}
+
+	m.nextTimestamp = timestamp
+
+	return nil
+}
+
+func (m *mockEvmStore) DevIncreaseTime(seconds uint64) (uint64, error) {
+	m.timeOffset += seconds
+
+	return m.timeOffset, nil
+}
+
+func (m *mockEvmStore) DevSnapshot() (uint64, error) {
+	m.snapshots++
+
+	return m.snapshots, nil
+}
+
+func (m *mockEvmStore) DevRevert(id uint64) (bool, error) {
+	if id == 0 || id > m.snapshots {
+		return false, nil
+	}
+
+	m.reverted = append(m.reverted, id)
+	m.snapshots = id - 1
+
+	return true, nil
+}
+
+// mockDevStore is the store of the node running the dev consensus
+type mockDevStore struct {
+	*mockStore
+	*mockEvmStore
+}
+
+func TestEvmQuantity_Unmarshal(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected uint64
+		err      bool
+	}{
+		{`3600`, 3600, false},
+		{`"3600"`, 3600, false},
+		{`"0xe10"`, 3600, false},
+		{`"0xzz"`, 0, true},
+		{`-1`, 0, true},
+		{`true`, 0, true},
+	}
+
+	for _, c := range cases {
+		var q evmQuantity
+
+		err := json.Unmarshal([]byte(c.input), &q)
+		if c.err {
+			assert.Error(t, err, c.input)
+
+			continue
+		}
+
+		assert.NoError(t, err, c.input)
+		assert.Equal(t, c.expected, uint64(q), c.input)
+	}
+}
+
+func TestEvmEndpoint_MineAndTime(t *testing.T) {
+	store := &mockEvmStore{headTimestamp: 100}
+	evm := &Evm{store}
+
+	res, err := evm.Mine(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x0", res)
+
+	timestamp := evmQuantity(200)
+	_, err = evm.Mine(&timestamp)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{0, 200}, store.mined)
+
+	timestamp = 50
+	_, err = evm.Mine(&timestamp)
+	assert.Error(t, err)
+
+	res, err = evm.SetNextBlockTimestamp(300)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(300), res)
+	assert.Equal(t, uint64(300), store.nextTimestamp)
+
+	_, err = evm.SetNextBlockTimestamp(100)
+	assert.Error(t, err)
+
+	res, err = evm.IncreaseTime(60)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(60), res)
+
+	res, err = evm.IncreaseTime(40)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), res)
+}
+
+func TestEvmEndpoint_SnapshotRevert(t *testing.T) {
+	store := &mockEvmStore{}
+	evm := &Evm{store}
+
+	res, err := evm.Snapshot()
+	assert.NoError(t, err)
+	assert.Equal(t, argUint64(1), res)
+
+	res, err = evm.Snapshot()
+	assert.NoError(t, err)
+	assert.Equal(t, argUint64(2), res)
+
+	res, err = evm.Revert(1)
+	assert.NoError(t, err)
+	assert.Equal(t, true, res)
+
+	// the later snapshots are dropped along with the reverted one
+	res, err = evm.Revert(2)
+	assert.NoError(t, err)
+	assert.Equal(t, false, res)
+	assert.Equal(t, []uint64{1}, store.reverted)
+}
+
+func TestEvmEndpoint_DevModeOnly(t *testing.T) {
+	store := &mockDevStore{newMockStore(), &mockEvmStore{}}
+
+	request := []byte(`{"method": "evm_snapshot", "params": []}`)
+
+	// the endpoint is not served out of the dev mode
+	dispatcher, err := newDispatcher(hclog.NewNullLogger(), store, dispatcherParams{})
+	assert.NoError(t, err)
+
+	resp, err := dispatcher.Handle(context.Background(), request, "")
+	assert.NoError(t, err)
+
+	var res string
+
+	assert.Error(t, expectJSONResult(resp, &res))
+
+	dispatcher, err = newDispatcher(hclog.NewNullLogger(), store, dispatcherParams{devMode: true})
+	assert.NoError(t, err)
+
+	resp, err = dispatcher.Handle(context.Background(), request, "")
+	assert.NoError(t, err)
+
+	assert.NoError(t, expectJSONResult(resp, &res))
+	assert.Equal(t, "0x1", res)
+
+	resp, err = dispatcher.Handle(context.Background(), []byte(`{"method": "evm_increaseTime", "params": [3600]}`), "")
+	assert.NoError(t, err)
+
+	var total uint64
+
+	assert.NoError(t, expectJSONResult(resp, &total))
+	assert.Equal(t, uint64(3600), total)
+}
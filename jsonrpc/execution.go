@@ -0,0 +1,101 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// contextType is the type of the context of the request, the first parameter of the endpoints executing the EVM
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// executionLimiter bounds the requests executing the EVM, such as eth_call or the debug traces.
+// Every execution is aborted after its timeout, and a limited number of them run at the same time,
+// the others are queued until a running one ends or their timeout expires
+type executionLimiter struct {
+	// the timeout of the executions, per method if overridden. 0 for no timeout
+	timeout        time.Duration
+	methodTimeouts map[string]time.Duration
+
+	// slots holds a token per running execution, nil for no limit
+	slots chan struct{}
+
+	metrics *Metrics
+}
+
+func newExecutionLimiter(
+	timeout time.Duration,
+	methodTimeouts map[string]time.Duration,
+	limit uint64,
+	metrics *Metrics,
+) *executionLimiter {
+	l := &executionLimiter{
+		timeout:        timeout,
+		methodTimeouts: methodTimeouts,
+		metrics:        metrics,
+	}
+
+	if limit > 0 {
+		l.slots = make(chan struct{}, limit)
+	}
+
+	return l
+}
+
+// timeoutOf returns the timeout of the executions of the method
+func (l *executionLimiter) timeoutOf(method string) time.Duration {
+	if timeout, ok := l.methodTimeouts[method]; ok {
+		return timeout
+	}
+
+	return l.timeout
+}
+
+// begin waits for the execution of the method to be allowed to run, and returns its context,
+// done once the timeout expires. The execution ends with the release function
+func (l *executionLimiter) begin(ctx context.Context, method string) (context.Context, func(), Error) {
+	timeout := l.timeoutOf(method)
+
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	// the time spent in the queue counts towards the timeout
+	if l.slots != nil {
+		l.metrics.QueuedExecutions.Add(1)
+
+		select {
+		case l.slots <- struct{}{}:
+			l.metrics.QueuedExecutions.Add(-1)
+		case <-ctx.Done():
+			l.metrics.QueuedExecutions.Add(-1)
+			cancel()
+
+			return nil, nil, l.abortedError(ctx, method)
+		}
+	}
+
+	release := func() {
+		cancel()
+
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+
+	return ctx, release, nil
+}
+
+// abortedError returns the error of the execution of the method aborted with its context
+func (l *executionLimiter) abortedError(ctx context.Context, method string) Error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return NewExecutionAbortedError(fmt.Sprintf("timeout = %s", l.timeoutOf(method)))
+	}
+
+	return NewExecutionAbortedError("canceled")
+}
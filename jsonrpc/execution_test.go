@@ -0,0 +1,72 @@
+package jsonrpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutionLimiter_Timeout(t *testing.T) {
+	limiter := newExecutionLimiter(
+		time.Second,
+		map[string]time.Duration{"debug_traceTransaction": time.Minute, "eth_estimateGas": 0},
+		0,
+		NilMetrics(),
+	)
+
+	assert.Equal(t, time.Second, limiter.timeoutOf("eth_call"))
+	assert.Equal(t, time.Minute, limiter.timeoutOf("debug_traceTransaction"))
+
+	ctx, release, err := limiter.begin(context.Background(), "eth_call")
+	assert.NoError(t, err)
+
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+
+	// the context is done once released
+	release()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	// the overridden timeout of 0 disables it
+	ctx, release, err = limiter.begin(context.Background(), "eth_estimateGas")
+	assert.NoError(t, err)
+
+	defer release()
+
+	_, ok = ctx.Deadline()
+	assert.False(t, ok)
+}
+
+func TestExecutionLimiter_Queue(t *testing.T) {
+	limiter := newExecutionLimiter(50*time.Millisecond, nil, 1, NilMetrics())
+
+	_, release, err := limiter.begin(context.Background(), "eth_call")
+	assert.NoError(t, err)
+
+	// the next execution waits for the running one, until its timeout expires
+	_, _, err = limiter.begin(context.Background(), "eth_call")
+	if assert.Error(t, err) {
+		assert.Equal(t, "execution aborted (timeout = 50ms)", err.Error())
+	}
+
+	started := make(chan struct{})
+
+	go func() {
+		_, next, err := limiter.begin(context.Background(), "eth_call")
+		assert.NoError(t, err)
+
+		next()
+		close(started)
+	}()
+
+	release()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("the queued execution was not started")
+	}
+}
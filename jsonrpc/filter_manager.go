@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
+	txpoolProto "github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -19,11 +20,17 @@ import (
 var (
 	ErrFilterDoesNotExists              = errors.New("filter does not exists")
 	ErrWSFilterDoesNotSupportGetChanges = errors.New("web socket Filter doesn't support to return a batch of the changes")
+	ErrFilterNotLogFilter               = errors.New("filter is not a log filter")
 )
 
-// defaultTimeout is the timeout to remove the filters that don't have a web socket stream
+// defaultTimeout is the timeout to remove the filters that don't have a web socket stream,
+// once they are not polled anymore
 var defaultTimeout = 1 * time.Minute
 
+// defaultBufferSize is the number of updates kept by a filter, and of the pending transactions
+// queued for the filters, before the new ones are dropped
+const defaultBufferSize uint64 = 1024
+
 const (
 	// The index in heap which is indicating the element is not in the heap
 	NoIndexInHeap = -1
@@ -57,14 +64,18 @@ type filterBase struct {
 
 	// websocket connection
 	ws wsConn
+
+	// maximum number of updates kept until they are sent or polled
+	bufferSize uint64
 }
 
 // newFilterBase initializes filterBase with unique ID
-func newFilterBase(ws wsConn) filterBase {
+func newFilterBase(ws wsConn, bufferSize uint64) filterBase {
 	return filterBase{
-		id:        uuid.New().String(),
-		ws:        ws,
-		heapIndex: NoIndexInHeap,
+		id:         uuid.New().String(),
+		ws:         ws,
+		heapIndex:  NoIndexInHeap,
+		bufferSize: bufferSize,
 	}
 }
 
@@ -153,12 +164,18 @@ type logFilter struct {
 	logs  []*Log
 }
 
-// appendLog appends new log to logs
-func (f *logFilter) appendLog(log *Log) {
+// appendLog appends new log to logs, it returns false if the log is dropped as the buffer is full
+func (f *logFilter) appendLog(log *Log) bool {
 	f.Lock()
 	defer f.Unlock()
 
+	if uint64(len(f.logs)) >= f.bufferSize {
+		return false
+	}
+
 	f.logs = append(f.logs, log)
+
+	return true
 }
 
 // takeLogUpdates returns all saved logs in filter and set new log slice
@@ -202,27 +219,102 @@ func (f *logFilter) sendUpdates() error {
 	return nil
 }
 
+// pendingTxFilter is a filter to store the hashes of the transactions promoted in the tx pool
+type pendingTxFilter struct {
+	filterBase
+	sync.Mutex
+	txHashes []types.Hash
+}
+
+// appendTxHash appends new hash to hashes, it returns false if the hash is dropped as the buffer is full
+func (f *pendingTxFilter) appendTxHash(hash types.Hash) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	if uint64(len(f.txHashes)) >= f.bufferSize {
+		return false
+	}
+
+	f.txHashes = append(f.txHashes, hash)
+
+	return true
+}
+
+// takeTxHashUpdates returns all saved hashes in filter and set new hash slice
+func (f *pendingTxFilter) takeTxHashUpdates() []types.Hash {
+	f.Lock()
+	defer f.Unlock()
+
+	txHashes := f.txHashes
+	f.txHashes = []types.Hash{}
+
+	return txHashes
+}
+
+// getUpdates returns stored hashes in string
+func (f *pendingTxFilter) getUpdates() (string, error) {
+	txHashes := f.takeTxHashUpdates()
+
+	res, err := json.Marshal(txHashes)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+// sendUpdates writes stored hashes to web socket stream
+func (f *pendingTxFilter) sendUpdates() error {
+	txHashes := f.takeTxHashUpdates()
+
+	for _, txHash := range txHashes {
+		res, err := json.Marshal(txHash)
+		if err != nil {
+			return err
+		}
+
+		if err := f.writeMessageToWs(string(res)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // filterManagerStore provides methods required by FilterManager
 type filterManagerStore interface {
 	// Header returns the current header of the chain (genesis if empty)
 	Header() *types.Header
 
 	// SubscribeEvents subscribes for chain head events
-	SubscribeEvents() blockchain.Subscription
+	SubscribeEvents() (<-chan *blockchain.Event, func())
+
+	// GetBlockByNumber returns a block using the provided number
+	GetBlockByNumber(num uint64, full bool) (*types.Block, bool)
 
 	// GetReceiptsByHash returns the receipts for a block hash
 	GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error)
+
+	// SubscribeTxEvents subscribes for tx pool events
+	SubscribeTxEvents(eventTypes ...txpoolProto.EventType) (<-chan *txpoolProto.TxPoolEvent, func())
 }
 
 // FilterManager manages all running filters
 type FilterManager struct {
 	logger hclog.Logger
 
-	timeout time.Duration
+	timeout    time.Duration
+	bufferSize uint64
+
+	store       filterManagerStore
+	blockStream *blockStream
 
-	store        filterManagerStore
-	subscription blockchain.Subscription
-	blockStream  *blockStream
+	eventsCh     <-chan *blockchain.Event
+	cancelEvents func()
+	eventsLock   sync.Mutex // guards cancelEvents, renewed once unsubscribed as a slow consumer
+
+	txEventsCh     <-chan *txpoolProto.TxPoolEvent
+	cancelTxEvents func()
 
 	lock     sync.RWMutex
 	filters  map[string]filter
@@ -232,10 +324,26 @@ type FilterManager struct {
 	closeCh  chan struct{}
 }
 
-func NewFilterManager(logger hclog.Logger, store filterManagerStore) *FilterManager {
+// NewFilterManager creates the filter manager with the buffer size of the filters, and the timeout
+// of the filters that are not polled. The defaults are used for the values that are 0
+func NewFilterManager(
+	logger hclog.Logger,
+	store filterManagerStore,
+	bufferSize uint64,
+	timeout time.Duration,
+) *FilterManager {
+	if bufferSize == 0 {
+		bufferSize = defaultBufferSize
+	}
+
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
 	m := &FilterManager{
 		logger:      logger.Named("filter"),
-		timeout:     defaultTimeout,
+		timeout:     timeout,
+		bufferSize:  bufferSize,
 		store:       store,
 		blockStream: &blockStream{},
 		lock:        sync.RWMutex{},
@@ -250,23 +358,27 @@ func NewFilterManager(logger hclog.Logger, store filterManagerStore) *FilterMana
 	m.blockStream.push(header)
 
 	// start the head watcher
-	m.subscription = store.SubscribeEvents()
+	m.eventsCh, m.cancelEvents = store.SubscribeEvents()
+
+	// start the pending transactions watcher
+	m.txEventsCh, m.cancelTxEvents = store.SubscribeTxEvents(txpoolProto.EventType_PROMOTED)
 
 	return m
 }
 
 // Run starts worker process to handle events
 func (f *FilterManager) Run() {
-	// watch for new events in the blockchain
-	watchCh := make(chan *blockchain.Event)
+	// watch for promoted transactions in the tx pool, the transactions are dropped
+	// when the filters can't keep up, so the tx pool events don't pile up
+	txHashCh := make(chan types.Hash, f.bufferSize)
 
 	go func() {
-		for {
-			evnt := f.subscription.GetEvent()
-			if evnt == nil {
-				return
+		for evnt := range f.txEventsCh {
+			select {
+			case txHashCh <- types.StringToHash(evnt.TxHash):
+			default:
+				f.logger.Warn("dropped the pending transaction, the buffer is full", "hash", evnt.TxHash)
 			}
-			watchCh <- evnt
 		}
 	}()
 
@@ -282,12 +394,41 @@ func (f *FilterManager) Run() {
 		}
 
 		select {
-		case evnt := <-watchCh:
+		case evnt, ok := <-f.eventsCh:
+			if !ok {
+				// the subscription is canceled on close
+				select {
+				case <-f.closeCh:
+					return
+				default:
+				}
+
+				// unsubscribed by the blockchain as a slow consumer,
+				// subscribe again and catch up with the blocks written in between
+				f.logger.Warn("the blockchain events are closed, subscribing again")
+
+				if !f.resubscribeEvents() {
+					return
+				}
+
+				if err := f.dispatchMissedBlocks(); err != nil {
+					f.logger.Error("failed to dispatch the missed blocks", "err", err)
+				}
+
+				continue
+			}
+
 			// new blockchain event
 			if err := f.dispatchEvent(evnt); err != nil {
 				f.logger.Error("failed to dispatch event", "err", err)
 			}
 
+		case txHash := <-txHashCh:
+			// new pending transaction
+			if err := f.dispatchTxHash(txHash); err != nil {
+				f.logger.Error("failed to dispatch pending transaction", "err", err)
+			}
+
 		case <-timeoutCh:
 			// timeout for filter
 			if !f.Uninstall(filterBase.id) {
@@ -307,12 +448,62 @@ func (f *FilterManager) Run() {
 // Close closed closeCh so that terminate worker
 func (f *FilterManager) Close() {
 	close(f.closeCh)
+
+	f.eventsLock.Lock()
+	f.cancelEvents()
+	f.eventsLock.Unlock()
+
+	f.cancelTxEvents()
+}
+
+// resubscribeEvents subscribes to the blockchain events again, unless the filter manager is closed
+func (f *FilterManager) resubscribeEvents() bool {
+	f.eventsLock.Lock()
+	defer f.eventsLock.Unlock()
+
+	select {
+	case <-f.closeCh:
+		return false
+	default:
+	}
+
+	f.eventsCh, f.cancelEvents = f.store.SubscribeEvents()
+
+	return true
+}
+
+// dispatchMissedBlocks dispatches the blocks written on top of the last known head, up to the current head.
+// The reorgs missed in between can't be replayed, and a block written while subscribing may be dispatched twice
+func (f *FilterManager) dispatchMissedBlocks() error {
+	last := f.blockStream.Head().header
+	head := f.store.Header()
+
+	if head.Hash == last.Hash {
+		return nil
+	}
+
+	evnt := &blockchain.Event{
+		Type: blockchain.EventHead,
+	}
+
+	for num := last.Number + 1; num < head.Number; num++ {
+		block, ok := f.store.GetBlockByNumber(num, false)
+		if !ok {
+			return fmt.Errorf("block %d not found", num)
+		}
+
+		evnt.AddNewHeader(block.Header)
+	}
+
+	evnt.AddNewHeader(head)
+
+	return f.dispatchEvent(evnt)
 }
 
 // NewBlockFilter adds new BlockFilter
 func (f *FilterManager) NewBlockFilter(ws wsConn) string {
 	filter := &blockFilter{
-		filterBase: newFilterBase(ws),
+		filterBase: newFilterBase(ws, f.bufferSize),
 		block:      f.blockStream.Head(),
 	}
 
@@ -322,13 +513,22 @@ func (f *FilterManager) NewBlockFilter(ws wsConn) string {
 // NewLogFilter adds new LogFilter
 func (f *FilterManager) NewLogFilter(logQuery *LogQuery, ws wsConn) string {
 	filter := &logFilter{
-		filterBase: newFilterBase(ws),
+		filterBase: newFilterBase(ws, f.bufferSize),
 		query:      logQuery,
 	}
 
 	return f.addFilter(filter)
 }
 
+// NewPendingTxFilter adds new PendingTxFilter
+func (f *FilterManager) NewPendingTxFilter(ws wsConn) string {
+	filter := &pendingTxFilter{
+		filterBase: newFilterBase(ws, f.bufferSize),
+	}
+
+	return f.addFilter(filter)
+}
+
 // Exists checks the filter with given ID exists
 func (f *FilterManager) Exists(id string) bool {
 	f.lock.RLock()
@@ -339,11 +539,30 @@ func (f *FilterManager) Exists(id string) bool {
 	return ok
 }
 
-// GetFilterChanges returns the updates of the filter with given ID in string
-func (f *FilterManager) GetFilterChanges(id string) (string, error) {
+// GetLogQuery returns the query of the log filter with given ID
+func (f *FilterManager) GetLogQuery(id string) (*LogQuery, error) {
 	f.lock.RLock()
 	defer f.lock.RUnlock()
 
+	filter, ok := f.filters[id]
+	if !ok {
+		return nil, ErrFilterDoesNotExists
+	}
+
+	logFilter, ok := filter.(*logFilter)
+	if !ok {
+		return nil, ErrFilterNotLogFilter
+	}
+
+	return logFilter.query, nil
+}
+
+// GetFilterChanges returns the updates of the filter with given ID in string,
+// the filter is polled so its timeout starts over
+func (f *FilterManager) GetFilterChanges(id string) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
 	filter, ok := f.filters[id]
 
 	if !ok {
@@ -355,6 +574,8 @@ func (f *FilterManager) GetFilterChanges(id string) (string, error) {
 		return "", ErrWSFilterDoesNotSupportGetChanges
 	}
 
+	f.resetTimeout(filter.getFilterBase())
+
 	res, err := filter.getUpdates()
 	if err != nil {
 		return "", err
@@ -371,6 +592,18 @@ func (f *FilterManager) Uninstall(id string) bool {
 	return f.removeFilterByID(id)
 }
 
+// RemoveFilterByWs removes all the filters of the given web socket connection, once it is closed
+func (f *FilterManager) RemoveFilterByWs(ws wsConn) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for id, filter := range f.filters {
+		if filter.getFilterBase().ws == ws {
+			f.removeFilterByID(id)
+		}
+	}
+}
+
 // removeFilterByID removes the filter with given ID, unsafe against race condition
 func (f *FilterManager) removeFilterByID(id string) bool {
 	filter, ok := f.filters[id]
@@ -406,6 +639,17 @@ func (f *FilterManager) addFilter(filter filter) string {
 	return base.id
 }
 
+// resetTimeout starts over the timeout of the filter, unsafe against race condition
+func (f *FilterManager) resetTimeout(base *filterBase) {
+	if base.heapIndex == NoIndexInHeap {
+		return
+	}
+
+	base.expiredAt = time.Now().Add(f.timeout)
+	heap.Fix(&f.timeouts, base.heapIndex)
+	f.emitSignalToUpdateCh()
+}
+
 func (f *FilterManager) emitSignalToUpdateCh() {
 	select {
 	// notify worker of new filter with timeout
@@ -487,7 +731,7 @@ func (f *FilterManager) appendLogsToFilters(header *types.Header, removed bool)
 
 	for indx, receipt := range receipts {
 		// check the logs with the filters
-		for _, log := range receipt.Logs {
+		for logIndx, log := range receipt.Logs {
 			nn := &Log{
 				Address:     log.Address,
 				Topics:      log.Topics,
@@ -496,12 +740,17 @@ func (f *FilterManager) appendLogsToFilters(header *types.Header, removed bool)
 				BlockHash:   header.Hash,
 				TxHash:      receipt.TxHash,
 				TxIndex:     argUint64(indx),
+				LogIndex:    argUint64(logIndx),
 				Removed:     removed,
 			}
 
-			for _, f := range logFilters {
-				if f.query.Match(log) {
-					f.appendLog(nn)
+			for _, filter := range logFilters {
+				if !filter.query.Match(log) {
+					continue
+				}
+
+				if !filter.appendLog(nn) {
+					f.logger.Warn("dropped the log, the buffer of the filter is full", "id", filter.id)
 				}
 			}
 		}
@@ -510,6 +759,19 @@ func (f *FilterManager) appendLogsToFilters(header *types.Header, removed bool)
 	return nil
 }
 
+// dispatchTxHash is a event handler for new pending transaction
+func (f *FilterManager) dispatchTxHash(txHash types.Hash) error {
+	// store the hash in each pending transaction filter
+	for _, filter := range f.getPendingTxFilters() {
+		if !filter.appendTxHash(txHash) {
+			f.logger.Warn("dropped the pending transaction, the buffer of the filter is full", "id", filter.id)
+		}
+	}
+
+	// send data to web socket stream
+	return f.flushWsFilters()
+}
+
 // flushWsFilters make each filters with web socket connection write the updates to web socket stream
 // flushWsFilters also removes the filters if flushWsFilters notices the connection is closed
 func (f *FilterManager) flushWsFilters() error {
@@ -570,6 +832,22 @@ func (f *FilterManager) getLogFilters() []*logFilter {
 	return logFilters
 }
 
+// getPendingTxFilters returns pendingTxFilters
+func (f *FilterManager) getPendingTxFilters() []*pendingTxFilter {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	pendingTxFilters := []*pendingTxFilter{}
+
+	for _, f := range f.filters {
+		if pendingTxFilter, ok := f.(*pendingTxFilter); ok {
+			pendingTxFilters = append(pendingTxFilters, pendingTxFilter)
+		}
+	}
+
+	return pendingTxFilters
+}
+
 type timeHeapImpl []*filterBase
 
 func (t *timeHeapImpl) addFilter(filter *filterBase) {
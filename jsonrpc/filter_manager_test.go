@@ -1,6 +1,9 @@
 package jsonrpc
 
 import (
+	"encoding/json"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,7 +17,7 @@ import (
 func TestFilterLog(t *testing.T) {
 	store := newMockStore()
 
-	m := NewFilterManager(hclog.NewNullLogger(), store)
+	m := NewFilterManager(hclog.NewNullLogger(), store, 0, 0)
 	go m.Run()
 
 	id := m.NewLogFilter(&LogQuery{
@@ -69,10 +72,85 @@ func TestFilterLog(t *testing.T) {
 	}
 }
 
+func TestFilterLog_Reorg(t *testing.T) {
+	store := newMockStore()
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 0, 0)
+	go m.Run()
+
+	id := m.NewLogFilter(&LogQuery{
+		Topics: [][]types.Hash{
+			{hash1},
+		},
+	}, nil)
+
+	receipts := func() []*types.Receipt {
+		return []*types.Receipt{
+			{
+				Logs: []*types.Log{
+					{Topics: []types.Hash{hash2}},
+					{Topics: []types.Hash{hash1}},
+				},
+			},
+		}
+	}
+
+	// the block 2 replaces the block 1 at the same height
+	store.emitEvent(&mockEvent{
+		OldChain: []*mockHeader{
+			{header: &types.Header{Number: 1, Hash: hash1}, receipts: receipts()},
+		},
+		NewChain: []*mockHeader{
+			{header: &types.Header{Number: 1, Hash: hash2}, receipts: receipts()},
+		},
+	})
+
+	time.Sleep(500 * time.Millisecond)
+
+	res, err := m.GetFilterChanges(id)
+	assert.NoError(t, err)
+
+	var logs []*Log
+
+	assert.NoError(t, json.Unmarshal([]byte(res), &logs))
+
+	if !assert.Len(t, logs, 2) {
+		t.FailNow()
+	}
+
+	// the logs of the old chain are flagged as removed
+	assert.Equal(t, hash1, logs[0].BlockHash)
+	assert.True(t, logs[0].Removed)
+	assert.Equal(t, hash2, logs[1].BlockHash)
+	assert.False(t, logs[1].Removed)
+
+	for _, log := range logs {
+		assert.Equal(t, argUint64(1), log.LogIndex)
+	}
+}
+
+func TestFilterGetLogQuery(t *testing.T) {
+	store := newMockStore()
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 0, 0)
+
+	query := &LogQuery{Topics: [][]types.Hash{{hash1}}}
+
+	res, err := m.GetLogQuery(m.NewLogFilter(query, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, query, res)
+
+	_, err = m.GetLogQuery(m.NewBlockFilter(nil))
+	assert.ErrorIs(t, err, ErrFilterNotLogFilter)
+
+	_, err = m.GetLogQuery("1")
+	assert.ErrorIs(t, err, ErrFilterDoesNotExists)
+}
+
 func TestFilterBlock(t *testing.T) {
 	store := newMockStore()
 
-	m := NewFilterManager(hclog.NewNullLogger(), store)
+	m := NewFilterManager(hclog.NewNullLogger(), store, 0, 0)
 	go m.Run()
 
 	// add block filter
@@ -130,11 +208,135 @@ func TestFilterBlock(t *testing.T) {
 	}
 }
 
+// evictingStore is a mock store whose subscribers of the blockchain events have a buffer of a single event,
+// the subscribers with a full buffer are unsubscribed, as the slow consumers are by the blockchain
+type evictingStore struct {
+	*mockStore
+
+	lock          sync.Mutex
+	blocks        []*types.Block
+	subscribers   map[chan *blockchain.Event]struct{}
+	subscriptions int
+}
+
+func newEvictingStore() *evictingStore {
+	return &evictingStore{
+		mockStore:   newMockStore(),
+		blocks:      []*types.Block{{Header: &types.Header{Number: 0, Hash: types.StringToHash("0")}}},
+		subscribers: map[chan *blockchain.Event]struct{}{},
+	}
+}
+
+func (m *evictingStore) Header() *types.Header {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.blocks[len(m.blocks)-1].Header
+}
+
+func (m *evictingStore) GetBlockByNumber(num uint64, _ bool) (*types.Block, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if num >= uint64(len(m.blocks)) {
+		return nil, false
+	}
+
+	return m.blocks[num], true
+}
+
+func (m *evictingStore) SubscribeEvents() (<-chan *blockchain.Event, func()) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	ch := make(chan *blockchain.Event, 1)
+	m.subscribers[ch] = struct{}{}
+	m.subscriptions++
+
+	return ch, func() {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+
+		m.unsubscribe(ch)
+	}
+}
+
+func (m *evictingStore) unsubscribe(ch chan *blockchain.Event) {
+	if _, ok := m.subscribers[ch]; ok {
+		delete(m.subscribers, ch)
+		close(ch)
+	}
+}
+
+func (m *evictingStore) numSubscriptions() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.subscriptions
+}
+
+// writeBlock writes the next block, and sends its event to the subscribers
+func (m *evictingStore) writeBlock() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	num := uint64(len(m.blocks))
+	header := &types.Header{Number: num, Hash: types.StringToHash(fmt.Sprint(num))}
+	m.blocks = append(m.blocks, &types.Block{Header: header})
+
+	evnt := &blockchain.Event{Type: blockchain.EventHead}
+	evnt.AddNewHeader(header)
+
+	for ch := range m.subscribers {
+		select {
+		case ch <- evnt:
+		default:
+			m.unsubscribe(ch)
+		}
+	}
+}
+
+func TestFilterBlock_SlowConsumer(t *testing.T) {
+	store := newEvictingStore()
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 0, 0)
+	defer m.Close()
+
+	id := m.NewBlockFilter(nil)
+
+	// the blocks overflow the buffer of the subscription before the manager runs
+	for i := 0; i < 3; i++ {
+		store.writeBlock()
+	}
+
+	go m.Run()
+
+	// the manager subscribes again and catches up with the head
+	assert.Eventually(t, func() bool {
+		return store.numSubscriptions() == 2 && m.blockStream.Head().header.Number == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	changes, err := m.GetFilterChanges(id)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("[\"%s\",\"%s\",\"%s\"]",
+		types.StringToHash("1"), types.StringToHash("2"), types.StringToHash("3")), changes)
+
+	// and keeps getting the new blocks
+	store.writeBlock()
+
+	assert.Eventually(t, func() bool {
+		return m.blockStream.Head().header.Number == 4
+	}, 5*time.Second, 10*time.Millisecond)
+
+	changes, err = m.GetFilterChanges(id)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("[\"%s\"]", types.StringToHash("4")), changes)
+}
+
 func TestFilterTimeout(t *testing.T) {
 	store := newMockStore()
 
-	m := NewFilterManager(hclog.NewNullLogger(), store)
-	m.timeout = 2 * time.Second
+	m := NewFilterManager(hclog.NewNullLogger(), store, 0, 2*time.Second)
 
 	go m.Run()
 
@@ -146,6 +348,28 @@ func TestFilterTimeout(t *testing.T) {
 	assert.False(t, m.Exists(id))
 }
 
+func TestFilterTimeout_Polling(t *testing.T) {
+	store := newMockStore()
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 0, 2*time.Second)
+
+	go m.Run()
+
+	id := m.NewBlockFilter(nil)
+
+	// the filter is kept as long as it is polled
+	for i := 0; i < 3; i++ {
+		time.Sleep(time.Second)
+
+		_, err := m.GetFilterChanges(id)
+		assert.NoError(t, err)
+	}
+
+	assert.True(t, m.Exists(id))
+	time.Sleep(3 * time.Second)
+	assert.False(t, m.Exists(id))
+}
+
 func TestFilterWebsocket(t *testing.T) {
 	store := newMockStore()
 
@@ -153,7 +377,7 @@ func TestFilterWebsocket(t *testing.T) {
 		msgCh: make(chan []byte, 1),
 	}
 
-	m := NewFilterManager(hclog.NewNullLogger(), store)
+	m := NewFilterManager(hclog.NewNullLogger(), store, 0, 0)
 	go m.Run()
 
 	id := m.NewBlockFilter(mock)
@@ -221,7 +445,7 @@ func (m *MockClosedWSConnection) WriteMessage(_messageType int, _data []byte) er
 func TestClosedFilterDeletion(t *testing.T) {
 	store := newMockStore()
 
-	m := NewFilterManager(hclog.NewNullLogger(), store)
+	m := NewFilterManager(hclog.NewNullLogger(), store, 0, 0)
 
 	go m.Run()
 
@@ -245,3 +469,86 @@ func TestClosedFilterDeletion(t *testing.T) {
 	// false because filter was removed automatically
 	assert.False(t, m.Exists(id))
 }
+
+func TestFilterPendingTx(t *testing.T) {
+	store := newMockStore()
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 0, 0)
+	go m.Run()
+
+	id := m.NewPendingTxFilter(nil)
+
+	store.emitTxEvent(hash1)
+	store.emitTxEvent(hash2)
+
+	time.Sleep(500 * time.Millisecond)
+
+	res, fetchErr := m.GetFilterChanges(id)
+	assert.NoError(t, fetchErr)
+	assert.JSONEq(t, `["`+hash1.String()+`","`+hash2.String()+`"]`, res)
+
+	// the hashes are only returned once
+	res, fetchErr = m.GetFilterChanges(id)
+	assert.NoError(t, fetchErr)
+	assert.JSONEq(t, `[]`, res)
+}
+
+func TestFilterPendingTx_Websocket(t *testing.T) {
+	store := newMockStore()
+
+	mock := &mockWsConn{
+		msgCh: make(chan []byte, 1),
+	}
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 0, 0)
+	go m.Run()
+
+	m.NewPendingTxFilter(mock)
+
+	store.emitTxEvent(hash1)
+
+	select {
+	case msg := <-mock.msgCh:
+		assert.Contains(t, string(msg), hash1.String())
+	case <-time.After(2 * time.Second):
+		t.Fatal("pending transaction not received in 2 seconds")
+	}
+}
+
+func TestFilterBufferSize(t *testing.T) {
+	store := newMockStore()
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 1, 0)
+
+	id := m.NewPendingTxFilter(nil)
+
+	// the second hash doesn't fit in the buffer of the filter
+	assert.NoError(t, m.dispatchTxHash(hash1))
+	assert.NoError(t, m.dispatchTxHash(hash2))
+
+	res, fetchErr := m.GetFilterChanges(id)
+	assert.NoError(t, fetchErr)
+	assert.JSONEq(t, `["`+hash1.String()+`"]`, res)
+}
+
+func TestRemoveFilterByWs(t *testing.T) {
+	store := newMockStore()
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 0, 0)
+
+	closed, open := &mockWsConn{}, &mockWsConn{}
+
+	blockID := m.NewBlockFilter(closed)
+	logID := m.NewLogFilter(&LogQuery{}, closed)
+	pendingTxID := m.NewPendingTxFilter(closed)
+	otherID := m.NewBlockFilter(open)
+
+	m.RemoveFilterByWs(closed)
+
+	assert.False(t, m.Exists(blockID))
+	assert.False(t, m.Exists(logID))
+	assert.False(t, m.Exists(pendingTxID))
+
+	// the filters of the other connections are kept
+	assert.True(t, m.Exists(otherID))
+}
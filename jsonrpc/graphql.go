@@ -0,0 +1,632 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+// graphQLMethod is the method name the access control checks the GraphQL queries with, as read methods
+const graphQLMethod = "graphql"
+
+// maxGraphQLBodySize is the max size of the body of the POST requests, the larger ones are rejected
+const maxGraphQLBodySize = 1 << 20
+
+// gqlObject is an object type of the GraphQL schema
+type gqlObject struct {
+	name   string
+	fields map[string]*gqlField
+}
+
+// gqlField is a field of an object type. The resolver returns the value of the field from the one of the object,
+// a scalar marshaled as is, a value of the object type of the field, or a list of them as []interface{}
+type gqlField struct {
+	args map[string]*gqlArg
+
+	// typ is the object type of the field, empty for the scalars
+	typ string
+
+	resolve func(parent interface{}, args map[string]interface{}) (interface{}, error)
+}
+
+// gqlArg is an argument of a field, coerced from the value of the query
+type gqlArg struct {
+	required bool
+	coerce   func(value interface{}) (interface{}, error)
+}
+
+// gqlSchema is the set of the object types of the schema, the queries are executed from the Query type
+type gqlSchema map[string]*gqlObject
+
+// gqlError is an error of the response, the field errors have the path of the field
+type gqlError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+type gqlResponse struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*gqlError `json:"errors,omitempty"`
+}
+
+// gqlQueryError is an error of the query itself, such as an unknown field, the query is not executed
+type gqlQueryError struct {
+	msg string
+}
+
+func (e *gqlQueryError) Error() string {
+	return e.msg
+}
+
+func newQueryError(format string, args ...interface{}) error {
+	return &gqlQueryError{msg: fmt.Sprintf(format, args...)}
+}
+
+// gqlResult is the result of a selection set, marshaled with the fields in the order of the query
+type gqlResult struct {
+	keys   []string
+	values []interface{}
+}
+
+func (r *gqlResult) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for indx, key := range r.keys {
+		if indx > 0 {
+			buf.WriteByte(',')
+		}
+
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(r.values[indx])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// gqlExecution is the execution of an operation, collecting the errors of the fields
+type gqlExecution struct {
+	schema    gqlSchema
+	fragments map[string]*gqlFragment
+	variables map[string]interface{}
+	errors    []*gqlError
+}
+
+// execute runs the operation of the query, the only one of the document if the operation name is not set
+func (s gqlSchema) execute(query, operationName string, variables map[string]interface{}) *gqlResponse {
+	requestError := func(err error) *gqlResponse {
+		return &gqlResponse{Errors: []*gqlError{{Message: err.Error()}}}
+	}
+
+	doc, err := parseGraphQL(query)
+	if err != nil {
+		return requestError(fmt.Errorf("syntax error: %w", err))
+	}
+
+	operation, err := doc.operation(operationName)
+	if err != nil {
+		return requestError(err)
+	}
+
+	if operation.kind != "query" {
+		return requestError(fmt.Errorf("the %s operations are not supported", operation.kind))
+	}
+
+	exec := &gqlExecution{
+		schema:    s,
+		fragments: doc.fragments,
+		variables: map[string]interface{}{},
+	}
+
+	for _, variable := range operation.variables {
+		value, ok := variables[variable.name]
+		if !ok && variable.hasDefault {
+			value, ok = variable.defaultValue, true
+		}
+
+		if variable.nonNull && (!ok || value == nil) {
+			return requestError(fmt.Errorf("the variable $%s is required", variable.name))
+		}
+
+		exec.variables[variable.name] = value
+	}
+
+	data, err := exec.executeSelections(s["Query"], nil, operation.selections, nil)
+	if err != nil {
+		return requestError(err)
+	}
+
+	return &gqlResponse{Data: data, Errors: exec.errors}
+}
+
+// operation returns the operation of the document with the name
+func (d *gqlDocument) operation(name string) (*gqlOperation, error) {
+	if name == "" {
+		if len(d.operations) != 1 {
+			return nil, errors.New("the operation name is required for the documents of several operations")
+		}
+
+		return d.operations[0], nil
+	}
+
+	for _, operation := range d.operations {
+		if operation.name == name {
+			return operation, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+// collectFields returns the fields of the selections by response key, in the order of the query.
+// The fragments are expanded for the object type, the fields skipped by the directives are left out
+func (e *gqlExecution) collectFields(
+	object *gqlObject,
+	selections []*gqlSelection,
+	keys []string,
+	fields map[string][]*gqlSelection,
+	visited map[string]bool,
+) ([]string, error) {
+	for _, selection := range selections {
+		include, err := e.included(selection.directives)
+		if err != nil {
+			return nil, err
+		}
+
+		if !include {
+			continue
+		}
+
+		switch {
+		case selection.spread != "":
+			if visited[selection.spread] {
+				continue
+			}
+
+			visited[selection.spread] = true
+
+			fragment, ok := e.fragments[selection.spread]
+			if !ok {
+				return nil, newQueryError("unknown fragment %q", selection.spread)
+			}
+
+			if fragment.typeCondition != object.name {
+				continue
+			}
+
+			if keys, err = e.collectFields(object, fragment.selections, keys, fields, visited); err != nil {
+				return nil, err
+			}
+
+		case selection.inline:
+			if selection.typeCondition != "" && selection.typeCondition != object.name {
+				continue
+			}
+
+			if keys, err = e.collectFields(object, selection.selections, keys, fields, visited); err != nil {
+				return nil, err
+			}
+
+		default:
+			if _, ok := fields[selection.alias]; !ok {
+				keys = append(keys, selection.alias)
+			}
+
+			fields[selection.alias] = append(fields[selection.alias], selection)
+		}
+	}
+
+	return keys, nil
+}
+
+// included checks the @skip and @include directives of the selection
+func (e *gqlExecution) included(directives []*gqlDirective) (bool, error) {
+	for _, directive := range directives {
+		if directive.name != "skip" && directive.name != "include" {
+			return false, newQueryError("unknown directive @%s", directive.name)
+		}
+
+		if len(directive.args) != 1 || directive.args[0].name != "if" {
+			return false, newQueryError("the directive @%s requires the if argument", directive.name)
+		}
+
+		value, ok := resolveValue(directive.args[0].value, e.variables).(bool)
+		if !ok {
+			return false, newQueryError("the if argument of the directive @%s must be a boolean", directive.name)
+		}
+
+		if value == (directive.name == "skip") {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (e *gqlExecution) executeSelections(
+	object *gqlObject,
+	parent interface{},
+	selections []*gqlSelection,
+	path []interface{},
+) (*gqlResult, error) {
+	fields := map[string][]*gqlSelection{}
+
+	keys, err := e.collectFields(object, selections, nil, fields, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &gqlResult{
+		keys:   keys,
+		values: make([]interface{}, len(keys)),
+	}
+
+	for indx, key := range keys {
+		if result.values[indx], err = e.executeField(object, parent, fields[key], append(path, key)); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// executeField resolves the field of the object, the selections of the field are merged.
+// A field failing to resolve is null, with the error added to the response
+func (e *gqlExecution) executeField(
+	object *gqlObject,
+	parent interface{},
+	nodes []*gqlSelection,
+	path []interface{},
+) (interface{}, error) {
+	node := nodes[0]
+
+	if node.name == "__typename" {
+		return object.name, nil
+	}
+
+	field, ok := object.fields[node.name]
+	if !ok {
+		return nil, newQueryError("cannot query the field %q of the type %s", node.name, object.name)
+	}
+
+	args, err := e.coerceArguments(field, node)
+	if err != nil {
+		return nil, err
+	}
+
+	selections := []*gqlSelection{}
+	for _, node := range nodes {
+		selections = append(selections, node.selections...)
+	}
+
+	if field.typ == "" && len(selections) > 0 {
+		return nil, newQueryError("the field %q of the type %s has no fields to select", node.name, object.name)
+	} else if field.typ != "" && len(selections) == 0 {
+		return nil, newQueryError("the field %q of the type %s requires a selection of fields", node.name, object.name)
+	}
+
+	value, err := field.resolve(parent, args)
+	if err != nil {
+		e.errors = append(e.errors, &gqlError{
+			Message: err.Error(),
+			Path:    append([]interface{}{}, path...),
+		})
+
+		return nil, nil
+	}
+
+	if field.typ == "" || value == nil {
+		return value, nil
+	}
+
+	fieldObject := e.schema[field.typ]
+
+	list, ok := value.([]interface{})
+	if !ok {
+		return e.executeSelections(fieldObject, value, selections, path)
+	}
+
+	results := make([]interface{}, len(list))
+
+	for indx, item := range list {
+		if results[indx], err = e.executeSelections(fieldObject, item, selections, append(path, indx)); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+func (e *gqlExecution) coerceArguments(field *gqlField, node *gqlSelection) (map[string]interface{}, error) {
+	args := map[string]interface{}{}
+
+	for _, arg := range node.args {
+		def, ok := field.args[arg.name]
+		if !ok {
+			return nil, newQueryError("unknown argument %q of the field %q", arg.name, node.name)
+		}
+
+		value := resolveValue(arg.value, e.variables)
+		if value == nil {
+			continue
+		}
+
+		coerced, err := def.coerce(value)
+		if err != nil {
+			return nil, newQueryError("invalid argument %q of the field %q: %v", arg.name, node.name, err)
+		}
+
+		args[arg.name] = coerced
+	}
+
+	for name, def := range field.args {
+		if _, ok := args[name]; def.required && !ok {
+			return nil, newQueryError("the argument %q of the field %q is required", name, node.name)
+		}
+	}
+
+	return args, nil
+}
+
+// The input scalars of the schema. The Long values are read from the numbers and from the decimal
+// or the hex strings, the BigInt, Bytes32 and Address values from the hex strings
+
+func coerceLong(value interface{}) (interface{}, error) {
+	var raw string
+
+	switch v := value.(type) {
+	case json.Number:
+		raw = v.String()
+	case string:
+		raw = v
+	default:
+		return nil, fmt.Errorf("expected a Long value")
+	}
+
+	number, err := strconv.ParseUint(raw, 0, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Long value %s", raw)
+	}
+
+	return number, nil
+}
+
+func coerceInt(value interface{}) (interface{}, error) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("expected an Int value")
+	}
+
+	n, err := strconv.ParseInt(number.String(), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Int value %s", number)
+	}
+
+	return int(n), nil
+}
+
+// coerceHexBytes reads the hex string of the given number of bytes
+func coerceHexBytes(value interface{}, size int, name string) ([]byte, error) {
+	raw, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a %s value", name)
+	}
+
+	buf, err := hex.DecodeHex(raw)
+	if err != nil || len(buf) != size {
+		return nil, fmt.Errorf("invalid %s value %s", name, raw)
+	}
+
+	return buf, nil
+}
+
+func coerceBytes32(value interface{}) (interface{}, error) {
+	buf, err := coerceHexBytes(value, types.HashLength, "Bytes32")
+	if err != nil {
+		return nil, err
+	}
+
+	return types.BytesToHash(buf), nil
+}
+
+func coerceAddress(value interface{}) (interface{}, error) {
+	buf, err := coerceHexBytes(value, types.AddressLength, "Address")
+	if err != nil {
+		return nil, err
+	}
+
+	return types.BytesToAddress(buf), nil
+}
+
+// coerceList returns the coercion of the lists of the items, a single item is a list of one
+func coerceList(item func(interface{}) (interface{}, error)) func(interface{}) (interface{}, error) {
+	return func(value interface{}) (interface{}, error) {
+		list, ok := value.([]interface{})
+		if !ok {
+			list = []interface{}{value}
+		}
+
+		values := make([]interface{}, len(list))
+
+		for indx, raw := range list {
+			if raw == nil {
+				return nil, fmt.Errorf("null item at %d", indx)
+			}
+
+			coerced, err := item(raw)
+			if err != nil {
+				return nil, err
+			}
+
+			values[indx] = coerced
+		}
+
+		return values, nil
+	}
+}
+
+// graphQLRequest is the request of a query, sent as the json body of a POST request
+// or as the parameters of a GET request
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// graphQLHandler serves the queries of the schema over HTTP
+type graphQLHandler struct {
+	logger hclog.Logger
+	schema gqlSchema
+	config *Config
+}
+
+func decodeGraphQLVariables(raw []byte, variables *map[string]interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	return decoder.Decode(variables)
+}
+
+func (h *graphQLHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding")
+
+	if req.Method == http.MethodOptions {
+		return
+	}
+
+	writeResponse := func(status int, resp *gqlResponse) {
+		w.WriteHeader(status)
+
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			h.logger.Error("failed to write the GraphQL response", "err", err)
+		}
+	}
+
+	requestError := func(status int, err error) {
+		writeResponse(status, &gqlResponse{Errors: []*gqlError{{Message: err.Error()}}})
+	}
+
+	if !h.config.AccessControl.isAllowed(h.config.AccessControl.clientIP(req), graphQLMethod) {
+		requestError(http.StatusForbidden, NewUnauthorizedError(graphQLMethod))
+
+		return
+	}
+
+	request := &graphQLRequest{}
+
+	switch req.Method {
+	case http.MethodGet:
+		request.Query = req.URL.Query().Get("query")
+		request.OperationName = req.URL.Query().Get("operationName")
+
+		if raw := req.URL.Query().Get("variables"); raw != "" {
+			if err := decodeGraphQLVariables([]byte(raw), &request.Variables); err != nil {
+				requestError(http.StatusBadRequest, fmt.Errorf("invalid variables: %w", err))
+
+				return
+			}
+		}
+
+	case http.MethodPost:
+		data, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxGraphQLBodySize))
+		if err != nil {
+			requestError(http.StatusBadRequest, err)
+
+			return
+		}
+
+		var raw struct {
+			Query         string          `json:"query"`
+			OperationName string          `json:"operationName"`
+			Variables     json.RawMessage `json:"variables"`
+		}
+
+		if err := json.Unmarshal(data, &raw); err != nil {
+			requestError(http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+
+			return
+		}
+
+		request.Query, request.OperationName = raw.Query, raw.OperationName
+
+		if len(raw.Variables) > 0 && !bytes.Equal(raw.Variables, []byte("null")) {
+			if err := decodeGraphQLVariables(raw.Variables, &request.Variables); err != nil {
+				requestError(http.StatusBadRequest, fmt.Errorf("invalid variables: %w", err))
+
+				return
+			}
+		}
+
+	default:
+		requestError(http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+
+		return
+	}
+
+	if strings.TrimSpace(request.Query) == "" {
+		requestError(http.StatusBadRequest, errors.New("the query is empty"))
+
+		return
+	}
+
+	h.logger.Debug("handle", "query", request.Query)
+
+	writeResponse(http.StatusOK, h.schema.execute(request.Query, request.OperationName, request.Variables))
+}
+
+// setupGraphQL starts the GraphQL server on its own address, the queries are resolved by the eth endpoint
+func (j *JSONRPC) setupGraphQL(eth *Eth) error {
+	j.logger.Info("graphql server started", "addr", j.config.GraphQLAddr.String())
+
+	lis, err := net.Listen("tcp", j.config.GraphQLAddr.String())
+	if err != nil {
+		return err
+	}
+
+	handler := &graphQLHandler{
+		logger: j.logger.Named("graphql"),
+		schema: newGraphQLSchema(eth),
+		config: j.config,
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/graphql", middlewareFactory(j.config)(handler))
+
+	j.graphQLServer = &http.Server{
+		Handler: mux,
+	}
+
+	go func() {
+		if err := j.graphQLServer.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			j.logger.Error("closed graphql connection", "err", err)
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,866 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The GraphQL documents are parsed into the operations and the fragments they define. The parser covers
+// the executable documents: the operations with their variables, the fields with their aliases and arguments,
+// the fragment spreads, the inline fragments and the directives. The type system definitions are not parsed
+
+// gqlDocument is a parsed GraphQL document
+type gqlDocument struct {
+	operations []*gqlOperation
+	fragments  map[string]*gqlFragment
+}
+
+// gqlOperation is an operation of the document, the anonymous queries have no name
+type gqlOperation struct {
+	kind       string
+	name       string
+	variables  []*gqlVariableDefinition
+	selections []*gqlSelection
+}
+
+// gqlVariableDefinition is a variable of the operation, with its default value if any
+type gqlVariableDefinition struct {
+	name         string
+	nonNull      bool
+	defaultValue interface{}
+	hasDefault   bool
+}
+
+// gqlFragment is a named fragment, spread in the selection sets
+type gqlFragment struct {
+	typeCondition string
+	selections    []*gqlSelection
+}
+
+// gqlSelection is a field, a fragment spread or an inline fragment of a selection set
+type gqlSelection struct {
+	// alias and name are the ones of a field, the alias is the name if not set
+	alias string
+	name  string
+	args  []*gqlArgument
+
+	// spread is the name of the spread fragment
+	spread string
+
+	// inline is set for the inline fragments, with their type condition if any
+	inline        bool
+	typeCondition string
+
+	directives []*gqlDirective
+	selections []*gqlSelection
+}
+
+type gqlArgument struct {
+	name  string
+	value interface{}
+}
+
+type gqlDirective struct {
+	name string
+	args []*gqlArgument
+}
+
+// gqlVariable and gqlEnum are the variable and the enum values of the arguments. The other values are
+// the ones of the json decoded variables: the numbers are json.Number, the lists []interface{}
+// and the input objects map[string]interface{}
+type (
+	gqlVariable string
+	gqlEnum     string
+)
+
+type gqlTokenKind int
+
+const (
+	gqlEOF gqlTokenKind = iota
+	gqlPunctuator
+	gqlName
+	gqlInt
+	gqlFloat
+	gqlString
+)
+
+type gqlToken struct {
+	kind  gqlTokenKind
+	value string
+	pos   int
+}
+
+func (t gqlToken) String() string {
+	switch t.kind {
+	case gqlEOF:
+		return "<EOF>"
+	case gqlString:
+		return strconv.Quote(t.value)
+	default:
+		return fmt.Sprintf("%q", t.value)
+	}
+}
+
+// gqlLexer splits the source of the document into the tokens,
+// the white spaces, the commas and the comments are ignored
+type gqlLexer struct {
+	src string
+	pos int
+}
+
+func (l *gqlLexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.src[l.pos:], "\uFEFF"):
+			l.pos += len("\uFEFF")
+		default:
+			return
+		}
+	}
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (l *gqlLexer) next() (gqlToken, error) {
+	l.skipIgnored()
+
+	start := l.pos
+	if l.pos >= len(l.src) {
+		return gqlToken{kind: gqlEOF, pos: start}, nil
+	}
+
+	c := l.src[l.pos]
+
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.pos += 3
+
+		return gqlToken{kind: gqlPunctuator, value: "...", pos: start}, nil
+
+	case strings.IndexByte("!$&()=:@[]{}|", c) >= 0:
+		l.pos++
+
+		return gqlToken{kind: gqlPunctuator, value: string(c), pos: start}, nil
+
+	case isNameStart(c):
+		for l.pos < len(l.src) && (isNameStart(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+
+		return gqlToken{kind: gqlName, value: l.src[start:l.pos], pos: start}, nil
+
+	case c == '-' || isDigit(c):
+		return l.number()
+
+	case c == '"':
+		return l.string()
+	}
+
+	return gqlToken{}, fmt.Errorf("unexpected character %q at %d", c, start)
+}
+
+func (l *gqlLexer) digits() int {
+	start := l.pos
+	for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+		l.pos++
+	}
+
+	return l.pos - start
+}
+
+func (l *gqlLexer) number() (gqlToken, error) {
+	start := l.pos
+	kind := gqlInt
+
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+
+	// the integer part has no leading zero
+	intStart := l.pos
+	if n := l.digits(); n == 0 || (n > 1 && l.src[intStart] == '0') {
+		return gqlToken{}, fmt.Errorf("invalid number at %d", start)
+	}
+
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		l.pos++
+		kind = gqlFloat
+
+		if l.digits() == 0 {
+			return gqlToken{}, fmt.Errorf("invalid number at %d", start)
+		}
+	}
+
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		l.pos++
+		kind = gqlFloat
+
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+
+		if l.digits() == 0 {
+			return gqlToken{}, fmt.Errorf("invalid number at %d", start)
+		}
+	}
+
+	// a name can't directly follow a number
+	if l.pos < len(l.src) && (isNameStart(l.src[l.pos]) || l.src[l.pos] == '.') {
+		return gqlToken{}, fmt.Errorf("invalid number at %d", start)
+	}
+
+	return gqlToken{kind: kind, value: l.src[start:l.pos], pos: start}, nil
+}
+
+// string reads a string or a block string, the indentation of the block strings is kept
+func (l *gqlLexer) string() (gqlToken, error) {
+	start := l.pos
+
+	if strings.HasPrefix(l.src[l.pos:], `"""`) {
+		l.pos += 3
+
+		var value strings.Builder
+
+		for l.pos < len(l.src) {
+			switch {
+			case strings.HasPrefix(l.src[l.pos:], `\"""`):
+				value.WriteString(`"""`)
+				l.pos += 4
+			case strings.HasPrefix(l.src[l.pos:], `"""`):
+				l.pos += 3
+
+				return gqlToken{kind: gqlString, value: value.String(), pos: start}, nil
+			default:
+				value.WriteByte(l.src[l.pos])
+				l.pos++
+			}
+		}
+
+		return gqlToken{}, fmt.Errorf("unterminated string at %d", start)
+	}
+
+	l.pos++
+
+	var value strings.Builder
+
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+
+		switch c {
+		case '"':
+			l.pos++
+
+			return gqlToken{kind: gqlString, value: value.String(), pos: start}, nil
+		case '\n', '\r':
+			return gqlToken{}, fmt.Errorf("unterminated string at %d", start)
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				return gqlToken{}, fmt.Errorf("unterminated string at %d", start)
+			}
+
+			escaped := l.src[l.pos+1]
+			l.pos += 2
+
+			switch escaped {
+			case '"', '\\', '/':
+				value.WriteByte(escaped)
+			case 'b':
+				value.WriteByte('\b')
+			case 'f':
+				value.WriteByte('\f')
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.src) {
+					return gqlToken{}, fmt.Errorf("invalid unicode escape at %d", l.pos-2)
+				}
+
+				code, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return gqlToken{}, fmt.Errorf("invalid unicode escape at %d", l.pos-2)
+				}
+
+				value.WriteRune(rune(code))
+				l.pos += 4
+			default:
+				return gqlToken{}, fmt.Errorf("invalid escape %q at %d", escaped, l.pos-2)
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			value.WriteRune(r)
+			l.pos += size
+		}
+	}
+
+	return gqlToken{}, fmt.Errorf("unterminated string at %d", start)
+}
+
+// maxGraphQLDepth is the deepest nesting of the selection sets, the values and the types of a document
+const maxGraphQLDepth = 64
+
+// gqlParser is a recursive descent parser of the GraphQL documents, reading one token ahead
+type gqlParser struct {
+	lexer *gqlLexer
+	tok   gqlToken
+
+	// depth is the nesting of the selection set, the value or the type being parsed
+	depth int
+}
+
+// parseGraphQL parses the GraphQL document of the source
+func parseGraphQL(src string) (*gqlDocument, error) {
+	p := &gqlParser{lexer: &gqlLexer{src: src}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &gqlDocument{
+		fragments: map[string]*gqlFragment{},
+	}
+
+	for p.tok.kind != gqlEOF {
+		if p.peekName("fragment") {
+			name, fragment, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := doc.fragments[name]; ok {
+				return nil, fmt.Errorf("the fragment %q is defined more than once", name)
+			}
+
+			doc.fragments[name] = fragment
+
+			continue
+		}
+
+		operation, err := p.parseOperation()
+		if err != nil {
+			return nil, err
+		}
+
+		doc.operations = append(doc.operations, operation)
+	}
+
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("the document has no operation")
+	}
+
+	return doc, nil
+}
+
+func (p *gqlParser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+
+	p.tok = tok
+
+	return nil
+}
+
+// nest enters a nested selection set, value or type, the documents nested deeper than maxGraphQLDepth
+// are rejected so that the recursion of the parser is bounded. unnest leaves it
+func (p *gqlParser) nest() error {
+	if p.depth++; p.depth > maxGraphQLDepth {
+		return fmt.Errorf("the document is nested deeper than %d at %d", maxGraphQLDepth, p.tok.pos)
+	}
+
+	return nil
+}
+
+func (p *gqlParser) unnest() {
+	p.depth--
+}
+
+func (p *gqlParser) unexpected() error {
+	return fmt.Errorf("unexpected %s at %d", p.tok, p.tok.pos)
+}
+
+func (p *gqlParser) peek(punctuator string) bool {
+	return p.tok.kind == gqlPunctuator && p.tok.value == punctuator
+}
+
+func (p *gqlParser) peekName(name string) bool {
+	return p.tok.kind == gqlName && p.tok.value == name
+}
+
+// skip consumes the punctuator if it is the next token
+func (p *gqlParser) skip(punctuator string) (bool, error) {
+	if !p.peek(punctuator) {
+		return false, nil
+	}
+
+	return true, p.advance()
+}
+
+func (p *gqlParser) expect(punctuator string) error {
+	if !p.peek(punctuator) {
+		return p.unexpected()
+	}
+
+	return p.advance()
+}
+
+func (p *gqlParser) expectName() (string, error) {
+	if p.tok.kind != gqlName {
+		return "", p.unexpected()
+	}
+
+	name := p.tok.value
+
+	return name, p.advance()
+}
+
+func (p *gqlParser) parseOperation() (*gqlOperation, error) {
+	operation := &gqlOperation{kind: "query"}
+
+	// the query shorthand is a single selection set
+	if !p.peek("{") {
+		kind, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		if kind != "query" && kind != "mutation" && kind != "subscription" {
+			return nil, fmt.Errorf("unexpected %q, expected an operation", kind)
+		}
+
+		operation.kind = kind
+
+		if p.tok.kind == gqlName {
+			if operation.name, err = p.expectName(); err != nil {
+				return nil, err
+			}
+		}
+
+		if operation.variables, err = p.parseVariableDefinitions(); err != nil {
+			return nil, err
+		}
+
+		// the directives of the operations are not supported, they are parsed and ignored
+		if _, err := p.parseDirectives(); err != nil {
+			return nil, err
+		}
+	}
+
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	operation.selections = selections
+
+	return operation, nil
+}
+
+func (p *gqlParser) parseVariableDefinitions() ([]*gqlVariableDefinition, error) {
+	if ok, err := p.skip("("); !ok || err != nil {
+		return nil, err
+	}
+
+	variables := []*gqlVariableDefinition{}
+
+	for !p.peek(")") {
+		if err := p.expect("$"); err != nil {
+			return nil, err
+		}
+
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+
+		nonNull, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+
+		variable := &gqlVariableDefinition{name: name, nonNull: nonNull}
+
+		if ok, err := p.skip("="); err != nil {
+			return nil, err
+		} else if ok {
+			if variable.defaultValue, err = p.parseValue(true); err != nil {
+				return nil, err
+			}
+
+			variable.hasDefault = true
+		}
+
+		if _, err := p.parseDirectives(); err != nil {
+			return nil, err
+		}
+
+		variables = append(variables, variable)
+	}
+
+	return variables, p.advance()
+}
+
+// parseType parses the type of a variable, the arguments are coerced by the fields
+// so only the nullability of the variable is kept
+func (p *gqlParser) parseType() (bool, error) {
+	if err := p.nest(); err != nil {
+		return false, err
+	}
+
+	defer p.unnest()
+
+	if ok, err := p.skip("["); err != nil {
+		return false, err
+	} else if ok {
+		if _, err := p.parseType(); err != nil {
+			return false, err
+		}
+
+		if err := p.expect("]"); err != nil {
+			return false, err
+		}
+	} else if _, err := p.expectName(); err != nil {
+		return false, err
+	}
+
+	return p.skip("!")
+}
+
+func (p *gqlParser) parseFragment() (string, *gqlFragment, error) {
+	// the fragment keyword
+	if err := p.advance(); err != nil {
+		return "", nil, err
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if name == "on" {
+		return "", nil, fmt.Errorf("invalid fragment name %q", name)
+	}
+
+	if !p.peekName("on") {
+		return "", nil, p.unexpected()
+	}
+
+	if err := p.advance(); err != nil {
+		return "", nil, err
+	}
+
+	fragment := &gqlFragment{}
+	if fragment.typeCondition, err = p.expectName(); err != nil {
+		return "", nil, err
+	}
+
+	if _, err := p.parseDirectives(); err != nil {
+		return "", nil, err
+	}
+
+	if fragment.selections, err = p.parseSelectionSet(); err != nil {
+		return "", nil, err
+	}
+
+	return name, fragment, nil
+}
+
+func (p *gqlParser) parseSelectionSet() ([]*gqlSelection, error) {
+	if err := p.nest(); err != nil {
+		return nil, err
+	}
+
+	defer p.unnest()
+
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	selections := []*gqlSelection{}
+
+	for !p.peek("}") {
+		selection, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+
+		selections = append(selections, selection)
+	}
+
+	if len(selections) == 0 {
+		return nil, fmt.Errorf("empty selection set at %d", p.tok.pos)
+	}
+
+	return selections, p.advance()
+}
+
+func (p *gqlParser) parseSelection() (*gqlSelection, error) {
+	var err error
+
+	selection := &gqlSelection{}
+
+	if ok, err := p.skip("..."); err != nil {
+		return nil, err
+	} else if ok {
+		switch {
+		case p.peekName("on"):
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+
+			if selection.typeCondition, err = p.expectName(); err != nil {
+				return nil, err
+			}
+
+			selection.inline = true
+		case p.tok.kind == gqlName:
+			selection.spread = p.tok.value
+
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		default:
+			selection.inline = true
+		}
+
+		if selection.directives, err = p.parseDirectives(); err != nil {
+			return nil, err
+		}
+
+		if selection.inline {
+			if selection.selections, err = p.parseSelectionSet(); err != nil {
+				return nil, err
+			}
+		}
+
+		return selection, nil
+	}
+
+	if selection.name, err = p.expectName(); err != nil {
+		return nil, err
+	}
+
+	selection.alias = selection.name
+
+	if ok, err := p.skip(":"); err != nil {
+		return nil, err
+	} else if ok {
+		if selection.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+
+	if selection.args, err = p.parseArguments(); err != nil {
+		return nil, err
+	}
+
+	if selection.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	if p.peek("{") {
+		if selection.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+
+	return selection, nil
+}
+
+func (p *gqlParser) parseArguments() ([]*gqlArgument, error) {
+	if ok, err := p.skip("("); !ok || err != nil {
+		return nil, err
+	}
+
+	args := []*gqlArgument{}
+
+	for !p.peek(")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+
+		value, err := p.parseValue(false)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, &gqlArgument{name: name, value: value})
+	}
+
+	if len(args) == 0 {
+		return nil, p.unexpected()
+	}
+
+	return args, p.advance()
+}
+
+func (p *gqlParser) parseDirectives() ([]*gqlDirective, error) {
+	directives := []*gqlDirective{}
+
+	for p.peek("@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		args, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+
+		directives = append(directives, &gqlDirective{name: name, args: args})
+	}
+
+	return directives, nil
+}
+
+// parseValue parses the value of an argument, the default values of the variables are constant
+func (p *gqlParser) parseValue(constant bool) (interface{}, error) {
+	if err := p.nest(); err != nil {
+		return nil, err
+	}
+
+	defer p.unnest()
+
+	tok := p.tok
+
+	switch {
+	case p.peek("$") && !constant:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		return gqlVariable(name), nil
+
+	case p.peek("["):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		values := []interface{}{}
+
+		for !p.peek("]") {
+			value, err := p.parseValue(constant)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, value)
+		}
+
+		return values, p.advance()
+
+	case p.peek("{"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		fields := map[string]interface{}{}
+
+		for !p.peek("}") {
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+
+			if fields[name], err = p.parseValue(constant); err != nil {
+				return nil, err
+			}
+		}
+
+		return fields, p.advance()
+
+	case tok.kind == gqlInt || tok.kind == gqlFloat:
+		return json.Number(tok.value), p.advance()
+
+	case tok.kind == gqlString:
+		return tok.value, p.advance()
+
+	case tok.kind == gqlName:
+		var value interface{}
+
+		switch tok.value {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		default:
+			value = gqlEnum(tok.value)
+		}
+
+		return value, p.advance()
+	}
+
+	return nil, p.unexpected()
+}
+
+// resolveValue replaces the variables of the argument value with their values
+func resolveValue(value interface{}, variables map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case gqlVariable:
+		return variables[string(v)]
+
+	case gqlEnum:
+		return string(v)
+
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for indx, item := range v {
+			values[indx] = resolveValue(item, variables)
+		}
+
+		return values
+
+	case map[string]interface{}:
+		fields := make(map[string]interface{}, len(v))
+		for name, item := range v {
+			fields[name] = resolveValue(item, variables)
+		}
+
+		return fields
+	}
+
+	return value
+}
@@ -0,0 +1,68 @@
+//go:build go1.18
+// +build go1.18
+
+package jsonrpc
+
+import (
+	"testing"
+)
+
+// selectionDepth returns the deepest nesting of the selection sets
+func selectionDepth(selections []*gqlSelection) int {
+	depth := 0
+
+	for _, selection := range selections {
+		if nested := selectionDepth(selection.selections); nested > depth {
+			depth = nested
+		}
+	}
+
+	if len(selections) == 0 {
+		return 0
+	}
+
+	return depth + 1
+}
+
+// FuzzParseGraphQL parses the random documents, the parser has to return an error instead of panicking,
+// and the parsed documents have to keep within the nesting limit
+func FuzzParseGraphQL(f *testing.F) {
+	f.Add(`{ block { number hash } }`)
+	f.Add(`query Block($number: Long = "0x1", $hashes: [Bytes32!]!) { head: block(number: $number) { ...F } }`)
+	f.Add(`fragment F on Block { number extraData(format: HEX, text: """ a "block" string """) }`)
+	f.Add(`{ logs(filter: {fromBlock: 1, topics: [["0x01", "0x02"]], addresses: null}) { index } }`)
+	f.Add(`{ block @include(if: $a) { ... on Block @skip(if: false) { hash } } }`)
+	f.Add(`mutation { sendRawTransaction(data: "0x01é\n") }`)
+	f.Add(`{ block(number: -1.5e10) { number } }`)
+
+	f.Fuzz(func(t *testing.T, query string) {
+		if len(query) > maxGraphQLBodySize {
+			return
+		}
+
+		doc, err := parseGraphQL(query)
+		if err != nil {
+			return
+		}
+
+		if len(doc.operations) == 0 {
+			t.Fatalf("the document %q is parsed without an operation", query)
+		}
+
+		for _, operation := range doc.operations {
+			if depth := selectionDepth(operation.selections); depth > maxGraphQLDepth {
+				t.Fatalf("the operation of %q is nested %d deep", query, depth)
+			}
+
+			for _, variable := range operation.variables {
+				resolveValue(variable.defaultValue, map[string]interface{}{})
+			}
+		}
+
+		for _, fragment := range doc.fragments {
+			if depth := selectionDepth(fragment.selections); depth > maxGraphQLDepth {
+				t.Fatalf("the fragment of %q is nested %d deep", query, depth)
+			}
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGraphQL(t *testing.T) {
+	doc, err := parseGraphQL(`
+		# the block and its transactions
+		query Block($number: Long = "0x1", $hashes: [Bytes32!]!) {
+			head: block(number: $number) {
+				...BlockFields
+				... on Block @include(if: true) { hash }
+			}
+			logs(filter: {fromBlock: 1, topics: [["0x01", "0x02"]], addresses: null})
+		}
+
+		fragment BlockFields on Block {
+			number
+			extraData(format: HEX, text: """ a "block" string """)
+		}
+	`)
+	assert.NoError(t, err)
+
+	operation, err := doc.operation("")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "query", operation.kind)
+	assert.Equal(t, "Block", operation.name)
+
+	if assert.Len(t, operation.variables, 2) {
+		assert.Equal(t, "number", operation.variables[0].name)
+		assert.True(t, operation.variables[0].hasDefault)
+		assert.Equal(t, "0x1", operation.variables[0].defaultValue)
+		assert.False(t, operation.variables[0].nonNull)
+		assert.True(t, operation.variables[1].nonNull)
+	}
+
+	if assert.Len(t, operation.selections, 2) {
+		head := operation.selections[0]
+		assert.Equal(t, "head", head.alias)
+		assert.Equal(t, "block", head.name)
+		assert.Equal(t, gqlVariable("number"), head.args[0].value)
+
+		if assert.Len(t, head.selections, 2) {
+			assert.Equal(t, "BlockFields", head.selections[0].spread)
+			assert.True(t, head.selections[1].inline)
+			assert.Equal(t, "Block", head.selections[1].typeCondition)
+			assert.Equal(t, "include", head.selections[1].directives[0].name)
+		}
+
+		logs := operation.selections[1]
+		assert.Equal(t, map[string]interface{}{
+			"fromBlock": json.Number("1"),
+			"topics":    []interface{}{[]interface{}{"0x01", "0x02"}},
+			"addresses": nil,
+		}, logs.args[0].value)
+	}
+
+	fragment, ok := doc.fragments["BlockFields"]
+	if assert.True(t, ok) {
+		assert.Equal(t, "Block", fragment.typeCondition)
+
+		if assert.Len(t, fragment.selections, 2) {
+			args := fragment.selections[1].args
+			assert.Equal(t, gqlEnum("HEX"), args[0].value)
+			assert.Equal(t, ` a "block" string `, args[1].value)
+		}
+	}
+}
+
+func TestParseGraphQL_Shorthand(t *testing.T) {
+	doc, err := parseGraphQL(`{ block { number } }`)
+	assert.NoError(t, err)
+
+	operation, err := doc.operation("")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "query", operation.kind)
+		assert.Equal(t, "block", operation.selections[0].name)
+	}
+}
+
+func TestParseGraphQL_Errors(t *testing.T) {
+	cases := []struct {
+		name  string
+		query string
+	}{
+		{"unclosed selection set", `{ block { number }`},
+		{"empty selection set", `{ block { } }`},
+		{"unterminated string", `{ block(hash: "0x01) { number } }`},
+		{"variable in a default value", `query ($a: Long = $b) { block { number } }`},
+		{"invalid number", `{ block(number: 01) { number } }`},
+		{"duplicate fragment", `{ block { ...F } } fragment F on Block { number } fragment F on Block { hash }`},
+		{"unknown definition", `schema { query: Query }`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := parseGraphQL(c.query)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseGraphQL_Depth(t *testing.T) {
+	queries := map[string]func(depth int) string{
+		"selection sets": func(depth int) string {
+			return strings.Repeat("{ block ", depth-1) + "{ number }" + strings.Repeat(" }", depth-1)
+		},
+		"values": func(depth int) string {
+			return `{ logs(filter: ` + strings.Repeat("[", depth-1) + strings.Repeat("]", depth-1) + `) { index } }`
+		},
+		"types": func(depth int) string {
+			return `query ($a: ` + strings.Repeat("[", depth-1) + "Long" + strings.Repeat("]", depth-1) + `) { block { number } }`
+		},
+	}
+
+	for name, query := range queries {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseGraphQL(query(maxGraphQLDepth))
+			assert.NoError(t, err)
+
+			_, err = parseGraphQL(query(maxGraphQLDepth + 1))
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), "nested deeper than 64")
+			}
+
+			// the deep nesting doesn't overflow the stack
+			_, err = parseGraphQL(query(1000000))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseGraphQL_Operation(t *testing.T) {
+	doc, err := parseGraphQL(`query A { block { number } } query B { block { hash } }`)
+	assert.NoError(t, err)
+
+	// the operation has to be selected by name if the document has several
+	_, err = doc.operation("")
+	assert.Error(t, err)
+
+	_, err = doc.operation("C")
+	assert.Error(t, err)
+
+	operation, err := doc.operation("B")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "hash", operation.selections[0].selections[0].name)
+	}
+}
+
+func TestResolveValue(t *testing.T) {
+	variables := map[string]interface{}{"from": "0x1"}
+
+	value := resolveValue(map[string]interface{}{
+		"fromBlock": gqlVariable("from"),
+		"toBlock":   gqlVariable("to"),
+		"topics":    []interface{}{gqlVariable("from")},
+	}, variables)
+
+	assert.Equal(t, map[string]interface{}{
+		"fromBlock": "0x1",
+		"toBlock":   nil,
+		"topics":    []interface{}{"0x1"},
+	}, value)
+}
@@ -0,0 +1,717 @@
+package jsonrpc
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// The GraphQL schema is the subset of the EIP-1767 one for the blocks, the transactions, the accounts
+// and the logs. The fields are resolved by the eth endpoint, so the queries share the lookups and the limits
+// of the JSON-RPC methods, and the values are the ones of their results: the Long and the BigInt values
+// are hex encoded. The schema, in the GraphQL schema language:
+//
+//	type Query {
+//	  block(number: Long, hash: Bytes32): Block
+//	  blocks(from: Long!, to: Long): [Block!]!
+//	  transaction(hash: Bytes32!): Transaction
+//	  logs(filter: FilterCriteria!): [Log!]!
+//	  pending: Pending!
+//	  chainID: BigInt!
+//	  gasPrice: BigInt!
+//	}
+//
+//	type Block {
+//	  number: Long!
+//	  hash: Bytes32!
+//	  parent: Block
+//	  nonce: Bytes!
+//	  transactionsRoot: Bytes32!
+//	  transactionCount: Long
+//	  stateRoot: Bytes32!
+//	  receiptsRoot: Bytes32!
+//	  miner(block: Long): Account!
+//	  extraData: Bytes!
+//	  gasLimit: Long!
+//	  gasUsed: Long!
+//	  baseFeePerGas: BigInt
+//	  timestamp: Long!
+//	  logsBloom: Bytes!
+//	  mixHash: Bytes32!
+//	  difficulty: BigInt!
+//	  totalDifficulty: BigInt!
+//	  ommerHash: Bytes32!
+//	  transactions: [Transaction!]
+//	  transactionAt(index: Int!): Transaction
+//	  logs(filter: BlockFilterCriteria!): [Log!]!
+//	  account(address: Address!): Account!
+//	}
+//
+//	type Transaction {
+//	  hash: Bytes32!
+//	  nonce: Long!
+//	  index: Int
+//	  from(block: Long): Account!
+//	  to(block: Long): Account
+//	  value: BigInt!
+//	  gasPrice: BigInt!
+//	  maxFeePerGas: BigInt
+//	  maxPriorityFeePerGas: BigInt
+//	  effectiveGasPrice: BigInt
+//	  gas: Long!
+//	  inputData: Bytes!
+//	  block: Block
+//	  status: Long
+//	  gasUsed: Long
+//	  cumulativeGasUsed: Long
+//	  createdContract(block: Long): Account
+//	  logs: [Log!]
+//	  r: BigInt!
+//	  s: BigInt!
+//	  v: BigInt!
+//	  type: Int
+//	}
+//
+//	type Account {
+//	  address: Address!
+//	  balance: BigInt!
+//	  transactionCount: Long!
+//	  code: Bytes!
+//	  storage(slot: Bytes32!): Bytes32!
+//	}
+//
+//	type Log {
+//	  index: Int!
+//	  account(block: Long): Account!
+//	  topics: [Bytes32!]!
+//	  data: Bytes!
+//	  transaction: Transaction!
+//	}
+//
+//	type Pending {
+//	  transactionCount: Long!
+//	  transactions: [Transaction!]
+//	  account(address: Address!): Account!
+//	}
+//
+//	input BlockFilterCriteria {
+//	  addresses: [Address!]
+//	  topics: [[Bytes32!]!]
+//	}
+//
+//	input FilterCriteria {
+//	  fromBlock: Long
+//	  toBlock: Long
+//	  addresses: [Address!]
+//	  topics: [[Bytes32!]!]
+//	}
+//
+// The accounts of the transactions and the logs are read at their block argument, at the head if not set
+
+// gqlAccount is an account at the block, the state of the block is read by the fields
+type gqlAccount struct {
+	address types.Address
+	number  BlockNumber
+}
+
+// gqlPending is the pending block, made of the executable transactions of the pool
+type gqlPending struct{}
+
+// graphQLSchema resolves the fields of the schema with the eth endpoint
+type graphQLSchema struct {
+	eth *Eth
+}
+
+// newGraphQLSchema returns the EIP-1767 schema resolved by the eth endpoint
+func newGraphQLSchema(eth *Eth) gqlSchema {
+	s := &graphQLSchema{eth: eth}
+
+	objects := []*gqlObject{
+		{name: "Query", fields: s.queryFields()},
+		{name: "Block", fields: s.blockFields()},
+		{name: "Transaction", fields: s.transactionFields()},
+		{name: "Account", fields: s.accountFields()},
+		{name: "Log", fields: s.logFields()},
+		{name: "Pending", fields: s.pendingFields()},
+	}
+
+	schema := gqlSchema{}
+	for _, object := range objects {
+		schema[object.name] = object
+	}
+
+	return schema
+}
+
+// scalar returns the field of the scalar read from the object
+func scalar(resolve func(parent interface{}) interface{}) *gqlField {
+	return &gqlField{
+		resolve: func(parent interface{}, _ map[string]interface{}) (interface{}, error) {
+			return resolve(parent), nil
+		},
+	}
+}
+
+// blockArg is the optional block argument of the accounts
+var blockArg = map[string]*gqlArg{
+	"block": {coerce: coerceLong},
+}
+
+// accountAt returns the account at the block of the block argument, or at the given block if not set
+func accountAt(address types.Address, args map[string]interface{}, number BlockNumber) *gqlAccount {
+	if block, ok := args["block"].(uint64); ok {
+		number = BlockNumber(block)
+	}
+
+	return &gqlAccount{address: address, number: number}
+}
+
+// coerceFilterCriteria reads the log filter of the block, or of the range of blocks if the range is allowed
+func coerceFilterCriteria(blockRange bool) func(interface{}) (interface{}, error) {
+	return func(value interface{}) (interface{}, error) {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an input object")
+		}
+
+		query := &LogQuery{
+			fromBlock: LatestBlockNumber,
+			toBlock:   LatestBlockNumber,
+		}
+
+		for name, raw := range fields {
+			if raw == nil {
+				continue
+			}
+
+			switch {
+			case blockRange && (name == "fromBlock" || name == "toBlock"):
+				number, err := coerceLong(raw)
+				if err != nil {
+					return nil, err
+				}
+
+				if name == "fromBlock" {
+					query.fromBlock = BlockNumber(number.(uint64))
+				} else {
+					query.toBlock = BlockNumber(number.(uint64))
+				}
+
+			case name == "addresses":
+				addresses, err := coerceList(coerceAddress)(raw)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, addr := range addresses.([]interface{}) {
+					query.Addresses = append(query.Addresses, addr.(types.Address))
+				}
+
+			case name == "topics":
+				topics, err := coerceList(coerceList(coerceBytes32))(raw)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, set := range topics.([]interface{}) {
+					hashes := []types.Hash{}
+					for _, topic := range set.([]interface{}) {
+						hashes = append(hashes, topic.(types.Hash))
+					}
+
+					query.Topics = append(query.Topics, hashes)
+				}
+
+			default:
+				return nil, fmt.Errorf("unknown field %q", name)
+			}
+		}
+
+		return query, nil
+	}
+}
+
+// toList returns the items as a list of the schema
+func toList(n int, item func(i int) interface{}) []interface{} {
+	list := make([]interface{}, n)
+	for i := range list {
+		list[i] = item(i)
+	}
+
+	return list
+}
+
+// logs returns the logs of the query
+func (s *graphQLSchema) logs(query *LogQuery) (interface{}, error) {
+	res, err := s.eth.GetLogs(query)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, _ := res.([]*Log)
+
+	return toList(len(logs), func(i int) interface{} { return logs[i] }), nil
+}
+
+// block returns the block of the result of the eth endpoint, nil if not found
+func (s *graphQLSchema) block(res interface{}, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	if block, ok := res.(*block); ok {
+		return block, nil
+	}
+
+	return nil, nil
+}
+
+// transaction returns the transaction of the hash, nil if not found
+func (s *graphQLSchema) transaction(hash types.Hash) (interface{}, error) {
+	res, err := s.eth.GetTransactionByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if txn, ok := res.(*transaction); ok {
+		return txn, nil
+	}
+
+	return nil, nil
+}
+
+// receipt returns the receipt of the transaction, nil if it is pending
+func (s *graphQLSchema) receipt(txn *transaction) (*receipt, error) {
+	if txn.BlockHash == nil {
+		return nil, nil
+	}
+
+	res, err := s.eth.GetTransactionReceipt(txn.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, _ := res.(*receipt)
+
+	return receipt, nil
+}
+
+func (s *graphQLSchema) queryFields() map[string]*gqlField {
+	return map[string]*gqlField{
+		"block": {
+			typ: "Block",
+			args: map[string]*gqlArg{
+				"number": {coerce: coerceLong},
+				"hash":   {coerce: coerceBytes32},
+			},
+			resolve: func(_ interface{}, args map[string]interface{}) (interface{}, error) {
+				if hash, ok := args["hash"].(types.Hash); ok {
+					return s.block(s.eth.GetBlockByHash(hash, true))
+				}
+
+				number := LatestBlockNumber
+				if n, ok := args["number"].(uint64); ok {
+					number = BlockNumber(n)
+				}
+
+				return s.block(s.eth.GetBlockByNumber(number, true))
+			},
+		},
+		"blocks": {
+			typ: "Block",
+			args: map[string]*gqlArg{
+				"from": {coerce: coerceLong, required: true},
+				"to":   {coerce: coerceLong},
+			},
+			resolve: func(_ interface{}, args map[string]interface{}) (interface{}, error) {
+				from := args["from"].(uint64)
+
+				to := s.eth.store.Header().Number
+				if n, ok := args["to"].(uint64); ok {
+					to = n
+				}
+
+				if to < from {
+					return []interface{}{}, nil
+				}
+
+				// the range of blocks is limited as the one of the logs queries
+				if limit := s.eth.blockRangeLimit; limit != 0 && to-from >= limit {
+					return nil, fmt.Errorf(
+						"%w: the query covers %d blocks, the limit is %d",
+						ErrBlockRangeTooHigh,
+						to-from+1,
+						limit,
+					)
+				}
+
+				blocks := []interface{}{}
+
+				for number := from; number <= to; number++ {
+					block, err := s.block(s.eth.GetBlockByNumber(BlockNumber(number), true))
+					if err != nil {
+						return nil, err
+					}
+
+					if block == nil {
+						break
+					}
+
+					blocks = append(blocks, block)
+				}
+
+				return blocks, nil
+			},
+		},
+		"transaction": {
+			typ: "Transaction",
+			args: map[string]*gqlArg{
+				"hash": {coerce: coerceBytes32, required: true},
+			},
+			resolve: func(_ interface{}, args map[string]interface{}) (interface{}, error) {
+				return s.transaction(args["hash"].(types.Hash))
+			},
+		},
+		"logs": {
+			typ: "Log",
+			args: map[string]*gqlArg{
+				"filter": {coerce: coerceFilterCriteria(true), required: true},
+			},
+			resolve: func(_ interface{}, args map[string]interface{}) (interface{}, error) {
+				return s.logs(args["filter"].(*LogQuery))
+			},
+		},
+		"pending": {
+			typ: "Pending",
+			resolve: func(interface{}, map[string]interface{}) (interface{}, error) {
+				return &gqlPending{}, nil
+			},
+		},
+		"chainID": {
+			resolve: func(interface{}, map[string]interface{}) (interface{}, error) {
+				return s.eth.ChainId()
+			},
+		},
+		"gasPrice": {
+			resolve: func(interface{}, map[string]interface{}) (interface{}, error) {
+				return s.eth.GasPrice()
+			},
+		},
+	}
+}
+
+func (s *graphQLSchema) blockFields() map[string]*gqlField {
+	field := func(read func(b *block) interface{}) *gqlField {
+		return scalar(func(parent interface{}) interface{} {
+			return read(parent.(*block))
+		})
+	}
+
+	return map[string]*gqlField{
+		"number":           field(func(b *block) interface{} { return b.Number }),
+		"hash":             field(func(b *block) interface{} { return b.Hash }),
+		"nonce":            field(func(b *block) interface{} { return b.Nonce }),
+		"transactionsRoot": field(func(b *block) interface{} { return b.TxRoot }),
+		"transactionCount": field(func(b *block) interface{} { return argUint64(len(b.Transactions)) }),
+		"stateRoot":        field(func(b *block) interface{} { return b.StateRoot }),
+		"receiptsRoot":     field(func(b *block) interface{} { return b.ReceiptsRoot }),
+		"extraData":        field(func(b *block) interface{} { return b.ExtraData }),
+		"gasLimit":         field(func(b *block) interface{} { return b.GasLimit }),
+		"gasUsed":          field(func(b *block) interface{} { return b.GasUsed }),
+		"baseFeePerGas":    field(func(b *block) interface{} { return b.BaseFee }),
+		"timestamp":        field(func(b *block) interface{} { return b.Timestamp }),
+		"logsBloom":        field(func(b *block) interface{} { return b.LogsBloom }),
+		"mixHash":          field(func(b *block) interface{} { return b.MixHash }),
+		"difficulty":       field(func(b *block) interface{} { return b.Difficulty }),
+		"totalDifficulty":  field(func(b *block) interface{} { return b.TotalDifficulty }),
+		"ommerHash":        field(func(b *block) interface{} { return b.Sha3Uncles }),
+		"parent": {
+			typ: "Block",
+			resolve: func(parent interface{}, _ map[string]interface{}) (interface{}, error) {
+				b := parent.(*block)
+				if b.Number == 0 {
+					return nil, nil
+				}
+
+				return s.block(s.eth.GetBlockByHash(b.ParentHash, true))
+			},
+		},
+		"miner": {
+			typ:  "Account",
+			args: blockArg,
+			resolve: func(parent interface{}, args map[string]interface{}) (interface{}, error) {
+				b := parent.(*block)
+
+				return accountAt(b.Miner, args, BlockNumber(b.Number)), nil
+			},
+		},
+		"transactions": {
+			typ: "Transaction",
+			resolve: func(parent interface{}, _ map[string]interface{}) (interface{}, error) {
+				txs := parent.(*block).Transactions
+
+				return toList(len(txs), func(i int) interface{} { return txs[i] }), nil
+			},
+		},
+		"transactionAt": {
+			typ: "Transaction",
+			args: map[string]*gqlArg{
+				"index": {coerce: coerceInt, required: true},
+			},
+			resolve: func(parent interface{}, args map[string]interface{}) (interface{}, error) {
+				txs, indx := parent.(*block).Transactions, args["index"].(int)
+				if indx < 0 || indx >= len(txs) {
+					return nil, nil
+				}
+
+				return txs[indx], nil
+			},
+		},
+		"logs": {
+			typ: "Log",
+			args: map[string]*gqlArg{
+				"filter": {coerce: coerceFilterCriteria(false), required: true},
+			},
+			resolve: func(parent interface{}, args map[string]interface{}) (interface{}, error) {
+				query := args["filter"].(*LogQuery)
+				query.BlockHash = &parent.(*block).Hash
+
+				return s.logs(query)
+			},
+		},
+		"account": {
+			typ: "Account",
+			args: map[string]*gqlArg{
+				"address": {coerce: coerceAddress, required: true},
+			},
+			resolve: func(parent interface{}, args map[string]interface{}) (interface{}, error) {
+				return &gqlAccount{
+					address: args["address"].(types.Address),
+					number:  BlockNumber(parent.(*block).Number),
+				}, nil
+			},
+		},
+	}
+}
+
+func (s *graphQLSchema) transactionFields() map[string]*gqlField {
+	field := func(read func(txn *transaction) interface{}) *gqlField {
+		return scalar(func(parent interface{}) interface{} {
+			return read(parent.(*transaction))
+		})
+	}
+
+	// receiptField reads the field of the receipt of the transaction, null for the pending ones
+	receiptField := func(read func(r *receipt) interface{}) *gqlField {
+		return &gqlField{
+			resolve: func(parent interface{}, _ map[string]interface{}) (interface{}, error) {
+				receipt, err := s.receipt(parent.(*transaction))
+				if err != nil || receipt == nil {
+					return nil, err
+				}
+
+				return read(receipt), nil
+			},
+		}
+	}
+
+	return map[string]*gqlField{
+		"hash":                 field(func(txn *transaction) interface{} { return txn.Hash }),
+		"nonce":                field(func(txn *transaction) interface{} { return txn.Nonce }),
+		"value":                field(func(txn *transaction) interface{} { return txn.Value }),
+		"gasPrice":             field(func(txn *transaction) interface{} { return txn.GasPrice }),
+		"maxFeePerGas":         field(func(txn *transaction) interface{} { return txn.MaxFeePerGas }),
+		"maxPriorityFeePerGas": field(func(txn *transaction) interface{} { return txn.MaxPriorityFeePerGas }),
+		"gas":                  field(func(txn *transaction) interface{} { return txn.Gas }),
+		"inputData":            field(func(txn *transaction) interface{} { return txn.Input }),
+		"r":                    field(func(txn *transaction) interface{} { return txn.R }),
+		"s":                    field(func(txn *transaction) interface{} { return txn.S }),
+		"v":                    field(func(txn *transaction) interface{} { return txn.V }),
+		"type":                 field(func(txn *transaction) interface{} { return uint64(txn.Type) }),
+		"index": field(func(txn *transaction) interface{} {
+			if txn.TxIndex == nil {
+				return nil
+			}
+
+			return uint64(*txn.TxIndex)
+		}),
+		"status":            receiptField(func(r *receipt) interface{} { return r.Status }),
+		"gasUsed":           receiptField(func(r *receipt) interface{} { return r.GasUsed }),
+		"cumulativeGasUsed": receiptField(func(r *receipt) interface{} { return r.CumulativeGasUsed }),
+		"effectiveGasPrice": receiptField(func(r *receipt) interface{} { return r.EffectiveGasPrice }),
+		"from": {
+			typ:  "Account",
+			args: blockArg,
+			resolve: func(parent interface{}, args map[string]interface{}) (interface{}, error) {
+				return accountAt(parent.(*transaction).From, args, LatestBlockNumber), nil
+			},
+		},
+		"to": {
+			typ:  "Account",
+			args: blockArg,
+			resolve: func(parent interface{}, args map[string]interface{}) (interface{}, error) {
+				to := parent.(*transaction).To
+				if to == nil {
+					return nil, nil
+				}
+
+				return accountAt(*to, args, LatestBlockNumber), nil
+			},
+		},
+		"block": {
+			typ: "Block",
+			resolve: func(parent interface{}, _ map[string]interface{}) (interface{}, error) {
+				blockHash := parent.(*transaction).BlockHash
+				if blockHash == nil {
+					return nil, nil
+				}
+
+				return s.block(s.eth.GetBlockByHash(*blockHash, true))
+			},
+		},
+		"createdContract": {
+			typ:  "Account",
+			args: blockArg,
+			resolve: func(parent interface{}, args map[string]interface{}) (interface{}, error) {
+				receipt, err := s.receipt(parent.(*transaction))
+				if err != nil || receipt == nil || receipt.ContractAddress == nil {
+					return nil, err
+				}
+
+				return accountAt(*receipt.ContractAddress, args, LatestBlockNumber), nil
+			},
+		},
+		"logs": {
+			typ: "Log",
+			resolve: func(parent interface{}, _ map[string]interface{}) (interface{}, error) {
+				receipt, err := s.receipt(parent.(*transaction))
+				if err != nil || receipt == nil {
+					return nil, err
+				}
+
+				return toList(len(receipt.Logs), func(i int) interface{} { return receipt.Logs[i] }), nil
+			},
+		},
+	}
+}
+
+func (s *graphQLSchema) accountFields() map[string]*gqlField {
+	// field reads the field of the account with the eth endpoint, at the block of the account
+	field := func(read func(a *gqlAccount, filter BlockNumberOrHash) (interface{}, error)) *gqlField {
+		return &gqlField{
+			resolve: func(parent interface{}, _ map[string]interface{}) (interface{}, error) {
+				account := parent.(*gqlAccount)
+
+				return read(account, BlockNumberOrHash{BlockNumber: &account.number})
+			},
+		}
+	}
+
+	return map[string]*gqlField{
+		"address": scalar(func(parent interface{}) interface{} {
+			return parent.(*gqlAccount).address
+		}),
+		"balance": field(func(a *gqlAccount, filter BlockNumberOrHash) (interface{}, error) {
+			return s.eth.GetBalance(a.address, filter)
+		}),
+		"transactionCount": field(func(a *gqlAccount, filter BlockNumberOrHash) (interface{}, error) {
+			return s.eth.GetTransactionCount(a.address, filter)
+		}),
+		"code": field(func(a *gqlAccount, filter BlockNumberOrHash) (interface{}, error) {
+			return s.eth.GetCode(a.address, filter)
+		}),
+		"storage": {
+			args: map[string]*gqlArg{
+				"slot": {coerce: coerceBytes32, required: true},
+			},
+			resolve: func(parent interface{}, args map[string]interface{}) (interface{}, error) {
+				account := parent.(*gqlAccount)
+
+				res, err := s.eth.GetStorageAt(
+					account.address,
+					args["slot"].(types.Hash),
+					BlockNumberOrHash{BlockNumber: &account.number},
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				// the value of the slot is a full word
+				value, _ := res.(*argBytes)
+				if value == nil {
+					return types.ZeroHash, nil
+				}
+
+				return types.BytesToHash(*value), nil
+			},
+		},
+	}
+}
+
+func (s *graphQLSchema) logFields() map[string]*gqlField {
+	return map[string]*gqlField{
+		"index": scalar(func(parent interface{}) interface{} {
+			return uint64(parent.(*Log).LogIndex)
+		}),
+		"topics": scalar(func(parent interface{}) interface{} {
+			return parent.(*Log).Topics
+		}),
+		"data": scalar(func(parent interface{}) interface{} {
+			return parent.(*Log).Data
+		}),
+		"account": {
+			typ:  "Account",
+			args: blockArg,
+			resolve: func(parent interface{}, args map[string]interface{}) (interface{}, error) {
+				log := parent.(*Log)
+
+				return accountAt(log.Address, args, BlockNumber(log.BlockNumber)), nil
+			},
+		},
+		"transaction": {
+			typ: "Transaction",
+			resolve: func(parent interface{}, _ map[string]interface{}) (interface{}, error) {
+				return s.transaction(parent.(*Log).TxHash)
+			},
+		},
+	}
+}
+
+func (s *graphQLSchema) pendingFields() map[string]*gqlField {
+	return map[string]*gqlField{
+		"transactionCount": {
+			resolve: func(interface{}, map[string]interface{}) (interface{}, error) {
+				res, err := s.eth.GetBlockTransactionCountByNumber(PendingBlockNumber)
+				if err != nil {
+					return nil, err
+				}
+
+				count, _ := res.(int)
+
+				return argUint64(count), nil
+			},
+		},
+		"transactions": {
+			typ: "Transaction",
+			resolve: func(interface{}, map[string]interface{}) (interface{}, error) {
+				res, err := s.block(s.eth.GetBlockByNumber(PendingBlockNumber, true))
+				if err != nil || res == nil {
+					return nil, err
+				}
+
+				txs := res.(*block).Transactions
+
+				return toList(len(txs), func(i int) interface{} { return txs[i] }), nil
+			},
+		},
+		"account": {
+			typ: "Account",
+			args: map[string]*gqlArg{
+				"address": {coerce: coerceAddress, required: true},
+			},
+			resolve: func(_ interface{}, args map[string]interface{}) (interface{}, error) {
+				return &gqlAccount{address: args["address"].(types.Address), number: PendingBlockNumber}, nil
+			},
+		},
+	}
+}
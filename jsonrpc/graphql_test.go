@@ -0,0 +1,349 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/fastrlp"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// mockGraphQLStore is the chain of blocks of the mock block store, with the state of a single account
+type mockGraphQLStore struct {
+	*mockBlockStore
+	account *mockAccount
+}
+
+func (m *mockGraphQLStore) GetAccount(root types.Hash, addr types.Address) (*state.Account, error) {
+	if m.account.address != addr {
+		return nil, ErrStateNotFound
+	}
+
+	return m.account.account, nil
+}
+
+func (m *mockGraphQLStore) GetStorage(root types.Hash, addr types.Address, slot types.Hash) ([]byte, error) {
+	value, ok := m.account.storage[slot]
+	if m.account.address != addr || !ok {
+		return nil, ErrStateNotFound
+	}
+
+	return value, nil
+}
+
+func (m *mockGraphQLStore) GetCode(hash types.Hash) ([]byte, error) {
+	return m.account.code, nil
+}
+
+func (m *mockGraphQLStore) GetNonce(addr types.Address) uint64 {
+	return m.account.account.Nonce
+}
+
+// newTestGraphQLStore returns the store of the genesis block and of the block of a transfer emitting a log
+func newTestGraphQLStore(t *testing.T) (*mockGraphQLStore, *types.Transaction) {
+	t.Helper()
+
+	txn := &types.Transaction{
+		Nonce:    0,
+		From:     addr0,
+		To:       &addr1,
+		Value:    big.NewInt(10),
+		GasPrice: big.NewInt(1),
+		Gas:      21000,
+		V:        big.NewInt(1),
+		R:        big.NewInt(2),
+		S:        big.NewInt(3),
+	}
+	txn.ComputeHash()
+
+	head := newTestBlock(1, hash1)
+	head.Header.ParentHash = hash3
+	head.Header.GasUsed = 21000
+	head.Transactions = []*types.Transaction{txn}
+
+	blocks := newMockBlockStore()
+	blocks.add(newTestBlock(0, hash3), head)
+	blocks.receipts[hash1] = []*types.Receipt{
+		{
+			GasUsed:           21000,
+			CumulativeGasUsed: 21000,
+			TxHash:            txn.Hash,
+			Logs: []*types.Log{
+				{Address: addr1, Topics: []types.Hash{hash2}, Data: []byte{0x1}},
+			},
+		},
+	}
+
+	blocks.receipts[hash1][0].SetStatus(types.ReceiptSuccess)
+
+	slot := (&fastrlp.Arena{}).NewBytes([]byte{0x5}).MarshalTo(nil)
+
+	store := &mockGraphQLStore{
+		mockBlockStore: blocks,
+		account: &mockAccount{
+			address: addr0,
+			code:    []byte{0x60, 0x00},
+			account: &state.Account{Balance: big.NewInt(100), Nonce: 1},
+			storage: map[types.Hash][]byte{hash1: slot},
+		},
+	}
+
+	return store, txn
+}
+
+// newTestGraphQLSchema returns the schema resolved by the eth endpoint of the test store
+func newTestGraphQLSchema(t *testing.T) (gqlSchema, *types.Transaction) {
+	t.Helper()
+
+	store, txn := newTestGraphQLStore(t)
+
+	return newGraphQLSchema(newTestEthEndpoint(store)), txn
+}
+
+// executeQuery returns the json response of the query
+func executeQuery(t *testing.T, schema gqlSchema, query string, variables map[string]interface{}) string {
+	t.Helper()
+
+	data, err := json.Marshal(schema.execute(query, "", variables))
+	assert.NoError(t, err)
+
+	return string(data)
+}
+
+func TestGraphQL_Block(t *testing.T) {
+	schema, txn := newTestGraphQLSchema(t)
+
+	// the latest block by default, the fields are returned in the order of the query
+	res := executeQuery(t, schema, `{
+		block {
+			number
+			hash
+			transactionCount
+			parent { number parent { number } }
+			transactions { hash index from { address balance transactionCount } to { address balance } }
+		}
+	}`, nil)
+
+	assert.Equal(t, fmt.Sprintf(`{"data":{"block":{"number":"0x1","hash":"%s","transactionCount":"0x1",`+
+		`"parent":{"number":"0x0","parent":null},"transactions":[{"hash":"%s","index":0,`+
+		`"from":{"address":"%s","balance":"0x64","transactionCount":"0x1"},`+
+		`"to":{"address":"%s","balance":"0x0"}}]}}}`,
+		hash1, txn.Hash, addr0, addr1,
+	), res)
+
+	// by hash and by number
+	res = executeQuery(t, schema, fmt.Sprintf(`{
+		byHash: block(hash: "%s") { number }
+		byNumber: block(number: 0) { hash }
+		missing: block(number: 5) { number }
+	}`, hash3), nil)
+	assert.JSONEq(t, fmt.Sprintf(
+		`{"data":{"byHash":{"number":"0x0"},"byNumber":{"hash":"%s"},"missing":null}}`,
+		hash3,
+	), res)
+
+	res = executeQuery(t, schema, `{ blocks(from: 0) { number } }`, nil)
+	assert.JSONEq(t, `{"data":{"blocks":[{"number":"0x0"},{"number":"0x1"}]}}`, res)
+}
+
+func TestGraphQL_Blocks_RangeLimit(t *testing.T) {
+	store, _ := newTestGraphQLStore(t)
+
+	eth := newTestEthEndpoint(store)
+	eth.blockRangeLimit = 1
+	schema := newGraphQLSchema(eth)
+
+	res := executeQuery(t, schema, `{ blocks(from: 0, to: 1) { number } }`, nil)
+	assert.Contains(t, res, ErrBlockRangeTooHigh.Error())
+	assert.Contains(t, res, `"data":{"blocks":null}`)
+}
+
+func TestGraphQL_Transaction(t *testing.T) {
+	schema, txn := newTestGraphQLSchema(t)
+
+	res := executeQuery(t, schema, `query Tx($hash: Bytes32!) {
+		transaction(hash: $hash) {
+			nonce value gas status gasUsed
+			block { number }
+			logs { index data topics account { address } transaction { hash } }
+		}
+	}`, map[string]interface{}{"hash": txn.Hash.String()})
+
+	assert.JSONEq(t, fmt.Sprintf(`{"data":{"transaction":{"nonce":"0x0","value":"0xa","gas":"0x5208",
+		"status":"0x1","gasUsed":"0x5208","block":{"number":"0x1"},"logs":[{"index":0,"data":"0x01",
+		"topics":["%s"],"account":{"address":"%s"},"transaction":{"hash":"%s"}}]}}}`,
+		hash2, addr1, txn.Hash,
+	), res)
+
+	res = executeQuery(t, schema, `query Tx($hash: Bytes32!) { transaction(hash: $hash) { hash } }`, nil)
+	assert.JSONEq(t, `{"errors":[{"message":"the variable $hash is required"}]}`, res)
+}
+
+func TestGraphQL_Account(t *testing.T) {
+	schema, _ := newTestGraphQLSchema(t)
+
+	res := executeQuery(t, schema, fmt.Sprintf(`{
+		block(number: 1) { account(address: "%s") { balance transactionCount code storage(slot: "%s") } }
+		pending { transactionCount account(address: "%s") { balance } }
+	}`, addr0, hash1, addr1), nil)
+
+	assert.JSONEq(t, fmt.Sprintf(`{"data":{"block":{"account":{"balance":"0x64","transactionCount":"0x1",
+		"code":"0x6000","storage":"%s"}},"pending":{"transactionCount":"0x0","account":{"balance":"0x0"}}}}`,
+		types.BytesToHash([]byte{0x5}),
+	), res)
+}
+
+func TestGraphQL_Logs(t *testing.T) {
+	schema, _ := newTestGraphQLSchema(t)
+
+	query := `query Logs($topics: [[Bytes32!]!]) {
+		logs(filter: {fromBlock: 0, topics: $topics}) { index account { address } }
+		block { logs(filter: {addresses: ["%s"]}) { data } }
+	}`
+
+	res := executeQuery(t, schema, fmt.Sprintf(query, addr1), map[string]interface{}{
+		"topics": []interface{}{[]interface{}{hash2.String()}},
+	})
+	assert.JSONEq(t, fmt.Sprintf(`{"data":{"logs":[{"index":0,"account":{"address":"%s"}}],
+		"block":{"logs":[{"data":"0x01"}]}}}`, addr1), res)
+
+	// no log of other topics or addresses
+	res = executeQuery(t, schema, fmt.Sprintf(query, addr0), map[string]interface{}{
+		"topics": []interface{}{[]interface{}{hash3.String()}},
+	})
+	assert.JSONEq(t, `{"data":{"logs":[],"block":{"logs":[]}}}`, res)
+}
+
+func TestGraphQL_Execution(t *testing.T) {
+	schema, _ := newTestGraphQLSchema(t)
+
+	cases := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			"fragments and directives",
+			`query ($skip: Boolean = true) { block { ...F number @skip(if: $skip) ... on Block { __typename } } }
+			fragment F on Block { hash @include(if: false) gasUsed }`,
+			`{"data":{"block":{"gasUsed":"0x5208","__typename":"Block"}}}`,
+		},
+		{
+			"unknown field",
+			`{ block { nonexistent } }`,
+			`{"errors":[{"message":"cannot query the field \"nonexistent\" of the type Block"}]}`,
+		},
+		{
+			"missing selection of an object",
+			`{ block }`,
+			`{"errors":[{"message":"the field \"block\" of the type Query requires a selection of fields"}]}`,
+		},
+		{
+			"invalid argument",
+			`{ block(number: "one") { number } }`,
+			`{"errors":[{"message":"invalid argument \"number\" of the field \"block\": invalid Long value one"}]}`,
+		},
+		{
+			"mutation",
+			`mutation { sendRawTransaction(data: "0x00") }`,
+			`{"errors":[{"message":"the mutation operations are not supported"}]}`,
+		},
+		{
+			"syntax error",
+			`{ block { number }`,
+			`{"errors":[{"message":"syntax error: unexpected <EOF> at 18"}]}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.JSONEq(t, c.expected, executeQuery(t, schema, c.query, nil))
+		})
+	}
+}
+
+func TestGraphQLHandler(t *testing.T) {
+	schema, _ := newTestGraphQLSchema(t)
+
+	_, localhost, _ := net.ParseCIDR("127.0.0.0/8")
+
+	handler := &graphQLHandler{
+		logger: hclog.NewNullLogger(),
+		schema: schema,
+		config: &Config{},
+	}
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		req.RemoteAddr = "127.0.0.1:1234"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	// the variables of the POST requests
+	rec := serve(httptest.NewRequest(
+		http.MethodPost,
+		"/graphql",
+		strings.NewReader(`{"query":"query ($n: Long) { block(number: $n) { number } }","variables":{"n":0}}`),
+	))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"data":{"block":{"number":"0x0"}}}`, rec.Body.String())
+
+	// the query parameters of the GET requests
+	rec = serve(httptest.NewRequest(
+		http.MethodGet,
+		"/graphql?query="+url.QueryEscape("{ block { number } }"),
+		nil,
+	))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"data":{"block":{"number":"0x1"}}}`, rec.Body.String())
+
+	rec = serve(httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":""}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// the bodies over the size limit are not read
+	rec = serve(httptest.NewRequest(
+		http.MethodPost,
+		"/graphql",
+		strings.NewReader(`{"query":"{ block { number } }","operationName":"`+strings.Repeat("a", maxGraphQLBodySize)+`"}`),
+	))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "request body too large")
+
+	// the deeply nested queries are parse errors
+	rec = serve(httptest.NewRequest(
+		http.MethodPost,
+		"/graphql",
+		strings.NewReader(`{"query":"`+strings.Repeat("{ block ", 100)+strings.Repeat("}", 100)+`"}`),
+	))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "syntax error: the document is nested deeper than 64")
+
+	// the queries are read methods of the access control
+	handler.config.AccessControl = &AccessControl{WriteCIDRs: []*net.IPNet{localhost}}
+	assert.Equal(t, http.StatusOK, serve(httptest.NewRequest(
+		http.MethodGet,
+		"/graphql?query="+url.QueryEscape("{ block { number } }"),
+		nil,
+	)).Code)
+
+	_, private, _ := net.ParseCIDR("10.0.0.0/8")
+	handler.config.AccessControl = &AccessControl{ReadCIDRs: []*net.IPNet{private}}
+	assert.Equal(t, http.StatusForbidden, serve(httptest.NewRequest(
+		http.MethodGet,
+		"/graphql?query="+url.QueryEscape("{ block { number } }"),
+		nil,
+	)).Code)
+}
@@ -0,0 +1,203 @@
+package jsonrpc
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// IBFTStatus is the live state of the IBFT consensus
+type IBFTStatus struct {
+	State              string
+	Sequence           uint64
+	Round              uint64
+	Proposer           types.Address
+	IsProposer         bool
+	LockedProposal     *types.Hash
+	LastCommittedRound *uint64
+}
+
+// IBFTVote is a pending vote on adding or removing a validator
+type IBFTVote struct {
+	Validator types.Address
+	Address   types.Address
+	Authorize bool
+}
+
+// IBFTSnapshot is the validator set and the pending votes at a block
+type IBFTSnapshot struct {
+	Number     uint64
+	Hash       types.Hash
+	Validators []types.Address
+	Votes      []IBFTVote
+}
+
+// ibftStore provides access to the methods needed for ibft endpoint
+type ibftStore interface {
+	// Header returns the current header of the chain
+	Header() *types.Header
+
+	// GetIBFTStatus returns the live state of the IBFT consensus
+	GetIBFTStatus() (*IBFTStatus, error)
+
+	// GetIBFTValidators returns the validators from the extra data of the block
+	GetIBFTValidators(number uint64) ([]types.Address, error)
+
+	// GetIBFTValidatorsAt returns the validators of the block from the validator index
+	GetIBFTValidatorsAt(number uint64) ([]types.Address, error)
+
+	// GetIBFTSnapshot returns the snapshot at the block
+	GetIBFTSnapshot(number uint64) (*IBFTSnapshot, error)
+
+	// GetIBFTVanity returns the vanity data the proposer wrote into the block
+	GetIBFTVanity(number uint64) ([]byte, error)
+}
+
+// IBFT is the ibft jsonrpc endpoint. It only exposes read-only methods
+type IBFT struct {
+	store ibftStore
+}
+
+type ibftStatusResponse struct {
+	State              string        `json:"state"`
+	Sequence           argUint64     `json:"sequence"`
+	Round              argUint64     `json:"round"`
+	Proposer           types.Address `json:"proposer"`
+	IsProposer         bool          `json:"isProposer"`
+	Locked             bool          `json:"locked"`
+	LockedProposal     *types.Hash   `json:"lockedProposal"`
+	LastCommittedRound *argUint64    `json:"lastCommittedRound"`
+}
+
+type ibftVoteResponse struct {
+	Validator types.Address `json:"validator"`
+	Address   types.Address `json:"address"`
+	Authorize bool          `json:"authorize"`
+}
+
+type ibftSnapshotResponse struct {
+	Number     argUint64          `json:"number"`
+	Hash       types.Hash         `json:"hash"`
+	Validators []types.Address    `json:"validators"`
+	Votes      []ibftVoteResponse `json:"votes"`
+}
+
+// Status returns the live state of the IBFT consensus
+func (i *IBFT) Status() (interface{}, error) {
+	status, err := i.store.GetIBFTStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ibftStatusResponse{
+		State:          status.State,
+		Sequence:       argUint64(status.Sequence),
+		Round:          argUint64(status.Round),
+		Proposer:       status.Proposer,
+		IsProposer:     status.IsProposer,
+		Locked:         status.LockedProposal != nil,
+		LockedProposal: status.LockedProposal,
+	}
+
+	if status.LastCommittedRound != nil {
+		resp.LastCommittedRound = argUintPtr(*status.LastCommittedRound)
+	}
+
+	return resp, nil
+}
+
+// GetValidatorsByBlockNumber returns the validators from the extra data of the block
+func (i *IBFT) GetValidatorsByBlockNumber(number BlockNumber) (interface{}, error) {
+	num, err := i.getNumericBlockNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	validators, err := i.store.GetIBFTValidators(num)
+	if err != nil {
+		return nil, err
+	}
+
+	return validators, nil
+}
+
+// GetValidatorsAt returns the validators of the block from the validator index,
+// without reading the header of the block
+func (i *IBFT) GetValidatorsAt(number BlockNumber) (interface{}, error) {
+	num, err := i.getNumericBlockNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	validators, err := i.store.GetIBFTValidatorsAt(num)
+	if err != nil {
+		return nil, err
+	}
+
+	return validators, nil
+}
+
+// GetSnapshot returns the validator set and the pending votes at the block
+func (i *IBFT) GetSnapshot(number BlockNumber) (interface{}, error) {
+	num, err := i.getNumericBlockNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err := i.store.GetIBFTSnapshot(num)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ibftSnapshotResponse{
+		Number:     argUint64(snap.Number),
+		Hash:       snap.Hash,
+		Validators: snap.Validators,
+		Votes:      make([]ibftVoteResponse, len(snap.Votes)),
+	}
+
+	for indx, vote := range snap.Votes {
+		resp.Votes[indx] = ibftVoteResponse{
+			Validator: vote.Validator,
+			Address:   vote.Address,
+			Authorize: vote.Authorize,
+		}
+	}
+
+	return resp, nil
+}
+
+// GetVanityByBlockNumber returns the vanity data the proposer wrote into the block
+func (i *IBFT) GetVanityByBlockNumber(number BlockNumber) (interface{}, error) {
+	num, err := i.getNumericBlockNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	vanity, err := i.store.GetIBFTVanity(num)
+	if err != nil {
+		return nil, err
+	}
+
+	return argBytes(vanity), nil
+}
+
+func (i *IBFT) getNumericBlockNumber(number BlockNumber) (uint64, error) {
+	switch number {
+	case LatestBlockNumber:
+		return i.store.Header().Number, nil
+
+	case EarliestBlockNumber:
+		return 0, nil
+
+	case PendingBlockNumber:
+		return 0, fmt.Errorf("fetching the pending header is not supported")
+
+	default:
+		if number < 0 {
+			return 0, fmt.Errorf("invalid argument 0: block number larger than int64")
+		}
+
+		return uint64(number), nil
+	}
+}
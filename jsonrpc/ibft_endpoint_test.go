@@ -0,0 +1,196 @@
+package jsonrpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockIBFTStore struct {
+	header     *types.Header
+	status     *IBFTStatus
+	validators map[uint64][]types.Address
+	indexed    map[uint64][]types.Address
+	snapshots  map[uint64]*IBFTSnapshot
+	vanities   map[uint64][]byte
+}
+
+func (m *mockIBFTStore) Header() *types.Header {
+	return m.header
+}
+
+func (m *mockIBFTStore) GetIBFTStatus() (*IBFTStatus, error) {
+	return m.status, nil
+}
+
+func (m *mockIBFTStore) GetIBFTValidators(number uint64) ([]types.Address, error) {
+	validators, ok := m.validators[number]
+	if !ok {
+		return nil, errors.New("header not found")
+	}
+
+	return validators, nil
+}
+
+func (m *mockIBFTStore) GetIBFTValidatorsAt(number uint64) ([]types.Address, error) {
+	validators, ok := m.indexed[number]
+	if !ok {
+		return nil, errors.New("the validators of the block are not indexed")
+	}
+
+	return validators, nil
+}
+
+func (m *mockIBFTStore) GetIBFTSnapshot(number uint64) (*IBFTSnapshot, error) {
+	snap, ok := m.snapshots[number]
+	if !ok {
+		return nil, errors.New("snapshot not found")
+	}
+
+	return snap, nil
+}
+
+func (m *mockIBFTStore) GetIBFTVanity(number uint64) ([]byte, error) {
+	vanity, ok := m.vanities[number]
+	if !ok {
+		return nil, errors.New("header not found")
+	}
+
+	return vanity, nil
+}
+
+func TestIBFTEndpoint_Status(t *testing.T) {
+	lockedProposal := types.StringToHash("1")
+	lastRound := uint64(2)
+
+	ibftEndpoint := &IBFT{&mockIBFTStore{
+		status: &IBFTStatus{
+			State:              "ValidateState",
+			Sequence:           10,
+			Round:              1,
+			Proposer:           types.StringToAddress("1"),
+			IsProposer:         true,
+			LockedProposal:     &lockedProposal,
+			LastCommittedRound: &lastRound,
+		},
+	}}
+
+	result, err := ibftEndpoint.Status()
+	assert.NoError(t, err)
+
+	// nolint:forcetypeassert
+	status := result.(*ibftStatusResponse)
+
+	assert.Equal(t, "ValidateState", status.State)
+	assert.Equal(t, argUint64(10), status.Sequence)
+	assert.Equal(t, argUint64(1), status.Round)
+	assert.True(t, status.IsProposer)
+	assert.True(t, status.Locked)
+	assert.Equal(t, &lockedProposal, status.LockedProposal)
+	assert.Equal(t, argUintPtr(2), status.LastCommittedRound)
+}
+
+func TestIBFTEndpoint_GetValidatorsByBlockNumber(t *testing.T) {
+	validators := []types.Address{types.StringToAddress("1"), types.StringToAddress("2")}
+
+	ibftEndpoint := &IBFT{&mockIBFTStore{
+		header: &types.Header{Number: 5},
+		validators: map[uint64][]types.Address{
+			0: validators[:1],
+			5: validators,
+		},
+	}}
+
+	result, err := ibftEndpoint.GetValidatorsByBlockNumber(LatestBlockNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, validators, result)
+
+	result, err = ibftEndpoint.GetValidatorsByBlockNumber(EarliestBlockNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, validators[:1], result)
+
+	_, err = ibftEndpoint.GetValidatorsByBlockNumber(BlockNumber(6))
+	assert.Error(t, err)
+
+	_, err = ibftEndpoint.GetValidatorsByBlockNumber(PendingBlockNumber)
+	assert.Error(t, err)
+}
+
+func TestIBFTEndpoint_GetValidatorsAt(t *testing.T) {
+	validators := []types.Address{types.StringToAddress("1"), types.StringToAddress("2")}
+
+	ibftEndpoint := &IBFT{&mockIBFTStore{
+		header: &types.Header{Number: 5},
+		indexed: map[uint64][]types.Address{
+			2: validators[:1],
+			5: validators,
+		},
+	}}
+
+	result, err := ibftEndpoint.GetValidatorsAt(LatestBlockNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, validators, result)
+
+	result, err = ibftEndpoint.GetValidatorsAt(BlockNumber(2))
+	assert.NoError(t, err)
+	assert.Equal(t, validators[:1], result)
+
+	_, err = ibftEndpoint.GetValidatorsAt(BlockNumber(6))
+	assert.Error(t, err)
+
+	_, err = ibftEndpoint.GetValidatorsAt(PendingBlockNumber)
+	assert.Error(t, err)
+}
+
+func TestIBFTEndpoint_GetSnapshot(t *testing.T) {
+	validator, candidate := types.StringToAddress("1"), types.StringToAddress("2")
+
+	ibftEndpoint := &IBFT{&mockIBFTStore{
+		header: &types.Header{Number: 3},
+		snapshots: map[uint64]*IBFTSnapshot{
+			3: {
+				Number:     3,
+				Hash:       types.StringToHash("3"),
+				Validators: []types.Address{validator},
+				Votes: []IBFTVote{
+					{Validator: validator, Address: candidate, Authorize: true},
+				},
+			},
+		},
+	}}
+
+	result, err := ibftEndpoint.GetSnapshot(LatestBlockNumber)
+	assert.NoError(t, err)
+
+	// nolint:forcetypeassert
+	snap := result.(*ibftSnapshotResponse)
+
+	assert.Equal(t, argUint64(3), snap.Number)
+	assert.Equal(t, []types.Address{validator}, snap.Validators)
+	assert.Equal(t, []ibftVoteResponse{
+		{Validator: validator, Address: candidate, Authorize: true},
+	}, snap.Votes)
+
+	_, err = ibftEndpoint.GetSnapshot(BlockNumber(1))
+	assert.Error(t, err)
+}
+
+func TestIBFTEndpoint_GetVanityByBlockNumber(t *testing.T) {
+	vanity := []byte("vanity")
+
+	ibftEndpoint := &IBFT{&mockIBFTStore{
+		header: &types.Header{Number: 1},
+		vanities: map[uint64][]byte{
+			1: vanity,
+		},
+	}}
+
+	result, err := ibftEndpoint.GetVanityByBlockNumber(LatestBlockNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, argBytes(vanity), result)
+
+	_, err = ibftEndpoint.GetVanityByBlockNumber(BlockNumber(2))
+	assert.Error(t, err)
+}
@@ -1,11 +1,14 @@
 package jsonrpc
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/hashicorp/go-hclog"
@@ -37,11 +40,17 @@ type JSONRPC struct {
 	logger     hclog.Logger
 	config     *Config
 	dispatcher dispatcher
+	server     *http.Server
+
+	// graphQLServer serves the GraphQL queries, nil if it isn't served
+	graphQLServer *http.Server
 }
 
 type dispatcher interface {
-	HandleWs(reqBody []byte, conn wsConn) ([]byte, error)
-	Handle(reqBody []byte) ([]byte, error)
+	HandleWs(ctx context.Context, reqBody []byte, conn wsConn, client string) ([]byte, error)
+	Handle(ctx context.Context, reqBody []byte, client string) ([]byte, error)
+	RemoveFilterByWs(conn wsConn)
+	Close()
 }
 
 // JSONRPCStore defines all the methods required
@@ -51,6 +60,10 @@ type JSONRPCStore interface {
 	networkStore
 	txPoolStore
 	filterManagerStore
+	ibftStore
+	debugStore
+	edgeStore
+	evmStore
 }
 
 type Config struct {
@@ -58,14 +71,71 @@ type Config struct {
 	Addr                     *net.TCPAddr
 	ChainID                  uint64
 	AccessControlAllowOrigin []string
+	FeeHistoryLimit          uint64
+	BlockRangeLimit          uint64
+	LogsLimit                uint64
+	SubscriptionBufferSize   uint64
+	AllowedMethods           []string
+	DeniedMethods            []string
+	RateLimit                uint64
+	MethodRateLimits         map[string]uint64
+	BatchLimit               uint64
+	BatchWorkers             uint64
+	FilterTimeout            time.Duration
+	SyncDistance             uint64
+	DevMode                  bool
+	Metrics                  *Metrics
+
+	// AccessControl restricts the clients of the method groups to the listed networks, any client is allowed if nil
+	AccessControl *AccessControl
+
+	// GraphQLAddr is the address of the GraphQL server, it isn't served if not set
+	GraphQLAddr *net.TCPAddr
+
+	// ExecutionTimeout aborts the executions of the EVM, such as eth_call, running longer, per method if overridden.
+	// 0 for no timeout
+	ExecutionTimeout        time.Duration
+	MethodExecutionTimeouts map[string]time.Duration
+	// ExecutionLimit is the max no.of executions of the EVM running at the same time, the others are queued.
+	// 0 for no limit
+	ExecutionLimit uint64
 }
 
 // NewJSONRPC returns the JSONRPC http server
 func NewJSONRPC(logger hclog.Logger, config *Config) (*JSONRPC, error) {
+	dispatcher, err := newDispatcher(
+		logger,
+		config.Store,
+		dispatcherParams{
+			chainID:                 config.ChainID,
+			feeHistoryLimit:         config.FeeHistoryLimit,
+			blockRangeLimit:         config.BlockRangeLimit,
+			logsLimit:               config.LogsLimit,
+			subscriptionBufferSize:  config.SubscriptionBufferSize,
+			allowedMethods:          config.AllowedMethods,
+			deniedMethods:           config.DeniedMethods,
+			rateLimit:               config.RateLimit,
+			methodRateLimits:        config.MethodRateLimits,
+			batchLimit:              config.BatchLimit,
+			batchWorkers:            config.BatchWorkers,
+			filterTimeout:           config.FilterTimeout,
+			syncDistance:            config.SyncDistance,
+			devMode:                 config.DevMode,
+			metrics:                 config.Metrics,
+			accessControl:           config.AccessControl,
+			executionTimeout:        config.ExecutionTimeout,
+			methodExecutionTimeouts: config.MethodExecutionTimeouts,
+			executionLimit:          config.ExecutionLimit,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	srv := &JSONRPC{
 		logger:     logger.Named("jsonrpc"),
 		config:     config,
-		dispatcher: newDispatcher(logger, config.Store, config.ChainID),
+		dispatcher: dispatcher,
 	}
 
 	// start http server
@@ -73,6 +143,13 @@ func NewJSONRPC(logger hclog.Logger, config *Config) (*JSONRPC, error) {
 		return nil, err
 	}
 
+	// start graphql server, the queries are resolved by the eth endpoint of the dispatcher
+	if config.GraphQLAddr != nil {
+		if err := srv.setupGraphQL(dispatcher.endpoints.Eth); err != nil {
+			return nil, err
+		}
+	}
+
 	return srv, nil
 }
 
@@ -92,12 +169,12 @@ func (j *JSONRPC) setupHTTP() error {
 
 	mux.HandleFunc("/ws", j.handleWs)
 
-	srv := http.Server{
+	j.server = &http.Server{
 		Handler: mux,
 	}
 
 	go func() {
-		if err := srv.Serve(lis); err != nil {
+		if err := j.server.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			j.logger.Error("closed http connection", "err", err)
 		}
 	}()
@@ -105,25 +182,30 @@ func (j *JSONRPC) setupHTTP() error {
 	return nil
 }
 
-// The middlewareFactory builds a middleware which enables CORS using the provided config.
-func middlewareFactory(config *Config) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			origin := r.Header.Get("Origin")
+// Close stops accepting the new requests, waits for the ones in progress and stops the filters
+func (j *JSONRPC) Close() error {
+	err := j.server.Shutdown(context.Background())
 
-			for _, allowedOrigin := range config.AccessControlAllowOrigin {
-				if allowedOrigin == "*" {
-					w.Header().Set("Access-Control-Allow-Origin", "*")
+	if j.graphQLServer != nil {
+		if graphQLErr := j.graphQLServer.Shutdown(context.Background()); err == nil {
+			err = graphQLErr
+		}
+	}
 
-					break
-				}
+	// the filters are stopped before the blockchain closes their events
+	j.dispatcher.Close()
 
-				if allowedOrigin == origin {
-					w.Header().Set("Access-Control-Allow-Origin", origin)
+	return err
+}
 
-					break
-				}
+// The middlewareFactory builds a middleware which enables CORS using the provided config.
+func middlewareFactory(config *Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if origin, ok := allowedOrigin(config.AccessControlAllowOrigin, r.Header.Get("Origin")); ok {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
 			}
+
 			next.ServeHTTP(w, r)
 		})
 	}
@@ -168,11 +250,21 @@ func isSupportedWSType(messageType int) bool {
 }
 
 func (j *JSONRPC) handleWs(w http.ResponseWriter, req *http.Request) {
-	// CORS rule - Allow requests from anywhere
-	wsUpgrader.CheckOrigin = func(r *http.Request) bool { return true }
+	// CORS rule - the browsers connect from the allowed origins only, the other clients send no origin
+	upgrader := wsUpgrader
+	upgrader.CheckOrigin = func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		_, ok := allowedOrigin(j.config.AccessControlAllowOrigin, origin)
+
+		return ok
+	}
 
 	// Upgrade the connection to a WS one
-	ws, err := wsUpgrader.Upgrade(w, req, nil)
+	ws, err := upgrader.Upgrade(w, req, nil)
 	if err != nil {
 		j.logger.Error(fmt.Sprintf("Unable to upgrade to a WS connection, %s", err.Error()))
 
@@ -190,6 +282,11 @@ func (j *JSONRPC) handleWs(w http.ResponseWriter, req *http.Request) {
 	}(ws)
 
 	wrapConn := &wsWrapper{ws: ws, logger: j.logger}
+	client := j.config.AccessControl.clientIP(req)
+
+	// the executions of the requests of the connection are aborted once it is closed
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	j.logger.Info("Websocket connection established")
 	// Run the listen loop
@@ -209,12 +306,15 @@ func (j *JSONRPC) handleWs(w http.ResponseWriter, req *http.Request) {
 				j.logger.Info("Closing WS connection with error")
 			}
 
+			// the subscriptions of the connection can't be delivered anymore
+			j.dispatcher.RemoveFilterByWs(wrapConn)
+
 			break
 		}
 
 		if isSupportedWSType(msgType) {
 			go func() {
-				resp, handleErr := j.dispatcher.HandleWs(message, wrapConn)
+				resp, handleErr := j.dispatcher.HandleWs(ctx, message, wrapConn, client)
 				if handleErr != nil {
 					j.logger.Error(fmt.Sprintf("Unable to handle WS request, %s", handleErr.Error()))
 
@@ -268,7 +368,7 @@ func (j *JSONRPC) handle(w http.ResponseWriter, req *http.Request) {
 	// log request
 	j.logger.Debug("handle", "request", string(data))
 
-	resp, err := j.dispatcher.Handle(data)
+	resp, err := j.dispatcher.Handle(req.Context(), data, j.config.AccessControl.clientIP(req))
 
 	if err != nil {
 		//nolint
@@ -0,0 +1,80 @@
+package jsonrpc
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	prometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics represents the jsonrpc metrics, the counters are labeled with the method
+type Metrics struct {
+	// No.of requests served
+	Requests metrics.Counter
+	// No.of requests rejected because the client went over a rate limit
+	RateLimitedRequests metrics.Counter
+	// No.of requests rejected because the method is disabled
+	DeniedRequests metrics.Counter
+	// No.of executions of the EVM aborted on their timeout
+	AbortedExecutions metrics.Counter
+	// No.of executions of the EVM waiting for a running one to end
+	QueuedExecutions metrics.Gauge
+}
+
+// GetPrometheusMetrics return the jsonrpc metrics instance
+func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics {
+	labels := []string{}
+
+	for i := 0; i < len(labelsWithValues); i += 2 {
+		labels = append(labels, labelsWithValues[i])
+	}
+
+	// the gauges are not labeled with the method
+	gaugeLabels := append([]string{}, labels...)
+
+	labels = append(labels, "method")
+
+	return &Metrics{
+		Requests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "jsonrpc",
+			Name:      "requests",
+			Help:      "Number of requests served.",
+		}, labels).With(labelsWithValues...),
+		RateLimitedRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "jsonrpc",
+			Name:      "rate_limited_requests",
+			Help:      "Number of requests rejected by the rate limits.",
+		}, labels).With(labelsWithValues...),
+		DeniedRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "jsonrpc",
+			Name:      "denied_requests",
+			Help:      "Number of requests rejected because the method is disabled.",
+		}, labels).With(labelsWithValues...),
+		AbortedExecutions: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "jsonrpc",
+			Name:      "aborted_executions",
+			Help:      "Number of executions of the EVM aborted on their timeout.",
+		}, labels).With(labelsWithValues...),
+		QueuedExecutions: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "jsonrpc",
+			Name:      "queued_executions",
+			Help:      "Number of executions of the EVM waiting for a running one to end.",
+		}, gaugeLabels).With(labelsWithValues...),
+	}
+}
+
+// NilMetrics will return the non operational jsonrpc metrics
+func NilMetrics() *Metrics {
+	return &Metrics{
+		Requests:            discard.NewCounter(),
+		RateLimitedRequests: discard.NewCounter(),
+		DeniedRequests:      discard.NewCounter(),
+		AbortedExecutions:   discard.NewCounter(),
+		QueuedExecutions:    discard.NewGauge(),
+	}
+}
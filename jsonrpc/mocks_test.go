@@ -1,9 +1,9 @@
 package jsonrpc
 
 import (
-	"errors"
 	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/state"
+	txpoolProto "github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 	"math/big"
 	"sync"
@@ -48,17 +48,26 @@ type mockStore struct {
 	JSONRPCStore
 
 	header       *types.Header
-	subscription *blockchain.MockSubscription
+	events       chan *blockchain.Event
 	receiptsLock sync.Mutex
 	receipts     map[types.Hash][]*types.Receipt
 	accounts     map[types.Address]*state.Account
+	txEventsCh   chan *txpoolProto.TxPoolEvent
 }
 
 func newMockStore() *mockStore {
 	return &mockStore{
-		header:       &types.Header{Number: 0},
-		subscription: blockchain.NewMockSubscription(),
-		accounts:     map[types.Address]*state.Account{},
+		header:     &types.Header{Number: 0},
+		events:     make(chan *blockchain.Event),
+		accounts:   map[types.Address]*state.Account{},
+		txEventsCh: make(chan *txpoolProto.TxPoolEvent, 16),
+	}
+}
+
+func (m *mockStore) emitTxEvent(hash types.Hash) {
+	m.txEventsCh <- &txpoolProto.TxPoolEvent{
+		Type:   txpoolProto.EventType_PROMOTED,
+		TxHash: hash.String(),
 	}
 }
 
@@ -82,7 +91,7 @@ func (m *mockStore) emitEvent(evnt *mockEvent) {
 		bEvnt.OldChain = append(bEvnt.OldChain, i.header)
 	}
 
-	m.subscription.Push(bEvnt)
+	m.events <- bEvnt
 }
 
 func (m *mockStore) GetAccount(root types.Hash, addr types.Address) (*state.Account, error) {
@@ -90,7 +99,7 @@ func (m *mockStore) GetAccount(root types.Hash, addr types.Address) (*state.Acco
 		return acc, nil
 	}
 
-	return nil, errors.New("given root and slot not found in storage")
+	return nil, ErrStateNotFound
 }
 
 func (m *mockStore) SetAccount(addr types.Address, account *state.Account) {
@@ -110,8 +119,14 @@ func (m *mockStore) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error)
 	return receipts, nil
 }
 
-func (m *mockStore) SubscribeEvents() blockchain.Subscription {
-	return m.subscription
+func (m *mockStore) SubscribeEvents() (<-chan *blockchain.Event, func()) {
+	return m.events, func() {}
+}
+
+func (m *mockStore) SubscribeTxEvents(
+	eventTypes ...txpoolProto.EventType,
+) (<-chan *txpoolProto.TxPoolEvent, func()) {
+	return m.txEventsCh, func() {}
 }
 
 func (m *mockStore) GetBlockByNumber(num uint64, full bool) (*types.Block, bool) {
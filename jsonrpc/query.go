@@ -88,6 +88,11 @@ func (q *LogQuery) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	// the block hash selects a single block, it can't be used with a block range
+	if obj.BlockHash != nil && (obj.FromBlock != "" || obj.ToBlock != "") {
+		return fmt.Errorf("cannot specify both blockHash and fromBlock/toBlock")
+	}
+
 	q.BlockHash = obj.BlockHash
 
 	if obj.FromBlock == "" {
@@ -174,6 +179,49 @@ func (q *LogQuery) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MatchBloom returns whether the logs in the bloom may include logs matching this filter.
+// The blocks built before the logs bloom was written have an empty bloom, and always match
+func (q *LogQuery) MatchBloom(bloom types.Bloom) bool {
+	if bloom == (types.Bloom{}) {
+		return true
+	}
+
+	// check addresses
+	if len(q.Addresses) > 0 {
+		match := false
+
+		for _, addr := range q.Addresses {
+			if bloom.IsValueInBloom(addr.Bytes()) {
+				match = true
+
+				break
+			}
+		}
+
+		if !match {
+			return false
+		}
+	}
+	// check topics
+	for _, sub := range q.Topics {
+		match := len(sub) == 0
+
+		for _, topic := range sub {
+			if bloom.IsValueInBloom(topic.Bytes()) {
+				match = true
+
+				break
+			}
+		}
+
+		if !match {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Match returns whether the receipt includes topics for this filter
 func (q *LogQuery) Match(log *types.Log) bool {
 	// check addresses
@@ -119,6 +119,13 @@ func TestFilterDecode(t *testing.T) {
 				toBlock:   LatestBlockNumber,
 			},
 		},
+		{
+			`{
+				"blockHash": "` + hash1.String() + `",
+				"fromBlock": "earliest"
+			}`,
+			nil,
+		},
 	}
 
 	for indx, c := range cases {
@@ -141,6 +148,71 @@ func TestFilterDecode(t *testing.T) {
 	}
 }
 
+func TestFilterMatchBloom(t *testing.T) {
+	bloom := types.CreateBloom([]*types.Receipt{
+		{
+			Logs: []*types.Log{
+				{
+					Address: addr1,
+					Topics:  []types.Hash{hash1, hash2},
+				},
+			},
+		},
+	})
+
+	cases := []struct {
+		filter LogQuery
+		bloom  types.Bloom
+		match  bool
+	}{
+		{
+			// correct, no filter
+			LogQuery{},
+			bloom,
+			true,
+		},
+		{
+			// correct, one of the addresses
+			LogQuery{
+				Addresses: []types.Address{addr2, addr1},
+				Topics:    [][]types.Hash{{}, {hash3, hash2}},
+			},
+			bloom,
+			true,
+		},
+		{
+			// bad, the address is not in the bloom
+			LogQuery{
+				Addresses: []types.Address{addr2},
+			},
+			bloom,
+			false,
+		},
+		{
+			// bad, the topic is not in the bloom
+			LogQuery{
+				Topics: [][]types.Hash{{hash1}, {hash3}},
+			},
+			bloom,
+			false,
+		},
+		{
+			// correct, the empty bloom of the blocks built before the bloom was written
+			LogQuery{
+				Addresses: []types.Address{addr2},
+			},
+			types.Bloom{},
+			true,
+		},
+	}
+
+	for indx, c := range cases {
+		if c.filter.MatchBloom(c.bloom) != c.match {
+			t.Fatalf("bad %d", indx)
+		}
+	}
+}
+
 func TestFilterMatch(t *testing.T) {
 	cases := []struct {
 		filter LogQuery
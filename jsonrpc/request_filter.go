@@ -0,0 +1,167 @@
+package jsonrpc
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"golang.org/x/time/rate"
+)
+
+// maxLimitedClients is the number of rate limiters the request filter keeps track of,
+// the least recently used ones are dropped and start over
+const maxLimitedClients = 8192
+
+// unknownMethod is the metrics label of the requests to methods that do not exist,
+// so the clients can't blow up the metrics cardinality
+const unknownMethod = "unknown"
+
+// limiterKey identifies the rate limiter of a client, the method is empty
+// for the limiter of all the requests of the client
+type limiterKey struct {
+	client string
+	method string
+}
+
+// requestFilter rejects the requests to the disabled methods and the requests of the clients
+// not allowed to call them, as well as the requests of the clients that go over the rate limits
+type requestFilter struct {
+	metrics *Metrics
+
+	// accessControl restricts the clients of the method groups, any client is allowed if nil
+	accessControl *AccessControl
+
+	// allowedMethods and deniedMethods are the method names, or the namespaces
+	// such as debug_*, enabled and disabled. All the methods are enabled if no
+	// method is allowed, the denied ones are disabled even if allowed
+	allowedMethods []string
+	deniedMethods  []string
+
+	// rateLimit is the number of requests per second accepted from a single client,
+	// methodRateLimits are the numbers per method on top. 0 disables the limit
+	rateLimit        uint64
+	methodRateLimits map[string]uint64
+
+	lock     sync.Mutex
+	limiters *lru.Cache
+}
+
+// newRequestFilter creates a new jsonrpc request filter
+func newRequestFilter(metrics *Metrics, params dispatcherParams) (*requestFilter, error) {
+	limiters, err := lru.New(maxLimitedClients)
+	if err != nil {
+		return nil, err
+	}
+
+	return &requestFilter{
+		metrics:          metrics,
+		accessControl:    params.accessControl,
+		allowedMethods:   params.allowedMethods,
+		deniedMethods:    params.deniedMethods,
+		rateLimit:        params.rateLimit,
+		methodRateLimits: params.methodRateLimits,
+		limiters:         limiters,
+	}, nil
+}
+
+// validate checks if the request of the client to the method should be served,
+// the label is the method name reported in the metrics
+func (f *requestFilter) validate(client string, method string, label string) Error {
+	if !f.isEnabled(method) {
+		f.metrics.DeniedRequests.With("method", label).Add(1)
+
+		return NewMethodNotFoundError(method)
+	}
+
+	if !f.accessControl.isAllowed(client, method) {
+		f.metrics.DeniedRequests.With("method", label).Add(1)
+
+		return NewUnauthorizedError(method)
+	}
+
+	if retryAfter := f.reserve(client, method); retryAfter > 0 {
+		f.metrics.RateLimitedRequests.With("method", label).Add(1)
+
+		return NewLimitExceededError(method, retryAfter)
+	}
+
+	f.metrics.Requests.With("method", label).Add(1)
+
+	return nil
+}
+
+// isEnabled checks if the method is served
+func (f *requestFilter) isEnabled(method string) bool {
+	if matchMethod(f.deniedMethods, method) {
+		return false
+	}
+
+	return len(f.allowedMethods) == 0 || matchMethod(f.allowedMethods, method)
+}
+
+// reserve takes a token from the rate limiters of the client for the method,
+// if any of them is empty no token is taken and the time to wait is returned
+func (f *requestFilter) reserve(client string, method string) time.Duration {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var (
+		now          = time.Now()
+		retryAfter   time.Duration
+		reservations = make([]*rate.Reservation, 0, 2)
+	)
+
+	if f.rateLimit > 0 {
+		reservations = append(reservations, f.getLimiter(limiterKey{client, ""}, f.rateLimit).ReserveN(now, 1))
+	}
+
+	if limit := f.methodRateLimits[method]; limit > 0 {
+		reservations = append(reservations, f.getLimiter(limiterKey{client, method}, limit).ReserveN(now, 1))
+	}
+
+	for _, reservation := range reservations {
+		if delay := reservation.DelayFrom(now); delay > retryAfter {
+			retryAfter = delay
+		}
+	}
+
+	if retryAfter > 0 {
+		for _, reservation := range reservations {
+			reservation.CancelAt(now)
+		}
+	}
+
+	return retryAfter
+}
+
+// getLimiter returns the rate limiter of the key, creating it if needed
+func (f *requestFilter) getLimiter(key limiterKey, limit uint64) *rate.Limiter {
+	if limiter, ok := f.limiters.Get(key); ok {
+		return limiter.(*rate.Limiter) // nolint:forcetypeassert
+	}
+
+	limiter := rate.NewLimiter(rate.Limit(limit), int(limit))
+	f.limiters.Add(key, limiter)
+
+	return limiter
+}
+
+// matchMethod checks if the method is one of the names, or in one of the namespaces
+func matchMethod(names []string, method string) bool {
+	for _, name := range names {
+		if strings.HasSuffix(name, "_*") {
+			if strings.HasPrefix(method, strings.TrimSuffix(name, "*")) {
+				return true
+			}
+
+			continue
+		}
+
+		if name == method {
+			return true
+		}
+	}
+
+	return false
+}
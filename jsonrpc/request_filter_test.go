@@ -0,0 +1,135 @@
+package jsonrpc
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCounter counts the additions per method label
+type mockCounter struct {
+	lock   *sync.Mutex
+	counts map[string]float64
+	method string
+}
+
+func newMockCounter() *mockCounter {
+	return &mockCounter{
+		lock:   &sync.Mutex{},
+		counts: map[string]float64{},
+	}
+}
+
+func (c *mockCounter) With(labelValues ...string) metrics.Counter {
+	counter := *c
+
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		if labelValues[i] == "method" {
+			counter.method = labelValues[i+1]
+		}
+	}
+
+	return &counter
+}
+
+func (c *mockCounter) Add(delta float64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.counts[c.method] += delta
+}
+
+func (c *mockCounter) count(method string) float64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.counts[method]
+}
+
+func newMockMetrics() (*Metrics, *mockCounter, *mockCounter, *mockCounter) {
+	requests, rateLimited, denied := newMockCounter(), newMockCounter(), newMockCounter()
+
+	return &Metrics{
+		Requests:            requests,
+		RateLimitedRequests: rateLimited,
+		DeniedRequests:      denied,
+	}, requests, rateLimited, denied
+}
+
+func TestRequestFilter_Methods(t *testing.T) {
+	metrics, requests, _, denied := newMockMetrics()
+
+	filter, err := newRequestFilter(metrics, dispatcherParams{
+		allowedMethods: []string{"eth_*", "debug_traceTransaction", "txpool_content"},
+		deniedMethods:  []string{"debug_*", "eth_sendRawTransaction"},
+	})
+	assert.NoError(t, err)
+
+	cases := []struct {
+		method  string
+		enabled bool
+	}{
+		{"eth_getLogs", true},
+		{"eth_sendRawTransaction", false},
+		{"ethx_getLogs", false},
+		{"debug_traceTransaction", false},
+		{"txpool_content", true},
+		{"txpool_status", false},
+		{"net_version", false},
+	}
+
+	for _, c := range cases {
+		err := filter.validate("client", c.method, c.method)
+		if c.enabled {
+			assert.NoError(t, err, c.method)
+			assert.Equal(t, float64(1), requests.count(c.method))
+		} else if assert.Error(t, err, c.method) {
+			assert.Equal(t, -32601, err.ErrorCode())
+			assert.Equal(t, float64(1), denied.count(c.method))
+		}
+	}
+
+	// all the methods are enabled by default
+	filter, err = newRequestFilter(NilMetrics(), dispatcherParams{})
+	assert.NoError(t, err)
+	assert.NoError(t, filter.validate("client", "debug_traceTransaction", "debug_traceTransaction"))
+}
+
+func TestRequestFilter_RateLimits(t *testing.T) {
+	metrics, requests, rateLimited, _ := newMockMetrics()
+
+	filter, err := newRequestFilter(metrics, dispatcherParams{
+		rateLimit: 3,
+		methodRateLimits: map[string]uint64{
+			"eth_getLogs": 1,
+		},
+	})
+	assert.NoError(t, err)
+
+	// the method limit is reached first
+	assert.NoError(t, filter.validate("client1", "eth_getLogs", "eth_getLogs"))
+
+	rpcErr := filter.validate("client1", "eth_getLogs", "eth_getLogs")
+	if assert.Error(t, rpcErr) {
+		assert.Equal(t, -32005, rpcErr.ErrorCode())
+
+		// nolint:forcetypeassert
+		retryAfter := rpcErr.(dataError).ErrorData().(map[string]uint64)["retryAfter"]
+		assert.Equal(t, uint64(1), retryAfter)
+	}
+
+	// the rejected request did not take a token of the client limit
+	assert.NoError(t, filter.validate("client1", "eth_blockNumber", "eth_blockNumber"))
+	assert.NoError(t, filter.validate("client1", "eth_blockNumber", "eth_blockNumber"))
+	assert.Error(t, filter.validate("client1", "eth_blockNumber", "eth_blockNumber"))
+
+	// the limits are per client
+	assert.NoError(t, filter.validate("client2", "eth_getLogs", "eth_getLogs"))
+
+	assert.Equal(t, float64(2), requests.count("eth_getLogs"))
+	assert.Equal(t, float64(1), rateLimited.count("eth_getLogs"))
+	assert.Equal(t, float64(2), requests.count("eth_blockNumber"))
+	assert.Equal(t, float64(1), rateLimited.count("eth_blockNumber"))
+}
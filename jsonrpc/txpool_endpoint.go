@@ -39,23 +39,27 @@ type StatusResponse struct {
 }
 
 type txpoolTransaction struct {
-	Nonce       argUint64      `json:"nonce"`
-	GasPrice    argBig         `json:"gasPrice"`
-	Gas         argUint64      `json:"gas"`
-	To          *types.Address `json:"to"`
-	Value       argBig         `json:"value"`
-	Input       argBytes       `json:"input"`
-	Hash        types.Hash     `json:"hash"`
-	From        types.Address  `json:"from"`
-	BlockHash   types.Hash     `json:"blockHash"`
-	BlockNumber interface{}    `json:"blockNumber"`
-	TxIndex     interface{}    `json:"transactionIndex"`
+	Type                 argUint64      `json:"type"`
+	Nonce                argUint64      `json:"nonce"`
+	GasPrice             argBig         `json:"gasPrice"`
+	MaxPriorityFeePerGas *argBig        `json:"maxPriorityFeePerGas,omitempty"`
+	MaxFeePerGas         *argBig        `json:"maxFeePerGas,omitempty"`
+	Gas                  argUint64      `json:"gas"`
+	To                   *types.Address `json:"to"`
+	Value                argBig         `json:"value"`
+	Input                argBytes       `json:"input"`
+	Hash                 types.Hash     `json:"hash"`
+	From                 types.Address  `json:"from"`
+	BlockHash            types.Hash     `json:"blockHash"`
+	BlockNumber          interface{}    `json:"blockNumber"`
+	TxIndex              interface{}    `json:"transactionIndex"`
 }
 
 func toTxPoolTransaction(t *types.Transaction) *txpoolTransaction {
-	return &txpoolTransaction{
+	tx := &txpoolTransaction{
+		Type:        argUint64(t.Type),
 		Nonce:       argUint64(t.Nonce),
-		GasPrice:    argBig(*t.GasPrice),
+		GasPrice:    argBig(*t.GetGasFeeCap()),
 		Gas:         argUint64(t.Gas),
 		To:          t.To,
 		Value:       argBig(*t.Value),
@@ -66,6 +70,26 @@ func toTxPoolTransaction(t *types.Transaction) *txpoolTransaction {
 		BlockNumber: nil,
 		TxIndex:     nil,
 	}
+
+	if t.IsDynamicFee() {
+		tx.MaxPriorityFeePerGas = argBigPtr(t.MaxPriorityFeePerGas)
+		tx.MaxFeePerGas = argBigPtr(t.MaxFeePerGas)
+	}
+
+	return tx
+}
+
+// toTxPoolSummary returns the one-line summary of the transaction used by txpool_inspect,
+// the recipient (or the contract creation) followed by the value, the gas and the gas price
+func toTxPoolSummary(t *types.Transaction) string {
+	recipient := "contract creation"
+	if t.To != nil {
+		recipient = t.To.String()
+	}
+
+	return fmt.Sprintf(
+		"%s: %d wei + %d gas × %d wei", recipient, t.Value, t.Gas, t.GetGasFeeCap(),
+	)
 }
 
 // Create response for txpool_content request.
@@ -119,9 +143,7 @@ func (t *TxPool) Inspect() (interface{}, error) {
 
 		for _, tx := range txs {
 			nonceStr := strconv.FormatUint(tx.Nonce, 10)
-			pendingRPCTxs[addr.String()][nonceStr] = fmt.Sprintf(
-				"%d wei + %d gas x %d wei", tx.Value, tx.Gas, tx.GasPrice,
-			)
+			pendingRPCTxs[addr.String()][nonceStr] = toTxPoolSummary(tx)
 		}
 	}
 
@@ -132,9 +154,7 @@ func (t *TxPool) Inspect() (interface{}, error) {
 
 		for _, tx := range txs {
 			nonceStr := strconv.FormatUint(tx.Nonce, 10)
-			queuedRPCTxs[addr.String()][nonceStr] = fmt.Sprintf(
-				"%d wei + %d gas x %d wei", tx.Value, tx.Gas, tx.GasPrice,
-			)
+			queuedRPCTxs[addr.String()][nonceStr] = toTxPoolSummary(tx)
 		}
 	}
 
@@ -156,7 +156,32 @@ func TestInspectEndpoint(t *testing.T) {
 		assert.Equal(t, uint64(1), response.CurrentCapacity)
 		transactionInfo := response.Queued[testTx.From.String()]
 		assert.NotNil(t, transactionInfo)
-		assert.NotNil(t, transactionInfo[strconv.FormatUint(testTx.Nonce, 10)])
+		assert.Equal(
+			t,
+			addr1.String()+": 200 wei + 200 gas × 1 wei",
+			transactionInfo[strconv.FormatUint(testTx.Nonce, 10)],
+		)
+	})
+
+	t.Run("returns contract creation summary", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := newMockTxPoolStore()
+		address1 := types.Address{0x1}
+		testTx := newTestTransaction(2, address1)
+		testTx.To = nil
+		mockStore.pending[address1] = []*types.Transaction{testTx}
+		txPoolEndpoint := &TxPool{mockStore}
+
+		result, _ := txPoolEndpoint.Inspect()
+		// nolint:forcetypeassert
+		response := result.(InspectResponse)
+
+		assert.Equal(
+			t,
+			"contract creation: 200 wei + 200 gas × 1 wei",
+			response.Pending[testTx.From.String()][strconv.FormatUint(testTx.Nonce, 10)],
+		)
 	})
 
 	t.Run("returns correct data for pending transactions", func(t *testing.T) {
@@ -1,11 +1,13 @@
 package jsonrpc
 
 import (
+	"encoding/json"
 	"math/big"
 	"strconv"
 	"strings"
 
 	"github.com/0xPolygon/polygon-edge/helper/hex"
+	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/types"
 )
 
@@ -15,20 +17,25 @@ type transactionOrHash interface {
 }
 
 type transaction struct {
-	Nonce       argUint64      `json:"nonce"`
-	GasPrice    argBig         `json:"gasPrice"`
-	Gas         argUint64      `json:"gas"`
-	To          *types.Address `json:"to"`
-	Value       argBig         `json:"value"`
-	Input       argBytes       `json:"input"`
-	V           argBig         `json:"v"`
-	R           argBig         `json:"r"`
-	S           argBig         `json:"s"`
-	Hash        types.Hash     `json:"hash"`
-	From        types.Address  `json:"from"`
-	BlockHash   *types.Hash    `json:"blockHash"`
-	BlockNumber *argUint64     `json:"blockNumber"`
-	TxIndex     *argUint64     `json:"transactionIndex"`
+	Type                 argUint64         `json:"type"`
+	Nonce                argUint64         `json:"nonce"`
+	GasPrice             argBig            `json:"gasPrice"`
+	MaxPriorityFeePerGas *argBig           `json:"maxPriorityFeePerGas,omitempty"`
+	MaxFeePerGas         *argBig           `json:"maxFeePerGas,omitempty"`
+	Gas                  argUint64         `json:"gas"`
+	To                   *types.Address    `json:"to"`
+	Value                argBig            `json:"value"`
+	Input                argBytes          `json:"input"`
+	ChainID              *argBig           `json:"chainId,omitempty"`
+	AccessList           *types.AccessList `json:"accessList,omitempty"`
+	V                    argBig            `json:"v"`
+	R                    argBig            `json:"r"`
+	S                    argBig            `json:"s"`
+	Hash                 types.Hash        `json:"hash"`
+	From                 types.Address     `json:"from"`
+	BlockHash            *types.Hash       `json:"blockHash"`
+	BlockNumber          *argUint64        `json:"blockNumber"`
+	TxIndex              *argUint64        `json:"transactionIndex"`
 }
 
 func (t transaction) getHash() types.Hash { return t.Hash }
@@ -43,18 +50,23 @@ func (h transactionHash) MarshalText() ([]byte, error) {
 }
 
 func toPendingTransaction(t *types.Transaction) *transaction {
-	return toTransaction(t, nil, nil, nil)
+	return toTransaction(t, nil, nil, nil, nil)
 }
 
+// toTransaction converts the transaction to its json-rpc representation.
+// The gas price of the dynamic fee transactions is the effective gas price in the block
+// with the passed in base fee, or the maximum fee per gas if the base fee isn't known
 func toTransaction(
 	t *types.Transaction,
+	baseFee *big.Int,
 	blockNumber *argUint64,
 	blockHash *types.Hash,
 	txIndex *int,
 ) *transaction {
 	res := &transaction{
+		Type:     argUint64(t.Type),
 		Nonce:    argUint64(t.Nonce),
-		GasPrice: argBig(*t.GasPrice),
+		GasPrice: argBig(*t.EffectiveGasPrice(baseFee)),
 		Gas:      argUint64(t.Gas),
 		To:       t.To,
 		Value:    argBig(*t.Value),
@@ -66,6 +78,21 @@ func toTransaction(
 		From:     t.From,
 	}
 
+	if t.Type != types.LegacyTx {
+		accessList := t.AccessList.Copy()
+		if accessList == nil {
+			accessList = types.AccessList{}
+		}
+
+		res.ChainID = argBigPtr(t.ChainID)
+		res.AccessList = &accessList
+	}
+
+	if t.IsDynamicFee() {
+		res.MaxPriorityFeePerGas = argBigPtr(t.MaxPriorityFeePerGas)
+		res.MaxFeePerGas = argBigPtr(t.MaxFeePerGas)
+	}
+
 	if blockNumber != nil {
 		res.BlockNumber = blockNumber
 	}
@@ -100,6 +127,7 @@ type block struct {
 	MixHash         types.Hash          `json:"mixHash"`
 	Nonce           types.Nonce         `json:"nonce"`
 	Hash            types.Hash          `json:"hash"`
+	BaseFee         *argUint64          `json:"baseFeePerGas,omitempty"`
 	Transactions    []transactionOrHash `json:"transactions"`
 	Uncles          []types.Hash        `json:"uncles"`
 }
@@ -129,12 +157,17 @@ func toBlock(b *types.Block, fullTx bool) *block {
 		Uncles:          []types.Hash{},
 	}
 
+	if h.BaseFee != 0 {
+		res.BaseFee = argUintPtr(h.BaseFee)
+	}
+
 	for idx, txn := range b.Transactions {
 		if fullTx {
 			res.Transactions = append(
 				res.Transactions,
 				toTransaction(
 					txn,
+					h.GetBaseFee(),
 					argUintPtr(b.Number()),
 					argHashPtr(b.Hash()),
 					&idx,
@@ -155,6 +188,11 @@ func toBlock(b *types.Block, fullTx bool) *block {
 	return res
 }
 
+// MarshalBlock encodes the block in the JSON format of eth_getBlockByNumber, with the full transactions
+func MarshalBlock(b *types.Block) ([]byte, error) {
+	return json.Marshal(toBlock(b, true))
+}
+
 type receipt struct {
 	Root              types.Hash     `json:"root"`
 	CumulativeGasUsed argUint64      `json:"cumulativeGasUsed"`
@@ -166,9 +204,11 @@ type receipt struct {
 	BlockHash         types.Hash     `json:"blockHash"`
 	BlockNumber       argUint64      `json:"blockNumber"`
 	GasUsed           argUint64      `json:"gasUsed"`
-	ContractAddress   types.Address  `json:"contractAddress"`
+	EffectiveGasPrice argBig         `json:"effectiveGasPrice"`
+	ContractAddress   *types.Address `json:"contractAddress"`
 	FromAddr          types.Address  `json:"from"`
 	ToAddr            *types.Address `json:"to"`
+	Type              argUint64      `json:"type"`
 }
 
 type Log struct {
@@ -302,6 +342,100 @@ type txnArgs struct {
 	Data     *argBytes
 	Input    *argBytes
 	Nonce    *argUint64
+
+	AccessList *types.AccessList
+}
+
+// accessListResult is the access list of a call, along with the gas the call uses with it
+type accessListResult struct {
+	AccessList types.AccessList `json:"accessList"`
+	Error      string           `json:"error,omitempty"`
+	GasUsed    argUint64        `json:"gasUsed"`
+}
+
+// stateOverride replaces the fields of an account for the duration of a call
+type stateOverride struct {
+	Nonce     *argUint64                `json:"nonce"`
+	Balance   *argBig                   `json:"balance"`
+	Code      *argBytes                 `json:"code"`
+	State     map[types.Hash]types.Hash `json:"state"`
+	StateDiff map[types.Hash]types.Hash `json:"stateDiff"`
+}
+
+// stateOverrideSet are the accounts overridden for a call
+type stateOverrideSet map[types.Address]stateOverride
+
+// toOverrideSet converts the accounts overridden for a call to the state overrides
+func (s stateOverrideSet) toOverrideSet() state.OverrideSet {
+	if s == nil {
+		return nil
+	}
+
+	overrides := state.OverrideSet{}
+
+	for addr, account := range s {
+		override := &state.Override{
+			State:     account.State,
+			StateDiff: account.StateDiff,
+		}
+
+		if account.Nonce != nil {
+			nonce := uint64(*account.Nonce)
+			override.Nonce = &nonce
+		}
+
+		if account.Balance != nil {
+			override.Balance = (*big.Int)(account.Balance)
+		}
+
+		if account.Code != nil {
+			override.Code = []byte(*account.Code)
+		}
+
+		overrides[addr] = override
+	}
+
+	return overrides
+}
+
+// overrideNonce sets the nonce of the call to the overridden nonce of the sender, unless it is set
+func (s stateOverrideSet) overrideNonce(arg *txnArgs) {
+	from := types.ZeroAddress
+	if arg.From != nil {
+		from = *arg.From
+	}
+
+	if account, ok := s[from]; ok && account.Nonce != nil && arg.Nonce == nil {
+		arg.From = &from
+		arg.Nonce = account.Nonce
+	}
+}
+
+// accountProof is the merkle proof of an account and of its storage slots (EIP-1186)
+type accountProof struct {
+	Address      types.Address  `json:"address"`
+	AccountProof []argBytes     `json:"accountProof"`
+	Balance      argBig         `json:"balance"`
+	CodeHash     types.Hash     `json:"codeHash"`
+	Nonce        argUint64      `json:"nonce"`
+	StorageHash  types.Hash     `json:"storageHash"`
+	StorageProof []storageProof `json:"storageProof"`
+}
+
+// storageProof is the merkle proof of a storage slot in the storage trie of the account
+type storageProof struct {
+	Key   types.Hash `json:"key"`
+	Value argBig     `json:"value"`
+	Proof []argBytes `json:"proof"`
+}
+
+func toArgBytesList(list [][]byte) []argBytes {
+	res := make([]argBytes, 0, len(list))
+	for _, b := range list {
+		res = append(res, argBytes(b))
+	}
+
+	return res
 }
 
 type progression struct {
@@ -309,4 +443,6 @@ type progression struct {
 	StartingBlock string `json:"startingBlock"`
 	CurrentBlock  string `json:"currentBlock"`
 	HighestBlock  string `json:"highestBlock"`
+	KnownStates   string `json:"knownStates"`
+	PulledStates  string `json:"pulledStates"`
 }
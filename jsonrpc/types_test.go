@@ -115,7 +115,7 @@ func TestToTransaction_Returns_V_R_S_ValuesWithoutLeading0(t *testing.T) {
 		From:     types.Address{},
 	}
 
-	jsonTx := toTransaction(&txn, nil, nil, nil)
+	jsonTx := toTransaction(&txn, nil, nil, nil, nil)
 
 	jsonV, _ := jsonTx.V.MarshalText()
 	jsonR, _ := jsonTx.R.MarshalText()
@@ -125,3 +125,52 @@ func TestToTransaction_Returns_V_R_S_ValuesWithoutLeading0(t *testing.T) {
 	assert.Equal(t, hexWithoutLeading0, string(jsonR))
 	assert.Equal(t, hexWithoutLeading0, string(jsonS))
 }
+
+func TestToTransaction_DynamicFee(t *testing.T) {
+	txn := types.Transaction{
+		Type:                 types.DynamicFeeTx,
+		ChainID:              big.NewInt(100),
+		MaxPriorityFeePerGas: big.NewInt(2),
+		MaxFeePerGas:         big.NewInt(10),
+		Value:                big.NewInt(0),
+		V:                    big.NewInt(1),
+		R:                    big.NewInt(2),
+		S:                    big.NewInt(3),
+	}
+
+	// the gas price of the pending transactions is the maximum fee per gas
+	jsonTx := toPendingTransaction(&txn)
+	assert.Equal(t, argUint64(types.DynamicFeeTx), jsonTx.Type)
+	assert.Equal(t, argBig(*big.NewInt(10)), jsonTx.GasPrice)
+	assert.Equal(t, argBigPtr(big.NewInt(2)), jsonTx.MaxPriorityFeePerGas)
+	assert.Equal(t, argBigPtr(big.NewInt(10)), jsonTx.MaxFeePerGas)
+	assert.Equal(t, argBigPtr(big.NewInt(100)), jsonTx.ChainID)
+	assert.Equal(t, &types.AccessList{}, jsonTx.AccessList)
+
+	// and the effective gas price once sealed in a block
+	jsonTx = toTransaction(&txn, big.NewInt(5), nil, nil, nil)
+	assert.Equal(t, argBig(*big.NewInt(7)), jsonTx.GasPrice)
+}
+
+func TestToTransaction_AccessList(t *testing.T) {
+	accessList := types.AccessList{{Address: types.StringToAddress("1"), StorageKeys: []types.Hash{}}}
+	txn := types.Transaction{
+		Type:       types.AccessListTx,
+		ChainID:    big.NewInt(100),
+		GasPrice:   big.NewInt(10),
+		AccessList: accessList,
+		Value:      big.NewInt(0),
+		V:          big.NewInt(1),
+		R:          big.NewInt(2),
+		S:          big.NewInt(3),
+	}
+
+	// the access list transactions pay their gas price
+	jsonTx := toTransaction(&txn, big.NewInt(5), nil, nil, nil)
+	assert.Equal(t, argUint64(types.AccessListTx), jsonTx.Type)
+	assert.Equal(t, argBig(*big.NewInt(10)), jsonTx.GasPrice)
+	assert.Equal(t, argBigPtr(big.NewInt(100)), jsonTx.ChainID)
+	assert.Equal(t, &accessList, jsonTx.AccessList)
+	assert.Nil(t, jsonTx.MaxPriorityFeePerGas)
+	assert.Nil(t, jsonTx.MaxFeePerGas)
+}
@@ -3,6 +3,7 @@ package network
 import (
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/secrets"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/multiformats/go-multiaddr"
 	"net"
 )
@@ -12,6 +13,7 @@ type Config struct {
 	NoDiscover       bool                   // flag indicating if the discovery mechanism should be turned on
 	Addr             *net.TCPAddr           // the base address
 	NatAddr          net.IP                 // the NAT address
+	NATMode          NATMode                // the port mapping on the gateway, none if not set
 	DNS              multiaddr.Multiaddr    // the DNS address
 	DataDir          string                 // the base data directory for the client
 	MaxPeers         int64                  // the maximum number of peer connections
@@ -20,6 +22,10 @@ type Config struct {
 	Chain            *chain.Chain           // the reference to the chain configuration
 	SecretsManager   secrets.SecretsManager // the secrets manager used for key storage
 	Metrics          *Metrics               // the metrics reporting reference
+	Scoring          *ScoringConfig         // the peer reputation params, the defaults are used if nil
+	StaticPeers      []*peer.AddrInfo       // the peers the node keeps connected with at all times
+	TrustedPeers     []peer.ID              // the peers exempt from the peer limits and the banning
+	DiscoveryDNS     string                 // the domain of the DNS bootnode list, the chain one is used if not set
 }
 
 func DefaultConfig() *Config {
@@ -36,5 +42,6 @@ func DefaultConfig() *Config {
 		// The default ratio for outbound / inbound connections is 0.25
 		MaxInboundPeers:  32,
 		MaxOutboundPeers: 8,
+		Scoring:          DefaultScoringConfig(),
 	}
 }
@@ -0,0 +1,263 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	polyCrypto "github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/enode"
+	"github.com/0xPolygon/polygon-edge/network/common"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+)
+
+const (
+	// dnsDiscoveryInterval is the interval the DNS bootnode list is re-resolved at
+	dnsDiscoveryInterval = 10 * time.Minute
+
+	// dnsResolveTimeout is the time the resolution of the DNS bootnode list has to complete
+	dnsResolveTimeout = 30 * time.Second
+
+	// maxDNSTreeDepth is the max number of the subdomain links followed from the domain
+	maxDNSTreeDepth = 4
+
+	dnsAddrPrefix       = "dnsaddr="
+	dnsTreeRootPrefix   = "enrtree-root:"
+	dnsTreeBranchPrefix = "enrtree-branch:"
+	enodePrefix         = "enode://"
+)
+
+var (
+	ErrNoDNSBootnodes = errors.New("no bootnodes found in the DNS records")
+)
+
+// txtResolver resolves the TXT records of the domains
+type txtResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// discoveryDNS returns the domain of the DNS bootnode list, if any
+func (s *Server) discoveryDNS() string {
+	if s.config.DiscoveryDNS != "" {
+		return s.config.DiscoveryDNS
+	}
+
+	if s.config.Chain != nil {
+		return s.config.Chain.DiscoveryDNS
+	}
+
+	return ""
+}
+
+// setupDNSBootnodes resolves the DNS bootnode list at startup, if set.
+// The resolution failure is only fatal when no other bootnodes are configured
+func (s *Server) setupDNSBootnodes() ([]*peer.AddrInfo, error) {
+	domain := s.discoveryDNS()
+	if domain == "" {
+		return nil, nil
+	}
+
+	bootnodes, err := s.resolveDNSBootnodes(domain)
+	if err != nil {
+		if len(s.config.Chain.Bootnodes) == 0 {
+			return nil, fmt.Errorf("unable to resolve the DNS bootnodes, %w", err)
+		}
+
+		s.logger.Warn("Unable to resolve the DNS bootnodes, using the configured bootnodes", "domain", domain, "err", err)
+
+		return nil, nil
+	}
+
+	s.logger.Info("DNS bootnodes resolved", "domain", domain, "count", len(bootnodes))
+
+	return bootnodes, nil
+}
+
+// resolveDNSBootnodes resolves the bootnode list published in the TXT records of the domain.
+// A record is a libp2p multiaddr, optionally prefixed with dnsaddr=, or an enode URL.
+// Like the EIP-1459 trees, the enrtree-root: and the enrtree-branch: records link
+// the subdomains holding more records. The signatures of the tree aren't verified
+func (s *Server) resolveDNSBootnodes(domain string) ([]*peer.AddrInfo, error) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), dnsResolveTimeout)
+	defer cancelFn()
+
+	nodes := make(map[peer.ID]*peer.AddrInfo)
+
+	if err := s.resolveDNSTree(ctx, domain, domain, 0, nodes); err != nil {
+		return nil, err
+	}
+
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("%w %s", ErrNoDNSBootnodes, domain)
+	}
+
+	bootnodes := make([]*peer.AddrInfo, 0, len(nodes))
+	for _, node := range nodes {
+		bootnodes = append(bootnodes, node)
+	}
+
+	return bootnodes, nil
+}
+
+// resolveDNSTree adds the bootnodes in the TXT records of the name, and of its linked subdomains, to the nodes
+func (s *Server) resolveDNSTree(
+	ctx context.Context,
+	domain string,
+	name string,
+	depth int,
+	nodes map[peer.ID]*peer.AddrInfo,
+) error {
+	records, err := s.dnsResolver.LookupTXT(ctx, name)
+	if err != nil {
+		return fmt.Errorf("unable to resolve %s, %w", name, err)
+	}
+
+	for _, record := range records {
+		var links []string
+
+		switch {
+		case strings.HasPrefix(record, dnsTreeRootPrefix):
+			links = parseDNSTreeRoot(record)
+		case strings.HasPrefix(record, dnsTreeBranchPrefix):
+			links = strings.Split(strings.TrimPrefix(record, dnsTreeBranchPrefix), ",")
+		default:
+			node, parseErr := parseDNSBootnode(record)
+			if parseErr != nil {
+				s.logger.Warn("Skipping the invalid DNS bootnode record", "name", name, "record", record, "err", parseErr)
+
+				continue
+			}
+
+			if node.ID != s.host.ID() {
+				nodes[node.ID] = node
+			}
+
+			continue
+		}
+
+		if depth >= maxDNSTreeDepth {
+			s.logger.Warn("Skipping the DNS tree links past the max depth", "name", name, "depth", depth)
+
+			continue
+		}
+
+		for _, link := range links {
+			if link = strings.TrimSpace(link); link == "" {
+				continue
+			}
+
+			// the links are relative to the domain of the tree,
+			// the bootnodes of the other subdomains are kept if one can't be resolved
+			if err := s.resolveDNSTree(ctx, domain, link+"."+domain, depth+1, nodes); err != nil {
+				s.logger.Warn("Skipping the unresolved DNS tree link", "name", name, "err", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseDNSTreeRoot returns the subdomain the tree root links the entries at
+func parseDNSTreeRoot(record string) []string {
+	for _, field := range strings.Fields(strings.TrimPrefix(record, dnsTreeRootPrefix)) {
+		if strings.HasPrefix(field, "e=") {
+			return []string{strings.TrimPrefix(field, "e=")}
+		}
+	}
+
+	return nil
+}
+
+// parseDNSBootnode parses the bootnode of the TXT record, a libp2p multiaddr or an enode URL
+func parseDNSBootnode(record string) (*peer.AddrInfo, error) {
+	record = strings.TrimSpace(record)
+
+	if strings.HasPrefix(record, enodePrefix) {
+		return parseEnode(record)
+	}
+
+	return common.StringToAddrInfo(strings.TrimPrefix(record, dnsAddrPrefix))
+}
+
+// parseEnode converts the enode URL to the libp2p address of the node.
+// The node ID of the enode is the secp256k1 public key, like the libp2p keys of the nodes
+func parseEnode(rawURL string) (*peer.AddrInfo, error) {
+	node, err := enode.ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := node.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	libp2pPub, err := crypto.UnmarshalSecp256k1PublicKey(polyCrypto.MarshalPublicKey(pub))
+	if err != nil {
+		return nil, err
+	}
+
+	peerID, err := peer.IDFromPublicKey(libp2pPub)
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := manet.FromNetAddr(&net.TCPAddr{IP: node.IP, Port: int(node.TCP)})
+	if err != nil {
+		return nil, err
+	}
+
+	return &peer.AddrInfo{
+		ID:    peerID,
+		Addrs: []multiaddr.Multiaddr{addr},
+	}, nil
+}
+
+// runDNSDiscovery periodically re-resolves the DNS bootnode list,
+// and adds the new bootnodes to the routing table of the discovery service, to be dialed
+func (s *Server) runDNSDiscovery(domain string, known []*peer.AddrInfo) {
+	knownNodes := make(map[peer.ID]struct{}, len(known))
+	for _, node := range known {
+		knownNodes[node.ID] = struct{}{}
+	}
+
+	for {
+		select {
+		case <-time.After(dnsDiscoveryInterval):
+		case <-s.closeCh:
+			return
+		}
+
+		nodes, err := s.resolveDNSBootnodes(domain)
+		if err != nil {
+			s.logger.Warn("Unable to re-resolve the DNS bootnodes", "domain", domain, "err", err)
+
+			continue
+		}
+
+		newNodes := make([]*peer.AddrInfo, 0)
+
+		for _, node := range nodes {
+			if _, ok := knownNodes[node.ID]; ok {
+				continue
+			}
+
+			knownNodes[node.ID] = struct{}{}
+			newNodes = append(newNodes, node)
+		}
+
+		if len(newNodes) == 0 {
+			continue
+		}
+
+		s.logger.Info("New DNS bootnodes resolved", "domain", domain, "count", len(newNodes))
+
+		s.discovery.ConnectToBootnodes(newNodes)
+	}
+}
@@ -0,0 +1,264 @@
+package network
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/helper/tests"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+var errNoTXTRecords = errors.New("no such host")
+
+// mockTXTResolver resolves the TXT records of the domains from the map
+type mockTXTResolver map[string][]string
+
+func (m mockTXTResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	records, ok := m[name]
+	if !ok {
+		return nil, errNoTXTRecords
+	}
+
+	return records, nil
+}
+
+// generateTestEnode returns the enode URL of a new key, and the peer ID of the key
+func generateTestEnode(t *testing.T) (string, peer.ID) {
+	t.Helper()
+
+	_, pub, err := crypto.GenerateKeyPair(crypto.Secp256k1, 256)
+	if err != nil {
+		t.Fatalf("Unable to generate key pair, %v", err)
+	}
+
+	peerID, err := peer.IDFromPublicKey(pub)
+	if err != nil {
+		t.Fatalf("Unable to get the peer ID, %v", err)
+	}
+
+	rawKey := (*btcec.PublicKey)(pub.(*crypto.Secp256k1PublicKey)).SerializeUncompressed()
+
+	return fmt.Sprintf("enode://%s@127.0.0.1:30303", hex.EncodeToString(rawKey[1:])), peerID
+}
+
+func TestParseDNSBootnode(t *testing.T) {
+	multiAddr := tests.GenerateTestMultiAddr(t)
+	enode, enodeID := generateTestEnode(t)
+
+	testTable := []struct {
+		name         string
+		record       string
+		expectedAddr string
+		expectedID   string
+		shouldFail   bool
+	}{
+		{
+			"multiaddr",
+			multiAddr.String(),
+			multiAddr.String(),
+			"",
+			false,
+		},
+		{
+			"dnsaddr multiaddr",
+			dnsAddrPrefix + multiAddr.String(),
+			multiAddr.String(),
+			"",
+			false,
+		},
+		{
+			"enode",
+			enode,
+			fmt.Sprintf("/ip4/127.0.0.1/tcp/30303/p2p/%s", enodeID),
+			enodeID.String(),
+			false,
+		},
+		{
+			"enode without port",
+			"enode://" + hex.EncodeToString(make([]byte, 64)) + "@127.0.0.1",
+			"",
+			"",
+			true,
+		},
+		{
+			"enode with short key",
+			"enode://abcd@127.0.0.1:30303",
+			"",
+			"",
+			true,
+		},
+		{
+			"invalid record",
+			"v=spf1 -all",
+			"",
+			"",
+			true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			node, err := parseDNSBootnode(testCase.record)
+			if testCase.shouldFail {
+				assert.Error(t, err)
+
+				return
+			}
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			if testCase.expectedID != "" {
+				assert.Equal(t, testCase.expectedID, node.ID.String())
+			}
+
+			addrs, err := peer.AddrInfoToP2pAddrs(node)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedAddr, addrs[0].String())
+		})
+	}
+}
+
+func TestResolveDNSBootnodes(t *testing.T) {
+	rootAddr := tests.GenerateTestMultiAddr(t)
+	branchAddr := tests.GenerateTestMultiAddr(t)
+	deepAddr := tests.GenerateTestMultiAddr(t)
+	enode, enodeID := generateTestEnode(t)
+
+	resolver := mockTXTResolver{
+		"nodes.example.org": {
+			"enrtree-root:v1 e=ROOT l=LINKS seq=1 sig=SIG",
+			dnsAddrPrefix + rootAddr.String(),
+			"not a bootnode",
+		},
+		"ROOT.nodes.example.org": {
+			"enrtree-branch:A,B,MISSING",
+		},
+		"A.nodes.example.org": {
+			branchAddr.String(),
+		},
+		"B.nodes.example.org": {
+			enode,
+			"enrtree-branch:C",
+		},
+		"C.nodes.example.org": {
+			"enrtree-branch:D",
+		},
+		"D.nodes.example.org": {
+			// past the max depth
+			"enrtree-branch:E",
+		},
+		"E.nodes.example.org": {
+			deepAddr.String(),
+		},
+	}
+
+	server, createErr := CreateServer(&CreateServerParams{
+		ServerCallback: func(server *Server) {
+			server.dnsResolver = resolver
+		},
+	})
+	if createErr != nil {
+		t.Fatalf("Unable to create server, %v", createErr)
+	}
+
+	t.Cleanup(func() {
+		assert.NoError(t, server.Close())
+	})
+
+	bootnodes, err := server.resolveDNSBootnodes("nodes.example.org")
+	assert.NoError(t, err)
+
+	resolvedIDs := make(map[peer.ID]struct{})
+	for _, node := range bootnodes {
+		resolvedIDs[node.ID] = struct{}{}
+	}
+
+	for _, addr := range []string{rootAddr.String(), branchAddr.String()} {
+		info, err := peer.AddrInfoFromString(addr)
+		assert.NoError(t, err)
+
+		assert.Contains(t, resolvedIDs, info.ID)
+	}
+
+	assert.Contains(t, resolvedIDs, enodeID)
+	assert.Len(t, resolvedIDs, 3)
+
+	// the domain without the bootnodes fails
+	_, err = server.resolveDNSBootnodes("ROOT.nodes.example.org")
+	assert.ErrorIs(t, err, ErrNoDNSBootnodes)
+
+	_, err = server.resolveDNSBootnodes("unknown.example.org")
+	assert.ErrorIs(t, err, errNoTXTRecords)
+}
+
+func TestSetupDNSBootnodes(t *testing.T) {
+	testAddr := tests.GenerateTestMultiAddr(t)
+
+	testTable := []struct {
+		name       string
+		bootnodes  []string
+		records    []string
+		shouldFail bool
+	}{
+		{
+			"DNS bootnodes only",
+			nil,
+			[]string{testAddr.String()},
+			false,
+		},
+		{
+			"unresolved DNS with the configured bootnodes",
+			[]string{testAddr.String()},
+			nil,
+			false,
+		},
+		{
+			"unresolved DNS without the configured bootnodes",
+			nil,
+			nil,
+			true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			resolver := mockTXTResolver{}
+			if testCase.records != nil {
+				resolver["nodes.example.org"] = testCase.records
+			}
+
+			server, createErr := CreateServer(&CreateServerParams{
+				ServerCallback: func(server *Server) {
+					server.config.DiscoveryDNS = "nodes.example.org"
+					server.config.Chain.Bootnodes = testCase.bootnodes
+					server.dnsResolver = resolver
+				},
+			})
+
+			if testCase.shouldFail {
+				assert.Error(t, createErr)
+
+				// the discovery isn't set up when the server fails to start
+				assert.NoError(t, server.host.Close())
+
+				return
+			}
+
+			assert.NoError(t, createErr)
+
+			t.Cleanup(func() {
+				assert.NoError(t, server.Close())
+			})
+
+			assert.Len(t, server.bootnodes.getBootnodes(), 1)
+		})
+	}
+}
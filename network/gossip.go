@@ -5,6 +5,7 @@ import (
 	"reflect"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"google.golang.org/protobuf/proto"
 )
@@ -16,12 +17,31 @@ const (
 	subscribeOutputBufferSize = 1024
 )
 
+// ValidationResult is the result of the validation of a topic message
+type ValidationResult int
+
+const (
+	// ValidationAccept delivers the message to the subscribers and relays it to other peers
+	ValidationAccept ValidationResult = iota
+
+	// ValidationIgnore drops the message, like a duplicate one
+	ValidationIgnore
+
+	// ValidationReject drops the message as a protocol violation,
+	// and penalizes the peer the message was received from
+	ValidationReject
+)
+
 type Topic struct {
 	logger hclog.Logger
 
+	ps      *pubsub.PubSub
 	topic   *pubsub.Topic
 	typ     reflect.Type
 	closeCh chan struct{}
+
+	// penalize penalizes the peers that relay invalid messages
+	penalize func(peer.ID, PeerPenalty)
 }
 
 func (t *Topic) createObj() proto.Message {
@@ -53,6 +73,36 @@ func (t *Topic) Subscribe(handler func(obj interface{})) error {
 	return nil
 }
 
+// RegisterValidator registers a validator for the topic messages.
+// Messages the validator doesn't accept are neither delivered to the subscribers nor relayed to other peers,
+// the peer the rejected messages are received from is penalized.
+// The validator is passed in the ID of the peer that published the message
+func (t *Topic) RegisterValidator(validator func(from peer.ID, obj interface{}) ValidationResult) error {
+	return t.ps.RegisterTopicValidator(
+		t.topic.String(),
+		func(_ context.Context, receivedFrom peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+			obj := t.createObj()
+			if err := proto.Unmarshal(msg.Data, obj); err != nil {
+				t.logger.Error("failed to unmarshal topic", "err", err)
+				t.penalize(receivedFrom, PenaltyMalformedGossip)
+
+				return pubsub.ValidationReject
+			}
+
+			switch validator(msg.GetFrom(), obj) {
+			case ValidationAccept:
+				return pubsub.ValidationAccept
+			case ValidationReject:
+				t.penalize(receivedFrom, PenaltyInvalidMessage)
+
+				return pubsub.ValidationReject
+			default:
+				return pubsub.ValidationIgnore
+			}
+		},
+	)
+}
+
 func (t *Topic) readLoop(sub *pubsub.Subscription, handler func(obj interface{})) {
 	ctx, cancelFn := context.WithCancel(context.Background())
 
@@ -73,6 +123,7 @@ func (t *Topic) readLoop(sub *pubsub.Subscription, handler func(obj interface{})
 			obj := t.createObj()
 			if err := proto.Unmarshal(msg.Data, obj); err != nil {
 				t.logger.Error("failed to unmarshal topic", "err", err)
+				t.penalize(msg.ReceivedFrom, PenaltyMalformedGossip)
 
 				return
 			}
@@ -89,9 +140,11 @@ func (s *Server) NewTopic(protoID string, obj proto.Message) (*Topic, error) {
 	}
 
 	tt := &Topic{
-		logger: s.logger.Named(protoID),
-		topic:  topic,
-		typ:    reflect.TypeOf(obj).Elem(),
+		logger:   s.logger.Named(protoID),
+		ps:       s.ps,
+		topic:    topic,
+		typ:      reflect.TypeOf(obj).Elem(),
+		penalize: s.PenalizePeer,
 	}
 
 	return tt, nil
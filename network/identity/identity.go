@@ -17,6 +17,7 @@ const PeerID = "peerID"
 
 var (
 	ErrInvalidChainID   = errors.New("invalid chain ID")
+	ErrInvalidGenesis   = errors.New("invalid genesis hash")
 	ErrNoAvailableSlots = errors.New("no available Slots")
 )
 
@@ -51,6 +52,9 @@ type networkingServer interface {
 
 	// HasFreeConnectionSlot checks if there are available outbound connection slots [Thread safe]
 	HasFreeConnectionSlot(direction network.Direction) bool
+
+	// IsExemptFromPeerLimits checks if the connections of the peer are accepted over the peer limits [Thread safe]
+	IsExemptFromPeerLimits(peerID peer.ID) bool
 }
 
 // IdentityService is a networking service used to handle peer handshaking.
@@ -63,6 +67,7 @@ type IdentityService struct {
 	baseServer             networkingServer // The interface towards the base networking server
 
 	chainID int64   // The chain ID of the network
+	genesis string  // The hash of the genesis block, not compared if empty
 	hostID  peer.ID // The base networking server's host peer ID
 }
 
@@ -71,12 +76,14 @@ func NewIdentityService(
 	server networkingServer,
 	logger hclog.Logger,
 	chainID int64,
+	genesis string,
 	hostID peer.ID,
 ) *IdentityService {
 	return &IdentityService{
 		logger:     logger.Named("identity"),
 		baseServer: server,
 		chainID:    chainID,
+		genesis:    genesis,
 		hostID:     hostID,
 	}
 }
@@ -92,7 +99,8 @@ func (i *IdentityService) GetNotifyBundle() *network.NotifyBundle {
 				return
 			}
 
-			if !i.baseServer.HasFreeConnectionSlot(conn.Stat().Direction) {
+			if !i.baseServer.HasFreeConnectionSlot(conn.Stat().Direction) &&
+				!i.baseServer.IsExemptFromPeerLimits(peerID) {
 				i.disconnectFromPeer(peerID, ErrNoAvailableSlots.Error())
 
 				return
@@ -184,6 +192,19 @@ func (i *IdentityService) handleConnected(peerID peer.ID, direction network.Dire
 		return ErrInvalidChainID
 	}
 
+	// The peers running the same chain ID from different genesis files fork at the first block.
+	// The genesis hash is not sent by the older peers
+	if status.Genesis != "" && resp.Genesis != "" && status.Genesis != resp.Genesis {
+		i.logger.Error(
+			"the genesis of the peer doesn't match, check the genesis files",
+			"peer", peerID,
+			"local", status.Genesis,
+			"remote", resp.Genesis,
+		)
+
+		return fmt.Errorf("%w, local %s, remote %s", ErrInvalidGenesis, status.Genesis, resp.Genesis)
+	}
+
 	// If this is a NOT temporary connection, save it
 	if !resp.TemporaryDial && !status.TemporaryDial {
 		i.baseServer.AddPeer(peerID, direction)
@@ -212,6 +233,7 @@ func (i *IdentityService) constructStatus(peerID peer.ID) *proto.Status {
 			PeerID: i.hostID.Pretty(),
 		},
 		Chain:         i.chainID,
+		Genesis:       i.genesis,
 		TemporaryDial: i.baseServer.IsTemporaryDial(peerID),
 	}
 }
@@ -121,3 +121,54 @@ func TestHandshake_Errors(t *testing.T) {
 	// Make sure no peers have been  added to the base networking server
 	assert.Len(t, peersArray, 0)
 }
+
+// TestHandshake_Genesis tests the peer connections from different genesis files
+func TestHandshake_Genesis(t *testing.T) {
+	testTable := []struct {
+		name          string
+		localGenesis  string
+		remoteGenesis string
+		expectedErr   error
+	}{
+		{"same genesis", "0x1", "0x1", nil},
+		{"different genesis", "0x1", "0x2", ErrInvalidGenesis},
+		{"genesis not sent by the peer", "0x1", "", nil},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			peersArray := make([]peer.ID, 0)
+
+			identityService := newIdentityService(
+				func(server *networkTesting.MockNetworkingServer) {
+					server.HookAddPeer(func(id peer.ID, direction network.Direction) {
+						peersArray = append(peersArray, id)
+					})
+
+					server.GetMockIdentityClient().HookHello(func(
+						ctx context.Context,
+						in *proto.Status,
+						opts ...grpc.CallOption,
+					) (*proto.Status, error) {
+						return &proto.Status{
+							Genesis: testCase.remoteGenesis,
+						}, nil
+					})
+				},
+			)
+
+			identityService.genesis = testCase.localGenesis
+
+			connectErr := identityService.handleConnected("TestPeer", network.DirInbound)
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, connectErr, testCase.expectedErr)
+				assert.Len(t, peersArray, 0)
+
+				return
+			}
+
+			assert.NoError(t, connectErr)
+			assert.Len(t, peersArray, 1)
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package network
 
 import (
+	"crypto/sha256"
 	"encoding/hex"
 
+	polyCrypto "github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/libp2p/go-libp2p-core/crypto"
+	pb "github.com/libp2p/go-libp2p-core/crypto/pb"
 )
 
 // ReadLibp2pKey reads the private networking key from the secrets manager
@@ -46,3 +49,70 @@ func ParseLibp2pKey(key []byte) (crypto.PrivKey, error) {
 
 	return libp2pKey, nil
 }
+
+// kmsLibp2pKey is the networking private key held by the KMS of the secrets manager.
+// It signs like the secp256k1 libp2p keys, over the SHA-256 hash of the data
+type kmsLibp2pKey struct {
+	signer secrets.KeySigner
+	pub    crypto.PubKey
+}
+
+// NewKMSLibp2pKey returns the networking private key held by the KMS of the secrets manager
+func NewKMSLibp2pKey(signer secrets.KeySigner) (crypto.PrivKey, error) {
+	pub, err := signer.PublicKey(secrets.NetworkKey)
+	if err != nil {
+		return nil, err
+	}
+
+	libp2pPub, err := crypto.UnmarshalSecp256k1PublicKey(polyCrypto.MarshalPublicKey(pub))
+	if err != nil {
+		return nil, err
+	}
+
+	return &kmsLibp2pKey{
+		signer: signer,
+		pub:    libp2pPub,
+	}, nil
+}
+
+// Type implements the crypto.PrivKey interface method
+func (k *kmsLibp2pKey) Type() pb.KeyType {
+	return pb.KeyType_Secp256k1
+}
+
+// Raw implements the crypto.PrivKey interface method, the key material can't be exported from the KMS
+func (k *kmsLibp2pKey) Raw() ([]byte, error) {
+	return nil, secrets.ErrSecretNotExportable
+}
+
+// Equals implements the crypto.PrivKey interface method
+func (k *kmsLibp2pKey) Equals(o crypto.Key) bool {
+	other, ok := o.(crypto.PrivKey)
+	if !ok {
+		return false
+	}
+
+	return k.pub.Equals(other.GetPublic())
+}
+
+// Sign implements the crypto.PrivKey interface method
+func (k *kmsLibp2pKey) Sign(data []byte) ([]byte, error) {
+	hash := sha256.Sum256(data)
+
+	der, err := k.signer.SignDigest(secrets.NetworkKey, hash[:])
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := polyCrypto.NormalizeDERSignature(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return sig.Serialize(), nil
+}
+
+// GetPublic implements the crypto.PrivKey interface method
+func (k *kmsLibp2pKey) GetPublic() crypto.PubKey {
+	return k.pub
+}
@@ -0,0 +1,67 @@
+package network
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	polyCrypto "github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockKeySigner is a KMS signing the digests with the given key
+type mockKeySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (m *mockKeySigner) CreateKey(_ string) error {
+	return nil
+}
+
+func (m *mockKeySigner) PublicKey(_ string) (*ecdsa.PublicKey, error) {
+	return &m.key.PublicKey, nil
+}
+
+func (m *mockKeySigner) SignDigest(_ string, digest []byte) ([]byte, error) {
+	sig, err := (*btcec.PrivateKey)(m.key).Sign(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return sig.Serialize(), nil
+}
+
+func TestKMSLibp2pKey(t *testing.T) {
+	key, err := polyCrypto.GenerateKey()
+	assert.NoError(t, err)
+
+	kmsKey, err := NewKMSLibp2pKey(&mockKeySigner{key: key})
+	assert.NoError(t, err)
+
+	localKey := (*crypto.Secp256k1PrivateKey)(key)
+
+	// the node identity matches the one of the local key
+	kmsID, err := peer.IDFromPrivateKey(kmsKey)
+	assert.NoError(t, err)
+
+	localID, err := peer.IDFromPrivateKey(localKey)
+	assert.NoError(t, err)
+
+	assert.Equal(t, localID, kmsID)
+	assert.True(t, kmsKey.Equals(localKey))
+
+	// the signatures of the KMS are verified like the ones of the local key
+	data := []byte("handshake payload")
+
+	sig, err := kmsKey.Sign(data)
+	assert.NoError(t, err)
+
+	valid, err := localKey.GetPublic().Verify(data, sig)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	_, err = kmsKey.Raw()
+	assert.Error(t, err)
+}
@@ -0,0 +1,195 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-nat"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// NATMode is the way the libp2p port of the node behind a NAT is mapped on the gateway
+type NATMode string
+
+const (
+	// NATNone doesn't map the port, the external address can still be set by the operator
+	NATNone NATMode = "none"
+
+	// NATUPnP maps the port with the UPnP gateway protocol
+	NATUPnP NATMode = "upnp"
+
+	// NATPMP maps the port with the NAT-PMP gateway protocol
+	NATPMP NATMode = "pmp"
+)
+
+const (
+	// natDiscoveryTimeout is the time the gateway discovery has to complete
+	natDiscoveryTimeout = 10 * time.Second
+
+	// natMappingLease is the lifetime of the port mapping on the gateway
+	natMappingLease = 20 * time.Minute
+
+	// natRefreshInterval is the interval the port mapping lease is renewed at, before it expires
+	natRefreshInterval = natMappingLease / 2
+
+	natMappingDescription = "polygon-edge libp2p"
+)
+
+var (
+	errNoGateway      = errors.New("no gateway supporting the port mapping found")
+	errNoMappedPort   = errors.New("the gateway didn't map the port")
+	errNoExternalIPv4 = errors.New("the gateway has no external IPv4 address")
+	errUnknownNATMode = errors.New("unknown NAT mode")
+)
+
+// portMapping is the mapping of the libp2p listen port on the gateway,
+// and the external address it's reachable at
+type portMapping struct {
+	lock         sync.RWMutex
+	externalAddr multiaddr.Multiaddr
+}
+
+// addr returns the external address of the mapped port, nil if the port isn't mapped
+func (m *portMapping) addr() multiaddr.Multiaddr {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.externalAddr
+}
+
+// setAddr sets the external address of the mapped port, and returns whether it changed
+func (m *portMapping) setAddr(addr multiaddr.Multiaddr) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	changed := m.externalAddr == nil || !m.externalAddr.Equal(addr)
+	m.externalAddr = addr
+
+	return changed
+}
+
+// newAddrsFactory returns the libp2p address factory, that replaces the listen addresses
+// with the external address set by the operator, or adds the external address of the mapped port
+func newAddrsFactory(config *Config, mapping *portMapping) func([]multiaddr.Multiaddr) []multiaddr.Multiaddr {
+	return func(addrs []multiaddr.Multiaddr) []multiaddr.Multiaddr {
+		if config.NatAddr != nil {
+			addr, _ := multiaddr.NewMultiaddr(fmt.Sprintf("/ip4/%s/tcp/%d", config.NatAddr.String(), config.Addr.Port))
+
+			if addr != nil {
+				addrs = []multiaddr.Multiaddr{addr}
+			}
+		} else if config.DNS != nil {
+			addrs = []multiaddr.Multiaddr{config.DNS}
+		} else if addr := mapping.addr(); addr != nil {
+			// the external address is dialed first by the peers
+			addrs = append([]multiaddr.Multiaddr{addr}, addrs...)
+		}
+
+		return addrs
+	}
+}
+
+// isGatewayOfMode checks if the discovered gateway speaks the protocol of the mode
+func isGatewayOfMode(gateway nat.NAT, mode NATMode) bool {
+	switch mode {
+	case NATUPnP:
+		return strings.HasPrefix(gateway.Type(), "UPNP")
+	case NATPMP:
+		return gateway.Type() == "NAT-PMP"
+	default:
+		return false
+	}
+}
+
+// discoverGateway returns the first gateway on the local network that speaks the protocol of the mode
+func discoverGateway(mode NATMode) (nat.NAT, error) {
+	if mode != NATUPnP && mode != NATPMP {
+		return nil, fmt.Errorf("%w %s", errUnknownNATMode, mode)
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), natDiscoveryTimeout)
+	defer cancelFn()
+
+	for gateway := range nat.DiscoverNATs(ctx) {
+		if isGatewayOfMode(gateway, mode) {
+			return gateway, nil
+		}
+	}
+
+	return nil, errNoGateway
+}
+
+// mapPort maps the libp2p listen port on the gateway, or renews the lease of the existing mapping,
+// and returns the external address of the mapped port
+func mapPort(gateway nat.NAT, port int) (multiaddr.Multiaddr, error) {
+	externalPort, err := gateway.AddPortMapping("tcp", port, natMappingDescription, natMappingLease)
+	if err != nil {
+		return nil, err
+	}
+
+	if externalPort == 0 {
+		return nil, errNoMappedPort
+	}
+
+	externalIP, err := gateway.GetExternalAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	if externalIP.To4() == nil {
+		return nil, errNoExternalIPv4
+	}
+
+	return multiaddr.NewMultiaddr(fmt.Sprintf("/ip4/%s/tcp/%d", externalIP.String(), externalPort))
+}
+
+// runPortMapping maps the libp2p listen port on the gateway and keeps the mapping leased,
+// so the node is dialable from outside of the local network. The node keeps running
+// without the mapping if the gateway doesn't support it
+func (s *Server) runPortMapping() {
+	if s.config.NATMode == "" || s.config.NATMode == NATNone {
+		return
+	}
+
+	gateway, err := discoverGateway(s.config.NATMode)
+	if err != nil {
+		s.logger.Warn(
+			"Unable to map the libp2p port on the gateway, the node may not be dialable from outside of the local network",
+			"nat", s.config.NATMode,
+			"err", err,
+		)
+
+		return
+	}
+
+	s.logger.Info("Gateway discovered", "type", gateway.Type())
+
+	for {
+		addr, err := mapPort(gateway, s.config.Addr.Port)
+		if err != nil {
+			s.logger.Warn("Unable to map the libp2p port on the gateway", "type", gateway.Type(), "err", err)
+		} else if s.portMapping.setAddr(addr) {
+			s.logger.Info("Mapped the libp2p port on the gateway", "addr", addr.String())
+		}
+
+		select {
+		case <-time.After(natRefreshInterval):
+		case <-s.closeCh:
+			if err := gateway.DeletePortMapping("tcp", s.config.Addr.Port); err != nil {
+				s.logger.Debug("Unable to delete the port mapping", "err", err)
+			}
+
+			return
+		}
+	}
+}
+
+// AdvertisedAddrs returns the addresses the node is advertised at to the other peers,
+// including the external address of the port mapped on the gateway [Thread safe]
+func (s *Server) AdvertisedAddrs() []multiaddr.Multiaddr {
+	return s.host.Addrs()
+}
@@ -0,0 +1,113 @@
+package network
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockGateway is a gateway that maps the ports to the fixed external port
+type mockGateway struct {
+	typ          string
+	externalIP   net.IP
+	externalPort int
+	mappingErr   error
+
+	leases []time.Duration
+}
+
+func (g *mockGateway) Type() string {
+	return g.typ
+}
+
+func (g *mockGateway) GetDeviceAddress() (net.IP, error) {
+	return net.ParseIP("192.168.0.1"), nil
+}
+
+func (g *mockGateway) GetExternalAddress() (net.IP, error) {
+	return g.externalIP, nil
+}
+
+func (g *mockGateway) GetInternalAddress() (net.IP, error) {
+	return net.ParseIP("192.168.0.2"), nil
+}
+
+func (g *mockGateway) AddPortMapping(_ string, _ int, _ string, timeout time.Duration) (int, error) {
+	g.leases = append(g.leases, timeout)
+
+	return g.externalPort, g.mappingErr
+}
+
+func (g *mockGateway) DeletePortMapping(_ string, _ int) error {
+	return nil
+}
+
+func TestMapPort(t *testing.T) {
+	gateway := &mockGateway{
+		typ:          "UPNP (IG2-IP1)",
+		externalIP:   net.ParseIP("1.2.3.4"),
+		externalPort: 40000,
+	}
+
+	addr, err := mapPort(gateway, DefaultLibp2pPort)
+	assert.NoError(t, err)
+	assert.Equal(t, "/ip4/1.2.3.4/tcp/40000", addr.String())
+	assert.Equal(t, []time.Duration{natMappingLease}, gateway.leases)
+
+	// the gateway without a mapped port
+	gateway.externalPort = 0
+
+	_, err = mapPort(gateway, DefaultLibp2pPort)
+	assert.ErrorIs(t, err, errNoMappedPort)
+
+	gateway.mappingErr = errors.New("not supported")
+
+	_, err = mapPort(gateway, DefaultLibp2pPort)
+	assert.Error(t, err)
+}
+
+func TestIsGatewayOfMode(t *testing.T) {
+	upnp := &mockGateway{typ: "UPNP (IG1-PPP1)"}
+	pmp := &mockGateway{typ: "NAT-PMP"}
+
+	assert.True(t, isGatewayOfMode(upnp, NATUPnP))
+	assert.False(t, isGatewayOfMode(upnp, NATPMP))
+	assert.True(t, isGatewayOfMode(pmp, NATPMP))
+	assert.False(t, isGatewayOfMode(pmp, NATUPnP))
+	assert.False(t, isGatewayOfMode(pmp, NATNone))
+
+	_, err := discoverGateway(NATNone)
+	assert.ErrorIs(t, err, errUnknownNATMode)
+}
+
+func TestAddrsFactory_PortMapping(t *testing.T) {
+	config := DefaultConfig()
+	mapping := &portMapping{}
+	factory := newAddrsFactory(config, mapping)
+
+	listenAddr, _ := multiaddr.NewMultiaddr("/ip4/192.168.0.2/tcp/1478")
+	externalAddr, _ := multiaddr.NewMultiaddr("/ip4/1.2.3.4/tcp/40000")
+
+	// the listen addresses are advertised until the port is mapped
+	assert.Equal(t, []multiaddr.Multiaddr{listenAddr}, factory([]multiaddr.Multiaddr{listenAddr}))
+
+	assert.True(t, mapping.setAddr(externalAddr))
+	assert.False(t, mapping.setAddr(externalAddr))
+
+	assert.Equal(
+		t,
+		[]multiaddr.Multiaddr{externalAddr, listenAddr},
+		factory([]multiaddr.Multiaddr{listenAddr}),
+	)
+
+	// the external address set by the operator takes precedence
+	config.NatAddr = net.ParseIP("5.6.7.8")
+
+	addrs := factory([]multiaddr.Multiaddr{listenAddr})
+	assert.Len(t, addrs, 1)
+	assert.Equal(t, "/ip4/5.6.7.8/tcp/1478", addrs[0].String())
+}
@@ -0,0 +1,334 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/control"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// PeerPenalty is a category of the protocol violations the peers are penalized for
+type PeerPenalty string
+
+const (
+	// PenaltyInvalidBlock is the penalty for a block, or a block body, that fails the verification
+	PenaltyInvalidBlock PeerPenalty = "invalid_block"
+
+	// PenaltyInvalidMessage is the penalty for a gossip message rejected by the topic validator,
+	// like a malformed IBFT message
+	PenaltyInvalidMessage PeerPenalty = "invalid_message"
+
+	// PenaltyMalformedGossip is the penalty for a gossip message that can't be decoded
+	PenaltyMalformedGossip PeerPenalty = "malformed_gossip"
+)
+
+var peerPenalties = []PeerPenalty{
+	PenaltyInvalidBlock,
+	PenaltyInvalidMessage,
+	PenaltyMalformedGossip,
+}
+
+var (
+	ErrUnknownPenalty = errors.New("unknown peer penalty")
+)
+
+// ParsePeerPenalty returns the penalty category of the name
+func ParsePeerPenalty(name string) (PeerPenalty, error) {
+	for _, penalty := range peerPenalties {
+		if string(penalty) == name {
+			return penalty, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w %s, expected one of %v", ErrUnknownPenalty, name, peerPenalties)
+}
+
+// ScoringConfig details the params of the peer reputation
+type ScoringConfig struct {
+	Penalties      map[PeerPenalty]int64 // the score decrement of every violation
+	BanThreshold   int64                 // the peers whose score drops below the threshold are banned
+	BanDuration    time.Duration         // the duration of the first ban, doubled by every repeated ban
+	MaxBanDuration time.Duration         // the upper limit of the ban duration
+	ScoreRecovery  time.Duration         // the time it takes a penalized peer to recover a score point
+}
+
+// DefaultScoringConfig returns the default params of the peer reputation
+func DefaultScoringConfig() *ScoringConfig {
+	return &ScoringConfig{
+		Penalties: map[PeerPenalty]int64{
+			PenaltyInvalidBlock:    50,
+			PenaltyInvalidMessage:  10,
+			PenaltyMalformedGossip: 20,
+		},
+		BanThreshold:   -100,
+		BanDuration:    10 * time.Minute,
+		MaxBanDuration: 24 * time.Hour,
+		ScoreRecovery:  time.Minute,
+	}
+}
+
+// peerRecord is the reputation of a penalized peer
+type peerRecord struct {
+	score   int64
+	updated time.Time // the time the score recovery is counted from
+
+	bans        uint // the number of times the peer was banned, the next ban lasts longer
+	bannedUntil time.Time
+}
+
+// peerScorer keeps the score of the peers penalized for protocol violations,
+// and bans the peers whose score drops below the threshold. Every peer starts with a zero score
+type peerScorer struct {
+	config *ScoringConfig
+	now    func() time.Time
+
+	lock  sync.Mutex
+	peers map[peer.ID]*peerRecord
+}
+
+// newPeerScorer creates a new peer scorer
+func newPeerScorer(config *ScoringConfig) *peerScorer {
+	return &peerScorer{
+		config: config,
+		now:    time.Now,
+		peers:  make(map[peer.ID]*peerRecord),
+	}
+}
+
+// recoverScore adds the score points the peer recovered since the last update
+func (s *peerScorer) recoverScore(record *peerRecord, now time.Time) {
+	if s.config.ScoreRecovery <= 0 || record.score >= 0 {
+		return
+	}
+
+	points := int64(now.Sub(record.updated) / s.config.ScoreRecovery)
+	if points <= 0 {
+		return
+	}
+
+	record.score += points
+	record.updated = record.updated.Add(time.Duration(points) * s.config.ScoreRecovery)
+
+	if record.score > 0 {
+		record.score = 0
+	}
+}
+
+// banDuration returns the duration of the next ban of the peer
+func (s *peerScorer) banDuration(record *peerRecord) time.Duration {
+	duration := s.config.BanDuration
+
+	for i := uint(0); i < record.bans && duration < s.config.MaxBanDuration; i++ {
+		duration *= 2
+	}
+
+	if s.config.MaxBanDuration > 0 && duration > s.config.MaxBanDuration {
+		duration = s.config.MaxBanDuration
+	}
+
+	return duration
+}
+
+// banLocked bans the peer for the duration, or for the ban backoff of the peer if zero.
+// The score of the peer is reset, it starts over once the ban is done
+func (s *peerScorer) banLocked(record *peerRecord, duration time.Duration, now time.Time) time.Duration {
+	if duration == 0 {
+		duration = s.banDuration(record)
+	}
+
+	record.bans++
+	record.bannedUntil = now.Add(duration)
+	record.score = 0
+	record.updated = now
+
+	return duration
+}
+
+// get returns the record of the peer, creating it if needed
+func (s *peerScorer) get(peerID peer.ID) *peerRecord {
+	record, ok := s.peers[peerID]
+	if !ok {
+		record = &peerRecord{}
+		s.peers[peerID] = record
+	}
+
+	return record
+}
+
+// penalize decrements the score of the peer for the violation,
+// and returns the ban duration if the peer is banned as a result
+func (s *peerScorer) penalize(peerID peer.ID, penalty PeerPenalty) (time.Duration, bool) {
+	weight := s.config.Penalties[penalty]
+	if weight <= 0 {
+		return 0, false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	record := s.get(peerID)
+
+	if now.Before(record.bannedUntil) {
+		// the messages still in flight from the banned peer are not counted
+		return 0, false
+	}
+
+	s.recoverScore(record, now)
+
+	if record.score == 0 {
+		record.updated = now
+	}
+
+	record.score -= weight
+
+	if record.score >= s.config.BanThreshold {
+		return 0, false
+	}
+
+	return s.banLocked(record, 0, now), true
+}
+
+// ban bans the peer for the duration, or for the ban backoff of the peer if zero
+func (s *peerScorer) ban(peerID peer.ID, duration time.Duration) time.Duration {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.banLocked(s.get(peerID), duration, s.now())
+}
+
+// unban lifts the ban of the peer and clears its record, and returns whether the peer was banned
+func (s *peerScorer) unban(peerID peer.ID) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	record, ok := s.peers[peerID]
+	if !ok {
+		return false
+	}
+
+	delete(s.peers, peerID)
+
+	return s.now().Before(record.bannedUntil)
+}
+
+// isBanned checks if the peer is banned
+func (s *peerScorer) isBanned(peerID peer.ID) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	record, ok := s.peers[peerID]
+
+	return ok && s.now().Before(record.bannedUntil)
+}
+
+// score returns the current score of the peer, and the time its ban ends if it's banned
+func (s *peerScorer) score(peerID peer.ID) (int64, time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	record, ok := s.peers[peerID]
+	if !ok {
+		return 0, time.Time{}
+	}
+
+	now := s.now()
+	s.recoverScore(record, now)
+
+	if !now.Before(record.bannedUntil) {
+		return record.score, time.Time{}
+	}
+
+	return record.score, record.bannedUntil
+}
+
+// banGater is the connection gater refusing the connections of the banned peers
+type banGater struct {
+	scorer *peerScorer
+}
+
+// InterceptPeerDial implements the connmgr.ConnectionGater interface
+func (g *banGater) InterceptPeerDial(peerID peer.ID) bool {
+	return !g.scorer.isBanned(peerID)
+}
+
+// InterceptAddrDial implements the connmgr.ConnectionGater interface
+func (g *banGater) InterceptAddrDial(peerID peer.ID, _ multiaddr.Multiaddr) bool {
+	return !g.scorer.isBanned(peerID)
+}
+
+// InterceptAccept implements the connmgr.ConnectionGater interface.
+// The peer of an inbound connection is known once the connection is secured
+func (g *banGater) InterceptAccept(_ network.ConnMultiaddrs) bool {
+	return true
+}
+
+// InterceptSecured implements the connmgr.ConnectionGater interface
+func (g *banGater) InterceptSecured(_ network.Direction, peerID peer.ID, _ network.ConnMultiaddrs) bool {
+	return !g.scorer.isBanned(peerID)
+}
+
+// InterceptUpgraded implements the connmgr.ConnectionGater interface
+func (g *banGater) InterceptUpgraded(_ network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
+
+// PenalizePeer decrements the score of the peer for the protocol violation,
+// and bans the peer if its score drops below the ban threshold. The trusted peers are not penalized [Thread safe]
+func (s *Server) PenalizePeer(peerID peer.ID, penalty PeerPenalty) {
+	if peerID == "" || peerID == s.host.ID() {
+		return
+	}
+
+	if s.IsTrustedPeer(peerID) {
+		s.logger.Debug("Trusted peer violated the protocol", "id", peerID.String(), "penalty", penalty)
+
+		return
+	}
+
+	duration, banned := s.scorer.penalize(peerID, penalty)
+
+	s.logger.Debug("Peer penalized", "id", peerID.String(), "penalty", penalty)
+
+	if banned {
+		s.logger.Warn("Banning peer for protocol violations", "id", peerID.String(), "duration", duration)
+
+		s.DisconnectFromPeer(peerID, fmt.Sprintf("banned for %s", duration))
+	}
+}
+
+// BanPeer bans the peer for the duration, or for the ban backoff of the peer if zero,
+// and closes its connections. Returns the time the ban ends [Thread safe]
+func (s *Server) BanPeer(peerID peer.ID, duration time.Duration) time.Time {
+	duration = s.scorer.ban(peerID, duration)
+
+	s.logger.Info("Banning peer", "id", peerID.String(), "duration", duration)
+
+	s.DisconnectFromPeer(peerID, fmt.Sprintf("banned for %s", duration))
+
+	_, bannedUntil := s.scorer.score(peerID)
+
+	return bannedUntil
+}
+
+// UnbanPeer lifts the ban of the peer and resets its score.
+// Returns whether the peer was banned [Thread safe]
+func (s *Server) UnbanPeer(peerID peer.ID) bool {
+	banned := s.scorer.unban(peerID)
+
+	if banned {
+		s.logger.Info("Peer unbanned", "id", peerID.String())
+	}
+
+	return banned
+}
+
+// PeerScore returns the score of the peer, and the time its ban ends if it's banned [Thread safe]
+func (s *Server) PeerScore(peerID peer.ID) (int64, time.Time) {
+	return s.scorer.score(peerID)
+}
@@ -0,0 +1,174 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestScorer creates a peer scorer with a clock moved by the test
+func newTestScorer() (*peerScorer, *time.Time) {
+	now := time.Unix(1_000_000, 0)
+
+	scorer := newPeerScorer(DefaultScoringConfig())
+	scorer.now = func() time.Time {
+		return now
+	}
+
+	return scorer, &now
+}
+
+func TestPeerScorer_Ban(t *testing.T) {
+	scorer, now := newTestScorer()
+	peerID := peer.ID("A")
+
+	// the score drops below the threshold on the third invalid block
+	for i := 0; i < 2; i++ {
+		_, banned := scorer.penalize(peerID, PenaltyInvalidBlock)
+		assert.False(t, banned)
+	}
+
+	score, bannedUntil := scorer.score(peerID)
+	assert.Equal(t, int64(-100), score)
+	assert.True(t, bannedUntil.IsZero())
+
+	duration, banned := scorer.penalize(peerID, PenaltyInvalidBlock)
+	assert.True(t, banned)
+	assert.Equal(t, 10*time.Minute, duration)
+	assert.True(t, scorer.isBanned(peerID))
+
+	// the violations of the banned peer are not counted
+	_, banned = scorer.penalize(peerID, PenaltyInvalidBlock)
+	assert.False(t, banned)
+
+	score, bannedUntil = scorer.score(peerID)
+	assert.Equal(t, int64(0), score)
+	assert.Equal(t, now.Add(duration), bannedUntil)
+
+	// the other peers are not affected
+	assert.False(t, scorer.isBanned(peer.ID("B")))
+
+	*now = now.Add(duration)
+	assert.False(t, scorer.isBanned(peerID))
+}
+
+func TestPeerScorer_RepeatedBans(t *testing.T) {
+	scorer, now := newTestScorer()
+	peerID := peer.ID("A")
+
+	for _, expected := range []time.Duration{
+		10 * time.Minute,
+		20 * time.Minute,
+		40 * time.Minute,
+	} {
+		assert.Equal(t, expected, scorer.ban(peerID, 0))
+
+		*now = now.Add(expected)
+	}
+
+	// the ban duration is capped
+	for i := 0; i < 10; i++ {
+		scorer.ban(peerID, 0)
+	}
+
+	assert.Equal(t, 24*time.Hour, scorer.ban(peerID, 0))
+
+	// the explicit duration is kept
+	assert.Equal(t, time.Minute, scorer.ban(peerID, time.Minute))
+
+	// the unbanned peer starts over
+	assert.True(t, scorer.unban(peerID))
+	assert.False(t, scorer.isBanned(peerID))
+	assert.False(t, scorer.unban(peerID))
+
+	assert.Equal(t, 10*time.Minute, scorer.ban(peerID, 0))
+}
+
+func TestPeerScorer_Recovery(t *testing.T) {
+	scorer, now := newTestScorer()
+	peerID := peer.ID("A")
+
+	scorer.penalize(peerID, PenaltyInvalidBlock)
+	scorer.penalize(peerID, PenaltyInvalidBlock)
+
+	// a point is recovered every minute
+	*now = now.Add(60 * time.Minute)
+
+	score, _ := scorer.score(peerID)
+	assert.Equal(t, int64(-40), score)
+
+	// the recovered peer is not banned by the next violation
+	_, banned := scorer.penalize(peerID, PenaltyInvalidBlock)
+	assert.False(t, banned)
+
+	// the score doesn't go over zero
+	*now = now.Add(24 * time.Hour)
+
+	score, _ = scorer.score(peerID)
+	assert.Equal(t, int64(0), score)
+}
+
+func TestPeerScorer_UnknownPenalty(t *testing.T) {
+	scorer, _ := newTestScorer()
+
+	_, banned := scorer.penalize(peer.ID("A"), PeerPenalty("unknown"))
+	assert.False(t, banned)
+
+	score, _ := scorer.score(peer.ID("A"))
+	assert.Equal(t, int64(0), score)
+
+	_, err := ParsePeerPenalty("unknown")
+	assert.ErrorIs(t, err, ErrUnknownPenalty)
+
+	penalty, err := ParsePeerPenalty("invalid_block")
+	assert.NoError(t, err)
+	assert.Equal(t, PenaltyInvalidBlock, penalty)
+}
+
+func TestBanPeer(t *testing.T) {
+	servers, createErr := createServers(2, nil)
+	if createErr != nil {
+		t.Fatalf("Unable to create servers, %v", createErr)
+	}
+
+	t.Cleanup(func() {
+		closeTestServers(t, servers)
+	})
+
+	if joinErr := JoinAndWait(servers[0], servers[1], DefaultBufferTimeout, DefaultJoinTimeout); joinErr != nil {
+		t.Fatalf("Unable to join servers, %v", joinErr)
+	}
+
+	peerID := servers[1].AddrInfo().ID
+
+	// the penalized peer is disconnected once its score drops below the threshold
+	for i := 0; i < 3; i++ {
+		servers[0].PenalizePeer(peerID, PenaltyInvalidBlock)
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), DefaultLeaveTimeout)
+	defer cancelFn()
+
+	disconnected, err := WaitUntilPeerDisconnectsFrom(ctx, servers[0], peerID)
+	assert.NoError(t, err)
+	assert.True(t, disconnected)
+
+	// the banned peer has to see the disconnect as well, before it tries to connect back
+	disconnected, err = WaitUntilPeerDisconnectsFrom(ctx, servers[1], servers[0].AddrInfo().ID)
+	assert.NoError(t, err)
+	assert.True(t, disconnected)
+
+	_, bannedUntil := servers[0].PeerScore(peerID)
+	assert.False(t, bannedUntil.IsZero())
+
+	// the banned peer can't connect back
+	assert.Error(t, JoinAndWait(servers[1], servers[0], 5*time.Second, 5*time.Second))
+	assert.False(t, servers[0].hasPeer(peerID))
+
+	// the unbanned peer is accepted again
+	assert.True(t, servers[0].UnbanPeer(peerID))
+	assert.NoError(t, JoinAndWait(servers[0], servers[1], DefaultBufferTimeout, DefaultJoinTimeout))
+}
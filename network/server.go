@@ -10,6 +10,7 @@ import (
 	"github.com/libp2p/go-libp2p"
 	noise "github.com/libp2p/go-libp2p-noise"
 	rawGrpc "google.golang.org/grpc"
+	"net"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -86,6 +87,18 @@ type Server struct {
 	temporaryDials sync.Map // map of temporary connections; peerID -> bool
 
 	bootnodes *bootnodesWrapper // reference of all bootnodes for the node
+
+	scorer *peerScorer // the reputation of the peers penalized for protocol violations
+
+	staticPeers  map[peer.ID]*peer.AddrInfo // the peers the node keeps connected with at all times
+	trustedPeers map[peer.ID]struct{}       // the peers exempt from the peer limits and the banning
+	pinnedLock   sync.RWMutex               // lock for the static and the trusted peers
+
+	staticDials sync.Map // map of the static peers being dialed; peerID -> struct{}
+
+	portMapping *portMapping // the mapping of the libp2p port on the gateway
+
+	dnsResolver txtResolver // the resolver of the DNS bootnode list
 }
 
 // NewServer returns a new instance of the networking server
@@ -102,26 +115,23 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 		return nil, err
 	}
 
-	addrsFactory := func(addrs []multiaddr.Multiaddr) []multiaddr.Multiaddr {
-		if config.NatAddr != nil {
-			addr, _ := multiaddr.NewMultiaddr(fmt.Sprintf("/ip4/%s/tcp/%d", config.NatAddr.String(), config.Addr.Port))
-
-			if addr != nil {
-				addrs = []multiaddr.Multiaddr{addr}
-			}
-		} else if config.DNS != nil {
-			addrs = []multiaddr.Multiaddr{config.DNS}
-		}
+	mapping := &portMapping{}
 
-		return addrs
+	scoringConfig := config.Scoring
+	if scoringConfig == nil {
+		scoringConfig = DefaultScoringConfig()
 	}
 
+	scorer := newPeerScorer(scoringConfig)
+
 	host, err := libp2p.New(
 		// Use noise as the encryption protocol
 		libp2p.Security(noise.ID, noise.New),
 		libp2p.ListenAddrs(listenAddr),
-		libp2p.AddrsFactory(addrsFactory),
+		libp2p.AddrsFactory(newAddrsFactory(config, mapping)),
 		libp2p.Identity(key),
+		// Refuse the connections of the banned peers
+		libp2p.ConnectionGater(&banGater{scorer: scorer}),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create libp2p stack: %w", err)
@@ -144,6 +154,11 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 		emitterPeerEvent: emitter,
 		protocols:        map[string]Protocol{},
 		secretsManager:   config.SecretsManager,
+		scorer:           scorer,
+		portMapping:      mapping,
+		dnsResolver:      net.DefaultResolver,
+		staticPeers:      make(map[peer.ID]*peer.AddrInfo),
+		trustedPeers:     make(map[peer.ID]struct{}),
 		bootnodes: &bootnodesWrapper{
 			bootnodeArr:       make([]*peer.AddrInfo, 0),
 			bootnodesMap:      make(map[peer.ID]*peer.AddrInfo),
@@ -167,6 +182,8 @@ func NewServer(logger hclog.Logger, config *Config) (*Server, error) {
 
 	srv.ps = ps
 
+	srv.setupPinnedPeers()
+
 	return srv, nil
 }
 
@@ -213,6 +230,17 @@ func (pci *PeerConnInfo) getProtocolStream(protocol string) *rawGrpc.ClientConn
 func setupLibp2pKey(secretsManager secrets.SecretsManager) (crypto.PrivKey, error) {
 	var key crypto.PrivKey
 
+	if keySigner, ok := secretsManager.(secrets.KeySigner); ok {
+		// The key is held by the KMS, create it if not present
+		if !secretsManager.HasSecret(secrets.NetworkKey) {
+			if createErr := keySigner.CreateKey(secrets.NetworkKey); createErr != nil {
+				return nil, fmt.Errorf("unable to create networking private key in Secrets Manager, %w", createErr)
+			}
+		}
+
+		return NewKMSLibp2pKey(keySigner)
+	}
+
 	if secretsManager.HasSecret(secrets.NetworkKey) {
 		// The key is present in the secrets manager, read it
 		networkingKey, readErr := ReadLibp2pKey(secretsManager)
@@ -258,10 +286,16 @@ func (s *Server) Start() error {
 		if setupErr := s.setupDiscovery(); setupErr != nil {
 			return fmt.Errorf("unable to setup discovery, %w", setupErr)
 		}
+
+		if domain := s.discoveryDNS(); domain != "" {
+			go s.runDNSDiscovery(domain, s.bootnodes.getBootnodes())
+		}
 	}
 
 	go s.runDial()
 	go s.checkPeerConnections()
+	go s.runStaticDial()
+	go s.runPortMapping()
 
 	// watch for disconnected peers
 	s.host.Network().Notify(&network.NotifyBundle{
@@ -276,14 +310,22 @@ func (s *Server) Start() error {
 
 // setupBootnodes sets up the node's bootnode connections
 func (s *Server) setupBootnodes() error {
-	// Check the bootnode config is present
-	if s.config.Chain.Bootnodes == nil {
-		return ErrNoBootnodes
+	// The bootnodes resolved from the DNS are added to the configured ones
+	dnsBootnodes, err := s.setupDNSBootnodes()
+	if err != nil {
+		return err
 	}
 
-	// Check if at least one bootnode is specified
-	if len(s.config.Chain.Bootnodes) < MinimumBootNodes {
-		return ErrMinBootnodes
+	if len(dnsBootnodes) == 0 {
+		// Check the bootnode config is present
+		if s.config.Chain.Bootnodes == nil {
+			return ErrNoBootnodes
+		}
+
+		// Check if at least one bootnode is specified
+		if len(s.config.Chain.Bootnodes) < MinimumBootNodes {
+			return ErrMinBootnodes
+		}
 	}
 
 	bootnodesArr := make([]*peer.AddrInfo, 0)
@@ -305,6 +347,15 @@ func (s *Server) setupBootnodes() error {
 		bootnodesMap[bootnode.ID] = bootnode
 	}
 
+	for _, bootnode := range dnsBootnodes {
+		if _, ok := bootnodesMap[bootnode.ID]; ok {
+			continue
+		}
+
+		bootnodesArr = append(bootnodesArr, bootnode)
+		bootnodesMap[bootnode.ID] = bootnode
+	}
+
 	// It's fine for the bootnodes field to be unprotected
 	// at this point because it is initialized once (doesn't change),
 	// and used only after this point
@@ -105,11 +105,18 @@ func (s *Server) IsTemporaryDial(peerID peer.ID) bool {
 
 // setupIdentity sets up the identity service for the node
 func (s *Server) setupIdentity() error {
+	// The genesis state root is computed once the blockchain is set up, before the server is started
+	genesis := ""
+	if s.config.Chain.Genesis != nil {
+		genesis = s.config.Chain.Genesis.Hash().String()
+	}
+
 	// Create an instance of the identity service
 	identityService := identity.NewIdentityService(
 		s,
 		s.logger,
 		int64(s.config.Chain.Params.ChainID),
+		genesis,
 		s.host.ID(),
 	)
 
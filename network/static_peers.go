@@ -0,0 +1,174 @@
+package network
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/network/common"
+	peerEvent "github.com/0xPolygon/polygon-edge/network/event"
+	"github.com/libp2p/go-libp2p-core/peer"
+	swarm "github.com/libp2p/go-libp2p-swarm"
+)
+
+const (
+	// staticRedialInterval is the interval the disconnected static peers are redialed at
+	staticRedialInterval = 5 * time.Second
+
+	// staticDialTimeout is the time a dial of a static peer has to complete
+	staticDialTimeout = 10 * time.Second
+)
+
+// setupPinnedPeers sets up the static and the trusted peers of the configuration
+func (s *Server) setupPinnedPeers() {
+	s.pinnedLock.Lock()
+	defer s.pinnedLock.Unlock()
+
+	for _, info := range s.config.StaticPeers {
+		if info.ID == s.host.ID() {
+			continue
+		}
+
+		s.staticPeers[info.ID] = info
+	}
+
+	for _, peerID := range s.config.TrustedPeers {
+		s.trustedPeers[peerID] = struct{}{}
+	}
+}
+
+// AddStaticPeer adds the peer to the static peers, the node keeps a connection to them at all times.
+// The static peers added at runtime are not kept across restarts [Thread safe]
+func (s *Server) AddStaticPeer(rawPeerMultiaddr string) error {
+	info, err := common.StringToAddrInfo(rawPeerMultiaddr)
+	if err != nil {
+		return err
+	}
+
+	if info.ID == s.host.ID() {
+		return nil
+	}
+
+	s.pinnedLock.Lock()
+	s.staticPeers[info.ID] = info
+	s.pinnedLock.Unlock()
+
+	s.logger.Info("Static peer added", "addr", common.AddrInfoToString(info))
+
+	go s.dialStaticPeer(info)
+
+	return nil
+}
+
+// RemoveStaticPeer removes the peer from the static peers. The connection to the peer is kept,
+// but it's not redialed anymore. Returns whether the peer was a static peer [Thread safe]
+func (s *Server) RemoveStaticPeer(peerID peer.ID) bool {
+	s.pinnedLock.Lock()
+	defer s.pinnedLock.Unlock()
+
+	if _, ok := s.staticPeers[peerID]; !ok {
+		return false
+	}
+
+	delete(s.staticPeers, peerID)
+
+	s.logger.Info("Static peer removed", "id", peerID.String())
+
+	return true
+}
+
+// StaticPeers returns the static peers of the node [Thread safe]
+func (s *Server) StaticPeers() []*peer.AddrInfo {
+	s.pinnedLock.RLock()
+	defer s.pinnedLock.RUnlock()
+
+	peers := make([]*peer.AddrInfo, 0, len(s.staticPeers))
+	for _, info := range s.staticPeers {
+		peers = append(peers, info)
+	}
+
+	return peers
+}
+
+// IsStaticPeer checks if the peer is a static peer [Thread safe]
+func (s *Server) IsStaticPeer(peerID peer.ID) bool {
+	s.pinnedLock.RLock()
+	defer s.pinnedLock.RUnlock()
+
+	_, ok := s.staticPeers[peerID]
+
+	return ok
+}
+
+// IsTrustedPeer checks if the peer is a trusted peer, that is never banned for its score [Thread safe]
+func (s *Server) IsTrustedPeer(peerID peer.ID) bool {
+	s.pinnedLock.RLock()
+	defer s.pinnedLock.RUnlock()
+
+	_, ok := s.trustedPeers[peerID]
+
+	return ok
+}
+
+// IsExemptFromPeerLimits checks if the connections of the peer are accepted over the peer limits.
+// The trusted peers and the static peers are [Thread safe]
+func (s *Server) IsExemptFromPeerLimits(peerID peer.ID) bool {
+	return s.IsTrustedPeer(peerID) || s.IsStaticPeer(peerID)
+}
+
+// runStaticDial keeps the node connected to the static peers. The static peers are redialed
+// as soon as they disconnect, and at every redial interval while they are not connected
+func (s *Server) runStaticDial() {
+	if err := s.SubscribeFn(func(event *peerEvent.PeerEvent) {
+		if event.Type != peerEvent.PeerDisconnected {
+			return
+		}
+
+		s.pinnedLock.RLock()
+		info, ok := s.staticPeers[event.PeerID]
+		s.pinnedLock.RUnlock()
+
+		if ok {
+			go s.dialStaticPeer(info)
+		}
+	}); err != nil {
+		s.logger.Error("Cannot instantiate an event subscription for the static peers", "err", err)
+	}
+
+	for {
+		for _, info := range s.StaticPeers() {
+			go s.dialStaticPeer(info)
+		}
+
+		select {
+		case <-time.After(staticRedialInterval):
+		case <-s.closeCh:
+			return
+		}
+	}
+}
+
+// dialStaticPeer dials the static peer, if it's not connected or being dialed already.
+// The dial bypasses the dial queue and the outbound peer limit
+func (s *Server) dialStaticPeer(info *peer.AddrInfo) {
+	if s.isConnected(info.ID) {
+		return
+	}
+
+	if _, dialing := s.staticDials.LoadOrStore(info.ID, struct{}{}); dialing {
+		return
+	}
+
+	defer s.staticDials.Delete(info.ID)
+
+	// the static peers are redialed right away, not after the dial backoff of the swarm
+	if sw, ok := s.host.Network().(*swarm.Swarm); ok {
+		sw.Backoff().Clear(info.ID)
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), staticDialTimeout)
+	defer cancelFn()
+
+	if err := s.host.Connect(ctx, *info); err != nil {
+		s.logger.Debug("failed to dial static peer", "addr", info.String(), "err", err)
+	}
+}
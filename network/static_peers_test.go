@@ -0,0 +1,108 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/network/common"
+	peerEvent "github.com/0xPolygon/polygon-edge/network/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticPeer_Redial(t *testing.T) {
+	servers, createErr := createServers(2, nil)
+	if createErr != nil {
+		t.Fatalf("Unable to create servers, %v", createErr)
+	}
+
+	t.Cleanup(func() {
+		closeTestServers(t, servers)
+	})
+
+	peerID := servers[1].AddrInfo().ID
+
+	assert.NoError(t, servers[0].AddStaticPeer(common.AddrInfoToString(servers[1].AddrInfo())))
+	assert.True(t, servers[0].IsStaticPeer(peerID))
+	assert.True(t, servers[0].IsExemptFromPeerLimits(peerID))
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), DefaultJoinTimeout)
+	defer cancelFn()
+
+	connected, err := WaitUntilPeerConnectsTo(ctx, servers[0], peerID)
+	assert.NoError(t, err)
+	assert.True(t, connected)
+
+	sub, err := servers[0].Subscribe()
+	if err != nil {
+		t.Fatalf("Unable to subscribe to the peer events, %v", err)
+	}
+
+	defer sub.Close()
+
+	// the static peer is redialed once it disconnects
+	servers[1].DisconnectFromPeer(servers[0].AddrInfo().ID, "testing")
+
+	disconnected := false
+
+	for {
+		select {
+		case event := <-sub.GetCh():
+			if event.PeerID != peerID {
+				continue
+			}
+
+			switch event.Type {
+			case peerEvent.PeerDisconnected:
+				disconnected = true
+			case peerEvent.PeerConnected:
+				if disconnected {
+					// the removed static peer is not redialed anymore
+					assert.True(t, servers[0].RemoveStaticPeer(peerID))
+					assert.False(t, servers[0].RemoveStaticPeer(peerID))
+					assert.False(t, servers[0].IsExemptFromPeerLimits(peerID))
+					assert.Len(t, servers[0].StaticPeers(), 0)
+
+					return
+				}
+			}
+		case <-ctx.Done():
+			t.Fatalf("The static peer wasn't redialed")
+		}
+	}
+}
+
+func TestTrustedPeer_NotBanned(t *testing.T) {
+	servers, createErr := createServers(2, nil)
+	if createErr != nil {
+		t.Fatalf("Unable to create servers, %v", createErr)
+	}
+
+	t.Cleanup(func() {
+		closeTestServers(t, servers)
+	})
+
+	if joinErr := JoinAndWait(servers[0], servers[1], DefaultBufferTimeout, DefaultJoinTimeout); joinErr != nil {
+		t.Fatalf("Unable to join servers, %v", joinErr)
+	}
+
+	peerID := servers[1].AddrInfo().ID
+
+	servers[0].pinnedLock.Lock()
+	servers[0].trustedPeers[peerID] = struct{}{}
+	servers[0].pinnedLock.Unlock()
+
+	assert.True(t, servers[0].IsExemptFromPeerLimits(peerID))
+
+	// the violations of the trusted peer are not counted
+	for i := 0; i < 3; i++ {
+		servers[0].PenalizePeer(peerID, PenaltyInvalidBlock)
+	}
+
+	score, bannedUntil := servers[0].PeerScore(peerID)
+	assert.Equal(t, int64(0), score)
+	assert.True(t, bannedUntil.IsZero())
+
+	time.Sleep(time.Second)
+	assert.True(t, servers[0].hasPeer(peerID))
+}
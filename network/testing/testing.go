@@ -29,6 +29,7 @@ type MockNetworkingServer struct {
 	emitEventFn              emitEventDelegate
 	isTemporaryDialFn        isTemporaryDialDelegate
 	hasFreeConnectionSlotFn  hasFreeConnectionSlotDelegate
+	isExemptFromPeerLimitsFn isExemptFromPeerLimitsDelegate
 
 	// Discovery Hooks
 	newDiscoveryClientFn       newDiscoveryClientDelegate
@@ -72,6 +73,7 @@ type updatePendingConnCountDelegate func(int64, network.Direction)
 type emitEventDelegate func(*event.PeerEvent)
 type isTemporaryDialDelegate func(peer.ID) bool
 type hasFreeConnectionSlotDelegate func(network.Direction) bool
+type isExemptFromPeerLimitsDelegate func(peer.ID) bool
 
 // Required for Discovery
 type getRandomBootnodeDelegate func() *peer.AddrInfo
@@ -161,6 +163,18 @@ func (m *MockNetworkingServer) HookHasFreeConnectionSlot(fn hasFreeConnectionSlo
 	m.hasFreeConnectionSlotFn = fn
 }
 
+func (m *MockNetworkingServer) IsExemptFromPeerLimits(peerID peer.ID) bool {
+	if m.isExemptFromPeerLimitsFn != nil {
+		return m.isExemptFromPeerLimitsFn(peerID)
+	}
+
+	return false
+}
+
+func (m *MockNetworkingServer) HookIsExemptFromPeerLimits(fn isExemptFromPeerLimitsDelegate) {
+	m.isExemptFromPeerLimitsFn = fn
+}
+
 func (m *MockNetworkingServer) GetRandomBootnode() *peer.AddrInfo {
 	if m.getRandomBootnodeFn != nil {
 		return m.getRandomBootnodeFn()
@@ -9,7 +9,7 @@ import (
 
 // Blockchain is the interface required by the syncer to connect to the blockchain
 type blockchainShim interface {
-	SubscribeEvents() blockchain.Subscription
+	SubscribeEventStream() blockchain.Subscription
 	Header() *types.Header
 	CurrentTD() *big.Int
 
@@ -19,7 +19,11 @@ type blockchainShim interface {
 	GetHeaderByHash(types.Hash) (*types.Header, bool)
 	GetHeaderByNumber(n uint64) (*types.Header, bool)
 
+	// RecoverSeals recovers the seals of the headers ahead of their verification
+	RecoverSeals(headers []*types.Header)
+
 	// advance chain methods
-	WriteBlock(block *types.Block) error
+	WriteBlock(block *types.Block, source string) error
+	WriteBlockWithReceipts(block *types.Block, receipts []*types.Receipt) error
 	CalculateGasLimit(number uint64) (uint64, error)
 }
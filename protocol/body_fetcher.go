@@ -0,0 +1,376 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/0xPolygon/polygon-edge/types/buildroot"
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	// maxInflightBodyRequests is the number of body requests a single peer serves at the same time
+	maxInflightBodyRequests = 2
+
+	// bodyRequestTimeout is the time a peer has to serve a body request,
+	// the range is assigned to another peer once it expires
+	bodyRequestTimeout = 10 * time.Second
+
+	// maxBodyRequestFailures is the number of failed body requests after which a peer is not used anymore
+	maxBodyRequestFailures = 3
+)
+
+var (
+	ErrNoBodyPeers = errors.New("no peer serves the bodies")
+
+	errInvalidBody     = errors.New("invalid body")
+	errInvalidReceipts = errors.New("invalid receipts")
+)
+
+// bodyTask is a range of blocks whose bodies are downloaded from a single peer
+type bodyTask struct {
+	indx   int
+	blocks []*types.Block
+
+	// failed are the peers that failed to serve the range
+	failed map[peer.ID]struct{}
+}
+
+// bodyResult is the response of a peer to a body task.
+// The bodies and the receipts are indexed like the blocks of the task,
+// they are nil for the blocks that were not requested
+type bodyResult struct {
+	task     *bodyTask
+	peer     *SyncPeer
+	bodies   []*types.Body
+	receipts [][]*types.Receipt
+	elapsed  time.Duration
+	err      error
+}
+
+// bodyFetcher downloads the bodies, and the receipts if required, of ranges of headers
+// concurrently from several peers. The ranges are assigned to the peers with free request slots,
+// the ranges that fail or time out are assigned to other peers, and the completed ranges
+// are buffered until they can be written in order
+type bodyFetcher struct {
+	logger  hclog.Logger
+	metrics *Metrics
+
+	peers    []*SyncPeer
+	inflight map[peer.ID]int
+	failures map[peer.ID]int
+
+	maxInflight int
+	timeout     time.Duration
+
+	// withReceipts returns whether the receipts of the block are downloaded, none are if it's nil
+	withReceipts func(header *types.Header) bool
+
+	// penalize penalizes the peers serving invalid bodies or receipts
+	penalize func(peer.ID, network.PeerPenalty)
+}
+
+// newBodyFetcher creates a body fetcher using the passed in peers
+func (s *Syncer) newBodyFetcher(peers []*SyncPeer, withReceipts func(header *types.Header) bool) *bodyFetcher {
+	return &bodyFetcher{
+		logger:       s.logger,
+		metrics:      s.metrics,
+		peers:        peers,
+		inflight:     map[peer.ID]int{},
+		failures:     map[peer.ID]int{},
+		maxInflight:  maxInflightBodyRequests,
+		timeout:      bodyRequestTimeout,
+		withReceipts: withReceipts,
+		penalize:     s.server.PenalizePeer,
+	}
+}
+
+// bodyPeers returns the peers to download the bodies from, starting with the passed in peer
+func (s *Syncer) bodyPeers(p *SyncPeer) []*SyncPeer {
+	peers := []*SyncPeer{p}
+
+	s.peers.Range(func(_, peer interface{}) bool {
+		syncPeer, ok := peer.(*SyncPeer)
+		if ok && syncPeer != p {
+			peers = append(peers, syncPeer)
+		}
+
+		return true
+	})
+
+	return peers
+}
+
+// fetch downloads the bodies of the ranges of blocks, and passes the completed ranges
+// to write in the same order as they were passed in
+func (f *bodyFetcher) fetch(
+	ranges [][]*types.Block,
+	write func(blocks []*types.Block, receipts [][]*types.Receipt) error,
+) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		pending  = make([]*bodyTask, 0, len(ranges))
+		done     = make([]*bodyResult, len(ranges))
+		results  = make(chan *bodyResult)
+		inflight = 0
+		next     = 0
+	)
+
+	for indx, blocks := range ranges {
+		task := &bodyTask{
+			indx:   indx,
+			blocks: blocks,
+			failed: map[peer.ID]struct{}{},
+		}
+
+		if f.requested(blocks) {
+			pending = append(pending, task)
+		} else {
+			// nothing to download
+			done[indx] = &bodyResult{
+				task:     task,
+				receipts: make([][]*types.Receipt, len(blocks)),
+			}
+		}
+	}
+
+	for next < len(ranges) {
+		for done[next] != nil {
+			res := done[next]
+			done[next] = nil
+
+			if err := write(res.task.blocks, res.receipts); err != nil {
+				return err
+			}
+
+			if next++; next == len(ranges) {
+				return nil
+			}
+		}
+
+		pending = f.assign(ctx, pending, results, &inflight)
+
+		if inflight == 0 {
+			return fmt.Errorf("%w, range %d", ErrNoBodyPeers, pending[0].indx)
+		}
+
+		res := <-results
+
+		inflight--
+		f.inflight[res.peer.peer]--
+
+		if res.err != nil {
+			f.logger.Debug("failed to get the bodies from peer", "id", res.peer.peer, "err", res.err)
+			f.metrics.FailedBodyRequests.With(peerIDLabel, res.peer.peer.String()).Add(1)
+
+			f.failures[res.peer.peer]++
+			res.task.failed[res.peer.peer] = struct{}{}
+
+			if errors.Is(res.err, errInvalidBody) || errors.Is(res.err, errInvalidReceipts) {
+				f.penalize(res.peer.peer, network.PenaltyInvalidBlock)
+			}
+
+			// the lowest ranges are assigned first, they block the writes
+			pending = append(pending, res.task)
+			sort.Slice(pending, func(i, j int) bool {
+				return pending[i].indx < pending[j].indx
+			})
+
+			continue
+		}
+
+		peerID, blocks := res.peer.peer.String(), float64(len(res.task.blocks))
+
+		f.metrics.DownloadedBlocks.With(peerIDLabel, peerID).Add(blocks)
+
+		if seconds := res.elapsed.Seconds(); seconds > 0 {
+			f.metrics.PeerThroughput.With(peerIDLabel, peerID).Set(blocks / seconds)
+		}
+
+		for indx, body := range res.bodies {
+			if body != nil {
+				res.task.blocks[indx].Transactions = body.Transactions
+			}
+		}
+
+		done[res.task.indx] = res
+	}
+
+	return nil
+}
+
+// assign requests the pending tasks to the peers with free request slots,
+// and returns the tasks that are still pending
+func (f *bodyFetcher) assign(
+	ctx context.Context,
+	pending []*bodyTask,
+	results chan<- *bodyResult,
+	inflight *int,
+) []*bodyTask {
+	remaining := make([]*bodyTask, 0, len(pending))
+
+	for _, task := range pending {
+		p := f.selectPeer(task)
+		if p == nil {
+			remaining = append(remaining, task)
+
+			continue
+		}
+
+		f.inflight[p.peer]++
+		*inflight++
+
+		go f.request(ctx, p, task, results)
+	}
+
+	return remaining
+}
+
+// selectPeer returns the least busy peer that can serve the task, if any
+func (f *bodyFetcher) selectPeer(task *bodyTask) *SyncPeer {
+	last := task.blocks[len(task.blocks)-1].Number()
+
+	usable := func(p *SyncPeer) bool {
+		return !p.IsClosed() && f.failures[p.peer] < maxBodyRequestFailures && p.Number() >= last
+	}
+
+	// the range failed on all the peers, they are given another chance
+	// until they are not used anymore
+	retry := true
+
+	for _, p := range f.peers {
+		if _, failed := task.failed[p.peer]; usable(p) && !failed {
+			retry = false
+
+			break
+		}
+	}
+
+	if retry {
+		task.failed = map[peer.ID]struct{}{}
+	}
+
+	var selected *SyncPeer
+
+	for _, p := range f.peers {
+		if _, failed := task.failed[p.peer]; failed || !usable(p) || f.inflight[p.peer] >= f.maxInflight {
+			continue
+		}
+
+		if selected == nil || f.inflight[p.peer] < f.inflight[selected.peer] {
+			selected = p
+		}
+	}
+
+	return selected
+}
+
+// request downloads the task from the peer, and sends the result back unless the fetch is done
+func (f *bodyFetcher) request(ctx context.Context, p *SyncPeer, task *bodyTask, results chan<- *bodyResult) {
+	reqCtx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	start := time.Now()
+	res := &bodyResult{
+		task: task,
+		peer: p,
+	}
+
+	res.bodies, res.receipts, res.err = f.download(reqCtx, p, task.blocks)
+	res.elapsed = time.Since(start)
+
+	select {
+	case results <- res:
+	case <-ctx.Done():
+	}
+}
+
+// download gets the bodies and the receipts of the blocks from the peer,
+// and verifies them against the headers
+func (f *bodyFetcher) download(
+	ctx context.Context,
+	p *SyncPeer,
+	blocks []*types.Block,
+) ([]*types.Body, [][]*types.Receipt, error) {
+	var (
+		bodies   = make([]*types.Body, len(blocks))
+		receipts = make([][]*types.Receipt, len(blocks))
+
+		bodyHashes    = []types.Hash{}
+		bodyIndex     = []int{}
+		receiptHashes = []types.Hash{}
+		receiptIndex  = []int{}
+	)
+
+	for indx, block := range blocks {
+		if block.Header.TxRoot != types.EmptyRootHash {
+			bodyHashes = append(bodyHashes, block.Hash())
+			bodyIndex = append(bodyIndex, indx)
+		}
+
+		if f.receiptsRequested(block.Header) {
+			receiptHashes = append(receiptHashes, block.Hash())
+			receiptIndex = append(receiptIndex, indx)
+		}
+	}
+
+	if len(bodyHashes) != 0 {
+		res, err := getBodies(ctx, p.client, bodyHashes)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i, body := range res {
+			header := blocks[bodyIndex[i]].Header
+
+			if root := buildroot.CalculateTransactionsRoot(body.Transactions); root != header.TxRoot {
+				return nil, nil, fmt.Errorf("%w of block %d, transactions root %s", errInvalidBody, header.Number, root)
+			}
+
+			bodies[bodyIndex[i]] = body
+		}
+	}
+
+	if len(receiptHashes) != 0 {
+		res, err := getReceipts(ctx, p.client, receiptHashes)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i, blockReceipts := range res {
+			header := blocks[receiptIndex[i]].Header
+
+			if root := buildroot.CalculateReceiptsRoot(blockReceipts); root != header.ReceiptsRoot {
+				return nil, nil, fmt.Errorf("%w of block %d, receipts root %s", errInvalidReceipts, header.Number, root)
+			}
+
+			receipts[receiptIndex[i]] = blockReceipts
+		}
+	}
+
+	return bodies, receipts, nil
+}
+
+// requested returns whether anything is downloaded for the blocks
+func (f *bodyFetcher) requested(blocks []*types.Block) bool {
+	for _, block := range blocks {
+		if block.Header.TxRoot != types.EmptyRootHash || f.receiptsRequested(block.Header) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// receiptsRequested returns whether the receipts of the block are downloaded
+func (f *bodyFetcher) receiptsRequested(header *types.Header) bool {
+	return f.withReceipts != nil && header.ReceiptsRoot != types.EmptyRootHash && f.withReceipts(header)
+}
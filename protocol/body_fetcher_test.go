@@ -0,0 +1,185 @@
+package protocol
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// slowBlockchain is a mock blockchain serving every body with a delay
+type slowBlockchain struct {
+	*mockBlockchain
+	delay time.Duration
+}
+
+func (b *slowBlockchain) GetBodyByHash(h types.Hash) (*types.Body, bool) {
+	time.Sleep(b.delay)
+
+	return b.mockBlockchain.GetBodyByHash(h)
+}
+
+// headerRanges splits the headers into ranges of blocks without bodies
+func headerRanges(headers []*types.Header, size int) [][]*types.Block {
+	ranges := [][]*types.Block{}
+
+	for start := 0; start < len(headers); start += size {
+		end := start + size
+		if end > len(headers) {
+			end = len(headers)
+		}
+
+		ranges = append(ranges, blockchain.HeadersToBlocks(headers[start:end]))
+	}
+
+	return ranges
+}
+
+// setupBodyFetcher connects a syncer to the peer chains, and creates a body fetcher using all of them
+func setupBodyFetcher(t *testing.T, genesis *types.Header, peerChains []blockchainShim) (*bodyFetcher, []*SyncPeer) {
+	t.Helper()
+
+	syncer, peerSyncers := SetupSyncerNetwork(t, NewMockBlockchain([]*types.Header{genesis}), peerChains)
+	WaitUntilPeerConnected(t, syncer, len(peerChains), 10*time.Second)
+
+	peers := make([]*SyncPeer, 0, len(peerSyncers))
+
+	for _, peerSyncer := range peerSyncers {
+		peer := getPeer(syncer, peerSyncer.server.AddrInfo().ID)
+		assert.NotNil(t, peer)
+
+		peers = append(peers, peer)
+	}
+
+	return syncer.newBodyFetcher(peers, nil), peers
+}
+
+// fetchBlocks fetches the bodies of the headers, and returns the written blocks
+func fetchBlocks(t *testing.T, fetcher *bodyFetcher, headers []*types.Header) []*types.Block {
+	t.Helper()
+
+	written := []*types.Block{}
+
+	assert.NoError(t, fetcher.fetch(headerRanges(headers, 10), func(blocks []*types.Block, _ [][]*types.Receipt) error {
+		written = append(written, blocks...)
+
+		return nil
+	}))
+
+	return written
+}
+
+// assertBodies checks the blocks are the expected ones, in order and with their transactions
+func assertBodies(t *testing.T, expected []*types.Block, blocks []*types.Block) {
+	t.Helper()
+
+	if !assert.Len(t, blocks, len(expected)) {
+		return
+	}
+
+	for indx, block := range blocks {
+		assert.Equal(t, expected[indx].Hash(), block.Hash())
+
+		if assert.Len(t, block.Transactions, len(expected[indx].Transactions)) {
+			assert.Equal(t, expected[indx].Transactions[0].Hash, block.Transactions[0].Hash)
+		}
+	}
+}
+
+func TestBodyFetcher_SlowPeer(t *testing.T) {
+	t.Parallel()
+
+	headers, blocks, _ := blockchain.NewTestBodyChain(101)
+
+	// a range of 10 blocks takes the slow peer 10 seconds to serve
+	slowPeerChain := &slowBlockchain{
+		mockBlockchain: &mockBlockchain{blocks: blocks},
+		delay:          time.Second,
+	}
+
+	fetcher, peers := setupBodyFetcher(t, headers[0], []blockchainShim{
+		slowPeerChain,
+		&mockBlockchain{blocks: blocks},
+		&mockBlockchain{blocks: blocks},
+	})
+	fetcher.timeout = 500 * time.Millisecond
+
+	start := time.Now()
+	written := fetchBlocks(t, fetcher, headers[1:])
+	elapsed := time.Since(start)
+
+	// the ranges of the slow peer are downloaded from the other peers
+	assertBodies(t, blocks[1:], written)
+	assert.True(t, elapsed < 10*time.Second, "the download is bounded by the slow peer, took %s", elapsed)
+	assert.Greater(t, fetcher.failures[peers[0].peer], 0)
+}
+
+func TestBodyFetcher_InvalidBody(t *testing.T) {
+	t.Parallel()
+
+	headers, blocks, _ := blockchain.NewTestBodyChain(51)
+
+	// the peer serves other transactions than the ones of the headers
+	invalidBlocks := make([]*types.Block, 0, len(blocks))
+
+	for _, block := range blocks {
+		tx := block.Transactions
+		if len(tx) != 0 {
+			invalid := tx[0].Copy()
+			invalid.Value = big.NewInt(1)
+			invalid.ComputeHash()
+
+			tx = []*types.Transaction{invalid}
+		}
+
+		invalidBlocks = append(invalidBlocks, &types.Block{
+			Header:       block.Header,
+			Transactions: tx,
+		})
+	}
+
+	fetcher, peers := setupBodyFetcher(t, headers[0], []blockchainShim{
+		&mockBlockchain{blocks: invalidBlocks},
+		&mockBlockchain{blocks: blocks},
+	})
+
+	penalized := map[peer.ID]int{}
+	fetcher.penalize = func(peerID peer.ID, penalty network.PeerPenalty) {
+		assert.Equal(t, network.PenaltyInvalidBlock, penalty)
+
+		penalized[peerID]++
+	}
+
+	written := fetchBlocks(t, fetcher, headers[1:])
+
+	// the ranges are downloaded again from the honest peer
+	assertBodies(t, blocks[1:], written)
+	assert.Greater(t, fetcher.failures[peers[0].peer], 0)
+	assert.Equal(t, 0, fetcher.failures[peers[1].peer])
+
+	// only the peer serving the invalid bodies is penalized
+	assert.Equal(t, fetcher.failures[peers[0].peer], penalized[peers[0].peer])
+	assert.Equal(t, 0, penalized[peers[1].peer])
+}
+
+func TestBodyFetcher_NoPeer(t *testing.T) {
+	t.Parallel()
+
+	headers, _, _ := blockchain.NewTestBodyChain(21)
+
+	// the peer doesn't have the bodies
+	fetcher, _ := setupBodyFetcher(t, headers[0], []blockchainShim{
+		&mockBlockchain{blocks: blockchain.HeadersToBlocks(headers)},
+	})
+
+	err := fetcher.fetch(headerRanges(headers[1:], 10), func([]*types.Block, [][]*types.Receipt) error {
+		return nil
+	})
+
+	assert.ErrorIs(t, err, ErrNoBodyPeers)
+}
@@ -0,0 +1,76 @@
+package protocol
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	clockSkewWindow    = 20              // The number of the latest received blocks the skew is checked on
+	clockSkewThreshold = 5 * time.Second // The offset to the local clock beyond which a block is skewed
+)
+
+// clockSkewTracker tracks the offsets of the timestamps of the blocks received from the peers
+// to the local clock. The blocks are broadcast as soon as they are sealed, so the local clock
+// is skewed if the majority of them are further off than the threshold in the same direction
+type clockSkewTracker struct {
+	lock sync.Mutex
+
+	lastNumber uint64          // The number of the latest observed block
+	offsets    []time.Duration // The offsets of the latest observed blocks, positive if ahead of the local clock
+}
+
+func newClockSkewTracker() *clockSkewTracker {
+	return &clockSkewTracker{
+		offsets: make([]time.Duration, 0, clockSkewWindow),
+	}
+}
+
+// observe records the offset of the block received at the given time, the blocks at or below
+// the latest observed one are skipped since every peer broadcasts them. The genesis is never broadcast.
+// Once the window is full, it returns the median offset and whether the local clock is skewed,
+// the window is started over after a skew is reported
+func (c *clockSkewTracker) observe(number, timestamp uint64, now time.Time) (time.Duration, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if number <= c.lastNumber {
+		return 0, false
+	}
+
+	c.lastNumber = number
+
+	if len(c.offsets) == clockSkewWindow {
+		c.offsets = append(c.offsets[:0], c.offsets[1:]...)
+	}
+
+	c.offsets = append(c.offsets, time.Unix(int64(timestamp), 0).Sub(now))
+
+	if len(c.offsets) < clockSkewWindow {
+		return 0, false
+	}
+
+	ahead, behind := 0, 0
+
+	for _, offset := range c.offsets {
+		if offset > clockSkewThreshold {
+			ahead++
+		} else if offset < -clockSkewThreshold {
+			behind++
+		}
+	}
+
+	if ahead <= clockSkewWindow/2 && behind <= clockSkewWindow/2 {
+		return 0, false
+	}
+
+	sorted := append([]time.Duration{}, c.offsets...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	c.offsets = c.offsets[:0]
+
+	return sorted[len(sorted)/2].Round(time.Second), true
+}
@@ -0,0 +1,64 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClockSkewTracker(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	// observeWindow observes a window of blocks from the number, the given ones being off by the offset
+	observeWindow := func(c *clockSkewTracker, from uint64, offset time.Duration, skewed int) (time.Duration, bool) {
+		var (
+			skew time.Duration
+			ok   bool
+		)
+
+		for indx := 0; indx < clockSkewWindow; indx++ {
+			blockTime := now.Add(-time.Second)
+			if indx < skewed {
+				blockTime = now.Add(offset)
+			}
+
+			skew, ok = c.observe(from+uint64(indx), uint64(blockTime.Unix()), now)
+		}
+
+		return skew, ok
+	}
+
+	t.Run("should not report the synchronized clock", func(t *testing.T) {
+		_, skewed := observeWindow(newClockSkewTracker(), 1, 10*time.Second, clockSkewWindow/2)
+		assert.False(t, skewed)
+	})
+
+	t.Run("should report the clock behind the majority of the blocks", func(t *testing.T) {
+		c := newClockSkewTracker()
+
+		skew, skewed := observeWindow(c, 1, 10*time.Second, clockSkewWindow/2+1)
+		assert.True(t, skewed)
+		assert.Equal(t, 10*time.Second, skew)
+
+		// the window is started over
+		assert.Empty(t, c.offsets)
+	})
+
+	t.Run("should report the clock ahead of the majority of the blocks", func(t *testing.T) {
+		skew, skewed := observeWindow(newClockSkewTracker(), 1, -10*time.Second, clockSkewWindow)
+		assert.True(t, skewed)
+		assert.Equal(t, -10*time.Second, skew)
+	})
+
+	t.Run("should skip the blocks already observed", func(t *testing.T) {
+		c := newClockSkewTracker()
+
+		for indx := 0; indx < clockSkewWindow; indx++ {
+			_, skewed := c.observe(1, uint64(now.Add(time.Minute).Unix()), now)
+			assert.False(t, skewed)
+		}
+
+		assert.Len(t, c.offsets, 1)
+	})
+}
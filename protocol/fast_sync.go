@@ -0,0 +1,313 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/helper/progress"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+const (
+	// fastSyncPivotDistance is the distance of the pivot block from the head of the peer.
+	// The blocks up to the pivot are written without being executed, the state of the pivot
+	// is downloaded, and the blocks after it are executed by the bulk sync
+	fastSyncPivotDistance = 64
+
+	// stateSyncLogInterval is the number of synced state items between the progress logs
+	stateSyncLogInterval = 100000
+)
+
+var (
+	ErrFastSyncUnavailable = errors.New("fast sync unavailable")
+	ErrPivotNotFound       = errors.New("pivot header not found")
+	ErrPivotMismatch       = errors.New("written pivot block does not match")
+)
+
+var (
+	// fastSyncPivotKey is the key of the state root of the pivot block, in the state storage.
+	// It is set for as long as the state of the pivot is not fully written
+	fastSyncPivotKey = []byte("fastsyncpivot")
+)
+
+// FastSyncWithPeer syncs the chain with the peer up to a recent pivot block, without executing the blocks.
+// The consensus verifies the headers, and the bodies and the receipts are checked against them.
+// The state of the pivot block is then downloaded from the peers serving it, verifying the hash
+// of every trie node. The blocks after the pivot are left to the bulk sync.
+//
+// The fast sync only runs from the genesis, or to complete an interrupted fast sync.
+// ErrFastSyncUnavailable is returned if the state cannot be downloaded,
+// the bulk sync should be used instead
+func (s *Syncer) FastSyncWithPeer(p *SyncPeer) error {
+	if s.stateStorage == nil {
+		return fmt.Errorf("%w, the state storage is not set", ErrFastSyncUnavailable)
+	}
+
+	header := s.blockchain.Header()
+
+	_, pending := s.fastSyncPivot()
+	if header.Number != 0 && !pending {
+		// the blocks are executed already
+		return nil
+	}
+
+	pivot := header
+
+	if target := p.Number(); target > header.Number+fastSyncPivotDistance {
+		pivotNumber := target - fastSyncPivotDistance
+
+		pivotHeader, err := getHeader(p.client, &pivotNumber, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get the pivot header: %w", err)
+		}
+
+		if pivotHeader == nil {
+			return ErrPivotNotFound
+		}
+
+		pivot = pivotHeader
+	}
+
+	if pivot.Hash == header.Hash && !pending {
+		// close enough to the peer to execute all the blocks
+		return nil
+	}
+
+	peers := s.statePeers(pivot.StateRoot)
+	if len(peers) == 0 {
+		return fmt.Errorf("%w, no peer serves the state", ErrFastSyncUnavailable)
+	}
+
+	s.setFastSyncPivot(pivot.StateRoot)
+	s.syncProgress.start(progress.ChainSyncFast, header.Number)
+
+	if pivot.Number > header.Number {
+		if err := s.fastSyncBlocks(p, pivot); err != nil {
+			return err
+		}
+	}
+
+	if err := s.syncState(peers, pivot.StateRoot); err != nil {
+		return err
+	}
+
+	s.setFastSyncPivot(types.ZeroHash)
+
+	s.logger.Info("fast sync completed", "pivot", pivot.Number, "hash", pivot.Hash)
+
+	return nil
+}
+
+// fastSyncPivot returns the state root of the pivot block of an ongoing fast sync, if any
+func (s *Syncer) fastSyncPivot() (types.Hash, bool) {
+	data, ok := s.stateStorage.Get(fastSyncPivotKey)
+	if !ok || len(data) != types.HashLength {
+		return types.ZeroHash, false
+	}
+
+	return types.BytesToHash(data), true
+}
+
+// setFastSyncPivot sets the state root of the pivot block of an ongoing fast sync,
+// the zero hash clears it
+func (s *Syncer) setFastSyncPivot(root types.Hash) {
+	if root == types.ZeroHash {
+		s.stateStorage.Put(fastSyncPivotKey, []byte{})
+
+		return
+	}
+
+	s.stateStorage.Put(fastSyncPivotKey, root.Bytes())
+}
+
+// statePeers returns the peers serving the state of the passed in root
+func (s *Syncer) statePeers(root types.Hash) []*SyncPeer {
+	peers := []*SyncPeer{}
+	probe := []itrie.SyncItem{{Hash: root}}
+
+	s.peers.Range(func(peerID, peer interface{}) bool {
+		syncPeer, ok := peer.(*SyncPeer)
+		if !ok {
+			return true
+		}
+
+		_, data, err := getStateItems(context.Background(), syncPeer.client, probe)
+		if err != nil || len(data[0]) == 0 {
+			s.logger.Debug("peer does not serve the state", "id", peerID, "root", root, "err", err)
+
+			return true
+		}
+
+		peers = append(peers, syncPeer)
+
+		return true
+	})
+
+	return peers
+}
+
+// fastSyncBlocks writes the blocks of the peer up to the pivot block, without executing them
+func (s *Syncer) fastSyncBlocks(p *SyncPeer, pivot *types.Header) error {
+	ancestor, fork, err := s.findCommonAncestor(p.client, p.status)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("fast syncing blocks", "ancestor", ancestor.Number, "pivot", pivot.Number)
+
+	startBlock := fork
+
+	fetcher := s.newBodyFetcher(s.bodyPeers(p), func(header *types.Header) bool {
+		return header.Number <= pivot.Number
+	})
+
+	for startBlock.Number < pivot.Number {
+		sk := &skeleton{
+			span: 10,
+			num:  5,
+		}
+
+		if err := sk.build(p.client, startBlock.Hash); err != nil {
+			return fmt.Errorf("failed to build skeleton: %w", err)
+		}
+
+		for indx := range sk.slots {
+			if err := sk.fillHeaders(uint64(indx), p.client); err != nil {
+				return fmt.Errorf("failed to fill skeleton: %w", err)
+			}
+		}
+
+		last := startBlock
+
+		if err := fetcher.fetch(sk.ranges(), func(blocks []*types.Block, receipts [][]*types.Receipt) error {
+			if err := s.writeFastSyncBlocks(blocks, receipts, pivot); err != nil {
+				return err
+			}
+
+			if len(blocks) != 0 {
+				last = blocks[len(blocks)-1].Header
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if last.Number <= startBlock.Number {
+			return fmt.Errorf("peer has no blocks past %d", startBlock.Number)
+		}
+
+		startBlock = last
+	}
+
+	// the state is synced for the pivot, it has to be the written block
+	if header, ok := s.blockchain.GetHeaderByNumber(pivot.Number); !ok || header.Hash != pivot.Hash {
+		return ErrPivotMismatch
+	}
+
+	return nil
+}
+
+// writeFastSyncBlocks writes the blocks up to the pivot block, along with their receipts
+func (s *Syncer) writeFastSyncBlocks(blocks []*types.Block, receipts [][]*types.Receipt, pivot *types.Header) error {
+	for indx, block := range blocks {
+		if block.Number() > pivot.Number {
+			break
+		}
+
+		if _, ok := s.blockchain.GetHeaderByHash(block.Hash()); ok {
+			// written already
+			continue
+		}
+
+		if err := s.blockchain.WriteBlockWithReceipts(block, receipts[indx]); err != nil {
+			return fmt.Errorf("failed to write fast sync blocks: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// syncState downloads the state of the root from the peers, until it is fully written.
+// The peers failing to serve the state are not used anymore
+func (s *Syncer) syncState(peers []*SyncPeer, root types.Hash) error {
+	sync := itrie.NewStateSync(root, s.stateStorage)
+
+	s.logger.Info("syncing the state", "root", root, "peers", len(peers))
+
+	var (
+		next       int
+		lastSynced uint64
+	)
+
+	dropPeer := func(p *SyncPeer) {
+		for indx, peer := range peers {
+			if peer == p {
+				peers = append(peers[:indx], peers[indx+1:]...)
+
+				break
+			}
+		}
+	}
+
+	for !sync.Done() {
+		items, err := sync.Missing(maxStateItemsAmount)
+		if err != nil {
+			return fmt.Errorf("failed to walk the state: %w", err)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+
+		if len(peers) == 0 {
+			return fmt.Errorf("%w, no peer serves the state", ErrFastSyncUnavailable)
+		}
+
+		peer := peers[next%len(peers)]
+		next++
+
+		if err := s.processStateItems(sync, peer, items); err != nil {
+			s.logger.Warn("failed to sync the state from peer", "id", peer.peer, "err", err)
+
+			sync.Retry(items)
+			dropPeer(peer)
+
+			continue
+		}
+
+		s.syncProgress.updateStates(sync.Synced()+sync.Pending(), sync.Synced())
+
+		if synced := sync.Synced(); synced-lastSynced >= stateSyncLogInterval {
+			s.logger.Info("syncing the state", "root", root, "items", synced)
+
+			lastSynced = synced
+		}
+	}
+
+	s.logger.Info("synced the state", "root", root, "items", sync.Synced())
+
+	return nil
+}
+
+// processStateItems requests the state items from the peer, and writes them
+func (s *Syncer) processStateItems(sync *itrie.StateSync, p *SyncPeer, items []itrie.SyncItem) error {
+	served, data, err := getStateItems(context.Background(), p.client, items)
+	if err != nil {
+		return err
+	}
+
+	for indx, item := range served {
+		if len(data[indx]) == 0 {
+			return fmt.Errorf("state item %s not found", item.Hash)
+		}
+
+		if err := sync.Process(item.Hash, data[indx]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package protocol
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestState commits a few accounts with storage to a new state storage
+func newTestState(t *testing.T) (itrie.Storage, types.Hash) {
+	t.Helper()
+
+	storage := itrie.NewMemoryStorage()
+	objs := []*state.Object{}
+
+	for i := 0; i < 20; i++ {
+		obj := &state.Object{
+			Address: types.BytesToAddress([]byte{byte(i + 1)}),
+			Balance: big.NewInt(int64(i + 1)),
+			Root:    types.EmptyRootHash,
+		}
+
+		for j := 0; j < 10; j++ {
+			obj.Storage = append(obj.Storage, &state.StorageObject{
+				Key: types.BytesToHash([]byte{byte(j)}).Bytes(),
+				Val: types.BytesToHash([]byte{byte(i + j + 1)}).Bytes(),
+			})
+		}
+
+		objs = append(objs, obj)
+	}
+
+	_, root := itrie.NewState(storage).NewSnapshot().Commit(objs)
+
+	return storage, types.BytesToHash(root)
+}
+
+// newTestStateHeaderChain creates a chain of headers with the passed in state root
+func newTestStateHeaderChain(n int, root types.Hash) []*types.Header {
+	headers := make([]*types.Header, 0, n)
+
+	for i := 0; i < n; i++ {
+		header := &types.Header{
+			Number:       uint64(i),
+			TxRoot:       types.EmptyRootHash,
+			Sha3Uncles:   types.EmptyUncleHash,
+			ReceiptsRoot: types.EmptyRootHash,
+			Difficulty:   uint64(i),
+		}
+
+		if i != 0 {
+			// the genesis state is the same in both nodes
+			header.StateRoot = root
+			header.ParentHash = headers[i-1].Hash
+		}
+
+		headers = append(headers, header.ComputeHash())
+	}
+
+	return headers
+}
+
+// createStateSyncer creates a syncer serving the passed in state storage
+func createStateSyncer(t *testing.T, chain blockchainShim, storage itrie.Storage) *Syncer {
+	t.Helper()
+
+	srv, createErr := network.CreateServer(&network.CreateServerParams{
+		ConfigCallback: defaultNetworkConfig,
+	})
+	if createErr != nil {
+		t.Fatalf("Unable to create networking server, %v", createErr)
+	}
+
+	syncer := NewSyncer(hclog.NewNullLogger(), srv, chain)
+	syncer.SetStateStorage(storage)
+	syncer.Start()
+
+	return syncer
+}
+
+// setupFastSyncNetwork connects a syncer with an empty state to the peer syncers
+func setupFastSyncNetwork(
+	t *testing.T,
+	chain blockchainShim,
+	peerChain blockchainShim,
+	peerStorage itrie.Storage,
+) (*Syncer, *Syncer) {
+	t.Helper()
+
+	syncer := createStateSyncer(t, chain, itrie.NewMemoryStorage())
+	peerSyncer := createStateSyncer(t, peerChain, peerStorage)
+
+	if joinErr := network.JoinAndWait(
+		syncer.server,
+		peerSyncer.server,
+		network.DefaultBufferTimeout,
+		network.DefaultJoinTimeout,
+	); joinErr != nil {
+		t.Fatalf("Unable to join servers, %v", joinErr)
+	}
+
+	WaitUntilPeerConnected(t, syncer, 1, 10*time.Second)
+
+	return syncer, peerSyncer
+}
+
+func TestFastSyncWithPeer(t *testing.T) {
+	t.Parallel()
+
+	peerStorage, root := newTestState(t)
+	peerHeaders := newTestStateHeaderChain(100, root)
+
+	chain, peerChain := NewMockBlockchain(peerHeaders[:1]), NewMockBlockchain(peerHeaders)
+	syncer, peerSyncer := setupFastSyncNetwork(t, chain, peerChain, peerStorage)
+
+	peer := getPeer(syncer, peerSyncer.server.AddrInfo().ID)
+	assert.NotNil(t, peer)
+
+	assert.NoError(t, syncer.FastSyncWithPeer(peer))
+
+	// the blocks are written up to the pivot
+	pivot := peerHeaders[len(peerHeaders)-1-fastSyncPivotDistance]
+	assert.Equal(t, pivot.Hash, chain.Header().Hash)
+	assert.Equal(t, peerChain.blocks[:pivot.Number+1], chain.blocks)
+
+	// the state of the pivot is fully written
+	_, pending := syncer.fastSyncPivot()
+	assert.False(t, pending)
+
+	snap, err := itrie.NewState(syncer.stateStorage).NewSnapshotAt(root)
+	assert.NoError(t, err)
+
+	for i := 0; i < 20; i++ {
+		_, ok := snap.Get(crypto.Keccak256(types.BytesToAddress([]byte{byte(i + 1)}).Bytes()))
+		assert.True(t, ok)
+	}
+
+	sync := itrie.NewStateSync(root, syncer.stateStorage)
+
+	items, err := sync.Missing(maxStateItemsAmount)
+	assert.NoError(t, err)
+	assert.Len(t, items, 0)
+
+	// the fast sync is done, the remaining blocks are left to the bulk sync
+	assert.NoError(t, syncer.FastSyncWithPeer(peer))
+	assert.Equal(t, pivot.Hash, chain.Header().Hash)
+}
+
+func TestFastSyncWithPeer_StateNotServed(t *testing.T) {
+	t.Parallel()
+
+	_, root := newTestState(t)
+	peerHeaders := newTestStateHeaderChain(100, root)
+
+	chain, peerChain := NewMockBlockchain(peerHeaders[:1]), NewMockBlockchain(peerHeaders)
+
+	// the peer doesn't have the state of the pivot
+	syncer, peerSyncer := setupFastSyncNetwork(t, chain, peerChain, itrie.NewMemoryStorage())
+
+	peer := getPeer(syncer, peerSyncer.server.AddrInfo().ID)
+	assert.NotNil(t, peer)
+
+	assert.ErrorIs(t, syncer.FastSyncWithPeer(peer), ErrFastSyncUnavailable)
+
+	// nothing is written, the node falls back to the bulk sync
+	assert.Len(t, chain.blocks, 1)
+
+	_, pending := syncer.fastSyncPivot()
+	assert.False(t, pending)
+}
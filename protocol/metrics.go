@@ -0,0 +1,62 @@
+package protocol
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	prometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// peerIDLabel is the label of the per peer syncer metrics
+const peerIDLabel = "peer_id"
+
+// Metrics represents the syncer metrics
+type Metrics struct {
+	// No.of blocks whose bodies were downloaded from the peer
+	DownloadedBlocks metrics.Counter
+	// Blocks per second served by the peer in its latest body response
+	PeerThroughput metrics.Gauge
+	// No.of body requests to the peer that failed or timed out
+	FailedBodyRequests metrics.Counter
+}
+
+// GetPrometheusMetrics return the syncer metrics instance
+func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics {
+	labels := []string{}
+
+	for i := 0; i < len(labelsWithValues); i += 2 {
+		labels = append(labels, labelsWithValues[i])
+	}
+
+	labels = append(labels, peerIDLabel)
+
+	return &Metrics{
+		DownloadedBlocks: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "downloaded_blocks",
+			Help:      "Number of blocks whose bodies were downloaded from the peer.",
+		}, labels).With(labelsWithValues...),
+		PeerThroughput: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "peer_throughput",
+			Help:      "Blocks per second served by the peer in its latest body response.",
+		}, labels).With(labelsWithValues...),
+		FailedBodyRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "syncer",
+			Name:      "failed_body_requests",
+			Help:      "Number of body requests to the peer that failed or timed out.",
+		}, labels).With(labelsWithValues...),
+	}
+}
+
+// NilMetrics will return the non operational syncer metrics
+func NilMetrics() *Metrics {
+	return &Metrics{
+		DownloadedBlocks:   discard.NewCounter(),
+		PeerThroughput:     discard.NewGauge(),
+		FailedBodyRequests: discard.NewCounter(),
+	}
+}
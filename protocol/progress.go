@@ -0,0 +1,100 @@
+package protocol
+
+import (
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/helper/progress"
+)
+
+// syncProgress is the progress of the node syncing with the network.
+// Unlike the progression of a single sync batch, it is kept across the batches,
+// until the node reaches the highest block known from the peers
+type syncProgress struct {
+	lock sync.RWMutex
+
+	// syncing is set once a sync batch starts, and reset once the node reaches the highest block
+	syncing  bool
+	syncType progress.ChainSyncType
+
+	// startingBlock is the block the node started the sync from
+	startingBlock uint64
+
+	// highestBlock is the highest block known from the status of the peers
+	highestBlock uint64
+
+	// knownStates and pulledStates are the state trie nodes known and written by the fast sync
+	knownStates  uint64
+	pulledStates uint64
+}
+
+// start marks the start of a sync batch. The starting block is only set by the first batch of the sync
+func (p *syncProgress) start(syncType progress.ChainSyncType, startingBlock uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if !p.syncing {
+		p.syncing = true
+		p.startingBlock = startingBlock
+	}
+
+	p.syncType = syncType
+}
+
+// updateHighest raises the highest block known from the peers
+func (p *syncProgress) updateHighest(number uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if number > p.highestBlock {
+		p.highestBlock = number
+	}
+}
+
+// updateCurrent ends the sync once the written block reaches the highest block
+func (p *syncProgress) updateCurrent(number uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.syncing && number >= p.highestBlock {
+		p.syncing = false
+		p.knownStates = 0
+		p.pulledStates = 0
+	}
+}
+
+// updateStates sets the state trie nodes known and written by the fast sync
+func (p *syncProgress) updateStates(known, pulled uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.knownStates = known
+	p.pulledStates = pulled
+}
+
+// progression returns a snapshot of the progress for the current block,
+// nil if the node is not behind the highest block
+func (p *syncProgress) progression(currentBlock uint64) *progress.Progression {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if currentBlock >= p.highestBlock {
+		return nil
+	}
+
+	// the sync starts from the current block, until the first sync batch starts
+	progression := &progress.Progression{
+		SyncType:      progress.ChainSyncBulk,
+		StartingBlock: currentBlock,
+		CurrentBlock:  currentBlock,
+		HighestBlock:  p.highestBlock,
+		KnownStates:   p.knownStates,
+		PulledStates:  p.pulledStates,
+	}
+
+	if p.syncing {
+		progression.SyncType = p.syncType
+		progression.StartingBlock = p.startingBlock
+	}
+
+	return progression
+}
@@ -4,9 +4,23 @@ import "github.com/0xPolygon/polygon-edge/types"
 
 // DecodeHashes decode to types Hash in the request
 func (h *HashRequest) DecodeHashes() ([]types.Hash, error) {
+	return decodeHashes(h.Hash)
+}
+
+// DecodeNodes decodes the hashes of the trie nodes in the request
+func (s *StateRequest) DecodeNodes() ([]types.Hash, error) {
+	return decodeHashes(s.Nodes)
+}
+
+// DecodeCodes decodes the hashes of the contract code in the request
+func (s *StateRequest) DecodeCodes() ([]types.Hash, error) {
+	return decodeHashes(s.Codes)
+}
+
+func decodeHashes(hashes []string) ([]types.Hash, error) {
 	resp := []types.Hash{}
 
-	for _, h := range h.Hash {
+	for _, h := range hashes {
 		hh := types.Hash{}
 		if err := hh.UnmarshalText([]byte(h)); err != nil {
 			return nil, err
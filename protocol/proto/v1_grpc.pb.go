@@ -23,6 +23,7 @@ type V1Client interface {
 	GetObjectsByHash(ctx context.Context, in *HashRequest, opts ...grpc.CallOption) (*Response, error)
 	GetHeaders(ctx context.Context, in *GetHeadersRequest, opts ...grpc.CallOption) (*Response, error)
 	Notify(ctx context.Context, in *NotifyReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetState(ctx context.Context, in *StateRequest, opts ...grpc.CallOption) (*Response, error)
 }
 
 type v1Client struct {
@@ -69,6 +70,15 @@ func (c *v1Client) Notify(ctx context.Context, in *NotifyReq, opts ...grpc.CallO
 	return out, nil
 }
 
+func (c *v1Client) GetState(ctx context.Context, in *StateRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/v1.V1/GetState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // V1Server is the server API for V1 service.
 // All implementations must embed UnimplementedV1Server
 // for forward compatibility
@@ -77,6 +87,7 @@ type V1Server interface {
 	GetObjectsByHash(context.Context, *HashRequest) (*Response, error)
 	GetHeaders(context.Context, *GetHeadersRequest) (*Response, error)
 	Notify(context.Context, *NotifyReq) (*emptypb.Empty, error)
+	GetState(context.Context, *StateRequest) (*Response, error)
 	mustEmbedUnimplementedV1Server()
 }
 
@@ -96,6 +107,9 @@ func (UnimplementedV1Server) GetHeaders(context.Context, *GetHeadersRequest) (*R
 func (UnimplementedV1Server) Notify(context.Context, *NotifyReq) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Notify not implemented")
 }
+func (UnimplementedV1Server) GetState(context.Context, *StateRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetState not implemented")
+}
 func (UnimplementedV1Server) mustEmbedUnimplementedV1Server() {}
 
 // UnsafeV1Server may be embedded to opt out of forward compatibility for this service.
@@ -181,6 +195,24 @@ func _V1_Notify_Handler(srv interface{}, ctx context.Context, dec func(interface
 	return interceptor(ctx, in, info, handler)
 }
 
+func _V1_GetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(V1Server).GetState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.V1/GetState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(V1Server).GetState(ctx, req.(*StateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // V1_ServiceDesc is the grpc.ServiceDesc for V1 service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -204,6 +236,10 @@ var V1_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Notify",
 			Handler:    _V1_Notify_Handler,
 		},
+		{
+			MethodName: "GetState",
+			Handler:    _V1_GetState_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "v1.proto",
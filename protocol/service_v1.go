@@ -7,6 +7,7 @@ import (
 
 	"github.com/0xPolygon/polygon-edge/network/grpc"
 	"github.com/0xPolygon/polygon-edge/protocol/proto"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -22,6 +23,7 @@ type serviceV1 struct {
 	logger hclog.Logger
 
 	store blockchainShim
+	state itrie.Storage
 }
 
 type rlpObject interface {
@@ -172,6 +174,57 @@ func (s *serviceV1) GetHeaders(_ context.Context, req *proto.GetHeadersRequest)
 	return resp, nil
 }
 
+const maxStateItemsAmount = 384
+
+var errStateNotServed = errors.New("state is not served")
+
+// GetState implements the V1Server interface.
+// The data of the items that are not found is empty
+func (s *serviceV1) GetState(_ context.Context, req *proto.StateRequest) (*proto.Response, error) {
+	if s.state == nil {
+		return nil, errStateNotServed
+	}
+
+	if len(req.Nodes)+len(req.Codes) > maxStateItemsAmount {
+		return nil, fmt.Errorf("cannot request more than %d state items", maxStateItemsAmount)
+	}
+
+	nodes, err := req.DecodeNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := req.DecodeCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.Response{
+		Objs: make([]*proto.Response_Component, 0, len(nodes)+len(codes)),
+	}
+	addData := func(data []byte, ok bool) {
+		if !ok {
+			data = []byte{}
+		}
+
+		resp.Objs = append(resp.Objs, &proto.Response_Component{
+			Spec: &anypb.Any{
+				Value: data,
+			},
+		})
+	}
+
+	for _, hash := range nodes {
+		addData(s.state.Get(hash.Bytes()))
+	}
+
+	for _, hash := range codes {
+		addData(s.state.GetCode(hash))
+	}
+
+	return resp, nil
+}
+
 // Helper functions to decode responses from the grpc layer
 func getBodies(ctx context.Context, clt proto.V1Client, hashes []types.Hash) ([]*types.Body, error) {
 	input := make([]string, 0, len(hashes))
@@ -204,3 +257,78 @@ func getBodies(ctx context.Context, clt proto.V1Client, hashes []types.Hash) ([]
 
 	return res, nil
 }
+
+func getReceipts(ctx context.Context, clt proto.V1Client, hashes []types.Hash) ([][]*types.Receipt, error) {
+	input := make([]string, 0, len(hashes))
+
+	for _, h := range hashes {
+		input = append(input, h.String())
+	}
+
+	resp, err := clt.GetObjectsByHash(ctx, &proto.HashRequest{Hash: input, Type: proto.HashRequest_RECEIPTS})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([][]*types.Receipt, 0, len(resp.Objs))
+
+	for _, obj := range resp.Objs {
+		var receipts types.Receipts
+		if obj.Spec.Value != nil {
+			if err := receipts.UnmarshalRLP(obj.Spec.Value); err != nil {
+				return nil, err
+			}
+		}
+
+		res = append(res, receipts)
+	}
+
+	if len(res) != len(input) {
+		return nil, fmt.Errorf("not correct size")
+	}
+
+	return res, nil
+}
+
+// getStateItems returns the data of the state items, along with the items in the order they are served
+func getStateItems(
+	ctx context.Context,
+	clt proto.V1Client,
+	items []itrie.SyncItem,
+) ([]itrie.SyncItem, [][]byte, error) {
+	req := &proto.StateRequest{}
+
+	// the nodes are served first, then the code
+	ordered := make([]itrie.SyncItem, 0, len(items))
+
+	for _, item := range items {
+		if !item.Code {
+			req.Nodes = append(req.Nodes, item.Hash.String())
+			ordered = append(ordered, item)
+		}
+	}
+
+	for _, item := range items {
+		if item.Code {
+			req.Codes = append(req.Codes, item.Hash.String())
+			ordered = append(ordered, item)
+		}
+	}
+
+	resp, err := clt.GetState(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(resp.Objs) != len(ordered) {
+		return nil, nil, fmt.Errorf("not correct size")
+	}
+
+	res := make([][]byte, 0, len(resp.Objs))
+
+	for _, obj := range resp.Objs {
+		res = append(res, obj.Spec.Value)
+	}
+
+	return ordered, res, nil
+}
@@ -51,7 +51,8 @@ func (s *skeleton) build(clt proto.V1Client, ancestor types.Hash) error {
 	return nil
 }
 
-func (s *skeleton) fillSlot(indx uint64, clt proto.V1Client) error {
+// fillHeaders gets the headers of the slot, the bodies are downloaded separately
+func (s *skeleton) fillHeaders(indx uint64, clt proto.V1Client) error {
 	slot := s.slots[indx]
 	req := &proto.GetHeadersRequest{
 		Hash:   slot.hash.String(),
@@ -71,31 +72,18 @@ func (s *skeleton) fillSlot(indx uint64, clt proto.V1Client) error {
 		})
 	}
 
-	// for each header with body we request it
-	bodyHashes := []types.Hash{}
-	bodyIndex := []int{}
-
-	for indx, h := range resp {
-		if h.TxRoot != types.EmptyRootHash {
-			bodyHashes = append(bodyHashes, h.Hash)
-			bodyIndex = append(bodyIndex, indx)
-		}
-	}
-
-	if len(bodyHashes) == 0 {
-		return nil
-	}
+	return nil
+}
 
-	bodies, err := getBodies(context.Background(), clt, bodyHashes)
-	if err != nil {
-		return err
-	}
+// ranges returns the blocks of the slots
+func (s *skeleton) ranges() [][]*types.Block {
+	ranges := make([][]*types.Block, 0, len(s.slots))
 
-	for indx, body := range bodies {
-		slot.blocks[bodyIndex[indx]].Transactions = body.Transactions
+	for _, slot := range s.slots {
+		ranges = append(ranges, slot.blocks)
 	}
 
-	return nil
+	return ranges
 }
 
 func (s *skeleton) addSkeleton(headers []*types.Header) error {
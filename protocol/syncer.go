@@ -15,6 +15,7 @@ import (
 	"github.com/0xPolygon/polygon-edge/network"
 	libp2pGrpc "github.com/0xPolygon/polygon-edge/network/grpc"
 	"github.com/0xPolygon/polygon-edge/protocol/proto"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -27,6 +28,10 @@ import (
 const (
 	maxEnqueueSize = 50
 	popTimeout     = 10 * time.Second
+
+	// maxFutureBlockWait is the longest the syncer waits for the local clock to catch up
+	// with a block ahead of it, the block is rejected if it is further ahead
+	maxFutureBlockWait = 30 * time.Second
 )
 
 var (
@@ -36,6 +41,8 @@ var (
 	ErrForkNotFound           = errors.New("fork not found")
 	ErrPopTimeout             = errors.New("timeout")
 	ErrConnectionClosed       = errors.New("connection closed")
+
+	errBulkSyncStopped = errors.New("bulk sync stopped")
 )
 
 // SyncPeer is a representation of the peer the node is syncing with
@@ -209,30 +216,52 @@ type Syncer struct {
 
 	server *network.Server
 
-	syncProgression *progress.ProgressionWrapper
+	// stateStorage is the storage of the state trie, it is served to the peers
+	// and written by the fast sync. The state is not served if it's not set
+	stateStorage itrie.Storage
+
+	metrics *Metrics
+
+	// syncProgress is the progress of the sync with the network, reported by eth_syncing
+	syncProgress *syncProgress
+
+	// clockSkew tracks the timestamps of the received blocks against the local clock
+	clockSkew *clockSkewTracker
 }
 
 // NewSyncer creates a new Syncer instance
 func NewSyncer(logger hclog.Logger, server *network.Server, blockchain blockchainShim) *Syncer {
 	s := &Syncer{
-		logger:          logger.Named("syncer"),
-		stopCh:          make(chan struct{}),
-		blockchain:      blockchain,
-		server:          server,
-		syncProgression: progress.NewProgressionWrapper(progress.ChainSyncBulk),
+		logger:       logger.Named("syncer"),
+		stopCh:       make(chan struct{}),
+		blockchain:   blockchain,
+		server:       server,
+		metrics:      NilMetrics(),
+		syncProgress: &syncProgress{},
+		clockSkew:    newClockSkewTracker(),
 	}
 
 	return s
 }
 
-// GetSyncProgression returns the latest sync progression, if any
+// SetStateStorage sets the storage of the state trie
+func (s *Syncer) SetStateStorage(storage itrie.Storage) {
+	s.stateStorage = storage
+}
+
+// SetMetrics sets the metrics of the syncer
+func (s *Syncer) SetMetrics(metrics *Metrics) {
+	s.metrics = metrics
+}
+
+// GetSyncProgression returns a snapshot of the sync progression,
+// nil if the node is not behind the highest block known from the peers
 func (s *Syncer) GetSyncProgression() *progress.Progression {
-	return s.syncProgression.GetProgression()
+	return s.syncProgress.progression(s.blockchain.Header().Number)
 }
 
 // syncCurrentStatus taps into the blockchain event steam and updates the Syncer.status field
-func (s *Syncer) syncCurrentStatus() {
-	sub := s.blockchain.SubscribeEvents()
+func (s *Syncer) syncCurrentStatus(sub blockchain.Subscription) {
 	eventCh := sub.GetEventCh()
 
 	// watch the subscription and notify
@@ -259,6 +288,8 @@ func (s *Syncer) syncCurrentStatus() {
 			s.status = status
 			s.statusLock.Unlock()
 
+			s.syncProgress.updateCurrent(s.blockchain.Header().Number)
+
 		case <-s.stopCh:
 			sub.Close()
 
@@ -273,6 +304,8 @@ const syncerV1 = "/syncer/0.1"
 func (s *Syncer) enqueueBlock(peerID peer.ID, b *types.Block) {
 	s.logger.Debug("enqueue block", "peer", peerID, "number", b.Number(), "hash", b.Hash())
 
+	s.checkClockSkew(b.Header)
+
 	peer, ok := s.peers.Load(peerID)
 	if ok {
 		syncPeer, ok := peer.(*SyncPeer)
@@ -308,6 +341,7 @@ func (s *Syncer) updatePeerStatus(peerID peer.ID, status *Status) {
 		}
 
 		syncPeer.updateStatus(status)
+		s.syncProgress.updateHighest(status.Number)
 	}
 }
 
@@ -345,7 +379,12 @@ func (s *Syncer) Broadcast(b *types.Block) {
 
 // Start starts the syncer protocol
 func (s *Syncer) Start() {
-	s.serviceV1 = &serviceV1{syncer: s, logger: hclog.NewNullLogger(), store: s.blockchain}
+	s.serviceV1 = &serviceV1{
+		syncer: s,
+		logger: hclog.NewNullLogger(),
+		store:  s.blockchain,
+		state:  s.stateStorage,
+	}
 
 	// Get the current status of the syncer
 	currentHeader := s.blockchain.Header()
@@ -357,8 +396,8 @@ func (s *Syncer) Start() {
 		Difficulty: diff,
 	}
 
-	// Run the blockchain event listener loop
-	go s.syncCurrentStatus()
+	// Run the blockchain event listener loop, the events written after the start are not missed
+	go s.syncCurrentStatus(s.blockchain.SubscribeEventStream())
 
 	// Register the grpc protocol for syncer
 	grpcStream := libp2pGrpc.NewGrpcStream()
@@ -486,6 +525,8 @@ func (s *Syncer) AddPeer(peerID peer.ID) error {
 		enqueueCh: make(chan struct{}),
 	})
 
+	s.syncProgress.updateHighest(status.Number)
+
 	return nil
 }
 
@@ -603,8 +644,9 @@ func (s *Syncer) WatchSyncWithPeer(p *SyncPeer, handler func(b *types.Block) boo
 			break
 		}
 
-		if err := s.blockchain.WriteBlock(b); err != nil {
+		if err := s.writeBlock(b); err != nil {
 			s.logger.Error("failed to write block", "err", err)
+			s.penalizeInvalidBlock(p.peer, b, err)
 
 			break
 		}
@@ -615,6 +657,69 @@ func (s *Syncer) WatchSyncWithPeer(p *SyncPeer, handler func(b *types.Block) boo
 	}
 }
 
+// writeBlock writes the block to the blockchain. The block too far ahead of the local clock
+// is written again once the local clock catches up with its timestamp, instead of being rejected
+func (s *Syncer) writeBlock(block *types.Block) error {
+	err := s.blockchain.WriteBlock(block, blockchain.SourceSyncer)
+	if !errors.Is(err, blockchain.ErrFutureBlock) {
+		return err
+	}
+
+	wait := time.Until(time.Unix(int64(block.Header.Timestamp), 0))
+	if wait > maxFutureBlockWait {
+		return err
+	}
+
+	s.logger.Warn("block ahead of the local clock, retrying later", "number", block.Number(), "wait", wait)
+
+	select {
+	case <-time.After(wait):
+	case <-s.stopCh:
+		return err
+	}
+
+	return s.blockchain.WriteBlock(block, blockchain.SourceSyncer)
+}
+
+// checkClockSkew checks the timestamp of the received block against the local clock,
+// and warns if the local clock is skewed relative to the majority of the received blocks
+func (s *Syncer) checkClockSkew(header *types.Header) {
+	skew, skewed := s.clockSkew.observe(header.Number, header.Timestamp, time.Now())
+	if !skewed {
+		return
+	}
+
+	direction := "behind"
+	if skew < 0 {
+		direction, skew = "ahead of", -skew
+	}
+
+	s.logger.Warn(fmt.Sprintf(
+		"the local clock appears to be %s %s the timestamps of the majority of the received blocks, "+
+			"please check the clock of the node is synchronized",
+		skew, direction,
+	))
+}
+
+// penalizeInvalidBlock penalizes the peer for the block that failed the verification.
+// The blocks that don't extend the local chain, that are already written,
+// or that are ahead of the local clock are not counted
+func (s *Syncer) penalizeInvalidBlock(peerID peer.ID, block *types.Block, err error) {
+	if errors.Is(err, blockchain.ErrClosed) || errors.Is(err, blockchain.ErrFutureBlock) {
+		return
+	}
+
+	if _, ok := s.blockchain.GetHeaderByHash(block.ParentHash()); !ok {
+		return
+	}
+
+	if _, ok := s.blockchain.GetHeaderByHash(block.Hash()); ok {
+		return
+	}
+
+	s.server.PenalizePeer(peerID, network.PenaltyInvalidBlock)
+}
+
 func (s *Syncer) logSyncPeerPopBlockError(err error, peer *SyncPeer) {
 	if errors.Is(err, ErrPopTimeout) {
 		msg := "failed to pop block within %ds from peer: id=%s, please check if all the validators are running"
@@ -624,8 +729,10 @@ func (s *Syncer) logSyncPeerPopBlockError(err error, peer *SyncPeer) {
 	}
 }
 
-// BulkSyncWithPeer finds common ancestor with a peer and syncs block until latest block
-func (s *Syncer) BulkSyncWithPeer(p *SyncPeer, newBlockHandler func(block *types.Block)) error {
+// BulkSyncWithPeer finds common ancestor with a peer and syncs block until latest block.
+// The headers are taken from the peer, the bodies are downloaded concurrently from all the peers.
+// The sync stops after the written block for which the handler returns true
+func (s *Syncer) BulkSyncWithPeer(p *SyncPeer, newBlockHandler func(block *types.Block) bool) error {
 	// find the common ancestor
 	ancestor, fork, err := s.findCommonAncestor(p.client, p.status)
 	if err != nil {
@@ -639,18 +746,16 @@ func (s *Syncer) BulkSyncWithPeer(p *SyncPeer, newBlockHandler func(block *types
 
 	var lastTarget uint64
 
-	// Create a blockchain subscription for the sync progression and start tracking
-	s.syncProgression.StartProgression(startBlock.Number, s.blockchain.SubscribeEvents())
+	s.syncProgress.start(progress.ChainSyncBulk, startBlock.Number)
 
-	// Stop monitoring the sync progression upon exit
-	defer s.syncProgression.StopProgression()
+	fetcher := s.newBodyFetcher(s.bodyPeers(p), nil)
 
 	// sync up to the current known header
 	for {
 		// update target
-		target := p.status.Number
+		target := p.Number()
 
-		s.syncProgression.UpdateHighestProgression(target)
+		s.syncProgress.updateHighest(target)
 
 		if target == lastTarget {
 			// there are no more changes to pull for now
@@ -672,18 +777,40 @@ func (s *Syncer) BulkSyncWithPeer(p *SyncPeer, newBlockHandler func(block *types
 
 			// fill skeleton
 			for indx := range sk.slots {
-				sk.fillSlot(uint64(indx), p.client) //nolint
+				if err := sk.fillHeaders(uint64(indx), p.client); err != nil {
+					return fmt.Errorf("failed to fill skeleton: %w", err)
+				}
 			}
 
 			// sync the data
-			for _, slot := range sk.slots {
-				for _, block := range slot.blocks {
-					if err := s.blockchain.WriteBlock(block); err != nil {
+			if err := fetcher.fetch(sk.ranges(), func(blocks []*types.Block, _ [][]*types.Receipt) error {
+				// the seals are recovered concurrently, the blocks are still verified and written in order
+				headers := make([]*types.Header, len(blocks))
+				for indx, block := range blocks {
+					headers[indx] = block.Header
+				}
+
+				s.blockchain.RecoverSeals(headers)
+
+				for _, block := range blocks {
+					if err := s.writeBlock(block); err != nil {
+						s.penalizeInvalidBlock(p.peer, block, err)
+
 						return fmt.Errorf("failed to write bulk sync blocks: %w", err)
 					}
 
-					newBlockHandler(block)
+					if newBlockHandler(block) {
+						return errBulkSyncStopped
+					}
+				}
+
+				return nil
+			}); err != nil {
+				if errors.Is(err, errBulkSyncStopped) {
+					return nil
 				}
+
+				return err
 			}
 
 			// try to get the next block
@@ -8,10 +8,12 @@ import (
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/helper/progress"
 	"github.com/0xPolygon/polygon-edge/helper/tests"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -130,7 +132,7 @@ func TestBroadcast(t *testing.T) {
 			newBlocks := GenerateNewBlocks(t, peerSyncer.blockchain, tt.numNewBlocks)
 
 			for _, newBlock := range newBlocks {
-				assert.NoError(t, peerSyncer.blockchain.WriteBlock(newBlock))
+				assert.NoError(t, peerSyncer.blockchain.WriteBlock(newBlock, blockchain.SourceSealer))
 			}
 
 			for _, newBlock := range newBlocks {
@@ -313,7 +315,7 @@ func TestWatchSyncWithPeer(t *testing.T) {
 			newBlocks := GenerateNewBlocks(t, peerChain, tt.numNewBlocks)
 
 			for _, newBlock := range newBlocks {
-				assert.NoError(t, peerSyncer.blockchain.WriteBlock(newBlock))
+				assert.NoError(t, peerSyncer.blockchain.WriteBlock(newBlock, blockchain.SourceSealer))
 			}
 
 			for _, b := range newBlocks {
@@ -383,8 +385,10 @@ func TestBulkSyncWithPeer(t *testing.T) {
 			syncer, peerSyncers := SetupSyncerNetwork(t, chain, []blockchainShim{peerChain})
 			peerSyncer := peerSyncers[0]
 			var handledNewBlocks []*types.Block
-			newBlocksHandler := func(block *types.Block) {
+			newBlocksHandler := func(block *types.Block) bool {
 				handledNewBlocks = append(handledNewBlocks, block)
+
+				return false
 			}
 
 			peer := getPeer(syncer, peerSyncer.server.AddrInfo().ID)
@@ -409,6 +413,29 @@ func TestBulkSyncWithPeer(t *testing.T) {
 	}
 }
 
+func TestBulkSyncWithPeer_Stop(t *testing.T) {
+	t.Parallel()
+
+	chain := NewMockBlockchain(blockchain.NewTestHeaderChainWithSeed(nil, 10, 0))
+	peerChain := NewMockBlockchain(blockchain.NewTestHeaderChainWithSeed(nil, 30, 0))
+	syncer, peerSyncers := SetupSyncerNetwork(t, chain, []blockchainShim{peerChain})
+
+	peer := getPeer(syncer, peerSyncers[0].server.AddrInfo().ID)
+	assert.NotNil(t, peer)
+
+	// the sync stops after the block for which the handler returns true
+	handled := 0
+	err := syncer.BulkSyncWithPeer(peer, func(block *types.Block) bool {
+		handled++
+
+		return block.Number() == 15
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 6, handled)
+	assert.Equal(t, peerChain.blocks[:16], chain.blocks)
+}
+
 func TestSyncer_GetSyncProgression(t *testing.T) {
 	initialChainSize := 10
 	targetChainSize := 1000
@@ -420,26 +447,50 @@ func TestSyncer_GetSyncProgression(t *testing.T) {
 	syncHeaders := blockchain.NewTestHeaderChainWithSeed(nil, targetChainSize, 0)
 	syncBlocks := blockchain.HeadersToBlocks(syncHeaders)
 
-	syncer.syncProgression.StartProgression(uint64(initialChainSize), syncerChain.SubscribeEvents())
+	// the node is not behind any peer
+	assert.Nil(t, syncer.GetSyncProgression())
+
+	// the highest block is known from the status of the peers, before the sync starts
+	syncer.updatePeerStatus(peer.ID("peer"), &Status{Number: uint64(targetChainSize - 1)})
+	assert.Nil(t, syncer.GetSyncProgression())
+
+	syncer.syncProgress.updateHighest(uint64(targetChainSize - 1))
 
-	if syncer.GetSyncProgression() == nil {
+	progression := syncer.GetSyncProgression()
+	if progression == nil {
 		t.Fatalf("Unable to start progression")
 	}
 
-	assert.Equal(t, uint64(initialChainSize), syncer.syncProgression.GetProgression().StartingBlock)
+	assert.Equal(t, uint64(initialChainSize-1), progression.StartingBlock)
+	assert.Equal(t, uint64(initialChainSize-1), progression.CurrentBlock)
+	assert.Equal(t, uint64(targetChainSize-1), progression.HighestBlock)
 
-	syncer.syncProgression.UpdateHighestProgression(uint64(targetChainSize))
+	// the starting block is kept across the sync batches
+	syncer.syncProgress.start(progress.ChainSyncBulk, uint64(initialChainSize))
+	syncer.syncProgress.start(progress.ChainSyncBulk, uint64(initialChainSize+10))
 
-	assert.Equal(t, uint64(targetChainSize), syncer.syncProgression.GetProgression().HighestBlock)
+	assert.Equal(t, uint64(initialChainSize), syncer.GetSyncProgression().StartingBlock)
 
-	writeErr := syncerChain.WriteBlocks(syncBlocks[initialChainSize+1:])
+	halfChainSize := targetChainSize / 2
 
-	assert.NoError(t, writeErr)
+	assert.NoError(t, syncerChain.WriteBlocks(syncBlocks[initialChainSize:halfChainSize]))
 
-	WaitUntilProgressionUpdated(t, syncer, 15*time.Second, uint64(targetChainSize-1))
-	assert.Equal(t, uint64(targetChainSize-1), syncer.syncProgression.GetProgression().CurrentBlock)
+	WaitUntilProgressionUpdated(t, syncer, 15*time.Second, uint64(halfChainSize-1))
 
-	syncer.syncProgression.StopProgression()
+	progression = syncer.GetSyncProgression()
+	if progression == nil {
+		t.Fatalf("The sync completed before the highest block")
+	}
+
+	assert.Equal(t, uint64(initialChainSize), progression.StartingBlock)
+	assert.Equal(t, uint64(halfChainSize-1), progression.CurrentBlock)
+	assert.Equal(t, uint64(targetChainSize-1), progression.HighestBlock)
+
+	// the sync is completed once the highest block is written
+	assert.NoError(t, syncerChain.WriteBlocks(syncBlocks[halfChainSize:]))
+
+	WaitUntilProgressionUpdated(t, syncer, 15*time.Second, uint64(targetChainSize))
+	assert.Nil(t, syncer.GetSyncProgression())
 }
 
 type mockBlockStore struct {
@@ -452,6 +503,9 @@ func (m *mockBlockStore) CalculateGasLimit(number uint64) (uint64, error) {
 	panic("implement me")
 }
 
+func (m *mockBlockStore) RecoverSeals(headers []*types.Header) {
+}
+
 func newMockBlockStore() *mockBlockStore {
 	bs := &mockBlockStore{
 		blocks:       make([]*types.Block, 0),
@@ -482,7 +536,7 @@ func (m *mockBlockStore) GetBlockByNumber(blockNumber uint64, full bool) (*types
 
 	return nil, false
 }
-func (m *mockBlockStore) SubscribeEvents() blockchain.Subscription {
+func (m *mockBlockStore) SubscribeEventStream() blockchain.Subscription {
 	return m.subscription
 }
 func (m *mockBlockStore) GetReceiptsByHash(types.Hash) ([]*types.Receipt, error) {
@@ -511,7 +565,7 @@ func (m *mockBlockStore) GetBodyByHash(hash types.Hash) (*types.Body, bool) {
 
 func (m *mockBlockStore) WriteBlocks(blocks []*types.Block) error {
 	for _, block := range blocks {
-		if writeErr := m.WriteBlock(block); writeErr != nil {
+		if writeErr := m.WriteBlock(block, blockchain.SourceSyncer); writeErr != nil {
 			return writeErr
 		}
 	}
@@ -519,13 +573,17 @@ func (m *mockBlockStore) WriteBlocks(blocks []*types.Block) error {
 	return nil
 }
 
-func (m *mockBlockStore) WriteBlock(block *types.Block) error {
+func (m *mockBlockStore) WriteBlock(block *types.Block, _ string) error {
 	m.td.Add(m.td, big.NewInt(int64(block.Header.Difficulty)))
 	m.blocks = append(m.blocks, block)
 
 	return nil
 }
 
+func (m *mockBlockStore) WriteBlockWithReceipts(block *types.Block, _ []*types.Receipt) error {
+	return m.WriteBlock(block, blockchain.SourceSyncer)
+}
+
 func (m *mockBlockStore) CurrentTD() *big.Int {
 	return m.td
 }
@@ -689,3 +747,62 @@ func TestSyncer_PeerDisconnected(t *testing.T) {
 	// reference node's sync peer map
 	assert.False(t, found)
 }
+
+// futureBlockchain is the blockchain rejecting the first writes as ahead of the local clock
+type futureBlockchain struct {
+	*mockBlockchain
+
+	rejects int
+}
+
+func (b *futureBlockchain) WriteBlock(block *types.Block, source string) error {
+	if b.rejects > 0 {
+		b.rejects--
+
+		return blockchain.ErrFutureBlock
+	}
+
+	return b.mockBlockchain.WriteBlock(block, source)
+}
+
+func TestSyncer_WriteFutureBlock(t *testing.T) {
+	t.Parallel()
+
+	newBlock := func(chain *futureBlockchain, ahead time.Duration) *types.Block {
+		header := &types.Header{
+			ParentHash: chain.Header().Hash,
+			Number:     chain.Header().Number + 1,
+			Timestamp:  uint64(time.Now().Add(ahead).Unix()),
+		}
+
+		return &types.Block{Header: header.ComputeHash()}
+	}
+
+	t.Run("should write the block once the local clock catches up", func(t *testing.T) {
+		t.Parallel()
+
+		chain := &futureBlockchain{
+			mockBlockchain: NewMockBlockchain(blockchain.NewTestHeaderChain(1)),
+			rejects:        1,
+		}
+		syncer := NewSyncer(hclog.NewNullLogger(), nil, chain)
+
+		block := newBlock(chain, time.Second)
+		assert.NoError(t, syncer.writeBlock(block))
+		assert.Equal(t, block.Header, chain.Header())
+		assert.False(t, time.Now().Before(time.Unix(int64(block.Header.Timestamp), 0)))
+	})
+
+	t.Run("should reject the block too far ahead of the local clock", func(t *testing.T) {
+		t.Parallel()
+
+		chain := &futureBlockchain{
+			mockBlockchain: NewMockBlockchain(blockchain.NewTestHeaderChain(1)),
+			rejects:        1,
+		}
+		syncer := NewSyncer(hclog.NewNullLogger(), nil, chain)
+
+		assert.ErrorIs(t, syncer.writeBlock(newBlock(chain, 2*maxFutureBlockWait)), blockchain.ErrFutureBlock)
+		assert.Equal(t, uint64(0), chain.Header().Number)
+	})
+}
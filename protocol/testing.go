@@ -108,12 +108,13 @@ func WaitUntilProcessedAllEvents(t *testing.T, syncer *Syncer, timeout time.Dura
 	})
 
 	_, err := tests.RetryUntilTimeout(ctx, func() (interface{}, bool) {
-		return nil, len(syncer.blockchain.SubscribeEvents().GetEventCh()) > 0
+		return nil, len(syncer.blockchain.SubscribeEventStream().GetEventCh()) > 0
 	})
 	assert.NoError(t, err)
 }
 
-// WaitUntilProgressionUpdated waits until the syncer's progression current block reaches a target
+// WaitUntilProgressionUpdated waits until the syncer's progression current block reaches a target,
+// or the sync is completed
 func WaitUntilProgressionUpdated(t *testing.T, syncer *Syncer, timeout time.Duration, target uint64) {
 	t.Helper()
 
@@ -124,7 +125,9 @@ func WaitUntilProgressionUpdated(t *testing.T, syncer *Syncer, timeout time.Dura
 	})
 
 	_, err := tests.RetryUntilTimeout(ctx, func() (interface{}, bool) {
-		return nil, syncer.syncProgression.GetProgression().CurrentBlock < target
+		progression := syncer.GetSyncProgression()
+
+		return nil, progression != nil && progression.CurrentBlock < target
 	})
 	assert.NoError(t, err)
 }
@@ -246,6 +249,9 @@ func (b *mockBlockchain) CalculateGasLimit(number uint64) (uint64, error) {
 	panic("implement me")
 }
 
+func (b *mockBlockchain) RecoverSeals(headers []*types.Header) {
+}
+
 func NewMockBlockchain(headers []*types.Header) *mockBlockchain {
 	return &mockBlockchain{
 		blocks:        blockchain.HeadersToBlocks(headers),
@@ -253,7 +259,7 @@ func NewMockBlockchain(headers []*types.Header) *mockBlockchain {
 	}
 }
 
-func (b *mockBlockchain) SubscribeEvents() blockchain.Subscription {
+func (b *mockBlockchain) SubscribeEventStream() blockchain.Subscription {
 	subscription := NewMockSubscription()
 	b.subscriptions = append(b.subscriptions, subscription)
 
@@ -297,8 +303,14 @@ func (b *mockBlockchain) GetReceiptsByHash(types.Hash) ([]*types.Receipt, error)
 	panic("not implement")
 }
 
-func (b *mockBlockchain) GetBodyByHash(types.Hash) (*types.Body, bool) {
-	panic("not implement")
+func (b *mockBlockchain) GetBodyByHash(h types.Hash) (*types.Body, bool) {
+	for _, b := range b.blocks {
+		if b.Header.Hash == h {
+			return b.Body(), true
+		}
+	}
+
+	return nil, false
 }
 
 func (b *mockBlockchain) GetHeaderByHash(h types.Hash) (*types.Header, bool) {
@@ -321,7 +333,7 @@ func (b *mockBlockchain) GetHeaderByNumber(n uint64) (*types.Header, bool) {
 	return nil, false
 }
 
-func (b *mockBlockchain) WriteBlock(block *types.Block) error {
+func (b *mockBlockchain) WriteBlock(block *types.Block, _ string) error {
 	b.blocks = append(b.blocks, block)
 	for _, subscription := range b.subscriptions {
 		subscription.AppendBlock(block)
@@ -330,9 +342,13 @@ func (b *mockBlockchain) WriteBlock(block *types.Block) error {
 	return nil
 }
 
+func (b *mockBlockchain) WriteBlockWithReceipts(block *types.Block, _ []*types.Receipt) error {
+	return b.WriteBlock(block, blockchain.SourceSyncer)
+}
+
 func (b *mockBlockchain) WriteBlocks(blocks []*types.Block) error {
 	for _, block := range blocks {
-		if writeErr := b.WriteBlock(block); writeErr != nil {
+		if writeErr := b.WriteBlock(block, blockchain.SourceSyncer); writeErr != nil {
 			return writeErr
 		}
 	}
@@ -0,0 +1,243 @@
+package awskms
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/secrets"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// defaultAliasPrefix is the prefix of the key aliases, followed by the node name and the secret name
+	defaultAliasPrefix = "polygon-edge"
+
+	// keyDeletionWindow is the number of days until a removed key is deleted, the minimum of AWS KMS
+	keyDeletionWindow = 7
+)
+
+var (
+	errConfiguredKey = errors.New("the key of the secret is configured by its key id, it isn't created by the node")
+)
+
+// AwsKmsManager is a SecretsManager that holds the validator and the networking keys
+// in AWS KMS. The keys never leave the KMS, the digests are signed by its Sign API
+type AwsKmsManager struct {
+	// Local logger object
+	logger hclog.Logger
+
+	// The AWS region
+	region string
+
+	// The AWS shared config profile, the default credential chain is used if empty
+	profile string
+
+	// The AWS KMS client
+	client kmsiface.KMSAPI
+
+	// The base path of the key aliases of the node
+	aliasPath string
+
+	// The key ids (ARNs or aliases) of the secrets configured in the extra map
+	keyIDs map[string]string
+}
+
+// SecretsManagerFactory implements the factory method
+func SecretsManagerFactory(
+	config *secrets.SecretsManagerConfig,
+	params *secrets.SecretsManagerParams) (secrets.SecretsManager, error) { //nolint
+
+	// Check if the node name is present
+	if config.Name == "" {
+		return nil, errors.New("no node name specified for AWS KMS secrets manager")
+	}
+
+	// Check if the extra map is present
+	if config.Extra == nil || config.Extra["region"] == nil {
+		return nil, errors.New("required extra map containing 'region' not found for aws-kms")
+	}
+
+	// Set up the base object
+	awsKmsManager := &AwsKmsManager{
+		logger: params.Logger.Named(string(secrets.AWSKMS)),
+		region: fmt.Sprintf("%v", config.Extra["region"]),
+		keyIDs: make(map[string]string),
+	}
+
+	if profile, ok := config.Extra["profile"]; ok {
+		awsKmsManager.profile = fmt.Sprintf("%v", profile)
+	}
+
+	aliasPrefix := defaultAliasPrefix
+	if prefix, ok := config.Extra["alias-prefix"]; ok {
+		aliasPrefix = fmt.Sprintf("%v", prefix)
+	}
+
+	// Set the base path of the key aliases of the node
+	awsKmsManager.aliasPath = fmt.Sprintf("alias/%s/%s", aliasPrefix, config.Name)
+
+	// The existing keys can be referenced by their key id
+	for _, name := range []string{secrets.ValidatorKey, secrets.NetworkKey} {
+		if keyID, ok := config.Extra[name]; ok {
+			awsKmsManager.keyIDs[name] = fmt.Sprintf("%v", keyID)
+		}
+	}
+
+	// Run the initial setup
+	if err := awsKmsManager.Setup(); err != nil {
+		return nil, err
+	}
+
+	return awsKmsManager, nil
+}
+
+// Setup sets up the AWS KMS secrets manager
+func (a *AwsKmsManager) Setup() error {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            aws.Config{Region: aws.String(a.region)},
+		Profile:           a.profile,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to initialize AWS KMS client: %w", err)
+	}
+
+	a.client = kms.New(sess, aws.NewConfig().WithRegion(a.region))
+
+	return nil
+}
+
+// constructKeyID is a helper method for constructing the key id of the secret
+func (a *AwsKmsManager) constructKeyID(name string) string {
+	if keyID, ok := a.keyIDs[name]; ok {
+		return keyID
+	}
+
+	return fmt.Sprintf("%s/%s", a.aliasPath, name)
+}
+
+// isKeySecret checks if the secret is one of the keys held by the KMS
+func isKeySecret(name string) bool {
+	return name == secrets.ValidatorKey || name == secrets.NetworkKey
+}
+
+// GetSecret checks the key of the secret is present, the key material can't be exported from AWS KMS
+func (a *AwsKmsManager) GetSecret(name string) ([]byte, error) {
+	if !a.HasSecret(name) {
+		return nil, secrets.ErrSecretNotFound
+	}
+
+	return nil, secrets.ErrSecretNotExportable
+}
+
+// SetSecret can't import the key material, the keys are created in AWS KMS by CreateKey
+func (a *AwsKmsManager) SetSecret(name string, _ []byte) error {
+	return fmt.Errorf("unable to store secret (%s), %w", name, secrets.ErrSecretNotImportable)
+}
+
+// HasSecret checks if the enabled key of the secret is present on AWS KMS
+func (a *AwsKmsManager) HasSecret(name string) bool {
+	if !isKeySecret(name) {
+		return false
+	}
+
+	key, err := a.client.DescribeKey(&kms.DescribeKeyInput{
+		KeyId: aws.String(a.constructKeyID(name)),
+	})
+	if err != nil || key.KeyMetadata == nil {
+		return false
+	}
+
+	return aws.StringValue(key.KeyMetadata.KeyState) == kms.KeyStateEnabled
+}
+
+// RemoveSecret schedules the deletion of the key of the secret from AWS KMS
+func (a *AwsKmsManager) RemoveSecret(name string) error {
+	// Check if non-existent
+	if !a.HasSecret(name) {
+		return secrets.ErrSecretNotFound
+	}
+
+	keyID := a.constructKeyID(name)
+
+	if _, err := a.client.ScheduleKeyDeletion(&kms.ScheduleKeyDeletionInput{
+		KeyId:               aws.String(keyID),
+		PendingWindowInDays: aws.Int64(keyDeletionWindow),
+	}); err != nil {
+		return fmt.Errorf("unable to delete secret (%s), %w", name, err)
+	}
+
+	// The alias is released for the new key of the secret
+	if strings.HasPrefix(keyID, a.aliasPath) {
+		if _, err := a.client.DeleteAlias(&kms.DeleteAliasInput{
+			AliasName: aws.String(keyID),
+		}); err != nil {
+			return fmt.Errorf("unable to delete the alias of secret (%s), %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// CreateKey creates the secp256k1 signing key of the secret on AWS KMS, under the alias of the node
+func (a *AwsKmsManager) CreateKey(name string) error {
+	if _, ok := a.keyIDs[name]; ok {
+		return errConfiguredKey
+	}
+
+	if !isKeySecret(name) {
+		return secrets.ErrSecretNotFound
+	}
+
+	key, err := a.client.CreateKey(&kms.CreateKeyInput{
+		Description: aws.String(fmt.Sprintf("Polygon Edge %s (%s)", name, a.aliasPath)),
+		KeySpec:     aws.String(kms.KeySpecEccSecgP256k1),
+		KeyUsage:    aws.String(kms.KeyUsageTypeSignVerify),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create the key of secret (%s), %w", name, err)
+	}
+
+	if _, err := a.client.CreateAlias(&kms.CreateAliasInput{
+		AliasName:   aws.String(a.constructKeyID(name)),
+		TargetKeyId: key.KeyMetadata.KeyId,
+	}); err != nil {
+		return fmt.Errorf("unable to create the alias of secret (%s), %w", name, err)
+	}
+
+	return nil
+}
+
+// PublicKey returns the public key of the secret from AWS KMS
+func (a *AwsKmsManager) PublicKey(name string) (*ecdsa.PublicKey, error) {
+	key, err := a.client.GetPublicKey(&kms.GetPublicKeyInput{
+		KeyId: aws.String(a.constructKeyID(name)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the public key of secret (%s), %w", name, err)
+	}
+
+	return crypto.ParsePKIXPublicKey(key.PublicKey)
+}
+
+// SignDigest signs the digest with the key of the secret on AWS KMS
+func (a *AwsKmsManager) SignDigest(name string, digest []byte) ([]byte, error) {
+	signature, err := a.client.Sign(&kms.SignInput{
+		KeyId:            aws.String(a.constructKeyID(name)),
+		Message:          digest,
+		MessageType:      aws.String(kms.MessageTypeDigest),
+		SigningAlgorithm: aws.String(kms.SigningAlgorithmSpecEcdsaSha256),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign with secret (%s), %w", name, err)
+	}
+
+	return signature.Signature, nil
+}
@@ -0,0 +1,187 @@
+package awskms
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/secrets"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+var errKeyNotFound = errors.New("key not found")
+
+// mockKMS is an AWS KMS holding the keys in memory by alias
+type mockKMS struct {
+	kmsiface.KMSAPI
+
+	keys    map[string]*ecdsa.PrivateKey
+	aliases map[string]string
+}
+
+func newMockKMS() *mockKMS {
+	return &mockKMS{
+		keys:    make(map[string]*ecdsa.PrivateKey),
+		aliases: make(map[string]string),
+	}
+}
+
+func (m *mockKMS) key(keyID *string) (*ecdsa.PrivateKey, error) {
+	id := aws.StringValue(keyID)
+	if target, ok := m.aliases[id]; ok {
+		id = target
+	}
+
+	key, ok := m.keys[id]
+	if !ok {
+		return nil, errKeyNotFound
+	}
+
+	return key, nil
+}
+
+func (m *mockKMS) CreateKey(_ *kms.CreateKeyInput) (*kms.CreateKeyOutput, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	keyID := crypto.PubKeyToAddress(&key.PublicKey).String()
+	m.keys[keyID] = key
+
+	return &kms.CreateKeyOutput{
+		KeyMetadata: &kms.KeyMetadata{KeyId: aws.String(keyID)},
+	}, nil
+}
+
+func (m *mockKMS) CreateAlias(input *kms.CreateAliasInput) (*kms.CreateAliasOutput, error) {
+	m.aliases[aws.StringValue(input.AliasName)] = aws.StringValue(input.TargetKeyId)
+
+	return &kms.CreateAliasOutput{}, nil
+}
+
+func (m *mockKMS) DeleteAlias(input *kms.DeleteAliasInput) (*kms.DeleteAliasOutput, error) {
+	delete(m.aliases, aws.StringValue(input.AliasName))
+
+	return &kms.DeleteAliasOutput{}, nil
+}
+
+func (m *mockKMS) DescribeKey(input *kms.DescribeKeyInput) (*kms.DescribeKeyOutput, error) {
+	if _, err := m.key(input.KeyId); err != nil {
+		return nil, err
+	}
+
+	return &kms.DescribeKeyOutput{
+		KeyMetadata: &kms.KeyMetadata{KeyState: aws.String(kms.KeyStateEnabled)},
+	}, nil
+}
+
+func (m *mockKMS) ScheduleKeyDeletion(_ *kms.ScheduleKeyDeletionInput) (*kms.ScheduleKeyDeletionOutput, error) {
+	return &kms.ScheduleKeyDeletionOutput{}, nil
+}
+
+func (m *mockKMS) GetPublicKey(input *kms.GetPublicKeyInput) (*kms.GetPublicKeyOutput, error) {
+	key, err := m.key(input.KeyId)
+	if err != nil {
+		return nil, err
+	}
+
+	// the SubjectPublicKeyInfo of the secp256k1 key
+	params, _ := asn1.Marshal(asn1.ObjectIdentifier{1, 3, 132, 0, 10})
+	pub := crypto.MarshalPublicKey(&key.PublicKey)
+
+	der, err := asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1},
+			Parameters: asn1.RawValue{FullBytes: params},
+		},
+		PublicKey: asn1.BitString{Bytes: pub, BitLength: 8 * len(pub)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &kms.GetPublicKeyOutput{PublicKey: der}, nil
+}
+
+func (m *mockKMS) Sign(input *kms.SignInput) (*kms.SignOutput, error) {
+	key, err := m.key(input.KeyId)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := (*btcec.PrivateKey)(key).Sign(input.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kms.SignOutput{Signature: sig.Serialize()}, nil
+}
+
+func newTestAwsKmsManager(keyIDs map[string]string) *AwsKmsManager {
+	return &AwsKmsManager{
+		logger:    hclog.NewNullLogger(),
+		client:    newMockKMS(),
+		aliasPath: "alias/polygon-edge/node",
+		keyIDs:    keyIDs,
+	}
+}
+
+func TestAwsKmsManager_Keys(t *testing.T) {
+	manager := newTestAwsKmsManager(map[string]string{})
+
+	assert.False(t, manager.HasSecret(secrets.ValidatorKey))
+	assert.NoError(t, manager.CreateKey(secrets.ValidatorKey))
+	assert.True(t, manager.HasSecret(secrets.ValidatorKey))
+
+	// the key material never leaves the KMS
+	_, err := manager.GetSecret(secrets.ValidatorKey)
+	assert.ErrorIs(t, err, secrets.ErrSecretNotExportable)
+	assert.ErrorIs(t, manager.SetSecret(secrets.ValidatorKey, []byte("key")), secrets.ErrSecretNotImportable)
+
+	// the digests signed by the KMS recover to the public key of the secret
+	pub, err := manager.PublicKey(secrets.ValidatorKey)
+	assert.NoError(t, err)
+
+	hash := crypto.Keccak256([]byte("seal"))
+
+	der, err := manager.SignDigest(secrets.ValidatorKey, hash)
+	assert.NoError(t, err)
+
+	signature, err := crypto.CompactSignatureFromDER(der, hash, pub)
+	assert.NoError(t, err)
+
+	recovered, err := crypto.RecoverPubkey(signature, hash)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubKeyToAddress(pub), crypto.PubKeyToAddress(recovered))
+
+	// the removed key releases the alias of the secret
+	assert.NoError(t, manager.RemoveSecret(secrets.ValidatorKey))
+	assert.False(t, manager.HasSecret(secrets.ValidatorKey))
+}
+
+func TestAwsKmsManager_ConfiguredKeys(t *testing.T) {
+	manager := newTestAwsKmsManager(map[string]string{
+		secrets.NetworkKey: "alias/existing-network-key",
+	})
+
+	// the configured keys are referenced, not created
+	assert.Equal(t, "alias/existing-network-key", manager.constructKeyID(secrets.NetworkKey))
+	assert.Equal(t, "alias/polygon-edge/node/validator-key", manager.constructKeyID(secrets.ValidatorKey))
+	assert.ErrorIs(t, manager.CreateKey(secrets.NetworkKey), errConfiguredKey)
+
+	// only the keys are held by the KMS
+	assert.False(t, manager.HasSecret("other-secret"))
+	assert.ErrorIs(t, manager.CreateKey("other-secret"), secrets.ErrSecretNotFound)
+}
@@ -0,0 +1,282 @@
+package gcpkms
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/secrets"
+	gax "github.com/googleapis/gax-go/v2"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/api/option"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+)
+
+const (
+	// requestTimeout is the timeout of a single Cloud KMS request
+	requestTimeout = 10 * time.Second
+
+	// keyVersion is the version of the keys created by the node
+	keyVersion = 1
+)
+
+var (
+	errConfiguredKey = errors.New("the key of the secret is configured by its key version, it isn't created by the node")
+	errInvalidPEM    = errors.New("invalid PEM encoded public key")
+)
+
+// kmsClient is the subset of the Cloud KMS client used by the secrets manager
+type kmsClient interface {
+	GetCryptoKeyVersion(
+		ctx context.Context,
+		req *kmspb.GetCryptoKeyVersionRequest,
+		opts ...gax.CallOption,
+	) (*kmspb.CryptoKeyVersion, error)
+	GetPublicKey(
+		ctx context.Context,
+		req *kmspb.GetPublicKeyRequest,
+		opts ...gax.CallOption,
+	) (*kmspb.PublicKey, error)
+	CreateCryptoKey(
+		ctx context.Context,
+		req *kmspb.CreateCryptoKeyRequest,
+		opts ...gax.CallOption,
+	) (*kmspb.CryptoKey, error)
+	DestroyCryptoKeyVersion(
+		ctx context.Context,
+		req *kmspb.DestroyCryptoKeyVersionRequest,
+		opts ...gax.CallOption,
+	) (*kmspb.CryptoKeyVersion, error)
+	AsymmetricSign(
+		ctx context.Context,
+		req *kmspb.AsymmetricSignRequest,
+		opts ...gax.CallOption,
+	) (*kmspb.AsymmetricSignResponse, error)
+}
+
+// GcpKmsManager is a SecretsManager that holds the validator and the networking keys
+// in GCP Cloud KMS. The keys never leave the KMS, the digests are signed by its AsymmetricSign API
+type GcpKmsManager struct {
+	// Local logger object
+	logger hclog.Logger
+
+	// The resource name of the key ring, projects/*/locations/*/keyRings/*
+	keyRing string
+
+	// The name of the current node, the prefix of the crypto key ids
+	name string
+
+	// The path to the service account credentials, the application default credentials are used if empty
+	credentialsFile string
+
+	// The Cloud KMS client
+	client kmsClient
+
+	// The key version resource names of the secrets configured in the extra map
+	keyVersions map[string]string
+}
+
+// SecretsManagerFactory implements the factory method
+func SecretsManagerFactory(
+	config *secrets.SecretsManagerConfig,
+	params *secrets.SecretsManagerParams) (secrets.SecretsManager, error) { //nolint
+
+	// Check if the node name is present
+	if config.Name == "" {
+		return nil, errors.New("no node name specified for GCP KMS secrets manager")
+	}
+
+	// Check if the extra map is present
+	if config.Extra == nil || config.Extra["key-ring"] == nil {
+		return nil, errors.New("required extra map containing 'key-ring' not found for gcp-kms")
+	}
+
+	// Set up the base object
+	gcpKmsManager := &GcpKmsManager{
+		logger:      params.Logger.Named(string(secrets.GCPKMS)),
+		keyRing:     fmt.Sprintf("%v", config.Extra["key-ring"]),
+		name:        config.Name,
+		keyVersions: make(map[string]string),
+	}
+
+	if credentialsFile, ok := config.Extra["credentials-file"]; ok {
+		gcpKmsManager.credentialsFile = fmt.Sprintf("%v", credentialsFile)
+	}
+
+	// The existing keys can be referenced by their key version
+	for _, name := range []string{secrets.ValidatorKey, secrets.NetworkKey} {
+		if keyVersion, ok := config.Extra[name]; ok {
+			gcpKmsManager.keyVersions[name] = fmt.Sprintf("%v", keyVersion)
+		}
+	}
+
+	// Run the initial setup
+	if err := gcpKmsManager.Setup(); err != nil {
+		return nil, err
+	}
+
+	return gcpKmsManager, nil
+}
+
+// Setup sets up the GCP KMS secrets manager
+func (g *GcpKmsManager) Setup() error {
+	opts := []option.ClientOption{}
+	if g.credentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(g.credentialsFile))
+	}
+
+	client, err := kms.NewKeyManagementClient(context.Background(), opts...)
+	if err != nil {
+		return fmt.Errorf("unable to initialize GCP KMS client: %w", err)
+	}
+
+	g.client = client
+
+	return nil
+}
+
+// constructKeyID is a helper method for constructing the crypto key id of the secret
+func (g *GcpKmsManager) constructKeyID(name string) string {
+	return fmt.Sprintf("%s-%s", g.name, name)
+}
+
+// constructKeyVersion is a helper method for constructing the key version resource name of the secret
+func (g *GcpKmsManager) constructKeyVersion(name string) string {
+	if keyVersion, ok := g.keyVersions[name]; ok {
+		return keyVersion
+	}
+
+	return fmt.Sprintf("%s/cryptoKeys/%s/cryptoKeyVersions/%d", g.keyRing, g.constructKeyID(name), keyVersion)
+}
+
+// isKeySecret checks if the secret is one of the keys held by the KMS
+func isKeySecret(name string) bool {
+	return name == secrets.ValidatorKey || name == secrets.NetworkKey
+}
+
+// GetSecret checks the key of the secret is present, the key material can't be exported from GCP KMS
+func (g *GcpKmsManager) GetSecret(name string) ([]byte, error) {
+	if !g.HasSecret(name) {
+		return nil, secrets.ErrSecretNotFound
+	}
+
+	return nil, secrets.ErrSecretNotExportable
+}
+
+// SetSecret can't import the key material, the keys are created in GCP KMS by CreateKey
+func (g *GcpKmsManager) SetSecret(name string, _ []byte) error {
+	return fmt.Errorf("unable to store secret (%s), %w", name, secrets.ErrSecretNotImportable)
+}
+
+// HasSecret checks if the enabled key version of the secret is present on GCP KMS
+func (g *GcpKmsManager) HasSecret(name string) bool {
+	if !isKeySecret(name) {
+		return false
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancelFn()
+
+	version, err := g.client.GetCryptoKeyVersion(ctx, &kmspb.GetCryptoKeyVersionRequest{
+		Name: g.constructKeyVersion(name),
+	})
+	if err != nil {
+		return false
+	}
+
+	return version.State == kmspb.CryptoKeyVersion_ENABLED
+}
+
+// RemoveSecret schedules the destruction of the key version of the secret on GCP KMS
+func (g *GcpKmsManager) RemoveSecret(name string) error {
+	// Check if non-existent
+	if !g.HasSecret(name) {
+		return secrets.ErrSecretNotFound
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancelFn()
+
+	if _, err := g.client.DestroyCryptoKeyVersion(ctx, &kmspb.DestroyCryptoKeyVersionRequest{
+		Name: g.constructKeyVersion(name),
+	}); err != nil {
+		return fmt.Errorf("unable to delete secret (%s), %w", name, err)
+	}
+
+	return nil
+}
+
+// CreateKey creates the HSM protected secp256k1 signing key of the secret in the key ring
+func (g *GcpKmsManager) CreateKey(name string) error {
+	if _, ok := g.keyVersions[name]; ok {
+		return errConfiguredKey
+	}
+
+	if !isKeySecret(name) {
+		return secrets.ErrSecretNotFound
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancelFn()
+
+	if _, err := g.client.CreateCryptoKey(ctx, &kmspb.CreateCryptoKeyRequest{
+		Parent:      g.keyRing,
+		CryptoKeyId: g.constructKeyID(name),
+		CryptoKey: &kmspb.CryptoKey{
+			Purpose: kmspb.CryptoKey_ASYMMETRIC_SIGN,
+			VersionTemplate: &kmspb.CryptoKeyVersionTemplate{
+				Algorithm:       kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256,
+				ProtectionLevel: kmspb.ProtectionLevel_HSM,
+			},
+		},
+	}); err != nil {
+		return fmt.Errorf("unable to create the key of secret (%s), %w", name, err)
+	}
+
+	return nil
+}
+
+// PublicKey returns the public key of the secret from GCP KMS
+func (g *GcpKmsManager) PublicKey(name string) (*ecdsa.PublicKey, error) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancelFn()
+
+	key, err := g.client.GetPublicKey(ctx, &kmspb.GetPublicKeyRequest{
+		Name: g.constructKeyVersion(name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the public key of secret (%s), %w", name, err)
+	}
+
+	block, _ := pem.Decode([]byte(key.Pem))
+	if block == nil {
+		return nil, errInvalidPEM
+	}
+
+	return crypto.ParsePKIXPublicKey(block.Bytes)
+}
+
+// SignDigest signs the digest with the key of the secret on GCP KMS
+func (g *GcpKmsManager) SignDigest(name string, digest []byte) ([]byte, error) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancelFn()
+
+	signature, err := g.client.AsymmetricSign(ctx, &kmspb.AsymmetricSignRequest{
+		Name: g.constructKeyVersion(name),
+		Digest: &kmspb.Digest{
+			Digest: &kmspb.Digest_Sha256{
+				Sha256: digest,
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign with secret (%s), %w", name, err)
+	}
+
+	return signature.Signature, nil
+}
@@ -0,0 +1,257 @@
+package gcpkms
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/secrets"
+	"github.com/btcsuite/btcd/btcec"
+	gax "github.com/googleapis/gax-go/v2"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+)
+
+const testKeyRing = "projects/polygon/locations/global/keyRings/edge"
+
+var errKeyNotFound = errors.New("key not found")
+
+// mockKMS is a Cloud KMS holding the keys in memory by key version
+type mockKMS struct {
+	keys   map[string]*ecdsa.PrivateKey
+	states map[string]kmspb.CryptoKeyVersion_CryptoKeyVersionState
+
+	// created are the requests of the created keys
+	created []*kmspb.CreateCryptoKeyRequest
+
+	// pem replaces the PEM encoded public keys, if set
+	pem string
+}
+
+func newMockKMS() *mockKMS {
+	return &mockKMS{
+		keys:   make(map[string]*ecdsa.PrivateKey),
+		states: make(map[string]kmspb.CryptoKeyVersion_CryptoKeyVersionState),
+	}
+}
+
+func (m *mockKMS) key(name string) (*ecdsa.PrivateKey, error) {
+	key, ok := m.keys[name]
+	if !ok {
+		return nil, errKeyNotFound
+	}
+
+	return key, nil
+}
+
+func (m *mockKMS) GetCryptoKeyVersion(
+	_ context.Context,
+	req *kmspb.GetCryptoKeyVersionRequest,
+	_ ...gax.CallOption,
+) (*kmspb.CryptoKeyVersion, error) {
+	if _, err := m.key(req.Name); err != nil {
+		return nil, err
+	}
+
+	return &kmspb.CryptoKeyVersion{Name: req.Name, State: m.states[req.Name]}, nil
+}
+
+func (m *mockKMS) GetPublicKey(
+	_ context.Context,
+	req *kmspb.GetPublicKeyRequest,
+	_ ...gax.CallOption,
+) (*kmspb.PublicKey, error) {
+	key, err := m.key(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.pem != "" {
+		return &kmspb.PublicKey{Pem: m.pem}, nil
+	}
+
+	// the SubjectPublicKeyInfo of the secp256k1 key
+	params, _ := asn1.Marshal(asn1.ObjectIdentifier{1, 3, 132, 0, 10})
+	pub := crypto.MarshalPublicKey(&key.PublicKey)
+
+	der, err := asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1},
+			Parameters: asn1.RawValue{FullBytes: params},
+		},
+		PublicKey: asn1.BitString{Bytes: pub, BitLength: 8 * len(pub)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &kmspb.PublicKey{
+		Pem: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	}, nil
+}
+
+func (m *mockKMS) CreateCryptoKey(
+	_ context.Context,
+	req *kmspb.CreateCryptoKeyRequest,
+	_ ...gax.CallOption,
+) (*kmspb.CryptoKey, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("%s/cryptoKeys/%s", req.Parent, req.CryptoKeyId)
+	version := fmt.Sprintf("%s/cryptoKeyVersions/%d", name, keyVersion)
+
+	m.keys[version] = key
+	m.states[version] = kmspb.CryptoKeyVersion_ENABLED
+	m.created = append(m.created, req)
+
+	return &kmspb.CryptoKey{Name: name}, nil
+}
+
+func (m *mockKMS) DestroyCryptoKeyVersion(
+	_ context.Context,
+	req *kmspb.DestroyCryptoKeyVersionRequest,
+	_ ...gax.CallOption,
+) (*kmspb.CryptoKeyVersion, error) {
+	if _, err := m.key(req.Name); err != nil {
+		return nil, err
+	}
+
+	m.states[req.Name] = kmspb.CryptoKeyVersion_DESTROY_SCHEDULED
+
+	return &kmspb.CryptoKeyVersion{Name: req.Name, State: m.states[req.Name]}, nil
+}
+
+func (m *mockKMS) AsymmetricSign(
+	_ context.Context,
+	req *kmspb.AsymmetricSignRequest,
+	_ ...gax.CallOption,
+) (*kmspb.AsymmetricSignResponse, error) {
+	key, err := m.key(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := (*btcec.PrivateKey)(key).Sign(req.Digest.GetSha256())
+	if err != nil {
+		return nil, err
+	}
+
+	return &kmspb.AsymmetricSignResponse{Signature: sig.Serialize()}, nil
+}
+
+func newTestGcpKmsManager(client *mockKMS, keyVersions map[string]string) *GcpKmsManager {
+	return &GcpKmsManager{
+		logger:      hclog.NewNullLogger(),
+		keyRing:     testKeyRing,
+		name:        "node",
+		client:      client,
+		keyVersions: keyVersions,
+	}
+}
+
+func TestGcpKmsManager_Keys(t *testing.T) {
+	client := newMockKMS()
+	manager := newTestGcpKmsManager(client, map[string]string{})
+
+	assert.False(t, manager.HasSecret(secrets.ValidatorKey))
+	assert.NoError(t, manager.CreateKey(secrets.ValidatorKey))
+	assert.True(t, manager.HasSecret(secrets.ValidatorKey))
+
+	// the key is an HSM protected secp256k1 signing key in the key ring
+	if assert.Len(t, client.created, 1) {
+		req := client.created[0]
+
+		assert.Equal(t, testKeyRing, req.Parent)
+		assert.Equal(t, "node-validator-key", req.CryptoKeyId)
+		assert.Equal(t, kmspb.CryptoKey_ASYMMETRIC_SIGN, req.CryptoKey.Purpose)
+		assert.Equal(t, kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256, req.CryptoKey.VersionTemplate.Algorithm)
+		assert.Equal(t, kmspb.ProtectionLevel_HSM, req.CryptoKey.VersionTemplate.ProtectionLevel)
+	}
+
+	// the key material never leaves the KMS
+	_, err := manager.GetSecret(secrets.ValidatorKey)
+	assert.ErrorIs(t, err, secrets.ErrSecretNotExportable)
+	assert.ErrorIs(t, manager.SetSecret(secrets.ValidatorKey, []byte("key")), secrets.ErrSecretNotImportable)
+
+	// the digests signed by the KMS recover to the public key of the secret
+	pub, err := manager.PublicKey(secrets.ValidatorKey)
+	assert.NoError(t, err)
+
+	hash := crypto.Keccak256([]byte("seal"))
+
+	der, err := manager.SignDigest(secrets.ValidatorKey, hash)
+	assert.NoError(t, err)
+
+	signature, err := crypto.CompactSignatureFromDER(der, hash, pub)
+	assert.NoError(t, err)
+
+	recovered, err := crypto.RecoverPubkey(signature, hash)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubKeyToAddress(pub), crypto.PubKeyToAddress(recovered))
+
+	// the removed key version is scheduled for destruction, it isn't enabled anymore
+	assert.NoError(t, manager.RemoveSecret(secrets.ValidatorKey))
+	assert.False(t, manager.HasSecret(secrets.ValidatorKey))
+	assert.ErrorIs(t, manager.RemoveSecret(secrets.ValidatorKey), secrets.ErrSecretNotFound)
+}
+
+func TestGcpKmsManager_ConfiguredKeys(t *testing.T) {
+	manager := newTestGcpKmsManager(newMockKMS(), map[string]string{
+		secrets.NetworkKey: testKeyRing + "/cryptoKeys/existing-network-key/cryptoKeyVersions/3",
+	})
+
+	// the configured keys are referenced by their key version, not created
+	assert.Equal(
+		t,
+		testKeyRing+"/cryptoKeys/existing-network-key/cryptoKeyVersions/3",
+		manager.constructKeyVersion(secrets.NetworkKey),
+	)
+	assert.Equal(
+		t,
+		testKeyRing+"/cryptoKeys/node-validator-key/cryptoKeyVersions/1",
+		manager.constructKeyVersion(secrets.ValidatorKey),
+	)
+	assert.ErrorIs(t, manager.CreateKey(secrets.NetworkKey), errConfiguredKey)
+
+	// only the keys are held by the KMS
+	assert.False(t, manager.HasSecret("other-secret"))
+	assert.ErrorIs(t, manager.CreateKey("other-secret"), secrets.ErrSecretNotFound)
+
+	_, err := manager.GetSecret("other-secret")
+	assert.ErrorIs(t, err, secrets.ErrSecretNotFound)
+}
+
+func TestGcpKmsManager_Errors(t *testing.T) {
+	client := newMockKMS()
+	manager := newTestGcpKmsManager(client, map[string]string{})
+
+	// the errors of the KMS are wrapped for the missing keys
+	_, err := manager.PublicKey(secrets.ValidatorKey)
+	assert.ErrorIs(t, err, errKeyNotFound)
+
+	_, err = manager.SignDigest(secrets.ValidatorKey, crypto.Keccak256([]byte("seal")))
+	assert.ErrorIs(t, err, errKeyNotFound)
+
+	assert.ErrorIs(t, manager.RemoveSecret(secrets.ValidatorKey), secrets.ErrSecretNotFound)
+
+	// the public keys have to be PEM encoded
+	assert.NoError(t, manager.CreateKey(secrets.ValidatorKey))
+
+	client.pem = "not a PEM encoded key"
+
+	_, err = manager.PublicKey(secrets.ValidatorKey)
+	assert.ErrorIs(t, err, errInvalidPEM)
+}
@@ -2,21 +2,32 @@ package helper
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/crypto/bls"
 	"github.com/0xPolygon/polygon-edge/helper/common"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/secrets"
+	"github.com/0xPolygon/polygon-edge/secrets/awskms"
 	"github.com/0xPolygon/polygon-edge/secrets/awsssm"
+	"github.com/0xPolygon/polygon-edge/secrets/gcpkms"
 	"github.com/0xPolygon/polygon-edge/secrets/hashicorpvault"
 	"github.com/0xPolygon/polygon-edge/secrets/local"
+	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
 	libp2pCrypto "github.com/libp2p/go-libp2p-core/crypto"
 	"path/filepath"
+	"sort"
 )
 
-// SetupLocalSecretsManager is a helper method for boilerplate local secrets manager setup
-func SetupLocalSecretsManager(dataDir string) (secrets.SecretsManager, error) {
+var (
+	errNotKMS = errors.New("the secrets manager doesn't hold the keys in a KMS")
+)
+
+// SetupLocalSecretsManager is a helper method for boilerplate local secrets manager setup.
+// The secrets are encrypted with the passphrase, if any
+func SetupLocalSecretsManager(dataDir, passphrase string) (secrets.SecretsManager, error) {
 	subDirectories := []string{secrets.ConsensusFolderLocal, secrets.NetworkFolderLocal}
 
 	// Check if the sub-directories exist / are already populated
@@ -30,17 +41,48 @@ func SetupLocalSecretsManager(dataDir string) (secrets.SecretsManager, error) {
 		}
 	}
 
+	return GetLocalSecretsManager(dataDir, passphrase)
+}
+
+// GetLocalSecretsManager is a helper method for opening the local secrets manager of the data directory.
+// The secrets are encrypted with the passphrase, if any
+func GetLocalSecretsManager(dataDir, passphrase string) (secrets.SecretsManager, error) {
+	extra := map[string]interface{}{
+		secrets.Path: dataDir,
+	}
+
+	if passphrase != "" {
+		extra[secrets.Passphrase] = passphrase
+	}
+
 	return local.SecretsManagerFactory(
 		nil, // Local secrets manager doesn't require a config
 		&secrets.SecretsManagerParams{
 			Logger: hclog.NewNullLogger(),
-			Extra: map[string]interface{}{
-				secrets.Path: dataDir,
-			},
+			Extra:  extra,
 		},
 	)
 }
 
+// EncryptLocalSecrets encrypts the plaintext local secrets of the data directory in place,
+// and returns the names of the encrypted secrets
+func EncryptLocalSecrets(dataDir, passphrase string) ([]string, error) {
+	manager, err := GetLocalSecretsManager(dataDir, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	localManager, ok := manager.(*local.LocalSecretsManager)
+	if !ok {
+		return nil, errors.New("invalid type assertion")
+	}
+
+	encrypted, err := localManager.EncryptSecrets()
+	sort.Strings(encrypted)
+
+	return encrypted, err
+}
+
 // SetupHashicorpVault is a helper method for boilerplate hashicorp vault secrets manager setup
 func SetupHashicorpVault(
 	secretsConfig *secrets.SecretsManagerConfig,
@@ -65,6 +107,30 @@ func SetupAWSSSM(
 	)
 }
 
+// SetupAWSKMS is a helper method for boilerplate aws kms secrets manager setup
+func SetupAWSKMS(
+	secretsConfig *secrets.SecretsManagerConfig,
+) (secrets.SecretsManager, error) {
+	return awskms.SecretsManagerFactory(
+		secretsConfig,
+		&secrets.SecretsManagerParams{
+			Logger: hclog.NewNullLogger(),
+		},
+	)
+}
+
+// SetupGCPKMS is a helper method for boilerplate gcp kms secrets manager setup
+func SetupGCPKMS(
+	secretsConfig *secrets.SecretsManagerConfig,
+) (secrets.SecretsManager, error) {
+	return gcpkms.SecretsManagerFactory(
+		secretsConfig,
+		&secrets.SecretsManagerParams{
+			Logger: hclog.NewNullLogger(),
+		},
+	)
+}
+
 func InitValidatorKey(secretsManager secrets.SecretsManager) (*ecdsa.PrivateKey, error) {
 	// Generate the IBFT validator private key
 	validatorKey, validatorKeyEncoded, keyErr := crypto.GenerateAndEncodePrivateKey()
@@ -83,6 +149,24 @@ func InitValidatorKey(secretsManager secrets.SecretsManager) (*ecdsa.PrivateKey,
 	return validatorKey, nil
 }
 
+// InitBLSKey generates the BLS key the validator signs the aggregated committed seals with
+func InitBLSKey(secretsManager secrets.SecretsManager) (*bls.SecretKey, error) {
+	blsKey, blsKeyEncoded, keyErr := bls.GenerateAndEncodeKey()
+	if keyErr != nil {
+		return nil, keyErr
+	}
+
+	// Write the BLS key to the secrets manager storage
+	if setErr := secretsManager.SetSecret(
+		secrets.ValidatorBLSKey,
+		blsKeyEncoded,
+	); setErr != nil {
+		return nil, setErr
+	}
+
+	return blsKey, nil
+}
+
 func InitNetworkingPrivateKey(secretsManager secrets.SecretsManager) (libp2pCrypto.PrivKey, error) {
 	// Generate the libp2p private key
 	libp2pKey, libp2pKeyEncoded, keyErr := network.GenerateAndEncodeLibp2pKey()
@@ -100,3 +184,40 @@ func InitNetworkingPrivateKey(secretsManager secrets.SecretsManager) (libp2pCryp
 
 	return libp2pKey, keyErr
 }
+
+// InitKMSValidatorKey creates the validator key in the KMS of the secrets manager,
+// or references the existing one, and returns the validator address
+func InitKMSValidatorKey(secretsManager secrets.SecretsManager) (types.Address, error) {
+	pub, err := initKMSKey(secretsManager, secrets.ValidatorKey)
+	if err != nil {
+		return types.ZeroAddress, err
+	}
+
+	return crypto.PubKeyToAddress(pub), nil
+}
+
+// InitKMSNetworkingPrivateKey creates the networking key in the KMS of the secrets manager,
+// or references the existing one
+func InitKMSNetworkingPrivateKey(secretsManager secrets.SecretsManager) (libp2pCrypto.PrivKey, error) {
+	if _, err := initKMSKey(secretsManager, secrets.NetworkKey); err != nil {
+		return nil, err
+	}
+
+	return network.NewKMSLibp2pKey(secretsManager.(secrets.KeySigner))
+}
+
+// initKMSKey creates the key of the secret in the KMS if not present, and returns its public key
+func initKMSKey(secretsManager secrets.SecretsManager, name string) (*ecdsa.PublicKey, error) {
+	keySigner, ok := secretsManager.(secrets.KeySigner)
+	if !ok {
+		return nil, errNotKMS
+	}
+
+	if !secretsManager.HasSecret(name) {
+		if createErr := keySigner.CreateKey(name); createErr != nil {
+			return nil, createErr
+		}
+	}
+
+	return keySigner.PublicKey(name)
+}
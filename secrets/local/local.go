@@ -1,14 +1,18 @@
 package local
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
+	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/helper/keystore"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/hashicorp/go-hclog"
 )
@@ -22,6 +26,10 @@ type LocalSecretsManager struct {
 	// Path to the base working directory
 	path string
 
+	// Passphrase of the secrets encrypted in the keystore V3 format.
+	// The secrets are stored in plaintext if empty
+	passphrase string
+
 	// Map of known secrets and their paths
 	secretPathMap map[string]string
 
@@ -51,6 +59,13 @@ func SecretsManagerFactory(
 		return nil, errors.New("invalid type assertion")
 	}
 
+	if passphrase, ok := params.Extra[secrets.Passphrase]; ok {
+		localManager.passphrase, ok = passphrase.(string)
+		if !ok {
+			return nil, errors.New("invalid type assertion")
+		}
+	}
+
 	// Run the initial setup
 	_ = localManager.Setup()
 
@@ -60,7 +75,7 @@ func SecretsManagerFactory(
 // Setup sets up the local SecretsManager
 func (l *LocalSecretsManager) Setup() error {
 	// The local SecretsManager initially handles only the
-	// validator and networking private keys
+	// validator (current and next) and networking private keys
 	l.secretPathMapLock.Lock()
 	defer l.secretPathMapLock.Unlock()
 
@@ -78,6 +93,20 @@ func (l *LocalSecretsManager) Setup() error {
 		secrets.ValidatorKeyLocal,
 	)
 
+	// baseDir/consensus/next-validator.key
+	l.secretPathMap[secrets.NextValidatorKey] = filepath.Join(
+		l.path,
+		secrets.ConsensusFolderLocal,
+		secrets.NextValidatorKeyLocal,
+	)
+
+	// baseDir/consensus/validator-bls.key
+	l.secretPathMap[secrets.ValidatorBLSKey] = filepath.Join(
+		l.path,
+		secrets.ConsensusFolderLocal,
+		secrets.ValidatorBLSKeyLocal,
+	)
+
 	// baseDir/libp2p/libp2p.key
 	l.secretPathMap[secrets.NetworkKey] = filepath.Join(
 		l.path,
@@ -108,7 +137,21 @@ func (l *LocalSecretsManager) GetSecret(name string) ([]byte, error) {
 		)
 	}
 
-	return secret, nil
+	if !keystore.IsEncrypted(secret) {
+		return secret, nil
+	}
+
+	if l.passphrase == "" {
+		return nil, fmt.Errorf("unable to read secret from disk (%s), %w", secretPath, secrets.ErrSecretEncrypted)
+	}
+
+	// The secrets are kept hex encoded, as in the plaintext secrets
+	key, err := keystore.DecryptKey(secret, l.passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt secret (%s), %w", secretPath, err)
+	}
+
+	return []byte(hex.EncodeToString(key)), nil
 }
 
 // SetSecret saves the local SecretsManager's secret to disk
@@ -126,6 +169,15 @@ func (l *LocalSecretsManager) SetSecret(name string, value []byte) error {
 		return secrets.ErrSecretNotFound
 	}
 
+	if l.passphrase != "" {
+		encrypted, err := encryptSecret(name, value, l.passphrase)
+		if err != nil {
+			return fmt.Errorf("unable to encrypt secret (%s), %w", name, err)
+		}
+
+		value = encrypted
+	}
+
 	// Write the secret to disk
 	if err := ioutil.WriteFile(secretPath, value, 0600); err != nil {
 		return fmt.Errorf(
@@ -138,11 +190,12 @@ func (l *LocalSecretsManager) SetSecret(name string, value []byte) error {
 	return nil
 }
 
-// HasSecret checks if the secret is present on disk
+// HasSecret checks if the secret is present on disk.
+// The encrypted secrets are present even if they can't be decrypted, so they are never regenerated
 func (l *LocalSecretsManager) HasSecret(name string) bool {
 	_, err := l.GetSecret(name)
 
-	return err == nil
+	return err == nil || errors.Is(err, secrets.ErrSecretEncrypted) || errors.Is(err, keystore.ErrDecrypt)
 }
 
 // RemoveSecret removes the local SecretsManager's secret from disk
@@ -163,3 +216,89 @@ func (l *LocalSecretsManager) RemoveSecret(name string) error {
 
 	return nil
 }
+
+// EncryptSecrets encrypts the plaintext secrets on disk in place, with the passphrase of the secrets manager
+func (l *LocalSecretsManager) EncryptSecrets() ([]string, error) {
+	if l.passphrase == "" {
+		return nil, errors.New("no passphrase specified for the encryption of the secrets")
+	}
+
+	l.secretPathMapLock.RLock()
+	secretPaths := make(map[string]string, len(l.secretPathMap))
+
+	for name, secretPath := range l.secretPathMap {
+		secretPaths[name] = secretPath
+	}
+	l.secretPathMapLock.RUnlock()
+
+	encrypted := make([]string, 0, len(secretPaths))
+
+	for name, secretPath := range secretPaths {
+		secret, err := ioutil.ReadFile(secretPath)
+		if os.IsNotExist(err) || (err == nil && keystore.IsEncrypted(secret)) {
+			continue
+		}
+
+		if err != nil {
+			return encrypted, fmt.Errorf("unable to read secret from disk (%s), %w", secretPath, err)
+		}
+
+		value, err := encryptSecret(name, secret, l.passphrase)
+		if err != nil {
+			return encrypted, fmt.Errorf("unable to encrypt secret (%s), %w", name, err)
+		}
+
+		// The encrypted secret replaces the plaintext one at once
+		tmpPath := secretPath + ".tmp"
+		if err := ioutil.WriteFile(tmpPath, value, 0600); err != nil {
+			return encrypted, fmt.Errorf("unable to write secret to disk (%s), %w", tmpPath, err)
+		}
+
+		if err := os.Rename(tmpPath, secretPath); err != nil {
+			return encrypted, fmt.Errorf("unable to replace secret on disk (%s), %w", secretPath, err)
+		}
+
+		encrypted = append(encrypted, name)
+	}
+
+	return encrypted, nil
+}
+
+// encryptSecret encrypts the hex encoded secret to the keystore V3 format.
+// The validator keys are stored with their address, like the Ethereum account keys
+func encryptSecret(name string, value []byte, passphrase string) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimSpace(string(value)))
+	if err != nil {
+		return nil, err
+	}
+
+	address := ""
+
+	if name == secrets.ValidatorKey || name == secrets.NextValidatorKey {
+		validatorKey, err := crypto.ParsePrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		address = strings.TrimPrefix(strings.ToLower(crypto.PubKeyToAddress(&validatorKey.PublicKey).String()), "0x")
+	}
+
+	return keystore.EncryptKey(key, passphrase, address)
+}
+
+// HasEncryptedSecrets checks if any secret in the data directory is encrypted,
+// so the passphrase is required by the node
+func HasEncryptedSecrets(dataDir string) bool {
+	for _, secretPath := range []string{
+		filepath.Join(dataDir, secrets.ConsensusFolderLocal, secrets.ValidatorKeyLocal),
+		filepath.Join(dataDir, secrets.ConsensusFolderLocal, secrets.NextValidatorKeyLocal),
+		filepath.Join(dataDir, secrets.ConsensusFolderLocal, secrets.ValidatorBLSKeyLocal),
+		filepath.Join(dataDir, secrets.NetworkFolderLocal, secrets.NetworkKeyLocal),
+	} {
+		if secret, err := ioutil.ReadFile(secretPath); err == nil && keystore.IsEncrypted(secret) {
+			return true
+		}
+	}
+
+	return false
+}
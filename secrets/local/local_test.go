@@ -4,10 +4,13 @@ import (
 	"encoding/hex"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/helper/keystore"
 	"github.com/0xPolygon/polygon-edge/secrets"
 	"github.com/hashicorp/go-hclog"
 	libp2pCrypto "github.com/libp2p/go-libp2p-core/crypto"
@@ -273,3 +276,119 @@ func TestLocalSecretsManager_RemoveSecret(t *testing.T) {
 		})
 	}
 }
+
+// openLocalSecretsManager is a helper method for opening the local secrets manager
+// of the working directory, with the passphrase of the encrypted secrets
+func openLocalSecretsManager(t *testing.T, workingDirectory, passphrase string) *LocalSecretsManager {
+	t.Helper()
+
+	manager, factoryErr := SecretsManagerFactory(nil, &secrets.SecretsManagerParams{
+		Logger: hclog.NewNullLogger(),
+		Extra: map[string]interface{}{
+			secrets.Path:       workingDirectory,
+			secrets.Passphrase: passphrase,
+		},
+	})
+	if factoryErr != nil {
+		t.Fatalf("Unable to instantiate local secrets manager, %v", factoryErr)
+	}
+
+	localManager, ok := manager.(*LocalSecretsManager)
+	assert.True(t, ok)
+
+	return localManager
+}
+
+func TestLocalSecretsManager_Encrypted(t *testing.T) {
+	// Lower the scrypt cost of the encrypted test secrets
+	scryptN := keystore.ScryptN
+	keystore.ScryptN = 1 << 12
+
+	t.Cleanup(func() {
+		keystore.ScryptN = scryptN
+	})
+
+	validatorKey, validatorKeyEncoded, genErr := crypto.GenerateAndEncodePrivateKey()
+	if genErr != nil {
+		t.Fatalf("Unable to generate validator private key, %v", genErr)
+	}
+
+	_, libp2pKeyEncoded, genErr := generateAndEncodeLibp2pKey()
+	if genErr != nil {
+		t.Fatalf("Unable to generate networking private key, %v", genErr)
+	}
+
+	workingDirectory := t.TempDir()
+	validatorKeyPath := filepath.Join(workingDirectory, secrets.ConsensusFolderLocal, secrets.ValidatorKeyLocal)
+
+	t.Run("should store the secrets in the keystore format", func(t *testing.T) {
+		manager := openLocalSecretsManager(t, workingDirectory, "passphrase")
+
+		assert.NoError(t, manager.SetSecret(secrets.ValidatorKey, validatorKeyEncoded))
+
+		// the validator key is stored with its address, like the Ethereum account keys
+		secret, err := ioutil.ReadFile(validatorKeyPath)
+		assert.NoError(t, err)
+		assert.True(t, keystore.IsEncrypted(secret))
+		assert.Contains(
+			t,
+			string(secret),
+			strings.ToLower(crypto.PubKeyToAddress(&validatorKey.PublicKey).String()[2:]),
+		)
+
+		parsedKey, err := crypto.ReadConsensusKey(manager)
+		assert.NoError(t, err)
+		assert.True(t, validatorKey.Equal(parsedKey))
+
+		assert.True(t, HasEncryptedSecrets(workingDirectory))
+	})
+
+	t.Run("should not read the secrets without the passphrase", func(t *testing.T) {
+		manager := openLocalSecretsManager(t, workingDirectory, "")
+
+		_, err := manager.GetSecret(secrets.ValidatorKey)
+		assert.ErrorIs(t, err, secrets.ErrSecretEncrypted)
+
+		// the encrypted secret is present, so it isn't regenerated
+		assert.True(t, manager.HasSecret(secrets.ValidatorKey))
+	})
+
+	t.Run("should not read the secrets with a wrong passphrase", func(t *testing.T) {
+		manager := openLocalSecretsManager(t, workingDirectory, "wrong passphrase")
+
+		_, err := manager.GetSecret(secrets.ValidatorKey)
+		assert.ErrorIs(t, err, keystore.ErrDecrypt)
+		assert.True(t, manager.HasSecret(secrets.ValidatorKey))
+	})
+
+	t.Run("should encrypt the plaintext secrets in place", func(t *testing.T) {
+		plaintextDirectory := t.TempDir()
+
+		plaintext := openLocalSecretsManager(t, plaintextDirectory, "")
+		assert.NoError(t, plaintext.SetSecret(secrets.ValidatorKey, validatorKeyEncoded))
+		assert.NoError(t, plaintext.SetSecret(secrets.NetworkKey, libp2pKeyEncoded))
+		assert.False(t, HasEncryptedSecrets(plaintextDirectory))
+
+		manager := openLocalSecretsManager(t, plaintextDirectory, "passphrase")
+
+		encrypted, err := manager.EncryptSecrets()
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{secrets.ValidatorKey, secrets.NetworkKey}, encrypted)
+		assert.True(t, HasEncryptedSecrets(plaintextDirectory))
+
+		// the secrets read the same as before the encryption
+		for name, value := range map[string][]byte{
+			secrets.ValidatorKey: validatorKeyEncoded,
+			secrets.NetworkKey:   libp2pKeyEncoded,
+		} {
+			secret, err := manager.GetSecret(name)
+			assert.NoError(t, err)
+			assert.Equal(t, value, secret)
+		}
+
+		// the encrypted secrets are skipped
+		encrypted, err = manager.EncryptSecrets()
+		assert.NoError(t, err)
+		assert.Empty(t, encrypted)
+	})
+}
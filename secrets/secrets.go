@@ -1,6 +1,7 @@
 package secrets
 
 import (
+	"crypto/ecdsa"
 	"errors"
 
 	"github.com/hashicorp/go-hclog"
@@ -19,6 +20,9 @@ const (
 
 	// Name is the name of the current node
 	Name = "name"
+
+	// Passphrase is the passphrase of the encrypted local secrets
+	Passphrase = "passphrase"
 )
 
 // Define constant names for available secrets
@@ -26,14 +30,22 @@ const (
 	// ValidatorKey is the private key secret of the validator node
 	ValidatorKey = "validator-key"
 
+	// NextValidatorKey is the private key secret the validator node rotates to
+	NextValidatorKey = "next-validator-key"
+
+	// ValidatorBLSKey is the BLS secret key the validator node signs the aggregated committed seals with
+	ValidatorBLSKey = "validator-bls-key"
+
 	// NetworkKey is the libp2p private key secret used for networking
 	NetworkKey = "network-key"
 )
 
 // Define constant file names for the local StorageManager
 const (
-	ValidatorKeyLocal = "validator.key"
-	NetworkKeyLocal   = "libp2p.key"
+	ValidatorKeyLocal     = "validator.key"
+	NextValidatorKeyLocal = "next-validator.key"
+	ValidatorBLSKeyLocal  = "validator-bls.key"
+	NetworkKeyLocal       = "libp2p.key"
 )
 
 // Define constant folder names for the local StorageManager
@@ -43,7 +55,10 @@ const (
 )
 
 var (
-	ErrSecretNotFound = errors.New("secret not found")
+	ErrSecretNotFound      = errors.New("secret not found")
+	ErrSecretEncrypted     = errors.New("secret is encrypted, the passphrase is required to read it")
+	ErrSecretNotExportable = errors.New("secret is a key held by the KMS, it can't be exported")
+	ErrSecretNotImportable = errors.New("secret is a key held by the KMS, it has to be created in the KMS")
 )
 
 type SecretsManagerType string
@@ -58,6 +73,12 @@ const (
 
 	// AWSSSM pertains to AWS SSM using configured EC2 instance role
 	AWSSSM SecretsManagerType = "aws-ssm"
+
+	// AWSKMS pertains to the keys held by AWS KMS
+	AWSKMS SecretsManagerType = "aws-kms"
+
+	// GCPKMS pertains to the keys held by GCP Cloud KMS
+	GCPKMS SecretsManagerType = "gcp-kms"
 )
 
 // SecretsManager defines the base public interface that all
@@ -79,6 +100,20 @@ type SecretsManager interface {
 	RemoveSecret(name string) error
 }
 
+// KeySigner is implemented by the secrets managers holding the private keys in a KMS.
+// The key material never leaves the KMS, the digests are signed by the KMS instead
+type KeySigner interface {
+	// CreateKey creates the secp256k1 signing key of the secret in the KMS
+	CreateKey(name string) error
+
+	// PublicKey returns the public key of the secret
+	PublicKey(name string) (*ecdsa.PublicKey, error)
+
+	// SignDigest signs the 32 byte digest with the key of the secret,
+	// and returns the DER encoded ECDSA signature
+	SignDigest(name string, digest []byte) ([]byte, error)
+}
+
 // SecretsManagerParams defines the configuration params for the
 // secrets manager
 type SecretsManagerParams struct {
@@ -103,5 +138,6 @@ type SecretsManagerFactory func(
 // SupportedServiceManager checks if the passed in service manager type is supported
 func SupportedServiceManager(service SecretsManagerType) bool {
 	return service == HashicorpVault || service == AWSSSM ||
+		service == AWSKMS || service == GCPKMS ||
 		service == Local
 }
@@ -27,6 +27,16 @@ func TestSupportedServiceManager(t *testing.T) {
 			AWSSSM,
 			true,
 		},
+		{
+			"Valid AWS KMS secrets manager",
+			AWSKMS,
+			true,
+		},
+		{
+			"Valid GCP KMS secrets manager",
+			GCPKMS,
+			true,
+		},
 		{
 			"Invalid secrets manager",
 			"MarsSecretsManager",
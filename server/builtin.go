@@ -1,12 +1,17 @@
 package server
 
 import (
+	"github.com/0xPolygon/polygon-edge/blocksink"
+	"github.com/0xPolygon/polygon-edge/blocksink/grpcstream"
+	"github.com/0xPolygon/polygon-edge/blocksink/webhook"
 	"github.com/0xPolygon/polygon-edge/consensus"
 	consensusDev "github.com/0xPolygon/polygon-edge/consensus/dev"
 	consensusDummy "github.com/0xPolygon/polygon-edge/consensus/dummy"
 	consensusIBFT "github.com/0xPolygon/polygon-edge/consensus/ibft"
 	"github.com/0xPolygon/polygon-edge/secrets"
+	"github.com/0xPolygon/polygon-edge/secrets/awskms"
 	"github.com/0xPolygon/polygon-edge/secrets/awsssm"
+	"github.com/0xPolygon/polygon-edge/secrets/gcpkms"
 	"github.com/0xPolygon/polygon-edge/secrets/hashicorpvault"
 	"github.com/0xPolygon/polygon-edge/secrets/local"
 )
@@ -31,6 +36,14 @@ var secretsManagerBackends = map[secrets.SecretsManagerType]secrets.SecretsManag
 	secrets.Local:          local.SecretsManagerFactory,
 	secrets.HashicorpVault: hashicorpvault.SecretsManagerFactory,
 	secrets.AWSSSM:         awsssm.SecretsManagerFactory,
+	secrets.AWSKMS:         awskms.SecretsManagerFactory,
+	secrets.GCPKMS:         gcpkms.SecretsManagerFactory,
+}
+
+// blockSinkBackends defines the factories of the block sinks
+var blockSinkBackends = map[blocksink.SinkType]blocksink.Factory{
+	blocksink.Webhook: webhook.Factory,
+	blocksink.GRPC:    grpcstream.Factory,
 }
 
 func ConsensusSupported(value string) bool {
@@ -2,10 +2,15 @@ package server
 
 import (
 	"net"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 
+	"github.com/0xPolygon/polygon-edge/alerting"
+	"github.com/0xPolygon/polygon-edge/blocksink"
 	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/jsonrpc"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/secrets"
 )
@@ -21,21 +26,100 @@ type Config struct {
 	GRPCAddr   *net.TCPAddr
 	LibP2PAddr *net.TCPAddr
 
-	PriceLimit uint64
-	MaxSlots   uint64
-	BlockTime  uint64
+	// GRPCSecurity is the TLS and the authentication of the operator GRPC server, plaintext and open if nil
+	GRPCSecurity *GRPCSecurity
+
+	PriceLimit         uint64
+	PriceBump          uint64
+	MaxSlots           uint64
+	MaxAccountEnqueued uint64
+	MaxAccountPromoted uint64
+	JournalPath        string
+	JournalRotate      time.Duration
+	BlockTime          uint64
+
+	// AllowLocalUnderpriced exempts the local transactions from the price limit and the eviction
+	// below the base fee or when the pool is full
+	AllowLocalUnderpriced bool
+	// UnderpricedEvictionBlocks is the number of blocks a transaction can stay below the base fee, 0 for no eviction
+	UnderpricedEvictionBlocks uint64
+
+	// AllowedFutureDrift is the drift of the block timestamps ahead of the local clock in seconds,
+	// the drift of the chain is used if not set
+	AllowedFutureDrift uint64
+
+	IBFTSnapshotRetention uint64
+	IBFTMsgRateLimit      uint64
+	IBFTRemoteSigner      *consensus.RemoteSignerConfig
+	IBFTWALDir            string
+
+	BlockVanity string
+
+	FastSync bool
+
+	NonValidator bool
+
+	// SecretsPassphrase is the passphrase of the encrypted local secrets
+	SecretsPassphrase string
+
+	PruneState     bool
+	PruneRetention uint64
 
 	Telemetry *Telemetry
 	Network   *network.Config
 
+	// Health are the address and the readiness thresholds of the health endpoints
+	Health *Health
+
 	DataDir     string
 	RestoreFile *string
 
+	// DBEngine is the key value database of the data dir,
+	// the one of the existing databases if not set
+	DBEngine string
+
+	// StateCacheSize is the memory budget of the cache of the state trie nodes
+	// and the contract code in MB, the cache is disabled if zero
+	StateCacheSize uint64
+
+	// LevelDBWriteBuffer, LevelDBBlockCache and LevelDBOpenFiles tune the leveldb databases of the data dir,
+	// the sizes of the memtable and of the block cache in MB and the number of the table files kept open.
+	// The defaults of leveldb are used if zero
+	LevelDBWriteBuffer uint64
+	LevelDBBlockCache  uint64
+	LevelDBOpenFiles   uint64
+
+	// IndexInternalTxs records the internal transactions of the executed blocks
+	IndexInternalTxs bool
+
+	// ParallelExecution executes the transactions of the blocks optimistically in parallel
+	ParallelExecution bool
+
+	// BadBlockDir is the directory the rejected blocks are dumped to, they are only kept in memory if not set
+	BadBlockDir string
+
+	// BlockSinks are the sinks the finalized blocks are pushed to
+	BlockSinks []*blocksink.Config
+
+	// Alerts is the configuration of the alerts of the reorgs and of the stale head, they are disabled if nil
+	Alerts *alerting.Config
+
 	Seal bool
 
 	SecretsManager *secrets.SecretsManagerConfig
 
-	LogLevel hclog.Level
+	LogLevel        hclog.Level
+	LogModuleLevels map[string]hclog.Level
+	JSONLogFormat   bool
+	LogFile         *LogFile
+}
+
+// LogFile holds the config details for the log file output, the logs
+// are written to the console if not set
+type LogFile struct {
+	Path       string
+	MaxSize    int64
+	MaxBackups uint64
 }
 
 // Telemetry holds the config details for metric services
@@ -43,8 +127,35 @@ type Telemetry struct {
 	PrometheusAddr *net.TCPAddr
 }
 
+// Health holds the config details for the health and the readiness endpoints,
+// they are not served if the address is not set
+type Health struct {
+	Addr *net.TCPAddr
+	// MaxBlockLag is the number of blocks the node can be behind its best peer and be ready
+	MaxBlockLag uint64
+	// MinPeers is the number of peers the node has to be connected to to be ready
+	MinPeers uint64
+}
+
 // JSONRPC holds the config details for the JSON-RPC server
 type JSONRPC struct {
 	JSONRPCAddr              *net.TCPAddr
 	AccessControlAllowOrigin []string
+	FeeHistoryLimit          uint64
+	BlockRangeLimit          uint64
+	LogsLimit                uint64
+	SubscriptionBufferSize   uint64
+	AllowedMethods           []string
+	DeniedMethods            []string
+	RateLimit                uint64
+	MethodRateLimits         map[string]uint64
+	BatchLimit               uint64
+	BatchWorkers             uint64
+	FilterTimeout            time.Duration
+	SyncDistance             uint64
+	AccessControl            *jsonrpc.AccessControl
+	GraphQLAddr              *net.TCPAddr
+	ExecutionTimeout         time.Duration
+	MethodExecutionTimeouts  map[string]time.Duration
+	ExecutionLimit           uint64
 }
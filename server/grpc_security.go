@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// GRPCAuthHeader is the metadata key of the operator token
+	GRPCAuthHeader = "authorization"
+
+	// GRPCAuthScheme is the scheme the operator token is prefixed with
+	GRPCAuthScheme = "Bearer "
+)
+
+// regularGRPCMethods are the operator methods that accept the regular token, the read-only ones
+// and the transaction submission. All the other methods require the admin token, if it is set
+var regularGRPCMethods = map[string]struct{}{
+	"/v1.System/GetStatus":                     {},
+	"/v1.System/PeersList":                     {},
+	"/v1.System/PeersStatus":                   {},
+	"/v1.System/BlockByNumber":                 {},
+	"/v1.System/Subscribe":                     {},
+	"/v1.IbftOperator/GetSnapshot":             {},
+	"/v1.IbftOperator/Candidates":              {},
+	"/v1.IbftOperator/Status":                  {},
+	"/v1.IbftOperator/Inspect":                 {},
+	"/v1.IbftOperator/BadBlocks":               {},
+	"/v1.IbftOperator/ListPendingVotes":        {},
+	"/v1.IbftLightClient/GetValidatorSetProof": {},
+	"/v1.IbftLightClient/GetHeadersWithSeals":  {},
+	"/v1.TxnPoolOperator/Status":               {},
+	"/v1.TxnPoolOperator/AddTxn":               {},
+	"/v1.TxnPoolOperator/Subscribe":            {},
+}
+
+var (
+	errGRPCTLSKeyPair   = errors.New("both the TLS certificate and the TLS key of the GRPC server should be set")
+	errGRPCClientCA     = errors.New("the client CA of the GRPC server requires the TLS certificate")
+	errInvalidClientCA  = errors.New("no certificates found in the client CA file")
+	errMissingGRPCToken = status.Error(codes.Unauthenticated, "missing operator token")
+	errInvalidGRPCToken = status.Error(codes.Unauthenticated, "invalid operator token")
+	errAdminGRPCToken   = status.Error(codes.PermissionDenied, "the method requires the admin token")
+)
+
+// GRPCSecurity holds the TLS and the authentication config of the operator GRPC server
+type GRPCSecurity struct {
+	// TLSCertFile and TLSKeyFile are the key pair of the server, the connections are not encrypted if not set
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// TLSClientCAFile is the CA the client certificates are verified with (mTLS), if set
+	TLSClientCAFile string
+
+	// Token is the bearer token required by the operator methods, if set
+	Token string
+
+	// AdminToken is the bearer token required by the sensitive operator methods, if set.
+	// It is also accepted by the other methods
+	AdminToken string
+}
+
+// isEncrypted checks if the GRPC server accepts only the TLS connections
+func (c *GRPCSecurity) isEncrypted() bool {
+	return c != nil && c.TLSCertFile != ""
+}
+
+// isAuthenticated checks if the GRPC server requires either of the tokens
+func (c *GRPCSecurity) isAuthenticated() bool {
+	return c != nil && (c.Token != "" || c.AdminToken != "")
+}
+
+// newGRPCServer creates the operator GRPC server, with the TLS and the token authentication set
+func newGRPCServer(config *GRPCSecurity) (*grpc.Server, error) {
+	opts := make([]grpc.ServerOption, 0)
+
+	if config.isEncrypted() {
+		creds, err := newGRPCServerCredentials(config)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, grpc.Creds(creds))
+	} else if config != nil && (config.TLSKeyFile != "" || config.TLSClientCAFile != "") {
+		if config.TLSKeyFile != "" {
+			return nil, errGRPCTLSKeyPair
+		}
+
+		return nil, errGRPCClientCA
+	}
+
+	if config.isAuthenticated() {
+		auth := &grpcAuthenticator{
+			token:      config.Token,
+			adminToken: config.AdminToken,
+		}
+
+		opts = append(
+			opts,
+			grpc.UnaryInterceptor(auth.unaryInterceptor),
+			grpc.StreamInterceptor(auth.streamInterceptor),
+		)
+	}
+
+	return grpc.NewServer(opts...), nil
+}
+
+// newGRPCServerCredentials loads the key pair of the server, and the CA of the clients if mTLS is set
+func newGRPCServerCredentials(config *GRPCSecurity) (credentials.TransportCredentials, error) {
+	if config.TLSKeyFile == "" {
+		return nil, errGRPCTLSKeyPair
+	}
+
+	cert, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the GRPC server key pair, %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if config.TLSClientCAFile != "" {
+		rawCA, err := ioutil.ReadFile(config.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the GRPC client CA, %w", err)
+		}
+
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(rawCA) {
+			return nil, errInvalidClientCA
+		}
+
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// grpcAuthenticator checks the bearer token of the operator requests
+type grpcAuthenticator struct {
+	token      string
+	adminToken string
+}
+
+func (a *grpcAuthenticator) unaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if err := a.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (a *grpcAuthenticator) streamInterceptor(
+	srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if err := a.authorize(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+
+	return handler(srv, stream)
+}
+
+// authorize checks the token of the request against the tokens the method accepts.
+// The regular methods accept either token, the other methods accept only the admin token if it's set
+func (a *grpcAuthenticator) authorize(ctx context.Context, method string) error {
+	_, isRegularMethod := regularGRPCMethods[method]
+	isAdminMethod := !isRegularMethod
+
+	if isAdminMethod && a.adminToken != "" {
+		token, err := requestToken(ctx)
+		if err != nil {
+			return err
+		}
+
+		if !tokensEqual(token, a.adminToken) {
+			return errAdminGRPCToken
+		}
+
+		return nil
+	}
+
+	if a.token == "" {
+		// only the admin methods are protected
+		return nil
+	}
+
+	token, err := requestToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !tokensEqual(token, a.token) && (a.adminToken == "" || !tokensEqual(token, a.adminToken)) {
+		return errInvalidGRPCToken
+	}
+
+	return nil
+}
+
+// requestToken returns the bearer token in the metadata of the request
+func requestToken(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", errMissingGRPCToken
+	}
+
+	values := md.Get(GRPCAuthHeader)
+	if len(values) == 0 || !strings.HasPrefix(values[0], GRPCAuthScheme) {
+		return "", errMissingGRPCToken
+	}
+
+	return strings.TrimPrefix(values[0], GRPCAuthScheme), nil
+}
+
+// tokensEqual compares the tokens in constant time
+func tokensEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
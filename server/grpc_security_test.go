@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	ibftProto "github.com/0xPolygon/polygon-edge/consensus/ibft/proto"
+	"github.com/0xPolygon/polygon-edge/server/proto"
+	txpoolProto "github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	testMethod      = "/v1.System/PeersList"
+	testAdminMethod = "/v1.IbftOperator/Propose"
+)
+
+func contextWithToken(token string) context.Context {
+	if token == "" {
+		return context.Background()
+	}
+
+	return metadata.NewIncomingContext(
+		context.Background(),
+		metadata.Pairs(GRPCAuthHeader, GRPCAuthScheme+token),
+	)
+}
+
+func TestGRPCAuthenticator_Authorize(t *testing.T) {
+	testTable := []struct {
+		name        string
+		token       string
+		adminToken  string
+		method      string
+		request     string
+		expectedErr error
+	}{
+		{"token accepted", "token", "", testMethod, "token", nil},
+		{"missing token", "token", "", testMethod, "", errMissingGRPCToken},
+		{"invalid token", "token", "", testMethod, "other", errInvalidGRPCToken},
+		{"admin token accepted by the regular method", "token", "admin", testMethod, "admin", nil},
+		{"admin method without the admin token set", "token", "", testAdminMethod, "token", nil},
+		{"admin method with the admin token", "token", "admin", testAdminMethod, "admin", nil},
+		{"admin method with the regular token", "token", "admin", testAdminMethod, "token", errAdminGRPCToken},
+		{"admin method without a token", "", "admin", testAdminMethod, "", errMissingGRPCToken},
+		{"regular method open with only the admin token set", "", "admin", testMethod, "", nil},
+		{"add peer, regular token", "token", "admin", "/v1.System/PeersAdd", "token", errAdminGRPCToken},
+		{"unknown method, regular token", "token", "admin", "/v1.System/Unknown", "token", errAdminGRPCToken},
+		{"ban peer, regular token", "token", "admin", "/v1.System/PeersBan", "token", errAdminGRPCToken},
+		{"unban peer, regular token", "token", "admin", "/v1.System/PeersUnban", "token", errAdminGRPCToken},
+		{"add static peer, regular token", "token", "admin", "/v1.System/PeersAddStatic", "token", errAdminGRPCToken},
+		{"remove static peer, regular token", "token", "admin", "/v1.System/PeersRemoveStatic", "token", errAdminGRPCToken},
+		{"snapshot, regular token", "token", "admin", "/v1.System/Snapshot", "token", errAdminGRPCToken},
+		{"export, regular token", "token", "admin", "/v1.System/Export", "token", errAdminGRPCToken},
+		{"ban peer, admin token", "token", "admin", "/v1.System/PeersBan", "admin", nil},
+		{"export, admin token", "token", "admin", "/v1.System/Export", "admin", nil},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			auth := &grpcAuthenticator{
+				token:      testCase.token,
+				adminToken: testCase.adminToken,
+			}
+
+			assert.Equal(
+				t,
+				testCase.expectedErr,
+				auth.authorize(contextWithToken(testCase.request), testCase.method),
+			)
+		})
+	}
+}
+
+func TestGRPCMethods_Classification(t *testing.T) {
+	// the methods of the operator services that require the admin token
+	adminMethods := map[string]struct{}{
+		"/v1.System/PeersAdd":                 {},
+		"/v1.System/PeersBan":                 {},
+		"/v1.System/PeersUnban":               {},
+		"/v1.System/PeersAddStatic":           {},
+		"/v1.System/PeersRemoveStatic":        {},
+		"/v1.System/DBCompact":                {},
+		"/v1.System/Export":                   {},
+		"/v1.System/Snapshot":                 {},
+		"/v1.IbftOperator/Propose":            {},
+		"/v1.IbftOperator/RotateValidatorKey": {},
+		"/v1.IbftOperator/RetractVote":        {},
+	}
+
+	registered := map[string]struct{}{}
+
+	for _, desc := range []grpc.ServiceDesc{
+		proto.System_ServiceDesc,
+		ibftProto.IbftOperator_ServiceDesc,
+		ibftProto.IbftLightClient_ServiceDesc,
+		txpoolProto.TxnPoolOperator_ServiceDesc,
+	} {
+		for _, method := range desc.Methods {
+			registered["/"+desc.ServiceName+"/"+method.MethodName] = struct{}{}
+		}
+
+		for _, stream := range desc.Streams {
+			registered["/"+desc.ServiceName+"/"+stream.StreamName] = struct{}{}
+		}
+	}
+
+	auth := &grpcAuthenticator{
+		token:      "token",
+		adminToken: "admin",
+	}
+
+	for method := range registered {
+		_, isAdminMethod := adminMethods[method]
+
+		err := auth.authorize(contextWithToken("token"), method)
+		if isAdminMethod {
+			assert.Equal(t, errAdminGRPCToken, err, method)
+		} else {
+			assert.NoError(t, err, method)
+		}
+	}
+
+	// the regular methods have to match the full names of the methods of the operator services
+	for method := range regularGRPCMethods {
+		assert.Contains(t, registered, method)
+	}
+}
+
+func TestNewGRPCServer_InvalidTLS(t *testing.T) {
+	_, err := newGRPCServer(&GRPCSecurity{TLSKeyFile: "key.pem"})
+	assert.ErrorIs(t, err, errGRPCTLSKeyPair)
+
+	_, err = newGRPCServer(&GRPCSecurity{TLSCertFile: "cert.pem"})
+	assert.ErrorIs(t, err, errGRPCTLSKeyPair)
+
+	_, err = newGRPCServer(&GRPCSecurity{TLSClientCAFile: "ca.pem"})
+	assert.ErrorIs(t, err, errGRPCClientCA)
+
+	server, err := newGRPCServer(nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, server)
+}
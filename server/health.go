@@ -0,0 +1,144 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+
+	consensusIBFT "github.com/0xPolygon/polygon-edge/consensus/ibft"
+	"github.com/0xPolygon/polygon-edge/server/proto"
+)
+
+// healthReport is the health and the readiness of the node, as served by the health endpoints
+type healthReport struct {
+	// Healthy is set while the process is up and the storage is open
+	Healthy bool `json:"healthy"`
+	// Ready is set once the node is healthy, synced with its best peer,
+	// running the consensus and connected to enough peers
+	Ready bool `json:"ready"`
+
+	StorageOpen      bool   `json:"storageOpen"`
+	ConsensusRunning bool   `json:"consensusRunning"`
+	Head             uint64 `json:"head"`
+	BestPeer         uint64 `json:"bestPeer"`
+	MaxBlockLag      uint64 `json:"maxBlockLag"`
+	Peers            uint64 `json:"peers"`
+	MinPeers         uint64 `json:"minPeers"`
+
+	// Failures are the reasons the node is not healthy or not ready
+	Failures []string `json:"failures"`
+}
+
+// check evaluates the report against the thresholds of the config
+func (r *healthReport) check(config *Health) {
+	r.MaxBlockLag = config.MaxBlockLag
+	r.MinPeers = config.MinPeers
+	r.Failures = []string{}
+
+	if !r.StorageOpen {
+		r.Failures = append(r.Failures, "the storage can't be read")
+	}
+
+	r.Healthy = len(r.Failures) == 0
+
+	if !r.ConsensusRunning {
+		r.Failures = append(r.Failures, "the consensus is not running")
+	}
+
+	if r.BestPeer > r.Head && r.BestPeer-r.Head > r.MaxBlockLag {
+		r.Failures = append(r.Failures,
+			fmt.Sprintf("%d blocks behind the best peer, more than %d", r.BestPeer-r.Head, r.MaxBlockLag))
+	}
+
+	if r.Peers < r.MinPeers {
+		r.Failures = append(r.Failures, fmt.Sprintf("%d peers connected, less than %d", r.Peers, r.MinPeers))
+	}
+
+	r.Ready = len(r.Failures) == 0
+}
+
+// toProto converts the report to the health of the operator status
+func (r *healthReport) toProto() *proto.ServerStatus_Health {
+	return &proto.ServerStatus_Health{
+		Healthy:          r.Healthy,
+		Ready:            r.Ready,
+		StorageOpen:      r.StorageOpen,
+		ConsensusRunning: r.ConsensusRunning,
+		BestPeer:         int64(r.BestPeer),
+		MaxBlockLag:      int64(r.MaxBlockLag),
+		Peers:            int64(r.Peers),
+		MinPeers:         int64(r.MinPeers),
+		Failures:         r.Failures,
+	}
+}
+
+// healthReport reports the health and the readiness of the node.
+// The best peer is only known to the IBFT syncer, the other consensuses are never behind
+func (s *Server) healthReport() *healthReport {
+	head := s.blockchain.Header()
+
+	// the canonical hash is read from the storage on every call
+	_, storageOpen := s.blockchain.GetHeaderByNumber(head.Number)
+
+	report := &healthReport{
+		StorageOpen:      storageOpen,
+		ConsensusRunning: atomic.LoadUint32(&s.consensusRunning) == 1,
+		Head:             head.Number,
+		BestPeer:         head.Number,
+		Peers:            uint64(len(s.network.Peers())),
+	}
+
+	if ibft, ok := s.consensus.(*consensusIBFT.Ibft); ok {
+		if number, ok := ibft.BestPeerNumber(); ok && number > head.Number {
+			report.BestPeer = number
+		}
+	}
+
+	report.check(s.config.Health)
+
+	return report
+}
+
+// healthHandler serves the health report, with the 503 status code if the report doesn't pass
+func healthHandler(report func() *healthReport, passed func(*healthReport) bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		r := report()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if !passed(r) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		_ = json.NewEncoder(w).Encode(r)
+	}
+}
+
+// startHealthServer serves the liveness of the node at /health, and its readiness at /ready
+func (s *Server) startHealthServer(listenAddr *net.TCPAddr) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", healthHandler(s.healthReport, func(r *healthReport) bool {
+		return r.Healthy
+	}))
+	mux.HandleFunc("/ready", healthHandler(s.healthReport, func(r *healthReport) bool {
+		return r.Ready
+	}))
+
+	srv := &http.Server{
+		Addr:    listenAddr.String(),
+		Handler: mux,
+	}
+
+	go func() {
+		s.logger.Info("Health server started", "addr", listenAddr.String())
+
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Health HTTP server ListenAndServe", "err", err)
+		}
+	}()
+
+	return srv
+}
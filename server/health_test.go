@@ -0,0 +1,89 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthReport_Check(t *testing.T) {
+	t.Parallel()
+
+	config := &Health{MaxBlockLag: 5, MinPeers: 2}
+
+	newReport := func() *healthReport {
+		return &healthReport{
+			StorageOpen:      true,
+			ConsensusRunning: true,
+			Head:             10,
+			BestPeer:         15,
+			Peers:            2,
+		}
+	}
+
+	testTable := []struct {
+		name     string
+		modify   func(r *healthReport)
+		healthy  bool
+		ready    bool
+		failures int
+	}{
+		{"healthy and ready", func(r *healthReport) {}, true, true, 0},
+		{"storage closed", func(r *healthReport) { r.StorageOpen = false }, false, false, 1},
+		{"consensus not running", func(r *healthReport) { r.ConsensusRunning = false }, true, false, 1},
+		{"behind the best peer", func(r *healthReport) { r.BestPeer = 16 }, true, false, 1},
+		{"not enough peers", func(r *healthReport) { r.Peers = 1 }, true, false, 1},
+		{"all failing", func(r *healthReport) {
+			r.StorageOpen, r.ConsensusRunning, r.BestPeer, r.Peers = false, false, 100, 0
+		}, false, false, 4},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			report := newReport()
+			testCase.modify(report)
+			report.check(config)
+
+			assert.Equal(t, testCase.healthy, report.Healthy)
+			assert.Equal(t, testCase.ready, report.Ready)
+			assert.Len(t, report.Failures, testCase.failures)
+			assert.Equal(t, config.MaxBlockLag, report.MaxBlockLag)
+			assert.Equal(t, config.MinPeers, report.MinPeers)
+		})
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	t.Parallel()
+
+	report := &healthReport{StorageOpen: true, Head: 10, BestPeer: 10}
+	report.check(&Health{})
+
+	serve := func(passed func(*healthReport) bool) (*httptest.ResponseRecorder, *healthReport) {
+		recorder := httptest.NewRecorder()
+		healthHandler(func() *healthReport { return report }, passed).
+			ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		served := &healthReport{}
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), served))
+
+		return recorder, served
+	}
+
+	// the node is healthy, but not ready without the consensus running
+	recorder, served := serve(func(r *healthReport) bool { return r.Healthy })
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, report, served)
+
+	recorder, served = serve(func(r *healthReport) bool { return r.Ready })
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, []string{"the consensus is not running"}, served.Failures)
+}
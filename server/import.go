@@ -0,0 +1,148 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/archive"
+	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/helper/progress"
+)
+
+// ImportChain writes the blocks of the exported chain file to the data dir of the config,
+// without starting the node. The blocks are verified by the consensus and executed
+// like the synced ones. It returns the range of the blocks written
+func ImportChain(config *Config, filePath string) (uint64, uint64, error) {
+	m, err := newOfflineServer(config)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	defer m.closeOffline()
+
+	return archive.ImportChain(m.blockchain, m.logger, filePath, m.restoreProgression)
+}
+
+// RestoreSnapshot rebuilds the data dir of the config from the snapshot, without starting
+// the node. The blocks are verified by the consensus, the state of the head is taken
+// from the snapshot instead of executing them
+func RestoreSnapshot(config *Config, filePath string) (*archive.SnapshotManifest, error) {
+	m, err := newOfflineServer(config)
+	if err != nil {
+		return nil, err
+	}
+
+	defer m.closeOffline()
+
+	return archive.RestoreSnapshot(
+		m.blockchain,
+		m.stateStorage,
+		uint64(config.Chain.Params.ChainID),
+		m.logger,
+		filePath,
+	)
+}
+
+// RollbackChain moves the head of the chain of the data dir of the config back to the block,
+// without starting the node. The blocks above it are deleted, the state of the block
+// must still be available to run the chain from it
+func RollbackChain(config *Config, number uint64) (*blockchain.RollbackResult, error) {
+	m, err := newOfflineServer(config)
+	if err != nil {
+		return nil, err
+	}
+
+	defer m.closeOffline()
+
+	if head := m.blockchain.Header(); number >= head.Number {
+		return nil, fmt.Errorf("%w: %d, the head is %d", blockchain.ErrRollbackAboveHead, number, head.Number)
+	}
+
+	header, ok := m.blockchain.GetHeaderByNumber(number)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", number)
+	}
+
+	// the state of the root is not available anymore once pruned
+	if _, err := m.state.NewSnapshotAt(header.StateRoot); err != nil {
+		return nil, fmt.Errorf("the state of block %d is not available, it may be pruned: %w", number, err)
+	}
+
+	return m.blockchain.Rollback(number)
+}
+
+// newOfflineServer sets up the blockchain of the data dir of the config, with the consensus
+// verifying the blocks. The networking and the consensus are not started
+func newOfflineServer(config *Config) (*Server, error) {
+	logger, logFile, err := newLogger(config)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Server{
+		logger:             logger,
+		logFile:            logFile,
+		config:             config,
+		chain:              config.Chain,
+		serverMetrics:      metricProvider("polygon", config.Chain.Name, false),
+		restoreProgression: progress.NewProgressionWrapper(progress.ChainSyncRestore),
+	}
+
+	m.logger.Info("Data dir", "path", config.DataDir)
+
+	// the consensus saves its snapshots on close
+	if err := common.SetupDataDir(config.DataDir, append([]string{"consensus"}, dirPaths...)); err != nil {
+		return nil, fmt.Errorf("failed to create data directories: %w", err)
+	}
+
+	if err := m.setupBlockchain(); err != nil {
+		return nil, err
+	}
+
+	if err := m.setupConsensus(); err != nil {
+		m.closeStorage()
+
+		return nil, err
+	}
+
+	m.blockchain.SetConsensus(m.consensus)
+
+	if err := m.blockchain.ComputeGenesis(); err != nil {
+		m.closeStorage()
+
+		return nil, err
+	}
+
+	if err := m.consensus.Initialize(); err != nil {
+		m.closeStorage()
+
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// closeOffline closes the layers set up by newOfflineServer
+func (s *Server) closeOffline() {
+	if err := s.consensus.Close(); err != nil {
+		s.logger.Error("failed to close consensus", "err", err.Error())
+	}
+
+	s.closeStorage()
+	s.closeLogFile()
+}
+
+// closeStorage closes the blockchain and the state storage
+func (s *Server) closeStorage() {
+	if err := s.blockchain.Close(); err != nil {
+		s.logger.Error("failed to close blockchain", "err", err.Error())
+	}
+
+	if s.pruneSub != nil {
+		s.pruneSub.Close()
+	}
+
+	if err := s.stateStorage.Close(); err != nil {
+		s.logger.Error("failed to close storage for trie", "err", err.Error())
+	}
+}
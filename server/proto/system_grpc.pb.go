@@ -27,12 +27,24 @@ type SystemClient interface {
 	PeersList(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*PeersListResponse, error)
 	// PeersInfo returns the info of a peer
 	PeersStatus(ctx context.Context, in *PeersStatusRequest, opts ...grpc.CallOption) (*Peer, error)
+	// PeersBan bans a peer, closing its connections
+	PeersBan(ctx context.Context, in *PeersBanRequest, opts ...grpc.CallOption) (*PeersBanResponse, error)
+	// PeersUnban lifts the ban of a peer, and resets its score
+	PeersUnban(ctx context.Context, in *PeersUnbanRequest, opts ...grpc.CallOption) (*PeersUnbanResponse, error)
+	// PeersAddStatic adds a static peer, the node keeps connected with it at all times
+	PeersAddStatic(ctx context.Context, in *PeersAddRequest, opts ...grpc.CallOption) (*PeersAddResponse, error)
+	// PeersRemoveStatic removes a static peer, it isn't redialed anymore
+	PeersRemoveStatic(ctx context.Context, in *PeersRemoveStaticRequest, opts ...grpc.CallOption) (*PeersRemoveStaticResponse, error)
 	// Subscribe subscribes to blockchain events
 	Subscribe(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (System_SubscribeClient, error)
 	// Export returns blockchain data
 	BlockByNumber(ctx context.Context, in *BlockByNumberRequest, opts ...grpc.CallOption) (*BlockResponse, error)
 	// Export returns blockchain data
 	Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (System_ExportClient, error)
+	// Snapshot returns a consistent snapshot of the blocks and the state of the head
+	Snapshot(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (System_SnapshotClient, error)
+	// DBCompact compacts the databases of the data dir, discarding the deleted and the overwritten entries
+	DBCompact(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*DBCompactResponse, error)
 }
 
 type systemClient struct {
@@ -79,6 +91,42 @@ func (c *systemClient) PeersStatus(ctx context.Context, in *PeersStatusRequest,
 	return out, nil
 }
 
+func (c *systemClient) PeersBan(ctx context.Context, in *PeersBanRequest, opts ...grpc.CallOption) (*PeersBanResponse, error) {
+	out := new(PeersBanResponse)
+	err := c.cc.Invoke(ctx, "/v1.System/PeersBan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *systemClient) PeersUnban(ctx context.Context, in *PeersUnbanRequest, opts ...grpc.CallOption) (*PeersUnbanResponse, error) {
+	out := new(PeersUnbanResponse)
+	err := c.cc.Invoke(ctx, "/v1.System/PeersUnban", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *systemClient) PeersAddStatic(ctx context.Context, in *PeersAddRequest, opts ...grpc.CallOption) (*PeersAddResponse, error) {
+	out := new(PeersAddResponse)
+	err := c.cc.Invoke(ctx, "/v1.System/PeersAddStatic", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *systemClient) PeersRemoveStatic(ctx context.Context, in *PeersRemoveStaticRequest, opts ...grpc.CallOption) (*PeersRemoveStaticResponse, error) {
+	out := new(PeersRemoveStaticResponse)
+	err := c.cc.Invoke(ctx, "/v1.System/PeersRemoveStatic", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *systemClient) Subscribe(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (System_SubscribeClient, error) {
 	stream, err := c.cc.NewStream(ctx, &System_ServiceDesc.Streams[0], "/v1.System/Subscribe", opts...)
 	if err != nil {
@@ -152,6 +200,47 @@ func (x *systemExportClient) Recv() (*ExportEvent, error) {
 	return m, nil
 }
 
+func (c *systemClient) Snapshot(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (System_SnapshotClient, error) {
+	stream, err := c.cc.NewStream(ctx, &System_ServiceDesc.Streams[2], "/v1.System/Snapshot", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &systemSnapshotClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type System_SnapshotClient interface {
+	Recv() (*SnapshotEvent, error)
+	grpc.ClientStream
+}
+
+type systemSnapshotClient struct {
+	grpc.ClientStream
+}
+
+func (x *systemSnapshotClient) Recv() (*SnapshotEvent, error) {
+	m := new(SnapshotEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *systemClient) DBCompact(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*DBCompactResponse, error) {
+	out := new(DBCompactResponse)
+	err := c.cc.Invoke(ctx, "/v1.System/DBCompact", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SystemServer is the server API for System service.
 // All implementations must embed UnimplementedSystemServer
 // for forward compatibility
@@ -164,12 +253,24 @@ type SystemServer interface {
 	PeersList(context.Context, *emptypb.Empty) (*PeersListResponse, error)
 	// PeersInfo returns the info of a peer
 	PeersStatus(context.Context, *PeersStatusRequest) (*Peer, error)
+	// PeersBan bans a peer, closing its connections
+	PeersBan(context.Context, *PeersBanRequest) (*PeersBanResponse, error)
+	// PeersUnban lifts the ban of a peer, and resets its score
+	PeersUnban(context.Context, *PeersUnbanRequest) (*PeersUnbanResponse, error)
+	// PeersAddStatic adds a static peer, the node keeps connected with it at all times
+	PeersAddStatic(context.Context, *PeersAddRequest) (*PeersAddResponse, error)
+	// PeersRemoveStatic removes a static peer, it isn't redialed anymore
+	PeersRemoveStatic(context.Context, *PeersRemoveStaticRequest) (*PeersRemoveStaticResponse, error)
 	// Subscribe subscribes to blockchain events
 	Subscribe(*emptypb.Empty, System_SubscribeServer) error
 	// Export returns blockchain data
 	BlockByNumber(context.Context, *BlockByNumberRequest) (*BlockResponse, error)
 	// Export returns blockchain data
 	Export(*ExportRequest, System_ExportServer) error
+	// Snapshot returns a consistent snapshot of the blocks and the state of the head
+	Snapshot(*emptypb.Empty, System_SnapshotServer) error
+	// DBCompact compacts the databases of the data dir, discarding the deleted and the overwritten entries
+	DBCompact(context.Context, *emptypb.Empty) (*DBCompactResponse, error)
 	mustEmbedUnimplementedSystemServer()
 }
 
@@ -189,6 +290,18 @@ func (UnimplementedSystemServer) PeersList(context.Context, *emptypb.Empty) (*Pe
 func (UnimplementedSystemServer) PeersStatus(context.Context, *PeersStatusRequest) (*Peer, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PeersStatus not implemented")
 }
+func (UnimplementedSystemServer) PeersBan(context.Context, *PeersBanRequest) (*PeersBanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PeersBan not implemented")
+}
+func (UnimplementedSystemServer) PeersUnban(context.Context, *PeersUnbanRequest) (*PeersUnbanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PeersUnban not implemented")
+}
+func (UnimplementedSystemServer) PeersAddStatic(context.Context, *PeersAddRequest) (*PeersAddResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PeersAddStatic not implemented")
+}
+func (UnimplementedSystemServer) PeersRemoveStatic(context.Context, *PeersRemoveStaticRequest) (*PeersRemoveStaticResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PeersRemoveStatic not implemented")
+}
 func (UnimplementedSystemServer) Subscribe(*emptypb.Empty, System_SubscribeServer) error {
 	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
 }
@@ -198,6 +311,12 @@ func (UnimplementedSystemServer) BlockByNumber(context.Context, *BlockByNumberRe
 func (UnimplementedSystemServer) Export(*ExportRequest, System_ExportServer) error {
 	return status.Errorf(codes.Unimplemented, "method Export not implemented")
 }
+func (UnimplementedSystemServer) Snapshot(*emptypb.Empty, System_SnapshotServer) error {
+	return status.Errorf(codes.Unimplemented, "method Snapshot not implemented")
+}
+func (UnimplementedSystemServer) DBCompact(context.Context, *emptypb.Empty) (*DBCompactResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DBCompact not implemented")
+}
 func (UnimplementedSystemServer) mustEmbedUnimplementedSystemServer() {}
 
 // UnsafeSystemServer may be embedded to opt out of forward compatibility for this service.
@@ -283,6 +402,78 @@ func _System_PeersStatus_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _System_PeersBan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeersBanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).PeersBan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/PeersBan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).PeersBan(ctx, req.(*PeersBanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _System_PeersUnban_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeersUnbanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).PeersUnban(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/PeersUnban",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).PeersUnban(ctx, req.(*PeersUnbanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _System_PeersAddStatic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeersAddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).PeersAddStatic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/PeersAddStatic",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).PeersAddStatic(ctx, req.(*PeersAddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _System_PeersRemoveStatic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeersRemoveStaticRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).PeersRemoveStatic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/PeersRemoveStatic",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).PeersRemoveStatic(ctx, req.(*PeersRemoveStaticRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _System_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(emptypb.Empty)
 	if err := stream.RecvMsg(m); err != nil {
@@ -343,6 +534,45 @@ func (x *systemExportServer) Send(m *ExportEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _System_Snapshot_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SystemServer).Snapshot(m, &systemSnapshotServer{stream})
+}
+
+type System_SnapshotServer interface {
+	Send(*SnapshotEvent) error
+	grpc.ServerStream
+}
+
+type systemSnapshotServer struct {
+	grpc.ServerStream
+}
+
+func (x *systemSnapshotServer) Send(m *SnapshotEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _System_DBCompact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServer).DBCompact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.System/DBCompact",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServer).DBCompact(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // System_ServiceDesc is the grpc.ServiceDesc for System service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -366,10 +596,30 @@ var System_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PeersStatus",
 			Handler:    _System_PeersStatus_Handler,
 		},
+		{
+			MethodName: "PeersBan",
+			Handler:    _System_PeersBan_Handler,
+		},
+		{
+			MethodName: "PeersUnban",
+			Handler:    _System_PeersUnban_Handler,
+		},
+		{
+			MethodName: "PeersAddStatic",
+			Handler:    _System_PeersAddStatic_Handler,
+		},
+		{
+			MethodName: "PeersRemoveStatic",
+			Handler:    _System_PeersRemoveStatic_Handler,
+		},
 		{
 			MethodName: "BlockByNumber",
 			Handler:    _System_BlockByNumber_Handler,
 		},
+		{
+			MethodName: "DBCompact",
+			Handler:    _System_DBCompact_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -382,6 +632,11 @@ var System_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _System_Export_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Snapshot",
+			Handler:       _System_Snapshot_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "system.proto",
 }
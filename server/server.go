@@ -4,13 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/0xPolygon/polygon-edge/alerting"
 	"github.com/0xPolygon/polygon-edge/archive"
 	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/engine"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/leveldb"
+	"github.com/0xPolygon/polygon-edge/blocksink"
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/consensus"
+	consensusDev "github.com/0xPolygon/polygon-edge/consensus/dev"
+	consensusIBFT "github.com/0xPolygon/polygon-edge/consensus/ibft"
 	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/helper/common"
 	"github.com/0xPolygon/polygon-edge/helper/keccak"
+	"github.com/0xPolygon/polygon-edge/helper/logging"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
 	"github.com/0xPolygon/polygon-edge/jsonrpc"
 	"github.com/0xPolygon/polygon-edge/network"
@@ -32,15 +40,24 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 )
 
 // Minimal is the central manager of the blockchain client
 type Server struct {
 	logger       hclog.Logger
+	logFile      *logging.RotatingFile
 	config       *Config
 	state        state.State
 	stateStorage itrie.Storage
 
+	// databases are the kv storages of the data dir, by the name of their directory
+	databases map[string]storage.KV
+
+	// pruneSub is the blockchain subscription releasing the state of the old blocks,
+	// nil for the archive nodes
+	pruneSub blockchain.Subscription
+
 	consensus consensus.Consensus
 
 	// blockchain stack
@@ -66,11 +83,23 @@ type Server struct {
 
 	prometheusServer *http.Server
 
+	// healthServer serves the health and the readiness endpoints
+	healthServer *http.Server
+
+	// consensusRunning is set once the consensus is started, until it is closed
+	consensusRunning uint32
+
 	// secrets manager
 	secretsManager secrets.SecretsManager
 
 	// restore
 	restoreProgression *progress.ProgressionWrapper
+
+	// blockSinks pushes the finalized blocks to the configured sinks
+	blockSinks *blocksink.Bus
+
+	// alerts posts the alerts of the reorgs and of the stale head to the configured webhooks
+	alerts *alerting.Monitor
 }
 
 var dirPaths = []string{
@@ -80,21 +109,25 @@ var dirPaths = []string{
 
 // NewServer creates a new Minimal server, using the passed in configuration
 func NewServer(config *Config) (*Server, error) {
-	logger := hclog.New(&hclog.LoggerOptions{
-		Name:  "polygon",
-		Level: config.LogLevel,
-	})
+	logger, logFile, err := newLogger(config)
+	if err != nil {
+		return nil, err
+	}
 
 	m := &Server{
 		logger:             logger,
+		logFile:            logFile,
 		config:             config,
 		chain:              config.Chain,
-		grpcServer:         grpc.NewServer(),
 		restoreProgression: progress.NewProgressionWrapper(progress.ChainSyncRestore),
 	}
 
 	m.logger.Info("Data dir", "path", config.DataDir)
 
+	if m.grpcServer, err = newGRPCServer(config.GRPCSecurity); err != nil {
+		return nil, fmt.Errorf("failed to set up the GRPC server: %w", err)
+	}
+
 	// Generate all the paths in the dataDir
 	if err := common.SetupDataDir(config.DataDir, dirPaths); err != nil {
 		return nil, fmt.Errorf("failed to create data directories: %w", err)
@@ -128,33 +161,13 @@ func NewServer(config *Config) (*Server, error) {
 	}
 
 	// start blockchain object
-	stateStorage, err := itrie.NewLevelDBStorage(filepath.Join(m.config.DataDir, "trie"), logger)
-	if err != nil {
-		return nil, err
-	}
-
-	m.stateStorage = stateStorage
-
-	st := itrie.NewState(stateStorage)
-	m.state = st
-
-	m.executor = state.NewExecutor(config.Chain.Params, st, logger)
-	m.executor.SetRuntime(precompiled.NewPrecompiled())
-	m.executor.SetRuntime(evm.NewEVM())
-
-	// compute the genesis root state
-	genesisRoot := m.executor.WriteGenesis(config.Chain.Genesis.Alloc)
-	config.Chain.Genesis.StateRoot = genesisRoot
-
-	// blockchain object
-	m.blockchain, err = blockchain.NewBlockchain(logger, m.config.DataDir, config.Chain, nil, m.executor)
-	if err != nil {
+	if err := m.setupBlockchain(); err != nil {
 		return nil, err
 	}
 
-	m.executor.GetHash = m.blockchain.GetHashHelper
-
 	{
+		var err error
+
 		hub := &txpoolHub{
 			state:      m.state,
 			Blockchain: m.blockchain,
@@ -168,9 +181,23 @@ func NewServer(config *Config) (*Server, error) {
 			m.network,
 			m.serverMetrics.txpool,
 			&txpool.Config{
-				Sealing:    m.config.Seal,
-				MaxSlots:   m.config.MaxSlots,
-				PriceLimit: m.config.PriceLimit,
+				Sealing:            m.config.Seal,
+				MaxSlots:           m.config.MaxSlots,
+				MaxAccountEnqueued: m.config.MaxAccountEnqueued,
+				MaxAccountPromoted: m.config.MaxAccountPromoted,
+				PriceLimit:         m.config.PriceLimit,
+				PriceBump:          m.config.PriceBump,
+				JournalPath:        m.config.JournalPath,
+				JournalRotate:      m.config.JournalRotate,
+				ReplayProtection:   m.chain.Params.Forks.ReplayProtection,
+				SizeLimitsFork:     m.chain.Params.Forks.SizeLimits,
+				SizeLimits: chain.SizeLimits{
+					MaxBlockSize: m.chain.Params.MaxBlockSize,
+					MaxTxSize:    m.chain.Params.MaxTxSize,
+				},
+
+				AllowLocalUnderpriced:     m.config.AllowLocalUnderpriced,
+				UnderpricedEvictionBlocks: m.config.UnderpricedEvictionBlocks,
 			},
 		)
 		if err != nil {
@@ -217,6 +244,8 @@ func NewServer(config *Config) (*Server, error) {
 		return nil, err
 	}
 
+	atomic.StoreUint32(&m.consensusRunning, 1)
+
 	// setup and start grpc server
 	if err := m.setupGRPC(); err != nil {
 		return nil, err
@@ -228,9 +257,147 @@ func NewServer(config *Config) (*Server, error) {
 
 	m.txpool.Start()
 
+	// push the blocks to the sinks, from the ones following their cursors
+	if m.blockSinks, err = blocksink.NewBus(
+		m.logger,
+		m.blockchain,
+		filepath.Join(m.config.DataDir, "blocksinks"),
+		m.config.BlockSinks,
+		blockSinkBackends,
+	); err != nil {
+		return nil, err
+	}
+
+	m.blockSinks.Start()
+
+	// watch the reorgs and the head of the chain
+	if config.Alerts != nil {
+		if m.alerts, err = alerting.NewMonitor(m.logger, m.blockchain, config.Alerts); err != nil {
+			return nil, err
+		}
+
+		m.alerts.Start()
+	}
+
+	if config.Health.Addr != nil {
+		m.healthServer = m.startHealthServer(config.Health.Addr)
+	}
+
 	return m, nil
 }
 
+// setupBlockchain sets up the state storage, the executor and the blockchain
+func (s *Server) setupBlockchain() error {
+	dbEngine := engine.Engine(s.config.DBEngine)
+
+	kvOptions := &leveldb.Options{
+		WriteBufferSize: s.config.LevelDBWriteBuffer,
+		BlockCacheSize:  s.config.LevelDBBlockCache,
+		OpenFilesLimit:  s.config.LevelDBOpenFiles,
+		Metrics:         s.serverMetrics.leveldb,
+	}
+
+	stateKV, err := engine.OpenKV(dbEngine, filepath.Join(s.config.DataDir, "trie"), kvOptions)
+	if err != nil {
+		return err
+	}
+
+	s.databases = map[string]storage.KV{"trie": stateKV}
+
+	stateStorage := itrie.NewKVStorage(stateKV)
+	if s.config.StateCacheSize != 0 {
+		stateStorage = itrie.NewCachedStorage(stateStorage, int(s.config.StateCacheSize<<20), s.serverMetrics.state)
+	}
+
+	s.stateStorage = stateStorage
+
+	var prunedState *itrie.PrunedState
+
+	if s.config.PruneState {
+		prunedState = itrie.NewPrunedState(stateStorage, s.config.PruneRetention, s.logger)
+		s.state = prunedState
+	} else {
+		s.state = itrie.NewState(stateStorage)
+	}
+
+	s.executor = state.NewExecutor(s.config.Chain.Params, s.state, s.logger)
+	s.executor.IndexInternalTxs = s.config.IndexInternalTxs
+	s.executor.ParallelExecution = s.config.ParallelExecution
+
+	precompiledRuntime := precompiled.NewPrecompiled()
+	if err := precompiledRuntime.RegisterPrecompiles(s.config.Chain.Params.Precompiles); err != nil {
+		return fmt.Errorf("unable to register the precompiled contracts: %w", err)
+	}
+
+	s.executor.SetRuntime(precompiledRuntime)
+	s.executor.SetRuntime(evm.NewEVM())
+
+	// compute the genesis root state
+	genesisRoot := s.executor.WriteGenesis(s.config.Chain.Genesis.Alloc)
+	s.config.Chain.Genesis.StateRoot = genesisRoot
+
+	// blockchain object
+	blockchainPath := filepath.Join(s.config.DataDir, "blockchain")
+
+	blockchainEngine, err := engine.Resolve(dbEngine, blockchainPath)
+	if err != nil {
+		return err
+	}
+
+	blockchainKV, err := engine.OpenKV(blockchainEngine, blockchainPath, kvOptions)
+	if err != nil {
+		return err
+	}
+
+	s.databases["blockchain"] = blockchainKV
+
+	db := storage.NewKeyValueStorage(s.logger.Named(string(blockchainEngine)), blockchainKV)
+
+	s.blockchain, err = blockchain.NewBlockchain(s.logger, db, s.config.Chain, nil, s.executor)
+	if err != nil {
+		return err
+	}
+
+	s.executor.GetHash = s.blockchain.GetHashHelper
+
+	if s.config.BadBlockDir != "" {
+		if err := s.blockchain.SetBadBlockDumpDir(s.config.BadBlockDir); err != nil {
+			return fmt.Errorf("failed to set up the bad block directory: %w", err)
+		}
+	}
+
+	if prunedState != nil {
+		s.pruneSub = s.blockchain.SubscribeEventStream()
+		go s.pruneState(prunedState, s.pruneSub)
+	}
+
+	return nil
+}
+
+// pruneState releases the state of the blocks out of the retention window as the head moves
+func (s *Server) pruneState(pruned *itrie.PrunedState, sub blockchain.Subscription) {
+	prune := func(head uint64) {
+		if err := pruned.Prune(head); err != nil {
+			s.logger.Error("failed to prune the state", "head", head, "err", err)
+		}
+	}
+
+	prune(s.blockchain.Header().Number)
+
+	for {
+		evnt := sub.GetEvent()
+		if evnt == nil {
+			return
+		}
+
+		if evnt.Type == blockchain.EventFork || len(evnt.NewChain) == 0 {
+			continue
+		}
+
+		prune(evnt.NewChain[0].Number)
+	}
+}
+
 func (s *Server) restoreChain() error {
 	if s.config.RestoreFile == nil {
 		return nil
@@ -303,11 +470,15 @@ func (s *Server) setupSecretsManager() error {
 	}
 
 	if secretsManagerType == secrets.Local {
-		// Only the base directory is required for
-		// the local secrets manager
+		// The local secrets manager requires the base directory,
+		// and the passphrase if the secrets are encrypted
 		secretsManagerParams.Extra = map[string]interface{}{
 			secrets.Path: s.config.DataDir,
 		}
+
+		if s.config.SecretsPassphrase != "" {
+			secretsManagerParams.Extra[secrets.Passphrase] = s.config.SecretsPassphrase
+		}
 	}
 
 	// Grab the factory method
@@ -363,8 +534,20 @@ func (s *Server) setupConsensus() error {
 			Grpc:           s.grpcServer,
 			Logger:         s.logger.Named("consensus"),
 			Metrics:        s.serverMetrics.consensus,
+			SyncerMetrics:  s.serverMetrics.syncer,
 			SecretsManager: s.secretsManager,
 			BlockTime:      s.config.BlockTime,
+
+			SnapshotRetention: s.config.IBFTSnapshotRetention,
+			MessageRateLimit:  s.config.IBFTMsgRateLimit,
+			RemoteSigner:      s.config.IBFTRemoteSigner,
+			WALDir:            s.config.IBFTWALDir,
+			BlockVanity:       s.config.BlockVanity,
+			FastSync:          s.config.FastSync,
+			NonValidator:      s.config.NonValidator,
+			StateStorage:      s.stateStorage,
+
+			AllowedFutureDrift: s.config.AllowedFutureDrift,
 		},
 	)
 
@@ -379,6 +562,7 @@ func (s *Server) setupConsensus() error {
 
 type jsonRPCHub struct {
 	state              state.State
+	stateStorage       itrie.Storage
 	restoreProgression *progress.ProgressionWrapper
 
 	*blockchain.Blockchain
@@ -457,11 +641,34 @@ func (j *jsonRPCHub) GetCode(hash types.Hash) ([]byte, error) {
 	return res, nil
 }
 
+// GetProof returns the merkle proof of the key in the state trie of the root, and the value of the key
+func (j *jsonRPCHub) GetProof(root types.Hash, key []byte) ([][]byte, []byte, error) {
+	// the state of the root is not available anymore once pruned
+	if _, err := j.state.NewSnapshotAt(root); err != nil {
+		return nil, nil, err
+	}
+
+	// the values in the trie are the hashed objects of the keys
+	return itrie.Prove(root, keccak.Keccak256(nil, key), j.stateStorage)
+}
+
+// getBlockCreator returns the creator of the block. The pending block past the head is not sealed yet,
+// the creator of the head stands in for its creator
+func (j *jsonRPCHub) getBlockCreator(header *types.Header) (types.Address, error) {
+	if head := j.Blockchain.Header(); header.Number > head.Number {
+		header = head
+	}
+
+	return j.GetConsensus().GetBlockCreator(header)
+}
+
 func (j *jsonRPCHub) ApplyTxn(
+	ctx context.Context,
 	header *types.Header,
 	txn *types.Transaction,
+	override state.OverrideSet,
 ) (result *runtime.ExecutionResult, err error) {
-	blockCreator, err := j.GetConsensus().GetBlockCreator(header)
+	blockCreator, err := j.getBlockCreator(header)
 	if err != nil {
 		return nil, err
 	}
@@ -472,11 +679,71 @@ func (j *jsonRPCHub) ApplyTxn(
 		return
 	}
 
+	if err = transition.ApplyOverride(override); err != nil {
+		return
+	}
+
+	transition.SetAbort(ctx.Done())
+
 	result, err = transition.Apply(txn)
 
 	return
 }
 
+func (j *jsonRPCHub) TraceTxn(
+	ctx context.Context,
+	header *types.Header,
+	txn *types.Transaction,
+	tracer runtime.Tracer,
+) (*runtime.ExecutionResult, error) {
+	blockCreator, err := j.getBlockCreator(header)
+	if err != nil {
+		return nil, err
+	}
+
+	transition, err := j.BeginTxn(header.StateRoot, header, blockCreator)
+	if err != nil {
+		return nil, err
+	}
+
+	transition.SetTracer(tracer)
+	transition.SetAbort(ctx.Done())
+
+	return transition.Apply(txn)
+}
+
+func (j *jsonRPCHub) TraceBlock(ctx context.Context, block *types.Block, tracers []runtime.Tracer) error {
+	parent, ok := j.GetParent(block.Header)
+	if !ok {
+		return fmt.Errorf("parent of block %d not found", block.Number())
+	}
+
+	blockCreator, err := j.GetConsensus().GetBlockCreator(block.Header)
+	if err != nil {
+		return err
+	}
+
+	return j.Executor.TraceBlock(parent.StateRoot, block, blockCreator, tracers, ctx.Done())
+}
+
+func (j *jsonRPCHub) GetBadBlocks() []*blockchain.BadBlock {
+	return j.BadBlocks()
+}
+
+func (j *jsonRPCHub) GetStateDiff(block *types.Block) ([]map[types.Address]*state.AccountDiff, error) {
+	parent, ok := j.GetParent(block.Header)
+	if !ok {
+		return nil, fmt.Errorf("parent of block %d not found", block.Number())
+	}
+
+	blockCreator, err := j.GetConsensus().GetBlockCreator(block.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	return j.Executor.StateDiffBlock(parent.StateRoot, block, blockCreator)
+}
+
 func (j *jsonRPCHub) GetSyncProgression() *progress.Progression {
 	// restore progression
 	if restoreProg := j.restoreProgression.GetProgression(); restoreProg != nil {
@@ -491,12 +758,161 @@ func (j *jsonRPCHub) GetSyncProgression() *progress.Progression {
 	return nil
 }
 
+var errIBFTNotEnabled = errors.New("the IBFT consensus is not enabled")
+
+// getIBFT returns the IBFT consensus, if it is the consensus of the node
+func (j *jsonRPCHub) getIBFT() (*consensusIBFT.Ibft, error) {
+	ibft, ok := j.Consensus.(*consensusIBFT.Ibft)
+	if !ok {
+		return nil, errIBFTNotEnabled
+	}
+
+	return ibft, nil
+}
+
+func (j *jsonRPCHub) GetIBFTStatus() (*jsonrpc.IBFTStatus, error) {
+	ibft, err := j.getIBFT()
+	if err != nil {
+		return nil, err
+	}
+
+	status := ibft.GetStatus()
+
+	return &jsonrpc.IBFTStatus{
+		State:              status.State.String(),
+		Sequence:           status.Sequence,
+		Round:              status.Round,
+		Proposer:           status.Proposer,
+		IsProposer:         status.IsProposer,
+		LockedProposal:     status.LockedProposal,
+		LastCommittedRound: status.LastCommittedRound,
+	}, nil
+}
+
+func (j *jsonRPCHub) GetIBFTValidators(number uint64) ([]types.Address, error) {
+	ibft, err := j.getIBFT()
+	if err != nil {
+		return nil, err
+	}
+
+	return ibft.GetValidatorsByBlockNumber(number)
+}
+
+func (j *jsonRPCHub) GetIBFTValidatorsAt(number uint64) ([]types.Address, error) {
+	ibft, err := j.getIBFT()
+	if err != nil {
+		return nil, err
+	}
+
+	return ibft.GetValidatorsAt(number)
+}
+
+func (j *jsonRPCHub) GetIBFTSnapshot(number uint64) (*jsonrpc.IBFTSnapshot, error) {
+	ibft, err := j.getIBFT()
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err := ibft.GetSnapshotByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &jsonrpc.IBFTSnapshot{
+		Number:     snap.Number,
+		Hash:       types.StringToHash(snap.Hash),
+		Validators: snap.Set,
+		Votes:      make([]jsonrpc.IBFTVote, len(snap.Votes)),
+	}
+
+	for indx, vote := range snap.Votes {
+		resp.Votes[indx] = jsonrpc.IBFTVote{
+			Validator: vote.Validator,
+			Address:   vote.Address,
+			Authorize: vote.Authorize,
+		}
+	}
+
+	return resp, nil
+}
+
+func (j *jsonRPCHub) GetIBFTVanity(number uint64) ([]byte, error) {
+	if _, err := j.getIBFT(); err != nil {
+		return nil, err
+	}
+
+	header, ok := j.Blockchain.GetHeaderByNumber(number)
+	if !ok {
+		return nil, fmt.Errorf("header at %d not found", number)
+	}
+
+	return consensusIBFT.GetVanity(header), nil
+}
+
+var errDevNotEnabled = errors.New("the dev consensus is not enabled")
+
+// getDev returns the dev consensus, if it is the consensus of the node
+func (j *jsonRPCHub) getDev() (*consensusDev.Dev, error) {
+	dev, ok := j.Consensus.(*consensusDev.Dev)
+	if !ok {
+		return nil, errDevNotEnabled
+	}
+
+	return dev, nil
+}
+
+func (j *jsonRPCHub) DevMine(timestamp uint64) error {
+	dev, err := j.getDev()
+	if err != nil {
+		return err
+	}
+
+	return dev.Mine(timestamp)
+}
+
+func (j *jsonRPCHub) DevSetNextBlockTimestamp(timestamp uint64) error {
+	dev, err := j.getDev()
+	if err != nil {
+		return err
+	}
+
+	return dev.SetNextBlockTimestamp(timestamp)
+}
+
+func (j *jsonRPCHub) DevIncreaseTime(seconds uint64) (uint64, error) {
+	dev, err := j.getDev()
+	if err != nil {
+		return 0, err
+	}
+
+	return dev.IncreaseTime(seconds), nil
+}
+
+func (j *jsonRPCHub) DevSnapshot() (uint64, error) {
+	dev, err := j.getDev()
+	if err != nil {
+		return 0, err
+	}
+
+	return dev.Snapshot(), nil
+}
+
+func (j *jsonRPCHub) DevRevert(id uint64) (bool, error) {
+	dev, err := j.getDev()
+	if err != nil {
+		return false, err
+	}
+
+	return dev.Revert(id)
+}
+
 // SETUP //
 
 // setupJSONRCP sets up the JSONRPC server, using the set configuration
 func (s *Server) setupJSONRPC() error {
 	hub := &jsonRPCHub{
 		state:              s.state,
+		stateStorage:       s.stateStorage,
 		restoreProgression: s.restoreProgression,
 		Blockchain:         s.blockchain,
 		TxPool:             s.txpool,
@@ -505,11 +921,33 @@ func (s *Server) setupJSONRPC() error {
 		Server:             s.network,
 	}
 
+	// the evm endpoint is served by the dev consensus only
+	_, isDev := s.consensus.(*consensusDev.Dev)
+
 	conf := &jsonrpc.Config{
 		Store:                    hub,
 		Addr:                     s.config.JSONRPC.JSONRPCAddr,
 		ChainID:                  uint64(s.config.Chain.Params.ChainID),
 		AccessControlAllowOrigin: s.config.JSONRPC.AccessControlAllowOrigin,
+		FeeHistoryLimit:          s.config.JSONRPC.FeeHistoryLimit,
+		BlockRangeLimit:          s.config.JSONRPC.BlockRangeLimit,
+		LogsLimit:                s.config.JSONRPC.LogsLimit,
+		SubscriptionBufferSize:   s.config.JSONRPC.SubscriptionBufferSize,
+		AllowedMethods:           s.config.JSONRPC.AllowedMethods,
+		DeniedMethods:            s.config.JSONRPC.DeniedMethods,
+		RateLimit:                s.config.JSONRPC.RateLimit,
+		MethodRateLimits:         s.config.JSONRPC.MethodRateLimits,
+		BatchLimit:               s.config.JSONRPC.BatchLimit,
+		BatchWorkers:             s.config.JSONRPC.BatchWorkers,
+		FilterTimeout:            s.config.JSONRPC.FilterTimeout,
+		SyncDistance:             s.config.JSONRPC.SyncDistance,
+		DevMode:                  isDev,
+		Metrics:                  s.serverMetrics.jsonrpc,
+		AccessControl:            s.config.JSONRPC.AccessControl,
+		GraphQLAddr:              s.config.JSONRPC.GraphQLAddr,
+		ExecutionTimeout:         s.config.JSONRPC.ExecutionTimeout,
+		MethodExecutionTimeouts:  s.config.JSONRPC.MethodExecutionTimeouts,
+		ExecutionLimit:           s.config.JSONRPC.ExecutionLimit,
 	}
 
 	srv, err := jsonrpc.NewJSONRPC(s.logger, conf)
@@ -537,7 +975,17 @@ func (s *Server) setupGRPC() error {
 		}
 	}()
 
-	s.logger.Info("GRPC server running", "addr", s.config.GRPCAddr.String())
+	security := s.config.GRPCSecurity
+	if security.isAuthenticated() && !security.isEncrypted() {
+		s.logger.Warn("The GRPC operator tokens are sent in plaintext, set the TLS certificate of the GRPC server")
+	}
+
+	s.logger.Info(
+		"GRPC server running",
+		"addr", s.config.GRPCAddr.String(),
+		"tls", security.isEncrypted(),
+		"auth", security.isAuthenticated(),
+	)
 
 	return nil
 }
@@ -554,9 +1002,34 @@ func (s *Server) JoinPeer(rawPeerMultiaddr string) error {
 
 // Close closes the Minimal server (blockchain, networking, consensus)
 func (s *Server) Close() {
-	// Close the blockchain layer
-	if err := s.blockchain.Close(); err != nil {
-		s.logger.Error("failed to close blockchain", "err", err.Error())
+	// Stop accepting the new transactions and requests
+	s.txpool.StopAccepting()
+
+	if s.jsonrpcServer != nil {
+		if err := s.jsonrpcServer.Close(); err != nil {
+			s.logger.Error("failed to close the JSON-RPC server", "err", err.Error())
+		}
+	}
+
+	s.grpcServer.Stop()
+
+	// Close the consensus layer, once the block in progress is committed or aborted
+	atomic.StoreUint32(&s.consensusRunning, 0)
+
+	if err := s.consensus.Close(); err != nil {
+		s.logger.Error("failed to close consensus", "err", err.Error())
+	}
+
+	// close the txpool's main loop and its journal
+	s.txpool.Close()
+
+	// Stop the delivery to the block sinks before the blockchain is closed
+	if s.blockSinks != nil {
+		s.blockSinks.Close()
+	}
+
+	if s.alerts != nil {
+		s.alerts.Close()
 	}
 
 	// Close the networking layer
@@ -564,9 +1037,14 @@ func (s *Server) Close() {
 		s.logger.Error("failed to close networking", "err", err.Error())
 	}
 
-	// Close the consensus layer
-	if err := s.consensus.Close(); err != nil {
-		s.logger.Error("failed to close consensus", "err", err.Error())
+	// Close the blockchain layer, once the block write in progress is completed
+	if err := s.blockchain.Close(); err != nil {
+		s.logger.Error("failed to close blockchain", "err", err.Error())
+	}
+
+	// Stop the pruning before the state storage is closed
+	if s.pruneSub != nil {
+		s.pruneSub.Close()
 	}
 
 	// Close the state storage
@@ -580,8 +1058,51 @@ func (s *Server) Close() {
 		}
 	}
 
-	// close the txpool's main loop
-	s.txpool.Close()
+	if s.healthServer != nil {
+		if err := s.healthServer.Shutdown(context.Background()); err != nil {
+			s.logger.Error("Health server shutdown error", "err", err)
+		}
+	}
+
+	s.closeLogFile()
+}
+
+// newLogger creates the logger of the server, the sub-loggers of the modules
+// log at the level of their module. The logs are written to the log file if set
+func newLogger(config *Config) (hclog.Logger, *logging.RotatingFile, error) {
+	opts := &hclog.LoggerOptions{
+		Name:       "polygon",
+		JSONFormat: config.JSONLogFormat,
+	}
+
+	var logFile *logging.RotatingFile
+
+	if config.LogFile != nil {
+		var err error
+
+		logFile, err = logging.NewRotatingFile(config.LogFile.Path, config.LogFile.MaxSize, config.LogFile.MaxBackups)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		opts.Output = logFile
+	}
+
+	return logging.NewLogger(opts, &logging.Levels{
+		Default: config.LogLevel,
+		Modules: config.LogModuleLevels,
+	}), logFile, nil
+}
+
+// closeLogFile closes the log file, if any
+func (s *Server) closeLogFile() {
+	if s.logFile == nil {
+		return
+	}
+
+	if err := s.logFile.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to close the log file: %v\n", err)
+	}
 }
 
 // Entry is a backend configuration entry
@@ -1,8 +1,12 @@
 package server
 
 import (
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/leveldb"
 	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/jsonrpc"
 	"github.com/0xPolygon/polygon-edge/network"
+	"github.com/0xPolygon/polygon-edge/protocol"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
 	"github.com/0xPolygon/polygon-edge/txpool"
 )
 
@@ -11,6 +15,10 @@ type serverMetrics struct {
 	consensus *consensus.Metrics
 	network   *network.Metrics
 	txpool    *txpool.Metrics
+	syncer    *protocol.Metrics
+	jsonrpc   *jsonrpc.Metrics
+	state     *itrie.Metrics
+	leveldb   *leveldb.Metrics
 }
 
 // metricProvider serverMetric instance for the given ChainID and nameSpace
@@ -20,6 +28,10 @@ func metricProvider(nameSpace string, chainID string, metricsRequired bool) *ser
 			consensus: consensus.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
 			network:   network.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
 			txpool:    txpool.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
+			syncer:    protocol.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
+			jsonrpc:   jsonrpc.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
+			state:     itrie.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
+			leveldb:   leveldb.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
 		}
 	}
 
@@ -27,5 +39,9 @@ func metricProvider(nameSpace string, chainID string, metricsRequired bool) *ser
 		consensus: consensus.NilMetrics(),
 		network:   network.NilMetrics(),
 		txpool:    txpool.NilMetrics(),
+		syncer:    protocol.NilMetrics(),
+		jsonrpc:   jsonrpc.NilMetrics(),
+		state:     itrie.NilMetrics(),
+		leveldb:   leveldb.NilMetrics(),
 	}
 }
@@ -5,18 +5,29 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/0xPolygon/polygon-edge/archive"
 	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
 	"github.com/0xPolygon/polygon-edge/network/common"
 	"github.com/0xPolygon/polygon-edge/server/proto"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/libp2p/go-libp2p-core/peer"
 	empty "google.golang.org/protobuf/types/known/emptypb"
+	"sort"
+	"sync/atomic"
+	"time"
 )
 
+var errCompactionInProgress = errors.New("a compaction of the databases is already in progress")
+
 type systemService struct {
 	proto.UnimplementedSystemServer
 
 	server *Server
+
+	// compacting is set while the databases are compacted
+	compacting uint32
 }
 
 // GetStatus returns the current system status, in the form of:
@@ -38,12 +49,18 @@ func (s *systemService) GetStatus(ctx context.Context, req *empty.Empty) (*proto
 		P2PAddr: common.AddrInfoToString(s.server.network.AddrInfo()),
 	}
 
+	for _, addr := range s.server.network.AdvertisedAddrs() {
+		status.AdvertisedAddrs = append(status.AdvertisedAddrs, addr.String())
+	}
+
+	status.Health = s.server.healthReport().toProto()
+
 	return status, nil
 }
 
 // Subscribe implements the blockchain event subscription service
 func (s *systemService) Subscribe(req *empty.Empty, stream proto.System_SubscribeServer) error {
-	sub := s.server.blockchain.SubscribeEvents()
+	sub := s.server.blockchain.SubscribeEventStream()
 
 	for {
 		evnt := sub.GetEvent()
@@ -124,15 +141,78 @@ func (s *systemService) getPeer(id peer.ID) (*proto.Peer, error) {
 		addrs = append(addrs, addr.String())
 	}
 
+	score, bannedUntil := s.server.network.PeerScore(id)
+
 	peer := &proto.Peer{
 		Id:        id.String(),
 		Protocols: protocols,
 		Addrs:     addrs,
+		Score:     score,
+		Static:    s.server.network.IsStaticPeer(id),
+		Trusted:   s.server.network.IsTrustedPeer(id),
+	}
+
+	if !bannedUntil.IsZero() {
+		peer.BannedUntil = bannedUntil.Unix()
 	}
 
 	return peer, nil
 }
 
+// PeersBan implements the 'peers ban' operator service
+func (s *systemService) PeersBan(_ context.Context, req *proto.PeersBanRequest) (*proto.PeersBanResponse, error) {
+	peerID, err := peer.Decode(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	bannedUntil := s.server.network.BanPeer(peerID, time.Duration(req.Duration)*time.Second)
+
+	return &proto.PeersBanResponse{
+		BannedUntil: bannedUntil.Unix(),
+	}, nil
+}
+
+// PeersUnban implements the 'peers unban' operator service
+func (s *systemService) PeersUnban(_ context.Context, req *proto.PeersUnbanRequest) (*proto.PeersUnbanResponse, error) {
+	peerID, err := peer.Decode(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.PeersUnbanResponse{
+		Banned: s.server.network.UnbanPeer(peerID),
+	}, nil
+}
+
+// PeersAddStatic implements the 'peers add --static' operator service
+func (s *systemService) PeersAddStatic(_ context.Context, req *proto.PeersAddRequest) (*proto.PeersAddResponse, error) {
+	if err := s.server.network.AddStaticPeer(req.Id); err != nil {
+		return &proto.PeersAddResponse{
+			Message: "Unable to add static peer",
+		}, err
+	}
+
+	return &proto.PeersAddResponse{
+		Message: "Static peer added, the node keeps connected with it",
+	}, nil
+}
+
+// PeersRemoveStatic implements the 'peers remove-static' operator service
+func (s *systemService) PeersRemoveStatic(
+	_ context.Context,
+	req *proto.PeersRemoveStaticRequest,
+) (*proto.PeersRemoveStaticResponse, error) {
+	peerID, err := peer.Decode(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.PeersRemoveStaticResponse{
+		Removed: s.server.network.RemoveStaticPeer(peerID),
+	}, nil
+}
+
 // PeersList implements the 'peers list' operator service
 func (s *systemService) PeersList(
 	ctx context.Context,
@@ -301,3 +381,168 @@ func (w *blockStreamWriter) reset() {
 	w.pendingFrom = nil
 	w.pendingTo = nil
 }
+
+// Snapshot streams the blocks of the chain and the state of the head block, along with a manifest.
+// The writes of the blocks are paused until the snapshot is sent, so the blocks and the state
+// are those of the same head
+func (s *systemService) Snapshot(_ *empty.Empty, stream proto.System_SnapshotServer) error {
+	resume := s.server.blockchain.PauseWrites()
+	defer resume()
+
+	head := s.server.blockchain.Header()
+	writer := &snapshotStreamWriter{
+		stream:     stream,
+		maxPayload: defaultMaxGRPCPayloadSize,
+		height:     head.Number,
+	}
+
+	manifest := &archive.SnapshotManifest{
+		ChainID:     uint64(s.server.chain.Params.ChainID),
+		GenesisHash: s.server.blockchain.Genesis(),
+		Height:      head.Number,
+		HeadHash:    head.Hash,
+		StateRoot:   head.StateRoot,
+	}
+
+	if err := writer.write(manifest.MarshalRLP()); err != nil {
+		return err
+	}
+
+	// the genesis block is built from the genesis file on restore
+	for i := uint64(1); i <= head.Number; i++ {
+		block, ok := s.server.blockchain.GetBlockByNumber(i, true)
+		if !ok {
+			return fmt.Errorf("block %d not found", i)
+		}
+
+		receipts, err := s.server.blockchain.GetReceiptsByHash(block.Hash())
+		if err != nil {
+			return fmt.Errorf("failed to read the receipts of block %d: %w", i, err)
+		}
+
+		record := &archive.SnapshotRecord{
+			Type:     archive.SnapshotBlock,
+			Block:    block,
+			Receipts: receipts,
+		}
+
+		if err := writer.write(record.MarshalRLP()); err != nil {
+			return err
+		}
+
+		writer.blocks++
+	}
+
+	err := itrie.WalkState(head.StateRoot, s.server.stateStorage, func(item itrie.SyncItem, data []byte) error {
+		record := &archive.SnapshotRecord{
+			Type: archive.SnapshotStateNode,
+			Data: data,
+		}
+
+		if item.Code {
+			record.Type = archive.SnapshotCode
+		}
+
+		if err := writer.write(record.MarshalRLP()); err != nil {
+			return err
+		}
+
+		writer.stateItems++
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return writer.flush()
+}
+
+// DBCompact compacts the databases of the data dir one after the other, the deleted and the overwritten
+// entries are discarded. The databases remain available, but the compaction competes with the node
+// for the disk, so it is meant for a maintenance window
+func (s *systemService) DBCompact(_ context.Context, _ *empty.Empty) (*proto.DBCompactResponse, error) {
+	if !atomic.CompareAndSwapUint32(&s.compacting, 0, 1) {
+		return nil, errCompactionInProgress
+	}
+	defer atomic.StoreUint32(&s.compacting, 0)
+
+	names := make([]string, 0, len(s.server.databases))
+	for name := range s.server.databases {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	resp := &proto.DBCompactResponse{}
+
+	for _, name := range names {
+		compacter, ok := s.server.databases[name].(storage.KVCompacter)
+		if !ok {
+			return nil, fmt.Errorf("the %s database doesn't support the manual compaction", name)
+		}
+
+		s.server.logger.Info("compacting the database", "name", name)
+
+		start := time.Now()
+		if err := compacter.Compact(); err != nil {
+			return nil, fmt.Errorf("failed to compact the %s database: %w", name, err)
+		}
+
+		duration := time.Since(start)
+
+		s.server.logger.Info("compacted the database", "name", name, "duration", duration)
+
+		resp.Databases = append(resp.Databases, &proto.DBCompactResponse_Database{
+			Name:     name,
+			Duration: duration.Milliseconds(),
+		})
+	}
+
+	return resp, nil
+}
+
+// snapshotStreamWriter sends the items of a snapshot in events of up to maxPayload bytes
+type snapshotStreamWriter struct {
+	buf        bytes.Buffer
+	stream     proto.System_SnapshotServer
+	maxPayload uint64
+
+	height     uint64
+	blocks     uint64
+	stateItems uint64
+}
+
+func (w *snapshotStreamWriter) write(data []byte) error {
+	if uint64(w.buf.Len()+len(data)) >= w.maxPayload {
+		// send buffered data to client first
+		if err := w.flush(); err != nil {
+			return err
+		}
+	}
+
+	w.buf.Write(data)
+
+	return nil
+}
+
+func (w *snapshotStreamWriter) flush() error {
+	// nothing happens in case of empty buffer
+	if w.buf.Len() == 0 {
+		return nil
+	}
+
+	err := w.stream.Send(&proto.SnapshotEvent{
+		Height:     w.height,
+		Blocks:     w.blocks,
+		StateItems: w.stateItems,
+		Data:       w.buf.Bytes(),
+	})
+	if err != nil {
+		return err
+	}
+
+	w.buf.Reset()
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package state
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// AccountState is the state of an account before or after a transaction
+type AccountState struct {
+	Balance *big.Int
+	Nonce   uint64
+	Code    []byte
+}
+
+// StorageDiff is the change of a storage slot
+type StorageDiff struct {
+	Before types.Hash
+	After  types.Hash
+}
+
+// AccountDiff is the change of an account made by a transaction. The states are nil
+// if the account doesn't exist before or after the transaction
+type AccountDiff struct {
+	Before *AccountState
+	After  *AccountState
+
+	// Storage are the slots written by the transaction with a new value
+	Storage map[types.Hash]*StorageDiff
+}
+
+// CodeChanged checks if the code of the account is changed by the transaction
+func (d *AccountDiff) CodeChanged() bool {
+	return !bytes.Equal(d.Before.code(), d.After.code())
+}
+
+func (s *AccountState) code() []byte {
+	if s == nil {
+		return nil
+	}
+
+	return s.Code
+}
+
+// StateDiffBlock re-executes the transactions of the block on the parent state,
+// and returns the accounts changed by each of them
+func (e *Executor) StateDiffBlock(
+	parentRoot types.Hash,
+	block *types.Block,
+	blockCreator types.Address,
+) ([]map[types.Address]*AccountDiff, error) {
+	txn, err := e.BeginTxn(parentRoot, block.Header, blockCreator)
+	if err != nil {
+		return nil, err
+	}
+
+	txn.block = block
+	txn.stateDiffs = []map[types.Address]*AccountDiff{}
+
+	for indx, t := range block.Transactions {
+		if err := txn.writeBlockTransaction(t); err != nil {
+			return nil, err
+		}
+
+		// the transactions exceeding the block gas limit change nothing
+		if len(txn.stateDiffs) == indx {
+			txn.stateDiffs = append(txn.stateDiffs, map[types.Address]*AccountDiff{})
+		}
+	}
+
+	return txn.stateDiffs, nil
+}
+
+// diffState returns the accounts changed between the states of the txns, before and after
+// a transaction. The accounts touched by the transaction are the ones inserted in the radix
+// tree since, the empty accounts are removed if deleteEmptyObjects is set
+func diffState(pre, post *Txn, deleteEmptyObjects bool) map[types.Address]*AccountDiff {
+	diffs := map[types.Address]*AccountDiff{}
+
+	post.txn.Root().Walk(func(k []byte, v interface{}) bool {
+		object, ok := v.(*StateObject)
+		if !ok {
+			return false
+		}
+
+		if prev, ok := pre.txn.Get(k); ok && prev == v {
+			return false
+		}
+
+		addr := types.BytesToAddress(k)
+		diff := &AccountDiff{
+			Before:  readAccountState(pre, addr),
+			Storage: map[types.Hash]*StorageDiff{},
+		}
+
+		removed := object.Deleted || object.Suicide || (deleteEmptyObjects && object.Empty())
+		if !removed {
+			diff.After = readAccountState(post, addr)
+		}
+
+		if object.Txn != nil {
+			object.Txn.Root().Walk(func(key []byte, _ interface{}) bool {
+				slot := types.BytesToHash(key)
+
+				before := pre.GetState(addr, slot)
+
+				var after types.Hash
+				if !removed {
+					after = post.GetState(addr, slot)
+				}
+
+				if before != after {
+					diff.Storage[slot] = &StorageDiff{before, after}
+				}
+
+				return false
+			})
+		}
+
+		if diff.changed() {
+			diffs[addr] = diff
+		}
+
+		return false
+	})
+
+	return diffs
+}
+
+// readAccountState reads the account of the txn, nil if it doesn't exist
+func readAccountState(txn *Txn, addr types.Address) *AccountState {
+	object, ok := txn.getStateObject(addr)
+	if !ok {
+		return nil
+	}
+
+	state := &AccountState{
+		Balance: new(big.Int).Set(object.Account.Balance),
+		Nonce:   object.Account.Nonce,
+	}
+
+	// the accounts without code are not looked up in the code storage
+	codeHash := types.BytesToHash(object.Account.CodeHash)
+	if object.DirtyCode || (codeHash != types.ZeroHash && codeHash != types.BytesToHash(emptyCodeHash)) {
+		state.Code = txn.GetCode(addr)
+	}
+
+	return state
+}
+
+// changed checks if the account or its storage is changed
+func (d *AccountDiff) changed() bool {
+	if len(d.Storage) != 0 {
+		return true
+	}
+
+	if d.Before == nil || d.After == nil {
+		return d.Before != d.After
+	}
+
+	return d.Before.Balance.Cmp(d.After.Balance) != 0 ||
+		d.Before.Nonce != d.After.Nonce ||
+		d.CodeChanged()
+}
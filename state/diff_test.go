@@ -0,0 +1,89 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransition_StateDiffs(t *testing.T) {
+	t.Parallel()
+
+	contract := types.StringToAddress("c")
+	receiver := types.StringToAddress("1234")
+	slot := types.BytesToHash([]byte{0x1})
+
+	transition := newTestTransition(map[types.Address]*PreState{
+		addr1: {
+			Balance: 1000,
+		},
+		contract: {
+			Nonce: 1,
+		},
+	})
+	transition.r = &Executor{
+		config:   &chain.Params{},
+		runtimes: []runtime.Runtime{precompiled.NewPrecompiled(), evm.NewEVM()},
+	}
+	transition.config = chain.AllForksEnabled.At(0)
+	transition.gasPool = 10000000
+	transition.stateDiffs = []map[types.Address]*AccountDiff{}
+
+	// SSTORE(1, 0x2a)
+	transition.state.SetCode(contract, []byte{
+		0x60, 0x2a,
+		0x60, 0x01,
+		0x55,
+		0x00,
+	})
+	transition.state.SetState(contract, slot, types.BytesToHash([]byte{0x5}))
+
+	// a transfer to a new account, and a call to the contract writing the slot
+	assert.NoError(t, transition.Write(&types.Transaction{
+		From:     addr1,
+		To:       &receiver,
+		Gas:      21000,
+		GasPrice: big.NewInt(0),
+		Value:    big.NewInt(10),
+	}))
+	assert.NoError(t, transition.Write(&types.Transaction{
+		Nonce:    1,
+		From:     addr1,
+		To:       &contract,
+		Gas:      100000,
+		GasPrice: big.NewInt(0),
+		Value:    big.NewInt(0),
+	}))
+
+	assert.Len(t, transition.stateDiffs, 2)
+
+	// the touched empty coinbase is not part of the diffs
+	transfer := transition.stateDiffs[0]
+	assert.Len(t, transfer, 2)
+
+	assert.Equal(t, big.NewInt(1000), transfer[addr1].Before.Balance)
+	assert.Equal(t, big.NewInt(990), transfer[addr1].After.Balance)
+	assert.Equal(t, uint64(0), transfer[addr1].Before.Nonce)
+	assert.Equal(t, uint64(1), transfer[addr1].After.Nonce)
+	assert.False(t, transfer[addr1].CodeChanged())
+
+	assert.Nil(t, transfer[receiver].Before)
+	assert.Equal(t, big.NewInt(10), transfer[receiver].After.Balance)
+
+	call := transition.stateDiffs[1]
+	assert.Len(t, call, 2)
+
+	assert.Equal(t, uint64(2), call[addr1].After.Nonce)
+	assert.Equal(t, 0, call[addr1].Before.Balance.Cmp(call[addr1].After.Balance))
+
+	assert.Equal(t, map[types.Hash]*StorageDiff{
+		slot: {types.BytesToHash([]byte{0x5}), types.BytesToHash([]byte{0x2a})},
+	}, call[contract].Storage)
+	assert.False(t, call[contract].CodeChanged())
+}
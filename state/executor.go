@@ -11,14 +11,16 @@ import (
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/state/runtime/tracer"
 	"github.com/0xPolygon/polygon-edge/types"
 )
 
 const (
-	spuriousDragonMaxCodeSize = 24576
-
-	TxGas                 uint64 = 21000 // Per transaction not creating a contract
-	TxGasContractCreation uint64 = 53000 // Per transaction that creates a contract
+	TxGas                     uint64 = 21000 // Per transaction not creating a contract
+	TxGasContractCreation     uint64 = 53000 // Per transaction that creates a contract
+	TxAccessListAddressGas    uint64 = 2400  // Per address in the access list of the transaction
+	TxAccessListStorageKeyGas uint64 = 1900  // Per storage key in the access list of the transaction
 )
 
 var emptyCodeHashTwo = types.BytesToHash(crypto.Keccak256(nil))
@@ -37,6 +39,14 @@ type Executor struct {
 	GetHash  GetHashByNumberHelper
 
 	PostHook func(txn *Transition)
+
+	// IndexInternalTxs records the internal transactions of the processed blocks,
+	// at the cost of tracing the call frames of their transactions
+	IndexInternalTxs bool
+
+	// ParallelExecution executes the transactions of the processed blocks optimistically in parallel,
+	// the ones conflicting with the transactions before them are executed again serially
+	ParallelExecution bool
 }
 
 // NewExecutor creates a new executor
@@ -71,11 +81,34 @@ func (e *Executor) WriteGenesis(alloc map[types.Address]*chain.GenesisAccount) t
 		}
 	}
 
-	_, root := txn.Commit(false)
+	_, root := e.commit(txn, 0, false)
 
 	return types.BytesToHash(root)
 }
 
+// commit commits the state of the block, its root is referenced when the state is pruned
+func (e *Executor) commit(txn *Txn, number uint64, deleteEmptyObjects bool) (Snapshot, []byte) {
+	pruned, ok := e.state.(PrunedState)
+	if !ok {
+		return txn.Commit(deleteEmptyObjects)
+	}
+
+	var (
+		snap Snapshot
+		root []byte
+	)
+
+	if err := pruned.Reference(number, func() types.Hash {
+		snap, root = txn.Commit(deleteEmptyObjects)
+
+		return types.BytesToHash(root)
+	}); err != nil {
+		e.logger.Error("failed to reference the state root", "number", number, "err", err)
+	}
+
+	return snap, root
+}
+
 // SetRuntime adds a runtime to the runtime set
 func (e *Executor) SetRuntime(r runtime.Runtime) {
 	e.runtimes = append(e.runtimes, r)
@@ -100,23 +133,94 @@ func (e *Executor) ProcessBlock(
 
 	txn.block = block
 
+	if e.IndexInternalTxs {
+		txn.internalTxs = []*types.InternalTransaction{}
+	}
+
+	// the state root of the receipts before the Byzantium fork is committed after every transaction
+	if e.ParallelExecution && len(block.Transactions) > 1 &&
+		!e.IndexInternalTxs && e.PostHook == nil && txn.config.Byzantium {
+		if err := txn.writeBlockTransactionsParallel(block.Transactions); err != nil {
+			return nil, err
+		}
+
+		return txn, nil
+	}
+
 	for _, t := range block.Transactions {
-		if t.ExceedsBlockGasLimit(block.Header.GasLimit) {
-			if err := txn.WriteFailedReceipt(t); err != nil {
-				return nil, err
-			}
+		var transferTracer *tracer.TransferTracer
 
-			continue
+		if e.IndexInternalTxs {
+			transferTracer = tracer.NewTransferTracer()
+			txn.SetTracer(transferTracer)
 		}
 
-		if err := txn.Write(t); err != nil {
+		if err := txn.writeBlockTransaction(t); err != nil {
 			return nil, err
 		}
+
+		if transferTracer == nil {
+			continue
+		}
+
+		for _, internalTx := range transferTracer.Result() {
+			internalTx.TxHash = t.Hash
+			txn.internalTxs = append(txn.internalTxs, internalTx)
+		}
 	}
 
+	txn.SetTracer(nil)
+
 	return txn, nil
 }
 
+// TraceBlock re-executes the transactions of the block on the parent state, every transaction
+// is traced by the tracer at its index, if any. The transactions following the last traced one are not executed.
+// The execution stops with runtime.ErrExecutionAborted once the abort channel is closed
+func (e *Executor) TraceBlock(
+	parentRoot types.Hash,
+	block *types.Block,
+	blockCreator types.Address,
+	tracers []runtime.Tracer,
+	abort <-chan struct{},
+) error {
+	txn, err := e.BeginTxn(parentRoot, block.Header, blockCreator)
+	if err != nil {
+		return err
+	}
+
+	txn.block = block
+	txn.SetAbort(abort)
+
+	last := -1
+
+	for indx, tracer := range tracers {
+		if tracer != nil && indx < len(block.Transactions) {
+			last = indx
+		}
+	}
+
+	for indx, t := range block.Transactions[:last+1] {
+		txn.SetTracer(tracers[indx])
+
+		if err := txn.writeBlockTransaction(t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeBlockTransaction writes the transaction of the block, the transactions
+// exceeding the block gas limit are written as failed
+func (t *Transition) writeBlockTransaction(txn *types.Transaction) error {
+	if txn.ExceedsBlockGasLimit(t.block.Header.GasLimit) {
+		return t.WriteFailedReceipt(txn)
+	}
+
+	return t.Write(txn)
+}
+
 // StateAt returns snapshot at given root
 func (e *Executor) State() State {
 	return e.state
@@ -153,6 +257,7 @@ func (e *Executor) BeginTxn(
 		Difficulty: types.BytesToHash(new(big.Int).SetUint64(header.Difficulty).Bytes()),
 		GasLimit:   int64(header.GasLimit),
 		ChainID:    int64(e.config.ChainID),
+		BaseFee:    types.BytesToHash(new(big.Int).SetUint64(header.BaseFee).Bytes()),
 	}
 
 	txn := &Transition{
@@ -169,6 +274,14 @@ func (e *Executor) BeginTxn(
 		totalGas: 0,
 	}
 
+	if config.EIP1559 {
+		txn.baseFee = header.GetBaseFee()
+
+		if collector, ok := e.config.FeeCollectorAt(header.Number); ok {
+			txn.feeCollector = &collector
+		}
+	}
+
 	return txn, nil
 }
 
@@ -187,16 +300,40 @@ type Transition struct {
 	getHash GetHashByNumber
 	ctx     runtime.TxContext
 	gasPool uint64
+	baseFee *big.Int // Base fee per gas of the block past the EIP-1559 fork, nil otherwise
+
+	// feeCollector is credited with the base fees of the transactions, they are burnt if nil
+	feeCollector *types.Address
+
+	// tracer records the execution of the transactions, if set
+	tracer runtime.Tracer
+
+	// abort is closed to abort the execution of the transactions, such as on the timeout of a call
+	abort <-chan struct{}
 
 	// result
 	receipts []*types.Receipt
 	totalGas uint64
+
+	// internalTxs are the internal transactions of the processed block, nil if they are not indexed
+	internalTxs []*types.InternalTransaction
+
+	// stateDiffs are the accounts changed by each written transaction, nil if they are not recorded
+	stateDiffs []map[types.Address]*AccountDiff
+
+	// fees are the fees of the transaction executed in parallel, nil if they are credited right away
+	fees []*deferredFee
 }
 
 func (t *Transition) TotalGas() uint64 {
 	return t.totalGas
 }
 
+// InternalTxs returns the internal transactions of the processed block, nil if they are not indexed
+func (t *Transition) InternalTxs() []*types.InternalTransaction {
+	return t.internalTxs
+}
+
 func (t *Transition) Receipts() []*types.Receipt {
 	return t.receipts
 }
@@ -220,6 +357,7 @@ func (t *Transition) WriteFailedReceipt(txn *types.Transaction) error {
 		CumulativeGasUsed: t.totalGas,
 		TxHash:            txn.Hash,
 		Logs:              t.state.Logs(),
+		EffectiveGasPrice: txn.EffectiveGasPrice(t.baseFee),
 	}
 
 	receipt.LogsBloom = types.CreateBloom([]*types.Receipt{receipt})
@@ -235,20 +373,18 @@ func (t *Transition) WriteFailedReceipt(txn *types.Transaction) error {
 
 // Write writes another transaction to the executor
 func (t *Transition) Write(txn *types.Transaction) error {
-	signer := crypto.NewSigner(t.config, uint64(t.r.config.ChainID))
-
-	var err error
-	if txn.From == emptyFrom {
-		// Decrypt the from address
-		txn.From, err = signer.Sender(txn)
-		if err != nil {
-			return NewTransitionApplicationError(err, false)
-		}
+	if err := t.prepareTransaction(txn); err != nil {
+		return err
 	}
 
 	// Make a local copy and apply the transaction
 	msg := txn.Copy()
 
+	var pre *Txn
+	if t.stateDiffs != nil {
+		pre = t.state.Copy()
+	}
+
 	result, e := t.Apply(msg)
 	if e != nil {
 		t.logger.Error("failed to apply tx", "err", e)
@@ -256,50 +392,90 @@ func (t *Transition) Write(txn *types.Transaction) error {
 		return e
 	}
 
+	if pre != nil {
+		// the empty accounts are removed below, as the Byzantium and the EIP155 forks do
+		t.stateDiffs = append(t.stateDiffs, diffState(pre, t.state, t.config.Byzantium || t.config.EIP155))
+	}
+
 	t.totalGas += result.GasUsed
 
 	logs := t.state.Logs()
 
 	var root []byte
 
+	if t.config.Byzantium {
+		// The suicided accounts are set as deleted for the next iteration
+		t.state.CleanDeleteObjects(true)
+	} else {
+		ss, aux := t.r.commit(t.state, uint64(t.ctx.Number), t.config.EIP155)
+		t.state = NewTxn(t.auxState, ss)
+		root = aux
+	}
+
+	t.appendReceipt(txn, result, logs, root)
+
+	return nil
+}
+
+// prepareTransaction recovers the sender of the transaction and checks its fees and its replay protection
+func (t *Transition) prepareTransaction(txn *types.Transaction) error {
+	signer := crypto.NewSigner(t.config, uint64(t.r.config.ChainID))
+
+	var err error
+	if txn.From == emptyFrom {
+		// Decrypt the from address
+		txn.From, err = signer.Sender(txn)
+		if err != nil {
+			return NewTransitionApplicationError(err, false)
+		}
+	}
+
+	// the unprotected transactions of the blocks before the fork are still valid
+	if t.config.ReplayProtection && !txn.IsReplayProtected() {
+		return NewTransitionApplicationError(ErrUnprotectedTx, false)
+	}
+
+	return t.checkFees(txn)
+}
+
+// appendReceipt appends the receipt of the applied transaction, with the state root before the Byzantium fork
+func (t *Transition) appendReceipt(
+	txn *types.Transaction,
+	result *runtime.ExecutionResult,
+	logs []*types.Log,
+	root []byte,
+) {
 	receipt := &types.Receipt{
 		CumulativeGasUsed: t.totalGas,
 		TxHash:            txn.Hash,
 		GasUsed:           result.GasUsed,
+		EffectiveGasPrice: txn.EffectiveGasPrice(t.baseFee),
 	}
 
 	if t.config.Byzantium {
-		// The suicided accounts are set as deleted for the next iteration
-		t.state.CleanDeleteObjects(true)
-
 		if result.Failed() {
 			receipt.SetStatus(types.ReceiptFailed)
 		} else {
 			receipt.SetStatus(types.ReceiptSuccess)
 		}
 	} else {
-		ss, aux := t.state.Commit(t.config.EIP155)
-		t.state = NewTxn(t.auxState, ss)
-		root = aux
 		receipt.Root = types.BytesToHash(root)
 	}
 
 	// if the transaction created a contract, store the creation address in the receipt.
-	if msg.To == nil {
-		receipt.ContractAddress = crypto.CreateAddress(msg.From, txn.Nonce)
+	if txn.To == nil {
+		receipt.ContractAddress = crypto.CreateAddress(txn.From, txn.Nonce)
 	}
 
 	// Set the receipt logs and create a bloom for filtering
 	receipt.Logs = logs
 	receipt.LogsBloom = types.CreateBloom([]*types.Receipt{receipt})
 	t.receipts = append(t.receipts, receipt)
-
-	return nil
 }
 
 // Commit commits the final result
 func (t *Transition) Commit() (Snapshot, types.Hash) {
-	s2, root := t.state.Commit(t.config.EIP155)
+	s2, root := t.r.commit(t.state, uint64(t.ctx.Number), t.config.EIP155)
 
 	return s2, types.BytesToHash(root)
 }
@@ -332,9 +508,18 @@ func (t *Transition) GetTxnHash() types.Hash {
 
 // Apply applies a new transaction
 func (t *Transition) Apply(msg *types.Transaction) (*runtime.ExecutionResult, error) {
+	if tracer, ok := t.tracer.(runtime.PrestateTracer); ok {
+		tracer.CapturePrestate(t.state.Copy())
+	}
+
 	s := t.state.Snapshot() //nolint:ifshort
 	result, err := t.apply(msg)
 
+	// the aborted execution has no result, the transaction is reverted as if it was never applied
+	if err == nil && errors.Is(result.Err, runtime.ErrExecutionAborted) {
+		result, err = nil, result.Err
+	}
+
 	if err != nil {
 		t.state.RevertToSnapshot(s)
 	}
@@ -352,10 +537,45 @@ func (t *Transition) ContextPtr() *runtime.TxContext {
 	return &t.ctx
 }
 
-func (t *Transition) subGasLimitPrice(msg *types.Transaction) error {
+// checkFees checks the fees of the transaction can be paid in the block
+func (t *Transition) checkFees(txn *types.Transaction) error {
+	// the access lists are part of the transactions past the Berlin fork
+	if (txn.IsAccessList() || len(txn.AccessList) != 0) && !t.config.Berlin {
+		return NewTransitionApplicationError(types.ErrTxTypeNotSupported, false)
+	}
+
+	if txn.IsDynamicFee() {
+		if !t.config.EIP1559 {
+			return NewTransitionApplicationError(types.ErrTxTypeNotSupported, false)
+		}
+
+		if txn.MaxPriorityFeePerGas.Cmp(txn.MaxFeePerGas) > 0 {
+			return NewTransitionApplicationError(ErrTipAboveFeeCap, false)
+		}
+	}
+
+	// the base fee may go down in the next blocks, the transaction is kept
+	if t.baseFee != nil && txn.GetGasFeeCap().Cmp(t.baseFee) < 0 {
+		return NewTransitionApplicationError(ErrFeeCapTooLow, true)
+	}
+
+	return nil
+}
+
+func (t *Transition) subGasLimitPrice(msg *types.Transaction, gasPrice *big.Int) error {
+	gas := new(big.Int).SetUint64(msg.Gas)
+
+	// the sender has to afford the maximum fee per gas of the transaction
+	if msg.IsDynamicFee() {
+		maxGasCost := new(big.Int).Mul(msg.MaxFeePerGas, gas)
+		if t.state.GetBalance(msg.From).Cmp(maxGasCost) < 0 {
+			return ErrNotEnoughFundsForGas
+		}
+	}
+
 	// deduct the upfront max gas cost
-	upfrontGasCost := new(big.Int).Set(msg.GasPrice)
-	upfrontGasCost.Mul(upfrontGasCost, new(big.Int).SetUint64(msg.Gas))
+	upfrontGasCost := new(big.Int).Set(gasPrice)
+	upfrontGasCost.Mul(upfrontGasCost, gas)
 
 	if err := t.state.SubBalance(msg.From, upfrontGasCost); err != nil {
 		if errors.Is(err, runtime.ErrNotEnoughFunds) {
@@ -388,6 +608,9 @@ var (
 	ErrIntrinsicGasOverflow  = fmt.Errorf("overflow in intrinsic gas calculation")
 	ErrNotEnoughIntrinsicGas = fmt.Errorf("not enough gas supplied for intrinsic gas costs")
 	ErrNotEnoughFunds        = fmt.Errorf("not enough funds for transfer with given value")
+	ErrFeeCapTooLow          = fmt.Errorf("max fee per gas less than block base fee")
+	ErrTipAboveFeeCap        = fmt.Errorf("max priority fee per gas higher than max fee per gas")
+	ErrUnprotectedTx         = fmt.Errorf("only replay-protected transactions allowed")
 )
 
 type TransitionApplicationError struct {
@@ -399,6 +622,10 @@ func (e *TransitionApplicationError) Error() string {
 	return e.Err.Error()
 }
 
+func (e *TransitionApplicationError) Unwrap() error {
+	return e.Err
+}
+
 func NewTransitionApplicationError(err error, isRecoverable bool) *TransitionApplicationError {
 	return &TransitionApplicationError{
 		Err:           err,
@@ -433,8 +660,11 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 		return nil, NewTransitionApplicationError(err, true)
 	}
 
+	// the price per gas paid by the transaction, in reference to the base fee
+	gasPrice := msg.EffectiveGasPrice(t.baseFee)
+
 	// 2. caller has enough balance to cover transaction fee(gaslimit * gasprice)
-	if err := t.subGasLimitPrice(msg); err != nil {
+	if err := t.subGasLimitPrice(msg, gasPrice); err != nil {
 		return nil, NewTransitionApplicationError(err, true)
 	}
 
@@ -444,7 +674,7 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 	}
 
 	// 4. there is no overflow when calculating intrinsic gas
-	intrinsicGasCost, err := TransactionGasCost(msg, t.config.Homestead, t.config.Istanbul)
+	intrinsicGasCost, err := TransactionGasCost(msg, t.config.Homestead, t.config.Istanbul, t.config.EIP3860)
 	if err != nil {
 		return nil, NewTransitionApplicationError(err, false)
 	}
@@ -461,13 +691,21 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 		return nil, NewTransitionApplicationError(ErrNotEnoughFunds, true)
 	}
 
-	gasPrice := new(big.Int).Set(msg.GasPrice)
+	// 7. the init code of the contract creation is within the size limit
+	if t.config.EIP3860 && msg.IsContractCreation() && len(msg.Input) > runtime.MaxInitCodeSize {
+		return nil, NewTransitionApplicationError(runtime.ErrMaxInitCodeSizeExceeded, false)
+	}
+
 	value := new(big.Int).Set(msg.Value)
 
 	// Set the specific transaction fields in the context
 	t.ctx.GasPrice = types.BytesToHash(gasPrice.Bytes())
 	t.ctx.Origin = msg.From
 
+	if t.config.Berlin {
+		t.prepareAccessList(msg)
+	}
+
 	var result *runtime.ExecutionResult
 	if msg.IsContractCreation() {
 		result = t.Create2(msg.From, msg.Input, value, gasLeft)
@@ -476,23 +714,76 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 		result = t.Call2(msg.From, *msg.To, msg.Input, value, gasLeft)
 	}
 
+	// eip-3529, the refund is capped to a fifth of the gas used
+	refundQuotient := runtime.MaxRefundQuotient
+	if t.config.London {
+		refundQuotient = runtime.MaxRefundQuotientLondon
+	}
+
 	refund := txn.GetRefund()
-	result.UpdateGasUsed(msg.Gas, refund)
+	result.UpdateGasUsed(msg.Gas, refund, refundQuotient)
 
 	// refund the sender
 	remaining := new(big.Int).Mul(new(big.Int).SetUint64(result.GasLeft), gasPrice)
 	txn.AddBalance(msg.From, remaining)
 
 	// pay the coinbase
-	coinbaseFee := new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), gasPrice)
-	txn.AddBalance(t.ctx.Coinbase, coinbaseFee)
+	if tip := msg.EffectiveTip(t.baseFee); tip.Sign() > 0 {
+		coinbaseFee := new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), tip)
+		t.payFee(t.ctx.Coinbase, coinbaseFee)
+	}
+
+	// the base fee is credited to the fee collector, or burnt
+	if t.feeCollector != nil && t.baseFee != nil {
+		collectedFee := new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), t.baseFee)
+		t.payFee(*t.feeCollector, collectedFee)
+	}
 
 	// return gas to the pool
 	t.addGasPool(result.GasLeft)
 
+	if t.tracer != nil {
+		t.tracer.CaptureEnd(result)
+	}
+
 	return result, nil
 }
 
+// payFee credits the fee of the transaction, or defers it if the transaction is executed in parallel
+func (t *Transition) payFee(addr types.Address, amount *big.Int) {
+	if t.fees != nil {
+		t.fees = append(t.fees, &deferredFee{addr: addr, amount: amount})
+
+		return
+	}
+
+	t.state.AddBalance(addr, amount)
+}
+
+// prepareAccessList warms the sender, the recipient, the precompiled contracts
+// and the access list of the transaction, as in EIP-2929 and EIP-2930
+func (t *Transition) prepareAccessList(msg *types.Transaction) {
+	t.state.ClearAccessList()
+
+	t.state.AddAddressToAccessList(msg.From)
+
+	if msg.To != nil {
+		t.state.AddAddressToAccessList(*msg.To)
+	}
+
+	for _, addr := range precompiled.ActiveAddresses(&t.config) {
+		t.state.AddAddressToAccessList(addr)
+	}
+
+	for _, tuple := range msg.AccessList {
+		t.state.AddAddressToAccessList(tuple.Address)
+
+		for _, key := range tuple.StorageKeys {
+			t.state.AddSlotToAccessList(tuple.Address, key)
+		}
+	}
+}
+
 func (t *Transition) Create2(
 	caller types.Address,
 	code []byte,
@@ -505,6 +796,20 @@ func (t *Transition) Create2(
 	return t.applyCreate(contract, t)
 }
 
+// CreateAt runs the creation code of the contract at the given address, instead of the address
+// derived from the caller. It is used to predeploy the contracts at genesis
+func (t *Transition) CreateAt(
+	caller types.Address,
+	address types.Address,
+	code []byte,
+	value *big.Int,
+	gas uint64,
+) *runtime.ExecutionResult {
+	contract := runtime.NewContractCreation(1, caller, caller, address, value, gas, code)
+
+	return t.applyCreate(contract, t)
+}
+
 func (t *Transition) Call2(
 	caller types.Address,
 	to types.Address,
@@ -573,14 +878,52 @@ func (t *Transition) applyCall(
 		}
 	}
 
+	t.captureEnter(c, callType)
+
 	result := t.run(c, host)
 	if result.Failed() {
 		t.state.RevertToSnapshot(snapshot)
 	}
 
+	t.captureExit(c, result)
+
 	return result
 }
 
+// captureEnter traces the start of the call frame, the frames are reported to the
+// addresses of the code they run, from the address of the contract making the call
+func (t *Transition) captureEnter(c *runtime.Contract, callType runtime.CallType) {
+	if t.tracer == nil {
+		return
+	}
+
+	from, to, value := c.Caller, c.CodeAddress, c.Value
+
+	switch callType {
+	case runtime.DelegateCall:
+		from, value = c.Address, nil
+	case runtime.StaticCall:
+		value = nil
+	case runtime.Create, runtime.Create2:
+		to = c.Address
+	}
+
+	input := c.Input
+	if callType == runtime.Create || callType == runtime.Create2 {
+		input = c.Code
+	}
+
+	t.tracer.CaptureEnter(callType, from, to, input, c.Gas, value)
+}
+
+func (t *Transition) captureExit(c *runtime.Contract, result *runtime.ExecutionResult) {
+	if t.tracer == nil {
+		return
+	}
+
+	t.tracer.CaptureExit(result.ReturnValue, c.Gas-result.GasLeft, result.Err)
+}
+
 var emptyHash types.Hash
 
 func (t *Transition) hasCodeOrNonce(addr types.Address) bool {
@@ -599,6 +942,11 @@ func (t *Transition) hasCodeOrNonce(addr types.Address) bool {
 }
 
 func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtime.ExecutionResult {
+	callType := runtime.Create
+	if c.Type == runtime.Create2 {
+		callType = runtime.Create2
+	}
+
 	gasLimit := c.Gas
 
 	if c.Depth > int(1024)+1 {
@@ -611,6 +959,11 @@ func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtim
 	// Increment the nonce of the caller
 	t.state.IncrNonce(c.Caller)
 
+	// the created contract is warm, even if the creation fails
+	if t.config.Berlin {
+		t.state.AddAddressToAccessList(c.Address)
+	}
+
 	// Check if there if there is a collision and the address already exists
 	if t.hasCodeOrNonce(c.Address) {
 		return &runtime.ExecutionResult{
@@ -636,6 +989,17 @@ func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtim
 		}
 	}
 
+	t.captureEnter(c, callType)
+
+	result := t.create(c, host, snapshot)
+
+	t.captureExit(c, result)
+
+	return result
+}
+
+// create runs the creation code of the contract, and stores the code it returns
+func (t *Transition) create(c *runtime.Contract, host runtime.Host, snapshot int) *runtime.ExecutionResult {
 	result := t.run(c, host)
 
 	if result.Failed() {
@@ -644,7 +1008,8 @@ func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtim
 		return result
 	}
 
-	if t.config.EIP158 && len(result.ReturnValue) > spuriousDragonMaxCodeSize {
+	// the limit came with EIP158 in the spurious dragon fork, the chains without it can enable EIP170 alone
+	if (t.config.EIP158 || t.config.EIP170) && len(result.ReturnValue) > runtime.MaxCodeSize {
 		// Contract size exceeds 'SpuriousDragon' size limit
 		t.state.RevertToSnapshot(snapshot)
 
@@ -685,6 +1050,29 @@ func (t *Transition) SetStorage(
 	return t.state.SetStorage(addr, key, value, config)
 }
 
+// SetTracer sets the tracer of the next transactions, nil stops the tracing
+func (t *Transition) SetTracer(tracer runtime.Tracer) {
+	t.tracer = tracer
+}
+
+func (t *Transition) GetTracer() runtime.Tracer {
+	return t.tracer
+}
+
+// SetAbort sets the channel closed to abort the execution of the next transactions, nil never aborts them
+func (t *Transition) SetAbort(abort <-chan struct{}) {
+	t.abort = abort
+}
+
+func (t *Transition) Aborted() bool {
+	select {
+	case <-t.abort:
+		return true
+	default:
+		return false
+	}
+}
+
 func (t *Transition) GetTxContext() runtime.TxContext {
 	return t.ctx
 }
@@ -730,7 +1118,8 @@ func (t *Transition) GetNonce(addr types.Address) uint64 {
 }
 
 func (t *Transition) Selfdestruct(addr types.Address, beneficiary types.Address) {
-	if !t.state.HasSuicided(addr) {
+	// eip-3529, there is no refund for the selfdestruct
+	if !t.config.London && !t.state.HasSuicided(addr) {
 		t.state.AddRefund(24000)
 	}
 
@@ -738,8 +1127,28 @@ func (t *Transition) Selfdestruct(addr types.Address, beneficiary types.Address)
 	t.state.Suicide(addr)
 }
 
+func (t *Transition) AccessAddress(addr types.Address) bool {
+	if t.state.AddressInAccessList(addr) {
+		return true
+	}
+
+	t.state.AddAddressToAccessList(addr)
+
+	return false
+}
+
+func (t *Transition) AccessSlot(addr types.Address, key types.Hash) bool {
+	if t.state.SlotInAccessList(addr, key) {
+		return true
+	}
+
+	t.state.AddSlotToAccessList(addr, key)
+
+	return false
+}
+
 func (t *Transition) Callx(c *runtime.Contract, h runtime.Host) *runtime.ExecutionResult {
-	if c.Type == runtime.Create {
+	if c.Type == runtime.Create || c.Type == runtime.Create2 {
 		return t.applyCreate(c, h)
 	}
 
@@ -765,7 +1174,7 @@ func (t *Transition) SetAccountDirectly(addr types.Address, account *chain.Genes
 	return nil
 }
 
-func TransactionGasCost(msg *types.Transaction, isHomestead, isIstanbul bool) (uint64, error) {
+func TransactionGasCost(msg *types.Transaction, isHomestead, isIstanbul, isEIP3860 bool) (uint64, error) {
 	cost := uint64(0)
 
 	// Contract creation is only paid on the homestead fork
@@ -805,5 +1214,38 @@ func TransactionGasCost(msg *types.Transaction, isHomestead, isIstanbul bool) (u
 		cost += zeros * 4
 	}
 
+	// the words of the init code are paid, as in EIP-3860
+	if msg.IsContractCreation() && isEIP3860 {
+		words := (uint64(len(payload)) + 31) / 32
+
+		if (math.MaxUint64-cost)/runtime.InitCodeWordGas < words {
+			return 0, ErrIntrinsicGasOverflow
+		}
+
+		cost += words * runtime.InitCodeWordGas
+	}
+
+	// the accessed addresses and storage keys are paid upfront, as in EIP-2930
+	if len(msg.AccessList) > 0 {
+		keys := uint64(0)
+		for _, tuple := range msg.AccessList {
+			keys += uint64(len(tuple.StorageKeys))
+		}
+
+		addresses := uint64(len(msg.AccessList))
+
+		if (math.MaxUint64-cost)/TxAccessListAddressGas < addresses {
+			return 0, ErrIntrinsicGasOverflow
+		}
+
+		cost += addresses * TxAccessListAddressGas
+
+		if (math.MaxUint64-cost)/TxAccessListStorageKeyGas < keys {
+			return 0, ErrIntrinsicGasOverflow
+		}
+
+		cost += keys * TxAccessListStorageKeyGas
+	}
+
 	return cost, nil
 }
@@ -0,0 +1,258 @@
+package itrie
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/go-kit/kit/metrics"
+)
+
+// codeCacheDivisor is the share of the cache holding the contract code,
+// the rest of it holds the trie nodes
+const codeCacheDivisor = 4
+
+// CachedStorage is a trie storage keeping the recently read trie nodes and contract code in memory.
+// The trie nodes and the code are addressed by their hash, so their values never change:
+// the entries are only evicted when the nodes are deleted, e.g. when the pruning releases
+// the state of the blocks reorganized out of the chain. The other keys are not cached
+type CachedStorage struct {
+	Storage
+
+	nodes *lruCache
+	code  *lruCache
+}
+
+// NewCachedStorage creates a trie storage caching up to size bytes of the storage
+func NewCachedStorage(storage Storage, size int, metrics *Metrics) *CachedStorage {
+	if metrics == nil {
+		metrics = NilMetrics()
+	}
+
+	codeSize := size / codeCacheDivisor
+
+	return &CachedStorage{
+		Storage: storage,
+		nodes:   newLRUCache(size-codeSize, metrics.NodeCacheHits, metrics.NodeCacheMisses),
+		code:    newLRUCache(codeSize, metrics.CodeCacheHits, metrics.CodeCacheMisses),
+	}
+}
+
+// isNodeKey checks if the key is the one of a trie node, the hash of the node
+func isNodeKey(k []byte) bool {
+	return len(k) == types.HashLength
+}
+
+func (c *CachedStorage) Put(k, v []byte) {
+	c.Storage.Put(k, v)
+
+	if isNodeKey(k) {
+		c.nodes.add(string(k), v)
+	}
+}
+
+func (c *CachedStorage) Get(k []byte) ([]byte, bool) {
+	if !isNodeKey(k) {
+		return c.Storage.Get(k)
+	}
+
+	return c.nodes.load(string(k), func() ([]byte, bool) {
+		return c.Storage.Get(k)
+	})
+}
+
+func (c *CachedStorage) SetCode(hash types.Hash, code []byte) {
+	c.Storage.SetCode(hash, code)
+	c.code.add(string(hash.Bytes()), code)
+}
+
+func (c *CachedStorage) GetCode(hash types.Hash) ([]byte, bool) {
+	return c.code.load(string(hash.Bytes()), func() ([]byte, bool) {
+		return c.Storage.GetCode(hash)
+	})
+}
+
+func (c *CachedStorage) Batch() Batch {
+	return &cachedBatch{Batch: c.Storage.Batch(), nodes: c.nodes}
+}
+
+// Purge drops all the cached entries
+func (c *CachedStorage) Purge() {
+	c.nodes.purge()
+	c.code.purge()
+}
+
+// cachedBatch is a batch of the cached storage, the cache is updated once the batch is written
+type cachedBatch struct {
+	Batch
+
+	nodes  *lruCache
+	writes []cachedWrite
+}
+
+type cachedWrite struct {
+	key     string
+	value   []byte
+	deleted bool
+}
+
+func (b *cachedBatch) Put(k, v []byte) {
+	b.Batch.Put(k, v)
+
+	if isNodeKey(k) {
+		b.writes = append(b.writes, cachedWrite{key: string(k), value: v})
+	}
+}
+
+func (b *cachedBatch) Delete(k []byte) {
+	b.Batch.Delete(k)
+
+	if isNodeKey(k) {
+		b.writes = append(b.writes, cachedWrite{key: string(k), deleted: true})
+	}
+}
+
+func (b *cachedBatch) Write() {
+	b.Batch.Write()
+
+	for _, write := range b.writes {
+		if write.deleted {
+			b.nodes.remove(write.key)
+		} else {
+			b.nodes.add(write.key, write.value)
+		}
+	}
+
+	b.writes = nil
+}
+
+// lruCache is a least recently used cache bounded by the size of its entries
+type lruCache struct {
+	lock sync.Mutex
+
+	size    int
+	maxSize int
+	entries map[string]*list.Element
+	order   *list.List
+
+	// removals is increased by every removal, the values read from the storage
+	// while an entry was removed are not added since they may be stale
+	removals uint64
+
+	hits   metrics.Counter
+	misses metrics.Counter
+}
+
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+func newLRUCache(maxSize int, hits, misses metrics.Counter) *lruCache {
+	return &lruCache{
+		maxSize: maxSize,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+		hits:    hits,
+		misses:  misses,
+	}
+}
+
+func entrySize(key string, value []byte) int {
+	return len(key) + len(value)
+}
+
+// load returns the cached value of the key, or the one read from the storage which is then cached.
+// The returned values are shared, they must not be modified
+func (c *lruCache) load(key string, read func() ([]byte, bool)) ([]byte, bool) {
+	c.lock.Lock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		c.lock.Unlock()
+		c.hits.Add(1)
+
+		entry, _ := elem.Value.(*lruEntry)
+
+		return entry.value, true
+	}
+
+	removals := c.removals
+	c.lock.Unlock()
+	c.misses.Add(1)
+
+	value, ok := read()
+	if !ok {
+		return value, false
+	}
+
+	value = append([]byte{}, value...)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.removals == removals {
+		c.addLocked(key, value)
+	}
+
+	return value, true
+}
+
+// add caches a copy of the value of the key
+func (c *lruCache) add(key string, value []byte) {
+	value = append([]byte{}, value...)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.addLocked(key, value)
+}
+
+func (c *lruCache) addLocked(key string, value []byte) {
+	size := entrySize(key, value)
+	if size > c.maxSize {
+		return
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeLocked(elem)
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+	c.size += size
+
+	for c.size > c.maxSize {
+		c.removeLocked(c.order.Back())
+	}
+}
+
+func (c *lruCache) removeLocked(elem *list.Element) {
+	entry, _ := elem.Value.(*lruEntry)
+
+	c.order.Remove(elem)
+	delete(c.entries, entry.key)
+	c.size -= entrySize(entry.key, entry.value)
+}
+
+// remove evicts the key
+func (c *lruCache) remove(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.removals++
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeLocked(elem)
+	}
+}
+
+// purge evicts all the keys
+func (c *lruCache) purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.removals++
+	c.size = 0
+	c.entries = map[string]*list.Element{}
+	c.order.Init()
+}
@@ -0,0 +1,217 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/go-kit/kit/metrics"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingStorage counts the reads of the storage
+type countingStorage struct {
+	Storage
+
+	reads     int
+	codeReads int
+}
+
+func (c *countingStorage) Get(k []byte) ([]byte, bool) {
+	c.reads++
+
+	return c.Storage.Get(k)
+}
+
+func (c *countingStorage) GetCode(hash types.Hash) ([]byte, bool) {
+	c.codeReads++
+
+	return c.Storage.GetCode(hash)
+}
+
+// mockCounter is a counter of the test metrics
+type mockCounter struct {
+	value float64
+}
+
+func (c *mockCounter) With(labelValues ...string) metrics.Counter {
+	return c
+}
+
+func (c *mockCounter) Add(delta float64) {
+	c.value += delta
+}
+
+func counterValue(counter metrics.Counter) float64 {
+	mock, _ := counter.(*mockCounter)
+
+	return mock.value
+}
+
+func newTestMetrics() *Metrics {
+	return &Metrics{
+		NodeCacheHits:   &mockCounter{},
+		NodeCacheMisses: &mockCounter{},
+		CodeCacheHits:   &mockCounter{},
+		CodeCacheMisses: &mockCounter{},
+	}
+}
+
+func TestCachedStorage_Reads(t *testing.T) {
+	backend := &countingStorage{Storage: NewMemoryStorage()}
+	metrics := newTestMetrics()
+	storage := NewCachedStorage(backend, 1<<20, metrics)
+
+	node := types.StringToHash("1").Bytes()
+	backend.Storage.Put(node, []byte{0x1})
+
+	// the node is read from the storage once
+	for i := 0; i < 3; i++ {
+		data, ok := storage.Get(node)
+		assert.True(t, ok)
+		assert.Equal(t, []byte{0x1}, data)
+	}
+
+	assert.Equal(t, 1, backend.reads)
+	assert.Equal(t, float64(2), counterValue(metrics.NodeCacheHits))
+	assert.Equal(t, float64(1), counterValue(metrics.NodeCacheMisses))
+
+	// the other keys are not cached
+	storage.Put([]byte("key"), []byte{0x2})
+
+	for i := 0; i < 2; i++ {
+		data, ok := storage.Get([]byte("key"))
+		assert.True(t, ok)
+		assert.Equal(t, []byte{0x2}, data)
+	}
+
+	assert.Equal(t, 3, backend.reads)
+
+	// the missing nodes are not cached
+	_, ok := storage.Get(types.StringToHash("2").Bytes())
+	assert.False(t, ok)
+
+	backend.Storage.Put(types.StringToHash("2").Bytes(), []byte{0x3})
+
+	_, ok = storage.Get(types.StringToHash("2").Bytes())
+	assert.True(t, ok)
+
+	// the written code is served from the cache
+	code := types.StringToHash("3")
+	storage.SetCode(code, []byte{0x4})
+
+	data, ok := storage.GetCode(code)
+	assert.True(t, ok)
+	assert.Equal(t, []byte{0x4}, data)
+	assert.Equal(t, 0, backend.codeReads)
+	assert.Equal(t, float64(1), counterValue(metrics.CodeCacheHits))
+}
+
+func TestCachedStorage_Batch(t *testing.T) {
+	backend := &countingStorage{Storage: NewMemoryStorage()}
+	storage := NewCachedStorage(backend, 1<<20, nil)
+
+	node1, node2 := types.StringToHash("1").Bytes(), types.StringToHash("2").Bytes()
+	storage.Put(node1, []byte{0x1})
+
+	batch := storage.Batch()
+	batch.Delete(node1)
+	batch.Put(node2, []byte{0x2})
+
+	// the cache is updated once the batch is written
+	_, ok := storage.Get(node1)
+	assert.True(t, ok)
+
+	batch.Write()
+
+	_, ok = storage.Get(node1)
+	assert.False(t, ok)
+
+	reads := backend.reads
+
+	data, ok := storage.Get(node2)
+	assert.True(t, ok)
+	assert.Equal(t, []byte{0x2}, data)
+	assert.Equal(t, reads, backend.reads)
+}
+
+func TestLRUCache_Size(t *testing.T) {
+	metrics := NilMetrics()
+	cache := newLRUCache(10, metrics.NodeCacheHits, metrics.NodeCacheMisses)
+
+	cache.add("a", []byte{1, 2, 3, 4})
+	cache.add("b", []byte{1, 2, 3, 4})
+
+	// a is the least recently used entry
+	value, ok := cache.load("a", nil)
+	assert.True(t, ok)
+	assert.Equal(t, []byte{1, 2, 3, 4}, value)
+
+	cache.add("c", []byte{1, 2, 3, 4})
+
+	assert.Equal(t, 10, cache.size)
+	assert.Len(t, cache.entries, 2)
+	assert.NotContains(t, cache.entries, "b")
+
+	// the entries larger than the cache are not added
+	cache.add("d", make([]byte, 10))
+	assert.NotContains(t, cache.entries, "d")
+
+	// the values read while an entry is removed may be stale
+	_, ok = cache.load("e", func() ([]byte, bool) {
+		cache.remove("a")
+
+		return []byte{1}, true
+	})
+	assert.True(t, ok)
+	assert.NotContains(t, cache.entries, "e")
+}
+
+func TestCachedStorage_PrunedFork(t *testing.T) {
+	storage := NewCachedStorage(NewMemoryStorage(), 1<<20, nil)
+	p := NewPrunedState(storage, 1, hclog.NewNullLogger())
+
+	account := types.StringToAddress("1")
+
+	commit := func(number uint64, parent types.Hash, balance int64) types.Hash {
+		snap, err := p.NewSnapshotAt(parent)
+		assert.NoError(t, err)
+
+		txn := state.NewTxn(p, snap)
+		txn.AddBalance(account, big.NewInt(balance))
+
+		var root types.Hash
+
+		assert.NoError(t, p.Reference(number, func() types.Hash {
+			_, data := txn.Commit(false)
+			root = types.BytesToHash(data)
+
+			return root
+		}))
+
+		return root
+	}
+
+	genesis := commit(0, types.EmptyRootHash, 1)
+
+	// two blocks at the same height, the second one is the chain after the reorg
+	fork := commit(1, genesis, 2)
+	canonical := commit(1, genesis, 3)
+
+	// the fork is in the cache
+	_, err := p.NewSnapshotAt(fork)
+	assert.NoError(t, err)
+
+	head := commit(2, canonical, 4)
+	assert.NoError(t, p.Prune(2))
+
+	// the state of the fork is released without being served from the cache
+	_, err = p.NewSnapshotAt(fork)
+	assert.ErrorIs(t, err, state.ErrStatePruned)
+
+	snap, err := p.NewSnapshotAt(head)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(8), state.NewTxn(p, snap).GetBalance(account))
+}
@@ -0,0 +1,66 @@
+package itrie
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	prometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics represents the trie storage metrics
+type Metrics struct {
+	// No.of trie node reads served from the cache
+	NodeCacheHits metrics.Counter
+	// No.of trie node reads sent to the database
+	NodeCacheMisses metrics.Counter
+	// No.of contract code reads served from the cache
+	CodeCacheHits metrics.Counter
+	// No.of contract code reads sent to the database
+	CodeCacheMisses metrics.Counter
+}
+
+// GetPrometheusMetrics return the trie storage metrics instance
+func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics {
+	labels := []string{}
+
+	for i := 0; i < len(labelsWithValues); i += 2 {
+		labels = append(labels, labelsWithValues[i])
+	}
+
+	return &Metrics{
+		NodeCacheHits: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "state",
+			Name:      "node_cache_hits",
+			Help:      "Number of trie node reads served from the cache.",
+		}, labels).With(labelsWithValues...),
+		NodeCacheMisses: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "state",
+			Name:      "node_cache_misses",
+			Help:      "Number of trie node reads missing the cache.",
+		}, labels).With(labelsWithValues...),
+		CodeCacheHits: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "state",
+			Name:      "code_cache_hits",
+			Help:      "Number of contract code reads served from the cache.",
+		}, labels).With(labelsWithValues...),
+		CodeCacheMisses: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "state",
+			Name:      "code_cache_misses",
+			Help:      "Number of contract code reads missing the cache.",
+		}, labels).With(labelsWithValues...),
+	}
+}
+
+// NilMetrics will return the non operational trie storage metrics
+func NilMetrics() *Metrics {
+	return &Metrics{
+		NodeCacheHits:   discard.NewCounter(),
+		NodeCacheMisses: discard.NewCounter(),
+		CodeCacheHits:   discard.NewCounter(),
+		CodeCacheMisses: discard.NewCounter(),
+	}
+}
@@ -0,0 +1,120 @@
+package itrie
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// Prove returns the merkle proof of the key in the trie of the root: the stored nodes on the path
+// of the key, from the root down. The value of the key is returned as well, nil if the key
+// is not in the trie, in which case the proof is the one of its exclusion
+func Prove(root types.Hash, key []byte, storage Storage) ([][]byte, []byte, error) {
+	proof := [][]byte{}
+
+	if root == types.EmptyRootHash {
+		return proof, nil, nil
+	}
+
+	hash, path := root.Bytes(), bytesToHexNibbles(key)
+
+	for {
+		data, ok := storage.Get(hash)
+		if !ok {
+			return nil, nil, fmt.Errorf("trie node %s not found", types.BytesToHash(hash))
+		}
+
+		proof = append(proof, data)
+
+		node, err := parseNode(data, storage)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var value []byte
+
+		hash, path, value = walkNode(node, path)
+		if hash == nil {
+			return proof, value, nil
+		}
+	}
+}
+
+// VerifyProof checks the merkle proof of the key against the root, and returns the proven value
+// of the key, nil if the proof is the one of its exclusion
+func VerifyProof(root types.Hash, key []byte, proof [][]byte) ([]byte, error) {
+	nodes := make(map[types.Hash][]byte, len(proof))
+	for _, data := range proof {
+		nodes[types.BytesToHash(crypto.Keccak256(data))] = data
+	}
+
+	if root == types.EmptyRootHash {
+		return nil, nil
+	}
+
+	hash, path := root.Bytes(), bytesToHexNibbles(key)
+
+	for {
+		data, ok := nodes[types.BytesToHash(hash)]
+		if !ok {
+			return nil, fmt.Errorf("proof node %s missing", types.BytesToHash(hash))
+		}
+
+		node, err := parseNode(data, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var value []byte
+
+		hash, path, value = walkNode(node, path)
+		if hash == nil {
+			return value, nil
+		}
+	}
+}
+
+// walkNode follows the path through the decoded node. It returns the hash of the stored node
+// the path continues in along with the rest of the path, or the value found at the end of
+// the path, nil if the path is not in the trie
+func walkNode(node Node, path []byte) ([]byte, []byte, []byte) {
+	for {
+		switch n := node.(type) {
+		case nil:
+			return nil, nil, nil
+
+		case *ValueNode:
+			if n.hash {
+				return n.buf, path, nil
+			}
+
+			if len(path) != 0 {
+				return nil, nil, nil
+			}
+
+			return nil, nil, n.buf
+
+		case *ShortNode:
+			plen := len(n.key)
+			if plen > len(path) || !bytes.Equal(path[:plen], n.key) {
+				return nil, nil, nil
+			}
+
+			node, path = n.child, path[plen:]
+
+		case *FullNode:
+			if len(path) == 0 {
+				node = n.value
+
+				continue
+			}
+
+			node, path = n.getEdge(path[0]), path[1:]
+
+		default:
+			panic(fmt.Sprintf("unknown node type %v", n))
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package itrie
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProve(t *testing.T) {
+	storage, root := buildSyncState(t)
+
+	snap, err := NewState(storage).NewSnapshotAt(root)
+	assert.NoError(t, err)
+
+	for i := 0; i < 50; i++ {
+		key := hashit(types.BytesToAddress([]byte{byte(i + 1)}).Bytes())
+
+		proof, value, err := Prove(root, key, storage)
+		assert.NoError(t, err)
+
+		expected, ok := snap.Get(key)
+		assert.True(t, ok)
+		assert.Equal(t, expected, value)
+
+		// the proof starts at the root
+		assert.Equal(t, root.Bytes(), crypto.Keccak256(proof[0]))
+
+		proven, err := VerifyProof(root, key, proof)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, proven)
+
+		if i%5 != 0 {
+			continue
+		}
+
+		// the storage of the contracts
+		var account state.Account
+		assert.NoError(t, account.UnmarshalRlp(value))
+
+		slot := hashit(types.BytesToHash([]byte{byte(3)}).Bytes())
+
+		proof, value, err = Prove(account.Root, slot, storage)
+		assert.NoError(t, err)
+		assert.NotNil(t, value)
+
+		proven, err = VerifyProof(account.Root, slot, proof)
+		assert.NoError(t, err)
+		assert.Equal(t, value, proven)
+	}
+}
+
+func TestProve_Exclusion(t *testing.T) {
+	storage, root := buildSyncState(t)
+
+	key := hashit(types.StringToAddress("0xdead").Bytes())
+
+	proof, value, err := Prove(root, key, storage)
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+	assert.NotEmpty(t, proof)
+
+	proven, err := VerifyProof(root, key, proof)
+	assert.NoError(t, err)
+	assert.Nil(t, proven)
+
+	// the empty trie has an empty proof
+	proof, value, err = Prove(types.EmptyRootHash, key, storage)
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+	assert.Empty(t, proof)
+
+	// the proof does not prove the key for another root
+	_, err = VerifyProof(types.StringToHash("1"), key, proof)
+	assert.Error(t, err)
+
+	// the proof of a missing root
+	_, _, err = Prove(types.StringToHash("1"), key, storage)
+	assert.Error(t, err)
+}
+
+func TestVerifyProof_Tampered(t *testing.T) {
+	storage, root := buildSyncState(t)
+
+	key := hashit(types.BytesToAddress([]byte{1}).Bytes())
+
+	proof, _, err := Prove(root, key, storage)
+	assert.NoError(t, err)
+
+	// a node of the path is missing
+	_, err = VerifyProof(root, key, proof[:len(proof)-1])
+	assert.Error(t, err)
+
+	// a node of the path is modified, its hash does not match the reference of its parent
+	last := append([]byte{}, proof[len(proof)-1]...)
+	last[len(last)-1]++
+
+	tampered := append(append([][]byte{}, proof[:len(proof)-1]...), last)
+
+	_, err = VerifyProof(root, key, tampered)
+	assert.Error(t, err)
+}
@@ -0,0 +1,317 @@
+package itrie
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/umbracle/fastrlp"
+)
+
+var (
+	// refPrefix is the prefix of the reference counts of the trie nodes
+	refPrefix = []byte("ref")
+
+	// rootsPrefix is the prefix of the state roots committed for a block number
+	rootsPrefix = []byte("roots")
+
+	// pruneTailKey is the key of the lowest block number whose roots are not released yet
+	pruneTailKey = []byte("prunetail")
+)
+
+// PrunedState is a state keeping the tries of the latest blocks only.
+// The trie nodes are reference counted: the state roots are referenced by the blocks
+// they were committed for, and every node by the nodes pointing to it, the storage tries
+// by the accounts. Once a block falls out of the retention window its roots are released,
+// and the nodes that are not referenced anymore are deleted. The contract code is kept
+type PrunedState struct {
+	*State
+
+	logger    hclog.Logger
+	retention uint64
+
+	// lock serializes the references and the pruning
+	lock sync.Mutex
+	tail uint64
+}
+
+// NewPrunedState creates a state keeping the tries of the last retention blocks
+func NewPrunedState(storage Storage, retention uint64, logger hclog.Logger) *PrunedState {
+	if retention == 0 {
+		retention = 1
+	}
+
+	p := &PrunedState{
+		State:     NewState(storage),
+		logger:    logger.Named("pruning"),
+		retention: retention,
+	}
+
+	if data, ok := storage.Get(pruneTailKey); ok && len(data) == 8 {
+		p.tail = binary.BigEndian.Uint64(data)
+	}
+
+	return p
+}
+
+// NewSnapshotAt returns the snapshot of the state root, or state.ErrStatePruned
+// if the root is not in the storage anymore
+func (p *PrunedState) NewSnapshotAt(root types.Hash) (state.Snapshot, error) {
+	if root != types.EmptyRootHash {
+		if _, ok := p.storage.Get(root.Bytes()); !ok {
+			return nil, fmt.Errorf("%w, root %s", state.ErrStatePruned, root)
+		}
+	}
+
+	return p.State.NewSnapshotAt(root)
+}
+
+// Reference runs the commit of the state of the block, and references the committed root.
+// The roots of the blocks already out of the retention window are not referenced
+func (p *PrunedState) Reference(number uint64, commit func() types.Hash) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	root := commit()
+
+	if number < p.tail || root == types.EmptyRootHash {
+		return nil
+	}
+
+	refs := newRefCounter(p.storage)
+	if err := refs.inc(root, true); err != nil {
+		return err
+	}
+
+	key := rootsKey(number)
+	roots, _ := p.storage.Get(key)
+
+	batch := p.storage.Batch()
+	refs.write(batch, p.cache)
+	batch.Put(key, append(append([]byte{}, roots...), root.Bytes()...))
+	batch.Write()
+
+	return nil
+}
+
+// Prune releases the roots of the blocks out of the retention window of the head,
+// and deletes the trie nodes that are not referenced anymore
+func (p *PrunedState) Prune(head uint64) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if head < p.retention {
+		return nil
+	}
+
+	last := head - p.retention
+	if last < p.tail {
+		return nil
+	}
+
+	refs := newRefCounter(p.storage)
+	batch := p.storage.Batch()
+
+	for number := p.tail; number <= last; number++ {
+		key := rootsKey(number)
+
+		roots, ok := p.storage.Get(key)
+		if !ok {
+			continue
+		}
+
+		for i := 0; i+types.HashLength <= len(roots); i += types.HashLength {
+			if err := refs.dec(types.BytesToHash(roots[i:i+types.HashLength]), true); err != nil {
+				return err
+			}
+		}
+
+		batch.Delete(key)
+	}
+
+	tail := make([]byte, 8)
+	binary.BigEndian.PutUint64(tail, last+1)
+
+	refs.write(batch, p.cache)
+	batch.Put(pruneTailKey, tail)
+	batch.Write()
+
+	p.tail = last + 1
+
+	p.logger.Debug("pruned state", "tail", p.tail, "deleted", len(refs.deleted))
+
+	return nil
+}
+
+func rootsKey(number uint64) []byte {
+	key := make([]byte, len(rootsPrefix)+8)
+	copy(key, rootsPrefix)
+	binary.BigEndian.PutUint64(key[len(rootsPrefix):], number)
+
+	return key
+}
+
+func refKey(hash types.Hash) []byte {
+	return append(append([]byte{}, refPrefix...), hash.Bytes()...)
+}
+
+// refCounter updates the reference counts of the trie nodes in memory,
+// they are written to the storage in a single batch along with the deleted nodes.
+// A node references its children for as long as it is referenced itself
+type refCounter struct {
+	storage Storage
+	counts  map[types.Hash]uint64
+	deleted map[types.Hash]struct{}
+}
+
+func newRefCounter(storage Storage) *refCounter {
+	return &refCounter{
+		storage: storage,
+		counts:  map[types.Hash]uint64{},
+		deleted: map[types.Hash]struct{}{},
+	}
+}
+
+func (r *refCounter) count(hash types.Hash) uint64 {
+	if count, ok := r.counts[hash]; ok {
+		return count
+	}
+
+	data, ok := r.storage.Get(refKey(hash))
+	if !ok || len(data) != 8 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(data)
+}
+
+// inc references the node, the first reference references its children
+func (r *refCounter) inc(hash types.Hash, account bool) error {
+	count := r.count(hash)
+	r.counts[hash] = count + 1
+
+	if count != 0 {
+		return nil
+	}
+
+	data, ok := r.storage.Get(hash.Bytes())
+	if !ok {
+		return fmt.Errorf("trie node %s not found", hash)
+	}
+
+	return r.children(data, account, r.inc)
+}
+
+// dec releases the node, the last reference deletes it and releases its children
+func (r *refCounter) dec(hash types.Hash, account bool) error {
+	count := r.count(hash)
+	if count == 0 {
+		// not referenced
+		return nil
+	}
+
+	r.counts[hash] = count - 1
+
+	if count != 1 {
+		return nil
+	}
+
+	r.deleted[hash] = struct{}{}
+
+	data, ok := r.storage.Get(hash.Bytes())
+	if !ok {
+		return nil
+	}
+
+	return r.children(data, account, r.dec)
+}
+
+// write puts the updated reference counts and the deleted nodes into the batch,
+// and evicts the deleted tries from the cache
+func (r *refCounter) write(batch Batch, cache *lru.Cache) {
+	for hash, count := range r.counts {
+		if count == 0 {
+			batch.Delete(refKey(hash))
+
+			continue
+		}
+
+		buf := make([]byte, 8)
+		binary.BigEndian.PutUint64(buf, count)
+		batch.Put(refKey(hash), buf)
+	}
+
+	for hash := range r.deleted {
+		batch.Delete(hash.Bytes())
+		cache.Remove(hash)
+	}
+}
+
+// children calls fn with the nodes referenced by the trie node
+func (r *refCounter) children(data []byte, account bool, fn func(types.Hash, bool) error) error {
+	p := parserPool.Get()
+	defer parserPool.Put(p)
+
+	v, err := p.Parse(data)
+	if err != nil {
+		return err
+	}
+
+	if v.Type() != fastrlp.TypeArray {
+		return fmt.Errorf("storage item should be an array")
+	}
+
+	n, err := decodeNode(v, r.storage)
+	if err != nil {
+		return err
+	}
+
+	return r.nodeChildren(n, account, fn)
+}
+
+func (r *refCounter) nodeChildren(node Node, account bool, fn func(types.Hash, bool) error) error {
+	switch n := node.(type) {
+	case nil:
+		return nil
+
+	case *ValueNode:
+		if n.hash {
+			return fn(types.BytesToHash(n.buf), account)
+		}
+
+		if !account {
+			// storage slot
+			return nil
+		}
+
+		var acct state.Account
+		if err := acct.UnmarshalRlp(n.buf); err != nil {
+			return err
+		}
+
+		if acct.Root == types.EmptyRootHash || acct.Root == types.ZeroHash {
+			return nil
+		}
+
+		return fn(acct.Root, false)
+
+	case *ShortNode:
+		return r.nodeChildren(n.child, account, fn)
+
+	case *FullNode:
+		for _, child := range n.children {
+			if err := r.nodeChildren(child, account, fn); err != nil {
+				return err
+			}
+		}
+
+		return r.nodeChildren(n.value, account, fn)
+
+	default:
+		return fmt.Errorf("unknown node type %T", n)
+	}
+}
@@ -0,0 +1,107 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// reachableNodes returns the trie nodes referenced by the roots
+func reachableNodes(t *testing.T, storage Storage, roots ...types.Hash) map[types.Hash]struct{} {
+	t.Helper()
+
+	refs := newRefCounter(storage)
+	nodes := map[types.Hash]struct{}{}
+
+	var visit func(hash types.Hash, account bool) error
+
+	visit = func(hash types.Hash, account bool) error {
+		if _, ok := nodes[hash]; ok {
+			return nil
+		}
+
+		nodes[hash] = struct{}{}
+
+		data, ok := storage.Get(hash.Bytes())
+		assert.True(t, ok, "node %s", hash)
+
+		return refs.children(data, account, visit)
+	}
+
+	for _, root := range roots {
+		assert.NoError(t, visit(root, true))
+	}
+
+	return nodes
+}
+
+// storedNodes returns the number of trie nodes in the memory storage
+func storedNodes(storage Storage) int {
+	stored := 0
+
+	for key := range storage.(*memStorage).db {
+		// hex encoded hashes, the other keys are prefixed
+		if len(key) == 2+2*types.HashLength {
+			stored++
+		}
+	}
+
+	return stored
+}
+
+func TestPrunedState(t *testing.T) {
+	storage := NewMemoryStorage()
+	p := NewPrunedState(storage, 2, hclog.NewNullLogger())
+
+	account := types.StringToAddress("1")
+	contract := types.StringToAddress("2")
+
+	roots := []types.Hash{}
+	parent := types.EmptyRootHash
+
+	for number := uint64(0); number < 6; number++ {
+		snap, err := p.NewSnapshotAt(parent)
+		assert.NoError(t, err)
+
+		txn := state.NewTxn(p, snap)
+		txn.AddBalance(account, big.NewInt(1))
+		txn.SetState(contract, types.StringToHash("1"), types.BytesToHash([]byte{byte(number + 1)}))
+		txn.SetState(contract, types.BytesToHash([]byte{byte(number + 2)}), types.StringToHash("1"))
+
+		assert.NoError(t, p.Reference(number, func() types.Hash {
+			_, root := txn.Commit(false)
+			parent = types.BytesToHash(root)
+
+			return parent
+		}))
+		assert.NoError(t, p.Prune(number))
+
+		roots = append(roots, parent)
+	}
+
+	// the state of the last two blocks is kept
+	for number, root := range roots {
+		snap, err := p.NewSnapshotAt(root)
+		if number < 4 {
+			assert.ErrorIs(t, err, state.ErrStatePruned)
+
+			continue
+		}
+
+		assert.NoError(t, err)
+
+		txn := state.NewTxn(p, snap)
+		assert.Equal(t, big.NewInt(int64(number+1)), txn.GetBalance(account))
+		assert.Equal(t, types.BytesToHash([]byte{byte(number + 1)}), txn.GetState(contract, types.StringToHash("1")))
+	}
+
+	// the nodes of the released roots are garbage collected
+	assert.Equal(t, len(reachableNodes(t, storage, roots[4:]...)), storedNodes(storage))
+
+	// the pruning picks up where it stopped
+	assert.Equal(t, uint64(4), NewPrunedState(storage, 2, hclog.NewNullLogger()).tail)
+}
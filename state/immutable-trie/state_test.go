@@ -10,6 +10,18 @@ func TestState(t *testing.T) {
 	state.TestState(t, buildPreState)
 }
 
+func TestParallelExecution(t *testing.T) {
+	state.TestParallelExecution(t, buildPreState)
+}
+
+func TestParallelExecution_CachedStorage(t *testing.T) {
+	state.TestParallelExecution(t, buildCachedPreState)
+}
+
+func BenchmarkParallelExecution(b *testing.B) {
+	state.BenchmarkParallelExecution(b, buildPreState)
+}
+
 func buildPreState(pre state.PreStates) (state.State, state.Snapshot) {
 	storage := NewMemoryStorage()
 	st := NewState(storage)
@@ -17,3 +29,11 @@ func buildPreState(pre state.PreStates) (state.State, state.Snapshot) {
 
 	return st, snap
 }
+
+func buildCachedPreState(pre state.PreStates) (state.State, state.Snapshot) {
+	storage := NewCachedStorage(NewMemoryStorage(), 1<<20, nil)
+	st := NewState(storage)
+	snap := st.NewSnapshot()
+
+	return st, snap
+}
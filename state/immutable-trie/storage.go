@@ -3,10 +3,11 @@ package itrie
 import (
 	"fmt"
 
+	"github.com/0xPolygon/polygon-edge/blockchain/storage"
+	"github.com/0xPolygon/polygon-edge/blockchain/storage/leveldb"
 	"github.com/0xPolygon/polygon-edge/helper/hex"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
-	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/umbracle/fastrlp"
 )
 
@@ -19,6 +20,7 @@ var (
 
 type Batch interface {
 	Put(k, v []byte)
+	Delete(k []byte)
 	Write()
 }
 
@@ -33,23 +35,26 @@ type Storage interface {
 	Close() error
 }
 
-// KVStorage is a k/v storage on memory using leveldb
+// KVStorage is the trie storage of a kv database
 type KVStorage struct {
-	db *leveldb.DB
+	db storage.KV
 }
 
-// KVBatch is a batch write for leveldb
+// KVBatch is a batch write of the kv database
 type KVBatch struct {
-	db    *leveldb.DB
-	batch *leveldb.Batch
+	batch storage.KVBatch
 }
 
 func (b *KVBatch) Put(k, v []byte) {
-	b.batch.Put(k, v)
+	b.batch.Set(k, v)
+}
+
+func (b *KVBatch) Delete(k []byte) {
+	b.batch.Delete(k)
 }
 
 func (b *KVBatch) Write() {
-	_ = b.db.Write(b.batch, nil)
+	_ = b.batch.Write()
 }
 
 func (kv *KVStorage) SetCode(hash types.Hash, code []byte) {
@@ -61,37 +66,38 @@ func (kv *KVStorage) GetCode(hash types.Hash) ([]byte, bool) {
 }
 
 func (kv *KVStorage) Batch() Batch {
-	return &KVBatch{db: kv.db, batch: &leveldb.Batch{}}
+	return &KVBatch{batch: kv.db.NewBatch()}
 }
 
 func (kv *KVStorage) Put(k, v []byte) {
-	_ = kv.db.Put(k, v, nil)
+	_ = kv.db.Set(k, v)
 }
 
 func (kv *KVStorage) Get(k []byte) ([]byte, bool) {
-	data, err := kv.db.Get(k, nil)
+	data, ok, err := kv.db.Get(k)
 	if err != nil {
-		if err.Error() == "leveldb: not found" {
-			return nil, false
-		} else {
-			panic(err)
-		}
+		panic(err)
 	}
 
-	return data, true
+	return data, ok
 }
 
 func (kv *KVStorage) Close() error {
 	return kv.db.Close()
 }
 
+// NewKVStorage creates the trie storage of the kv database
+func NewKVStorage(db storage.KV) Storage {
+	return &KVStorage{db}
+}
+
 func NewLevelDBStorage(path string, logger hclog.Logger) (Storage, error) {
-	db, err := leveldb.OpenFile(path, nil)
+	db, err := leveldb.NewLevelDBKV(path)
 	if err != nil {
 		return nil, err
 	}
 
-	return &KVStorage{db}, nil
+	return NewKVStorage(db), nil
 }
 
 type memStorage struct {
@@ -147,6 +153,10 @@ func (m *memBatch) Put(p, v []byte) {
 	(*m.db)[hex.EncodeToHex(p)] = buf
 }
 
+func (m *memBatch) Delete(p []byte) {
+	delete(*m.db, hex.EncodeToHex(p))
+}
+
 func (m *memBatch) Write() {
 }
 
@@ -157,6 +167,13 @@ func GetNode(root []byte, storage Storage) (Node, bool, error) {
 		return nil, false, nil
 	}
 
+	n, err := parseNode(data, storage)
+
+	return n, err == nil, err
+}
+
+// parseNode decodes the stored data of a node
+func parseNode(data []byte, storage Storage) (Node, error) {
 	// NOTE. We dont need to make copies of the bytes because the nodes
 	// take the reference from data itself which is a safe copy.
 	p := parserPool.Get()
@@ -164,16 +181,14 @@ func GetNode(root []byte, storage Storage) (Node, bool, error) {
 
 	v, err := p.Parse(data)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 
 	if v.Type() != fastrlp.TypeArray {
-		return nil, false, fmt.Errorf("storage item should be an array")
+		return nil, fmt.Errorf("storage item should be an array")
 	}
 
-	n, err := decodeNode(v, storage)
-
-	return n, err == nil, err
+	return decodeNode(v, storage)
 }
 
 func decodeNode(v *fastrlp.Value, s Storage) (Node, error) {
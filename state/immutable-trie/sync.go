@@ -0,0 +1,318 @@
+package itrie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/umbracle/fastrlp"
+)
+
+var (
+	ErrSyncHashMismatch = errors.New("hash of the synced item does not match")
+	ErrSyncNotRequested = errors.New("synced item was not requested")
+)
+
+var emptyCodeHash = hashit(nil)
+
+// SyncItem is an item of the state that is missing from the storage,
+// either a trie node or the code of a contract
+type SyncItem struct {
+	Hash types.Hash
+	Code bool
+
+	// account marks the nodes of the account trie, their leafs are accounts
+	account bool
+}
+
+// StateSync downloads the state of a root into the storage, item by item.
+// Every item is verified against the hash it was referenced by before it is written,
+// and the items already in the storage are walked locally, so an interrupted sync
+// picks up where it stopped
+type StateSync struct {
+	storage Storage
+
+	queue     []SyncItem
+	requested map[types.Hash]SyncItem
+
+	// the storage tries and the code shared by several accounts are only scheduled once
+	scheduled map[types.Hash]struct{}
+
+	synced uint64
+}
+
+// NewStateSync creates the sync of the state of the passed in root
+func NewStateSync(root types.Hash, storage Storage) *StateSync {
+	s := &StateSync{
+		storage:   storage,
+		requested: map[types.Hash]SyncItem{},
+		scheduled: map[types.Hash]struct{}{},
+	}
+
+	if root != types.EmptyRootHash {
+		s.queue = append(s.queue, SyncItem{Hash: root, account: true})
+	}
+
+	return s
+}
+
+// Done returns whether the whole state is in the storage
+func (s *StateSync) Done() bool {
+	return len(s.queue) == 0 && len(s.requested) == 0
+}
+
+// Synced returns the number of items written to the storage so far
+func (s *StateSync) Synced() uint64 {
+	return s.synced
+}
+
+// Pending returns the number of items known but not written to the storage yet
+func (s *StateSync) Pending() uint64 {
+	return uint64(len(s.queue) + len(s.requested))
+}
+
+// Missing returns up to max items to request, they are expected back through Process.
+// The items found in the storage are not returned
+func (s *StateSync) Missing(max int) ([]SyncItem, error) {
+	items := []SyncItem{}
+
+	for len(s.queue) > 0 && len(items) < max {
+		item := s.queue[len(s.queue)-1]
+		s.queue = s.queue[:len(s.queue)-1]
+
+		if _, ok := s.requested[item.Hash]; ok {
+			continue
+		}
+
+		if item.Code {
+			if _, ok := s.storage.GetCode(item.Hash); !ok {
+				s.requested[item.Hash] = item
+				items = append(items, item)
+			}
+
+			continue
+		}
+
+		data, ok := s.storage.Get(item.Hash.Bytes())
+		if !ok {
+			s.requested[item.Hash] = item
+			items = append(items, item)
+
+			continue
+		}
+
+		// the node was written before, its children might not
+		if err := s.schedule(item, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+// Retry puts back the requested items that were not served
+func (s *StateSync) Retry(items []SyncItem) {
+	for _, item := range items {
+		if _, ok := s.requested[item.Hash]; !ok {
+			continue
+		}
+
+		delete(s.requested, item.Hash)
+		s.queue = append(s.queue, item)
+	}
+}
+
+// Process verifies the data of a requested item, writes it
+// and schedules the items it references
+func (s *StateSync) Process(hash types.Hash, data []byte) error {
+	item, ok := s.requested[hash]
+	if !ok {
+		return ErrSyncNotRequested
+	}
+
+	if !bytes.Equal(hashit(data), hash.Bytes()) {
+		return fmt.Errorf("%w, %s", ErrSyncHashMismatch, hash)
+	}
+
+	if item.Code {
+		s.storage.SetCode(hash, data)
+	} else {
+		if err := s.schedule(item, data); err != nil {
+			return err
+		}
+
+		s.storage.Put(hash.Bytes(), data)
+	}
+
+	delete(s.requested, hash)
+
+	s.synced++
+
+	return nil
+}
+
+// schedule queues the children of the trie node
+func (s *StateSync) schedule(item SyncItem, data []byte) error {
+	p := parserPool.Get()
+	defer parserPool.Put(p)
+
+	v, err := p.Parse(data)
+	if err != nil {
+		return err
+	}
+
+	if v.Type() != fastrlp.TypeArray {
+		return fmt.Errorf("storage item should be an array")
+	}
+
+	n, err := decodeNode(v, s.storage)
+	if err != nil {
+		return err
+	}
+
+	return s.scheduleNode(n, item.account)
+}
+
+func (s *StateSync) scheduleNode(node Node, account bool) error {
+	switch n := node.(type) {
+	case nil:
+		return nil
+
+	case *ValueNode:
+		if n.hash {
+			// reference to a stored node
+			s.queue = append(s.queue, SyncItem{Hash: types.BytesToHash(n.buf), account: account})
+
+			return nil
+		}
+
+		if !account {
+			// storage slot
+			return nil
+		}
+
+		return s.scheduleAccount(n.buf)
+
+	case *ShortNode:
+		return s.scheduleNode(n.child, account)
+
+	case *FullNode:
+		for _, child := range n.children {
+			if err := s.scheduleNode(child, account); err != nil {
+				return err
+			}
+		}
+
+		return s.scheduleNode(n.value, account)
+
+	default:
+		return fmt.Errorf("unknown node type %T", n)
+	}
+}
+
+// scheduleAccount queues the storage trie and the code of the account
+func (s *StateSync) scheduleAccount(data []byte) error {
+	var account state.Account
+	if err := account.UnmarshalRlp(data); err != nil {
+		return err
+	}
+
+	if account.Root != types.EmptyRootHash && account.Root != types.ZeroHash {
+		if _, ok := s.scheduled[account.Root]; !ok {
+			s.scheduled[account.Root] = struct{}{}
+			s.queue = append(s.queue, SyncItem{Hash: account.Root})
+		}
+	}
+
+	codeHash := types.BytesToHash(account.CodeHash)
+	if len(account.CodeHash) != 0 && codeHash != types.ZeroHash && !bytes.Equal(account.CodeHash, emptyCodeHash) {
+		if _, ok := s.scheduled[codeHash]; !ok {
+			s.scheduled[codeHash] = struct{}{}
+			s.queue = append(s.queue, SyncItem{Hash: codeHash, Code: true})
+		}
+	}
+
+	return nil
+}
+
+// walkBatchSize is the number of items WalkState reads at once
+const walkBatchSize = 256
+
+// WalkState calls fn with every item of the state of the root, read from the storage.
+// The items are verified against their hashes, and the storage tries and the code
+// shared by several accounts are only visited once
+func WalkState(root types.Hash, storage Storage, fn func(item SyncItem, data []byte) error) error {
+	// the sync sees every item as missing, so all of them go through fn
+	sync := NewStateSync(root, &walkStorage{storage})
+
+	for !sync.Done() {
+		items, err := sync.Missing(walkBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			var (
+				data []byte
+				ok   bool
+			)
+
+			if item.Code {
+				data, ok = storage.GetCode(item.Hash)
+			} else {
+				data, ok = storage.Get(item.Hash.Bytes())
+			}
+
+			if !ok {
+				return fmt.Errorf("state item %s not found", item.Hash)
+			}
+
+			if err := sync.Process(item.Hash, data); err != nil {
+				return err
+			}
+
+			if err := fn(item, data); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// VerifyState checks that the whole state of the root is in the storage
+func VerifyState(root types.Hash, storage Storage) error {
+	sync := NewStateSync(root, storage)
+
+	for !sync.Done() {
+		items, err := sync.Missing(walkBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(items) != 0 {
+			return fmt.Errorf("state item %s is missing", items[0].Hash)
+		}
+	}
+
+	return nil
+}
+
+// walkStorage is a storage without any item which drops the writes
+type walkStorage struct {
+	Storage
+}
+
+func (w *walkStorage) Put(k, v []byte) {}
+
+func (w *walkStorage) Get(k []byte) ([]byte, bool) {
+	return nil, false
+}
+
+func (w *walkStorage) SetCode(hash types.Hash, code []byte) {}
+
+func (w *walkStorage) GetCode(hash types.Hash) ([]byte, bool) {
+	return nil, false
+}
@@ -0,0 +1,183 @@
+package itrie
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// buildSyncState commits a few accounts, with storage and code, to a new storage
+func buildSyncState(t *testing.T) (Storage, types.Hash) {
+	t.Helper()
+
+	storage := NewMemoryStorage()
+	snap := NewState(storage).NewSnapshot()
+
+	code := []byte{0x60, 0x01, 0x60, 0x02}
+	objs := []*state.Object{}
+
+	for i := 0; i < 50; i++ {
+		obj := &state.Object{
+			Address: types.BytesToAddress([]byte{byte(i + 1)}),
+			Balance: big.NewInt(int64(i)),
+			Nonce:   uint64(i),
+			Root:    types.EmptyRootHash,
+		}
+
+		if i%5 == 0 {
+			// contracts sharing the same code
+			obj.CodeHash = types.BytesToHash(hashit(code))
+			obj.DirtyCode = true
+			obj.Code = code
+
+			for j := 0; j < 20; j++ {
+				obj.Storage = append(obj.Storage, &state.StorageObject{
+					Key: types.BytesToHash([]byte{byte(j)}).Bytes(),
+					Val: types.BytesToHash([]byte{byte(i + j + 1)}).Bytes(),
+				})
+			}
+		} else {
+			obj.CodeHash = types.BytesToHash(emptyCodeHash)
+		}
+
+		objs = append(objs, obj)
+	}
+
+	_, root := snap.Commit(objs)
+
+	return storage, types.BytesToHash(root)
+}
+
+// serveSync serves the missing items out of the source storage, until the sync is done
+func serveSync(t *testing.T, sync *StateSync, source Storage) {
+	t.Helper()
+
+	for !sync.Done() {
+		items, err := sync.Missing(16)
+		assert.NoError(t, err)
+
+		for _, item := range items {
+			var (
+				data []byte
+				ok   bool
+			)
+
+			if item.Code {
+				data, ok = source.GetCode(item.Hash)
+			} else {
+				data, ok = source.Get(item.Hash.Bytes())
+			}
+
+			assert.True(t, ok)
+			assert.NoError(t, sync.Process(item.Hash, data))
+		}
+	}
+}
+
+func TestStateSync(t *testing.T) {
+	source, root := buildSyncState(t)
+
+	storage := NewMemoryStorage()
+	sync := NewStateSync(root, storage)
+
+	serveSync(t, sync, source)
+
+	// all the trie nodes and the code are copied
+	assert.Equal(t, len(source.(*memStorage).db), len(storage.(*memStorage).db))
+	assert.Equal(t, source.(*memStorage).code, storage.(*memStorage).code)
+
+	// the synced state can be opened
+	snap, err := NewState(storage).NewSnapshotAt(root)
+	assert.NoError(t, err)
+
+	_, ok := snap.Get(hashit(types.BytesToAddress([]byte{1}).Bytes()))
+	assert.True(t, ok)
+
+	// nothing is requested once the state is in the storage
+	sync = NewStateSync(root, storage)
+
+	items, err := sync.Missing(16)
+	assert.NoError(t, err)
+	assert.Len(t, items, 0)
+	assert.True(t, sync.Done())
+}
+
+func TestStateSync_Resume(t *testing.T) {
+	source, root := buildSyncState(t)
+
+	storage := NewMemoryStorage()
+	sync := NewStateSync(root, storage)
+
+	// the sync is interrupted after a few nodes
+	for i := 0; i < 3; i++ {
+		items, err := sync.Missing(4)
+		assert.NoError(t, err)
+
+		for _, item := range items {
+			data, _ := source.Get(item.Hash.Bytes())
+			assert.NoError(t, sync.Process(item.Hash, data))
+		}
+	}
+
+	// a new sync only requests the items missing from the storage
+	sync = NewStateSync(root, storage)
+	serveSync(t, sync, source)
+
+	assert.Equal(t, len(source.(*memStorage).db), len(storage.(*memStorage).db))
+}
+
+func TestStateSync_InvalidItem(t *testing.T) {
+	source, root := buildSyncState(t)
+
+	sync := NewStateSync(root, NewMemoryStorage())
+
+	items, err := sync.Missing(16)
+	assert.NoError(t, err)
+
+	if assert.Len(t, items, 1) {
+		data, _ := source.Get(root.Bytes())
+
+		// the data of another node is rejected
+		assert.ErrorIs(t, sync.Process(root, append([]byte{}, data[1:]...)), ErrSyncHashMismatch)
+		assert.ErrorIs(t, sync.Process(types.StringToHash("1"), data), ErrSyncNotRequested)
+
+		// the item is requested again once it is retried
+		sync.Retry(items)
+
+		items, err = sync.Missing(16)
+		assert.NoError(t, err)
+		assert.Equal(t, []SyncItem{{Hash: root, account: true}}, items)
+		assert.NoError(t, sync.Process(root, data))
+	}
+}
+
+func TestWalkState(t *testing.T) {
+	source, root := buildSyncState(t)
+	storage := NewMemoryStorage()
+
+	// the state is incomplete until all the walked items are written
+	assert.Error(t, VerifyState(root, storage))
+
+	err := WalkState(root, source, func(item SyncItem, data []byte) error {
+		if item.Code {
+			storage.SetCode(item.Hash, data)
+		} else {
+			storage.Put(item.Hash.Bytes(), data)
+		}
+
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, len(source.(*memStorage).db), len(storage.(*memStorage).db))
+	assert.Equal(t, source.(*memStorage).code, storage.(*memStorage).code)
+	assert.NoError(t, VerifyState(root, storage))
+
+	// the walk fails on the missing items
+	assert.Error(t, WalkState(types.StringToHash("1"), source, func(SyncItem, []byte) error {
+		return nil
+	}))
+}
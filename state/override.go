@@ -0,0 +1,54 @@
+package state
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// Override replaces the fields of an account for the duration of a call, the nil fields are kept.
+// State replaces the whole storage of the account, while StateDiff only replaces the given slots
+type Override struct {
+	Nonce     *uint64
+	Balance   *big.Int
+	Code      []byte
+	State     map[types.Hash]types.Hash
+	StateDiff map[types.Hash]types.Hash
+}
+
+// OverrideSet are the accounts overridden for a call
+type OverrideSet map[types.Address]*Override
+
+// ApplyOverride applies the overrides to the state of the transition
+func (t *Transition) ApplyOverride(overrides OverrideSet) error {
+	for addr, override := range overrides {
+		if override.State != nil && override.StateDiff != nil {
+			return fmt.Errorf("account %s has both state and stateDiff overrides", addr)
+		}
+	}
+
+	for addr, override := range overrides {
+		if override.Nonce != nil {
+			t.state.SetNonce(addr, *override.Nonce)
+		}
+
+		if override.Balance != nil {
+			t.state.SetBalance(addr, override.Balance)
+		}
+
+		if override.Code != nil {
+			t.state.SetCode(addr, override.Code)
+		}
+
+		if override.State != nil {
+			t.state.SetFullState(addr, override.State)
+		}
+
+		for key, value := range override.StateDiff {
+			t.state.SetState(addr, key, value)
+		}
+	}
+
+	return nil
+}
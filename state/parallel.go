@@ -0,0 +1,506 @@
+package state
+
+import (
+	"bytes"
+	"math/big"
+	"runtime"
+	"sync"
+
+	stateRuntime "github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// accessSet is a set of accounts and storage slots of the state
+type accessSet struct {
+	accounts map[types.Address]struct{}
+	slots    map[types.Address]map[types.Hash]struct{}
+
+	// resets are the accounts whose storage is dropped, as the created ones
+	resets map[types.Address]struct{}
+}
+
+func newAccessSet() *accessSet {
+	return &accessSet{
+		accounts: map[types.Address]struct{}{},
+		slots:    map[types.Address]map[types.Hash]struct{}{},
+		resets:   map[types.Address]struct{}{},
+	}
+}
+
+// addAccount adds the account, the calls on a nil set are ignored
+func (a *accessSet) addAccount(addr types.Address) {
+	if a == nil {
+		return
+	}
+
+	a.accounts[addr] = struct{}{}
+}
+
+// addSlot adds the storage slot of the account, the calls on a nil set are ignored
+func (a *accessSet) addSlot(addr types.Address, key types.Hash) {
+	if a == nil {
+		return
+	}
+
+	slots, ok := a.slots[addr]
+	if !ok {
+		slots = map[types.Hash]struct{}{}
+		a.slots[addr] = slots
+	}
+
+	slots[key] = struct{}{}
+}
+
+// addReset adds the account as one whose storage is dropped, the calls on a nil set are ignored
+func (a *accessSet) addReset(addr types.Address) {
+	if a == nil {
+		return
+	}
+
+	a.accounts[addr] = struct{}{}
+	a.resets[addr] = struct{}{}
+}
+
+func (a *accessSet) hasAccount(addr types.Address) bool {
+	_, ok := a.accounts[addr]
+
+	return ok
+}
+
+func (a *accessSet) hasSlot(addr types.Address, key types.Hash) bool {
+	_, ok := a.slots[addr][key]
+
+	return ok
+}
+
+// recordChanges adds the candidate accounts and slots which differ between the pre and the post state
+func (a *accessSet) recordChanges(candidates *accessSet, pre, post *Txn) {
+	for addr := range candidates.accounts {
+		before, _ := pre.getStateObject(addr)
+		after, _ := post.getStateObject(addr)
+
+		if _, reset := candidates.resets[addr]; reset || objectChanged(before, after) {
+			a.addAccount(addr)
+		}
+	}
+
+	for addr, slots := range candidates.slots {
+		for key := range slots {
+			if pre.GetState(addr, key) != post.GetState(addr, key) {
+				a.addSlot(addr, key)
+			}
+		}
+	}
+}
+
+// objectChanged checks if the account differs apart from its storage, nil if it doesn't exist
+func objectChanged(before, after *StateObject) bool {
+	if before == nil || after == nil {
+		return before != after
+	}
+
+	return before.Suicide != after.Suicide ||
+		before.Account.Nonce != after.Account.Nonce ||
+		before.Account.Balance.Cmp(after.Account.Balance) != 0 ||
+		before.Account.Root != after.Account.Root ||
+		!bytes.Equal(before.Account.CodeHash, after.Account.CodeHash)
+}
+
+// lockedState serializes the accesses to the state shared by the transactions executed in parallel.
+// The nodes of the tries are resolved in place as they are looked up, and the tries and the code
+// are read through the caches of the state, so none of them is accessed concurrently
+type lockedState struct {
+	State
+
+	lock *sync.Mutex
+}
+
+func newLockedState(state State) *lockedState {
+	return &lockedState{
+		State: state,
+		lock:  &sync.Mutex{},
+	}
+}
+
+func (s *lockedState) NewSnapshotAt(root types.Hash) (Snapshot, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snapshot, err := s.State.NewSnapshotAt(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.wrap(snapshot), nil
+}
+
+func (s *lockedState) NewSnapshot() Snapshot {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.wrap(s.State.NewSnapshot())
+}
+
+func (s *lockedState) GetCode(hash types.Hash) ([]byte, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.State.GetCode(hash)
+}
+
+// wrap returns the snapshot of the state accessed under the lock
+func (s *lockedState) wrap(snapshot Snapshot) Snapshot {
+	return &lockedSnapshot{
+		Snapshot: snapshot,
+		lock:     s.lock,
+	}
+}
+
+type lockedSnapshot struct {
+	Snapshot
+
+	lock *sync.Mutex
+}
+
+func (s *lockedSnapshot) Get(k []byte) ([]byte, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.Snapshot.Get(k)
+}
+
+func (s *lockedSnapshot) Commit(objs []*Object) (Snapshot, []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.Snapshot.Commit(objs)
+}
+
+// deferredFee is a fee of the transaction executed in parallel, credited once the transaction is merged
+type deferredFee struct {
+	addr   types.Address
+	amount *big.Int
+}
+
+// speculation is the optimistic execution of a transaction on the state of the parent block
+type speculation struct {
+	// state is the state of the parent block with the writes of the transaction,
+	// it records the accounts and the slots read and written by the transaction
+	state *Txn
+
+	result *stateRuntime.ExecutionResult
+	logs   []*types.Log
+	fees   []*deferredFee
+
+	// err is the error of the execution, the transaction is executed again serially to return it
+	err error
+}
+
+// newSpeculation returns the transition executing a transaction of the block in parallel
+func (t *Transition) newSpeculation(state *Txn) *Transition {
+	return &Transition{
+		logger:       t.logger,
+		auxState:     t.auxState,
+		block:        t.block,
+		r:            t.r,
+		config:       t.config,
+		state:        state,
+		getHash:      t.getHash,
+		ctx:          t.ctx,
+		gasPool:      t.block.Header.GasLimit,
+		baseFee:      t.baseFee,
+		feeCollector: t.feeCollector,
+		fees:         []*deferredFee{},
+		receipts:     []*types.Receipt{},
+	}
+}
+
+// speculate executes the transaction on the state of the parent block. The fees are deferred,
+// every transaction paying the coinbase would conflict with the ones before it otherwise
+func (t *Transition) speculate(txn *types.Transaction, state *Txn) *speculation {
+	spec := &speculation{
+		state: state,
+	}
+
+	exec := t.newSpeculation(state)
+
+	if err := exec.prepareTransaction(txn); err != nil {
+		spec.err = err
+
+		return spec
+	}
+
+	spec.result, spec.err = exec.Apply(txn.Copy())
+	if spec.err != nil {
+		return spec
+	}
+
+	spec.logs = state.Logs()
+	spec.fees = exec.fees
+
+	// the suicided and the touched empty accounts are deleted, as after the serial execution
+	state.CleanDeleteObjects(true)
+
+	return spec
+}
+
+// speculateAll executes the transactions of the block in parallel, each of them on the state of the parent block
+func (t *Transition) speculateAll(txs []*types.Transaction) []*speculation {
+	specs := make([]*speculation, len(txs))
+
+	state := newLockedState(t.auxState)
+	snapshot := state.wrap(t.state.snapshot)
+
+	indexes := make(chan int, len(txs))
+
+	for indx, txn := range txs {
+		if !txn.ExceedsBlockGasLimit(t.block.Header.GasLimit) {
+			indexes <- indx
+		}
+	}
+
+	close(indexes)
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(txs) {
+		workers = len(txs)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for indx := range indexes {
+				txn := newTxn(state, snapshot)
+				txn.reads = newAccessSet()
+				txn.writes = newAccessSet()
+
+				specs[indx] = t.speculate(txs[indx], txn)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return specs
+}
+
+// writeBlockTransactionsParallel writes the transactions of the block executed optimistically in parallel.
+// The writes of the transactions are merged in order, the ones reading the state written by a transaction
+// before them in the block are executed again serially, so the state is the one of the serial execution
+func (t *Transition) writeBlockTransactionsParallel(txs []*types.Transaction) error {
+	specs := t.speculateAll(txs)
+
+	// written are the accounts and the slots changed by the transactions merged so far
+	written := newAccessSet()
+	reexecuted := 0
+
+	for indx, txn := range txs {
+		if txn.ExceedsBlockGasLimit(t.block.Header.GasLimit) {
+			if err := t.WriteFailedReceipt(txn); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		pre := t.state.Copy()
+		t.state.writes = newAccessSet()
+
+		if spec := specs[indx]; t.canMerge(txn, spec, written) {
+			t.merge(txn, spec)
+		} else {
+			reexecuted++
+
+			if err := t.Write(txn); err != nil {
+				t.state.writes = nil
+
+				return err
+			}
+		}
+
+		written.recordChanges(t.state.writes, pre, t.state)
+		t.state.writes = nil
+	}
+
+	t.logger.Debug("executed the transactions in parallel", "txs", len(txs), "reexecuted", reexecuted)
+
+	return nil
+}
+
+// canMerge checks if the transaction executed on the state of the parent block
+// reads none of the accounts and the slots written by the merged transactions
+func (t *Transition) canMerge(txn *types.Transaction, spec *speculation, written *accessSet) bool {
+	if spec.err != nil || t.gasPool < txn.Gas {
+		return false
+	}
+
+	reads, writes := spec.state.reads, spec.state.writes
+
+	// the fees credited to the coinbase and the fee collector are not part of the speculation
+	if reads.hasAccount(t.ctx.Coinbase) || (t.feeCollector != nil && reads.hasAccount(*t.feeCollector)) {
+		return false
+	}
+
+	for addr := range reads.accounts {
+		if written.hasAccount(addr) {
+			return false
+		}
+	}
+
+	for addr, slots := range reads.slots {
+		for key := range slots {
+			if written.hasSlot(addr, key) {
+				return false
+			}
+		}
+	}
+
+	for addr := range writes.accounts {
+		// the slots of the speculation are merged over the storage it was executed on only
+		if !t.sameStorageRoot(spec, addr) {
+			return false
+		}
+
+		// the storage written by the merged transactions can't be replaced by the one of the speculation
+		if _, ok := written.slots[addr]; !ok {
+			continue
+		}
+
+		if _, reset := writes.resets[addr]; reset {
+			return false
+		}
+
+		if object, ok := spec.state.rawObject(addr); ok && (object.Deleted || object.Suicide) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sameStorageRoot checks if the account written by the speculation has the storage root of the account
+// in the merged state, the storage written by the merged transactions is not committed to the root yet.
+// The storage of the reset and the deleted accounts replaces the one of the merged state
+func (t *Transition) sameStorageRoot(spec *speculation, addr types.Address) bool {
+	if _, reset := spec.state.writes.resets[addr]; reset {
+		return true
+	}
+
+	object, ok := spec.state.rawObject(addr)
+	if !ok || object.Deleted || object.Suicide {
+		return true
+	}
+
+	current, ok := t.state.getStateObject(addr)
+	if !ok {
+		return true
+	}
+
+	return current.Account.Root == object.Account.Root
+}
+
+// merge writes the accounts and the slots of the speculation to the state,
+// credits the deferred fees and appends the receipt of the transaction
+func (t *Transition) merge(txn *types.Transaction, spec *speculation) {
+	for addr := range spec.state.writes.accounts {
+		object, ok := spec.state.rawObject(addr)
+		if !ok {
+			// the writes are reverted
+			continue
+		}
+
+		_, reset := spec.state.writes.resets[addr]
+		if reset {
+			t.state.writes.addReset(addr)
+		}
+
+		t.state.writes.addAccount(addr)
+
+		if object.Txn != nil {
+			object.Txn.Root().Walk(func(k []byte, _ interface{}) bool {
+				t.state.writes.addSlot(addr, types.BytesToHash(k))
+
+				return false
+			})
+		}
+
+		// the account is not changed by the merged transactions, it is the one of the parent block.
+		// The storage of the reset and the deleted accounts is dropped, canMerge checks none of it is written
+		if reset || object.Deleted || object.Suicide || !t.state.hasObject(addr) {
+			t.state.txn.Insert(addr.Bytes(), object)
+
+			continue
+		}
+
+		t.state.mergeObject(addr, object)
+	}
+
+	for _, fee := range spec.fees {
+		t.state.AddBalance(fee.addr, fee.amount)
+	}
+
+	t.state.CleanDeleteObjects(true)
+
+	t.gasPool -= spec.result.GasUsed
+	t.totalGas += spec.result.GasUsed
+
+	t.appendReceipt(txn, spec.result, spec.logs, nil)
+}
+
+// rawObject returns the object of the account written to the radix tree, the deleted ones included
+func (txn *Txn) rawObject(addr types.Address) (*StateObject, bool) {
+	val, ok := txn.txn.Get(addr.Bytes())
+	if !ok {
+		return nil, false
+	}
+
+	object, ok := val.(*StateObject)
+
+	return object, ok
+}
+
+// hasObject checks if the account is written to the radix tree
+func (txn *Txn) hasObject(addr types.Address) bool {
+	_, ok := txn.rawObject(addr)
+
+	return ok
+}
+
+// mergeObject writes the account of the object, and the slots written to its storage,
+// over the account in the radix tree. canMerge checks both storages have the same root
+func (txn *Txn) mergeObject(addr types.Address, object *StateObject) {
+	current, ok := txn.getStateObject(addr)
+	if !ok {
+		txn.txn.Insert(addr.Bytes(), object)
+
+		return
+	}
+
+	current.Account.Balance = new(big.Int).Set(object.Account.Balance)
+	current.Account.Nonce = object.Account.Nonce
+	current.Account.CodeHash = object.Account.CodeHash
+
+	if object.DirtyCode {
+		current.DirtyCode = true
+		current.Code = object.Code
+	}
+
+	if object.Txn != nil {
+		if current.Txn == nil {
+			current.Txn = object.Txn.CommitOnly().Txn()
+		} else {
+			object.Txn.Root().Walk(func(k []byte, v interface{}) bool {
+				current.Txn.Insert(k, v)
+
+				return false
+			})
+		}
+	}
+
+	txn.txn.Insert(addr.Bytes(), current)
+}
@@ -24,7 +24,10 @@ func newMockContract(value *big.Int, gas uint64, code []byte) *runtime.Contract
 
 // mockHost is a struct which meets the requirements of runtime.Host interface but throws panic in each methods
 // we don't test all opcodes in this test
-type mockHost struct{}
+type mockHost struct {
+	tracer  runtime.Tracer
+	aborted bool
+}
 
 func (m *mockHost) AccountExists(addr types.Address) bool {
 	panic("Not implemented in tests")
@@ -87,6 +90,61 @@ func (m *mockHost) GetNonce(addr types.Address) uint64 {
 	panic("Not implemented in tests")
 }
 
+func (m *mockHost) GetTracer() runtime.Tracer {
+	return m.tracer
+}
+
+func (m *mockHost) Aborted() bool {
+	return m.aborted
+}
+
+func (m *mockHost) AccessAddress(addr types.Address) bool {
+	panic("Not implemented in tests")
+}
+
+func (m *mockHost) AccessSlot(addr types.Address, key types.Hash) bool {
+	panic("Not implemented in tests")
+}
+
+// mockTracer records the steps of the execution
+type mockTracer struct {
+	steps []mockStep
+}
+
+type mockStep struct {
+	pc    uint64
+	op    string
+	gas   uint64
+	cost  uint64
+	stack int
+	err   error
+}
+
+func (m *mockTracer) CaptureState(pc uint64, op string, gas uint64, depth int, stack []*big.Int, memory []byte) {
+	m.steps = append(m.steps, mockStep{pc: pc, op: op, gas: gas, stack: len(stack)})
+}
+
+func (m *mockTracer) CaptureStateEnd(cost uint64, err error) {
+	m.steps[len(m.steps)-1].cost = cost
+	m.steps[len(m.steps)-1].err = err
+}
+
+func (m *mockTracer) CaptureStorage(addr types.Address, key types.Hash, value types.Hash) {}
+
+func (m *mockTracer) CaptureEnter(
+	typ runtime.CallType,
+	from types.Address,
+	to types.Address,
+	input []byte,
+	gas uint64,
+	value *big.Int,
+) {
+}
+
+func (m *mockTracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+func (m *mockTracer) CaptureEnd(result *runtime.ExecutionResult) {}
+
 func TestRun(t *testing.T) {
 	t.Parallel()
 
@@ -169,3 +227,56 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+func TestRun_Tracer(t *testing.T) {
+	t.Parallel()
+
+	tracer := &mockTracer{}
+	contract := newMockContract(big.NewInt(0), 5000, []byte{
+		PUSH1, 0x01, PUSH1, 0x02, ADD,
+		PUSH1, 0x00, MSTORE8,
+		PUSH1, 0x01, PUSH1, 0x00, RETURN,
+	})
+
+	res := NewEVM().Run(contract, &mockHost{tracer: tracer}, &chain.ForksInTime{})
+	assert.NoError(t, res.Err)
+
+	assert.Equal(t, []mockStep{
+		{pc: 0, op: "PUSH1", gas: 5000, cost: 3, stack: 0},
+		{pc: 2, op: "PUSH1", gas: 4997, cost: 3, stack: 1},
+		{pc: 4, op: "ADD", gas: 4994, cost: 3, stack: 2},
+		{pc: 5, op: "PUSH1", gas: 4991, cost: 3, stack: 1},
+		{pc: 7, op: "MSTORE8", gas: 4988, cost: 6, stack: 2},
+		{pc: 8, op: "PUSH1", gas: 4982, cost: 3, stack: 0},
+		{pc: 10, op: "PUSH1", gas: 4979, cost: 3, stack: 1},
+		{pc: 12, op: "RETURN", gas: 4976, cost: 0, stack: 2},
+	}, tracer.steps)
+
+	// the failed opcode is traced with its error
+	tracer = &mockTracer{}
+	res = NewEVM().Run(newMockContract(big.NewInt(0), 5000, []byte{ADD}), &mockHost{tracer: tracer}, &chain.ForksInTime{})
+
+	assert.ErrorIs(t, res.Err, errStackUnderflow)
+	assert.Equal(t, []mockStep{{pc: 0, op: "ADD", gas: 5000, err: errStackUnderflow}}, tracer.steps)
+}
+
+func TestRun_Aborted(t *testing.T) {
+	t.Parallel()
+
+	// an infinite loop, stopped by the host aborting the execution
+	loop := []byte{JUMPDEST, PUSH1, 0x00, JUMP}
+
+	res := NewEVM().Run(newMockContract(big.NewInt(0), 1000000, loop), &mockHost{aborted: true}, &chain.ForksInTime{})
+
+	assert.ErrorIs(t, res.Err, runtime.ErrExecutionAborted)
+	assert.Equal(t, uint64(0), res.GasLeft)
+
+	// the executions shorter than the stride are not checked
+	res = NewEVM().Run(newMockContract(big.NewInt(0), 5000, []byte{
+		PUSH1, 0x01, PUSH1, 0x00, MSTORE8,
+		PUSH1, 0x01, PUSH1, 0x00, RETURN,
+	}), &mockHost{aborted: true}, &chain.ForksInTime{})
+
+	assert.NoError(t, res.Err)
+	assert.Equal(t, []byte{0x01}, res.ReturnValue)
+}
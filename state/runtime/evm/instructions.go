@@ -461,11 +461,35 @@ func opMStore8(c *state) {
 
 // --- storage ---
 
+// the access costs of the accounts and the storage slots, as in EIP-2929
+const (
+	coldAccountAccessCost uint64 = 2600
+	coldSloadCost         uint64 = 2100
+	warmStorageReadCost   uint64 = 100
+)
+
+// accountAccessCost returns the cost of accessing the account past the Berlin fork,
+// the account is warm for the rest of the transaction
+func (c *state) accountAccessCost(addr types.Address) uint64 {
+	if c.host.AccessAddress(addr) {
+		return warmStorageReadCost
+	}
+
+	return coldAccountAccessCost
+}
+
 func opSload(c *state) {
 	loc := c.top()
+	key := bigToHash(loc)
 
 	var gas uint64
-	if c.config.Istanbul {
+	if c.config.Berlin {
+		// eip-2929
+		gas = warmStorageReadCost
+		if !c.host.AccessSlot(c.msg.Address, key) {
+			gas = coldSloadCost
+		}
+	} else if c.config.Istanbul {
 		// eip-1884
 		gas = 800
 	} else if c.config.EIP150 {
@@ -478,8 +502,12 @@ func opSload(c *state) {
 		return
 	}
 
-	val := c.host.GetStorage(c.msg.Address, bigToHash(loc))
+	val := c.host.GetStorage(c.msg.Address, key)
 	loc.SetBytes(val.Bytes())
+
+	if c.tracer != nil {
+		c.tracer.CaptureStorage(c.msg.Address, key, val)
+	}
 }
 
 func opSStore(c *state) {
@@ -500,12 +528,25 @@ func opSStore(c *state) {
 
 	legacyGasMetering := !c.config.Istanbul && (c.config.Petersburg || !c.config.Constantinople)
 
-	status := c.host.SetStorage(c.msg.Address, key, val, c.config)
 	cost := uint64(0)
 
+	// eip-2929, the cold slots are charged once and the costs of eip-2200 are reduced
+	coldCost := uint64(0)
+	if c.config.Berlin && !c.host.AccessSlot(c.msg.Address, key) {
+		coldCost = coldSloadCost
+	}
+
+	status := c.host.SetStorage(c.msg.Address, key, val, c.config)
+
+	if c.tracer != nil {
+		c.tracer.CaptureStorage(c.msg.Address, key, val)
+	}
+
 	switch status {
-	case runtime.StorageUnchanged:
-		if c.config.Istanbul {
+	case runtime.StorageUnchanged, runtime.StorageModifiedAgain:
+		if c.config.Berlin {
+			cost = warmStorageReadCost
+		} else if c.config.Istanbul {
 			// eip-2200
 			cost = 800
 		} else if legacyGasMetering {
@@ -514,27 +555,17 @@ func opSStore(c *state) {
 			cost = 200
 		}
 
-	case runtime.StorageModified:
+	case runtime.StorageModified, runtime.StorageDeleted:
 		cost = 5000
-
-	case runtime.StorageModifiedAgain:
-		if c.config.Istanbul {
-			// eip-2200
-			cost = 800
-		} else if legacyGasMetering {
-			cost = 5000
-		} else {
-			cost = 200
+		if c.config.Berlin {
+			cost -= coldSloadCost
 		}
 
 	case runtime.StorageAdded:
 		cost = 20000
-
-	case runtime.StorageDeleted:
-		cost = 5000
 	}
 
-	if !c.consumeGas(cost) {
+	if !c.consumeGas(cost + coldCost) {
 		return
 	}
 }
@@ -575,7 +606,9 @@ func opBalance(c *state) {
 	addr, _ := c.popAddr()
 
 	var gas uint64
-	if c.config.Istanbul {
+	if c.config.Berlin {
+		gas = c.accountAccessCost(addr)
+	} else if c.config.Istanbul {
 		// eip-1884
 		gas = 700
 	} else if c.config.EIP150 {
@@ -611,6 +644,16 @@ func opChainID(c *state) {
 	c.push1().SetUint64(uint64(c.host.GetTxContext().ChainID))
 }
 
+func opBaseFee(c *state) {
+	if !c.config.London {
+		c.exit(errOpCodeNotFound)
+
+		return
+	}
+
+	c.push1().SetBytes(c.host.GetTxContext().BaseFee.Bytes())
+}
+
 func opOrigin(c *state) {
 	c.push1().SetBytes(c.host.GetTxContext().Origin.Bytes())
 }
@@ -658,7 +701,9 @@ func opExtCodeSize(c *state) {
 	addr, _ := c.popAddr()
 
 	var gas uint64
-	if c.config.EIP150 {
+	if c.config.Berlin {
+		gas = c.accountAccessCost(addr)
+	} else if c.config.EIP150 {
 		gas = 700
 	} else {
 		gas = 20
@@ -693,7 +738,9 @@ func opExtCodeHash(c *state) {
 	address, _ := c.popAddr()
 
 	var gas uint64
-	if c.config.Istanbul {
+	if c.config.Berlin {
+		gas = c.accountAccessCost(address)
+	} else if c.config.Istanbul {
 		gas = 700
 	} else {
 		gas = 400
@@ -767,7 +814,9 @@ func opExtCodeCopy(c *state) {
 	}
 
 	var gas uint64
-	if c.config.EIP150 {
+	if c.config.Berlin {
+		gas = c.accountAccessCost(address)
+	} else if c.config.EIP150 {
 		gas = 700
 	} else {
 		gas = 20
@@ -927,6 +976,11 @@ func opSelfDestruct(c *state) {
 		}
 	}
 
+	// eip-2929, the beneficiary is not warm
+	if c.config.Berlin && !c.host.AccessAddress(address) {
+		gas += coldAccountAccessCost
+	}
+
 	if !c.consumeGas(gas) {
 		return
 	}
@@ -1078,6 +1132,9 @@ func opCreate(op OpCode) instruction {
 		}
 
 		contract.Type = runtime.Create
+		if op == CREATE2 {
+			contract.Type = runtime.Create2
+		}
 
 		// Correct call
 		result := c.host.Callx(contract, c.host)
@@ -1208,7 +1265,9 @@ func (c *state) buildCallContract(op OpCode) (*runtime.Contract, uint64, uint64,
 	}
 
 	var gasCost uint64
-	if c.config.EIP150 {
+	if c.config.Berlin {
+		gasCost = c.accountAccessCost(addr)
+	} else if c.config.EIP150 {
 		gasCost = 700
 	} else {
 		gasCost = 40
@@ -1231,6 +1290,13 @@ func (c *state) buildCallContract(op OpCode) (*runtime.Contract, uint64, uint64,
 		gasCost += 9000
 	}
 
+	// the gas left once the call is paid would underflow
+	if c.gas < gasCost {
+		c.exit(errOutOfGas)
+
+		return nil, 0, 0, nil
+	}
+
 	var gas uint64
 
 	ok = initialGas.IsUint64()
@@ -1346,6 +1412,19 @@ func (c *state) buildCreateContract(op OpCode) (*runtime.Contract, error) {
 		}
 	}
 
+	// eip-3860, the size of the init code is limited and its words are paid
+	if c.config.EIP3860 {
+		if len(input) > runtime.MaxInitCodeSize {
+			c.exit(runtime.ErrMaxInitCodeSizeExceeded)
+
+			return nil, nil
+		}
+
+		if !c.consumeGas(((uint64(len(input)) + 31) / 32) * runtime.InitCodeWordGas) {
+			return nil, nil
+		}
+	}
+
 	// Calculate and consume gas for the call
 	gas := c.gas
 
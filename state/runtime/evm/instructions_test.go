@@ -378,3 +378,213 @@ func TestCreate(t *testing.T) {
 		})
 	}
 }
+
+// mockHostForAccess keeps the warm addresses and slots of the executing transaction
+type mockHostForAccess struct {
+	mockHost
+	addresses map[types.Address]struct{}
+	slots     map[types.Hash]struct{}
+	status    runtime.StorageStatus
+}
+
+func newMockHostForAccess(status runtime.StorageStatus) *mockHostForAccess {
+	return &mockHostForAccess{
+		addresses: map[types.Address]struct{}{},
+		slots:     map[types.Hash]struct{}{},
+		status:    status,
+	}
+}
+
+func (m *mockHostForAccess) AccessAddress(addr types.Address) bool {
+	_, warm := m.addresses[addr]
+	m.addresses[addr] = struct{}{}
+
+	return warm
+}
+
+func (m *mockHostForAccess) AccessSlot(addr types.Address, key types.Hash) bool {
+	_, warm := m.slots[key]
+	m.slots[key] = struct{}{}
+
+	return warm
+}
+
+func (m *mockHostForAccess) GetStorage(types.Address, types.Hash) types.Hash {
+	return types.ZeroHash
+}
+
+func (m *mockHostForAccess) SetStorage(
+	types.Address,
+	types.Hash,
+	types.Hash,
+	*chain.ForksInTime,
+) runtime.StorageStatus {
+	return m.status
+}
+
+func (m *mockHostForAccess) GetBalance(types.Address) *big.Int {
+	return big.NewInt(0)
+}
+
+func TestBerlinAccessGas(t *testing.T) {
+	berlin := &chain.ForksInTime{
+		EIP150:         true,
+		Byzantium:      true,
+		Constantinople: true,
+		Petersburg:     true,
+		Istanbul:       true,
+		Berlin:         true,
+	}
+
+	// run executes the instruction twice with the same arguments, and returns the gas of each execution
+	run := func(host runtime.Host, instr instruction, args ...*big.Int) (uint64, uint64) {
+		s, closeFn := getState()
+		defer closeFn()
+
+		s.msg = newMockContract(big.NewInt(0), 100000, nil)
+		s.config = berlin
+		s.host = host
+
+		costs := make([]uint64, 2)
+
+		for indx := range costs {
+			for i := len(args) - 1; i >= 0; i-- {
+				s.push(new(big.Int).Set(args[i]))
+			}
+
+			s.gas = 100000
+			instr(s)
+			costs[indx] = 100000 - s.gas
+
+			assert.False(t, s.stop)
+
+			// the instructions reading a value leave it on the stack
+			s.sp = 0
+		}
+
+		return costs[0], costs[1]
+	}
+
+	t.Run("SLOAD", func(t *testing.T) {
+		cold, warm := run(newMockHostForAccess(runtime.StorageUnchanged), opSload, one)
+
+		assert.Equal(t, coldSloadCost, cold)
+		assert.Equal(t, warmStorageReadCost, warm)
+	})
+
+	t.Run("BALANCE", func(t *testing.T) {
+		cold, warm := run(newMockHostForAccess(runtime.StorageUnchanged), opBalance, one)
+
+		assert.Equal(t, coldAccountAccessCost, cold)
+		assert.Equal(t, warmStorageReadCost, warm)
+	})
+
+	t.Run("SSTORE modified", func(t *testing.T) {
+		cold, warm := run(newMockHostForAccess(runtime.StorageModified), opSStore, one, two)
+
+		// the cold slot is charged once, on top of the reduced cost of the write
+		assert.Equal(t, uint64(5000), cold)
+		assert.Equal(t, uint64(5000)-coldSloadCost, warm)
+	})
+
+	t.Run("SSTORE modified again", func(t *testing.T) {
+		cold, warm := run(newMockHostForAccess(runtime.StorageModifiedAgain), opSStore, one, two)
+
+		assert.Equal(t, coldSloadCost+warmStorageReadCost, cold)
+		assert.Equal(t, warmStorageReadCost, warm)
+	})
+
+	t.Run("SSTORE added", func(t *testing.T) {
+		cold, _ := run(newMockHostForAccess(runtime.StorageAdded), opSStore, one, two)
+
+		assert.Equal(t, uint64(20000)+coldSloadCost, cold)
+	})
+
+	t.Run("call with less gas than the cold account access", func(t *testing.T) {
+		s, closeFn := getState()
+		defer closeFn()
+
+		s.msg = newMockContract(big.NewInt(0), 1000, nil)
+		s.config = berlin
+		s.host = newMockHostForAccess(runtime.StorageUnchanged)
+		s.gas = 1000
+
+		// retSize, retOffset, inSize, inOffset, address, gas
+		for _, arg := range []int64{0, 0, 0, 0, 1, 1000} {
+			s.push(big.NewInt(arg))
+		}
+
+		opCall(STATICCALL)(s)
+
+		assert.True(t, s.stop)
+		assert.Equal(t, errOutOfGas, s.err)
+	})
+}
+
+type mockHostForBaseFee struct {
+	mockHost
+	baseFee uint64
+}
+
+func (m *mockHostForBaseFee) GetTxContext() runtime.TxContext {
+	return runtime.TxContext{
+		BaseFee: types.BytesToHash(new(big.Int).SetUint64(m.baseFee).Bytes()),
+	}
+}
+
+func TestBaseFee(t *testing.T) {
+	s, closeFn := getState()
+	defer closeFn()
+
+	s.config = &chain.ForksInTime{London: true}
+	s.host = &mockHostForBaseFee{baseFee: 1000}
+
+	opBaseFee(s)
+
+	assert.False(t, s.stop)
+	assert.Equal(t, big.NewInt(1000), s.pop())
+
+	// the opcode doesn't exist before the london fork
+	s.config = &chain.ForksInTime{}
+
+	opBaseFee(s)
+
+	assert.True(t, s.stop)
+	assert.Equal(t, errOpCodeNotFound, s.err)
+}
+
+func TestCreate_InitCodeSizeLimit(t *testing.T) {
+	testTable := []struct {
+		name   string
+		config *chain.ForksInTime
+		size   int64
+		err    error
+	}{
+		{"over the limit", &chain.ForksInTime{EIP150: true, EIP3860: true}, runtime.MaxInitCodeSize + 1,
+			runtime.ErrMaxInitCodeSizeExceeded},
+		{"at the limit", &chain.ForksInTime{EIP150: true, EIP3860: true}, runtime.MaxInitCodeSize, nil},
+		{"before the fork", &chain.ForksInTime{EIP150: true}, runtime.MaxInitCodeSize + 1, nil},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, closeFn := getState()
+			defer closeFn()
+
+			s.msg = newMockContract(big.NewInt(0), 10000000, nil)
+			s.config = testCase.config
+			s.host = &mockHostForCreate{
+				callxResult: &runtime.ExecutionResult{},
+			}
+			s.gas = 10000000
+
+			s.push(big.NewInt(testCase.size)) // length
+			s.push(big.NewInt(0))             // offset
+			s.push(big.NewInt(0))             // value
+
+			opCreate(CREATE)(s)
+
+			assert.Equal(t, testCase.err, s.err)
+		})
+	}
+}
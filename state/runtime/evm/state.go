@@ -47,6 +47,9 @@ var (
 	errReturnDataOutOfBounds = errors.New("return data out of bounds")
 )
 
+// abortCheckStride is the number of opcodes executed between the checks of the host aborting the execution
+const abortCheckStride = 1024
+
 // Instructions is the code of instructions
 
 type state struct {
@@ -57,6 +60,7 @@ type state struct {
 	host   runtime.Host
 	msg    *runtime.Contract // change with msg
 	config *chain.ForksInTime
+	tracer runtime.Tracer
 
 	// memory
 	memory      []byte
@@ -88,6 +92,7 @@ func (c *state) reset() {
 	c.lastGasCost = 0
 	c.stop = false
 	c.err = nil
+	c.tracer = nil
 
 	// reset bitmap
 	c.bitmap.reset()
@@ -213,41 +218,29 @@ func (c *state) Run() ([]byte, error) {
 	var vmerr error
 
 	codeSize := len(c.code)
-	for !c.stop {
+	for steps := 1; !c.stop; steps++ {
 		if c.ip >= codeSize {
 			c.halt()
 
 			break
 		}
 
-		op := OpCode(c.code[c.ip])
-
-		inst := dispatchTable[op]
-		if inst.inst == nil {
-			c.exit(errOpCodeNotFound)
+		if steps%abortCheckStride == 0 && c.host.Aborted() {
+			c.exit(runtime.ErrExecutionAborted)
 
 			break
 		}
-		// check if the depth of the stack is enough for the instruction
-		if c.sp < inst.stack {
-			c.exit(errStackUnderflow)
 
-			break
-		}
-		// consume the gas of the instruction
-		if !c.consumeGas(inst.gas) {
-			c.exit(errOutOfGas)
+		op := OpCode(c.code[c.ip])
 
-			break
+		var ok bool
+		if c.tracer == nil {
+			ok = c.step(op)
+		} else {
+			ok = c.traceStep(op)
 		}
 
-		// execute the instruction
-		inst.inst(c)
-
-		// check if stack size exceeds the max size
-		if c.sp > stackSize {
-			c.exit(errStackOverflow)
-
+		if !ok {
 			break
 		}
 		c.ip++
@@ -260,6 +253,53 @@ func (c *state) Run() ([]byte, error) {
 	return c.ret, vmerr
 }
 
+// step executes the opcode, and returns false if the execution stopped with an error
+func (c *state) step(op OpCode) bool {
+	inst := dispatchTable[op]
+	if inst.inst == nil {
+		c.exit(errOpCodeNotFound)
+
+		return false
+	}
+	// check if the depth of the stack is enough for the instruction
+	if c.sp < inst.stack {
+		c.exit(errStackUnderflow)
+
+		return false
+	}
+	// consume the gas of the instruction
+	if !c.consumeGas(inst.gas) {
+		c.exit(errOutOfGas)
+
+		return false
+	}
+
+	// execute the instruction
+	inst.inst(c)
+
+	// check if stack size exceeds the max size
+	if c.sp > stackSize {
+		c.exit(errStackOverflow)
+
+		return false
+	}
+
+	return true
+}
+
+// traceStep executes the opcode, and passes it to the tracer along with its gas cost
+func (c *state) traceStep(op OpCode) bool {
+	gas := c.gas
+
+	c.tracer.CaptureState(uint64(c.ip), op.String(), gas, c.msg.Depth, c.stack[:c.sp], c.memory)
+
+	ok := c.step(op)
+
+	c.tracer.CaptureStateEnd(gas-c.gas, c.err)
+
+	return ok
+}
+
 func (c *state) inStaticCall() bool {
 	return c.msg.Static
 }
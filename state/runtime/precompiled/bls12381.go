@@ -0,0 +1,301 @@
+package precompiled
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// The names of the BLS12-381 builtin contracts of EIP-2537, the chains declare them in the params
+// at their own addresses, usually behind the EIP2537 fork
+const (
+	BLS12381G1AddBuiltin   = "bls12381G1Add"
+	BLS12381G1MulBuiltin   = "bls12381G1Mul"
+	BLS12381G2AddBuiltin   = "bls12381G2Add"
+	BLS12381G2MulBuiltin   = "bls12381G2Mul"
+	BLS12381PairingBuiltin = "bls12381Pairing"
+)
+
+// The gas schedule of EIP-2537
+const (
+	bls12381G1AddGas          = 600
+	bls12381G1MulGas          = 12000
+	bls12381G2AddGas          = 4500
+	bls12381G2MulGas          = 55000
+	bls12381PairingBaseGas    = 115000
+	bls12381PairingPerPairGas = 23000
+)
+
+const (
+	// fieldElementSize is the size of an encoded element of the base field, padded to 64 bytes
+	fieldElementSize = 64
+
+	// fieldElementPadding is the number of zero bytes the encoded field elements start with
+	fieldElementPadding = fieldElementSize - 48
+
+	// g1EncodedSize is the size of an encoded G1 point, its x and y coordinates
+	g1EncodedSize = 2 * fieldElementSize
+
+	// g2EncodedSize is the size of an encoded G2 point, the c0 and c1 coefficients of its x and y coordinates
+	g2EncodedSize = 4 * fieldElementSize
+
+	// scalarSize is the size of the big endian scalar of the multiplications
+	scalarSize = 32
+
+	// pairSize is the size of the G1 and G2 points of a pair of the pairing check
+	pairSize = g1EncodedSize + g2EncodedSize
+)
+
+var (
+	errBLS12381InvalidInputLength  = errors.New("invalid input length")
+	errBLS12381InvalidFieldElement = errors.New("invalid field element padding")
+	errBLS12381InvalidPoint        = errors.New("invalid point")
+	errBLS12381NotInSubgroup       = errors.New("point is not in the subgroup")
+)
+
+func init() {
+	for name, builtin := range map[string]Builtin{
+		BLS12381G1AddBuiltin:   &bls12381G1Add{},
+		BLS12381G1MulBuiltin:   &bls12381G1Mul{},
+		BLS12381G2AddBuiltin:   &bls12381G2Add{},
+		BLS12381G2MulBuiltin:   &bls12381G2Mul{},
+		BLS12381PairingBuiltin: &bls12381Pairing{},
+	} {
+		if err := RegisterBuiltin(name, builtin); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// bls12381G1Add adds two G1 points, the points are not checked to be in the subgroup
+type bls12381G1Add struct{}
+
+func (b *bls12381G1Add) Gas(input []byte, config *chain.ForksInTime) uint64 {
+	return bls12381G1AddGas
+}
+
+func (b *bls12381G1Add) Run(input []byte) ([]byte, error) {
+	if len(input) != 2*g1EncodedSize {
+		return nil, errBLS12381InvalidInputLength
+	}
+
+	g := bls12381.NewG1()
+
+	p0, err := decodeBLS12381G1(g, input[:g1EncodedSize])
+	if err != nil {
+		return nil, err
+	}
+
+	p1, err := decodeBLS12381G1(g, input[g1EncodedSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeBLS12381G1(g, g.Add(g.New(), p0, p1)), nil
+}
+
+// bls12381G1Mul multiplies a G1 point of the subgroup by a scalar
+type bls12381G1Mul struct{}
+
+func (b *bls12381G1Mul) Gas(input []byte, config *chain.ForksInTime) uint64 {
+	return bls12381G1MulGas
+}
+
+func (b *bls12381G1Mul) Run(input []byte) ([]byte, error) {
+	if len(input) != g1EncodedSize+scalarSize {
+		return nil, errBLS12381InvalidInputLength
+	}
+
+	g := bls12381.NewG1()
+
+	p0, err := decodeBLS12381G1(g, input[:g1EncodedSize])
+	if err != nil {
+		return nil, err
+	}
+
+	if !g.InCorrectSubgroup(p0) {
+		return nil, errBLS12381NotInSubgroup
+	}
+
+	scalar := new(big.Int).SetBytes(input[g1EncodedSize:])
+
+	return encodeBLS12381G1(g, g.MulScalarBig(g.New(), p0, scalar)), nil
+}
+
+// bls12381G2Add adds two G2 points, the points are not checked to be in the subgroup
+type bls12381G2Add struct{}
+
+func (b *bls12381G2Add) Gas(input []byte, config *chain.ForksInTime) uint64 {
+	return bls12381G2AddGas
+}
+
+func (b *bls12381G2Add) Run(input []byte) ([]byte, error) {
+	if len(input) != 2*g2EncodedSize {
+		return nil, errBLS12381InvalidInputLength
+	}
+
+	g := bls12381.NewG2()
+
+	p0, err := decodeBLS12381G2(g, input[:g2EncodedSize])
+	if err != nil {
+		return nil, err
+	}
+
+	p1, err := decodeBLS12381G2(g, input[g2EncodedSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeBLS12381G2(g, g.Add(g.New(), p0, p1)), nil
+}
+
+// bls12381G2Mul multiplies a G2 point of the subgroup by a scalar
+type bls12381G2Mul struct{}
+
+func (b *bls12381G2Mul) Gas(input []byte, config *chain.ForksInTime) uint64 {
+	return bls12381G2MulGas
+}
+
+func (b *bls12381G2Mul) Run(input []byte) ([]byte, error) {
+	if len(input) != g2EncodedSize+scalarSize {
+		return nil, errBLS12381InvalidInputLength
+	}
+
+	g := bls12381.NewG2()
+
+	p0, err := decodeBLS12381G2(g, input[:g2EncodedSize])
+	if err != nil {
+		return nil, err
+	}
+
+	if !g.InCorrectSubgroup(p0) {
+		return nil, errBLS12381NotInSubgroup
+	}
+
+	scalar := new(big.Int).SetBytes(input[g2EncodedSize:])
+
+	return encodeBLS12381G2(g, g.MulScalarBig(g.New(), p0, scalar)), nil
+}
+
+// bls12381Pairing checks if the product of the pairings of the G1 and G2 points of the subgroups is the identity
+type bls12381Pairing struct{}
+
+func (b *bls12381Pairing) Gas(input []byte, config *chain.ForksInTime) uint64 {
+	return bls12381PairingBaseGas + bls12381PairingPerPairGas*uint64(len(input)/pairSize)
+}
+
+func (b *bls12381Pairing) Run(input []byte) ([]byte, error) {
+	if len(input) == 0 || len(input)%pairSize != 0 {
+		return nil, errBLS12381InvalidInputLength
+	}
+
+	g1, g2 := bls12381.NewG1(), bls12381.NewG2()
+	engine := bls12381.NewEngine()
+
+	for i := 0; i < len(input)/pairSize; i++ {
+		pair := input[i*pairSize : (i+1)*pairSize]
+
+		p1, err := decodeBLS12381G1(g1, pair[:g1EncodedSize])
+		if err != nil {
+			return nil, err
+		}
+
+		p2, err := decodeBLS12381G2(g2, pair[g1EncodedSize:])
+		if err != nil {
+			return nil, err
+		}
+
+		if !g1.InCorrectSubgroup(p1) || !g2.InCorrectSubgroup(p2) {
+			return nil, errBLS12381NotInSubgroup
+		}
+
+		engine.AddPair(p1, p2)
+	}
+
+	if engine.Check() {
+		return trueBytes, nil
+	}
+
+	return falseBytes, nil
+}
+
+// decodeBLS12381FieldElement strips the zero padding of the encoded field element
+func decodeBLS12381FieldElement(in []byte) ([]byte, error) {
+	for _, b := range in[:fieldElementPadding] {
+		if b != 0 {
+			return nil, errBLS12381InvalidFieldElement
+		}
+	}
+
+	return in[fieldElementPadding:fieldElementSize], nil
+}
+
+// decodeBLS12381G1 decodes the G1 point, checking it's on the curve. The point (0, 0) is the point at infinity
+func decodeBLS12381G1(g *bls12381.G1, in []byte) (*bls12381.PointG1, error) {
+	raw := make([]byte, 0, 96)
+
+	for i := 0; i < 2; i++ {
+		elem, err := decodeBLS12381FieldElement(in[i*fieldElementSize:])
+		if err != nil {
+			return nil, err
+		}
+
+		raw = append(raw, elem...)
+	}
+
+	p, err := g.FromBytes(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", errBLS12381InvalidPoint, err)
+	}
+
+	return p, nil
+}
+
+// decodeBLS12381G2 decodes the G2 point, checking it's on the curve. The point (0, 0) is the point at infinity.
+// The coefficients of the coordinates are encoded c0 first, the library reads them c1 first
+func decodeBLS12381G2(g *bls12381.G2, in []byte) (*bls12381.PointG2, error) {
+	raw := make([]byte, 0, 192)
+
+	for _, i := range []int{1, 0, 3, 2} {
+		elem, err := decodeBLS12381FieldElement(in[i*fieldElementSize:])
+		if err != nil {
+			return nil, err
+		}
+
+		raw = append(raw, elem...)
+	}
+
+	p, err := g.FromBytes(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", errBLS12381InvalidPoint, err)
+	}
+
+	return p, nil
+}
+
+// encodeBLS12381G1 encodes the G1 point with its coordinates padded
+func encodeBLS12381G1(g *bls12381.G1, p *bls12381.PointG1) []byte {
+	raw := g.ToBytes(p)
+	out := make([]byte, g1EncodedSize)
+
+	for i := 0; i < 2; i++ {
+		copy(out[i*fieldElementSize+fieldElementPadding:(i+1)*fieldElementSize], raw[i*48:(i+1)*48])
+	}
+
+	return out
+}
+
+// encodeBLS12381G2 encodes the G2 point with the coefficients of its coordinates padded, c0 first
+func encodeBLS12381G2(g *bls12381.G2, p *bls12381.PointG2) []byte {
+	raw := g.ToBytes(p)
+	out := make([]byte, g2EncodedSize)
+
+	for i, j := range []int{1, 0, 3, 2} {
+		copy(out[j*fieldElementSize+fieldElementPadding:(j+1)*fieldElementSize], raw[i*48:(i+1)*48])
+	}
+
+	return out
+}
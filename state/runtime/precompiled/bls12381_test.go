@@ -0,0 +1,211 @@
+package precompiled
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/helper/hex"
+	bls12381 "github.com/kilic/bls12-381"
+	"github.com/stretchr/testify/assert"
+)
+
+// g1OutsideSubgroup returns a point of the G1 curve which is not in the subgroup
+func g1OutsideSubgroup(t *testing.T) []byte {
+	t.Helper()
+
+	g := bls12381.NewG1()
+	modulus, _ := new(big.Int).SetString(
+		"1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+
+	for x := int64(1); ; x++ {
+		// y^2 = x^3 + 4
+		rhs := big.NewInt(x*x*x + 4)
+		if y := new(big.Int).ModSqrt(rhs, modulus); y != nil {
+			out := make([]byte, g1EncodedSize)
+			big.NewInt(x).FillBytes(out[:fieldElementSize])
+			y.FillBytes(out[fieldElementSize:])
+
+			p, err := decodeBLS12381G1(g, out)
+			assert.NoError(t, err)
+
+			if !g.InCorrectSubgroup(p) {
+				return out
+			}
+		}
+	}
+}
+
+// testG1 returns the encoded multiple of the G1 generator
+func testG1(k int64) []byte {
+	g := bls12381.NewG1()
+
+	return encodeBLS12381G1(g, g.MulScalarBig(g.New(), g.One(), big.NewInt(k)))
+}
+
+// testG2 returns the encoded multiple of the G2 generator
+func testG2(k int64) []byte {
+	g := bls12381.NewG2()
+
+	return encodeBLS12381G2(g, g.MulScalarBig(g.New(), g.One(), big.NewInt(k)))
+}
+
+func TestBLS12381(t *testing.T) {
+	scalar := make([]byte, scalarSize)
+	scalar[scalarSize-1] = 3
+
+	testPrecompiled(t, &builtinContract{&bls12381G1Add{}}, []precompiledTest{
+		{
+			Name:     "g1 add",
+			Input:    hexJoin(testG1(1), testG1(2)),
+			Expected: hexJoin(testG1(3)),
+		},
+		{
+			Name:     "g1 add infinity",
+			Input:    hexJoin(testG1(1), testG1(0)),
+			Expected: hexJoin(testG1(1)),
+		},
+	})
+
+	testPrecompiled(t, &builtinContract{&bls12381G1Mul{}}, []precompiledTest{
+		{
+			Name:     "g1 mul",
+			Input:    hexJoin(testG1(1), scalar),
+			Expected: hexJoin(testG1(3)),
+		},
+	})
+
+	testPrecompiled(t, &builtinContract{&bls12381G2Add{}}, []precompiledTest{
+		{
+			Name:     "g2 add",
+			Input:    hexJoin(testG2(1), testG2(-1)),
+			Expected: hexJoin(testG2(0)),
+		},
+	})
+
+	testPrecompiled(t, &builtinContract{&bls12381G2Mul{}}, []precompiledTest{
+		{
+			Name:     "g2 mul",
+			Input:    hexJoin(testG2(1), scalar),
+			Expected: hexJoin(testG2(3)),
+		},
+	})
+
+	testPrecompiled(t, &builtinContract{&bls12381Pairing{}}, []precompiledTest{
+		{
+			Name:     "pairing check",
+			Input:    hexJoin(testG1(3), testG2(1), testG1(-1), testG2(3)),
+			Expected: hexJoin(trueBytes),
+		},
+		{
+			Name:     "failed pairing check",
+			Input:    hexJoin(testG1(1), testG2(1)),
+			Expected: hexJoin(falseBytes),
+		},
+	})
+}
+
+// TestBLS12381_Vectors runs the vectors of EIP-2537 on the points built from the generators,
+// the expected results follow from the group laws and the bilinearity of the pairing
+func TestBLS12381_Vectors(t *testing.T) {
+	for file, builtin := range map[string]Builtin{
+		"blsG1Add.json":   &bls12381G1Add{},
+		"blsG1Mul.json":   &bls12381G1Mul{},
+		"blsG2Add.json":   &bls12381G2Add{},
+		"blsG2Mul.json":   &bls12381G2Mul{},
+		"blsPairing.json": &bls12381Pairing{},
+	} {
+		file, builtin := file, builtin
+
+		t.Run(file, func(t *testing.T) {
+			ReadTestCase(t, file, func(t *testing.T, c *TestCase) {
+				t.Helper()
+
+				out, err := builtin.Run(c.Input)
+				assert.NoError(t, err)
+				assert.Equal(t, c.Expected, out)
+				assert.Equal(t, c.Gas, builtin.Gas(c.Input, nil))
+			})
+		})
+	}
+}
+
+func TestBLS12381_Errors(t *testing.T) {
+	g1, g2 := testG1(1), testG2(1)
+	outside := g1OutsideSubgroup(t)
+
+	notOnCurve := make([]byte, g1EncodedSize)
+	notOnCurve[fieldElementSize-1] = 1
+	notOnCurve[g1EncodedSize-1] = 1
+
+	badPadding := testG1(1)
+	badPadding[0] = 1
+
+	testTable := []struct {
+		name        string
+		builtin     Builtin
+		input       []byte
+		expectedErr error
+	}{
+		{"g1 add length", &bls12381G1Add{}, g1, errBLS12381InvalidInputLength},
+		{"g1 add not on curve", &bls12381G1Add{}, hexJoinBytes(g1, notOnCurve), errBLS12381InvalidPoint},
+		{"g1 add field element padding", &bls12381G1Add{}, hexJoinBytes(g1, badPadding), errBLS12381InvalidFieldElement},
+		{"g1 add outside the subgroup", &bls12381G1Add{}, hexJoinBytes(g1, outside), nil},
+		{"g1 mul outside the subgroup", &bls12381G1Mul{}, hexJoinBytes(outside, make([]byte, 32)), errBLS12381NotInSubgroup},
+		{"g2 mul length", &bls12381G2Mul{}, g2, errBLS12381InvalidInputLength},
+		{"pairing empty input", &bls12381Pairing{}, nil, errBLS12381InvalidInputLength},
+		{"pairing outside the subgroup", &bls12381Pairing{}, hexJoinBytes(outside, g2), errBLS12381NotInSubgroup},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := testCase.builtin.Run(testCase.input)
+			assert.ErrorIs(t, err, testCase.expectedErr)
+		})
+	}
+}
+
+func hexJoin(parts ...[]byte) string {
+	return hex.EncodeToString(hexJoinBytes(parts...))
+}
+
+// hexJoinBytes concatenates the parts of the input
+func hexJoinBytes(parts ...[]byte) []byte {
+	var out []byte
+	for _, part := range parts {
+		out = append(out, part...)
+	}
+
+	return out
+}
+
+// BenchmarkBLS12381 measures the cost of the builtins
+func BenchmarkBLS12381(b *testing.B) {
+	g1, g2 := testG1(5), testG2(7)
+	scalar := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 8*scalarSize), big.NewInt(1)).Bytes()
+
+	cases := []struct {
+		name    string
+		builtin Builtin
+		input   []byte
+	}{
+		{"g1 add", &bls12381G1Add{}, hexJoinBytes(g1, g1)},
+		{"g1 mul", &bls12381G1Mul{}, hexJoinBytes(g1, scalar)},
+		{"g2 add", &bls12381G2Add{}, hexJoinBytes(g2, g2)},
+		{"g2 mul", &bls12381G2Mul{}, hexJoinBytes(g2, scalar)},
+		{"pairing 1", &bls12381Pairing{}, hexJoinBytes(g1, g2)},
+		{"pairing 4", &bls12381Pairing{}, bytes.Repeat(hexJoinBytes(g1, g2), 4)},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		b.Run(c.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := c.builtin.Run(c.input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package precompiled
 
 import (
 	"encoding/binary"
+	"strconv"
 
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
@@ -17,13 +18,17 @@ type contract interface {
 
 // Precompiled is the runtime for the precompiled contracts
 type Precompiled struct {
-	buf       []byte
 	contracts map[types.Address]contract
+
+	// forks are the names of the forks activating the contracts declared by the chain
+	forks map[types.Address]string
 }
 
 // NewPrecompiled creates a new runtime for the precompiled contracts
 func NewPrecompiled() *Precompiled {
-	p := &Precompiled{}
+	p := &Precompiled{
+		forks: map[types.Address]string{},
+	}
 	p.setupContracts()
 
 	return p
@@ -67,8 +72,30 @@ func (p *Precompiled) CanRun(c *runtime.Contract, _ runtime.Host, config *chain.
 		return false
 	}
 
+	if active, ok := p.isDeclaredActive(c.CodeAddress, config); ok {
+		return active
+	}
+
+	return isActive(c.CodeAddress, config)
+}
+
+// ActiveAddresses returns the addresses of the precompiled contracts available in the forks
+func ActiveAddresses(config *chain.ForksInTime) []types.Address {
+	addrs := make([]types.Address, 0, 9)
+
+	for i := 1; i <= 9; i++ {
+		if addr := types.StringToAddress(strconv.Itoa(i)); isActive(addr, config) {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	return addrs
+}
+
+// isActive checks if the precompiled contract at the address is available in the forks
+func isActive(addr types.Address, config *chain.ForksInTime) bool {
 	// byzantium precompiles
-	switch c.CodeAddress {
+	switch addr {
 	case five:
 		fallthrough
 	case six:
@@ -80,7 +107,7 @@ func (p *Precompiled) CanRun(c *runtime.Contract, _ runtime.Host, config *chain.
 	}
 
 	// istanbul precompiles
-	switch c.CodeAddress {
+	switch addr {
 	case nine:
 		return config.Istanbul
 	}
@@ -123,8 +150,6 @@ func (p *Precompiled) Run(c *runtime.Contract, _ runtime.Host, config *chain.For
 	return result
 }
 
-var zeroPadding = make([]byte, 64)
-
 func (p *Precompiled) leftPad(buf []byte, n int) []byte {
 	// TODO, avoid buffer allocation
 	l := len(buf)
@@ -138,42 +163,25 @@ func (p *Precompiled) leftPad(buf []byte, n int) []byte {
 	return tmp
 }
 
+// get returns the first size bytes of the input, right padded with zeros, and the rest of the input.
+// The bytes are copied to a buffer of the call, the transactions of a block may be executed in parallel
 func (p *Precompiled) get(input []byte, size int) ([]byte, []byte) {
-	p.buf = extendByteSlice(p.buf, size)
+	buf := make([]byte, size)
 	n := size
 
 	if len(input) < n {
 		n = len(input)
 	}
 
-	// copy the part from the input
-	copy(p.buf[0:], input[:n])
-
-	// copy empty values
-	if n < size {
-		rest := size - n
-		if rest < 64 {
-			copy(p.buf[n:], zeroPadding[0:size-n])
-		} else {
-			copy(p.buf[n:], make([]byte, rest))
-		}
-	}
+	// copy the part from the input, the rest is left empty
+	copy(buf[0:], input[:n])
 
-	return p.buf, input[n:]
+	return buf, input[n:]
 }
 
 func (p *Precompiled) getUint64(input []byte) (uint64, []byte) {
-	p.buf, input = p.get(input, 32)
-	num := binary.BigEndian.Uint64(p.buf[24:32])
+	buf, input := p.get(input, 32)
+	num := binary.BigEndian.Uint64(buf[24:32])
 
 	return num, input
 }
-
-func extendByteSlice(b []byte, needLen int) []byte {
-	b = b[:cap(b)]
-	if n := needLen - cap(b); n > 0 {
-		b = append(b, make([]byte, n)...)
-	}
-
-	return b[:needLen]
-}
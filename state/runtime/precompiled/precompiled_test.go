@@ -0,0 +1,44 @@
+package precompiled
+
+import (
+	"encoding/hex"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrecompiled_RunConcurrently(t *testing.T) {
+	p := NewPrecompiled()
+	config := &chain.ForksInTime{Byzantium: true, Istanbul: true}
+
+	var wg sync.WaitGroup
+
+	// the runs of the transactions executed in parallel don't share the input buffers
+	for i := 0; i < 8; i++ {
+		for _, c := range modExpTests {
+			c := c
+
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				input, _ := hex.DecodeString(c.Input)
+				result := p.Run(&runtime.Contract{
+					CodeAddress: five,
+					Input:       input,
+					Gas:         math.MaxUint64,
+				}, nil, config)
+
+				assert.NoError(t, result.Err)
+				assert.Equal(t, c.Expected, hex.EncodeToString(result.ReturnValue), c.Name)
+			}()
+		}
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,104 @@
+package precompiled
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var (
+	ErrBuiltinExists          = errors.New("builtin precompiled contract already registered")
+	ErrUnknownBuiltin         = errors.New("unknown builtin precompiled contract")
+	ErrPrecompileAddressInUse = errors.New("address already used by a precompiled contract")
+)
+
+// Builtin is the implementation of a precompiled contract the chains can declare in their params
+type Builtin interface {
+	// Gas returns the gas cost of the execution on the input
+	Gas(input []byte, config *chain.ForksInTime) uint64
+
+	// Run validates the input and executes the contract
+	Run(input []byte) ([]byte, error)
+}
+
+var (
+	builtinsLock sync.RWMutex
+	builtins     = map[string]Builtin{}
+)
+
+// RegisterBuiltin registers the builtin implementation with the name the chain params declare it with
+func RegisterBuiltin(name string, builtin Builtin) error {
+	builtinsLock.Lock()
+	defer builtinsLock.Unlock()
+
+	if _, ok := builtins[name]; ok {
+		return fmt.Errorf("%w: %s", ErrBuiltinExists, name)
+	}
+
+	builtins[name] = builtin
+
+	return nil
+}
+
+// getBuiltin returns the builtin implementation registered with the name
+func getBuiltin(name string) (Builtin, bool) {
+	builtinsLock.RLock()
+	defer builtinsLock.RUnlock()
+
+	builtin, ok := builtins[name]
+
+	return builtin, ok
+}
+
+// builtinContract runs a builtin implementation as a precompiled contract
+type builtinContract struct {
+	builtin Builtin
+}
+
+func (b *builtinContract) gas(input []byte, config *chain.ForksInTime) uint64 {
+	return b.builtin.Gas(input, config)
+}
+
+func (b *builtinContract) run(input []byte) ([]byte, error) {
+	return b.builtin.Run(input)
+}
+
+// RegisterPrecompiles declares the builtin contracts of the chain params at their addresses.
+// The declared contracts are not part of the addresses warmed by the access lists
+func (p *Precompiled) RegisterPrecompiles(precompiles []*chain.Precompile) error {
+	for _, precompile := range precompiles {
+		builtin, ok := getBuiltin(precompile.Builtin)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownBuiltin, precompile.Builtin)
+		}
+
+		if _, ok := p.contracts[precompile.Address]; ok {
+			return fmt.Errorf("%w: %s", ErrPrecompileAddressInUse, precompile.Address)
+		}
+
+		if _, err := (&chain.ForksInTime{}).Enabled(precompile.Fork); err != nil {
+			return err
+		}
+
+		p.contracts[precompile.Address] = &builtinContract{builtin}
+		p.forks[precompile.Address] = precompile.Fork
+	}
+
+	return nil
+}
+
+// isDeclaredActive checks if the contract declared at the address is active in the forks,
+// and if the address is the one of a declared contract
+func (p *Precompiled) isDeclaredActive(addr types.Address, config *chain.ForksInTime) (bool, bool) {
+	fork, ok := p.forks[addr]
+	if !ok {
+		return false, false
+	}
+
+	enabled, _ := config.Enabled(fork)
+
+	return enabled, true
+}
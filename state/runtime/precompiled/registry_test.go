@@ -0,0 +1,73 @@
+package precompiled
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var blsAddress = types.StringToAddress("0x100")
+
+func TestRegisterPrecompiles(t *testing.T) {
+	p := NewPrecompiled()
+
+	assert.NoError(t, p.RegisterPrecompiles([]*chain.Precompile{
+		{Address: blsAddress, Builtin: BLS12381G1AddBuiltin, Fork: "EIP2537"},
+	}))
+
+	contract := &runtime.Contract{
+		CodeAddress: blsAddress,
+		Input:       hexJoinBytes(testG1(1), testG1(1)),
+		Gas:         1000,
+	}
+
+	// the contract is only active once the fork is
+	assert.False(t, p.CanRun(contract, nil, &chain.ForksInTime{}))
+	assert.True(t, p.CanRun(contract, nil, &chain.ForksInTime{EIP2537: true}))
+
+	result := p.Run(contract, nil, &chain.ForksInTime{EIP2537: true})
+	assert.NoError(t, result.Err)
+	assert.Equal(t, uint64(400), result.GasLeft)
+	assert.Equal(t, testG1(2), result.ReturnValue)
+
+	// the standard contracts keep their activation
+	assert.True(t, p.CanRun(&runtime.Contract{CodeAddress: types.StringToAddress("1")}, nil, &chain.ForksInTime{}))
+}
+
+func TestRegisterPrecompiles_Errors(t *testing.T) {
+	testTable := []struct {
+		name        string
+		precompile  *chain.Precompile
+		expectedErr error
+	}{
+		{
+			"unknown builtin",
+			&chain.Precompile{Address: blsAddress, Builtin: "unknown"},
+			ErrUnknownBuiltin,
+		},
+		{
+			"address of a standard contract",
+			&chain.Precompile{Address: types.StringToAddress("1"), Builtin: BLS12381G1AddBuiltin},
+			ErrPrecompileAddressInUse,
+		},
+		{
+			"unknown fork",
+			&chain.Precompile{Address: blsAddress, Builtin: BLS12381G1AddBuiltin, Fork: "unknown"},
+			chain.ErrUnknownFork,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := NewPrecompiled().RegisterPrecompiles([]*chain.Precompile{testCase.precompile})
+			assert.ErrorIs(t, err, testCase.expectedErr)
+		})
+	}
+}
+
+func TestRegisterBuiltin_Exists(t *testing.T) {
+	assert.ErrorIs(t, RegisterBuiltin(BLS12381PairingBuiltin, &bls12381Pairing{}), ErrBuiltinExists)
+}
@@ -18,6 +18,7 @@ type TxContext struct {
 	GasLimit   int64
 	ChainID    int64
 	Difficulty types.Hash
+	BaseFee    types.Hash
 }
 
 // StorageStatus is the status of the storage access
@@ -69,6 +70,60 @@ type Host interface {
 	Callx(*Contract, Host) *ExecutionResult
 	Empty(addr types.Address) bool
 	GetNonce(addr types.Address) uint64
+	GetTracer() Tracer
+
+	// Aborted returns true if the execution of the transaction is aborted, such as on the timeout of a call.
+	// It is checked at a stride of the opcodes, the execution stops with ErrExecutionAborted
+	Aborted() bool
+
+	// AccessAddress adds the address to the access list of the transaction, as in EIP-2929.
+	// It returns true if the address was already accessed (warm)
+	AccessAddress(addr types.Address) bool
+	// AccessSlot adds the storage slot to the access list of the transaction, as in EIP-2929.
+	// It returns true if the slot was already accessed (warm)
+	AccessSlot(addr types.Address, key types.Hash) bool
+}
+
+// Tracer records the execution of a transaction step by step
+type Tracer interface {
+	// CaptureState is called before an opcode is executed,
+	// the stack and the memory are only valid for the duration of the call
+	CaptureState(pc uint64, op string, gas uint64, depth int, stack []*big.Int, memory []byte)
+
+	// CaptureStateEnd is called after the opcode is executed, with the gas it cost
+	// and the error it stopped the execution with, if any
+	CaptureStateEnd(cost uint64, err error)
+
+	// CaptureStorage is called when the opcode reads or writes a storage slot
+	CaptureStorage(addr types.Address, key types.Hash, value types.Hash)
+
+	// CaptureEnter is called when a call frame starts, the frame of the transaction included.
+	// The value is nil for the delegate and static calls
+	CaptureEnter(typ CallType, from types.Address, to types.Address, input []byte, gas uint64, value *big.Int)
+
+	// CaptureExit is called when the call frame ends, with its output and the gas it used
+	CaptureExit(output []byte, gasUsed uint64, err error)
+
+	// CaptureEnd is called with the result once the transaction is applied
+	CaptureEnd(result *ExecutionResult)
+}
+
+// StateReader reads the accounts of a state
+type StateReader interface {
+	Exist(addr types.Address) bool
+	GetBalance(addr types.Address) *big.Int
+	GetNonce(addr types.Address) uint64
+	GetCode(addr types.Address) []byte
+	GetState(addr types.Address, key types.Hash) types.Hash
+}
+
+// PrestateTracer is a tracer reading the state the transaction is applied on
+type PrestateTracer interface {
+	Tracer
+
+	// CapturePrestate is called before the transaction is applied,
+	// the state is not modified by the transaction
+	CapturePrestate(state StateReader)
 }
 
 // ExecutionResult includes all output after executing given evm
@@ -84,11 +139,30 @@ func (r *ExecutionResult) Succeeded() bool { return r.Err == nil }
 func (r *ExecutionResult) Failed() bool    { return r.Err != nil }
 func (r *ExecutionResult) Reverted() bool  { return errors.Is(r.Err, ErrExecutionReverted) }
 
-func (r *ExecutionResult) UpdateGasUsed(gasLimit uint64, refund uint64) {
+const (
+	// MaxCodeSize is the size limit of the deployed code, as in EIP-170
+	MaxCodeSize = 24576
+
+	// MaxInitCodeSize is the size limit of the init code, as in EIP-3860
+	MaxInitCodeSize = 2 * MaxCodeSize
+
+	// InitCodeWordGas is the gas paid per word of the init code, as in EIP-3860
+	InitCodeWordGas uint64 = 2
+)
+
+const (
+	// MaxRefundQuotient is the quotient of the gas used the refund can go up to
+	MaxRefundQuotient uint64 = 2
+
+	// MaxRefundQuotientLondon is the quotient of the gas used the refund can go up to after EIP-3529
+	MaxRefundQuotientLondon uint64 = 5
+)
+
+func (r *ExecutionResult) UpdateGasUsed(gasLimit uint64, refund uint64, refundQuotient uint64) {
 	r.GasUsed = gasLimit - r.GasLeft
 
-	// Refund can go up to half the gas used
-	if maxRefund := r.GasUsed / 2; refund > maxRefund {
+	// Refund can go up to the given fraction of the gas used
+	if maxRefund := r.GasUsed / refundQuotient; refund > maxRefund {
 		refund = maxRefund
 	}
 
@@ -103,10 +177,12 @@ var (
 	ErrNotEnoughFunds           = errors.New("not enough funds")
 	ErrInsufficientBalance      = errors.New("insufficient balance for transfer")
 	ErrMaxCodeSizeExceeded      = errors.New("evm: max code size exceeded")
+	ErrMaxInitCodeSizeExceeded  = errors.New("evm: max initcode size exceeded")
 	ErrContractAddressCollision = errors.New("contract address collision")
 	ErrDepth                    = errors.New("max call depth exceeded")
 	ErrExecutionReverted        = errors.New("execution was reverted")
 	ErrCodeStoreOutOfGas        = errors.New("contract creation code storage out of gas")
+	ErrExecutionAborted         = errors.New("execution aborted")
 )
 
 type CallType int
@@ -120,6 +196,25 @@ const (
 	Create2
 )
 
+func (t CallType) String() string {
+	switch t {
+	case Call:
+		return "CALL"
+	case CallCode:
+		return "CALLCODE"
+	case DelegateCall:
+		return "DELEGATECALL"
+	case StaticCall:
+		return "STATICCALL"
+	case Create:
+		return "CREATE"
+	case Create2:
+		return "CREATE2"
+	default:
+		panic("BUG: call type not found")
+	}
+}
+
 // Runtime can process contracts
 type Runtime interface {
 	Run(c *Contract, host Host, config *chain.ForksInTime) *ExecutionResult
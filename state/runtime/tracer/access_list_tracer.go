@@ -0,0 +1,162 @@
+package tracer
+
+import (
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var _ runtime.Tracer = &AccessListTracer{}
+
+// AccessListTracer records the addresses and the storage slots accessed by a transaction, as in EIP-2930.
+// The excluded addresses, the sender, the recipient and the precompiled contracts, are warm
+// in any transaction so they are only part of the list for their storage slots
+type AccessListTracer struct {
+	excluded map[types.Address]struct{}
+
+	// list is the access list in the order the addresses and the slots are accessed
+	list  types.AccessList
+	addrs map[types.Address]int
+	slots map[types.Address]map[types.Hash]struct{}
+}
+
+// NewAccessListTracer creates an access list tracer, starting from the access list of the transaction
+func NewAccessListTracer(accessList types.AccessList, excluded []types.Address) *AccessListTracer {
+	a := &AccessListTracer{
+		excluded: make(map[types.Address]struct{}, len(excluded)),
+		list:     types.AccessList{},
+		addrs:    map[types.Address]int{},
+		slots:    map[types.Address]map[types.Hash]struct{}{},
+	}
+
+	for _, addr := range excluded {
+		a.excluded[addr] = struct{}{}
+	}
+
+	for _, tuple := range accessList {
+		a.addAddress(tuple.Address)
+
+		for _, key := range tuple.StorageKeys {
+			a.addSlot(tuple.Address, key)
+		}
+	}
+
+	return a
+}
+
+// CaptureState implements the tracer interface, the accounts accessed by the opcodes are recorded
+func (a *AccessListTracer) CaptureState(pc uint64, op string, gas uint64, depth int, stack []*big.Int, memory []byte) {
+	// stack position of the address the opcode accesses, from the top
+	var pos int
+
+	switch op {
+	case "BALANCE", "EXTCODESIZE", "EXTCODECOPY", "EXTCODEHASH", "SELFDESTRUCT":
+		pos = 1
+	case "CALL", "CALLCODE", "DELEGATECALL", "STATICCALL":
+		pos = 2
+	default:
+		return
+	}
+
+	if len(stack) < pos {
+		return
+	}
+
+	a.addAddress(types.BytesToAddress(stack[len(stack)-pos].Bytes()))
+}
+
+// CaptureStateEnd implements the tracer interface
+func (a *AccessListTracer) CaptureStateEnd(cost uint64, err error) {}
+
+// CaptureStorage implements the tracer interface, the slots read and written are recorded
+func (a *AccessListTracer) CaptureStorage(addr types.Address, key types.Hash, value types.Hash) {
+	a.addSlot(addr, key)
+}
+
+// CaptureEnter implements the tracer interface
+func (a *AccessListTracer) CaptureEnter(
+	typ runtime.CallType,
+	from types.Address,
+	to types.Address,
+	input []byte,
+	gas uint64,
+	value *big.Int,
+) {
+}
+
+// CaptureExit implements the tracer interface
+func (a *AccessListTracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+// CaptureEnd implements the tracer interface
+func (a *AccessListTracer) CaptureEnd(result *runtime.ExecutionResult) {}
+
+// addAddress records the address, unless it is excluded
+func (a *AccessListTracer) addAddress(addr types.Address) {
+	if _, ok := a.excluded[addr]; ok {
+		return
+	}
+
+	a.tuple(addr)
+}
+
+// addSlot records the storage slot of the address, and the address even if it is excluded
+func (a *AccessListTracer) addSlot(addr types.Address, key types.Hash) {
+	if _, ok := a.slots[addr][key]; ok {
+		return
+	}
+
+	indx := a.tuple(addr)
+
+	if a.slots[addr] == nil {
+		a.slots[addr] = map[types.Hash]struct{}{}
+	}
+
+	a.slots[addr][key] = struct{}{}
+	a.list[indx].StorageKeys = append(a.list[indx].StorageKeys, key)
+}
+
+// tuple returns the index of the tuple of the address in the access list, it is added if missing
+func (a *AccessListTracer) tuple(addr types.Address) int {
+	if indx, ok := a.addrs[addr]; ok {
+		return indx
+	}
+
+	a.list = append(a.list, types.AccessTuple{Address: addr, StorageKeys: []types.Hash{}})
+	a.addrs[addr] = len(a.list) - 1
+
+	return len(a.list) - 1
+}
+
+// Result returns the access list of the transaction
+func (a *AccessListTracer) Result() types.AccessList {
+	return a.list.Copy()
+}
+
+// Equal checks if the addresses and the slots accessed are the ones of the access list
+func (a *AccessListTracer) Equal(accessList types.AccessList) bool {
+	other := NewAccessListTracer(accessList, nil)
+
+	if len(other.list) != len(a.list) {
+		return false
+	}
+
+	for addr := range a.addrs {
+		if _, ok := other.addrs[addr]; !ok {
+			return false
+		}
+
+		slots, otherSlots := a.slots[addr], other.slots[addr]
+		if len(slots) != len(otherSlots) {
+			return false
+		}
+
+		for key := range slots {
+			if _, ok := otherSlots[key]; !ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
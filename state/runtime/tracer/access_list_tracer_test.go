@@ -0,0 +1,55 @@
+package tracer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessListTracer(t *testing.T) {
+	t.Parallel()
+
+	sender := types.StringToAddress("1")
+	contract := types.StringToAddress("2")
+	other := types.StringToAddress("3")
+	listed := types.StringToAddress("4")
+
+	slot1 := types.StringToHash("1")
+	slot2 := types.StringToHash("2")
+
+	accessListTracer := NewAccessListTracer(
+		types.AccessList{{Address: listed, StorageKeys: []types.Hash{}}},
+		[]types.Address{sender, contract},
+	)
+
+	// the slots of the excluded addresses are recorded
+	accessListTracer.CaptureStorage(contract, slot1, types.ZeroHash)
+	accessListTracer.CaptureStorage(contract, slot2, types.ZeroHash)
+	accessListTracer.CaptureStorage(contract, slot1, types.ZeroHash)
+
+	accessListTracer.CaptureState(0, "BALANCE", 1000, 1, []*big.Int{new(big.Int).SetBytes(other.Bytes())}, nil)
+	accessListTracer.CaptureState(1, "BALANCE", 1000, 1, []*big.Int{new(big.Int).SetBytes(sender.Bytes())}, nil)
+	accessListTracer.CaptureState(2, "SLOAD", 1000, 1, []*big.Int{big.NewInt(1)}, nil)
+
+	expected := types.AccessList{
+		{Address: listed, StorageKeys: []types.Hash{}},
+		{Address: contract, StorageKeys: []types.Hash{slot1, slot2}},
+		{Address: other, StorageKeys: []types.Hash{}},
+	}
+
+	assert.Equal(t, expected, accessListTracer.Result())
+	assert.True(t, accessListTracer.Equal(expected))
+	assert.True(t, accessListTracer.Equal(types.AccessList{
+		{Address: other},
+		{Address: contract, StorageKeys: []types.Hash{slot2, slot1}},
+		{Address: listed},
+	}))
+	assert.False(t, accessListTracer.Equal(expected[:2]))
+	assert.False(t, accessListTracer.Equal(types.AccessList{
+		{Address: listed},
+		{Address: contract, StorageKeys: []types.Hash{slot1}},
+		{Address: other, StorageKeys: []types.Hash{slot2}},
+	}))
+}
@@ -0,0 +1,141 @@
+package tracer
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/umbracle/go-web3/abi"
+)
+
+var _ runtime.Tracer = &CallTracer{}
+
+// CallFrame is a call of the transaction, along with the calls it made
+type CallFrame struct {
+	Type  runtime.CallType
+	From  types.Address
+	Value *big.Int
+	Input []byte
+
+	// To is the called address, nil for the contract creations that failed
+	To *types.Address
+
+	Gas     uint64
+	GasUsed uint64
+	Output  []byte
+	Err     error
+
+	// RevertReason is the error string the frame reverted with, if any
+	RevertReason string
+
+	Calls []*CallFrame
+}
+
+// CallTracer records the tree of the calls made by a transaction
+type CallTracer struct {
+	root *CallFrame
+
+	// open are the frames being executed, the innermost last
+	open []*CallFrame
+}
+
+// NewCallTracer creates a call tracer
+func NewCallTracer() *CallTracer {
+	return &CallTracer{
+		open: []*CallFrame{},
+	}
+}
+
+// CaptureState implements the tracer interface
+func (c *CallTracer) CaptureState(pc uint64, op string, gas uint64, depth int, stack []*big.Int, memory []byte) {
+}
+
+// CaptureStateEnd implements the tracer interface
+func (c *CallTracer) CaptureStateEnd(cost uint64, err error) {}
+
+// CaptureStorage implements the tracer interface
+func (c *CallTracer) CaptureStorage(addr types.Address, key types.Hash, value types.Hash) {}
+
+// CaptureEnter implements the tracer interface
+func (c *CallTracer) CaptureEnter(
+	typ runtime.CallType,
+	from types.Address,
+	to types.Address,
+	input []byte,
+	gas uint64,
+	value *big.Int,
+) {
+	frame := &CallFrame{
+		Type:  typ,
+		From:  from,
+		To:    &to,
+		Input: append([]byte{}, input...),
+		Gas:   gas,
+		Calls: []*CallFrame{},
+	}
+
+	if value != nil {
+		frame.Value = new(big.Int).Set(value)
+	}
+
+	if len(c.open) == 0 {
+		c.root = frame
+	} else {
+		parent := c.open[len(c.open)-1]
+		parent.Calls = append(parent.Calls, frame)
+	}
+
+	c.open = append(c.open, frame)
+}
+
+// CaptureExit implements the tracer interface
+func (c *CallTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if len(c.open) == 0 {
+		return
+	}
+
+	frame := c.open[len(c.open)-1]
+	c.open = c.open[:len(c.open)-1]
+
+	frame.GasUsed = gasUsed
+
+	if err == nil {
+		frame.Output = append([]byte{}, output...)
+
+		return
+	}
+
+	frame.Err = err
+
+	if frame.Type == runtime.Create || frame.Type == runtime.Create2 {
+		frame.To = nil
+	}
+
+	// only the reverts return data on failure
+	if !errors.Is(err, runtime.ErrExecutionReverted) || len(output) == 0 {
+		return
+	}
+
+	frame.Output = append([]byte{}, output...)
+
+	if reason, err := abi.UnpackRevertError(output); err == nil {
+		frame.RevertReason = reason
+	}
+}
+
+// CaptureEnd implements the tracer interface, the transaction frame
+// is reported with the gas of the transaction, the intrinsic gas included
+func (c *CallTracer) CaptureEnd(result *runtime.ExecutionResult) {
+	if c.root == nil {
+		return
+	}
+
+	c.root.Gas = result.GasUsed + result.GasLeft
+	c.root.GasUsed = result.GasUsed
+}
+
+// Result returns the frame of the transaction, nil if it was not executed
+func (c *CallTracer) Result() *CallFrame {
+	return c.root
+}
@@ -0,0 +1,79 @@
+package tracer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// revertError returns the return data of a revert with an error string
+func revertError(reason string) []byte {
+	// Error(string) selector, offset and length of the string
+	data := []byte{0x08, 0xc3, 0x79, 0xa0}
+	data = append(data, types.BytesToHash([]byte{0x20}).Bytes()...)
+	data = append(data, types.BytesToHash([]byte{byte(len(reason))}).Bytes()...)
+
+	word := make([]byte, 32)
+	copy(word, reason)
+
+	return append(data, word...)
+}
+
+func TestCallTracer_NestedCalls(t *testing.T) {
+	t.Parallel()
+
+	from := types.StringToAddress("1")
+	to := types.StringToAddress("2")
+	created := types.StringToAddress("3")
+
+	callTracer := NewCallTracer()
+
+	callTracer.CaptureEnter(runtime.Call, from, to, []byte{0x1}, 1000, big.NewInt(1))
+	callTracer.CaptureEnter(runtime.DelegateCall, to, created, []byte{0x2}, 500, nil)
+	callTracer.CaptureExit([]byte{0x3}, 100, nil)
+	callTracer.CaptureEnter(runtime.Create, to, created, []byte{0x4}, 300, big.NewInt(0))
+	callTracer.CaptureExit([]byte{0x5}, 300, runtime.ErrOutOfGas)
+	callTracer.CaptureExit([]byte{0x6}, 800, nil)
+	callTracer.CaptureEnd(&runtime.ExecutionResult{GasUsed: 21800, GasLeft: 200})
+
+	root := callTracer.Result()
+	if !assert.Len(t, root.Calls, 2) {
+		t.FailNow()
+	}
+
+	// the frame of the transaction has the gas of the transaction
+	assert.Equal(t, uint64(22000), root.Gas)
+	assert.Equal(t, uint64(21800), root.GasUsed)
+	assert.Equal(t, []byte{0x6}, root.Output)
+	assert.Equal(t, big.NewInt(1), root.Value)
+
+	assert.Equal(t, runtime.DelegateCall, root.Calls[0].Type)
+	assert.Nil(t, root.Calls[0].Value)
+	assert.Equal(t, []byte{0x3}, root.Calls[0].Output)
+	assert.Equal(t, uint64(100), root.Calls[0].GasUsed)
+
+	// the failed creations have neither address nor output
+	assert.Nil(t, root.Calls[1].To)
+	assert.Nil(t, root.Calls[1].Output)
+	assert.ErrorIs(t, root.Calls[1].Err, runtime.ErrOutOfGas)
+}
+
+func TestCallTracer_Revert(t *testing.T) {
+	t.Parallel()
+
+	callTracer := NewCallTracer()
+
+	output := revertError("boom")
+
+	callTracer.CaptureEnter(runtime.Call, types.StringToAddress("1"), types.StringToAddress("2"), nil, 1000, nil)
+	callTracer.CaptureExit(output, 500, runtime.ErrExecutionReverted)
+
+	root := callTracer.Result()
+
+	assert.Equal(t, output, root.Output)
+	assert.Equal(t, "boom", root.RevertReason)
+	assert.ErrorIs(t, root.Err, runtime.ErrExecutionReverted)
+}
@@ -0,0 +1,127 @@
+package tracer
+
+import (
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var _ runtime.PrestateTracer = &PrestateTracer{}
+
+// Account is an account as it was before the transaction
+type Account struct {
+	Balance *big.Int
+	Nonce   uint64
+	Code    []byte
+
+	// Storage are the slots accessed by the transaction
+	Storage map[types.Hash]types.Hash
+}
+
+// PrestateTracer records the accounts and the storage slots accessed by a transaction,
+// with their values before the transaction was applied
+type PrestateTracer struct {
+	state    runtime.StateReader
+	accounts map[types.Address]*Account
+}
+
+// NewPrestateTracer creates a prestate tracer
+func NewPrestateTracer() *PrestateTracer {
+	return &PrestateTracer{
+		accounts: map[types.Address]*Account{},
+	}
+}
+
+// CapturePrestate implements the prestate tracer interface
+func (p *PrestateTracer) CapturePrestate(state runtime.StateReader) {
+	p.state = state
+}
+
+// CaptureState implements the tracer interface, the accounts read by the opcodes are recorded
+func (p *PrestateTracer) CaptureState(pc uint64, op string, gas uint64, depth int, stack []*big.Int, memory []byte) {
+	// stack position of the address the opcode accesses, from the top
+	var pos int
+
+	switch op {
+	case "BALANCE", "EXTCODESIZE", "EXTCODECOPY", "EXTCODEHASH", "SELFDESTRUCT":
+		pos = 1
+	case "CALL", "CALLCODE", "DELEGATECALL", "STATICCALL":
+		pos = 2
+	default:
+		return
+	}
+
+	if len(stack) < pos {
+		return
+	}
+
+	p.lookupAccount(types.BytesToAddress(stack[len(stack)-pos].Bytes()))
+}
+
+// CaptureStateEnd implements the tracer interface
+func (p *PrestateTracer) CaptureStateEnd(cost uint64, err error) {}
+
+// CaptureStorage implements the tracer interface
+func (p *PrestateTracer) CaptureStorage(addr types.Address, key types.Hash, value types.Hash) {
+	account := p.lookupAccount(addr)
+	if account == nil {
+		return
+	}
+
+	if _, ok := account.Storage[key]; !ok {
+		account.Storage[key] = p.state.GetState(addr, key)
+	}
+}
+
+// CaptureEnter implements the tracer interface, the contracts
+// created by the transaction are not part of the prestate
+func (p *PrestateTracer) CaptureEnter(
+	typ runtime.CallType,
+	from types.Address,
+	to types.Address,
+	input []byte,
+	gas uint64,
+	value *big.Int,
+) {
+	p.lookupAccount(from)
+
+	if (typ == runtime.Create || typ == runtime.Create2) && p.state != nil && !p.state.Exist(to) {
+		return
+	}
+
+	p.lookupAccount(to)
+}
+
+// CaptureExit implements the tracer interface
+func (p *PrestateTracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+// CaptureEnd implements the tracer interface
+func (p *PrestateTracer) CaptureEnd(result *runtime.ExecutionResult) {}
+
+// lookupAccount records the account the first time it is accessed
+func (p *PrestateTracer) lookupAccount(addr types.Address) *Account {
+	if account, ok := p.accounts[addr]; ok {
+		return account
+	}
+
+	if p.state == nil {
+		return nil
+	}
+
+	account := &Account{
+		Balance: p.state.GetBalance(addr),
+		Nonce:   p.state.GetNonce(addr),
+		Code:    p.state.GetCode(addr),
+		Storage: map[types.Hash]types.Hash{},
+	}
+
+	p.accounts[addr] = account
+
+	return account
+}
+
+// Result returns the accounts accessed by the transaction
+func (p *PrestateTracer) Result() map[types.Address]*Account {
+	return p.accounts
+}
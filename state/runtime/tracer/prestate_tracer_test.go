@@ -0,0 +1,107 @@
+package tracer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAccount struct {
+	balance int64
+	nonce   uint64
+	code    []byte
+	storage map[types.Hash]types.Hash
+}
+
+type mockState map[types.Address]*mockAccount
+
+func (m mockState) Exist(addr types.Address) bool {
+	_, ok := m[addr]
+
+	return ok
+}
+
+func (m mockState) GetBalance(addr types.Address) *big.Int {
+	if account, ok := m[addr]; ok {
+		return big.NewInt(account.balance)
+	}
+
+	return big.NewInt(0)
+}
+
+func (m mockState) GetNonce(addr types.Address) uint64 {
+	if account, ok := m[addr]; ok {
+		return account.nonce
+	}
+
+	return 0
+}
+
+func (m mockState) GetCode(addr types.Address) []byte {
+	if account, ok := m[addr]; ok {
+		return account.code
+	}
+
+	return nil
+}
+
+func (m mockState) GetState(addr types.Address, key types.Hash) types.Hash {
+	if account, ok := m[addr]; ok {
+		return account.storage[key]
+	}
+
+	return types.ZeroHash
+}
+
+func TestPrestateTracer(t *testing.T) {
+	t.Parallel()
+
+	sender := types.StringToAddress("1")
+	contract := types.StringToAddress("2")
+	other := types.StringToAddress("3")
+	created := types.StringToAddress("4")
+
+	slot := types.StringToHash("1")
+
+	state := mockState{
+		sender:   {balance: 100, nonce: 1},
+		contract: {code: []byte{0x1}, storage: map[types.Hash]types.Hash{slot: types.StringToHash("2")}},
+		other:    {balance: 10},
+	}
+
+	prestateTracer := NewPrestateTracer()
+	prestateTracer.CapturePrestate(state)
+
+	prestateTracer.CaptureEnter(runtime.Call, sender, contract, nil, 1000, big.NewInt(1))
+
+	// the slots have the value they had before the transaction
+	prestateTracer.CaptureStorage(contract, slot, types.StringToHash("3"))
+	prestateTracer.CaptureStorage(contract, slot, types.StringToHash("4"))
+
+	prestateTracer.CaptureState(0, "BALANCE", 1000, 1, []*big.Int{new(big.Int).SetBytes(other.Bytes())}, nil)
+	prestateTracer.CaptureStateEnd(400, nil)
+
+	prestateTracer.CaptureEnter(runtime.Create, contract, created, nil, 500, big.NewInt(0))
+	prestateTracer.CaptureExit(nil, 500, nil)
+	prestateTracer.CaptureExit(nil, 1000, nil)
+
+	assert.Equal(t, map[types.Address]*Account{
+		sender: {
+			Balance: big.NewInt(100),
+			Nonce:   1,
+			Storage: map[types.Hash]types.Hash{},
+		},
+		contract: {
+			Balance: big.NewInt(0),
+			Code:    []byte{0x1},
+			Storage: map[types.Hash]types.Hash{slot: types.StringToHash("2")},
+		},
+		other: {
+			Balance: big.NewInt(10),
+			Storage: map[types.Hash]types.Hash{},
+		},
+	}, prestateTracer.Result())
+}
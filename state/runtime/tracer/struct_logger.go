@@ -0,0 +1,145 @@
+package tracer
+
+import (
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var _ runtime.Tracer = &StructLogger{}
+
+// Config are the options of the struct logger, the captures
+// of the memory, the stack and the storage are the most expensive ones
+type Config struct {
+	DisableMemory  bool
+	DisableStack   bool
+	DisableStorage bool
+}
+
+// StructLog is the state of the EVM at an opcode of the execution
+type StructLog struct {
+	PC      uint64
+	Op      string
+	Gas     uint64
+	GasCost uint64
+	Depth   int
+	Err     error
+	Stack   []*big.Int
+	Memory  []byte
+
+	// Storage are the slots of the contract accessed so far, set on the opcodes accessing the storage
+	Storage map[types.Hash]types.Hash
+}
+
+// StructLogger records the state of the EVM at every opcode of a transaction
+type StructLogger struct {
+	config Config
+
+	logs []*StructLog
+
+	// open are the indexes of the logs whose opcode is being executed,
+	// the calls execute the opcodes of the called contract in between
+	open []int
+
+	storage map[types.Address]map[types.Hash]types.Hash
+	result  *runtime.ExecutionResult
+}
+
+// NewStructLogger creates a struct logger with the passed in options
+func NewStructLogger(config Config) *StructLogger {
+	return &StructLogger{
+		config:  config,
+		logs:    []*StructLog{},
+		open:    []int{},
+		storage: map[types.Address]map[types.Hash]types.Hash{},
+	}
+}
+
+// CaptureState implements the tracer interface
+func (l *StructLogger) CaptureState(pc uint64, op string, gas uint64, depth int, stack []*big.Int, memory []byte) {
+	log := &StructLog{
+		PC:    pc,
+		Op:    op,
+		Gas:   gas,
+		Depth: depth,
+	}
+
+	if !l.config.DisableStack {
+		log.Stack = make([]*big.Int, len(stack))
+		for i, val := range stack {
+			log.Stack[i] = new(big.Int).Set(val)
+		}
+	}
+
+	if !l.config.DisableMemory {
+		log.Memory = append([]byte{}, memory...)
+	}
+
+	l.open = append(l.open, len(l.logs))
+	l.logs = append(l.logs, log)
+}
+
+// CaptureStateEnd implements the tracer interface
+func (l *StructLogger) CaptureStateEnd(cost uint64, err error) {
+	if len(l.open) == 0 {
+		return
+	}
+
+	log := l.logs[l.open[len(l.open)-1]]
+	l.open = l.open[:len(l.open)-1]
+
+	log.GasCost = cost
+	log.Err = err
+}
+
+// CaptureStorage implements the tracer interface
+func (l *StructLogger) CaptureStorage(addr types.Address, key types.Hash, value types.Hash) {
+	if l.config.DisableStorage || len(l.open) == 0 {
+		return
+	}
+
+	storage, ok := l.storage[addr]
+	if !ok {
+		storage = map[types.Hash]types.Hash{}
+		l.storage[addr] = storage
+	}
+
+	storage[key] = value
+
+	log := l.logs[l.open[len(l.open)-1]]
+	log.Storage = make(map[types.Hash]types.Hash, len(storage))
+
+	for k, v := range storage {
+		log.Storage[k] = v
+	}
+}
+
+// CaptureEnter implements the tracer interface
+func (l *StructLogger) CaptureEnter(
+	typ runtime.CallType,
+	from types.Address,
+	to types.Address,
+	input []byte,
+	gas uint64,
+	value *big.Int,
+) {
+}
+
+// CaptureExit implements the tracer interface
+func (l *StructLogger) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+// CaptureEnd implements the tracer interface
+func (l *StructLogger) CaptureEnd(result *runtime.ExecutionResult) {
+	l.result = result
+}
+
+// StructLogs returns the recorded states, in the order the opcodes were executed
+func (l *StructLogger) StructLogs() []*StructLog {
+	return l.logs
+}
+
+// Result returns the result of the traced transaction, nil until it is applied
+func (l *StructLogger) Result() *runtime.ExecutionResult {
+	return l.result
+}
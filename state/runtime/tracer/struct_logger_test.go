@@ -0,0 +1,80 @@
+package tracer
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructLogger_NestedCall(t *testing.T) {
+	t.Parallel()
+
+	logger := NewStructLogger(Config{})
+	errCall := errors.New("call failed")
+
+	// the call opcode ends after the opcodes of the called contract
+	logger.CaptureState(0, "CALL", 1000, 1, []*big.Int{big.NewInt(1)}, []byte{0x1})
+	logger.CaptureState(0, "PUSH1", 500, 2, nil, nil)
+	logger.CaptureStateEnd(3, nil)
+	logger.CaptureState(2, "ADD", 497, 2, nil, nil)
+	logger.CaptureStateEnd(0, errCall)
+	logger.CaptureStateEnd(600, nil)
+
+	logs := logger.StructLogs()
+	if !assert.Len(t, logs, 3) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "CALL", logs[0].Op)
+	assert.Equal(t, uint64(600), logs[0].GasCost)
+	assert.Equal(t, []*big.Int{big.NewInt(1)}, logs[0].Stack)
+	assert.Equal(t, []byte{0x1}, logs[0].Memory)
+
+	assert.Equal(t, uint64(3), logs[1].GasCost)
+	assert.Equal(t, 2, logs[1].Depth)
+	assert.ErrorIs(t, logs[2].Err, errCall)
+}
+
+func TestStructLogger_Storage(t *testing.T) {
+	t.Parallel()
+
+	addr := types.StringToAddress("1")
+	key1, key2 := types.StringToHash("1"), types.StringToHash("2")
+	val := types.StringToHash("3")
+
+	logger := NewStructLogger(Config{})
+
+	logger.CaptureState(0, "SLOAD", 1000, 1, nil, nil)
+	logger.CaptureStorage(addr, key1, types.ZeroHash)
+	logger.CaptureStateEnd(800, nil)
+	logger.CaptureState(1, "SSTORE", 200, 1, nil, nil)
+	logger.CaptureStorage(addr, key2, val)
+	logger.CaptureStateEnd(100, nil)
+
+	// the accessed slots of the contract accumulate
+	logs := logger.StructLogs()
+	assert.Equal(t, map[types.Hash]types.Hash{key1: types.ZeroHash}, logs[0].Storage)
+	assert.Equal(t, map[types.Hash]types.Hash{key1: types.ZeroHash, key2: val}, logs[1].Storage)
+}
+
+func TestStructLogger_Disabled(t *testing.T) {
+	t.Parallel()
+
+	logger := NewStructLogger(Config{
+		DisableMemory:  true,
+		DisableStack:   true,
+		DisableStorage: true,
+	})
+
+	logger.CaptureState(0, "SSTORE", 1000, 1, []*big.Int{big.NewInt(1), big.NewInt(2)}, []byte{0x1})
+	logger.CaptureStorage(types.StringToAddress("1"), types.StringToHash("1"), types.StringToHash("2"))
+	logger.CaptureStateEnd(800, nil)
+
+	log := logger.StructLogs()[0]
+	assert.Nil(t, log.Stack)
+	assert.Nil(t, log.Memory)
+	assert.Nil(t, log.Storage)
+}
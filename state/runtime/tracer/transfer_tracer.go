@@ -0,0 +1,90 @@
+package tracer
+
+import (
+	"math/big"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+var _ runtime.Tracer = &TransferTracer{}
+
+// TransferTracer records the value transfers made by the call frames of a transaction,
+// the frame of the transaction excluded. It only follows the frames, so it is light
+// enough to run on every transaction of the imported blocks
+type TransferTracer struct {
+	transfers []*types.InternalTransaction
+
+	// open are the indexes of the first transfer of the frames being executed, the innermost last.
+	// The transfers from the index onwards are dropped if the frame fails
+	open []int
+}
+
+// NewTransferTracer creates a transfer tracer
+func NewTransferTracer() *TransferTracer {
+	return &TransferTracer{
+		transfers: []*types.InternalTransaction{},
+		open:      []int{},
+	}
+}
+
+// CaptureState implements the tracer interface
+func (c *TransferTracer) CaptureState(pc uint64, op string, gas uint64, depth int, stack []*big.Int, memory []byte) {
+}
+
+// CaptureStateEnd implements the tracer interface
+func (c *TransferTracer) CaptureStateEnd(cost uint64, err error) {}
+
+// CaptureStorage implements the tracer interface
+func (c *TransferTracer) CaptureStorage(addr types.Address, key types.Hash, value types.Hash) {}
+
+// CaptureEnter implements the tracer interface, only the calls and the creations move the value
+func (c *TransferTracer) CaptureEnter(
+	typ runtime.CallType,
+	from types.Address,
+	to types.Address,
+	input []byte,
+	gas uint64,
+	value *big.Int,
+) {
+	depth := len(c.open)
+	c.open = append(c.open, len(c.transfers))
+
+	if depth == 0 || value == nil || value.Sign() == 0 {
+		return
+	}
+
+	if typ != runtime.Call && typ != runtime.Create && typ != runtime.Create2 {
+		return
+	}
+
+	c.transfers = append(c.transfers, &types.InternalTransaction{
+		From:  from,
+		To:    to,
+		Value: new(big.Int).Set(value),
+		Depth: uint64(depth),
+	})
+}
+
+// CaptureExit implements the tracer interface
+func (c *TransferTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if len(c.open) == 0 {
+		return
+	}
+
+	first := c.open[len(c.open)-1]
+	c.open = c.open[:len(c.open)-1]
+
+	// the state of the failed frame is reverted, its transfers and the ones of its calls included
+	if err != nil {
+		c.transfers = c.transfers[:first]
+	}
+}
+
+// CaptureEnd implements the tracer interface
+func (c *TransferTracer) CaptureEnd(result *runtime.ExecutionResult) {}
+
+// Result returns the transfers of the transaction, they are not bound to the transaction hash
+func (c *TransferTracer) Result() []*types.InternalTransaction {
+	return c.transfers
+}
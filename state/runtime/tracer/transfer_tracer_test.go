@@ -0,0 +1,86 @@
+package tracer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferTracer_Transfers(t *testing.T) {
+	t.Parallel()
+
+	addr1 := types.StringToAddress("1")
+	addr2 := types.StringToAddress("2")
+	addr3 := types.StringToAddress("3")
+
+	transferTracer := NewTransferTracer()
+
+	// the transfer of the transaction is not internal
+	transferTracer.CaptureEnter(runtime.Call, addr1, addr2, nil, 1000, big.NewInt(1))
+	transferTracer.CaptureEnter(runtime.Call, addr2, addr3, nil, 500, big.NewInt(2))
+	transferTracer.CaptureEnter(runtime.Create, addr3, addr1, nil, 300, big.NewInt(3))
+	transferTracer.CaptureExit(nil, 100, nil)
+	transferTracer.CaptureExit(nil, 200, nil)
+
+	// the frames without value, and the ones keeping it, don't move any
+	transferTracer.CaptureEnter(runtime.Call, addr2, addr3, nil, 500, big.NewInt(0))
+	transferTracer.CaptureExit(nil, 100, nil)
+	transferTracer.CaptureEnter(runtime.CallCode, addr2, addr3, nil, 500, big.NewInt(4))
+	transferTracer.CaptureExit(nil, 100, nil)
+	transferTracer.CaptureEnter(runtime.DelegateCall, addr2, addr3, nil, 500, nil)
+	transferTracer.CaptureExit(nil, 100, nil)
+
+	transferTracer.CaptureExit(nil, 800, nil)
+
+	assert.Equal(t, []*types.InternalTransaction{
+		{From: addr2, To: addr3, Value: big.NewInt(2), Depth: 1},
+		{From: addr3, To: addr1, Value: big.NewInt(3), Depth: 2},
+	}, transferTracer.Result())
+}
+
+func TestTransferTracer_Revert(t *testing.T) {
+	t.Parallel()
+
+	addr1 := types.StringToAddress("1")
+	addr2 := types.StringToAddress("2")
+	addr3 := types.StringToAddress("3")
+
+	transferTracer := NewTransferTracer()
+
+	transferTracer.CaptureEnter(runtime.Call, addr1, addr2, nil, 1000, nil)
+	transferTracer.CaptureEnter(runtime.Call, addr2, addr3, nil, 500, big.NewInt(1))
+	transferTracer.CaptureExit(nil, 100, nil)
+
+	// the transfers of the failed frame and of its calls are reverted
+	transferTracer.CaptureEnter(runtime.Call, addr2, addr3, nil, 500, big.NewInt(2))
+	transferTracer.CaptureEnter(runtime.Call, addr3, addr1, nil, 300, big.NewInt(3))
+	transferTracer.CaptureExit(nil, 100, nil)
+	transferTracer.CaptureExit(nil, 500, runtime.ErrExecutionReverted)
+
+	transferTracer.CaptureExit(nil, 800, nil)
+
+	assert.Equal(t, []*types.InternalTransaction{
+		{From: addr2, To: addr3, Value: big.NewInt(1), Depth: 1},
+	}, transferTracer.Result())
+}
+
+func TestTransferTracer_FailedTransaction(t *testing.T) {
+	t.Parallel()
+
+	addr1 := types.StringToAddress("1")
+	addr2 := types.StringToAddress("2")
+	addr3 := types.StringToAddress("3")
+
+	transferTracer := NewTransferTracer()
+
+	// the failed transaction moves no value
+	transferTracer.CaptureEnter(runtime.Call, addr1, addr2, nil, 1000, nil)
+	transferTracer.CaptureEnter(runtime.Call, addr2, addr3, nil, 500, big.NewInt(1))
+	transferTracer.CaptureExit(nil, 100, nil)
+	transferTracer.CaptureExit(nil, 1000, runtime.ErrOutOfGas)
+
+	assert.Empty(t, transferTracer.Result())
+}
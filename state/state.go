@@ -2,6 +2,7 @@ package state
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -18,6 +19,21 @@ type State interface {
 	GetCode(hash types.Hash) ([]byte, bool)
 }
 
+var (
+	ErrStatePruned = errors.New("state not available, pruned")
+)
+
+// PrunedState is a state keeping the tries of the latest blocks only.
+// The state roots are referenced by the number of the block they were committed for,
+// and the tries are released once the block falls out of the retention window
+type PrunedState interface {
+	State
+
+	// Reference runs the commit of the state of the block, and references the committed root.
+	// The state is not pruned in between, so the nodes written by the commit are kept
+	Reference(number uint64, commit func() types.Hash) error
+}
+
 type Snapshot interface {
 	Get(k []byte) ([]byte, bool)
 	Commit(objs []*Object) (Snapshot, []byte)
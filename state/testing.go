@@ -4,8 +4,12 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
 	"github.com/0xPolygon/polygon-edge/types"
 )
 
@@ -337,3 +341,349 @@ func testChangeAccountBalanceToZero(t *testing.T, buildPreState buildPreState) {
 	txn = newTxn(state, snap)
 	assert.False(t, txn.Exist(addr1))
 }
+
+var (
+	parallelCoinbase = types.StringToAddress("c0ffee")
+	parallelToken    = types.StringToAddress("70ce")
+	parallelStore    = types.StringToAddress("5707e")
+	parallelSlots    = types.StringToAddress("5107")
+
+	// parallelTokenCode moves the amount at [32:64] of the input from the balance of the caller
+	// to the balance of the address at [0:32], the balances are stored at the slots of the addresses
+	parallelTokenCode = []byte{
+		0x60, 0x20, 0x35, 0x33, 0x54, 0x03, 0x33, 0x55, // sstore(caller, sload(caller) - amount)
+		0x60, 0x20, 0x35, 0x60, 0x00, 0x35, 0x54, 0x01, 0x60, 0x00, 0x35, 0x55, // sstore(to, sload(to) + amount)
+		0x00,
+	}
+
+	// parallelStoreCode writes 1 to the slot 0 if the input is not empty
+	parallelStoreCode = []byte{
+		0x36, 0x15, 0x60, 0x0b, 0x57, // jumpi(11, iszero(calldatasize))
+		0x60, 0x01, 0x60, 0x00, 0x55, 0x00, // sstore(0, 1), stop
+		0x5b, 0x00, // stop
+	}
+
+	// parallelSlotsCode writes 1 to the slot at [0:32] of the input
+	parallelSlotsCode = []byte{
+		0x60, 0x01, 0x60, 0x00, 0x35, 0x55, // sstore(calldataload(0), 1)
+		0x00,
+	}
+)
+
+func parallelAddress(prefix byte, i int) types.Address {
+	return types.BytesToAddress([]byte{prefix, byte(i >> 8), byte(i)})
+}
+
+func parallelSender(i int) types.Address {
+	return parallelAddress(0x1, i)
+}
+
+func parallelRecipient(i int) types.Address {
+	return parallelAddress(0x2, i)
+}
+
+// newParallelExecutor returns the executor and the genesis root of the funded senders,
+// which also hold a balance of the token
+func newParallelExecutor(buildPreState buildPreState, senders int) (*Executor, types.Hash) {
+	st, _ := buildPreState(nil)
+
+	executor := NewExecutor(&chain.Params{
+		Forks:   chain.AllForksEnabled,
+		ChainID: 100,
+	}, st, hclog.NewNullLogger())
+	executor.SetRuntime(precompiled.NewPrecompiled())
+	executor.SetRuntime(evm.NewEVM())
+	executor.GetHash = func(*types.Header) GetHashByNumber {
+		return func(uint64) types.Hash {
+			return types.ZeroHash
+		}
+	}
+
+	alloc := map[types.Address]*chain.GenesisAccount{
+		parallelToken: {
+			Code:    parallelTokenCode,
+			Storage: map[types.Hash]types.Hash{},
+			Balance: big.NewInt(0),
+		},
+		parallelStore: {
+			Code:    parallelStoreCode,
+			Balance: big.NewInt(0),
+		},
+		parallelSlots: {
+			Code: parallelSlotsCode,
+			Storage: map[types.Hash]types.Hash{
+				types.BytesToHash([]byte{0xff}): types.BytesToHash([]byte{0x1}),
+			},
+			Balance: big.NewInt(0),
+		},
+	}
+
+	for i := 0; i < senders; i++ {
+		alloc[parallelSender(i)] = &chain.GenesisAccount{
+			Balance: big.NewInt(1000000000),
+		}
+		alloc[parallelToken].Storage[types.BytesToHash(parallelSender(i).Bytes())] = types.BytesToHash([]byte{0xff})
+	}
+
+	return executor, executor.WriteGenesis(alloc)
+}
+
+func newParallelTransfer(from, to types.Address, nonce uint64) *types.Transaction {
+	return (&types.Transaction{
+		From:     from,
+		To:       &to,
+		Nonce:    nonce,
+		Value:    big.NewInt(1000),
+		Gas:      21000,
+		GasPrice: big.NewInt(1),
+	}).ComputeHash()
+}
+
+func newParallelTokenTransfer(from, to types.Address, nonce uint64) *types.Transaction {
+	token := parallelToken
+
+	input := make([]byte, 64)
+	copy(input[12:32], to.Bytes())
+	input[63] = 1
+
+	return (&types.Transaction{
+		From:     from,
+		To:       &token,
+		Nonce:    nonce,
+		Value:    big.NewInt(0),
+		Input:    input,
+		Gas:      100000,
+		GasPrice: big.NewInt(1),
+	}).ComputeHash()
+}
+
+func newParallelSlotWrite(from types.Address, slot int, nonce uint64) *types.Transaction {
+	return (&types.Transaction{
+		From:     from,
+		To:       &parallelSlots,
+		Nonce:    nonce,
+		Value:    big.NewInt(0),
+		Input:    types.BytesToHash([]byte{byte(slot >> 8), byte(slot)}).Bytes(),
+		Gas:      100000,
+		GasPrice: big.NewInt(1),
+	}).ComputeHash()
+}
+
+// parallelBlocks are the synthetic blocks of independent and conflicting transactions
+var parallelBlocks = map[string]func(n int) []*types.Transaction{
+	"independent transfers": func(n int) []*types.Transaction {
+		txs := make([]*types.Transaction, n)
+		for i := range txs {
+			txs[i] = newParallelTransfer(parallelSender(i), parallelRecipient(i), 0)
+		}
+
+		return txs
+	},
+	"transfers to the same recipient": func(n int) []*types.Transaction {
+		txs := make([]*types.Transaction, n)
+		for i := range txs {
+			txs[i] = newParallelTransfer(parallelSender(i), parallelRecipient(0), 0)
+		}
+
+		return txs
+	},
+	"transfers of the same sender": func(n int) []*types.Transaction {
+		txs := make([]*types.Transaction, n)
+		for i := range txs {
+			txs[i] = newParallelTransfer(parallelSender(0), parallelRecipient(i), uint64(i))
+		}
+
+		return txs
+	},
+	"transfers to the senders and the coinbase": func(n int) []*types.Transaction {
+		txs := make([]*types.Transaction, n)
+		for i := range txs {
+			to := parallelSender(i + 1)
+			if i%3 == 0 {
+				to = parallelCoinbase
+			}
+
+			txs[i] = newParallelTransfer(parallelSender(i), to, 0)
+		}
+
+		return txs
+	},
+	"independent token transfers": func(n int) []*types.Transaction {
+		txs := make([]*types.Transaction, n)
+		for i := range txs {
+			txs[i] = newParallelTokenTransfer(parallelSender(i), parallelRecipient(i), 0)
+		}
+
+		return txs
+	},
+	"token transfers to the same recipient": func(n int) []*types.Transaction {
+		txs := make([]*types.Transaction, n)
+		for i := range txs {
+			txs[i] = newParallelTokenTransfer(parallelSender(i), parallelRecipient(0), 0)
+		}
+
+		return txs
+	},
+	"storage write and transfer to the same contract": func(n int) []*types.Transaction {
+		txs := make([]*types.Transaction, n)
+		for i := range txs {
+			txs[i] = newParallelTransfer(parallelSender(i), parallelRecipient(i), 0)
+		}
+
+		// the transfer to the contract doesn't drop the slot written by the transaction before it
+		txs[0] = newParallelTransfer(parallelSender(0), parallelStore, 0)
+		txs[0].Value = big.NewInt(0)
+		txs[0].Input = []byte{0x1}
+		txs[0].Gas = 100000
+		txs[0].ComputeHash()
+
+		txs[1] = newParallelTransfer(parallelSender(1), parallelStore, 0)
+		txs[1].Gas = 100000
+		txs[1].ComputeHash()
+
+		return txs
+	},
+	"storage writes to the slots of the same contract": func(n int) []*types.Transaction {
+		txs := make([]*types.Transaction, n)
+		for i := range txs {
+			// the slots written before are merged along with the ones of the speculations
+			txs[i] = newParallelSlotWrite(parallelSender(i), i+1, 0)
+		}
+
+		return txs
+	},
+	"storage writes to the same slots of the same contract": func(n int) []*types.Transaction {
+		txs := make([]*types.Transaction, n)
+		for i := range txs {
+			// the odd transactions write the slot of the transaction before them, and the preset one
+			slot := i - i%2
+			if i%5 == 0 {
+				slot = 0xff
+			}
+
+			txs[i] = newParallelSlotWrite(parallelSender(i), slot, 0)
+		}
+
+		return txs
+	},
+	"token transfers along with storage writes and transfers": func(n int) []*types.Transaction {
+		txs := make([]*types.Transaction, n)
+		for i := range txs {
+			switch i % 3 {
+			case 0:
+				txs[i] = newParallelTokenTransfer(parallelSender(i), parallelSender(i+1), 0)
+			case 1:
+				txs[i] = newParallelSlotWrite(parallelSender(i), i, 0)
+			default:
+				txs[i] = newParallelTransfer(parallelSender(i), parallelSlots, 0)
+				txs[i].Gas = 100000
+				txs[i].ComputeHash()
+			}
+		}
+
+		return txs
+	},
+	"mixed transfers": func(n int) []*types.Transaction {
+		txs := make([]*types.Transaction, n)
+		for i := range txs {
+			switch i % 4 {
+			case 0:
+				txs[i] = newParallelTokenTransfer(parallelSender(i), parallelSender(i+1), 0)
+			case 1:
+				txs[i] = newParallelTransfer(parallelSender(i), parallelRecipient(i), 0)
+			case 2:
+				// out of gas in the contract call
+				txs[i] = newParallelTokenTransfer(parallelSender(i), parallelRecipient(i), 0)
+				txs[i].Gas = 22000
+				txs[i].ComputeHash()
+			default:
+				// exceeds the block gas limit
+				txs[i] = newParallelTransfer(parallelSender(i), parallelRecipient(i), 0)
+				txs[i].Gas = 1 << 40
+				txs[i].ComputeHash()
+			}
+		}
+
+		return txs
+	},
+}
+
+// processParallelBlock processes the block of the transactions on the genesis state
+func processParallelBlock(
+	executor *Executor,
+	root types.Hash,
+	txs []*types.Transaction,
+	parallel bool,
+) (*Transition, error) {
+	block := &types.Block{
+		Header: &types.Header{
+			Number:   1,
+			GasLimit: 50000000,
+		},
+		Transactions: make([]*types.Transaction, len(txs)),
+	}
+
+	for i, txn := range txs {
+		block.Transactions[i] = txn.Copy()
+	}
+
+	executor.ParallelExecution = parallel
+
+	return executor.ProcessBlock(root, block, parallelCoinbase)
+}
+
+// TestParallelExecution tests the state of the blocks executed in parallel is the one of the serial execution
+func TestParallelExecution(t *testing.T, buildPreState buildPreState) {
+	t.Helper()
+	t.Parallel()
+
+	for name, build := range parallelBlocks {
+		build := build
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			executor, root := newParallelExecutor(buildPreState, 101)
+			txs := build(100)
+
+			serial, err := processParallelBlock(executor, root, txs, false)
+			assert.NoError(t, err)
+
+			parallel, err := processParallelBlock(executor, root, txs, true)
+			assert.NoError(t, err)
+
+			_, serialRoot := serial.Commit()
+			_, parallelRoot := parallel.Commit()
+
+			assert.Equal(t, serialRoot, parallelRoot)
+			assert.Equal(t, serial.TotalGas(), parallel.TotalGas())
+			assert.Equal(t, serial.Receipts(), parallel.Receipts())
+		})
+	}
+}
+
+// BenchmarkParallelExecution benchmarks the serial and the parallel execution
+// of the blocks of independent and conflicting transactions
+func BenchmarkParallelExecution(b *testing.B, buildPreState buildPreState) {
+	b.Helper()
+
+	for _, name := range []string{"independent token transfers", "token transfers to the same recipient"} {
+		executor, root := newParallelExecutor(buildPreState, 500)
+		txs := parallelBlocks[name](500)
+
+		for _, parallel := range []bool{false, true} {
+			mode := "serial"
+			if parallel {
+				mode = "parallel"
+			}
+
+			b.Run(name+"/"+mode, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := processParallelBlock(executor, root, txs, parallel); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
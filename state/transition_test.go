@@ -4,7 +4,13 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
 	"github.com/0xPolygon/polygon-edge/state/runtime"
+	"github.com/0xPolygon/polygon-edge/state/runtime/evm"
+	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
+	"github.com/0xPolygon/polygon-edge/state/runtime/tracer"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
@@ -74,7 +80,7 @@ func TestSubGasLimitPrice(t *testing.T) {
 				GasPrice: big.NewInt(tt.gasPrice),
 			}
 
-			err := transition.subGasLimitPrice(msg)
+			err := transition.subGasLimitPrice(msg, msg.GasPrice)
 
 			assert.Equal(t, tt.expectedErr, err)
 			if err == nil {
@@ -159,3 +165,495 @@ func TestTransfer(t *testing.T) {
 		})
 	}
 }
+
+func TestTransition_CallTracer(t *testing.T) {
+	t.Parallel()
+
+	contract := types.StringToAddress("1234")
+	initCode := []byte{
+		0x60, 0x00, 0x60, 0x00, 0xfd, // REVERT(0, 0)
+	}
+
+	code := []byte{
+		// STATICCALL(gas, identity, 0, 0, 0, 0)
+		0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x04, 0x5a, 0xfa, 0x50,
+		// MSTORE(0, initCode)
+		0x64, 0x60, 0x00, 0x60, 0x00, 0xfd, 0x60, 0x00, 0x52,
+		// CREATE2(0, 27, 5, 0)
+		0x60, 0x00, 0x60, 0x05, 0x60, 0x1b, 0x60, 0x00, 0xf5, 0x50,
+		0x00, // STOP
+	}
+
+	transition := newTestTransition(map[types.Address]*PreState{
+		addr1: {
+			Balance: 1000,
+		},
+	})
+	transition.r = &Executor{
+		runtimes: []runtime.Runtime{precompiled.NewPrecompiled(), evm.NewEVM()},
+	}
+	transition.config = chain.AllForksEnabled.At(0)
+	transition.state.SetCode(contract, code)
+
+	callTracer := tracer.NewCallTracer()
+	transition.SetTracer(callTracer)
+
+	result := transition.Call2(addr1, contract, []byte{0x1}, big.NewInt(10), 1000000)
+	assert.NoError(t, result.Err)
+
+	root := callTracer.Result()
+	if !assert.NotNil(t, root) || !assert.Len(t, root.Calls, 2) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, runtime.Call, root.Type)
+	assert.Equal(t, addr1, root.From)
+	assert.Equal(t, contract, *root.To)
+	assert.Equal(t, big.NewInt(10), root.Value)
+	assert.Equal(t, []byte{0x1}, root.Input)
+	assert.Equal(t, uint64(1000000)-result.GasLeft, root.GasUsed)
+
+	// the precompiled contracts are called like any other contract
+	static := root.Calls[0]
+	assert.Equal(t, runtime.StaticCall, static.Type)
+	assert.Equal(t, contract, static.From)
+	assert.Equal(t, types.StringToAddress("4"), *static.To)
+	assert.Nil(t, static.Value)
+	assert.NoError(t, static.Err)
+
+	// the failed creations have no address
+	create := root.Calls[1]
+	assert.Equal(t, runtime.Create2, create.Type)
+	assert.Equal(t, contract, create.From)
+	assert.Nil(t, create.To)
+	assert.Equal(t, initCode, create.Input)
+	assert.ErrorIs(t, create.Err, runtime.ErrExecutionReverted)
+}
+
+func TestTransition_TransferTracer(t *testing.T) {
+	t.Parallel()
+
+	contract := types.StringToAddress("1234")
+	recipient := types.StringToAddress("5678")
+	reverter := types.StringToAddress("9abc")
+
+	code := []byte{
+		// CALL(gas, recipient, 5, 0, 0, 0, 0)
+		0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x05, 0x61, 0x56, 0x78, 0x5a, 0xf1, 0x50,
+		// CALL(gas, reverter, 3, 0, 0, 0, 0)
+		0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x03, 0x61, 0x9a, 0xbc, 0x5a, 0xf1, 0x50,
+		0x00, // STOP
+	}
+
+	transition := newTestTransition(map[types.Address]*PreState{
+		addr1: {
+			Balance: 1000,
+		},
+	})
+	transition.r = &Executor{
+		runtimes: []runtime.Runtime{precompiled.NewPrecompiled(), evm.NewEVM()},
+	}
+	transition.config = chain.AllForksEnabled.At(0)
+	transition.state.SetCode(contract, code)
+	transition.state.SetCode(reverter, []byte{0x60, 0x00, 0x60, 0x00, 0xfd}) // REVERT(0, 0)
+
+	transferTracer := tracer.NewTransferTracer()
+	transition.SetTracer(transferTracer)
+
+	result := transition.Call2(addr1, contract, nil, big.NewInt(10), 1000000)
+	assert.NoError(t, result.Err)
+
+	// the transfer of the reverted call is not recorded
+	assert.Equal(t, []*types.InternalTransaction{
+		{From: contract, To: recipient, Value: big.NewInt(5), Depth: 1},
+	}, transferTracer.Result())
+	assert.Equal(t, big.NewInt(5), transition.state.GetBalance(recipient))
+	assert.Zero(t, transition.state.GetBalance(reverter).Sign())
+}
+
+func TestTransition_AccessListGas(t *testing.T) {
+	t.Parallel()
+
+	contract := types.StringToAddress("1234")
+	slot := types.BytesToHash([]byte{0x1})
+	code := []byte{
+		0x60, 0x01, 0x54, 0x50, // POP(SLOAD(1))
+		0x60, 0x01, 0x54, 0x50, // POP(SLOAD(1))
+		0x00, // STOP
+	}
+
+	berlin := chain.AllForksEnabled.At(0)
+	berlin.Berlin = true
+
+	testTable := []struct {
+		name       string
+		config     chain.ForksInTime
+		accessList types.AccessList
+		gasUsed    uint64
+	}{
+		{
+			// 21000 + PUSH1 + SLOAD + POP + PUSH1 + SLOAD + POP
+			"before the berlin fork",
+			chain.AllForksEnabled.At(0),
+			nil,
+			21000 + 3 + 800 + 2 + 3 + 800 + 2,
+		},
+		{
+			// the first access of the slot is cold
+			"cold slot",
+			berlin,
+			nil,
+			21000 + 3 + 2100 + 2 + 3 + 100 + 2,
+		},
+		{
+			// the slot is paid upfront, with the address of the contract
+			"slot in the access list",
+			berlin,
+			types.AccessList{{Address: contract, StorageKeys: []types.Hash{slot}}},
+			21000 + 2400 + 1900 + 3 + 100 + 2 + 3 + 100 + 2,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			transition := newTestTransition(map[types.Address]*PreState{
+				addr1: {
+					Balance: 1000,
+				},
+			})
+			transition.r = &Executor{
+				runtimes: []runtime.Runtime{precompiled.NewPrecompiled(), evm.NewEVM()},
+			}
+			transition.config = testCase.config
+			transition.gasPool = 1000000
+			transition.state.SetCode(contract, code)
+
+			result, err := transition.Apply(&types.Transaction{
+				From:       addr1,
+				To:         &contract,
+				Gas:        100000,
+				GasPrice:   big.NewInt(0),
+				Value:      big.NewInt(0),
+				AccessList: testCase.accessList,
+			})
+			assert.NoError(t, err)
+			assert.NoError(t, result.Err)
+			assert.Equal(t, testCase.gasUsed, result.GasUsed)
+
+			// the access list is cleared once the transaction is written
+			assert.True(t, transition.state.SlotInAccessList(contract, slot) == testCase.config.Berlin)
+		})
+	}
+}
+
+func TestTransition_AccessListRevert(t *testing.T) {
+	t.Parallel()
+
+	contract := types.StringToAddress("1234")
+	slot := types.BytesToHash([]byte{0x1})
+
+	txn := newTestTxn(defaultPreState)
+	txn.AddAddressToAccessList(addr1)
+
+	snapshot := txn.Snapshot()
+	txn.AddSlotToAccessList(contract, slot)
+
+	assert.True(t, txn.AddressInAccessList(contract))
+	assert.True(t, txn.SlotInAccessList(contract, slot))
+
+	// the accesses of the reverted calls are reverted
+	txn.RevertToSnapshot(snapshot)
+
+	assert.True(t, txn.AddressInAccessList(addr1))
+	assert.False(t, txn.AddressInAccessList(contract))
+	assert.False(t, txn.SlotInAccessList(contract, slot))
+
+	txn.CleanDeleteObjects(true)
+	assert.False(t, txn.AddressInAccessList(addr1))
+}
+
+func TestTransition_LondonRefund(t *testing.T) {
+	t.Parallel()
+
+	contract := types.StringToAddress("1234")
+	slot := types.BytesToHash([]byte{0x1})
+
+	london := chain.AllForksEnabled.At(0)
+	london.London = true
+
+	testTable := []struct {
+		name    string
+		config  chain.ForksInTime
+		code    []byte
+		gasUsed uint64
+	}{
+		{
+			// 21000 + PUSH1 + PUSH1 + SSTORE, the refund of 15000 is capped to half of the gas used
+			"clear slot before the london fork",
+			chain.AllForksEnabled.At(0),
+			[]byte{0x60, 0x00, 0x60, 0x01, 0x55, 0x00},
+			26006 - 26006/2,
+		},
+		{
+			// the refund of 4800 is below a fifth of the gas used
+			"clear slot",
+			london,
+			[]byte{0x60, 0x00, 0x60, 0x01, 0x55, 0x00},
+			26006 - 4800,
+		},
+		{
+			// 21000 + PUSH1 + SELFDESTRUCT, the refund of 24000 is capped to half of the gas used
+			"selfdestruct before the london fork",
+			chain.AllForksEnabled.At(0),
+			[]byte{0x60, 0x00, 0xff},
+			26003 - 26003/2,
+		},
+		{
+			"selfdestruct",
+			london,
+			[]byte{0x60, 0x00, 0xff},
+			26003,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			transition := newTestTransition(map[types.Address]*PreState{
+				addr1: {
+					Balance: 1000,
+				},
+				contract: {
+					// the storage of the pre state is indexed by the hash of the slot
+					State: map[types.Hash]types.Hash{
+						types.BytesToHash(keccak.Keccak256(nil, slot.Bytes())): slot,
+					},
+				},
+			})
+			transition.r = &Executor{
+				runtimes: []runtime.Runtime{precompiled.NewPrecompiled(), evm.NewEVM()},
+			}
+			transition.config = testCase.config
+			transition.gasPool = 1000000
+			transition.state.SetCode(contract, testCase.code)
+
+			result, err := transition.Apply(&types.Transaction{
+				From:     addr1,
+				To:       &contract,
+				Gas:      100000,
+				GasPrice: big.NewInt(0),
+				Value:    big.NewInt(0),
+			})
+			assert.NoError(t, err)
+			assert.NoError(t, result.Err)
+			assert.Equal(t, testCase.gasUsed, result.GasUsed)
+		})
+	}
+}
+
+func TestTransition_FeeCollector(t *testing.T) {
+	t.Parallel()
+
+	coinbase := types.StringToAddress("c0")
+	collector := types.StringToAddress("fee")
+	receiver := types.StringToAddress("1234")
+
+	config := chain.AllForksEnabled.At(0)
+	config.EIP1559 = true
+
+	testTable := []struct {
+		name         string
+		feeCollector *types.Address
+		collected    int64 // The base fee per gas credited to the collector
+	}{
+		{"burn the base fee", nil, 0},
+		{"credit the base fee to the collector", &collector, 7},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			transition := newTestTransition(map[types.Address]*PreState{
+				addr1: {
+					Balance: 1000000,
+				},
+			})
+			transition.r = &Executor{
+				runtimes: []runtime.Runtime{precompiled.NewPrecompiled(), evm.NewEVM()},
+			}
+			transition.config = config
+			transition.gasPool = 1000000
+			transition.ctx.Coinbase = coinbase
+			transition.baseFee = big.NewInt(7)
+			transition.feeCollector = testCase.feeCollector
+
+			result, err := transition.Apply(&types.Transaction{
+				From:     addr1,
+				To:       &receiver,
+				Gas:      21000,
+				GasPrice: big.NewInt(10),
+				Value:    big.NewInt(0),
+			})
+			assert.NoError(t, err)
+			assert.NoError(t, result.Err)
+			assert.Equal(t, uint64(21000), result.GasUsed)
+
+			// the tip is paid to the coinbase in both cases
+			assert.Equal(t, big.NewInt(1000000-21000*10), transition.state.GetBalance(addr1))
+			assert.Equal(t, big.NewInt(21000*3), transition.state.GetBalance(coinbase))
+			assert.Equal(t, 0, big.NewInt(21000*testCase.collected).Cmp(transition.state.GetBalance(collector)))
+		})
+	}
+}
+
+func TestTransition_CodeSizeLimits(t *testing.T) {
+	t.Parallel()
+
+	apply := func(config chain.ForksInTime, input []byte) (*runtime.ExecutionResult, error) {
+		transition := newTestTransition(map[types.Address]*PreState{
+			addr1: {
+				Balance: 1000,
+			},
+		})
+		transition.r = &Executor{
+			runtimes: []runtime.Runtime{precompiled.NewPrecompiled(), evm.NewEVM()},
+		}
+		transition.config = config
+		transition.gasPool = 10000000
+
+		return transition.Apply(&types.Transaction{
+			From:     addr1,
+			Gas:      6000000,
+			GasPrice: big.NewInt(0),
+			Value:    big.NewInt(0),
+			Input:    input,
+		})
+	}
+
+	t.Run("init code over the limit", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := apply(chain.ForksInTime{Homestead: true, EIP3860: true}, make([]byte, runtime.MaxInitCodeSize+1))
+		assert.ErrorIs(t, err, runtime.ErrMaxInitCodeSizeExceeded)
+
+		_, err = apply(chain.ForksInTime{Homestead: true}, make([]byte, runtime.MaxInitCodeSize+1))
+		assert.NoError(t, err)
+	})
+
+	t.Run("deployed code over the limit", func(t *testing.T) {
+		t.Parallel()
+
+		// RETURN(0, 24577)
+		code := []byte{0x61, 0x60, 0x01, 0x60, 0x00, 0xf3}
+
+		result, err := apply(chain.ForksInTime{Homestead: true, EIP170: true}, code)
+		assert.NoError(t, err)
+		assert.ErrorIs(t, result.Err, runtime.ErrMaxCodeSizeExceeded)
+		assert.Equal(t, uint64(6000000), result.GasUsed)
+
+		// without the limit, the code is deployed
+		result, err = apply(chain.ForksInTime{Homestead: true}, code)
+		assert.NoError(t, err)
+		assert.NoError(t, result.Err)
+	})
+}
+
+func TestTransactionGasCost_InitCode(t *testing.T) {
+	t.Parallel()
+
+	create := &types.Transaction{Input: make([]byte, 64)}
+
+	cost, err := TransactionGasCost(create, true, true, false)
+	assert.NoError(t, err)
+	assert.Equal(t, TxGasContractCreation+64*4, cost)
+
+	// the 2 words of the init code are paid
+	cost, err = TransactionGasCost(create, true, true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, TxGasContractCreation+64*4+2*runtime.InitCodeWordGas, cost)
+
+	// the input of the calls is not init code
+	call := &types.Transaction{To: &addr1, Input: make([]byte, 64)}
+
+	cost, err = TransactionGasCost(call, true, true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, TxGas+64*4, cost)
+}
+
+func TestTransition_ReplayProtection(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	receiver := types.StringToAddress("1234")
+
+	tx, err := (&crypto.FrontierSigner{}).SignTx(&types.Transaction{
+		To:       &receiver,
+		Gas:      21000,
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(0),
+	}, key)
+	assert.NoError(t, err)
+
+	write := func(replayProtection bool) error {
+		transition := newTestTransition(map[types.Address]*PreState{
+			crypto.PubKeyToAddress(&key.PublicKey): {
+				Balance: 1000000,
+			},
+		})
+		transition.r = &Executor{
+			config:   &chain.Params{ChainID: 100},
+			runtimes: []runtime.Runtime{precompiled.NewPrecompiled(), evm.NewEVM()},
+		}
+		transition.config = chain.AllForksEnabled.At(0)
+		transition.config.ReplayProtection = replayProtection
+		transition.gasPool = 1000000
+
+		return transition.Write(tx.Copy())
+	}
+
+	// the unprotected transactions of the blocks before the fork are still valid
+	assert.NoError(t, write(false))
+	assert.ErrorIs(t, write(true), ErrUnprotectedTx)
+}
+
+func TestTransition_Aborted(t *testing.T) {
+	t.Parallel()
+
+	transition := newTestTransition(map[types.Address]*PreState{
+		addr1: {
+			Balance: 1000,
+		},
+	})
+	transition.r = &Executor{
+		runtimes: []runtime.Runtime{precompiled.NewPrecompiled(), evm.NewEVM()},
+	}
+	transition.gasPool = 100000000
+
+	abort := make(chan struct{})
+	close(abort)
+	transition.SetAbort(abort)
+
+	// the contract creation loops until the execution is aborted, JUMPDEST PUSH1 0 JUMP
+	result, err := transition.Apply(&types.Transaction{
+		From:     addr1,
+		Gas:      50000000,
+		GasPrice: big.NewInt(0),
+		Value:    big.NewInt(0),
+		Input:    []byte{0x5b, 0x60, 0x00, 0x56},
+	})
+
+	assert.ErrorIs(t, err, runtime.ErrExecutionAborted)
+	assert.Nil(t, result)
+
+	// the aborted transaction is reverted
+	assert.Equal(t, uint64(0), transition.state.GetNonce(addr1))
+}
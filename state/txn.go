@@ -21,6 +21,9 @@ var (
 
 	// refundIndex is the index of the refund
 	refundIndex = types.BytesToHash([]byte{3}).Bytes()
+
+	// accessListIndex is the prefix of the addresses and the slots accessed by the transaction
+	accessListIndex = types.BytesToHash([]byte{4}).Bytes()
 )
 
 // Txn is a reference of the state
@@ -31,6 +34,11 @@ type Txn struct {
 	txn       *iradix.Txn
 	codeCache *lru.Cache
 	hash      *keccak.Keccak
+
+	// reads and writes record the accounts and the slots accessed by the transactions
+	// executed in parallel, nil if they are not recorded
+	reads  *accessSet
+	writes *accessSet
 }
 
 func NewTxn(state State, snapshot Snapshot) *Txn {
@@ -72,6 +80,21 @@ func (txn *Txn) Snapshot() int {
 	return id
 }
 
+// Copy returns a copy of the txn at this point in time,
+// the changes made to either of them do not affect the other
+func (txn *Txn) Copy() *Txn {
+	codeCache, _ := lru.New(20)
+
+	return &Txn{
+		snapshot:  txn.snapshot,
+		state:     txn.state,
+		snapshots: []*iradix.Tree{},
+		txn:       txn.txn.CommitOnly().Txn(),
+		codeCache: codeCache,
+		hash:      keccak.NewKeccak256(),
+	}
+}
+
 // RevertToSnapshot reverts to a given snapshot
 func (txn *Txn) RevertToSnapshot(id int) {
 	if id > len(txn.snapshots) {
@@ -93,6 +116,8 @@ func (txn *Txn) GetAccount(addr types.Address) (*Account, bool) {
 }
 
 func (txn *Txn) getStateObject(addr types.Address) (*StateObject, bool) {
+	txn.reads.addAccount(addr)
+
 	// Try to get state from radix tree which holds transient states during block processing first
 	val, exists := txn.txn.Get(addr.Bytes())
 	if exists {
@@ -146,6 +171,8 @@ func (txn *Txn) upsertAccount(addr types.Address, create bool, f func(object *St
 		}
 	}
 
+	txn.writes.addAccount(addr)
+
 	// run the callback to modify the account
 	f(object)
 
@@ -157,6 +184,8 @@ func (txn *Txn) upsertAccount(addr types.Address, create bool, f func(object *St
 func (txn *Txn) AddSealingReward(addr types.Address, balance *big.Int) {
 	txn.upsertAccount(addr, true, func(object *StateObject) {
 		if object.Suicide {
+			txn.writes.addReset(addr)
+
 			*object = *newStateObject(txn)
 			object.Account.Balance.SetBytes(balance.Bytes())
 		} else {
@@ -278,13 +307,19 @@ func (txn *Txn) SetStorage(
 		return runtime.StorageModified
 	}
 
+	// eip-3529, the refund of the cleared slots is reduced
+	clearRefund := uint64(15000)
+	if config.London {
+		clearRefund = 4800
+	}
+
 	if original == current {
 		if original == zeroHash { // create slot (2.1.1)
 			return runtime.StorageAdded
 		}
 
 		if value == zeroHash { // delete slot (2.1.2b)
-			txn.AddRefund(15000)
+			txn.AddRefund(clearRefund)
 
 			return runtime.StorageDeleted
 		}
@@ -294,22 +329,26 @@ func (txn *Txn) SetStorage(
 
 	if original != zeroHash { // Storage slot was populated before this transaction started
 		if current == zeroHash { // recreate slot (2.2.1.1)
-			txn.SubRefund(15000)
+			txn.SubRefund(clearRefund)
 		} else if value == zeroHash { // delete slot (2.2.1.2)
-			txn.AddRefund(15000)
+			txn.AddRefund(clearRefund)
 		}
 	}
 
 	if original == value {
 		if original == zeroHash { // reset to original nonexistent slot (2.2.2.1)
 			// Storage was used as memory (allocation and deallocation occurred within the same contract)
-			if config.Istanbul {
+			if config.Berlin {
+				txn.AddRefund(19900)
+			} else if config.Istanbul {
 				txn.AddRefund(19200)
 			} else {
 				txn.AddRefund(19800)
 			}
 		} else { // reset to original existing slot (2.2.2.2)
-			if config.Istanbul {
+			if config.Berlin {
+				txn.AddRefund(2800)
+			} else if config.Istanbul {
 				txn.AddRefund(4200)
 			} else {
 				txn.AddRefund(4800)
@@ -326,6 +365,8 @@ func (txn *Txn) SetState(
 	key,
 	value types.Hash,
 ) {
+	txn.writes.addSlot(addr, key)
+
 	txn.upsertAccount(addr, true, func(object *StateObject) {
 		if object.Txn == nil {
 			object.Txn = iradix.New().Txn()
@@ -339,8 +380,25 @@ func (txn *Txn) SetState(
 	})
 }
 
+// SetFullState replaces the whole storage of the address with the given slots
+func (txn *Txn) SetFullState(addr types.Address, storage map[types.Hash]types.Hash) {
+	txn.writes.addReset(addr)
+
+	txn.upsertAccount(addr, true, func(object *StateObject) {
+		object.Account.Root = emptyStateHash
+		object.Account.Trie = txn.state.NewSnapshot()
+		object.Txn = iradix.New().Txn()
+	})
+
+	for key, value := range storage {
+		txn.SetState(addr, key, value)
+	}
+}
+
 // GetState returns the state of the address at a given key
 func (txn *Txn) GetState(addr types.Address, key types.Hash) types.Hash {
+	txn.reads.addSlot(addr, key)
+
 	object, exists := txn.getStateObject(addr)
 	if !exists {
 		return types.Hash{}
@@ -365,6 +423,27 @@ func (txn *Txn) GetState(addr types.Address, key types.Hash) types.Hash {
 	return object.GetCommitedState(types.BytesToHash(k))
 }
 
+// GetDirtyStorage returns the non-empty storage slots of the address written by the transaction.
+// The slots committed before the transaction aren't included
+func (txn *Txn) GetDirtyStorage(addr types.Address) map[types.Hash]types.Hash {
+	storage := make(map[types.Hash]types.Hash)
+
+	object, exists := txn.getStateObject(addr)
+	if !exists || object.Txn == nil {
+		return storage
+	}
+
+	object.Txn.Root().Walk(func(k []byte, v interface{}) bool {
+		if val, ok := v.([]byte); ok && val != nil {
+			storage[types.BytesToHash(k)] = types.BytesToHash(val)
+		}
+
+		return false
+	})
+
+	return storage
+}
+
 // Nonce
 
 // IncrNonce increases the nonce of the address
@@ -496,6 +575,48 @@ func (txn *Txn) GetRefund() uint64 {
 	return data.(uint64)
 }
 
+// Access list
+
+// accessListKey returns the key of the address, or of the storage slot of the address, in the access list
+func accessListKey(addr types.Address, slot *types.Hash) []byte {
+	key := append(append([]byte{}, accessListIndex...), addr.Bytes()...)
+	if slot != nil {
+		key = append(key, slot.Bytes()...)
+	}
+
+	return key
+}
+
+// AddressInAccessList checks if the address was accessed by the transaction, as in EIP-2929
+func (txn *Txn) AddressInAccessList(addr types.Address) bool {
+	_, exists := txn.txn.Get(accessListKey(addr, nil))
+
+	return exists
+}
+
+// SlotInAccessList checks if the storage slot of the address was accessed by the transaction
+func (txn *Txn) SlotInAccessList(addr types.Address, slot types.Hash) bool {
+	_, exists := txn.txn.Get(accessListKey(addr, &slot))
+
+	return exists
+}
+
+// AddAddressToAccessList marks the address as accessed by the transaction
+func (txn *Txn) AddAddressToAccessList(addr types.Address) {
+	txn.txn.Insert(accessListKey(addr, nil), true)
+}
+
+// AddSlotToAccessList marks the storage slot of the address, and the address, as accessed by the transaction
+func (txn *Txn) AddSlotToAccessList(addr types.Address, slot types.Hash) {
+	txn.txn.Insert(accessListKey(addr, nil), true)
+	txn.txn.Insert(accessListKey(addr, &slot), true)
+}
+
+// ClearAccessList removes every address and storage slot accessed by the transaction
+func (txn *Txn) ClearAccessList() {
+	txn.txn.DeletePrefix(accessListIndex)
+}
+
 // GetCommittedState returns the state of the address in the trie
 func (txn *Txn) GetCommittedState(addr types.Address, key types.Hash) types.Hash {
 	obj, ok := txn.getStateObject(addr)
@@ -555,6 +676,7 @@ func (txn *Txn) CreateAccount(addr types.Address) {
 		obj.Account.Balance.SetBytes(prev.Account.Balance.Bytes())
 	}
 
+	txn.writes.addReset(addr)
 	txn.txn.Insert(addr.Bytes(), obj)
 }
 
@@ -588,10 +710,12 @@ func (txn *Txn) CleanDeleteObjects(deleteEmptyObjects bool) {
 		obj2 := obj.Copy()
 		obj2.Deleted = true
 		txn.txn.Insert(k, obj2)
+		txn.writes.addAccount(types.BytesToAddress(k))
 	}
 
-	// delete refunds
+	// delete refunds and the access list
 	txn.txn.Delete(refundIndex)
+	txn.ClearAccessList()
 }
 
 func (txn *Txn) Commit(deleteEmptyObjects bool) (Snapshot, []byte) {
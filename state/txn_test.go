@@ -139,6 +139,24 @@ func TestSnapshotUpdateData(t *testing.T) {
 	assert.Equal(t, hash1, txn.GetState(addr1, hash1))
 }
 
+func TestTxnCopy(t *testing.T) {
+	txn := newTestTxn(defaultPreState)
+
+	txn.SetState(addr1, hash1, hash1)
+	txn.AddBalance(addr1, big.NewInt(1))
+
+	copied := txn.Copy()
+
+	txn.SetState(addr1, hash1, hash2)
+	txn.AddBalance(addr1, big.NewInt(1))
+	copied.SetState(addr2, hash1, hash1)
+
+	// the changes made after the copy are not shared
+	assert.Equal(t, hash1, copied.GetState(addr1, hash1))
+	assert.Equal(t, new(big.Int).Sub(txn.GetBalance(addr1), big.NewInt(1)), copied.GetBalance(addr1))
+	assert.Equal(t, types.ZeroHash, txn.GetState(addr2, hash1))
+}
+
 func hashit(k []byte) []byte {
 	h := sha3.NewLegacyKeccak256()
 	h.Write(k)
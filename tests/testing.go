@@ -289,6 +289,9 @@ type stTransaction struct {
 	Nonce    uint64         `json:"nonce"`
 	From     types.Address  `json:"secretKey"`
 	To       *types.Address `json:"to"`
+
+	// AccessLists are the EIP-2930 access lists of the transaction, one per data
+	AccessLists []*types.AccessList `json:"accessLists"`
 }
 
 func (t *stTransaction) At(i indexes) (*types.Transaction, error) {
@@ -315,6 +318,11 @@ func (t *stTransaction) At(i indexes) (*types.Transaction, error) {
 
 	msg.From = t.From
 
+	if i.Data < len(t.AccessLists) && t.AccessLists[i.Data] != nil {
+		msg.Type = types.AccessListTx
+		msg.AccessList = t.AccessLists[i.Data].Copy()
+	}
+
 	return msg, nil
 }
 
@@ -327,6 +335,8 @@ func (t *stTransaction) UnmarshalJSON(input []byte) error {
 		Nonce     string   `json:"nonce"`
 		SecretKey string   `json:"secretKey"`
 		To        string   `json:"to"`
+
+		AccessLists []*types.AccessList `json:"accessLists"`
 	}
 
 	var dec txUnmarshall
@@ -337,6 +347,7 @@ func (t *stTransaction) UnmarshalJSON(input []byte) error {
 	}
 
 	t.Data = dec.Data
+	t.AccessLists = dec.AccessLists
 
 	for _, i := range dec.GasLimit {
 		if j, err := stringToUint64(i); err != nil {
@@ -438,6 +449,17 @@ var Forks = map[string]*chain.Forks{
 		Petersburg:     chain.NewFork(0),
 		Istanbul:       chain.NewFork(0),
 	},
+	"Berlin": {
+		Homestead:      chain.NewFork(0),
+		EIP150:         chain.NewFork(0),
+		EIP155:         chain.NewFork(0),
+		EIP158:         chain.NewFork(0),
+		Byzantium:      chain.NewFork(0),
+		Constantinople: chain.NewFork(0),
+		Petersburg:     chain.NewFork(0),
+		Istanbul:       chain.NewFork(0),
+		Berlin:         chain.NewFork(0),
+	},
 	"FrontierToHomesteadAt5": {
 		Homestead: chain.NewFork(5),
 	},
@@ -1,6 +1,7 @@
 package txpool
 
 import (
+	"math/big"
 	"sync"
 	"sync/atomic"
 
@@ -66,6 +67,45 @@ func (m *accountsMap) getPrimaries() (primaries []*types.Transaction) {
 	return primaries
 }
 
+// getEvictionCandidate returns the lowest priced transaction the pool can evict,
+// out of the accounts other than the excluded one. Only the highest nonce transaction
+// of each account is a candidate, so the eviction doesn't leave a nonce gap.
+// If keepLocal is set, the local accounts are exempt from the eviction.
+func (m *accountsMap) getEvictionCandidate(
+	exclude types.Address,
+	keepLocal bool,
+) (*account, *types.Transaction) {
+	var (
+		cheapestAccount *account
+		cheapest        *types.Transaction
+	)
+
+	m.Range(func(key, value interface{}) bool {
+		addr, _ := key.(types.Address)
+		if addr == exclude {
+			return true
+		}
+
+		account := m.get(addr)
+		if keepLocal && account.isLocal() {
+			return true
+		}
+
+		candidate := account.lastEvictable()
+		if candidate == nil {
+			return true
+		}
+
+		if cheapest == nil || candidate.GetGasFeeCap().Cmp(cheapest.GetGasFeeCap()) < 0 {
+			cheapestAccount, cheapest = account, candidate
+		}
+
+		return true
+	})
+
+	return cheapestAccount, cheapest
+}
+
 // get returns the account associated with the given address.
 func (m *accountsMap) get(addr types.Address) *account {
 	a, ok := m.Load(addr)
@@ -103,6 +143,8 @@ func (m *accountsMap) promoted() (total uint64) {
 }
 
 // allTxs returns all promoted and all enqueued transactions, depending on the flag.
+// The transactions of each account are a snapshot taken while both its queues are locked,
+// so a concurrent promotion can't leave a nonce out of the result.
 func (m *accountsMap) allTxs(includeEnqueued bool) (
 	allPromoted, allEnqueued map[types.Address][]*types.Transaction,
 ) {
@@ -116,19 +158,19 @@ func (m *accountsMap) allTxs(includeEnqueued bool) (
 		account.promoted.lock(false)
 		defer account.promoted.unlock()
 
-		if account.promoted.length() != 0 {
-			allPromoted[addr] = account.promoted.queue
-		}
-
 		if includeEnqueued {
 			account.enqueued.lock(false)
 			defer account.enqueued.unlock()
 
 			if account.enqueued.length() != 0 {
-				allEnqueued[addr] = account.enqueued.queue
+				allEnqueued[addr] = account.enqueued.copy()
 			}
 		}
 
+		if account.promoted.length() != 0 {
+			allPromoted[addr] = account.promoted.copy()
+		}
+
 		return true
 	})
 
@@ -150,6 +192,9 @@ type account struct {
 	init               sync.Once
 	enqueued, promoted *accountQueue
 	nextNonce          uint64
+
+	// local is set once the account submits a transaction through the json-RPC/gRPC endpoints
+	local uint32
 }
 
 // getNonce returns the next expected nonce for this account.
@@ -157,11 +202,70 @@ func (a *account) getNonce() uint64 {
 	return atomic.LoadUint64(&a.nextNonce)
 }
 
+// pendingNonce returns the nonce following the transactions of the account in sequence,
+// the enqueued ones waiting for their promotion included.
+func (a *account) pendingNonce() uint64 {
+	a.enqueued.lock(false)
+	defer a.enqueued.unlock()
+
+	nonce := a.getNonce()
+	for a.enqueued.get(nonce) != nil {
+		nonce++
+	}
+
+	return nonce
+}
+
 // setNonce sets the next expected nonce for this account.
 func (a *account) setNonce(nonce uint64) {
 	atomic.StoreUint64(&a.nextNonce, nonce)
 }
 
+// markLocal marks the account as submitting local transactions.
+func (a *account) markLocal() {
+	atomic.StoreUint32(&a.local, 1)
+}
+
+// isLocal checks if the account submitted local transactions.
+func (a *account) isLocal() bool {
+	return atomic.LoadUint32(&a.local) == 1
+}
+
+// evictFrom removes the transactions with the given nonce and the higher ones,
+// which can't be executed without it, and rolls the nonce back if promoted
+// transactions were removed. It returns the removed promoted and enqueued transactions.
+func (a *account) evictFrom(nonce uint64) (
+	evictedPromoted,
+	evictedEnqueued []*types.Transaction,
+) {
+	a.promoted.lock(true)
+	a.enqueued.lock(true)
+
+	defer func() {
+		a.enqueued.unlock()
+		a.promoted.unlock()
+	}()
+
+	for _, tx := range a.promoted.copy() {
+		if tx.Nonce >= nonce && a.promoted.remove(tx) {
+			evictedPromoted = append(evictedPromoted, tx)
+		}
+	}
+
+	for _, tx := range a.enqueued.copy() {
+		if tx.Nonce >= nonce && a.enqueued.remove(tx) {
+			evictedEnqueued = append(evictedEnqueued, tx)
+		}
+	}
+
+	if len(evictedPromoted) != 0 && nonce < a.getNonce() {
+		// roll back the nonce to the evicted tx
+		a.setNonce(nonce)
+	}
+
+	return
+}
+
 //	reset aligns the account with the new nonce
 //	by pruning all transactions with nonce lesser than new.
 //	After pruning, a promotion may be signaled if the first
@@ -180,7 +284,19 @@ func (a *account) reset(nonce uint64, promoteCh chan<- promoteRequest) (
 	)
 
 	if nonce <= a.getNonce() {
-		// only the promoted queue needed pruning
+		// only the promoted queue needed pruning,
+		// but it may have room for the enqueued txs
+		// held back by the promoted limit
+		if len(prunedPromoted) != 0 {
+			a.enqueued.lock(false)
+			defer a.enqueued.unlock()
+
+			if first := a.enqueued.peek(); first != nil &&
+				first.Nonce == a.getNonce() {
+				promoteCh <- promoteRequest{account: first.From}
+			}
+		}
+
 		return
 	}
 
@@ -209,27 +325,175 @@ func (a *account) reset(nonce uint64, promoteCh chan<- promoteRequest) (
 }
 
 // enqueue attempts tp push the transaction onto the enqueued queue.
-func (a *account) enqueue(tx *types.Transaction) error {
+// A transaction with the nonce of an enqueued or promoted transaction replaces it,
+// if it bumps the gas price by at least priceBump percent.
+// The replaced transaction is returned, along with the flag indicating if it was promoted.
+// Once maxEnqueued transactions are enqueued (0 for no limit), new higher nonce transactions are rejected.
+func (a *account) enqueue(tx *types.Transaction, priceBump, maxEnqueued uint64) (
+	replaced *types.Transaction,
+	promoted bool,
+	err error,
+) {
+	// both queues are locked, so the replaced
+	// transaction can't be promoted meanwhile
+	a.promoted.lock(true)
 	a.enqueued.lock(true)
-	defer a.enqueued.unlock()
 
-	// reject low nonce tx
+	defer func() {
+		a.enqueued.unlock()
+		a.promoted.unlock()
+	}()
+
+	// low nonce txs can only replace promoted txs
 	if tx.Nonce < a.getNonce() {
-		return ErrNonceTooLow
+		replaced = a.promoted.get(tx.Nonce)
+		if replaced == nil {
+			return nil, false, ErrNonceTooLow
+		}
+
+		if !isPriceBumped(replaced, tx, priceBump) {
+			return nil, false, ErrReplacementUnderpriced
+		}
+
+		a.promoted.replace(tx)
+
+		return replaced, true, nil
+	}
+
+	if replaced = a.enqueued.get(tx.Nonce); replaced != nil {
+		if !isPriceBumped(replaced, tx, priceBump) {
+			return nil, false, ErrReplacementUnderpriced
+		}
+
+		a.enqueued.replace(tx)
+
+		return replaced, false, nil
+	}
+
+	if a.isEnqueuedFull(tx, maxEnqueued) {
+		return nil, false, ErrMaxEnqueuedLimitReached
 	}
 
 	// enqueue tx
 	a.enqueued.push(tx)
 
+	return nil, false, nil
+}
+
+// validateEnqueue checks if the transaction bumps the gas price enough
+// to replace the enqueued or promoted transaction with the same nonce (if any),
+// or if it fits in the enqueued transactions of the account otherwise.
+func (a *account) validateEnqueue(tx *types.Transaction, priceBump, maxEnqueued uint64) error {
+	a.promoted.lock(false)
+	a.enqueued.lock(false)
+
+	defer func() {
+		a.enqueued.unlock()
+		a.promoted.unlock()
+	}()
+
+	replaced := a.enqueued.get(tx.Nonce)
+	if replaced == nil {
+		replaced = a.promoted.get(tx.Nonce)
+	}
+
+	if replaced != nil {
+		if !isPriceBumped(replaced, tx, priceBump) {
+			return ErrReplacementUnderpriced
+		}
+
+		return nil
+	}
+
+	if a.isEnqueuedFull(tx, maxEnqueued) {
+		return ErrMaxEnqueuedLimitReached
+	}
+
+	return nil
+}
+
+// isEnqueuedFull checks if the account has no room for the transaction in the enqueued queue.
+// The transaction with the expected nonce is always accepted, as it unblocks the enqueued ones.
+func (a *account) isEnqueuedFull(tx *types.Transaction, maxEnqueued uint64) bool {
+	return maxEnqueued != 0 &&
+		tx.Nonce > a.getNonce() &&
+		a.enqueued.length() >= maxEnqueued
+}
+
+// lastEvictable returns the highest nonce transaction of the account, or nil if it has none to evict.
+// The last promoted transaction is only evicted once nothing is enqueued, and the head of
+// the promoted queue is never evicted, as it may be executed already.
+func (a *account) lastEvictable() *types.Transaction {
+	a.promoted.lock(false)
+	a.enqueued.lock(false)
+
+	defer func() {
+		a.enqueued.unlock()
+		a.promoted.unlock()
+	}()
+
+	if tx := a.enqueued.last(); tx != nil {
+		return tx
+	}
+
+	if a.promoted.length() > 1 {
+		return a.promoted.last()
+	}
+
 	return nil
 }
 
+// evict removes the transaction, if it is still the last evictable transaction of the account.
+// It returns the flag indicating if the transaction was evicted, and if it was promoted.
+func (a *account) evict(tx *types.Transaction) (evicted bool, promoted bool) {
+	a.promoted.lock(true)
+	a.enqueued.lock(true)
+
+	defer func() {
+		a.enqueued.unlock()
+		a.promoted.unlock()
+	}()
+
+	if a.enqueued.length() != 0 {
+		if a.enqueued.last() != tx {
+			return false, false
+		}
+
+		return a.enqueued.remove(tx), false
+	}
+
+	if a.promoted.length() <= 1 || a.promoted.last() != tx {
+		return false, false
+	}
+
+	a.promoted.remove(tx)
+
+	// roll back the nonce to the evicted tx
+	a.setNonce(tx.Nonce)
+
+	return true, true
+}
+
+// isPriceBumped checks if both the fee cap and the tip cap of the transaction
+// are at least priceBump percent higher than the ones of the replaced transaction.
+func isPriceBumped(replaced, tx *types.Transaction, priceBump uint64) bool {
+	bump := func(price *big.Int) *big.Int {
+		bumped := new(big.Int).Mul(price, new(big.Int).SetUint64(100+priceBump))
+
+		return bumped.Div(bumped, big.NewInt(100))
+	}
+
+	return tx.GetGasFeeCap().Cmp(bump(replaced.GetGasFeeCap())) >= 0 &&
+		tx.GetGasTipCap().Cmp(bump(replaced.GetGasTipCap())) >= 0
+}
+
 // Promote moves eligible transactions from enqueued to promoted.
 //
 // Eligible transactions are all sequential in order of nonce
 // and the first one has to have nonce less (or equal) to the account's
-// nextNonce.
-func (a *account) promote() []*types.Transaction {
+// nextNonce. At most maxPromoted transactions are promoted (0 for no limit),
+// the rest is held back in the enqueued queue.
+func (a *account) promote(maxPromoted uint64) []*types.Transaction {
 	a.promoted.lock(true)
 	a.enqueued.lock(true)
 
@@ -258,6 +522,10 @@ func (a *account) promote() []*types.Transaction {
 			break
 		}
 
+		if maxPromoted != 0 && a.promoted.length() >= maxPromoted {
+			break
+		}
+
 		// pop from enqueued
 		tx = a.enqueued.pop()
 
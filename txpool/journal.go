@@ -0,0 +1,220 @@
+package txpool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// journalEntryPrefix is the length of the size prefix of the journal entries
+const journalEntryPrefix = 4
+
+var (
+	errJournalClosed = errors.New("journal closed")
+)
+
+// txJournal persists the local transactions of the pool, so they are not lost
+// when the node is restarted. Each entry is the size prefixed RLP encoding of a transaction.
+// The gossiped transactions are never journaled
+type txJournal struct {
+	sync.Mutex
+
+	path   string
+	writer *os.File
+	closed bool
+
+	// hashes of the journaled transactions
+	locals map[types.Hash]struct{}
+}
+
+// newTxJournal creates the journal at the passed in path,
+// it is opened for writing on the first rotation
+func newTxJournal(path string) (*txJournal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	return &txJournal{
+		path:   path,
+		locals: make(map[types.Hash]struct{}),
+	}, nil
+}
+
+// load replays the journaled transactions through the passed in function.
+// The transactions it rejects are skipped, and are not kept by the next rotation
+func (j *txJournal) load(add func(tx *types.Transaction) error) (loaded, dropped int, err error) {
+	file, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		// nothing journaled yet
+		return 0, 0, nil
+	}
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+
+	for {
+		tx, readErr := readJournalEntry(reader)
+		if errors.Is(readErr, io.EOF) {
+			return loaded, dropped, nil
+		}
+
+		if readErr != nil {
+			return loaded, dropped, readErr
+		}
+
+		if addErr := add(tx); addErr != nil {
+			dropped++
+
+			continue
+		}
+
+		j.Lock()
+		j.locals[tx.Hash] = struct{}{}
+		j.Unlock()
+
+		loaded++
+	}
+}
+
+// readJournalEntry reads the next transaction of the journal
+func readJournalEntry(reader io.Reader) (*types.Transaction, error) {
+	prefix := make([]byte, journalEntryPrefix)
+	if _, err := io.ReadFull(reader, prefix); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, binary.BigEndian.Uint32(prefix))
+	if _, err := io.ReadFull(reader, data); err != nil {
+		// the node was stopped in the middle of a write
+		return nil, fmt.Errorf("truncated journal entry, %w", err)
+	}
+
+	tx := &types.Transaction{}
+	if err := tx.UnmarshalRLP(data); err != nil {
+		return nil, err
+	}
+
+	tx.ComputeHash()
+
+	return tx, nil
+}
+
+// writeJournalEntry appends the transaction to the journal
+func writeJournalEntry(writer io.Writer, tx *types.Transaction) error {
+	data := tx.MarshalRLP()
+
+	entry := make([]byte, journalEntryPrefix, journalEntryPrefix+len(data))
+	binary.BigEndian.PutUint32(entry, uint32(len(data)))
+
+	_, err := writer.Write(append(entry, data...))
+
+	return err
+}
+
+// insert journals the local transaction. If the journal is not opened yet,
+// the transaction is only written by the next rotation
+func (j *txJournal) insert(tx *types.Transaction) error {
+	j.Lock()
+	defer j.Unlock()
+
+	if j.closed {
+		return errJournalClosed
+	}
+
+	j.locals[tx.Hash] = struct{}{}
+
+	if j.writer == nil {
+		return nil
+	}
+
+	return writeJournalEntry(j.writer, tx)
+}
+
+// rotate rewrites the journal with the local transactions out of the passed in ones,
+// so the transactions that left the pool are not kept forever
+func (j *txJournal) rotate(txs []*types.Transaction) error {
+	j.Lock()
+	defer j.Unlock()
+
+	if j.closed {
+		return errJournalClosed
+	}
+
+	if j.writer != nil {
+		if err := j.writer.Close(); err != nil {
+			return err
+		}
+
+		j.writer = nil
+	}
+
+	tmpPath := j.path + ".new"
+
+	replacement, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	locals := make(map[types.Hash]struct{})
+
+	for _, tx := range txs {
+		if _, ok := j.locals[tx.Hash]; !ok {
+			continue
+		}
+
+		if err := writeJournalEntry(replacement, tx); err != nil {
+			replacement.Close()
+
+			return err
+		}
+
+		locals[tx.Hash] = struct{}{}
+	}
+
+	if err := replacement.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return err
+	}
+
+	writer, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	j.writer = writer
+	j.locals = locals
+
+	return nil
+}
+
+// close closes the journal, nothing is journaled afterwards
+func (j *txJournal) close() error {
+	j.Lock()
+	defer j.Unlock()
+
+	j.closed = true
+
+	if j.writer == nil {
+		return nil
+	}
+
+	err := j.writer.Close()
+	j.writer = nil
+
+	return err
+}
@@ -0,0 +1,214 @@
+package txpool
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/helper/tests"
+	"github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// nonceMockStore returns the configured nonces of the accounts
+type nonceMockStore struct {
+	defaultMockStore
+	nonces map[types.Address]uint64
+}
+
+func (m nonceMockStore) GetNonce(_ types.Hash, addr types.Address) uint64 {
+	return m.nonces[addr]
+}
+
+func newJournalTestPool(t *testing.T, path string, mockStore store) *TxPool {
+	t.Helper()
+
+	pool, err := NewTxPool(
+		hclog.NewNullLogger(),
+		forks.At(0),
+		mockStore,
+		nil,
+		nil,
+		nilMetrics,
+		&Config{
+			PriceLimit:  defaultPriceLimit,
+			PriceBump:   defaultPriceBump,
+			MaxSlots:    defaultMaxSlots,
+			JournalPath: path,
+		},
+	)
+	assert.NoError(t, err)
+
+	pool.SetSigner(crypto.NewEIP155Signer(100))
+
+	return pool
+}
+
+// loadJournaled returns the transactions in the journal
+func loadJournaled(t *testing.T, path string) []*types.Transaction {
+	t.Helper()
+
+	journal, err := newTxJournal(path)
+	assert.NoError(t, err)
+
+	txs := []*types.Transaction{}
+
+	_, _, err = journal.load(func(tx *types.Transaction) error {
+		txs = append(txs, tx)
+
+		return nil
+	})
+	assert.NoError(t, err)
+
+	return txs
+}
+
+func TestJournal_Replay(t *testing.T) {
+	signer := crypto.NewEIP155Signer(100)
+	key1, sender1 := tests.GenerateKeyAndAddr(t)
+	key2, sender2 := tests.GenerateKeyAndAddr(t)
+
+	sign := func(tx *types.Transaction, key *ecdsa.PrivateKey) *types.Transaction {
+		t.Helper()
+
+		signedTx, err := signer.SignTx(tx, key)
+		assert.NoError(t, err)
+
+		return signedTx
+	}
+
+	path := filepath.Join(t.TempDir(), "transactions.rlp")
+	mockStore := nonceMockStore{
+		defaultMockStore: defaultMockStore{DefaultHeader: mockHeader},
+		nonces:           map[types.Address]uint64{},
+	}
+
+	pool := newJournalTestPool(t, path, mockStore)
+	pool.Start()
+
+	promoteSubscription := pool.eventManager.subscribe([]proto.EventType{proto.EventType_PROMOTED})
+
+	localTxs := []*types.Transaction{
+		sign(newTx(types.ZeroAddress, 0, 1), key1),
+		sign(newTx(types.ZeroAddress, 1, 1), key1),
+	}
+
+	for _, tx := range localTxs {
+		assert.NoError(t, pool.AddTx(tx))
+	}
+
+	// the gossiped transactions are not journaled
+	assert.NoError(t, pool.addTx(gossip, sign(newTx(types.ZeroAddress, 0, 1), key2)))
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancelFn()
+
+	assert.Len(t, waitForEvents(ctx, promoteSubscription, 3), 3)
+
+	pool.Close()
+
+	journaled := loadJournaled(t, path)
+	if assert.Len(t, journaled, 2) {
+		assert.Equal(t, localTxs[0].Hash, journaled[0].Hash)
+		assert.Equal(t, localTxs[1].Hash, journaled[1].Hash)
+	}
+
+	// the first transaction was written to a block in the meantime,
+	// it is skipped when the journal is replayed
+	mockStore.nonces[sender1] = 1
+
+	pool = newJournalTestPool(t, path, mockStore)
+	promoteSubscription = pool.eventManager.subscribe([]proto.EventType{proto.EventType_PROMOTED})
+
+	pool.Start()
+
+	defer pool.Close()
+
+	ctx, cancelFn = context.WithTimeout(context.Background(), time.Second*10)
+	defer cancelFn()
+
+	assert.Len(t, waitForEvents(ctx, promoteSubscription, 1), 1)
+
+	assert.Equal(t, uint64(1), pool.accounts.get(sender1).promoted.length())
+	assert.False(t, pool.accounts.exists(sender2))
+
+	_, ok := pool.index.get(localTxs[1].Hash)
+	assert.True(t, ok)
+
+	// the journal is rotated after the replay, so it only keeps the replayed transaction
+	journaled = loadJournaled(t, path)
+	if assert.Len(t, journaled, 1) {
+		assert.Equal(t, localTxs[1].Hash, journaled[0].Hash)
+	}
+}
+
+func TestJournal_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "txpool", "transactions.rlp")
+
+	journal, err := newTxJournal(path)
+	assert.NoError(t, err)
+
+	// open the journal
+	assert.NoError(t, journal.rotate(nil))
+
+	txs := []*types.Transaction{
+		newTx(addr1, 0, 1),
+		newTx(addr1, 1, 1),
+		newTx(addr2, 0, 1),
+	}
+
+	for _, tx := range txs {
+		tx.ComputeHash()
+		assert.NoError(t, journal.insert(tx))
+	}
+
+	assert.Len(t, loadJournaled(t, path), 3)
+
+	// only the local transactions still in the pool are kept
+	assert.NoError(t, journal.rotate([]*types.Transaction{txs[1], newTx(addr3, 0, 1)}))
+
+	journaled := loadJournaled(t, path)
+	if assert.Len(t, journaled, 1) {
+		assert.Equal(t, txs[1].Hash, journaled[0].Hash)
+	}
+
+	assert.NoError(t, journal.close())
+	assert.ErrorIs(t, journal.insert(txs[0]), errJournalClosed)
+}
+
+func TestJournal_TruncatedEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transactions.rlp")
+
+	journal, err := newTxJournal(path)
+	assert.NoError(t, err)
+	assert.NoError(t, journal.rotate(nil))
+
+	for _, tx := range []*types.Transaction{newTx(addr1, 0, 1), newTx(addr1, 1, 1)} {
+		tx.ComputeHash()
+		assert.NoError(t, journal.insert(tx))
+	}
+
+	assert.NoError(t, journal.close())
+
+	// the node was stopped in the middle of writing the last entry
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Truncate(path, info.Size()-1))
+
+	journal, err = newTxJournal(path)
+	assert.NoError(t, err)
+
+	loaded, dropped, err := journal.load(func(tx *types.Transaction) error {
+		return nil
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, loaded)
+	assert.Equal(t, 0, dropped)
+}
@@ -11,6 +11,8 @@ import (
 type Metrics struct {
 	// Pending transactions
 	PendingTxs metrics.Gauge
+	// Transactions evicted after their fee cap stayed below the base fee
+	UnderpricedEvictedTxs metrics.Counter
 }
 
 // GetPrometheusMetrics return the txpool metrics instance
@@ -28,12 +30,19 @@ func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics
 			Name:      "pending_transactions",
 			Help:      "Pending transactions in the pool",
 		}, labels).With(labelsWithValues...),
+		UnderpricedEvictedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "txpool",
+			Name:      "underpriced_evicted_transactions",
+			Help:      "Transactions evicted after their fee cap stayed below the base fee",
+		}, labels).With(labelsWithValues...),
 	}
 }
 
 // NilMetrics will return the non operational txpool metrics
 func NilMetrics() *Metrics {
 	return &Metrics{
-		PendingTxs: discard.NewGauge(),
+		PendingTxs:            discard.NewGauge(),
+		UnderpricedEvictedTxs: discard.NewCounter(),
 	}
 }
@@ -3,7 +3,9 @@ package txpool
 import (
 	"fmt"
 	"math/big"
+	"sync"
 
+	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/types"
 )
 
@@ -35,12 +37,127 @@ func (m defaultMockStore) GetBlockByHash(types.Hash, bool) (*types.Block, bool)
 	return nil, false
 }
 
+func (m defaultMockStore) SubscribeEvents() (<-chan *blockchain.Event, func()) {
+	return make(chan *blockchain.Event), func() {}
+}
+
 func (m defaultMockStore) GetBalance(types.Hash, types.Address) (*big.Int, error) {
 	balance := big.NewInt(0).SetUint64(100000000000000)
 
 	return balance, nil
 }
 
+// reorgMockStore is a mock store serving the blocks of a chain reorganization
+type reorgMockStore struct {
+	defaultMockStore
+
+	blocks map[types.Hash]*types.Block
+	events chan *blockchain.Event
+}
+
+func (m reorgMockStore) GetBlockByHash(hash types.Hash, _ bool) (*types.Block, bool) {
+	block, ok := m.blocks[hash]
+
+	return block, ok
+}
+
+func (m reorgMockStore) SubscribeEvents() (<-chan *blockchain.Event, func()) {
+	return m.events, func() {}
+}
+
+// evictingMockStore is a mock store whose subscribers of the chain events can be unsubscribed,
+// as the blockchain unsubscribes the slow consumers once their buffer is full
+type evictingMockStore struct {
+	defaultMockStore
+
+	lock        sync.Mutex
+	nonces      map[types.Address]uint64
+	subscribers map[chan *blockchain.Event]struct{}
+
+	// the blocks are served once the gate is closed,
+	// the reads of the blocks are signaled on readCh
+	gate   chan struct{}
+	readCh chan struct{}
+}
+
+func newEvictingMockStore() *evictingMockStore {
+	return &evictingMockStore{
+		defaultMockStore: NewDefaultMockStore(mockHeader),
+		nonces:           map[types.Address]uint64{},
+		subscribers:      map[chan *blockchain.Event]struct{}{},
+		gate:             make(chan struct{}),
+		readCh:           make(chan struct{}, 1),
+	}
+}
+
+func (m *evictingMockStore) GetBlockByHash(types.Hash, bool) (*types.Block, bool) {
+	select {
+	case m.readCh <- struct{}{}:
+	default:
+	}
+
+	<-m.gate
+
+	return nil, false
+}
+
+func (m *evictingMockStore) GetNonce(_ types.Hash, addr types.Address) uint64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.nonces[addr]
+}
+
+func (m *evictingMockStore) setNonce(addr types.Address, nonce uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.nonces[addr] = nonce
+}
+
+func (m *evictingMockStore) SubscribeEvents() (<-chan *blockchain.Event, func()) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	ch := make(chan *blockchain.Event, 1)
+	m.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+
+		m.unsubscribe(ch)
+	}
+}
+
+func (m *evictingMockStore) unsubscribe(ch chan *blockchain.Event) {
+	if _, ok := m.subscribers[ch]; ok {
+		delete(m.subscribers, ch)
+		close(ch)
+	}
+}
+
+// push sends the event to the subscribers, the ones with a full buffer are unsubscribed
+func (m *evictingMockStore) push(evnt *blockchain.Event) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for ch := range m.subscribers {
+		select {
+		case ch <- evnt:
+		default:
+			m.unsubscribe(ch)
+		}
+	}
+}
+
+func (m *evictingMockStore) numSubscribers() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return len(m.subscribers)
+}
+
 type faultyMockStore struct {
 }
 
@@ -56,6 +173,10 @@ func (fms faultyMockStore) GetBlockByHash(hash types.Hash, b bool) (*types.Block
 	return nil, false
 }
 
+func (fms faultyMockStore) SubscribeEvents() (<-chan *blockchain.Event, func()) {
+	return make(chan *blockchain.Event), func() {}
+}
+
 func (fms faultyMockStore) GetBalance(root types.Hash, addr types.Address) (*big.Int, error) {
 	return nil, fmt.Errorf("unable to fetch account state")
 }
@@ -9,10 +9,13 @@ import (
 	empty "google.golang.org/protobuf/types/known/emptypb"
 )
 
-// Status implements the GRPC status endpoint. Returns the number of transactions in the pool
+// Status implements the GRPC status endpoint. Returns the number of transactions in the pool,
+// and the number of slots they occupy
 func (p *TxPool) Status(ctx context.Context, req *empty.Empty) (*proto.TxnPoolStatusResp, error) {
 	resp := &proto.TxnPoolStatusResp{
-		Length: p.accounts.promoted(),
+		Length:   p.accounts.promoted(),
+		Slots:    p.gauge.read(),
+		MaxSlots: p.gauge.max,
 	}
 
 	return resp, nil
@@ -78,3 +81,15 @@ func (p *TxPool) Subscribe(
 		}
 	}
 }
+
+// SubscribeTxEvents subscribes to new events of the given types in the tx pool, for the in-process listeners.
+// The returned function cancels the subscription and closes the events channel
+func (p *TxPool) SubscribeTxEvents(eventTypes ...proto.EventType) (<-chan *proto.TxPoolEvent, func()) {
+	subscription := p.eventManager.subscribe(eventTypes)
+
+	cancel := func() {
+		p.eventManager.cancelSubscription(subscription.subscriptionID)
+	}
+
+	return subscription.subscriptionChannel, cancel
+}
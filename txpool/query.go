@@ -7,7 +7,8 @@ import "github.com/0xPolygon/polygon-edge/types"
 
 // GetNonce returns the next nonce for the account
 //
-// -> Returns the value from the TxPool if the account is initialized in-memory
+// -> Returns the value from the TxPool if the account is initialized in-memory,
+// following the enqueued transactions in sequence with the promoted ones
 //
 // -> Returns the value from the world state otherwise
 func (p *TxPool) GetNonce(addr types.Address) uint64 {
@@ -19,7 +20,7 @@ func (p *TxPool) GetNonce(addr types.Address) uint64 {
 		return stateNonce
 	}
 
-	return account.getNonce()
+	return account.pendingNonce()
 }
 
 // GetCapacity returns the current number of slots
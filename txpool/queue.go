@@ -1,7 +1,9 @@
 package txpool
 
 import (
+	"bytes"
 	"container/heap"
+	"math/big"
 	"sync"
 	"sync/atomic"
 
@@ -75,6 +77,63 @@ func (q *accountQueue) clear() (removed []*types.Transaction) {
 	return
 }
 
+// get returns the transaction with the given nonce, or nil if the queue doesn't have it.
+func (q *accountQueue) get(nonce uint64) *types.Transaction {
+	for _, tx := range q.queue {
+		if tx.Nonce == nonce {
+			return tx
+		}
+	}
+
+	return nil
+}
+
+// replace swaps the transaction with the same nonce for the given one.
+// The nonce order of the queue is kept, as both transactions have the same nonce.
+func (q *accountQueue) replace(tx *types.Transaction) {
+	for i, queued := range q.queue {
+		if queued.Nonce == tx.Nonce {
+			q.queue[i] = tx
+
+			return
+		}
+	}
+}
+
+// last returns the transaction with the highest nonce, or nil if the queue is empty.
+func (q *accountQueue) last() *types.Transaction {
+	var last *types.Transaction
+
+	for _, tx := range q.queue {
+		if last == nil || tx.Nonce > last.Nonce {
+			last = tx
+		}
+	}
+
+	return last
+}
+
+// remove removes the given transaction from the queue, it returns false if the queue doesn't have it.
+func (q *accountQueue) remove(tx *types.Transaction) bool {
+	for i, queued := range q.queue {
+		if queued == tx {
+			heap.Remove(&q.queue, i)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// copy returns a copy of the queued transactions, which isn't affected by later changes of the queue.
+func (q *accountQueue) copy() []*types.Transaction {
+	txs := make([]*types.Transaction, len(q.queue))
+	copy(txs, q.queue)
+
+	return txs
+}
+
 // push pushes the given transactions onto the queue.
 func (q *accountQueue) push(tx *types.Transaction) {
 	heap.Push(&q.queue, tx)
@@ -151,13 +210,18 @@ func (q *minNonceQueue) Pop() interface{} {
 	return x
 }
 
+// pricedQueue is the queue of the executable transactions, one per account.
+// The transactions are popped by the tip they pay, the next transaction of the account
+// is pushed once the previous one is popped, which keeps the nonce order of each account
 type pricedQueue struct {
 	queue maxPriceQueue
 }
 
 func newPricedQueue() *pricedQueue {
 	q := pricedQueue{
-		queue: make(maxPriceQueue, 0),
+		queue: maxPriceQueue{
+			txs: make([]*pricedTx, 0),
+		},
 	}
 
 	heap.Init(&q.queue)
@@ -167,12 +231,24 @@ func newPricedQueue() *pricedQueue {
 
 // clear empties the underlying queue.
 func (q *pricedQueue) clear() {
-	q.queue = q.queue[:0]
+	q.queue.txs = q.queue.txs[:0]
+}
+
+// setBaseFee sets the base fee the transactions tips are calculated against.
+// The queue has to be empty, the order of the queued transactions depends on the base fee
+func (q *pricedQueue) setBaseFee(baseFee uint64) {
+	q.queue.baseFee = nil
+	if baseFee != 0 {
+		q.queue.baseFee = new(big.Int).SetUint64(baseFee)
+	}
 }
 
 // Pushes the given transactions onto the queue.
 func (q *pricedQueue) push(tx *types.Transaction) {
-	heap.Push(&q.queue, tx)
+	heap.Push(&q.queue, &pricedTx{
+		tx:  tx,
+		tip: tx.EffectiveTip(q.queue.baseFee),
+	})
 }
 
 // Pop removes the first transaction from the queue
@@ -182,12 +258,12 @@ func (q *pricedQueue) pop() *types.Transaction {
 		return nil
 	}
 
-	transaction, ok := heap.Pop(&q.queue).(*types.Transaction)
+	transaction, ok := heap.Pop(&q.queue).(*pricedTx)
 	if !ok {
 		return nil
 	}
 
-	return transaction
+	return transaction.tx
 }
 
 // length returns the number of transactions in the queue.
@@ -195,8 +271,21 @@ func (q *pricedQueue) length() uint64 {
 	return uint64(q.queue.Len())
 }
 
-// transactions sorted by gas price (descending)
-type maxPriceQueue []*types.Transaction
+// pricedTx is a queued transaction with the tip it pays,
+// calculated once when the transaction is pushed
+type pricedTx struct {
+	tx  *types.Transaction
+	tip *big.Int
+}
+
+// transactions sorted by the tip paid on top of the base fee (descending).
+// The tip is the gas price without a base fee.
+// The transactions paying the same tip are sorted by hash (ascending),
+// so the validators with the same transactions build the blocks in the same order
+type maxPriceQueue struct {
+	baseFee *big.Int
+	txs     []*pricedTx
+}
 
 /* Queue methods required by the heap interface */
 
@@ -205,35 +294,42 @@ func (q *maxPriceQueue) Peek() *types.Transaction {
 		return nil
 	}
 
-	return (*q)[0]
+	return q.txs[0].tx
 }
 
 func (q *maxPriceQueue) Len() int {
-	return len(*q)
+	return len(q.txs)
 }
 
 func (q *maxPriceQueue) Swap(i, j int) {
-	(*q)[i], (*q)[j] = (*q)[j], (*q)[i]
+	q.txs[i], q.txs[j] = q.txs[j], q.txs[i]
 }
 
 func (q *maxPriceQueue) Less(i, j int) bool {
-	return (*q)[i].GasPrice.Uint64() > (*q)[j].GasPrice.Uint64()
+	switch q.txs[i].tip.Cmp(q.txs[j].tip) {
+	case 1:
+		return true
+	case -1:
+		return false
+	}
+
+	return bytes.Compare(q.txs[i].tx.Hash[:], q.txs[j].tx.Hash[:]) < 0
 }
 
 func (q *maxPriceQueue) Push(x interface{}) {
-	transaction, ok := x.(*types.Transaction)
+	transaction, ok := x.(*pricedTx)
 	if !ok {
 		return
 	}
 
-	*q = append(*q, transaction)
+	q.txs = append(q.txs, transaction)
 }
 
 func (q *maxPriceQueue) Pop() interface{} {
-	old := q
-	n := len(*old)
-	x := (*old)[n-1]
-	*q = (*old)[0 : n-1]
+	n := len(q.txs)
+	x := q.txs[n-1]
+	q.txs[n-1] = nil
+	q.txs = q.txs[0 : n-1]
 
 	return x
 }
@@ -0,0 +1,93 @@
+package txpool
+
+import (
+	"github.com/0xPolygon/polygon-edge/txpool/proto"
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// Snapshot returns the promoted and the enqueued transactions of the pool,
+// the ones of each account in nonce order
+func (p *TxPool) Snapshot() []*types.Transaction {
+	promoted, enqueued := p.GetTxs(true)
+
+	txs := make([]*types.Transaction, 0)
+
+	for addr, accountTxs := range promoted {
+		txs = append(txs, accountTxs...)
+		txs = append(txs, enqueued[addr]...)
+
+		delete(enqueued, addr)
+	}
+
+	for _, accountTxs := range enqueued {
+		txs = append(txs, accountTxs...)
+	}
+
+	return txs
+}
+
+// Restore replaces the transactions of the pool with the ones of a snapshot, once the head
+// of the chain is moved back to the block of the snapshot. The nonces of the accounts are reset
+// to the state of the head, and the transactions are enqueued and promoted before it returns
+func (p *TxPool) Restore(txs []*types.Transaction) {
+	stateRoot := p.store.Header().StateRoot
+
+	// drop the transactions of all the accounts
+	p.accounts.Range(func(key, value interface{}) bool {
+		addr, _ := key.(types.Address)
+		account := p.accounts.get(addr)
+
+		account.promoted.lock(true)
+		account.enqueued.lock(true)
+
+		dropped := account.promoted.clear()
+		p.metrics.PendingTxs.Add(float64(-1 * len(dropped)))
+
+		dropped = append(dropped, account.enqueued.clear()...)
+
+		account.setNonce(p.store.GetNonce(stateRoot, addr))
+
+		account.enqueued.unlock()
+		account.promoted.unlock()
+
+		p.index.remove(dropped...)
+		p.gauge.decrease(slotsRequired(dropped...))
+
+		if len(dropped) != 0 {
+			p.eventManager.signalEvent(proto.EventType_DROPPED, toHash(dropped...)...)
+		}
+
+		return true
+	})
+
+	restored := make(map[types.Address]*account)
+
+	for _, tx := range txs {
+		if err := p.validateTx(local, tx); err != nil {
+			p.logger.Warn("skipping restored transaction", "hash", tx.Hash.String(), "err", err)
+
+			continue
+		}
+
+		account := p.createAccountOnce(tx.From)
+
+		if _, _, err := account.enqueue(tx, p.priceBump, p.maxAccountEnqueued); err != nil {
+			p.logger.Warn("skipping restored transaction", "hash", tx.Hash.String(), "err", err)
+
+			continue
+		}
+
+		p.index.add(tx)
+		p.gauge.increase(slotsRequired(tx))
+		p.eventManager.signalEvent(proto.EventType_ENQUEUED, tx.Hash)
+
+		restored[tx.From] = account
+	}
+
+	for _, account := range restored {
+		promoted := account.promote(p.maxAccountPromoted)
+
+		p.metrics.PendingTxs.Add(float64(len(promoted)))
+		p.eventManager.signalEvent(proto.EventType_PROMOTED, toHash(promoted...)...)
+	}
+}
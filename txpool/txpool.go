@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/any"
 	"github.com/hashicorp/go-hclog"
@@ -11,8 +14,10 @@ import (
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
 	"github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 )
@@ -21,24 +26,51 @@ const (
 	txSlotSize  = 32 * 1024  // 32kB
 	txMaxSize   = 128 * 1024 //128Kb
 	topicNameV1 = "txpool/0.1"
+
+	// defaultJournalRotate is the journal rotation interval, if none is configured
+	defaultJournalRotate = time.Hour
 )
 
 // errors
 var (
-	ErrIntrinsicGas        = errors.New("intrinsic gas too low")
-	ErrBlockLimitExceeded  = errors.New("exceeds block gas limit")
-	ErrNegativeValue       = errors.New("negative value")
-	ErrNonEncryptedTx      = errors.New("non-encrypted transaction")
-	ErrInvalidSender       = errors.New("invalid sender")
-	ErrTxPoolOverflow      = errors.New("txpool is full")
-	ErrUnderpriced         = errors.New("transaction underpriced")
-	ErrNonceTooLow         = errors.New("nonce too low")
-	ErrInsufficientFunds   = errors.New("insufficient funds for gas * price + value")
-	ErrInvalidAccountState = errors.New("invalid account state")
-	ErrAlreadyKnown        = errors.New("already known")
-	ErrOversizedData       = errors.New("oversized data")
+	ErrIntrinsicGas            = errors.New("intrinsic gas too low")
+	ErrBlockLimitExceeded      = errors.New("exceeds block gas limit")
+	ErrNegativeValue           = errors.New("negative value")
+	ErrNonEncryptedTx          = errors.New("non-encrypted transaction")
+	ErrInvalidSender           = errors.New("invalid sender")
+	ErrTxPoolOverflow          = errors.New("txpool is full")
+	ErrUnderpriced             = errors.New("transaction underpriced")
+	ErrNonceTooLow             = errors.New("nonce too low")
+	ErrInsufficientFunds       = errors.New("insufficient funds for gas * price + value")
+	ErrInvalidAccountState     = errors.New("invalid account state")
+	ErrAlreadyKnown            = errors.New("already known")
+	ErrOversizedData           = errors.New("oversized data")
+	ErrMaxInitCodeSizeExceeded = errors.New("max initcode size exceeded")
+	ErrTxTypeNotSupported      = errors.New("transaction type not supported")
+	ErrTipAboveFeeCap          = errors.New("max priority fee per gas higher than max fee per gas")
+	ErrAccessListNotEmpty      = errors.New("access lists are not supported")
+	ErrReplacementUnderpriced  = errors.New("replacement transaction underpriced")
+	ErrMaxEnqueuedLimitReached = errors.New("maximum number of enqueued transactions reached")
+	ErrTxPoolStopped           = errors.New("txpool is shutting down")
+	ErrUnprotectedTx           = errors.New("only replay-protected transactions allowed")
+	ErrInvalidChainID          = errors.New("invalid chain id")
 )
 
+// TxRejectedError is returned by AddTx for the transactions the pool doesn't accept.
+// The reason is one of the errors above, so callers can tell the rejections apart
+// without matching the error messages
+type TxRejectedError struct {
+	Reason error
+}
+
+func (e *TxRejectedError) Error() string {
+	return e.Reason.Error()
+}
+
+func (e *TxRejectedError) Unwrap() error {
+	return e.Reason
+}
+
 // indicates origin of a transaction
 type txOrigin int
 
@@ -67,6 +99,7 @@ type store interface {
 	GetNonce(root types.Hash, addr types.Address) uint64
 	GetBalance(root types.Hash, addr types.Address) (*big.Int, error)
 	GetBlockByHash(types.Hash, bool) (*types.Block, bool)
+	SubscribeEvents() (<-chan *blockchain.Event, func())
 }
 
 type signer interface {
@@ -74,9 +107,32 @@ type signer interface {
 }
 
 type Config struct {
-	PriceLimit uint64
-	MaxSlots   uint64
-	Sealing    bool
+	PriceLimit         uint64
+	PriceBump          uint64
+	MaxSlots           uint64
+	MaxAccountEnqueued uint64
+	MaxAccountPromoted uint64
+	Sealing            bool
+
+	// JournalPath is the file the local transactions are journaled to,
+	// the transactions are not journaled if not set
+	JournalPath   string
+	JournalRotate time.Duration
+
+	// ReplayProtection is the fork from which only the replay protected transactions are accepted
+	ReplayProtection *chain.Fork
+
+	// SizeLimitsFork is the fork from which the transactions are checked against the size limits
+	SizeLimitsFork *chain.Fork
+	SizeLimits     chain.SizeLimits
+
+	// AllowLocalUnderpriced exempts the local transactions from the price limit,
+	// and from the eviction below the base fee or when the pool is full
+	AllowLocalUnderpriced bool
+
+	// UnderpricedEvictionBlocks is the number of blocks a transaction can stay in the pool
+	// with its fee cap below the base fee before it is evicted, 0 for no eviction
+	UnderpricedEvictionBlocks uint64
 }
 
 /* All requests are passed to the main loop
@@ -122,6 +178,13 @@ type TxPool struct {
 	forks  chain.ForksInTime
 	store  store
 
+	// replayProtection is the fork from which the unprotected transactions are rejected, nil if never
+	replayProtection *chain.Fork
+
+	// sizeLimitsFork is the fork from which the sizeLimits are enforced, nil if never
+	sizeLimitsFork *chain.Fork
+	sizeLimits     chain.SizeLimits
+
 	// map of all accounts registered by the pool
 	accounts accountsMap
 
@@ -138,9 +201,35 @@ type TxPool struct {
 	// gauge for measuring pool capacity
 	gauge slotGauge
 
+	// lock making room in the pool, so the
+	// same tx isn't evicted twice
+	evictLock sync.Mutex
+
 	// priceLimit is a lower threshold for gas price
 	priceLimit uint64
 
+	// allowLocalUnderpriced exempts the local transactions from the price limit
+	// and from the eviction below the base fee or when the pool is full
+	allowLocalUnderpriced bool
+
+	// underpricedBlocks is the number of blocks a transaction can stay below the base fee
+	// before it is evicted, 0 for no eviction
+	underpricedBlocks uint64
+
+	// the height each pooled transaction was first seen below the base fee at,
+	// only updated as the new heads are processed
+	underpriced     map[types.Hash]uint64
+	underpricedLock sync.Mutex
+
+	// priceBump is the minimum gas price increase (in percent)
+	// for a transaction to replace another one with the same nonce
+	priceBump uint64
+
+	// per account limits of the enqueued and promoted
+	// transactions, 0 for no limit
+	maxAccountEnqueued uint64
+	maxAccountPromoted uint64
+
 	// channels on which the pool's event loop
 	// does dispatching/handling requests.
 	enqueueReqCh chan enqueueRequest
@@ -149,10 +238,26 @@ type TxPool struct {
 	// shutdown channel
 	shutdownCh chan struct{}
 
+	// set once the pool stops accepting the new transactions, before the shutdown
+	stopped uint32
+
+	// unsubscribes from the chain reorganizations,
+	// the transactions of the dropped blocks are returned to the pool
+	cancelReorgs func()
+
+	// guards the subscription to the chain reorganizations, which is renewed
+	// once the pool is unsubscribed as a slow consumer, until the pool is closed
+	reorgsLock   sync.Mutex
+	reorgsClosed bool
+
 	// flag indicating if the current node is a sealer,
 	// and should therefore gossip transactions
 	sealing bool
 
+	// journal of the local transactions, nil if journaling is disabled
+	journal       *txJournal
+	journalRotate time.Duration
+
 	// prometheus API
 	metrics *Metrics
 
@@ -174,21 +279,45 @@ func NewTxPool(
 	config *Config,
 ) (*TxPool, error) {
 	pool := &TxPool{
-		logger:      logger.Named("txpool"),
-		forks:       forks,
-		store:       store,
-		metrics:     metrics,
-		accounts:    accountsMap{},
-		executables: newPricedQueue(),
-		index:       lookupMap{all: make(map[types.Hash]*types.Transaction)},
-		gauge:       slotGauge{height: 0, max: config.MaxSlots},
-		priceLimit:  config.PriceLimit,
-		sealing:     config.Sealing,
+		logger:             logger.Named("txpool"),
+		forks:              forks,
+		store:              store,
+		metrics:            metrics,
+		accounts:           accountsMap{},
+		executables:        newPricedQueue(),
+		index:              lookupMap{all: make(map[types.Hash]*types.Transaction)},
+		gauge:              slotGauge{height: 0, max: config.MaxSlots},
+		priceLimit:         config.PriceLimit,
+		priceBump:          config.PriceBump,
+		underpricedBlocks:  config.UnderpricedEvictionBlocks,
+		underpriced:        make(map[types.Hash]uint64),
+		maxAccountEnqueued: config.MaxAccountEnqueued,
+		maxAccountPromoted: config.MaxAccountPromoted,
+		sealing:            config.Sealing,
+		replayProtection:   config.ReplayProtection,
+		sizeLimitsFork:     config.SizeLimitsFork,
+		sizeLimits:         config.SizeLimits,
+
+		allowLocalUnderpriced: config.AllowLocalUnderpriced,
 	}
 
 	// Attach the event manager
 	pool.eventManager = newEventManager(pool.logger)
 
+	if config.JournalPath != "" {
+		journal, err := newTxJournal(config.JournalPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open the txpool journal, %w", err)
+		}
+
+		pool.journal = journal
+		pool.journalRotate = config.JournalRotate
+
+		if pool.journalRotate == 0 {
+			pool.journalRotate = defaultJournalRotate
+		}
+	}
+
 	if network != nil {
 		// subscribe to the gossip protocol
 		topic, err := network.NewTopic(topicNameV1, &proto.Txn{})
@@ -218,11 +347,22 @@ func NewTxPool(
 // Start runs the pool's main loop in the background.
 // On each request received, the appropriate handler
 // is invoked in a separate goroutine.
+// The journaled local transactions are replayed once the loop is running.
 func (p *TxPool) Start() {
 	// set default value of txpool pending transactions gauge
 	p.metrics.PendingTxs.Set(0)
 
 	go func() {
+		// the journal is only rotated if journaling is enabled
+		var rotateCh <-chan time.Time
+
+		if p.journal != nil {
+			ticker := time.NewTicker(p.journalRotate)
+			defer ticker.Stop()
+
+			rotateCh = ticker.C
+		}
+
 		for {
 			select {
 			case <-p.shutdownCh:
@@ -231,15 +371,92 @@ func (p *TxPool) Start() {
 				go p.handleEnqueueRequest(req)
 			case req := <-p.promoteReqCh:
 				go p.handlePromoteRequest(req)
+			case <-rotateCh:
+				go p.rotateJournal()
 			}
 		}
 	}()
+
+	if reorgCh, ok := p.subscribeReorgs(); ok {
+		go p.watchReorgs(reorgCh)
+	}
+
+	if p.journal != nil {
+		p.loadJournal()
+	}
+}
+
+// StopAccepting rejects the new local and gossiped transactions, before the node shuts down.
+// The transactions in the pool are kept, and can still be written to the blocks
+func (p *TxPool) StopAccepting() {
+	atomic.StoreUint32(&p.stopped, 1)
+}
+
+func (p *TxPool) isStopped() bool {
+	return atomic.LoadUint32(&p.stopped) == 1
 }
 
 // Close shuts down the pool's main loop.
 func (p *TxPool) Close() {
 	p.eventManager.Close()
 	p.shutdownCh <- struct{}{}
+
+	p.reorgsLock.Lock()
+	p.reorgsClosed = true
+
+	if p.cancelReorgs != nil {
+		p.cancelReorgs()
+	}
+	p.reorgsLock.Unlock()
+
+	if p.journal != nil {
+		if err := p.journal.close(); err != nil {
+			p.logger.Error("failed to close the journal", "err", err)
+		}
+	}
+}
+
+// loadJournal replays the journaled local transactions, validating them against
+// the current state. The transactions that are not valid anymore are skipped,
+// and the journal is rotated so it only keeps the ones that made it into the pool
+func (p *TxPool) loadJournal() {
+	loaded, dropped, err := p.journal.load(func(tx *types.Transaction) error {
+		if err := p.addTx(local, tx); err != nil {
+			p.logger.Warn("skipping journaled transaction", "hash", tx.Hash.String(), "err", err)
+
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		p.logger.Error("failed to load the journal", "err", err)
+	}
+
+	p.logger.Info("loaded journaled transactions", "loaded", loaded, "dropped", dropped)
+
+	p.rotateJournal()
+}
+
+// rotateJournal rewrites the journal with the local transactions still in the pool
+func (p *TxPool) rotateJournal() {
+	promoted, enqueued := p.GetTxs(true)
+
+	// the promoted transactions of an account have lower nonces
+	// than the enqueued ones, so they are replayed first
+	txs := make([]*types.Transaction, 0)
+
+	for _, accountTxs := range promoted {
+		txs = append(txs, accountTxs...)
+	}
+
+	for _, accountTxs := range enqueued {
+		txs = append(txs, accountTxs...)
+	}
+
+	if err := p.journal.rotate(txs); err != nil {
+		p.logger.Error("failed to rotate the journal", "err", err)
+	}
 }
 
 // SetSigner sets the signer the pool will use
@@ -251,10 +468,21 @@ func (p *TxPool) SetSigner(s signer) {
 // AddTx adds a new transaction to the pool (sent from json-RPC/gRPC endpoints)
 // and broadcasts it to the network (if enabled).
 func (p *TxPool) AddTx(tx *types.Transaction) error {
+	if p.isStopped() {
+		return &TxRejectedError{Reason: ErrTxPoolStopped}
+	}
+
 	if err := p.addTx(local, tx); err != nil {
 		p.logger.Error("failed to add tx", "err", err)
 
-		return err
+		return &TxRejectedError{Reason: err}
+	}
+
+	// only the local transactions are journaled
+	if p.journal != nil {
+		if err := p.journal.insert(tx); err != nil {
+			p.logger.Error("failed to journal tx", "err", err)
+		}
 	}
 
 	// broadcast the transaction only if a topic
@@ -276,12 +504,17 @@ func (p *TxPool) AddTx(tx *types.Transaction) error {
 
 // Prepare generates all the transactions
 // ready for execution. (primaries)
-func (p *TxPool) Prepare() {
+// The transactions are sorted by the tip they pay
+// on top of the base fee of the block being built,
+// and by hash if they pay the same tip.
+func (p *TxPool) Prepare(baseFee uint64) {
 	// clear from previous round
 	if p.executables.length() != 0 {
 		p.executables.clear()
 	}
 
+	p.executables.setBaseFee(baseFee)
+
 	// fetch primary from each account
 	primaries := p.accounts.getPrimaries()
 
@@ -312,7 +545,6 @@ func (p *TxPool) Pop(tx *types.Transaction) {
 	account := p.accounts.get(tx.From)
 
 	account.promoted.lock(true)
-	defer account.promoted.unlock()
 
 	// pop the top most promoted tx
 	account.promoted.pop()
@@ -323,6 +555,16 @@ func (p *TxPool) Pop(tx *types.Transaction) {
 	// update metrics
 	p.metrics.PendingTxs.Add(-1)
 
+	account.promoted.unlock()
+
+	if p.maxAccountPromoted != 0 {
+		// promote the enqueued txs held back by the promoted limit
+		p.handlePromoteRequest(promoteRequest{account: tx.From})
+	}
+
+	account.promoted.lock(false)
+	defer account.promoted.unlock()
+
 	// update executables
 	if tx := account.promoted.peek(); tx != nil {
 		p.executables.push(tx)
@@ -392,6 +634,160 @@ func (p *TxPool) ResetWithHeaders(headers ...*types.Header) {
 	// process the txs in the event
 	// to make sure the pool is up-to-date
 	p.processEvent(e)
+
+	if len(headers) != 0 {
+		p.evictUnderpriced(headers[len(headers)-1])
+	}
+}
+
+// evictUnderpriced evicts the transactions whose fee cap stayed below the base fee
+// for more than underpricedBlocks blocks, up to the given head. The higher nonce transactions
+// of their accounts are evicted along, as they can't be executed without them
+func (p *TxPool) evictUnderpriced(head *types.Header) {
+	if p.underpricedBlocks == 0 {
+		return
+	}
+
+	p.underpricedLock.Lock()
+	defer p.underpricedLock.Unlock()
+
+	baseFee := head.GetBaseFee()
+	if baseFee == nil {
+		// nothing is underpriced before the EIP-1559 fork
+		p.underpriced = make(map[types.Hash]uint64)
+
+		return
+	}
+
+	// the lowest evicted nonce of each account
+	evictFrom := make(map[types.Address]uint64)
+	underpriced := make(map[types.Hash]uint64)
+
+	track := func(addr types.Address, txs []*types.Transaction) {
+		if p.allowLocalUnderpriced && p.accounts.get(addr).isLocal() {
+			return
+		}
+
+		for _, tx := range txs {
+			if tx.GetGasFeeCap().Cmp(baseFee) >= 0 {
+				continue
+			}
+
+			since, ok := p.underpriced[tx.Hash]
+			if !ok {
+				since = head.Number
+			}
+
+			underpriced[tx.Hash] = since
+
+			if head.Number-since < p.underpricedBlocks {
+				continue
+			}
+
+			if nonce, ok := evictFrom[addr]; !ok || tx.Nonce < nonce {
+				evictFrom[addr] = tx.Nonce
+			}
+		}
+	}
+
+	promoted, enqueued := p.accounts.allTxs(true)
+
+	for addr, txs := range promoted {
+		track(addr, txs)
+	}
+
+	for addr, txs := range enqueued {
+		track(addr, txs)
+	}
+
+	p.underpriced = underpriced
+
+	for addr, nonce := range evictFrom {
+		evictedPromoted, evictedEnqueued := p.accounts.get(addr).evictFrom(nonce)
+		evicted := append(evictedPromoted, evictedEnqueued...)
+
+		for _, tx := range evicted {
+			delete(p.underpriced, tx.Hash)
+		}
+
+		p.index.remove(evicted...)
+		p.gauge.decrease(slotsRequired(evicted...))
+
+		p.metrics.PendingTxs.Add(float64(-1 * len(evictedPromoted)))
+		p.metrics.UnderpricedEvictedTxs.Add(float64(len(evicted)))
+
+		p.eventManager.signalEvent(proto.EventType_EVICTED_UNDERPRICED, toHash(evicted...)...)
+		p.logger.Debug("evicted underpriced txs",
+			"num", len(evicted),
+			"next_nonce", nonce,
+			"address", addr.String(),
+			"base_fee", baseFee,
+		)
+	}
+}
+
+// subscribeReorgs subscribes to the chain events, unless the pool is closed
+func (p *TxPool) subscribeReorgs() (<-chan *blockchain.Event, bool) {
+	p.reorgsLock.Lock()
+	defer p.reorgsLock.Unlock()
+
+	if p.reorgsClosed {
+		return nil, false
+	}
+
+	eventCh, cancel := p.store.SubscribeEvents()
+	p.cancelReorgs = cancel
+
+	return eventCh, true
+}
+
+// watchReorgs processes the chain reorganizations until the pool is closed.
+// The new heads are processed through ResetWithHeaders. Once unsubscribed as a slow consumer,
+// the pool subscribes again and resyncs its accounts with the head, as the missed events can't be replayed
+func (p *TxPool) watchReorgs(eventCh <-chan *blockchain.Event) {
+	for {
+		for evnt := range eventCh {
+			if evnt.Type != blockchain.EventReorg {
+				continue
+			}
+
+			p.logger.Debug("processing chain reorg", "dropped", len(evnt.OldChain), "added", len(evnt.NewChain))
+
+			p.processEvent(evnt)
+		}
+
+		var ok bool
+		if eventCh, ok = p.subscribeReorgs(); !ok {
+			return
+		}
+
+		p.logger.Warn("unsubscribed from the chain events, resyncing the accounts with the head")
+
+		p.resyncAccounts()
+	}
+}
+
+// resyncAccounts resets all the accounts of the pool to their nonces at the head of the chain
+func (p *TxPool) resyncAccounts() {
+	head := p.store.Header()
+	stateNonces := make(map[types.Address]uint64)
+
+	p.accounts.Range(func(key, value interface{}) bool {
+		addr, ok := key.(types.Address)
+		if !ok {
+			return true
+		}
+
+		stateNonces[addr] = p.store.GetNonce(head.StateRoot, addr)
+
+		return true
+	})
+
+	if len(stateNonces) != 0 {
+		p.resetAccounts(stateNonces)
+	}
+
+	p.evictUnderpriced(head)
 }
 
 // processEvent collects the latest nonces for each account containted
@@ -466,22 +862,44 @@ func (p *TxPool) processEvent(event *blockchain.Event) {
 
 // validateTx ensures the transaction conforms to specific
 // constraints before entering the pool.
-func (p *TxPool) validateTx(tx *types.Transaction) error {
+func (p *TxPool) validateTx(origin txOrigin, tx *types.Transaction) error {
 	// Check the transaction size to overcome DOS Attacks
 	if uint64(len(tx.MarshalRLP())) > txMaxSize {
 		return ErrOversizedData
 	}
 
+	// Check the init code of the contract creation is within the size limit, as in EIP-3860
+	if p.forks.EIP3860 && tx.IsContractCreation() && len(tx.Input) > runtime.MaxInitCodeSize {
+		return ErrMaxInitCodeSizeExceeded
+	}
+
 	// Check if the transaction has a strictly positive value
 	if tx.Value.Sign() < 0 {
 		return ErrNegativeValue
 	}
 
+	// Grab the latest block
+	latestHeader := p.store.Header()
+
+	// Check the transaction is replay protected past the fork, it would be rejected by the next block
+	if p.replayProtection != nil && p.replayProtection.Active(latestHeader.Number+1) && !tx.IsReplayProtected() {
+		return ErrUnprotectedTx
+	}
+
+	// Check the transaction is within the size limits past the fork, it couldn't be included in the next block
+	if p.sizeLimitsFork != nil && p.sizeLimitsFork.Active(latestHeader.Number+1) {
+		if err := p.sizeLimits.VerifyTx(tx); err != nil {
+			return err
+		}
+	}
+
 	// Check if the transaction is signed properly
 
-	// Extract the sender
+	// Extract the sender, the chain id is checked along with the signature
 	from, signerErr := p.signer.Sender(tx)
-	if signerErr != nil {
+	if errors.Is(signerErr, crypto.ErrInvalidChainID) {
+		return ErrInvalidChainID
+	} else if signerErr != nil {
 		return ErrInvalidSender
 	}
 
@@ -497,13 +915,17 @@ func (p *TxPool) validateTx(tx *types.Transaction) error {
 		tx.From = from
 	}
 
-	// Reject underpriced transactions
-	if tx.IsUnderpriced(p.priceLimit) {
+	// Reject underpriced transactions, the local ones may be exempt
+	if tx.IsUnderpriced(p.priceLimit) && !(origin == local && p.allowLocalUnderpriced) {
 		return ErrUnderpriced
 	}
 
+	if err := p.validateFees(tx, latestHeader); err != nil {
+		return err
+	}
+
 	// Grab the state root for the latest block
-	stateRoot := p.store.Header().StateRoot
+	stateRoot := latestHeader.StateRoot
 
 	// Check nonce ordering
 	if p.store.GetNonce(stateRoot, tx.From) > tx.Nonce {
@@ -521,7 +943,7 @@ func (p *TxPool) validateTx(tx *types.Transaction) error {
 	}
 
 	// Make sure the transaction has more gas than the basic transaction fee
-	intrinsicGas, err := state.TransactionGasCost(tx, p.forks.Homestead, p.forks.Istanbul)
+	intrinsicGas, err := state.TransactionGasCost(tx, p.forks.Homestead, p.forks.Istanbul, p.forks.EIP3860)
 	if err != nil {
 		return err
 	}
@@ -531,7 +953,7 @@ func (p *TxPool) validateTx(tx *types.Transaction) error {
 	}
 
 	// Grab the block gas limit for the latest block
-	latestBlockGasLimit := p.store.Header().GasLimit
+	latestBlockGasLimit := latestHeader.GasLimit
 
 	if tx.Gas > latestBlockGasLimit {
 		return ErrBlockLimitExceeded
@@ -540,6 +962,41 @@ func (p *TxPool) validateTx(tx *types.Transaction) error {
 	return nil
 }
 
+// validateFees ensures the fees of the transaction
+// can be paid past the latest block
+func (p *TxPool) validateFees(tx *types.Transaction, latestHeader *types.Header) error {
+	// The base fee is part of the blocks past the EIP-1559 fork
+	eip1559 := p.forks.EIP1559 || latestHeader.BaseFee != 0
+
+	if tx.IsDynamicFee() {
+		if !eip1559 {
+			return ErrTxTypeNotSupported
+		}
+
+		if tx.MaxPriorityFeePerGas.Cmp(tx.MaxFeePerGas) > 0 {
+			return ErrTipAboveFeeCap
+		}
+	}
+
+	// The access lists are part of the transactions past the Berlin fork
+	if !p.forks.Berlin {
+		if tx.IsAccessList() {
+			return ErrTxTypeNotSupported
+		}
+
+		if len(tx.AccessList) != 0 {
+			return ErrAccessListNotEmpty
+		}
+	}
+
+	// Reject transactions that can't pay the latest base fee
+	if baseFee := latestHeader.GetBaseFee(); baseFee != nil && tx.GetGasFeeCap().Cmp(baseFee) < 0 {
+		return ErrUnderpriced
+	}
+
+	return nil
+}
+
 // addTx is the main entry point to the pool
 // for all new transactions. If the call is
 // successful, an account is created for this address
@@ -551,15 +1008,10 @@ func (p *TxPool) addTx(origin txOrigin, tx *types.Transaction) error {
 	)
 
 	// validate incoming tx
-	if err := p.validateTx(tx); err != nil {
+	if err := p.validateTx(origin, tx); err != nil {
 		return err
 	}
 
-	// check for overflow
-	if p.gauge.read()+slotsRequired(tx) > p.gauge.max {
-		return ErrTxPoolOverflow
-	}
-
 	tx.ComputeHash()
 
 	// check if already known
@@ -578,11 +1030,29 @@ func (p *TxPool) addTx(origin txOrigin, tx *types.Transaction) error {
 		}
 	}
 
+	// reject replacements that don't bump the gas price enough
+	// and txs over the account limit, the replacement itself
+	// happens once the tx is enqueued
+	if account := p.accounts.get(tx.From); account != nil {
+		if err := account.validateEnqueue(tx, p.priceBump, p.maxAccountEnqueued); err != nil {
+			return err
+		}
+	}
+
+	// check for overflow
+	if err := p.makeRoom(tx); err != nil {
+		return err
+	}
+
 	// initialize account for this address once
 	if !p.accounts.exists(tx.From) {
 		p.createAccountOnce(tx.From)
 	}
 
+	if origin == local {
+		p.accounts.get(tx.From).markLocal()
+	}
+
 	// send request [BLOCKING]
 	p.enqueueReqCh <- enqueueRequest{tx: tx}
 	p.eventManager.signalEvent(proto.EventType_ADDED, tx.Hash)
@@ -602,7 +1072,8 @@ func (p *TxPool) handleEnqueueRequest(req enqueueRequest) {
 	account := p.accounts.get(addr)
 
 	// enqueue tx
-	if err := account.enqueue(tx); err != nil {
+	replaced, promoted, err := account.enqueue(tx, p.priceBump, p.maxAccountEnqueued)
+	if err != nil {
 		p.logger.Error("enqueue request", "err", err)
 
 		return
@@ -614,6 +1085,26 @@ func (p *TxPool) handleEnqueueRequest(req enqueueRequest) {
 	p.index.add(tx)
 	p.gauge.increase(slotsRequired(tx))
 
+	if replaced != nil {
+		p.logger.Debug("replaced tx",
+			"hash", replaced.Hash.String(),
+			"replacement", tx.Hash.String(),
+		)
+
+		// drop the replaced tx
+		p.index.remove(replaced)
+		p.gauge.decrease(slotsRequired(replaced))
+
+		p.eventManager.signalEvent(proto.EventType_REPLACED, replaced.Hash)
+
+		if promoted {
+			// the replacement took the place of a promoted tx
+			p.eventManager.signalEvent(proto.EventType_PROMOTED, tx.Hash)
+
+			return
+		}
+	}
+
 	p.eventManager.signalEvent(proto.EventType_ENQUEUED, tx.Hash)
 
 	if tx.Nonce > account.getNonce() {
@@ -633,7 +1124,7 @@ func (p *TxPool) handlePromoteRequest(req promoteRequest) {
 	account := p.accounts.get(addr)
 
 	// promote enqueued txs
-	promoted := account.promote()
+	promoted := account.promote(p.maxAccountPromoted)
 	p.logger.Debug("promote request", "promoted", promoted, "addr", addr.String())
 
 	// update metrics
@@ -641,10 +1132,55 @@ func (p *TxPool) handlePromoteRequest(req promoteRequest) {
 	p.eventManager.signalEvent(proto.EventType_PROMOTED, toHash(promoted...)...)
 }
 
+// makeRoom ensures the pool has enough free slots for the transaction.
+// If the pool is full, the lowest priced transactions of the other accounts
+// are evicted, as long as they are priced lower than the transaction.
+// The local accounts are kept if they are exempt from the price limit.
+func (p *TxPool) makeRoom(tx *types.Transaction) error {
+	p.evictLock.Lock()
+	defer p.evictLock.Unlock()
+
+	required := slotsRequired(tx)
+	if required > p.gauge.max {
+		return ErrTxPoolOverflow
+	}
+
+	for p.gauge.read()+required > p.gauge.max {
+		account, cheapest := p.accounts.getEvictionCandidate(tx.From, p.allowLocalUnderpriced)
+		if cheapest == nil ||
+			cheapest.GetGasFeeCap().Cmp(tx.GetGasFeeCap()) >= 0 {
+			// nothing cheaper to evict
+			return ErrTxPoolOverflow
+		}
+
+		evicted, promoted := account.evict(cheapest)
+		if !evicted {
+			// the account changed meanwhile, look for another candidate
+			continue
+		}
+
+		// pool resource cleanup
+		p.index.remove(cheapest)
+		p.gauge.decrease(slotsRequired(cheapest))
+
+		if promoted {
+			p.metrics.PendingTxs.Add(-1)
+		}
+
+		p.eventManager.signalEvent(proto.EventType_DROPPED, cheapest.Hash)
+		p.logger.Debug("evicted tx",
+			"hash", cheapest.Hash.String(),
+			"address", cheapest.From.String(),
+		)
+	}
+
+	return nil
+}
+
 // addGossipTx handles receiving transactions
 // gossiped by the network.
 func (p *TxPool) addGossipTx(obj interface{}) {
-	if !p.sealing {
+	if !p.sealing || p.isStopped() {
 		return
 	}
 
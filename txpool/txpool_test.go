@@ -1,15 +1,18 @@
 package txpool
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"math/big"
 	"testing"
 	"time"
 
+	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/helper/tests"
+	"github.com/0xPolygon/polygon-edge/state/runtime"
 	"github.com/0xPolygon/polygon-edge/txpool/proto"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/golang/protobuf/ptypes/any"
@@ -19,6 +22,7 @@ import (
 
 const (
 	defaultPriceLimit uint64 = 1
+	defaultPriceBump  uint64 = 10
 	defaultMaxSlots   uint64 = 4096
 	validGasLimit     uint64 = 4712350
 )
@@ -88,6 +92,7 @@ func newTestPoolWithSlots(maxSlots uint64, mockStore ...store) (*TxPool, error)
 		nilMetrics,
 		&Config{
 			PriceLimit: defaultPriceLimit,
+			PriceBump:  defaultPriceBump,
 			MaxSlots:   maxSlots,
 			Sealing:    false,
 		},
@@ -204,6 +209,23 @@ func TestAddTxErrors(t *testing.T) {
 		)
 	})
 
+	t.Run("ErrUnderpriced local exemption", func(t *testing.T) {
+		t.Parallel()
+		pool := setupPool()
+		pool.priceLimit = 1000000
+		pool.allowLocalUnderpriced = true
+
+		tx := newTx(defaultAddr, 0, 1) // gasPrice == 1
+		tx = signTx(tx)
+
+		// only the local transactions are exempt from the price limit
+		assert.NoError(t, pool.validateTx(local, tx))
+		assert.ErrorIs(t,
+			pool.validateTx(gossip, tx),
+			ErrUnderpriced,
+		)
+	})
+
 	t.Run("ErrInvalidAccountState", func(t *testing.T) {
 		t.Parallel()
 		pool := setupPool()
@@ -292,6 +314,87 @@ func TestAddTxErrors(t *testing.T) {
 		)
 	})
 
+	t.Run("ErrMaxInitCodeSizeExceeded", func(t *testing.T) {
+		t.Parallel()
+		pool := setupPool()
+		pool.forks.EIP3860 = true
+
+		tx := newTx(defaultAddr, 0, 1)
+		tx.To = nil
+		tx.Input = make([]byte, runtime.MaxInitCodeSize+1)
+		tx = signTx(tx)
+
+		assert.ErrorIs(t,
+			pool.addTx(local, tx),
+			ErrMaxInitCodeSizeExceeded,
+		)
+	})
+
+	t.Run("ErrUnprotectedTx", func(t *testing.T) {
+		t.Parallel()
+		pool := setupPool()
+
+		tx, err := (&crypto.FrontierSigner{}).SignTx(newTx(defaultAddr, 0, 1), defaultKey)
+		assert.NoError(t, err)
+
+		// the unprotected transactions are accepted before the fork
+		pool.replayProtection = chain.NewFork(2)
+		assert.NoError(t, pool.validateTx(local, tx.Copy()))
+
+		pool = setupPool()
+		pool.replayProtection = chain.NewFork(1)
+
+		assert.ErrorIs(t,
+			pool.addTx(local, tx),
+			ErrUnprotectedTx,
+		)
+	})
+
+	t.Run("ErrTxTooLarge", func(t *testing.T) {
+		t.Parallel()
+		pool := setupPool()
+
+		tx := signTx(newTx(defaultAddr, 0, 1))
+
+		// the size limits are not enforced before the fork
+		pool.sizeLimitsFork = chain.NewFork(2)
+		pool.sizeLimits = chain.SizeLimits{MaxTxSize: tx.Size() - 1}
+		assert.NoError(t, pool.validateTx(local, tx.Copy()))
+
+		pool = setupPool()
+		pool.sizeLimitsFork = chain.NewFork(1)
+		pool.sizeLimits = chain.SizeLimits{MaxTxSize: tx.Size() - 1}
+
+		assert.ErrorIs(t,
+			pool.addTx(local, tx.Copy()),
+			chain.ErrTxTooLarge,
+		)
+
+		// the transaction larger than a block is rejected apart from the oversized ones
+		pool.sizeLimits = chain.SizeLimits{MaxBlockSize: tx.Size() - 1}
+
+		assert.ErrorIs(t,
+			pool.addTx(local, tx.Copy()),
+			chain.ErrBlockTooLarge,
+		)
+
+		pool.sizeLimits = chain.SizeLimits{MaxBlockSize: tx.Size(), MaxTxSize: tx.Size()}
+		assert.NoError(t, pool.validateTx(local, tx))
+	})
+
+	t.Run("ErrInvalidChainID", func(t *testing.T) {
+		t.Parallel()
+		pool := setupPool()
+
+		tx, err := crypto.NewEIP155Signer(1).SignTx(newTx(defaultAddr, 0, 1), defaultKey)
+		assert.NoError(t, err)
+
+		assert.ErrorIs(t,
+			pool.addTx(local, tx),
+			ErrInvalidChainID,
+		)
+	})
+
 	t.Run("ErrNonceTooLow", func(t *testing.T) {
 		t.Parallel()
 		pool := setupPool()
@@ -320,6 +423,23 @@ func TestAddTxErrors(t *testing.T) {
 			ErrInsufficientFunds,
 		)
 	})
+
+	t.Run("TxRejectedError", func(t *testing.T) {
+		t.Parallel()
+		pool := setupPool()
+
+		pool.store = faultyMockStore{}
+		tx := newTx(defaultAddr, 0, 1)
+		tx = signTx(tx)
+
+		// the rejections of AddTx carry the reason
+		err := pool.AddTx(tx)
+
+		var rejected *TxRejectedError
+		if assert.ErrorAs(t, err, &rejected) {
+			assert.Equal(t, ErrNonceTooLow, rejected.Reason)
+		}
+	})
 }
 
 func TestAddGossipTx(t *testing.T) {
@@ -385,6 +505,37 @@ func TestAddGossipTx(t *testing.T) {
 	})
 }
 
+func TestStopAccepting(t *testing.T) {
+	t.Parallel()
+
+	key, sender := tests.GenerateKeyAndAddr(t)
+	signer := crypto.NewEIP155Signer(uint64(100))
+
+	pool, err := newTestPool()
+	assert.NoError(t, err)
+	pool.SetSigner(signer)
+
+	pool.sealing = true
+	pool.createAccountOnce(sender)
+
+	signedTx, err := signer.SignTx(newTx(types.ZeroAddress, 0, 1), key)
+	assert.NoError(t, err)
+
+	pool.StopAccepting()
+
+	// the local transactions are rejected
+	assert.ErrorIs(t, pool.AddTx(signedTx), ErrTxPoolStopped)
+
+	// the gossiped transactions are dropped
+	pool.addGossipTx(&proto.Txn{
+		Raw: &any.Any{
+			Value: signedTx.MarshalRLP(),
+		},
+	})
+
+	assert.Equal(t, uint64(0), pool.accounts.get(sender).enqueued.length())
+}
+
 func TestDropKnownGossipTx(t *testing.T) {
 	pool, err := newTestPool()
 	assert.NoError(t, err)
@@ -611,6 +762,331 @@ func TestPromoteHandler(t *testing.T) {
 	})
 }
 
+func TestReplaceTx(t *testing.T) {
+	t.Parallel()
+
+	// returns a new tx with the given nonce and gas price
+	newPricedTx := func(nonce, gasPrice uint64) *types.Transaction {
+		tx := newTx(addr1, nonce, 1)
+		tx.GasPrice = new(big.Int).SetUint64(gasPrice)
+
+		return tx
+	}
+
+	setupPool := func(t *testing.T) *TxPool {
+		t.Helper()
+
+		pool, err := newTestPool()
+		assert.NoError(t, err)
+		pool.SetSigner(&mockSigner{})
+
+		return pool
+	}
+
+	t.Run("replace enqueued tx", func(t *testing.T) {
+		t.Parallel()
+
+		pool := setupPool(t)
+
+		replacedCh, cancel := pool.SubscribeTxEvents(proto.EventType_REPLACED)
+		defer cancel()
+
+		tx := newPricedTx(5, 100)
+		go func() {
+			assert.NoError(t, pool.addTx(local, tx))
+		}()
+		pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+
+		replacement := newPricedTx(5, 110)
+		go func() {
+			assert.NoError(t, pool.addTx(local, replacement))
+		}()
+		pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+
+		acc := pool.accounts.get(addr1)
+		assert.Equal(t, uint64(1), acc.enqueued.length())
+		assert.Equal(t, replacement.Hash, acc.enqueued.peek().Hash)
+		assert.Equal(t, uint64(1), pool.gauge.read())
+
+		_, known := pool.index.get(tx.Hash)
+		assert.False(t, known)
+
+		select {
+		case event := <-replacedCh:
+			assert.Equal(t, tx.Hash.String(), event.TxHash)
+		case <-time.After(time.Second):
+			t.Fatal("replaced event not received")
+		}
+	})
+
+	t.Run("replace promoted tx", func(t *testing.T) {
+		t.Parallel()
+
+		pool := setupPool(t)
+
+		go func() {
+			assert.NoError(t, pool.addTx(local, newPricedTx(0, 100)))
+		}()
+		go pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+		pool.handlePromoteRequest(<-pool.promoteReqCh)
+
+		replacement := newPricedTx(0, 200)
+		go func() {
+			assert.NoError(t, pool.addTx(local, replacement))
+		}()
+		pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+
+		acc := pool.accounts.get(addr1)
+		assert.Equal(t, uint64(0), acc.enqueued.length())
+		assert.Equal(t, uint64(1), acc.promoted.length())
+		assert.Equal(t, replacement.Hash, acc.promoted.peek().Hash)
+		assert.Equal(t, uint64(1), acc.getNonce())
+		assert.Equal(t, uint64(1), pool.gauge.read())
+	})
+
+	t.Run("reject underpriced replacement", func(t *testing.T) {
+		t.Parallel()
+
+		pool := setupPool(t)
+
+		go func() {
+			assert.NoError(t, pool.addTx(local, newPricedTx(0, 100)))
+		}()
+		go pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+		pool.handlePromoteRequest(<-pool.promoteReqCh)
+
+		go func() {
+			assert.NoError(t, pool.addTx(local, newPricedTx(2, 100)))
+		}()
+		pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+
+		// below the 10% bump, for both the promoted and the enqueued tx
+		assert.ErrorIs(t, pool.addTx(local, newPricedTx(0, 109)), ErrReplacementUnderpriced)
+		assert.ErrorIs(t, pool.addTx(local, newPricedTx(2, 109)), ErrReplacementUnderpriced)
+
+		acc := pool.accounts.get(addr1)
+		assert.Equal(t, uint64(1), acc.enqueued.length())
+		assert.Equal(t, uint64(1), acc.promoted.length())
+		assert.Equal(t, uint64(2), pool.gauge.read())
+	})
+
+	t.Run("replacement racing with promotion", func(t *testing.T) {
+		t.Parallel()
+
+		pool := setupPool(t)
+
+		go func() {
+			assert.NoError(t, pool.addTx(local, newPricedTx(0, 100)))
+		}()
+		go pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+
+		promoteReq := <-pool.promoteReqCh
+
+		replacement := newPricedTx(0, 200)
+		go func() {
+			assert.NoError(t, pool.addTx(local, replacement))
+		}()
+		enqueueReq := <-pool.enqueueReqCh
+
+		// the replacement either replaces the enqueued tx before it is promoted,
+		// or the promoted tx once it is promoted
+		enqueued := make(chan struct{})
+		go func() {
+			pool.handleEnqueueRequest(enqueueReq)
+			close(enqueued)
+		}()
+
+		pool.handlePromoteRequest(promoteReq)
+
+		// the replacement of the enqueued tx signals another promotion
+		select {
+		case req := <-pool.promoteReqCh:
+			pool.handlePromoteRequest(req)
+			<-enqueued
+		case <-enqueued:
+		}
+
+		acc := pool.accounts.get(addr1)
+		assert.Equal(t, uint64(0), acc.enqueued.length())
+		assert.Equal(t, uint64(1), acc.promoted.length())
+		assert.Equal(t, replacement.Hash, acc.promoted.peek().Hash)
+		assert.Equal(t, uint64(1), pool.gauge.read())
+	})
+}
+
+func TestAccountLimits(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reject txs over the enqueued limit", func(t *testing.T) {
+		t.Parallel()
+
+		pool, err := newTestPool()
+		assert.NoError(t, err)
+		pool.SetSigner(&mockSigner{})
+
+		pool.maxAccountEnqueued = 2
+
+		for nonce := uint64(5); nonce < 7; nonce++ {
+			go func(nonce uint64) {
+				assert.NoError(t, pool.addTx(local, newTx(addr1, nonce, 1)))
+			}(nonce)
+			pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+		}
+
+		assert.ErrorIs(t, pool.addTx(local, newTx(addr1, 7, 1)), ErrMaxEnqueuedLimitReached)
+
+		// the limit is per account
+		go func() {
+			assert.NoError(t, pool.addTx(local, newTx(addr2, 7, 1)))
+		}()
+		pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+
+		// the tx with the expected nonce is accepted
+		go func() {
+			assert.NoError(t, pool.addTx(local, newTx(addr1, 0, 1)))
+		}()
+		go pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+		<-pool.promoteReqCh
+
+		assert.Equal(t, uint64(3), pool.accounts.get(addr1).enqueued.length())
+		assert.Equal(t, uint64(1), pool.accounts.get(addr2).enqueued.length())
+	})
+
+	t.Run("hold back txs over the promoted limit", func(t *testing.T) {
+		t.Parallel()
+
+		pool, err := newTestPool()
+		assert.NoError(t, err)
+		pool.SetSigner(&mockSigner{})
+
+		pool.maxAccountPromoted = 2
+
+		for nonce := uint64(1); nonce < 3; nonce++ {
+			go func(nonce uint64) {
+				assert.NoError(t, pool.addTx(local, newTx(addr1, nonce, 1)))
+			}(nonce)
+			pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+		}
+
+		go func() {
+			assert.NoError(t, pool.addTx(local, newTx(addr1, 0, 1)))
+		}()
+		go pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+		pool.handlePromoteRequest(<-pool.promoteReqCh)
+
+		acc := pool.accounts.get(addr1)
+		assert.Equal(t, uint64(2), acc.promoted.length())
+		assert.Equal(t, uint64(1), acc.enqueued.length())
+		assert.Equal(t, uint64(2), acc.getNonce())
+
+		// popping a tx makes room for the held back one
+		pool.Prepare(0)
+		pool.Pop(pool.Peek())
+
+		assert.Equal(t, uint64(2), acc.promoted.length())
+		assert.Equal(t, uint64(0), acc.enqueued.length())
+		assert.Equal(t, uint64(3), acc.getNonce())
+		assert.Equal(t, uint64(2), pool.gauge.read())
+	})
+}
+
+func TestEvictLowestPriced(t *testing.T) {
+	t.Parallel()
+
+	pool, err := newTestPoolWithSlots(2)
+	assert.NoError(t, err)
+	pool.SetSigner(&mockSigner{})
+
+	// returns a new tx of one slot with the given gas price
+	newPricedTx := func(addr types.Address, gasPrice uint64) *types.Transaction {
+		tx := newTx(addr, 5, 1)
+		tx.GasPrice = new(big.Int).SetUint64(gasPrice)
+
+		return tx
+	}
+
+	cheapest := newPricedTx(addr1, 1)
+
+	// fill the pool
+	for _, tx := range []*types.Transaction{cheapest, newPricedTx(addr2, 3)} {
+		go func(tx *types.Transaction) {
+			assert.NoError(t, pool.addTx(local, tx))
+		}(tx)
+		pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+	}
+
+	// the pricier tx takes the place of the cheapest one
+	go func() {
+		assert.NoError(t, pool.addTx(local, newPricedTx(addr3, 2)))
+	}()
+	pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+
+	assert.Equal(t, uint64(0), pool.accounts.get(addr1).enqueued.length())
+	assert.Equal(t, uint64(1), pool.accounts.get(addr2).enqueued.length())
+	assert.Equal(t, uint64(1), pool.accounts.get(addr3).enqueued.length())
+
+	_, known := pool.index.get(cheapest.Hash)
+	assert.False(t, known)
+
+	// nothing is cheaper than the new tx
+	assert.ErrorIs(t, pool.addTx(local, newPricedTx(addr4, 2)), ErrTxPoolOverflow)
+
+	status, err := pool.Status(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), status.Slots)
+	assert.Equal(t, uint64(2), status.MaxSlots)
+}
+
+func TestEvictLowestPriced_KeepLocal(t *testing.T) {
+	t.Parallel()
+
+	pool, err := newTestPoolWithSlots(2)
+	assert.NoError(t, err)
+	pool.SetSigner(&mockSigner{})
+
+	pool.allowLocalUnderpriced = true
+
+	// returns a new tx of one slot with the given gas price
+	newPricedTx := func(addr types.Address, gasPrice uint64) *types.Transaction {
+		tx := newTx(addr, 5, 1)
+		tx.GasPrice = new(big.Int).SetUint64(gasPrice)
+
+		return tx
+	}
+
+	localTx, remoteTx := newPricedTx(addr1, 1), newPricedTx(addr2, 2)
+
+	// fill the pool with a cheap local tx and a pricier remote one
+	for _, req := range []struct {
+		origin txOrigin
+		tx     *types.Transaction
+	}{
+		{local, localTx},
+		{gossip, remoteTx},
+	} {
+		go func(origin txOrigin, tx *types.Transaction) {
+			assert.NoError(t, pool.addTx(origin, tx))
+		}(req.origin, req.tx)
+		pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+	}
+
+	// the remote tx is evicted instead of the cheaper local one
+	go func() {
+		assert.NoError(t, pool.addTx(gossip, newPricedTx(addr3, 3)))
+	}()
+	pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+
+	_, known := pool.index.get(localTx.Hash)
+	assert.True(t, known)
+
+	_, known = pool.index.get(remoteTx.Hash)
+	assert.False(t, known)
+
+	// the local tx is the only one cheaper than the new tx
+	assert.ErrorIs(t, pool.addTx(gossip, newPricedTx(addr4, 3)), ErrTxPoolOverflow)
+	assert.Equal(t, uint64(2), pool.gauge.read())
+}
+
 func TestResetAccount(t *testing.T) {
 	t.Run("reset promoted", func(t *testing.T) {
 		testCases := []struct {
@@ -1023,7 +1499,7 @@ func TestPop(t *testing.T) {
 	assert.Equal(t, uint64(1), pool.accounts.get(addr1).promoted.length())
 
 	// pop the tx
-	pool.Prepare()
+	pool.Prepare(0)
 	tx := pool.Peek()
 	pool.Pop(tx)
 
@@ -1048,7 +1524,7 @@ func TestDrop(t *testing.T) {
 	assert.Equal(t, uint64(1), pool.accounts.get(addr1).promoted.length())
 
 	// pop the tx
-	pool.Prepare()
+	pool.Prepare(0)
 	tx := pool.Peek()
 	pool.Drop(tx)
 
@@ -1441,13 +1917,6 @@ func TestResetAccounts_Enqueued(t *testing.T) {
 }
 
 func TestExecutablesOrder(t *testing.T) {
-	newPricedTx := func(addr types.Address, nonce, gasPrice uint64) *types.Transaction {
-		tx := newTx(addr, nonce, 1)
-		tx.GasPrice.SetUint64(gasPrice)
-
-		return tx
-	}
-
 	testCases := []struct {
 		name               string
 		allTxs             map[types.Address][]*types.Transaction
@@ -1754,7 +2223,7 @@ func TestRecovery(t *testing.T) {
 			assert.Len(t, waitForEvents(ctx, promoteSubscription, totalTx), totalTx)
 
 			func() {
-				pool.Prepare()
+				pool.Prepare(0)
 				for {
 					tx := pool.Peek()
 					if tx == nil {
@@ -1977,3 +2446,506 @@ func TestGetTxs(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTxs_Snapshot(t *testing.T) {
+	pool, err := newTestPool()
+	assert.NoError(t, err)
+	pool.SetSigner(&mockSigner{})
+
+	// send 1 tx and promote it
+	go func() {
+		err := pool.addTx(local, newTx(addr1, 0, 1))
+		assert.NoError(t, err)
+	}()
+	go pool.handleEnqueueRequest(<-pool.enqueueReqCh)
+	pool.handlePromoteRequest(<-pool.promoteReqCh)
+
+	allPromoted, _ := pool.GetTxs(true)
+	assert.Len(t, allPromoted[addr1], 1)
+
+	// pop the tx, the snapshot is not affected
+	pool.Prepare(0)
+	pool.Pop(pool.Peek())
+
+	assert.Equal(t, uint64(0), pool.accounts.get(addr1).promoted.length())
+	assert.Len(t, allPromoted[addr1], 1)
+	assert.Equal(t, uint64(0), allPromoted[addr1][0].Nonce)
+}
+
+func TestValidateFees(t *testing.T) {
+	t.Parallel()
+
+	newDynamicTx := func(tip, feeCap int64) *types.Transaction {
+		tx := newTx(addr1, 0, 1)
+		tx.Type = types.DynamicFeeTx
+		tx.GasPrice = nil
+		tx.MaxPriorityFeePerGas = big.NewInt(tip)
+		tx.MaxFeePerGas = big.NewInt(feeCap)
+
+		return tx
+	}
+
+	withAccessList := newDynamicTx(1, 10)
+	withAccessList.AccessList = types.AccessList{{Address: addr2}}
+
+	legacyTx := newTx(addr1, 0, 1)
+	legacyTx.GasPrice = big.NewInt(5)
+
+	accessListTx := newTx(addr1, 0, 1)
+	accessListTx.Type = types.AccessListTx
+	accessListTx.GasPrice = big.NewInt(5)
+	accessListTx.AccessList = types.AccessList{{Address: addr2}}
+
+	testTable := []struct {
+		name    string
+		tx      *types.Transaction
+		baseFee uint64
+		berlin  bool
+		err     error
+	}{
+		{"legacy transaction before the fork", legacyTx, 0, false, nil},
+		{"dynamic fee transaction before the fork", newDynamicTx(1, 10), 0, false, ErrTxTypeNotSupported},
+		{"legacy transaction past the fork", legacyTx, 5, false, nil},
+		{"dynamic fee transaction past the fork", newDynamicTx(1, 10), 5, false, nil},
+		{"tip above the fee cap", newDynamicTx(11, 10), 5, false, ErrTipAboveFeeCap},
+		{"access list before the berlin fork", withAccessList, 5, false, ErrAccessListNotEmpty},
+		{"access list past the berlin fork", withAccessList, 5, true, nil},
+		{"access list transaction before the berlin fork", accessListTx, 0, false, ErrTxTypeNotSupported},
+		{"access list transaction past the berlin fork", accessListTx, 0, true, nil},
+		{"legacy transaction below the base fee", legacyTx, 6, false, ErrUnderpriced},
+		{"dynamic fee transaction below the base fee", newDynamicTx(1, 10), 11, false, ErrUnderpriced},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			pool, err := newTestPool()
+			assert.NoError(t, err)
+
+			pool.forks.Berlin = testCase.berlin
+
+			err = pool.validateFees(testCase.tx, &types.Header{BaseFee: testCase.baseFee})
+			if testCase.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, testCase.err)
+			}
+		})
+	}
+}
+
+func TestPricedQueue_EffectiveTipOrder(t *testing.T) {
+	t.Parallel()
+
+	legacyTx := newTx(addr1, 0, 1)
+	legacyTx.GasPrice = big.NewInt(12)
+
+	// pays a tip of 5 in a block with a base fee of 10
+	dynamicTx := newTx(addr2, 0, 1)
+	dynamicTx.Type = types.DynamicFeeTx
+	dynamicTx.GasPrice = nil
+	dynamicTx.MaxPriorityFeePerGas = big.NewInt(5)
+	dynamicTx.MaxFeePerGas = big.NewInt(20)
+
+	q := newPricedQueue()
+
+	// without a base fee the maximum fee per gas is the tip
+	q.push(legacyTx)
+	q.push(dynamicTx)
+	assert.Equal(t, dynamicTx, q.pop())
+	assert.Equal(t, legacyTx, q.pop())
+
+	// the legacy transaction pays a tip of 2 on top of the base fee
+	q.setBaseFee(10)
+	q.push(legacyTx)
+	q.push(dynamicTx)
+	assert.Equal(t, dynamicTx, q.pop())
+	assert.Equal(t, legacyTx, q.pop())
+
+	q.setBaseFee(1)
+	q.push(legacyTx)
+	q.push(dynamicTx)
+	assert.Equal(t, legacyTx, q.pop())
+	assert.Equal(t, dynamicTx, q.pop())
+}
+
+func TestPricedQueue_TieBreak(t *testing.T) {
+	t.Parallel()
+
+	txs := make([]*types.Transaction, 0, 5)
+	for _, addr := range []types.Address{addr1, addr2, addr3, addr4, addr5} {
+		txs = append(txs, newTx(addr, 0, 1).ComputeHash())
+	}
+
+	// the transactions paying the same tip are popped in the same order, whatever the push order
+	popAll := func(txs []*types.Transaction) []types.Hash {
+		q := newPricedQueue()
+		for _, tx := range txs {
+			q.push(tx)
+		}
+
+		hashes := make([]types.Hash, 0, len(txs))
+		for tx := q.pop(); tx != nil; tx = q.pop() {
+			hashes = append(hashes, tx.Hash)
+		}
+
+		return hashes
+	}
+
+	reversed := make([]*types.Transaction, len(txs))
+	for i, tx := range txs {
+		reversed[len(txs)-1-i] = tx
+	}
+
+	order := popAll(txs)
+	assert.Equal(t, order, popAll(reversed))
+
+	for i := 1; i < len(order); i++ {
+		assert.Negative(t, bytes.Compare(order[i-1][:], order[i][:]))
+	}
+}
+
+func TestExecutablesOrder_NonceFairness(t *testing.T) {
+	pool, err := newTestPool()
+	assert.NoError(t, err)
+	pool.SetSigner(&mockSigner{})
+
+	// the sender paying the highest tip for the later nonces has to wait for its first nonce
+	for _, tx := range []*types.Transaction{
+		newPricedTx(addr1, 0, 1),
+		newPricedTx(addr1, 1, 10),
+		newPricedTx(addr2, 0, 5),
+		newPricedTx(addr2, 1, 2),
+	} {
+		pushPromoted(pool, tx)
+	}
+
+	pool.Prepare(0)
+
+	var popped []uint64
+
+	for tx := pool.Peek(); tx != nil; tx = pool.Peek() {
+		pool.Pop(tx)
+		popped = append(popped, tx.GasPrice.Uint64())
+	}
+
+	assert.Equal(t, []uint64{5, 2, 1, 10}, popped)
+}
+
+// newPricedTx returns a new valid hashed tx with the given nonce and gas price
+func newPricedTx(addr types.Address, nonce, gasPrice uint64) *types.Transaction {
+	tx := newTx(addr, nonce, 1)
+	tx.GasPrice.SetUint64(gasPrice)
+
+	return tx.ComputeHash()
+}
+
+// pushPromoted adds the given tx straight to the promoted queue of its account
+func pushPromoted(pool *TxPool, tx *types.Transaction) {
+	account := pool.accounts.initOnce(tx.From, 0)
+	account.promoted.push(tx)
+	pool.gauge.increase(slotsRequired(tx))
+}
+
+// benchmarkPool returns a pool of the given number of promoted txs,
+// split across the accounts with 10 txs each.
+func benchmarkPool(b *testing.B, txs int) *TxPool {
+	b.Helper()
+
+	pool, err := newTestPool()
+	if err != nil {
+		b.Fatalf("Unable to create the pool, %v", err)
+	}
+
+	for i := 0; i < txs; i++ {
+		addr := types.BytesToAddress(new(big.Int).SetUint64(uint64(i/10 + 1)).Bytes())
+		pushPromoted(pool, newPricedTx(addr, uint64(i%10), uint64(i*7919%1000+1)))
+	}
+
+	return pool
+}
+
+func BenchmarkPricedQueue_50k(b *testing.B) {
+	txs := make([]*types.Transaction, 50000)
+	for i := range txs {
+		txs[i] = newPricedTx(addr1, uint64(i), uint64(i*7919%1000+1))
+	}
+
+	q := newPricedQueue()
+	q.setBaseFee(100)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, tx := range txs {
+			q.push(tx)
+		}
+
+		for q.length() != 0 {
+			q.pop()
+		}
+	}
+}
+
+func BenchmarkPrepareAndPop_50k(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+
+		pool := benchmarkPool(b, 50000)
+
+		b.StartTimer()
+
+		pool.Prepare(100)
+
+		for tx := pool.Peek(); tx != nil; tx = pool.Peek() {
+			pool.Pop(tx)
+		}
+	}
+}
+
+func TestReorg_DroppedTransactions(t *testing.T) {
+	dropped := newTx(addr1, 0, 1).ComputeHash()
+	included := newTx(addr2, 0, 1).ComputeHash()
+
+	oldBlock := &types.Block{
+		Header:       &types.Header{Number: 1, Hash: types.StringToHash("1")},
+		Transactions: []*types.Transaction{dropped, included},
+	}
+	newBlock := &types.Block{
+		Header:       &types.Header{Number: 1, Hash: types.StringToHash("2")},
+		Transactions: []*types.Transaction{included},
+	}
+
+	store := reorgMockStore{
+		defaultMockStore: NewDefaultMockStore(mockHeader),
+		blocks: map[types.Hash]*types.Block{
+			oldBlock.Hash(): oldBlock,
+			newBlock.Hash(): newBlock,
+		},
+		events: make(chan *blockchain.Event),
+	}
+
+	pool, err := newTestPool(store)
+	assert.NoError(t, err)
+	pool.SetSigner(&mockSigner{})
+
+	pool.Start()
+	defer pool.Close()
+
+	subscription := pool.eventManager.subscribe([]proto.EventType{proto.EventType_PROMOTED})
+
+	// the competing block replaces the old one
+	store.events <- &blockchain.Event{
+		Type:     blockchain.EventReorg,
+		OldChain: []*types.Header{oldBlock.Header},
+		NewChain: []*types.Header{newBlock.Header},
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancelFunc()
+
+	assert.Equal(t, 1, len(waitForEvents(ctx, subscription, 1)))
+
+	// only the transaction missing from the new block is returned to the pool
+	assert.Equal(t, uint64(1), pool.accounts.get(addr1).promoted.length())
+
+	_, ok := pool.index.get(included.Hash)
+	assert.False(t, ok)
+}
+
+func TestReorg_ResubscribeSlowConsumer(t *testing.T) {
+	store := newEvictingMockStore()
+
+	pool, err := newTestPool(store)
+	assert.NoError(t, err)
+	pool.SetSigner(&mockSigner{})
+
+	pool.Start()
+
+	// addr1 has a promoted tx, mined in a block the pool misses
+	pushPromoted(pool, newTx(addr1, 0, 1))
+	pool.accounts.get(addr1).setNonce(1)
+
+	reorg := &blockchain.Event{
+		Type:     blockchain.EventReorg,
+		OldChain: []*types.Header{{Number: 1, Hash: types.StringToHash("1")}},
+		NewChain: []*types.Header{{Number: 1, Hash: types.StringToHash("2")}},
+	}
+
+	// the pool is stuck on the first reorg while the buffer of its subscription overflows
+	store.push(reorg)
+	<-store.readCh
+
+	store.setNonce(addr1, 1)
+	store.push(reorg)
+	store.push(reorg)
+
+	assert.Equal(t, 0, store.numSubscribers())
+
+	close(store.gate)
+
+	// the pool subscribes again and resyncs the accounts with the head
+	assert.Eventually(t, func() bool {
+		return store.numSubscribers() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		promoted := pool.accounts.get(addr1).promoted
+
+		promoted.lock(false)
+		defer promoted.unlock()
+
+		return promoted.length() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// and stops once closed
+	pool.Close()
+
+	assert.Equal(t, 0, store.numSubscribers())
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	pool, err := newTestPool()
+	assert.NoError(t, err)
+	pool.SetSigner(&mockSigner{})
+
+	// addr1 has 2 promoted txs and an enqueued one past the nonce gap
+	tx0, tx1, tx3 := newPricedTx(addr1, 0, 1), newPricedTx(addr1, 1, 1), newPricedTx(addr1, 3, 1)
+
+	pushPromoted(pool, tx0)
+	pushPromoted(pool, tx1)
+	pool.accounts.get(addr1).setNonce(2)
+	pool.accounts.get(addr1).enqueued.push(tx3)
+	pool.index.add(tx0, tx1, tx3)
+	pool.gauge.increase(slotsRequired(tx3))
+
+	snapshot := pool.Snapshot()
+	assert.Equal(t, []*types.Transaction{tx0, tx1, tx3}, snapshot)
+
+	// the txs are mined, and addr2 sends a tx afterwards
+	pool.Prepare(0)
+	pool.Pop(pool.Peek())
+	pool.Pop(pool.Peek())
+
+	tx := newPricedTx(addr2, 0, 1)
+	pushPromoted(pool, tx)
+	pool.index.add(tx)
+
+	pool.Restore(snapshot)
+
+	account1 := pool.accounts.get(addr1)
+	assert.Equal(t, uint64(2), account1.getNonce())
+	assert.Equal(t, uint64(2), account1.promoted.length())
+	assert.Equal(t, uint64(1), account1.enqueued.length())
+
+	account2 := pool.accounts.get(addr2)
+	assert.Equal(t, uint64(0), account2.getNonce())
+	assert.Equal(t, uint64(0), account2.promoted.length())
+
+	_, ok := pool.index.get(tx.Hash)
+	assert.False(t, ok)
+
+	for _, tx := range snapshot {
+		_, ok := pool.index.get(tx.Hash)
+		assert.True(t, ok)
+	}
+
+	assert.Equal(t, slotsRequired(snapshot...), pool.gauge.read())
+}
+
+func TestGetNonce_Pending(t *testing.T) {
+	pool, err := newTestPool()
+	assert.NoError(t, err)
+
+	// the account isn't known to the pool, the nonce is the one of the state
+	assert.Equal(t, uint64(0), pool.GetNonce(addr1))
+
+	// addr1 has 2 promoted txs, and 2 enqueued ones not promoted yet, the last one past a nonce gap
+	pushPromoted(pool, newPricedTx(addr1, 0, 1))
+	pushPromoted(pool, newPricedTx(addr1, 1, 1))
+
+	account := pool.accounts.get(addr1)
+	account.setNonce(2)
+	account.enqueued.push(newPricedTx(addr1, 4, 1))
+	account.enqueued.push(newPricedTx(addr1, 2, 1))
+
+	assert.Equal(t, uint64(3), pool.GetNonce(addr1))
+
+	// the gap is filled
+	account.enqueued.push(newPricedTx(addr1, 3, 1))
+
+	assert.Equal(t, uint64(5), pool.GetNonce(addr1))
+}
+
+func TestEvictUnderpriced(t *testing.T) {
+	pool, err := newTestPool()
+	assert.NoError(t, err)
+
+	pool.underpricedBlocks = 2
+	pool.allowLocalUnderpriced = true
+
+	// addr1 has an underpriced tx between 2 others, addr2 is local, addr3 is priced just below the base fee
+	tx0, tx1, tx2 := newPricedTx(addr1, 0, 10), newPricedTx(addr1, 1, 1), newPricedTx(addr1, 2, 10)
+	localTx, closeTx := newPricedTx(addr2, 0, 1), newPricedTx(addr3, 0, 4)
+
+	for _, tx := range []*types.Transaction{tx0, tx1, tx2, localTx, closeTx} {
+		pushPromoted(pool, tx)
+		pool.index.add(tx)
+	}
+
+	pool.accounts.get(addr1).setNonce(3)
+	pool.accounts.get(addr2).setNonce(1)
+	pool.accounts.get(addr2).markLocal()
+	pool.accounts.get(addr3).setNonce(1)
+
+	evictedCh, cancel := pool.SubscribeTxEvents(proto.EventType_EVICTED_UNDERPRICED)
+	defer cancel()
+
+	// the base fee drops below the price of addr3 at the second block, it is tracked again from the third one
+	pool.evictUnderpriced(&types.Header{Number: 1, BaseFee: 5})
+	pool.evictUnderpriced(&types.Header{Number: 2, BaseFee: 3})
+	assert.Equal(t, uint64(3), pool.accounts.get(addr1).promoted.length())
+
+	pool.evictUnderpriced(&types.Header{Number: 3, BaseFee: 5})
+
+	// the underpriced tx is evicted along with the higher nonce one
+	account1 := pool.accounts.get(addr1)
+	assert.Equal(t, uint64(1), account1.getNonce())
+	assert.Equal(t, uint64(1), account1.promoted.length())
+	assert.Equal(t, slotsRequired(tx0, localTx, closeTx), pool.gauge.read())
+
+	assertEvicted := func(txs ...*types.Transaction) {
+		t.Helper()
+
+		for _, tx := range txs {
+			_, ok := pool.index.get(tx.Hash)
+			assert.False(t, ok)
+
+			select {
+			case evnt := <-evictedCh:
+				assert.Equal(t, tx.Hash.String(), evnt.TxHash)
+			case <-time.After(5 * time.Second):
+				t.Fatal("evicted event not received")
+			}
+		}
+	}
+
+	assertEvicted(tx1, tx2)
+
+	// the local tx is exempt, the tx of addr3 is evicted once it stays below the base fee for 2 blocks
+	pool.evictUnderpriced(&types.Header{Number: 4, BaseFee: 5})
+	assert.Equal(t, uint64(1), pool.accounts.get(addr3).promoted.length())
+
+	pool.evictUnderpriced(&types.Header{Number: 5, BaseFee: 5})
+	assert.Equal(t, uint64(1), pool.accounts.get(addr2).promoted.length())
+	assert.Equal(t, uint64(0), pool.accounts.get(addr3).promoted.length())
+	assert.Equal(t, uint64(0), pool.accounts.get(addr3).getNonce())
+	assert.Empty(t, pool.underpriced)
+
+	assertEvicted(closeTx)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"math/big"
 	"sync/atomic"
 
 	"github.com/0xPolygon/polygon-edge/helper/hex"
@@ -27,6 +28,7 @@ type Header struct {
 	ExtraData    []byte
 	MixHash      Hash
 	Nonce        Nonce
+	BaseFee      uint64 // Base fee per gas of the block, as in EIP-1559. 0 before the fork
 	Hash         Hash
 }
 
@@ -42,6 +44,15 @@ func (h *Header) HasReceipts() bool {
 	return h.ReceiptsRoot != EmptyRootHash
 }
 
+// GetBaseFee returns the base fee per gas of the block, or nil before the EIP-1559 fork
+func (h *Header) GetBaseFee() *big.Int {
+	if h.BaseFee == 0 {
+		return nil
+	}
+
+	return new(big.Int).SetUint64(h.BaseFee)
+}
+
 func (h *Header) SetNonce(i uint64) {
 	binary.BigEndian.PutUint64(h.Nonce[:], i)
 }
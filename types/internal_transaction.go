@@ -0,0 +1,19 @@
+package types
+
+import (
+	"math/big"
+)
+
+// InternalTransaction is a value transfer made by a call frame inside a transaction,
+// as opposed to the transfer of the transaction itself
+type InternalTransaction struct {
+	TxHash Hash
+	From   Address
+	To     Address
+	Value  *big.Int
+
+	// Depth is the depth of the call frame, the frame of the transaction is at depth 0
+	Depth uint64
+}
+
+type InternalTransactions []*InternalTransaction
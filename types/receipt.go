@@ -3,6 +3,7 @@ package types
 import (
 	"database/sql/driver"
 	"errors"
+	"math/big"
 
 	goHex "encoding/hex"
 
@@ -31,6 +32,10 @@ type Receipt struct {
 	GasUsed         uint64
 	ContractAddress Address
 	TxHash          Hash
+
+	// EffectiveGasPrice is the price per gas paid by the transaction,
+	// nil for the receipts stored before it was recorded
+	EffectiveGasPrice *big.Int
 }
 
 func (r *Receipt) SetStatus(s ReceiptStatus) {
@@ -120,6 +125,7 @@ func (b *Bloom) setEncode(hasher *keccak.Keccak, h []byte) {
 // IsLogInBloom checks if the log has a possible presence in the bloom filter
 func (b *Bloom) IsLogInBloom(log *Log) bool {
 	hasher := keccak.DefaultKeccakPool.Get()
+	defer keccak.DefaultKeccakPool.Put(hasher)
 
 	// Check if the log address is present
 	addressPresent := b.isByteArrPresent(hasher, log.Address.Bytes())
@@ -136,11 +142,17 @@ func (b *Bloom) IsLogInBloom(log *Log) bool {
 		}
 	}
 
-	keccak.DefaultKeccakPool.Put(hasher)
-
 	return true
 }
 
+// IsValueInBloom checks if the address or topic has a possible presence in the bloom filter
+func (b *Bloom) IsValueInBloom(data []byte) bool {
+	hasher := keccak.DefaultKeccakPool.Get()
+	defer keccak.DefaultKeccakPool.Put(hasher)
+
+	return b.isByteArrPresent(hasher, data)
+}
+
 // isByteArrPresent checks if the byte array is possibly present in the Bloom filter
 func (b *Bloom) isByteArrPresent(hasher *keccak.Keccak, data []byte) bool {
 	hasher.Reset()
@@ -158,7 +170,7 @@ func (b *Bloom) isByteArrPresent(hasher *keccak.Keccak, data []byte) bool {
 
 		referenceByte := b[byteLocation]
 
-		isSet := int(referenceByte & (1 << (bitLocation - 1)))
+		isSet := int(referenceByte & (1 << bitLocation))
 
 		if isSet == 0 {
 			return false
@@ -1,11 +1,14 @@
 package types
 
 import (
+	"encoding/hex"
 	"math/big"
 	"reflect"
 	"testing"
 
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
 	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/fastrlp"
 )
 
 type codec interface {
@@ -75,3 +78,227 @@ func TestRLPUnmarshal_Header_ComputeHash(t *testing.T) {
 	assert.NoError(t, h2.UnmarshalRLP(data))
 	assert.Equal(t, h.Hash, h2.Hash)
 }
+
+func TestRLPStorage_Receipt_EffectiveGasPrice(t *testing.T) {
+	receipt := &Receipt{
+		CumulativeGasUsed: 10,
+		GasUsed:           10,
+		EffectiveGasPrice: big.NewInt(7),
+	}
+	receipt.SetStatus(ReceiptSuccess)
+
+	stored := &Receipt{}
+	assert.NoError(t, stored.UnmarshalStoreRLP(receipt.MarshalStoreRLPTo(nil)))
+	assert.Equal(t, big.NewInt(7), stored.EffectiveGasPrice)
+
+	// the receipts stored before the effective gas price was recorded are still read
+	receipt.EffectiveGasPrice = nil
+
+	stored = &Receipt{}
+	assert.NoError(t, stored.UnmarshalStoreRLP(receipt.MarshalStoreRLPTo(nil)))
+	assert.Nil(t, stored.EffectiveGasPrice)
+	assert.Equal(t, uint64(10), stored.GasUsed)
+}
+
+func TestRLPUnmarshal_TruncatedLengthPrefix(t *testing.T) {
+	// the bytes of the lengths of the long strings and lists run past the input
+	for _, input := range [][]byte{
+		{0xb9, 0x01},
+		{0xbf, 0xff, 0xff},
+		{0xc3, 0x01, 0x02, 0xfa},
+		{0xf8, 0x03, 0xfb, 0xff, 0xff},
+	} {
+		assert.Error(t, (&Header{}).UnmarshalRLP(input), "input %x", input)
+		assert.Error(t, (&Transaction{}).UnmarshalRLP(input), "input %x", input)
+	}
+}
+
+func TestRLPUnmarshal_Nested(t *testing.T) {
+	decode := func(input []byte, maxDepth int) error {
+		return UnmarshalRlpNested(func(p *fastrlp.Parser, v *fastrlp.Value) error {
+			return nil
+		}, input, maxDepth)
+	}
+
+	cases := []struct {
+		name  string
+		input []byte
+		err   error
+	}{
+		{"nested at the limit", []byte{0xc2, 0xc1, 0xc0}, nil},
+		{"nested too deep", []byte{0xc3, 0xc2, 0xc1, 0xc0}, ErrRLPTooDeep},
+		{"sibling lists", []byte{0xc4, 0xc1, 0xc0, 0xc1, 0xc0}, nil},
+		{"empty", []byte{}, ErrRLPTruncated},
+		{"item past its list", []byte{0xc2, 0x83, 0x01, 0x02}, ErrRLPTruncated},
+		{"inflated length", []byte{0xbb, 0xff, 0xff, 0xff, 0xff}, ErrRLPTruncated},
+		{"trailing data", []byte{0xc0, 0x80}, ErrRLPTrailing},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := decode(c.input, 3)
+			if c.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, c.err)
+			}
+		})
+	}
+
+	// the nesting isn't limited without a depth
+	assert.NoError(t, decode([]byte{0xc3, 0xc2, 0xc1, 0xc0}, 0))
+}
+
+func TestRLPMarshall_And_Unmarshall_DynamicFeeTransaction(t *testing.T) {
+	addrTo := StringToAddress("11")
+	txn := &Transaction{
+		Type:                 DynamicFeeTx,
+		ChainID:              big.NewInt(100),
+		Nonce:                1,
+		MaxPriorityFeePerGas: big.NewInt(2),
+		MaxFeePerGas:         big.NewInt(20),
+		Gas:                  11,
+		To:                   &addrTo,
+		Value:                big.NewInt(1),
+		Input:                []byte{1, 2},
+		AccessList: AccessList{
+			{Address: addrTo, StorageKeys: []Hash{StringToHash("1")}},
+		},
+		V: big.NewInt(1),
+		S: big.NewInt(26),
+		R: big.NewInt(27),
+	}
+	txn.ComputeHash()
+
+	// the typed transactions are encoded as the type byte followed by the payload
+	data := txn.MarshalRLP()
+	assert.Equal(t, byte(DynamicFeeTx), data[0])
+
+	unmarshalledTxn := new(Transaction)
+	assert.NoError(t, unmarshalledTxn.UnmarshalRLP(data))
+
+	unmarshalledTxn.ComputeHash()
+	assert.Equal(t, txn, unmarshalledTxn)
+
+	// the typed transactions are kept as byte strings within the blocks
+	block := &Block{
+		Header:       &Header{BaseFee: 7},
+		Transactions: []*Transaction{txn},
+	}
+	block.Header.ComputeHash()
+
+	unmarshalledBlock := new(Block)
+	assert.NoError(t, unmarshalledBlock.UnmarshalRLP(block.MarshalRLP()))
+	assert.Equal(t, uint64(7), unmarshalledBlock.Header.BaseFee)
+	assert.Equal(t, block.Hash(), unmarshalledBlock.Hash())
+	assert.Equal(t, txn.Hash, unmarshalledBlock.Transactions[0].Hash)
+	assert.Equal(t, txn.MaxFeePerGas, unmarshalledBlock.Transactions[0].MaxFeePerGas)
+
+	// and in the storage format
+	txn.From = StringToAddress("2")
+
+	storedTxn := new(Transaction)
+	assert.NoError(t, storedTxn.UnmarshalStoreRLP(txn.MarshalStoreRLPTo(nil)))
+
+	storedTxn.ComputeHash()
+	assert.Equal(t, txn, storedTxn)
+}
+
+func TestRLPMarshall_And_Unmarshall_AccessListTransaction(t *testing.T) {
+	addrTo := StringToAddress("11")
+	txn := &Transaction{
+		Type:     AccessListTx,
+		ChainID:  big.NewInt(100),
+		Nonce:    1,
+		GasPrice: big.NewInt(20),
+		Gas:      11,
+		To:       &addrTo,
+		Value:    big.NewInt(1),
+		Input:    []byte{1, 2},
+		AccessList: AccessList{
+			{Address: addrTo, StorageKeys: []Hash{StringToHash("1"), StringToHash("2")}},
+			{Address: StringToAddress("12"), StorageKeys: []Hash{}},
+		},
+		V: big.NewInt(1),
+		S: big.NewInt(26),
+		R: big.NewInt(27),
+	}
+	txn.ComputeHash()
+
+	data := txn.MarshalRLP()
+	assert.Equal(t, byte(AccessListTx), data[0])
+
+	unmarshalledTxn := new(Transaction)
+	assert.NoError(t, unmarshalledTxn.UnmarshalRLP(data))
+
+	unmarshalledTxn.ComputeHash()
+	assert.Equal(t, txn, unmarshalledTxn)
+
+	// and in the storage format
+	txn.From = StringToAddress("2")
+
+	storedTxn := new(Transaction)
+	assert.NoError(t, storedTxn.UnmarshalStoreRLP(txn.MarshalStoreRLPTo(nil)))
+
+	storedTxn.ComputeHash()
+	assert.Equal(t, txn, storedTxn)
+}
+
+func TestRLPUnmarshal_AccessListTransaction_Geth(t *testing.T) {
+	// the signed access list transaction of the go-ethereum transaction tests
+	raw := "01f8630103018261a894b94f5374fce5edbc8e2a8697c15331677e6ebf0b0a825544c001a0c9519f4f2b30335884581971573fadf6" +
+		"0c6204f59a911df35ee8a540456b2660a032f1e8e2c5dd761f9e4f88f41c8310aeaba26a8bfcdacfedfa12ec3862d37521"
+
+	data, err := hex.DecodeString(raw)
+	assert.NoError(t, err)
+
+	txn := new(Transaction)
+	assert.NoError(t, txn.UnmarshalRLP(data))
+
+	to := StringToAddress("0xb94f5374fce5edbc8e2a8697c15331677e6ebf0b")
+
+	assert.Equal(t, AccessListTx, txn.Type)
+	assert.Equal(t, big.NewInt(1), txn.ChainID)
+	assert.Equal(t, uint64(3), txn.Nonce)
+	assert.Equal(t, big.NewInt(1), txn.GasPrice)
+	assert.Equal(t, uint64(25000), txn.Gas)
+	assert.Equal(t, &to, txn.To)
+	assert.Equal(t, big.NewInt(10), txn.Value)
+	assert.Equal(t, []byte{0x55, 0x44}, txn.Input)
+	assert.Empty(t, txn.AccessList)
+	assert.Equal(t, big.NewInt(1), txn.V)
+
+	// the encoding is the same
+	assert.Equal(t, data, txn.MarshalRLP())
+	assert.Equal(t, BytesToHash(keccak.Keccak256(nil, data)), txn.Hash)
+}
+
+func TestTransaction_EffectiveGasPrice(t *testing.T) {
+	legacyTxn := &Transaction{GasPrice: big.NewInt(10)}
+	dynamicTxn := &Transaction{
+		Type:                 DynamicFeeTx,
+		MaxPriorityFeePerGas: big.NewInt(2),
+		MaxFeePerGas:         big.NewInt(10),
+	}
+
+	cases := []struct {
+		txn     *Transaction
+		baseFee *big.Int
+		price   int64
+		tip     int64
+	}{
+		{txn: legacyTxn, baseFee: nil, price: 10, tip: 10},
+		{txn: legacyTxn, baseFee: big.NewInt(4), price: 10, tip: 6},
+		{txn: dynamicTxn, baseFee: nil, price: 10, tip: 10},
+		// the priority fee is paid on top of the base fee
+		{txn: dynamicTxn, baseFee: big.NewInt(4), price: 6, tip: 2},
+		// the price is capped by the maximum fee per gas
+		{txn: dynamicTxn, baseFee: big.NewInt(9), price: 10, tip: 1},
+		{txn: dynamicTxn, baseFee: big.NewInt(12), price: 10, tip: -2},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, big.NewInt(c.price), c.txn.EffectiveGasPrice(c.baseFee))
+		assert.Equal(t, big.NewInt(c.tip), c.txn.EffectiveTip(c.baseFee))
+	}
+}
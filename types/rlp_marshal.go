@@ -83,6 +83,11 @@ func (h *Header) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
 	vv.Set(arena.NewBytes(h.MixHash.Bytes()))
 	vv.Set(arena.NewCopyBytes(h.Nonce[:]))
 
+	// the base fee is only part of the headers past the EIP-1559 fork
+	if h.BaseFee != 0 {
+		vv.Set(arena.NewUint(h.BaseFee))
+	}
+
 	return vv
 }
 
@@ -158,12 +163,29 @@ func (t *Transaction) MarshalRLP() []byte {
 	return t.MarshalRLPTo(nil)
 }
 
+// MarshalRLPTo marshals the transaction to RLP. The typed transactions are
+// marshalled as their envelope, the type byte followed by the RLP payload
 func (t *Transaction) MarshalRLPTo(dst []byte) []byte {
-	return MarshalRLPTo(t.MarshalRLPWith, dst)
+	if t.Type == LegacyTx {
+		return MarshalRLPTo(t.MarshalRLPWith, dst)
+	}
+
+	dst = append(dst, byte(t.Type))
+
+	if t.Type == AccessListTx {
+		return MarshalRLPTo(t.marshalAccessListRLPWith, dst)
+	}
+
+	return MarshalRLPTo(t.marshalDynamicFeeRLPWith, dst)
 }
 
-// MarshalRLPWith marshals the transaction to RLP with a specific fastrlp.Arena
+// MarshalRLPWith marshals the transaction to RLP with a specific fastrlp.Arena.
+// The typed transactions are marshalled as the RLP bytes of their envelope
 func (t *Transaction) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	if t.Type != LegacyTx {
+		return arena.NewCopyBytes(t.MarshalRLP())
+	}
+
 	vv := arena.NewArray()
 
 	vv.Set(arena.NewUint(t.Nonce))
@@ -187,3 +209,89 @@ func (t *Transaction) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
 
 	return vv
 }
+
+// marshalAccessListRLPWith marshals the payload of the access list transaction, as in EIP-2930
+func (t *Transaction) marshalAccessListRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	vv := arena.NewArray()
+
+	vv.Set(arena.NewBigInt(t.ChainID))
+	vv.Set(arena.NewUint(t.Nonce))
+	vv.Set(arena.NewBigInt(t.GasPrice))
+	vv.Set(arena.NewUint(t.Gas))
+
+	// Address may be empty
+	if t.To != nil {
+		vv.Set(arena.NewBytes((*t.To).Bytes()))
+	} else {
+		vv.Set(arena.NewNull())
+	}
+
+	vv.Set(arena.NewBigInt(t.Value))
+	vv.Set(arena.NewCopyBytes(t.Input))
+	vv.Set(t.AccessList.MarshalRLPWith(arena))
+
+	// signature values
+	vv.Set(arena.NewBigInt(t.V))
+	vv.Set(arena.NewBigInt(t.R))
+	vv.Set(arena.NewBigInt(t.S))
+
+	return vv
+}
+
+// marshalDynamicFeeRLPWith marshals the payload of the dynamic fee transaction, as in EIP-1559
+func (t *Transaction) marshalDynamicFeeRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	vv := arena.NewArray()
+
+	vv.Set(arena.NewBigInt(t.ChainID))
+	vv.Set(arena.NewUint(t.Nonce))
+	vv.Set(arena.NewBigInt(t.MaxPriorityFeePerGas))
+	vv.Set(arena.NewBigInt(t.MaxFeePerGas))
+	vv.Set(arena.NewUint(t.Gas))
+
+	// Address may be empty
+	if t.To != nil {
+		vv.Set(arena.NewBytes((*t.To).Bytes()))
+	} else {
+		vv.Set(arena.NewNull())
+	}
+
+	vv.Set(arena.NewBigInt(t.Value))
+	vv.Set(arena.NewCopyBytes(t.Input))
+	vv.Set(t.AccessList.MarshalRLPWith(arena))
+
+	// signature values
+	vv.Set(arena.NewBigInt(t.V))
+	vv.Set(arena.NewBigInt(t.R))
+	vv.Set(arena.NewBigInt(t.S))
+
+	return vv
+}
+
+// MarshalRLPWith marshals the access list to RLP with a specific fastrlp.Arena
+func (a AccessList) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	if len(a) == 0 {
+		return arena.NewNullArray()
+	}
+
+	vv := arena.NewArray()
+
+	for _, tuple := range a {
+		tv := arena.NewArray()
+		tv.Set(arena.NewBytes(tuple.Address.Bytes()))
+
+		if len(tuple.StorageKeys) == 0 {
+			tv.Set(arena.NewNullArray())
+		} else {
+			keys := arena.NewArray()
+			for _, key := range tuple.StorageKeys {
+				keys.Set(arena.NewBytes(key.Bytes()))
+			}
+
+			tv.Set(keys)
+		}
+
+		vv.Set(tv)
+	}
+
+	return vv
+}
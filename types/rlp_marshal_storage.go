@@ -80,5 +80,34 @@ func (r *Receipt) MarshalStoreRLPWith(a *fastrlp.Arena) *fastrlp.Value {
 	// gas used
 	vv.Set(a.NewUint(r.GasUsed))
 
+	// effective gas price, left out of the receipts stored before it was recorded
+	if r.EffectiveGasPrice != nil {
+		vv.Set(a.NewBigInt(r.EffectiveGasPrice))
+	}
+
+	return vv
+}
+
+func (i InternalTransactions) MarshalRLPTo(dst []byte) []byte {
+	return MarshalRLPTo(i.MarshalRLPWith, dst)
+}
+
+func (i *InternalTransactions) MarshalRLPWith(a *fastrlp.Arena) *fastrlp.Value {
+	vv := a.NewArray()
+	for _, ii := range *i {
+		vv.Set(ii.MarshalRLPWith(a))
+	}
+
+	return vv
+}
+
+func (i *InternalTransaction) MarshalRLPWith(a *fastrlp.Arena) *fastrlp.Value {
+	vv := a.NewArray()
+	vv.Set(a.NewBytes(i.TxHash.Bytes()))
+	vv.Set(a.NewBytes(i.From.Bytes()))
+	vv.Set(a.NewBytes(i.To.Bytes()))
+	vv.Set(a.NewBigInt(i.Value))
+	vv.Set(a.NewUint(i.Depth))
+
 	return vv
 }
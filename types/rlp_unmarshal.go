@@ -1,9 +1,11 @@
 package types
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
 	"github.com/umbracle/fastrlp"
 )
 
@@ -13,7 +15,23 @@ type RLPUnmarshaler interface {
 
 type unmarshalRLPFunc func(p *fastrlp.Parser, v *fastrlp.Value) error
 
+var (
+	ErrRLPTruncated = errors.New("cannot parse RLP: item truncated")
+	ErrRLPTrailing  = errors.New("cannot parse RLP: trailing data")
+	ErrRLPTooDeep   = errors.New("cannot parse RLP: lists nested too deep")
+)
+
 func UnmarshalRlp(obj unmarshalRLPFunc, input []byte) error {
+	return UnmarshalRlpNested(obj, input, 0)
+}
+
+// UnmarshalRlpNested unmarshals the untrusted input whose lists are nested at most maxDepth deep.
+// The input has to be a single RLP value whose items fit in their lists, 0 depth doesn't limit the nesting
+func UnmarshalRlpNested(obj unmarshalRLPFunc, input []byte, maxDepth int) error {
+	if err := checkLengthPrefixes(input, maxDepth); err != nil {
+		return err
+	}
+
 	pr := fastrlp.DefaultParserPool.Get()
 
 	v, err := pr.Parse(input)
@@ -34,6 +52,101 @@ func UnmarshalRlp(obj unmarshalRLPFunc, input []byte) error {
 	return nil
 }
 
+// checkLengthPrefixes walks the RLP prefixes of the input as the parser reads them,
+// and checks the bytes of their lengths are part of the input.
+// The parser doesn't check them, and panics on the long prefixes truncated by the end of the input.
+// If maxDepth is set, the ends of the lists are tracked to limit their nesting,
+// and the items running past their list, or past the input, and the trailing data are rejected
+func checkLengthPrefixes(input []byte, maxDepth int) error {
+	if maxDepth > 0 && len(input) == 0 {
+		return ErrRLPTruncated
+	}
+
+	// ends are the offsets of the ends of the open lists
+	ends := make([]int, 0, maxDepth)
+
+	for pos := 0; pos < len(input); {
+		for len(ends) > 0 && pos == ends[len(ends)-1] {
+			ends = ends[:len(ends)-1]
+		}
+
+		if maxDepth > 0 && len(ends) == 0 && pos > 0 {
+			return ErrRLPTrailing
+		}
+
+		// limit is the end of the innermost open list
+		limit := len(input)
+		if len(ends) > 0 {
+			limit = ends[len(ends)-1]
+		}
+
+		prefix := input[pos]
+
+		var (
+			isList             bool
+			headerLen, dataLen int
+		)
+
+		switch {
+		case prefix < 0x80:
+			headerLen = 1
+		case prefix < 0xB8:
+			headerLen, dataLen = 1, int(prefix-0x80)
+		case prefix < 0xC0:
+			headerLen = 1 + int(prefix-0xB7)
+		case prefix < 0xF8:
+			isList, headerLen, dataLen = true, 1, int(prefix-0xC0)
+		default:
+			isList, headerLen = true, 1+int(prefix-0xF7)
+		}
+
+		if pos+headerLen > len(input) {
+			return fmt.Errorf("%w: length of %d bytes truncated at offset %d", ErrRLPTruncated, headerLen-1, pos)
+		}
+
+		// the long items are prefixed with the length of their length
+		if headerLen > 1 {
+			size := uint64(0)
+			for _, b := range input[pos+1 : pos+headerLen] {
+				size = size<<8 | uint64(b)
+			}
+
+			switch {
+			case size <= uint64(len(input)):
+				dataLen = int(size)
+			case maxDepth > 0:
+				return fmt.Errorf("%w: item of %d bytes at offset %d", ErrRLPTruncated, size, pos)
+			case !isList:
+				// the parser rejects the bytes running past the input
+				return nil
+			}
+		}
+
+		if maxDepth > 0 && pos+headerLen+dataLen > limit {
+			return fmt.Errorf("%w: item of %d bytes at offset %d", ErrRLPTruncated, dataLen, pos)
+		}
+
+		if !isList {
+			pos += headerLen + dataLen
+
+			continue
+		}
+
+		// the elements of the list follow
+		if maxDepth > 0 {
+			if len(ends) == maxDepth {
+				return fmt.Errorf("%w: over %d lists at offset %d", ErrRLPTooDeep, maxDepth, pos)
+			}
+
+			ends = append(ends, pos+headerLen+dataLen)
+		}
+
+		pos += headerLen
+	}
+
+	return nil
+}
+
 func (b *Block) UnmarshalRLP(input []byte) error {
 	return UnmarshalRlp(b.UnmarshalRLPFrom, input)
 }
@@ -97,8 +210,9 @@ func (h *Header) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 		return err
 	}
 
-	if num := len(elems); num != 15 {
-		return fmt.Errorf("not enough elements to decode header, expected 15 but found %d", num)
+	// the headers past the EIP-1559 fork have the base fee as the 16th element
+	if num := len(elems); num != 15 && num != 16 {
+		return fmt.Errorf("not enough elements to decode header, expected 15 or 16 but found %d", num)
 	}
 
 	// parentHash
@@ -165,6 +279,14 @@ func (h *Header) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 
 	h.SetNonce(nonce)
 
+	// baseFee
+	h.BaseFee = 0
+	if len(elems) == 16 {
+		if h.BaseFee, err = elems[15].GetUint64(); err != nil {
+			return err
+		}
+	}
+
 	// compute the hash after the decoding
 	h.ComputeHash()
 
@@ -288,12 +410,61 @@ func (l *Log) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 	return nil
 }
 
+// UnmarshalRLP unmarshals a Transaction in RLP format. The typed transactions
+// are unmarshalled from their envelope, the type byte followed by the RLP payload
 func (t *Transaction) UnmarshalRLP(input []byte) error {
+	// the RLP lists start at 0xc0, the transaction types are in [0x00, 0x7f]
+	if len(input) > 0 && input[0] <= 0x7f {
+		return t.unmarshalTypedRLP(input)
+	}
+
+	t.Type = LegacyTx
+
 	return UnmarshalRlp(t.UnmarshalRLPFrom, input)
 }
 
+// unmarshalTypedRLP unmarshals the envelope of a typed transaction
+func (t *Transaction) unmarshalTypedRLP(input []byte) error {
+	var unmarshalPayload unmarshalRLPFunc
+
+	switch typ := TxType(input[0]); typ {
+	case AccessListTx:
+		unmarshalPayload = t.unmarshalAccessListTxRLPFrom
+	case DynamicFeeTx:
+		unmarshalPayload = t.unmarshalDynamicFeeRLPFrom
+	default:
+		return fmt.Errorf("%w, %d", ErrTxTypeNotSupported, typ)
+	}
+
+	t.Type = TxType(input[0])
+
+	if err := UnmarshalRlp(unmarshalPayload, input[1:]); err != nil {
+		return err
+	}
+
+	keccak.Keccak256(t.Hash[:0], input)
+
+	return nil
+}
+
 // UnmarshalRLP unmarshals a Transaction in RLP format
 func (t *Transaction) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	if v.Type() == fastrlp.TypeBytes {
+		// typed transactions are RLP bytes of their envelope
+		envelope, err := v.Bytes()
+		if err != nil {
+			return err
+		}
+
+		if len(envelope) == 0 {
+			return fmt.Errorf("empty typed transaction envelope")
+		}
+
+		return t.unmarshalTypedRLP(envelope)
+	}
+
+	t.Type = LegacyTx
+
 	elems, err := v.GetElems()
 	if err != nil {
 		return err
@@ -356,3 +527,201 @@ func (t *Transaction) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) erro
 
 	return nil
 }
+
+// unmarshalAccessListTxRLPFrom unmarshals the payload of an access list transaction, as in EIP-2930
+func (t *Transaction) unmarshalAccessListTxRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	if num := len(elems); num != 11 {
+		return fmt.Errorf("not enough elements to decode access list transaction, expected 11 but found %d", num)
+	}
+
+	// chainID
+	t.ChainID = new(big.Int)
+	if err := elems[0].GetBigInt(t.ChainID); err != nil {
+		return err
+	}
+	// nonce
+	if t.Nonce, err = elems[1].GetUint64(); err != nil {
+		return err
+	}
+	// gasPrice
+	t.GasPrice = new(big.Int)
+	if err := elems[2].GetBigInt(t.GasPrice); err != nil {
+		return err
+	}
+	// gas
+	if t.Gas, err = elems[3].GetUint64(); err != nil {
+		return err
+	}
+	// to
+	if vv, _ := elems[4].Bytes(); len(vv) == 20 {
+		// address
+		addr := BytesToAddress(vv)
+		t.To = &addr
+	} else {
+		// reset To
+		t.To = nil
+	}
+	// value
+	t.Value = new(big.Int)
+	if err := elems[5].GetBigInt(t.Value); err != nil {
+		return err
+	}
+	// input
+	if t.Input, err = elems[6].GetBytes(t.Input[:0]); err != nil {
+		return err
+	}
+	// accessList
+	if t.AccessList, err = unmarshalAccessListRLPFrom(elems[7]); err != nil {
+		return err
+	}
+
+	// the access list transactions have no dynamic fees
+	t.MaxPriorityFeePerGas = nil
+	t.MaxFeePerGas = nil
+
+	// V
+	t.V = new(big.Int)
+	if err = elems[8].GetBigInt(t.V); err != nil {
+		return err
+	}
+	// R
+	t.R = new(big.Int)
+	if err = elems[9].GetBigInt(t.R); err != nil {
+		return err
+	}
+	// S
+	t.S = new(big.Int)
+	if err = elems[10].GetBigInt(t.S); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// unmarshalDynamicFeeRLPFrom unmarshals the payload of a dynamic fee transaction, as in EIP-1559
+func (t *Transaction) unmarshalDynamicFeeRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	if num := len(elems); num != 12 {
+		return fmt.Errorf("not enough elements to decode dynamic fee transaction, expected 12 but found %d", num)
+	}
+
+	// chainID
+	t.ChainID = new(big.Int)
+	if err := elems[0].GetBigInt(t.ChainID); err != nil {
+		return err
+	}
+	// nonce
+	if t.Nonce, err = elems[1].GetUint64(); err != nil {
+		return err
+	}
+	// maxPriorityFeePerGas
+	t.MaxPriorityFeePerGas = new(big.Int)
+	if err := elems[2].GetBigInt(t.MaxPriorityFeePerGas); err != nil {
+		return err
+	}
+	// maxFeePerGas
+	t.MaxFeePerGas = new(big.Int)
+	if err := elems[3].GetBigInt(t.MaxFeePerGas); err != nil {
+		return err
+	}
+	// gas
+	if t.Gas, err = elems[4].GetUint64(); err != nil {
+		return err
+	}
+	// to
+	if vv, _ := elems[5].Bytes(); len(vv) == 20 {
+		// address
+		addr := BytesToAddress(vv)
+		t.To = &addr
+	} else {
+		// reset To
+		t.To = nil
+	}
+	// value
+	t.Value = new(big.Int)
+	if err := elems[6].GetBigInt(t.Value); err != nil {
+		return err
+	}
+	// input
+	if t.Input, err = elems[7].GetBytes(t.Input[:0]); err != nil {
+		return err
+	}
+	// accessList
+	if t.AccessList, err = unmarshalAccessListRLPFrom(elems[8]); err != nil {
+		return err
+	}
+
+	// the dynamic fee transactions have no gas price
+	t.GasPrice = nil
+
+	// V
+	t.V = new(big.Int)
+	if err = elems[9].GetBigInt(t.V); err != nil {
+		return err
+	}
+	// R
+	t.R = new(big.Int)
+	if err = elems[10].GetBigInt(t.R); err != nil {
+		return err
+	}
+	// S
+	t.S = new(big.Int)
+	if err = elems[11].GetBigInt(t.S); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// unmarshalAccessListRLPFrom unmarshals an access list in RLP format
+func unmarshalAccessListRLPFrom(v *fastrlp.Value) (AccessList, error) {
+	elems, err := v.GetElems()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elems) == 0 {
+		return nil, nil
+	}
+
+	list := make(AccessList, len(elems))
+
+	for indx, elem := range elems {
+		tuple, err := elem.GetElems()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(tuple) != 2 {
+			return nil, fmt.Errorf("expected 2 elements in the access tuple but found %d", len(tuple))
+		}
+
+		if err := tuple[0].GetAddr(list[indx].Address[:]); err != nil {
+			return nil, err
+		}
+
+		keys, err := tuple[1].GetElems()
+		if err != nil {
+			return nil, err
+		}
+
+		list[indx].StorageKeys = make([]Hash, len(keys))
+
+		for keyIndx, key := range keys {
+			if err := key.GetHash(list[indx].StorageKeys[keyIndx][:]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return list, nil
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/umbracle/fastrlp"
 )
@@ -115,8 +116,8 @@ func (r *Receipt) UnmarshalStoreRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) err
 		return err
 	}
 
-	if len(elems) != 3 {
-		return fmt.Errorf("expected 3 elements")
+	if len(elems) != 3 && len(elems) != 4 {
+		return fmt.Errorf("expected 3 or 4 elements")
 	}
 
 	if err := r.UnmarshalRLPFrom(p, elems[0]); err != nil {
@@ -140,5 +141,69 @@ func (r *Receipt) UnmarshalStoreRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) err
 		return err
 	}
 
+	// effective gas price
+	if len(elems) == 4 {
+		r.EffectiveGasPrice = new(big.Int)
+		if err := elems[3].GetBigInt(r.EffectiveGasPrice); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *InternalTransactions) UnmarshalRLP(input []byte) error {
+	return UnmarshalRlp(i.UnmarshalRLPFrom, input)
+}
+
+func (i *InternalTransactions) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	for _, elem := range elems {
+		ii := &InternalTransaction{}
+		if err := ii.UnmarshalRLPFrom(p, elem); err != nil {
+			return err
+		}
+
+		(*i) = append(*i, ii)
+	}
+
+	return nil
+}
+
+func (i *InternalTransaction) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	if len(elems) != 5 {
+		return fmt.Errorf("expected 5 elements")
+	}
+
+	if err := elems[0].GetHash(i.TxHash[:]); err != nil {
+		return err
+	}
+
+	if err := elems[1].GetAddr(i.From[:]); err != nil {
+		return err
+	}
+
+	if err := elems[2].GetAddr(i.To[:]); err != nil {
+		return err
+	}
+
+	i.Value = new(big.Int)
+	if err := elems[3].GetBigInt(i.Value); err != nil {
+		return err
+	}
+
+	if i.Depth, err = elems[4].GetUint64(); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -1,13 +1,52 @@
 package types
 
 import (
+	"errors"
 	"math/big"
 	"sync/atomic"
 
 	"github.com/0xPolygon/polygon-edge/helper/keccak"
 )
 
+// TxType is the type of the transaction, as in EIP-2718
+type TxType byte
+
+const (
+	LegacyTx     TxType = 0x00
+	AccessListTx TxType = 0x01
+	DynamicFeeTx TxType = 0x02
+)
+
+var ErrTxTypeNotSupported = errors.New("transaction type not supported")
+
+// AccessTuple is an address and the storage keys accessed by the transaction
+type AccessTuple struct {
+	Address     Address `json:"address"`
+	StorageKeys []Hash  `json:"storageKeys"`
+}
+
+// AccessList is the list of the addresses and storage keys accessed by the transaction, as in EIP-2930
+type AccessList []AccessTuple
+
+// Copy returns a deep copy of the access list
+func (a AccessList) Copy() AccessList {
+	if a == nil {
+		return nil
+	}
+
+	cp := make(AccessList, len(a))
+	for indx, tuple := range a {
+		cp[indx] = AccessTuple{
+			Address:     tuple.Address,
+			StorageKeys: append([]Hash{}, tuple.StorageKeys...),
+		}
+	}
+
+	return cp
+}
+
 type Transaction struct {
+	Type     TxType
 	Nonce    uint64
 	GasPrice *big.Int
 	Gas      uint64
@@ -20,6 +59,13 @@ type Transaction struct {
 	Hash     Hash
 	From     Address
 
+	// Typed transaction fields, the chain ID and the access list are set by
+	// the access list transactions (EIP-2930) and the dynamic fee transactions (EIP-1559)
+	ChainID              *big.Int
+	MaxPriorityFeePerGas *big.Int
+	MaxFeePerGas         *big.Int
+	AccessList           AccessList
+
 	// Cache
 	size atomic.Value
 }
@@ -28,8 +74,37 @@ func (t *Transaction) IsContractCreation() bool {
 	return t.To == nil
 }
 
+// IsDynamicFee checks if the transaction is an EIP-1559 dynamic fee transaction
+func (t *Transaction) IsDynamicFee() bool {
+	return t.Type == DynamicFeeTx
+}
+
+// IsAccessList checks if the transaction is an EIP-2930 access list transaction
+func (t *Transaction) IsAccessList() bool {
+	return t.Type == AccessListTx
+}
+
+// IsReplayProtected checks if the transaction is signed for a chain, as in EIP-155.
+// The typed transactions always carry their chain id, the legacy ones are unprotected if V is 27 or 28
+func (t *Transaction) IsReplayProtected() bool {
+	if t.Type != LegacyTx || t.V == nil || !t.V.IsUint64() {
+		return true
+	}
+
+	v := t.V.Uint64()
+
+	return v != 27 && v != 28
+}
+
 // ComputeHash computes the hash of the transaction
 func (t *Transaction) ComputeHash() *Transaction {
+	if t.Type != LegacyTx {
+		// typed transactions are hashed with their envelope
+		keccak.Keccak256(t.Hash[:0], t.MarshalRLP())
+
+		return t
+	}
+
 	ar := marshalArenaPool.Get()
 	hash := keccak.DefaultKeccakPool.Get()
 
@@ -66,15 +141,79 @@ func (t *Transaction) Copy() *Transaction {
 		tt.S = big.NewInt(0).SetBits(t.S.Bits())
 	}
 
+	if t.ChainID != nil {
+		tt.ChainID = new(big.Int).Set(t.ChainID)
+	}
+
+	if t.MaxPriorityFeePerGas != nil {
+		tt.MaxPriorityFeePerGas = new(big.Int).Set(t.MaxPriorityFeePerGas)
+	}
+
+	if t.MaxFeePerGas != nil {
+		tt.MaxFeePerGas = new(big.Int).Set(t.MaxFeePerGas)
+	}
+
+	tt.AccessList = t.AccessList.Copy()
+
 	tt.Input = make([]byte, len(t.Input))
 	copy(tt.Input[:], t.Input[:])
 
 	return tt
 }
 
-// Cost returns gas * gasPrice + value
+// GetGasFeeCap returns the maximum price per gas the transaction pays.
+// It is the gas price of the legacy transactions
+func (t *Transaction) GetGasFeeCap() *big.Int {
+	if t.IsDynamicFee() {
+		return t.MaxFeePerGas
+	}
+
+	return t.GasPrice
+}
+
+// GetGasTipCap returns the maximum price per gas the transaction pays on top of the base fee.
+// It is the gas price of the legacy transactions
+func (t *Transaction) GetGasTipCap() *big.Int {
+	if t.IsDynamicFee() {
+		return t.MaxPriorityFeePerGas
+	}
+
+	return t.GasPrice
+}
+
+// EffectiveGasPrice returns the price per gas the transaction pays in a block with the base fee,
+// min(maxFeePerGas, baseFee + maxPriorityFeePerGas). It is the gas price of the legacy transactions
+func (t *Transaction) EffectiveGasPrice(baseFee *big.Int) *big.Int {
+	if !t.IsDynamicFee() {
+		return new(big.Int).Set(t.GasPrice)
+	}
+
+	if baseFee == nil {
+		return new(big.Int).Set(t.MaxFeePerGas)
+	}
+
+	price := new(big.Int).Add(baseFee, t.MaxPriorityFeePerGas)
+	if price.Cmp(t.MaxFeePerGas) > 0 {
+		price.Set(t.MaxFeePerGas)
+	}
+
+	return price
+}
+
+// EffectiveTip returns the price per gas the block creator receives in a block with the base fee.
+// The tip is negative if the transaction can't pay the base fee
+func (t *Transaction) EffectiveTip(baseFee *big.Int) *big.Int {
+	price := t.EffectiveGasPrice(baseFee)
+	if baseFee == nil {
+		return price
+	}
+
+	return price.Sub(price, baseFee)
+}
+
+// Cost returns gas * gasPrice + value, using the maximum fee per gas of the dynamic fee transactions
 func (t *Transaction) Cost() *big.Int {
-	total := new(big.Int).Mul(t.GasPrice, new(big.Int).SetUint64(t.Gas))
+	total := new(big.Int).Mul(t.GetGasFeeCap(), new(big.Int).SetUint64(t.Gas))
 	total.Add(total, t.Value)
 
 	return total
@@ -101,5 +240,5 @@ func (t *Transaction) ExceedsBlockGasLimit(blockGasLimit uint64) bool {
 }
 
 func (t *Transaction) IsUnderpriced(priceLimit uint64) bool {
-	return t.GasPrice.Cmp(big.NewInt(0).SetUint64(priceLimit)) < 0
+	return t.GetGasFeeCap().Cmp(big.NewInt(0).SetUint64(priceLimit)) < 0
 }
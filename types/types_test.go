@@ -84,3 +84,21 @@ func TestTransactionCopy(t *testing.T) {
 		t.Fatal("[ERROR] Copied transaction not equal base transaction")
 	}
 }
+
+func TestBloom_IsLogInBloom(t *testing.T) {
+	t.Parallel()
+
+	log := &Log{
+		Address: StringToAddress("1"),
+		Topics:  []Hash{StringToHash("2"), StringToHash("3")},
+	}
+
+	bloom := CreateBloom([]*Receipt{{Logs: []*Log{log}}})
+
+	assert.True(t, bloom.IsLogInBloom(log))
+	assert.True(t, bloom.IsValueInBloom(log.Address.Bytes()))
+	assert.True(t, bloom.IsValueInBloom(log.Topics[1].Bytes()))
+
+	assert.False(t, bloom.IsLogInBloom(&Log{Address: StringToAddress("4")}))
+	assert.False(t, bloom.IsValueInBloom(StringToHash("5").Bytes()))
+}